@@ -0,0 +1,258 @@
+// Package client is a Go client for the panel's REST API, for programs that
+// want to control servers without going through the web UI.
+//
+// The methods here follow the documented /api routes and return the same
+// types the panel serves, so they change only when the API does.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"minecraft-admin/minecraft"
+)
+
+// APIVersion is the version of the REST API this package speaks.
+const APIVersion = "1"
+
+// Client talks to one panel. It keeps the session cookie and CSRF token
+// from Login and is safe for concurrent use.
+type Client struct {
+	baseURL string
+	http    *http.Client
+
+	mu        sync.RWMutex
+	csrfToken string
+}
+
+// APIError is a non-2xx response from the panel. Code and Params are set
+// for errors the panel reports with a stable code.
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+	Params  map[string]any
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("panel API error %d (%s): %s", e.Status, e.Code, e.Message)
+	}
+	return fmt.Sprintf("panel API error %d: %s", e.Status, e.Message)
+}
+
+// New returns a client for the panel at baseURL, e.g. "http://localhost:8080".
+func New(baseURL string) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid panel URL %q", baseURL)
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		baseURL: u.String(),
+		http:    &http.Client{Jar: jar, Timeout: 60 * time.Second},
+	}, nil
+}
+
+// Login starts a session. Later calls reuse its cookie and CSRF token.
+func (c *Client) Login(ctx context.Context, username, password string) error {
+	var resp struct {
+		CSRFToken string `json:"csrfToken"`
+	}
+	body := map[string]string{"username": username, "password": password}
+	if err := c.do(ctx, http.MethodPost, "/api/auth/login", body, &resp); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.csrfToken = resp.CSRFToken
+	c.mu.Unlock()
+	return nil
+}
+
+// Logout ends the session.
+func (c *Client) Logout(ctx context.Context) error {
+	err := c.do(ctx, http.MethodPost, "/api/auth/logout", nil, nil)
+	c.mu.Lock()
+	c.csrfToken = ""
+	c.mu.Unlock()
+	return err
+}
+
+// ListServers returns every server with its current status.
+func (c *Client) ListServers(ctx context.Context) ([]minecraft.ServerInfo, error) {
+	var servers []minecraft.ServerInfo
+	if err := c.do(ctx, http.MethodGet, "/api/servers", nil, &servers); err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
+
+// GetServer returns one server's status.
+func (c *Client) GetServer(ctx context.Context, id string) (*minecraft.ServerInfo, error) {
+	return c.serverAction(ctx, http.MethodGet, id, "status")
+}
+
+// StartServer starts a server and returns its status.
+func (c *Client) StartServer(ctx context.Context, id string) (*minecraft.ServerInfo, error) {
+	return c.serverAction(ctx, http.MethodPost, id, "start")
+}
+
+// StopServer asks a server to stop and returns its status.
+func (c *Client) StopServer(ctx context.Context, id string) (*minecraft.ServerInfo, error) {
+	return c.serverAction(ctx, http.MethodPost, id, "stop")
+}
+
+// KillServer force-stops a server and returns its status.
+func (c *Client) KillServer(ctx context.Context, id string) (*minecraft.ServerInfo, error) {
+	return c.serverAction(ctx, http.MethodPost, id, "kill")
+}
+
+// SuspendServer freezes a running server's process and returns its status.
+func (c *Client) SuspendServer(ctx context.Context, id string) (*minecraft.ServerInfo, error) {
+	return c.serverAction(ctx, http.MethodPost, id, "suspend")
+}
+
+// ResumeServer continues a suspended server and returns its status.
+func (c *Client) ResumeServer(ctx context.Context, id string) (*minecraft.ServerInfo, error) {
+	return c.serverAction(ctx, http.MethodPost, id, "resume")
+}
+
+// SendCommand runs a console command on a server.
+func (c *Client) SendCommand(ctx context.Context, id, command string) error {
+	return c.do(ctx, http.MethodPost, serverPath(id, "command"), map[string]string{"command": command}, nil)
+}
+
+// ListBackups returns a server's backups.
+func (c *Client) ListBackups(ctx context.Context, id string) ([]minecraft.BackupInfo, error) {
+	var backups []minecraft.BackupInfo
+	if err := c.do(ctx, http.MethodGet, serverPath(id, "backups"), nil, &backups); err != nil {
+		return nil, err
+	}
+	return backups, nil
+}
+
+// backupPollInterval is how often CreateBackup checks on its job.
+var backupPollInterval = time.Second
+
+// backupJob is a backup job with its result decoded.
+type backupJob struct {
+	minecraft.Job
+	Result *minecraft.BackupInfo `json:"result"`
+}
+
+// CreateBackup backs up a server and waits for the backup job to finish.
+func (c *Client) CreateBackup(ctx context.Context, id string) (*minecraft.BackupInfo, error) {
+	var job backupJob
+	if err := c.do(ctx, http.MethodPost, serverPath(id, "backups"), nil, &job); err != nil {
+		return nil, err
+	}
+	for job.State == minecraft.JobStateQueued || job.State == minecraft.JobStateRunning {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backupPollInterval):
+		}
+		if err := c.do(ctx, http.MethodGet, serverPath(id, "backup-jobs/"+url.PathEscape(job.ID)), nil, &job); err != nil {
+			return nil, err
+		}
+	}
+	if job.State != minecraft.JobStateSucceeded || job.Result == nil {
+		return nil, fmt.Errorf("backup %s: %s", job.State, job.Error)
+	}
+	return job.Result, nil
+}
+
+func (c *Client) serverAction(ctx context.Context, method, id, action string) (*minecraft.ServerInfo, error) {
+	var info minecraft.ServerInfo
+	if err := c.do(ctx, method, serverPath(id, action), nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func serverPath(id, action string) string {
+	return "/api/servers/" + url.PathEscape(id) + "/" + action
+}
+
+// do sends a JSON request and decodes a JSON response into out, if given.
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if method != http.MethodGet {
+		c.mu.RLock()
+		token := c.csrfToken
+		c.mu.RUnlock()
+		if token != "" {
+			req.Header.Set("X-CSRF-Token", token)
+		}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decodeAPIError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s %s response: %w", method, path, err)
+	}
+	return nil
+}
+
+// decodeAPIError reads the panel's error body. Structured errors carry a
+// code; older ones put the code or the message in "error".
+func decodeAPIError(resp *http.Response) error {
+	var payload struct {
+		Error   string         `json:"error"`
+		Code    string         `json:"code"`
+		Message string         `json:"message"`
+		Params  map[string]any `json:"params"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	apiErr := &APIError{Status: resp.StatusCode}
+	if json.Unmarshal(data, &payload) != nil {
+		apiErr.Message = strings.TrimSpace(string(data))
+		return apiErr
+	}
+	apiErr.Code = payload.Code
+	apiErr.Params = payload.Params
+	apiErr.Message = payload.Message
+	if apiErr.Message == "" {
+		apiErr.Message = payload.Error
+	} else if apiErr.Code == "" {
+		apiErr.Code = payload.Error
+	}
+	return apiErr
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"minecraft-admin/handlers"
+	"minecraft-admin/minecraft"
+)
+
+func TestClientLogsInAndCallsServerRoutes(t *testing.T) {
+	base := t.TempDir()
+	mgr, err := minecraft.NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+	if _, _, err := mgr.UpdateAppSettings(minecraft.AppSettingsUpdate{DefaultMinRAM: "0.5", DefaultMaxRAM: "1", DefaultFlags: "none", LoginUser: "adminuser", LoginPassword: "strongpass123"}); err != nil {
+		t.Fatalf("UpdateAppSettings failed: %v", err)
+	}
+	created, err := mgr.CreateServer("Lobby", "Vanilla", "1.21.10", 0, "", "", 0, "", false)
+	if err != nil {
+		t.Fatalf("CreateServer failed: %v", err)
+	}
+
+	auth := handlers.NewAuthHandler(mgr, base)
+	servers := handlers.NewServerHandler(mgr)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/auth/login", auth.Login)
+	mux.HandleFunc("GET /api/servers", servers.List)
+	mux.HandleFunc("POST /api/servers/{id}/command", servers.SendCommand)
+	srv := httptest.NewServer(auth.Middleware(mux))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ctx := context.Background()
+
+	var apiErr *APIError
+	if _, err := c.ListServers(ctx); !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnauthorized {
+		t.Fatalf("expected 401 before login, got %v", err)
+	}
+	if err := c.Login(ctx, "adminuser", "wrong"); err == nil {
+		t.Fatalf("expected wrong password to fail")
+	}
+	if err := c.Login(ctx, "adminuser", "strongpass123"); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	list, err := c.ListServers(ctx)
+	if err != nil {
+		t.Fatalf("ListServers failed: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != created.ID {
+		t.Fatalf("unexpected servers %+v", list)
+	}
+
+	// The CSRF token from login is sent; the command fails only because
+	// the server is not running.
+	err = c.SendCommand(ctx, created.ID, "list")
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadRequest {
+		t.Fatalf("expected not-running error, got %v", err)
+	}
+	if err := c.SendCommand(ctx, "missing", "list"); !errors.As(err, &apiErr) || apiErr.Code != minecraft.MessageServerNotFound {
+		t.Fatalf("expected server_not_found code, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -13,44 +14,105 @@ import (
 	"minecraft-admin/minecraft"
 )
 
-const (
-	sessionCookieName = "orexa_session"
-	sessionTTL        = 7 * 24 * time.Hour
-	loginWindow       = 15 * time.Minute
-	loginBlockTime    = 15 * time.Minute
-	loginMaxFailures  = 10
-)
+const sessionCookieName = "orexa_session"
 
 type sessionRecord struct {
 	Username           string    `json:"username"`
+	Role               string    `json:"role"`
+	Created            time.Time `json:"created"`
 	Expires            time.Time `json:"expires"`
 	MustChangePassword bool      `json:"mustChangePassword"`
+	ClientIP           string    `json:"clientIp"`
+	UserAgent          string    `json:"userAgent"`
+	CSRFToken          string    `json:"csrfToken"`
+}
+
+// sessionPolicy is the session and lockout configuration from the panel
+// settings, read on every use so changes apply without a restart.
+type sessionPolicy struct {
+	ttl           time.Duration
+	loginWindow   time.Duration
+	loginBlock    time.Duration
+	maxFailures   int
+	bindIP        bool
+	bindUserAgent bool
 }
 
-type loginAttempt struct {
-	Count        int
-	WindowStart  time.Time
-	BlockedUntil time.Time
+func (h *AuthHandler) sessionPolicy() sessionPolicy {
+	var settings minecraft.AppSettings
+	if h.mgr != nil {
+		settings = h.mgr.GetSettings()
+	}
+	policy := sessionPolicy{
+		ttl:           time.Duration(settings.SessionTTLHours) * time.Hour,
+		loginWindow:   time.Duration(settings.LoginWindowMinutes) * time.Minute,
+		loginBlock:    time.Duration(settings.LoginLockoutMinutes) * time.Minute,
+		maxFailures:   settings.LoginMaxFailures,
+		bindIP:        settings.SessionBindIP,
+		bindUserAgent: settings.SessionBindUserAgent,
+	}
+	if policy.ttl <= 0 {
+		policy.ttl = 7 * 24 * time.Hour
+	}
+	if policy.loginWindow <= 0 {
+		policy.loginWindow = 15 * time.Minute
+	}
+	if policy.loginBlock <= 0 {
+		policy.loginBlock = 15 * time.Minute
+	}
+	if policy.maxFailures <= 0 {
+		policy.maxFailures = 10
+	}
+	return policy
 }
 
+type loginAttempt = minecraft.LoginAttemptRecord
+
 type AuthHandler struct {
 	mgr            *minecraft.Manager
 	mu             sync.RWMutex
 	sessions       map[string]sessionRecord
 	loginAttempts  map[string]loginAttempt
+	wsTickets      map[string]wsTicket
 	trustedProxies *trustedProxySet
 	csrfMode       string
+	haToken        string
+	// publicStatus opens /api/public-status to everyone unless
+	// publicStatusToken is set, in which case that token is required.
+	publicStatus      bool
+	publicStatusToken string
+	// authLogPath receives one line per failed login or lockout, in a
+	// format fail2ban can match. Empty disables the file.
+	authLogPath string
+	authLogMu   sync.Mutex
 }
 
 func NewAuthHandler(mgr *minecraft.Manager, baseDir string) *AuthHandler {
-	_ = baseDir
-	return &AuthHandler{
+	h := &AuthHandler{
 		mgr:            mgr,
 		sessions:       make(map[string]sessionRecord),
 		loginAttempts:  make(map[string]loginAttempt),
+		wsTickets:      make(map[string]wsTicket),
 		trustedProxies: newTrustedProxySetFromEnv(),
 		csrfMode:       csrfModeFromEnv(),
+		haToken:        haTokenFromEnv(),
+	}
+	h.publicStatus, h.publicStatusToken = publicStatusFromEnv()
+	if baseDir != "" {
+		h.authLogPath = filepath.Join(baseDir, "data", "auth.log")
+	}
+	if mgr != nil {
+		attempts, err := mgr.LoadLoginAttempts()
+		if err != nil {
+			log.Printf("Login lockouts not restored: %v", err)
+		} else {
+			h.loginAttempts = attempts
+		}
+		if len(mgr.GetSettings().AccessAllowList) > 0 && len(h.trustedProxies.nets) == 0 {
+			log.Printf("Warning: an access allow list is set but ADPANEL_TRUSTED_PROXIES is not; behind a reverse proxy every client has the proxy's address and is checked as such")
+		}
 	}
+	return h
 }
 
 func (h *AuthHandler) cleanupExpiredSessionsLocked() {
@@ -63,13 +125,7 @@ func (h *AuthHandler) cleanupExpiredSessionsLocked() {
 }
 
 func (h *AuthHandler) clientIP(r *http.Request) string {
-	if xff := strings.TrimSpace(r.Header.Get("X-Forwarded-For")); xff != "" {
-		return realClientIPFromXFF(r.RemoteAddr, xff, h.trustedProxies)
-	}
-	if ip := remoteAddrIP(r.RemoteAddr); ip != nil {
-		return ip.String()
-	}
-	return strings.TrimSpace(r.RemoteAddr)
+	return requestClientIP(r, h.trustedProxies)
 }
 
 func (h *AuthHandler) isSecureRequest(r *http.Request) bool {
@@ -100,26 +156,38 @@ func (h *AuthHandler) loginBlocked(ip string) (bool, time.Duration) {
 	return false, 0
 }
 
-func (h *AuthHandler) noteLoginFailure(ip string) {
+func (h *AuthHandler) noteLoginFailure(ip, username, reason string) {
+	policy := h.sessionPolicy()
 	now := time.Now()
 	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	attempt := h.loginAttempts[ip]
-	if attempt.WindowStart.IsZero() || now.Sub(attempt.WindowStart) > loginWindow {
+	if attempt.WindowStart.IsZero() || now.Sub(attempt.WindowStart) > policy.loginWindow {
 		attempt = loginAttempt{Count: 0, WindowStart: now}
 	}
 	attempt.Count++
-	if attempt.Count >= loginMaxFailures {
-		attempt.BlockedUntil = now.Add(loginBlockTime)
+	blocked := false
+	if attempt.Count >= policy.maxFailures {
+		attempt.BlockedUntil = now.Add(policy.loginBlock)
+		blocked = true
 	}
 	h.loginAttempts[ip] = attempt
+	h.mu.Unlock()
+
+	h.logAuthEvent(fmt.Sprintf("auth failure: ip=%s user=%q reason=%s", ip, username, reason))
+	if blocked {
+		h.logAuthEvent(fmt.Sprintf("auth blocked: ip=%s failures=%d until=%s", ip, attempt.Count, attempt.BlockedUntil.UTC().Format(time.RFC3339)))
+	}
+	h.persistLoginAttempts()
 }
 
 func (h *AuthHandler) clearLoginFailures(ip string) {
 	h.mu.Lock()
-	defer h.mu.Unlock()
+	_, existed := h.loginAttempts[ip]
 	delete(h.loginAttempts, ip)
+	h.mu.Unlock()
+	if existed {
+		h.persistLoginAttempts()
+	}
 }
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
@@ -130,7 +198,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 			seconds = 1
 		}
 		w.Header().Set("Retry-After", fmt.Sprintf("%d", seconds))
-		respondError(w, http.StatusTooManyRequests, "Too many failed login attempts. Try again later.")
+		respondCodedError(w, http.StatusTooManyRequests, codeLoginRateLimited, "Too many failed login attempts. Try again later.")
 		return
 	}
 
@@ -139,19 +207,20 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		Password string `json:"password"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 
 	req.Username = strings.TrimSpace(req.Username)
 	if req.Username == "" || req.Password == "" {
-		h.noteLoginFailure(ip)
-		respondError(w, http.StatusBadRequest, "Username and password are required")
+		h.noteLoginFailure(ip, req.Username, "missing_credentials")
+		respondCodedError(w, http.StatusBadRequest, codeCredentialsRequired, "Username and password are required")
 		return
 	}
-	if !h.mgr.ValidateLogin(req.Username, req.Password) {
-		h.noteLoginFailure(ip)
-		respondError(w, http.StatusUnauthorized, "Invalid credentials")
+	role, valid := h.mgr.AuthenticateUser(req.Username, req.Password)
+	if !valid {
+		h.noteLoginFailure(ip, req.Username, "invalid_credentials")
+		respondCodedError(w, http.StatusUnauthorized, codeInvalidCredentials, "Invalid credentials")
 		return
 	}
 	h.clearLoginFailures(ip)
@@ -162,13 +231,25 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusInternalServerError, "Failed to create session")
 		return
 	}
+	csrfToken, err := newSessionToken()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create session")
+		return
+	}
 
-	expires := time.Now().Add(sessionTTL)
+	policy := h.sessionPolicy()
+	now := time.Now()
+	expires := now.Add(policy.ttl)
 	h.mu.Lock()
 	h.sessions[token] = sessionRecord{
 		Username:           req.Username,
+		Role:               role,
+		Created:            now,
 		Expires:            expires,
 		MustChangePassword: mustChangePassword,
+		ClientIP:           ip,
+		UserAgent:          r.UserAgent(),
+		CSRFToken:          csrfToken,
 	}
 	h.mu.Unlock()
 
@@ -180,16 +261,37 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		Secure:   h.isSecureRequest(r),
 		SameSite: http.SameSiteLaxMode,
 		Expires:  expires,
-		MaxAge:   int(sessionTTL.Seconds()),
+		MaxAge:   int(policy.ttl.Seconds()),
 	})
+	h.setCSRFCookie(w, r, csrfToken, expires)
 
 	respondJSON(w, http.StatusOK, map[string]any{
 		"authenticated":      true,
 		"username":           req.Username,
+		"role":               role,
 		"mustChangePassword": mustChangePassword,
+		"csrfToken":          csrfToken,
 	})
 }
 
+// setCSRFCookie sends the CSRF token in a cookie the SPA can read. An empty
+// token clears it.
+func (h *AuthHandler) setCSRFCookie(w http.ResponseWriter, r *http.Request, token string, expires time.Time) {
+	cookie := &http.Cookie{
+		Name:     csrfCookieName,
+		Value:    token,
+		Path:     "/",
+		Secure:   h.isSecureRequest(r),
+		SameSite: http.SameSiteStrictMode,
+		Expires:  expires,
+	}
+	if token == "" {
+		cookie.Expires = time.Unix(0, 0)
+		cookie.MaxAge = -1
+	}
+	http.SetCookie(w, cookie)
+}
+
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	if c, err := r.Cookie(sessionCookieName); err == nil {
 		h.mu.Lock()
@@ -206,6 +308,7 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		Expires:  time.Unix(0, 0),
 		MaxAge:   -1,
 	})
+	h.setCSRFCookie(w, r, "", time.Time{})
 	respondJSON(w, http.StatusOK, map[string]bool{"authenticated": false})
 }
 
@@ -215,15 +318,26 @@ func (h *AuthHandler) Session(w http.ResponseWriter, r *http.Request) {
 		respondJSON(w, http.StatusOK, map[string]any{"authenticated": false})
 		return
 	}
+	// Re-send the token so a browser that lost the cookie can recover it.
+	h.setCSRFCookie(w, r, rec.CSRFToken, rec.Expires)
 	respondJSON(w, http.StatusOK, map[string]any{
 		"authenticated":      true,
 		"username":           rec.Username,
+		"role":               rec.Role,
 		"mustChangePassword": rec.MustChangePassword,
+		"csrfToken":          rec.CSRFToken,
 	})
 }
 
 func (h *AuthHandler) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.mgr != nil && !h.mgr.GetSettings().IPAccessPermitted(h.clientIP(r), directRequest(r)) {
+			respondJSON(w, http.StatusForbidden, map[string]string{
+				"error":   "ip_not_allowed",
+				"message": "Access to the panel is not allowed from this address.",
+			})
+			return
+		}
 		if r.Method == http.MethodOptions {
 			next.ServeHTTP(w, r)
 			return
@@ -234,14 +348,32 @@ func (h *AuthHandler) Middleware(next http.Handler) http.Handler {
 			next.ServeHTTP(w, r)
 			return
 		}
-		if path == "/api/auth/login" || path == "/api/auth/logout" || path == "/api/auth/session" || path == "/api/health" || path == "/api/ready" {
+		if path == "/api/auth/login" || path == "/api/auth/logout" || path == "/api/auth/session" || path == "/api/health" || path == "/api/ready" || path == cspReportPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Method == http.MethodGet && isIntegrationPath(path) && h.integrationTokenMatches(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.Method == http.MethodGet && path == "/api/public-status" && h.publicStatusAllowed(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.Method == http.MethodPost && isWebhookPath(path) {
 			next.ServeHTTP(w, r)
 			return
 		}
 
 		rec, ok := h.sessionFromRequest(r)
+		if !ok && r.Method == http.MethodGet && isWebSocketPath(path) {
+			if rec, ok = h.redeemWebSocketTicket(r); ok {
+				r = withWebSocketTicket(r)
+			}
+		}
 		if !ok {
-			respondError(w, http.StatusUnauthorized, "Authentication required")
+			respondCodedError(w, http.StatusUnauthorized, codeAuthenticationRequired, "Authentication required")
 			return
 		}
 		if rec.MustChangePassword && !h.isPasswordChangeAllowedRoute(path, r.Method) {
@@ -251,6 +383,14 @@ func (h *AuthHandler) Middleware(next http.Handler) http.Handler {
 			})
 			return
 		}
+		if !roleAllows(rec.Role, r.Method, path) {
+			respondJSON(w, http.StatusForbidden, map[string]string{
+				"error":   "role_forbidden",
+				"message": "Your account's role does not allow this action.",
+			})
+			return
+		}
+		r = withSessionUser(withSessionRole(r, rec.Role), rec.Username)
 		if isUnsafeHTTPMethod(r.Method) && !h.isCSRFIgnoredRoute(path) {
 			if !requestOriginMatchesCSRF(r, h.trustedProxies) {
 				if h.csrfMode == "report" {
@@ -264,6 +404,19 @@ func (h *AuthHandler) Middleware(next http.Handler) http.Handler {
 					return
 				}
 			}
+			// The origin check above trusts clients that send no Origin or
+			// Referer; the token closes that gap when CORS is opened up.
+			if !requestCSRFTokenMatches(r, rec.CSRFToken) {
+				if h.csrfMode == "report" {
+					log.Printf("CSRF report-only missing token: method=%s path=%s client_ip=%s", r.Method, path, h.clientIP(r))
+				} else if h.csrfMode == "enforce" {
+					respondJSON(w, http.StatusForbidden, map[string]string{
+						"error":   "csrf_token_invalid",
+						"message": "Missing or invalid CSRF token. Reload the page and try again.",
+					})
+					return
+				}
+			}
 		}
 		next.ServeHTTP(w, r)
 	})
@@ -273,7 +426,10 @@ func (h *AuthHandler) isPasswordChangeAllowedRoute(path, method string) bool {
 	if path == "/api/auth/logout" || path == "/api/auth/session" || path == "/api/health" || path == "/api/ready" {
 		return true
 	}
-	if path == "/api/settings" && (method == http.MethodPut || method == http.MethodGet) {
+	if path == "/api/settings" && (method == http.MethodPut || method == http.MethodPatch || method == http.MethodGet) {
+		return true
+	}
+	if path == "/api/settings/validate" && method == http.MethodPost {
 		return true
 	}
 	if path == "/api/system/usage" && method == http.MethodGet {
@@ -304,15 +460,26 @@ func (h *AuthHandler) sessionFromRequest(r *http.Request) (sessionRecord, bool)
 	if err != nil || c == nil || strings.TrimSpace(c.Value) == "" {
 		return sessionRecord{}, false
 	}
-	token := c.Value
+	return h.sessionFromToken(r, c.Value)
+}
 
+// sessionFromToken looks up a session by token and applies the expiry and
+// binding checks against r.
+func (h *AuthHandler) sessionFromToken(r *http.Request, token string) (sessionRecord, bool) {
 	h.mu.RLock()
 	rec, ok := h.sessions[token]
 	h.mu.RUnlock()
 	if !ok {
 		return sessionRecord{}, false
 	}
-	if time.Now().After(rec.Expires) {
+	// A lowered TTL also ends older sessions, and binding checks apply to
+	// sessions issued before binding was switched on.
+	policy := h.sessionPolicy()
+	now := time.Now()
+	expired := now.After(rec.Expires) || (!rec.Created.IsZero() && now.After(rec.Created.Add(policy.ttl)))
+	mismatch := (policy.bindIP && rec.ClientIP != h.clientIP(r)) ||
+		(policy.bindUserAgent && rec.UserAgent != r.UserAgent())
+	if expired || mismatch {
 		h.mu.Lock()
 		delete(h.sessions, token)
 		h.mu.Unlock()
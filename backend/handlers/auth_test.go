@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"minecraft-admin/minecraft"
 )
@@ -56,13 +59,14 @@ func TestDefaultCredentialGateAndUnlockFlow(t *testing.T) {
 
 	settingsReq := httptest.NewRequest(http.MethodPut, "/api/settings", nil)
 	settingsReq.AddCookie(sessionCookie)
+	settingsReq.Header.Set(csrfHeaderName, loginBody["csrfToken"].(string))
 	settingsRec := httptest.NewRecorder()
 	middleware.ServeHTTP(settingsRec, settingsReq)
 	if settingsRec.Code != http.StatusOK {
 		t.Fatalf("expected settings endpoint to be allowed during gate, got %d", settingsRec.Code)
 	}
 
-	if _, err := mgr.UpdateAppSettings("", "0.5", "1", "none", 3, 30, 15, 20, "adminuser", "strongpass123"); err != nil {
+	if _, _, err := mgr.UpdateAppSettings(minecraft.AppSettingsUpdate{DefaultMinRAM: "0.5", DefaultMaxRAM: "1", DefaultFlags: "none", LoginUser: "adminuser", LoginPassword: "strongpass123"}); err != nil {
 		t.Fatalf("UpdateAppSettings failed: %v", err)
 	}
 
@@ -103,6 +107,41 @@ func TestTrustedProxyForwardedHeaderUsage(t *testing.T) {
 	}
 }
 
+func TestAccessAllowListAppliesToSameHostProxy(t *testing.T) {
+	t.Setenv("ADPANEL_TRUSTED_PROXIES", "")
+	mgr, err := minecraft.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+	allow := []string{"10.0.0.0/8"}
+	if _, _, err := mgr.PatchAppSettings(minecraft.AppSettingsPatch{AccessAllowList: &allow, RequesterIP: "10.0.0.5"}); err != nil {
+		t.Fatalf("PatchAppSettings failed: %v", err)
+	}
+
+	handler := NewAuthHandler(mgr, "").Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	// A reverse proxy on the same host without trusted proxies: every
+	// client connects from loopback.
+	proxied := httptest.NewRequest(http.MethodGet, "/index.html", nil)
+	proxied.RemoteAddr = "127.0.0.1:9000"
+	proxied.Header.Set("X-Forwarded-For", "203.0.113.9")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, proxied)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected a proxied request to be checked against the allow list, got %d", rec.Code)
+	}
+
+	local := httptest.NewRequest(http.MethodGet, "/index.html", nil)
+	local.RemoteAddr = "127.0.0.1:9000"
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, local)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected a direct local request to be allowed, got %d", rec.Code)
+	}
+}
+
 func TestCSRFMiddlewareRejectsCrossOriginUnsafeRequest(t *testing.T) {
 	base := t.TempDir()
 	mgr, err := minecraft.NewManager(base)
@@ -112,7 +151,7 @@ func TestCSRFMiddlewareRejectsCrossOriginUnsafeRequest(t *testing.T) {
 	defer mgr.StopAll()
 
 	handler := NewAuthHandler(mgr, base)
-	if _, err := mgr.UpdateAppSettings("", "0.5", "1", "none", 3, 30, 15, 20, "adminuser", "strongpass123"); err != nil {
+	if _, _, err := mgr.UpdateAppSettings(minecraft.AppSettingsUpdate{DefaultMinRAM: "0.5", DefaultMaxRAM: "1", DefaultFlags: "none", LoginUser: "adminuser", LoginPassword: "strongpass123"}); err != nil {
 		t.Fatalf("UpdateAppSettings failed: %v", err)
 	}
 
@@ -147,3 +186,515 @@ func TestCSRFMiddlewareRejectsCrossOriginUnsafeRequest(t *testing.T) {
 		t.Fatalf("unexpected csrf error payload: %v", body)
 	}
 }
+
+func TestCSRFMiddlewareRequiresSessionToken(t *testing.T) {
+	base := t.TempDir()
+	mgr, err := minecraft.NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	handler := NewAuthHandler(mgr, base)
+	if _, _, err := mgr.UpdateAppSettings(minecraft.AppSettingsUpdate{DefaultMinRAM: "0.5", DefaultMaxRAM: "1", DefaultFlags: "none", LoginUser: "adminuser", LoginPassword: "strongpass123"}); err != nil {
+		t.Fatalf("UpdateAppSettings failed: %v", err)
+	}
+
+	loginReq := httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(`{"username":"adminuser","password":"strongpass123"}`))
+	loginRec := httptest.NewRecorder()
+	handler.Login(loginRec, loginReq)
+	if loginRec.Code != http.StatusOK {
+		t.Fatalf("expected login 200, got %d", loginRec.Code)
+	}
+	var sessionCookie, csrfCookie *http.Cookie
+	for _, cookie := range loginRec.Result().Cookies() {
+		switch cookie.Name {
+		case sessionCookieName:
+			sessionCookie = cookie
+		case csrfCookieName:
+			csrfCookie = cookie
+		}
+	}
+	if sessionCookie == nil || csrfCookie == nil || csrfCookie.HttpOnly || csrfCookie.Value == "" {
+		t.Fatalf("expected session cookie and a readable CSRF cookie, got %v", loginRec.Result().Cookies())
+	}
+
+	middleware := handler.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	send := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/servers/test/start", nil)
+		req.AddCookie(sessionCookie)
+		if token != "" {
+			req.Header.Set(csrfHeaderName, token)
+		}
+		rec := httptest.NewRecorder()
+		middleware.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send(""); rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "csrf_token_invalid") {
+		t.Fatalf("expected missing token to be rejected, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := send("wrong-token"); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected wrong token to be rejected, got %d", rec.Code)
+	}
+	if rec := send(csrfCookie.Value); rec.Code != http.StatusOK {
+		t.Fatalf("expected matching token to pass, got %d", rec.Code)
+	}
+
+	getReq := httptest.NewRequest(http.MethodGet, "/api/servers", nil)
+	getReq.AddCookie(sessionCookie)
+	getRec := httptest.NewRecorder()
+	middleware.ServeHTTP(getRec, getReq)
+	if getRec.Code != http.StatusOK {
+		t.Fatalf("expected safe methods to skip the token check, got %d", getRec.Code)
+	}
+}
+
+func TestSessionSecuritySettingsApplyToExistingSessions(t *testing.T) {
+	base := t.TempDir()
+	mgr, err := minecraft.NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	handler := NewAuthHandler(mgr, base)
+	if _, _, err := mgr.UpdateAppSettings(minecraft.AppSettingsUpdate{DefaultMinRAM: "0.5", DefaultMaxRAM: "1", DefaultFlags: "none", LoginUser: "adminuser", LoginPassword: "strongpass123"}); err != nil {
+		t.Fatalf("UpdateAppSettings failed: %v", err)
+	}
+
+	login := func() *http.Cookie {
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(`{"username":"adminuser","password":"strongpass123"}`))
+		req.Header.Set("User-Agent", "browser-a")
+		rec := httptest.NewRecorder()
+		handler.Login(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected login 200, got %d", rec.Code)
+		}
+		return rec.Result().Cookies()[0]
+	}
+	sessionValid := func(cookie *http.Cookie, userAgent string) bool {
+		req := httptest.NewRequest(http.MethodGet, "/api/auth/session", nil)
+		req.Header.Set("User-Agent", userAgent)
+		req.AddCookie(cookie)
+		_, ok := handler.sessionFromRequest(req)
+		return ok
+	}
+
+	cookie := login()
+	if !sessionValid(cookie, "browser-b") {
+		t.Fatalf("expected session to work from another user agent while binding is off")
+	}
+	bind := true
+	if _, _, err := mgr.PatchAppSettings(minecraft.AppSettingsPatch{SessionBindUserAgent: &bind}); err != nil {
+		t.Fatalf("PatchAppSettings failed: %v", err)
+	}
+	if sessionValid(cookie, "browser-b") {
+		t.Fatalf("expected existing session to be dropped for a different user agent")
+	}
+
+	cookie = login()
+	handler.mu.Lock()
+	rec := handler.sessions[cookie.Value]
+	rec.Created = time.Now().Add(-2 * time.Hour)
+	handler.sessions[cookie.Value] = rec
+	handler.mu.Unlock()
+	if !sessionValid(cookie, "browser-a") {
+		t.Fatalf("expected two-hour-old session to be valid under the default TTL")
+	}
+	ttl := 1
+	if _, _, err := mgr.PatchAppSettings(minecraft.AppSettingsPatch{SessionTTLHours: &ttl}); err != nil {
+		t.Fatalf("PatchAppSettings failed: %v", err)
+	}
+	if sessionValid(cookie, "browser-a") {
+		t.Fatalf("expected lowered TTL to end the older session")
+	}
+
+	failures := 3
+	if _, _, err := mgr.PatchAppSettings(minecraft.AppSettingsPatch{LoginMaxFailures: &failures}); err != nil {
+		t.Fatalf("PatchAppSettings failed: %v", err)
+	}
+	for i := 0; i < failures; i++ {
+		handler.noteLoginFailure("203.0.113.5", "adminuser", "invalid_credentials")
+	}
+	if blocked, _ := handler.loginBlocked("203.0.113.5"); !blocked {
+		t.Fatalf("expected lockout after %d failures", failures)
+	}
+}
+
+func TestLoginBlocksSurviveRestartAndCanBeCleared(t *testing.T) {
+	base := t.TempDir()
+	mgr, err := minecraft.NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	handler := NewAuthHandler(mgr, base)
+	for i := 0; i < 10; i++ {
+		handler.noteLoginFailure("198.51.100.7", "admin", "invalid_credentials")
+	}
+
+	restarted := NewAuthHandler(mgr, base)
+	if blocked, _ := restarted.loginBlocked("198.51.100.7"); !blocked {
+		t.Fatalf("expected the lockout to be restored after a restart")
+	}
+
+	logData, err := os.ReadFile(filepath.Join(base, "data", "auth.log"))
+	if err != nil {
+		t.Fatalf("expected auth log: %v", err)
+	}
+	if !strings.Contains(string(logData), `auth failure: ip=198.51.100.7 user="admin" reason=invalid_credentials`) ||
+		!strings.Contains(string(logData), "auth blocked: ip=198.51.100.7") {
+		t.Fatalf("unexpected auth log contents:\n%s", logData)
+	}
+
+	listRec := httptest.NewRecorder()
+	restarted.ListLoginBlocks(listRec, httptest.NewRequest(http.MethodGet, "/api/security/login-blocks", nil))
+	if !strings.Contains(listRec.Body.String(), `"ip":"198.51.100.7"`) || !strings.Contains(listRec.Body.String(), `"blocked":true`) {
+		t.Fatalf("expected the block to be listed, got %s", listRec.Body.String())
+	}
+
+	clearReq := httptest.NewRequest(http.MethodDelete, "/api/security/login-blocks/198.51.100.7", nil)
+	clearReq.SetPathValue("ip", "198.51.100.7")
+	clearRec := httptest.NewRecorder()
+	restarted.ClearLoginBlock(clearRec, clearReq)
+	if clearRec.Code != http.StatusOK {
+		t.Fatalf("expected clear to succeed, got %d", clearRec.Code)
+	}
+	if blocked, _ := NewAuthHandler(mgr, base).loginBlocked("198.51.100.7"); blocked {
+		t.Fatalf("expected the cleared block to stay cleared after a restart")
+	}
+}
+
+func TestWebSocketTicketIsSingleUse(t *testing.T) {
+	base := t.TempDir()
+	mgr, err := minecraft.NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	handler := NewAuthHandler(mgr, base)
+	if _, _, err := mgr.UpdateAppSettings(minecraft.AppSettingsUpdate{DefaultMinRAM: "0.5", DefaultMaxRAM: "1", DefaultFlags: "none", LoginUser: "adminuser", LoginPassword: "strongpass123"}); err != nil {
+		t.Fatalf("UpdateAppSettings failed: %v", err)
+	}
+	loginRec := httptest.NewRecorder()
+	handler.Login(loginRec, httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(`{"username":"adminuser","password":"strongpass123"}`)))
+	var sessionCookie *http.Cookie
+	for _, cookie := range loginRec.Result().Cookies() {
+		if cookie.Name == sessionCookieName {
+			sessionCookie = cookie
+		}
+	}
+	if sessionCookie == nil {
+		t.Fatalf("expected session cookie")
+	}
+
+	ticketReq := httptest.NewRequest(http.MethodPost, "/api/auth/ws-ticket", nil)
+	ticketReq.AddCookie(sessionCookie)
+	ticketRec := httptest.NewRecorder()
+	handler.IssueWebSocketTicket(ticketRec, ticketReq)
+	var minted struct {
+		Ticket string `json:"ticket"`
+	}
+	if err := json.Unmarshal(ticketRec.Body.Bytes(), &minted); err != nil || minted.Ticket == "" {
+		t.Fatalf("expected a ticket, got %d %s", ticketRec.Code, ticketRec.Body.String())
+	}
+
+	var sawTicket bool
+	middleware := handler.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawTicket = hasWebSocketTicket(r)
+		w.WriteHeader(http.StatusOK)
+	}))
+	open := func(path string) int {
+		rec := httptest.NewRecorder()
+		middleware.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	if code := open("/api/servers?ticket=" + minted.Ticket); code != http.StatusUnauthorized {
+		t.Fatalf("expected ticket to be refused outside WebSocket routes, got %d", code)
+	}
+	if code := open("/api/logs/test?ticket=" + minted.Ticket); code != http.StatusOK || !sawTicket {
+		t.Fatalf("expected ticket to open the socket, got %d (ticket=%v)", code, sawTicket)
+	}
+	if code := open("/api/logs/test?ticket=" + minted.Ticket); code != http.StatusUnauthorized {
+		t.Fatalf("expected reused ticket to be rejected, got %d", code)
+	}
+}
+
+func TestIntegrationTokenAllowsOnlyHomeAssistantReads(t *testing.T) {
+	t.Setenv("ADPANEL_HA_TOKEN", "ha-secret")
+	base := t.TempDir()
+	mgr, err := minecraft.NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	integrations := NewIntegrationHandler(mgr)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/integrations/ha", integrations.HomeAssistantList)
+	mux.HandleFunc("GET /api/integrations/ha/{id}", integrations.HomeAssistantServer)
+	mux.HandleFunc("GET /api/servers", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	middleware := NewAuthHandler(mgr, base).Middleware(mux)
+
+	send := func(method, path, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		middleware.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send(http.MethodGet, "/api/integrations/ha", "Bearer ha-secret"); rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Fatalf("expected 200 with an empty list, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := send(http.MethodGet, "/api/integrations/ha/missing", "Bearer ha-secret"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown server, got %d", rec.Code)
+	}
+	if rec := send(http.MethodGet, "/api/integrations/ha", "Bearer wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a wrong token, got %d", rec.Code)
+	}
+	if rec := send(http.MethodGet, "/api/servers", "Bearer ha-secret"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected the token to be rejected outside integrations, got %d", rec.Code)
+	}
+}
+
+func TestPublicStatusAccess(t *testing.T) {
+	base := t.TempDir()
+	mgr, err := minecraft.NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	integrations := NewIntegrationHandler(mgr)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/public-status", integrations.PublicStatus)
+	send := func(middleware http.Handler, path, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		middleware.ServeHTTP(rec, req)
+		return rec
+	}
+
+	closed := NewAuthHandler(mgr, base).Middleware(mux)
+	if rec := send(closed, "/api/public-status", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected the status to need a session by default, got %d", rec.Code)
+	}
+
+	t.Setenv("ADPANEL_PUBLIC_STATUS", "true")
+	open := NewAuthHandler(mgr, base).Middleware(mux)
+	if rec := send(open, "/api/public-status", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"servers":[]`) {
+		t.Fatalf("expected a public empty status, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := send(open, "/api/public-status?server=missing", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown server, got %d", rec.Code)
+	}
+
+	t.Setenv("ADPANEL_PUBLIC_STATUS_TOKEN", "status-secret")
+	tokened := NewAuthHandler(mgr, base).Middleware(mux)
+	if rec := send(tokened, "/api/public-status", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected the token to be required, got %d", rec.Code)
+	}
+	if rec := send(tokened, "/api/public-status", "Bearer status-secret"); rec.Code != http.StatusOK {
+		t.Fatalf("expected the bearer token to be accepted, got %d", rec.Code)
+	}
+	if rec := send(tokened, "/api/public-status?token=status-secret", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected the query token to be accepted, got %d", rec.Code)
+	}
+}
+
+func TestWebhookReceiverSkipsSessionButNeedsSecret(t *testing.T) {
+	base := t.TempDir()
+	mgr, err := minecraft.NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/hooks/{id}/{hookId}", NewIntegrationHandler(mgr).ReceiveWebhook)
+	handler := NewAuthHandler(mgr, base).Middleware(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/hooks/srv1/abc?secret=nope", strings.NewReader(`{"player":"Steve"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), "wrong secret") {
+		t.Fatalf("expected the receiver to refuse an unknown webhook, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestStatusPageIsPublicAndFramable(t *testing.T) {
+	base := t.TempDir()
+	mgr, err := minecraft.NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", NewIntegrationHandler(mgr).StatusPage)
+	handler := SecurityHeaders(mgr, NewAuthHandler(mgr, base).Middleware(mux))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 while no server is shown, got %d", rec.Code)
+	}
+	if rec.Header().Get("X-Frame-Options") != "" || !strings.Contains(rec.Header().Get("Content-Security-Policy"), "frame-ancestors *") {
+		t.Fatalf("expected the status page to be framable, got %v", rec.Header())
+	}
+}
+
+func TestRoleAllows(t *testing.T) {
+	cases := []struct {
+		role, method, path string
+		want               bool
+	}{
+		{minecraft.RoleAdmin, http.MethodDelete, "/api/servers/lobby", true},
+		{minecraft.RoleAdmin, http.MethodPost, "/api/users", true},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/lobby/start", true},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/lobby/suspend", true},
+		{minecraft.RoleViewer, http.MethodPost, "/api/servers/lobby/resume", false},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/modpack", false},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/modpack/curseforge", false},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/lobby/command", true},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/lobby/players/Steve/kick", true},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/lobby/players/Steve/send", true},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/lobby/join-check", true},
+		{minecraft.RoleOperator, http.MethodPut, "/api/servers/lobby/join-check", false},
+		{minecraft.RoleOperator, http.MethodDelete, "/api/servers/lobby/tempbans/Steve", true},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/lobby/whitelist", true},
+		{minecraft.RoleOperator, http.MethodDelete, "/api/servers/lobby/bans/203.0.113.9", true},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/lobby/ops", false},
+		{minecraft.RoleOperator, http.MethodDelete, "/api/servers/lobby/ops/Steve", false},
+		{minecraft.RoleViewer, http.MethodPost, "/api/servers/lobby/bans", false},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/lobby/tasks", false},
+		{minecraft.RoleOperator, http.MethodPut, "/api/servers/lobby/datapacks/terralith.zip/toggle", false},
+		{minecraft.RoleViewer, http.MethodGet, "/api/servers/lobby/datapacks", true},
+		{minecraft.RoleOperator, http.MethodDelete, "/api/servers/lobby/worlds/old", false},
+		{minecraft.RoleOperator, http.MethodPut, "/api/servers/lobby/worlds/active", false},
+		{minecraft.RoleViewer, http.MethodGet, "/api/servers/lobby/worlds", true},
+		{minecraft.RoleOperator, http.MethodPut, "/api/servers/lobby/restart-on-crash", false},
+		{minecraft.RoleOperator, http.MethodPut, "/api/servers/lobby/restart-verification", false},
+		{minecraft.RoleOperator, http.MethodPut, "/api/servers/lobby/disk-quota", false},
+		{minecraft.RoleViewer, http.MethodGet, "/api/servers/lobby/disk-quota", true},
+		{minecraft.RoleOperator, http.MethodPut, "/api/servers/proxy/network", false},
+		{minecraft.RoleViewer, http.MethodGet, "/api/networks", true},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/import", false},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/lobby/export", false},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/import/archive", false},
+		{minecraft.RoleViewer, http.MethodGet, "/api/servers/lobby/export/Lobby_2026-01-01_00-00-00.adpanel.tar.gz", false},
+		{minecraft.RoleOperator, http.MethodGet, "/api/servers/lobby/export/Lobby_2026-01-01_00-00-00.adpanel.tar.gz", true},
+		{minecraft.RoleOperator, http.MethodPut, "/api/servers/lobby/status-page", false},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/lobby/webhooks", false},
+		{minecraft.RoleOperator, http.MethodDelete, "/api/servers/lobby", false},
+		{minecraft.RoleOperator, http.MethodPut, "/api/settings", false},
+		{minecraft.RoleOperator, http.MethodPut, "/api/moderation/presets", false},
+		{minecraft.RoleOperator, http.MethodGet, "/api/users", false},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/corrupt/key/recover", false},
+		{minecraft.RoleOperator, http.MethodPost, "/api/servers/corrupt/key/discard", false},
+		{minecraft.RoleViewer, http.MethodGet, "/api/servers", true},
+		{minecraft.RoleViewer, http.MethodPost, "/api/auth/ws-ticket", true},
+		{minecraft.RoleViewer, http.MethodPost, "/api/servers/lobby/start", false},
+		{minecraft.RoleViewer, http.MethodGet, "/api/servers/lobby/files/content", false},
+		{minecraft.RoleViewer, http.MethodGet, "/api/servers/lobby/files/delete-preview", true},
+		{minecraft.RoleViewer, http.MethodGet, "/api/servers/lobby/plugins/EssentialsX.jar/config", true},
+		{minecraft.RoleViewer, http.MethodGet, "/api/servers/lobby/plugins/EssentialsX.jar/config/content", false},
+		{minecraft.RoleOperator, http.MethodPut, "/api/servers/lobby/plugins/EssentialsX.jar/config/content", false},
+		{minecraft.RoleViewer, http.MethodGet, "/api/security/login-blocks", false},
+		{minecraft.RoleOperator, http.MethodGet, "/api/backup-targets", false},
+		{"", http.MethodGet, "/api/servers", false},
+	}
+	for _, c := range cases {
+		if got := roleAllows(c.role, c.method, c.path); got != c.want {
+			t.Fatalf("roleAllows(%q, %s, %s) = %v, want %v", c.role, c.method, c.path, got, c.want)
+		}
+	}
+}
+
+func TestMiddlewareEnforcesUserRoles(t *testing.T) {
+	base := t.TempDir()
+	mgr, err := minecraft.NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+	if _, _, err := mgr.UpdateAppSettings(minecraft.AppSettingsUpdate{DefaultMinRAM: "0.5", DefaultMaxRAM: "1", DefaultFlags: "none", LoginUser: "adminuser", LoginPassword: "strongpass123"}); err != nil {
+		t.Fatalf("UpdateAppSettings failed: %v", err)
+	}
+	if _, err := mgr.CreateUser(minecraft.UserInput{Username: "watcher", Password: "watchpass123", Role: minecraft.RoleViewer}); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+
+	handler := NewAuthHandler(mgr, base)
+	loginRec := httptest.NewRecorder()
+	handler.Login(loginRec, httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(`{"username":"watcher","password":"watchpass123"}`)))
+	var login struct {
+		Role      string `json:"role"`
+		CSRFToken string `json:"csrfToken"`
+	}
+	if err := json.Unmarshal(loginRec.Body.Bytes(), &login); err != nil || login.Role != minecraft.RoleViewer {
+		t.Fatalf("expected viewer login, got %d %s", loginRec.Code, loginRec.Body.String())
+	}
+	var sessionCookie *http.Cookie
+	for _, cookie := range loginRec.Result().Cookies() {
+		if cookie.Name == sessionCookieName {
+			sessionCookie = cookie
+		}
+	}
+
+	var readOnly, admin bool
+	middleware := handler.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		readOnly = !canSendCommands(r)
+		admin = isAdminSession(r)
+		w.WriteHeader(http.StatusOK)
+	}))
+	send := func(method, path string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.AddCookie(sessionCookie)
+		req.Header.Set("X-CSRF-Token", login.CSRFToken)
+		rec := httptest.NewRecorder()
+		middleware.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := send(http.MethodGet, "/api/servers"); code != http.StatusOK || !readOnly || admin {
+		t.Fatalf("expected viewer read to pass as read-only, got %d (readOnly=%v, admin=%v)", code, readOnly, admin)
+	}
+	if code := send(http.MethodPost, "/api/servers/lobby/stop"); code != http.StatusForbidden {
+		t.Fatalf("expected viewer stop to be forbidden, got %d", code)
+	}
+
+	operator := minecraft.RoleOperator
+	if _, err := mgr.UpdateUser("watcher", minecraft.UserUpdate{Role: &operator}); err != nil {
+		t.Fatalf("UpdateUser failed: %v", err)
+	}
+	handler.setUserSessionRole("watcher", operator)
+	if code := send(http.MethodPost, "/api/servers/lobby/stop"); code != http.StatusOK {
+		t.Fatalf("expected operator stop to pass, got %d", code)
+	}
+
+	deleteReq := httptest.NewRequest(http.MethodDelete, "/api/users/watcher", nil)
+	deleteReq.SetPathValue("username", "watcher")
+	deleteRec := httptest.NewRecorder()
+	handler.DeleteUser(deleteRec, deleteReq)
+	if deleteRec.Code != http.StatusOK {
+		t.Fatalf("DeleteUser failed: %d %s", deleteRec.Code, deleteRec.Body.String())
+	}
+	if code := send(http.MethodGet, "/api/servers"); code != http.StatusUnauthorized {
+		t.Fatalf("expected a deleted user's session to end, got %d", code)
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+
+	"minecraft-admin/minecraft"
+)
+
+// ListTargets handles GET /api/backup-targets. Secrets are left out.
+func (h *BackupHandler) ListTargets(w http.ResponseWriter, r *http.Request) {
+	targets, err := h.mgr.ListBackupTargets()
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, targets)
+}
+
+// SetTargets handles PUT /api/backup-targets
+func (h *BackupHandler) SetTargets(w http.ResponseWriter, r *http.Request) {
+	var req []minecraft.BackupTarget
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	targets, err := h.mgr.SetBackupTargets(req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, targets)
+}
+
+// TestTarget handles POST /api/backup-targets/{targetId}/test
+func (h *BackupHandler) TestTarget(w http.ResponseWriter, r *http.Request) {
+	if err := h.mgr.TestBackupTarget(r.Context(), r.PathValue("targetId")); err != nil {
+		respondErr(w, http.StatusBadGateway, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// GetServerTargets handles GET /api/servers/{id}/backup-targets
+func (h *BackupHandler) GetServerTargets(w http.ResponseWriter, r *http.Request) {
+	targets, err := h.mgr.GetServerBackupTargets(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string][]string{"targets": targets})
+}
+
+// SetServerTargets handles PUT /api/servers/{id}/backup-targets
+func (h *BackupHandler) SetServerTargets(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Targets []string `json:"targets"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	targets, err := h.mgr.SetServerBackupTargets(r.PathValue("id"), req.Targets)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string][]string{"targets": targets})
+}
+
+// Upload handles POST /api/servers/{id}/backups/{name}/upload, which sends
+// a backup to the server's targets now, for example to retry a failed
+// upload. It returns the upload job.
+func (h *BackupHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	job, err := h.mgr.UploadBackup(r.PathValue("id"), r.PathValue("name"))
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusAccepted, job)
+}
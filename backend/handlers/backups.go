@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"minecraft-admin/minecraft"
@@ -21,21 +22,44 @@ func (h *BackupHandler) List(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	backups, err := h.mgr.ListBackups(id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, err.Error())
+		respondErr(w, http.StatusNotFound, err)
 		return
 	}
 	respondJSON(w, http.StatusOK, backups)
 }
 
-// Create handles POST /api/servers/{id}/backups
+// Create handles POST /api/servers/{id}/backups with an optional
+// {"mode":"incremental"}. The backup runs as a job; poll it with
+// GET /api/servers/{id}/backup-jobs/{jobId}.
 func (h *BackupHandler) Create(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
-	backup, err := h.mgr.CreateBackup(id)
+	var req struct {
+		Mode string `json:"mode"`
+	}
+	if err := decodeJSONOptional(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	job, err := h.mgr.StartBackup(id, req.Mode)
+	if errors.Is(err, minecraft.ErrDiskQuotaExceeded) {
+		respondErr(w, http.StatusInsufficientStorage, err)
+		return
+	}
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, http.StatusInternalServerError, err)
 		return
 	}
-	respondJSON(w, http.StatusCreated, backup)
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// Job handles GET /api/servers/{id}/backup-jobs/{jobId}
+func (h *BackupHandler) Job(w http.ResponseWriter, r *http.Request) {
+	job, err := h.mgr.GetBackupJob(r.PathValue("id"), r.PathValue("jobId"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, job)
 }
 
 // Delete handles DELETE /api/servers/{id}/backups/{name}
@@ -44,7 +68,7 @@ func (h *BackupHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	name := r.PathValue("name")
 
 	if err := h.mgr.DeleteBackup(id, name); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -58,7 +82,7 @@ func (h *BackupHandler) Download(w http.ResponseWriter, r *http.Request) {
 
 	backupPath, err := h.mgr.GetBackupPath(id, name)
 	if err != nil {
-		respondError(w, http.StatusNotFound, err.Error())
+		respondErr(w, http.StatusNotFound, err)
 		return
 	}
 
@@ -72,12 +96,13 @@ func (h *BackupHandler) Restore(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	name := r.PathValue("name")
 
-	if err := h.mgr.RestoreBackup(id, name); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	job, err := h.mgr.StartRestoreBackup(id, name)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"status": "restored"})
+	respondJSON(w, http.StatusAccepted, job)
 }
 
 // GetSchedule handles GET /api/servers/{id}/backup-schedule
@@ -85,7 +110,7 @@ func (h *BackupHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	info, err := h.mgr.GetBackupSchedule(id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, err.Error())
+		respondErr(w, http.StatusNotFound, err)
 		return
 	}
 	respondJSON(w, http.StatusOK, info)
@@ -96,14 +121,15 @@ func (h *BackupHandler) SetSchedule(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	var req struct {
 		Schedule string `json:"schedule"`
+		Mode     string `json:"mode"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 
-	if err := h.mgr.SetBackupSchedule(id, req.Schedule); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	if err := h.mgr.SetBackupSchedule(id, req.Schedule, req.Mode); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressMinBytes is the smallest response worth compressing; below it the
+// encoding overhead eats the saving.
+const compressMinBytes = 1024
+
+var (
+	gzipWriters = sync.Pool{New: func() any {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	}}
+	// Browsers refuse zstd responses with a window over 8 MiB.
+	zstdWriters = sync.Pool{New: func() any {
+		w, _ := zstd.NewWriter(nil, zstd.WithWindowSize(8<<20), zstd.WithEncoderConcurrency(1))
+		return w
+	}}
+)
+
+// Compress encodes API responses with zstd or gzip when the client accepts
+// them. Only text-like bodies of at least compressMinBytes are encoded, so
+// downloads of archives and jars pass through untouched, as do WebSocket
+// upgrades and range requests.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") || r.Method == http.MethodHead || r.Header.Get("Range") != "" ||
+			strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Accept-Encoding")
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, status: http.StatusOK}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks zstd or gzip from an Accept-Encoding header by
+// quality, preferring zstd on a tie. It returns "" when neither is
+// acceptable.
+func negotiateEncoding(header string) string {
+	quality := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		quality[name] = q
+	}
+	best, bestQ := "", 0.0
+	for _, name := range []string{"zstd", "gzip"} {
+		q, ok := quality[name]
+		if !ok {
+			q, ok = quality["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// compressibleType reports whether a Content-Type is text that compresses
+// well.
+func compressibleType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if mediaType == "text/event-stream" {
+		return false
+	}
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/javascript", "application/xml", "image/svg+xml":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+json")
+}
+
+// compressWriter holds back the first compressMinBytes of a response to
+// decide whether to encode it.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         []byte
+	enc         io.WriteCloser
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = status
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		cw.passThrough()
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.decided {
+		if cw.enc != nil {
+			return cw.enc.Write(p)
+		}
+		return cw.ResponseWriter.Write(p)
+	}
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= compressMinBytes {
+		if err := cw.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// decide starts encoding when the response is eligible and big enough, or
+// when a flush asks for streaming, and otherwise passes it through.
+func (cw *compressWriter) decide(bigEnough bool) error {
+	header := cw.Header()
+	if header.Get("Content-Type") == "" && len(cw.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(cw.buf))
+	}
+	if !bigEnough || header.Get("Content-Encoding") != "" || !compressibleType(header.Get("Content-Type")) {
+		return cw.passThrough()
+	}
+
+	cw.decided = true
+	header.Set("Content-Encoding", cw.encoding)
+	header.Del("Content-Length")
+	cw.ResponseWriter.WriteHeader(cw.status)
+	switch cw.encoding {
+	case "zstd":
+		enc := zstdWriters.Get().(*zstd.Encoder)
+		enc.Reset(cw.ResponseWriter)
+		cw.enc = enc
+	default:
+		enc := gzipWriters.Get().(*gzip.Writer)
+		enc.Reset(cw.ResponseWriter)
+		cw.enc = enc
+	}
+	buf := cw.buf
+	cw.buf = nil
+	_, err := cw.enc.Write(buf)
+	return err
+}
+
+func (cw *compressWriter) passThrough() error {
+	if cw.decided {
+		return nil
+	}
+	cw.decided = true
+	cw.ResponseWriter.WriteHeader(cw.status)
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := cw.ResponseWriter.Write(buf)
+	return err
+}
+
+// Flush sends what is buffered so far, compressed if the response is
+// eligible, so streamed responses keep streaming.
+func (cw *compressWriter) Flush() {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.decided {
+		_ = cw.decide(true)
+	}
+	switch enc := cw.enc.(type) {
+	case *gzip.Writer:
+		_ = enc.Flush()
+	case *zstd.Encoder:
+		_ = enc.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+func (cw *compressWriter) close() {
+	if !cw.decided {
+		if !cw.wroteHeader && len(cw.buf) == 0 {
+			// Nothing was written; let net/http send its default reply.
+			return
+		}
+		_ = cw.decide(len(cw.buf) >= compressMinBytes)
+	}
+	if cw.enc == nil {
+		return
+	}
+	_ = cw.enc.Close()
+	switch enc := cw.enc.(type) {
+	case *gzip.Writer:
+		enc.Reset(io.Discard)
+		gzipWriters.Put(enc)
+	case *zstd.Encoder:
+		enc.Reset(nil)
+		zstdWriters.Put(enc)
+	}
+	cw.enc = nil
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	cases := map[string]string{
+		"":                         "",
+		"gzip, deflate, br":        "gzip",
+		"gzip, deflate, br, zstd":  "zstd",
+		"zstd;q=0.5, gzip":         "gzip",
+		"zstd;q=0, gzip;q=0":       "",
+		"*":                        "zstd",
+		"identity":                 "",
+		"GZIP;q=0.8, zstd;q=bogus": "gzip",
+	}
+	for header, want := range cases {
+		if got := negotiateEncoding(header); got != want {
+			t.Fatalf("negotiateEncoding(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestCompressEncodesLargeTextResponses(t *testing.T) {
+	body := strings.Repeat(`{"line":"[12:00:00 INFO]: Done"},`, 200)
+	handler := Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/small":
+			respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		case "/api/download":
+			w.Header().Set("Content-Type", "application/zip")
+			w.Write([]byte(body))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Length", "999999")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(body[:100]))
+			w.Write([]byte(body[100:]))
+		}
+	}))
+
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/api/logs", "gzip")
+	if rec.Code != http.StatusCreated || rec.Header().Get("Content-Encoding") != "gzip" || rec.Header().Get("Content-Length") != "" {
+		t.Fatalf("expected a gzip response, got status=%d headers=%v", rec.Code, rec.Header())
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader failed: %v", err)
+	}
+	if data, _ := io.ReadAll(zr); string(data) != body {
+		t.Fatalf("gzip body did not round-trip")
+	}
+
+	rec = get("/api/logs", "gzip, zstd")
+	if rec.Header().Get("Content-Encoding") != "zstd" {
+		t.Fatalf("expected a zstd response, got headers=%v", rec.Header())
+	}
+	dec, err := zstd.NewReader(bytes.NewReader(rec.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("zstd.NewReader failed: %v", err)
+	}
+	defer dec.Close()
+	if data, _ := io.ReadAll(dec); string(data) != body {
+		t.Fatalf("zstd body did not round-trip")
+	}
+
+	if rec := get("/api/small", "gzip"); rec.Header().Get("Content-Encoding") != "" || !strings.Contains(rec.Body.String(), `"ok"`) {
+		t.Fatalf("expected a small response to pass through, got headers=%v body=%q", rec.Header(), rec.Body.String())
+	}
+	if rec := get("/api/download", "gzip"); rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != body {
+		t.Fatalf("expected an archive to pass through, got headers=%v", rec.Header())
+	}
+	if rec := get("/api/logs", ""); rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != body {
+		t.Fatalf("expected no encoding without Accept-Encoding, got headers=%v", rec.Header())
+	}
+}
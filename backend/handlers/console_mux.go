@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// maxConsoleSubscriptions caps how many server streams one multiplexed
+// socket may follow at once.
+const maxConsoleSubscriptions = 50
+
+// wsClientMessage is a request sent on the multiplexed console socket.
+type wsClientMessage struct {
+	Type     string `json:"type"`
+	ServerID string `json:"serverId"`
+	LastSeq  uint64 `json:"lastSeq,omitempty"`
+	Command  string `json:"command,omitempty"`
+}
+
+// runConsoleCommand sends a console command and records it in the log.
+func (h *MinecraftHandler) runConsoleCommand(id, command string) error {
+	if err := h.mgr.SendCommand(id, command); err != nil {
+		return err
+	}
+	if err := h.mgr.RecordConsoleCommand(id, command); err != nil {
+		log.Printf("Failed to record command in console for server %s: %v", id, err)
+	}
+	return nil
+}
+
+// WebSocketConsole returns an HTTP handler for a single socket that follows
+// several server consoles. Clients send subscribe, unsubscribe and command
+// messages naming a serverId; every message sent back carries the serverId
+// it belongs to.
+func (h *MinecraftHandler) WebSocketConsole() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := h.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Printf("WebSocket upgrade failed for console stream: %v", err)
+			return
+		}
+		defer conn.Close()
+		defer trackWebSocket()()
+
+		out := make(chan wsMessage, 256)
+		done := make(chan struct{})
+		send := func(msg wsMessage) {
+			select {
+			case out <- msg:
+			case <-done:
+			}
+		}
+
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		})
+
+		// Read goroutine: owns the subscriptions and handles client requests.
+		go func() {
+			defer close(done)
+			subs := make(map[string]func())
+			defer func() {
+				for _, cancel := range subs {
+					cancel()
+				}
+			}()
+
+			subscribe := func(id string, lastSeq uint64) {
+				if cancel, ok := subs[id]; ok {
+					cancel()
+					delete(subs, id)
+				}
+				if len(subs) >= maxConsoleSubscriptions {
+					send(wsMessage{Type: "error", ServerID: id, Message: "Too many console subscriptions"})
+					return
+				}
+				if _, err := h.mgr.GetStatus(id); err != nil {
+					send(wsMessage{Type: "error", ServerID: id, Message: "Server not found"})
+					return
+				}
+				snapshot, reset, logCh, unsubscribe := h.mgr.SubscribeLogsWithSnapshot(id, lastSeq)
+				stop := make(chan struct{})
+				subs[id] = func() {
+					close(stop)
+					unsubscribe()
+				}
+				send(wsMessage{Type: "snapshot", ServerID: id, Entries: snapshot, Reset: reset})
+				sentSeq := streamLastSeq(lastSeq, snapshot, reset)
+				go func() {
+					for {
+						select {
+						case entry, ok := <-logCh:
+							if !ok {
+								// Not running, or too slow to keep up: the client
+								// resubscribes from the lastSeq it has.
+								send(wsMessage{Type: "end", ServerID: id, LastSeq: sentSeq})
+								return
+							}
+							send(wsMessage{Type: "log", ServerID: id, Seq: entry.Seq, Line: entry.Line, Dropped: entry.Dropped})
+							if entry.Dropped == 0 {
+								sentSeq = entry.Seq
+							}
+						case <-stop:
+							return
+						case <-done:
+							return
+						}
+					}
+				}()
+			}
+
+			for {
+				_, raw, err := conn.ReadMessage()
+				if err != nil {
+					if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+						log.Printf("WebSocket read error for console stream: %v", err)
+					}
+					return
+				}
+				conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+				var msg wsClientMessage
+				if err := json.Unmarshal(raw, &msg); err != nil {
+					send(wsMessage{Type: "error", Message: "Invalid message"})
+					continue
+				}
+				id := strings.TrimSpace(msg.ServerID)
+				if id == "" {
+					send(wsMessage{Type: "error", Message: "serverId is required"})
+					continue
+				}
+				switch msg.Type {
+				case "subscribe":
+					subscribe(id, msg.LastSeq)
+				case "unsubscribe":
+					if cancel, ok := subs[id]; ok {
+						cancel()
+						delete(subs, id)
+					}
+					send(wsMessage{Type: "unsubscribed", ServerID: id})
+				case "command":
+					if _, ok := subs[id]; !ok {
+						send(wsMessage{Type: "error", ServerID: id, Message: "Subscribe to the server before sending commands"})
+						continue
+					}
+					if !canSendCommands(r) {
+						send(wsMessage{Type: "error", ServerID: id, Message: "Your account's role cannot send commands"})
+						continue
+					}
+					command := strings.TrimSpace(msg.Command)
+					if command == "" {
+						continue
+					}
+					if err := h.runConsoleCommand(id, command); err != nil {
+						log.Printf("Failed to send command to server %s: %v", id, err)
+						send(wsMessage{Type: "error", ServerID: id, Message: err.Error()})
+					}
+				default:
+					send(wsMessage{Type: "error", ServerID: id, Message: "Unknown message type"})
+				}
+			}
+		}()
+
+		ping := time.NewTicker(wsPingPeriod)
+		defer ping.Stop()
+
+		// Write loop: the only writer on conn.
+		for {
+			select {
+			case msg := <-out:
+				conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+				if err := conn.WriteJSON(msg); err != nil {
+					log.Printf("WebSocket write error for console stream: %v", err)
+					return
+				}
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+					return
+				}
+			case <-done:
+				// Clients resubscribe with the lastSeq of each server's
+				// newest log message.
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "stream ending"), time.Now().Add(wsWriteWait))
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"minecraft-admin/minecraft"
+)
+
+func TestConsoleSocketTagsRepliesWithServerID(t *testing.T) {
+	mgr, err := minecraft.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	srv := httptest.NewServer(NewMinecraftHandler(mgr).WebSocketConsole())
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), map[string][]string{"Origin": {srv.URL}})
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	exchange := func(req wsClientMessage) wsMessage {
+		if err := conn.WriteJSON(req); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		var reply wsMessage
+		if err := conn.ReadJSON(&reply); err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		return reply
+	}
+
+	if reply := exchange(wsClientMessage{Type: "subscribe", ServerID: "missing"}); reply.Type != "error" || reply.ServerID != "missing" {
+		t.Fatalf("expected unknown server error, got %+v", reply)
+	}
+	if reply := exchange(wsClientMessage{Type: "command", ServerID: "other", Command: "list"}); reply.Type != "error" || reply.ServerID != "other" {
+		t.Fatalf("expected command without subscription to fail, got %+v", reply)
+	}
+	if reply := exchange(wsClientMessage{Type: "unsubscribe", ServerID: "other"}); reply.Type != "unsubscribed" || reply.ServerID != "other" {
+		t.Fatalf("expected unsubscribe ack, got %+v", reply)
+	}
+	if reply := exchange(wsClientMessage{Type: "subscribe"}); reply.Type != "error" || reply.ServerID != "" {
+		t.Fatalf("expected missing serverId error, got %+v", reply)
+	}
+}
+
+func TestEndStreamReportsLastSeq(t *testing.T) {
+	snapshot := []minecraft.ConsoleLogEntry{{Seq: 8, Line: "a"}, {Seq: 9, Line: "b"}}
+	if got := streamLastSeq(7, snapshot, false); got != 9 {
+		t.Fatalf("expected lastSeq 9 after snapshot, got %d", got)
+	}
+	if got := streamLastSeq(40, nil, true); got != 0 {
+		t.Fatalf("expected reset stream to start over, got %d", got)
+	}
+
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		endStream(conn, 9, websocket.CloseNormalClosure)
+	}))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var end wsMessage
+	if err := conn.ReadJSON(&end); err != nil || end.Type != "end" || end.LastSeq != 9 {
+		t.Fatalf("expected end with lastSeq 9, got %+v (%v)", end, err)
+	}
+	_, _, err = conn.ReadMessage()
+	closeErr, ok := err.(*websocket.CloseError)
+	if !ok || closeErr.Code != websocket.CloseNormalClosure || closeErr.Text != "stream ending, lastSeq=9" {
+		t.Fatalf("expected close frame with lastSeq, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"minecraft-admin/minecraft"
+)
+
+// coreProtectQueryFromURL reads a lookup from the query string.
+func coreProtectQueryFromURL(values url.Values) (minecraft.CoreProtectQuery, error) {
+	q := minecraft.CoreProtectQuery{
+		Player: values.Get("player"),
+		World:  values.Get("world"),
+		Action: values.Get("action"),
+	}
+	optional := func(key string) (*int, error) {
+		raw := strings.TrimSpace(values.Get(key))
+		if raw == "" {
+			return nil, nil
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a whole number", key)
+		}
+		return &v, nil
+	}
+	var err error
+	for key, dst := range map[string]**int{"x": &q.X, "y": &q.Y, "z": &q.Z} {
+		if *dst, err = optional(key); err != nil {
+			return q, err
+		}
+	}
+	for key, dst := range map[string]*int{"radius": &q.Radius, "hours": &q.Hours, "limit": &q.Limit} {
+		v, err := optional(key)
+		if err != nil {
+			return q, err
+		}
+		if v != nil {
+			*dst = *v
+		}
+	}
+	return q, nil
+}
+
+// CoreProtectLookup handles GET /api/servers/{id}/coreprotect/lookup
+func (h *PlayerHandler) CoreProtectLookup(w http.ResponseWriter, r *http.Request) {
+	q, err := coreProtectQueryFromURL(r.URL.Query())
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	entries, err := h.mgr.CoreProtectLookup(r.PathValue("id"), q)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, entries)
+}
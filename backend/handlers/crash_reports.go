@@ -22,7 +22,7 @@ func (h *CrashReportHandler) List(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	reports, err := h.mgr.ListCrashReports(id)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 	respondJSON(w, http.StatusOK, reports)
@@ -35,7 +35,7 @@ func (h *CrashReportHandler) Read(w http.ResponseWriter, r *http.Request) {
 
 	content, err := h.mgr.ReadCrashReport(id, name)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -52,7 +52,7 @@ func (h *CrashReportHandler) Copy(w http.ResponseWriter, r *http.Request) {
 
 	copyName, err := h.mgr.CopyCrashReport(id, name)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 	respondJSON(w, http.StatusOK, map[string]string{"status": "copied", "name": copyName})
@@ -64,7 +64,7 @@ func (h *CrashReportHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	name := r.PathValue("name")
 
 	if err := h.mgr.DeleteCrashReport(id, name); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ListDatapacks handles GET /api/servers/{id}/datapacks
+func (h *PluginHandler) ListDatapacks(w http.ResponseWriter, r *http.Request) {
+	packs, err := h.mgr.ListDatapacks(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, packs)
+}
+
+// UploadDatapack handles POST /api/servers/{id}/datapacks (multipart form)
+func (h *PluginHandler) UploadDatapack(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.uploadMaxBytes)
+	if err := r.ParseMultipartForm(8 << 20); err != nil {
+		if isRequestBodyTooLarge(err) {
+			respondCodedError(w, http.StatusRequestEntityTooLarge, codeUploadTooLarge, "uploaded file exceeds maximum allowed size")
+			return
+		}
+		respondCodedError(w, http.StatusBadRequest, codeInvalidFormData, "Failed to parse form data")
+		return
+	}
+	if r.MultipartForm != nil {
+		defer r.MultipartForm.RemoveAll()
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeFileRequired, "No file provided")
+		return
+	}
+	defer file.Close()
+	if !checkUploadQuota(w, h.mgr, id, header.Size) {
+		return
+	}
+
+	tmpFile, err := os.CreateTemp("", "orexa-datapack-upload-*.zip")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create temporary upload file")
+		return
+	}
+	tmpPath := tmpFile.Name()
+	defer func() {
+		_ = os.Remove(tmpPath)
+	}()
+	if _, err := io.Copy(tmpFile, file); err != nil {
+		_ = tmpFile.Close()
+		respondError(w, http.StatusInternalServerError, "Failed to store uploaded file")
+		return
+	}
+	if err := tmpFile.Close(); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to finalize uploaded file")
+		return
+	}
+
+	conflictAction := strings.ToLower(strings.TrimSpace(r.FormValue("conflictAction")))
+	savedName, status, err := h.mgr.UploadDatapack(id, header.Filename, tmpPath, conflictAction)
+	if err != nil {
+		respondPluginInstallError(w, header.Filename, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": status, "name": savedName})
+}
+
+// ToggleDatapack handles PUT /api/servers/{id}/datapacks/{name}/toggle
+func (h *PluginHandler) ToggleDatapack(w http.ResponseWriter, r *http.Request) {
+	pack, err := h.mgr.ToggleDatapack(r.PathValue("id"), r.PathValue("name"))
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, pack)
+}
+
+// DeleteDatapack handles DELETE /api/servers/{id}/datapacks/{name}
+func (h *PluginHandler) DeleteDatapack(w http.ResponseWriter, r *http.Request) {
+	if err := h.mgr.DeleteDatapack(r.PathValue("id"), r.PathValue("name")); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"minecraft-admin/minecraft"
+)
+
+// GetDiskQuota handles GET /api/servers/{id}/disk-quota
+func (h *ServerHandler) GetDiskQuota(w http.ResponseWriter, r *http.Request) {
+	quota, err := h.mgr.GetDiskQuota(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, quota)
+}
+
+// SetDiskQuota handles PUT /api/servers/{id}/disk-quota
+func (h *ServerHandler) SetDiskQuota(w http.ResponseWriter, r *http.Request) {
+	var req minecraft.DiskQuotaSettings
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	quota, err := h.mgr.SetDiskQuota(r.PathValue("id"), req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, quota)
+}
+
+// checkUploadQuota answers 507 and returns false when an enforced disk
+// quota refuses an upload of size bytes to the server.
+func checkUploadQuota(w http.ResponseWriter, mgr *minecraft.Manager, id string, size int64) bool {
+	err := mgr.CheckUploadQuota(id, size)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, minecraft.ErrDiskQuotaExceeded):
+		respondErr(w, http.StatusInsufficientStorage, err)
+	default:
+		respondErr(w, http.StatusNotFound, err)
+	}
+	return false
+}
@@ -24,13 +24,13 @@ func (h *FileHandler) Exists(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	subPath := strings.TrimSpace(r.URL.Query().Get("path"))
 	if subPath == "" {
-		respondError(w, http.StatusBadRequest, "path parameter is required")
+		respondCodedError(w, http.StatusBadRequest, codePathRequired, "path parameter is required")
 		return
 	}
 
 	absPath, err := h.mgr.GetFilePath(id, subPath)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -71,7 +71,7 @@ func (h *FileHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	files, err := h.mgr.ListFiles(id, subPath)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -83,13 +83,13 @@ func (h *FileHandler) ReadContent(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	subPath := r.URL.Query().Get("path")
 	if subPath == "" {
-		respondError(w, http.StatusBadRequest, "path parameter is required")
+		respondCodedError(w, http.StatusBadRequest, codePathRequired, "path parameter is required")
 		return
 	}
 
 	data, err := h.mgr.ReadFileContent(id, subPath)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -106,17 +106,17 @@ func (h *FileHandler) WriteContent(w http.ResponseWriter, r *http.Request) {
 		Content string `json:"content"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 
 	if req.Path == "" {
-		respondError(w, http.StatusBadRequest, "path is required")
+		respondCodedError(w, http.StatusBadRequest, codePathRequired, "path is required")
 		return
 	}
 
 	if err := h.mgr.WriteFileContent(id, req.Path, []byte(req.Content)); err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -134,10 +134,10 @@ func (h *FileHandler) Upload(w http.ResponseWriter, r *http.Request) {
 	r.Body = http.MaxBytesReader(w, r.Body, h.uploadMaxBytes)
 	if err := r.ParseMultipartForm(8 << 20); err != nil {
 		if isRequestBodyTooLarge(err) {
-			respondError(w, http.StatusRequestEntityTooLarge, "uploaded file exceeds maximum allowed size")
+			respondCodedError(w, http.StatusRequestEntityTooLarge, codeUploadTooLarge, "uploaded file exceeds maximum allowed size")
 			return
 		}
-		respondError(w, http.StatusBadRequest, "Failed to parse form data")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidFormData, "Failed to parse form data")
 		return
 	}
 	if r.MultipartForm != nil {
@@ -146,10 +146,13 @@ func (h *FileHandler) Upload(w http.ResponseWriter, r *http.Request) {
 
 	file, header, err := r.FormFile("file")
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "No file provided")
+		respondCodedError(w, http.StatusBadRequest, codeFileRequired, "No file provided")
 		return
 	}
 	defer file.Close()
+	if !checkUploadQuota(w, h.mgr, id, header.Size) {
+		return
+	}
 
 	relativePath := filepath.ToSlash(filepath.Clean(r.FormValue("relativePath")))
 	if relativePath == "." || relativePath == "/" {
@@ -166,7 +169,7 @@ func (h *FileHandler) Upload(w http.ResponseWriter, r *http.Request) {
 
 	absPath, err := h.mgr.GetFilePath(id, targetPath)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -198,12 +201,12 @@ func (h *FileHandler) Upload(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := os.MkdirAll(filepath.Dir(absPath), 0755); err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, http.StatusInternalServerError, err)
 		return
 	}
 
 	if err := writeUploadedStream(filepath.Dir(absPath), absPath, file, conflictAction == "replace"); err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -252,18 +255,45 @@ func (h *FileHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	subPath := r.URL.Query().Get("path")
 	if subPath == "" {
-		respondError(w, http.StatusBadRequest, "path parameter is required")
+		respondCodedError(w, http.StatusBadRequest, codePathRequired, "path parameter is required")
 		return
 	}
 
-	if err := h.mgr.DeletePath(id, subPath); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	job, err := h.mgr.StartDeletePath(id, subPath)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	if job != nil {
+		respondJSON(w, http.StatusAccepted, job)
 		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
 }
 
+// DeletePreview handles GET /api/servers/{id}/files/delete-preview?path=world
+func (h *FileHandler) DeletePreview(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	subPath := r.URL.Query().Get("path")
+	if subPath == "" {
+		respondCodedError(w, http.StatusBadRequest, codePathRequired, "path parameter is required")
+		return
+	}
+
+	preview, err := h.mgr.PreviewDelete(id, subPath)
+	if err != nil {
+		status := http.StatusBadRequest
+		if os.IsNotExist(err) {
+			status = http.StatusNotFound
+		}
+		respondErr(w, status, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, preview)
+}
+
 // MkDir handles POST /api/servers/{id}/files/mkdir
 func (h *FileHandler) MkDir(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
@@ -272,17 +302,17 @@ func (h *FileHandler) MkDir(w http.ResponseWriter, r *http.Request) {
 		Path string `json:"path"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 
 	if req.Path == "" {
-		respondError(w, http.StatusBadRequest, "path is required")
+		respondCodedError(w, http.StatusBadRequest, codePathRequired, "path is required")
 		return
 	}
 
 	if err := h.mgr.CreateDirectory(id, req.Path); err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -298,7 +328,7 @@ func (h *FileHandler) Rename(w http.ResponseWriter, r *http.Request) {
 		NewName string `json:"newName"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 
@@ -308,7 +338,7 @@ func (h *FileHandler) Rename(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.mgr.RenamePath(id, req.OldPath, req.NewName); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -325,7 +355,7 @@ func (h *FileHandler) Download(w http.ResponseWriter, r *http.Request) {
 		Paths []string `json:"paths"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 	if len(req.Paths) == 0 {
@@ -337,12 +367,12 @@ func (h *FileHandler) Download(w http.ResponseWriter, r *http.Request) {
 	if len(req.Paths) == 1 {
 		absPath, err := h.mgr.GetFilePath(id, req.Paths[0])
 		if err != nil {
-			respondError(w, http.StatusBadRequest, err.Error())
+			respondErr(w, http.StatusBadRequest, err)
 			return
 		}
 		info, err := os.Stat(absPath)
 		if err != nil {
-			respondError(w, http.StatusNotFound, "File not found")
+			respondCodedError(w, http.StatusNotFound, codeFileNotFound, "File not found")
 			return
 		}
 		if !info.IsDir() {
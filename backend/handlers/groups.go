@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"minecraft-admin/minecraft"
+)
+
+// GroupHandler handles server group endpoints
+type GroupHandler struct {
+	mgr *minecraft.Manager
+}
+
+// NewGroupHandler creates a new GroupHandler
+func NewGroupHandler(mgr *minecraft.Manager) *GroupHandler {
+	return &GroupHandler{mgr: mgr}
+}
+
+// List handles GET /api/groups
+func (h *GroupHandler) List(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.mgr.ListServerGroups())
+}
+
+// Summary handles GET /api/groups/{name}/summary
+func (h *GroupHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	summary, err := h.mgr.GroupSummary(r.PathValue("name"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, summary)
+}
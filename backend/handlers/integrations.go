@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+
+	"minecraft-admin/minecraft"
+)
+
+// IntegrationHandler serves flat, read-only server state for home
+// automation tools such as Home Assistant REST sensors.
+type IntegrationHandler struct {
+	mgr *minecraft.Manager
+}
+
+// NewIntegrationHandler creates a new IntegrationHandler
+func NewIntegrationHandler(mgr *minecraft.Manager) *IntegrationHandler {
+	return &IntegrationHandler{mgr: mgr}
+}
+
+// HomeAssistantList handles GET /api/integrations/ha
+func (h *IntegrationHandler) HomeAssistantList(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.mgr.ServerStates())
+}
+
+// HomeAssistantServer handles GET /api/integrations/ha/{id}
+func (h *IntegrationHandler) HomeAssistantServer(w http.ResponseWriter, r *http.Request) {
+	state, err := h.mgr.ServerState(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, state)
+}
+
+// PublicServerStatus is what the public status endpoint shows of a server:
+// whether it is up and how full it is, nothing else.
+type PublicServerStatus struct {
+	Name       string `json:"name"`
+	Online     bool   `json:"online"`
+	Players    int    `json:"players"`
+	MaxPlayers int    `json:"maxPlayers"`
+}
+
+// PublicStatus handles GET /api/public-status. With ?server=<name or id> it
+// reports that one server and answers 503 while it is offline, so uptime
+// monitors can check it by status code alone.
+func (h *IntegrationHandler) PublicStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	states := h.mgr.ServerStates()
+	if want := strings.TrimSpace(r.URL.Query().Get("server")); want != "" {
+		for _, state := range states {
+			if state.ID == want || strings.EqualFold(state.Name, want) {
+				status := http.StatusOK
+				if !state.Online {
+					status = http.StatusServiceUnavailable
+				}
+				respondJSON(w, status, publicServerStatus(state))
+				return
+			}
+		}
+		respondError(w, http.StatusNotFound, "Server not found")
+		return
+	}
+
+	servers := make([]PublicServerStatus, 0, len(states))
+	online := 0
+	for _, state := range states {
+		servers = append(servers, publicServerStatus(state))
+		if state.Online {
+			online++
+		}
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"online":  online,
+		"total":   len(servers),
+		"servers": servers,
+	})
+}
+
+func publicServerStatus(state minecraft.ServerState) PublicServerStatus {
+	return PublicServerStatus{Name: state.Name, Online: state.Online, Players: state.Players, MaxPlayers: state.MaxPlayers}
+}
+
+// publicStatusFromEnv reads who may see /api/public-status without a
+// session: anyone when ADPANEL_PUBLIC_STATUS is true, or holders of
+// ADPANEL_PUBLIC_STATUS_TOKEN when that is set, which takes precedence.
+func publicStatusFromEnv() (bool, string) {
+	v := strings.TrimSpace(strings.ToLower(os.Getenv("ADPANEL_PUBLIC_STATUS")))
+	return v == "1" || v == "true" || v == "yes" || v == "on", strings.TrimSpace(os.Getenv("ADPANEL_PUBLIC_STATUS_TOKEN"))
+}
+
+// publicStatusAllowed reports whether r may read the public status without
+// a session. The token may come as a bearer token or, for monitors that
+// cannot set headers, as ?token=.
+func (h *AuthHandler) publicStatusAllowed(r *http.Request) bool {
+	if h.publicStatusToken == "" {
+		return h.publicStatus
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		token = r.URL.Query().Get("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(h.publicStatusToken)) == 1
+}
+
+// haTokenFromEnv reads the bearer token that lets integrations read
+// /api/integrations/ha without a session. Unset means session only.
+func haTokenFromEnv() string {
+	return strings.TrimSpace(os.Getenv("ADPANEL_HA_TOKEN"))
+}
+
+func isIntegrationPath(path string) bool {
+	return path == "/api/integrations/ha" || strings.HasPrefix(path, "/api/integrations/ha/")
+}
+
+// integrationTokenMatches reports whether r carries the configured
+// integration token as "Authorization: Bearer <token>".
+func (h *AuthHandler) integrationTokenMatches(r *http.Request) bool {
+	if h.haToken == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(h.haToken)) == 1
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"minecraft-admin/minecraft"
+)
+
+// JobHandler handles the long-running job endpoints
+type JobHandler struct {
+	mgr *minecraft.Manager
+}
+
+// NewJobHandler creates a new JobHandler
+func NewJobHandler(mgr *minecraft.Manager) *JobHandler {
+	return &JobHandler{mgr: mgr}
+}
+
+// List handles GET /api/jobs?serverId=&state=
+func (h *JobHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	respondJSON(w, http.StatusOK, h.mgr.ListJobs(query.Get("serverId"), query.Get("state")))
+}
+
+// Get handles GET /api/jobs/{id}
+func (h *JobHandler) Get(w http.ResponseWriter, r *http.Request) {
+	job, err := h.mgr.GetJob(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, job)
+}
+
+// Cancel handles POST /api/jobs/{id}/cancel
+func (h *JobHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	if err := h.mgr.CancelJob(r.PathValue("id")); err != nil {
+		switch {
+		case errors.Is(err, minecraft.ErrJobNotFound):
+			respondErr(w, http.StatusNotFound, err)
+		case errors.Is(err, minecraft.ErrJobAlreadyFinished):
+			respondErr(w, http.StatusConflict, err)
+		default:
+			respondErr(w, http.StatusBadRequest, err)
+		}
+		return
+	}
+	respondJSON(w, http.StatusAccepted, map[string]string{"status": "cancelling"})
+}
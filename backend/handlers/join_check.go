@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"minecraft-admin/minecraft"
+)
+
+// GetJoinCheck handles GET /api/servers/{id}/join-check
+func (h *ServerHandler) GetJoinCheck(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.mgr.GetJoinCheck(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// SetJoinCheck handles PUT /api/servers/{id}/join-check
+func (h *ServerHandler) SetJoinCheck(w http.ResponseWriter, r *http.Request) {
+	var req minecraft.JoinCheckSettings
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	settings, err := h.mgr.SetJoinCheck(r.PathValue("id"), req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// RunJoinCheck handles POST /api/servers/{id}/join-check. A refused join
+// is a result, not an error, so it still answers 200.
+func (h *ServerHandler) RunJoinCheck(w http.ResponseWriter, r *http.Request) {
+	result, err := h.mgr.RunJoinCheck(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"minecraft-admin/minecraft"
+)
+
+// logAuthEvent writes an auth event to the panel log and, when configured,
+// appends it to the auth log with a UTC timestamp for fail2ban.
+func (h *AuthHandler) logAuthEvent(line string) {
+	log.Print(line)
+	if h.authLogPath == "" {
+		return
+	}
+	h.authLogMu.Lock()
+	defer h.authLogMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(h.authLogPath), 0755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(h.authLogPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		log.Printf("Failed to open auth log: %v", err)
+		return
+	}
+	defer f.Close()
+	fmt.Fprintf(f, "%s orexa-panel %s\n", time.Now().UTC().Format(time.RFC3339), line)
+}
+
+// persistLoginAttempts saves the failed-login state, dropping entries whose
+// window and block have both run out.
+func (h *AuthHandler) persistLoginAttempts() {
+	if h.mgr == nil {
+		return
+	}
+	policy := h.sessionPolicy()
+	now := time.Now()
+	h.mu.Lock()
+	snapshot := make(map[string]minecraft.LoginAttemptRecord, len(h.loginAttempts))
+	for ip, attempt := range h.loginAttempts {
+		if attempt.BlockedUntil.Before(now) && now.Sub(attempt.WindowStart) > policy.loginWindow {
+			delete(h.loginAttempts, ip)
+			continue
+		}
+		snapshot[ip] = attempt
+	}
+	h.mu.Unlock()
+	if err := h.mgr.SaveLoginAttempts(snapshot); err != nil {
+		log.Printf("Failed to save login lockouts: %v", err)
+	}
+}
+
+type loginBlockInfo struct {
+	IP           string    `json:"ip"`
+	Failures     int       `json:"failures"`
+	WindowStart  time.Time `json:"windowStart"`
+	BlockedUntil time.Time `json:"blockedUntil,omitempty"`
+	Blocked      bool      `json:"blocked"`
+}
+
+// ListLoginBlocks handles GET /api/security/login-blocks
+func (h *AuthHandler) ListLoginBlocks(w http.ResponseWriter, _ *http.Request) {
+	now := time.Now()
+	h.mu.RLock()
+	blocks := make([]loginBlockInfo, 0, len(h.loginAttempts))
+	for ip, attempt := range h.loginAttempts {
+		blocks = append(blocks, loginBlockInfo{
+			IP:           ip,
+			Failures:     attempt.Count,
+			WindowStart:  attempt.WindowStart,
+			BlockedUntil: attempt.BlockedUntil,
+			Blocked:      attempt.BlockedUntil.After(now),
+		})
+	}
+	h.mu.RUnlock()
+	sort.Slice(blocks, func(i, j int) bool {
+		if blocks[i].Blocked != blocks[j].Blocked {
+			return blocks[i].Blocked
+		}
+		return blocks[i].IP < blocks[j].IP
+	})
+	respondJSON(w, http.StatusOK, map[string]any{
+		"blocks":      blocks,
+		"authLogPath": h.authLogPath,
+	})
+}
+
+// ClearLoginBlock handles DELETE /api/security/login-blocks/{ip}
+func (h *AuthHandler) ClearLoginBlock(w http.ResponseWriter, r *http.Request) {
+	ip := strings.TrimSpace(r.PathValue("ip"))
+	h.mu.Lock()
+	_, ok := h.loginAttempts[ip]
+	delete(h.loginAttempts, ip)
+	h.mu.Unlock()
+	if !ok {
+		respondError(w, http.StatusNotFound, "No failed logins recorded for this address")
+		return
+	}
+	h.persistLoginAttempts()
+	h.logAuthEvent(fmt.Sprintf("auth unblocked: ip=%s", ip))
+	respondJSON(w, http.StatusOK, map[string]string{"status": "cleared"})
+}
+
+// ClearLoginBlocks handles DELETE /api/security/login-blocks
+func (h *AuthHandler) ClearLoginBlocks(w http.ResponseWriter, _ *http.Request) {
+	h.mu.Lock()
+	cleared := len(h.loginAttempts)
+	h.loginAttempts = make(map[string]loginAttempt)
+	h.mu.Unlock()
+	h.persistLoginAttempts()
+	if cleared > 0 {
+		h.logAuthEvent(fmt.Sprintf("auth unblocked: all count=%d", cleared))
+	}
+	respondJSON(w, http.StatusOK, map[string]int{"cleared": cleared})
+}
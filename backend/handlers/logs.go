@@ -1,8 +1,14 @@
 package handlers
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"minecraft-admin/minecraft"
 )
@@ -21,7 +27,7 @@ func (h *LogHandler) List(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	files, err := h.mgr.ListLogFiles(id)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 	respondJSON(w, http.StatusOK, files)
@@ -34,7 +40,7 @@ func (h *LogHandler) Read(w http.ResponseWriter, r *http.Request) {
 
 	content, err := h.mgr.ReadLogFile(id, name)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -43,3 +49,140 @@ func (h *LogHandler) Read(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write(content)
 }
+
+// Search handles GET /api/servers/{id}/logs/search?q=&regex=&level=&from=&to=&page=&pageSize=&context=
+func (h *LogHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	opts := minecraft.LogSearchOptions{
+		Query: query.Get("q"),
+		Level: query.Get("level"),
+	}
+	opts.Regex = queryFlag(query.Get("regex"))
+
+	var err error
+	if opts.From, err = parseLogSearchTime(query.Get("from"), false); err != nil {
+		respondError(w, http.StatusBadRequest, "from must be a date (YYYY-MM-DD) or an RFC 3339 time")
+		return
+	}
+	if opts.To, err = parseLogSearchTime(query.Get("to"), true); err != nil {
+		respondError(w, http.StatusBadRequest, "to must be a date (YYYY-MM-DD) or an RFC 3339 time")
+		return
+	}
+	for _, param := range []struct {
+		name   string
+		target *int
+	}{{"page", &opts.Page}, {"pageSize", &opts.PageSize}, {"context", &opts.Context}} {
+		raw := strings.TrimSpace(query.Get(param.name))
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			respondError(w, http.StatusBadRequest, param.name+" must be a whole number")
+			return
+		}
+		*param.target = v
+	}
+	if query.Get("context") == "" {
+		opts.Context = 2
+	}
+
+	result, err := h.mgr.SearchLogs(r.Context(), r.PathValue("id"), opts)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, result)
+}
+
+// parseLogSearchTime reads an RFC 3339 time or a plain date in the panel's
+// time zone. A plain date used as the end of a range covers that whole day.
+func parseLogSearchTime(raw string, endOfDay bool) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", raw, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		return day.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return day, nil
+}
+
+// Export handles GET /api/logs/{id}/export?latest=&strip=, downloading the
+// console buffer as a text file. latest=true appends logs/latest.log and
+// strip=true removes ANSI escapes and colour codes.
+func (h *LogHandler) Export(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	query := r.URL.Query()
+	includeLatest := queryFlag(query.Get("latest"))
+	strip := queryFlag(query.Get("strip"))
+
+	lines, err := h.mgr.ConsoleLog(id)
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	var latest *os.File
+	if includeLatest {
+		latest, err = h.mgr.OpenLatestLog(id)
+		if err != nil && !os.IsNotExist(err) {
+			respondErr(w, http.StatusInternalServerError, err)
+			return
+		}
+		if latest != nil {
+			defer latest.Close()
+		}
+	}
+
+	name := fmt.Sprintf("console-%s-%s.txt", id, time.Now().Format("2006-01-02_15-04-05"))
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.WriteHeader(http.StatusOK)
+
+	out := bufio.NewWriter(w)
+	defer out.Flush()
+	writeLine := func(line string) {
+		if strip {
+			line = minecraft.StripConsoleFormatting(line)
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	if includeLatest {
+		writeLine("===== Console =====")
+	}
+	for _, line := range lines {
+		writeLine(line)
+	}
+	if !includeLatest {
+		return
+	}
+	writeLine("")
+	writeLine("===== logs/latest.log =====")
+	if latest == nil {
+		writeLine("(no latest.log)")
+		return
+	}
+	if !strip {
+		io.Copy(out, latest)
+		return
+	}
+	scanner := bufio.NewScanner(latest)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		writeLine(scanner.Text())
+	}
+}
+
+// queryFlag reads a boolean query parameter.
+func queryFlag(v string) bool {
+	v = strings.ToLower(strings.TrimSpace(v))
+	return v == "1" || v == "true" || v == "yes" || v == "on"
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+
+	"minecraft-admin/minecraft"
+)
+
+// GetMaintenance handles GET /api/servers/{id}/maintenance
+func (h *ServerHandler) GetMaintenance(w http.ResponseWriter, r *http.Request) {
+	routine, err := h.mgr.GetMaintenanceRoutine(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, routine)
+}
+
+// SetMaintenance handles PUT /api/servers/{id}/maintenance
+func (h *ServerHandler) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req minecraft.MaintenanceRoutine
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	routine, err := h.mgr.SetMaintenanceRoutine(r.PathValue("id"), req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, routine)
+}
+
+// RunMaintenance handles POST /api/servers/{id}/maintenance/run
+func (h *ServerHandler) RunMaintenance(w http.ResponseWriter, r *http.Request) {
+	job, err := h.mgr.StartMaintenance(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusConflict, err)
+		return
+	}
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// GetRestartVerification handles GET /api/servers/{id}/restart-verification
+func (h *ServerHandler) GetRestartVerification(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.mgr.GetRestartVerification(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// SetRestartVerification handles PUT /api/servers/{id}/restart-verification
+func (h *ServerHandler) SetRestartVerification(w http.ResponseWriter, r *http.Request) {
+	var req minecraft.RestartVerificationSettings
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	settings, err := h.mgr.SetRestartVerification(r.PathValue("id"), req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, settings)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"minecraft-admin/minecraft"
+)
+
+// Codes of the fixed handler messages, so clients can translate them.
+const (
+	codeInvalidRequestBody     = "invalid_request_body"
+	codeServerIDRequired       = "server_id_required"
+	codeServerNameRequired     = "server_name_required"
+	codeServerTypeRequired     = "server_type_required"
+	codeAuthenticationRequired = "authentication_required"
+	codeCredentialsRequired    = "credentials_required"
+	codeInvalidCredentials     = "invalid_credentials"
+	codeLoginRateLimited       = "login_rate_limited"
+	codeFileRequired           = "file_required"
+	codeInvalidFormData        = "invalid_form_data"
+	codeUploadTooLarge         = "upload_too_large"
+	codeFileNotFound           = "file_not_found"
+	codePathRequired           = "path_required"
+	codeDownloadURLRequired    = "download_url_required"
+	codeSourceURLRequired      = "source_url_required"
+	codeDelayMustBePositive    = "delay_must_be_positive"
+	codeDelayMustNotBeNegative = "delay_must_not_be_negative"
+)
+
+// respondErr writes err as a JSON error response, adding its code and
+// parameters when it carries them.
+func respondErr(w http.ResponseWriter, status int, err error) {
+	var msgErr *minecraft.MessageError
+	if errors.As(err, &msgErr) {
+		respondJSON(w, status, map[string]any{
+			"error":  msgErr.Message,
+			"code":   msgErr.Code,
+			"params": msgErr.Params,
+		})
+		return
+	}
+	respondError(w, status, err.Error())
+}
+
+// respondCodedError writes a JSON error response with a stable code that
+// clients can translate instead of showing message.
+func respondCodedError(w http.ResponseWriter, status int, code, message string) {
+	respondJSON(w, status, map[string]string{"error": message, "code": code})
+}
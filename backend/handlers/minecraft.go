@@ -1,16 +1,26 @@
 package handlers
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/websocket"
 
 	"minecraft-admin/minecraft"
 )
 
+// WebSocket keepalive timing. A socket whose peer stops answering pings is
+// closed once wsPongWait passes without any message or pong.
+const (
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = 50 * time.Second
+	wsWriteWait  = 10 * time.Second
+)
+
 // MinecraftHandler handles WebSocket connections for console streaming
 type MinecraftHandler struct {
 	mgr            *minecraft.Manager
@@ -30,6 +40,11 @@ func NewMinecraftHandler(mgr *minecraft.Manager) *MinecraftHandler {
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				origin := strings.TrimSpace(r.Header.Get("Origin"))
+				// Non-browser clients send no Origin; a ticket proves they
+				// hold a session without relying on the cookie.
+				if origin == "" && hasWebSocketTicket(r) {
+					return true
+				}
 				allowed := isAllowedWebSocketOriginForRequest(r, origin, allowedOrigins, trustedProxies)
 				if !allowed {
 					log.Printf("WebSocket origin rejected for %s from %q", r.URL.Path, origin)
@@ -42,11 +57,42 @@ func NewMinecraftHandler(mgr *minecraft.Manager) *MinecraftHandler {
 
 // wsMessage is the JSON structure sent to WebSocket clients
 type wsMessage struct {
-	Type    string                      `json:"type"`
-	Seq     uint64                      `json:"seq,omitempty"`
-	Line    string                      `json:"line,omitempty"`
-	Entries []minecraft.ConsoleLogEntry `json:"entries,omitempty"`
-	Reset   bool                        `json:"reset,omitempty"`
+	Type     string                      `json:"type"`
+	ServerID string                      `json:"serverId,omitempty"`
+	Message  string                      `json:"message,omitempty"`
+	Seq      uint64                      `json:"seq,omitempty"`
+	Line     string                      `json:"line,omitempty"`
+	Entries  []minecraft.ConsoleLogEntry `json:"entries,omitempty"`
+	Reset    bool                        `json:"reset,omitempty"`
+	LastSeq  uint64                      `json:"lastSeq,omitempty"`
+	Dropped  uint64                      `json:"dropped,omitempty"`
+}
+
+// streamLastSeq returns the newest sequence a client holds after receiving
+// snapshot on top of what it had at lastSeq.
+func streamLastSeq(lastSeq uint64, snapshot []minecraft.ConsoleLogEntry, reset bool) uint64 {
+	if reset {
+		lastSeq = 0
+	}
+	for _, entry := range snapshot {
+		if entry.Seq > lastSeq {
+			lastSeq = entry.Seq
+		}
+	}
+	return lastSeq
+}
+
+// endStream tells the client the stream is ending and the last sequence it
+// was sent, then closes the socket. Reconnecting with ?lastSeq= picks up
+// from there without gaps.
+func endStream(conn *websocket.Conn, lastSeq uint64, code int) {
+	deadline := time.Now().Add(wsWriteWait)
+	conn.SetWriteDeadline(deadline)
+	if err := conn.WriteJSON(wsMessage{Type: "end", LastSeq: lastSeq}); err != nil {
+		return
+	}
+	reason := fmt.Sprintf("stream ending, lastSeq=%d", lastSeq)
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline)
 }
 
 // WebSocketLogs returns an HTTP handler that upgrades to WebSocket for log streaming
@@ -71,6 +117,7 @@ func (h *MinecraftHandler) WebSocketLogs() http.Handler {
 			return
 		}
 		defer conn.Close()
+		defer trackWebSocket()()
 
 		log.Printf("WebSocket connected for server %s", id)
 
@@ -93,10 +140,18 @@ func (h *MinecraftHandler) WebSocketLogs() http.Handler {
 			log.Printf("WebSocket initial snapshot write error for server %s: %v", id, err)
 			return
 		}
+		sentSeq := streamLastSeq(lastSeq, snapshot, reset)
 
 		// Channel to signal connection close
 		done := make(chan struct{})
 
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		})
+		ping := time.NewTicker(wsPingPeriod)
+		defer ping.Stop()
+
 		// Read goroutine: client sends commands
 		go func() {
 			defer close(done)
@@ -108,15 +163,16 @@ func (h *MinecraftHandler) WebSocketLogs() http.Handler {
 					}
 					return
 				}
+				conn.SetReadDeadline(time.Now().Add(wsPongWait))
 
 				command := strings.TrimSpace(string(msg))
+				if command != "" && !canSendCommands(r) {
+					log.Printf("Ignored console command for server %s from a read-only session", id)
+					continue
+				}
 				if command != "" {
-					if err := h.mgr.SendCommand(id, command); err != nil {
+					if err := h.runConsoleCommand(id, command); err != nil {
 						log.Printf("Failed to send command to server %s: %v", id, err)
-						continue
-					}
-					if err := h.mgr.RecordConsoleCommand(id, command); err != nil {
-						log.Printf("Failed to record command in console for server %s: %v", id, err)
 					}
 				}
 			}
@@ -127,19 +183,32 @@ func (h *MinecraftHandler) WebSocketLogs() http.Handler {
 			select {
 			case entry, ok := <-logCh:
 				if !ok {
-					return // Channel closed
+					endStream(conn, sentSeq, websocket.CloseNormalClosure)
+					return // Server is not running, or this client fell too far behind
 				}
+				conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
 				err := conn.WriteJSON(wsMessage{
-					Type: "log",
-					Seq:  entry.Seq,
-					Line: entry.Line,
+					Type:    "log",
+					Seq:     entry.Seq,
+					Line:    entry.Line,
+					Dropped: entry.Dropped,
 				})
 				if err != nil {
 					log.Printf("WebSocket write error for server %s: %v", id, err)
 					return
 				}
+				if entry.Dropped == 0 {
+					sentSeq = entry.Seq
+				}
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+					return
+				}
 			case <-done:
-				return // Client disconnected
+				// Client disconnected or stopped answering pings; the
+				// close frame only reaches peers that are still there.
+				endStream(conn, sentSeq, websocket.CloseGoingAway)
+				return
 			}
 		}
 	})
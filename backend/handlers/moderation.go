@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"minecraft-admin/minecraft"
+)
+
+// GetModerationPresets handles GET /api/moderation/presets
+func (h *PlayerHandler) GetModerationPresets(w http.ResponseWriter, r *http.Request) {
+	presets, err := h.mgr.GetModerationPresets()
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, presets)
+}
+
+// SetModerationPresets handles PUT /api/moderation/presets
+func (h *PlayerHandler) SetModerationPresets(w http.ResponseWriter, r *http.Request) {
+	var req []minecraft.ModerationPreset
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	presets, err := h.mgr.SetModerationPresets(req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, presets)
+}
+
+// ModerationLog handles GET /api/moderation/log?serverId=&limit=
+func (h *PlayerHandler) ModerationLog(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if raw := strings.TrimSpace(r.URL.Query().Get("limit")); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 {
+			respondError(w, http.StatusBadRequest, "limit must be a positive whole number")
+			return
+		}
+		limit = v
+	}
+	events, err := h.mgr.ModerationLog(strings.TrimSpace(r.URL.Query().Get("serverId")), limit)
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, events)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"minecraft-admin/minecraft"
+)
+
+// ListNetworks handles GET /api/networks
+func (h *ServerHandler) ListNetworks(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.mgr.ListNetworks())
+}
+
+// SetNetwork handles PUT /api/servers/{id}/network
+func (h *ServerHandler) SetNetwork(w http.ResponseWriter, r *http.Request) {
+	var req minecraft.ProxyNetwork
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	result, err := h.mgr.SetProxyNetwork(r.PathValue("id"), req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, result)
+}
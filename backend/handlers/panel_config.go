@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"minecraft-admin/minecraft"
+)
+
+// PanelConfigHandler handles panel configuration export/import
+type PanelConfigHandler struct {
+	mgr            *minecraft.Manager
+	uploadMaxBytes int64
+}
+
+// NewPanelConfigHandler creates a new PanelConfigHandler
+func NewPanelConfigHandler(mgr *minecraft.Manager) *PanelConfigHandler {
+	return &PanelConfigHandler{mgr: mgr, uploadMaxBytes: uploadMaxBytesFromEnv()}
+}
+
+// Export handles GET /api/system/config/export
+func (h *PanelConfigHandler) Export(w http.ResponseWriter, _ *http.Request) {
+	var buf bytes.Buffer
+	if err := h.mgr.ExportPanelConfig(&buf); err != nil {
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	name := fmt.Sprintf("panel-config_%s.tar.gz", time.Now().Format("2006-01-02_15-04-05"))
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+name+"\"")
+	w.Header().Set("Content-Type", "application/gzip")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// Import handles POST /api/system/config/import
+func (h *PanelConfigHandler) Import(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.uploadMaxBytes)
+	if err := r.ParseMultipartForm(8 << 20); err != nil {
+		if isRequestBodyTooLarge(err) {
+			respondCodedError(w, http.StatusRequestEntityTooLarge, codeUploadTooLarge, "uploaded file exceeds maximum allowed size")
+			return
+		}
+		respondCodedError(w, http.StatusBadRequest, codeInvalidFormData, "Failed to parse form data")
+		return
+	}
+	if r.MultipartForm != nil {
+		defer r.MultipartForm.RemoveAll()
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeFileRequired, "No file provided")
+		return
+	}
+	defer file.Close()
+
+	result, err := h.mgr.ImportPanelConfig(file)
+	if err != nil {
+		if errors.Is(err, minecraft.ErrPanelNotEmpty) {
+			respondErr(w, http.StatusConflict, err)
+			return
+		}
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, result)
+}
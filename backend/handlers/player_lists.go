@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"minecraft-admin/minecraft"
+)
+
+type playerListRequest struct {
+	Name   string `json:"name"`
+	IP     string `json:"ip"`
+	Reason string `json:"reason"`
+	Preset string `json:"preset"`
+}
+
+// ListWhitelist handles GET /api/servers/{id}/whitelist
+func (h *PlayerHandler) ListWhitelist(w http.ResponseWriter, r *http.Request) {
+	h.listPlayerList(w, r, minecraft.PlayerListWhitelist)
+}
+
+// AddWhitelist handles POST /api/servers/{id}/whitelist
+func (h *PlayerHandler) AddWhitelist(w http.ResponseWriter, r *http.Request) {
+	h.addToPlayerList(w, r, minecraft.PlayerListWhitelist)
+}
+
+// RemoveWhitelist handles DELETE /api/servers/{id}/whitelist/{name}
+func (h *PlayerHandler) RemoveWhitelist(w http.ResponseWriter, r *http.Request) {
+	h.removeFromPlayerList(w, r, minecraft.PlayerListWhitelist)
+}
+
+// ListOps handles GET /api/servers/{id}/ops
+func (h *PlayerHandler) ListOps(w http.ResponseWriter, r *http.Request) {
+	h.listPlayerList(w, r, minecraft.PlayerListOps)
+}
+
+// AddOp handles POST /api/servers/{id}/ops
+func (h *PlayerHandler) AddOp(w http.ResponseWriter, r *http.Request) {
+	h.addToPlayerList(w, r, minecraft.PlayerListOps)
+}
+
+// RemoveOp handles DELETE /api/servers/{id}/ops/{name}
+func (h *PlayerHandler) RemoveOp(w http.ResponseWriter, r *http.Request) {
+	h.removeFromPlayerList(w, r, minecraft.PlayerListOps)
+}
+
+func (h *PlayerHandler) listPlayerList(w http.ResponseWriter, r *http.Request, list string) {
+	entries, err := h.mgr.ListPlayerList(r.PathValue("id"), list)
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, entries)
+}
+
+func (h *PlayerHandler) addToPlayerList(w http.ResponseWriter, r *http.Request, list string) {
+	var req playerListRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if err := h.mgr.AddToPlayerList(r.PathValue("id"), list, name, ""); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "added", "player": name})
+}
+
+func (h *PlayerHandler) removeFromPlayerList(w http.ResponseWriter, r *http.Request, list string) {
+	name := r.PathValue("name")
+	if err := h.mgr.RemoveFromPlayerList(r.PathValue("id"), list, name); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "removed", "player": name})
+}
+
+// ListBans handles GET /api/servers/{id}/bans
+func (h *PlayerHandler) ListBans(w http.ResponseWriter, r *http.Request) {
+	bans, err := h.mgr.ListBans(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, bans)
+}
+
+// AddBan handles POST /api/servers/{id}/bans with either a player name or
+// an IP address.
+func (h *PlayerHandler) AddBan(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req playerListRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	ip := strings.TrimSpace(req.IP)
+	if (name == "") == (ip == "") {
+		respondError(w, http.StatusBadRequest, "Provide either a player name or an IP address")
+		return
+	}
+	reason, err := h.mgr.ResolveModerationReason(req.Preset, req.Reason)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	target := name
+	if ip != "" {
+		err = h.mgr.BanIP(id, ip, reason)
+		target = ip
+	} else {
+		err = h.mgr.AddToPlayerList(id, minecraft.PlayerListBans, name, reason)
+	}
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	h.mgr.RecordModeration(minecraft.ModerationEvent{
+		ServerID: id,
+		Actor:    sessionUsername(r),
+		Action:   minecraft.ModerationBan,
+		Player:   target,
+		Reason:   reason,
+		Preset:   req.Preset,
+	})
+	respondJSON(w, http.StatusOK, map[string]string{"status": "banned", "target": target})
+}
+
+// RemoveBan handles DELETE /api/servers/{id}/bans/{target}, where target is
+// a player name or an IP address.
+func (h *PlayerHandler) RemoveBan(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	target := r.PathValue("target")
+	var err error
+	if net.ParseIP(target) != nil {
+		err = h.mgr.PardonIP(id, target)
+	} else {
+		err = h.mgr.RemoveFromPlayerList(id, minecraft.PlayerListBans, target)
+	}
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	h.mgr.RecordModeration(minecraft.ModerationEvent{
+		ServerID: id,
+		Actor:    sessionUsername(r),
+		Action:   minecraft.ModerationPardon,
+		Player:   target,
+	})
+	respondJSON(w, http.StatusOK, map[string]string{"status": "pardoned", "target": target})
+}
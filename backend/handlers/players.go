@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"minecraft-admin/minecraft"
@@ -30,12 +32,12 @@ func (h *PlayerHandler) List(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	players, isStale, lastSyncAt, err := h.mgr.ListPlayersWithFreshness(id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, err.Error())
+		respondErr(w, http.StatusNotFound, err)
 		return
 	}
 	pingSupported, pingStatus, err := h.mgr.GetPingSupport(id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, err.Error())
+		respondErr(w, http.StatusNotFound, err)
 		return
 	}
 	resp := PlayersResponse{
@@ -50,6 +52,60 @@ func (h *PlayerHandler) List(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, resp)
 }
 
+// History handles GET /api/servers/{id}/players/history?days=30
+func (h *PlayerHandler) History(w http.ResponseWriter, r *http.Request) {
+	days := 0
+	if raw := strings.TrimSpace(r.URL.Query().Get("days")); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 {
+			respondError(w, http.StatusBadRequest, "days must be a positive whole number")
+			return
+		}
+		days = v
+	}
+	history, err := h.mgr.GetPlayerHistory(r.PathValue("id"), days)
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, history)
+}
+
+// Inspect handles GET /api/servers/{id}/players/{name}/inspect
+func (h *PlayerHandler) Inspect(w http.ResponseWriter, r *http.Request) {
+	details, err := h.mgr.InspectPlayer(r.PathValue("id"), r.PathValue("name"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, details)
+}
+
+// Locate handles GET /api/servers/{id}/players/{name}/locate
+func (h *PlayerHandler) Locate(w http.ResponseWriter, r *http.Request) {
+	location, err := h.mgr.LocatePlayer(r.PathValue("id"), r.PathValue("name"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, location)
+}
+
+// LocateStructure handles POST /api/servers/{id}/locate
+func (h *PlayerHandler) LocateStructure(w http.ResponseWriter, r *http.Request) {
+	var req minecraft.LocateStructureRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	location, err := h.mgr.LocateStructure(r.PathValue("id"), req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, location)
+}
+
 // Kick handles POST /api/servers/{id}/players/{name}/kick
 func (h *PlayerHandler) Kick(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
@@ -57,44 +113,149 @@ func (h *PlayerHandler) Kick(w http.ResponseWriter, r *http.Request) {
 
 	var req struct {
 		Reason string `json:"reason"`
+		Preset string `json:"preset"`
 	}
 	_ = decodeJSONOptional(r, &req)
 
-	if err := h.mgr.KickPlayer(id, name, req.Reason); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	reason, err := h.mgr.ResolveModerationReason(req.Preset, req.Reason)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.mgr.KickPlayer(id, name, reason); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
+	h.mgr.RecordModeration(minecraft.ModerationEvent{
+		ServerID: id,
+		Actor:    sessionUsername(r),
+		Action:   minecraft.ModerationKick,
+		Player:   name,
+		Reason:   reason,
+		Preset:   req.Preset,
+	})
 
 	respondJSON(w, http.StatusOK, map[string]string{"status": "kicked", "player": name})
 }
 
-// Ban handles POST /api/servers/{id}/players/{name}/ban
+// Ban handles POST /api/servers/{id}/players/{name}/ban. A duration such
+// as "3d" makes the ban temporary.
 func (h *PlayerHandler) Ban(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	name := r.PathValue("name")
 
 	var req struct {
-		Reason string `json:"reason"`
+		Reason   string `json:"reason"`
+		Preset   string `json:"preset"`
+		Duration string `json:"duration"`
 	}
 	_ = decodeJSONOptional(r, &req)
 
-	if err := h.mgr.BanPlayer(id, name, req.Reason); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	reason, err := h.mgr.ResolveModerationReason(req.Preset, req.Reason)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
+	event := minecraft.ModerationEvent{
+		ServerID: id,
+		Actor:    sessionUsername(r),
+		Action:   minecraft.ModerationBan,
+		Player:   name,
+		Reason:   reason,
+		Preset:   req.Preset,
+	}
+
+	if req.Duration != "" {
+		ban, err := h.mgr.TempBanPlayer(id, name, reason, req.Duration)
+		if err != nil {
+			respondErr(w, http.StatusBadRequest, err)
+			return
+		}
+		event.Action = minecraft.ModerationTempBan
+		event.ExpiresAt = ban.ExpiresAt
+		h.mgr.RecordModeration(event)
+		respondJSON(w, http.StatusOK, map[string]string{"status": "banned", "player": name, "expiresAt": ban.ExpiresAt})
+		return
+	}
+
+	if err := h.mgr.BanPlayer(id, name, reason); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	h.mgr.RecordModeration(event)
 
 	respondJSON(w, http.StatusOK, map[string]string{"status": "banned", "player": name})
 }
 
+// ListTempBans handles GET /api/servers/{id}/tempbans
+func (h *PlayerHandler) ListTempBans(w http.ResponseWriter, r *http.Request) {
+	bans, err := h.mgr.ListTempBans(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, bans)
+}
+
+// LiftTempBan handles DELETE /api/servers/{id}/tempbans/{name}
+func (h *PlayerHandler) LiftTempBan(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if err := h.mgr.LiftTempBan(r.PathValue("id"), name); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	h.mgr.RecordModeration(minecraft.ModerationEvent{
+		ServerID: r.PathValue("id"),
+		Actor:    sessionUsername(r),
+		Action:   minecraft.ModerationPardon,
+		Player:   name,
+		Reason:   "Temporary ban lifted early",
+	})
+	respondJSON(w, http.StatusOK, map[string]string{"status": "pardoned", "player": name})
+}
+
 // Kill handles POST /api/servers/{id}/players/{name}/kill
 func (h *PlayerHandler) Kill(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	name := r.PathValue("name")
 
 	if err := h.mgr.KillPlayer(id, name); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]string{"status": "killed", "player": name})
 }
+
+// Send handles POST /api/servers/{id}/players/{name}/send?target=lobby. The
+// server may be the Velocity proxy itself or a backend behind it.
+func (h *PlayerHandler) Send(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	name := r.PathValue("name")
+	target := r.URL.Query().Get("target")
+
+	proxy, err := h.mgr.SendPlayer(id, name, target)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	h.mgr.RecordModeration(minecraft.ModerationEvent{
+		ServerID: proxy.ProxyID,
+		Actor:    sessionUsername(r),
+		Action:   minecraft.ModerationSend,
+		Player:   name,
+		Reason:   "Sent to " + target,
+	})
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "sent", "player": name, "target": target})
+}
+
+// ProxyBackends handles GET /api/servers/{id}/proxy-backends
+func (h *PlayerHandler) ProxyBackends(w http.ResponseWriter, r *http.Request) {
+	proxy, err := h.mgr.FindProxyBackends(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, proxy)
+}
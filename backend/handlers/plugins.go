@@ -30,23 +30,24 @@ func (h *PluginHandler) List(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	plugins, err := h.mgr.ListPlugins(id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, err.Error())
+		respondErr(w, http.StatusNotFound, err)
 		return
 	}
 	respondJSON(w, http.StatusOK, plugins)
 }
 
-// Upload handles POST /api/servers/{id}/plugins (multipart form)
+// Upload handles POST /api/servers/{id}/plugins (multipart form). The
+// optional "dir" field picks plugins, mods or datapacks.
 func (h *PluginHandler) Upload(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 
 	r.Body = http.MaxBytesReader(w, r.Body, h.uploadMaxBytes)
 	if err := r.ParseMultipartForm(8 << 20); err != nil {
 		if isRequestBodyTooLarge(err) {
-			respondError(w, http.StatusRequestEntityTooLarge, "uploaded file exceeds maximum allowed size")
+			respondCodedError(w, http.StatusRequestEntityTooLarge, codeUploadTooLarge, "uploaded file exceeds maximum allowed size")
 			return
 		}
-		respondError(w, http.StatusBadRequest, "Failed to parse form data")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidFormData, "Failed to parse form data")
 		return
 	}
 	if r.MultipartForm != nil {
@@ -55,10 +56,13 @@ func (h *PluginHandler) Upload(w http.ResponseWriter, r *http.Request) {
 
 	file, header, err := r.FormFile("file")
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "No file provided")
+		respondCodedError(w, http.StatusBadRequest, codeFileRequired, "No file provided")
 		return
 	}
 	defer file.Close()
+	if !checkUploadQuota(w, h.mgr, id, header.Size) {
+		return
+	}
 
 	tmpFile, err := os.CreateTemp("", "orexa-plugin-upload-*"+filepath.Ext(header.Filename))
 	if err != nil {
@@ -80,51 +84,173 @@ func (h *PluginHandler) Upload(w http.ResponseWriter, r *http.Request) {
 	}
 
 	conflictAction := strings.ToLower(strings.TrimSpace(r.FormValue("conflictAction")))
-	savedName, status, err := h.mgr.UploadPluginFromFile(id, header.Filename, tmpPath, conflictAction)
+	savedName, status, err := h.mgr.UploadPluginFromFile(id, r.FormValue("dir"), header.Filename, tmpPath, conflictAction)
 	if err != nil {
-		if errors.Is(err, os.ErrExist) {
-			respondJSON(w, http.StatusConflict, map[string]string{
-				"error": "file_exists",
-				"name":  header.Filename,
-			})
-			return
-		}
-		if errors.Is(err, minecraft.ErrExtensionAlreadyInstalled) {
-			respondJSON(w, http.StatusConflict, map[string]string{
-				"error": "already_installed",
-			})
-			return
-		}
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondPluginInstallError(w, header.Filename, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": status, "name": savedName})
+}
+
+// InstallFromURL handles POST /api/servers/{id}/plugins/from-url
+func (h *PluginHandler) InstallFromURL(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var req struct {
+		URL            string `json:"url"`
+		ConflictAction string `json:"conflictAction"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.URL) == "" {
+		respondCodedError(w, http.StatusBadRequest, codeDownloadURLRequired, "Download URL is required")
 		return
 	}
 
+	savedName, status, err := h.mgr.InstallPluginFromURL(id, req.URL, req.ConflictAction)
+	if err != nil {
+		respondPluginInstallError(w, savedName, err)
+		return
+	}
 	respondJSON(w, http.StatusOK, map[string]string{"status": status, "name": savedName})
 }
 
-// Delete handles DELETE /api/servers/{id}/plugins/{name}
+// InstallFromCatalog handles POST /api/servers/{id}/plugins/install
+func (h *PluginHandler) InstallFromCatalog(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var req struct {
+		Source         string `json:"source"`
+		ProjectID      string `json:"projectId"`
+		VersionID      string `json:"versionId"`
+		ConflictAction string `json:"conflictAction"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.ProjectID) == "" {
+		respondError(w, http.StatusBadRequest, "Project ID is required")
+		return
+	}
+
+	savedName, status, err := h.mgr.InstallPluginFromCatalog(id, req.Source, req.ProjectID, req.VersionID, req.ConflictAction)
+	if err != nil {
+		respondPluginInstallError(w, savedName, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": status, "name": savedName})
+}
+
+// respondPluginInstallError maps install failures to the conflict and
+// quarantine responses the plugins page understands.
+func respondPluginInstallError(w http.ResponseWriter, fileName string, err error) {
+	var quarantined *minecraft.QuarantineError
+	switch {
+	case errors.Is(err, os.ErrExist):
+		respondJSON(w, http.StatusConflict, map[string]string{
+			"error": "file_exists",
+			"name":  fileName,
+		})
+	case errors.Is(err, minecraft.ErrExtensionAlreadyInstalled):
+		respondJSON(w, http.StatusConflict, map[string]string{
+			"error": "already_installed",
+		})
+	case errors.As(err, &quarantined):
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"error":      "quarantined",
+			"message":    quarantined.Error(),
+			"quarantine": quarantined.Entry,
+		})
+	default:
+		respondErr(w, http.StatusBadRequest, err)
+	}
+}
+
+// ListQuarantine handles GET /api/servers/{id}/plugins/quarantine
+func (h *PluginHandler) ListQuarantine(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.mgr.ListQuarantinedPlugins(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, entries)
+}
+
+// ApproveQuarantine handles POST /api/servers/{id}/plugins/quarantine/{qid}/approve
+func (h *PluginHandler) ApproveQuarantine(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	qid := r.PathValue("qid")
+
+	var req struct {
+		ConflictAction string `json:"conflictAction"`
+	}
+	if err := decodeJSONOptional(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	savedName, status, err := h.mgr.ApproveQuarantinedPlugin(id, qid, req.ConflictAction)
+	if err != nil {
+		respondPluginInstallError(w, savedName, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": status, "name": savedName})
+}
+
+// DiscardQuarantine handles DELETE /api/servers/{id}/plugins/quarantine/{qid}
+func (h *PluginHandler) DiscardQuarantine(w http.ResponseWriter, r *http.Request) {
+	if err := h.mgr.DiscardQuarantinedPlugin(r.PathValue("id"), r.PathValue("qid")); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "discarded"})
+}
+
+// Delete handles DELETE /api/servers/{id}/plugins/{name}?dir=
 func (h *PluginHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	name := r.PathValue("name")
 
-	if err := h.mgr.DeletePlugin(id, name); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	if err := h.mgr.DeletePlugin(id, r.URL.Query().Get("dir"), name); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
 }
 
-// Toggle handles PUT /api/servers/{id}/plugins/{name}/toggle
+// Toggle handles PUT /api/servers/{id}/plugins/{name}/toggle?dir=
 func (h *PluginHandler) Toggle(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	name := r.PathValue("name")
 
-	plugin, err := h.mgr.TogglePlugin(id, name)
+	// An optional note, e.g. why the plugin is being disabled, is saved
+	// against the renamed file.
+	var req struct {
+		Note string `json:"note"`
+	}
+	if err := decodeJSONOptional(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	dir := r.URL.Query().Get("dir")
+	plugin, err := h.mgr.TogglePlugin(id, dir, name)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
+	if strings.TrimSpace(req.Note) != "" {
+		if err := h.mgr.SetPluginNote(id, dir, plugin.FileName, req.Note); err != nil {
+			respondErr(w, http.StatusBadRequest, err)
+			return
+		}
+		plugin.Note = strings.TrimSpace(req.Note)
+	}
 
 	respondJSON(w, http.StatusOK, plugin)
 }
@@ -134,7 +260,7 @@ func (h *PluginHandler) CheckUpdates(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	results, err := h.mgr.CheckPluginUpdates(id)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 	respondJSON(w, http.StatusOK, results)
@@ -146,20 +272,21 @@ func (h *PluginHandler) Update(w http.ResponseWriter, r *http.Request) {
 	name := r.PathValue("name")
 
 	var req struct {
-		URL string `json:"url"`
+		URL   string `json:"url"`
+		Stage bool   `json:"stage"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 	if req.URL == "" {
-		respondError(w, http.StatusBadRequest, "Download URL is required")
+		respondCodedError(w, http.StatusBadRequest, codeDownloadURLRequired, "Download URL is required")
 		return
 	}
 
-	plugin, err := h.mgr.UpdatePlugin(id, name, req.URL)
+	plugin, err := h.mgr.UpdatePlugin(id, name, req.URL, req.Stage)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -175,16 +302,141 @@ func (h *PluginHandler) SetSource(w http.ResponseWriter, r *http.Request) {
 		URL string `json:"url"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 	if req.URL == "" {
-		respondError(w, http.StatusBadRequest, "Source URL is required")
+		respondCodedError(w, http.StatusBadRequest, codeSourceURLRequired, "Source URL is required")
 		return
 	}
 
 	if err := h.mgr.SetPluginSource(id, name, req.URL); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "saved"})
+}
+
+// SetNote handles PUT /api/servers/{id}/plugins/{name}/note
+func (h *PluginHandler) SetNote(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	name := r.PathValue("name")
+
+	var req struct {
+		Note string `json:"note"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	if err := h.mgr.SetPluginNote(id, r.URL.Query().Get("dir"), name, req.Note); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "saved"})
+}
+
+// UpdatesOverview handles GET /api/plugins/updates
+func (h *PluginHandler) UpdatesOverview(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.mgr.CheckAllPluginUpdates())
+}
+
+// Search handles GET /api/plugins/search?q=&type=&serverId=
+func (h *PluginHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	results, err := h.mgr.SearchPlugins(r.Context(), query.Get("q"), query.Get("type"), query.Get("serverId"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, minecraft.ErrPluginCatalogUnavailable) {
+			status = http.StatusBadGateway
+		}
+		respondErr(w, status, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, results)
+}
+
+// SetUpdateChannel handles PUT /api/servers/{id}/plugins/update-channel
+func (h *PluginHandler) SetUpdateChannel(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var req struct {
+		Channel string `json:"channel"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	server, err := h.mgr.SetPluginUpdateChannel(id, req.Channel)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, server)
+}
+
+// ListConfigs handles GET /api/servers/{id}/plugins/{name}/config
+func (h *PluginHandler) ListConfigs(w http.ResponseWriter, r *http.Request) {
+	listing, err := h.mgr.ListPluginConfigs(r.PathValue("id"), r.PathValue("name"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, minecraft.ErrPluginDataFolderNotFound) {
+			status = http.StatusNotFound
+		}
+		respondErr(w, status, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, listing)
+}
+
+// ReadConfig handles GET /api/servers/{id}/plugins/{name}/config/content?path=config.yml
+func (h *PluginHandler) ReadConfig(w http.ResponseWriter, r *http.Request) {
+	configPath := r.URL.Query().Get("path")
+	if configPath == "" {
+		respondCodedError(w, http.StatusBadRequest, codePathRequired, "path parameter is required")
+		return
+	}
+
+	data, err := h.mgr.ReadPluginConfig(r.PathValue("id"), r.PathValue("name"), configPath)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, minecraft.ErrPluginDataFolderNotFound) || errors.Is(err, os.ErrNotExist) {
+			status = http.StatusNotFound
+		}
+		respondErr(w, status, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(data)
+}
+
+// WriteConfig handles PUT /api/servers/{id}/plugins/{name}/config/content
+func (h *PluginHandler) WriteConfig(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Path    string `json:"path"`
+		Content string `json:"content"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	if req.Path == "" {
+		respondCodedError(w, http.StatusBadRequest, codePathRequired, "path is required")
+		return
+	}
+
+	if err := h.mgr.WritePluginConfig(r.PathValue("id"), r.PathValue("name"), req.Path, []byte(req.Content)); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, minecraft.ErrPluginDataFolderNotFound) || errors.Is(err, os.ErrNotExist) {
+			status = http.StatusNotFound
+		}
+		respondErr(w, status, err)
 		return
 	}
 
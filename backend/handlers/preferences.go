@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"minecraft-admin/minecraft"
+)
+
+// GetPreferences handles GET /api/preferences
+func (h *AuthHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	username, ok := h.usernameFromRequest(r)
+	if !ok {
+		respondCodedError(w, http.StatusUnauthorized, codeAuthenticationRequired, "Authentication required")
+		return
+	}
+	prefs, err := h.mgr.GetUserPreferences(username)
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, prefs)
+}
+
+// UpdatePreferences handles PUT /api/preferences
+func (h *AuthHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	username, ok := h.usernameFromRequest(r)
+	if !ok {
+		respondCodedError(w, http.StatusUnauthorized, codeAuthenticationRequired, "Authentication required")
+		return
+	}
+	var req minecraft.UserPreferences
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	prefs, err := h.mgr.SetUserPreferences(username, req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, prefs)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+
+	"minecraft-admin/minecraft"
+)
+
+// GetRegionPrune handles GET /api/servers/{id}/region-prune
+func (h *BackupHandler) GetRegionPrune(w http.ResponseWriter, r *http.Request) {
+	info, err := h.mgr.GetRegionPrune(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, info)
+}
+
+// SetRegionPrune handles PUT /api/servers/{id}/region-prune
+func (h *BackupHandler) SetRegionPrune(w http.ResponseWriter, r *http.Request) {
+	var req minecraft.RegionPruneSettings
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	info, err := h.mgr.SetRegionPrune(r.PathValue("id"), req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, info)
+}
+
+// PreviewRegionPrune handles POST /api/servers/{id}/region-prune/preview
+func (h *BackupHandler) PreviewRegionPrune(w http.ResponseWriter, r *http.Request) {
+	var req minecraft.RegionPruneSettings
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	plan, err := h.mgr.PreviewRegionPrune(r.PathValue("id"), req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, plan)
+}
+
+// PruneRegions handles POST /api/servers/{id}/region-prune
+func (h *BackupHandler) PruneRegions(w http.ResponseWriter, r *http.Request) {
+	var req minecraft.RegionPruneSettings
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	result, err := h.mgr.PruneRegions(r.PathValue("id"), req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"minecraft-admin/minecraft"
+)
+
+type (
+	sessionRoleKey struct{}
+	sessionUserKey struct{}
+)
+
+// withSessionRole records the role of the session that authenticated r.
+func withSessionRole(r *http.Request, role string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), sessionRoleKey{}, role))
+}
+
+// withSessionUser records the username of the session that authenticated r.
+func withSessionUser(r *http.Request, username string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), sessionUserKey{}, username))
+}
+
+// sessionUsername returns who made r, or "" for requests that did not pass
+// through the session middleware.
+func sessionUsername(r *http.Request) string {
+	username, _ := r.Context().Value(sessionUserKey{}).(string)
+	return username
+}
+
+// canSendCommands reports whether r may run console commands. Requests
+// that did not pass through the session middleware are not restricted.
+func canSendCommands(r *http.Request) bool {
+	role, ok := r.Context().Value(sessionRoleKey{}).(string)
+	return !ok || role != minecraft.RoleViewer
+}
+
+// isAdminSession reports whether r was made by an admin. Requests that did
+// not pass through the session middleware are not restricted.
+func isAdminSession(r *http.Request) bool {
+	role, ok := r.Context().Value(sessionRoleKey{}).(string)
+	return !ok || role == minecraft.RoleAdmin
+}
+
+// roleAllows reports whether role may call method on path. Admins may do
+// anything. Operators run servers but cannot delete them or change panel
+// settings, and viewers only read.
+func roleAllows(role, method, path string) bool {
+	switch role {
+	case minecraft.RoleAdmin:
+		return true
+	case minecraft.RoleOperator, minecraft.RoleViewer:
+	default:
+		return false
+	}
+
+	if adminOnlyPath(path) {
+		return false
+	}
+	if role == minecraft.RoleViewer && viewerHiddenPath(path) {
+		return false
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return true
+	}
+	// Per-user actions that change nothing on the servers or the panel.
+	if (path == "/api/auth/ws-ticket" && method == http.MethodPost) || (path == "/api/preferences" && method == http.MethodPut) {
+		return true
+	}
+	if role == minecraft.RoleViewer {
+		return false
+	}
+	return operatorAction(method, path)
+}
+
+// adminOnlyPath covers user management, security settings, panel
+// configuration and backup targets, which only admins may read or change.
+func adminOnlyPath(path string) bool {
+	for _, prefix := range []string{"/api/users", "/api/security/", "/api/system/config/", "/api/backup-targets/"} {
+		if path == strings.TrimSuffix(prefix, "/") || strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// viewerHiddenPath covers reads that expose server files, which can hold
+// secrets such as the RCON password.
+func viewerHiddenPath(path string) bool {
+	parts := strings.Split(strings.TrimPrefix(path, "/api/servers/"), "/")
+	if !strings.HasPrefix(path, "/api/servers/") || len(parts) < 2 {
+		return false
+	}
+	switch {
+	case parts[1] == "files" && len(parts) == 3 && (parts[2] == "content" || parts[2] == "download"):
+		return true
+	case parts[1] == "backups" && len(parts) == 4 && parts[3] == "download":
+		return true
+	case parts[1] == "export" && len(parts) == 3:
+		return true
+	case parts[1] == "plugins" && len(parts) == 5 && parts[3] == "config" && parts[4] == "content":
+		return true
+	}
+	return false
+}
+
+// operatorAction lists the changes operators may make: server lifecycle
+// including suspending and resuming, console commands, scheduled restarts
+// and stops, new backups, player moderation including lifting temporary
+// bans, whitelist and ban list edits and proxy transfers, structure
+// lookups, join checks and cancelling jobs. Granting operator status stays with admins.
+func operatorAction(method, path string) bool {
+	if strings.HasPrefix(path, "/api/jobs/") && strings.HasSuffix(path, "/cancel") && method == http.MethodPost {
+		return true
+	}
+	if !strings.HasPrefix(path, "/api/servers/") {
+		return false
+	}
+	parts := strings.Split(strings.TrimPrefix(path, "/api/servers/"), "/")
+	if len(parts) < 2 || parts[0] == "" {
+		return false
+	}
+	switch len(parts) {
+	case 2:
+		switch parts[1] {
+		case "start", "start-safe", "stop", "kill", "suspend", "resume", "command", "schedule-stop", "backups", "locate", "join-check", "whitelist", "bans":
+			return method == http.MethodPost
+		case "schedule-restart":
+			return method == http.MethodPost || method == http.MethodDelete
+		}
+	case 3:
+		switch parts[1] {
+		case "files":
+			return parts[2] == "download" && method == http.MethodPost
+		case "tempbans", "whitelist", "bans":
+			return method == http.MethodDelete
+		}
+	case 4:
+		if parts[1] == "players" && method == http.MethodPost {
+			switch parts[3] {
+			case "kick", "ban", "kill", "send":
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"log"
@@ -16,6 +17,12 @@ const (
 	defaultMaxUploadBytes       int64 = 256 * 1024 * 1024
 	defaultMaxServerImportBytes int64 = 8 * 1024 * 1024 * 1024
 	defaultCSRFMode                   = "enforce"
+
+	// csrfCookieName holds the per-session CSRF token. It is readable by the
+	// SPA, which echoes it in csrfHeaderName on state-changing requests; a
+	// cross-site page can send the session cookie but cannot read this one.
+	csrfCookieName = "orexa_csrf"
+	csrfHeaderName = "X-CSRF-Token"
 )
 
 type trustedProxySet struct {
@@ -124,6 +131,29 @@ func parseForwardedForIPs(xffHeader string) []net.IP {
 }
 
 // Resolve trusted proxy chains from right-to-left to avoid spoofed left-most entries.
+// requestClientIP returns the client address of r, taking X-Forwarded-For
+// into account only when the request comes from a trusted proxy.
+// directRequest reports whether r carries no proxy headers, so the client
+// address is the connection's own peer.
+func directRequest(r *http.Request) bool {
+	for _, header := range []string{"X-Forwarded-For", "X-Real-IP", "Forwarded"} {
+		if r.Header.Get(header) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func requestClientIP(r *http.Request, trusted *trustedProxySet) string {
+	if xff := strings.TrimSpace(r.Header.Get("X-Forwarded-For")); xff != "" {
+		return realClientIPFromXFF(r.RemoteAddr, xff, trusted)
+	}
+	if ip := remoteAddrIP(r.RemoteAddr); ip != nil {
+		return ip.String()
+	}
+	return strings.TrimSpace(r.RemoteAddr)
+}
+
 func realClientIPFromXFF(remoteAddr string, xffHeader string, trusted *trustedProxySet) string {
 	remoteIP := remoteAddrIP(remoteAddr)
 	if remoteIP == nil {
@@ -335,6 +365,16 @@ func requestOriginMatchesCSRF(r *http.Request, trusted *trustedProxySet) bool {
 	return true
 }
 
+// requestCSRFTokenMatches reports whether r carries the session's CSRF token
+// in csrfHeaderName.
+func requestCSRFTokenMatches(r *http.Request, expected string) bool {
+	got := strings.TrimSpace(r.Header.Get(csrfHeaderName))
+	if got == "" || expected == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(expected)) == 1
+}
+
 func isRequestBodyTooLarge(err error) bool {
 	if err == nil {
 		return false
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"minecraft-admin/minecraft"
+)
+
+const cspReportPath = "/api/csp-report"
+
+// contentSecurityPolicy fits the built SPA: scripts and styles come from the
+// panel itself (inline styles are needed by the toast and animation
+// libraries), images may come from plugin and avatar hosts, and the console
+// streams over WebSocket.
+var contentSecurityPolicy = strings.Join([]string{
+	"default-src 'self'",
+	"script-src 'self'",
+	"style-src 'self' 'unsafe-inline'",
+	"img-src 'self' data: https:",
+	"font-src 'self' data:",
+	"connect-src 'self' ws: wss:",
+	"object-src 'none'",
+	"base-uri 'self'",
+	"form-action 'self'",
+	"frame-ancestors 'none'",
+	"report-uri " + cspReportPath,
+}, "; ")
+
+// SecurityHeaders sets the browser hardening headers on every response. The
+// CSP is sent as report-only when the panel settings ask for it. The public
+// status page gets its own policy and may be framed.
+func SecurityHeaders(mgr *minecraft.Manager, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		cspHeader := "Content-Security-Policy"
+		if mgr != nil && mgr.GetSettings().CSPReportOnly {
+			cspHeader = "Content-Security-Policy-Report-Only"
+		}
+		if r.URL.Path == statusPagePath {
+			header.Set(cspHeader, statusPagePolicy)
+		} else {
+			header.Set(cspHeader, contentSecurityPolicy)
+			header.Set("X-Frame-Options", "DENY")
+		}
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("Referrer-Policy", "same-origin")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CSPReport handles POST /api/csp-report. Browsers post violations here
+// without credentials, so the body is size-capped and only logged.
+func CSPReport(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 8<<10))
+	if err == nil && len(body) > 0 {
+		log.Printf("CSP violation report: %s", strings.TrimSpace(string(body)))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
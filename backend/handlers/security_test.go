@@ -4,7 +4,10 @@ import (
 	"crypto/tls"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"minecraft-admin/minecraft"
 )
 
 func TestUploadMaxBytesFromEnv(t *testing.T) {
@@ -108,3 +111,45 @@ func TestRequestOriginMatchesCSRF(t *testing.T) {
 		t.Fatalf("expected cross-origin csrf check to fail")
 	}
 }
+
+func TestSecurityHeadersFollowReportOnlySetting(t *testing.T) {
+	base := t.TempDir()
+	mgr, err := minecraft.NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	handler := SecurityHeaders(mgr, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func() http.Header {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rec.Result().Header
+	}
+
+	headers := serve()
+	if headers.Get("Content-Security-Policy") == "" || headers.Get("Content-Security-Policy-Report-Only") != "" {
+		t.Fatalf("expected an enforced CSP by default, got %v", headers)
+	}
+	if headers.Get("X-Frame-Options") != "DENY" || headers.Get("X-Content-Type-Options") != "nosniff" || headers.Get("Referrer-Policy") == "" {
+		t.Fatalf("missing hardening headers: %v", headers)
+	}
+
+	reportOnly := true
+	if _, _, err := mgr.PatchAppSettings(minecraft.AppSettingsPatch{CSPReportOnly: &reportOnly}); err != nil {
+		t.Fatalf("PatchAppSettings failed: %v", err)
+	}
+	headers = serve()
+	if headers.Get("Content-Security-Policy") != "" || headers.Get("Content-Security-Policy-Report-Only") == "" {
+		t.Fatalf("expected report-only CSP after enabling the setting, got %v", headers)
+	}
+
+	auth := NewAuthHandler(mgr, base).Middleware(http.HandlerFunc(CSPReport))
+	rec := httptest.NewRecorder()
+	auth.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, cspReportPath, strings.NewReader(`{"csp-report":{}}`)))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected CSP reports to be accepted without a session, got %d", rec.Code)
+	}
+}
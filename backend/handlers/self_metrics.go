@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"minecraft-admin/minecraft"
+)
+
+// latencyBucketsMs are the upper bounds of the API latency histogram
+// buckets, in milliseconds. Slower requests land in the last, open bucket.
+var latencyBucketsMs = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// openWebSockets counts the console, multi-console and status streams
+// currently connected.
+var openWebSockets atomic.Int64
+
+// trackWebSocket counts a WebSocket as open until the returned func runs.
+func trackWebSocket() func() {
+	openWebSockets.Add(1)
+	return func() { openWebSockets.Add(-1) }
+}
+
+// routeLatency is the histogram of one route. counts has one more entry
+// than latencyBucketsMs for requests slower than the last bound.
+type routeLatency struct {
+	counts []uint64
+	count  uint64
+	sumMs  float64
+	maxMs  float64
+	errors uint64
+}
+
+// LatencyBucket is one histogram bucket: how many requests took at most
+// LeMs milliseconds. The last bucket has no bound.
+type LatencyBucket struct {
+	LeMs  float64 `json:"leMs,omitempty"`
+	Count uint64  `json:"count"`
+}
+
+// RouteLatency is the latency histogram of one API route since the panel
+// started. Errors counts 5xx responses.
+type RouteLatency struct {
+	Route   string          `json:"route"`
+	Count   uint64          `json:"count"`
+	Errors  uint64          `json:"errors"`
+	MeanMs  float64         `json:"meanMs"`
+	MaxMs   float64         `json:"maxMs"`
+	Buckets []LatencyBucket `json:"buckets"`
+}
+
+// SelfMetrics is what the panel reports about its own process.
+type SelfMetrics struct {
+	UptimeSeconds  int64          `json:"uptimeSeconds"`
+	Goroutines     int            `json:"goroutines"`
+	HeapAllocBytes uint64         `json:"heapAllocBytes"`
+	HeapInuseBytes uint64         `json:"heapInuseBytes"`
+	SysBytes       uint64         `json:"sysBytes"`
+	HeapObjects    uint64         `json:"heapObjects"`
+	GCCycles       uint32         `json:"gcCycles"`
+	LastGCPauseMs  float64        `json:"lastGcPauseMs"`
+	OpenWebSockets int64          `json:"openWebSockets"`
+	JobsQueued     int            `json:"jobsQueued"`
+	JobsRunning    int            `json:"jobsRunning"`
+	API            []RouteLatency `json:"api"`
+}
+
+// SelfMetricsHandler times API requests and reports the panel's own
+// goroutines, memory, sockets and job queue, so leaks show up before they
+// take the panel down.
+type SelfMetricsHandler struct {
+	mgr       *minecraft.Manager
+	startedAt time.Time
+
+	mu     sync.Mutex
+	routes map[string]*routeLatency
+}
+
+func NewSelfMetricsHandler(mgr *minecraft.Manager) *SelfMetricsHandler {
+	return &SelfMetricsHandler{mgr: mgr, startedAt: time.Now(), routes: make(map[string]*routeLatency)}
+}
+
+// Middleware times every API request mux serves, grouped by the route
+// pattern it matched so IDs in paths do not split the histograms.
+// WebSocket streams stay open for as long as the client watches and are
+// counted separately.
+func (h *SelfMetricsHandler) Middleware(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") || strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		_, route := mux.Handler(r)
+		if route == "" {
+			route = "unmatched"
+		}
+		sw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		started := time.Now()
+		mux.ServeHTTP(sw, r)
+		h.observe(route, time.Since(started), sw.status)
+	})
+}
+
+func (h *SelfMetricsHandler) observe(route string, elapsed time.Duration, status int) {
+	ms := float64(elapsed) / float64(time.Millisecond)
+	bucket := sort.SearchFloat64s(latencyBucketsMs, ms)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	stats, ok := h.routes[route]
+	if !ok {
+		stats = &routeLatency{counts: make([]uint64, len(latencyBucketsMs)+1)}
+		h.routes[route] = stats
+	}
+	stats.counts[bucket]++
+	stats.count++
+	stats.sumMs += ms
+	stats.maxMs = max(stats.maxMs, ms)
+	if status >= 500 {
+		stats.errors++
+	}
+}
+
+// Snapshot returns the current metrics, with routes sorted by name.
+func (h *SelfMetricsHandler) Snapshot() SelfMetrics {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	metrics := SelfMetrics{
+		UptimeSeconds:  int64(time.Since(h.startedAt).Seconds()),
+		Goroutines:     runtime.NumGoroutine(),
+		HeapAllocBytes: mem.HeapAlloc,
+		HeapInuseBytes: mem.HeapInuse,
+		SysBytes:       mem.Sys,
+		HeapObjects:    mem.HeapObjects,
+		GCCycles:       mem.NumGC,
+		OpenWebSockets: openWebSockets.Load(),
+	}
+	if mem.NumGC > 0 {
+		metrics.LastGCPauseMs = float64(mem.PauseNs[(mem.NumGC+255)%256]) / float64(time.Millisecond)
+	}
+	if h.mgr != nil {
+		metrics.JobsQueued, metrics.JobsRunning = h.mgr.JobCounts()
+	}
+
+	h.mu.Lock()
+	metrics.API = make([]RouteLatency, 0, len(h.routes))
+	for route, stats := range h.routes {
+		entry := RouteLatency{
+			Route:   route,
+			Count:   stats.count,
+			Errors:  stats.errors,
+			MeanMs:  stats.sumMs / float64(stats.count),
+			MaxMs:   stats.maxMs,
+			Buckets: make([]LatencyBucket, len(stats.counts)),
+		}
+		for i, count := range stats.counts {
+			entry.Buckets[i].Count = count
+			if i < len(latencyBucketsMs) {
+				entry.Buckets[i].LeMs = latencyBucketsMs[i]
+			}
+		}
+		metrics.API = append(metrics.API, entry)
+	}
+	h.mu.Unlock()
+	sort.Slice(metrics.API, func(i, j int) bool { return metrics.API[i].Route < metrics.API[j].Route })
+	return metrics
+}
+
+// Get handles GET /api/system/self-metrics
+func (h *SelfMetricsHandler) Get(w http.ResponseWriter, _ *http.Request) {
+	respondJSON(w, http.StatusOK, h.Snapshot())
+}
+
+// statusRecorder remembers the status code a handler wrote.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush passes through so streamed downloads are not buffered.
+func (w *statusRecorder) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
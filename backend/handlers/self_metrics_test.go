@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSelfMetricsGroupsLatencyByRoute(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/servers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, map[string]string{"id": r.PathValue("id")})
+	})
+	mux.HandleFunc("POST /api/servers/{id}/start", func(w http.ResponseWriter, r *http.Request) {
+		respondError(w, http.StatusInternalServerError, "boom")
+	})
+	h := NewSelfMetricsHandler(nil)
+	handler := h.Middleware(mux)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/api/servers/alpha", nil),
+		httptest.NewRequest(http.MethodGet, "/api/servers/beta", nil),
+		httptest.NewRequest(http.MethodPost, "/api/servers/alpha/start", nil),
+		httptest.NewRequest(http.MethodGet, "/index.html", nil),
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	done := trackWebSocket()
+	metrics := h.Snapshot()
+	done()
+
+	if metrics.OpenWebSockets < 1 || metrics.Goroutines < 1 || metrics.HeapAllocBytes == 0 {
+		t.Fatalf("expected runtime and socket figures, got %+v", metrics)
+	}
+	if len(metrics.API) != 2 {
+		t.Fatalf("expected two routes, got %+v", metrics.API)
+	}
+	get, start := metrics.API[0], metrics.API[1]
+	if get.Route != "GET /api/servers/{id}" || get.Count != 2 || get.Errors != 0 {
+		t.Fatalf("unexpected stats for the detail route: %+v", get)
+	}
+	if start.Route != "POST /api/servers/{id}/start" || start.Count != 1 || start.Errors != 1 {
+		t.Fatalf("unexpected stats for the start route: %+v", start)
+	}
+	var bucketed uint64
+	for _, bucket := range get.Buckets {
+		bucketed += bucket.Count
+	}
+	if bucketed != get.Count || len(get.Buckets) != len(latencyBucketsMs)+1 {
+		t.Fatalf("expected every request in one bucket, got %+v", get.Buckets)
+	}
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"minecraft-admin/minecraft"
@@ -49,7 +52,7 @@ func (h *ServerHandler) Reorder(w http.ResponseWriter, r *http.Request) {
 		OrderedIDs []string `json:"orderedIds"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 	if len(req.OrderedIDs) == 0 {
@@ -58,7 +61,7 @@ func (h *ServerHandler) Reorder(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.mgr.SetServerOrder(req.OrderedIDs); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -69,45 +72,35 @@ func (h *ServerHandler) Reorder(w http.ResponseWriter, r *http.Request) {
 func (h *ServerHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req CreateServerRequest
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 
 	if req.Name == "" {
-		respondError(w, http.StatusBadRequest, "Server name is required")
+		respondCodedError(w, http.StatusBadRequest, codeServerNameRequired, "Server name is required")
 		return
 	}
 	if req.Type == "" {
-		respondError(w, http.StatusBadRequest, "Server type is required")
+		respondCodedError(w, http.StatusBadRequest, codeServerTypeRequired, "Server type is required")
 		return
 	}
 	if _, err := minecraft.GetProvider(req.Type); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 	if req.Version == "" {
 		req.Version = "Latest"
 	}
-	if req.Port == 0 {
-		req.Port = 25565
-	}
-	if req.Port < 1024 || req.Port > 65535 {
-		respondError(w, http.StatusBadRequest, "Port must be between 1024 and 65535")
+	// A zero port and empty RAM, player and flag values take the panel's
+	// new-server defaults.
+	if req.Port != 0 && (req.Port < 1024 || req.Port > 65535) {
+		respondCodedError(w, http.StatusBadRequest, minecraft.MessagePortOutOfRange, "Port must be between 1024 and 65535")
 		return
 	}
-	if req.MinRAM == "" {
-		req.MinRAM = "512M"
-	}
-	if req.MaxRAM == "" {
-		req.MaxRAM = "1024M"
-	}
-	if req.MaxPlayers <= 0 {
-		req.MaxPlayers = 20
-	}
 
 	server, err := h.mgr.CreateServer(req.Name, req.Type, req.Version, req.Port, req.MinRAM, req.MaxRAM, req.MaxPlayers, req.Flags, req.AlwaysPreTouch)
 	if err != nil {
-		respondError(w, http.StatusConflict, err.Error())
+		respondErr(w, http.StatusConflict, err)
 		return
 	}
 
@@ -118,40 +111,71 @@ func (h *ServerHandler) Create(w http.ResponseWriter, r *http.Request) {
 func (h *ServerHandler) Start(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
-		respondError(w, http.StatusBadRequest, "Server ID is required")
+		respondCodedError(w, http.StatusBadRequest, codeServerIDRequired, "Server ID is required")
 		return
 	}
 
 	if err := h.mgr.StartServer(id); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
 	status, err := h.mgr.GetStatus(id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, http.StatusInternalServerError, err)
 		return
 	}
 
 	respondJSON(w, http.StatusOK, status)
 }
 
+// BootFailure handles GET /api/servers/{id}/boot-failure
+func (h *ServerHandler) BootFailure(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	report, err := h.mgr.BootFailureReport(id)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	if report == nil {
+		respondError(w, http.StatusNotFound, "No boot failure recorded for this server")
+		return
+	}
+	respondJSON(w, http.StatusOK, report)
+}
+
 // StartSafeMode handles POST /api/servers/{id}/start-safe
 func (h *ServerHandler) StartSafeMode(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
-		respondError(w, http.StatusBadRequest, "Server ID is required")
+		respondCodedError(w, http.StatusBadRequest, codeServerIDRequired, "Server ID is required")
+		return
+	}
+
+	// Without a selection every plugin and mod is disabled; with one, only
+	// the listed files are.
+	var req struct {
+		Disable []minecraft.ExtensionRef `json:"disable"`
+	}
+	if err := decodeJSONOptional(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 
-	if err := h.mgr.StartServerSafeMode(id); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	var err error
+	if len(req.Disable) > 0 {
+		err = h.mgr.StartServerSafeModeSelective(id, req.Disable)
+	} else {
+		err = h.mgr.StartServerSafeMode(id)
+	}
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
 	status, err := h.mgr.GetStatus(id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -162,18 +186,18 @@ func (h *ServerHandler) StartSafeMode(w http.ResponseWriter, r *http.Request) {
 func (h *ServerHandler) Stop(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
-		respondError(w, http.StatusBadRequest, "Server ID is required")
+		respondCodedError(w, http.StatusBadRequest, codeServerIDRequired, "Server ID is required")
 		return
 	}
 
 	if err := h.mgr.StopServer(id); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
 	status, err := h.mgr.GetStatus(id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -184,35 +208,106 @@ func (h *ServerHandler) Stop(w http.ResponseWriter, r *http.Request) {
 func (h *ServerHandler) Kill(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
-		respondError(w, http.StatusBadRequest, "Server ID is required")
+		respondCodedError(w, http.StatusBadRequest, codeServerIDRequired, "Server ID is required")
 		return
 	}
 
 	if err := h.mgr.KillServer(id); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	status, err := h.mgr.GetStatus(id)
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, status)
+}
+
+// Suspend handles POST /api/servers/{id}/suspend
+func (h *ServerHandler) Suspend(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.mgr.SuspendServer(id); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
 	status, err := h.mgr.GetStatus(id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, http.StatusInternalServerError, err)
 		return
 	}
 
 	respondJSON(w, http.StatusOK, status)
 }
 
+// Resume handles POST /api/servers/{id}/resume
+func (h *ServerHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.mgr.ResumeServer(id); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	status, err := h.mgr.GetStatus(id)
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, status)
+}
+
+// SendCommand handles POST /api/servers/{id}/command
+func (h *ServerHandler) SendCommand(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req struct {
+		Command string `json:"command"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	command := strings.TrimSpace(req.Command)
+	if command == "" {
+		respondError(w, http.StatusBadRequest, "command is required")
+		return
+	}
+
+	if err := h.mgr.SendCommand(id, command); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.mgr.RecordConsoleCommand(id, command); err != nil {
+		log.Printf("Failed to record command in console for server %s: %v", id, err)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "sent"})
+}
+
+// Get handles GET /api/servers/{id}. Install paths are shown to admins only.
+func (h *ServerHandler) Get(w http.ResponseWriter, r *http.Request) {
+	detail, err := h.mgr.GetServerDetail(r.PathValue("id"), isAdminSession(r))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, detail)
+}
+
 // Status handles GET /api/servers/{id}/status
 func (h *ServerHandler) Status(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
-		respondError(w, http.StatusBadRequest, "Server ID is required")
+		respondCodedError(w, http.StatusBadRequest, codeServerIDRequired, "Server ID is required")
 		return
 	}
 
 	status, err := h.mgr.GetStatus(id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, err.Error())
+		respondErr(w, http.StatusNotFound, err)
 		return
 	}
 
@@ -223,19 +318,20 @@ func (h *ServerHandler) Status(w http.ResponseWriter, r *http.Request) {
 func (h *ServerHandler) ScheduleRestart(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	var req struct {
-		DelaySeconds int `json:"delaySeconds"`
+		DelaySeconds int    `json:"delaySeconds"`
+		Reason       string `json:"reason"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 	if req.DelaySeconds < 0 {
-		respondError(w, http.StatusBadRequest, "delaySeconds must be zero or positive")
+		respondCodedError(w, http.StatusBadRequest, codeDelayMustNotBeNegative, "delaySeconds must be zero or positive")
 		return
 	}
 
-	if err := h.mgr.ScheduleRestart(id, req.DelaySeconds); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	if err := h.mgr.ScheduleRestart(id, req.DelaySeconds, req.Reason); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -246,29 +342,135 @@ func (h *ServerHandler) ScheduleRestart(w http.ResponseWriter, r *http.Request)
 func (h *ServerHandler) CancelRestart(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if err := h.mgr.CancelRestart(id); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 	respondJSON(w, http.StatusOK, map[string]string{"status": "cancelled"})
 }
 
+// ImportDirectory handles POST /api/servers/import, registering a server
+// folder that already exists under one of the import roots. The copy runs
+// as a job that carries the new server as its result.
+func (h *ServerHandler) ImportDirectory(w http.ResponseWriter, r *http.Request) {
+	var opts minecraft.ServerDirImportOptions
+	if err := decodeJSON(r, &opts); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	job, err := h.mgr.StartImportServerDirectory(opts)
+	if err != nil {
+		var portErr *minecraft.ImportPortConflictError
+		if errors.As(err, &portErr) {
+			respondJSON(w, http.StatusBadRequest, map[string]any{
+				"error":         "port_in_use",
+				"message":       "That port is already in use.",
+				"suggestedPort": portErr.SuggestedPort,
+			})
+			return
+		}
+		if errors.Is(err, minecraft.ErrDirectoryImportDisabled) {
+			respondErr(w, http.StatusForbidden, err)
+			return
+		}
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// Export handles POST /api/servers/{id}/export
+func (h *ServerHandler) Export(w http.ResponseWriter, r *http.Request) {
+	job, err := h.mgr.StartServerExport(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// DownloadExport handles GET /api/servers/{id}/export/{name}
+func (h *ServerHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	path, err := h.mgr.ServerExportPath(r.PathValue("id"), name)
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+name+"\"")
+	w.Header().Set("Content-Type", "application/gzip")
+	http.ServeFile(w, r, path)
+}
+
+// ImportArchive handles POST /api/servers/import/archive
+func (h *ServerHandler) ImportArchive(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.importMaxBytes)
+	if err := r.ParseMultipartForm(8 << 20); err != nil {
+		if isRequestBodyTooLarge(err) {
+			respondCodedError(w, http.StatusRequestEntityTooLarge, codeUploadTooLarge, "uploaded file exceeds maximum allowed size")
+			return
+		}
+		respondCodedError(w, http.StatusBadRequest, codeInvalidFormData, "Failed to parse form data")
+		return
+	}
+	if r.MultipartForm != nil {
+		defer r.MultipartForm.RemoveAll()
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeFileRequired, "No file provided")
+		return
+	}
+	defer file.Close()
+
+	opts := minecraft.ServerArchiveImportOptions{Name: r.FormValue("name")}
+	if raw := strings.TrimSpace(r.FormValue("port")); raw != "" {
+		port, err := strconv.Atoi(raw)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "port must be a number")
+			return
+		}
+		opts.Port = port
+	}
+
+	server, err := h.mgr.ImportServerArchive(file, opts)
+	if err != nil {
+		var portErr *minecraft.ImportPortConflictError
+		if errors.As(err, &portErr) {
+			respondJSON(w, http.StatusBadRequest, map[string]any{
+				"error":         "port_in_use",
+				"message":       "That port is already in use.",
+				"suggestedPort": portErr.SuggestedPort,
+			})
+			return
+		}
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, server)
+}
+
 // ScheduleStop handles POST /api/servers/{id}/schedule-stop
 func (h *ServerHandler) ScheduleStop(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	var req struct {
-		DelaySeconds int `json:"delaySeconds"`
+		DelaySeconds int    `json:"delaySeconds"`
+		Reason       string `json:"reason"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 	if req.DelaySeconds <= 0 {
-		respondError(w, http.StatusBadRequest, "delaySeconds must be positive")
+		respondCodedError(w, http.StatusBadRequest, codeDelayMustBePositive, "delaySeconds must be positive")
 		return
 	}
 
-	if err := h.mgr.ScheduleStop(id, req.DelaySeconds); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	if err := h.mgr.ScheduleStop(id, req.DelaySeconds, req.Reason); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -284,9 +486,10 @@ func (h *ServerHandler) Clone(w http.ResponseWriter, r *http.Request) {
 		CopyPlugins bool   `json:"copyPlugins"`
 		CopyWorlds  bool   `json:"copyWorlds"`
 		CopyConfig  bool   `json:"copyConfig"`
+		LinkFiles   bool   `json:"linkFiles"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 	if req.SourceID == "" || req.Name == "" {
@@ -297,13 +500,39 @@ func (h *ServerHandler) Clone(w http.ResponseWriter, r *http.Request) {
 		req.Port = 25565
 	}
 
-	server, err := h.mgr.CloneServer(req.SourceID, req.Name, req.Port, req.CopyPlugins, req.CopyWorlds, req.CopyConfig)
+	job, err := h.mgr.StartCloneServer(req.SourceID, req.Name, req.Port, minecraft.CloneOptions{
+		CopyPlugins: req.CopyPlugins,
+		CopyWorlds:  req.CopyWorlds,
+		CopyConfig:  req.CopyConfig,
+		LinkFiles:   req.LinkFiles,
+	})
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, server)
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// RestoreAsNew handles POST /api/servers/restore-as-new
+func (h *ServerHandler) RestoreAsNew(w http.ResponseWriter, r *http.Request) {
+	var req minecraft.RestoreAsNewOptions
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	if req.SourceID == "" || req.Backup == "" {
+		respondError(w, http.StatusBadRequest, "sourceId and backup are required")
+		return
+	}
+
+	job, err := h.mgr.StartRestoreBackupAsNew(req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, job)
 }
 
 // AnalyzeImport handles POST /api/servers/import/analyze (multipart form)
@@ -311,10 +540,10 @@ func (h *ServerHandler) AnalyzeImport(w http.ResponseWriter, r *http.Request) {
 	r.Body = http.MaxBytesReader(w, r.Body, h.importMaxBytes)
 	if err := r.ParseMultipartForm(8 << 20); err != nil {
 		if isRequestBodyTooLarge(err) {
-			respondError(w, http.StatusRequestEntityTooLarge, "uploaded file exceeds maximum allowed size")
+			respondCodedError(w, http.StatusRequestEntityTooLarge, codeUploadTooLarge, "uploaded file exceeds maximum allowed size")
 			return
 		}
-		respondError(w, http.StatusBadRequest, "Failed to parse form data")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidFormData, "Failed to parse form data")
 		return
 	}
 	if r.MultipartForm != nil {
@@ -323,14 +552,14 @@ func (h *ServerHandler) AnalyzeImport(w http.ResponseWriter, r *http.Request) {
 
 	file, header, err := r.FormFile("file")
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "No file provided")
+		respondCodedError(w, http.StatusBadRequest, codeFileRequired, "No file provided")
 		return
 	}
 	defer file.Close()
 
 	result, err := h.mgr.AnalyzeServerImportArchive(header.Filename, file)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -353,7 +582,7 @@ func (h *ServerHandler) CommitImport(w http.ResponseWriter, r *http.Request) {
 		} `json:"properties"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 	if strings.TrimSpace(req.AnalysisID) == "" {
@@ -393,7 +622,7 @@ func (h *ServerHandler) CommitImport(w http.ResponseWriter, r *http.Request) {
 			})
 			return
 		}
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -409,23 +638,170 @@ func (h *ServerHandler) CancelImport(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.mgr.CancelServerImportAnalysis(analysisID); err != nil {
-		respondError(w, http.StatusNotFound, err.Error())
+		respondErr(w, http.StatusNotFound, err)
 		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]string{"status": "cancelled"})
 }
 
+// InstallModpack handles POST /api/servers/modpack, a multipart upload of
+// a Modrinth .mrpack or CurseForge pack zip with the new server's settings
+// as form fields.
+func (h *ServerHandler) InstallModpack(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.importMaxBytes)
+	if err := r.ParseMultipartForm(8 << 20); err != nil {
+		if isRequestBodyTooLarge(err) {
+			respondCodedError(w, http.StatusRequestEntityTooLarge, codeUploadTooLarge, "uploaded file exceeds maximum allowed size")
+			return
+		}
+		respondCodedError(w, http.StatusBadRequest, codeInvalidFormData, "Failed to parse form data")
+		return
+	}
+	if r.MultipartForm != nil {
+		defer r.MultipartForm.RemoveAll()
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeFileRequired, "No file provided")
+		return
+	}
+	defer file.Close()
+	lowerName := strings.ToLower(strings.TrimSpace(header.Filename))
+	if !strings.HasSuffix(lowerName, ".mrpack") && !strings.HasSuffix(lowerName, ".zip") {
+		respondError(w, http.StatusBadRequest, "unsupported file format, use a .mrpack or .zip modpack")
+		return
+	}
+
+	opts := minecraft.ModpackInstallOptions{
+		Name:           strings.TrimSpace(r.FormValue("name")),
+		MinRAM:         strings.TrimSpace(r.FormValue("minRam")),
+		MaxRAM:         strings.TrimSpace(r.FormValue("maxRam")),
+		Flags:          strings.TrimSpace(r.FormValue("flags")),
+		AlwaysPreTouch: r.FormValue("alwaysPreTouch") == "true",
+	}
+	for field, target := range map[string]*int{"port": &opts.Port, "maxPlayers": &opts.MaxPlayers} {
+		raw := strings.TrimSpace(r.FormValue(field))
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, field+" must be a number")
+			return
+		}
+		*target = n
+	}
+	if opts.Port != 0 && (opts.Port < 1024 || opts.Port > 65535) {
+		respondCodedError(w, http.StatusBadRequest, minecraft.MessagePortOutOfRange, "Port must be between 1024 and 65535")
+		return
+	}
+
+	server, err := h.mgr.CreateModpackServer(opts, file)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, server)
+}
+
+// InstallCurseForgeModpack handles POST /api/servers/modpack/curseforge
+func (h *ServerHandler) InstallCurseForgeModpack(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ProjectID int `json:"projectId"`
+		// FileID picks a version of the pack; zero takes its main file.
+		FileID         int    `json:"fileId"`
+		Name           string `json:"name"`
+		Port           int    `json:"port"`
+		MinRAM         string `json:"minRam"`
+		MaxRAM         string `json:"maxRam"`
+		MaxPlayers     int    `json:"maxPlayers"`
+		Flags          string `json:"flags"`
+		AlwaysPreTouch bool   `json:"alwaysPreTouch"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	if req.ProjectID <= 0 {
+		respondError(w, http.StatusBadRequest, "projectId is required")
+		return
+	}
+	if req.Port != 0 && (req.Port < 1024 || req.Port > 65535) {
+		respondCodedError(w, http.StatusBadRequest, minecraft.MessagePortOutOfRange, "Port must be between 1024 and 65535")
+		return
+	}
+
+	opts := minecraft.ModpackInstallOptions{
+		Name:           strings.TrimSpace(req.Name),
+		Port:           req.Port,
+		MinRAM:         req.MinRAM,
+		MaxRAM:         req.MaxRAM,
+		MaxPlayers:     req.MaxPlayers,
+		Flags:          req.Flags,
+		AlwaysPreTouch: req.AlwaysPreTouch,
+	}
+	server, err := h.mgr.CreateCurseForgeModpackServer(r.Context(), opts, req.ProjectID, req.FileID)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, server)
+}
+
+// ListCorrupt handles GET /api/servers/corrupt
+func (h *ServerHandler) ListCorrupt(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.mgr.ListCorruptServerEntries())
+}
+
+// RecoverCorrupt handles POST /api/servers/corrupt/{key}/recover
+func (h *ServerHandler) RecoverCorrupt(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Config json.RawMessage `json:"config"`
+	}
+	if err := decodeJSONOptional(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	server, err := h.mgr.RecoverCorruptServerEntry(r.PathValue("key"), req.Config)
+	if err != nil {
+		if errors.Is(err, minecraft.ErrCorruptEntryNotFound) {
+			respondErr(w, http.StatusNotFound, err)
+			return
+		}
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, server)
+}
+
+// DiscardCorrupt handles POST /api/servers/corrupt/{key}/discard
+func (h *ServerHandler) DiscardCorrupt(w http.ResponseWriter, r *http.Request) {
+	if err := h.mgr.DiscardCorruptServerEntry(r.PathValue("key")); err != nil {
+		if errors.Is(err, minecraft.ErrCorruptEntryNotFound) {
+			respondErr(w, http.StatusNotFound, err)
+			return
+		}
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "discarded"})
+}
+
 // RetryInstall handles POST /api/servers/{id}/retry-install
 func (h *ServerHandler) RetryInstall(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if err := h.mgr.RetryInstall(id); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 	status, err := h.mgr.GetStatus(id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, http.StatusInternalServerError, err)
 		return
 	}
 	respondJSON(w, http.StatusOK, status)
@@ -435,10 +811,11 @@ func (h *ServerHandler) RetryInstall(w http.ResponseWriter, r *http.Request) {
 func (h *ServerHandler) UpdateVersion(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	var req struct {
-		Version string `json:"version"`
+		Version      string `json:"version"`
+		UpgradeWorld bool   `json:"upgradeWorld"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 	if req.Version == "" {
@@ -446,15 +823,49 @@ func (h *ServerHandler) UpdateVersion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	server, err := h.mgr.UpdateVersion(id, req.Version)
+	server, err := h.mgr.UpdateVersion(id, req.Version, req.UpgradeWorld)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
 	respondJSON(w, http.StatusOK, server)
 }
 
+// UpgradeWorld handles POST /api/servers/{id}/world-upgrade
+func (h *ServerHandler) UpgradeWorld(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req minecraft.WorldUpgradeOptions
+	if err := decodeJSONOptional(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	job, err := h.mgr.StartWorldUpgrade(id, req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// Benchmark handles POST /api/servers/{id}/benchmark
+func (h *ServerHandler) Benchmark(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req minecraft.BenchmarkOptions
+	if err := decodeJSONOptional(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	job, err := h.mgr.StartBenchmark(id, req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusAccepted, job)
+}
+
 // UpdateSettings handles PUT /api/servers/{id}/settings
 func (h *ServerHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
@@ -465,7 +876,7 @@ func (h *ServerHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
 		Port       int    `json:"port"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 	if req.MinRAM == "" || req.MaxRAM == "" || req.MaxPlayers <= 0 || req.Port == 0 {
@@ -475,7 +886,7 @@ func (h *ServerHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
 
 	server, err := h.mgr.UpdateSettings(id, req.MinRAM, req.MaxRAM, req.MaxPlayers, req.Port)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -490,13 +901,89 @@ func (h *ServerHandler) SetFlags(w http.ResponseWriter, r *http.Request) {
 		AlwaysPreTouch bool   `json:"alwaysPreTouch"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 
 	server, err := h.mgr.SetFlags(id, req.Flags, req.AlwaysPreTouch)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, server)
+}
+
+// SetReadyCommands handles PUT /api/servers/{id}/ready-commands
+func (h *ServerHandler) SetReadyCommands(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req struct {
+		Commands []string `json:"commands"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	server, err := h.mgr.SetReadyCommands(id, req.Commands)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, server)
+}
+
+// SetGroups handles PUT /api/servers/{id}/groups
+func (h *ServerHandler) SetGroups(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req struct {
+		Groups []string `json:"groups"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	server, err := h.mgr.SetServerGroups(id, req.Groups)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, server)
+}
+
+// SetWarningMessages handles PUT /api/servers/{id}/warning-messages
+func (h *ServerHandler) SetWarningMessages(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req minecraft.WarningMessages
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	server, err := h.mgr.SetWarningMessages(id, req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, server)
+}
+
+// SetRCON handles PUT /api/servers/{id}/rcon
+func (h *ServerHandler) SetRCON(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req minecraft.RCONConfig
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	server, err := h.mgr.SetRCONConfig(id, req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -510,13 +997,49 @@ func (h *ServerHandler) SetAutoStart(w http.ResponseWriter, r *http.Request) {
 		AutoStart bool `json:"autoStart"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 
 	server, err := h.mgr.SetAutoStart(id, req.AutoStart)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, server)
+}
+
+// SetCrashRestart handles PUT /api/servers/{id}/restart-on-crash
+func (h *ServerHandler) SetCrashRestart(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req minecraft.CrashRestartSettings
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	server, err := h.mgr.SetCrashRestart(id, req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, server)
+}
+
+// SetConsoleBuffer handles PUT /api/servers/{id}/console-buffer
+func (h *ServerHandler) SetConsoleBuffer(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req minecraft.ConsoleBufferSettings
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	server, err := h.mgr.SetConsoleBuffer(id, req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -530,13 +1053,13 @@ func (h *ServerHandler) Rename(w http.ResponseWriter, r *http.Request) {
 		Name string `json:"name"`
 	}
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
 
 	server, err := h.mgr.RenameServer(id, req.Name)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -547,14 +1070,15 @@ func (h *ServerHandler) Rename(w http.ResponseWriter, r *http.Request) {
 func (h *ServerHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
-		respondError(w, http.StatusBadRequest, "Server ID is required")
+		respondCodedError(w, http.StatusBadRequest, codeServerIDRequired, "Server ID is required")
 		return
 	}
 
-	if err := h.mgr.DeleteServer(id); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	job, err := h.mgr.StartDeleteServer(id)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+	respondJSON(w, http.StatusAccepted, job)
 }
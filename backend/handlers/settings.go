@@ -1,80 +1,128 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"minecraft-admin/minecraft"
 )
 
 type SettingsHandler struct {
-	mgr *minecraft.Manager
+	mgr            *minecraft.Manager
+	trustedProxies *trustedProxySet
 }
 
 func NewSettingsHandler(mgr *minecraft.Manager) *SettingsHandler {
-	return &SettingsHandler{mgr: mgr}
+	return &SettingsHandler{mgr: mgr, trustedProxies: newTrustedProxySetFromEnv()}
+}
+
+// settingsResponse is the settings payload shared by Get, Update and
+// Validate.
+func settingsResponse(settings minecraft.AppSettings) map[string]any {
+	return map[string]any{
+		"userAgent":             settings.UserAgent,
+		"defaultMinRam":         settings.DefaultMinRAM,
+		"defaultMaxRam":         settings.DefaultMaxRAM,
+		"defaultFlags":          settings.DefaultFlags,
+		"statusPollInterval":    settings.StatusPollInterval,
+		"tpsPollInterval":       settings.TpsPollInterval,
+		"playerSyncInterval":    settings.PlayerSyncInterval,
+		"pingPollInterval":      settings.PingPollInterval,
+		"defaultPortRangeStart": settings.DefaultPortRangeStart,
+		"defaultPortRangeEnd":   settings.DefaultPortRangeEnd,
+		"defaultMaxPlayers":     settings.DefaultMaxPlayers,
+		"defaultBackupSchedule": settings.DefaultBackupSchedule,
+		"sessionTtlHours":       settings.SessionTTLHours,
+		"loginMaxFailures":      settings.LoginMaxFailures,
+		"loginWindowMinutes":    settings.LoginWindowMinutes,
+		"loginLockoutMinutes":   settings.LoginLockoutMinutes,
+		"sessionBindIp":         settings.SessionBindIP,
+		"sessionBindUserAgent":  settings.SessionBindUserAgent,
+		"cspReportOnly":         settings.CSPReportOnly,
+		"accessAllowList":       settings.AccessAllowList,
+		"accessDenyList":        settings.AccessDenyList,
+		"restartWarningMinutes": settings.RestartWarningMinutes,
+		"curseForgeApiKeySet":   settings.CurseForgeAPIKeySet,
+		"locale":                settings.Locale,
+		"supportedLocales":      minecraft.SupportedLocales(),
+		"loginUser":             settings.LoginUser,
+		"passwordMinLength":     minecraft.LoginPasswordMinLength,
+		"maxUploadBytes":        uploadMaxBytesFromEnv(),
+	}
 }
 
 func (h *SettingsHandler) Get(w http.ResponseWriter, _ *http.Request) {
-	settings := h.mgr.GetSettings()
-	respondJSON(w, http.StatusOK, map[string]any{
-		"userAgent":          settings.UserAgent,
-		"defaultMinRam":      settings.DefaultMinRAM,
-		"defaultMaxRam":      settings.DefaultMaxRAM,
-		"defaultFlags":       settings.DefaultFlags,
-		"statusPollInterval": settings.StatusPollInterval,
-		"tpsPollInterval":    settings.TpsPollInterval,
-		"playerSyncInterval": settings.PlayerSyncInterval,
-		"pingPollInterval":   settings.PingPollInterval,
-		"loginUser":          settings.LoginUser,
-		"passwordMinLength":  minecraft.LoginPasswordMinLength,
-		"maxUploadBytes":     uploadMaxBytesFromEnv(),
-	})
+	respondJSON(w, http.StatusOK, settingsResponse(h.mgr.GetSettings()))
 }
 
+// Update handles PUT /api/settings. The response carries the saved settings
+// plus a "fields" list saying which values were applied, clamped or
+// rejected.
 func (h *SettingsHandler) Update(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		UserAgent          string `json:"userAgent"`
-		DefaultMinRAM      string `json:"defaultMinRam"`
-		DefaultMaxRAM      string `json:"defaultMaxRam"`
-		DefaultFlags       string `json:"defaultFlags"`
-		StatusPollInterval int    `json:"statusPollInterval"`
-		TpsPollInterval    int    `json:"tpsPollInterval"`
-		PlayerSyncInterval int    `json:"playerSyncInterval"`
-		PingPollInterval   int    `json:"pingPollInterval"`
-		LoginUser          string `json:"loginUser"`
-		LoginPassword      string `json:"loginPassword"`
-	}
+	var req minecraft.AppSettingsUpdate
 	if err := decodeJSON(r, &req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	req.RequesterIP = requestClientIP(r, h.trustedProxies)
+	req.RequesterDirect = directRequest(r)
+	settings, fields, err := h.mgr.UpdateAppSettings(req)
+	respondSettingsUpdate(w, settings, fields, err)
+}
+
+// Patch handles PATCH /api/settings. Only the fields present in the body
+// are changed.
+func (h *SettingsHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	var patch minecraft.AppSettingsPatch
+	if err := decodeJSON(r, &patch); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
-	settings, err := h.mgr.UpdateAppSettings(
-		req.UserAgent,
-		req.DefaultMinRAM,
-		req.DefaultMaxRAM,
-		req.DefaultFlags,
-		req.StatusPollInterval,
-		req.TpsPollInterval,
-		req.PlayerSyncInterval,
-		req.PingPollInterval,
-		req.LoginUser,
-		req.LoginPassword,
-	)
+	patch.RequesterIP = requestClientIP(r, h.trustedProxies)
+	patch.RequesterDirect = directRequest(r)
+	settings, fields, err := h.mgr.PatchAppSettings(patch)
+	respondSettingsUpdate(w, settings, fields, err)
+}
+
+func respondSettingsUpdate(w http.ResponseWriter, settings minecraft.AppSettings, fields []minecraft.SettingsFieldResult, err error) {
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		var rejected *minecraft.SettingsRejectedError
+		if errors.As(err, &rejected) {
+			respondJSON(w, http.StatusBadRequest, map[string]any{
+				"error":  err.Error(),
+				"code":   "settings_rejected",
+				"fields": fields,
+			})
+			return
+		}
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	resp := settingsResponse(settings)
+	resp["fields"] = fields
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// Validate handles POST /api/settings/validate: a dry run of Update that
+// saves nothing.
+func (h *SettingsHandler) Validate(w http.ResponseWriter, r *http.Request) {
+	var req minecraft.AppSettingsUpdate
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
 		return
 	}
-	respondJSON(w, http.StatusOK, map[string]any{
-		"userAgent":          settings.UserAgent,
-		"defaultMinRam":      settings.DefaultMinRAM,
-		"defaultMaxRam":      settings.DefaultMaxRAM,
-		"defaultFlags":       settings.DefaultFlags,
-		"statusPollInterval": settings.StatusPollInterval,
-		"tpsPollInterval":    settings.TpsPollInterval,
-		"playerSyncInterval": settings.PlayerSyncInterval,
-		"pingPollInterval":   settings.PingPollInterval,
-		"loginUser":          settings.LoginUser,
-		"passwordMinLength":  minecraft.LoginPasswordMinLength,
-		"maxUploadBytes":     uploadMaxBytesFromEnv(),
-	})
+	req.RequesterIP = requestClientIP(r, h.trustedProxies)
+	req.RequesterDirect = directRequest(r)
+	settings, fields := h.mgr.ValidateAppSettings(req)
+	valid := true
+	for _, field := range fields {
+		if field.Status == minecraft.SettingRejected {
+			valid = false
+			break
+		}
+	}
+	resp := settingsResponse(settings)
+	resp["fields"] = fields
+	resp["valid"] = valid
+	respondJSON(w, http.StatusOK, resp)
 }
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"minecraft-admin/minecraft"
+)
+
+func TestSettingsReportCurseForgeKeyWithoutReturningIt(t *testing.T) {
+	mgr, err := minecraft.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+	t.Setenv("ADPANEL_CURSEFORGE_API_KEY", "")
+	handler := NewSettingsHandler(mgr)
+
+	const key = "cf-secret-key"
+	patch := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/api/settings", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.Patch(rec, req)
+		return rec
+	}
+	defer patch(`{"curseForgeApiKey":""}`)
+
+	saved := patch(`{"curseForgeApiKey":"` + key + `"}`)
+	getRec := httptest.NewRecorder()
+	handler.Get(getRec, httptest.NewRequest(http.MethodGet, "/api/settings", nil))
+	for name, rec := range map[string]*httptest.ResponseRecorder{"PATCH": saved, "GET": getRec} {
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected %s 200, got %d: %s", name, rec.Code, rec.Body.String())
+		}
+		if strings.Contains(rec.Body.String(), key) {
+			t.Fatalf("expected %s to never return the key, got %s", name, rec.Body.String())
+		}
+		var body map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to decode %s response: %v", name, err)
+		}
+		if set, ok := body["curseForgeApiKeySet"].(bool); !ok || !set {
+			t.Fatalf("expected %s to report a saved key, got %v", name, body["curseForgeApiKeySet"])
+		}
+	}
+}
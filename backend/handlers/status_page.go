@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"time"
+)
+
+const statusPagePath = "/status"
+
+// statusPagePolicy lets the status page be framed by community sites. It
+// loads nothing and runs no script; its styles are inline.
+const statusPagePolicy = "default-src 'none'; style-src 'unsafe-inline'; base-uri 'none'; form-action 'none'; frame-ancestors *"
+
+var statusPageTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"uptime": formatUptime,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta http-equiv="refresh" content="60">
+<title>Server Status</title>
+<style>
+body{margin:0;padding:24px;background:#1a1a1a;color:#e5e5e5;font:14px/1.5 system-ui,sans-serif}
+main{max-width:720px;margin:0 auto}
+h1{font-size:20px;margin:0 0 16px}
+section{background:#202020;border:1px solid #333;border-radius:8px;padding:16px;margin-bottom:12px}
+h2{font-size:16px;margin:0;display:flex;justify-content:space-between;gap:8px}
+.state{font-size:12px;font-weight:600;padding:2px 8px;border-radius:999px;background:#3a3a3a;color:#aaa}
+.online{background:#14532d;color:#86efac}
+.motd{color:#aaa;margin:4px 0 8px}
+dl{display:grid;grid-template-columns:auto 1fr;gap:2px 12px;margin:0}
+dt{color:#888}dd{margin:0}
+footer{color:#666;font-size:12px;text-align:center}
+</style>
+</head>
+<body>
+<main>
+<h1>Server Status</h1>
+{{range .Servers}}<section>
+<h2>{{.Name}} {{if .Online}}<span class="state online">Online</span>{{else}}<span class="state">{{.Status}}</span>{{end}}</h2>
+{{if .Motd}}<p class="motd">{{.Motd}}</p>{{end}}
+<dl>
+{{if .Version}}<dt>Version</dt><dd>{{.Version}}</dd>{{end}}
+<dt>Players</dt><dd>{{len .Players}} / {{.MaxPlayers}}{{if .Players}}: {{range $i, $p := .Players}}{{if $i}}, {{end}}{{$p}}{{end}}{{end}}</dd>
+{{if .Online}}<dt>Uptime</dt><dd>{{uptime .UptimeSeconds}}</dd>{{end}}
+</dl>
+</section>
+{{end}}<footer>Updated {{.Updated}}</footer>
+</main>
+</body>
+</html>
+`))
+
+// StatusPage handles GET /status, a read-only page listing the servers set
+// to show on it. It needs no session and answers 404 while no server is
+// shown, so the page only exists once an admin opts a server in.
+func (h *IntegrationHandler) StatusPage(w http.ResponseWriter, r *http.Request) {
+	servers := h.mgr.StatusPageServers()
+	if len(servers) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	var buf bytes.Buffer
+	err := statusPageTemplate.Execute(&buf, map[string]any{
+		"Servers": servers,
+		"Updated": time.Now().UTC().Format("2006-01-02 15:04 UTC"),
+	})
+	if err != nil {
+		log.Printf("Failed to render status page: %v", err)
+		http.Error(w, "Failed to render status page", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// formatUptime renders seconds as the two largest of days, hours and
+// minutes, e.g. "3d 4h" or "12m".
+func formatUptime(seconds int64) string {
+	d := time.Duration(seconds) * time.Second
+	days := int(d / (24 * time.Hour))
+	hours := int(d/time.Hour) % 24
+	minutes := int(d/time.Minute) % 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// SetShowOnStatusPage handles PUT /api/servers/{id}/status-page
+func (h *ServerHandler) SetShowOnStatusPage(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+
+	server, err := h.mgr.SetShowOnStatusPage(r.PathValue("id"), req.Enabled)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, server)
+}
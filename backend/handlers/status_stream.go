@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"minecraft-admin/minecraft"
+
+	"github.com/gorilla/websocket"
+)
+
+// statusMessage is sent on the status stream. A snapshot carries the full
+// state of the followed servers; an update carries only what changed.
+type statusMessage struct {
+	Type        string                       `json:"type"`
+	Message     string                       `json:"message,omitempty"`
+	Servers     []minecraft.ServerStatus     `json:"servers,omitempty"`
+	Removed     []string                     `json:"removed,omitempty"`
+	Order       []string                     `json:"order,omitempty"`
+	Transitions []minecraft.StatusTransition `json:"transitions,omitempty"`
+}
+
+// statusClientMessage changes which servers the status stream follows.
+type statusClientMessage struct {
+	Type      string   `json:"type"`
+	ServerIDs []string `json:"serverIds"`
+}
+
+// parseStatusFilter cleans a list of server IDs; empty means all servers.
+func parseStatusFilter(ids []string) []string {
+	filter := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id = strings.TrimSpace(id); id != "" {
+			filter = append(filter, id)
+		}
+	}
+	return filter
+}
+
+// WebSocketStatus returns an HTTP handler that pushes server status,
+// metrics, player counts and status transitions as they change. The
+// optional servers query parameter, a comma-separated list of IDs, limits
+// the stream; clients can change it later with a subscribe message.
+func (h *MinecraftHandler) WebSocketStatus() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := h.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Printf("WebSocket upgrade failed for status stream: %v", err)
+			return
+		}
+		defer conn.Close()
+		defer trackWebSocket()()
+
+		filters := make(chan []string, 1)
+		filters <- parseStatusFilter(strings.Split(r.URL.Query().Get("servers"), ","))
+		done := make(chan struct{})
+
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		})
+
+		// Read goroutine: handles filter changes and notices disconnects.
+		go func() {
+			defer close(done)
+			for {
+				_, raw, err := conn.ReadMessage()
+				if err != nil {
+					if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+						log.Printf("WebSocket read error for status stream: %v", err)
+					}
+					return
+				}
+				conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+				var msg statusClientMessage
+				if err := json.Unmarshal(raw, &msg); err != nil || msg.Type != "subscribe" {
+					continue
+				}
+				// Only the newest filter matters.
+				select {
+				case <-filters:
+				default:
+				}
+				filters <- parseStatusFilter(msg.ServerIDs)
+			}
+		}()
+
+		ping := time.NewTicker(wsPingPeriod)
+		defer ping.Stop()
+
+		var updates <-chan minecraft.StatusUpdate
+		unsubscribe := func() {}
+		defer func() { unsubscribe() }()
+
+		write := func(msg statusMessage) bool {
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteJSON(msg); err != nil {
+				log.Printf("WebSocket write error for status stream: %v", err)
+				return false
+			}
+			return true
+		}
+
+		// Write loop: the only writer on conn.
+		for {
+			select {
+			case filter := <-filters:
+				unsubscribe()
+				var snapshot []minecraft.ServerStatus
+				snapshot, updates, unsubscribe = h.mgr.SubscribeStatus(filter)
+				if !write(statusMessage{Type: "snapshot", Servers: snapshot}) {
+					return
+				}
+			case update, ok := <-updates:
+				if !ok {
+					// Fell behind: the client reconnects for a fresh snapshot.
+					conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "stream ending"), time.Now().Add(wsWriteWait))
+					return
+				}
+				if !write(statusMessage{
+					Type:        "update",
+					Servers:     update.Servers,
+					Removed:     update.Removed,
+					Order:       update.Order,
+					Transitions: update.Transitions,
+				}) {
+					return
+				}
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	})
+}
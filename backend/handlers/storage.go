@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"net/http"
+
+	"minecraft-admin/minecraft"
+)
+
+type StorageHandler struct {
+	mgr *minecraft.Manager
+}
+
+func NewStorageHandler(mgr *minecraft.Manager) *StorageHandler {
+	return &StorageHandler{mgr: mgr}
+}
+
+func (h *StorageHandler) Get(w http.ResponseWriter, _ *http.Request) {
+	respondJSON(w, http.StatusOK, h.mgr.GetPersistenceStatus())
+}
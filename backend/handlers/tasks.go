@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"minecraft-admin/minecraft"
+)
+
+// ListTasks handles GET /api/servers/{id}/tasks
+func (h *ServerHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
+	tasks, err := h.mgr.ListTasks(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, tasks)
+}
+
+// CreateTask handles POST /api/servers/{id}/tasks
+func (h *ServerHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
+	h.saveTask(w, r, "")
+}
+
+// UpdateTask handles PUT /api/servers/{id}/tasks/{taskId}
+func (h *ServerHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
+	h.saveTask(w, r, r.PathValue("taskId"))
+}
+
+func (h *ServerHandler) saveTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	var req minecraft.ScheduledTask
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	task, err := h.mgr.SaveTask(r.PathValue("id"), taskID, req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, task)
+}
+
+// DeleteTask handles DELETE /api/servers/{id}/tasks/{taskId}
+func (h *ServerHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
+	if err := h.mgr.DeleteTask(r.PathValue("id"), r.PathValue("taskId")); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// RunTask handles POST /api/servers/{id}/tasks/{taskId}/run
+func (h *ServerHandler) RunTask(w http.ResponseWriter, r *http.Request) {
+	task, err := h.mgr.RunTask(r.PathValue("id"), r.PathValue("taskId"))
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, task)
+}
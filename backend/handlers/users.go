@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"minecraft-admin/minecraft"
+)
+
+// ListUsers handles GET /api/users
+func (h *AuthHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.mgr.ListUsers())
+}
+
+// CreateUser handles POST /api/users
+func (h *AuthHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req minecraft.UserInput
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	user, err := h.mgr.CreateUser(req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	h.logAuthEvent("user created: user=" + user.Username + " role=" + user.Role)
+	respondJSON(w, http.StatusCreated, user)
+}
+
+// UpdateUser handles PUT /api/users/{username}
+func (h *AuthHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+	var req minecraft.UserUpdate
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	user, err := h.mgr.UpdateUser(username, req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	// A new password signs the user out everywhere; a new role applies to
+	// their open sessions right away.
+	if req.Password != nil {
+		h.endUserSessions(username)
+	} else {
+		h.setUserSessionRole(username, user.Role)
+	}
+	h.logAuthEvent("user updated: user=" + user.Username + " role=" + user.Role)
+	respondJSON(w, http.StatusOK, user)
+}
+
+// DeleteUser handles DELETE /api/users/{username}
+func (h *AuthHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+	if current, ok := h.usernameFromRequest(r); ok && strings.EqualFold(current, username) {
+		respondError(w, http.StatusBadRequest, "You cannot delete your own account")
+		return
+	}
+	if err := h.mgr.DeleteUser(username); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	h.endUserSessions(username)
+	h.logAuthEvent("user deleted: user=" + username)
+	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func (h *AuthHandler) endUserSessions(username string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for token, rec := range h.sessions {
+		if rec.Username == username {
+			delete(h.sessions, token)
+		}
+	}
+}
+
+func (h *AuthHandler) setUserSessionRole(username, role string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for token, rec := range h.sessions {
+		if rec.Username == username {
+			rec.Role = role
+			h.sessions[token] = rec
+		}
+	}
+}
@@ -20,13 +20,13 @@ func NewVersionHandler(mgr *minecraft.Manager) *VersionHandler {
 func (h *VersionHandler) List(w http.ResponseWriter, r *http.Request) {
 	serverType := r.PathValue("type")
 	if serverType == "" {
-		respondError(w, http.StatusBadRequest, "Server type is required")
+		respondCodedError(w, http.StatusBadRequest, codeServerTypeRequired, "Server type is required")
 		return
 	}
 
 	versions, err := h.mgr.GetVersions(serverType)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondErr(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"minecraft-admin/minecraft"
+)
+
+const (
+	webhookPathPrefix  = "/api/hooks/"
+	maxWebhookBodySize = 64 << 10
+)
+
+// ListWebhooks handles GET /api/servers/{id}/webhooks
+func (h *ServerHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := h.mgr.ListWebhooks(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, hooks)
+}
+
+// CreateWebhook handles POST /api/servers/{id}/webhooks
+func (h *ServerHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	h.saveWebhook(w, r, "")
+}
+
+// UpdateWebhook handles PUT /api/servers/{id}/webhooks/{hookId}
+func (h *ServerHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	h.saveWebhook(w, r, r.PathValue("hookId"))
+}
+
+func (h *ServerHandler) saveWebhook(w http.ResponseWriter, r *http.Request, hookID string) {
+	var req struct {
+		Name     string   `json:"name"`
+		Commands []string `json:"commands"`
+		Enabled  bool     `json:"enabled"`
+		// Secret sets the shared secret; leave it empty to have one
+		// generated for a new webhook or to keep an existing one.
+		Secret string `json:"secret"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	hook, secret, err := h.mgr.SaveWebhook(r.PathValue("id"), hookID, minecraft.InboundWebhook{
+		Name:     req.Name,
+		Commands: req.Commands,
+		Enabled:  req.Enabled,
+	}, req.Secret)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, minecraft.ErrWebhookNotFound) {
+			status = http.StatusNotFound
+		}
+		respondErr(w, status, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"webhook": hook,
+		"secret":  secret,
+		"url":     fmt.Sprintf("%s%s/%s", webhookPathPrefix, r.PathValue("id"), hook.ID),
+	})
+}
+
+// DeleteWebhook handles DELETE /api/servers/{id}/webhooks/{hookId}
+func (h *ServerHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := h.mgr.DeleteWebhook(r.PathValue("id"), r.PathValue("hookId")); err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// ReceiveWebhook handles POST /api/hooks/{id}/{hookId}, called by outside
+// services without a session. The shared secret comes as a bearer token,
+// an X-Webhook-Secret header or ?secret=. Values for the command templates
+// come from the JSON or form body and the query string.
+func (h *IntegrationHandler) ReceiveWebhook(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxWebhookBodySize)
+	values, err := webhookValues(r)
+	if err != nil {
+		if isRequestBodyTooLarge(err) {
+			respondError(w, http.StatusRequestEntityTooLarge, "webhook body is too large")
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Invalid webhook body")
+		return
+	}
+
+	secret, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		secret = r.Header.Get("X-Webhook-Secret")
+	}
+	if secret == "" {
+		secret = r.URL.Query().Get("secret")
+	}
+
+	ran, err := h.mgr.ReceiveWebhook(r.PathValue("id"), r.PathValue("hookId"), secret, values)
+	switch {
+	case errors.Is(err, minecraft.ErrWebhookNotFound), errors.Is(err, minecraft.ErrWebhookUnauthorized):
+		// Unknown hooks and wrong secrets look the same from outside.
+		respondError(w, http.StatusUnauthorized, "Unknown webhook or wrong secret")
+	case errors.Is(err, minecraft.ErrWebhookServerOffline):
+		respondErr(w, http.StatusConflict, err)
+	case err != nil:
+		respondErr(w, http.StatusBadRequest, err)
+	default:
+		respondJSON(w, http.StatusOK, map[string]any{"status": "ok", "commands": len(ran)})
+	}
+}
+
+// webhookValues collects the scalar fields of a JSON object or form body,
+// then the query string, leaving out the secret.
+func webhookValues(r *http.Request) (map[string]string, error) {
+	values := make(map[string]string)
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch contentType {
+	case "application/json":
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		for key, value := range body {
+			switch v := value.(type) {
+			case string:
+				values[key] = v
+			case float64, bool:
+				values[key] = fmt.Sprint(v)
+			}
+		}
+	case "application/x-www-form-urlencoded":
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		form, err := url.ParseQuery(string(data))
+		if err != nil {
+			return nil, err
+		}
+		for key := range form {
+			values[key] = form.Get(key)
+		}
+	}
+	for key := range r.URL.Query() {
+		if key != "secret" {
+			if _, ok := values[key]; !ok {
+				values[key] = r.URL.Query().Get(key)
+			}
+		}
+	}
+	return values, nil
+}
+
+// isWebhookPath reports whether path is an inbound webhook, which checks
+// its own secret instead of a session.
+func isWebhookPath(path string) bool {
+	return strings.HasPrefix(path, webhookPathPrefix)
+}
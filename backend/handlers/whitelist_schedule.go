@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"minecraft-admin/minecraft"
+)
+
+// GetWhitelistSchedule handles GET /api/servers/{id}/whitelist-schedule
+func (h *PlayerHandler) GetWhitelistSchedule(w http.ResponseWriter, r *http.Request) {
+	info, err := h.mgr.GetWhitelistSchedule(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, info)
+}
+
+// SetWhitelistSchedule handles PUT /api/servers/{id}/whitelist-schedule
+func (h *PlayerHandler) SetWhitelistSchedule(w http.ResponseWriter, r *http.Request) {
+	var req minecraft.WhitelistSchedule
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	info, err := h.mgr.SetWhitelistSchedule(r.PathValue("id"), req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, info)
+}
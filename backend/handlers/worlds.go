@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ListWorlds handles GET /api/servers/{id}/worlds
+func (h *ServerHandler) ListWorlds(w http.ResponseWriter, r *http.Request) {
+	worlds, err := h.mgr.ListWorlds(r.PathValue("id"))
+	if err != nil {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, worlds)
+}
+
+// UploadWorld handles POST /api/servers/{id}/worlds (multipart form with a
+// world .zip and an optional folder name)
+func (h *ServerHandler) UploadWorld(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.importMaxBytes)
+	if err := r.ParseMultipartForm(8 << 20); err != nil {
+		if isRequestBodyTooLarge(err) {
+			respondCodedError(w, http.StatusRequestEntityTooLarge, codeUploadTooLarge, "uploaded file exceeds maximum allowed size")
+			return
+		}
+		respondCodedError(w, http.StatusBadRequest, codeInvalidFormData, "Failed to parse form data")
+		return
+	}
+	if r.MultipartForm != nil {
+		defer r.MultipartForm.RemoveAll()
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeFileRequired, "No file provided")
+		return
+	}
+	defer file.Close()
+	if !checkUploadQuota(w, h.mgr, id, header.Size) {
+		return
+	}
+	baseName := filepath.Base(strings.TrimSpace(header.Filename))
+	if !strings.HasSuffix(strings.ToLower(baseName), ".zip") {
+		respondError(w, http.StatusBadRequest, "unsupported file format, use a .zip world")
+		return
+	}
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		name = strings.TrimSuffix(baseName, filepath.Ext(baseName))
+	}
+
+	tmpFile, err := os.CreateTemp("", "orexa-world-upload-*.zip")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create temporary upload file")
+		return
+	}
+	tmpPath := tmpFile.Name()
+	defer func() {
+		_ = os.Remove(tmpPath)
+	}()
+	if _, err := io.Copy(tmpFile, file); err != nil {
+		_ = tmpFile.Close()
+		respondError(w, http.StatusInternalServerError, "Failed to store uploaded file")
+		return
+	}
+	if err := tmpFile.Close(); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to finalize uploaded file")
+		return
+	}
+
+	world, err := h.mgr.UploadWorld(id, name, tmpPath)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, world)
+}
+
+// SwitchWorld handles PUT /api/servers/{id}/worlds/active
+func (h *ServerHandler) SwitchWorld(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	worlds, err := h.mgr.SwitchWorld(r.PathValue("id"), req.Name)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, worlds)
+}
+
+// ResetWorld handles POST /api/servers/{id}/worlds/{name}/reset
+func (h *ServerHandler) ResetWorld(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Dimensions []string `json:"dimensions"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		respondCodedError(w, http.StatusBadRequest, codeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	result, err := h.mgr.ResetWorldDimensions(r.PathValue("id"), r.PathValue("name"), req.Dimensions)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, result)
+}
+
+// DeleteWorld handles DELETE /api/servers/{id}/worlds/{name}
+func (h *ServerHandler) DeleteWorld(w http.ResponseWriter, r *http.Request) {
+	backup, err := h.mgr.DeleteWorld(r.PathValue("id"), r.PathValue("name"))
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"status": "deleted", "backup": backup})
+}
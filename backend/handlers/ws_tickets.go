@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// wsTicketTTL is how long a minted WebSocket ticket can be redeemed.
+const wsTicketTTL = 30 * time.Second
+
+// wsTicket lets a client that cannot send the session cookie open a
+// WebSocket once. It is tied to the session that minted it, so logging out
+// or an expired session also invalidates the ticket.
+type wsTicket struct {
+	SessionToken string
+	Expires      time.Time
+}
+
+type wsTicketContextKey struct{}
+
+// isWebSocketPath reports whether path is served by a WebSocket upgrade.
+func isWebSocketPath(path string) bool {
+	return path == "/api/console" || path == "/api/ws/status" || strings.HasPrefix(path, "/api/logs/")
+}
+
+// withWebSocketTicket marks r as authenticated by a ticket instead of the
+// session cookie.
+func withWebSocketTicket(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), wsTicketContextKey{}, true))
+}
+
+func hasWebSocketTicket(r *http.Request) bool {
+	ok, _ := r.Context().Value(wsTicketContextKey{}).(bool)
+	return ok
+}
+
+// IssueWebSocketTicket handles POST /api/auth/ws-ticket
+func (h *AuthHandler) IssueWebSocketTicket(w http.ResponseWriter, r *http.Request) {
+	c, err := r.Cookie(sessionCookieName)
+	if err != nil || c == nil || strings.TrimSpace(c.Value) == "" {
+		respondCodedError(w, http.StatusUnauthorized, codeAuthenticationRequired, "Authentication required")
+		return
+	}
+	ticket, err := newSessionToken()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create ticket")
+		return
+	}
+	expires := time.Now().Add(wsTicketTTL)
+
+	h.mu.Lock()
+	now := time.Now()
+	for key, t := range h.wsTickets {
+		if now.After(t.Expires) {
+			delete(h.wsTickets, key)
+		}
+	}
+	h.wsTickets[ticket] = wsTicket{SessionToken: c.Value, Expires: expires}
+	h.mu.Unlock()
+
+	respondJSON(w, http.StatusOK, map[string]any{
+		"ticket":    ticket,
+		"expiresAt": expires.UTC(),
+	})
+}
+
+// redeemWebSocketTicket consumes the ticket query parameter. Each ticket
+// works once, and only while the session that minted it is still valid.
+func (h *AuthHandler) redeemWebSocketTicket(r *http.Request) (sessionRecord, bool) {
+	ticket := strings.TrimSpace(r.URL.Query().Get("ticket"))
+	if ticket == "" {
+		return sessionRecord{}, false
+	}
+	h.mu.Lock()
+	t, ok := h.wsTickets[ticket]
+	delete(h.wsTickets, ticket)
+	h.mu.Unlock()
+	if !ok || time.Now().After(t.Expires) {
+		return sessionRecord{}, false
+	}
+	return h.sessionFromToken(r, t.SessionToken)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -9,11 +10,16 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"syscall"
 	"time"
 
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
 	"minecraft-admin/handlers"
 	"minecraft-admin/minecraft"
 )
@@ -50,7 +56,6 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to initialize manager: %v", err)
 	}
-	defer mgr.StopAll()
 
 	// Create handlers
 	serverHandler := handlers.NewServerHandler(mgr)
@@ -64,7 +69,13 @@ func main() {
 	versionHandler := handlers.NewVersionHandler(mgr)
 	settingsHandler := handlers.NewSettingsHandler(mgr)
 	systemUsageHandler := handlers.NewSystemUsageHandler(mgr)
+	storageHandler := handlers.NewStorageHandler(mgr)
+	jobHandler := handlers.NewJobHandler(mgr)
+	panelConfigHandler := handlers.NewPanelConfigHandler(mgr)
+	groupHandler := handlers.NewGroupHandler(mgr)
+	integrationHandler := handlers.NewIntegrationHandler(mgr)
 	authHandler := handlers.NewAuthHandler(mgr, baseDir)
+	selfMetricsHandler := handlers.NewSelfMetricsHandler(mgr)
 
 	// Set up router using Go 1.22+ ServeMux
 	mux := http.NewServeMux()
@@ -101,21 +112,71 @@ func main() {
 	mux.HandleFunc("POST /api/servers/{id}/start-safe", serverHandler.StartSafeMode)
 	mux.HandleFunc("POST /api/servers/{id}/stop", serverHandler.Stop)
 	mux.HandleFunc("POST /api/servers/{id}/kill", serverHandler.Kill)
+	mux.HandleFunc("POST /api/servers/{id}/suspend", serverHandler.Suspend)
+	mux.HandleFunc("POST /api/servers/{id}/resume", serverHandler.Resume)
+	mux.HandleFunc("GET /api/servers/{id}", serverHandler.Get)
 	mux.HandleFunc("GET /api/servers/{id}/status", serverHandler.Status)
+	mux.HandleFunc("POST /api/servers/{id}/command", serverHandler.SendCommand)
+	mux.HandleFunc("GET /api/servers/{id}/boot-failure", serverHandler.BootFailure)
 	mux.HandleFunc("POST /api/servers/{id}/schedule-restart", serverHandler.ScheduleRestart)
 	mux.HandleFunc("DELETE /api/servers/{id}/schedule-restart", serverHandler.CancelRestart)
 	mux.HandleFunc("POST /api/servers/{id}/schedule-stop", serverHandler.ScheduleStop)
 	mux.HandleFunc("POST /api/servers/{id}/retry-install", serverHandler.RetryInstall)
 	mux.HandleFunc("PUT /api/servers/{id}/version", serverHandler.UpdateVersion)
+	mux.HandleFunc("POST /api/servers/{id}/world-upgrade", serverHandler.UpgradeWorld)
+	mux.HandleFunc("GET /api/servers/{id}/worlds", serverHandler.ListWorlds)
+	mux.HandleFunc("POST /api/servers/{id}/worlds", serverHandler.UploadWorld)
+	mux.HandleFunc("PUT /api/servers/{id}/worlds/active", serverHandler.SwitchWorld)
+	mux.HandleFunc("POST /api/servers/{id}/worlds/{name}/reset", serverHandler.ResetWorld)
+	mux.HandleFunc("DELETE /api/servers/{id}/worlds/{name}", serverHandler.DeleteWorld)
+	mux.HandleFunc("POST /api/servers/{id}/benchmark", serverHandler.Benchmark)
 	mux.HandleFunc("PUT /api/servers/{id}/settings", serverHandler.UpdateSettings)
 	mux.HandleFunc("PUT /api/servers/{id}/auto-start", serverHandler.SetAutoStart)
+	mux.HandleFunc("PUT /api/servers/{id}/status-page", serverHandler.SetShowOnStatusPage)
+	mux.HandleFunc("PUT /api/servers/{id}/restart-on-crash", serverHandler.SetCrashRestart)
+	mux.HandleFunc("PUT /api/servers/{id}/console-buffer", serverHandler.SetConsoleBuffer)
+	mux.HandleFunc("GET /api/servers/{id}/disk-quota", serverHandler.GetDiskQuota)
+	mux.HandleFunc("PUT /api/servers/{id}/disk-quota", serverHandler.SetDiskQuota)
+	mux.HandleFunc("GET /api/networks", serverHandler.ListNetworks)
+	mux.HandleFunc("PUT /api/servers/{id}/network", serverHandler.SetNetwork)
 	mux.HandleFunc("PUT /api/servers/{id}/flags", serverHandler.SetFlags)
+	mux.HandleFunc("PUT /api/servers/{id}/ready-commands", serverHandler.SetReadyCommands)
+	mux.HandleFunc("GET /api/servers/{id}/join-check", serverHandler.GetJoinCheck)
+	mux.HandleFunc("PUT /api/servers/{id}/join-check", serverHandler.SetJoinCheck)
+	mux.HandleFunc("POST /api/servers/{id}/join-check", serverHandler.RunJoinCheck)
+	mux.HandleFunc("GET /api/servers/{id}/tasks", serverHandler.ListTasks)
+	mux.HandleFunc("POST /api/servers/{id}/tasks", serverHandler.CreateTask)
+	mux.HandleFunc("PUT /api/servers/{id}/tasks/{taskId}", serverHandler.UpdateTask)
+	mux.HandleFunc("DELETE /api/servers/{id}/tasks/{taskId}", serverHandler.DeleteTask)
+	mux.HandleFunc("POST /api/servers/{id}/tasks/{taskId}/run", serverHandler.RunTask)
+	mux.HandleFunc("GET /api/servers/{id}/maintenance", serverHandler.GetMaintenance)
+	mux.HandleFunc("PUT /api/servers/{id}/maintenance", serverHandler.SetMaintenance)
+	mux.HandleFunc("POST /api/servers/{id}/maintenance/run", serverHandler.RunMaintenance)
+	mux.HandleFunc("GET /api/servers/{id}/restart-verification", serverHandler.GetRestartVerification)
+	mux.HandleFunc("PUT /api/servers/{id}/restart-verification", serverHandler.SetRestartVerification)
+	mux.HandleFunc("GET /api/servers/{id}/webhooks", serverHandler.ListWebhooks)
+	mux.HandleFunc("POST /api/servers/{id}/webhooks", serverHandler.CreateWebhook)
+	mux.HandleFunc("PUT /api/servers/{id}/webhooks/{hookId}", serverHandler.UpdateWebhook)
+	mux.HandleFunc("DELETE /api/servers/{id}/webhooks/{hookId}", serverHandler.DeleteWebhook)
+	mux.HandleFunc("PUT /api/servers/{id}/groups", serverHandler.SetGroups)
+	mux.HandleFunc("PUT /api/servers/{id}/warning-messages", serverHandler.SetWarningMessages)
+	mux.HandleFunc("PUT /api/servers/{id}/rcon", serverHandler.SetRCON)
 	mux.HandleFunc("PUT /api/servers/{id}/name", serverHandler.Rename)
 	mux.HandleFunc("DELETE /api/servers/{id}", serverHandler.Delete)
 	mux.HandleFunc("POST /api/servers/clone", serverHandler.Clone)
+	mux.HandleFunc("POST /api/servers/{id}/export", serverHandler.Export)
+	mux.HandleFunc("GET /api/servers/{id}/export/{name}", serverHandler.DownloadExport)
+	mux.HandleFunc("POST /api/servers/restore-as-new", serverHandler.RestoreAsNew)
+	mux.HandleFunc("POST /api/servers/import", serverHandler.ImportDirectory)
 	mux.HandleFunc("POST /api/servers/import/analyze", serverHandler.AnalyzeImport)
 	mux.HandleFunc("POST /api/servers/import/commit", serverHandler.CommitImport)
+	mux.HandleFunc("POST /api/servers/import/archive", serverHandler.ImportArchive)
 	mux.HandleFunc("DELETE /api/servers/import/analyze/{id}", serverHandler.CancelImport)
+	mux.HandleFunc("POST /api/servers/modpack", serverHandler.InstallModpack)
+	mux.HandleFunc("POST /api/servers/modpack/curseforge", serverHandler.InstallCurseForgeModpack)
+	mux.HandleFunc("GET /api/servers/corrupt", serverHandler.ListCorrupt)
+	mux.HandleFunc("POST /api/servers/corrupt/{key}/recover", serverHandler.RecoverCorrupt)
+	mux.HandleFunc("POST /api/servers/corrupt/{key}/discard", serverHandler.DiscardCorrupt)
 
 	// Version fetching
 	mux.HandleFunc("GET /api/versions/{type}", versionHandler.List)
@@ -123,12 +184,33 @@ func main() {
 	// System settings
 	mux.HandleFunc("GET /api/settings", settingsHandler.Get)
 	mux.HandleFunc("PUT /api/settings", settingsHandler.Update)
+	mux.HandleFunc("PATCH /api/settings", settingsHandler.Patch)
+	mux.HandleFunc("POST /api/settings/validate", settingsHandler.Validate)
 	mux.HandleFunc("GET /api/system/usage", systemUsageHandler.Get)
+	mux.HandleFunc("GET /api/system/storage", storageHandler.Get)
+	mux.HandleFunc("GET /api/system/self-metrics", selfMetricsHandler.Get)
+	mux.HandleFunc("GET /api/system/config/export", panelConfigHandler.Export)
+	mux.HandleFunc("POST /api/system/config/import", panelConfigHandler.Import)
+
+	// Long-running jobs
+	mux.HandleFunc("GET /api/jobs", jobHandler.List)
+	mux.HandleFunc("GET /api/jobs/{id}", jobHandler.Get)
+	mux.HandleFunc("POST /api/jobs/{id}/cancel", jobHandler.Cancel)
 
 	// Authentication
 	mux.HandleFunc("POST /api/auth/login", authHandler.Login)
 	mux.HandleFunc("POST /api/auth/logout", authHandler.Logout)
 	mux.HandleFunc("GET /api/auth/session", authHandler.Session)
+	mux.HandleFunc("POST /api/auth/ws-ticket", authHandler.IssueWebSocketTicket)
+	mux.HandleFunc("GET /api/preferences", authHandler.GetPreferences)
+	mux.HandleFunc("PUT /api/preferences", authHandler.UpdatePreferences)
+	mux.HandleFunc("GET /api/security/login-blocks", authHandler.ListLoginBlocks)
+	mux.HandleFunc("DELETE /api/security/login-blocks", authHandler.ClearLoginBlocks)
+	mux.HandleFunc("DELETE /api/security/login-blocks/{ip}", authHandler.ClearLoginBlock)
+	mux.HandleFunc("GET /api/users", authHandler.ListUsers)
+	mux.HandleFunc("POST /api/users", authHandler.CreateUser)
+	mux.HandleFunc("PUT /api/users/{username}", authHandler.UpdateUser)
+	mux.HandleFunc("DELETE /api/users/{username}", authHandler.DeleteUser)
 
 	// Crash reports
 	mux.HandleFunc("GET /api/servers/{id}/crash-reports", crashHandler.List)
@@ -138,19 +220,52 @@ func main() {
 
 	// WebSocket route for console logs (live streaming)
 	mux.Handle("GET /api/logs/{id}", mcHandler.WebSocketLogs())
+	mux.HandleFunc("GET /api/logs/{id}/export", logHandler.Export)
+	// One socket following several consoles
+	mux.Handle("GET /api/console", mcHandler.WebSocketConsole())
+	// WebSocket route for live server status, replacing list polling
+	mux.Handle("GET /api/ws/status", mcHandler.WebSocketStatus())
 
 	// HTTP routes to list/read saved log files when server is offline
 	mux.HandleFunc("GET /api/servers/{id}/logs", logHandler.List)
+	mux.HandleFunc("GET /api/servers/{id}/logs/search", logHandler.Search)
 	mux.HandleFunc("GET /api/servers/{id}/logs/{name}", logHandler.Read)
 
+	// Server groups
+	mux.HandleFunc("GET /api/groups", groupHandler.List)
+	mux.HandleFunc("GET /api/groups/{name}/summary", groupHandler.Summary)
+
+	// Home automation integrations
+	mux.HandleFunc("GET /api/integrations/ha", integrationHandler.HomeAssistantList)
+	mux.HandleFunc("GET /api/integrations/ha/{id}", integrationHandler.HomeAssistantServer)
+	mux.HandleFunc("GET /api/public-status", integrationHandler.PublicStatus)
+	mux.HandleFunc("GET /status", integrationHandler.StatusPage)
+	mux.HandleFunc("POST /api/hooks/{id}/{hookId}", integrationHandler.ReceiveWebhook)
+
 	// Plugin management
+	mux.HandleFunc("GET /api/plugins/updates", pluginHandler.UpdatesOverview)
+	mux.HandleFunc("GET /api/plugins/search", pluginHandler.Search)
 	mux.HandleFunc("GET /api/servers/{id}/plugins", pluginHandler.List)
 	mux.HandleFunc("POST /api/servers/{id}/plugins", pluginHandler.Upload)
+	mux.HandleFunc("POST /api/servers/{id}/plugins/from-url", pluginHandler.InstallFromURL)
+	mux.HandleFunc("POST /api/servers/{id}/plugins/install", pluginHandler.InstallFromCatalog)
 	mux.HandleFunc("DELETE /api/servers/{id}/plugins/{name}", pluginHandler.Delete)
 	mux.HandleFunc("PUT /api/servers/{id}/plugins/{name}/toggle", pluginHandler.Toggle)
 	mux.HandleFunc("PUT /api/servers/{id}/plugins/{name}/source", pluginHandler.SetSource)
+	mux.HandleFunc("PUT /api/servers/{id}/plugins/{name}/note", pluginHandler.SetNote)
+	mux.HandleFunc("GET /api/servers/{id}/plugins/{name}/config", pluginHandler.ListConfigs)
+	mux.HandleFunc("GET /api/servers/{id}/plugins/{name}/config/content", pluginHandler.ReadConfig)
+	mux.HandleFunc("PUT /api/servers/{id}/plugins/{name}/config/content", pluginHandler.WriteConfig)
 	mux.HandleFunc("GET /api/servers/{id}/plugins/check-updates", pluginHandler.CheckUpdates)
+	mux.HandleFunc("PUT /api/servers/{id}/plugins/update-channel", pluginHandler.SetUpdateChannel)
 	mux.HandleFunc("POST /api/servers/{id}/plugins/{name}/update", pluginHandler.Update)
+	mux.HandleFunc("GET /api/servers/{id}/plugins/quarantine", pluginHandler.ListQuarantine)
+	mux.HandleFunc("POST /api/servers/{id}/plugins/quarantine/{qid}/approve", pluginHandler.ApproveQuarantine)
+	mux.HandleFunc("DELETE /api/servers/{id}/plugins/quarantine/{qid}", pluginHandler.DiscardQuarantine)
+	mux.HandleFunc("GET /api/servers/{id}/datapacks", pluginHandler.ListDatapacks)
+	mux.HandleFunc("POST /api/servers/{id}/datapacks", pluginHandler.UploadDatapack)
+	mux.HandleFunc("PUT /api/servers/{id}/datapacks/{name}/toggle", pluginHandler.ToggleDatapack)
+	mux.HandleFunc("DELETE /api/servers/{id}/datapacks/{name}", pluginHandler.DeleteDatapack)
 
 	// Backup management
 	mux.HandleFunc("GET /api/servers/{id}/backups", backupHandler.List)
@@ -158,8 +273,19 @@ func main() {
 	mux.HandleFunc("DELETE /api/servers/{id}/backups/{name}", backupHandler.Delete)
 	mux.HandleFunc("GET /api/servers/{id}/backups/{name}/download", backupHandler.Download)
 	mux.HandleFunc("POST /api/servers/{id}/backups/{name}/restore", backupHandler.Restore)
+	mux.HandleFunc("POST /api/servers/{id}/backups/{name}/upload", backupHandler.Upload)
+	mux.HandleFunc("GET /api/servers/{id}/backup-jobs/{jobId}", backupHandler.Job)
 	mux.HandleFunc("GET /api/servers/{id}/backup-schedule", backupHandler.GetSchedule)
 	mux.HandleFunc("PUT /api/servers/{id}/backup-schedule", backupHandler.SetSchedule)
+	mux.HandleFunc("GET /api/servers/{id}/backup-targets", backupHandler.GetServerTargets)
+	mux.HandleFunc("PUT /api/servers/{id}/backup-targets", backupHandler.SetServerTargets)
+	mux.HandleFunc("GET /api/backup-targets", backupHandler.ListTargets)
+	mux.HandleFunc("PUT /api/backup-targets", backupHandler.SetTargets)
+	mux.HandleFunc("POST /api/backup-targets/{targetId}/test", backupHandler.TestTarget)
+	mux.HandleFunc("GET /api/servers/{id}/region-prune", backupHandler.GetRegionPrune)
+	mux.HandleFunc("PUT /api/servers/{id}/region-prune", backupHandler.SetRegionPrune)
+	mux.HandleFunc("POST /api/servers/{id}/region-prune/preview", backupHandler.PreviewRegionPrune)
+	mux.HandleFunc("POST /api/servers/{id}/region-prune", backupHandler.PruneRegions)
 
 	// File browser
 	mux.HandleFunc("GET /api/servers/{id}/files", fileHandler.List)
@@ -167,6 +293,7 @@ func main() {
 	mux.HandleFunc("GET /api/servers/{id}/files/content", fileHandler.ReadContent)
 	mux.HandleFunc("PUT /api/servers/{id}/files/content", fileHandler.WriteContent)
 	mux.HandleFunc("POST /api/servers/{id}/files/upload", fileHandler.Upload)
+	mux.HandleFunc("GET /api/servers/{id}/files/delete-preview", fileHandler.DeletePreview)
 	mux.HandleFunc("DELETE /api/servers/{id}/files", fileHandler.Delete)
 	mux.HandleFunc("POST /api/servers/{id}/files/mkdir", fileHandler.MkDir)
 	mux.HandleFunc("PUT /api/servers/{id}/files/rename", fileHandler.Rename)
@@ -174,28 +301,114 @@ func main() {
 
 	// Player management
 	mux.HandleFunc("GET /api/servers/{id}/players", playerHandler.List)
+	mux.HandleFunc("GET /api/servers/{id}/players/history", playerHandler.History)
+	mux.HandleFunc("GET /api/servers/{id}/players/{name}/inspect", playerHandler.Inspect)
+	mux.HandleFunc("GET /api/servers/{id}/players/{name}/locate", playerHandler.Locate)
+	mux.HandleFunc("POST /api/servers/{id}/locate", playerHandler.LocateStructure)
+	mux.HandleFunc("GET /api/servers/{id}/coreprotect/lookup", playerHandler.CoreProtectLookup)
+	mux.HandleFunc("GET /api/servers/{id}/whitelist-schedule", playerHandler.GetWhitelistSchedule)
+	mux.HandleFunc("PUT /api/servers/{id}/whitelist-schedule", playerHandler.SetWhitelistSchedule)
 	mux.HandleFunc("POST /api/servers/{id}/players/{name}/kick", playerHandler.Kick)
 	mux.HandleFunc("POST /api/servers/{id}/players/{name}/ban", playerHandler.Ban)
+	mux.HandleFunc("GET /api/servers/{id}/tempbans", playerHandler.ListTempBans)
+	mux.HandleFunc("DELETE /api/servers/{id}/tempbans/{name}", playerHandler.LiftTempBan)
 	mux.HandleFunc("POST /api/servers/{id}/players/{name}/kill", playerHandler.Kill)
+	mux.HandleFunc("POST /api/servers/{id}/players/{name}/send", playerHandler.Send)
+	mux.HandleFunc("GET /api/servers/{id}/proxy-backends", playerHandler.ProxyBackends)
+	mux.HandleFunc("GET /api/servers/{id}/whitelist", playerHandler.ListWhitelist)
+	mux.HandleFunc("POST /api/servers/{id}/whitelist", playerHandler.AddWhitelist)
+	mux.HandleFunc("DELETE /api/servers/{id}/whitelist/{name}", playerHandler.RemoveWhitelist)
+	mux.HandleFunc("GET /api/servers/{id}/ops", playerHandler.ListOps)
+	mux.HandleFunc("POST /api/servers/{id}/ops", playerHandler.AddOp)
+	mux.HandleFunc("DELETE /api/servers/{id}/ops/{name}", playerHandler.RemoveOp)
+	mux.HandleFunc("GET /api/servers/{id}/bans", playerHandler.ListBans)
+	mux.HandleFunc("POST /api/servers/{id}/bans", playerHandler.AddBan)
+	mux.HandleFunc("DELETE /api/servers/{id}/bans/{target}", playerHandler.RemoveBan)
+	mux.HandleFunc("GET /api/moderation/presets", playerHandler.GetModerationPresets)
+	mux.HandleFunc("PUT /api/moderation/presets", playerHandler.SetModerationPresets)
+	mux.HandleFunc("GET /api/moderation/log", playerHandler.ModerationLog)
+
+	// Browser CSP violation reports
+	mux.HandleFunc("POST /api/csp-report", handlers.CSPReport)
 
 	// Serve static files (React SPA)
 	mux.Handle("/", spaHandler(distDir))
 
-	// Wrap with CORS middleware
-	handler := corsMiddleware(authHandler.Middleware(mux))
+	// Wrap with request timing, CORS, compression and security header middleware
+	handler := handlers.SecurityHeaders(mgr, handlers.Compress(corsMiddleware(authHandler.Middleware(selfMetricsHandler.Middleware(mux)))))
 
 	log.Println("=== Orexa Panel ===")
 	log.Printf("Servers directory: %s", filepath.Join(baseDir, "Servers"))
-	log.Println("Server running on http://localhost:4010")
+	srv, err := newHTTPServer(":4010", handler)
+	if err != nil {
+		log.Fatalf("Failed to configure HTTP server: %v", err)
+	}
+	certFile := strings.TrimSpace(os.Getenv("ADPANEL_TLS_CERT"))
+	keyFile := strings.TrimSpace(os.Getenv("ADPANEL_TLS_KEY"))
+
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	serveErr := make(chan error, 1)
+	go func() {
+		if certFile != "" && keyFile != "" {
+			log.Println("Server running on https://localhost:4010")
+			serveErr <- srv.ListenAndServeTLS(certFile, keyFile)
+			return
+		}
+		log.Println("Server running on http://localhost:4010")
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	exitCode := 0
+	select {
+	case err := <-serveErr:
+		log.Printf("HTTP server failed: %v", err)
+		exitCode = 1
+	case <-ctx.Done():
+		log.Println("Shutting down: draining in-flight requests...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("HTTP server did not drain in time (%v), closing remaining connections", err)
+			_ = srv.Close()
+		}
+		cancel()
+	}
+
+	policy := minecraft.ShutdownPolicyFromEnv()
+	log.Printf("Stopping panel (shutdown policy %q)...", policy)
+	mgr.Shutdown(policy)
+	log.Println("Panel stopped")
+	os.Exit(exitCode)
+}
+
+// httpShutdownTimeout bounds how long a shutdown waits for in-flight
+// requests; uploads and downloads still running after it are cut off.
+const httpShutdownTimeout = 20 * time.Second
+
+// newHTTPServer configures the panel's HTTP server. HTTP/2 is negotiated
+// over TLS when ADPANEL_TLS_CERT and ADPANEL_TLS_KEY are set, and accepted
+// in cleartext (h2c) from reverse proxies otherwise. WebSocket upgrades
+// stay on HTTP/1.1.
+func newHTTPServer(addr string, handler http.Handler) (*http.Server, error) {
+	h2 := &http2.Server{
+		MaxConcurrentStreams: 250,
+		IdleTimeout:          2 * time.Minute,
+		// Ping idle connections so dead mobile clients are dropped.
+		ReadIdleTimeout: 30 * time.Second,
+		PingTimeout:     15 * time.Second,
+	}
 	srv := &http.Server{
-		Addr:              ":4010",
-		Handler:           handler,
+		Addr:              addr,
+		Handler:           h2c.NewHandler(handler, h2),
 		ReadHeaderTimeout: 10 * time.Second,
 		WriteTimeout:      10 * time.Minute,
 		IdleTimeout:       2 * time.Minute,
 		MaxHeaderBytes:    1 << 20,
 	}
-	log.Fatal(srv.ListenAndServe())
+	if err := http2.ConfigureServer(srv, h2); err != nil {
+		return nil, err
+	}
+	return srv, nil
 }
 
 // spaHandler serves static files from distDir, falling back to index.html for client-side routes
@@ -232,8 +445,8 @@ func corsMiddleware(next http.Handler) http.Handler {
 			w.Header().Set("Access-Control-Allow-Origin", allowed)
 			w.Header().Set("Vary", "Origin")
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-CSRF-Token")
 		}
 
 		if r.Method == "OPTIONS" {
@@ -466,6 +679,10 @@ func runReadinessChecks(baseDir, distDir string, mgr *minecraft.Manager) error {
 		return err
 	}
 
+	if status := mgr.GetPersistenceStatus(); status.Pending && status.LastError != "" {
+		return &checkError{message: "panel data could not be saved: " + status.LastError}
+	}
+
 	_ = mgr.ListServers()
 	return nil
 }
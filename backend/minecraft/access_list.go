@@ -0,0 +1,78 @@
+package minecraft
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// normalizeAccessEntries validates IP and CIDR entries for the panel access
+// lists. Bare IPs become single-host CIDRs; duplicates are dropped.
+func normalizeAccessEntries(field string, entries []string) ([]string, error) {
+	out := make([]string, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		network, err := parseAccessEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%s entry %q is not an IP address or CIDR range", field, entry)
+		}
+		normalized := network.String()
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		out = append(out, normalized)
+	}
+	return out, nil
+}
+
+func parseAccessEntry(entry string) (*net.IPNet, error) {
+	if _, network, err := net.ParseCIDR(entry); err == nil {
+		return network, nil
+	}
+	ip := net.ParseIP(entry)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid entry")
+	}
+	bits := 32
+	if ip.To4() == nil {
+		bits = 128
+	} else {
+		ip = ip.To4()
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+func accessListContains(entries []string, ip net.IP) bool {
+	for _, entry := range entries {
+		if network, err := parseAccessEntry(entry); err == nil && network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IPAccessPermitted reports whether a client may reach the panel under the
+// access lists. direct says clientIP is the connection's own peer and the
+// request carried no proxy headers; loopback is then always allowed so
+// local health checks and recovery keep working. A reverse proxy on the
+// same host also connects from loopback, so proxied requests get no such
+// exemption. Otherwise the deny list wins, and a non-empty allow list
+// admits only its ranges.
+func (s AppSettings) IPAccessPermitted(clientIP string, direct bool) bool {
+	ip := net.ParseIP(strings.TrimSpace(clientIP))
+	if ip == nil {
+		return len(s.AccessAllowList) == 0 && len(s.AccessDenyList) == 0
+	}
+	if direct && ip.IsLoopback() {
+		return true
+	}
+	if accessListContains(s.AccessDenyList, ip) {
+		return false
+	}
+	return len(s.AccessAllowList) == 0 || accessListContains(s.AccessAllowList, ip)
+}
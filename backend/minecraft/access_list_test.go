@@ -0,0 +1,61 @@
+package minecraft
+
+import "testing"
+
+func TestIPAccessPermittedAppliesDenyThenAllow(t *testing.T) {
+	settings := AppSettings{
+		AccessAllowList: []string{"192.168.1.0/24", "10.8.0.0/16"},
+		AccessDenyList:  []string{"192.168.1.66/32"},
+	}
+	cases := map[string]bool{
+		"192.168.1.20": true,
+		"10.8.3.4":     true,
+		"192.168.1.66": false,
+		"203.0.113.9":  false,
+		"127.0.0.1":    true,
+		"::1":          true,
+	}
+	for ip, want := range cases {
+		if got := settings.IPAccessPermitted(ip, true); got != want {
+			t.Fatalf("IPAccessPermitted(%s) = %v, want %v", ip, got, want)
+		}
+	}
+	// Behind a proxy on the same host, loopback is just another address.
+	if settings.IPAccessPermitted("127.0.0.1", false) {
+		t.Fatalf("expected proxied loopback requests to need the allow list")
+	}
+	if !(AppSettings{}).IPAccessPermitted("203.0.113.9", false) {
+		t.Fatalf("empty lists must allow every address")
+	}
+}
+
+func TestAccessListUpdateRejectsSelfLockout(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	allow := []string{"10.0.0.0/8"}
+	_, fields, err := mgr.PatchAppSettings(AppSettingsPatch{AccessAllowList: &allow, RequesterIP: "192.168.1.20"})
+	if err == nil {
+		t.Fatalf("expected an allow list excluding the requester to be rejected")
+	}
+	if got := findSettingResult(fields, "accessAllowList"); got.Status != SettingRejected {
+		t.Fatalf("expected accessAllowList to be rejected, got %+v", fields)
+	}
+
+	bad := []string{"not-an-ip"}
+	if _, _, err := mgr.PatchAppSettings(AppSettingsPatch{AccessDenyList: &bad}); err == nil {
+		t.Fatalf("expected an invalid entry to be rejected")
+	}
+
+	allow = []string{"192.168.1.0/24", "192.168.1.20", " 10.0.0.1 "}
+	settings, _, err := mgr.PatchAppSettings(AppSettingsPatch{AccessAllowList: &allow, RequesterIP: "192.168.1.20"})
+	if err != nil {
+		t.Fatalf("PatchAppSettings failed: %v", err)
+	}
+	if len(settings.AccessAllowList) != 3 || settings.AccessAllowList[1] != "192.168.1.20/32" || settings.AccessAllowList[2] != "10.0.0.1/32" {
+		t.Fatalf("expected normalized entries, got %v", settings.AccessAllowList)
+	}
+}
@@ -0,0 +1,155 @@
+package minecraft
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAPIMinInterval           = 200 * time.Millisecond
+	maxAPIRateLimitRetries          = 3
+	maxAPIRetryAfter                = 30 * time.Second
+	defaultPluginUpdateCheckWorkers = 6
+)
+
+// apiHTTPClient is shared by JSON API calls so connections to Modrinth,
+// Spiget and friends are reused instead of re-dialled per request.
+var apiHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// apiHostPacer spaces out requests to the same host. Modrinth allows about
+// 300 requests a minute; one request per 200ms per host stays under that.
+type apiHostPacer struct {
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+var apiPacer = &apiHostPacer{next: make(map[string]time.Time)}
+
+// wait blocks until host may be called again and reserves the following slot.
+func (p *apiHostPacer) wait(ctx context.Context, host string, interval time.Duration) error {
+	p.mu.Lock()
+	now := time.Now()
+	slot := p.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	p.next[host] = slot.Add(interval)
+	p.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// backOff pushes the host's next slot out, e.g. after a 429, so every
+// caller waits rather than only the one that got rate limited.
+func (p *apiHostPacer) backOff(host string, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if until := time.Now().Add(d); p.next[host].Before(until) {
+		p.next[host] = until
+	}
+}
+
+func apiMinIntervalFromEnv() time.Duration {
+	raw := strings.TrimSpace(os.Getenv("ADPANEL_PLUGIN_API_MIN_INTERVAL_MS"))
+	if raw == "" {
+		return defaultAPIMinInterval
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		log.Printf("Invalid ADPANEL_PLUGIN_API_MIN_INTERVAL_MS value %q, using default %d", raw, defaultAPIMinInterval.Milliseconds())
+		return defaultAPIMinInterval
+	}
+	return time.Duration(n) * time.Millisecond
+}
+
+func pluginUpdateCheckWorkersFromEnv() int {
+	raw := strings.TrimSpace(os.Getenv("ADPANEL_PLUGIN_UPDATE_CHECK_WORKERS"))
+	if raw == "" {
+		return defaultPluginUpdateCheckWorkers
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		log.Printf("Invalid ADPANEL_PLUGIN_UPDATE_CHECK_WORKERS value %q, using default %d", raw, defaultPluginUpdateCheckWorkers)
+		return defaultPluginUpdateCheckWorkers
+	}
+	return n
+}
+
+// parseRetryAfter reads a Retry-After header in either delay-seconds or
+// HTTP-date form. Missing or unparseable values fall back to fallback.
+func parseRetryAfter(value string, fallback time.Duration) time.Duration {
+	value = strings.TrimSpace(value)
+	d := fallback
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		d = time.Duration(secs) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		d = time.Until(at)
+	}
+	if d < 0 {
+		d = 0
+	}
+	if d > maxAPIRetryAfter {
+		d = maxAPIRetryAfter
+	}
+	return d
+}
+
+// doAPIGet performs a paced GET against a JSON API and retries 429 responses
+// after the server's Retry-After. The caller closes the returned body.
+func doAPIGet(ctx context.Context, rawURL string) (*http.Response, error) {
+	host := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		host = strings.ToLower(u.Host)
+	}
+	interval := apiMinIntervalFromEnv()
+
+	for attempt := 0; ; attempt++ {
+		if err := apiPacer.wait(ctx, host, interval); err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", userAgent())
+		req.Header.Set("Accept", "application/json")
+		resp, err := apiHTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+		resp.Body.Close()
+		if attempt >= maxAPIRateLimitRetries {
+			return nil, fmt.Errorf("API request to %s was rate limited", rawURL)
+		}
+		delay := parseRetryAfter(resp.Header.Get("Retry-After"), time.Duration(attempt+1)*2*time.Second)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return nil, fmt.Errorf("API request to %s was rate limited", rawURL)
+		}
+		apiPacer.backOff(host, delay)
+		if debugPluginUpdatesEnabled() {
+			log.Printf("[UpdateDebug] rate limited by %s, retrying in %s", host, delay)
+		}
+	}
+}
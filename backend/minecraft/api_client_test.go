@@ -0,0 +1,62 @@
+package minecraft
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	if d := parseRetryAfter("5", time.Second); d != 5*time.Second {
+		t.Fatalf("expected 5s, got %s", d)
+	}
+	if d := parseRetryAfter("", 2*time.Second); d != 2*time.Second {
+		t.Fatalf("expected fallback, got %s", d)
+	}
+	if d := parseRetryAfter("3600", time.Second); d != maxAPIRetryAfter {
+		t.Fatalf("expected delay to be capped, got %s", d)
+	}
+	if d := parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), time.Second); d != 0 {
+		t.Fatalf("expected past date to mean no wait, got %s", d)
+	}
+}
+
+func TestFetchJSONRetriesRateLimit(t *testing.T) {
+	t.Setenv("ADPANEL_PLUGIN_API_MIN_INTERVAL_MS", "0")
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	var out struct {
+		OK bool `json:"ok"`
+	}
+	if err := fetchJSON(context.Background(), srv.URL, &out); err != nil || !out.OK {
+		t.Fatalf("expected retry to succeed, got ok=%v err=%v", out.OK, err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 requests, got %d", calls.Load())
+	}
+}
+
+func TestAPIHostPacerSpacesRequests(t *testing.T) {
+	pacer := &apiHostPacer{next: make(map[string]time.Time)}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := pacer.wait(context.Background(), "example.com", 20*time.Millisecond); err != nil {
+			t.Fatalf("wait failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("expected requests to be spaced out, took %s", elapsed)
+	}
+}
@@ -0,0 +1,229 @@
+package minecraft
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// backupProgressInterval limits how often archive progress is reported.
+const backupProgressInterval = 250 * time.Millisecond
+
+// backupExcluded matches tar's --exclude=backups: anything named backups is
+// left out, at any depth. Folders still being deleted or unpacked are left
+// out too.
+func backupExcluded(name string) bool {
+	return name == "backups" || name == deletingDirName || name == refreshingDirName
+}
+
+// walkBackupSource calls fn for every directory, regular file and symlink
+// under dir that belongs in a backup, with its path relative to dir.
+// Entries that vanish during the walk are skipped.
+func walkBackupSource(ctx context.Context, dir string, fn func(path, rel string, info os.FileInfo) error) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path != dir {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		if backupExcluded(d.Name()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.Mode().IsRegular() && !info.IsDir() && info.Mode()&os.ModeSymlink == 0 {
+			// Sockets, pipes and devices have no place in a backup.
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		return fn(path, rel, info)
+	})
+}
+
+// backupSourceSize totals the regular files a backup of dir will hold.
+func backupSourceSize(ctx context.Context, dir string) (int64, error) {
+	var total int64
+	err := walkBackupSource(ctx, dir, func(_, _ string, info os.FileInfo) error {
+		if info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
+
+// throttledReport returns a func that passes *done and total to report at
+// most once per backupProgressInterval.
+func throttledReport(done *int64, total int64, report func(done, total int64)) func() {
+	var last time.Time
+	return func() {
+		if now := time.Now(); now.Sub(last) >= backupProgressInterval {
+			last = now
+			report(*done, total)
+		}
+	}
+}
+
+// progressReader counts bytes read and stops once ctx is cancelled.
+type progressReader struct {
+	ctx    context.Context
+	r      io.Reader
+	done   *int64
+	report func()
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	if err := p.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := p.r.Read(b)
+	*p.done += int64(n)
+	p.report()
+	return n, err
+}
+
+// tarFile is a file written into an archive from memory.
+type tarFile struct {
+	name string
+	data []byte
+}
+
+// tarGzOptions changes how writeTarGzWith lays out an archive.
+type tarGzOptions struct {
+	// prefix goes before every entry name; "./" when empty.
+	prefix string
+	// extra files are written before the tree, at the archive's root.
+	extra []tarFile
+	// skipSymlinks leaves symlinks out, for archives extracted by readers
+	// that refuse them.
+	skipSymlinks bool
+}
+
+// writeTarGz streams dir into a gzipped tar at dest. Files that vanish
+// while the archive is written are skipped; report receives the bytes of
+// file content archived so far and the total expected.
+func writeTarGz(ctx context.Context, dir, dest string, report func(done, total int64)) error {
+	return writeTarGzWith(ctx, dir, dest, tarGzOptions{}, report)
+}
+
+// writeTarGzWith is writeTarGz with a different archive layout.
+func writeTarGzWith(ctx context.Context, dir, dest string, opts tarGzOptions, report func(done, total int64)) (err error) {
+	prefix := opts.prefix
+	if prefix == "" {
+		prefix = "./"
+	}
+	total, err := backupSourceSize(ctx, dir)
+	if err != nil {
+		return err
+	}
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	var done int64
+	throttled := throttledReport(&done, total, report)
+
+	for _, file := range opts.extra {
+		hdr := &tar.Header{Name: file.name, Mode: 0644, Size: int64(len(file.data)), ModTime: time.Now(), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			return err
+		}
+	}
+
+	destAbs, _ := filepath.Abs(dest)
+	walkErr := walkBackupSource(ctx, dir, func(path, rel string, info os.FileInfo) error {
+		if abs, _ := filepath.Abs(path); abs == destAbs {
+			return nil
+		}
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if opts.skipSymlinks {
+				return nil
+			}
+			var err error
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = prefix + filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+
+		if !info.Mode().IsRegular() {
+			return tw.WriteHeader(hdr)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		defer f.Close()
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		// Only the size recorded in the header is copied, so a file that
+		// grows while it is read (such as a log) stays consistent.
+		src := &progressReader{ctx: ctx, r: io.LimitReader(f, hdr.Size), done: &done, report: throttled}
+		n, err := io.Copy(tw, src)
+		if err != nil {
+			return fmt.Errorf("failed to archive %s: %w", rel, err)
+		}
+		if n < hdr.Size {
+			return fmt.Errorf("failed to archive %s: file shrank while it was read", rel)
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return walkErr
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	report(done, total)
+	return nil
+}
@@ -0,0 +1,153 @@
+package minecraft
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readTarGz(t *testing.T, path string) map[string]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open archive: %v", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("failed to read gzip: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	entries := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries
+		}
+		if err != nil {
+			t.Fatalf("failed to read tar: %v", err)
+		}
+		data, _ := io.ReadAll(tr)
+		if hdr.Typeflag == tar.TypeSymlink {
+			data = []byte("-> " + hdr.Linkname)
+		}
+		entries[hdr.Name] = string(data)
+	}
+}
+
+func TestWriteTarGzArchivesServerDirectory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"server.properties":        "motd=Hi\n",
+		"world/level.dat":          "level",
+		"world/region/r.0.0.mca":   "region",
+		"backups/old.tar.gz":       "skip me",
+		"plugins/backups/x.zip":    "skip me too",
+		"plugins/Essentials/a.yml": "a: 1",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	if err := os.Symlink("world", filepath.Join(dir, "world_link")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "backup.tar.gz")
+	var lastDone, lastTotal int64
+	if err := writeTarGz(context.Background(), dir, dest, func(done, total int64) {
+		lastDone, lastTotal = done, total
+	}); err != nil {
+		t.Fatalf("writeTarGz failed: %v", err)
+	}
+
+	entries := readTarGz(t, dest)
+	for _, name := range []string{"./server.properties", "./world/level.dat", "./world/region/r.0.0.mca", "./plugins/Essentials/a.yml"} {
+		want := files[name[2:]]
+		if got, ok := entries[name]; !ok || got != want {
+			t.Fatalf("expected %s with %q, got %q (present: %v)", name, want, got, ok)
+		}
+	}
+	if _, ok := entries["./world/"]; !ok {
+		t.Fatalf("expected directory entries, got %v", entries)
+	}
+	if entries["./world_link"] != "-> world" {
+		t.Fatalf("expected the symlink to be kept, got %q", entries["./world_link"])
+	}
+	for name := range entries {
+		if name == "./backups/" || name == "./backups/old.tar.gz" || name == "./plugins/backups/x.zip" {
+			t.Fatalf("expected %s to be excluded", name)
+		}
+	}
+	wantTotal := int64(len("motd=Hi\n") + len("level") + len("region") + len("a: 1"))
+	if lastTotal != wantTotal || lastDone != wantTotal {
+		t.Fatalf("expected final progress %d/%d, got %d/%d", wantTotal, wantTotal, lastDone, lastTotal)
+	}
+}
+
+func TestWriteTarGzStopsWhenCancelled(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("data"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := writeTarGz(ctx, dir, filepath.Join(t.TempDir(), "b.tar.gz"), func(int64, int64) {}); err == nil {
+		t.Fatalf("expected a cancelled archive to fail")
+	}
+}
+
+func TestStartBackupReportsJob(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		t.Fatalf("failed to create server dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Dir, "server.properties"), []byte("motd=Hi\n"), 0644); err != nil {
+		t.Fatalf("failed to write server.properties: %v", err)
+	}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = &runningServer{status: "Stopped"}
+	mgr.mu.Unlock()
+
+	job, err := mgr.StartBackup(cfg.ID, "")
+	if err != nil {
+		t.Fatalf("StartBackup failed: %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for !job.finished() {
+		if time.Now().After(deadline) {
+			t.Fatalf("backup job did not finish: %+v", job)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if job, err = mgr.GetBackupJob(cfg.ID, job.ID); err != nil {
+			t.Fatalf("GetBackupJob failed: %v", err)
+		}
+	}
+	backup, ok := job.Result.(*BackupInfo)
+	if job.State != JobStateSucceeded || job.Progress != 100 || !ok || job.BytesDone != job.BytesTotal || job.BytesTotal != int64(len("motd=Hi\n")) {
+		t.Fatalf("unexpected finished job: %+v", job)
+	}
+	if _, err := os.Stat(filepath.Join(mgr.backupDir(cfg), backup.Name)); err != nil {
+		t.Fatalf("expected the backup on disk: %v", err)
+	}
+	if _, err := mgr.GetBackupJob("other", job.ID); err != ErrJobNotFound {
+		t.Fatalf("expected another server's lookup to fail, got %v", err)
+	}
+}
@@ -0,0 +1,293 @@
+package minecraft
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// s3PartSize is the size of each part of a multipart upload. Archives no
+// larger than one part go up in a single PUT.
+var s3PartSize int64 = 64 << 20
+
+const s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// s3Uploader writes objects to S3-compatible storage using Signature
+// Version 4.
+type s3Uploader struct {
+	target BackupTarget
+	client *http.Client
+	now    func() time.Time
+}
+
+func newS3Uploader(t BackupTarget) *s3Uploader {
+	if t.Region == "" {
+		t.Region = "us-east-1"
+	}
+	if t.Endpoint == "" {
+		t.Endpoint = "https://s3." + t.Region + ".amazonaws.com"
+	}
+	return &s3Uploader{target: t, client: &http.Client{}, now: time.Now}
+}
+
+// s3Escape percent-encodes everything but unreserved characters, as the
+// canonical request requires.
+func s3Escape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~' || (keepSlash && c == '/') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// objectURL returns the object's URL and its canonical path.
+func (u *s3Uploader) objectURL(key string, query url.Values) (*url.URL, string, error) {
+	base, err := url.Parse(u.target.Endpoint)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid endpoint: %w", err)
+	}
+	path := "/" + s3Escape(strings.TrimPrefix(key, "/"), true)
+	if u.target.PathStyle {
+		path = "/" + s3Escape(u.target.Bucket, false) + path
+	} else {
+		base.Host = u.target.Bucket + "." + base.Host
+	}
+	path = strings.TrimRight(base.EscapedPath(), "/") + path
+	raw := base.Scheme + "://" + base.Host + path
+	if len(query) > 0 {
+		raw += "?" + s3CanonicalQuery(query)
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, "", err
+	}
+	return parsed, path, nil
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, s3Escape(k, false)+"="+s3Escape(query.Get(k), false))
+	}
+	return strings.Join(parts, "&")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// sign adds the SigV4 headers to req. payloadHash is the hex SHA-256 of the
+// body, or UNSIGNED-PAYLOAD for streamed archives.
+func (u *s3Uploader) sign(req *http.Request, canonicalPath string, query url.Values, payloadHash string) {
+	now := u.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	names := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := map[string]string{"host": req.URL.Host, "x-amz-content-sha256": payloadHash, "x-amz-date": amzDate}
+	if ct := req.Header.Get("Content-Type"); ct != "" {
+		names = append(names, "content-type")
+		values["content-type"] = ct
+	}
+	sort.Strings(names)
+	var headers strings.Builder
+	for _, name := range names {
+		headers.WriteString(name + ":" + strings.TrimSpace(values[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		canonicalPath,
+		s3CanonicalQuery(query),
+		headers.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + u.target.Region + "/s3/aws4_request"
+	sum := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(sum[:])
+
+	key := hmacSHA256([]byte("AWS4"+u.target.SecretKey), day)
+	key = hmacSHA256(key, u.target.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		u.target.AccessKey, scope, signedHeaders, signature))
+}
+
+// s3Error is the XML error body S3 returns.
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// do sends a signed request. A body given as []byte is hashed into the
+// signature; an io.Reader is sent unsigned with the given length.
+func (u *s3Uploader) do(ctx context.Context, method, key string, query url.Values, body any, length int64) (*http.Response, error) {
+	target, canonicalPath, err := u.objectURL(key, query)
+	if err != nil {
+		return nil, err
+	}
+	payloadHash := s3UnsignedPayload
+	var reader io.Reader
+	switch b := body.(type) {
+	case []byte:
+		sum := sha256.Sum256(b)
+		payloadHash = hex.EncodeToString(sum[:])
+		reader = bytes.NewReader(b)
+		length = int64(len(b))
+	case io.Reader:
+		reader = b
+	default:
+		sum := sha256.Sum256(nil)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = length
+	if _, ok := body.([]byte); ok {
+		req.Header.Set("Content-Type", "application/xml")
+	}
+	u.sign(req, canonicalPath, query, payloadHash)
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		var e s3Error
+		if xml.Unmarshal(data, &e) == nil && e.Code != "" {
+			return nil, fmt.Errorf("S3 %s: %s (%s)", method, e.Message, e.Code)
+		}
+		return nil, fmt.Errorf("S3 %s failed with status %d", method, resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func (u *s3Uploader) upload(ctx context.Context, localPath, remotePath string, size int64, progress func(n int64)) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var done int64
+	report := func() { progress(done) }
+	if size <= s3PartSize {
+		resp, err := u.do(ctx, http.MethodPut, remotePath, nil, &progressReader{ctx: ctx, r: f, done: &done, report: report}, size)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+	return u.uploadMultipart(ctx, f, remotePath, size, &done, report)
+}
+
+type s3CompletePart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// uploadMultipart sends f in s3PartSize parts and aborts the upload if any
+// part fails, so no orphaned parts are left to bill for.
+func (u *s3Uploader) uploadMultipart(ctx context.Context, f *os.File, key string, size int64, done *int64, report func()) error {
+	resp, err := u.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, 0)
+	if err != nil {
+		return err
+	}
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	err = xml.NewDecoder(resp.Body).Decode(&initiated)
+	resp.Body.Close()
+	if err != nil || initiated.UploadID == "" {
+		return fmt.Errorf("S3 did not start the multipart upload")
+	}
+	uploadID := initiated.UploadID
+	abort := func(cause error) error {
+		// The abort must go out even when ctx was cancelled.
+		if resp, err := u.do(context.Background(), http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, 0); err == nil {
+			resp.Body.Close()
+		}
+		return cause
+	}
+
+	var parts []s3CompletePart
+	for offset, number := int64(0), 1; offset < size; offset, number = offset+s3PartSize, number+1 {
+		length := s3PartSize
+		if size-offset < length {
+			length = size - offset
+		}
+		section := io.NewSectionReader(f, offset, length)
+		query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+		resp, err := u.do(ctx, http.MethodPut, key, query, &progressReader{ctx: ctx, r: section, done: done, report: report}, length)
+		if err != nil {
+			return abort(fmt.Errorf("part %d: %w", number, err))
+		}
+		resp.Body.Close()
+		parts = append(parts, s3CompletePart{PartNumber: number, ETag: resp.Header.Get("ETag")})
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name         `xml:"CompleteMultipartUpload"`
+		Parts   []s3CompletePart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return abort(err)
+	}
+	resp, err = u.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, body, 0)
+	if err != nil {
+		return abort(err)
+	}
+	defer resp.Body.Close()
+	// S3 can report a failed completion inside a 200 response.
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var e s3Error
+	if xml.Unmarshal(data, &e) == nil && e.Code != "" {
+		return abort(fmt.Errorf("S3 could not complete the upload: %s (%s)", e.Message, e.Code))
+	}
+	return nil
+}
+
+func (u *s3Uploader) remove(ctx context.Context, remotePath string) error {
+	resp, err := u.do(ctx, http.MethodDelete, remotePath, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,401 @@
+package minecraft
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SFTP version 3 packet types and flags, from draft-ietf-secsh-filexfer-02.
+const (
+	sftpInit    = 1
+	sftpVersion = 2
+	sftpOpen    = 3
+	sftpClose   = 4
+	sftpWrite   = 6
+	sftpStat    = 17
+	sftpRemove  = 13
+	sftpMkdir   = 14
+	sftpRename  = 18
+	sftpStatus  = 101
+	sftpHandle  = 102
+
+	sftpFlagWrite  = 0x02
+	sftpFlagCreate = 0x08
+	sftpFlagTrunc  = 0x10
+
+	sftpStatusOK = 0
+)
+
+const (
+	sftpDialTimeout = 15 * time.Second
+	// sftpChunkSize keeps write packets well under the 34000-byte minimum
+	// servers must accept.
+	sftpChunkSize = 32 * 1024
+	// sftpMaxInFlight is how many writes may await their status at once,
+	// so throughput is not limited to one chunk per round trip.
+	sftpMaxInFlight = 16
+	sftpMaxPacket   = 256 * 1024
+)
+
+func parseSFTPPrivateKey(key string) (ssh.Signer, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return signer, nil
+}
+
+// sftpUploader copies archives to an SFTP server.
+type sftpUploader struct {
+	target BackupTarget
+}
+
+// sftpConn is a minimal SFTP client: enough to create folders and write,
+// rename and remove files.
+type sftpConn struct {
+	ssh    *ssh.Client
+	w      io.WriteCloser
+	r      io.Reader
+	nextID uint32
+	stop   func() bool
+}
+
+// dialSFTP connects and starts the sftp subsystem. The host must present
+// the key whose fingerprint is configured on the target.
+func dialSFTP(ctx context.Context, t BackupTarget) (*sftpConn, error) {
+	var auth []ssh.AuthMethod
+	if t.PrivateKey != "" {
+		signer, err := parseSFTPPrivateKey(t.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if t.Password != "" {
+		auth = append(auth, ssh.Password(t.Password))
+	}
+	config := &ssh.ClientConfig{
+		User: t.User,
+		Auth: auth,
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			fingerprint := ssh.FingerprintSHA256(key)
+			if t.HostKey == "" {
+				return fmt.Errorf("host key %s is not trusted yet; add it to the target if it is the right server", fingerprint)
+			}
+			if fingerprint != t.HostKey {
+				return fmt.Errorf("host key %s does not match the configured %s", fingerprint, t.HostKey)
+			}
+			return nil
+		},
+		Timeout: sftpDialTimeout,
+	}
+
+	addr := net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+	dialer := net.Dialer{Timeout: sftpDialTimeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(netConn, addr, config)
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	session, err := client.NewSession()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	w, err := session.StdinPipe()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	r, err := session.StdoutPipe()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("server does not offer SFTP: %w", err)
+	}
+
+	// Closing the connection unblocks any read or write when ctx ends.
+	c := &sftpConn{ssh: client, w: w, r: r, stop: context.AfterFunc(ctx, func() { client.Close() })}
+	if err := c.send(sftpInit, binary.BigEndian.AppendUint32(nil, 3)); err != nil {
+		c.Close()
+		return nil, err
+	}
+	typ, _, err := c.recv()
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	if typ != sftpVersion {
+		c.Close()
+		return nil, fmt.Errorf("unexpected SFTP handshake reply %d", typ)
+	}
+	return c, nil
+}
+
+func (c *sftpConn) Close() error {
+	c.stop()
+	c.w.Close()
+	return c.ssh.Close()
+}
+
+func (c *sftpConn) send(typ byte, payload []byte) error {
+	header := make([]byte, 5)
+	binary.BigEndian.PutUint32(header, uint32(len(payload)+1))
+	header[4] = typ
+	if _, err := c.w.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *sftpConn) recv() (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(c.r, header); err != nil {
+		return 0, nil, err
+	}
+	length := binary.BigEndian.Uint32(header)
+	if length < 1 || length > sftpMaxPacket {
+		return 0, nil, fmt.Errorf("invalid SFTP packet length %d", length)
+	}
+	payload := make([]byte, length-1)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return 0, nil, err
+	}
+	return header[4], payload, nil
+}
+
+func sftpString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// request sends a packet with a fresh request ID and returns the ID.
+func (c *sftpConn) request(typ byte, build func(b []byte) []byte) (uint32, error) {
+	c.nextID++
+	id := c.nextID
+	return id, c.send(typ, build(binary.BigEndian.AppendUint32(nil, id)))
+}
+
+// sftpStatusError is a non-OK status reply.
+type sftpStatusError struct {
+	code    uint32
+	message string
+}
+
+func (e *sftpStatusError) Error() string {
+	if e.message != "" {
+		return "SFTP: " + e.message
+	}
+	return fmt.Sprintf("SFTP error %d", e.code)
+}
+
+// reply reads one response and returns its request ID, type and body.
+func (c *sftpConn) reply() (uint32, byte, []byte, error) {
+	typ, payload, err := c.recv()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if len(payload) < 4 {
+		return 0, 0, nil, fmt.Errorf("short SFTP reply")
+	}
+	return binary.BigEndian.Uint32(payload), typ, payload[4:], nil
+}
+
+func statusError(body []byte) error {
+	if len(body) < 4 {
+		return fmt.Errorf("short SFTP status")
+	}
+	code := binary.BigEndian.Uint32(body)
+	if code == sftpStatusOK {
+		return nil
+	}
+	e := &sftpStatusError{code: code}
+	if len(body) >= 8 {
+		n := binary.BigEndian.Uint32(body[4:])
+		if int(n) <= len(body)-8 {
+			e.message = string(body[8 : 8+n])
+		}
+	}
+	return e
+}
+
+// call sends a request and waits for its reply.
+func (c *sftpConn) call(typ byte, build func(b []byte) []byte) (byte, []byte, error) {
+	id, err := c.request(typ, build)
+	if err != nil {
+		return 0, nil, err
+	}
+	replyID, replyType, body, err := c.reply()
+	if err != nil {
+		return 0, nil, err
+	}
+	if replyID != id {
+		return 0, nil, fmt.Errorf("unexpected SFTP reply")
+	}
+	if replyType == sftpStatus {
+		return replyType, body, statusError(body)
+	}
+	return replyType, body, nil
+}
+
+func (c *sftpConn) pathCall(typ byte, p string, extra func(b []byte) []byte) error {
+	_, _, err := c.call(typ, func(b []byte) []byte {
+		b = sftpString(b, p)
+		if extra != nil {
+			b = extra(b)
+		}
+		return b
+	})
+	return err
+}
+
+// mkdirAll creates dir and its parents, ignoring ones that already exist.
+func (c *sftpConn) mkdirAll(dir string) error {
+	if dir == "" || dir == "." || dir == "/" {
+		return nil
+	}
+	if _, _, err := c.call(sftpStat, func(b []byte) []byte { return sftpString(b, dir) }); err == nil {
+		return nil
+	}
+	if err := c.mkdirAll(path.Dir(dir)); err != nil {
+		return err
+	}
+	noAttrs := func(b []byte) []byte { return binary.BigEndian.AppendUint32(b, 0) }
+	if err := c.pathCall(sftpMkdir, dir, noAttrs); err != nil {
+		// Another upload may have created it in the meantime.
+		if _, _, statErr := c.call(sftpStat, func(b []byte) []byte { return sftpString(b, dir) }); statErr != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// writeFile streams r into remote, keeping several writes in flight.
+func (c *sftpConn) writeFile(ctx context.Context, remote string, r io.Reader) error {
+	typ, body, err := c.call(sftpOpen, func(b []byte) []byte {
+		b = sftpString(b, remote)
+		b = binary.BigEndian.AppendUint32(b, sftpFlagWrite|sftpFlagCreate|sftpFlagTrunc)
+		return binary.BigEndian.AppendUint32(b, 0)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", remote, err)
+	}
+	if typ != sftpHandle || len(body) < 4 || int(binary.BigEndian.Uint32(body)) > len(body)-4 {
+		return fmt.Errorf("unexpected SFTP open reply")
+	}
+	handle := string(body[4 : 4+binary.BigEndian.Uint32(body)])
+
+	pending := make(map[uint32]bool)
+	awaitOne := func() error {
+		id, typ, body, err := c.reply()
+		if err != nil {
+			return err
+		}
+		if !pending[id] || typ != sftpStatus {
+			return fmt.Errorf("unexpected SFTP reply")
+		}
+		delete(pending, id)
+		return statusError(body)
+	}
+
+	buf := make([]byte, sftpChunkSize)
+	var offset uint64
+	var writeErr error
+	for writeErr == nil {
+		if err := ctx.Err(); err != nil {
+			writeErr = err
+			break
+		}
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			chunk := buf[:n]
+			id, sendErr := c.request(sftpWrite, func(b []byte) []byte {
+				b = sftpString(b, handle)
+				b = binary.BigEndian.AppendUint64(b, offset)
+				return sftpString(b, string(chunk))
+			})
+			if sendErr != nil {
+				writeErr = sendErr
+				break
+			}
+			pending[id] = true
+			offset += uint64(n)
+			if len(pending) >= sftpMaxInFlight {
+				writeErr = awaitOne()
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil && writeErr == nil {
+			writeErr = err
+		}
+	}
+	for len(pending) > 0 && writeErr == nil {
+		writeErr = awaitOne()
+	}
+	closeErr := c.pathCall(sftpClose, handle, nil)
+	if writeErr != nil {
+		return writeErr
+	}
+	return closeErr
+}
+
+// upload writes the archive under a temporary name and renames it into
+// place, so a broken transfer never looks like a complete backup.
+func (u sftpUploader) upload(ctx context.Context, localPath, remotePath string, size int64, progress func(n int64)) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	c, err := dialSFTP(ctx, u.target)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if err := c.mkdirAll(path.Dir(remotePath)); err != nil {
+		return err
+	}
+	partial := path.Join(path.Dir(remotePath), "."+path.Base(remotePath)+".partial")
+	var done int64
+	src := &progressReader{ctx: ctx, r: f, done: &done, report: func() { progress(done) }}
+	if err := c.writeFile(ctx, partial, src); err != nil {
+		_ = c.pathCall(sftpRemove, partial, nil)
+		return err
+	}
+	// SFTP v3 renames refuse to replace an existing file.
+	_ = c.pathCall(sftpRemove, remotePath, nil)
+	if err := c.pathCall(sftpRename, partial, func(b []byte) []byte { return sftpString(b, remotePath) }); err != nil {
+		return fmt.Errorf("failed to move the upload into place: %w", err)
+	}
+	return nil
+}
+
+func (u sftpUploader) remove(ctx context.Context, remotePath string) error {
+	c, err := dialSFTP(ctx, u.target)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return c.pathCall(sftpRemove, remotePath, nil)
+}
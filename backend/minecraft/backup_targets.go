@@ -0,0 +1,528 @@
+package minecraft
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// Remote backup target kinds.
+const (
+	BackupTargetS3   = "s3"
+	BackupTargetSFTP = "sftp"
+)
+
+// Upload states of a backup on one target.
+const (
+	BackupUploadPending   = "pending"
+	BackupUploadUploading = "uploading"
+	BackupUploadUploaded  = "uploaded"
+	BackupUploadFailed    = "failed"
+)
+
+const maxBackupTargets = 20
+
+// BackupTarget is a remote place full backup archives are copied to after
+// they are created. Archives land in Path/<server folder>/<archive name>.
+// Secrets are never returned by the API; HasSecret tells whether one is
+// stored, and saving a target without a secret keeps the stored one.
+type BackupTarget struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Path string `json:"path,omitempty"`
+
+	// S3-compatible storage. An empty endpoint means AWS in Region;
+	// PathStyle puts the bucket in the path instead of the host name, as
+	// MinIO and most self-hosted stores expect.
+	Endpoint  string `json:"endpoint,omitempty"`
+	Region    string `json:"region,omitempty"`
+	Bucket    string `json:"bucket,omitempty"`
+	AccessKey string `json:"accessKey,omitempty"`
+	SecretKey string `json:"secretKey,omitempty"`
+	PathStyle bool   `json:"pathStyle,omitempty"`
+
+	// SFTP. HostKey is the server's SHA256 key fingerprint; connections to
+	// a host presenting any other key are refused.
+	Host       string `json:"host,omitempty"`
+	Port       int    `json:"port,omitempty"`
+	User       string `json:"user,omitempty"`
+	Password   string `json:"password,omitempty"`
+	PrivateKey string `json:"privateKey,omitempty"`
+	HostKey    string `json:"hostKey,omitempty"`
+
+	HasSecret bool `json:"hasSecret"`
+}
+
+// BackupUpload is the state of one backup on one target.
+type BackupUpload struct {
+	Target     string `json:"target"`
+	TargetName string `json:"targetName,omitempty"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	UpdatedAt  string `json:"updatedAt"`
+	JobID      string `json:"jobId,omitempty"`
+}
+
+// backupUploader copies a local file to a target.
+type backupUploader interface {
+	upload(ctx context.Context, localPath, remotePath string, size int64, progress func(n int64)) error
+	remove(ctx context.Context, remotePath string) error
+}
+
+func (t BackupTarget) hasSecret() bool {
+	return t.SecretKey != "" || t.Password != "" || t.PrivateKey != ""
+}
+
+// redacted returns the target without its secrets, for the API.
+func (t BackupTarget) redacted() BackupTarget {
+	t.HasSecret = t.hasSecret()
+	t.SecretKey = ""
+	t.Password = ""
+	t.PrivateKey = ""
+	return t
+}
+
+func (t BackupTarget) uploader() (backupUploader, error) {
+	switch t.Type {
+	case BackupTargetS3:
+		return newS3Uploader(t), nil
+	case BackupTargetSFTP:
+		return sftpUploader{target: t}, nil
+	}
+	return nil, fmt.Errorf("unknown backup target type %q", t.Type)
+}
+
+// remotePath is where an archive of a server's backup folder lands.
+func (t BackupTarget) remotePath(folder, name string) string {
+	return path.Join(strings.Trim(t.Path, "/"), folder, name)
+}
+
+func validateBackupTarget(t *BackupTarget) error {
+	t.Name = strings.TrimSpace(t.Name)
+	t.Type = strings.ToLower(strings.TrimSpace(t.Type))
+	t.Path = strings.Trim(strings.TrimSpace(t.Path), "/")
+	if t.Name == "" {
+		return fmt.Errorf("backup targets need a name")
+	}
+	if strings.Contains(t.Path, "..") {
+		return fmt.Errorf("%s: path must not contain ..", t.Name)
+	}
+	switch t.Type {
+	case BackupTargetS3:
+		t.Endpoint = strings.TrimRight(strings.TrimSpace(t.Endpoint), "/")
+		t.Region = strings.TrimSpace(t.Region)
+		t.Bucket = strings.TrimSpace(t.Bucket)
+		t.AccessKey = strings.TrimSpace(t.AccessKey)
+		if t.Endpoint != "" && !strings.HasPrefix(t.Endpoint, "https://") && !strings.HasPrefix(t.Endpoint, "http://") {
+			return fmt.Errorf("%s: endpoint must start with https:// or http://", t.Name)
+		}
+		if t.Bucket == "" || t.AccessKey == "" {
+			return fmt.Errorf("%s: bucket and access key are required", t.Name)
+		}
+		t.Host, t.Port, t.User, t.Password, t.PrivateKey, t.HostKey = "", 0, "", "", "", ""
+	case BackupTargetSFTP:
+		t.Host = strings.TrimSpace(t.Host)
+		t.User = strings.TrimSpace(t.User)
+		t.HostKey = strings.TrimSpace(t.HostKey)
+		if t.Host == "" || t.User == "" {
+			return fmt.Errorf("%s: host and user are required", t.Name)
+		}
+		if t.Port == 0 {
+			t.Port = 22
+		}
+		if t.Port < 1 || t.Port > 65535 {
+			return fmt.Errorf("%s: port must be between 1 and 65535", t.Name)
+		}
+		if t.HostKey != "" && !strings.HasPrefix(t.HostKey, "SHA256:") {
+			return fmt.Errorf("%s: host key must be a SHA256 fingerprint such as SHA256:abc...", t.Name)
+		}
+		t.Endpoint, t.Region, t.Bucket, t.AccessKey, t.SecretKey, t.PathStyle = "", "", "", "", "", false
+	default:
+		return fmt.Errorf("%s: type must be s3 or sftp", t.Name)
+	}
+	return nil
+}
+
+// loadBackupTargetsLocked reads the stored targets. Callers hold
+// m.backupTargetsMu.
+func (m *Manager) loadBackupTargetsLocked() ([]BackupTarget, error) {
+	data, err := m.storage().Load(storeDocBackupTargets)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []BackupTarget{}, nil
+		}
+		return nil, fmt.Errorf("failed to read backup targets: %w", err)
+	}
+	var targets []BackupTarget
+	if err := json.Unmarshal(data, &targets); err != nil {
+		return nil, fmt.Errorf("failed to parse backup targets: %w", err)
+	}
+	return targets, nil
+}
+
+func (m *Manager) backupTargets() ([]BackupTarget, error) {
+	m.backupTargetsMu.Lock()
+	defer m.backupTargetsMu.Unlock()
+	return m.loadBackupTargetsLocked()
+}
+
+func (m *Manager) backupTarget(id string) (BackupTarget, error) {
+	targets, err := m.backupTargets()
+	if err != nil {
+		return BackupTarget{}, err
+	}
+	for _, t := range targets {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return BackupTarget{}, fmt.Errorf("backup target %s not found", id)
+}
+
+// ListBackupTargets returns the configured targets without their secrets.
+func (m *Manager) ListBackupTargets() ([]BackupTarget, error) {
+	targets, err := m.backupTargets()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]BackupTarget, len(targets))
+	for i, t := range targets {
+		out[i] = t.redacted()
+	}
+	return out, nil
+}
+
+// SetBackupTargets replaces the configured targets. A target sent without
+// a secret keeps the one stored under its ID. Servers stop using targets
+// that were removed.
+func (m *Manager) SetBackupTargets(targets []BackupTarget) ([]BackupTarget, error) {
+	if len(targets) > maxBackupTargets {
+		return nil, fmt.Errorf("at most %d backup targets are allowed", maxBackupTargets)
+	}
+	m.backupTargetsMu.Lock()
+	current, err := m.loadBackupTargetsLocked()
+	if err != nil {
+		m.backupTargetsMu.Unlock()
+		return nil, err
+	}
+	existing := make(map[string]BackupTarget, len(current))
+	for _, t := range current {
+		existing[t.ID] = t
+	}
+
+	seen := make(map[string]bool, len(targets))
+	for i := range targets {
+		t := &targets[i]
+		if err := validateBackupTarget(t); err != nil {
+			m.backupTargetsMu.Unlock()
+			return nil, err
+		}
+		t.ID = slugID(t.ID)
+		if t.ID == "" {
+			t.ID = slugID(t.Name)
+		}
+		if t.ID == "" || seen[t.ID] {
+			m.backupTargetsMu.Unlock()
+			return nil, fmt.Errorf("%s: choose a unique name", t.Name)
+		}
+		seen[t.ID] = true
+		if old, ok := existing[t.ID]; ok && old.Type == t.Type && !t.hasSecret() {
+			t.SecretKey, t.Password, t.PrivateKey = old.SecretKey, old.Password, old.PrivateKey
+		}
+		if t.Type == BackupTargetS3 && t.SecretKey == "" {
+			m.backupTargetsMu.Unlock()
+			return nil, fmt.Errorf("%s: secret key is required", t.Name)
+		}
+		if t.Type == BackupTargetSFTP && t.Password == "" && t.PrivateKey == "" {
+			m.backupTargetsMu.Unlock()
+			return nil, fmt.Errorf("%s: a password or private key is required", t.Name)
+		}
+		if t.PrivateKey != "" {
+			if _, err := parseSFTPPrivateKey(t.PrivateKey); err != nil {
+				m.backupTargetsMu.Unlock()
+				return nil, fmt.Errorf("%s: %w", t.Name, err)
+			}
+		}
+		t.HasSecret = false
+	}
+
+	data, err := json.MarshalIndent(targets, "", "  ")
+	if err == nil {
+		err = m.storage().Save(storeDocBackupTargets, data)
+	}
+	m.backupTargetsMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	changed := false
+	for _, cfg := range m.configs {
+		kept := cfg.BackupTargets[:0]
+		for _, id := range cfg.BackupTargets {
+			if seen[id] {
+				kept = append(kept, id)
+			}
+		}
+		if len(kept) != len(cfg.BackupTargets) {
+			cfg.BackupTargets = kept
+			changed = true
+		}
+	}
+	if changed {
+		m.persist()
+	}
+	m.mu.Unlock()
+
+	return m.ListBackupTargets()
+}
+
+// TestBackupTarget uploads and removes a small file to check that a target
+// is reachable and writable.
+func (m *Manager) TestBackupTarget(ctx context.Context, id string) error {
+	t, err := m.backupTarget(id)
+	if err != nil {
+		return err
+	}
+	up, err := t.uploader()
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp("", "orexa-target-test-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	content := fmt.Sprintf("Orexa Panel connection test %s\n", time.Now().UTC().Format(time.RFC3339))
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	remote := t.remotePath("", ".orexa-connection-test")
+	if err := up.upload(ctx, tmp.Name(), remote, int64(len(content)), func(int64) {}); err != nil {
+		return err
+	}
+	return up.remove(ctx, remote)
+}
+
+// GetServerBackupTargets returns the IDs of the targets a server's backups
+// are uploaded to.
+func (m *Manager) GetServerBackupTargets(id string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{}, cfg.BackupTargets...), nil
+}
+
+// SetServerBackupTargets chooses the targets a server's scheduled backups
+// are uploaded to. An empty list keeps backups local only.
+func (m *Manager) SetServerBackupTargets(id string, targetIDs []string) ([]string, error) {
+	targets, err := m.backupTargets()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		known[t.ID] = true
+	}
+	selected := make([]string, 0, len(targetIDs))
+	seen := make(map[string]bool, len(targetIDs))
+	for _, tid := range targetIDs {
+		tid = strings.TrimSpace(tid)
+		if !known[tid] {
+			return nil, fmt.Errorf("backup target %s not found", tid)
+		}
+		if !seen[tid] {
+			seen[tid] = true
+			selected = append(selected, tid)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	cfg.BackupTargets = selected
+	if err := m.persist(); err != nil {
+		return nil, err
+	}
+	return append([]string{}, selected...), nil
+}
+
+// backupUploads returns a backup's upload states for the backup list. An
+// upload left pending or running by a panel restart shows as failed.
+func (m *Manager) backupUploads(cfg *ServerConfig, name string) []BackupUpload {
+	m.mu.RLock()
+	uploads := append([]BackupUpload(nil), cfg.BackupUploads[name]...)
+	m.mu.RUnlock()
+	for i, u := range uploads {
+		if u.Status != BackupUploadPending && u.Status != BackupUploadUploading {
+			continue
+		}
+		if job, err := m.GetJob(u.JobID); err != nil || job.finished() {
+			uploads[i].Status = BackupUploadFailed
+			uploads[i].Error = "Upload was interrupted"
+		}
+	}
+	return uploads
+}
+
+// setBackupUpload records the state of a backup on one target.
+func (m *Manager) setBackupUpload(serverID, backup string, upload BackupUpload) {
+	upload.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg, ok := m.configs[serverID]
+	if !ok {
+		return
+	}
+	if cfg.BackupUploads == nil {
+		cfg.BackupUploads = make(map[string][]BackupUpload)
+	}
+	list := cfg.BackupUploads[backup]
+	for i := range list {
+		if list[i].Target == upload.Target {
+			list[i] = upload
+			m.persist()
+			return
+		}
+	}
+	cfg.BackupUploads[backup] = append(list, upload)
+	m.persist()
+}
+
+// forgetBackupUploads drops the upload states of a deleted backup.
+func (m *Manager) forgetBackupUploads(serverID, backup string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cfg, ok := m.configs[serverID]; ok {
+		if _, ok := cfg.BackupUploads[backup]; ok {
+			delete(cfg.BackupUploads, backup)
+			m.persist()
+		}
+	}
+}
+
+// UploadBackup copies a full backup archive to the server's selected
+// targets in a background job. Incremental snapshots stay local.
+func (m *Manager) UploadBackup(id, name string) (*Job, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	var targetIDs []string
+	if err == nil {
+		targetIDs = append(targetIDs, cfg.BackupTargets...)
+	}
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if len(targetIDs) == 0 {
+		return nil, fmt.Errorf("no backup targets are selected for this server")
+	}
+	localPath, incremental, err := m.resolveBackup(cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	if incremental {
+		return nil, fmt.Errorf("incremental snapshots are kept locally and cannot be uploaded")
+	}
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]BackupTarget, 0, len(targetIDs))
+	for _, tid := range targetIDs {
+		t, err := m.backupTarget(tid)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, t)
+	}
+
+	job := m.newJob(JobTypeBackupUpload, id)
+	for _, t := range targets {
+		m.setBackupUpload(id, name, BackupUpload{Target: t.ID, TargetName: t.Name, Status: BackupUploadPending, JobID: job.id})
+	}
+	folder := m.backupFolderKey(cfg)
+	go func() {
+		err := m.uploadBackupJob(job, id, name, localPath, folder, info.Size(), targets)
+		if err != nil {
+			log.Printf("[%s] Backup upload failed: %v", cfg.Name, err)
+		}
+		job.finish(err)
+	}()
+	return m.GetJob(job.id)
+}
+
+func (m *Manager) uploadBackupJob(job *jobHandle, serverID, name, localPath, folder string, size int64, targets []BackupTarget) error {
+	job.start(fmt.Sprintf("Uploading %s", name))
+	total := size * int64(len(targets))
+	var failed []string
+	for i, t := range targets {
+		status := BackupUpload{Target: t.ID, TargetName: t.Name, Status: BackupUploadUploading, JobID: job.id}
+		m.setBackupUpload(serverID, name, status)
+		job.log(fmt.Sprintf("Uploading to %s", t.Name))
+
+		base := size * int64(i)
+		var done int64
+		throttled := throttledReport(&done, total, func(done, total int64) {
+			job.transfer(done, total, 0, 100)
+		})
+		err := func() error {
+			up, err := t.uploader()
+			if err != nil {
+				return err
+			}
+			return up.upload(job.ctx, localPath, t.remotePath(folder, name), size, func(n int64) {
+				done = base + n
+				throttled()
+			})
+		}()
+		if err != nil {
+			if job.ctx.Err() != nil {
+				err = job.ctx.Err()
+			}
+			status.Status = BackupUploadFailed
+			status.Error = err.Error()
+			m.setBackupUpload(serverID, name, status)
+			job.log(fmt.Sprintf("Upload to %s failed: %v", t.Name, err))
+			failed = append(failed, t.Name)
+			if job.ctx.Err() != nil {
+				m.failPendingUploads(serverID, name, targets[i+1:], job)
+				return job.ctx.Err()
+			}
+			continue
+		}
+		status.Status = BackupUploadUploaded
+		m.setBackupUpload(serverID, name, status)
+		job.log(fmt.Sprintf("Uploaded to %s", t.Name))
+	}
+	job.transfer(total, total, 0, 100)
+	if len(failed) > 0 {
+		return fmt.Errorf("upload to %s failed", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// failPendingUploads marks the targets a cancelled job never reached.
+func (m *Manager) failPendingUploads(serverID, name string, targets []BackupTarget, job *jobHandle) {
+	for _, t := range targets {
+		m.setBackupUpload(serverID, name, BackupUpload{Target: t.ID, TargetName: t.Name, Status: BackupUploadFailed, Error: "Upload was cancelled", JobID: job.id})
+	}
+}
@@ -0,0 +1,223 @@
+package minecraft
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 stores objects in memory and accepts multipart uploads.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]string
+	parts   map[string]string
+	auth    []string
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	f := &fakeS3{objects: map[string]string{}, parts: map[string]string{}}
+	srv := httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeS3) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+	body, _ := io.ReadAll(r.Body)
+	key := r.URL.Path
+	q := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		io.WriteString(w, "<InitiateMultipartUploadResult><UploadId>up-1</UploadId></InitiateMultipartUploadResult>")
+	case r.Method == http.MethodPut && q.Get("uploadId") != "":
+		f.parts[q.Get("partNumber")] = string(body)
+		w.Header().Set("ETag", `"etag-`+q.Get("partNumber")+`"`)
+	case r.Method == http.MethodPost && q.Get("uploadId") != "":
+		var joined strings.Builder
+		for i := 1; i <= len(f.parts); i++ {
+			joined.WriteString(f.parts[strconv.Itoa(i)])
+		}
+		f.objects[key] = joined.String()
+		io.WriteString(w, "<CompleteMultipartUploadResult><Key>x</Key></CompleteMultipartUploadResult>")
+	case r.Method == http.MethodPut:
+		f.objects[key] = string(body)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestS3UploaderSignsAndUploadsInParts(t *testing.T) {
+	fake, srv := newFakeS3(t)
+	src := filepath.Join(t.TempDir(), "backup.tar.gz")
+	content := strings.Repeat("abcdefghij", 25)
+	if err := os.WriteFile(src, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+	up := newS3Uploader(BackupTarget{Type: BackupTargetS3, Endpoint: srv.URL, Bucket: "mc", AccessKey: "AKID", SecretKey: "secret", PathStyle: true})
+
+	var reported int64
+	if err := up.upload(context.Background(), src, "panel/lobby/backup.tar.gz", int64(len(content)), func(n int64) { reported = n }); err != nil {
+		t.Fatalf("single upload failed: %v", err)
+	}
+	if got := fake.objects["/mc/panel/lobby/backup.tar.gz"]; got != content {
+		t.Fatalf("unexpected object of %d bytes", len(got))
+	}
+	if reported != int64(len(content)) {
+		t.Fatalf("expected progress to reach %d, got %d", len(content), reported)
+	}
+	if auth := fake.auth[0]; !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/us-east-1/s3/aws4_request") {
+		t.Fatalf("unexpected authorization header %q", auth)
+	}
+
+	old := s3PartSize
+	s3PartSize = 100
+	defer func() { s3PartSize = old }()
+	if err := up.upload(context.Background(), src, "panel/lobby/big.tar.gz", int64(len(content)), func(int64) {}); err != nil {
+		t.Fatalf("multipart upload failed: %v", err)
+	}
+	if len(fake.parts) != 3 || fake.objects["/mc/panel/lobby/big.tar.gz"] != content {
+		t.Fatalf("expected 3 parts joined into the object, got %d parts", len(fake.parts))
+	}
+}
+
+func TestSetBackupTargetsKeepsSecretsAndRedacts(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+	mgr.mu.Lock()
+	mgr.configs["lobby"] = &ServerConfig{ID: "lobby", Name: "Lobby", Dir: filepath.Join(mgr.serversRoot, "Lobby")}
+	mgr.mu.Unlock()
+
+	if _, err := mgr.SetBackupTargets([]BackupTarget{{Name: "Box", Type: BackupTargetSFTP, Host: "backup.example", User: "mc"}}); err == nil {
+		t.Fatalf("expected an SFTP target without credentials to be refused")
+	}
+	if _, err := mgr.SetBackupTargets([]BackupTarget{{Name: "Bad", Type: "ftp"}}); err == nil {
+		t.Fatalf("expected an unknown type to be refused")
+	}
+
+	listed, err := mgr.SetBackupTargets([]BackupTarget{{Name: "Offsite S3", Type: BackupTargetS3, Bucket: "mc", AccessKey: "AKID", SecretKey: "secret"}})
+	if err != nil {
+		t.Fatalf("SetBackupTargets failed: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != "offsite-s3" || listed[0].SecretKey != "" || !listed[0].HasSecret {
+		t.Fatalf("expected a redacted target, got %+v", listed)
+	}
+	if _, err := mgr.SetServerBackupTargets("lobby", []string{"offsite-s3"}); err != nil {
+		t.Fatalf("SetServerBackupTargets failed: %v", err)
+	}
+	if _, err := mgr.SetServerBackupTargets("lobby", []string{"missing"}); err == nil {
+		t.Fatalf("expected an unknown target to be refused")
+	}
+
+	// Saving the redacted list back keeps the stored secret.
+	listed[0].Bucket = "mc-archive"
+	if _, err := mgr.SetBackupTargets(listed); err != nil {
+		t.Fatalf("resaving targets failed: %v", err)
+	}
+	stored, err := mgr.backupTarget("offsite-s3")
+	if err != nil || stored.SecretKey != "secret" || stored.Bucket != "mc-archive" {
+		t.Fatalf("expected the secret to survive, got %+v (%v)", stored, err)
+	}
+
+	if _, err := mgr.SetBackupTargets(nil); err != nil {
+		t.Fatalf("clearing targets failed: %v", err)
+	}
+	if ids, _ := mgr.GetServerBackupTargets("lobby"); len(ids) != 0 {
+		t.Fatalf("expected removed targets to be unselected, got %v", ids)
+	}
+}
+
+func TestUploadBackupRecordsStatus(t *testing.T) {
+	fake, srv := newFakeS3(t)
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "lobby", Name: "Lobby", Dir: filepath.Join(mgr.serversRoot, "Lobby")}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.mu.Unlock()
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		t.Fatalf("failed to create server dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Dir, "server.properties"), []byte("motd=Hi\n"), 0644); err != nil {
+		t.Fatalf("failed to write server.properties: %v", err)
+	}
+	backup, err := mgr.CreateBackup(cfg.ID)
+	if err != nil {
+		t.Fatalf("CreateBackup failed: %v", err)
+	}
+
+	if _, err := mgr.UploadBackup(cfg.ID, backup.Name); err == nil {
+		t.Fatalf("expected an upload without targets to be refused")
+	}
+	if _, err := mgr.SetBackupTargets([]BackupTarget{{Name: "Fake", Type: BackupTargetS3, Endpoint: srv.URL, Bucket: "mc", AccessKey: "AKID", SecretKey: "secret", PathStyle: true, Path: "panel"}}); err != nil {
+		t.Fatalf("SetBackupTargets failed: %v", err)
+	}
+	if _, err := mgr.SetServerBackupTargets(cfg.ID, []string{"fake"}); err != nil {
+		t.Fatalf("SetServerBackupTargets failed: %v", err)
+	}
+
+	job, err := mgr.UploadBackup(cfg.ID, backup.Name)
+	if err != nil {
+		t.Fatalf("UploadBackup failed: %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		current, err := mgr.GetJob(job.ID)
+		if err != nil {
+			t.Fatalf("GetJob failed: %v", err)
+		}
+		if current.finished() {
+			if current.State != JobStateSucceeded {
+				t.Fatalf("upload job ended %s: %s", current.State, current.Error)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("upload job did not finish")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	fake.mu.Lock()
+	_, uploaded := fake.objects["/mc/panel/lobby/"+backup.Name]
+	fake.mu.Unlock()
+	if !uploaded {
+		t.Fatalf("expected the archive under panel/lobby")
+	}
+	backups, err := mgr.ListBackups(cfg.ID)
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(backups) != 1 || len(backups[0].Uploads) != 1 || backups[0].Uploads[0].Status != BackupUploadUploaded || backups[0].Uploads[0].TargetName != "Fake" {
+		t.Fatalf("unexpected upload status: %+v", backups)
+	}
+
+	if err := mgr.DeleteBackup(cfg.ID, backup.Name); err != nil {
+		t.Fatalf("DeleteBackup failed: %v", err)
+	}
+	mgr.mu.RLock()
+	_, kept := cfg.BackupUploads[backup.Name]
+	mgr.mu.RUnlock()
+	if kept {
+		t.Fatalf("expected the upload record to go with the backup")
+	}
+}
@@ -0,0 +1,456 @@
+package minecraft
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBenchmarkRadius   = 512
+	minBenchmarkRadius       = 128
+	maxBenchmarkRadius       = 2048
+	defaultBenchmarkDuration = 60
+	minBenchmarkDuration     = 30
+	maxBenchmarkDuration     = 600
+	benchmarkBootTimeout     = 10 * time.Minute
+	benchmarkSampleInterval  = 5 * time.Second
+	// benchmarkSeed is used when server.properties has no level-seed, so
+	// both runs generate the same terrain.
+	benchmarkSeed = "orexa-benchmark"
+)
+
+var (
+	// paperMSPTPattern matches the numbers line of Paper's mspt command,
+	// "◴ 2.3/1.1/4.5, 2.4/1.0/7.8, 2.5/0.9/20.1"; the first value is the
+	// 5 second average.
+	paperMSPTPattern = regexp.MustCompile(`([0-9]+(?:\.[0-9]+)?)/[0-9]+(?:\.[0-9]+)?/[0-9]+(?:\.[0-9]+)?, [0-9]`)
+	// tickQueryPattern matches vanilla's "tick query" (1.20.3+).
+	tickQueryPattern = regexp.MustCompile(`Average time per tick: ([0-9]+(?:\.[0-9]+)?) ?ms`)
+	forgeMSPTPattern = regexp.MustCompile(`(?i)overall:.*mean tick time: ([0-9]+(?:\.[0-9]+)?) ?ms`)
+)
+
+// BenchmarkOptions configures a flag preset benchmark.
+type BenchmarkOptions struct {
+	// Presets are the two JVM flag presets to compare, run in order.
+	Presets []string `json:"presets"`
+	// Radius in blocks around 0,0 that is force-loaded as load.
+	Radius int `json:"radius"`
+	// Duration in seconds that tick times are sampled under load.
+	Duration int `json:"duration"`
+}
+
+// BenchmarkRun is how one preset did. Tick times are in milliseconds.
+type BenchmarkRun struct {
+	Preset      string  `json:"preset"`
+	BootSeconds float64 `json:"bootSeconds"`
+	AvgMSPT     float64 `json:"avgMspt,omitempty"`
+	MaxMSPT     float64 `json:"maxMspt,omitempty"`
+	AvgTPS      float64 `json:"avgTps,omitempty"`
+	MinTPS      float64 `json:"minTps,omitempty"`
+	Samples     int     `json:"samples"`
+	Error       string  `json:"error,omitempty"`
+}
+
+// BenchmarkReport is the result of a benchmark job.
+type BenchmarkReport struct {
+	Radius   int            `json:"radius"`
+	Duration int            `json:"duration"`
+	Runs     []BenchmarkRun `json:"runs"`
+	Summary  string         `json:"summary"`
+}
+
+func normalizeBenchmarkOptions(opts *BenchmarkOptions) error {
+	if len(opts.Presets) == 0 {
+		opts.Presets = []string{"aikars", "zgc"}
+	}
+	if len(opts.Presets) != 2 {
+		return fmt.Errorf("pick exactly two flag presets to compare")
+	}
+	for i, preset := range opts.Presets {
+		preset = strings.ToLower(strings.TrimSpace(preset))
+		if !containsString(jvmFlagPresets, preset) {
+			return fmt.Errorf("unknown flag preset %q; use one of %s", preset, strings.Join(jvmFlagPresets, ", "))
+		}
+		opts.Presets[i] = preset
+	}
+	if opts.Presets[0] == opts.Presets[1] {
+		return fmt.Errorf("pick two different flag presets")
+	}
+	if opts.Radius == 0 {
+		opts.Radius = defaultBenchmarkRadius
+	}
+	if opts.Radius < minBenchmarkRadius || opts.Radius > maxBenchmarkRadius {
+		return fmt.Errorf("radius must be between %d and %d blocks", minBenchmarkRadius, maxBenchmarkRadius)
+	}
+	if opts.Duration == 0 {
+		opts.Duration = defaultBenchmarkDuration
+	}
+	if opts.Duration < minBenchmarkDuration || opts.Duration > maxBenchmarkDuration {
+		return fmt.Errorf("duration must be between %d and %d seconds", minBenchmarkDuration, maxBenchmarkDuration)
+	}
+	return nil
+}
+
+// benchmarkLoadCommands force-loads every chunk within radius blocks of
+// 0,0, in squares of 16 by 16 chunks since one forceload command takes at
+// most 256 chunks.
+func benchmarkLoadCommands(radius int) []string {
+	r := (radius + 15) / 16
+	var commands []string
+	for x := -r; x <= r; x += 16 {
+		for z := -r; z <= r; z += 16 {
+			x2, z2 := min(x+15, r), min(z+15, r)
+			commands = append(commands, fmt.Sprintf("forceload add %d %d %d %d", x*16, z*16, x2*16+15, z2*16+15))
+		}
+	}
+	return commands
+}
+
+// benchmarkSampleCommands returns the console commands that report tick
+// times on serverType.
+func benchmarkSampleCommands(serverType string) []string {
+	switch strings.ToLower(serverType) {
+	case "paper", "purpur", "folia":
+		return []string{"mspt", "tps"}
+	case "spigot":
+		return []string{"tps"}
+	}
+	commands := []string{"tick query"}
+	if tps, ok := tpsCommandForType(serverType); ok {
+		commands = append(commands, tps)
+	}
+	return commands
+}
+
+func parseBenchmarkMSPT(line string) (float64, bool) {
+	for _, pattern := range []*regexp.Regexp{tickQueryPattern, forgeMSPTPattern, paperMSPTPattern} {
+		if match := pattern.FindStringSubmatch(line); match != nil {
+			if v, err := strconv.ParseFloat(match[1], 64); err == nil {
+				return v, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func parseBenchmarkTPS(line string) (float64, bool) {
+	if match := tpsPattern.FindStringSubmatch(line); match != nil {
+		v, err := strconv.ParseFloat(match[1], 64)
+		return v, err == nil
+	}
+	if match := forgeTpsPattern.FindStringSubmatch(line); match != nil {
+		text := match[1]
+		if text == "" {
+			text = match[2]
+		}
+		v, err := strconv.ParseFloat(text, 64)
+		return v, err == nil
+	}
+	// A single dimension's TPS is not the server's.
+	if _, ok := parseDimensionTick(line); ok {
+		return 0, false
+	}
+	if match := simpleTpsPattern.FindStringSubmatch(line); match != nil {
+		v, err := strconv.ParseFloat(match[1], 64)
+		return v, err == nil
+	}
+	return 0, false
+}
+
+// summarize fills in the run's averages from its samples. Without a TPS
+// command, TPS is derived from the tick time.
+func (run *BenchmarkRun) summarize(mspt, tps []float64) {
+	if len(tps) == 0 {
+		for _, v := range mspt {
+			if v > 0 {
+				tps = append(tps, min(20, 1000/v))
+			}
+		}
+	}
+	run.Samples = max(len(mspt), len(tps))
+	if len(mspt) > 0 {
+		sum := 0.0
+		for _, v := range mspt {
+			sum += v
+			run.MaxMSPT = max(run.MaxMSPT, v)
+		}
+		run.AvgMSPT = sum / float64(len(mspt))
+	}
+	if len(tps) > 0 {
+		sum := 0.0
+		run.MinTPS = tps[0]
+		for _, v := range tps {
+			sum += v
+			run.MinTPS = min(run.MinTPS, v)
+		}
+		run.AvgTPS = sum / float64(len(tps))
+	}
+}
+
+// compareBenchmarkRuns words the outcome of the two runs.
+func compareBenchmarkRuns(a, b BenchmarkRun) string {
+	for _, run := range []BenchmarkRun{a, b} {
+		if run.Error != "" {
+			return fmt.Sprintf("%s did not complete: %s", run.Preset, run.Error)
+		}
+	}
+	boot := fmt.Sprintf("Boot took %.1fs with %s and %.1fs with %s.", a.BootSeconds, a.Preset, b.BootSeconds, b.Preset)
+	switch {
+	case a.AvgMSPT > 0 && b.AvgMSPT > 0:
+		best, other := a, b
+		if b.AvgMSPT < a.AvgMSPT {
+			best, other = b, a
+		}
+		gain := (other.AvgMSPT - best.AvgMSPT) / other.AvgMSPT * 100
+		return fmt.Sprintf("%s ticked %.0f%% faster than %s under load (%.1f vs %.1f ms per tick). %s",
+			best.Preset, gain, other.Preset, best.AvgMSPT, other.AvgMSPT, boot)
+	case a.AvgTPS > 0 && b.AvgTPS > 0:
+		best, other := a, b
+		if b.AvgTPS > a.AvgTPS {
+			best, other = b, a
+		}
+		return fmt.Sprintf("%s held %.2f TPS under load against %.2f for %s. %s", best.Preset, best.AvgTPS, other.AvgTPS, other.Preset, boot)
+	}
+	return "The server reported no tick times, so only boot times can be compared. " + boot
+}
+
+// StartBenchmark queues a job that boots a stopped server once with each
+// of two flag presets, loads chunks around spawn and compares boot time
+// and tick times. Each run uses a fresh world in a scratch folder, so the
+// server's own world is not touched.
+func (m *Manager) StartBenchmark(id string, opts BenchmarkOptions) (*Job, error) {
+	if err := normalizeBenchmarkOptions(&opts); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	rs, rsOk := m.running[id]
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if !rsOk {
+		return nil, errServerNotFound(id)
+	}
+	if isProxyType(cfg.Type) {
+		return nil, fmt.Errorf("proxy servers have no worlds to load")
+	}
+	if err := m.validateManagedServerDir(cfg.Dir); err != nil {
+		return nil, m.configPathErrorLocked(id, err.Error())
+	}
+
+	rs.mu.Lock()
+	if rs.status != "Stopped" && rs.status != "Crashed" && rs.status != "Error" {
+		status := rs.status
+		rs.mu.Unlock()
+		return nil, fmt.Errorf("server must be stopped before benchmarking (status: %s)", status)
+	}
+	// Installing keeps the server from being started while the benchmark
+	// has it, as during a world upgrade.
+	rs.status = "Installing"
+	rs.installError = ""
+	rs.mu.Unlock()
+
+	job := m.newJob(JobTypeBenchmark, id)
+	go func() {
+		err := m.benchmarkJob(job, cfg, rs, opts)
+		rs.mu.Lock()
+		rs.status = "Stopped"
+		rs.mu.Unlock()
+		if err != nil {
+			log.Printf("[%s] Benchmark failed: %v", cfg.Name, err)
+		}
+		job.finish(err)
+	}()
+	return m.GetJob(job.id)
+}
+
+func (m *Manager) benchmarkJob(job *jobHandle, cfg *ServerConfig, rs *runningServer, opts BenchmarkOptions) error {
+	release, err := m.acquireServerOperation(job.ctx, cfg.ID, operationBenchmark)
+	if err != nil {
+		return err
+	}
+	defer release()
+	job.start("Preparing the benchmark")
+
+	say := func(msg string) {
+		m.broadcastLog(rs, m.appendLog(rs, "[Benchmark] "+msg))
+	}
+
+	propsPath := filepath.Join(cfg.Dir, "server.properties")
+	if seed, ok := parseServerPropertiesFile(propsPath)["level-seed"]; !ok || seed == "" {
+		if err := setServerProperties(propsPath, map[string]string{"level-seed": benchmarkSeed}); err != nil {
+			return fmt.Errorf("failed to set a benchmark seed: %w", err)
+		}
+		defer setServerProperties(propsPath, map[string]string{"level-seed": seed})
+	}
+	if len(cfg.StartCommand) > 0 {
+		// The runs rewrite user_jvm_args.txt; put the server's own preset back.
+		defer writeManagedUserJVMArgs(filepath.Join(cfg.Dir, "user_jvm_args.txt"), managedJVMArgs(cfg))
+	}
+
+	report := &BenchmarkReport{Radius: opts.Radius, Duration: opts.Duration}
+	for i, preset := range opts.Presets {
+		from, to := i*50, (i+1)*50
+		say(fmt.Sprintf("Run %d of 2: %s flags", i+1, preset))
+		job.log(fmt.Sprintf("Run %d of 2: %s flags", i+1, preset))
+		run := m.benchmarkRun(job, cfg, preset, opts, from, to, say)
+		if err := job.ctx.Err(); err != nil {
+			say("Benchmark cancelled")
+			return err
+		}
+		if run.Error != "" {
+			job.log(fmt.Sprintf("%s: %s", preset, run.Error))
+		} else {
+			job.log(fmt.Sprintf("%s: boot %.1fs, %.1f ms per tick, %.2f TPS over %d samples", preset, run.BootSeconds, run.AvgMSPT, run.AvgTPS, run.Samples))
+		}
+		report.Runs = append(report.Runs, run)
+	}
+	report.Summary = compareBenchmarkRuns(report.Runs[0], report.Runs[1])
+	job.setResult(report)
+	job.progress(100, report.Summary)
+	say(report.Summary)
+	return nil
+}
+
+// benchmarkRun boots the server with one preset and measures it. Failures
+// are reported in the run, so the other preset still gets its turn.
+func (m *Manager) benchmarkRun(job *jobHandle, cfg *ServerConfig, preset string, opts BenchmarkOptions, from, to int, say func(string)) BenchmarkRun {
+	run := BenchmarkRun{Preset: preset}
+	universe := ".benchmark-" + preset
+	universePath := filepath.Join(cfg.Dir, universe)
+	os.RemoveAll(universePath)
+	defer os.RemoveAll(universePath)
+
+	runCfg := *cfg
+	runCfg.Flags = preset
+	cmd, err := m.serverLaunchCommand(&runCfg, "--universe", universe)
+	if err != nil {
+		run.Error = err.Error()
+		return run
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		run.Error = fmt.Sprintf("failed to create stdin pipe: %v", err)
+		return run
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		run.Error = fmt.Sprintf("failed to create stdout pipe: %v", err)
+		return run
+	}
+	cmd.Stderr = cmd.Stdout
+	started := time.Now()
+	if err := cmd.Start(); err != nil {
+		run.Error = fmt.Sprintf("failed to start server: %v", err)
+		return run
+	}
+	pid := cmd.Process.Pid
+	log.Printf("[%s] Benchmark run with %s flags started (PID: %d)", cfg.Name, preset, pid)
+	job.progress(from, fmt.Sprintf("Booting with %s flags", preset))
+
+	lines := make(chan string)
+	output := lines
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	send := func(command string) { io.WriteString(stdin, command+"\n") }
+	var mspt, tps []float64
+	booted, stopping := false, false
+	bootTimeout := time.After(benchmarkBootTimeout)
+	var sampleTick <-chan time.Time
+	var loadDone, stopDeadline <-chan time.Time
+	var loadStarted time.Time
+	stop := func() {
+		stopping = true
+		sampleTick = nil
+		send("stop")
+		stopDeadline = time.After(worldUpgradeStopTimeout)
+	}
+
+	for lines != nil {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				lines = nil
+				continue
+			}
+			say(line)
+			clean := mcColorPattern.ReplaceAllString(ansiPattern.ReplaceAllString(line, ""), "")
+			if !booted && strings.Contains(clean, "Done (") {
+				booted = true
+				bootTimeout = nil
+				run.BootSeconds = time.Since(started).Seconds()
+				job.progress(from+(to-from)/5, fmt.Sprintf("Booted with %s flags in %.1fs, loading chunks", preset, run.BootSeconds))
+				for _, command := range benchmarkLoadCommands(opts.Radius) {
+					send(command)
+				}
+				ticker := time.NewTicker(benchmarkSampleInterval)
+				defer ticker.Stop()
+				sampleTick = ticker.C
+				loadStarted = time.Now()
+				loadDone = time.After(time.Duration(opts.Duration) * time.Second)
+				continue
+			}
+			if booted && !stopping {
+				if v, ok := parseBenchmarkMSPT(clean); ok {
+					mspt = append(mspt, v)
+				} else if v, ok := parseBenchmarkTPS(clean); ok {
+					tps = append(tps, v)
+				}
+			}
+		case <-sampleTick:
+			for _, command := range benchmarkSampleCommands(cfg.Type) {
+				send(command)
+			}
+			elapsed := time.Since(loadStarted).Seconds() / float64(opts.Duration)
+			job.progress(from+(to-from)/5+int(elapsed*float64(to-from)*3/5), fmt.Sprintf("Sampling tick times with %s flags", preset))
+		case <-loadDone:
+			loadDone = nil
+			job.progress(to-(to-from)/5, fmt.Sprintf("Stopping the %s run", preset))
+			stop()
+		case <-bootTimeout:
+			run.Error = fmt.Sprintf("server did not finish booting within %s", benchmarkBootTimeout)
+			killServerProcessTree(pid)
+			lines = nil
+		case <-job.ctx.Done():
+			killServerProcessTree(pid)
+			lines = nil
+		case <-stopDeadline:
+			log.Printf("[%s] Benchmark server did not stop, killing it", cfg.Name)
+			killServerProcessTree(pid)
+			lines = nil
+		}
+	}
+	// Drain what is left so the reader finishes before Wait closes the pipe.
+	for range output {
+	}
+	waitErr := cmd.Wait()
+
+	switch {
+	case run.Error != "":
+	case !booted:
+		run.Error = "server exited before it finished booting"
+		if waitErr != nil {
+			run.Error += ": " + waitErr.Error()
+		}
+	case !stopping:
+		run.Error = "server stopped during the load test"
+	}
+	run.summarize(mspt, tps)
+	return run
+}
@@ -0,0 +1,84 @@
+package minecraft
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBenchmarkLoadCommandsCoverRadius(t *testing.T) {
+	commands := benchmarkLoadCommands(512)
+	chunks := 0
+	for _, command := range commands {
+		var x1, z1, x2, z2 int
+		if _, err := fmt.Sscanf(command, "forceload add %d %d %d %d", &x1, &z1, &x2, &z2); err != nil {
+			t.Fatalf("unexpected command %q", command)
+		}
+		n := ((x2-x1)/16 + 1) * ((z2-z1)/16 + 1)
+		if n > 256 {
+			t.Fatalf("%q loads %d chunks, more than forceload allows", command, n)
+		}
+		chunks += n
+	}
+	// 32 chunks each way from 0 plus the centre row.
+	if chunks != 65*65 {
+		t.Fatalf("expected 4225 chunks, got %d", chunks)
+	}
+}
+
+func TestParseBenchmarkSamples(t *testing.T) {
+	cases := []struct {
+		line string
+		mspt float64
+		tps  float64
+	}{
+		{line: "[12:00:00 INFO]: ◴ 2.3/1.1/4.5, 2.4/1.0/7.8, 2.5/0.9/20.1", mspt: 2.3},
+		{line: "[12:00:00 INFO]: Average time per tick: 12.5ms (Target: 50.0ms)", mspt: 12.5},
+		{line: "[12:00:00 INFO]: Overall: Mean tick time: 0.755 ms. Mean TPS: 20.000", mspt: 0.755},
+		{line: "[12:00:00 INFO]: TPS from last 1m, 5m, 15m: 19.8, 20.0, 20.0", tps: 19.8},
+	}
+	for _, c := range cases {
+		mspt, okMSPT := parseBenchmarkMSPT(c.line)
+		tps, okTPS := parseBenchmarkTPS(c.line)
+		if c.mspt > 0 && (!okMSPT || mspt != c.mspt) {
+			t.Fatalf("expected %v ms from %q, got %v (%v)", c.mspt, c.line, mspt, okMSPT)
+		}
+		if c.tps > 0 && (!okTPS || tps != c.tps) {
+			t.Fatalf("expected %v TPS from %q, got %v (%v)", c.tps, c.line, tps, okTPS)
+		}
+	}
+}
+
+func TestBenchmarkOptionsAndSummary(t *testing.T) {
+	opts := BenchmarkOptions{}
+	if err := normalizeBenchmarkOptions(&opts); err != nil {
+		t.Fatalf("defaults rejected: %v", err)
+	}
+	if opts.Presets[0] != "aikars" || opts.Presets[1] != "zgc" || opts.Radius != defaultBenchmarkRadius || opts.Duration != defaultBenchmarkDuration {
+		t.Fatalf("unexpected defaults %+v", opts)
+	}
+	for _, bad := range []BenchmarkOptions{
+		{Presets: []string{"aikars", "aikars"}},
+		{Presets: []string{"aikars", "shenandoah"}},
+		{Presets: []string{"aikars", "zgc"}, Radius: 10000},
+	} {
+		if err := normalizeBenchmarkOptions(&bad); err == nil {
+			t.Fatalf("expected %+v to be rejected", bad)
+		}
+	}
+
+	a := BenchmarkRun{Preset: "aikars", BootSeconds: 20}
+	a.summarize([]float64{10, 14}, nil)
+	b := BenchmarkRun{Preset: "zgc", BootSeconds: 22}
+	b.summarize([]float64{8, 8}, nil)
+	if a.AvgMSPT != 12 || a.MaxMSPT != 14 || a.AvgTPS != 20 || a.Samples != 2 {
+		t.Fatalf("unexpected summary %+v", a)
+	}
+	if summary := compareBenchmarkRuns(a, b); !strings.HasPrefix(summary, "zgc ticked 33% faster than aikars") {
+		t.Fatalf("unexpected comparison %q", summary)
+	}
+	b.Error = "server exited before it finished booting"
+	if summary := compareBenchmarkRuns(a, b); !strings.Contains(summary, "zgc did not complete") {
+		t.Fatalf("unexpected comparison %q", summary)
+	}
+}
@@ -0,0 +1,281 @@
+package minecraft
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	bootFailureLogTailLines = 80
+	bootFailureReadLimit    = 256 * 1024
+)
+
+// BootFailureReport gathers what is known about a server that exited before
+// it finished booting.
+type BootFailureReport struct {
+	ServerID     string             `json:"serverId"`
+	StartedAt    string             `json:"startedAt"`
+	FailedAt     string             `json:"failedAt"`
+	ExitError    string             `json:"exitError,omitempty"`
+	Causes       []BootFailureCause `json:"causes"`
+	LogSource    string             `json:"logSource"`
+	LogTail      []string           `json:"logTail"`
+	CrashReport  string             `json:"crashReport,omitempty"`
+	CrashCause   string             `json:"crashCause,omitempty"`
+	InstallError string             `json:"installError,omitempty"`
+	InstallerLog []string           `json:"installerLog,omitempty"`
+	PortConflict *PortConflict      `json:"portConflict,omitempty"`
+}
+
+// BootFailureCause is a recognised reason for a failed boot, with the log
+// line that gave it away.
+type BootFailureCause struct {
+	Code     string `json:"code"`
+	Summary  string `json:"summary"`
+	Evidence string `json:"evidence"`
+}
+
+var bootFailurePatterns = []struct {
+	code    string
+	summary string
+	pattern *regexp.Regexp
+}{
+	{
+		code:    FailureReasonPortInUse,
+		summary: "The server port is already in use by another process.",
+		pattern: portInUsePattern,
+	},
+	{
+		code:    "eula_not_accepted",
+		summary: "The Minecraft EULA has not been accepted in eula.txt.",
+		pattern: regexp.MustCompile(`(?i)you need to agree to the eula|failed to load eula\.txt`),
+	},
+	{
+		code:    "wrong_java",
+		summary: "The selected Java version does not match what this server needs.",
+		pattern: regexp.MustCompile(`(?i)UnsupportedClassVersionError|compiled by a more recent version of the Java Runtime|unsupported java detected|requires running the server with java`),
+	},
+	{
+		code:    "missing_dependency",
+		summary: "A plugin or mod is missing a required dependency.",
+		pattern: regexp.MustCompile(`(?i)UnknownDependencyException|missing or unsupported mandatory dependencies|incompatible mods found|unknown dependency|missing (?:required )?dependenc(?:y|ies)|requires .+ which is missing`),
+	},
+}
+
+// detectBootFailureCauses reports each known cause at most once, in the
+// order the patterns are listed.
+func detectBootFailureCauses(lines []string) []BootFailureCause {
+	causes := make([]BootFailureCause, 0)
+	for _, p := range bootFailurePatterns {
+		for _, line := range lines {
+			if p.pattern.MatchString(line) {
+				causes = append(causes, BootFailureCause{Code: p.code, Summary: p.summary, Evidence: strings.TrimSpace(line)})
+				break
+			}
+		}
+	}
+	return causes
+}
+
+// buildBootFailureReport collects the log tail, any crash report written
+// since startedAt and leftover installer output. latest.log is preferred;
+// the console buffer stands in when the JVM died before writing it.
+func buildBootFailureReport(cfg *ServerConfig, startedAt time.Time, exitErr error, console []string, installError string) *BootFailureReport {
+	report := &BootFailureReport{
+		ServerID:     cfg.ID,
+		StartedAt:    startedAt.UTC().Format(time.RFC3339),
+		FailedAt:     time.Now().UTC().Format(time.RFC3339),
+		InstallError: installError,
+		LogSource:    "console",
+		LogTail:      lastLines(console, bootFailureLogTailLines),
+	}
+	if exitErr != nil {
+		report.ExitError = exitErr.Error()
+	}
+	// File times can be coarser than the clock, so allow a little slack.
+	since := startedAt.Add(-2 * time.Second)
+
+	latestLog := filepath.Join(cfg.Dir, "logs", "latest.log")
+	if info, err := os.Stat(latestLog); err == nil && !info.ModTime().Before(since) {
+		if lines, err := readFileTailLines(latestLog, bootFailureLogTailLines); err == nil && len(lines) > 0 {
+			report.LogSource = "latest.log"
+			report.LogTail = lines
+		}
+	}
+
+	var crashLines []string
+	if name, path := newestCrashReportSince(cfg.Dir, since); name != "" {
+		report.CrashReport = name
+		report.CrashCause = extractCrashCause(path)
+		crashLines, _ = readFileTailLines(path, 0)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(cfg.Dir, "*installer*.log"))
+	sort.Strings(matches)
+	for _, path := range matches {
+		if lines, err := readFileTailLines(path, 20); err == nil {
+			report.InstallerLog = append(report.InstallerLog, lines...)
+		}
+	}
+
+	scan := make([]string, 0, len(console)+len(report.LogTail)+len(crashLines)+len(report.InstallerLog)+1)
+	scan = append(scan, console...)
+	scan = append(scan, report.LogTail...)
+	scan = append(scan, crashLines...)
+	scan = append(scan, report.InstallerLog...)
+	if installError != "" {
+		scan = append(scan, installError)
+	}
+	report.Causes = detectBootFailureCauses(scan)
+	return report
+}
+
+func newestCrashReportSince(serverDir string, since time.Time) (string, string) {
+	crashDir := filepath.Join(serverDir, "crash-reports")
+	entries, err := os.ReadDir(crashDir)
+	if err != nil {
+		return "", ""
+	}
+	var name string
+	var newest time.Time
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".txt") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().Before(since) {
+			continue
+		}
+		if name == "" || info.ModTime().After(newest) {
+			name, newest = entry.Name(), info.ModTime()
+		}
+	}
+	if name == "" {
+		return "", ""
+	}
+	return name, filepath.Join(crashDir, name)
+}
+
+// readFileTailLines returns the last n lines (all when n <= 0) of the final
+// bootFailureReadLimit bytes of a file.
+func readFileTailLines(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := info.Size() - bootFailureReadLimit
+	if offset > 0 {
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	data, err := io.ReadAll(io.LimitReader(f, bootFailureReadLimit))
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		// Drop the partial first line.
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	text := strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if text == "" {
+		return []string{}, nil
+	}
+	return lastLines(strings.Split(text, "\n"), n), nil
+}
+
+func lastLines(lines []string, n int) []string {
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return append([]string(nil), lines...)
+}
+
+func (m *Manager) bootFailureReportPath(serverID string) string {
+	return filepath.Join(m.baseDir, "data", "boot-failures", sanitizeName(serverID)+".json")
+}
+
+// recordBootFailure builds and stores the report for a server whose process
+// exited while it was still booting.
+func (m *Manager) recordBootFailure(cfg *ServerConfig, rs *runningServer, startedAt time.Time, exitErr error, console []string, installError string) {
+	report := buildBootFailureReport(cfg, startedAt, exitErr, console, installError)
+	for i := range report.Causes {
+		if report.Causes[i].Code != FailureReasonPortInUse {
+			continue
+		}
+		rs.mu.RLock()
+		report.PortConflict = rs.portConflict
+		rs.mu.RUnlock()
+		if report.PortConflict == nil {
+			report.PortConflict = m.findPortHolder(cfg.Port)
+		}
+		report.Causes[i].Summary = "The server " + report.PortConflict.Describe() + "."
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err == nil {
+		path := m.bootFailureReportPath(cfg.ID)
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+			tmpFile := path + ".tmp"
+			if err = os.WriteFile(tmpFile, data, 0644); err == nil {
+				err = os.Rename(tmpFile, path)
+			}
+		}
+	}
+	if err != nil {
+		log.Printf("[%s] Failed to save boot failure report: %v", cfg.Name, err)
+	}
+
+	codes := make([]string, 0, len(report.Causes))
+	for _, cause := range report.Causes {
+		codes = append(codes, cause.Code)
+	}
+	if len(codes) > 0 {
+		log.Printf("[%s] Boot failed; likely cause: %s", cfg.Name, strings.Join(codes, ", "))
+	} else {
+		log.Printf("[%s] Boot failed; no known cause detected", cfg.Name)
+	}
+
+	rs.mu.Lock()
+	rs.bootFailedAt = time.Now()
+	rs.mu.Unlock()
+}
+
+// BootFailureReport returns the report from the server's last failed boot,
+// or nil when there is none.
+func (m *Manager) BootFailureReport(id string) (*BootFailureReport, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(m.bootFailureReportPath(cfg.ID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var report BootFailureReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("invalid boot failure report: %w", err)
+	}
+	return &report, nil
+}
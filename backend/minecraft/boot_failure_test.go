@@ -0,0 +1,104 @@
+package minecraft
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectBootFailureCauses(t *testing.T) {
+	cases := map[string]string{
+		"[Server thread/WARN]: **** FAILED TO BIND TO PORT!":                                        "port_in_use",
+		"[Server thread/INFO]: You need to agree to the EULA in order to run the server.":           "eula_not_accepted",
+		"java.lang.UnsupportedClassVersionError: net/minecraft/bundler/Main has been compiled by":   "wrong_java",
+		"org.bukkit.plugin.UnknownDependencyException: Unknown/missing dependency plugins: [Vault]": "missing_dependency",
+		"Incompatible mods found!": "missing_dependency",
+	}
+	for line, want := range cases {
+		causes := detectBootFailureCauses([]string{"[main/INFO]: Loading libraries", line})
+		if len(causes) == 0 || causes[0].Code != want {
+			t.Fatalf("detectBootFailureCauses(%q) = %+v, want %s", line, causes, want)
+		}
+		if causes[0].Evidence != line {
+			t.Fatalf("expected evidence %q, got %q", line, causes[0].Evidence)
+		}
+	}
+	if causes := detectBootFailureCauses([]string{"[Server thread/INFO]: Starting minecraft server"}); len(causes) != 0 {
+		t.Fatalf("expected no causes, got %+v", causes)
+	}
+}
+
+func TestBuildBootFailureReport(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &ServerConfig{ID: "srv1", Name: "Survival", Dir: dir}
+	startedAt := time.Now()
+
+	// A crash report from an earlier run must be ignored.
+	crashDir := filepath.Join(dir, "crash-reports")
+	if err := os.MkdirAll(crashDir, 0755); err != nil {
+		t.Fatalf("failed to create crash dir: %v", err)
+	}
+	oldCrash := filepath.Join(crashDir, "crash-old-server.txt")
+	if err := os.WriteFile(oldCrash, []byte("Description: Old\n"), 0644); err != nil {
+		t.Fatalf("failed to write old crash: %v", err)
+	}
+	old := startedAt.Add(-time.Hour)
+	if err := os.Chtimes(oldCrash, old, old); err != nil {
+		t.Fatalf("failed to age old crash: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(crashDir, "crash-new-server.txt"), []byte("Description: Exception in server tick loop\njava.net.BindException: Address already in use\n"), 0644); err != nil {
+		t.Fatalf("failed to write crash: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "logs"), 0755); err != nil {
+		t.Fatalf("failed to create logs dir: %v", err)
+	}
+	var log strings.Builder
+	for i := 0; i < bootFailureLogTailLines+10; i++ {
+		log.WriteString("[Server thread/INFO]: line\n")
+	}
+	log.WriteString("[Server thread/WARN]: Perhaps a server is already running on that port?\n")
+	if err := os.WriteFile(filepath.Join(dir, "logs", "latest.log"), []byte(log.String()), 0644); err != nil {
+		t.Fatalf("failed to write latest.log: %v", err)
+	}
+
+	report := buildBootFailureReport(cfg, startedAt, errors.New("exit status 1"), []string{"console line"}, "")
+	if report.LogSource != "latest.log" || len(report.LogTail) != bootFailureLogTailLines {
+		t.Fatalf("expected %d lines from latest.log, got %s/%d", bootFailureLogTailLines, report.LogSource, len(report.LogTail))
+	}
+	if !strings.Contains(report.LogTail[len(report.LogTail)-1], "already running on that port") {
+		t.Fatalf("expected tail to end with the last log line, got %q", report.LogTail[len(report.LogTail)-1])
+	}
+	if report.CrashReport != "crash-new-server.txt" || report.CrashCause != "Exception in server tick loop" {
+		t.Fatalf("unexpected crash report %q (%q)", report.CrashReport, report.CrashCause)
+	}
+	if len(report.Causes) != 1 || report.Causes[0].Code != "port_in_use" {
+		t.Fatalf("expected port_in_use from the crash report, got %+v", report.Causes)
+	}
+	if report.ExitError != "exit status 1" {
+		t.Fatalf("unexpected exit error %q", report.ExitError)
+	}
+}
+
+func TestBuildBootFailureReportFallsBackToConsole(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &ServerConfig{ID: "srv1", Name: "Survival", Dir: dir}
+	if err := os.WriteFile(filepath.Join(dir, "forge-installer.jar.log"), []byte("Downloading libraries\nThese libraries failed to download. Try again.\n"), 0644); err != nil {
+		t.Fatalf("failed to write installer log: %v", err)
+	}
+
+	console := []string{"Error: A JNI error has occurred", "java.lang.UnsupportedClassVersionError: compiled by a more recent version of the Java Runtime"}
+	report := buildBootFailureReport(cfg, time.Now(), nil, console, "")
+	if report.LogSource != "console" || len(report.LogTail) != 2 {
+		t.Fatalf("expected console tail, got %s %v", report.LogSource, report.LogTail)
+	}
+	if len(report.InstallerLog) != 2 {
+		t.Fatalf("expected installer log lines, got %v", report.InstallerLog)
+	}
+	if len(report.Causes) != 1 || report.Causes[0].Code != "wrong_java" {
+		t.Fatalf("expected wrong_java, got %+v", report.Causes)
+	}
+}
@@ -0,0 +1,191 @@
+package minecraft
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrCorruptEntryNotFound = errors.New("corrupt server entry not found")
+
+// CorruptServerEntry is a servers.json entry that could not be loaded. It is
+// kept in the servers.corrupt.json document until it is recovered or
+// discarded.
+type CorruptServerEntry struct {
+	Key        string          `json:"key"`
+	Index      int             `json:"index"`
+	Error      string          `json:"error"`
+	Raw        json.RawMessage `json:"raw"`
+	DetectedAt string          `json:"detectedAt"`
+}
+
+// decodeServerEntries decodes servers.json entry by entry so one malformed
+// server does not prevent the others from loading.
+func decodeServerEntries(raw []json.RawMessage) ([]*ServerConfig, []CorruptServerEntry) {
+	configs := make([]*ServerConfig, 0, len(raw))
+	var corrupt []CorruptServerEntry
+	seen := make(map[string]bool, len(raw))
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	for i, entry := range raw {
+		trimmed := bytes.TrimSpace(entry)
+		if bytes.Equal(trimmed, []byte("null")) {
+			continue
+		}
+		cfg, err := decodeServerEntry(trimmed)
+		if err == nil && seen[cfg.ID] {
+			err = fmt.Errorf("duplicate server id %s", cfg.ID)
+		}
+		if err != nil {
+			corrupt = append(corrupt, CorruptServerEntry{
+				Key:        uuid.New().String()[:8],
+				Index:      i,
+				Error:      err.Error(),
+				Raw:        append(json.RawMessage(nil), trimmed...),
+				DetectedAt: now,
+			})
+			continue
+		}
+		seen[cfg.ID] = true
+		configs = append(configs, cfg)
+	}
+	return configs, corrupt
+}
+
+func decodeServerEntry(data []byte) (*ServerConfig, error) {
+	var cfg ServerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(cfg.Name) == "" {
+		return nil, fmt.Errorf("server name is missing")
+	}
+	if strings.TrimSpace(cfg.Dir) == "" {
+		return nil, fmt.Errorf("server directory is missing")
+	}
+	if strings.TrimSpace(cfg.ID) == "" {
+		cfg.ID = uuid.New().String()[:8]
+	}
+	return &cfg, nil
+}
+
+// loadCorruptConfigsLocked reads previously set-aside entries (caller must hold m.mu).
+func (m *Manager) loadCorruptConfigsLocked() {
+	data, err := m.storage().Load(storeDocCorruptServers)
+	if err != nil {
+		return
+	}
+	var entries []CorruptServerEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		log.Printf("Ignoring unreadable %s: %v", storeDocCorruptServers, err)
+		return
+	}
+	m.corruptConfigs = append(m.corruptConfigs, entries...)
+}
+
+// persistCorruptConfigsLocked writes the set-aside entries (caller must hold m.mu).
+func (m *Manager) persistCorruptConfigsLocked() error {
+	if len(m.corruptConfigs) == 0 {
+		return m.storage().Delete(storeDocCorruptServers)
+	}
+	data, err := json.MarshalIndent(m.corruptConfigs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal corrupt entries: %w", err)
+	}
+	if err := m.storage().Save(storeDocCorruptServers, data); err != nil {
+		return fmt.Errorf("failed to write corrupt entries: %w", err)
+	}
+	return nil
+}
+
+// ListCorruptServerEntries returns servers.json entries that failed to load.
+func (m *Manager) ListCorruptServerEntries() []CorruptServerEntry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]CorruptServerEntry, len(m.corruptConfigs))
+	copy(out, m.corruptConfigs)
+	return out
+}
+
+// RecoverCorruptServerEntry loads a set-aside entry, optionally replacing its
+// JSON with a corrected version, and adds the server back to the panel.
+func (m *Manager) RecoverCorruptServerEntry(key string, corrected json.RawMessage) (*ServerInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	idx := -1
+	for i, entry := range m.corruptConfigs {
+		if entry.Key == key {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, ErrCorruptEntryNotFound
+	}
+
+	data := m.corruptConfigs[idx].Raw
+	if len(bytes.TrimSpace(corrected)) > 0 {
+		data = corrected
+	}
+	cfg, err := decodeServerEntry(data)
+	if err != nil {
+		return nil, fmt.Errorf("entry is still invalid: %w", err)
+	}
+	if _, exists := m.configs[cfg.ID]; exists {
+		return nil, fmt.Errorf("a server with id %s already exists", cfg.ID)
+	}
+	if err := m.validateManagedServerDir(cfg.Dir); err != nil {
+		return nil, fmt.Errorf("server directory is not usable: %w", err)
+	}
+	for _, other := range m.configs {
+		if other.Port == cfg.Port && cfg.Port != 0 {
+			return nil, fmt.Errorf("port %d is already used by %s", cfg.Port, other.Name)
+		}
+	}
+
+	cfg.Order = 0
+	m.configs[cfg.ID] = cfg
+	rs := &runningServer{
+		status:      "Stopped",
+		logBuffer:   make([]ConsoleLogEntry, 0),
+		nextLogSeq:  1,
+		players:     make(map[string]*onlinePlayer),
+		pingBlocked: make(map[string]bool),
+	}
+	rs.logMaxLines, rs.logTrimLines = consoleBufferLimits(cfg)
+	m.running[cfg.ID] = rs
+	m.normalizeServerOrderLocked()
+	if err := m.persist(); err != nil {
+		delete(m.configs, cfg.ID)
+		delete(m.running, cfg.ID)
+		return nil, err
+	}
+
+	m.corruptConfigs = append(m.corruptConfigs[:idx], m.corruptConfigs[idx+1:]...)
+	if err := m.persistCorruptConfigsLocked(); err != nil {
+		log.Printf("Failed to update corrupt server entries: %v", err)
+	}
+	log.Printf("[%s] Recovered server entry from servers.json", cfg.Name)
+	return m.serverInfo(cfg.ID), nil
+}
+
+// DiscardCorruptServerEntry permanently drops a set-aside entry.
+func (m *Manager) DiscardCorruptServerEntry(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, entry := range m.corruptConfigs {
+		if entry.Key != key {
+			continue
+		}
+		m.corruptConfigs = append(m.corruptConfigs[:i], m.corruptConfigs[i+1:]...)
+		return m.persistCorruptConfigsLocked()
+	}
+	return ErrCorruptEntryNotFound
+}
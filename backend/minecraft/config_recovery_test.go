@@ -0,0 +1,60 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSkipsCorruptServerEntriesAndAllowsRecovery(t *testing.T) {
+	base := t.TempDir()
+	dataDir := filepath.Join(base, "data")
+	serversDir := filepath.Join(base, "Servers")
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		t.Fatalf("failed to create data dir: %v", err)
+	}
+
+	goodDir := filepath.Join(serversDir, "good")
+	badDir := filepath.Join(serversDir, "bad")
+	data := fmt.Sprintf(`[
+  {"id": "good1", "name": "Good", "type": "Paper", "port": 25565, "dir": %q},
+  {"id": "bad1", "name": "Bad", "type": "Paper", "port": "25566", "dir": %q}
+]`, goodDir, badDir)
+	if err := os.WriteFile(filepath.Join(dataDir, "servers.json"), []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write servers.json: %v", err)
+	}
+
+	mgr, err := NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	if mgr.configs["good1"] == nil {
+		t.Fatal("expected valid entry to load")
+	}
+	corrupt := mgr.ListCorruptServerEntries()
+	if len(corrupt) != 1 || corrupt[0].Index != 1 {
+		t.Fatalf("expected one corrupt entry at index 1, got %+v", corrupt)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "servers.corrupt.json")); err != nil {
+		t.Fatalf("expected corrupt entry to be preserved on disk: %v", err)
+	}
+
+	fixed, _ := json.Marshal(map[string]any{"id": "bad1", "name": "Bad", "type": "Paper", "port": 25566, "dir": badDir})
+	info, err := mgr.RecoverCorruptServerEntry(corrupt[0].Key, fixed)
+	if err != nil {
+		t.Fatalf("recover failed: %v", err)
+	}
+	if info.ID != "bad1" || info.Port != 25566 {
+		t.Fatalf("unexpected recovered server: %+v", info)
+	}
+	if len(mgr.ListCorruptServerEntries()) != 0 {
+		t.Fatal("expected corrupt list to be empty after recovery")
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "servers.corrupt.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected corrupt file to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package minecraft
+
+import "fmt"
+
+const (
+	defaultLogBufferLines = 2000
+	defaultLogTrimLines   = 200
+	minLogBufferLines     = 100
+	maxLogBufferLines     = 50000
+)
+
+// ConsoleBufferSettings sizes a server's in-memory console history. Once
+// it holds more than MaxLines, the oldest TrimLines are dropped in one go.
+// Zero values take the defaults of 2000 and 200 lines.
+type ConsoleBufferSettings struct {
+	MaxLines  int `json:"maxLines"`
+	TrimLines int `json:"trimLines"`
+}
+
+func normalizeConsoleBuffer(s ConsoleBufferSettings) (ConsoleBufferSettings, error) {
+	if s.MaxLines == 0 {
+		s.MaxLines = defaultLogBufferLines
+	}
+	if s.MaxLines < minLogBufferLines || s.MaxLines > maxLogBufferLines {
+		return s, fmt.Errorf("maxLines must be between %d and %d", minLogBufferLines, maxLogBufferLines)
+	}
+	if s.TrimLines == 0 {
+		s.TrimLines = min(defaultLogTrimLines, s.MaxLines/10)
+	}
+	if s.TrimLines < 1 || s.TrimLines > s.MaxLines {
+		return s, fmt.Errorf("trimLines must be between 1 and maxLines")
+	}
+	return s, nil
+}
+
+// consoleBufferLimits returns the console buffer size and trim step a
+// server is configured with.
+func consoleBufferLimits(cfg *ServerConfig) (maxLines, trimLines int) {
+	if cfg != nil && cfg.ConsoleBuffer != nil {
+		if s, err := normalizeConsoleBuffer(*cfg.ConsoleBuffer); err == nil {
+			return s.MaxLines, s.TrimLines
+		}
+	}
+	return defaultLogBufferLines, defaultLogTrimLines
+}
+
+// setLogLimitsLocked applies new console buffer limits, dropping the
+// oldest lines at once when the buffer is already over the new size.
+// Callers hold rs.mu.
+func (rs *runningServer) setLogLimitsLocked(maxLines, trimLines int) {
+	rs.logMaxLines, rs.logTrimLines = maxLines, trimLines
+	if len(rs.logBuffer) > maxLines {
+		// Copy so the memory behind the dropped lines is released.
+		rs.logBuffer = append(make([]ConsoleLogEntry, 0, maxLines), rs.logBuffer[len(rs.logBuffer)-maxLines:]...)
+	}
+}
+
+// trimLogBufferLocked drops the oldest lines once the buffer is over its
+// limit. Callers hold rs.mu.
+func (rs *runningServer) trimLogBufferLocked() {
+	maxLines, trimLines := rs.logMaxLines, rs.logTrimLines
+	if maxLines <= 0 {
+		maxLines, trimLines = defaultLogBufferLines, defaultLogTrimLines
+	}
+	if len(rs.logBuffer) > maxLines {
+		rs.logBuffer = rs.logBuffer[min(trimLines, len(rs.logBuffer)):]
+	}
+}
+
+// SetConsoleBuffer changes how many console lines a server keeps in
+// memory. It applies at once, including to a running server.
+func (m *Manager) SetConsoleBuffer(id string, settings ConsoleBufferSettings) (*ServerInfo, error) {
+	normalized, err := normalizeConsoleBuffer(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ConsoleBuffer = &normalized
+	if err := m.persist(); err != nil {
+		return nil, err
+	}
+	if rs, ok := m.running[id]; ok {
+		rs.mu.Lock()
+		rs.setLogLimitsLocked(normalized.MaxLines, normalized.TrimLines)
+		rs.mu.Unlock()
+	}
+	return m.serverInfo(id), nil
+}
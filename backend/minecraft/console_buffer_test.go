@@ -0,0 +1,61 @@
+package minecraft
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeConsoleBuffer(t *testing.T) {
+	if s, err := normalizeConsoleBuffer(ConsoleBufferSettings{}); err != nil || s.MaxLines != defaultLogBufferLines || s.TrimLines != defaultLogTrimLines {
+		t.Fatalf("expected the defaults, got %+v (%v)", s, err)
+	}
+	if s, err := normalizeConsoleBuffer(ConsoleBufferSettings{MaxLines: 500}); err != nil || s.TrimLines != 50 {
+		t.Fatalf("expected a trim of a tenth of a small buffer, got %+v (%v)", s, err)
+	}
+	for _, bad := range []ConsoleBufferSettings{
+		{MaxLines: 10},
+		{MaxLines: maxLogBufferLines + 1},
+		{MaxLines: 1000, TrimLines: 1001},
+		{MaxLines: 1000, TrimLines: -1},
+	} {
+		if _, err := normalizeConsoleBuffer(bad); err == nil {
+			t.Fatalf("expected %+v to be refused", bad)
+		}
+	}
+}
+
+func TestSetConsoleBufferAppliesToRunningServer(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	rs := &runningServer{status: "Running"}
+	mgr.mu.Lock()
+	mgr.configs["srv"] = &ServerConfig{ID: "srv", Name: "Srv", Type: "Velocity", Dir: filepath.Join(mgr.serversRoot, "Srv")}
+	mgr.running["srv"] = rs
+	mgr.mu.Unlock()
+
+	for i := 0; i < 300; i++ {
+		mgr.appendLog(rs, "line")
+	}
+	info, err := mgr.SetConsoleBuffer("srv", ConsoleBufferSettings{MaxLines: 100, TrimLines: 10})
+	if err != nil {
+		t.Fatalf("SetConsoleBuffer failed: %v", err)
+	}
+	if info.ConsoleBuffer == nil || info.ConsoleBuffer.MaxLines != 100 {
+		t.Fatalf("expected the settings on the server, got %+v", info.ConsoleBuffer)
+	}
+	if len(rs.logBuffer) != 100 || rs.logBuffer[0].Seq != 201 {
+		t.Fatalf("expected the newest 100 lines kept, got %d starting at %d", len(rs.logBuffer), rs.logBuffer[0].Seq)
+	}
+
+	mgr.appendLog(rs, "one more")
+	if len(rs.logBuffer) != 91 || rs.logBuffer[len(rs.logBuffer)-1].Line != "one more" {
+		t.Fatalf("expected the oldest 10 lines trimmed, got %d lines", len(rs.logBuffer))
+	}
+	if _, err := mgr.SetConsoleBuffer("srv", ConsoleBufferSettings{MaxLines: 5}); err == nil {
+		t.Fatal("expected a tiny buffer to be refused")
+	}
+}
@@ -0,0 +1,169 @@
+package minecraft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxCopyErrors caps how many failures copyTree reports, so one unreadable
+// folder of region files does not produce a wall of text.
+const maxCopyErrors = 10
+
+// copyTree copies src to dst the way cp -a would, without shelling out, so
+// it behaves the same on every platform. Files keep their permissions and
+// modification times. Symlinks are recreated rather than followed, and
+// links that leave src (absolute, or climbing out with ..) are left out so
+// the copy never points at files the source did not contain.
+//
+// A file that fails to copy does not stop the rest: the walk carries on and
+// the failures are returned together at the end. Cancelling ctx stops it at
+// once. copied, if set, is told each file's size once it is written.
+func copyTree(ctx context.Context, src, dst string, copied func(int64)) error {
+	var report func(int64, bool)
+	if copied != nil {
+		report = func(n int64, _ bool) { copied(n) }
+	}
+	return shareTree(ctx, src, dst, nil, report)
+}
+
+// shareTree copies src to dst like copyTree, but when linkable is set it
+// avoids duplicating data where it can: files linkable accepts are hard
+// linked, and every other file is cloned copy-on-write on filesystems that
+// support it, such as Btrfs and XFS. Hard links share later writes, so
+// linkable must only accept files that are replaced rather than edited in
+// place. Anything that cannot be shared is copied. copied is told each
+// file's size and whether it was shared.
+func shareTree(ctx context.Context, src, dst string, linkable func(rel string) bool, copied func(n int64, shared bool)) error {
+	var errs []error
+	skipped := 0
+	fail := func(rel string, err error) {
+		if len(errs) < maxCopyErrors {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.ToSlash(rel), err))
+		} else {
+			skipped++
+		}
+	}
+
+	walkErr := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		rel, relErr := filepath.Rel(src, path)
+		if relErr != nil {
+			return relErr
+		}
+		if err != nil {
+			if path == src {
+				return err
+			}
+			fail(rel, err)
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			fail(rel, err)
+			return nil
+		}
+
+		switch {
+		case d.IsDir():
+			if err := os.MkdirAll(target, info.Mode().Perm()|0700); err != nil {
+				if path == src {
+					return err
+				}
+				fail(rel, err)
+				return fs.SkipDir
+			}
+		case d.Type()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				fail(rel, err)
+			} else if !symlinkStaysInside(src, path, link) {
+				fail(rel, fmt.Errorf("symlink to %s points outside the copied folder and was skipped", link))
+			} else if err := os.Symlink(link, target); err != nil {
+				fail(rel, err)
+			}
+		case d.Type().IsRegular():
+			if linkable != nil && ((linkable(rel) && os.Link(path, target) == nil) || reflinkFile(path, target, info) == nil) {
+				if copied != nil {
+					copied(info.Size(), true)
+				}
+			} else if err := copyRegularFile(path, target, info); err != nil {
+				fail(rel, err)
+			} else if copied != nil {
+				copied(info.Size(), false)
+			}
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return walkErr
+	}
+	if skipped > 0 {
+		errs = append(errs, fmt.Errorf("%d more files failed to copy", skipped))
+	}
+	return errors.Join(errs...)
+}
+
+// copyRegularFile copies one file, keeping its permissions and
+// modification time.
+func copyRegularFile(src, dst string, info os.FileInfo) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// symlinkStaysInside reports whether the link at path, pointing at target,
+// resolves to somewhere within root. Absolute targets never count, since a
+// copy of them would still point into the source.
+func symlinkStaysInside(root, path, target string) bool {
+	if filepath.IsAbs(target) {
+		return false
+	}
+	rel, err := filepath.Rel(root, filepath.Join(filepath.Dir(path), target))
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// treeSize totals the regular files under dir.
+func treeSize(dir string) int64 {
+	var total int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
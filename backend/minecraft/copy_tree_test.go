@@ -0,0 +1,106 @@
+package minecraft
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCopyTreeReportsBytesAndKeepsInternalSymlinks(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "world")
+	if err := os.MkdirAll(filepath.Join(src, "region"), 0755); err != nil {
+		t.Fatalf("failed to create source: %v", err)
+	}
+	region := filepath.Join(src, "region", "r.0.0.mca")
+	if err := os.WriteFile(region, []byte("chunks"), 0644); err != nil {
+		t.Fatalf("failed to write region: %v", err)
+	}
+	modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(region, modTime, modTime); err != nil {
+		t.Fatalf("failed to set mod time: %v", err)
+	}
+	if err := os.Symlink("region/r.0.0.mca", filepath.Join(src, "latest")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	if err := os.Symlink("../../etc", filepath.Join(src, "escape")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "copy")
+	var copied int64
+	err := copyTree(context.Background(), src, dst, func(n int64) { copied += n })
+	if err == nil || !strings.Contains(err.Error(), "escape") {
+		t.Fatalf("expected the escaping symlink to be reported, got %v", err)
+	}
+	info, statErr := os.Stat(filepath.Join(dst, "region", "r.0.0.mca"))
+	if data, readErr := os.ReadFile(filepath.Join(dst, "region", "r.0.0.mca")); readErr != nil || string(data) != "chunks" || statErr != nil || !info.ModTime().Equal(modTime) {
+		t.Fatalf("expected the region file to be copied with its mod time, got %q (%v)", data, readErr)
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "latest")); err != nil || link != "region/r.0.0.mca" {
+		t.Fatalf("expected the symlink to be recreated, got %q (%v)", link, err)
+	}
+	if _, err := os.Lstat(filepath.Join(dst, "escape")); !os.IsNotExist(err) {
+		t.Fatalf("expected the escaping symlink to be left out, got %v", err)
+	}
+	if copied != int64(len("chunks")) || treeSize(src) != copied {
+		t.Fatalf("expected %d bytes reported, got %d (tree size %d)", len("chunks"), copied, treeSize(src))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := copyTree(ctx, src, filepath.Join(t.TempDir(), "cancelled"), nil); err != context.Canceled {
+		t.Fatalf("expected a cancelled copy to fail, got %v", err)
+	}
+}
+
+func TestShareTreeLinksJarsAndCopiesTheRest(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "src")
+	if err := os.MkdirAll(filepath.Join(src, "plugins", "Essentials"), 0755); err != nil {
+		t.Fatalf("failed to create source: %v", err)
+	}
+	jar := filepath.Join(src, "plugins", "EssentialsX.jar")
+	config := filepath.Join(src, "plugins", "Essentials", "config.yml")
+	os.WriteFile(jar, []byte("jar bytes"), 0644)
+	os.WriteFile(config, []byte("motd: hi\n"), 0644)
+
+	dst := filepath.Join(root, "dst")
+	var total, shared int64
+	err := shareTree(context.Background(), src, dst, cloneLinkable, func(n int64, linked bool) {
+		total += n
+		if linked {
+			shared += n
+		}
+	})
+	if err != nil {
+		t.Fatalf("shareTree failed: %v", err)
+	}
+	srcInfo, _ := os.Stat(jar)
+	dstInfo, err := os.Stat(filepath.Join(dst, "plugins", "EssentialsX.jar"))
+	if err != nil || !os.SameFile(srcInfo, dstInfo) {
+		t.Fatalf("expected the jar to be hard linked, got %v", err)
+	}
+	if total != int64(len("jar bytes")+len("motd: hi\n")) || shared < int64(len("jar bytes")) {
+		t.Fatalf("unexpected totals: %d bytes, %d shared", total, shared)
+	}
+
+	// Configs are never hard linked, so editing the clone leaves the source alone.
+	dstConfig := filepath.Join(dst, "plugins", "Essentials", "config.yml")
+	srcInfo, _ = os.Stat(config)
+	dstInfo, _ = os.Stat(dstConfig)
+	if os.SameFile(srcInfo, dstInfo) {
+		t.Fatal("expected the config to be a separate file")
+	}
+	f, err := os.OpenFile(dstConfig, os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	f.WriteString("motd: clone\n")
+	f.Close()
+	if data, _ := os.ReadFile(config); string(data) != "motd: hi\n" {
+		t.Fatalf("editing the clone changed the source: %q", data)
+	}
+}
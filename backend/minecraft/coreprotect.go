@@ -0,0 +1,232 @@
+package minecraft
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	coreProtectDefaultRadius = 5
+	coreProtectMaxRadius     = 100
+	coreProtectDefaultHours  = 72
+	coreProtectMaxHours      = 24 * 365
+	coreProtectDefaultLimit  = 100
+	coreProtectMaxLimit      = 1000
+)
+
+var coreProtectPrefixPattern = regexp.MustCompile(`^[A-Za-z0-9_]*$`)
+
+// CoreProtectQuery selects block changes from CoreProtect's log. Either a
+// player or a full x, y, z position is required.
+type CoreProtectQuery struct {
+	Player string
+	World  string
+	X      *int
+	Y      *int
+	Z      *int
+	Radius int
+	Hours  int
+	// Action is "break", "place", "interact" or empty for all.
+	Action string
+	Limit  int
+}
+
+// CoreProtectEntry is one logged block change.
+type CoreProtectEntry struct {
+	Time       string `json:"time"`
+	Player     string `json:"player"`
+	World      string `json:"world"`
+	X          int    `json:"x"`
+	Y          int    `json:"y"`
+	Z          int    `json:"z"`
+	Block      string `json:"block"`
+	Action     string `json:"action"`
+	RolledBack bool   `json:"rolledBack"`
+}
+
+// coreProtectActions maps co_block action codes to names.
+var coreProtectActions = map[int]string{0: "break", 1: "place", 2: "interact"}
+
+// coreProtectDatabase returns the path of a server's CoreProtect SQLite
+// database and the table prefix from its config.
+func coreProtectDatabase(cfg *ServerConfig) (string, string, error) {
+	dir, err := SafePath(cfg.Dir, filepath.Join("plugins", "CoreProtect"))
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return "", "", fmt.Errorf("CoreProtect is not installed on this server")
+	}
+
+	prefix := "co_"
+	if data, err := os.ReadFile(filepath.Join(dir, "config.yml")); err == nil {
+		var settings struct {
+			UseMySQL    bool    `yaml:"use-mysql"`
+			TablePrefix *string `yaml:"table-prefix"`
+		}
+		if err := yaml.Unmarshal(data, &settings); err == nil {
+			if settings.UseMySQL {
+				return "", "", fmt.Errorf("CoreProtect is set to use MySQL; only its SQLite database can be browsed")
+			}
+			if settings.TablePrefix != nil {
+				prefix = *settings.TablePrefix
+			}
+		}
+	}
+	if !coreProtectPrefixPattern.MatchString(prefix) {
+		return "", "", fmt.Errorf("unsupported CoreProtect table prefix %q", prefix)
+	}
+
+	path := filepath.Join(dir, "database.db")
+	if _, err := os.Stat(path); err != nil {
+		return "", "", fmt.Errorf("CoreProtect has not created its database yet")
+	}
+	return path, prefix, nil
+}
+
+// normalizeCoreProtectQuery applies defaults and limits.
+func normalizeCoreProtectQuery(q *CoreProtectQuery) error {
+	q.Player = strings.TrimSpace(q.Player)
+	q.World = strings.TrimSpace(q.World)
+	q.Action = strings.ToLower(strings.TrimSpace(q.Action))
+	hasPos := q.X != nil && q.Y != nil && q.Z != nil
+	if !hasPos && (q.X != nil || q.Y != nil || q.Z != nil) {
+		return fmt.Errorf("x, y and z must be given together")
+	}
+	if q.Player == "" && !hasPos {
+		return fmt.Errorf("a player or a position is required")
+	}
+	switch q.Action {
+	case "", "break", "place", "interact":
+	default:
+		return fmt.Errorf("action must be break, place or interact")
+	}
+	if q.Radius <= 0 {
+		q.Radius = coreProtectDefaultRadius
+	}
+	if q.Radius > coreProtectMaxRadius {
+		return fmt.Errorf("radius must be at most %d", coreProtectMaxRadius)
+	}
+	if q.Hours <= 0 {
+		q.Hours = coreProtectDefaultHours
+	}
+	if q.Hours > coreProtectMaxHours {
+		return fmt.Errorf("hours must be at most %d", coreProtectMaxHours)
+	}
+	if q.Limit <= 0 {
+		q.Limit = coreProtectDefaultLimit
+	}
+	if q.Limit > coreProtectMaxLimit {
+		q.Limit = coreProtectMaxLimit
+	}
+	return nil
+}
+
+// CoreProtectLookup reads block changes from a server's CoreProtect
+// database without modifying it, newest first.
+func (m *Manager) CoreProtectLookup(id string, q CoreProtectQuery) ([]CoreProtectEntry, error) {
+	if err := normalizeCoreProtectQuery(&q); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.validateManagedServerDir(cfg.Dir); err != nil {
+		return nil, m.configPathErrorLocked(id, err.Error())
+	}
+	path, prefix, err := coreProtectDatabase(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dsn := (&url.URL{
+		Scheme: "file",
+		Path:   path,
+		RawQuery: url.Values{
+			"mode":    {"ro"},
+			"_pragma": {"busy_timeout(5000)", "query_only(1)"},
+		}.Encode(),
+	}).String()
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CoreProtect database: %w", err)
+	}
+	defer db.Close()
+
+	var where []string
+	var args []any
+	where = append(where, "b.time >= ?")
+	args = append(args, time.Now().Add(-time.Duration(q.Hours)*time.Hour).Unix())
+	if q.Player != "" {
+		where = append(where, "u.user = ? COLLATE NOCASE")
+		args = append(args, q.Player)
+	}
+	if q.X != nil {
+		where = append(where, "b.x BETWEEN ? AND ?", "b.y BETWEEN ? AND ?", "b.z BETWEEN ? AND ?")
+		args = append(args, *q.X-q.Radius, *q.X+q.Radius, *q.Y-q.Radius, *q.Y+q.Radius, *q.Z-q.Radius, *q.Z+q.Radius)
+	}
+	if q.World != "" {
+		where = append(where, "w.world = ?")
+		args = append(args, q.World)
+	}
+	if q.Action != "" {
+		for code, name := range coreProtectActions {
+			if name == q.Action {
+				where = append(where, "b.action = ?")
+				args = append(args, code)
+			}
+		}
+	}
+	args = append(args, q.Limit)
+
+	query := fmt.Sprintf(`SELECT b.time, COALESCE(u.user, ''), COALESCE(w.world, ''), b.x, b.y, b.z,
+		COALESCE(mm.material, ''), b.action, b.rolled_back
+		FROM %[1]sblock b
+		LEFT JOIN %[1]suser u ON u.id = b.user
+		LEFT JOIN %[1]sworld w ON w.id = b.wid
+		LEFT JOIN %[1]smaterial_map mm ON mm.id = b.type
+		WHERE %[2]s
+		ORDER BY b.time DESC
+		LIMIT ?`, prefix, strings.Join(where, " AND "))
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such table") {
+			return nil, errors.New("the CoreProtect database has no block log yet")
+		}
+		return nil, fmt.Errorf("failed to read CoreProtect database: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]CoreProtectEntry, 0)
+	for rows.Next() {
+		var entry CoreProtectEntry
+		var unix int64
+		var action, rolledBack int
+		if err := rows.Scan(&unix, &entry.Player, &entry.World, &entry.X, &entry.Y, &entry.Z, &entry.Block, &action, &rolledBack); err != nil {
+			return nil, fmt.Errorf("failed to read CoreProtect database: %w", err)
+		}
+		entry.Time = time.Unix(unix, 0).UTC().Format(time.RFC3339)
+		entry.Action = coreProtectActions[action]
+		if entry.Action == "" {
+			entry.Action = fmt.Sprintf("action %d", action)
+		}
+		entry.RolledBack = rolledBack != 0
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read CoreProtect database: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,86 @@
+package minecraft
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCoreProtectLookupReadsBlockLog(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv1", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = &runningServer{status: "Running"}
+	mgr.mu.Unlock()
+
+	if _, err := mgr.CoreProtectLookup(cfg.ID, CoreProtectQuery{Player: "Griefer"}); err == nil {
+		t.Fatalf("expected an error without CoreProtect")
+	}
+
+	dir := filepath.Join(cfg.Dir, "plugins", "CoreProtect")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create plugin dir: %v", err)
+	}
+	db, err := sql.Open("sqlite", filepath.Join(dir, "database.db"))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	now := time.Now().Unix()
+	for _, stmt := range []string{
+		`CREATE TABLE co_user (id INTEGER PRIMARY KEY ASC, time INTEGER, user TEXT, uuid TEXT)`,
+		`CREATE TABLE co_world (id INTEGER, world TEXT)`,
+		`CREATE TABLE co_material_map (id INTEGER, material TEXT)`,
+		`CREATE TABLE co_block (time INTEGER, user INTEGER, wid INTEGER, x INTEGER, y INTEGER, z INTEGER, type INTEGER, data INTEGER, meta BLOB, blockdata BLOB, action INTEGER, rolled_back INTEGER)`,
+		`INSERT INTO co_user (id, user) VALUES (1, 'Griefer'), (2, 'Builder')`,
+		`INSERT INTO co_world VALUES (1, 'world')`,
+		`INSERT INTO co_material_map VALUES (1, 'minecraft:oak_planks'), (2, 'minecraft:tnt')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("failed to set up database: %v", err)
+		}
+	}
+	insert := func(age time.Duration, user, x, y, z, material, action, rolledBack int) {
+		if _, err := db.Exec(`INSERT INTO co_block (time, user, wid, x, y, z, type, data, action, rolled_back) VALUES (?, ?, 1, ?, ?, ?, ?, 0, ?, ?)`,
+			now-int64(age.Seconds()), user, x, y, z, material, action, rolledBack); err != nil {
+			t.Fatalf("failed to insert block change: %v", err)
+		}
+	}
+	insert(time.Hour, 2, 100, 64, 100, 1, 1, 0)
+	insert(30*time.Minute, 1, 101, 64, 100, 1, 0, 0)
+	insert(20*time.Minute, 1, 102, 65, 99, 2, 1, 1)
+	insert(10*24*time.Hour, 1, 100, 64, 100, 1, 0, 0)
+	insert(5*time.Minute, 1, 500, 64, 500, 1, 0, 0)
+	db.Close()
+
+	entries, err := mgr.CoreProtectLookup(cfg.ID, CoreProtectQuery{Player: "griefer"})
+	if err != nil {
+		t.Fatalf("CoreProtectLookup failed: %v", err)
+	}
+	if len(entries) != 3 || entries[0].X != 500 || entries[2].Block != "minecraft:oak_planks" || entries[2].Action != "break" {
+		t.Fatalf("unexpected player history: %+v", entries)
+	}
+
+	x, y, z := 100, 64, 100
+	entries, err = mgr.CoreProtectLookup(cfg.ID, CoreProtectQuery{X: &x, Y: &y, Z: &z, Radius: 3, Action: "place"})
+	if err != nil {
+		t.Fatalf("CoreProtectLookup failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Player != "Griefer" || !entries[0].RolledBack || entries[1].Player != "Builder" || entries[0].World != "world" {
+		t.Fatalf("unexpected position history: %+v", entries)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "config.yml"), []byte("use-mysql: true\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := mgr.CoreProtectLookup(cfg.ID, CoreProtectQuery{Player: "Griefer"}); err == nil {
+		t.Fatalf("expected MySQL setups to be refused")
+	}
+}
@@ -0,0 +1,175 @@
+package minecraft
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	defaultCrashMaxRetries   = 3
+	maxCrashMaxRetries       = 20
+	defaultCrashInitialDelay = 10
+	minCrashInitialDelay     = 5
+	maxCrashInitialDelay     = 600
+	// maxCrashRestartDelay caps the backoff between automatic restarts.
+	maxCrashRestartDelay = 15 * time.Minute
+	// crashStableRun is how long a server must stay up before a crash
+	// counts as a new incident rather than another failed retry.
+	crashStableRun = 10 * time.Minute
+	// crashCountWindow is the period ServerInfo.CrashCount covers.
+	crashCountWindow = time.Hour
+)
+
+// CrashRestartSettings has a crashed server started again automatically.
+// Consecutive crashes wait InitialDelaySeconds, then twice as long each
+// time, and the panel gives up after MaxRetries restarts in a row.
+type CrashRestartSettings struct {
+	Enabled             bool `json:"enabled"`
+	MaxRetries          int  `json:"maxRetries"`
+	InitialDelaySeconds int  `json:"initialDelaySeconds"`
+}
+
+func normalizeCrashRestart(s CrashRestartSettings) (CrashRestartSettings, error) {
+	if s.MaxRetries == 0 {
+		s.MaxRetries = defaultCrashMaxRetries
+	}
+	if s.InitialDelaySeconds == 0 {
+		s.InitialDelaySeconds = defaultCrashInitialDelay
+	}
+	if s.MaxRetries < 1 || s.MaxRetries > maxCrashMaxRetries {
+		return s, fmt.Errorf("maxRetries must be between 1 and %d", maxCrashMaxRetries)
+	}
+	if s.InitialDelaySeconds < minCrashInitialDelay || s.InitialDelaySeconds > maxCrashInitialDelay {
+		return s, fmt.Errorf("initialDelaySeconds must be between %d and %d", minCrashInitialDelay, maxCrashInitialDelay)
+	}
+	return s, nil
+}
+
+// crashRestartDelay is the wait before automatic restart number attempt,
+// counting from 1.
+func crashRestartDelay(initial time.Duration, attempt int) time.Duration {
+	delay := initial
+	for i := 1; i < attempt && delay < maxCrashRestartDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxCrashRestartDelay)
+}
+
+// SetCrashRestart replaces a server's crash auto-restart settings.
+func (m *Manager) SetCrashRestart(id string, settings CrashRestartSettings) (*ServerInfo, error) {
+	normalized, err := normalizeCrashRestart(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	cfg.RestartOnCrash = &normalized
+	m.persist()
+
+	if rs, ok := m.running[id]; ok && !normalized.Enabled {
+		rs.mu.Lock()
+		stopCrashRestartLocked(rs)
+		rs.mu.Unlock()
+	}
+	return m.serverInfo(id), nil
+}
+
+// recordCrashLocked notes a crash and forgets crashes older than the
+// window ServerInfo reports. Callers hold rs.mu.
+func recordCrashLocked(rs *runningServer, at time.Time) {
+	kept := rs.crashTimes[:0]
+	for _, t := range rs.crashTimes {
+		if at.Sub(t) < crashCountWindow {
+			kept = append(kept, t)
+		}
+	}
+	rs.crashTimes = append(kept, at)
+	if !rs.bootStartedAt.IsZero() && at.Sub(rs.bootStartedAt) >= crashStableRun {
+		rs.crashRestarts = 0
+	}
+}
+
+func stopCrashRestartLocked(rs *runningServer) {
+	if rs.crashRestartTimer != nil {
+		rs.crashRestartTimer.Stop()
+		rs.crashRestartTimer = nil
+	}
+	rs.crashRestartAt = time.Time{}
+}
+
+// scheduleCrashRestart starts a crashed server again after the backoff
+// delay, unless auto-restart is off or the retries are used up.
+func (m *Manager) scheduleCrashRestart(id string, rs *runningServer) {
+	m.mu.RLock()
+	cfg, ok := m.configs[id]
+	var settings CrashRestartSettings
+	name := ""
+	if ok {
+		name = cfg.Name
+		if cfg.RestartOnCrash != nil {
+			settings = *cfg.RestartOnCrash
+		}
+	}
+	m.mu.RUnlock()
+	if !ok || !settings.Enabled {
+		return
+	}
+
+	rs.mu.Lock()
+	if rs.status != "Crashed" {
+		rs.mu.Unlock()
+		return
+	}
+	if rs.crashRestarts >= settings.MaxRetries {
+		stopCrashRestartLocked(rs)
+		rs.mu.Unlock()
+		log.Printf("[%s] Not restarting after crash: %d automatic restarts in a row failed", name, settings.MaxRetries)
+		m.broadcastLog(rs, m.appendLog(rs, fmt.Sprintf("[Panel] Server crashed again after %d automatic restarts; giving up until it is started by hand.", settings.MaxRetries)))
+		return
+	}
+	rs.crashRestarts++
+	attempt := rs.crashRestarts
+	delay := crashRestartDelay(time.Duration(settings.InitialDelaySeconds)*time.Second, attempt)
+	stopCrashRestartLocked(rs)
+	rs.crashRestartAt = time.Now().Add(delay)
+	rs.crashRestartTimer = time.AfterFunc(delay, func() { m.runCrashRestart(id, rs, name) })
+	rs.mu.Unlock()
+
+	log.Printf("[%s] Restarting after crash in %s (attempt %d of %d)", name, delay, attempt, settings.MaxRetries)
+	m.broadcastLog(rs, m.appendLog(rs, fmt.Sprintf("[Panel] Server crashed; restarting in %s (attempt %d of %d).", delay, attempt, settings.MaxRetries)))
+}
+
+// runCrashRestart starts a crashed server again under the server's
+// operation lock. A server started by hand while the restart waited for the
+// lock is left alone.
+func (m *Manager) runCrashRestart(id string, rs *runningServer, name string) {
+	m.runRestart(id, rs, "Restarting after crash", func(job *jobHandle) error {
+		rs.mu.Lock()
+		if rs.status != "Crashed" || rs.crashRestartAt.IsZero() {
+			rs.mu.Unlock()
+			job.log("Server no longer needs an automatic restart")
+			return nil
+		}
+		rs.crashRestartTimer = nil
+		rs.crashRestartAt = time.Time{}
+		rs.crashRestarting = true
+		rs.mu.Unlock()
+
+		if err := m.StartServer(id); err != nil {
+			rs.mu.Lock()
+			rs.crashRestarting = false
+			rs.mu.Unlock()
+			log.Printf("[%s] Automatic restart after crash failed: %v", name, err)
+			m.broadcastLog(rs, m.appendLog(rs, fmt.Sprintf("[Panel] Automatic restart failed: %v", err)))
+			return err
+		}
+		m.verifyRestart(job.ctx, id, verifyTriggerCrashRestart, nil)
+		return nil
+	})
+}
@@ -0,0 +1,83 @@
+package minecraft
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCrashRestartDelayBacksOff(t *testing.T) {
+	cases := map[int]time.Duration{
+		1:  10 * time.Second,
+		2:  20 * time.Second,
+		3:  40 * time.Second,
+		20: maxCrashRestartDelay,
+	}
+	for attempt, want := range cases {
+		if got := crashRestartDelay(10*time.Second, attempt); got != want {
+			t.Fatalf("crashRestartDelay(10s, %d) = %s, want %s", attempt, got, want)
+		}
+	}
+}
+
+func TestCrashRestartSchedulesUntilRetriesRunOut(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	rs := &runningServer{status: "Stopped"}
+	mgr.mu.Lock()
+	mgr.configs["srv"] = &ServerConfig{ID: "srv", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	mgr.running["srv"] = rs
+	mgr.mu.Unlock()
+
+	if _, err := mgr.SetCrashRestart("srv", CrashRestartSettings{Enabled: true, MaxRetries: 50}); err == nil {
+		t.Fatalf("expected too many retries to be rejected")
+	}
+	info, err := mgr.SetCrashRestart("srv", CrashRestartSettings{Enabled: true, MaxRetries: 2})
+	if err != nil {
+		t.Fatalf("SetCrashRestart failed: %v", err)
+	}
+	if info.RestartOnCrash == nil || info.RestartOnCrash.InitialDelaySeconds != defaultCrashInitialDelay {
+		t.Fatalf("expected the default initial delay, got %+v", info.RestartOnCrash)
+	}
+
+	crash := func() {
+		rs.mu.Lock()
+		rs.status = "Crashed"
+		rs.bootStartedAt = time.Now().Add(-time.Minute)
+		recordCrashLocked(rs, time.Now())
+		rs.mu.Unlock()
+		mgr.scheduleCrashRestart("srv", rs)
+	}
+
+	crash()
+	mgr.mu.RLock()
+	info = mgr.serverInfo("srv")
+	mgr.mu.RUnlock()
+	if info.CrashCount != 1 || info.LastCrashAt == "" || info.CrashRestarts != 1 || info.CrashRestartAt == "" {
+		t.Fatalf("expected one crash and a pending restart, got %+v", info)
+	}
+
+	crash()
+	crash()
+	rs.mu.Lock()
+	restarts, pending := rs.crashRestarts, rs.crashRestartTimer != nil
+	rs.mu.Unlock()
+	if restarts != 2 || pending {
+		t.Fatalf("expected the panel to give up after 2 restarts, got %d restarts, pending=%v", restarts, pending)
+	}
+
+	// A crash after the server stayed up a while starts a fresh count.
+	rs.mu.Lock()
+	rs.bootStartedAt = time.Now().Add(-time.Hour)
+	recordCrashLocked(rs, time.Now())
+	restarts, crashes := rs.crashRestarts, len(rs.crashTimes)
+	stopCrashRestartLocked(rs)
+	rs.mu.Unlock()
+	if restarts != 0 || crashes != 4 {
+		t.Fatalf("expected a fresh retry count and 4 recent crashes, got %d restarts and %d crashes", restarts, crashes)
+	}
+}
@@ -0,0 +1,309 @@
+package minecraft
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const curseForgeAPIBase = "https://api.curseforge.com"
+
+// CurseForge class IDs of the projects a pack can list.
+const (
+	curseForgeClassMods     = 6
+	curseForgeClassModpacks = 4471
+)
+
+// curseForgeGameMinecraft is Minecraft's game ID on CurseForge.
+const curseForgeGameMinecraft = 432
+
+// curseForgeReleaseStable is the release type of files that are not
+// marked beta or alpha.
+const curseForgeReleaseStable = 1
+
+var (
+	curseForgeKeyMu       sync.RWMutex
+	curseForgeKeyOverride string
+)
+
+// setCurseForgeAPIKeyOverride sets the key saved in the panel settings,
+// which takes precedence over ADPANEL_CURSEFORGE_API_KEY.
+func setCurseForgeAPIKeyOverride(key string) {
+	curseForgeKeyMu.Lock()
+	curseForgeKeyOverride = strings.TrimSpace(key)
+	curseForgeKeyMu.Unlock()
+}
+
+func curseForgeAPIKey() string {
+	curseForgeKeyMu.RLock()
+	key := curseForgeKeyOverride
+	curseForgeKeyMu.RUnlock()
+	if key != "" {
+		return key
+	}
+	return strings.TrimSpace(os.Getenv("ADPANEL_CURSEFORGE_API_KEY"))
+}
+
+// curseForgeClient calls the CurseForge API, which needs an API key from
+// the CurseForge developer console.
+type curseForgeClient struct {
+	baseURL string
+	apiKey  string
+}
+
+func newCurseForgeClient() (*curseForgeClient, error) {
+	key := curseForgeAPIKey()
+	if key == "" {
+		return nil, fmt.Errorf("CurseForge needs an API key; add one in Settings or set ADPANEL_CURSEFORGE_API_KEY")
+	}
+	return &curseForgeClient{baseURL: curseForgeAPIBase, apiKey: key}, nil
+}
+
+// do sends a paced request and decodes the "data" field of the response.
+func (c *curseForgeClient) do(ctx context.Context, method, apiPath string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	endpoint := c.baseURL + apiPath
+	host := endpoint
+	if u, err := url.Parse(endpoint); err == nil {
+		host = strings.ToLower(u.Host)
+	}
+	if err := apiPacer.wait(ctx, host, apiMinIntervalFromEnv()); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgent())
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("x-api-key", c.apiKey)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := apiHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("CurseForge rejected the API key (status %d)", resp.StatusCode)
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("CurseForge has no %s", apiPath)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("CurseForge request %s failed with status %d", apiPath, resp.StatusCode)
+	}
+	envelope := struct {
+		Data any `json:"data"`
+	}{Data: out}
+	return json.NewDecoder(resp.Body).Decode(&envelope)
+}
+
+type curseForgeMod struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Slug       string `json:"slug"`
+	ClassID    int    `json:"classId"`
+	MainFileID int    `json:"mainFileId"`
+}
+
+type curseForgeFile struct {
+	ID          int       `json:"id"`
+	ModID       int       `json:"modId"`
+	FileName    string    `json:"fileName"`
+	DisplayName string    `json:"displayName"`
+	DownloadURL string    `json:"downloadUrl"`
+	ReleaseType int       `json:"releaseType"`
+	FileDate    time.Time `json:"fileDate"`
+	// GameVersions mixes Minecraft versions with loader and side tags
+	// such as "NeoForge" and "Server".
+	GameVersions []string `json:"gameVersions"`
+	Hashes       []struct {
+		Value string `json:"value"`
+		Algo  int    `json:"algo"`
+	} `json:"hashes"`
+}
+
+// downloadURLs lists where to fetch the file. Authors can turn off API
+// downloads, leaving downloadUrl empty, but the file stays on the CDN.
+func (f curseForgeFile) downloadURLs() []string {
+	cdn := fmt.Sprintf("https://edge.forgecdn.net/files/%d/%d/%s", f.ID/1000, f.ID%1000, url.PathEscape(f.FileName))
+	if f.DownloadURL == "" || f.DownloadURL == cdn {
+		return []string{cdn}
+	}
+	return []string{f.DownloadURL, cdn}
+}
+
+// curseForgePack is a modpack archive to download.
+type curseForgePack struct {
+	projectName string
+	fileID      int
+	fileName    string
+	url         string
+}
+
+// packFile looks up a modpack project's file, or its main file when
+// fileID is zero.
+func (c *curseForgeClient) packFile(ctx context.Context, projectID, fileID int) (*curseForgePack, error) {
+	if projectID <= 0 {
+		return nil, fmt.Errorf("invalid CurseForge project ID")
+	}
+	var mod curseForgeMod
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v1/mods/%d", projectID), nil, &mod); err != nil {
+		return nil, err
+	}
+	if mod.ClassID != curseForgeClassModpacks {
+		return nil, fmt.Errorf("CurseForge project %d (%s) is not a modpack", projectID, mod.Name)
+	}
+	if fileID <= 0 {
+		fileID = mod.MainFileID
+	}
+	var file curseForgeFile
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v1/mods/%d/files/%d", projectID, fileID), nil, &file); err != nil {
+		return nil, err
+	}
+	return &curseForgePack{projectName: mod.Name, fileID: file.ID, fileName: file.FileName, url: file.downloadURLs()[0]}, nil
+}
+
+// resolveFiles turns a CurseForge pack's project and file IDs into
+// downloads under mods/. Files of other project classes, such as
+// resource packs and shaders, are client-side and counted as skipped.
+func (c *curseForgeClient) resolveFiles(ctx context.Context, files []modpackFile) ([]modpackFile, int, error) {
+	fileIDs := make([]int, 0, len(files))
+	modIDs := make([]int, 0, len(files))
+	for _, f := range files {
+		fileIDs = append(fileIDs, f.curseForgeFileID)
+		modIDs = append(modIDs, f.curseForgeProjectID)
+	}
+	var found []curseForgeFile
+	if err := c.do(ctx, http.MethodPost, "/v1/mods/files", map[string]any{"fileIds": fileIDs}, &found); err != nil {
+		return nil, 0, err
+	}
+	var mods []curseForgeMod
+	if err := c.do(ctx, http.MethodPost, "/v1/mods", map[string]any{"modIds": modIDs}, &mods); err != nil {
+		return nil, 0, err
+	}
+	byFile := make(map[int]curseForgeFile, len(found))
+	for _, f := range found {
+		byFile[f.ID] = f
+	}
+	classes := make(map[int]int, len(mods))
+	for _, mod := range mods {
+		classes[mod.ID] = mod.ClassID
+	}
+
+	resolved := make([]modpackFile, 0, len(files))
+	skipped := 0
+	for _, f := range files {
+		cf, ok := byFile[f.curseForgeFileID]
+		if !ok {
+			return nil, 0, fmt.Errorf("CurseForge file %d of project %d was not found", f.curseForgeFileID, f.curseForgeProjectID)
+		}
+		if class, ok := classes[f.curseForgeProjectID]; ok && class != curseForgeClassMods {
+			skipped++
+			continue
+		}
+		if cf.FileName == "" || path.Base(cf.FileName) != cf.FileName || strings.Contains(cf.FileName, "\\") {
+			return nil, 0, fmt.Errorf("CurseForge file %d has an unsafe name %q", cf.ID, cf.FileName)
+		}
+		rel, err := modpackFilePath("mods/" + cf.FileName)
+		if err != nil {
+			return nil, 0, err
+		}
+		file := f
+		file.path = rel
+		file.urls = cf.downloadURLs()
+		for _, h := range cf.Hashes {
+			// Algo 1 is SHA-1; 2 is MD5, which is not checked.
+			if h.Algo == 1 {
+				file.sha1 = strings.ToLower(h.Value)
+			}
+		}
+		resolved = append(resolved, file)
+	}
+	return resolved, skipped, nil
+}
+
+// curseForgeModLoaderType maps a server type to CurseForge's modLoaderType
+// filter, or 0 when the files should not be filtered by loader.
+func curseForgeModLoaderType(serverType string) int {
+	switch strings.ToLower(serverType) {
+	case "forge":
+		return 1
+	case "fabric":
+		return 4
+	case "neoforge":
+		return 6
+	default:
+		return 0
+	}
+}
+
+// modBySlug finds the Minecraft mod whose URL slug is slug.
+func (c *curseForgeClient) modBySlug(ctx context.Context, slug string) (*curseForgeMod, error) {
+	query := url.Values{}
+	query.Set("gameId", fmt.Sprint(curseForgeGameMinecraft))
+	query.Set("classId", fmt.Sprint(curseForgeClassMods))
+	query.Set("slug", slug)
+	var mods []curseForgeMod
+	if err := c.do(ctx, http.MethodGet, "/v1/mods/search?"+query.Encode(), nil, &mods); err != nil {
+		return nil, err
+	}
+	for i := range mods {
+		if strings.EqualFold(mods[i].Slug, slug) {
+			return &mods[i], nil
+		}
+	}
+	return nil, fmt.Errorf("CurseForge has no mod %q", slug)
+}
+
+// modFiles lists a mod's files for serverType's loader, newest first.
+func (c *curseForgeClient) modFiles(ctx context.Context, modID int, serverType string) ([]curseForgeFile, error) {
+	query := url.Values{}
+	query.Set("pageSize", "50")
+	if loader := curseForgeModLoaderType(serverType); loader != 0 {
+		query.Set("modLoaderType", fmt.Sprint(loader))
+	}
+	var files []curseForgeFile
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v1/mods/%d/files?%s", modID, query.Encode()), nil, &files); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].FileDate.After(files[j].FileDate) })
+	return files, nil
+}
+
+// supportsGameVersion reports whether the file lists mcVersion.
+func (f curseForgeFile) supportsGameVersion(mcVersion string) bool {
+	for _, v := range f.GameVersions {
+		if v == mcVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// versionLabel is what the panel shows as the file's version: the display
+// name authors set, or the file name without its extension.
+func (f curseForgeFile) versionLabel() string {
+	if name := strings.TrimSpace(f.DisplayName); name != "" {
+		return name
+	}
+	return strings.TrimSuffix(f.FileName, path.Ext(f.FileName))
+}
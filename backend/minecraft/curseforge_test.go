@@ -0,0 +1,63 @@
+package minecraft
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckCurseForgeByProject(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "test-key" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/mods/search":
+			if r.URL.Query().Get("slug") != "jei" || r.URL.Query().Get("classId") != "6" {
+				t.Errorf("unexpected search %s", r.URL.RawQuery)
+			}
+			json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{
+				{"id": 238222, "name": "Just Enough Items (JEI)", "slug": "jei"},
+			}})
+		case "/v1/mods/238222/files":
+			if r.URL.Query().Get("modLoaderType") != "6" {
+				t.Errorf("expected the NeoForge loader filter, got %s", r.URL.RawQuery)
+			}
+			json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{
+				{"id": 5101366, "fileName": "jei-1.20.4-neoforge-17.3.0.52.jar", "displayName": "jei-1.20.4-neoforge-17.3.0.52", "releaseType": 1, "fileDate": "2024-03-01T00:00:00Z", "gameVersions": []string{"1.20.4", "NeoForge"}},
+				{"id": 5200000, "fileName": "jei-1.20.4-neoforge-17.4.0.1.jar", "displayName": "jei-1.20.4-neoforge-17.4.0.1", "releaseType": 2, "fileDate": "2024-04-01T00:00:00Z", "gameVersions": []string{"1.20.4", "NeoForge"}},
+				{"id": 5300000, "fileName": "jei-1.21-neoforge-19.0.0.1.jar", "displayName": "jei-1.21-neoforge-19.0.0.1", "releaseType": 1, "fileDate": "2024-06-01T00:00:00Z", "gameVersions": []string{"1.21", "NeoForge"}},
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	client := &curseForgeClient{baseURL: srv.URL, apiKey: "test-key"}
+
+	info := checkCurseForgeByProject(context.Background(), client, "jei", "JEI", "17.3.0.49", "1.20.4", "neoforge", false)
+	if info == nil || info.VersionStatus != "outdated" || info.LatestVersion != "jei-1.20.4-neoforge-17.3.0.52" {
+		t.Fatalf("expected the newest stable 1.20.4 file, got %+v", info)
+	}
+	if info.UpdateURL != "https://edge.forgecdn.net/files/5101/366/jei-1.20.4-neoforge-17.3.0.52.jar" {
+		t.Fatalf("unexpected update URL %q", info.UpdateURL)
+	}
+
+	info = checkCurseForgeByProject(context.Background(), client, "jei", "JEI", "17.3.0.52", "1.20.4", "neoforge", true)
+	if info == nil || info.VersionStatus != "outdated" || info.LatestVersion != "jei-1.20.4-neoforge-17.4.0.1" {
+		t.Fatalf("expected prereleases to offer the beta, got %+v", info)
+	}
+
+	info = checkCurseForgeByProject(context.Background(), client, "jei", "JEI", "15.2.0.27", "1.20.1", "neoforge", false)
+	if info == nil || info.VersionStatus != "incompatible" {
+		t.Fatalf("expected no file for 1.20.1 to be incompatible, got %+v", info)
+	}
+
+	client.apiKey = "wrong"
+	if info := checkCurseForgeByProject(context.Background(), client, "jei", "JEI", "17.3.0.49", "1.20.4", "neoforge", false); info != nil {
+		t.Fatalf("expected no result with a rejected key, got %+v", info)
+	}
+}
@@ -0,0 +1,290 @@
+package minecraft
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DatapackInfo is a .zip datapack in the world's datapacks/ folder, with
+// what its pack.mcmeta says about it.
+type DatapackInfo struct {
+	FileName    string `json:"fileName"`
+	PackID      string `json:"packId"` // the name /datapack uses, e.g. file/Terralith.zip
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	PackFormat  int    `json:"packFormat,omitempty"`
+	Size        string `json:"size"`
+	Enabled     bool   `json:"enabled"`
+	Note        string `json:"note,omitempty"`
+}
+
+// datapackTarget returns the server, its datapacks folder and whether it is
+// running. Datapacks can change while the server runs, since the game is
+// told to reload, but not while it boots or stops.
+func (m *Manager) datapackTarget(id string) (ServerConfig, string, bool, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		m.mu.RUnlock()
+		return ServerConfig{}, "", false, err
+	}
+	snapshot := *cfg
+	rs := m.running[id]
+	m.mu.RUnlock()
+
+	_, dir, err := resolveExtensionDir(&snapshot, ExtensionDirDatapacks)
+	if err != nil {
+		return ServerConfig{}, "", false, err
+	}
+	status := "Stopped"
+	if rs != nil {
+		status = rs.currentRuntime().status
+	}
+	switch status {
+	case "Running":
+		return snapshot, dir, true, nil
+	case "Stopped", "Crashed", "Error":
+		return snapshot, dir, false, nil
+	default:
+		return ServerConfig{}, "", false, fmt.Errorf("server is %s", strings.ToLower(status))
+	}
+}
+
+// datapackPackID returns the name the game knows a datapack file by. Names
+// that would break a console command line are refused.
+func datapackPackID(fileName string) (string, error) {
+	base, _ := splitDisabledSuffix(filepath.Base(fileName))
+	if strings.ContainsAny(base, "\r\n\x00") {
+		return "", fmt.Errorf("invalid datapack file name")
+	}
+	return "file/" + base, nil
+}
+
+func quoteCommandArg(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// ListDatapacks lists the .zip datapacks in the server's world, enabled
+// ones first.
+func (m *Manager) ListDatapacks(id string) ([]DatapackInfo, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	_, dir, err := resolveExtensionDir(cfg, ExtensionDirDatapacks)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	manifest := m.loadExtensionManifest(cfg)
+	packs := make([]DatapackInfo, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || !extensionFileAllowed(ExtensionDirDatapacks, entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		packs = append(packs, newDatapackInfo(manifest, cfg, filepath.Join(dir, entry.Name()), info.Size()))
+	}
+	sort.SliceStable(packs, func(i, j int) bool {
+		if packs[i].Enabled != packs[j].Enabled {
+			return packs[i].Enabled
+		}
+		return strings.ToLower(packs[i].Name) < strings.ToLower(packs[j].Name)
+	})
+	return packs, nil
+}
+
+func newDatapackInfo(manifest map[string]*ExtensionProvenance, cfg *ServerConfig, path string, size int64) DatapackInfo {
+	fileName := filepath.Base(path)
+	base, disabled := splitDisabledSuffix(fileName)
+	pack := DatapackInfo{
+		FileName: fileName,
+		PackID:   "file/" + base,
+		Name:     strings.TrimSuffix(base, filepath.Ext(base)),
+		Size:     formatFileSize(size),
+		Enabled:  !disabled,
+	}
+	pack.Description, pack.PackFormat = readDatapackMeta(path)
+	if provenance := provenanceForFile(manifest, extensionRecordName(cfg, ExtensionDirDatapacks, fileName)); provenance != nil {
+		pack.Note = provenance.Note
+	}
+	return pack
+}
+
+// readDatapackMeta returns the description and pack format from a
+// datapack's pack.mcmeta, or zero values when it cannot be read.
+func readDatapackMeta(path string) (string, int) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return "", 0
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		if f.Name != "pack.mcmeta" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", 0
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, 64<<10))
+		rc.Close()
+		if err != nil {
+			return "", 0
+		}
+		return parsePackMcmeta(data)
+	}
+	return "", 0
+}
+
+// parsePackMcmeta reads pack.pack_format and pack.description. The
+// description may be a plain string or a text component.
+func parsePackMcmeta(data []byte) (string, int) {
+	var meta struct {
+		Pack struct {
+			PackFormat  int             `json:"pack_format"`
+			Description json.RawMessage `json:"description"`
+		} `json:"pack"`
+	}
+	if err := json.Unmarshal(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), &meta); err != nil {
+		return "", 0
+	}
+	description := ""
+	if len(meta.Pack.Description) > 0 {
+		description = mcColorPattern.ReplaceAllString(chatText(string(meta.Pack.Description)), "")
+	}
+	return description, meta.Pack.PackFormat
+}
+
+// UploadDatapack installs a datapack .zip. A running server is told to
+// reload, which loads the new pack.
+func (m *Manager) UploadDatapack(id, fileName, sourcePath, conflictAction string) (string, string, error) {
+	cfg, _, running, err := m.datapackTarget(id)
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := datapackPackID(fileName); err != nil {
+		return "", "", err
+	}
+	savedName, status, err := m.installPluginFile(id, fileName, sourcePath, conflictAction, pluginInstallOptions{
+		origin: ExtensionOriginUpload,
+		dir:    ExtensionDirDatapacks,
+	})
+	if err != nil || status == "skipped" || !running {
+		return savedName, status, err
+	}
+	if err := m.SendCommand(id, "reload"); err != nil {
+		log.Printf("[%s] Failed to reload datapacks: %v", cfg.Name, err)
+	}
+	return savedName, status, nil
+}
+
+// ToggleDatapack enables or disables a datapack by renaming it to or from
+// .zip.disabled. On a running server the game then reloads, which drops a
+// disabled pack; an enabled one is also passed to /datapack enable in case
+// the world remembers it as disabled. /datapack disable is not used: it
+// would record the pack as disabled in level.dat, and the game would keep
+// it off after it is enabled again while the server is stopped.
+func (m *Manager) ToggleDatapack(id, fileName string) (*DatapackInfo, error) {
+	cfg, dir, running, err := m.datapackTarget(id)
+	if err != nil {
+		return nil, err
+	}
+	fileName = filepath.Base(fileName)
+	if !extensionFileAllowed(ExtensionDirDatapacks, fileName) {
+		return nil, fmt.Errorf("%s is not a datapack", fileName)
+	}
+	packID, err := datapackPackID(fileName)
+	if err != nil {
+		return nil, err
+	}
+	_, disabled := splitDisabledSuffix(fileName)
+	newName := withDisabledSuffix(fileName, !disabled)
+	oldPath, err := SafePath(dir, fileName)
+	if err != nil {
+		return nil, err
+	}
+	newPath, err := SafePath(dir, newName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(newPath); err == nil {
+		return nil, fmt.Errorf("%s already exists", newName)
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return nil, err
+	}
+	_ = m.moveExtensionRecord(&cfg, extensionRecordName(&cfg, ExtensionDirDatapacks, fileName), extensionRecordName(&cfg, ExtensionDirDatapacks, newName))
+
+	if running {
+		commands := []string{"reload"}
+		if disabled {
+			commands = append(commands, "datapack enable "+quoteCommandArg(packID))
+		}
+		for _, command := range commands {
+			if err := m.SendCommand(id, command); err != nil {
+				log.Printf("[%s] Failed to send %q: %v", cfg.Name, command, err)
+				break
+			}
+		}
+	}
+
+	var size int64
+	if info, err := os.Stat(newPath); err == nil {
+		size = info.Size()
+	}
+	pack := newDatapackInfo(m.loadExtensionManifest(&cfg), &cfg, newPath, size)
+	return &pack, nil
+}
+
+// DeleteDatapack removes a datapack. A running server is told to reload so
+// the pack stops applying.
+func (m *Manager) DeleteDatapack(id, fileName string) error {
+	cfg, dir, running, err := m.datapackTarget(id)
+	if err != nil {
+		return err
+	}
+	fileName = filepath.Base(fileName)
+	if !extensionFileAllowed(ExtensionDirDatapacks, fileName) {
+		return fmt.Errorf("%s is not a datapack", fileName)
+	}
+	path, err := SafePath(dir, fileName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+
+	key := normalizeExtensionSourceKey(extensionRecordName(&cfg, ExtensionDirDatapacks, fileName))
+	if _, ok := m.loadExtensionManifest(&cfg)[key]; ok {
+		if err := m.updateExtensionManifest(&cfg, func(manifest map[string]*ExtensionProvenance) {
+			delete(manifest, key)
+		}); err != nil {
+			return err
+		}
+	}
+	if _, wasDisabled := splitDisabledSuffix(fileName); running && !wasDisabled {
+		if err := m.SendCommand(id, "reload"); err != nil {
+			log.Printf("[%s] Failed to reload datapacks: %v", cfg.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParsePackMcmeta(t *testing.T) {
+	cases := []struct {
+		data        string
+		description string
+		format      int
+	}{
+		{`{"pack":{"pack_format":48,"description":"§aTerrain §roverhaul"}}`, "Terrain overhaul", 48},
+		{"\xef\xbb\xbf" + `{"pack":{"pack_format":15,"description":{"text":"Better ","extra":[{"text":"villages"}]}}}`, "Better villages", 15},
+		{`{"pack":{"pack_format":10,"description":[{"text":"A"},"B"]}}`, "AB", 10},
+		{`not json`, "", 0},
+	}
+	for _, c := range cases {
+		description, format := parsePackMcmeta([]byte(c.data))
+		if description != c.description || format != c.format {
+			t.Fatalf("parsePackMcmeta(%q) = %q, %d; want %q, %d", c.data, description, format, c.description, c.format)
+		}
+	}
+}
+
+func TestDatapackManagement(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv1", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		t.Fatalf("failed to create server dir: %v", err)
+	}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.mu.Unlock()
+
+	if packs, err := mgr.ListDatapacks(cfg.ID); err != nil || len(packs) != 0 {
+		t.Fatalf("expected no datapacks before the world exists, got %+v (%v)", packs, err)
+	}
+
+	upload := filepath.Join(t.TempDir(), "upload.zip")
+	writeTestJar(t, upload, map[string]string{"pack.mcmeta": `{"pack":{"pack_format":48,"description":"Terrain overhaul"}}`})
+	if name, status, err := mgr.UploadDatapack(cfg.ID, "Terralith.zip", upload, ""); err != nil || name != "Terralith.zip" || status != "uploaded" {
+		t.Fatalf("UploadDatapack failed: name=%q status=%q err=%v", name, status, err)
+	}
+	packs, err := mgr.ListDatapacks(cfg.ID)
+	if err != nil || len(packs) != 1 {
+		t.Fatalf("expected one datapack, got %+v (%v)", packs, err)
+	}
+	if p := packs[0]; p.Name != "Terralith" || p.PackID != "file/Terralith.zip" || p.Description != "Terrain overhaul" || p.PackFormat != 48 || !p.Enabled {
+		t.Fatalf("unexpected datapack %+v", p)
+	}
+
+	toggled, err := mgr.ToggleDatapack(cfg.ID, "Terralith.zip")
+	if err != nil || toggled.Enabled || toggled.FileName != "Terralith.zip.disabled" || toggled.PackID != "file/Terralith.zip" || toggled.Description != "Terrain overhaul" {
+		t.Fatalf("expected the pack to be disabled, got %+v (%v)", toggled, err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Dir, "world", "datapacks", "Terralith.zip.disabled")); err != nil {
+		t.Fatalf("expected the disabled file on disk: %v", err)
+	}
+	if _, err := mgr.ToggleDatapack(cfg.ID, "../server.properties"); err == nil {
+		t.Fatal("expected a non-datapack name to be refused")
+	}
+
+	if err := mgr.DeleteDatapack(cfg.ID, "Terralith.zip.disabled"); err != nil {
+		t.Fatalf("DeleteDatapack failed: %v", err)
+	}
+	if packs, err := mgr.ListDatapacks(cfg.ID); err != nil || len(packs) != 0 {
+		t.Fatalf("expected the datapack to be gone, got %+v (%v)", packs, err)
+	}
+	if _, _, err := mgr.UploadDatapack(cfg.ID, "Bad\nreload.zip", upload, ""); err == nil {
+		t.Fatal("expected a name with a line break to be refused")
+	}
+}
@@ -0,0 +1,275 @@
+package minecraft
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// diskQuotaCheckInterval is how often every server with a quota is
+	// measured in the background.
+	diskQuotaCheckInterval = 15 * time.Minute
+	// diskUsageMaxAge is how old a measurement may be before an upload
+	// check walks the server folder again.
+	diskUsageMaxAge = time.Minute
+	maxDiskQuotaGB  = 100000
+)
+
+// ErrDiskQuotaExceeded is returned when an enforced quota refuses an upload
+// or backup.
+var ErrDiskQuotaExceeded = errors.New("disk quota exceeded")
+
+// DiskQuotaSettings caps how much disk a server may use: ServerGB for its
+// folder and BackupsGB for its backups, with 0 meaning no limit. Without
+// Enforce, going over only logs a warning to the console; with it,
+// uploads and backups that would go over are refused. Usage is the latest
+// measurement and is not saved.
+type DiskQuotaSettings struct {
+	ServerGB  float64    `json:"serverGb"`
+	BackupsGB float64    `json:"backupsGb"`
+	Enforce   bool       `json:"enforce"`
+	Usage     *DiskUsage `json:"usage,omitempty"`
+}
+
+// DiskUsage is how much a server's folder and backups took when last
+// measured, and whether either was over its quota.
+type DiskUsage struct {
+	ServerBytes  int64  `json:"serverBytes"`
+	BackupsBytes int64  `json:"backupsBytes"`
+	ServerOver   bool   `json:"serverOver,omitempty"`
+	BackupsOver  bool   `json:"backupsOver,omitempty"`
+	CheckedAt    string `json:"checkedAt"`
+
+	measuredAt time.Time
+}
+
+func quotaBytes(gb float64) int64 {
+	return int64(gb * 1024 * 1024 * 1024)
+}
+
+// GetDiskQuota returns a server's quota with its current usage.
+func (m *Manager) GetDiskQuota(id string) (*DiskQuotaSettings, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		m.mu.RUnlock()
+		return nil, err
+	}
+	cfgCopy := *cfg
+	m.mu.RUnlock()
+
+	settings := DiskQuotaSettings{}
+	if cfgCopy.DiskQuota != nil {
+		settings = *cfgCopy.DiskQuota
+	}
+	usage := m.diskUsageFor(&cfgCopy, diskUsageMaxAge)
+	settings.Usage = &usage
+	return &settings, nil
+}
+
+// SetDiskQuota saves a server's quota and measures it against the new
+// limits right away.
+func (m *Manager) SetDiskQuota(id string, s DiskQuotaSettings) (*DiskQuotaSettings, error) {
+	if s.ServerGB < 0 || s.ServerGB > maxDiskQuotaGB || s.BackupsGB < 0 || s.BackupsGB > maxDiskQuotaGB {
+		return nil, fmt.Errorf("quotas must be between 0 and %d GB", maxDiskQuotaGB)
+	}
+	s.Usage = nil
+
+	m.mu.Lock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	if s.ServerGB == 0 && s.BackupsGB == 0 {
+		cfg.DiskQuota = nil
+	} else {
+		saved := s
+		cfg.DiskQuota = &saved
+	}
+	if err := m.persist(); err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	cfgCopy := *cfg
+	m.mu.Unlock()
+
+	usage := m.refreshDiskUsage(&cfgCopy)
+	s.Usage = &usage
+	return &s, nil
+}
+
+// measureDiskUsage walks a server's folder and backups and compares them
+// with its quota.
+func (m *Manager) measureDiskUsage(cfg *ServerConfig) DiskUsage {
+	now := time.Now()
+	usage := DiskUsage{
+		ServerBytes:  treeSize(cfg.Dir),
+		BackupsBytes: treeSize(m.backupDir(cfg)),
+		CheckedAt:    now.UTC().Format(time.RFC3339),
+		measuredAt:   now,
+	}
+	if q := cfg.DiskQuota; q != nil {
+		usage.ServerOver = q.ServerGB > 0 && usage.ServerBytes > quotaBytes(q.ServerGB)
+		usage.BackupsOver = q.BackupsGB > 0 && usage.BackupsBytes > quotaBytes(q.BackupsGB)
+	}
+	return usage
+}
+
+// refreshDiskUsage measures a server again and remembers the result.
+func (m *Manager) refreshDiskUsage(cfg *ServerConfig) DiskUsage {
+	return m.rememberDiskUsage(cfg, m.measureDiskUsage(cfg))
+}
+
+// rememberDiskUsage stores a measurement and warns on the console when the
+// server has just gone over a quota.
+func (m *Manager) rememberDiskUsage(cfg *ServerConfig, usage DiskUsage) DiskUsage {
+	m.diskUsageMu.Lock()
+	if m.diskUsage == nil {
+		m.diskUsage = make(map[string]DiskUsage)
+	}
+	prev := m.diskUsage[cfg.ID]
+	m.diskUsage[cfg.ID] = usage
+	m.diskUsageMu.Unlock()
+
+	if usage.ServerOver && !prev.ServerOver {
+		m.warnDiskQuota(cfg, fmt.Sprintf("Server folder uses %s, over its %s quota.", formatFileSize(usage.ServerBytes), formatFileSize(quotaBytes(cfg.DiskQuota.ServerGB))))
+	}
+	if usage.BackupsOver && !prev.BackupsOver {
+		m.warnDiskQuota(cfg, fmt.Sprintf("Backups use %s, over their %s quota.", formatFileSize(usage.BackupsBytes), formatFileSize(quotaBytes(cfg.DiskQuota.BackupsGB))))
+	}
+	return usage
+}
+
+// rememberedDiskUsage returns the latest measurement of a server, if any.
+func (m *Manager) rememberedDiskUsage(id string) (DiskUsage, bool) {
+	m.diskUsageMu.Lock()
+	defer m.diskUsageMu.Unlock()
+	usage, ok := m.diskUsage[id]
+	return usage, ok
+}
+
+// diskUsageFor returns the remembered usage of a server when it is newer
+// than maxAge, and measures it again otherwise.
+func (m *Manager) diskUsageFor(cfg *ServerConfig, maxAge time.Duration) DiskUsage {
+	usage, ok := m.rememberedDiskUsage(cfg.ID)
+	if ok && time.Since(usage.measuredAt) < maxAge {
+		return usage
+	}
+	return m.refreshDiskUsage(cfg)
+}
+
+func (m *Manager) warnDiskQuota(cfg *ServerConfig, message string) {
+	if cfg.DiskQuota.Enforce {
+		message += " Uploads and backups are refused until space is freed."
+	}
+	log.Printf("[%s] Disk quota: %s", cfg.Name, message)
+	m.mu.RLock()
+	rs := m.running[cfg.ID]
+	m.mu.RUnlock()
+	if rs != nil {
+		m.broadcastLog(rs, m.appendLog(rs, "[Panel] "+message))
+	}
+}
+
+// CheckUploadQuota is called before size bytes are uploaded into a
+// server's folder. An enforced quota refuses an upload that would go over
+// it. Accepted uploads are counted against the remembered usage, so a
+// burst of uploads cannot slip past between measurements.
+func (m *Manager) CheckUploadQuota(id string, size int64) error {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		m.mu.RUnlock()
+		return err
+	}
+	cfgCopy := *cfg
+	m.mu.RUnlock()
+	quota := cfgCopy.DiskQuota
+	if quota == nil || quota.ServerGB <= 0 {
+		return nil
+	}
+
+	usage := m.diskUsageFor(&cfgCopy, diskUsageMaxAge)
+	limit := quotaBytes(quota.ServerGB)
+	if usage.ServerBytes+size > limit {
+		if quota.Enforce {
+			return fmt.Errorf("%w: the server folder uses %s of its %s quota", ErrDiskQuotaExceeded, formatFileSize(usage.ServerBytes), formatFileSize(limit))
+		}
+		if !usage.ServerOver {
+			usage.ServerOver = true
+			m.warnDiskQuota(&cfgCopy, fmt.Sprintf("An upload takes the server folder over its %s quota.", formatFileSize(limit)))
+		}
+	}
+	usage.ServerBytes += size
+
+	m.diskUsageMu.Lock()
+	m.diskUsage[cfgCopy.ID] = usage
+	m.diskUsageMu.Unlock()
+	return nil
+}
+
+// checkBackupQuota refuses a backup while an enforced backups quota is
+// already used up.
+func (m *Manager) checkBackupQuota(cfg *ServerConfig) error {
+	quota := cfg.DiskQuota
+	if quota == nil || quota.BackupsGB <= 0 || !quota.Enforce {
+		return nil
+	}
+	usage := m.refreshDiskUsage(cfg)
+	if limit := quotaBytes(quota.BackupsGB); usage.BackupsBytes >= limit {
+		return fmt.Errorf("%w: backups use %s of their %s quota", ErrDiskQuotaExceeded, formatFileSize(usage.BackupsBytes), formatFileSize(limit))
+	}
+	return nil
+}
+
+// enforceBackupQuota measures the backups after a new archive was written.
+// When an enforced quota is now exceeded the archive is removed again.
+func (m *Manager) enforceBackupQuota(cfg *ServerConfig, fileName string) error {
+	if cfg.DiskQuota == nil || cfg.DiskQuota.BackupsGB <= 0 {
+		return nil
+	}
+	usage := m.measureDiskUsage(cfg)
+	if !usage.BackupsOver || !cfg.DiskQuota.Enforce {
+		m.rememberDiskUsage(cfg, usage)
+		return nil
+	}
+	if err := os.Remove(filepath.Join(m.backupDir(cfg), fileName)); err != nil {
+		return err
+	}
+	m.refreshDiskUsage(cfg)
+	return fmt.Errorf("%w: %s would take backups to %s, over their %s quota", ErrDiskQuotaExceeded, fileName, formatFileSize(usage.BackupsBytes), formatFileSize(quotaBytes(cfg.DiskQuota.BackupsGB)))
+}
+
+// runDiskQuotaMonitor measures every server with a quota periodically,
+// warning when one goes over.
+func (m *Manager) runDiskQuotaMonitor() {
+	ticker := time.NewTicker(diskQuotaCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopScheduler:
+			return
+		case <-ticker.C:
+			m.checkDiskQuotas()
+		}
+	}
+}
+
+func (m *Manager) checkDiskQuotas() {
+	m.mu.RLock()
+	var configs []ServerConfig
+	for _, cfg := range m.configs {
+		if cfg.DiskQuota != nil {
+			configs = append(configs, *cfg)
+		}
+	}
+	m.mu.RUnlock()
+	for i := range configs {
+		m.refreshDiskUsage(&configs[i])
+	}
+}
@@ -0,0 +1,78 @@
+package minecraft
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiskQuotaUploadsAndBackups(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	rs := &runningServer{status: "Stopped"}
+	cfg := &ServerConfig{ID: "srv", Name: "Srv", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Srv")}
+	mgr.mu.Lock()
+	mgr.configs["srv"] = cfg
+	mgr.running["srv"] = rs
+	mgr.mu.Unlock()
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Dir, "world.dat"), make([]byte, 600<<10), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	oneMB := 1.0 / 1024
+	quota, err := mgr.SetDiskQuota("srv", DiskQuotaSettings{ServerGB: oneMB, BackupsGB: oneMB})
+	if err != nil {
+		t.Fatalf("SetDiskQuota failed: %v", err)
+	}
+	if quota.Usage == nil || quota.Usage.ServerBytes != 600<<10 || quota.Usage.ServerOver {
+		t.Fatalf("expected the folder measured under its quota, got %+v", quota.Usage)
+	}
+
+	// A soft quota lets the upload through and warns on the console.
+	if err := mgr.CheckUploadQuota("srv", 600<<10); err != nil {
+		t.Fatalf("expected a soft quota to allow the upload, got %v", err)
+	}
+	if len(rs.logBuffer) != 1 || !strings.Contains(rs.logBuffer[0].Line, "over its") {
+		t.Fatalf("expected one console warning, got %+v", rs.logBuffer)
+	}
+	if info := mgr.serverInfo("srv"); info.DiskQuota == nil || info.DiskQuota.Usage == nil || info.DiskQuota.Usage.ServerBytes != 1200<<10 {
+		t.Fatalf("expected the accepted upload counted on the server info, got %+v", info.DiskQuota)
+	}
+
+	if _, err := mgr.SetDiskQuota("srv", DiskQuotaSettings{ServerGB: oneMB, BackupsGB: oneMB, Enforce: true}); err != nil {
+		t.Fatalf("SetDiskQuota failed: %v", err)
+	}
+	if err := mgr.CheckUploadQuota("srv", 600<<10); !errors.Is(err, ErrDiskQuotaExceeded) {
+		t.Fatalf("expected an enforced quota to refuse the upload, got %v", err)
+	}
+	if err := mgr.CheckUploadQuota("srv", 100<<10); err != nil {
+		t.Fatalf("expected an upload that fits to be allowed, got %v", err)
+	}
+
+	backups := mgr.backupDir(cfg)
+	if err := os.MkdirAll(backups, 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(backups, "backup_old.tar.gz"), make([]byte, 2<<20), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := mgr.StartBackup("srv", BackupModeFull); !errors.Is(err, ErrDiskQuotaExceeded) {
+		t.Fatalf("expected a full backups quota to refuse the backup, got %v", err)
+	}
+
+	if _, err := mgr.SetDiskQuota("srv", DiskQuotaSettings{ServerGB: -1}); err == nil {
+		t.Fatal("expected a negative quota to be refused")
+	}
+	if _, err := mgr.SetDiskQuota("srv", DiskQuotaSettings{}); err != nil || mgr.configs["srv"].DiskQuota != nil {
+		t.Fatalf("expected zero limits to remove the quota, got %v", err)
+	}
+}
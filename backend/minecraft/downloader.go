@@ -103,14 +103,7 @@ func userAgent() string {
 }
 
 func fetchJSON(ctx context.Context, url string, target interface{}) error {
-	client := &http.Client{Timeout: 30 * time.Second}
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("User-Agent", userAgent())
-	req.Header.Set("Accept", "application/json")
-	resp, err := client.Do(req)
+	resp, err := doAPIGet(ctx, url)
 	if err != nil {
 		return err
 	}
@@ -439,6 +432,12 @@ func (p *FabricProvider) DownloadJar(ctx context.Context, version string, destDi
 		return fmt.Errorf("no Fabric loader versions available")
 	}
 
+	return downloadFabricServerJar(ctx, resolved, loaderVersion, destDir, progressFn)
+}
+
+// downloadFabricServerJar downloads the Fabric server launcher for a given
+// Minecraft and loader version as server.jar.
+func downloadFabricServerJar(ctx context.Context, mcVersion, loaderVersion, destDir string, progressFn func(string)) error {
 	// Get latest stable installer version
 	if progressFn != nil {
 		progressFn("Fetching Fabric installer versions...")
@@ -462,9 +461,9 @@ func (p *FabricProvider) DownloadJar(ctx context.Context, version string, destDi
 		return fmt.Errorf("no Fabric installer versions available")
 	}
 
-	downloadURL := fmt.Sprintf("https://meta.fabricmc.net/v2/versions/loader/%s/%s/%s/server/jar", resolved, loaderVersion, installerVersion)
+	downloadURL := fmt.Sprintf("https://meta.fabricmc.net/v2/versions/loader/%s/%s/%s/server/jar", mcVersion, loaderVersion, installerVersion)
 	if progressFn != nil {
-		progressFn(fmt.Sprintf("Downloading Fabric %s with loader %s (installer %s)...", resolved, loaderVersion, installerVersion))
+		progressFn(fmt.Sprintf("Downloading Fabric %s with loader %s (installer %s)...", mcVersion, loaderVersion, installerVersion))
 	}
 
 	return downloadFile(ctx, downloadURL, filepath.Join(destDir, "server.jar"), progressFn)
@@ -538,14 +537,19 @@ func (p *ForgeProvider) DownloadJar(ctx context.Context, version string, destDir
 		return fmt.Errorf("no Forge build found for MC %s", resolved)
 	}
 
+	return installForgeBuild(ctx, resolved, forgeBuild, destDir, javaExec, progressFn)
+}
+
+// installForgeBuild downloads and runs the installer of one Forge build.
+func installForgeBuild(ctx context.Context, mcVersion, forgeBuild, destDir, javaExec string, progressFn func(string)) error {
 	// Download installer
-	installerName := fmt.Sprintf("forge-%s-%s-installer.jar", resolved, forgeBuild)
+	installerName := fmt.Sprintf("forge-%s-%s-installer.jar", mcVersion, forgeBuild)
 	installerURL := fmt.Sprintf("https://maven.minecraftforge.net/net/minecraftforge/forge/%s-%s/%s",
-		resolved, forgeBuild, installerName)
+		mcVersion, forgeBuild, installerName)
 	installerPath := filepath.Join(destDir, "forge-installer.jar")
 
 	if progressFn != nil {
-		progressFn(fmt.Sprintf("Downloading Forge %s-%s installer...", resolved, forgeBuild))
+		progressFn(fmt.Sprintf("Downloading Forge %s-%s installer...", mcVersion, forgeBuild))
 	}
 
 	if err := downloadFile(ctx, installerURL, installerPath, progressFn); err != nil {
@@ -664,6 +668,12 @@ func (p *NeoForgeProvider) DownloadJar(ctx context.Context, version string, dest
 		return fmt.Errorf("no NeoForge version found for MC %s", resolved)
 	}
 
+	return installNeoForgeVersion(ctx, nfVersion, destDir, javaExec, progressFn)
+}
+
+// installNeoForgeVersion downloads and runs the installer of one NeoForge
+// version.
+func installNeoForgeVersion(ctx context.Context, nfVersion, destDir, javaExec string, progressFn func(string)) error {
 	// Download installer
 	installerName := fmt.Sprintf("neoforge-%s-installer.jar", nfVersion)
 	installerURL := fmt.Sprintf("https://maven.neoforged.net/releases/net/neoforged/neoforge/%s/%s",
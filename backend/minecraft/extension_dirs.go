@@ -0,0 +1,164 @@
+package minecraft
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Extension directories a server's plugins/mods API can address. Hybrid
+// servers load both plugins/ and mods/, and any game server can carry
+// datapacks in its world folder.
+const (
+	ExtensionDirPlugins   = "plugins"
+	ExtensionDirMods      = "mods"
+	ExtensionDirDatapacks = "datapacks"
+)
+
+// defaultExtensionDirName is the directory extensionsDir points at.
+func defaultExtensionDirName(cfg *ServerConfig) string {
+	if isModdedType(cfg.Type) {
+		return ExtensionDirMods
+	}
+	return ExtensionDirPlugins
+}
+
+// allowedExtensionDirNames lists the directories a server may use, default
+// first. Proxies only have plugins.
+func allowedExtensionDirNames(cfg *ServerConfig) []string {
+	if strings.EqualFold(cfg.Type, "Velocity") {
+		return []string{ExtensionDirPlugins}
+	}
+	if isModdedType(cfg.Type) {
+		return []string{ExtensionDirMods, ExtensionDirPlugins, ExtensionDirDatapacks}
+	}
+	return []string{ExtensionDirPlugins, ExtensionDirMods, ExtensionDirDatapacks}
+}
+
+// resolveExtensionDir validates a requested directory name and returns its
+// canonical name and path. An empty name means the server's default.
+func resolveExtensionDir(cfg *ServerConfig, name string) (string, string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = defaultExtensionDirName(cfg)
+	}
+	allowed := false
+	for _, candidate := range allowedExtensionDirNames(cfg) {
+		if candidate == name {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return "", "", fmt.Errorf("%s servers do not support the %q directory", cfg.Type, name)
+	}
+	if name == ExtensionDirDatapacks {
+		return name, filepath.Join(cfg.Dir, serverLevelName(cfg), "datapacks"), nil
+	}
+	return name, filepath.Join(cfg.Dir, name), nil
+}
+
+// splitDisabledSuffix returns fileName without a .disabled suffix, in any
+// case, and whether it had one.
+func splitDisabledSuffix(fileName string) (string, bool) {
+	if strings.HasSuffix(strings.ToLower(fileName), ".disabled") {
+		return fileName[:len(fileName)-len(".disabled")], true
+	}
+	return fileName, false
+}
+
+// withDisabledSuffix names fileName enabled or disabled.
+func withDisabledSuffix(fileName string, disabled bool) string {
+	base, _ := splitDisabledSuffix(fileName)
+	if disabled {
+		return base + ".disabled"
+	}
+	return base
+}
+
+// extensionFileAllowed reports whether fileName (minus any .disabled suffix)
+// belongs in the given directory: jars for plugins/mods, zips for datapacks.
+func extensionFileAllowed(dirName, fileName string) bool {
+	base, _ := splitDisabledSuffix(fileName)
+	lower := strings.ToLower(base)
+	if dirName == ExtensionDirDatapacks {
+		return strings.HasSuffix(lower, ".zip")
+	}
+	return strings.HasSuffix(lower, ".jar")
+}
+
+// extensionRecordName qualifies a file in a non-default directory so its
+// manifest and cache entries cannot collide with a default-directory file
+// of the same name. Default-directory names are left bare, which keeps
+// existing manifests valid.
+func extensionRecordName(cfg *ServerConfig, dirName, fileName string) string {
+	if dirName == "" || dirName == defaultExtensionDirName(cfg) {
+		return fileName
+	}
+	return dirName + "/" + filepath.Base(fileName)
+}
+
+// serverLevelName reads level-name from server.properties, defaulting to
+// "world". Names that would escape the server directory are ignored.
+func serverLevelName(cfg *ServerConfig) string {
+	f, err := os.Open(filepath.Join(cfg.Dir, "server.properties"))
+	if err != nil {
+		return "world"
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "level-name=") {
+			continue
+		}
+		name := strings.TrimSpace(strings.TrimPrefix(line, "level-name="))
+		if name == "" {
+			break
+		}
+		if _, err := SafePath(cfg.Dir, name); err != nil {
+			break
+		}
+		return name
+	}
+	return "world"
+}
+
+// pluginScanServerType picks the server type whose descriptor the jar scan
+// should expect, so a plugin uploaded to plugins/ on a hybrid mod server is
+// not flagged for lacking a mod descriptor (and vice versa).
+func pluginScanServerType(cfg *ServerConfig, dirName string) string {
+	switch {
+	case dirName == ExtensionDirPlugins && isModdedType(cfg.Type):
+		return "Paper"
+	case dirName == ExtensionDirMods && !isModdedType(cfg.Type):
+		return "Forge"
+	}
+	return cfg.Type
+}
+
+// validateDatapackZip checks that an upload is a datapack: a zip with
+// pack.mcmeta at its root and no entries that escape the archive.
+func validateDatapackZip(path string) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("file is not a valid zip archive")
+	}
+	defer r.Close()
+	hasMeta := false
+	for _, f := range r.File {
+		if unsafeZipEntryName(f.Name) {
+			return fmt.Errorf("datapack contains unsafe entry path %q", f.Name)
+		}
+		if f.Name == "pack.mcmeta" {
+			hasMeta = true
+		}
+	}
+	if !hasMeta {
+		return fmt.Errorf("datapack is missing pack.mcmeta")
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtensionDirectories(t *testing.T) {
+	t.Setenv("ADPANEL_VIRUSTOTAL_API_KEY", "")
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv1", Name: "Hybrid", Type: "Forge", Dir: filepath.Join(mgr.serversRoot, "Hybrid")}
+	if err := os.MkdirAll(filepath.Join(cfg.Dir, "mods"), 0755); err != nil {
+		t.Fatalf("failed to create mods dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Dir, "server.properties"), []byte("level-name=survival\n"), 0644); err != nil {
+		t.Fatalf("failed to write server.properties: %v", err)
+	}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.mu.Unlock()
+
+	writeTestJar(t, filepath.Join(cfg.Dir, "mods", "Sodium.jar"), map[string]string{"fabric.mod.json": `{"id":"sodium","version":"1.0.0"}`})
+
+	staged := filepath.Join(t.TempDir(), "Essentials.jar")
+	writeTestJar(t, staged, map[string]string{"plugin.yml": "name: Essentials\nversion: 2.0.0\n"})
+	if _, status, err := mgr.UploadPluginFromFile(cfg.ID, "plugins", "Essentials.jar", staged, ""); err != nil || status != "uploaded" {
+		t.Fatalf("plugin upload to plugins/ failed: status=%q err=%v", status, err)
+	}
+
+	pack := filepath.Join(t.TempDir(), "pack.zip")
+	writeTestJar(t, pack, map[string]string{"pack.mcmeta": `{"pack":{"pack_format":48}}`, "data/x/function/a.mcfunction": "say hi"})
+	if _, _, err := mgr.UploadPluginFromFile(cfg.ID, "datapacks", "Terrain.zip", pack, ""); err != nil {
+		t.Fatalf("datapack upload failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Dir, "survival", "datapacks", "Terrain.zip")); err != nil {
+		t.Fatalf("expected datapack in the level's datapacks dir: %v", err)
+	}
+
+	plugins, err := mgr.ListPlugins(cfg.ID)
+	if err != nil {
+		t.Fatalf("ListPlugins failed: %v", err)
+	}
+	dirs := map[string]string{}
+	for _, p := range plugins {
+		dirs[p.FileName] = p.Directory
+	}
+	if dirs["Sodium.jar"] != "mods" || dirs["Essentials.jar"] != "plugins" || dirs["Terrain.zip"] != "datapacks" {
+		t.Fatalf("unexpected directories: %v", dirs)
+	}
+
+	toggled, err := mgr.TogglePlugin(cfg.ID, "datapacks", "Terrain.zip")
+	if err != nil || toggled.Enabled || toggled.FileName != "Terrain.zip.disabled" {
+		t.Fatalf("toggle failed: %+v (%v)", toggled, err)
+	}
+	if err := mgr.DeletePlugin(cfg.ID, "datapacks", "Terrain.zip.disabled"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if err := mgr.DeletePlugin(cfg.ID, "datapacks", "Sodium.jar"); err == nil {
+		t.Fatalf("expected a jar name to be rejected for datapacks")
+	}
+
+	broken := filepath.Join(t.TempDir(), "broken.zip")
+	writeTestJar(t, broken, map[string]string{"readme.txt": "not a datapack"})
+	if _, _, err := mgr.UploadPluginFromFile(cfg.ID, "datapacks", "Broken.zip", broken, ""); err == nil {
+		t.Fatalf("expected datapack without pack.mcmeta to be rejected")
+	}
+	if _, _, err := mgr.UploadPluginFromFile(cfg.ID, "config", "x.jar", broken, ""); err == nil {
+		t.Fatalf("expected unknown directory to be rejected")
+	}
+}
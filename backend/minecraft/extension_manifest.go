@@ -0,0 +1,183 @@
+package minecraft
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// How an installed plugin/mod jar got onto the server.
+const (
+	ExtensionOriginUpload = "upload"
+	ExtensionOriginUpdate = "update"
+	ExtensionOriginURL    = "url"
+)
+
+// ExtensionProvenance is the manifest entry for one installed plugin/mod jar.
+type ExtensionProvenance struct {
+	SourceURL   string `json:"sourceUrl,omitempty"`
+	Origin      string `json:"origin,omitempty"`
+	DownloadURL string `json:"downloadUrl,omitempty"`
+	InstalledAt string `json:"installedAt,omitempty"`
+	UpdatedAt   string `json:"updatedAt,omitempty"`
+	SHA256      string `json:"sha256,omitempty"`
+
+	// Note is free text from the admin, typically why the entry is disabled.
+	Note          string `json:"note,omitempty"`
+	NoteUpdatedAt string `json:"noteUpdatedAt,omitempty"`
+}
+
+func (p *ExtensionProvenance) empty() bool {
+	return p == nil || *p == ExtensionProvenance{}
+}
+
+// extensionManifestMu serializes read-modify-write cycles on manifest files.
+var extensionManifestMu sync.Mutex
+
+// decodeExtensionManifest reads a manifest file. Older files mapped each jar
+// straight to its source URL; those entries are upgraded in place.
+func decodeExtensionManifest(data []byte) (map[string]*ExtensionProvenance, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	manifest := make(map[string]*ExtensionProvenance, len(raw))
+	for key, value := range raw {
+		var sourceURL string
+		if err := json.Unmarshal(value, &sourceURL); err == nil {
+			if strings.TrimSpace(sourceURL) != "" {
+				manifest[key] = &ExtensionProvenance{SourceURL: strings.TrimSpace(sourceURL)}
+			}
+			continue
+		}
+		var entry ExtensionProvenance
+		if err := json.Unmarshal(value, &entry); err != nil {
+			return nil, fmt.Errorf("invalid manifest entry %q: %w", key, err)
+		}
+		if !entry.empty() {
+			manifest[key] = &entry
+		}
+	}
+	return manifest, nil
+}
+
+func (m *Manager) loadExtensionManifest(cfg *ServerConfig) map[string]*ExtensionProvenance {
+	data, err := os.ReadFile(m.extensionSourcesPath(cfg))
+	if err != nil {
+		return map[string]*ExtensionProvenance{}
+	}
+	manifest, err := decodeExtensionManifest(data)
+	if err != nil {
+		return map[string]*ExtensionProvenance{}
+	}
+	return manifest
+}
+
+func (m *Manager) saveExtensionManifest(cfg *ServerConfig, manifest map[string]*ExtensionProvenance) error {
+	for key, entry := range manifest {
+		if entry.empty() {
+			delete(manifest, key)
+		}
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := m.extensionSourcesPath(cfg)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, path)
+}
+
+// updateExtensionManifest applies fn to the server's manifest and saves it.
+func (m *Manager) updateExtensionManifest(cfg *ServerConfig, fn func(manifest map[string]*ExtensionProvenance)) error {
+	extensionManifestMu.Lock()
+	defer extensionManifestMu.Unlock()
+	manifest := m.loadExtensionManifest(cfg)
+	fn(manifest)
+	return m.saveExtensionManifest(cfg, manifest)
+}
+
+// recordExtensionInstall stores how a jar was installed along with its hash.
+// A fresh install restarts the history; an update keeps the original install
+// time and source link. Notes survive both. A non-empty sourceURL replaces
+// the stored link.
+func (m *Manager) recordExtensionInstall(cfg *ServerConfig, fileName, origin, sourceURL, downloadURL, jarPath string) error {
+	hash, err := fileSHA256(jarPath)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", filepath.Base(jarPath), err)
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	key := normalizeExtensionSourceKey(fileName)
+	return m.updateExtensionManifest(cfg, func(manifest map[string]*ExtensionProvenance) {
+		entry := manifest[key]
+		if entry == nil || origin != ExtensionOriginUpdate {
+			fresh := &ExtensionProvenance{InstalledAt: now}
+			if entry != nil {
+				fresh.SourceURL = entry.SourceURL
+				fresh.Note = entry.Note
+				fresh.NoteUpdatedAt = entry.NoteUpdatedAt
+			}
+			entry = fresh
+		}
+		if entry.InstalledAt == "" {
+			entry.InstalledAt = now
+		}
+		if strings.TrimSpace(sourceURL) != "" {
+			entry.SourceURL = strings.TrimSpace(sourceURL)
+		}
+		entry.Origin = origin
+		entry.DownloadURL = strings.TrimSpace(downloadURL)
+		entry.UpdatedAt = now
+		entry.SHA256 = hash
+		manifest[key] = entry
+	})
+}
+
+// moveExtensionRecord carries a jar's manifest entry over to its new file name.
+func (m *Manager) moveExtensionRecord(cfg *ServerConfig, oldName, newName string) error {
+	oldKey, newKey := normalizeExtensionSourceKey(oldName), normalizeExtensionSourceKey(newName)
+	if oldKey == newKey {
+		return nil
+	}
+	return m.updateExtensionManifest(cfg, func(manifest map[string]*ExtensionProvenance) {
+		if entry, ok := manifest[oldKey]; ok {
+			manifest[newKey] = entry
+			delete(manifest, oldKey)
+		}
+	})
+}
+
+func provenanceForFile(manifest map[string]*ExtensionProvenance, fileName string) *ExtensionProvenance {
+	entry, ok := manifest[normalizeExtensionSourceKey(fileName)]
+	if !ok || entry.empty() {
+		return nil
+	}
+	copied := *entry
+	return &copied
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
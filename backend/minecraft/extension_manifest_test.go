@@ -0,0 +1,82 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDecodeExtensionManifestUpgradesLegacyEntries(t *testing.T) {
+	manifest, err := decodeExtensionManifest([]byte(`{
+		"essentials": "https://modrinth.com/project/essentialsx",
+		"empty": "  ",
+		"luckperms": {"sourceUrl": "https://www.spigotmc.org/resources/28140/", "origin": "update"}
+	}`))
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if got := manifest["essentials"]; got == nil || got.SourceURL != "https://modrinth.com/project/essentialsx" {
+		t.Fatalf("expected legacy entry to be upgraded, got %+v", got)
+	}
+	if _, ok := manifest["empty"]; ok {
+		t.Fatalf("expected blank legacy entry to be dropped")
+	}
+	if got := manifest["luckperms"]; got == nil || got.Origin != ExtensionOriginUpdate {
+		t.Fatalf("expected structured entry to be kept, got %+v", got)
+	}
+}
+
+func TestRecordExtensionInstallTracksProvenance(t *testing.T) {
+	base := t.TempDir()
+	mgr := &Manager{baseDir: base}
+	cfg := &ServerConfig{ID: "srv1", Name: "Lobby", Type: "Paper", Dir: filepath.Join(base, "Servers", "Lobby")}
+	pluginsDir := filepath.Join(cfg.Dir, "plugins")
+	if err := os.MkdirAll(pluginsDir, 0755); err != nil {
+		t.Fatalf("failed to create plugins dir: %v", err)
+	}
+	jar := filepath.Join(pluginsDir, "Essentials.jar")
+	if err := os.WriteFile(jar, []byte("v1"), 0644); err != nil {
+		t.Fatalf("failed to write jar: %v", err)
+	}
+	if err := mgr.updateExtensionManifest(cfg, func(manifest map[string]*ExtensionProvenance) {
+		manifest["Essentials.jar"] = &ExtensionProvenance{SourceURL: "https://modrinth.com/project/essentialsx"}
+	}); err != nil {
+		t.Fatalf("failed to seed manifest: %v", err)
+	}
+
+	if err := mgr.recordExtensionInstall(cfg, "Essentials.jar", ExtensionOriginUpload, "", "", jar); err != nil {
+		t.Fatalf("record upload failed: %v", err)
+	}
+	uploaded := provenanceForFile(mgr.loadExtensionManifest(cfg), "Essentials.jar")
+	if uploaded == nil || uploaded.Origin != ExtensionOriginUpload || uploaded.InstalledAt == "" || uploaded.SHA256 == "" {
+		t.Fatalf("unexpected upload provenance: %+v", uploaded)
+	}
+	if uploaded.SourceURL == "" {
+		t.Fatalf("expected upload to keep the existing source link")
+	}
+
+	updatedJar := filepath.Join(pluginsDir, "Essentials-2.jar")
+	if err := os.WriteFile(updatedJar, []byte("v2"), 0644); err != nil {
+		t.Fatalf("failed to write updated jar: %v", err)
+	}
+	if err := mgr.moveExtensionRecord(cfg, "Essentials.jar", "Essentials-2.jar"); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	if err := mgr.recordExtensionInstall(cfg, "Essentials-2.jar", ExtensionOriginUpdate, "", "https://cdn.modrinth.com/e2.jar", updatedJar); err != nil {
+		t.Fatalf("record update failed: %v", err)
+	}
+	manifest := mgr.loadExtensionManifest(cfg)
+	if old := provenanceForFile(manifest, "Essentials.jar"); old != nil {
+		t.Fatalf("expected old entry to be moved, got %+v", old)
+	}
+	updated := provenanceForFile(manifest, "Essentials-2.jar")
+	if updated == nil || updated.Origin != ExtensionOriginUpdate || updated.DownloadURL != "https://cdn.modrinth.com/e2.jar" {
+		t.Fatalf("unexpected update provenance: %+v", updated)
+	}
+	if updated.InstalledAt != uploaded.InstalledAt || updated.SourceURL != uploaded.SourceURL {
+		t.Fatalf("expected update to keep install time and source, got %+v", updated)
+	}
+	if updated.SHA256 == uploaded.SHA256 {
+		t.Fatalf("expected hash to change with the jar contents")
+	}
+}
@@ -0,0 +1,65 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPluginNoteFollowsToggle(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv1", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	if err := os.MkdirAll(filepath.Join(cfg.Dir, "plugins"), 0755); err != nil {
+		t.Fatalf("failed to create plugins dir: %v", err)
+	}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.mu.Unlock()
+
+	writeTestJar(t, filepath.Join(cfg.Dir, "plugins", "Essentials.jar"), map[string]string{"plugin.yml": "name: Essentials\nversion: 2.0.0\n"})
+
+	toggled, err := mgr.TogglePlugin(cfg.ID, "", "Essentials.jar")
+	if err != nil {
+		t.Fatalf("toggle failed: %v", err)
+	}
+	note := "disabled 2025-01-10: crashes with 1.21.4, waiting for update"
+	if err := mgr.SetPluginNote(cfg.ID, "", toggled.FileName, note); err != nil {
+		t.Fatalf("SetPluginNote failed: %v", err)
+	}
+
+	plugins, err := mgr.ListPlugins(cfg.ID)
+	if err != nil || len(plugins) != 1 {
+		t.Fatalf("ListPlugins = %+v, %v", plugins, err)
+	}
+	if plugins[0].Note != note || plugins[0].Provenance == nil || plugins[0].Provenance.NoteUpdatedAt == "" {
+		t.Fatalf("expected note on listed plugin, got %+v", plugins[0])
+	}
+
+	enabled, err := mgr.TogglePlugin(cfg.ID, "", toggled.FileName)
+	if err != nil {
+		t.Fatalf("re-enable failed: %v", err)
+	}
+	if enabled.Note != note {
+		t.Fatalf("expected note to follow the rename, got %q", enabled.Note)
+	}
+
+	if err := mgr.SetPluginNote(cfg.ID, "", "Essentials.jar", strings.Repeat("x", maxExtensionNoteLength+1)); err == nil {
+		t.Fatalf("expected overlong note to be rejected")
+	}
+	if err := mgr.SetPluginNote(cfg.ID, "", "Missing.jar", "note"); err == nil {
+		t.Fatalf("expected note on a missing file to be rejected")
+	}
+
+	if err := mgr.SetPluginNote(cfg.ID, "", "Essentials.jar", ""); err != nil {
+		t.Fatalf("clearing note failed: %v", err)
+	}
+	if _, ok := mgr.loadExtensionManifest(cfg)["Essentials.jar"]; ok {
+		t.Fatalf("expected cleared note to drop the empty manifest entry")
+	}
+}
@@ -0,0 +1,155 @@
+package minecraft
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// deletingDirName holds folders that have been taken out of the server
+	// directory and are being deleted in the background. It is hidden from
+	// the file browser and left out of backups.
+	deletingDirName = ".adpanel-deleting"
+	// Folders with more files or bytes than this are deleted by a job.
+	asyncDeleteMinFiles = 2000
+	asyncDeleteMinBytes = 512 << 20
+)
+
+// DeletePreview describes what deleting a path would remove, so a 40 GB
+// world is not deleted by accident. Async is set when the deletion would
+// run as a job.
+type DeletePreview struct {
+	Path    string `json:"path"`
+	IsDir   bool   `json:"isDir"`
+	Size    int64  `json:"size"`
+	Files   int    `json:"files"`
+	Folders int    `json:"folders"`
+	Async   bool   `json:"async"`
+}
+
+// measureDeleteTarget counts the files, folders and bytes under path.
+func measureDeleteTarget(path string) (*DeletePreview, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+	preview := &DeletePreview{IsDir: info.IsDir()}
+	if !info.IsDir() {
+		preview.Files = 1
+		preview.Size = info.Size()
+		return preview, nil
+	}
+	_ = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == path {
+			return nil
+		}
+		if d.IsDir() {
+			preview.Folders++
+			return nil
+		}
+		preview.Files++
+		if d.Type().IsRegular() {
+			if fi, err := d.Info(); err == nil {
+				preview.Size += fi.Size()
+			}
+		}
+		return nil
+	})
+	preview.Async = preview.Files >= asyncDeleteMinFiles || preview.Size >= asyncDeleteMinBytes
+	return preview, nil
+}
+
+// PreviewDelete returns the size and file count of a path before it is
+// deleted through the file API.
+func (m *Manager) PreviewDelete(id, subPath string) (*DeletePreview, error) {
+	_, targetPath, err := m.deleteTarget(id, subPath)
+	if err != nil {
+		return nil, err
+	}
+	preview, err := measureDeleteTarget(targetPath)
+	if err != nil {
+		return nil, err
+	}
+	preview.Path = filepath.ToSlash(filepath.Clean(subPath))
+	return preview, nil
+}
+
+// StartDeletePath deletes a path through the file API. Small paths are
+// removed right away and nil is returned. Large folders are first moved
+// into the hidden deleting folder, so they vanish from the server at once
+// and their name can be reused, then removed by a job that is returned.
+func (m *Manager) StartDeletePath(id, subPath string) (*Job, error) {
+	cfg, targetPath, err := m.deleteTarget(id, subPath)
+	if err != nil {
+		return nil, err
+	}
+	preview, err := measureDeleteTarget(targetPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !preview.Async {
+		return nil, os.RemoveAll(targetPath)
+	}
+
+	trashDir := filepath.Join(cfg.Dir, deletingDirName)
+	if err := os.MkdirAll(trashDir, 0755); err != nil {
+		return nil, err
+	}
+	trashPath := filepath.Join(trashDir, uuid.New().String())
+	if err := os.Rename(targetPath, trashPath); err != nil {
+		return nil, fmt.Errorf("failed to move %s aside for deletion: %w", subPath, err)
+	}
+
+	job := m.newJob(JobTypeFileDelete, id)
+	go func() {
+		job.finish(m.deletePathJob(job, cfg, subPath, trashPath, preview))
+	}()
+	return m.GetJob(job.id)
+}
+
+// deletePathJob removes a folder moved aside by StartDeletePath one entry
+// at a time, reporting progress by files removed.
+func (m *Manager) deletePathJob(job *jobHandle, cfg *ServerConfig, subPath, trashPath string, preview *DeletePreview) error {
+	job.start(fmt.Sprintf("Deleting %s (%s, %d files)", filepath.ToSlash(subPath), formatFileSize(preview.Size), preview.Files))
+	defer os.Remove(filepath.Dir(trashPath))
+
+	removed := 0
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			if entry.IsDir() {
+				if err := walk(path); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			removed++
+			if removed%200 == 0 && preview.Files > 0 {
+				job.progress(removed*99/preview.Files, "")
+			}
+		}
+		return os.Remove(dir)
+	}
+	if err := walk(trashPath); err != nil {
+		log.Printf("[%s] Failed to delete %s: %v", cfg.Name, filepath.ToSlash(subPath), err)
+		return fmt.Errorf("failed to delete %s: %w", filepath.ToSlash(subPath), err)
+	}
+	log.Printf("[%s] Deleted %s (%s, %d files)", cfg.Name, filepath.ToSlash(subPath), formatFileSize(preview.Size), preview.Files)
+	return nil
+}
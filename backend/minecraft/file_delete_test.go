@@ -0,0 +1,86 @@
+package minecraft
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDeletePreviewAndAsyncDelete(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv", Name: "Srv", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Srv")}
+	mgr.mu.Lock()
+	mgr.configs["srv"] = cfg
+	mgr.mu.Unlock()
+	region := filepath.Join(cfg.Dir, "world", "region")
+	if err := os.MkdirAll(region, 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	for i := 0; i < asyncDeleteMinFiles; i++ {
+		if err := os.WriteFile(filepath.Join(region, fmt.Sprintf("r.%d.0.mca", i)), []byte("chunk"), 0644); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	os.WriteFile(filepath.Join(cfg.Dir, "world", "level.dat"), []byte("level"), 0644)
+	os.MkdirAll(filepath.Join(cfg.Dir, "logs"), 0755)
+	os.WriteFile(filepath.Join(cfg.Dir, "logs", "latest.log"), []byte("log"), 0644)
+
+	preview, err := mgr.PreviewDelete("srv", "world")
+	if err != nil {
+		t.Fatalf("PreviewDelete failed: %v", err)
+	}
+	if !preview.IsDir || preview.Files != asyncDeleteMinFiles+1 || preview.Folders != 1 || preview.Size != int64(asyncDeleteMinFiles*5+5) || !preview.Async {
+		t.Fatalf("unexpected preview %+v", preview)
+	}
+	if _, err := mgr.PreviewDelete("srv", "."); err == nil {
+		t.Fatal("expected the server root to be refused")
+	}
+
+	// Small folders are deleted right away.
+	job, err := mgr.StartDeletePath("srv", "logs")
+	if err != nil || job != nil {
+		t.Fatalf("expected logs deleted inline, got %+v, %v", job, err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Dir, "logs")); !os.IsNotExist(err) {
+		t.Fatalf("expected logs gone, got %v", err)
+	}
+
+	// Large ones vanish at once and are removed by a job.
+	job, err = mgr.StartDeletePath("srv", "world")
+	if err != nil || job == nil || job.Type != JobTypeFileDelete {
+		t.Fatalf("expected a delete job, got %+v, %v", job, err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Dir, "world")); !os.IsNotExist(err) {
+		t.Fatalf("expected world moved aside, got %v", err)
+	}
+	files, err := mgr.ListFiles("srv", ".")
+	if err != nil {
+		t.Fatalf("ListFiles failed: %v", err)
+	}
+	for _, f := range files {
+		if f.Name == deletingDirName {
+			t.Fatal("expected the deleting folder hidden from the file browser")
+		}
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		current, _ := mgr.GetJob(job.ID)
+		if current != nil && current.State == JobStateSucceeded {
+			break
+		}
+		if current == nil || current.State == JobStateFailed || time.Now().After(deadline) {
+			t.Fatalf("delete job did not succeed: %+v", current)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Dir, deletingDirName)); !os.IsNotExist(err) {
+		t.Fatalf("expected the deleting folder cleaned up, got %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+package minecraft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Backup modes selectable for scheduled and manual backups.
+const (
+	BackupModeFull        = "full"
+	BackupModeIncremental = "incremental"
+)
+
+// Incremental snapshots live in their own folder inside a server's backups
+// folder, one directory per snapshot plus a <name>.json manifest beside it.
+const (
+	incrementalBackupDir    = "incremental"
+	incrementalBackupPrefix = "incremental_"
+)
+
+// incrementalManifest describes a finished snapshot. A snapshot directory
+// without one is incomplete and ignored.
+type incrementalManifest struct {
+	Created     string `json:"created"`
+	Base        string `json:"base,omitempty"`
+	Files       int    `json:"files"`
+	BytesTotal  int64  `json:"bytesTotal"`
+	BytesCopied int64  `json:"bytesCopied"`
+}
+
+func isIncrementalBackup(name string) bool {
+	return strings.HasPrefix(name, incrementalBackupPrefix)
+}
+
+func validBackupMode(mode string) bool {
+	return mode == "" || mode == BackupModeFull || mode == BackupModeIncremental
+}
+
+func (m *Manager) incrementalBackupRoot(cfg *ServerConfig) string {
+	return filepath.Join(m.backupDir(cfg), incrementalBackupDir)
+}
+
+func readIncrementalManifest(root, name string) (*incrementalManifest, error) {
+	data, err := os.ReadFile(filepath.Join(root, name+".json"))
+	if err != nil {
+		return nil, err
+	}
+	var manifest incrementalManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// listIncrementalSnapshots returns the complete snapshots under root,
+// oldest first.
+func listIncrementalSnapshots(root string) ([]string, map[string]*incrementalManifest) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, nil
+	}
+	var names []string
+	manifests := make(map[string]*incrementalManifest)
+	for _, entry := range entries {
+		if !entry.IsDir() || !isIncrementalBackup(entry.Name()) {
+			continue
+		}
+		manifest, err := readIncrementalManifest(root, entry.Name())
+		if err != nil {
+			continue
+		}
+		names = append(names, entry.Name())
+		manifests[entry.Name()] = manifest
+	}
+	sort.Strings(names)
+	return names, manifests
+}
+
+// incrementalBackupInfos lists a server's snapshots for ListBackups.
+func (m *Manager) incrementalBackupInfos(cfg *ServerConfig) []BackupInfo {
+	names, manifests := listIncrementalSnapshots(m.incrementalBackupRoot(cfg))
+	infos := make([]BackupInfo, 0, len(names))
+	for _, name := range names {
+		manifest := manifests[name]
+		infos = append(infos, BackupInfo{
+			Name:    name,
+			Date:    manifest.Created,
+			Size:    formatFileSize(manifest.BytesTotal),
+			Kind:    BackupModeIncremental,
+			NewData: formatFileSize(manifest.BytesCopied),
+		})
+	}
+	return infos
+}
+
+// writeIncrementalBackup snapshots the server directory. Files unchanged
+// since the previous snapshot (same size and modification time) are hard
+// links to it, so each snapshot is a complete tree but only changed files
+// take new space. Progress is reported between from and to percent.
+// Callers hold the server's operation lock.
+func (m *Manager) writeIncrementalBackup(job *jobHandle, cfg *ServerConfig, from, to int) (*BackupInfo, error) {
+	if err := m.validateManagedBackupDir(m.backupDir(cfg)); err != nil {
+		return nil, err
+	}
+	root := m.incrementalBackupRoot(cfg)
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+
+	name := incrementalBackupPrefix + time.Now().Format("2006-01-02_15-04-05")
+	if _, err := os.Stat(filepath.Join(root, name)); err == nil {
+		return nil, fmt.Errorf("snapshot %s already exists", name)
+	}
+	manifest := incrementalManifest{Created: time.Now().UTC().Format(time.RFC3339)}
+	baseDir := ""
+	if names, _ := listIncrementalSnapshots(root); len(names) > 0 {
+		manifest.Base = names[len(names)-1]
+		baseDir = filepath.Join(root, manifest.Base)
+	}
+
+	total, err := backupSourceSize(job.ctx, cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	tmp := filepath.Join(root, "."+name+".partial")
+	if err := os.RemoveAll(tmp); err != nil {
+		return nil, err
+	}
+	if err := os.Mkdir(tmp, 0755); err != nil {
+		return nil, err
+	}
+	fail := func(err error) (*BackupInfo, error) {
+		if rmErr := os.RemoveAll(tmp); rmErr != nil {
+			log.Printf("Failed to remove partial snapshot %s: %v", tmp, rmErr)
+		}
+		return nil, fmt.Errorf("incremental backup failed: %w", err)
+	}
+
+	var done int64
+	throttled := throttledReport(&done, total, func(done, total int64) {
+		job.transfer(done, total, from, to)
+	})
+	err = walkBackupSource(job.ctx, cfg.Dir, func(path, rel string, info os.FileInfo) error {
+		dst := filepath.Join(tmp, rel)
+		switch {
+		case info.IsDir():
+			return os.Mkdir(dst, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, dst)
+		}
+		manifest.Files++
+		if baseDir != "" {
+			base := filepath.Join(baseDir, rel)
+			if bi, err := os.Lstat(base); err == nil && bi.Mode().IsRegular() &&
+				bi.Size() == info.Size() && bi.ModTime().Equal(info.ModTime()) {
+				// A failed link, for example across filesystems, falls
+				// back to a copy.
+				if os.Link(base, dst) == nil {
+					done += info.Size()
+					throttled()
+					return nil
+				}
+			}
+		}
+		before := done
+		if err := copyFileContents(job.ctx, path, dst, info, &done, throttled); err != nil {
+			if os.IsNotExist(err) {
+				manifest.Files--
+				return nil
+			}
+			return fmt.Errorf("failed to copy %s: %w", rel, err)
+		}
+		manifest.BytesCopied += done - before
+		return nil
+	})
+	if err != nil {
+		return fail(err)
+	}
+	manifest.BytesTotal = done
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fail(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, name+".json"), data, 0644); err != nil {
+		return fail(err)
+	}
+	if err := os.Rename(tmp, filepath.Join(root, name)); err != nil {
+		_ = os.Remove(filepath.Join(root, name+".json"))
+		return fail(err)
+	}
+	job.transfer(done, total, from, to)
+	job.log(fmt.Sprintf("Created %s (%s new of %s)", name, formatFileSize(manifest.BytesCopied), formatFileSize(manifest.BytesTotal)))
+
+	return &BackupInfo{
+		Name:    name,
+		Date:    manifest.Created,
+		Size:    formatFileSize(manifest.BytesTotal),
+		Kind:    BackupModeIncremental,
+		NewData: formatFileSize(manifest.BytesCopied),
+	}, nil
+}
+
+// copyFileContents copies a regular file, keeping its permissions and
+// modification time so the next snapshot can tell it is unchanged.
+func copyFileContents(ctx context.Context, src, dst string, info os.FileInfo, done *int64, report func()) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, &progressReader{ctx: ctx, r: in, done: done, report: report}); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// restoreIncrementalSnapshot copies a snapshot into dest. Files are copied
+// rather than linked so the running server cannot change the snapshot.
+func restoreIncrementalSnapshot(ctx context.Context, snapshot, dest string) error {
+	return copyTree(ctx, snapshot, dest, nil)
+}
+
+// resolveBackup finds a full archive or incremental snapshot by name in a
+// server's backups folder.
+func (m *Manager) resolveBackup(cfg *ServerConfig, name string) (string, bool, error) {
+	backupsDir := m.backupDir(cfg)
+	if err := m.validateManagedBackupDir(backupsDir); err != nil {
+		return "", false, err
+	}
+	incremental := isIncrementalBackup(name)
+	base := backupsDir
+	if incremental {
+		base = m.incrementalBackupRoot(cfg)
+	}
+	path, err := SafePath(base, name)
+	if err != nil {
+		return "", false, err
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() != incremental {
+		return "", false, fmt.Errorf("backup %s not found", name)
+	}
+	if incremental {
+		if _, err := readIncrementalManifest(base, name); err != nil {
+			return "", false, fmt.Errorf("backup %s is incomplete", name)
+		}
+	}
+	return path, incremental, nil
+}
@@ -0,0 +1,127 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIncrementalBackupsLinkUnchangedFiles(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	write := func(rel, content string) {
+		path := filepath.Join(cfg.Dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", rel, err)
+		}
+	}
+	write("world/region/r.0.0.mca", "old region")
+	write("world/level.dat", "level v1")
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = &runningServer{status: "Stopped"}
+	mgr.mu.Unlock()
+
+	first, err := mgr.createBackup(cfg.ID, BackupModeIncremental)
+	if err != nil {
+		t.Fatalf("first incremental backup failed: %v", err)
+	}
+
+	// Snapshot names have one-second resolution.
+	time.Sleep(1100 * time.Millisecond)
+	write("world/level.dat", "level v2")
+	second, err := mgr.createBackup(cfg.ID, BackupModeIncremental)
+	if err != nil {
+		t.Fatalf("second incremental backup failed: %v", err)
+	}
+	if second.Kind != BackupModeIncremental || second.NewData != formatFileSize(int64(len("level v2"))) {
+		t.Fatalf("unexpected second snapshot: %+v", second)
+	}
+
+	root := mgr.incrementalBackupRoot(cfg)
+	sameFile := func(rel string) bool {
+		a, errA := os.Stat(filepath.Join(root, first.Name, rel))
+		b, errB := os.Stat(filepath.Join(root, second.Name, rel))
+		return errA == nil && errB == nil && os.SameFile(a, b)
+	}
+	if !sameFile("world/region/r.0.0.mca") {
+		t.Fatalf("expected the unchanged region to be hard-linked")
+	}
+	if sameFile("world/level.dat") {
+		t.Fatalf("expected the changed level.dat to be copied")
+	}
+
+	backups, err := mgr.ListBackups(cfg.ID)
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(backups) != 2 || backups[0].Name != second.Name || backups[1].Kind != BackupModeIncremental {
+		t.Fatalf("unexpected backup list: %+v", backups)
+	}
+	if _, err := mgr.GetBackupPath(cfg.ID, first.Name); err == nil {
+		t.Fatalf("expected snapshots to refuse direct download")
+	}
+
+	// Deleting the first snapshot leaves the second complete.
+	if err := mgr.DeleteBackup(cfg.ID, first.Name); err != nil {
+		t.Fatalf("DeleteBackup failed: %v", err)
+	}
+	write("world/level.dat", "level v3")
+	write("world/extra.dat", "stray")
+	if err := mgr.RestoreBackup(cfg.ID, second.Name); err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+	for rel, want := range map[string]string{"world/region/r.0.0.mca": "old region", "world/level.dat": "level v2"} {
+		if data, err := os.ReadFile(filepath.Join(cfg.Dir, filepath.FromSlash(rel))); err != nil || string(data) != want {
+			t.Fatalf("expected %s to be %q after restore, got %q (%v)", rel, want, data, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Dir, "world", "extra.dat")); !os.IsNotExist(err) {
+		t.Fatalf("expected files newer than the snapshot to be gone, got %v", err)
+	}
+
+	// The restored files are copies, so the server cannot change the snapshot.
+	write("world/region/r.0.0.mca", "new region")
+	if data, _ := os.ReadFile(filepath.Join(root, second.Name, "world", "region", "r.0.0.mca")); string(data) != "old region" {
+		t.Fatalf("expected the snapshot to be unaffected, got %q", data)
+	}
+}
+
+func TestBackupScheduleMode(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.mu.Unlock()
+
+	if err := mgr.SetBackupSchedule(cfg.ID, "daily", "differential"); err == nil {
+		t.Fatalf("expected an unknown mode to be rejected")
+	}
+	if err := mgr.SetBackupSchedule(cfg.ID, "daily", BackupModeIncremental); err != nil {
+		t.Fatalf("SetBackupSchedule failed: %v", err)
+	}
+	info, err := mgr.GetBackupSchedule(cfg.ID)
+	if err != nil || info["mode"] != BackupModeIncremental || info["schedule"] != "daily" {
+		t.Fatalf("unexpected schedule %v (%v)", info, err)
+	}
+	if err := mgr.SetBackupSchedule(cfg.ID, "daily", ""); err != nil {
+		t.Fatalf("SetBackupSchedule failed: %v", err)
+	}
+	if info, _ := mgr.GetBackupSchedule(cfg.ID); info["mode"] != BackupModeFull {
+		t.Fatalf("expected an empty mode to mean full, got %v", info)
+	}
+}
@@ -0,0 +1,220 @@
+package minecraft
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultJarScanMaxUncompressedBytes int64 = 512 * 1024 * 1024
+	defaultJarScanMaxClasses                 = 20000
+	jarScanMaxCompressionRatio               = 200
+	jarScanMinBombEntryBytes                 = 10 * 1024 * 1024
+)
+
+// Descriptors a jar must carry to be loadable by the server type.
+var (
+	pluginDescriptorFiles = []string{"plugin.yml", "paper-plugin.yml", "bungee.yml", "velocity-plugin.json"}
+	modDescriptorFiles    = []string{"fabric.mod.json", "quilt.mod.json", "META-INF/mods.toml", "META-INF/neoforge.mods.toml", "mcmod.info"}
+)
+
+// File types that have no business inside a plugin or mod jar.
+var jarScanExecutableExts = map[string]struct{}{
+	".exe": {}, ".dll": {}, ".so": {}, ".dylib": {},
+	".bat": {}, ".cmd": {}, ".ps1": {}, ".sh": {}, ".vbs": {},
+}
+
+// JarScanReport is the outcome of scanning an uploaded plugin/mod jar.
+// Any finding makes the jar suspicious.
+type JarScanReport struct {
+	SHA256            string            `json:"sha256"`
+	Entries           int               `json:"entries"`
+	ClassCount        int               `json:"classCount"`
+	UncompressedBytes int64             `json:"uncompressedBytes"`
+	Findings          []string          `json:"findings,omitempty"`
+	VirusTotal        *VirusTotalResult `json:"virusTotal,omitempty"`
+}
+
+func (r *JarScanReport) suspicious() bool {
+	return len(r.Findings) > 0
+}
+
+// VirusTotalResult is the detection summary for a jar hash. Known is false
+// when VirusTotal has never seen the file.
+type VirusTotalResult struct {
+	Known      bool   `json:"known"`
+	Malicious  int    `json:"malicious"`
+	Suspicious int    `json:"suspicious"`
+	Link       string `json:"link,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func jarScanMaxUncompressedBytesFromEnv() int64 {
+	raw := strings.TrimSpace(os.Getenv("ADPANEL_JAR_SCAN_MAX_UNCOMPRESSED_BYTES"))
+	if raw == "" {
+		return defaultJarScanMaxUncompressedBytes
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || n < 0 {
+		log.Printf("Invalid ADPANEL_JAR_SCAN_MAX_UNCOMPRESSED_BYTES value %q, using default %d", raw, defaultJarScanMaxUncompressedBytes)
+		return defaultJarScanMaxUncompressedBytes
+	}
+	return n
+}
+
+func jarScanMaxClassesFromEnv() int {
+	raw := strings.TrimSpace(os.Getenv("ADPANEL_JAR_SCAN_MAX_CLASSES"))
+	if raw == "" {
+		return defaultJarScanMaxClasses
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		log.Printf("Invalid ADPANEL_JAR_SCAN_MAX_CLASSES value %q, using default %d", raw, defaultJarScanMaxClasses)
+		return defaultJarScanMaxClasses
+	}
+	return n
+}
+
+// scanExtensionJar inspects a jar before it is installed. Files that are not
+// zip archives at all are rejected with an error; anything else unusual is
+// reported as a finding so the caller can quarantine it.
+func scanExtensionJar(ctx context.Context, jarPath, serverType string) (*JarScanReport, error) {
+	hash, err := fileSHA256(jarPath)
+	if err != nil {
+		return nil, err
+	}
+	r, err := zip.OpenReader(jarPath)
+	if err != nil {
+		return nil, fmt.Errorf("file is not a valid jar archive")
+	}
+	defer r.Close()
+
+	report := &JarScanReport{SHA256: hash, Entries: len(r.File)}
+	descriptors := pluginDescriptorFiles
+	kind := "plugin"
+	if isModdedType(serverType) {
+		descriptors = modDescriptorFiles
+		kind = "mod"
+	}
+	hasDescriptor := false
+	bombReported := false
+	for _, f := range r.File {
+		name := f.Name
+		if unsafeZipEntryName(name) {
+			report.Findings = append(report.Findings, fmt.Sprintf("unsafe entry path %q", name))
+			continue
+		}
+		for _, descriptor := range descriptors {
+			if name == descriptor {
+				hasDescriptor = true
+			}
+		}
+		lowerName := strings.ToLower(name)
+		if strings.HasSuffix(lowerName, ".class") {
+			report.ClassCount++
+		}
+		if _, ok := jarScanExecutableExts[path.Ext(lowerName)]; ok {
+			report.Findings = append(report.Findings, fmt.Sprintf("contains executable file %q", name))
+		}
+		size := int64(f.UncompressedSize64)
+		report.UncompressedBytes += size
+		if !bombReported && size >= jarScanMinBombEntryBytes && f.CompressedSize64 > 0 &&
+			f.UncompressedSize64/f.CompressedSize64 > jarScanMaxCompressionRatio {
+			report.Findings = append(report.Findings, fmt.Sprintf("entry %q has an extreme compression ratio", name))
+			bombReported = true
+		}
+	}
+	if !hasDescriptor {
+		report.Findings = append(report.Findings, fmt.Sprintf("no %s descriptor found (expected one of %s)", kind, strings.Join(descriptors, ", ")))
+	}
+	if limit := jarScanMaxUncompressedBytesFromEnv(); limit > 0 && report.UncompressedBytes > limit {
+		report.Findings = append(report.Findings, fmt.Sprintf("uncompressed size %s exceeds limit %s", formatFileSize(report.UncompressedBytes), formatFileSize(limit)))
+	}
+	if limit := jarScanMaxClassesFromEnv(); limit > 0 && report.ClassCount > limit {
+		report.Findings = append(report.Findings, fmt.Sprintf("contains %d classes (limit %d)", report.ClassCount, limit))
+	}
+
+	if apiKey := strings.TrimSpace(os.Getenv("ADPANEL_VIRUSTOTAL_API_KEY")); apiKey != "" {
+		vt := lookupVirusTotal(ctx, apiKey, hash)
+		report.VirusTotal = vt
+		if vt.Malicious > 0 || vt.Suspicious > 0 {
+			report.Findings = append(report.Findings, fmt.Sprintf("flagged by %d VirusTotal engines", vt.Malicious+vt.Suspicious))
+		}
+	}
+	return report, nil
+}
+
+func unsafeZipEntryName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "/") || strings.HasPrefix(name, "\\") || strings.Contains(name, "\\") {
+		return true
+	}
+	if len(name) >= 2 && name[1] == ':' {
+		return true
+	}
+	for _, segment := range strings.Split(name, "/") {
+		if segment == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+var virusTotalAPIBase = "https://www.virustotal.com/api/v3"
+
+// lookupVirusTotal asks VirusTotal about a file hash without uploading the
+// file. Lookup failures are recorded on the result but never block installs.
+func lookupVirusTotal(ctx context.Context, apiKey, hash string) *VirusTotalResult {
+	result := &VirusTotalResult{}
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, virusTotalAPIBase+"/files/"+hash, nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	req.Header.Set("x-apikey", apiKey)
+	req.Header.Set("User-Agent", userAgent())
+	resp, err := (&http.Client{Timeout: 15 * time.Second}).Do(req)
+	if err != nil {
+		log.Printf("VirusTotal lookup for %s failed: %v", hash, err)
+		result.Error = "lookup failed"
+		return result
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return result
+	default:
+		result.Error = fmt.Sprintf("lookup failed with status %d", resp.StatusCode)
+		return result
+	}
+	var body struct {
+		Data struct {
+			Attributes struct {
+				Stats struct {
+					Malicious  int `json:"malicious"`
+					Suspicious int `json:"suspicious"`
+				} `json:"last_analysis_stats"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		result.Error = "invalid response"
+		return result
+	}
+	result.Known = true
+	result.Malicious = body.Data.Attributes.Stats.Malicious
+	result.Suspicious = body.Data.Attributes.Stats.Suspicious
+	result.Link = "https://www.virustotal.com/gui/file/" + hash
+	return result
+}
@@ -0,0 +1,117 @@
+package minecraft
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestJar(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create jar: %v", err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range entries {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("failed to add %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to finish jar: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close jar: %v", err)
+	}
+}
+
+func TestScanExtensionJar(t *testing.T) {
+	t.Setenv("ADPANEL_VIRUSTOTAL_API_KEY", "")
+	dir := t.TempDir()
+
+	clean := filepath.Join(dir, "clean.jar")
+	writeTestJar(t, clean, map[string]string{"plugin.yml": "name: Clean\n", "com/example/Main.class": "x"})
+	report, err := scanExtensionJar(context.Background(), clean, "Paper")
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if report.suspicious() || report.ClassCount != 1 {
+		t.Fatalf("expected clean report, got %+v", report)
+	}
+
+	bad := filepath.Join(dir, "bad.jar")
+	writeTestJar(t, bad, map[string]string{"../../evil.sh": "rm -rf /", "payload.exe": "MZ"})
+	report, err = scanExtensionJar(context.Background(), bad, "Paper")
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if len(report.Findings) != 3 {
+		t.Fatalf("expected path, executable and descriptor findings, got %v", report.Findings)
+	}
+
+	// A Paper plugin is missing a mod descriptor on a Fabric server.
+	report, err = scanExtensionJar(context.Background(), clean, "Fabric")
+	if err != nil || !report.suspicious() {
+		t.Fatalf("expected plugin jar to be suspicious on a mod server, got %+v (%v)", report, err)
+	}
+
+	notZip := filepath.Join(dir, "fake.jar")
+	if err := os.WriteFile(notZip, []byte("not a zip"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := scanExtensionJar(context.Background(), notZip, "Paper"); err == nil {
+		t.Fatalf("expected non-zip file to be rejected")
+	}
+}
+
+func TestUploadQuarantinesSuspiciousJar(t *testing.T) {
+	t.Setenv("ADPANEL_VIRUSTOTAL_API_KEY", "")
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv1", Name: "Lobby", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Lobby")}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		t.Fatalf("failed to create server dir: %v", err)
+	}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.mu.Unlock()
+
+	staged := filepath.Join(t.TempDir(), "Odd.jar")
+	writeTestJar(t, staged, map[string]string{"com/example/Main.class": "x"})
+	_, status, err := mgr.UploadPluginFromFile(cfg.ID, "", "Odd.jar", staged, "")
+	var quarantined *QuarantineError
+	if !errors.As(err, &quarantined) || status != "quarantined" {
+		t.Fatalf("expected upload to be quarantined, got status=%q err=%v", status, err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Dir, "plugins", "Odd.jar")); !os.IsNotExist(err) {
+		t.Fatalf("expected quarantined jar to stay out of plugins/, stat err=%v", err)
+	}
+
+	entries, err := mgr.ListQuarantinedPlugins(cfg.ID)
+	if err != nil || len(entries) != 1 || entries[0].FileName != "Odd.jar" {
+		t.Fatalf("expected one quarantined entry, got %+v (%v)", entries, err)
+	}
+
+	name, status, err := mgr.ApproveQuarantinedPlugin(cfg.ID, entries[0].ID, "")
+	if err != nil || name != "Odd.jar" || status != "uploaded" {
+		t.Fatalf("approve failed: name=%q status=%q err=%v", name, status, err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Dir, "plugins", "Odd.jar")); err != nil {
+		t.Fatalf("expected approved jar to be installed: %v", err)
+	}
+	if entries, _ := mgr.ListQuarantinedPlugins(cfg.ID); len(entries) != 0 {
+		t.Fatalf("expected quarantine to be empty after approval, got %+v", entries)
+	}
+}
@@ -0,0 +1,325 @@
+package minecraft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Job types tracked by the job subsystem.
+const (
+	JobTypeInstall        = "install"
+	JobTypeBackup         = "backup"
+	JobTypeRestore        = "restore"
+	JobTypeClone          = "clone"
+	JobTypeDelete         = "delete"
+	JobTypeRestart        = "restart"
+	JobTypePluginUpdate   = "plugin-update"
+	JobTypePluginInstall  = "plugin-install"
+	JobTypeRegionPrune    = "region-prune"
+	JobTypeWorldUpgrade   = "world-upgrade"
+	JobTypeRestoreAsNew   = "restore-as-new"
+	JobTypeBackupUpload   = "backup-upload"
+	JobTypeBenchmark      = "benchmark"
+	JobTypeWorldReset     = "world-reset"
+	JobTypeWorldDelete    = "world-delete"
+	JobTypeWorldUpload    = "world-upload"
+	JobTypeMaintenance    = "maintenance"
+	JobTypeFileDelete     = "file-delete"
+	JobTypeStagingRefresh = "staging-refresh"
+	JobTypeImport         = "import"
+	JobTypeExport         = "export"
+)
+
+// Job lifecycle states.
+const (
+	JobStateQueued    = "queued"
+	JobStateRunning   = "running"
+	JobStateSucceeded = "succeeded"
+	JobStateFailed    = "failed"
+	JobStateCancelled = "cancelled"
+)
+
+const maxJobHistory = 200
+const maxJobLogLines = 200
+
+var (
+	ErrJobNotFound        = errors.New("job not found")
+	ErrJobAlreadyFinished = errors.New("job already finished")
+)
+
+// Job is the API-facing view of a long-running operation.
+type Job struct {
+	ID         string   `json:"id"`
+	Type       string   `json:"type"`
+	ServerID   string   `json:"serverId,omitempty"`
+	ServerName string   `json:"serverName,omitempty"`
+	State      string   `json:"state"`
+	Progress   int      `json:"progress"`
+	BytesDone  int64    `json:"bytesDone,omitempty"`
+	BytesTotal int64    `json:"bytesTotal,omitempty"`
+	Message    string   `json:"message,omitempty"`
+	Error      string   `json:"error,omitempty"`
+	Logs       []string `json:"logs,omitempty"`
+	Result     any      `json:"result,omitempty"`
+	CreatedAt  string   `json:"createdAt"`
+	StartedAt  string   `json:"startedAt,omitempty"`
+	EndedAt    string   `json:"endedAt,omitempty"`
+}
+
+func (j *Job) finished() bool {
+	return j.State == JobStateSucceeded || j.State == JobStateFailed || j.State == JobStateCancelled
+}
+
+type jobRecord struct {
+	job    Job
+	cancel context.CancelFunc
+}
+
+// jobTracker keeps active jobs plus a bounded history of finished ones.
+type jobTracker struct {
+	mu    sync.RWMutex
+	jobs  map[string]*jobRecord
+	order []string
+}
+
+// jobHandle is held by the goroutine running a job to report its progress.
+type jobHandle struct {
+	m   *Manager
+	id  string
+	ctx context.Context
+}
+
+// newJob registers a queued job for a server operation. The returned
+// handle's context is cancelled when the job is cancelled through the API.
+func (m *Manager) newJob(jobType, serverID string) *jobHandle {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	serverName := ""
+	if serverID != "" {
+		m.mu.RLock()
+		if cfg, ok := m.configs[serverID]; ok {
+			serverName = cfg.Name
+		}
+		m.mu.RUnlock()
+	}
+
+	id := uuid.New().String()
+	record := &jobRecord{
+		job: Job{
+			ID:         id,
+			Type:       jobType,
+			ServerID:   serverID,
+			ServerName: serverName,
+			State:      JobStateQueued,
+			CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+		},
+		cancel: cancel,
+	}
+
+	t := &m.jobs
+	t.mu.Lock()
+	if t.jobs == nil {
+		t.jobs = make(map[string]*jobRecord)
+	}
+	t.jobs[id] = record
+	t.order = append(t.order, id)
+	t.pruneLocked()
+	t.mu.Unlock()
+
+	return &jobHandle{m: m, id: id, ctx: ctx}
+}
+
+// pruneLocked drops the oldest finished jobs once history exceeds its limit.
+func (t *jobTracker) pruneLocked() {
+	excess := len(t.order) - maxJobHistory
+	if excess <= 0 {
+		return
+	}
+	kept := t.order[:0]
+	for _, id := range t.order {
+		record := t.jobs[id]
+		if excess > 0 && record != nil && record.job.finished() {
+			delete(t.jobs, id)
+			excess--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	t.order = kept
+}
+
+func (j *jobHandle) update(fn func(job *Job)) {
+	t := &j.m.jobs
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	record, ok := t.jobs[j.id]
+	if !ok || record.job.finished() {
+		return
+	}
+	fn(&record.job)
+}
+
+// start marks the job as running once it holds the server.
+func (j *jobHandle) start(message string) {
+	j.update(func(job *Job) {
+		job.State = JobStateRunning
+		job.StartedAt = time.Now().UTC().Format(time.RFC3339)
+		job.Message = message
+	})
+}
+
+func (j *jobHandle) progress(percent int, message string) {
+	if percent < 0 {
+		percent = 0
+	}
+	if percent > 100 {
+		percent = 100
+	}
+	j.update(func(job *Job) {
+		job.Progress = percent
+		if message != "" {
+			job.Message = message
+		}
+	})
+}
+
+// transfer records how many bytes of a copy are done and maps them onto
+// the job's progress between from and to percent.
+func (j *jobHandle) transfer(done, total int64, from, to int) {
+	percent := to
+	if total > 0 && done < total {
+		percent = from + int(int64(to-from)*done/total)
+	}
+	j.update(func(job *Job) {
+		job.BytesDone = done
+		job.BytesTotal = total
+		job.Progress = percent
+	})
+}
+
+// setResult attaches what the job produced, such as the backup it created.
+func (j *jobHandle) setResult(result any) {
+	j.update(func(job *Job) {
+		job.Result = result
+	})
+}
+
+func (j *jobHandle) log(line string) {
+	j.update(func(job *Job) {
+		job.Logs = append(job.Logs, line)
+		if len(job.Logs) > maxJobLogLines {
+			job.Logs = job.Logs[len(job.Logs)-maxJobLogLines:]
+		}
+		job.Message = line
+	})
+}
+
+// finish records the outcome of the job. A job whose context was cancelled
+// is reported as cancelled regardless of the error it returned.
+func (j *jobHandle) finish(err error) {
+	t := &j.m.jobs
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	record, ok := t.jobs[j.id]
+	if !ok || record.job.finished() {
+		return
+	}
+	job := &record.job
+	job.EndedAt = time.Now().UTC().Format(time.RFC3339)
+	switch {
+	case j.ctx.Err() != nil:
+		job.State = JobStateCancelled
+		job.Error = "cancelled"
+	case err != nil:
+		job.State = JobStateFailed
+		job.Error = err.Error()
+	default:
+		job.State = JobStateSucceeded
+		job.Progress = 100
+	}
+	record.cancel()
+}
+
+// ListJobs returns jobs newest first, optionally filtered by server and state.
+func (m *Manager) ListJobs(serverID, state string) []Job {
+	serverID = strings.TrimSpace(serverID)
+	state = strings.TrimSpace(strings.ToLower(state))
+
+	t := &m.jobs
+	t.mu.RLock()
+	jobs := make([]Job, 0, len(t.order))
+	for i := len(t.order) - 1; i >= 0; i-- {
+		record := t.jobs[t.order[i]]
+		if record == nil {
+			continue
+		}
+		if serverID != "" && record.job.ServerID != serverID {
+			continue
+		}
+		if state != "" && record.job.State != state {
+			continue
+		}
+		job := record.job
+		job.Logs = append([]string(nil), record.job.Logs...)
+		jobs = append(jobs, job)
+	}
+	t.mu.RUnlock()
+	return jobs
+}
+
+// JobCounts returns how many jobs are waiting for their server and how
+// many are running.
+func (m *Manager) JobCounts() (queued, running int) {
+	t := &m.jobs
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, record := range t.jobs {
+		switch record.job.State {
+		case JobStateQueued:
+			queued++
+		case JobStateRunning:
+			running++
+		}
+	}
+	return queued, running
+}
+
+// GetJob returns a single job by ID.
+func (m *Manager) GetJob(id string) (*Job, error) {
+	t := &m.jobs
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	record, ok := t.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	job := record.job
+	job.Logs = append([]string(nil), record.job.Logs...)
+	return &job, nil
+}
+
+// CancelJob requests cancellation of a queued or running job.
+func (m *Manager) CancelJob(id string) error {
+	t := &m.jobs
+	t.mu.RLock()
+	record, ok := t.jobs[id]
+	if !ok {
+		t.mu.RUnlock()
+		return ErrJobNotFound
+	}
+	if record.job.finished() {
+		t.mu.RUnlock()
+		return fmt.Errorf("%w: %s", ErrJobAlreadyFinished, record.job.State)
+	}
+	cancel := record.cancel
+	t.mu.RUnlock()
+
+	cancel()
+	return nil
+}
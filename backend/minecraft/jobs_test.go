@@ -0,0 +1,137 @@
+package minecraft
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJobLifecycleAndCancellation(t *testing.T) {
+	mgr := &Manager{configs: map[string]*ServerConfig{"srv1": {ID: "srv1", Name: "Alpha"}}}
+
+	done := mgr.newJob(JobTypeBackup, "srv1")
+	done.start("Creating backup archive")
+	done.log("Created backup_1.tar.gz")
+	done.finish(nil)
+
+	queued := mgr.newJob(JobTypeRestore, "srv1")
+	if err := mgr.CancelJob(queued.id); err != nil {
+		t.Fatalf("cancel failed: %v", err)
+	}
+	if !errors.Is(queued.ctx.Err(), context.Canceled) {
+		t.Fatal("expected job context to be cancelled")
+	}
+	queued.finish(queued.ctx.Err())
+
+	jobs := mgr.ListJobs("srv1", "")
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(jobs))
+	}
+	if jobs[0].ID != queued.id || jobs[0].State != JobStateCancelled {
+		t.Fatalf("expected newest job to be the cancelled restore, got %+v", jobs[0])
+	}
+	if jobs[1].State != JobStateSucceeded || jobs[1].Progress != 100 || jobs[1].ServerName != "Alpha" {
+		t.Fatalf("unexpected finished backup job: %+v", jobs[1])
+	}
+
+	if err := mgr.CancelJob(done.id); !errors.Is(err, ErrJobAlreadyFinished) {
+		t.Fatalf("expected already-finished error, got %v", err)
+	}
+	if _, err := mgr.GetJob("missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected not-found error, got %v", err)
+	}
+}
+
+func TestJobCountsQueuedAndRunning(t *testing.T) {
+	mgr := &Manager{configs: map[string]*ServerConfig{}}
+
+	running := mgr.newJob(JobTypeBackup, "")
+	running.start("Creating backup archive")
+	mgr.newJob(JobTypeRestore, "")
+	mgr.newJob(JobTypeClone, "")
+	finished := mgr.newJob(JobTypeDelete, "")
+	finished.finish(nil)
+
+	if queued, active := mgr.JobCounts(); queued != 2 || active != 1 {
+		t.Fatalf("expected 2 queued and 1 running, got %d and %d", queued, active)
+	}
+}
+
+func TestQueuedOperationStopsWaitingWhenCancelled(t *testing.T) {
+	mgr := &Manager{}
+	release, err := mgr.acquireServerOperation(context.Background(), "srv1", operationRestart)
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		_, err := mgr.acquireServerOperation(ctx, "srv1", operationBackup)
+		result <- err
+	}()
+	cancel()
+
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation error, got %v", err)
+	}
+	if _, _, queued := mgr.serverOperationState("srv1"); len(queued) != 0 {
+		t.Fatalf("expected cancelled waiter to leave the queue, got %v", queued)
+	}
+}
+
+func TestStartDeleteServerRemovesFilesInJob(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	if err := os.MkdirAll(filepath.Join(cfg.Dir, "world"), 0755); err != nil {
+		t.Fatalf("failed to create server dir: %v", err)
+	}
+	rs := &runningServer{status: "Running"}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = rs
+	mgr.mu.Unlock()
+
+	if _, err := mgr.StartDeleteServer(cfg.ID); err == nil {
+		t.Fatal("expected deleting a running server to be refused")
+	}
+	rs.mu.Lock()
+	rs.status = "Stopped"
+	rs.mu.Unlock()
+
+	job, err := mgr.StartDeleteServer(cfg.ID)
+	if err != nil {
+		t.Fatalf("StartDeleteServer failed: %v", err)
+	}
+	if job.Type != JobTypeDelete || job.ServerName != "Survival" {
+		t.Fatalf("unexpected job %+v", job)
+	}
+	if _, err := mgr.GetServerDir(cfg.ID); err == nil {
+		t.Fatal("expected the server to leave the panel before its files are deleted")
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for !job.finished() {
+		if time.Now().After(deadline) {
+			t.Fatalf("delete job did not finish: %+v", job)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if job, err = mgr.GetJob(job.ID); err != nil {
+			t.Fatalf("GetJob failed: %v", err)
+		}
+	}
+	if job.State != JobStateSucceeded {
+		t.Fatalf("unexpected finished job %+v", job)
+	}
+	if _, err := os.Stat(cfg.Dir); !os.IsNotExist(err) {
+		t.Fatalf("expected the server directory to be gone, got %v", err)
+	}
+}
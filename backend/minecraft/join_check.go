@@ -0,0 +1,431 @@
+package minecraft
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	defaultJoinCheckPlayer = "PanelCheck"
+	joinCheckTimeout       = 15 * time.Second
+	// joinCheckBootDelay gives plugins a moment after "Done" before the
+	// automatic check joins.
+	joinCheckBootDelay = 5 * time.Second
+)
+
+// Protocol versions where the login start packet changed shape.
+const (
+	protocol1_19   = 759
+	protocol1_19_1 = 760
+	protocol1_20_2 = 764
+)
+
+// JoinCheckSettings turns on the join check after every boot. Player is the
+// name the bot joins with; Last is the most recent result, whether it ran
+// automatically or on request.
+type JoinCheckSettings struct {
+	Enabled bool             `json:"enabled"`
+	Player  string           `json:"player,omitempty"`
+	Last    *JoinCheckResult `json:"last,omitempty"`
+}
+
+// JoinCheckResult is the outcome of one bot join. Joined means the server
+// let the bot in. An online-mode server is OK without being joined: the bot
+// has no account, so reaching authentication is as far as it can check.
+type JoinCheckResult struct {
+	OK        bool   `json:"ok"`
+	Joined    bool   `json:"joined"`
+	Player    string `json:"player"`
+	Version   string `json:"version,omitempty"`
+	Protocol  int    `json:"protocol,omitempty"`
+	Message   string `json:"message"`
+	LatencyMS int64  `json:"latencyMs"`
+	CheckedAt string `json:"checkedAt"`
+}
+
+// mcConn is a client connection that handles packet compression once the
+// server turns it on.
+type mcConn struct {
+	conn      net.Conn
+	r         *bufio.Reader
+	threshold int
+}
+
+func (c *mcConn) send(payload []byte) error {
+	if c.threshold < 0 {
+		return writePacket(c.conn, payload)
+	}
+	// Our packets are all below any sane threshold, so they go out with
+	// a data length of 0, meaning uncompressed.
+	var body bytes.Buffer
+	writeVarInt(&body, 0)
+	body.Write(payload)
+	return writePacket(c.conn, body.Bytes())
+}
+
+func (c *mcConn) receive() (int, *bytes.Reader, error) {
+	payload, err := readPacket(c.r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if c.threshold >= 0 {
+		reader := bytes.NewReader(payload)
+		dataLength, err := readVarInt(reader)
+		if err != nil {
+			return 0, nil, err
+		}
+		rest := payload[len(payload)-reader.Len():]
+		if dataLength == 0 {
+			payload = rest
+		} else {
+			if dataLength > 1<<21 {
+				return 0, nil, fmt.Errorf("invalid packet length %d", dataLength)
+			}
+			zr, err := zlib.NewReader(bytes.NewReader(rest))
+			if err != nil {
+				return 0, nil, err
+			}
+			payload = make([]byte, dataLength)
+			_, err = io.ReadFull(zr, payload)
+			zr.Close()
+			if err != nil {
+				return 0, nil, err
+			}
+		}
+	}
+	reader := bytes.NewReader(payload)
+	packetID, err := readVarInt(reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	return packetID, reader, nil
+}
+
+// offlinePlayerUUID is the UUID an offline-mode server gives a name: a
+// version 3 UUID of "OfflinePlayer:<name>".
+func offlinePlayerUUID(name string) [16]byte {
+	sum := md5.Sum([]byte("OfflinePlayer:" + name))
+	sum[6] = sum[6]&0x0f | 0x30
+	sum[8] = sum[8]&0x3f | 0x80
+	return sum
+}
+
+// loginStartPacket builds the login start packet for a protocol version.
+func loginStartPacket(protocol int, name string) []byte {
+	var b bytes.Buffer
+	writeVarInt(&b, 0x00)
+	writeMCString(&b, name)
+	id := offlinePlayerUUID(name)
+	switch {
+	case protocol >= protocol1_20_2:
+		b.Write(id[:])
+	case protocol > protocol1_19_1:
+		b.WriteByte(1)
+		b.Write(id[:])
+	case protocol == protocol1_19_1:
+		b.WriteByte(0) // no signature data
+		b.WriteByte(1)
+		b.Write(id[:])
+	case protocol == protocol1_19:
+		b.WriteByte(0)
+	}
+	return b.Bytes()
+}
+
+func handshakePacket(protocol, port, nextState int) []byte {
+	var b bytes.Buffer
+	writeVarInt(&b, 0x00)
+	writeVarInt(&b, protocol)
+	writeMCString(&b, "127.0.0.1")
+	var portBytes [2]byte
+	binary.BigEndian.PutUint16(portBytes[:], uint16(port))
+	b.Write(portBytes[:])
+	writeVarInt(&b, nextState)
+	return b.Bytes()
+}
+
+// chatText flattens a JSON text component, as used in disconnect reasons,
+// to plain text.
+func chatText(raw string) string {
+	var component any
+	if err := json.Unmarshal([]byte(raw), &component); err != nil {
+		return raw
+	}
+	var b strings.Builder
+	var walk func(v any)
+	walk = func(v any) {
+		switch c := v.(type) {
+		case string:
+			b.WriteString(c)
+		case []any:
+			for _, part := range c {
+				walk(part)
+			}
+		case map[string]any:
+			if text, ok := c["text"].(string); ok {
+				b.WriteString(text)
+			} else if key, ok := c["translate"].(string); ok {
+				b.WriteString(key)
+			}
+			if extra, ok := c["extra"]; ok {
+				walk(extra)
+			}
+		}
+	}
+	walk(component)
+	return strings.TrimSpace(b.String())
+}
+
+// queryServerVersion reads the version name and protocol from the status
+// response, so the bot can log in with the server's own protocol.
+func queryServerVersion(port int, deadline time.Time) (string, int, error) {
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), 3*time.Second)
+	if err != nil {
+		return "", 0, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(deadline)
+
+	if err := writePacket(conn, handshakePacket(-1, port, 1)); err != nil {
+		return "", 0, err
+	}
+	if err := writePacket(conn, []byte{0x00}); err != nil {
+		return "", 0, err
+	}
+	payload, err := readPacket(bufio.NewReader(conn))
+	if err != nil {
+		return "", 0, err
+	}
+	reader := bytes.NewReader(payload)
+	if packetID, err := readVarInt(reader); err != nil || packetID != 0x00 {
+		return "", 0, fmt.Errorf("unexpected status response")
+	}
+	statusJSON, err := readMCString(reader)
+	if err != nil {
+		return "", 0, err
+	}
+	var status struct {
+		Version struct {
+			Name     string `json:"name"`
+			Protocol int    `json:"protocol"`
+		} `json:"version"`
+	}
+	if err := json.Unmarshal([]byte(statusJSON), &status); err != nil {
+		return "", 0, err
+	}
+	if status.Version.Protocol <= 0 {
+		return "", 0, fmt.Errorf("server did not report a protocol version")
+	}
+	return status.Version.Name, status.Version.Protocol, nil
+}
+
+// joinServer logs the bot in to the server on port and returns once the
+// server accepts or refuses it. The bot disconnects right after a
+// successful login, before it sends any movement or chat.
+func joinServer(port int, player string) *JoinCheckResult {
+	started := time.Now()
+	result := &JoinCheckResult{Player: player}
+	finish := func(message string) *JoinCheckResult {
+		result.Message = message
+		result.LatencyMS = time.Since(started).Milliseconds()
+		result.CheckedAt = time.Now().UTC().Format(time.RFC3339)
+		return result
+	}
+	deadline := started.Add(joinCheckTimeout)
+
+	version, protocol, err := queryServerVersion(port, deadline)
+	if err != nil {
+		return finish(fmt.Sprintf("Server did not answer a status request: %v", err))
+	}
+	result.Version = version
+	result.Protocol = protocol
+
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), 3*time.Second)
+	if err != nil {
+		return finish(fmt.Sprintf("Could not connect: %v", err))
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(deadline)
+	c := &mcConn{conn: conn, r: bufio.NewReader(conn), threshold: -1}
+
+	if err := c.send(handshakePacket(protocol, port, 2)); err != nil {
+		return finish(fmt.Sprintf("Could not send the handshake: %v", err))
+	}
+	if err := c.send(loginStartPacket(protocol, player)); err != nil {
+		return finish(fmt.Sprintf("Could not start the login: %v", err))
+	}
+
+	for {
+		packetID, reader, err := c.receive()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return finish("Server closed the connection during login")
+			}
+			return finish(fmt.Sprintf("Login failed: %v", err))
+		}
+		switch packetID {
+		case 0x00: // disconnect
+			reason, _ := readMCString(reader)
+			return finish("Server refused the join: " + chatText(reason))
+		case 0x01: // encryption request
+			result.OK = true
+			return finish("Server is in online mode and asked for authentication, so the bot stopped there")
+		case 0x02: // login success
+			result.OK = true
+			result.Joined = true
+			return finish("Joined")
+		case 0x03: // set compression
+			threshold, err := readVarInt(reader)
+			if err != nil {
+				return finish(fmt.Sprintf("Login failed: %v", err))
+			}
+			c.threshold = threshold
+		case 0x04: // login plugin request: answer that we understand none
+			messageID, err := readVarInt(reader)
+			if err != nil {
+				return finish(fmt.Sprintf("Login failed: %v", err))
+			}
+			var reply bytes.Buffer
+			writeVarInt(&reply, 0x02)
+			writeVarInt(&reply, messageID)
+			reply.WriteByte(0)
+			if err := c.send(reply.Bytes()); err != nil {
+				return finish(fmt.Sprintf("Login failed: %v", err))
+			}
+		case 0x05: // cookie request: we hold no cookies
+			key, err := readMCString(reader)
+			if err != nil {
+				return finish(fmt.Sprintf("Login failed: %v", err))
+			}
+			var reply bytes.Buffer
+			writeVarInt(&reply, 0x04)
+			writeMCString(&reply, key)
+			reply.WriteByte(0)
+			if err := c.send(reply.Bytes()); err != nil {
+				return finish(fmt.Sprintf("Login failed: %v", err))
+			}
+		default:
+			return finish(fmt.Sprintf("Unexpected login packet 0x%02x", packetID))
+		}
+	}
+}
+
+// GetJoinCheck returns a server's join check settings and last result.
+func (m *Manager) GetJoinCheck(id string) (*JoinCheckSettings, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	settings := JoinCheckSettings{Player: defaultJoinCheckPlayer}
+	if cfg.JoinCheck != nil {
+		settings = *cfg.JoinCheck
+	}
+	return &settings, nil
+}
+
+// SetJoinCheck saves whether the bot joins after each boot and as whom.
+func (m *Manager) SetJoinCheck(id string, s JoinCheckSettings) (*JoinCheckSettings, error) {
+	s.Player = strings.TrimSpace(s.Player)
+	if s.Player == "" {
+		s.Player = defaultJoinCheckPlayer
+	}
+	if len(s.Player) < 3 || !isValidPlayerName(s.Player) {
+		return nil, fmt.Errorf("bot name must be 3 to 16 letters, digits or underscores")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	s.Last = nil
+	if cfg.JoinCheck != nil {
+		s.Last = cfg.JoinCheck.Last
+	}
+	cfg.JoinCheck = &s
+	if err := m.persist(); err != nil {
+		return nil, err
+	}
+	settings := s
+	return &settings, nil
+}
+
+// RunJoinCheck has the bot join the running server and stores the result.
+func (m *Manager) RunJoinCheck(id string) (*JoinCheckResult, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		m.mu.RUnlock()
+		return nil, err
+	}
+	port := cfg.Port
+	player := defaultJoinCheckPlayer
+	if cfg.JoinCheck != nil && cfg.JoinCheck.Player != "" {
+		player = cfg.JoinCheck.Player
+	}
+	rs := m.running[id]
+	m.mu.RUnlock()
+
+	if rs == nil || rs.currentRuntime().status != "Running" {
+		return nil, fmt.Errorf("server %s is not running", id)
+	}
+	result := joinServer(port, player)
+
+	m.mu.Lock()
+	if cfg, ok := m.configs[id]; ok {
+		if cfg.JoinCheck == nil {
+			cfg.JoinCheck = &JoinCheckSettings{Player: player}
+		}
+		cfg.JoinCheck.Last = result
+		if err := m.persist(); err != nil {
+			log.Printf("[%s] Failed to save join check result: %v", cfg.Name, err)
+		}
+	}
+	m.mu.Unlock()
+
+	status := "passed"
+	if !result.OK {
+		status = "failed"
+	}
+	entry := m.appendLog(rs, fmt.Sprintf("[Panel] Join check %s: %s", status, result.Message))
+	m.broadcastLog(rs, entry)
+	return result, nil
+}
+
+// runJoinCheckAfterBoot runs the join check shortly after the server
+// reports Running, when the server has it turned on.
+func (m *Manager) runJoinCheckAfterBoot(id string) {
+	m.mu.RLock()
+	cfg := m.configs[id]
+	enabled := cfg != nil && cfg.JoinCheck != nil && cfg.JoinCheck.Enabled
+	name := ""
+	if cfg != nil {
+		name = cfg.Name
+	}
+	m.mu.RUnlock()
+	if !enabled {
+		return
+	}
+	time.Sleep(joinCheckBootDelay)
+	result, err := m.RunJoinCheck(id)
+	if err != nil {
+		return
+	}
+	if !result.OK {
+		log.Printf("[%s] Join check failed: %s", name, result.Message)
+	}
+}
@@ -0,0 +1,99 @@
+package minecraft
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// fakeLoginServer answers one status request and one login, replying to
+// the login with the given packets.
+func fakeLoginServer(t *testing.T, protocol int, replies ...[]byte) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		status, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		r := bufio.NewReader(status)
+		readPacket(r)
+		readPacket(r)
+		var b bytes.Buffer
+		writeVarInt(&b, 0x00)
+		writeMCString(&b, `{"version":{"name":"Paper 1.21.1","protocol":`+strconv.Itoa(protocol)+`}}`)
+		writePacket(status, b.Bytes())
+		status.Close()
+
+		login, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer login.Close()
+		r = bufio.NewReader(login)
+		readPacket(r)
+		readPacket(r)
+		for _, reply := range replies {
+			writePacket(login, reply)
+		}
+		// Wait for the bot to hang up.
+		readPacket(r)
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func loginPacket(compressed bool, id int, fields func(*bytes.Buffer)) []byte {
+	var b bytes.Buffer
+	if compressed {
+		writeVarInt(&b, 0)
+	}
+	writeVarInt(&b, id)
+	if fields != nil {
+		fields(&b)
+	}
+	return b.Bytes()
+}
+
+func TestJoinServerReportsLoginOutcome(t *testing.T) {
+	setCompression := loginPacket(false, 0x03, func(b *bytes.Buffer) { writeVarInt(b, 256) })
+	success := loginPacket(true, 0x02, func(b *bytes.Buffer) {
+		id := offlinePlayerUUID("PanelCheck")
+		b.Write(id[:])
+		writeMCString(b, "PanelCheck")
+	})
+	result := joinServer(fakeLoginServer(t, 767, setCompression, success), "PanelCheck")
+	if !result.OK || !result.Joined || result.Version != "Paper 1.21.1" || result.Protocol != 767 {
+		t.Fatalf("expected a successful join, got %+v", result)
+	}
+
+	kick := loginPacket(false, 0x00, func(b *bytes.Buffer) {
+		writeMCString(b, `{"text":"","extra":[{"text":"You are not whitelisted on this server!"}]}`)
+	})
+	result = joinServer(fakeLoginServer(t, 767, kick), "PanelCheck")
+	if result.OK || result.Joined || !strings.Contains(result.Message, "not whitelisted") {
+		t.Fatalf("expected a refused join, got %+v", result)
+	}
+
+	encryption := loginPacket(false, 0x01, func(b *bytes.Buffer) { writeMCString(b, "") })
+	result = joinServer(fakeLoginServer(t, 763, encryption), "PanelCheck")
+	if !result.OK || result.Joined {
+		t.Fatalf("expected an online-mode server to pass without joining, got %+v", result)
+	}
+}
+
+func TestOfflinePlayerUUIDMatchesServer(t *testing.T) {
+	// Offline-mode servers give "Notch" this UUID.
+	id := offlinePlayerUUID("Notch")
+	if got := uuid.UUID(id).String(); got != "b50ad385-829d-3141-a216-7e7d7539ba7f" {
+		t.Fatalf("unexpected offline UUID %s", got)
+	}
+}
@@ -0,0 +1,252 @@
+package minecraft
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// userJVMArgsHeader opened user_jvm_args.txt files that older panel
+// versions wrote whole. Such a file holds nothing of the user's.
+const userJVMArgsHeader = "# JVM flags managed by Admin Panel\n"
+
+// The panel's flags sit between these markers in user_jvm_args.txt;
+// anything outside them belongs to the user and is left alone.
+const (
+	userJVMArgsBegin = "# BEGIN flags managed by Admin Panel"
+	userJVMArgsEnd   = "# END flags managed by Admin Panel"
+)
+
+// heapFlags are the JVM flags that size the heap.
+var heapFlags = []string{"-Xms", "-Xmx"}
+
+// managedJVMArgs are the flags the panel keeps in a Forge or NeoForge
+// server's user_jvm_args.txt: its RAM settings, then its flag preset.
+func managedJVMArgs(cfg *ServerConfig) []string {
+	var args []string
+	if ram := strings.TrimSpace(cfg.MinRAM); ram != "" {
+		args = append(args, "-Xms"+ram)
+	}
+	if ram := strings.TrimSpace(cfg.MaxRAM); ram != "" {
+		args = append(args, "-Xmx"+ram)
+	}
+	return append(args, buildJVMFlags(cfg.Flags, cfg.AlwaysPreTouch)...)
+}
+
+// writeManagedUserJVMArgs puts extraFlags in the managed block of path,
+// keeping the lines users added. The block goes first so that the user's
+// own flags win where the JVM takes the last of several; the exception
+// is the heap size, where a user line setting one the block sets is
+// commented out so the panel's RAM settings apply.
+func writeManagedUserJVMArgs(path string, extraFlags []string) error {
+	var existing string
+	if data, err := os.ReadFile(path); err == nil {
+		existing = string(data)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString(userJVMArgsBegin + "\n")
+	for _, f := range extraFlags {
+		b.WriteString(f + "\n")
+	}
+	b.WriteString(userJVMArgsEnd + "\n")
+	if user := userJVMArgsOutsideBlock(existing); user != "" {
+		b.WriteString(commentOutHeapFlags(user, extraFlags))
+	}
+	content := b.String()
+	if content == existing {
+		return nil
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// userJVMArgsOutsideBlock returns the lines of a user_jvm_args.txt that
+// are not the panel's. A begin marker without an end runs to the end of
+// the file.
+func userJVMArgsOutsideBlock(content string) string {
+	if strings.HasPrefix(content, userJVMArgsHeader) {
+		return ""
+	}
+	var kept []string
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		switch trimmed := strings.TrimSpace(line); {
+		case trimmed == userJVMArgsBegin:
+			inBlock = true
+		case trimmed == userJVMArgsEnd:
+			inBlock = false
+		case !inBlock:
+			kept = append(kept, line)
+		}
+	}
+	user := strings.TrimLeft(strings.Join(kept, "\n"), "\n")
+	if user != "" && !strings.HasSuffix(user, "\n") {
+		user += "\n"
+	}
+	return user
+}
+
+// commentOutHeapFlags moves the heap sizes flags also sets out of the
+// user's lines and into comments.
+func commentOutHeapFlags(user string, flags []string) string {
+	var managed []string
+	for _, flag := range heapFlags {
+		if argsSetFlag(flags, flag) {
+			managed = append(managed, flag)
+		}
+	}
+	if len(managed) == 0 {
+		return user
+	}
+	lines := strings.Split(user, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		var kept, replaced []string
+		for _, arg := range strings.Fields(trimmed) {
+			if setsAnyFlag(arg, managed) {
+				replaced = append(replaced, arg)
+			} else {
+				kept = append(kept, arg)
+			}
+		}
+		if len(replaced) == 0 {
+			continue
+		}
+		lines[i] = "# Replaced by the panel's RAM settings: " + strings.Join(replaced, " ")
+		if len(kept) > 0 {
+			lines[i] += "\n" + strings.Join(kept, " ")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func setsAnyFlag(arg string, flags []string) bool {
+	for _, flag := range flags {
+		if argsSetFlag([]string{arg}, flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// argsSetFlag reports whether args has a value for a flag such as -Xmx.
+func argsSetFlag(args []string, flag string) bool {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, flag) && len(arg) > len(flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// userJVMHeap returns the -Xms and -Xmx a user_jvm_args.txt sets outside
+// the panel's block, as megabyte sizes like "4096M".
+func userJVMHeap(path string) (minRAM, maxRAM string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", ""
+	}
+	for _, line := range strings.Split(userJVMArgsOutsideBlock(string(data)), "\n") {
+		if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, arg := range strings.Fields(line) {
+			if value, ok := strings.CutPrefix(arg, "-Xms"); ok {
+				minRAM = jvmSizeToMB(value)
+			} else if value, ok := strings.CutPrefix(arg, "-Xmx"); ok {
+				maxRAM = jvmSizeToMB(value)
+			}
+		}
+	}
+	return minRAM, maxRAM
+}
+
+// jvmSizeToMB converts a JVM memory size such as "6G" or "512m" to
+// megabytes, or returns "" when it cannot be read.
+func jvmSizeToMB(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	number, unit := value, byte(0)
+	if last := value[len(value)-1]; last < '0' || last > '9' {
+		number, unit = value[:len(value)-1], last|0x20
+	}
+	var perUnit float64
+	switch unit {
+	case 0:
+		perUnit = 1.0 / (1 << 20)
+	case 'k':
+		perUnit = 1.0 / 1024
+	case 'm':
+		perUnit = 1
+	case 'g':
+		perUnit = 1024
+	case 't':
+		perUnit = 1 << 20
+	default:
+		return ""
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n <= 0 {
+		return ""
+	}
+	mb := int(n * perUnit)
+	if mb <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%dM", mb)
+}
+
+// userJVMArgsManaged reports whether the panel keeps flags in a
+// user_jvm_args.txt.
+func userJVMArgsManaged(content string) bool {
+	if strings.HasPrefix(content, userJVMArgsHeader) {
+		return true
+	}
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == userJVMArgsBegin {
+			return true
+		}
+	}
+	return false
+}
+
+// validateUserJVMArgs refuses a start where the heap size is set more
+// than once across user_jvm_args.txt and the start command. Which one
+// the JVM honors depends on argument order, so a stale -Xmx left behind
+// silently decides how much memory the server gets.
+func validateUserJVMArgs(path string, startCommand []string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	var args []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		args = append(args, strings.Fields(line)...)
+	}
+	args = append(args, startCommand...)
+
+	for _, flag := range heapFlags {
+		var defs []string
+		for _, arg := range args {
+			if strings.HasPrefix(arg, flag) && len(arg) > len(flag) {
+				defs = append(defs, arg)
+			}
+		}
+		if len(defs) > 1 {
+			return fmt.Errorf("%s is set more than once (%s) in %s or the start command; remove the duplicates before starting", flag, strings.Join(defs, ", "), filepath.Base(path))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteManagedUserJVMArgsKeepsUserFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "user_jvm_args.txt")
+	if err := os.WriteFile(path, []byte("# Xmx and Xms set the maximum and minimum RAM usage\n-Xmx6G\n"), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if err := writeManagedUserJVMArgs(path, []string{"-XX:+UseG1GC"}); err != nil {
+		t.Fatalf("writeManagedUserJVMArgs failed: %v", err)
+	}
+	if err := writeManagedUserJVMArgs(path, []string{"-XX:+UseZGC", "-XX:+ZGenerational"}); err != nil {
+		t.Fatalf("writeManagedUserJVMArgs failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	want := userJVMArgsBegin + "\n-XX:+UseZGC\n-XX:+ZGenerational\n" + userJVMArgsEnd + "\n# Xmx and Xms set the maximum and minimum RAM usage\n-Xmx6G\n"
+	if string(data) != want {
+		t.Fatalf("unexpected file:\n%s\nwant:\n%s", data, want)
+	}
+	if !userJVMArgsManaged(string(data)) {
+		t.Fatal("expected the file to count as managed")
+	}
+
+	// A file older panels wrote whole is replaced rather than kept.
+	if err := os.WriteFile(path, []byte(userJVMArgsHeader+"-XX:+UseG1GC\n"), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := writeManagedUserJVMArgs(path, []string{"-XX:+UseG1GC"}); err != nil {
+		t.Fatalf("writeManagedUserJVMArgs failed: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if string(data) != userJVMArgsBegin+"\n-XX:+UseG1GC\n"+userJVMArgsEnd+"\n" {
+		t.Fatalf("expected the legacy file to be replaced, got:\n%s", data)
+	}
+}
+
+func TestValidateUserJVMArgs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "user_jvm_args.txt")
+	startCommand := []string{"java", "@user_jvm_args.txt", "@libraries/net/minecraftforge/forge/1.20.1-47.2.0/unix_args.txt", "nogui"}
+
+	if err := validateUserJVMArgs(path, startCommand); err != nil {
+		t.Fatalf("expected a missing file to pass, got %v", err)
+	}
+	if err := os.WriteFile(path, []byte("# -Xmx2G\n-Xmx4G -Xms2G\n"), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := validateUserJVMArgs(path, startCommand); err != nil {
+		t.Fatalf("expected one -Xmx to pass, got %v", err)
+	}
+	if err := os.WriteFile(path, []byte("-Xmx4G\n-Xmx8G\n"), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := validateUserJVMArgs(path, startCommand); err == nil || !strings.Contains(err.Error(), "-Xmx4G, -Xmx8G") {
+		t.Fatalf("expected a duplicate -Xmx error, got %v", err)
+	}
+	if err := os.WriteFile(path, []byte("-Xms1G\n"), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := validateUserJVMArgs(path, []string{"java", "-Xms2G", "@user_jvm_args.txt"}); err == nil || !strings.Contains(err.Error(), "-Xms") {
+		t.Fatalf("expected a duplicate -Xms error across the start command, got %v", err)
+	}
+}
+
+func TestWriteManagedUserJVMArgsReplacesUserHeap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "user_jvm_args.txt")
+	if err := os.WriteFile(path, []byte("-Xmx6G -XX:+UseG1GC\n"), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if minRAM, maxRAM := userJVMHeap(path); minRAM != "" || maxRAM != "6144M" {
+		t.Fatalf("expected the user's heap to read as 6144M, got min=%q max=%q", minRAM, maxRAM)
+	}
+
+	cfg := &ServerConfig{MinRAM: "1024M", MaxRAM: "4096M", Flags: "none"}
+	if err := writeManagedUserJVMArgs(path, managedJVMArgs(cfg)); err != nil {
+		t.Fatalf("writeManagedUserJVMArgs failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	want := userJVMArgsBegin + "\n-Xms1024M\n-Xmx4096M\n--add-modules=jdk.incubator.vector\n" + userJVMArgsEnd + "\n# Replaced by the panel's RAM settings: -Xmx6G\n-XX:+UseG1GC\n"
+	if string(data) != want {
+		t.Fatalf("unexpected file:\n%s\nwant:\n%s", data, want)
+	}
+	if err := validateUserJVMArgs(path, []string{"bash", "run.sh", "nogui"}); err != nil {
+		t.Fatalf("expected the rewritten file to pass validation, got %v", err)
+	}
+	if minRAM, maxRAM := userJVMHeap(path); minRAM != "" || maxRAM != "" {
+		t.Fatalf("expected no user heap once replaced, got min=%q max=%q", minRAM, maxRAM)
+	}
+}
+
+func TestJVMSizeToMB(t *testing.T) {
+	cases := map[string]string{"6G": "6144M", "512m": "512M", "2097152k": "2048M", "1073741824": "1024M", "1T": "1048576M", "x": "", "": ""}
+	for in, want := range cases {
+		if got := jvmSizeToMB(in); got != want {
+			t.Fatalf("jvmSizeToMB(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -53,6 +53,9 @@ func TestKillServerTerminatesProcessAndResetsState(t *testing.T) {
 	if err := cmd.Start(); err != nil {
 		t.Fatalf("failed to start test process: %v", err)
 	}
+	// StartServer always reaps the process from a waiter goroutine; mirror that
+	// here so the killed child does not linger as a zombie.
+	go func() { _ = cmd.Wait() }()
 
 	const id = "srv1"
 	rs := &runningServer{
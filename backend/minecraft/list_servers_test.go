@@ -0,0 +1,33 @@
+package minecraft
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListServersDoesNotBlockOnBusyServer(t *testing.T) {
+	rs := &runningServer{status: "Stopped"}
+	mgr := &Manager{
+		configs: map[string]*ServerConfig{"srv1": {ID: "srv1", Name: "Lobby", Type: "Paper"}},
+		running: map[string]*runningServer{"srv1": rs},
+	}
+
+	// Publish a snapshot, then hold the server lock as a slow writer would.
+	if got := mgr.ListServers(); len(got) != 1 || got[0].Status != "Stopped" {
+		t.Fatalf("unexpected initial listing: %+v", got)
+	}
+	rs.mu.Lock()
+	rs.status = "Installing"
+	defer rs.mu.Unlock()
+
+	done := make(chan []ServerInfo, 1)
+	go func() { done <- mgr.ListServers() }()
+	select {
+	case got := <-done:
+		if len(got) != 1 || got[0].Status != "Stopped" {
+			t.Fatalf("expected last published status while busy, got %+v", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("ListServers blocked on a busy server")
+	}
+}
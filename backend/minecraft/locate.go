@@ -0,0 +1,249 @@
+package minecraft
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// locateResultPattern matches the reply to "locate", e.g. "The nearest
+	// minecraft:village_plains is at [320, ~, -112] (341 blocks away)".
+	locateResultPattern = regexp.MustCompile(`The nearest (\S+) is at \[(-?\d+), (~|-?\d+), (-?\d+)\](?: \((\d+) blocks? away\))?`)
+	structureIDPattern  = regexp.MustCompile(`^#?(?:[a-z0-9_.-]+:)?[a-z0-9_./-]+$`)
+)
+
+// PlayerLocation is where a player is, read live over RCON or taken from
+// the last console refresh.
+type PlayerLocation struct {
+	Name      string    `json:"name"`
+	World     string    `json:"world,omitempty"`
+	Position  []float64 `json:"position"`
+	Source    string    `json:"source"`
+	UpdatedAt string    `json:"updatedAt"`
+}
+
+// LocateStructureRequest asks for the structure nearest to a player or to
+// an x, z position. With neither, the search starts at world spawn.
+type LocateStructureRequest struct {
+	Structure string `json:"structure"`
+	Player    string `json:"player,omitempty"`
+	X         *int   `json:"x,omitempty"`
+	Z         *int   `json:"z,omitempty"`
+}
+
+// StructureLocation is the result of a structure search. Y is omitted when
+// the game reports it as "~".
+type StructureLocation struct {
+	Structure string `json:"structure"`
+	X         int    `json:"x"`
+	Y         *int   `json:"y,omitempty"`
+	Z         int    `json:"z"`
+	Distance  int    `json:"distance"`
+}
+
+// parsePlayerPosition reads the three coordinates of a Pos reply.
+func parsePlayerPosition(values []string) ([]float64, bool) {
+	if len(values) != 3 {
+		return nil, false
+	}
+	pos := make([]float64, 0, 3)
+	for _, v := range values {
+		f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(v), "d"), 64)
+		if err != nil {
+			return nil, false
+		}
+		pos = append(pos, f)
+	}
+	return pos, true
+}
+
+// onlinePlayerForLocate returns the server's running state and the
+// canonical name of an online player.
+func (m *Manager) onlinePlayerForLocate(id, player string) (*runningServer, string, error) {
+	m.mu.RLock()
+	_, err := m.serverConfigForOperationLocked(id)
+	rs, rsOk := m.running[id]
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, "", err
+	}
+	if !rsOk {
+		return nil, "", errServerNotFound(id)
+	}
+
+	player = strings.TrimSpace(player)
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	if rs.status != "Running" {
+		return nil, "", fmt.Errorf("server %s is not running", id)
+	}
+	for _, p := range rs.players {
+		if strings.EqualFold(p.Name, player) {
+			return rs, p.Name, nil
+		}
+	}
+	return nil, "", fmt.Errorf("player %s is not online", player)
+}
+
+// LocatePlayer returns where an online player is. With RCON set up the
+// position is read live; otherwise the last one seen on the console is
+// returned.
+func (m *Manager) LocatePlayer(id, player string) (*PlayerLocation, error) {
+	rs, name, err := m.onlinePlayerForLocate(id, player)
+	if err != nil {
+		return nil, err
+	}
+	if !isValidPlayerName(name) {
+		return m.cachedPlayerLocation(rs, name)
+	}
+
+	reply, err := m.rconQuery(id, "data get entity "+name+" Pos")
+	if err != nil {
+		return m.cachedPlayerLocation(rs, name)
+	}
+	_, snbt, ok := strings.Cut(reply, "entity data: ")
+	if !ok {
+		return nil, fmt.Errorf("unexpected reply: %s", reply)
+	}
+	value, err := parseSNBT(snbt)
+	if err != nil {
+		return nil, err
+	}
+	list, _ := value.([]any)
+	pos := make([]float64, 0, 3)
+	for _, v := range list {
+		if f, ok := v.(float64); ok {
+			pos = append(pos, f)
+		}
+	}
+	if len(pos) != 3 {
+		return nil, fmt.Errorf("unexpected position: %s", snbt)
+	}
+
+	world := ""
+	if reply, err := m.rconQuery(id, "data get entity "+name+" Dimension"); err == nil {
+		if _, dim, ok := strings.Cut(reply, "entity data: "); ok {
+			world = normalizePlayerWorld(dim)
+		}
+	}
+
+	now := time.Now()
+	rs.mu.Lock()
+	if p, ok := rs.players[name]; ok {
+		p.Position = pos
+		p.PositionAt = now
+		if world != "" {
+			p.World = world
+			p.LastWorldAt = now
+		} else {
+			world = p.World
+		}
+	}
+	rs.mu.Unlock()
+
+	return &PlayerLocation{
+		Name:      name,
+		World:     world,
+		Position:  pos,
+		Source:    PlayerDataLive,
+		UpdatedAt: now.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// cachedPlayerLocation returns the position last read from the console.
+func (m *Manager) cachedPlayerLocation(rs *runningServer, name string) (*PlayerLocation, error) {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	p, ok := rs.players[name]
+	if !ok || len(p.Position) != 3 {
+		return nil, fmt.Errorf("no position known for %s yet; configure RCON for live lookups", name)
+	}
+	return &PlayerLocation{
+		Name:      name,
+		World:     p.World,
+		Position:  append([]float64(nil), p.Position...),
+		Source:    PlayerDataSaved,
+		UpdatedAt: p.PositionAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// locateCommand builds the locate command for a server version. Versions
+// before 1.19 take a structure name instead of "structure <id>".
+func locateCommand(version string, req LocateStructureRequest) string {
+	command := "locate structure " + req.Structure
+	if version != "" && compareVersions(version, "1.19") < 0 {
+		command = "locate " + strings.TrimPrefix(req.Structure, "minecraft:")
+	}
+	switch {
+	case req.Player != "":
+		return fmt.Sprintf("execute at %s run %s", req.Player, command)
+	case req.X != nil && req.Z != nil:
+		return fmt.Sprintf("execute positioned %d 0 %d run %s", *req.X, *req.Z, command)
+	}
+	return command
+}
+
+// parseLocateResult reads a locate reply.
+func parseLocateResult(reply string) (*StructureLocation, error) {
+	match := locateResultPattern.FindStringSubmatch(reply)
+	if match == nil {
+		reply = strings.TrimSpace(reply)
+		if reply == "" {
+			reply = "no reply from the server"
+		}
+		return nil, fmt.Errorf("%s", reply)
+	}
+	loc := &StructureLocation{Structure: match[1]}
+	loc.X, _ = strconv.Atoi(match[2])
+	loc.Z, _ = strconv.Atoi(match[4])
+	if match[3] != "~" {
+		y, _ := strconv.Atoi(match[3])
+		loc.Y = &y
+	}
+	if match[5] != "" {
+		loc.Distance, _ = strconv.Atoi(match[5])
+	}
+	return loc, nil
+}
+
+// LocateStructure runs "locate structure" over RCON and returns the
+// nearest match. RCON is required because the result is read from the
+// command's reply.
+func (m *Manager) LocateStructure(id string, req LocateStructureRequest) (*StructureLocation, error) {
+	req.Structure = strings.ToLower(strings.TrimSpace(req.Structure))
+	if !structureIDPattern.MatchString(req.Structure) {
+		return nil, fmt.Errorf("invalid structure id %q", req.Structure)
+	}
+	if (req.X == nil) != (req.Z == nil) {
+		return nil, fmt.Errorf("x and z must be given together")
+	}
+
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if isProxyType(cfg.Type) {
+		return nil, fmt.Errorf("proxy servers have no worlds to search")
+	}
+	if req.Player != "" {
+		_, name, err := m.onlinePlayerForLocate(id, req.Player)
+		if err != nil {
+			return nil, err
+		}
+		if !isValidPlayerName(name) {
+			return nil, fmt.Errorf("invalid player name %q", name)
+		}
+		req.Player = name
+	}
+
+	reply, err := m.rconQuery(id, locateCommand(cfg.Version, req))
+	if err != nil {
+		return nil, err
+	}
+	return parseLocateResult(reply)
+}
@@ -0,0 +1,55 @@
+package minecraft
+
+import "testing"
+
+func TestParseLocateResult(t *testing.T) {
+	loc, err := parseLocateResult("The nearest minecraft:village_plains is at [320, ~, -112] (341 blocks away)")
+	if err != nil {
+		t.Fatalf("parseLocateResult failed: %v", err)
+	}
+	if loc.Structure != "minecraft:village_plains" || loc.X != 320 || loc.Y != nil || loc.Z != -112 || loc.Distance != 341 {
+		t.Fatalf("unexpected location: %+v", loc)
+	}
+
+	loc, err = parseLocateResult("The nearest minecraft:ancient_city is at [-1200, -51, 880] (1480 blocks away)")
+	if err != nil || loc.Y == nil || *loc.Y != -51 {
+		t.Fatalf("expected a y coordinate, got %+v (%v)", loc, err)
+	}
+
+	if _, err := parseLocateResult("Could not find a structure of type \"minecraft:stronghold\" nearby"); err == nil {
+		t.Fatalf("expected the failure reply to be returned as an error")
+	}
+}
+
+func TestLocateCommand(t *testing.T) {
+	x, z := 100, -50
+	cases := []struct {
+		version string
+		req     LocateStructureRequest
+		want    string
+	}{
+		{"1.20.4", LocateStructureRequest{Structure: "minecraft:village_plains"}, "locate structure minecraft:village_plains"},
+		{"1.20.4", LocateStructureRequest{Structure: "#minecraft:village", Player: "Steve"}, "execute at Steve run locate structure #minecraft:village"},
+		{"1.21", LocateStructureRequest{Structure: "minecraft:monument", X: &x, Z: &z}, "execute positioned 100 0 -50 run locate structure minecraft:monument"},
+		{"1.18.2", LocateStructureRequest{Structure: "minecraft:stronghold"}, "locate stronghold"},
+	}
+	for _, c := range cases {
+		if got := locateCommand(c.version, c.req); got != c.want {
+			t.Fatalf("locateCommand(%q, %+v) = %q, want %q", c.version, c.req, got, c.want)
+		}
+	}
+}
+
+func TestPositionPatternReadsEntityPos(t *testing.T) {
+	matches := positionPattern.FindStringSubmatch("[12:00:00 INFO]: Steve has the following entity data: [-12.5d, 64.0d, 1.3E-4d]")
+	if len(matches) < 5 || matches[1] != "Steve" {
+		t.Fatalf("expected a position match, got %v", matches)
+	}
+	pos, ok := parsePlayerPosition(matches[2:5])
+	if !ok || pos[0] != -12.5 || pos[1] != 64 || pos[2] != 1.3e-4 {
+		t.Fatalf("unexpected position %v", pos)
+	}
+	if dimensionPattern.MatchString("Steve has the following entity data: [-12.5d, 64.0d, 3.0d]") {
+		t.Fatalf("position replies must not be read as dimensions")
+	}
+}
@@ -0,0 +1,389 @@
+package minecraft
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultLogSearchPageSize = 100
+	maxLogSearchPageSize     = 500
+	maxLogSearchContext      = 10
+	// maxLogSearchMatches stops a search for something as common as "INFO"
+	// from reading every log the server ever wrote.
+	maxLogSearchMatches = 5000
+	maxLogLineBytes     = 1 << 20
+)
+
+var (
+	// logLinePattern reads the clock time and level from vanilla
+	// ("[12:34:56] [Server thread/WARN]:") and Paper ("[12:34:56 WARN]:")
+	// log lines.
+	logLinePattern = regexp.MustCompile(`^\[(\d{2}):(\d{2}):(\d{2})(?:\.\d+)?(?: ([A-Z]+))?\](?: \[[^\]]*/([A-Z]+)\])?`)
+	// rotatedLogPattern matches the dated names Minecraft gives rotated
+	// logs, such as 2024-01-15-2.log.gz.
+	rotatedLogPattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-\d+\.log(?:\.gz)?$`)
+)
+
+// logLevelRank orders log levels by severity. Unknown levels rank as INFO.
+var logLevelRank = map[string]int{
+	"TRACE":   0,
+	"DEBUG":   1,
+	"INFO":    2,
+	"WARN":    3,
+	"WARNING": 3,
+	"ERROR":   4,
+	"SEVERE":  4,
+	"FATAL":   5,
+}
+
+// LogSearchOptions narrows a log search. Query is matched case-insensitively
+// unless Regex is set, in which case it is a Go regular expression. Level
+// keeps lines of that severity or worse. From and To, when set, bound the
+// lines' times. Context is how many lines around each match to return.
+type LogSearchOptions struct {
+	Query    string
+	Regex    bool
+	Level    string
+	From     time.Time
+	To       time.Time
+	Page     int
+	PageSize int
+	Context  int
+}
+
+// LogSearchMatch is one matching log line with the lines around it.
+type LogSearchMatch struct {
+	File   string   `json:"file"`
+	Line   int      `json:"line"`
+	Time   string   `json:"time,omitempty"`
+	Level  string   `json:"level,omitempty"`
+	Text   string   `json:"text"`
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
+}
+
+// LogSearchResult is one page of matches. Truncated is set when the search
+// stopped at maxLogSearchMatches, in which case Total is that cap.
+type LogSearchResult struct {
+	Matches   []LogSearchMatch `json:"matches"`
+	Total     int              `json:"total"`
+	Page      int              `json:"page"`
+	PageSize  int              `json:"pageSize"`
+	Truncated bool             `json:"truncated,omitempty"`
+}
+
+// SearchLogs searches latest.log and the rotated logs of a server, newest
+// file first and lines in file order. Stack trace lines carry the level and
+// time of the line that started them, so a level filter keeps whole traces.
+func (m *Manager) SearchLogs(ctx context.Context, id string, opts LogSearchOptions) (*LogSearchResult, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	search, err := newLogSearch(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	logsDir := filepath.Join(cfg.Dir, "logs")
+	entries, err := os.ReadDir(logsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	type logFile struct {
+		name    string
+		modTime time.Time
+	}
+	files := make([]logFile, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		lower := strings.ToLower(name)
+		if entry.IsDir() || !(strings.HasSuffix(lower, ".log") || strings.HasSuffix(lower, ".log.gz")) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, logFile{name: name, modTime: info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+
+	for _, file := range files {
+		if search.done() {
+			break
+		}
+		if err := search.scanFile(ctx, filepath.Join(logsDir, file.name), file.name, file.modTime); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("failed to search %s: %w", file.name, err)
+		}
+	}
+	return search.result(), nil
+}
+
+// logSearch holds the state of one search across files.
+type logSearch struct {
+	opts     LogSearchOptions
+	pattern  *regexp.Regexp
+	needle   string
+	minLevel int
+	start    int
+	end      int
+	matches  []LogSearchMatch
+	total    int
+}
+
+func newLogSearch(opts LogSearchOptions) (*logSearch, error) {
+	if opts.Page < 1 {
+		opts.Page = 1
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = defaultLogSearchPageSize
+	}
+	if opts.PageSize > maxLogSearchPageSize {
+		opts.PageSize = maxLogSearchPageSize
+	}
+	if opts.Context < 0 {
+		opts.Context = 0
+	}
+	if opts.Context > maxLogSearchContext {
+		opts.Context = maxLogSearchContext
+	}
+	s := &logSearch{opts: opts, minLevel: -1}
+	s.start = (opts.Page - 1) * opts.PageSize
+	s.end = s.start + opts.PageSize
+
+	if level := strings.ToUpper(strings.TrimSpace(opts.Level)); level != "" {
+		rank, ok := logLevelRank[level]
+		if !ok {
+			return nil, fmt.Errorf("unknown log level %q, use INFO, WARN or ERROR", opts.Level)
+		}
+		s.minLevel = rank
+	}
+	if opts.Regex && opts.Query != "" {
+		pattern, err := regexp.Compile(opts.Query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		s.pattern = pattern
+	} else {
+		s.needle = strings.ToLower(opts.Query)
+	}
+	return s, nil
+}
+
+func (s *logSearch) done() bool {
+	return s.total >= maxLogSearchMatches
+}
+
+func (s *logSearch) result() *LogSearchResult {
+	matches := s.matches
+	if matches == nil {
+		matches = []LogSearchMatch{}
+	}
+	return &LogSearchResult{
+		Matches:   matches,
+		Total:     s.total,
+		Page:      s.opts.Page,
+		PageSize:  s.opts.PageSize,
+		Truncated: s.done(),
+	}
+}
+
+func (s *logSearch) matchesText(line string) bool {
+	if s.pattern != nil {
+		return s.pattern.MatchString(line)
+	}
+	return s.needle == "" || strings.Contains(strings.ToLower(line), s.needle)
+}
+
+// scanFile searches one log. Line times combine the clock time on each
+// line with the file's date: the date in a rotated log's name, or for
+// latest.log the day it was last written, stepped back by each midnight
+// the log crosses.
+func (s *logSearch) scanFile(ctx context.Context, path, name string, modTime time.Time) error {
+	day := modTime.Local()
+	if m := rotatedLogPattern.FindStringSubmatch(name); m != nil {
+		if parsed, err := time.ParseInLocation("2006-01-02", m[1], time.Local); err == nil {
+			day = parsed
+		}
+	} else {
+		rollovers, err := countLogRollovers(ctx, path)
+		if err != nil {
+			return err
+		}
+		day = day.AddDate(0, 0, -rollovers)
+	}
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+
+	return readLogLines(ctx, path, func(scanner *bufio.Scanner) error {
+		var (
+			before    []string
+			pending   []int
+			lineNo    int
+			level     = "INFO"
+			lineTime  time.Time
+			lastClock = -1
+		)
+		for scanner.Scan() {
+			lineNo++
+			if lineNo%4096 == 0 {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+			text := scanner.Text()
+
+			for i := 0; i < len(pending); {
+				match := &s.matches[pending[i]]
+				match.After = append(match.After, text)
+				if len(match.After) >= s.opts.Context {
+					pending = append(pending[:i], pending[i+1:]...)
+					continue
+				}
+				i++
+			}
+
+			if m := logLinePattern.FindStringSubmatch(text); m != nil {
+				h, _ := strconv.Atoi(m[1])
+				mins, _ := strconv.Atoi(m[2])
+				sec, _ := strconv.Atoi(m[3])
+				clock := h*3600 + mins*60 + sec
+				if lastClock >= 0 && clock < lastClock {
+					day = day.AddDate(0, 0, 1)
+				}
+				lastClock = clock
+				lineTime = day.Add(time.Duration(clock) * time.Second)
+				switch {
+				case m[5] != "":
+					level = m[5]
+				case m[4] != "":
+					level = m[4]
+				default:
+					level = "INFO"
+				}
+			}
+
+			if s.keep(text, level, lineTime) {
+				if s.total >= s.start && s.total < s.end {
+					match := LogSearchMatch{
+						File:   name,
+						Line:   lineNo,
+						Level:  level,
+						Text:   text,
+						Before: append([]string(nil), before...),
+					}
+					if !lineTime.IsZero() {
+						match.Time = lineTime.Format(time.RFC3339)
+					}
+					s.matches = append(s.matches, match)
+					if s.opts.Context > 0 {
+						pending = append(pending, len(s.matches)-1)
+					}
+				}
+				s.total++
+				if s.done() {
+					return nil
+				}
+			}
+
+			if s.opts.Context > 0 {
+				if len(before) == s.opts.Context {
+					before = append(before[:0], before[1:]...)
+				}
+				before = append(before, text)
+			}
+		}
+		return scanner.Err()
+	})
+}
+
+func (s *logSearch) keep(text, level string, at time.Time) bool {
+	if s.minLevel >= 0 {
+		rank, ok := logLevelRank[level]
+		if !ok {
+			rank = logLevelRank["INFO"]
+		}
+		if rank < s.minLevel {
+			return false
+		}
+	}
+	if !at.IsZero() {
+		if !s.opts.From.IsZero() && at.Before(s.opts.From) {
+			return false
+		}
+		if !s.opts.To.IsZero() && at.After(s.opts.To) {
+			return false
+		}
+	}
+	return s.matchesText(text)
+}
+
+// countLogRollovers counts how often the clock on a log's lines goes
+// backwards, i.e. how many midnights the log spans.
+func countLogRollovers(ctx context.Context, path string) (int, error) {
+	rollovers := 0
+	err := readLogLines(ctx, path, func(scanner *bufio.Scanner) error {
+		last := -1
+		for scanner.Scan() {
+			m := logLinePattern.FindStringSubmatch(scanner.Text())
+			if m == nil {
+				continue
+			}
+			h, _ := strconv.Atoi(m[1])
+			mins, _ := strconv.Atoi(m[2])
+			sec, _ := strconv.Atoi(m[3])
+			clock := h*3600 + mins*60 + sec
+			if last >= 0 && clock < last {
+				rollovers++
+			}
+			last = clock
+		}
+		return scanner.Err()
+	})
+	return rollovers, err
+}
+
+// readLogLines opens a log, decompressing .gz files, and hands a line
+// scanner to scan.
+func readLogLines(ctx context.Context, path string, scan func(*bufio.Scanner) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(strings.ToLower(path), ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLogLineBytes)
+	return scan(scanner)
+}
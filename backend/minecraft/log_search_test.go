@@ -0,0 +1,81 @@
+package minecraft
+
+import (
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSearchLogs(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv1", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = &runningServer{status: "Stopped"}
+	mgr.mu.Unlock()
+
+	logsDir := filepath.Join(cfg.Dir, "logs")
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		t.Fatalf("failed to create logs dir: %v", err)
+	}
+	rotated, err := os.Create(filepath.Join(logsDir, "2024-01-15-1.log.gz"))
+	if err != nil {
+		t.Fatalf("failed to create rotated log: %v", err)
+	}
+	gz := gzip.NewWriter(rotated)
+	gz.Write([]byte("[23:59:58] [Server thread/INFO]: Starting minecraft server\n[00:00:01] [Server thread/ERROR]: Encountered an unexpected exception\njava.lang.NullPointerException: boom\n\tat net.minecraft.Foo.tick(Foo.java:10)\n"))
+	gz.Close()
+	rotated.Close()
+	old := time.Now().Add(-24 * time.Hour)
+	os.Chtimes(filepath.Join(logsDir, "2024-01-15-1.log.gz"), old, old)
+
+	latest := "[10:00:00 INFO]: Done (3.2s)! For help, type \"help\"\n[10:00:05 WARN]: Can't keep up! Is the server overloaded?\n[10:00:06 INFO]: Steve joined the game\n"
+	if err := os.WriteFile(filepath.Join(logsDir, "latest.log"), []byte(latest), 0644); err != nil {
+		t.Fatalf("failed to write latest.log: %v", err)
+	}
+
+	ctx := context.Background()
+	res, err := mgr.SearchLogs(ctx, cfg.ID, LogSearchOptions{Level: "warn", Context: 1})
+	if err != nil {
+		t.Fatalf("SearchLogs failed: %v", err)
+	}
+	if res.Total != 4 || res.Matches[0].File != "latest.log" || res.Matches[0].Line != 2 || res.Matches[0].Level != "WARN" {
+		t.Fatalf("unexpected level search %+v", res)
+	}
+	if m := res.Matches[0]; len(m.Before) != 1 || len(m.After) != 1 || m.After[0] != "[10:00:06 INFO]: Steve joined the game" {
+		t.Fatalf("expected one line of context, got %+v", m)
+	}
+	if m := res.Matches[3]; m.File != "2024-01-15-1.log.gz" || m.Level != "ERROR" || m.Time != time.Date(2024, 1, 16, 0, 0, 1, 0, time.Local).Format(time.RFC3339) {
+		t.Fatalf("expected the stack trace to carry the error's level and time, got %+v", m)
+	}
+
+	res, err = mgr.SearchLogs(ctx, cfg.ID, LogSearchOptions{Query: `Null\w+Exception`, Regex: true})
+	if err != nil || res.Total != 1 || res.Matches[0].Line != 3 {
+		t.Fatalf("unexpected regex search %+v (%v)", res, err)
+	}
+	if _, err := mgr.SearchLogs(ctx, cfg.ID, LogSearchOptions{Query: "(", Regex: true}); err == nil {
+		t.Fatal("expected an invalid regex to be refused")
+	}
+	if _, err := mgr.SearchLogs(ctx, cfg.ID, LogSearchOptions{Level: "loud"}); err == nil {
+		t.Fatal("expected an unknown level to be refused")
+	}
+
+	res, err = mgr.SearchLogs(ctx, cfg.ID, LogSearchOptions{Query: "server", Page: 3, PageSize: 1})
+	if err != nil || res.Total != 3 || len(res.Matches) != 1 || res.Matches[0].File != "2024-01-15-1.log.gz" {
+		t.Fatalf("unexpected last page %+v (%v)", res, err)
+	}
+
+	from := time.Date(2024, 1, 16, 0, 0, 0, 0, time.Local)
+	res, err = mgr.SearchLogs(ctx, cfg.ID, LogSearchOptions{From: from, To: from.Add(time.Hour)})
+	if err != nil || res.Total != 3 {
+		t.Fatalf("expected the lines after midnight, got %+v (%v)", res, err)
+	}
+}
@@ -0,0 +1,70 @@
+package minecraft
+
+import (
+	"fmt"
+	"log"
+)
+
+// logSubscriberBuffer is how many console lines a subscriber may have
+// queued before new lines are dropped for it.
+const logSubscriberBuffer = 1000
+
+// maxDroppedLogLines is how many lines a subscriber may miss in a row
+// before it is disconnected. Its channel is closed so the client can
+// reconnect and catch up from the log buffer instead.
+const maxDroppedLogLines = 5 * logSubscriberBuffer
+
+// logSubscriber is a live console stream. dropped counts lines that did not
+// fit since the last one that did; lastDroppedSeq is the newest of them.
+type logSubscriber struct {
+	ch             chan ConsoleLogEntry
+	dropped        uint64
+	lastDroppedSeq uint64
+}
+
+// droppedMarker is the entry sent ahead of the next delivered line after
+// some were dropped. It takes the sequence of the last dropped line, which
+// the subscriber never received, so it sorts in place.
+func (s *logSubscriber) droppedMarker() ConsoleLogEntry {
+	return ConsoleLogEntry{
+		Seq:     s.lastDroppedSeq,
+		Line:    fmt.Sprintf("[Panel] %d console lines dropped because this viewer fell behind", s.dropped),
+		Dropped: s.dropped,
+	}
+}
+
+// broadcastLog sends a line to all active subscribers. A subscriber whose
+// queue is full misses the line and later gets a marker saying how many it
+// missed; one that keeps missing lines is disconnected.
+func (m *Manager) broadcastLog(rs *runningServer, entry ConsoleLogEntry) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	kept := rs.subscribers[:0]
+	for _, sub := range rs.subscribers {
+		if sub.dropped > 0 && cap(sub.ch)-len(sub.ch) >= 2 {
+			sub.ch <- sub.droppedMarker()
+			sub.dropped = 0
+		}
+		if sub.dropped == 0 {
+			select {
+			case sub.ch <- entry:
+				kept = append(kept, sub)
+				continue
+			default:
+			}
+		}
+		sub.dropped++
+		sub.lastDroppedSeq = entry.Seq
+		if sub.dropped >= maxDroppedLogLines {
+			log.Printf("Disconnecting console viewer after %d dropped lines", sub.dropped)
+			close(sub.ch)
+			continue
+		}
+		kept = append(kept, sub)
+	}
+	for i := len(kept); i < len(rs.subscribers); i++ {
+		rs.subscribers[i] = nil
+	}
+	rs.subscribers = kept
+}
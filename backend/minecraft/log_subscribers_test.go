@@ -0,0 +1,81 @@
+package minecraft
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBroadcastLogMarksDroppedLinesAndDisconnectsSlowSubscribers(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	rs := &runningServer{status: "Running"}
+	mgr.mu.Lock()
+	mgr.configs["srv"] = &ServerConfig{ID: "srv", Name: "Srv", Type: "Paper"}
+	mgr.running["srv"] = rs
+	mgr.mu.Unlock()
+
+	_, _, ch, unsubscribe := mgr.SubscribeLogsWithSnapshot("srv", 0)
+	defer unsubscribe()
+
+	emit := func(n int) {
+		for i := 0; i < n; i++ {
+			mgr.broadcastLog(rs, mgr.appendLog(rs, "line"))
+		}
+	}
+
+	// Overflow the queue by three lines, then drain it.
+	emit(logSubscriberBuffer + 3)
+	for i := 0; i < logSubscriberBuffer; i++ {
+		<-ch
+	}
+	emit(1)
+	marker := <-ch
+	if marker.Dropped != 3 || marker.Seq != logSubscriberBuffer+3 {
+		t.Fatalf("expected marker for 3 dropped lines at seq %d, got %+v", logSubscriberBuffer+3, marker)
+	}
+	if next := <-ch; next.Dropped != 0 || next.Seq != logSubscriberBuffer+4 {
+		t.Fatalf("expected the next line after the marker, got %+v", next)
+	}
+
+	// A subscriber that never reads is cut off.
+	emit(logSubscriberBuffer + maxDroppedLogLines)
+	for range ch {
+	}
+	rs.mu.RLock()
+	remaining := len(rs.subscribers)
+	rs.mu.RUnlock()
+	if remaining != 0 {
+		t.Fatalf("expected slow subscriber to be removed, %d left", remaining)
+	}
+}
+
+func TestConsoleLogExportsBufferAndStripsFormatting(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	rs := &runningServer{status: "Running"}
+	mgr.mu.Lock()
+	mgr.configs["srv"] = &ServerConfig{ID: "srv", Name: "Srv", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Srv")}
+	mgr.running["srv"] = rs
+	mgr.mu.Unlock()
+	mgr.appendLog(rs, "\x1b[33m[12:00:00 WARN]: §cPlugin§r failed\x1b[0m")
+	mgr.appendLog(rs, "second")
+
+	lines, err := mgr.ConsoleLog("srv")
+	if err != nil || len(lines) != 2 || lines[1] != "second" {
+		t.Fatalf("unexpected console lines %q (%v)", lines, err)
+	}
+	if got := StripConsoleFormatting(lines[0]); got != "[12:00:00 WARN]: Plugin failed" {
+		t.Fatalf("unexpected stripped line %q", got)
+	}
+	if _, err := mgr.ConsoleLog("missing"); err == nil {
+		t.Fatal("expected an unknown server to fail")
+	}
+}
@@ -0,0 +1,46 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// LoginAttemptRecord tracks failed logins from one client address.
+type LoginAttemptRecord struct {
+	Count        int       `json:"count"`
+	WindowStart  time.Time `json:"windowStart"`
+	BlockedUntil time.Time `json:"blockedUntil,omitempty"`
+}
+
+// LoadLoginAttempts returns the saved failed-login state keyed by client IP.
+// A missing document is an empty set.
+func (m *Manager) LoadLoginAttempts() (map[string]LoginAttemptRecord, error) {
+	attempts := make(map[string]LoginAttemptRecord)
+	data, err := m.storage().Load(storeDocLoginAttempts)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return attempts, nil
+		}
+		return nil, fmt.Errorf("failed to read login attempts: %w", err)
+	}
+	if err := json.Unmarshal(data, &attempts); err != nil {
+		return nil, fmt.Errorf("failed to parse login attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// SaveLoginAttempts replaces the saved failed-login state so lockouts
+// survive a panel restart.
+func (m *Manager) SaveLoginAttempts(attempts map[string]LoginAttemptRecord) error {
+	data, err := json.MarshalIndent(attempts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal login attempts: %w", err)
+	}
+	if err := m.storage().Save(storeDocLoginAttempts, data); err != nil {
+		return fmt.Errorf("failed to save login attempts: %w", err)
+	}
+	return nil
+}
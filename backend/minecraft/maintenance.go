@@ -0,0 +1,363 @@
+package minecraft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaintenanceWarning = 300
+	// maintenanceStartTimeout is how long the routine waits for the server
+	// to report Running again before it calls the run a failure.
+	maintenanceStartTimeout = 10 * time.Minute
+	maintenancePollInterval = 2 * time.Second
+)
+
+// MaintenanceRoutine is a server's maintenance recipe: warn the players,
+// back up, stop, update the server jar and outdated plugins, then start the
+// server again and check that it comes up. Stopping and starting always
+// happen; the other steps can be turned off.
+type MaintenanceRoutine struct {
+	// WarningSeconds is how long players are warned before the server
+	// stops. The server's restart warnings count down over it.
+	WarningSeconds int    `json:"warningSeconds"`
+	Reason         string `json:"reason,omitempty"`
+	Backup         bool   `json:"backup"`
+	UpdateJar      bool   `json:"updateJar"`
+	UpdatePlugins  bool   `json:"updatePlugins"`
+}
+
+// defaultMaintenanceRoutine is used until a server saves its own.
+func defaultMaintenanceRoutine() MaintenanceRoutine {
+	return MaintenanceRoutine{
+		WarningSeconds: defaultMaintenanceWarning,
+		Backup:         true,
+		UpdateJar:      true,
+		UpdatePlugins:  true,
+	}
+}
+
+// GetMaintenanceRoutine returns a server's maintenance routine.
+func (m *Manager) GetMaintenanceRoutine(id string) (*MaintenanceRoutine, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	routine := defaultMaintenanceRoutine()
+	if cfg.Maintenance != nil {
+		routine = *cfg.Maintenance
+	}
+	return &routine, nil
+}
+
+// SetMaintenanceRoutine replaces a server's maintenance routine.
+func (m *Manager) SetMaintenanceRoutine(id string, routine MaintenanceRoutine) (*MaintenanceRoutine, error) {
+	routine.Reason = strings.TrimSpace(routine.Reason)
+	if routine.WarningSeconds < 0 || routine.WarningSeconds > maxTaskRestartDelay {
+		return nil, fmt.Errorf("warningSeconds must be between 0 and %d", maxTaskRestartDelay)
+	}
+	if strings.ContainsAny(routine.Reason, "\r\n") || len(routine.Reason) > maxWarningMessageLength {
+		return nil, fmt.Errorf("reason must be a single line of at most %d characters", maxWarningMessageLength)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Maintenance = &routine
+	if err := m.persist(); err != nil {
+		return nil, err
+	}
+	return &routine, nil
+}
+
+// StartMaintenance runs a server's maintenance routine as one job and
+// returns the job right away. Each step reports its progress in the job's
+// log, and the first step that fails stops the routine. A server that was
+// not running is maintained but left stopped. The routine holds the
+// server's operation lock throughout, so no backup, restore or restart
+// runs between its steps.
+func (m *Manager) StartMaintenance(id string) (*Job, error) {
+	routine, err := m.GetMaintenanceRoutine(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range m.ListJobs(id, "") {
+		if job.Type == JobTypeMaintenance && !job.finished() {
+			return nil, fmt.Errorf("maintenance is already running on this server")
+		}
+	}
+
+	job := m.newJob(JobTypeMaintenance, id)
+	go func() {
+		err := m.runMaintenance(job, id, *routine)
+		if err != nil {
+			m.mu.RLock()
+			name := id
+			if cfg := m.configs[id]; cfg != nil {
+				name = cfg.Name
+			}
+			m.mu.RUnlock()
+			log.Printf("[%s] Maintenance failed: %v", name, err)
+		}
+		job.finish(err)
+	}()
+	return m.GetJob(job.id)
+}
+
+// maintenanceStep is one step of a maintenance run.
+type maintenanceStep struct {
+	name string
+	run  func() error
+}
+
+func (m *Manager) runMaintenance(job *jobHandle, id string, routine MaintenanceRoutine) error {
+	release, err := m.acquireServerOperation(job.ctx, id, operationMaintenance)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	rs := m.running[id]
+	var serverType, mode string
+	modpack := false
+	if err == nil {
+		serverType, mode, modpack = cfg.Type, cfg.BackupMode, cfg.Modpack != nil
+	}
+	m.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if rs == nil {
+		return errServerNotFound(id)
+	}
+	status := rs.currentRuntime().status
+	if status == "Booting" || status == "Installing" {
+		return fmt.Errorf("server is busy (%s)", status)
+	}
+	wasRunning := status == "Running" || status == "Suspended"
+	verify, rollback := m.restartVerificationEnabled(id)
+	var snap *updateSnapshot
+
+	var steps []maintenanceStep
+	if wasRunning {
+		steps = append(steps, maintenanceStep{"Warn players", func() error {
+			return m.maintenanceCountdown(job.ctx, id, routine.WarningSeconds, routine.Reason)
+		}})
+	}
+	if routine.Backup {
+		steps = append(steps, maintenanceStep{"Back up", func() error {
+			_, err := m.createBackupLocked(id, mode)
+			return err
+		}})
+	}
+	if wasRunning {
+		steps = append(steps, maintenanceStep{"Stop server", func() error {
+			return m.StopServer(id)
+		}})
+	}
+	if wasRunning && rollback && (routine.UpdateJar || routine.UpdatePlugins) {
+		steps = append(steps, maintenanceStep{"Save rollback copy", func() error {
+			var err error
+			snap, err = m.snapshotForUpdate(id)
+			return err
+		}})
+	}
+	if routine.UpdateJar {
+		steps = append(steps, maintenanceStep{"Update server jar", func() error {
+			if modpack {
+				job.log("Skipped: modpack servers are updated through their pack")
+				return nil
+			}
+			return m.maintenanceUpdateJar(rs, id, serverType)
+		}})
+	}
+	if routine.UpdatePlugins {
+		steps = append(steps, maintenanceStep{"Update plugins", func() error {
+			return m.maintenanceUpdatePlugins(job, id)
+		}})
+	}
+	if wasRunning {
+		steps = append(steps, maintenanceStep{"Start server", func() error {
+			return m.StartServer(id)
+		}})
+		if verify {
+			steps = append(steps, maintenanceStep{"Verify health", func() error {
+				if result := m.verifyRestart(job.ctx, id, verifyTriggerMaintenance, snap); result != nil && !result.OK {
+					return errors.New(result.Message)
+				}
+				return nil
+			}})
+		} else {
+			steps = append(steps, maintenanceStep{"Verify running", func() error {
+				return waitForServerRunning(job.ctx, rs, maintenanceStartTimeout)
+			}})
+		}
+	}
+
+	job.start("Running maintenance")
+	if !wasRunning {
+		job.log("Server is not running; it will be left stopped")
+	}
+	for i, step := range steps {
+		if err := job.ctx.Err(); err != nil {
+			return err
+		}
+		message := fmt.Sprintf("Step %d/%d: %s", i+1, len(steps), step.name)
+		job.progress(i*100/len(steps), message)
+		job.log(message)
+		if err := step.run(); err != nil {
+			job.log(fmt.Sprintf("%s failed, stopping maintenance: %v", step.name, err))
+			return fmt.Errorf("%s failed: %w", strings.ToLower(step.name), err)
+		}
+	}
+	job.log("Maintenance complete")
+	return nil
+}
+
+// maintenanceCountdown warns players with the server's restart warnings
+// over seconds, then once more right before the stop.
+func (m *Manager) maintenanceCountdown(ctx context.Context, id string, seconds int, reason string) error {
+	end := time.Now().Add(time.Duration(seconds) * time.Second)
+	leads := []int{}
+	if seconds > 0 {
+		leads = append(leads, seconds)
+	}
+	for _, minutes := range m.currentRestartWarningMinutes() {
+		if lead := minutes * 60; lead < seconds {
+			leads = append(leads, lead)
+		}
+	}
+	if seconds > 10 {
+		leads = append(leads, 10)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(leads)))
+
+	last := -1
+	for _, lead := range leads {
+		if lead == last {
+			continue
+		}
+		last = lead
+		if err := sleepContext(ctx, time.Until(end.Add(-time.Duration(lead)*time.Second))); err != nil {
+			return err
+		}
+		if err := m.SendCommand(id, m.warningCommand(id, warningRestart, lead, reason)); err != nil {
+			log.Printf("Server %s: maintenance warning not sent: %v", id, err)
+		}
+	}
+	if err := sleepContext(ctx, time.Until(end)); err != nil {
+		return err
+	}
+	if err := m.SendCommand(id, m.warningCommand(id, warningRestartNow, 0, reason)); err != nil {
+		log.Printf("Server %s: maintenance warning not sent: %v", id, err)
+	}
+	return sleepContext(ctx, time.Second)
+}
+
+// maintenanceUpdateJar downloads the newest build of the server's version,
+// the same way a version update does. Callers hold the server's operation
+// lock.
+func (m *Manager) maintenanceUpdateJar(rs *runningServer, id, serverType string) error {
+	m.mu.RLock()
+	version := ""
+	if cfg := m.configs[id]; cfg != nil {
+		version = cfg.Version
+	}
+	m.mu.RUnlock()
+
+	rs.mu.Lock()
+	if rs.status != "Stopped" && rs.status != "Crashed" && rs.status != "Error" {
+		status := rs.status
+		rs.mu.Unlock()
+		return fmt.Errorf("server must be stopped to update its jar (status: %s)", status)
+	}
+	rs.status = "Installing"
+	rs.installError = ""
+	rs.mu.Unlock()
+
+	m.installServerJarLocked(id, serverType, version)
+
+	rs.mu.RLock()
+	status, installError := rs.status, rs.installError
+	rs.mu.RUnlock()
+	if status == "Error" {
+		return errors.New(installError)
+	}
+	return nil
+}
+
+// maintenanceUpdatePlugins installs every plugin update the update check
+// finds. Plugins without a download link are listed in the job log.
+func (m *Manager) maintenanceUpdatePlugins(job *jobHandle, id string) error {
+	updates, err := m.CheckPluginUpdates(id)
+	if err != nil {
+		return err
+	}
+	updated := 0
+	for _, update := range updates {
+		if update.VersionStatus != "outdated" {
+			continue
+		}
+		if err := job.ctx.Err(); err != nil {
+			return err
+		}
+		if update.UpdateURL == "" {
+			job.log(fmt.Sprintf("%s %s has an update but no download link; update it by hand", update.Name, update.LatestVersion))
+			continue
+		}
+		if _, err := m.UpdatePlugin(id, update.FileName, update.UpdateURL, false); err != nil {
+			return fmt.Errorf("%s: %w", update.Name, err)
+		}
+		job.log(fmt.Sprintf("Updated %s to %s", update.Name, update.LatestVersion))
+		updated++
+	}
+	job.log(fmt.Sprintf("%d plugins updated", updated))
+	return nil
+}
+
+// waitForServerRunning waits for a started server to finish booting.
+func waitForServerRunning(ctx context.Context, rs *runningServer, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		switch status := rs.currentRuntime().status; status {
+		case "Running":
+			return nil
+		case "Booting":
+		default:
+			return fmt.Errorf("server did not come up (status: %s)", status)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("server was still booting after %s", timeout)
+		}
+		if err := sleepContext(ctx, maintenancePollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// sleepContext waits for d or until ctx is cancelled.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
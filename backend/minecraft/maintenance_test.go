@@ -0,0 +1,167 @@
+package minecraft
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceStopsAtTheFirstFailedStep(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv", Name: "Survival", Type: "NoSuchSoftware", Version: "1.21", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		t.Fatalf("failed to create server dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Dir, "server.properties"), []byte("motd=Hi\n"), 0644); err != nil {
+		t.Fatalf("failed to write server.properties: %v", err)
+	}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = &runningServer{status: "Stopped"}
+	mgr.mu.Unlock()
+
+	if routine, err := mgr.GetMaintenanceRoutine(cfg.ID); err != nil || !routine.Backup || !routine.UpdateJar || !routine.UpdatePlugins {
+		t.Fatalf("expected every step on by default, got %+v (%v)", routine, err)
+	}
+	if _, err := mgr.SetMaintenanceRoutine(cfg.ID, MaintenanceRoutine{WarningSeconds: -1}); err == nil {
+		t.Fatal("expected a negative warning to be refused")
+	}
+	if _, err := mgr.SetMaintenanceRoutine(cfg.ID, MaintenanceRoutine{Backup: true, UpdateJar: true, UpdatePlugins: true}); err != nil {
+		t.Fatalf("SetMaintenanceRoutine failed: %v", err)
+	}
+
+	job, err := mgr.StartMaintenance(cfg.ID)
+	if err != nil {
+		t.Fatalf("StartMaintenance failed: %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for !job.finished() {
+		if time.Now().After(deadline) {
+			t.Fatalf("maintenance job did not finish: %+v", job)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if job, err = mgr.GetJob(job.ID); err != nil {
+			t.Fatalf("GetJob failed: %v", err)
+		}
+	}
+
+	if job.State != JobStateFailed || !strings.Contains(job.Error, "update server jar failed") {
+		t.Fatalf("expected the jar update to fail the run, got %+v", job)
+	}
+	logs := strings.Join(job.Logs, "\n")
+	if !strings.Contains(logs, "Step 1/3: Back up") || !strings.Contains(logs, "Step 2/3: Update server jar") {
+		t.Fatalf("expected the backup and jar steps in the log, got %q", logs)
+	}
+	if strings.Contains(logs, "Update plugins") {
+		t.Fatalf("expected the routine to stop before the plugin updates, got %q", logs)
+	}
+	backups, err := os.ReadDir(mgr.backupDir(cfg))
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("expected the backup to have been made, got %v (%v)", backups, err)
+	}
+	if _, err := mgr.StartMaintenance("missing"); err == nil {
+		t.Fatal("expected an unknown server to be refused")
+	}
+}
+
+func TestMaintenanceHoldsTheOperationLockThroughout(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv", Name: "Survival", Type: "Paper", Version: "1.21", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		t.Fatalf("failed to create server dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Dir, "server.properties"), []byte("motd=Hi\n"), 0644); err != nil {
+		t.Fatalf("failed to write server.properties: %v", err)
+	}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = &runningServer{status: "Stopped"}
+	mgr.mu.Unlock()
+	if _, err := mgr.SetMaintenanceRoutine(cfg.ID, MaintenanceRoutine{Backup: true}); err != nil {
+		t.Fatalf("SetMaintenanceRoutine failed: %v", err)
+	}
+
+	// Hold the lock so maintenance queues, then queue a restore behind it.
+	release, err := mgr.acquireServerOperation(context.Background(), cfg.ID, operationBackup)
+	if err != nil {
+		t.Fatalf("acquireServerOperation failed: %v", err)
+	}
+	job, err := mgr.StartMaintenance(cfg.ID)
+	if err != nil {
+		t.Fatalf("StartMaintenance failed: %v", err)
+	}
+	lock := mgr.serverOperationLockFor(cfg.ID)
+	queued := func() int {
+		lock.mu.Lock()
+		defer lock.mu.Unlock()
+		return len(lock.queue)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for queued() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("maintenance did not queue for the operation lock")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	restored := make(chan []string, 1)
+	go func() {
+		releaseRestore, err := mgr.acquireServerOperation(context.Background(), cfg.ID, operationRestore)
+		if err != nil {
+			restored <- nil
+			return
+		}
+		defer releaseRestore()
+		current, _ := mgr.GetJob(job.ID)
+		restored <- current.Logs
+	}()
+	for queued() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("the restore did not queue for the operation lock")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	release()
+
+	select {
+	case logs := <-restored:
+		if !strings.Contains(strings.Join(logs, "\n"), "Maintenance complete") {
+			t.Fatalf("expected the restore to wait for the whole routine, got %q", logs)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("the restore never got the operation lock")
+	}
+}
+
+func TestWaitForServerRunningIgnoresStaleSnapshot(t *testing.T) {
+	rs := &runningServer{status: "Stopped"}
+	rs.displayRuntime()
+
+	// The server boots while its lock is busy; the snapshot published
+	// before the start still says Stopped.
+	rs.mu.Lock()
+	rs.status = "Booting"
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		rs.status = "Running"
+		rs.mu.Unlock()
+	}()
+	if status := rs.displayRuntime().status; status != "Stopped" {
+		t.Fatalf("expected the display snapshot to be stale, got %s", status)
+	}
+	if err := waitForServerRunning(context.Background(), rs, 5*time.Second); err != nil {
+		t.Fatalf("expected the server to be seen running, got %v", err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"net"
 	"os"
 	"os/exec"
@@ -19,6 +20,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -39,41 +41,110 @@ type ServerConfig struct {
 	Dir                 string   `json:"dir"`
 	StartCommand        []string `json:"startCommand,omitempty"`
 	AutoStart           bool     `json:"autoStart"`
+	ShowOnStatusPage    bool     `json:"showOnStatusPage,omitempty"`
 	Flags               string   `json:"flags"`
 	AlwaysPreTouch      bool     `json:"alwaysPreTouch"`
 	BackupSchedule      string   `json:"backupSchedule,omitempty"`
 	LastScheduledBackup string   `json:"lastScheduledBackup,omitempty"`
+	BackupMode          string   `json:"backupMode,omitempty"`
+	ScheduledRestartAt  string   `json:"scheduledRestartAt,omitempty"`
+	// ScheduledRestartReason is the reason given when the pending restart
+	// was scheduled, shown in its warnings.
+	ScheduledRestartReason string                       `json:"scheduledRestartReason,omitempty"`
+	PluginUpdateChannel    string                       `json:"pluginUpdateChannel,omitempty"`
+	ReadyCommands          []string                     `json:"readyCommands,omitempty"`
+	Groups                 []string                     `json:"groups,omitempty"`
+	WarningMessages        *WarningMessages             `json:"warningMessages,omitempty"`
+	RCON                   *RCONConfig                  `json:"rcon,omitempty"`
+	RegionPrune            *RegionPruneSettings         `json:"regionPrune,omitempty"`
+	WhitelistSchedule      *WhitelistSchedule           `json:"whitelistSchedule,omitempty"`
+	TempBans               []TempBan                    `json:"tempBans,omitempty"`
+	JoinCheck              *JoinCheckSettings           `json:"joinCheck,omitempty"`
+	Tasks                  []ScheduledTask              `json:"tasks,omitempty"`
+	Webhooks               []InboundWebhook             `json:"webhooks,omitempty"`
+	Maintenance            *MaintenanceRoutine          `json:"maintenance,omitempty"`
+	RestartVerification    *RestartVerificationSettings `json:"restartVerification,omitempty"`
+	RestartOnCrash         *CrashRestartSettings        `json:"restartOnCrash,omitempty"`
+	ConsoleBuffer          *ConsoleBufferSettings       `json:"consoleBuffer,omitempty"`
+	DiskQuota              *DiskQuotaSettings           `json:"diskQuota,omitempty"`
+	Network                *ProxyNetwork                `json:"network,omitempty"`
+	// Modpack is the modpack the server was installed from.
+	Modpack *ModpackInfo `json:"modpack,omitempty"`
+	// BackupTargets are the remote targets scheduled backups are uploaded
+	// to; BackupUploads is each backup's upload state, keyed by its name.
+	BackupTargets []string                  `json:"backupTargets,omitempty"`
+	BackupUploads map[string][]BackupUpload `json:"backupUploads,omitempty"`
+	// PID and ProcessStartedAt identify the server's process while it runs,
+	// so a restarted panel can reattach to it.
+	PID              int   `json:"pid,omitempty"`
+	ProcessStartedAt int64 `json:"processStartedAt,omitempty"`
 }
 
 // ServerInfo is the API-facing struct with runtime state
 type ServerInfo struct {
-	ID                 string  `json:"id"`
-	Name               string  `json:"name"`
-	Type               string  `json:"type"`
-	Version            string  `json:"version"`
-	Status             string  `json:"status"`
-	CPU                float64 `json:"cpu"`
-	RAM                float64 `json:"ram"`
-	TPS                float64 `json:"tps"`
-	Port               int     `json:"port"`
-	MaxRAM             string  `json:"maxRam"`
-	MinRAM             string  `json:"minRam"`
-	MaxPlayers         int     `json:"maxPlayers"`
-	AutoStart          bool    `json:"autoStart"`
-	Flags              string  `json:"flags"`
-	AlwaysPreTouch     bool    `json:"alwaysPreTouch"`
-	InstallError       string  `json:"installError,omitempty"`
-	FabricTpsAvailable bool    `json:"fabricTpsAvailable,omitempty"`
-	TpsStale           bool    `json:"tpsStale,omitempty"`
-	CPUExact           float64 `json:"cpuExact,omitempty"`
-	RAMBytes           uint64  `json:"ramBytes,omitempty"`
-	RAMMB              float64 `json:"ramMb,omitempty"`
+	ID                  string           `json:"id"`
+	Name                string           `json:"name"`
+	Type                string           `json:"type"`
+	Version             string           `json:"version"`
+	Status              string           `json:"status"`
+	CPU                 float64          `json:"cpu"`
+	RAM                 float64          `json:"ram"`
+	TPS                 float64          `json:"tps"`
+	Port                int              `json:"port"`
+	MaxRAM              string           `json:"maxRam"`
+	MinRAM              string           `json:"minRam"`
+	MaxPlayers          int              `json:"maxPlayers"`
+	AutoStart           bool             `json:"autoStart"`
+	ShowOnStatusPage    bool             `json:"showOnStatusPage"`
+	Flags               string           `json:"flags"`
+	AlwaysPreTouch      bool             `json:"alwaysPreTouch"`
+	PluginUpdateChannel string           `json:"pluginUpdateChannel"`
+	ReadyCommands       []string         `json:"readyCommands,omitempty"`
+	Groups              []string         `json:"groups,omitempty"`
+	WarningMessages     *WarningMessages `json:"warningMessages,omitempty"`
+	RCONPort            int              `json:"rconPort,omitempty"`
+	InstallError        string           `json:"installError,omitempty"`
+	BootFailedAt        string           `json:"bootFailedAt,omitempty"`
+	FailureReason       string           `json:"failureReason,omitempty"`
+	PortConflict        *PortConflict    `json:"portConflict,omitempty"`
+	FabricTpsAvailable  bool             `json:"fabricTpsAvailable,omitempty"`
+	TpsStale            bool             `json:"tpsStale,omitempty"`
+	CPUExact            float64          `json:"cpuExact,omitempty"`
+	RAMBytes            uint64           `json:"ramBytes,omitempty"`
+	RAMMB               float64          `json:"ramMb,omitempty"`
+	RestartAt           string           `json:"restartAt,omitempty"`
+	BusyWith            string           `json:"busyWith,omitempty"`
+	BusySince           string           `json:"busySince,omitempty"`
+	QueuedOperations    []string         `json:"queuedOperations,omitempty"`
+	JoinCheck           *JoinCheckResult `json:"joinCheck,omitempty"`
+	// RestartVerification is the last check made after an automated
+	// restart or update.
+	RestartVerification *RestartVerificationResult `json:"restartVerification,omitempty"`
+	// CrashCount is how many times the server crashed in the last hour.
+	// CrashRestarts counts the automatic restarts since it last stayed up,
+	// and CrashRestartAt is when the next one is due.
+	RestartOnCrash *CrashRestartSettings  `json:"restartOnCrash,omitempty"`
+	ConsoleBuffer  *ConsoleBufferSettings `json:"consoleBuffer,omitempty"`
+	DiskQuota      *DiskQuotaSettings     `json:"diskQuota,omitempty"`
+	CrashCount     int                    `json:"crashCount,omitempty"`
+	LastCrashAt    string                 `json:"lastCrashAt,omitempty"`
+	CrashRestarts  int                    `json:"crashRestarts,omitempty"`
+	CrashRestartAt string                 `json:"crashRestartAt,omitempty"`
+	SuspendedAt    string                 `json:"suspendedAt,omitempty"`
+	// SwapBytes is how much of the server's memory is swapped out, and
+	// MemoryWarning says the host is short on memory while it runs.
+	SwapBytes     uint64 `json:"swapBytes,omitempty"`
+	MemoryWarning string `json:"memoryWarning,omitempty"`
+	// TickMetrics breaks a Forge or NeoForge server's TPS down by dimension.
+	TickMetrics *TickMetrics `json:"tickMetrics,omitempty"`
+	Modpack     *ModpackInfo `json:"modpack,omitempty"`
 }
 
 // PluginInfo represents a plugin jar file
 type PluginInfo struct {
 	Name          string `json:"name"`
 	FileName      string `json:"fileName"`
+	Directory     string `json:"directory"`
 	Size          string `json:"size"`
 	Enabled       bool   `json:"enabled"`
 	Version       string `json:"version"`
@@ -81,6 +152,13 @@ type PluginInfo struct {
 	VersionStatus string `json:"versionStatus,omitempty"`
 	UpdateURL     string `json:"updateUrl,omitempty"`
 	SourceURL     string `json:"sourceUrl,omitempty"`
+	Note          string `json:"note,omitempty"`
+	IconURL       string `json:"iconUrl,omitempty"`
+	Description   string `json:"description,omitempty"`
+	ProjectURL    string `json:"projectUrl,omitempty"`
+
+	Provenance    *ExtensionProvenance `json:"provenance,omitempty"`
+	PendingUpdate *StagedPluginUpdate  `json:"pendingUpdate,omitempty"`
 }
 
 // BackupInfo represents a backup archive
@@ -88,6 +166,12 @@ type BackupInfo struct {
 	Name string `json:"name"`
 	Date string `json:"date"`
 	Size string `json:"size"`
+	// Kind is "incremental" for hard-linked snapshots, whose NewData is the
+	// space they added on top of the previous snapshot.
+	Kind    string `json:"kind,omitempty"`
+	NewData string `json:"newData,omitempty"`
+	// Uploads is the backup's state on each remote target it was sent to.
+	Uploads []BackupUpload `json:"uploads,omitempty"`
 }
 
 // FileEntry represents a file or directory in the server's filesystem
@@ -106,6 +190,10 @@ type PlayerInfo struct {
 	Ping       int    `json:"ping"`
 	World      string `json:"world"`
 	OnlineTime string `json:"onlineTime"`
+	// Position is the player's last known x, y, z and PositionAt when it
+	// was read.
+	Position   []float64 `json:"position,omitempty"`
+	PositionAt string    `json:"positionAt,omitempty"`
 }
 
 // onlinePlayer tracks a connected player's session
@@ -118,6 +206,8 @@ type onlinePlayer struct {
 	JoinedAt    time.Time
 	LastWorldAt time.Time
 	LastPingAt  time.Time
+	Position    []float64
+	PositionAt  time.Time
 }
 
 // CrashReport represents a crash report file
@@ -132,6 +222,9 @@ type CrashReport struct {
 type ConsoleLogEntry struct {
 	Seq  uint64 `json:"seq"`
 	Line string `json:"line"`
+	// Dropped is set on the marker sent to a slow subscriber, counting the
+	// lines it missed.
+	Dropped uint64 `json:"dropped,omitempty"`
 }
 
 // runningServer holds runtime state for a managed server
@@ -142,15 +235,21 @@ type runningServer struct {
 	cpu                   float64
 	ram                   float64
 	ramBytes              uint64
+	swapBytes             uint64
 	tps                   float64
+	pendingDimensionTicks []DimensionTick
+	tickMetrics           *TickMetrics
 	pid                   int
 	logBuffer             []ConsoleLogEntry
-	subscribers           []chan ConsoleLogEntry
+	logMaxLines           int
+	logTrimLines          int
+	subscribers           []*logSubscriber
 	nextLogSeq            uint64
 	players               map[string]*onlinePlayer
 	pingBlocked           map[string]bool
 	lastPingPlayer        string
 	restartTimer          *time.Timer
+	restartWarnTimers     []*time.Timer
 	restartAt             time.Time
 	stopTimer             *time.Timer
 	stopAt                time.Time
@@ -167,21 +266,138 @@ type runningServer struct {
 	pingSupported         bool
 	pingDisabledReason    string
 	safeModeDisabled      []string // dirs renamed for safe mode (original paths)
+	safeModeDisabledFiles []string // extension files renamed to .disabled for selective safe mode
+	bootStartedAt         time.Time
+	bootFailedAt          time.Time // set when the last boot exited before reaching Running
+	stopRequested         bool      // stop/kill issued, so an exit while booting is not a failure
+	failureReason         string    // why the current/last boot failed, e.g. FailureReasonPortInUse
+	portConflict          *PortConflict
+	reattached            bool        // process outlived a panel restart, so there is no cmd or stdin
+	crashTimes            []time.Time // crashes within the last crashCountWindow
+	crashRestarts         int         // automatic restarts since the server last stayed up
+	crashRestartTimer     *time.Timer
+	crashRestartAt        time.Time
+	crashRestarting       bool         // the next start is an automatic restart after a crash
+	queuedRestarts        []*jobHandle // restart jobs waiting for the operation lock
+	suspendedAt           time.Time
 	mu                    sync.RWMutex
+	stdinMu               sync.Mutex // serializes writes to stdin without holding mu
+	lastRuntime           atomic.Pointer[runtimeSnapshot]
 	stopMetrics           chan struct{}
 }
 
+// runtimeSnapshot is the status and metrics view of a runningServer that
+// serverInfo last read. It is served instead when the server lock is busy.
+type runtimeSnapshot struct {
+	status         string
+	installError   string
+	bootFailedAt   time.Time
+	failureReason  string
+	portConflict   *PortConflict
+	cpu            float64
+	ram            float64
+	ramBytes       uint64
+	swapBytes      uint64
+	tps            float64
+	tickMetrics    *TickMetrics
+	restartAt      time.Time
+	lastTpsUpdate  time.Time
+	crashCount     int
+	lastCrashAt    time.Time
+	crashRestarts  int
+	crashRestartAt time.Time
+	suspendedAt    time.Time
+}
+
+// displayRuntime returns the server's status and metrics for display. If
+// another goroutine holds the lock, the previously published snapshot is
+// returned so listing servers never waits on a busy server. That snapshot
+// can be stale, so it must not drive decisions such as whether to start,
+// stop or send commands to the server; use currentRuntime for those.
+func (rs *runningServer) displayRuntime() runtimeSnapshot {
+	if !rs.mu.TryRLock() {
+		if snap := rs.lastRuntime.Load(); snap != nil {
+			return *snap
+		}
+		rs.mu.RLock()
+	}
+	return rs.publishRuntimeRLocked()
+}
+
+// currentRuntime returns the server's status and metrics as they are now,
+// waiting for the lock if needed.
+func (rs *runningServer) currentRuntime() runtimeSnapshot {
+	rs.mu.RLock()
+	return rs.publishRuntimeRLocked()
+}
+
+// publishRuntimeRLocked builds a snapshot, releases the read lock the
+// caller took and publishes the snapshot for displayRuntime.
+func (rs *runningServer) publishRuntimeRLocked() runtimeSnapshot {
+	snap := runtimeSnapshot{
+		status:         rs.status,
+		installError:   rs.installError,
+		bootFailedAt:   rs.bootFailedAt,
+		failureReason:  rs.failureReason,
+		portConflict:   rs.portConflict,
+		cpu:            rs.cpu,
+		ram:            rs.ram,
+		ramBytes:       rs.ramBytes,
+		swapBytes:      rs.swapBytes,
+		tps:            rs.tps,
+		tickMetrics:    rs.tickMetrics,
+		restartAt:      rs.restartAt,
+		lastTpsUpdate:  rs.lastTpsUpdate,
+		crashRestarts:  rs.crashRestarts,
+		crashRestartAt: rs.crashRestartAt,
+		suspendedAt:    rs.suspendedAt,
+	}
+	for _, t := range rs.crashTimes {
+		if time.Since(t) < crashCountWindow {
+			snap.crashCount++
+		}
+		snap.lastCrashAt = t
+	}
+	rs.mu.RUnlock()
+	rs.lastRuntime.Store(&snap)
+	return snap
+}
+
+// writeStdin sends one line to the server process. The status check holds
+// mu only briefly; the pipe write itself happens under stdinMu so a server
+// that stops reading its input cannot stall readers of the runtime state.
+func (rs *runningServer) writeStdin(line string) error {
+	rs.mu.RLock()
+	status := rs.status
+	stdin := rs.stdin
+	rs.mu.RUnlock()
+
+	if status != "Running" && status != "Booting" {
+		return errServerNotRunning
+	}
+	if stdin == nil {
+		return errNoStdinPipe
+	}
+
+	rs.stdinMu.Lock()
+	defer rs.stdinMu.Unlock()
+	_, err := io.WriteString(stdin, line+"\n")
+	return err
+}
+
 func clearScheduledActionsLocked(rs *runningServer) {
 	if rs.restartTimer != nil {
 		rs.restartTimer.Stop()
 		rs.restartTimer = nil
 	}
+	stopRestartWarningsLocked(rs)
 	rs.restartAt = time.Time{}
 	if rs.stopTimer != nil {
 		rs.stopTimer.Stop()
 		rs.stopTimer = nil
 	}
 	rs.stopAt = time.Time{}
+	stopCrashRestartLocked(rs)
 }
 
 func resetStoppedRuntimeStateLocked(rs *runningServer) {
@@ -189,41 +405,110 @@ func resetStoppedRuntimeStateLocked(rs *runningServer) {
 	rs.cpu = 0
 	rs.ram = 0
 	rs.ramBytes = 0
+	rs.swapBytes = 0
 	rs.tps = 0
 	rs.pid = 0
+	rs.reattached = false
+	rs.suspendedAt = time.Time{}
+	rs.pendingDimensionTicks = nil
+	rs.tickMetrics = nil
 	rs.players = make(map[string]*onlinePlayer)
 	clearScheduledActionsLocked(rs)
 }
 
-func (m *Manager) executeRestart(id string, cfg *ServerConfig) {
-	log.Printf("[%s] Scheduled restart executing", cfg.Name)
-	m.SendCommand(id, "say Server restarting now!")
-	time.Sleep(1 * time.Second)
+func stopRestartWarningsLocked(rs *runningServer) {
+	for _, t := range rs.restartWarnTimers {
+		t.Stop()
+	}
+	rs.restartWarnTimers = nil
+}
+
+// runRestart runs a restart as a job under the server's operation lock, so
+// no restart overlaps a backup, restore or other long operation. While the
+// job waits for the lock it is queued on the server, where CancelRestart
+// can cancel it. restart does the work once the lock is held.
+func (m *Manager) runRestart(id string, rs *runningServer, message string, restart func(job *jobHandle) error) error {
+	job := m.newJob(JobTypeRestart, id)
+	rs.mu.Lock()
+	rs.queuedRestarts = append(rs.queuedRestarts, job)
+	rs.mu.Unlock()
+
+	release, err := m.acquireServerOperation(job.ctx, id, operationRestart)
+	rs.mu.Lock()
+	for i, queued := range rs.queuedRestarts {
+		if queued == job {
+			rs.queuedRestarts = append(rs.queuedRestarts[:i], rs.queuedRestarts[i+1:]...)
+			break
+		}
+	}
+	rs.mu.Unlock()
+	if err == nil && job.ctx.Err() != nil {
+		// Cancelled just as the lock was handed over.
+		release()
+		err = job.ctx.Err()
+	}
+	if err != nil {
+		job.finish(err)
+		return err
+	}
+	defer release()
+
+	job.start(message)
+	err = restart(job)
+	job.finish(err)
+	return err
+}
 
-	if err := m.StopServer(id); err != nil {
-		log.Printf("[%s] Scheduled restart - stop failed: %v", cfg.Name, err)
+func (m *Manager) executeRestart(id string, cfg *ServerConfig, reason string) {
+	m.mu.RLock()
+	rs := m.running[id]
+	m.mu.RUnlock()
+	if rs == nil {
 		return
 	}
 
-	time.Sleep(3 * time.Second)
+	m.runRestart(id, rs, "Restarting server", func(job *jobHandle) error {
+		log.Printf("[%s] Scheduled restart executing", cfg.Name)
+		m.setPersistedRestartAt(id, time.Time{}, "")
+		m.SendCommand(id, m.warningCommand(id, warningRestartNow, 0, reason))
+		time.Sleep(1 * time.Second)
 
-	if err := m.StartServer(id); err != nil {
-		log.Printf("[%s] Scheduled restart - start failed: %v", cfg.Name, err)
-	} else {
+		if err := m.StopServer(id); err != nil {
+			log.Printf("[%s] Scheduled restart - stop failed: %v", cfg.Name, err)
+			return fmt.Errorf("stop failed: %w", err)
+		}
+		job.progress(50, "Server stopped")
+
+		time.Sleep(3 * time.Second)
+
+		if err := m.StartServer(id); err != nil {
+			log.Printf("[%s] Scheduled restart - start failed: %v", cfg.Name, err)
+			return fmt.Errorf("start failed: %w", err)
+		}
 		log.Printf("[%s] Scheduled restart completed", cfg.Name)
-	}
+		job.progress(90, "Server started")
+		m.verifyRestart(job.ctx, id, verifyTriggerRestart, nil)
+		return nil
+	})
 }
 
-const maxLogBuffer = 2000
-const logTrimSize = 200
 const maxPingChecksPerCycle = 6
 const maxWorldRefreshPerCycle = 6
+
+// playerPositionRefresh is how old a player's last known position may get
+// before the next player list sync asks for it again.
+const playerPositionRefresh = 30 * time.Second
 const extensionCapabilityCacheTTL = 15 * time.Second
 const serverConfigPathSafetyErrorCode = "server_config_path_unsafe"
 const emptyListSuppressionThreshold = 5
 
 var ErrExtensionAlreadyInstalled = errors.New("extension already installed")
 
+var (
+	errServerNotRunning = errors.New("server is not running")
+	errNoStdinPipe      = errors.New("server has no stdin pipe")
+)
+
 type ConfigPathSafetyError struct {
 	ServerID string
 	Reason   string
@@ -280,6 +565,7 @@ var (
 	forgeTpsPattern     = regexp.MustCompile(`(?i)overall:\s*(?:tps[:=]\s*)?([0-9.]+)\s*tps\b|overall:.*\btps[:=]\s*([0-9.]+)`)
 	simpleTpsPattern    = regexp.MustCompile(`(?i)\bTPS[:=]\s*([0-9.]+)`)
 	dimensionPattern    = regexp.MustCompile(playerNamePattern + ` has the following entity data: "?((?:[a-z0-9_.-]+:)?[a-z0-9_./-]+)"?`)
+	positionPattern     = regexp.MustCompile(playerNamePattern + ` has the following entity data: \[(-?[0-9.]+(?:E-?[0-9]+)?)d, (-?[0-9.]+(?:E-?[0-9]+)?)d, (-?[0-9.]+(?:E-?[0-9]+)?)d\]`)
 	listPattern         = regexp.MustCompile(`There are (\d+) of a max of (\d+) players online:\s*(.*)`)
 	pingPattern1        = regexp.MustCompile(`(?i)ping of ` + playerNamePattern + ` (?:is|was) ([0-9]+)`)
 	pingPattern2        = regexp.MustCompile(`(?i)` + playerNamePattern + `'?s ping(?: is|:)? ([0-9]+)`)
@@ -293,9 +579,17 @@ type Manager struct {
 	configs            map[string]*ServerConfig
 	running            map[string]*runningServer
 	dataFile           string
-	settingsFile       string
+	store              panelStore
+	persister          *persistWriter
 	settingsMu         sync.RWMutex
 	settings           AppSettings
+	prefsMu            sync.Mutex
+	usersMu            sync.Mutex
+	moderationMu       sync.Mutex
+	backupTargetsMu    sync.Mutex
+	playerHistoryMu    sync.Mutex
+	playerHistory      map[string]*serverPlayerHistory
+	users              map[string]userAccount
 	baseDir            string
 	serversRoot        string
 	serversRootReal    string
@@ -303,15 +597,29 @@ type Manager struct {
 	backupsRootReal    string
 	importsRoot        string
 	quarantinedServers map[string]string
+	corruptConfigs     []CorruptServerEntry
 	importAnalyses     map[string]*ServerImportAnalysis
 	stopScheduler      chan struct{}
 	stopUsageSampler   chan struct{}
 	stopImportCleanup  chan struct{}
+	stopPanelBackup    chan struct{}
+	panelBackupDone    chan struct{}
+	stopMQTT           chan struct{}
+	stopStatusStream   chan struct{}
+	statusKick         chan struct{}
+	statusMu           sync.Mutex
+	statusSubs         map[*statusSubscriber]struct{}
+	statusLast         []ServerStatus
 	hostLogicalCPUs    int
 	hostTotalRAMBytes  uint64
 	usageMu            sync.RWMutex
 	systemUsage        SystemUsageSnapshot
 	javaResolver       *javaRequirementResolver
+	jobs               jobTracker
+	opLocksMu          sync.Mutex
+	opLocks            map[string]*serverOperationLock
+	diskUsageMu        sync.Mutex
+	diskUsage          map[string]DiskUsage
 	mu                 sync.RWMutex
 }
 
@@ -329,6 +637,7 @@ type UsageProcessSnapshot struct {
 	CPUPercent float64 `json:"cpuPercent"`
 	RAMBytes   uint64  `json:"ramBytes"`
 	RAMPercent float64 `json:"ramPercent"`
+	SwapBytes  uint64  `json:"swapBytes,omitempty"`
 }
 
 type UsageTotalsSnapshot struct {
@@ -343,11 +652,15 @@ type SystemUsageSnapshot struct {
 	Panel     UsageProcessSnapshot   `json:"panel"`
 	Servers   []UsageProcessSnapshot `json:"servers"`
 	Total     UsageTotalsSnapshot    `json:"total"`
+	// MemoryPressure is nil where procfs cannot be read.
+	MemoryPressure *MemoryPressure `json:"memoryPressure,omitempty"`
 }
 
 var hiddenServerRootArtifacts = map[string]struct{}{
 	".adpanel-extension-sources.json": {},
 	".console_history":                {},
+	deletingDirName:                   {},
+	refreshingDirName:                 {},
 }
 
 // sanitizeName converts a server name to a safe directory name
@@ -614,6 +927,14 @@ func (m *Manager) currentPollIntervals() pollIntervals {
 	}
 }
 
+func (m *Manager) currentRestartWarningMinutes() []int {
+	m.settingsMu.RLock()
+	cfg := m.settings
+	m.settingsMu.RUnlock()
+	applySettingsDefaults(&cfg)
+	return cfg.RestartWarningMinutes
+}
+
 func scheduleListRefreshLocked(rs *runningServer, delay time.Duration) {
 	when := time.Now().Add(delay)
 	if !rs.pendingListRefresh || rs.nextListRefreshAt.IsZero() || when.Before(rs.nextListRefreshAt) {
@@ -770,11 +1091,17 @@ func NewManager(baseDir string) (*Manager, error) {
 		backupsRootReal = filepath.Clean(resolved)
 	}
 
+	storageBackend := storageBackendFromEnv()
+	store, err := openPanelStore(dataDir, storageBackend)
+	if err != nil {
+		return nil, err
+	}
+
 	mgr := &Manager{
 		configs:            make(map[string]*ServerConfig),
 		running:            make(map[string]*runningServer),
 		dataFile:           filepath.Join(dataDir, "servers.json"),
-		settingsFile:       filepath.Join(dataDir, "settings.json"),
+		store:              store,
 		baseDir:            baseDir,
 		serversRoot:        serversRootAbs,
 		serversRootReal:    serversRootReal,
@@ -786,37 +1113,56 @@ func NewManager(baseDir string) (*Manager, error) {
 		stopScheduler:      make(chan struct{}),
 		stopUsageSampler:   make(chan struct{}),
 		stopImportCleanup:  make(chan struct{}),
+		stopPanelBackup:    make(chan struct{}),
+		panelBackupDone:    make(chan struct{}),
+		stopMQTT:           make(chan struct{}),
+		stopStatusStream:   make(chan struct{}),
+		statusKick:         make(chan struct{}, 1),
+		statusSubs:         make(map[*statusSubscriber]struct{}),
 		javaResolver:       newJavaRequirementResolver(),
 	}
 	log.Printf("Java runtimes detected: %v", mgr.javaResolver.availableMajors())
 	mgr.loadHostUsageMetadata()
 
 	if err := mgr.load(); err != nil {
+		store.Close()
 		return nil, err
 	}
 	mgr.quarantineUnsafeServerConfigs()
 	mgr.migrateBackupsToStableIDs()
 	mgr.migrateLegacyServerArtifacts()
 	if err := mgr.loadSettings(); err != nil {
+		store.Close()
+		return nil, err
+	}
+	if err := mgr.loadUsers(); err != nil {
+		store.Close()
 		return nil, err
 	}
+	// Startup migrations above were written synchronously; later changes go
+	// through the coalescing writer.
+	mgr.persister = newPersistWriter(storageBackend, store.Save)
 	if mgr.IsUsingDefaultLogin() {
 		log.Printf("Auth initialized with default credentials. Change them in System Settings before exposing the panel.")
 	}
 
-	for id := range mgr.configs {
-		mgr.running[id] = &runningServer{
+	for id, cfg := range mgr.configs {
+		rs := &runningServer{
 			status:      "Stopped",
 			logBuffer:   make([]ConsoleLogEntry, 0),
 			nextLogSeq:  1,
 			players:     make(map[string]*onlinePlayer),
 			pingBlocked: make(map[string]bool),
 		}
+		rs.logMaxLines, rs.logTrimLines = consoleBufferLimits(cfg)
+		mgr.running[id] = rs
 	}
 
+	reattached := mgr.reattachServers()
+
 	// Auto-start servers that have AutoStart enabled
 	for id, cfg := range mgr.configs {
-		if cfg.AutoStart {
+		if cfg.AutoStart && !reattached[id] {
 			go func(serverID, serverName string) {
 				time.Sleep(2 * time.Second)
 				log.Printf("Auto-starting server: %s", serverName)
@@ -833,6 +1179,12 @@ func NewManager(baseDir string) (*Manager, error) {
 	go mgr.runBackupScheduler()
 	go mgr.runUsageSampler()
 	go mgr.runImportAnalysisCleanup()
+	go mgr.runPanelDataBackups()
+	go mgr.runStatusBroadcaster()
+	go mgr.runDiskQuotaMonitor()
+	if cfg, ok := mqttConfigFromEnv(); ok {
+		go mgr.runMQTTPublisher(cfg)
+	}
 
 	return mgr, nil
 }
@@ -950,6 +1302,7 @@ func (m *Manager) refreshPlayerWorlds(id string, rs *runningServer, playerNames
 			continue
 		}
 		m.SendCommand(id, fmt.Sprintf("data get entity %s Dimension", name))
+		m.SendCommand(id, fmt.Sprintf("data get entity %s Pos", name))
 		time.Sleep(100 * time.Millisecond)
 	}
 }
@@ -999,29 +1352,47 @@ func (m *Manager) migrateLegacyServerArtifacts() {
 	}
 }
 
-// load reads servers.json into configs map
+// load reads the servers document into configs map
 func (m *Manager) load() error {
-	data, err := os.ReadFile(m.dataFile)
+	data, err := m.storage().Load(storeDocServers)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, os.ErrNotExist) {
 			return nil
 		}
 		return fmt.Errorf("failed to read data file: %w", err)
 	}
 
-	var configs []*ServerConfig
-	if err := json.Unmarshal(data, &configs); err != nil {
-		return fmt.Errorf("failed to parse data file: %w", err)
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		log.Printf("servers.json is corrupt (%v); attempting recovery from panel backups", err)
+		recovered, recoverErr := m.recoverDataFile(storeDocServers, validateServersJSON)
+		if recoverErr != nil {
+			return fmt.Errorf("failed to parse data file: %w (recovery failed: %v)", err, recoverErr)
+		}
+		entries = nil
+		if err := json.Unmarshal(recovered, &entries); err != nil {
+			return fmt.Errorf("failed to parse recovered data file: %w", err)
+		}
 	}
 
+	m.loadCorruptConfigsLocked()
+	configs, corrupt := decodeServerEntries(entries)
 	for _, cfg := range configs {
-		if cfg == nil {
-			continue
+		m.configs[cfg.ID] = cfg
+	}
+	if len(corrupt) > 0 {
+		for _, entry := range corrupt {
+			log.Printf("Skipping corrupt servers.json entry #%d: %s (recover via /api/servers/corrupt)", entry.Index, entry.Error)
 		}
-		if strings.TrimSpace(cfg.ID) == "" {
-			cfg.ID = uuid.New().String()[:8]
+		m.corruptConfigs = append(m.corruptConfigs, corrupt...)
+		// Keep the bad entries outside servers.json so the next persist does
+		// not silently drop them.
+		if err := m.persistCorruptConfigsLocked(); err != nil {
+			return fmt.Errorf("failed to preserve corrupt server entries: %w", err)
+		}
+		if err := m.persist(); err != nil {
+			return fmt.Errorf("failed to rewrite data file without corrupt entries: %w", err)
 		}
-		m.configs[cfg.ID] = cfg
 	}
 
 	if m.normalizeServerOrderLocked() {
@@ -1033,38 +1404,88 @@ func (m *Manager) load() error {
 	return nil
 }
 
-// persist writes all configs to servers.json atomically
+// persist snapshots all configs and schedules them to be written to the
+// panel store. Once NewManager has finished loading, the write happens in
+// the background; failures are logged, retried and reported through
+// GetPersistenceStatus.
 func (m *Manager) persist() error {
+	data, err := m.marshalConfigsLocked()
+	if err != nil {
+		return err
+	}
+
+	if m.persister != nil {
+		m.persister.schedule(storeDocServers, data)
+		return nil
+	}
+	if err := m.storage().Save(storeDocServers, data); err != nil {
+		return fmt.Errorf("failed to save servers: %w", err)
+	}
+
+	return nil
+}
+
+// marshalConfigsLocked encodes all configs as the servers document.
+// Callers hold m.mu.
+func (m *Manager) marshalConfigsLocked() ([]byte, error) {
 	configs := make([]*ServerConfig, 0, len(m.configs))
 	for _, cfg := range m.configs {
 		configs = append(configs, cfg)
 	}
-
 	data, err := json.MarshalIndent(configs, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to marshal configs: %w", err)
+		return nil, fmt.Errorf("failed to marshal configs: %w", err)
 	}
+	return data, nil
+}
 
-	tmpFile := m.dataFile + ".tmp"
-	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
+// CreateServer creates a new server with the given config
+func (m *Manager) CreateServer(name, serverType, version string, port int, minRAM, maxRAM string, maxPlayers int, flags string, alwaysPreTouch bool) (*ServerInfo, error) {
+	cfg, err := m.createServer(name, serverType, version, port, minRAM, maxRAM, maxPlayers, flags, alwaysPreTouch, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := os.Rename(tmpFile, m.dataFile); err != nil {
-		return fmt.Errorf("failed to rename temp file: %w", err)
-	}
+	// Launch async jar download
+	go m.installServerJar(cfg.ID, serverType, version)
 
-	return nil
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.serverInfo(cfg.ID), nil
 }
 
-// CreateServer creates a new server with the given config
-func (m *Manager) CreateServer(name, serverType, version string, port int, minRAM, maxRAM string, maxPlayers int, flags string, alwaysPreTouch bool) (*ServerInfo, error) {
+// createServer sets up a new server's directory and config in the
+// Installing state; the caller starts its install. prepare, when set, can
+// add to the config before it is saved.
+func (m *Manager) createServer(name, serverType, version string, port int, minRAM, maxRAM string, maxPlayers int, flags string, alwaysPreTouch bool, prepare func(*ServerConfig) error) (*ServerConfig, error) {
+	// Values left empty come from the panel's new-server defaults.
+	defaults := m.GetSettings()
+	if minRAM == "" {
+		minRAM = ramSettingToJVM(defaults.DefaultMinRAM)
+	}
+	if maxRAM == "" {
+		maxRAM = ramSettingToJVM(defaults.DefaultMaxRAM)
+	}
+	if maxPlayers <= 0 {
+		maxPlayers = defaults.DefaultMaxPlayers
+	}
+	if flags == "" {
+		flags = defaults.DefaultFlags
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if port == 0 {
+		free, err := m.freePortInRangeLocked(defaults.DefaultPortRangeStart, defaults.DefaultPortRangeEnd)
+		if err != nil {
+			return nil, err
+		}
+		port = free
+	}
 	for _, cfg := range m.configs {
 		if cfg.Port == port {
-			return nil, fmt.Errorf("port %d is already in use by server %s", port, cfg.Name)
+			return nil, errPortTaken(port, cfg.Name)
 		}
 	}
 
@@ -1118,6 +1539,16 @@ func (m *Manager) CreateServer(name, serverType, version string, port int, minRA
 		Flags:          flags,
 		AlwaysPreTouch: alwaysPreTouch,
 	}
+	if defaults.DefaultBackupSchedule != "" {
+		cfg.BackupSchedule = defaults.DefaultBackupSchedule
+		cfg.LastScheduledBackup = time.Now().UTC().Format(time.RFC3339)
+	}
+	if prepare != nil {
+		if err := prepare(cfg); err != nil {
+			os.RemoveAll(serverDir)
+			return nil, err
+		}
+	}
 
 	m.configs[id] = cfg
 	m.assignNewServerOrderLocked(cfg)
@@ -1132,11 +1563,32 @@ func (m *Manager) CreateServer(name, serverType, version string, port int, minRA
 	if err := m.persist(); err != nil {
 		return nil, fmt.Errorf("failed to persist config: %w", err)
 	}
+	return cfg, nil
+}
 
-	// Launch async jar download
-	go m.installServerJar(id, serverType, version)
+// ramSettingToJVM converts a RAM setting in GB, such as "0.5", to a JVM size
+// like "512M". Values that are not a number are passed through unchanged.
+func ramSettingToJVM(gb string) string {
+	value, err := parseRAMSetting(strings.TrimSpace(gb))
+	if err != nil {
+		return gb
+	}
+	return fmt.Sprintf("%dM", int(math.Round(value*1024)))
+}
 
-	return m.serverInfo(id), nil
+// freePortInRangeLocked returns the lowest port in [start, end] that no
+// server uses. Callers hold m.mu.
+func (m *Manager) freePortInRangeLocked(start, end int) (int, error) {
+	used := make(map[int]bool, len(m.configs))
+	for _, cfg := range m.configs {
+		used[cfg.Port] = true
+	}
+	for port := start; port <= end; port++ {
+		if !used[port] {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("no free port in the default range %d-%d", start, end)
 }
 
 // buildJVMFlags returns extra JVM arguments based on the flags preset
@@ -1166,6 +1618,17 @@ func buildJVMFlags(flags string, alwaysPreTouch bool) []string {
 			"-XX:G1HeapRegionSize=8M",
 			"-XX:G1ReservePercent=20",
 		}
+	case "zgc":
+		args = []string{
+			"--add-modules=jdk.incubator.vector",
+			"-XX:+UseZGC",
+			// Generational ZGC is opt-in on Java 21 and the default after it;
+			// older JVMs skip the flag instead of refusing to start.
+			"-XX:+IgnoreUnrecognizedVMOptions",
+			"-XX:+ZGenerational",
+			"-XX:+DisableExplicitGC",
+			"-XX:+PerfDisableSharedMem",
+		}
 	case "velocity":
 		args = []string{
 			"-XX:+UseG1GC",
@@ -1203,18 +1666,6 @@ func buildJVMFlags(flags string, alwaysPreTouch bool) []string {
 	return args
 }
 
-func writeManagedUserJVMArgs(path string, extraFlags []string) error {
-	content := "# JVM flags managed by Admin Panel\n"
-	for _, f := range extraFlags {
-		content += f + "\n"
-	}
-	existing, err := os.ReadFile(path)
-	if err == nil && string(existing) == content {
-		return nil
-	}
-	return os.WriteFile(path, []byte(content), 0644)
-}
-
 // StartServer starts the Minecraft process for the given server
 func (m *Manager) StartServer(id string) error {
 	m.mu.RLock()
@@ -1226,54 +1677,28 @@ func (m *Manager) StartServer(id string) error {
 		return err
 	}
 	if !rsOk {
-		return fmt.Errorf("server %s not found", id)
+		return errServerNotFound(id)
 	}
 
+	// Normally applied when the process exits; this catches updates staged
+	// before a panel restart.
+	m.applyStagedPluginUpdates(cfg)
+
 	rs.mu.Lock()
 	if rs.status == "Installing" {
 		rs.mu.Unlock()
 		return fmt.Errorf("server %s is still installing, please wait", id)
 	}
-	if rs.status == "Running" || rs.status == "Booting" {
+	if rs.status == "Running" || rs.status == "Booting" || rs.status == "Suspended" {
 		rs.mu.Unlock()
 		return fmt.Errorf("server %s is already %s", id, rs.status)
 	}
 
-	// Determine start command
-	var cmd *exec.Cmd
-	javaExec, javaRequired, javaSelected, javaErr := m.javaResolver.resolve(cfg.Type, cfg.Version)
-	if javaErr != nil {
+	cmd, err := m.serverLaunchCommand(cfg)
+	if err != nil {
 		rs.mu.Unlock()
-		return fmt.Errorf("cannot start server due to Java compatibility: %w", javaErr)
-	}
-	log.Printf("[%s] Java selected: required=%d selected=%d exec=%s", cfg.Name, javaRequired, javaSelected, javaExec)
-	if len(cfg.StartCommand) > 0 {
-		// For StartCommand-based servers (e.g. Forge/NeoForge), keep user_jvm_args.txt
-		// in sync with selected preset while avoiding unnecessary rewrites.
-		extraFlags := buildJVMFlags(cfg.Flags, cfg.AlwaysPreTouch)
-		jvmArgsPath := filepath.Join(cfg.Dir, "user_jvm_args.txt")
-		if err := writeManagedUserJVMArgs(jvmArgsPath, extraFlags); err != nil {
-			log.Printf("[%s] Failed to write user_jvm_args.txt: %v", cfg.Name, err)
-		}
-		cmd = exec.Command(cfg.StartCommand[0], cfg.StartCommand[1:]...)
-		javaHome := filepath.Clean(filepath.Join(filepath.Dir(javaExec), ".."))
-		cmd.Env = append(os.Environ(), "JAVA_HOME="+javaHome, "PATH="+filepath.Dir(javaExec)+":"+os.Getenv("PATH"))
-	} else {
-		jarPath := filepath.Join(cfg.Dir, cfg.JarFile)
-		if _, err := os.Stat(jarPath); os.IsNotExist(err) {
-			rs.mu.Unlock()
-			return fmt.Errorf("server.jar not found at %s - please place the server jar file in the server directory", jarPath)
-		}
-		jvmArgs := []string{
-			"-Xmx" + cfg.MaxRAM,
-			"-Xms" + cfg.MinRAM,
-		}
-		jvmArgs = append(jvmArgs, buildJVMFlags(cfg.Flags, cfg.AlwaysPreTouch)...)
-		jvmArgs = append(jvmArgs, "-jar", cfg.JarFile, "nogui")
-		cmd = exec.Command(javaExec, jvmArgs...)
+		return err
 	}
-	prepareServerProcessCommand(cmd)
-	cmd.Dir = cfg.Dir
 
 	stdinPipe, err := cmd.StdinPipe()
 	if err != nil {
@@ -1301,10 +1726,21 @@ func (m *Manager) StartServer(id string) error {
 	rs.cmd = cmd
 	rs.stdin = stdinPipe
 	rs.status = "Booting"
+	if !rs.crashRestarting {
+		rs.crashRestarts = 0
+	}
+	rs.crashRestarting = false
+	m.notifyStatusChange()
+	rs.bootStartedAt = time.Now()
+	rs.bootFailedAt = time.Time{}
+	rs.stopRequested = false
+	rs.failureReason = ""
+	rs.portConflict = nil
 	rs.pid = cmd.Process.Pid
 	rs.cpu = 0
 	rs.ram = 0
 	rs.ramBytes = 0
+	rs.swapBytes = 0
 	rs.logBuffer = make([]ConsoleLogEntry, 0)
 	rs.nextLogSeq = 1
 	rs.pendingListRefresh = false
@@ -1312,14 +1748,20 @@ func (m *Manager) StartServer(id string) error {
 	resetIdlePollingSafeguardLocked(rs)
 	rs.lastPlayersSync = time.Time{}
 	rs.lastTpsUpdate = time.Time{}
+	rs.pendingDimensionTicks = nil
+	rs.tickMetrics = nil
 	clearScheduledActionsLocked(rs)
 	rs.players = make(map[string]*onlinePlayer)
 	rs.stopMetrics = make(chan struct{})
 	rs.mu.Unlock()
 
 	m.refreshPingSupport(id)
+	m.recordServerProcess(id, cmd.Process.Pid)
 
-	log.Printf("[%s] Server starting (PID: %d) in %s", cfg.Name, rs.pid, cfg.Dir)
+	log.Printf("[%s] Server starting (PID: %d) in %s", cfg.Name, cmd.Process.Pid, cfg.Dir)
+	// Sessions still open from a run the panel did not see end get no
+	// playtime, since when the players left is unknown.
+	m.endPlayerSessions(id, time.Time{})
 
 	go m.scanOutput(id, rs, stdoutPipe)
 	go m.scanOutput(id, rs, stderrPipe)
@@ -1327,23 +1769,45 @@ func (m *Manager) StartServer(id string) error {
 	go func() {
 		err := cmd.Wait()
 		rs.mu.Lock()
-		if rs.status == "Running" || rs.status == "Booting" {
+		bootFailed := rs.status == "Booting" && !rs.stopRequested
+		var bootStartedAt time.Time
+		var bootConsole []string
+		var bootInstallError string
+		if bootFailed {
+			bootStartedAt = rs.bootStartedAt
+			bootInstallError = rs.installError
+			bootConsole = make([]string, 0, len(rs.logBuffer))
+			for _, entry := range rs.logBuffer {
+				bootConsole = append(bootConsole, entry.Line)
+			}
+		}
+		crashed := false
+		if rs.status == "Running" || rs.status == "Booting" || rs.status == "Suspended" {
 			if err != nil {
 				rs.status = "Crashed"
 				log.Printf("[%s] Server crashed: %v", cfg.Name, err)
+				crashed = !rs.stopRequested
+				if crashed {
+					recordCrashLocked(rs, time.Now())
+				}
 			} else {
 				rs.status = "Stopped"
 				log.Printf("[%s] Server stopped gracefully", cfg.Name)
 			}
+			m.notifyStatusChange()
 		}
 		rs.cpu = 0
 		rs.ram = 0
 		rs.ramBytes = 0
+		rs.swapBytes = 0
 		rs.tps = 0
 		rs.pid = 0
+		rs.suspendedAt = time.Time{}
 		rs.players = make(map[string]*onlinePlayer)
 		rs.lastPlayersSync = time.Time{}
 		rs.lastTpsUpdate = time.Time{}
+		rs.pendingDimensionTicks = nil
+		rs.tickMetrics = nil
 		resetIdlePollingSafeguardLocked(rs)
 
 		// Restore safe mode disabled directories
@@ -1358,6 +1822,10 @@ func (m *Manager) StartServer(id string) error {
 			}
 			rs.safeModeDisabled = nil
 		}
+		if len(rs.safeModeDisabledFiles) > 0 {
+			restoreSafeModeFiles(cfg.Name, rs.safeModeDisabledFiles)
+			rs.safeModeDisabledFiles = nil
+		}
 		rs.mu.Unlock()
 
 		select {
@@ -1365,6 +1833,17 @@ func (m *Manager) StartServer(id string) error {
 		default:
 			close(rs.stopMetrics)
 		}
+
+		m.forgetServerProcess(id, cmd.Process.Pid)
+		m.endPlayerSessions(id, time.Now())
+
+		if bootFailed {
+			m.recordBootFailure(cfg, rs, bootStartedAt, err, bootConsole, bootInstallError)
+		}
+		m.applyStagedPluginUpdates(cfg)
+		if crashed {
+			m.scheduleCrashRestart(id, rs)
+		}
 	}()
 
 	go m.collectMetrics(id, rs)
@@ -1372,6 +1851,49 @@ func (m *Manager) StartServer(id string) error {
 	return nil
 }
 
+// serverLaunchCommand builds the command that runs cfg's server, with
+// extraArgs passed to the server after its own arguments.
+func (m *Manager) serverLaunchCommand(cfg *ServerConfig, extraArgs ...string) (*exec.Cmd, error) {
+	var cmd *exec.Cmd
+	javaExec, javaRequired, javaSelected, javaErr := m.javaResolver.resolve(cfg.Type, cfg.Version)
+	if javaErr != nil {
+		return nil, fmt.Errorf("cannot start server due to Java compatibility: %w", javaErr)
+	}
+	log.Printf("[%s] Java selected: required=%d selected=%d exec=%s", cfg.Name, javaRequired, javaSelected, javaExec)
+	if len(cfg.StartCommand) > 0 {
+		// For StartCommand-based servers (e.g. Forge/NeoForge), keep user_jvm_args.txt
+		// in sync with the RAM settings and selected preset while avoiding
+		// unnecessary rewrites.
+		jvmArgsPath := filepath.Join(cfg.Dir, "user_jvm_args.txt")
+		if err := writeManagedUserJVMArgs(jvmArgsPath, managedJVMArgs(cfg)); err != nil {
+			log.Printf("[%s] Failed to write user_jvm_args.txt: %v", cfg.Name, err)
+		}
+		if err := validateUserJVMArgs(jvmArgsPath, cfg.StartCommand); err != nil {
+			return nil, err
+		}
+		args := append(append([]string(nil), cfg.StartCommand[1:]...), extraArgs...)
+		cmd = exec.Command(cfg.StartCommand[0], args...)
+		javaHome := filepath.Clean(filepath.Join(filepath.Dir(javaExec), ".."))
+		cmd.Env = append(os.Environ(), "JAVA_HOME="+javaHome, "PATH="+filepath.Dir(javaExec)+":"+os.Getenv("PATH"))
+	} else {
+		jarPath := filepath.Join(cfg.Dir, cfg.JarFile)
+		if _, err := os.Stat(jarPath); os.IsNotExist(err) {
+			return nil, fmt.Errorf("server.jar not found at %s - please place the server jar file in the server directory", jarPath)
+		}
+		jvmArgs := []string{
+			"-Xmx" + cfg.MaxRAM,
+			"-Xms" + cfg.MinRAM,
+		}
+		jvmArgs = append(jvmArgs, buildJVMFlags(cfg.Flags, cfg.AlwaysPreTouch)...)
+		jvmArgs = append(jvmArgs, "-jar", cfg.JarFile, "nogui")
+		jvmArgs = append(jvmArgs, extraArgs...)
+		cmd = exec.Command(javaExec, jvmArgs...)
+	}
+	prepareServerProcessCommand(cmd)
+	cmd.Dir = cfg.Dir
+	return cmd, nil
+}
+
 // StartServerSafeMode starts a server with plugins/mods disabled
 // by temporarily renaming the plugins and mods directories.
 // They are automatically restored when the server stops.
@@ -1385,7 +1907,7 @@ func (m *Manager) StartServerSafeMode(id string) error {
 		return err
 	}
 	if !rsOk {
-		return fmt.Errorf("server %s not found", id)
+		return errServerNotFound(id)
 	}
 
 	// Rename plugins and mods dirs before starting
@@ -1423,42 +1945,145 @@ func (m *Manager) StartServerSafeMode(id string) error {
 	return nil
 }
 
-// scanOutput reads from a pipe and broadcasts each line, tracking players
-func (m *Manager) scanOutput(id string, rs *runningServer, pipe io.Reader) {
-	scanner := bufio.NewScanner(pipe)
-	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+// ExtensionRef names one file in a server's extension directories.
+type ExtensionRef struct {
+	Directory string `json:"directory"`
+	FileName  string `json:"fileName"`
+}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		// Strip ANSI and Minecraft color codes for pattern matching
-		clean := ansiPattern.ReplaceAllString(line, "")
-		clean = mcColorPattern.ReplaceAllString(clean, "")
-		clean = strings.TrimRight(clean, " \r")
-		var worldRefreshNames []string
+// StartServerSafeModeSelective starts a server with only the chosen
+// plugins/mods/datapacks disabled, which makes it practical to bisect a bad
+// jar. Each file is renamed to .disabled for this boot and renamed back when
+// the server stops.
+func (m *Manager) StartServerSafeModeSelective(id string, disable []ExtensionRef) error {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	rs, rsOk := m.running[id]
+	m.mu.RUnlock()
 
-		rs.mu.Lock()
-		if strings.Contains(clean, "Done (") {
-			isReadyLine := strings.Contains(clean, "! For help,") || strings.Contains(clean, ")!")
-			if isReadyLine {
-				rs.status = "Running"
-				// Run one list scan shortly after boot to hydrate player list state.
-				scheduleListRefreshLocked(rs, 2*time.Second)
-				cfg := m.configs[id]
-				if cfg != nil {
-					log.Printf("[%s] Server is now running", cfg.Name)
-				}
-			}
-		}
+	if err != nil {
+		return err
+	}
+	if !rsOk {
+		return errServerNotFound(id)
+	}
+	if len(disable) == 0 {
+		return fmt.Errorf("select at least one file to disable")
+	}
 
-		if matches := joinPattern.FindStringSubmatch(clean); len(matches) >= 3 {
-			playerName := matches[1]
-			playerIP := matches[2]
-			rs.players[playerName] = &onlinePlayer{
-				Name:     playerName,
-				IP:       playerIP,
-				Ping:     -1,
-				JoinedAt: time.Now(),
-			}
+	paths := make([]string, 0, len(disable))
+	seen := make(map[string]bool, len(disable))
+	for _, ref := range disable {
+		dirName, dirPath, err := resolveExtensionDir(cfg, ref.Directory)
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(strings.ToLower(ref.FileName), ".disabled") {
+			return fmt.Errorf("%s is already disabled", ref.FileName)
+		}
+		if !extensionFileAllowed(dirName, ref.FileName) {
+			return fmt.Errorf("%s is not a %s entry", ref.FileName, dirName)
+		}
+		path, err := SafePath(dirPath, ref.FileName)
+		if err != nil {
+			return err
+		}
+		if seen[path] {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("%s not found in %s", ref.FileName, dirName)
+		}
+		if _, err := os.Stat(path + ".disabled"); err == nil {
+			return fmt.Errorf("%s.disabled already exists in %s", ref.FileName, dirName)
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+
+	var disabledFiles []string
+	for _, path := range paths {
+		if err := os.Rename(path, path+".disabled"); err != nil {
+			restoreSafeModeFiles(cfg.Name, disabledFiles)
+			return fmt.Errorf("failed to disable %s for safe mode: %w", filepath.Base(path), err)
+		}
+		disabledFiles = append(disabledFiles, path)
+	}
+	log.Printf("[%s] Safe mode: disabled %d selected file(s)", cfg.Name, len(disabledFiles))
+
+	if err := m.StartServer(id); err != nil {
+		restoreSafeModeFiles(cfg.Name, disabledFiles)
+		return err
+	}
+
+	rs.mu.Lock()
+	rs.safeModeDisabledFiles = disabledFiles
+	rs.mu.Unlock()
+
+	return nil
+}
+
+// restoreSafeModeFiles renames files disabled by selective safe mode back.
+func restoreSafeModeFiles(serverName string, paths []string) {
+	for _, path := range paths {
+		if err := os.Rename(path+".disabled", path); err != nil {
+			log.Printf("[%s] Failed to restore %s from safe mode: %v", serverName, filepath.Base(path), err)
+		} else {
+			log.Printf("[%s] Restored %s from safe mode", serverName, filepath.Base(path))
+		}
+	}
+}
+
+// scanOutput reads from a pipe and broadcasts each line, tracking players
+func (m *Manager) scanOutput(id string, rs *runningServer, pipe io.Reader) {
+	scanner := bufio.NewScanner(pipe)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Strip ANSI and Minecraft color codes for pattern matching
+		clean := ansiPattern.ReplaceAllString(line, "")
+		clean = mcColorPattern.ReplaceAllString(clean, "")
+		clean = strings.TrimRight(clean, " \r")
+		var worldRefreshNames []string
+		var joinedPlayer, leftPlayer string
+		var onlineAfterJoin int
+
+		rs.mu.Lock()
+		if rs.status == "Booting" && rs.failureReason == "" && portInUsePattern.MatchString(clean) {
+			rs.failureReason = FailureReasonPortInUse
+			if cfg := m.configs[id]; cfg != nil {
+				go m.recordPortConflict(id, rs, cfg.Port)
+			}
+		}
+		if strings.Contains(clean, "Done (") {
+			isReadyLine := strings.Contains(clean, "! For help,") || strings.Contains(clean, ")!")
+			if isReadyLine {
+				rs.status = "Running"
+				m.notifyStatusChange()
+				// Run one list scan shortly after boot to hydrate player list state.
+				scheduleListRefreshLocked(rs, 2*time.Second)
+				cfg := m.configs[id]
+				if cfg != nil {
+					log.Printf("[%s] Server is now running", cfg.Name)
+				}
+				go m.resumePersistedRestart(id)
+				go m.runReadyCommands(id, rs)
+				go m.runJoinCheckAfterBoot(id)
+			}
+		}
+
+		if matches := joinPattern.FindStringSubmatch(clean); len(matches) >= 3 {
+			playerName := matches[1]
+			playerIP := matches[2]
+			rs.players[playerName] = &onlinePlayer{
+				Name:     playerName,
+				IP:       playerIP,
+				Ping:     -1,
+				JoinedAt: time.Now(),
+			}
+			joinedPlayer = playerName
+			onlineAfterJoin = len(rs.players)
 			rs.lastPlayersSync = time.Now()
 			resetIdlePollingSafeguardLocked(rs)
 			delete(rs.pingBlocked, playerName)
@@ -1469,6 +2094,7 @@ func (m *Manager) scanOutput(id string, rs *runningServer, pipe io.Reader) {
 		if matches := leavePattern.FindStringSubmatch(clean); len(matches) >= 2 {
 			playerName := matches[1]
 			delete(rs.players, playerName)
+			leftPlayer = playerName
 			delete(rs.pingBlocked, playerName)
 			rs.lastPlayersSync = time.Now()
 			resetIdlePollingSafeguardLocked(rs)
@@ -1491,6 +2117,14 @@ func (m *Manager) scanOutput(id string, rs *runningServer, pipe io.Reader) {
 				suppressLine = true
 			}
 		}
+		// Forge and NeoForge report each dimension before the Overall line.
+		dimensionTick, isDimensionTick := parseDimensionTick(clean)
+		if isDimensionTick {
+			addDimensionTickLocked(rs, dimensionTick)
+			if internalCmdRecent {
+				suppressLine = true
+			}
+		}
 		if matches := forgeTpsPattern.FindStringSubmatch(clean); len(matches) >= 3 {
 			tpsText := matches[1]
 			if tpsText == "" {
@@ -1500,11 +2134,12 @@ func (m *Manager) scanOutput(id string, rs *runningServer, pipe io.Reader) {
 				rs.tps = tpsVal
 				rs.lastTpsUpdate = time.Now()
 			}
+			publishTickMetricsLocked(rs, clean, time.Now())
 			if internalCmdRecent {
 				suppressLine = true
 			}
 		}
-		if matches := simpleTpsPattern.FindStringSubmatch(clean); len(matches) >= 2 {
+		if matches := simpleTpsPattern.FindStringSubmatch(clean); len(matches) >= 2 && !isDimensionTick {
 			if tpsVal, err := strconv.ParseFloat(matches[1], 64); err == nil {
 				rs.tps = tpsVal
 				rs.lastTpsUpdate = time.Now()
@@ -1525,6 +2160,17 @@ func (m *Manager) scanOutput(id string, rs *runningServer, pipe io.Reader) {
 				suppressLine = true
 			}
 		}
+		if matches := positionPattern.FindStringSubmatch(clean); len(matches) >= 5 {
+			if p, ok := rs.players[matches[1]]; ok {
+				if pos, ok := parsePlayerPosition(matches[2:5]); ok {
+					p.Position = pos
+					p.PositionAt = time.Now()
+				}
+			}
+			if playerCmdRecent {
+				suppressLine = true
+			}
+		}
 
 		// Parse list response to verify online players
 		if matches := listPattern.FindStringSubmatch(clean); matches != nil {
@@ -1579,7 +2225,7 @@ func (m *Manager) scanOutput(id string, rs *runningServer, pipe io.Reader) {
 						worldRefreshNames = append(worldRefreshNames, name)
 						continue
 					}
-					if player.World == "" || player.LastWorldAt.IsZero() || now.Sub(player.LastWorldAt) >= 2*time.Minute {
+					if player.World == "" || player.LastWorldAt.IsZero() || now.Sub(player.LastWorldAt) >= 2*time.Minute || now.Sub(player.PositionAt) >= playerPositionRefresh {
 						worldRefreshNames = append(worldRefreshNames, name)
 					}
 				}
@@ -1657,6 +2303,12 @@ func (m *Manager) scanOutput(id string, rs *runningServer, pipe io.Reader) {
 		if len(worldRefreshNames) > 0 {
 			go m.refreshPlayerWorlds(id, rs, worldRefreshNames)
 		}
+		if joinedPlayer != "" {
+			m.recordPlayerJoin(id, joinedPlayer, onlineAfterJoin, time.Now())
+		}
+		if leftPlayer != "" {
+			m.recordPlayerLeave(id, leftPlayer, time.Now())
+		}
 
 		entry := m.appendLog(rs, line)
 		if !suppressLine {
@@ -1840,27 +2492,52 @@ func (m *Manager) StopServer(id string) error {
 		return err
 	}
 	if !ok {
-		return fmt.Errorf("server %s not found", id)
+		return errServerNotFound(id)
 	}
 
 	rs.mu.Lock()
-	if rs.status != "Running" && rs.status != "Booting" {
-		rs.mu.Unlock()
-		return fmt.Errorf("server %s is not running (status: %s)", id, rs.status)
+	status := rs.status
+	// A suspended server has to run again to shut down cleanly.
+	if status == "Suspended" {
+		if err := resumeSuspendedLocked(rs); err != nil {
+			log.Printf("[%s] Failed to resume suspended server before stopping: %v", cfg.Name, err)
+		}
+		status = rs.status
+	}
+	if status == "Running" || status == "Booting" {
+		rs.stopRequested = true
+	}
+	cmd := rs.cmd
+	pid := rs.pid
+	reattached := rs.reattached
+	rs.mu.Unlock()
+	if status != "Running" && status != "Booting" {
+		return errServerNotRunningStatus(id, status)
+	}
+	// A server reattached after a panel restart has no child handle or
+	// stdin; it is stopped over RCON, or with SIGTERM as a last resort.
+	var startedAt int64
+	if reattached {
+		startedAt = processStartTime(pid)
 	}
 
-	if rs.stdin != nil {
-		_, err := io.WriteString(rs.stdin, "stop\n")
-		if err != nil {
-			log.Printf("[%s] Failed to send stop command: %v", cfg.Name, err)
+	if err := rs.writeStdin("stop"); errors.Is(err, errNoStdinPipe) && reattached {
+		if _, rconErr := m.rconCommand(id, "stop"); rconErr != nil {
+			log.Printf("[%s] Failed to send stop command over RCON (%v), sending SIGTERM", cfg.Name, rconErr)
+			if err := terminateServerProcess(pid); err != nil {
+				log.Printf("[%s] Failed to signal server process (pid=%d): %v", cfg.Name, pid, err)
+			}
 		}
+	} else if err != nil && !errors.Is(err, errNoStdinPipe) {
+		log.Printf("[%s] Failed to send stop command: %v", cfg.Name, err)
 	}
-	rs.mu.Unlock()
 
 	done := make(chan struct{})
 	go func() {
-		if rs.cmd != nil && rs.cmd.Process != nil {
-			rs.cmd.Process.Wait()
+		if reattached {
+			waitForProcessExit(pid, startedAt)
+		} else if cmd != nil && cmd.Process != nil {
+			cmd.Process.Wait()
 		}
 		close(done)
 	}()
@@ -1870,14 +2547,19 @@ func (m *Manager) StopServer(id string) error {
 		log.Printf("[%s] Server stopped", cfg.Name)
 	case <-time.After(30 * time.Second):
 		log.Printf("[%s] Stop timeout, killing process", cfg.Name)
-		if rs.cmd != nil && rs.cmd.Process != nil {
-			rs.cmd.Process.Kill()
+		if reattached {
+			killServerProcessTree(pid)
+		} else if cmd != nil && cmd.Process != nil {
+			cmd.Process.Kill()
 		}
 	}
 
 	rs.mu.Lock()
 	resetStoppedRuntimeStateLocked(rs)
 	rs.mu.Unlock()
+	if pid != 0 {
+		m.forgetServerProcess(id, pid)
+	}
 
 	return nil
 }
@@ -1893,21 +2575,22 @@ func (m *Manager) KillServer(id string) error {
 		return err
 	}
 	if !ok {
-		return fmt.Errorf("server %s not found", id)
+		return errServerNotFound(id)
 	}
 
 	rs.mu.Lock()
-	if rs.status != "Running" && rs.status != "Booting" {
+	if rs.status != "Running" && rs.status != "Booting" && rs.status != "Suspended" {
 		rs.mu.Unlock()
-		return fmt.Errorf("server %s is not running (status: %s)", id, rs.status)
+		return errServerNotRunningStatus(id, rs.status)
 	}
-	cmd := rs.cmd
+	rs.stopRequested = true
+	pid := serverProcessPIDLocked(rs)
 	stopMetrics := rs.stopMetrics
 	rs.mu.Unlock()
 
-	if cmd != nil && cmd.Process != nil {
-		if err := killServerProcessTree(cmd.Process.Pid); err != nil {
-			log.Printf("[%s] Failed to kill server process tree (pid=%d): %v", cfg.Name, cmd.Process.Pid, err)
+	if pid != 0 {
+		if err := killServerProcessTree(pid); err != nil {
+			log.Printf("[%s] Failed to kill server process tree (pid=%d): %v", cfg.Name, pid, err)
 			return fmt.Errorf("failed to kill server process")
 		}
 	}
@@ -1923,38 +2606,54 @@ func (m *Manager) KillServer(id string) error {
 	rs.mu.Lock()
 	resetStoppedRuntimeStateLocked(rs)
 	rs.mu.Unlock()
+	if pid != 0 {
+		m.forgetServerProcess(id, pid)
+	}
 
 	log.Printf("[%s] Server killed forcefully", cfg.Name)
 	return nil
 }
 
-// SendCommand writes a command to the server's stdin
+// SendCommand writes a command to the server's stdin, falling back to RCON
+// when stdin is unavailable and the server has RCON configured.
 func (m *Manager) SendCommand(id, command string) error {
 	m.mu.RLock()
-	if _, err := m.serverConfigForOperationLocked(id); err != nil {
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
 		m.mu.RUnlock()
 		return err
 	}
+	hasRCON := cfg.RCON != nil
 	rs, ok := m.running[id]
 	m.mu.RUnlock()
 
 	if !ok {
-		return fmt.Errorf("server %s not found", id)
+		return errServerNotFound(id)
 	}
 
-	rs.mu.RLock()
-	defer rs.mu.RUnlock()
-
-	if rs.status != "Running" && rs.status != "Booting" {
-		return fmt.Errorf("server %s is not running", id)
+	err = rs.writeStdin(command)
+	// Without stdin, for example when the server outlived a panel restart,
+	// RCON still reaches it. Its reply goes into the console history.
+	if hasRCON && (errors.Is(err, errServerNotRunning) || errors.Is(err, errNoStdinPipe)) {
+		reply, rconErr := m.rconCommand(id, command)
+		if rconErr != nil {
+			return fmt.Errorf("server %s is not reachable over stdin or RCON: %w", id, rconErr)
+		}
+		for _, line := range strings.Split(strings.TrimRight(reply, "\n"), "\n") {
+			if line != "" {
+				m.broadcastLog(rs, m.appendLog(rs, "[RCON] "+line))
+			}
+		}
+		return nil
 	}
-
-	if rs.stdin == nil {
+	switch {
+	case errors.Is(err, errServerNotRunning):
+		return fmt.Errorf("server %s is not running", id)
+	case errors.Is(err, errNoStdinPipe):
 		return fmt.Errorf("server %s has no stdin pipe", id)
+	default:
+		return err
 	}
-
-	_, err := io.WriteString(rs.stdin, command+"\n")
-	return err
 }
 
 // RecordConsoleCommand appends and broadcasts a panel-issued command so it appears in live console history.
@@ -1964,7 +2663,7 @@ func (m *Manager) RecordConsoleCommand(id, command string) error {
 	m.mu.RUnlock()
 
 	if !ok {
-		return fmt.Errorf("server %s not found", id)
+		return errServerNotFound(id)
 	}
 
 	trimmed := strings.TrimSpace(command)
@@ -2006,7 +2705,7 @@ func (m *Manager) SubscribeLogsWithSnapshot(id string, lastSeq uint64) ([]Consol
 		return []ConsoleLogEntry{}, false, ch, func() {}
 	}
 
-	ch := make(chan ConsoleLogEntry, 1000)
+	ch := make(chan ConsoleLogEntry, logSubscriberBuffer)
 
 	rs.mu.Lock()
 	snapshot := make([]ConsoleLogEntry, 0, len(rs.logBuffer))
@@ -2032,14 +2731,14 @@ func (m *Manager) SubscribeLogsWithSnapshot(id string, lastSeq uint64) ([]Consol
 		// Empty current buffer but client had history: treat as stream reset so UI can clear old logs.
 		reset = true
 	}
-	rs.subscribers = append(rs.subscribers, ch)
+	rs.subscribers = append(rs.subscribers, &logSubscriber{ch: ch})
 	rs.mu.Unlock()
 
 	unsubscribe := func() {
 		rs.mu.Lock()
 		defer rs.mu.Unlock()
 		for i, sub := range rs.subscribers {
-			if sub == ch {
+			if sub.ch == ch {
 				rs.subscribers = append(rs.subscribers[:i], rs.subscribers[i+1:]...)
 				break
 			}
@@ -2063,25 +2762,10 @@ func (m *Manager) appendLog(rs *runningServer, line string) ConsoleLogEntry {
 	}
 	rs.nextLogSeq++
 	rs.logBuffer = append(rs.logBuffer, entry)
-	if maxLogBuffer > 0 && len(rs.logBuffer) > maxLogBuffer {
-		rs.logBuffer = rs.logBuffer[logTrimSize:]
-	}
+	rs.trimLogBufferLocked()
 	return entry
 }
 
-// broadcastLog sends a line to all active subscribers
-func (m *Manager) broadcastLog(rs *runningServer, entry ConsoleLogEntry) {
-	rs.mu.RLock()
-	defer rs.mu.RUnlock()
-
-	for _, ch := range rs.subscribers {
-		select {
-		case ch <- entry:
-		default:
-		}
-	}
-}
-
 // GetStatus returns the current status and metrics for a server
 func (m *Manager) GetStatus(id string) (*ServerInfo, error) {
 	m.mu.RLock()
@@ -2094,11 +2778,32 @@ func (m *Manager) GetStatus(id string) (*ServerInfo, error) {
 	return m.serverInfo(id), nil
 }
 
-// ListServers returns all servers with their current status and metrics
+// ListServers returns all servers with their current status and metrics.
+// Configs are copied under the Manager lock; status and metrics are read
+// afterwards so a slow server never holds up the listing.
 func (m *Manager) ListServers() []ServerInfo {
+	type listEntry struct {
+		cfg ServerConfig
+		rs  *runningServer
+	}
+
 	m.mu.RLock()
-	defer m.mu.RUnlock()
+	ids := m.orderedServerIDsLocked()
+	entries := make([]listEntry, 0, len(ids))
+	for _, id := range ids {
+		entries = append(entries, listEntry{cfg: *m.configs[id], rs: m.running[id]})
+	}
+	m.mu.RUnlock()
+
+	servers := make([]ServerInfo, 0, len(entries))
+	for i := range entries {
+		servers = append(servers, *m.buildServerInfo(&entries[i].cfg, entries[i].rs))
+	}
+	return servers
+}
 
+// orderedServerIDsLocked returns server IDs in display order (caller must hold m.mu).
+func (m *Manager) orderedServerIDsLocked() []string {
 	ids := make([]string, 0, len(m.configs))
 	for id := range m.configs {
 		ids = append(ids, id)
@@ -2116,49 +2821,112 @@ func (m *Manager) ListServers() []ServerInfo {
 		}
 		return left.Order < right.Order
 	})
-
-	servers := make([]ServerInfo, 0, len(ids))
-	for _, id := range ids {
-		servers = append(servers, *m.serverInfo(id))
-	}
-	return servers
+	return ids
 }
 
 // serverInfo builds a ServerInfo from config and running state (caller must hold m.mu.RLock)
 func (m *Manager) serverInfo(id string) *ServerInfo {
-	cfg := m.configs[id]
-	rs := m.running[id]
+	return m.buildServerInfo(m.configs[id], m.running[id])
+}
 
+// buildServerInfo does not touch m.configs or m.running, so it is safe to
+// call on a config copy without holding m.mu.
+func (m *Manager) buildServerInfo(cfg *ServerConfig, rs *runningServer) *ServerInfo {
+	id := cfg.ID
 	info := &ServerInfo{
-		ID:             cfg.ID,
-		Name:           cfg.Name,
-		Type:           cfg.Type,
-		Version:        cfg.Version,
-		Port:           cfg.Port,
-		MaxRAM:         cfg.MaxRAM,
-		MinRAM:         cfg.MinRAM,
-		MaxPlayers:     cfg.MaxPlayers,
-		AutoStart:      cfg.AutoStart,
-		Flags:          cfg.Flags,
-		AlwaysPreTouch: cfg.AlwaysPreTouch,
-		Status:         "Stopped",
+		ID:                  cfg.ID,
+		Name:                cfg.Name,
+		Type:                cfg.Type,
+		Version:             cfg.Version,
+		Port:                cfg.Port,
+		MaxRAM:              cfg.MaxRAM,
+		MinRAM:              cfg.MinRAM,
+		MaxPlayers:          cfg.MaxPlayers,
+		AutoStart:           cfg.AutoStart,
+		ShowOnStatusPage:    cfg.ShowOnStatusPage,
+		Flags:               cfg.Flags,
+		AlwaysPreTouch:      cfg.AlwaysPreTouch,
+		Status:              "Stopped",
+		PluginUpdateChannel: normalizedPluginUpdateChannel(cfg.PluginUpdateChannel),
+		ReadyCommands:       append([]string(nil), cfg.ReadyCommands...),
+		Groups:              append([]string(nil), cfg.Groups...),
+	}
+	if cfg.WarningMessages != nil {
+		msgs := *cfg.WarningMessages
+		info.WarningMessages = &msgs
+	}
+	if cfg.RCON != nil {
+		info.RCONPort = cfg.RCON.Port
+	}
+	if cfg.JoinCheck != nil && cfg.JoinCheck.Last != nil {
+		last := *cfg.JoinCheck.Last
+		info.JoinCheck = &last
+	}
+	if cfg.RestartVerification != nil && cfg.RestartVerification.Last != nil {
+		last := *cfg.RestartVerification.Last
+		info.RestartVerification = &last
+	}
+	if cfg.RestartOnCrash != nil {
+		settings := *cfg.RestartOnCrash
+		info.RestartOnCrash = &settings
+	}
+	if cfg.ConsoleBuffer != nil {
+		settings := *cfg.ConsoleBuffer
+		info.ConsoleBuffer = &settings
+	}
+	if cfg.DiskQuota != nil {
+		quota := *cfg.DiskQuota
+		if usage, ok := m.rememberedDiskUsage(id); ok {
+			quota.Usage = &usage
+		}
+		info.DiskQuota = &quota
+	}
+	if cfg.Modpack != nil {
+		modpack := *cfg.Modpack
+		info.Modpack = &modpack
 	}
 	if strings.EqualFold(cfg.Type, "fabric") {
 		info.FabricTpsAvailable = hasFabricTps(filepath.Join(cfg.Dir, "mods"))
 	}
+	if busyWith, since, queued := m.serverOperationState(id); busyWith != "" {
+		info.BusyWith = busyWith
+		info.BusySince = since.UTC().Format(time.RFC3339)
+		info.QueuedOperations = queued
+	}
 
 	if rs != nil {
-		rs.mu.RLock()
-		info.Status = rs.status
-		info.CPU = rs.cpu
-		info.RAM = rs.ram
-		info.CPUExact = rs.cpu
-		info.RAMBytes = rs.ramBytes
-		info.RAMMB = bytesToMB(rs.ramBytes)
-		info.TPS = rs.tps
-		info.InstallError = rs.installError
-		lastTpsUpdate := rs.lastTpsUpdate
-		rs.mu.RUnlock()
+		runtime := rs.displayRuntime()
+		info.Status = runtime.status
+		info.CPU = runtime.cpu
+		info.RAM = runtime.ram
+		info.CPUExact = runtime.cpu
+		info.RAMBytes = runtime.ramBytes
+		info.RAMMB = bytesToMB(runtime.ramBytes)
+		info.SwapBytes = runtime.swapBytes
+		info.MemoryWarning = serverMemoryWarning(m.currentMemoryPressure(), runtime.status, runtime.swapBytes)
+		info.TPS = runtime.tps
+		info.TickMetrics = runtime.tickMetrics
+		info.InstallError = runtime.installError
+		if !runtime.bootFailedAt.IsZero() {
+			info.BootFailedAt = runtime.bootFailedAt.UTC().Format(time.RFC3339)
+		}
+		info.FailureReason = runtime.failureReason
+		info.PortConflict = runtime.portConflict
+		if !runtime.restartAt.IsZero() {
+			info.RestartAt = runtime.restartAt.UTC().Format(time.RFC3339)
+		}
+		info.CrashCount = runtime.crashCount
+		if !runtime.lastCrashAt.IsZero() {
+			info.LastCrashAt = runtime.lastCrashAt.UTC().Format(time.RFC3339)
+		}
+		info.CrashRestarts = runtime.crashRestarts
+		if !runtime.suspendedAt.IsZero() {
+			info.SuspendedAt = runtime.suspendedAt.UTC().Format(time.RFC3339)
+		}
+		if !runtime.crashRestartAt.IsZero() {
+			info.CrashRestartAt = runtime.crashRestartAt.UTC().Format(time.RFC3339)
+		}
+		lastTpsUpdate := runtime.lastTpsUpdate
 
 		_, tpsSupported := tpsCommandForType(cfg.Type)
 		if isProxyType(cfg.Type) {
@@ -2280,48 +3048,41 @@ func updateVelocityToml(path string, maxPlayers int, port int) error {
 
 // UpdateSettings updates RAM, MaxPlayers, and Port for a server (only when stopped).
 // For Velocity proxies, port/max players are persisted in velocity.toml.
+// The properties file is rewritten without holding the Manager lock; the
+// checks are repeated before the config change is applied.
 func (m *Manager) UpdateSettings(id, minRAM, maxRAM string, maxPlayers int, port int) (*ServerInfo, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	if port < 1024 || port > 65535 {
+		return nil, errPortOutOfRange()
+	}
 
-	cfg, err := m.serverConfigForOperationLocked(id)
+	m.mu.RLock()
+	cfg, err := m.checkSettingsUpdateLocked(id, port)
 	if err != nil {
+		m.mu.RUnlock()
 		return nil, err
 	}
+	serverType := cfg.Type
+	serverDir := cfg.Dir
+	m.mu.RUnlock()
 
-	rs := m.running[id]
-	if rs != nil {
-		rs.mu.RLock()
-		status := rs.status
-		rs.mu.RUnlock()
-		if status == "Running" || status == "Booting" {
-			return nil, fmt.Errorf("cannot change settings while server is running")
-		}
-	}
-
-	if port < 1024 || port > 65535 {
-		return nil, fmt.Errorf("port must be between 1024 and 65535")
-	}
-	if port != cfg.Port {
-		for _, other := range m.configs {
-			if other.ID != cfg.ID && other.Port == port {
-				return nil, fmt.Errorf("port %d is already in use by server %s", port, other.Name)
-			}
-		}
-	}
-
-	if strings.EqualFold(cfg.Type, "velocity") {
-		velocityPath := filepath.Join(cfg.Dir, "velocity.toml")
+	if strings.EqualFold(serverType, "velocity") {
+		velocityPath := filepath.Join(serverDir, "velocity.toml")
 		if err := updateVelocityToml(velocityPath, maxPlayers, port); err != nil {
 			return nil, fmt.Errorf("failed to update velocity.toml: %w", err)
 		}
 	} else {
-		propsPath := filepath.Join(cfg.Dir, "server.properties")
+		propsPath := filepath.Join(serverDir, "server.properties")
 		if err := updateJavaServerProperties(propsPath, maxPlayers, port); err != nil {
 			return nil, fmt.Errorf("failed to update server.properties: %w", err)
 		}
 	}
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg, err = m.checkSettingsUpdateLocked(id, port)
+	if err != nil {
+		return nil, err
+	}
 	cfg.MinRAM = minRAM
 	cfg.MaxRAM = maxRAM
 	cfg.MaxPlayers = maxPlayers
@@ -2333,8 +3094,36 @@ func (m *Manager) UpdateSettings(id, minRAM, maxRAM string, maxPlayers int, port
 	return m.serverInfo(id), nil
 }
 
+// checkSettingsUpdateLocked validates that a server's settings may change
+// to the given port (caller must hold m.mu).
+func (m *Manager) checkSettingsUpdateLocked(id string, port int) (*ServerConfig, error) {
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if rs := m.running[id]; rs != nil {
+		rs.mu.RLock()
+		status := rs.status
+		rs.mu.RUnlock()
+		if status == "Running" || status == "Booting" || status == "Suspended" {
+			return nil, fmt.Errorf("cannot change settings while server is running")
+		}
+	}
+
+	if port != cfg.Port {
+		for _, other := range m.configs {
+			if other.ID != cfg.ID && other.Port == port {
+				return nil, errPortTaken(port, other.Name)
+			}
+		}
+	}
+	return cfg, nil
+}
+
 // UpdateVersion updates a server to a newer server jar version (server must be stopped).
-func (m *Manager) UpdateVersion(id, version string) (*ServerInfo, error) {
+// With upgradeWorld the world upgrade job runs once the new jar is installed.
+func (m *Manager) UpdateVersion(id, version string, upgradeWorld bool) (*ServerInfo, error) {
 	version = strings.TrimSpace(version)
 	if version == "" {
 		return nil, fmt.Errorf("version is required")
@@ -2349,13 +3138,13 @@ func (m *Manager) UpdateVersion(id, version string) (*ServerInfo, error) {
 	}
 	if !rsOk {
 		m.mu.Unlock()
-		return nil, fmt.Errorf("server %s not found", id)
+		return nil, errServerNotFound(id)
 	}
 
 	rs.mu.RLock()
 	status := rs.status
 	rs.mu.RUnlock()
-	if status == "Running" {
+	if status == "Running" || status == "Suspended" {
 		m.mu.Unlock()
 		return nil, fmt.Errorf("Can't update while server is running.")
 	}
@@ -2372,7 +3161,21 @@ func (m *Manager) UpdateVersion(id, version string) (*ServerInfo, error) {
 	serverType := cfg.Type
 	m.mu.Unlock()
 
-	go m.installServerJar(id, serverType, version)
+	go func() {
+		m.installServerJar(id, serverType, version)
+		if !upgradeWorld {
+			return
+		}
+		rs.mu.RLock()
+		installed := rs.status == "Stopped"
+		rs.mu.RUnlock()
+		if !installed {
+			return
+		}
+		if _, err := m.StartWorldUpgrade(id, WorldUpgradeOptions{}); err != nil {
+			log.Printf("[%s] World upgrade after version update not started: %v", cfg.Name, err)
+		}
+	}()
 
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -2498,78 +3301,197 @@ func (m *Manager) migrateBackupDir(oldDir, newDir string) error {
 	return nil
 }
 
-// StopAll gracefully stops all running servers
+// Shutdown policies: what happens to running servers when the panel exits.
+const (
+	ShutdownPolicyStop         = "stop"
+	ShutdownPolicyLeaveRunning = "leave-running"
+)
+
+// ShutdownPolicyFromEnv reads ADPANEL_SHUTDOWN_POLICY, defaulting to
+// stopping every server.
+func ShutdownPolicyFromEnv() string {
+	raw := strings.ToLower(strings.TrimSpace(os.Getenv("ADPANEL_SHUTDOWN_POLICY")))
+	switch raw {
+	case "":
+		return ShutdownPolicyStop
+	case ShutdownPolicyStop, ShutdownPolicyLeaveRunning:
+		return raw
+	default:
+		log.Printf("Invalid ADPANEL_SHUTDOWN_POLICY value %q, using %q", raw, ShutdownPolicyStop)
+		return ShutdownPolicyStop
+	}
+}
+
+// StopAll gracefully stops all running servers and the panel's background
+// work.
 func (m *Manager) StopAll() {
+	m.Shutdown(ShutdownPolicyStop)
+}
+
+// Shutdown stops the panel's background work and then deals with running
+// servers as policy says: ShutdownPolicyStop stops them all at once, each
+// with the usual time to save, and ShutdownPolicyLeaveRunning leaves them
+// for the next panel run to reattach to.
+func (m *Manager) Shutdown(policy string) {
 	// Stop the backup scheduler
 	close(m.stopScheduler)
 	close(m.stopUsageSampler)
 	close(m.stopImportCleanup)
+	close(m.stopPanelBackup)
+	close(m.stopMQTT)
+	close(m.stopStatusStream)
+	defer func() {
+		if m.panelBackupDone != nil {
+			<-m.panelBackupDone
+		}
+		if m.persister != nil {
+			if err := m.persister.stop(); err != nil {
+				log.Printf("Error flushing panel data: %v", err)
+			}
+		}
+		if m.store != nil {
+			if err := m.store.Close(); err != nil {
+				log.Printf("Error closing panel store: %v", err)
+			}
+		}
+	}()
 
 	m.mu.RLock()
 	ids := make([]string, 0)
 	for id, rs := range m.running {
 		rs.mu.RLock()
-		if rs.status == "Running" || rs.status == "Booting" {
+		if rs.status == "Running" || rs.status == "Booting" || rs.status == "Suspended" {
 			ids = append(ids, id)
 		}
 		rs.mu.RUnlock()
 	}
 	m.mu.RUnlock()
 
-	for _, id := range ids {
-		log.Printf("Stopping server %s...", id)
-		if err := m.StopServer(id); err != nil {
-			log.Printf("Error stopping server %s: %v", id, err)
+	if policy == ShutdownPolicyLeaveRunning {
+		for _, id := range ids {
+			log.Printf("Leaving server %s running for the next panel run", id)
 		}
+		return
+	}
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			log.Printf("Stopping server %s...", id)
+			if err := m.StopServer(id); err != nil {
+				log.Printf("Error stopping server %s: %v", id, err)
+			}
+		}(id)
 	}
+	wg.Wait()
 }
 
 // DeleteServer removes a server config (must be stopped)
 func (m *Manager) DeleteServer(id string) error {
+	name, serverDir, backupPath, err := m.removeServerConfig(id)
+	if err != nil {
+		return err
+	}
+	job := m.newDeleteJob(id, name)
+	m.deleteServerFilesJob(job, id, serverDir, backupPath)
+	job.finish(nil)
+	return nil
+}
+
+// StartDeleteServer removes a stopped server from the panel right away and
+// queues a job that deletes its files, which takes a while for big worlds.
+func (m *Manager) StartDeleteServer(id string) (*Job, error) {
+	name, serverDir, backupPath, err := m.removeServerConfig(id)
+	if err != nil {
+		return nil, err
+	}
+	job := m.newDeleteJob(id, name)
+	go func() {
+		m.deleteServerFilesJob(job, id, serverDir, backupPath)
+		job.finish(nil)
+	}()
+	return m.GetJob(job.id)
+}
+
+// newDeleteJob registers the job for a server that is no longer in the
+// panel, so its name is filled in by hand.
+func (m *Manager) newDeleteJob(id, name string) *jobHandle {
+	job := m.newJob(JobTypeDelete, id)
+	job.update(func(j *Job) {
+		j.ServerName = name
+	})
+	return job
+}
+
+// deleteServerFilesJob removes a deleted server's files. The server is
+// already gone from the panel, so failures are logged rather than returned.
+func (m *Manager) deleteServerFilesJob(job *jobHandle, id, serverDir, backupPath string) {
+	job.start("Deleting server files")
+	if serverDir != "" {
+		if err := os.RemoveAll(serverDir); err != nil {
+			log.Printf("Warning: failed to delete server directory %s: %v", serverDir, err)
+			job.log(fmt.Sprintf("Failed to delete server directory: %v", err))
+		}
+	}
+	job.progress(70, "Deleting backups")
+	if err := os.RemoveAll(backupPath); err != nil {
+		log.Printf("Warning: failed to delete backup directory %s: %v", backupPath, err)
+		job.log(fmt.Sprintf("Failed to delete backups: %v", err))
+	}
+	job.progress(95, "Cleaning up")
+	if err := os.RemoveAll(m.pluginQuarantineDir(id)); err != nil {
+		log.Printf("Warning: failed to delete quarantine directory for %s: %v", id, err)
+	}
+	if err := os.RemoveAll(m.stagedPluginUpdatesDir(id)); err != nil {
+		log.Printf("Warning: failed to delete staged plugin updates for %s: %v", id, err)
+	}
+	if err := os.Remove(m.bootFailureReportPath(id)); err != nil && !os.IsNotExist(err) {
+		log.Printf("Warning: failed to delete boot failure report for %s: %v", id, err)
+	}
+	if err := os.Remove(m.modpackArchivePath(id)); err != nil && !os.IsNotExist(err) {
+		log.Printf("Warning: failed to delete modpack archive for %s: %v", id, err)
+	}
+}
+
+// removeServerConfig drops a stopped server from the panel and returns its
+// name and the directories that belong to it.
+func (m *Manager) removeServerConfig(id string) (name, serverDir, backupPath string, err error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	cfg, err := m.serverConfigForOperationLocked(id)
 	if err != nil {
-		return err
+		return "", "", "", err
 	}
 	rs, rsOk := m.running[id]
 	if !rsOk {
-		return fmt.Errorf("server %s not found", id)
+		return "", "", "", errServerNotFound(id)
 	}
 
 	rs.mu.RLock()
 	status := rs.status
 	rs.mu.RUnlock()
 
-	if status == "Running" || status == "Booting" || status == "Installing" {
-		return fmt.Errorf("cannot delete server %s while it is %s", id, status)
+	if status == "Running" || status == "Booting" || status == "Suspended" || status == "Installing" {
+		return "", "", "", fmt.Errorf("cannot delete server %s while it is %s", id, status)
 	}
 	if err := m.validateManagedServerDir(cfg.Dir); err != nil {
-		return m.configPathErrorLocked(id, err.Error())
+		return "", "", "", m.configPathErrorLocked(id, err.Error())
 	}
-
-	// Delete server directory
-	if cfg.Dir != "" {
-		if err := os.RemoveAll(cfg.Dir); err != nil {
-			log.Printf("Warning: failed to delete server directory %s: %v", cfg.Dir, err)
-		}
-	}
-
-	// Delete backup directory
-	backupPath := m.backupDir(cfg)
-	if err := m.validateManagedBackupDir(backupPath); err != nil {
-		return fmt.Errorf("failed backup directory safety check: %w", err)
-	}
-	if err := os.RemoveAll(backupPath); err != nil {
-		log.Printf("Warning: failed to delete backup directory %s: %v", backupPath, err)
+	backupPath = m.backupDir(cfg)
+	if err := m.validateManagedBackupDir(backupPath); err != nil {
+		return "", "", "", fmt.Errorf("failed backup directory safety check: %w", err)
 	}
 
 	delete(m.configs, id)
 	delete(m.running, id)
 	delete(m.quarantinedServers, id)
-
-	return m.persist()
+	if err := m.persist(); err != nil {
+		return "", "", "", err
+	}
+	m.forgetPlayerHistory(id)
+	return cfg.Name, cfg.Dir, backupPath, nil
 }
 
 // GetServerDir returns the directory path for a server
@@ -2602,7 +3524,7 @@ func (m *Manager) configPathErrorLocked(id, fallbackReason string) error {
 func (m *Manager) serverConfigForOperationLocked(id string) (*ServerConfig, error) {
 	cfg, ok := m.configs[id]
 	if !ok {
-		return nil, fmt.Errorf("server %s not found", id)
+		return nil, errServerNotFound(id)
 	}
 	if reason := m.quarantineReasonLocked(id); reason != "" {
 		return nil, m.configPathErrorLocked(id, reason)
@@ -2927,11 +3849,13 @@ func legacyExtensionSourcesPath(cfg *ServerConfig) string {
 }
 
 func normalizeExtensionSourceKey(fileName string) string {
-	name := strings.TrimSpace(filepath.Base(fileName))
-	if strings.HasSuffix(strings.ToLower(name), ".disabled") {
-		name = name[:len(name)-len(".disabled")]
+	name := strings.TrimSpace(fileName)
+	prefix := ""
+	if dir, rest, ok := strings.Cut(name, "/"); ok && (dir == ExtensionDirPlugins || dir == ExtensionDirMods || dir == ExtensionDirDatapacks) {
+		prefix, name = dir+"/", rest
 	}
-	return name
+	name, _ = splitDisabledSuffix(strings.TrimSpace(filepath.Base(name)))
+	return prefix + name
 }
 
 func (m *Manager) extensionSourcesPath(cfg *ServerConfig) string {
@@ -2942,46 +3866,9 @@ func (m *Manager) extensionSourcesPath(cfg *ServerConfig) string {
 	return filepath.Join(m.baseDir, "data", "extension-sources", id+".json")
 }
 
-func (m *Manager) loadExtensionSources(cfg *ServerConfig) map[string]string {
-	path := m.extensionSourcesPath(cfg)
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return map[string]string{}
-	}
-
-	var sources map[string]string
-	if err := json.Unmarshal(data, &sources); err != nil {
-		return map[string]string{}
-	}
-	if sources == nil {
-		return map[string]string{}
-	}
-	return sources
-}
-
-func (m *Manager) saveExtensionSources(cfg *ServerConfig, sources map[string]string) error {
-	if sources == nil {
-		sources = map[string]string{}
-	}
-	data, err := json.MarshalIndent(sources, "", "  ")
-	if err != nil {
-		return err
-	}
-	if err := os.MkdirAll(filepath.Dir(m.extensionSourcesPath(cfg)), 0755); err != nil {
-		return err
-	}
-	return os.WriteFile(m.extensionSourcesPath(cfg), data, 0644)
-}
-
-func sourceForFile(sources map[string]string, fileName string) string {
-	if sources == nil {
-		return ""
-	}
-	key := normalizeExtensionSourceKey(fileName)
-	return strings.TrimSpace(sources[key])
-}
-
-// ListPlugins scans the plugins/ or mods/ directory for .jar files
+// ListPlugins scans the server's extension directories: plugins/ or mods/
+// (both on hybrid servers) for .jar files and the world's datapacks/ for
+// .zip files. Each entry reports the directory it came from.
 func (m *Manager) ListPlugins(id string) ([]PluginInfo, error) {
 	m.mu.RLock()
 	cfg, err := m.serverConfigForOperationLocked(id)
@@ -2990,64 +3877,77 @@ func (m *Manager) ListPlugins(id string) ([]PluginInfo, error) {
 		return nil, err
 	}
 
-	pluginsDir := extensionsDir(cfg)
-	entries, err := os.ReadDir(pluginsDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return []PluginInfo{}, nil
-		}
-		return nil, err
-	}
-
-	sources := m.loadExtensionSources(cfg)
+	manifest := m.loadExtensionManifest(cfg)
+	defaultDir := defaultExtensionDirName(cfg)
 	plugins := make([]PluginInfo, 0)
-	for _, entry := range entries {
-		if entry.IsDir() {
+	for _, dirName := range allowedExtensionDirNames(cfg) {
+		_, dirPath, err := resolveExtensionDir(cfg, dirName)
+		if err != nil {
 			continue
 		}
-		lower := strings.ToLower(entry.Name())
-		info, err := entry.Info()
+		entries, err := os.ReadDir(dirPath)
 		if err != nil {
-			continue
+			if os.IsNotExist(err) || dirName != defaultDir {
+				continue
+			}
+			return nil, err
 		}
-
-		if strings.HasSuffix(lower, ".jar.disabled") {
-			jarPath := filepath.Join(pluginsDir, entry.Name())
-			pName, pVersion := extractPluginVersion(jarPath)
-			if pName == "" {
-				pName = strings.TrimSuffix(strings.TrimSuffix(entry.Name(), ".disabled"), ".jar")
-			}
-			plugins = append(plugins, PluginInfo{
-				Name:      pName,
-				FileName:  entry.Name(),
-				Size:      formatFileSize(info.Size()),
-				Enabled:   false,
-				Version:   pVersion,
-				SourceURL: sourceForFile(sources, entry.Name()),
-			})
-		} else if strings.HasSuffix(lower, ".jar") {
-			jarPath := filepath.Join(pluginsDir, entry.Name())
-			pName, pVersion := extractPluginVersion(jarPath)
-			if pName == "" {
-				pName = strings.TrimSuffix(entry.Name(), ".jar")
-			}
-			plugins = append(plugins, PluginInfo{
-				Name:      pName,
-				FileName:  entry.Name(),
-				Size:      formatFileSize(info.Size()),
-				Enabled:   true,
-				Version:   pVersion,
-				SourceURL: sourceForFile(sources, entry.Name()),
-			})
+		for _, entry := range entries {
+			if entry.IsDir() || !extensionFileAllowed(dirName, entry.Name()) {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			baseName, disabled := splitDisabledSuffix(entry.Name())
+			enabled := !disabled
+			pName, pVersion := "", ""
+			if dirName == ExtensionDirDatapacks {
+				pName = strings.TrimSuffix(baseName, filepath.Ext(baseName))
+			} else {
+				pName, pVersion = extractPluginVersion(filepath.Join(dirPath, entry.Name()))
+				if pName == "" {
+					pName = strings.TrimSuffix(baseName, ".jar")
+				}
+			}
+			plugin := newListedPluginInfo(manifest, pName, extensionRecordName(cfg, dirName, entry.Name()), info.Size(), enabled, pVersion)
+			plugin.FileName = entry.Name()
+			plugin.Directory = dirName
+			plugins = append(plugins, plugin)
 		}
 	}
 
+	staged := m.listStagedPluginUpdates(cfg.ID)
+	for i := range plugins {
+		if plugins[i].Directory == defaultDir {
+			plugins[i].PendingUpdate = staged[normalizeExtensionSourceKey(plugins[i].FileName)]
+		}
+	}
+	attachPluginProjects(id, plugins)
 	return plugins, nil
 }
 
-// UploadPlugin saves a .jar file to the server's plugins/mods directory.
+func newListedPluginInfo(manifest map[string]*ExtensionProvenance, name, fileName string, size int64, enabled bool, version string) PluginInfo {
+	info := PluginInfo{
+		Name:       name,
+		FileName:   fileName,
+		Size:       formatFileSize(size),
+		Enabled:    enabled,
+		Version:    version,
+		Provenance: provenanceForFile(manifest, fileName),
+	}
+	if info.Provenance != nil {
+		info.SourceURL = info.Provenance.SourceURL
+		info.Note = info.Provenance.Note
+	}
+	return info
+}
+
+// UploadPlugin saves a .jar file to the server's plugins/mods directory, or a
+// datapack .zip when dir is "datapacks". An empty dir means the default.
 // If a file with the same name exists, callers must choose whether to replace or skip it.
-func (m *Manager) UploadPlugin(id, fileName string, data []byte, conflictAction string) (string, string, error) {
+func (m *Manager) UploadPlugin(id, dir, fileName string, data []byte, conflictAction string) (string, string, error) {
 	tmpFile, err := os.CreateTemp("", "orexa-plugin-upload-*.jar")
 	if err != nil {
 		return "", "", err
@@ -3063,11 +3963,27 @@ func (m *Manager) UploadPlugin(id, fileName string, data []byte, conflictAction
 	if err := tmpFile.Close(); err != nil {
 		return "", "", err
 	}
-	return m.UploadPluginFromFile(id, fileName, tmpPath, conflictAction)
+	return m.UploadPluginFromFile(id, dir, fileName, tmpPath, conflictAction)
 }
 
 // UploadPluginFromFile installs a plugin/mod jar from a local staged file path.
-func (m *Manager) UploadPluginFromFile(id, fileName, sourcePath, conflictAction string) (string, string, error) {
+// The jar is scanned first; suspicious files are quarantined and reported with
+// a *QuarantineError instead of being installed.
+func (m *Manager) UploadPluginFromFile(id, dir, fileName, sourcePath, conflictAction string) (string, string, error) {
+	return m.installPluginFile(id, fileName, sourcePath, conflictAction, pluginInstallOptions{scan: true, origin: ExtensionOriginUpload, dir: dir})
+}
+
+// pluginInstallOptions describes a staged jar: whether it still needs a scan
+// and the provenance to record once it is installed.
+type pluginInstallOptions struct {
+	scan        bool
+	dir         string // target extension directory; empty means the default
+	origin      string
+	sourceURL   string
+	downloadURL string
+}
+
+func (m *Manager) installPluginFile(id, fileName, sourcePath, conflictAction string, opts pluginInstallOptions) (string, string, error) {
 	m.mu.RLock()
 	cfg, err := m.serverConfigForOperationLocked(id)
 	m.mu.RUnlock()
@@ -3075,18 +3991,25 @@ func (m *Manager) UploadPluginFromFile(id, fileName, sourcePath, conflictAction
 		return "", "", err
 	}
 
+	dirName, pDir, err := resolveExtensionDir(cfg, opts.dir)
+	if err != nil {
+		return "", "", err
+	}
+	opts.dir = dirName
 	fileName = filepath.Base(strings.TrimSpace(fileName))
 	if fileName == "" || fileName == "." {
 		return "", "", fmt.Errorf("invalid plugin file name")
 	}
-	if !strings.HasSuffix(strings.ToLower(fileName), ".jar") {
+	if !extensionFileAllowed(dirName, fileName) || strings.HasSuffix(strings.ToLower(fileName), ".disabled") {
+		if dirName == ExtensionDirDatapacks {
+			return "", "", fmt.Errorf("only .zip datapacks are allowed")
+		}
 		return "", "", fmt.Errorf("only .jar files are allowed")
 	}
 	if _, err := os.Stat(sourcePath); err != nil {
 		return "", "", err
 	}
 
-	pDir := extensionsDir(cfg)
 	if err := os.MkdirAll(pDir, 0755); err != nil {
 		return "", "", err
 	}
@@ -3095,7 +4018,10 @@ func (m *Manager) UploadPluginFromFile(id, fileName, sourcePath, conflictAction
 		return "", "", err
 	}
 
-	uploadedMetadataKey := extractExtensionMetadataKeyFromFile(sourcePath)
+	uploadedMetadataKey := ""
+	if dirName != ExtensionDirDatapacks {
+		uploadedMetadataKey = extractExtensionMetadataKeyFromFile(sourcePath)
+	}
 	if uploadedMetadataKey != "" {
 		entries, err := os.ReadDir(pDir)
 		if err != nil && !os.IsNotExist(err) {
@@ -3138,9 +4064,31 @@ func (m *Manager) UploadPluginFromFile(id, fileName, sourcePath, conflictAction
 		return "", "", statErr
 	}
 
+	if dirName == ExtensionDirDatapacks {
+		if err := validateDatapackZip(sourcePath); err != nil {
+			return "", "", err
+		}
+	} else if opts.scan {
+		report, err := scanExtensionJar(context.Background(), sourcePath, pluginScanServerType(cfg, dirName))
+		if err != nil {
+			return "", "", err
+		}
+		if report.suspicious() {
+			entry, err := m.quarantinePluginUpload(cfg, fileName, sourcePath, report, opts)
+			if err != nil {
+				return "", "", err
+			}
+			log.Printf("[%s] Quarantined %s: %s", cfg.Name, fileName, strings.Join(report.Findings, "; "))
+			return fileName, "quarantined", &QuarantineError{Entry: entry}
+		}
+	}
+
 	if err := moveOrCopyFile(sourcePath, pluginPath, conflictAction == "replace"); err != nil {
 		return "", "", err
 	}
+	if err := m.recordExtensionInstall(cfg, extensionRecordName(cfg, dirName, fileName), opts.origin, opts.sourceURL, opts.downloadURL, pluginPath); err != nil {
+		log.Printf("[%s] Failed to record provenance for %s: %v", cfg.Name, fileName, err)
+	}
 	status := "uploaded"
 	if conflictAction == "replace" {
 		status = "replaced"
@@ -3196,8 +4144,9 @@ func moveOrCopyFile(sourcePath, targetPath string, replace bool) error {
 	return nil
 }
 
-// DeletePlugin removes a plugin jar from the server's plugins directory
-func (m *Manager) DeletePlugin(id, fileName string) error {
+// DeletePlugin removes a plugin jar, mod jar or datapack from one of the
+// server's extension directories (empty dir means the default).
+func (m *Manager) DeletePlugin(id, dir, fileName string) error {
 	m.mu.RLock()
 	cfg, err := m.serverConfigForOperationLocked(id)
 	m.mu.RUnlock()
@@ -3205,7 +4154,14 @@ func (m *Manager) DeletePlugin(id, fileName string) error {
 		return err
 	}
 
-	pluginPath, err := SafePath(extensionsDir(cfg), fileName)
+	dirName, dirPath, err := resolveExtensionDir(cfg, dir)
+	if err != nil {
+		return err
+	}
+	if !extensionFileAllowed(dirName, fileName) {
+		return fmt.Errorf("%s is not a %s entry", fileName, dirName)
+	}
+	pluginPath, err := SafePath(dirPath, fileName)
 	if err != nil {
 		return err
 	}
@@ -3213,20 +4169,22 @@ func (m *Manager) DeletePlugin(id, fileName string) error {
 	if err := os.Remove(pluginPath); err != nil {
 		return err
 	}
+	if dirName == defaultExtensionDirName(cfg) {
+		m.discardStagedPluginUpdate(cfg, fileName)
+	}
 
-	sources := m.loadExtensionSources(cfg)
-	key := normalizeExtensionSourceKey(fileName)
-	if _, ok := sources[key]; ok {
-		delete(sources, key)
-		if err := m.saveExtensionSources(cfg, sources); err != nil {
-			return err
-		}
+	key := normalizeExtensionSourceKey(extensionRecordName(cfg, dirName, fileName))
+	if _, ok := m.loadExtensionManifest(cfg)[key]; ok {
+		return m.updateExtensionManifest(cfg, func(manifest map[string]*ExtensionProvenance) {
+			delete(manifest, key)
+		})
 	}
 	return nil
 }
 
-// TogglePlugin enables/disables a plugin by renaming .jar <-> .jar.disabled
-func (m *Manager) TogglePlugin(id, fileName string) (*PluginInfo, error) {
+// TogglePlugin enables/disables an extension by renaming it to or from a
+// .disabled name (.jar <-> .jar.disabled, .zip <-> .zip.disabled).
+func (m *Manager) TogglePlugin(id, dir, fileName string) (*PluginInfo, error) {
 	m.mu.RLock()
 	cfg, err := m.serverConfigForOperationLocked(id)
 	m.mu.RUnlock()
@@ -3234,49 +4192,22 @@ func (m *Manager) TogglePlugin(id, fileName string) (*PluginInfo, error) {
 		return nil, err
 	}
 
-	pluginsDir := extensionsDir(cfg)
-
-	if strings.HasSuffix(fileName, ".disabled") {
-		oldPath, err := SafePath(pluginsDir, fileName)
-		if err != nil {
-			return nil, err
-		}
-		newName := strings.TrimSuffix(fileName, ".disabled")
-		newPath, err := SafePath(pluginsDir, newName)
-		if err != nil {
-			return nil, err
-		}
-		if err := os.Rename(oldPath, newPath); err != nil {
-			return nil, err
-		}
-		sources := m.loadExtensionSources(cfg)
-		oldKey := normalizeExtensionSourceKey(fileName)
-		newKey := normalizeExtensionSourceKey(newName)
-		if oldKey != newKey {
-			if src, ok := sources[oldKey]; ok && strings.TrimSpace(src) != "" {
-				sources[newKey] = src
-				delete(sources, oldKey)
-				_ = m.saveExtensionSources(cfg, sources)
-			}
-		}
-		info, _ := os.Stat(newPath)
-		size := "0 B"
-		if info != nil {
-			size = formatFileSize(info.Size())
-		}
-		return &PluginInfo{
-			Name:     strings.TrimSuffix(newName, ".jar"),
-			FileName: newName,
-			Size:     size,
-			Enabled:  true,
-		}, nil
+	dirName, pluginsDir, err := resolveExtensionDir(cfg, dir)
+	if err != nil {
+		return nil, err
+	}
+	if !extensionFileAllowed(dirName, fileName) {
+		return nil, fmt.Errorf("%s is not a %s entry", fileName, dirName)
 	}
 
+	newName, enabling := splitDisabledSuffix(fileName)
+	if !enabling {
+		newName = fileName + ".disabled"
+	}
 	oldPath, err := SafePath(pluginsDir, fileName)
 	if err != nil {
 		return nil, err
 	}
-	newName := fileName + ".disabled"
 	newPath, err := SafePath(pluginsDir, newName)
 	if err != nil {
 		return nil, err
@@ -3284,35 +4215,45 @@ func (m *Manager) TogglePlugin(id, fileName string) (*PluginInfo, error) {
 	if err := os.Rename(oldPath, newPath); err != nil {
 		return nil, err
 	}
-	sources := m.loadExtensionSources(cfg)
-	oldKey := normalizeExtensionSourceKey(fileName)
-	newKey := normalizeExtensionSourceKey(newName)
-	if oldKey != newKey {
-		if src, ok := sources[oldKey]; ok && strings.TrimSpace(src) != "" {
-			sources[newKey] = src
-			delete(sources, oldKey)
-			_ = m.saveExtensionSources(cfg, sources)
-		}
-	}
+	_ = m.moveExtensionRecord(cfg, extensionRecordName(cfg, dirName, fileName), extensionRecordName(cfg, dirName, newName))
 	info, _ := os.Stat(newPath)
 	size := "0 B"
 	if info != nil {
 		size = formatFileSize(info.Size())
 	}
-	return &PluginInfo{
-		Name:     strings.TrimSuffix(fileName, ".jar"),
-		FileName: newName,
-		Size:     size,
-		Enabled:  false,
-	}, nil
+	baseName, _ := splitDisabledSuffix(newName)
+	toggled := &PluginInfo{
+		Name:      strings.TrimSuffix(baseName, filepath.Ext(baseName)),
+		FileName:  newName,
+		Directory: dirName,
+		Size:      size,
+		Enabled:   enabling,
+	}
+	if dirName != ExtensionDirDatapacks {
+		name, version := extractPluginVersion(newPath)
+		if name != "" {
+			toggled.Name = name
+		}
+		toggled.Version = version
+	}
+	if provenance := provenanceForFile(m.loadExtensionManifest(cfg), extensionRecordName(cfg, dirName, newName)); provenance != nil {
+		toggled.Note = provenance.Note
+	}
+	return toggled, nil
 }
 
 // ============================================================
 // Schedule Restart
 // ============================================================
 
-// ScheduleRestart schedules a server restart after delaySeconds
-func (m *Manager) ScheduleRestart(id string, delaySeconds int) error {
+// ScheduleRestart schedules a server restart after delaySeconds. The
+// optional reason is included in the warnings players see.
+func (m *Manager) ScheduleRestart(id string, delaySeconds int, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if strings.ContainsAny(reason, "\r\n") || len(reason) > maxWarningMessageLength {
+		return fmt.Errorf("reason must be a single line of at most %d characters", maxWarningMessageLength)
+	}
+
 	m.mu.RLock()
 	cfg, err := m.serverConfigForOperationLocked(id)
 	rs, rsOk := m.running[id]
@@ -3322,7 +4263,7 @@ func (m *Manager) ScheduleRestart(id string, delaySeconds int) error {
 		return err
 	}
 	if !rsOk {
-		return fmt.Errorf("server %s not found", id)
+		return errServerNotFound(id)
 	}
 
 	rs.mu.Lock()
@@ -3334,23 +4275,126 @@ func (m *Manager) ScheduleRestart(id string, delaySeconds int) error {
 
 	if delaySeconds == 0 {
 		rs.mu.Unlock()
-		go m.executeRestart(id, cfg)
+		go m.executeRestart(id, cfg, reason)
 		log.Printf("[%s] Immediate restart requested", cfg.Name)
 		return nil
 	}
 
-	rs.restartAt = time.Now().Add(time.Duration(delaySeconds) * time.Second)
-	rs.restartTimer = time.AfterFunc(time.Duration(delaySeconds)*time.Second, func() {
-		m.SendCommand(id, "say Server restarting in 10 seconds...")
-		time.Sleep(10 * time.Second)
-		m.executeRestart(id, cfg)
-	})
+	restartAt := time.Now().Add(time.Duration(delaySeconds) * time.Second)
+	m.armRestartLocked(id, cfg, rs, restartAt, reason)
 	rs.mu.Unlock()
 
+	m.setPersistedRestartAt(id, restartAt, reason)
 	log.Printf("[%s] Restart scheduled in %d seconds", cfg.Name, delaySeconds)
 	return nil
 }
 
+// restartFinalWarning is the last countdown warning before a restart.
+const restartFinalWarning = 10 * time.Second
+
+// restartWarning is a countdown warning sent after a delay, announcing the
+// seconds left until the restart.
+type restartWarning struct {
+	after   time.Duration
+	seconds int
+}
+
+// restartWarnings returns the countdown warnings for a restart delay away:
+// one per configured minute lead that fits in the delay, and a final
+// warning 10 seconds before, or at once when less time is left.
+func restartWarnings(delay time.Duration, leadMinutes []int) []restartWarning {
+	var warnings []restartWarning
+	for _, minutes := range leadMinutes {
+		lead := time.Duration(minutes) * time.Minute
+		if lead >= delay || lead <= restartFinalWarning {
+			continue
+		}
+		warnings = append(warnings, restartWarning{after: delay - lead, seconds: minutes * 60})
+	}
+	final := min(restartFinalWarning, delay)
+	if seconds := int((final + time.Second - 1) / time.Second); seconds > 0 {
+		warnings = append(warnings, restartWarning{after: delay - final, seconds: seconds})
+	}
+	return warnings
+}
+
+// armRestartLocked starts the restart timer and its countdown warnings
+// (caller must hold rs.mu). The restart runs at restartAt, so every
+// warning announces the real time left.
+func (m *Manager) armRestartLocked(id string, cfg *ServerConfig, rs *runningServer, restartAt time.Time, reason string) {
+	delay := time.Until(restartAt)
+	if delay < 0 {
+		delay = 0
+	}
+	rs.restartAt = restartAt
+	rs.restartTimer = time.AfterFunc(delay, func() {
+		m.executeRestart(id, cfg, reason)
+	})
+
+	for _, warning := range restartWarnings(delay, m.currentRestartWarningMinutes()) {
+		seconds := warning.seconds
+		rs.restartWarnTimers = append(rs.restartWarnTimers, time.AfterFunc(warning.after, func() {
+			m.SendCommand(id, m.warningCommand(id, warningRestart, seconds, reason))
+		}))
+	}
+}
+
+// resumePersistedRestart re-arms a restart that was scheduled before the
+// panel restarted. Schedules that already elapsed are dropped.
+func (m *Manager) resumePersistedRestart(id string) {
+	m.mu.RLock()
+	cfg := m.configs[id]
+	rs := m.running[id]
+	raw, reason := "", ""
+	if cfg != nil {
+		raw = cfg.ScheduledRestartAt
+		reason = cfg.ScheduledRestartReason
+	}
+	m.mu.RUnlock()
+
+	if cfg == nil || rs == nil || raw == "" {
+		return
+	}
+	restartAt, err := time.Parse(time.RFC3339, raw)
+	if err != nil || !restartAt.After(time.Now()) {
+		m.setPersistedRestartAt(id, time.Time{}, "")
+		return
+	}
+
+	rs.mu.Lock()
+	if rs.status != "Running" || rs.restartTimer != nil {
+		rs.mu.Unlock()
+		return
+	}
+	m.armRestartLocked(id, cfg, rs, restartAt, reason)
+	rs.mu.Unlock()
+
+	log.Printf("[%s] Resumed scheduled restart at %s", cfg.Name, restartAt.Format(time.RFC3339))
+}
+
+// setPersistedRestartAt stores the scheduled restart time and reason in
+// servers.json so they survive a panel restart. A zero time clears both.
+func (m *Manager) setPersistedRestartAt(id string, restartAt time.Time, reason string) {
+	value := ""
+	if !restartAt.IsZero() {
+		value = restartAt.UTC().Format(time.RFC3339)
+	} else {
+		reason = ""
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg, ok := m.configs[id]
+	if !ok || (cfg.ScheduledRestartAt == value && cfg.ScheduledRestartReason == reason) {
+		return
+	}
+	cfg.ScheduledRestartAt = value
+	cfg.ScheduledRestartReason = reason
+	if err := m.persist(); err != nil {
+		log.Printf("[%s] Failed to persist scheduled restart: %v", cfg.Name, err)
+	}
+}
+
 // CancelRestart cancels a scheduled restart
 func (m *Manager) CancelRestart(id string) error {
 	m.mu.RLock()
@@ -3362,25 +4406,42 @@ func (m *Manager) CancelRestart(id string) error {
 	m.mu.RUnlock()
 
 	if !ok {
-		return fmt.Errorf("server %s not found", id)
+		return errServerNotFound(id)
 	}
 
 	rs.mu.Lock()
-	defer rs.mu.Unlock()
-
-	if rs.restartTimer == nil {
+	queued := append([]*jobHandle(nil), rs.queuedRestarts...)
+	if rs.restartTimer == nil && len(queued) == 0 {
+		rs.mu.Unlock()
 		return fmt.Errorf("no restart scheduled for server %s", id)
 	}
 
-	rs.restartTimer.Stop()
-	rs.restartTimer = nil
+	if rs.restartTimer != nil {
+		rs.restartTimer.Stop()
+		rs.restartTimer = nil
+	}
+	stopRestartWarningsLocked(rs)
 	rs.restartAt = time.Time{}
+	rs.mu.Unlock()
+
+	// A restart already waiting for the operation lock is cancelled there.
+	for _, job := range queued {
+		m.CancelJob(job.id)
+	}
 
+	m.setPersistedRestartAt(id, time.Time{}, "")
+	m.SendCommand(id, "say "+m.playerMessage(playerMsgRestartCancelled, nil))
 	return nil
 }
 
-// ScheduleStop schedules a graceful server stop after delaySeconds.
-func (m *Manager) ScheduleStop(id string, delaySeconds int) error {
+// ScheduleStop schedules a graceful server stop after delaySeconds. The
+// optional reason is included in the warnings players see.
+func (m *Manager) ScheduleStop(id string, delaySeconds int, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if strings.ContainsAny(reason, "\r\n") || len(reason) > maxWarningMessageLength {
+		return fmt.Errorf("reason must be a single line of at most %d characters", maxWarningMessageLength)
+	}
+
 	m.mu.RLock()
 	cfg, err := m.serverConfigForOperationLocked(id)
 	rs, rsOk := m.running[id]
@@ -3390,7 +4451,7 @@ func (m *Manager) ScheduleStop(id string, delaySeconds int) error {
 		return err
 	}
 	if !rsOk {
-		return fmt.Errorf("server %s not found", id)
+		return errServerNotFound(id)
 	}
 
 	rs.mu.Lock()
@@ -3403,9 +4464,9 @@ func (m *Manager) ScheduleStop(id string, delaySeconds int) error {
 	rs.stopAt = time.Now().Add(time.Duration(delaySeconds) * time.Second)
 	rs.stopTimer = time.AfterFunc(time.Duration(delaySeconds)*time.Second, func() {
 		log.Printf("[%s] Scheduled stop executing", cfg.Name)
-		m.SendCommand(id, "say Server stopping in 10 seconds...")
+		m.SendCommand(id, m.warningCommand(id, warningStop, 10, reason))
 		time.Sleep(10 * time.Second)
-		m.SendCommand(id, "say Server stopping now!")
+		m.SendCommand(id, m.warningCommand(id, warningStopNow, 0, reason))
 		time.Sleep(1 * time.Second)
 		if err := m.StopServer(id); err != nil {
 			log.Printf("[%s] Scheduled stop failed: %v", cfg.Name, err)
@@ -3423,8 +4484,35 @@ func (m *Manager) ScheduleStop(id string, delaySeconds int) error {
 // Server Cloning
 // ============================================================
 
+// CloneOptions picks what a clone copies from its source. With LinkFiles
+// the copy shares disk with the source: plugin and mod jars are hard
+// linked, and other files are cloned copy-on-write where the filesystem
+// supports it.
+type CloneOptions struct {
+	CopyPlugins bool
+	CopyWorlds  bool
+	CopyConfig  bool
+	LinkFiles   bool
+}
+
 // CloneServer creates a new server by copying data from a source server
-func (m *Manager) CloneServer(sourceID, name string, port int, copyPlugins, copyWorlds, copyConfig bool) (*ServerInfo, error) {
+func (m *Manager) CloneServer(sourceID, name string, port int, opts CloneOptions) (*ServerInfo, error) {
+	m.mu.RLock()
+	sourceCfg, err := m.serverConfigForOperationLocked(sourceID)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	job := m.newJob(JobTypeClone, sourceID)
+	server, err := m.cloneServerJob(job, sourceCfg, name, port, opts)
+	job.finish(err)
+	return server, err
+}
+
+// StartCloneServer queues a clone and returns its job right away. The job
+// reports bytes copied and carries the new server as its result.
+func (m *Manager) StartCloneServer(sourceID, name string, port int, opts CloneOptions) (*Job, error) {
 	m.mu.RLock()
 	sourceCfg, err := m.serverConfigForOperationLocked(sourceID)
 	m.mu.RUnlock()
@@ -3432,6 +4520,32 @@ func (m *Manager) CloneServer(sourceID, name string, port int, copyPlugins, copy
 		return nil, err
 	}
 
+	job := m.newJob(JobTypeClone, sourceID)
+	go func() {
+		_, err := m.cloneServerJob(job, sourceCfg, name, port, opts)
+		if err != nil {
+			log.Printf("[%s] Clone failed: %v", sourceCfg.Name, err)
+		}
+		job.finish(err)
+	}()
+	return m.GetJob(job.id)
+}
+
+// cloneLinkable reports whether a cloned file may be hard linked. Only jars
+// qualify: the panel and the loaders replace them rather than write into
+// them, so the source and the clone never see each other's changes.
+func cloneLinkable(rel string) bool {
+	return strings.EqualFold(filepath.Ext(rel), ".jar")
+}
+
+func (m *Manager) cloneServerJob(job *jobHandle, sourceCfg *ServerConfig, name string, port int, opts CloneOptions) (*ServerInfo, error) {
+	release, err := m.acquireServerOperation(job.ctx, sourceCfg.ID, operationClone)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	job.start(fmt.Sprintf("Cloning into %s", name))
+
 	// Create the new server first (this handles port conflicts, dir creation, etc.)
 	newServer, err := m.CreateServer(name, sourceCfg.Type, sourceCfg.Version, port, sourceCfg.MinRAM, sourceCfg.MaxRAM, sourceCfg.MaxPlayers, sourceCfg.Flags, sourceCfg.AlwaysPreTouch)
 	if err != nil {
@@ -3439,86 +4553,112 @@ func (m *Manager) CloneServer(sourceID, name string, port int, copyPlugins, copy
 	}
 
 	// Get the new server's directory
-	m.mu.RLock()
+	m.mu.Lock()
 	newCfg := m.configs[newServer.ID]
-	m.mu.RUnlock()
+	newCfg.PluginUpdateChannel = sourceCfg.PluginUpdateChannel
+	newCfg.ReadyCommands = append([]string(nil), sourceCfg.ReadyCommands...)
+	newCfg.Groups = append([]string(nil), sourceCfg.Groups...)
+	if sourceCfg.WarningMessages != nil {
+		msgs := *sourceCfg.WarningMessages
+		newCfg.WarningMessages = &msgs
+	}
+	m.persist()
+	m.mu.Unlock()
 
 	srcDir := sourceCfg.Dir
 	dstDir := newCfg.Dir
+	job.log(fmt.Sprintf("Created server %s", newCfg.Name))
+	job.progress(5, "")
 
-	// Copy plugins
-	if copyPlugins {
-		srcPlugins := filepath.Join(srcDir, "plugins")
-		dstPlugins := filepath.Join(dstDir, "plugins")
-		if _, err := os.Stat(srcPlugins); err == nil {
-			os.RemoveAll(dstPlugins)
-			cmd := exec.Command("cp", "-r", srcPlugins, dstPlugins)
-			if output, err := cmd.CombinedOutput(); err != nil {
-				log.Printf("Warning: failed to copy plugins: %s: %v", string(output), err)
-			}
+	// Collect the folders to copy so progress can be reported in bytes.
+	type cloneCopy struct{ label, name string }
+	var copies []cloneCopy
+	if opts.CopyPlugins {
+		copies = append(copies, cloneCopy{"plugins", "plugins"}, cloneCopy{"mods", "mods"})
+	}
+	if opts.CopyWorlds {
+		for _, world := range []string{"world", "world_nether", "world_the_end"} {
+			copies = append(copies, cloneCopy{"world " + world, world})
 		}
 	}
+	if opts.CopyConfig {
+		copies = append(copies, cloneCopy{"config", "config"})
+	}
+	var total int64
+	for i := 0; i < len(copies); i++ {
+		info, err := os.Stat(filepath.Join(srcDir, copies[i].name))
+		if err != nil || !info.IsDir() {
+			copies = append(copies[:i], copies[i+1:]...)
+			i--
+			continue
+		}
+		total += treeSize(filepath.Join(srcDir, copies[i].name))
+	}
 
-	// Copy worlds
-	if copyWorlds {
-		worldDirs := []string{"world", "world_nether", "world_the_end"}
-		entries, _ := os.ReadDir(srcDir)
-		for _, entry := range entries {
-			if !entry.IsDir() {
-				continue
-			}
-			isWorld := false
-			for _, wd := range worldDirs {
-				if entry.Name() == wd {
-					isWorld = true
-					break
-				}
-			}
-			if !isWorld {
-				continue
-			}
-			src := filepath.Join(srcDir, entry.Name())
-			dst := filepath.Join(dstDir, entry.Name())
-			cmd := exec.Command("cp", "-r", src, dst)
-			if output, err := cmd.CombinedOutput(); err != nil {
-				log.Printf("Warning: failed to copy world %s: %s: %v", entry.Name(), string(output), err)
+	var linkable func(string) bool
+	if opts.LinkFiles {
+		linkable = cloneLinkable
+	}
+	var done, shared int64
+	for _, c := range copies {
+		percent := 95
+		if total > 0 {
+			percent = 5 + int(90*done/total)
+		}
+		job.progress(percent, fmt.Sprintf("Copying %s", c.label))
+		dst := filepath.Join(dstDir, c.name)
+		os.RemoveAll(dst)
+		err := shareTree(job.ctx, filepath.Join(srcDir, c.name), dst, linkable, func(n int64, linked bool) {
+			done += n
+			if linked {
+				shared += n
 			}
+			job.transfer(done, total, 5, 95)
+		})
+		if job.ctx.Err() != nil {
+			return nil, fmt.Errorf("clone cancelled; partially copied server %s was kept", newCfg.Name)
+		}
+		if err != nil {
+			log.Printf("Warning: failed to copy %s: %v", c.label, err)
+			job.log(fmt.Sprintf("Failed to copy %s: %v", c.label, err))
+		} else {
+			job.log(fmt.Sprintf("Copied %s", c.label))
 		}
 	}
+	if opts.LinkFiles {
+		job.log(fmt.Sprintf("%s of %s shared with %s instead of copied", formatFileSize(shared), formatFileSize(done), sourceCfg.Name))
+	}
+	job.progress(95, "")
 
 	// Copy configuration files
-	if copyConfig {
+	if opts.CopyConfig {
 		configFiles := []string{
 			"server.properties", "bukkit.yml", "spigot.yml", "paper.yml",
-			"paper-global.yml", "purpur.yml", "config",
+			"paper-global.yml", "purpur.yml",
 			"banned-players.json", "banned-ips.json", "ops.json", "whitelist.json",
 		}
 		for _, name := range configFiles {
 			src := filepath.Join(srcDir, name)
 			dst := filepath.Join(dstDir, name)
 			info, err := os.Stat(src)
-			if err != nil {
+			if err != nil || info.IsDir() {
 				continue
 			}
-			if info.IsDir() {
-				cmd := exec.Command("cp", "-r", src, dst)
-				cmd.CombinedOutput()
-			} else {
-				data, err := os.ReadFile(src)
-				if err == nil {
-					// Update port in server.properties for the new server
-					if name == "server.properties" {
-						content := string(data)
-						content = regexp.MustCompile(`server-port=\d+`).ReplaceAllString(
-							content, fmt.Sprintf("server-port=%d", port))
-						data = []byte(content)
-					}
-					os.WriteFile(dst, data, 0644)
+			data, err := os.ReadFile(src)
+			if err == nil {
+				// Update port in server.properties for the new server
+				if name == "server.properties" {
+					content := string(data)
+					content = regexp.MustCompile(`server-port=\d+`).ReplaceAllString(
+						content, fmt.Sprintf("server-port=%d", newServer.Port))
+					data = []byte(content)
 				}
+				os.WriteFile(dst, data, 0644)
 			}
 		}
 	}
 
+	job.setResult(newServer)
 	return newServer, nil
 }
 
@@ -3560,6 +4700,50 @@ func (m *Manager) installServerJar(id, serverType, version string) {
 		return
 	}
 
+	job := m.newJob(JobTypeInstall, id)
+	release, err := m.acquireServerOperation(job.ctx, id, operationInstall)
+	if err != nil {
+		rs.mu.Lock()
+		rs.status = "Error"
+		rs.installError = "Installation cancelled"
+		rs.mu.Unlock()
+		job.finish(err)
+		return
+	}
+	defer release()
+	m.installServerJarJob(job, cfg, rs, serverType, version)
+}
+
+// installServerJarLocked installs the server jar like installServerJar,
+// for callers that already hold the server's operation lock.
+func (m *Manager) installServerJarLocked(id, serverType, version string) {
+	m.mu.RLock()
+	cfg := m.configs[id]
+	rs := m.running[id]
+	m.mu.RUnlock()
+
+	if cfg == nil || rs == nil {
+		return
+	}
+	m.installServerJarJob(m.newJob(JobTypeInstall, id), cfg, rs, serverType, version)
+}
+
+// installServerJarJob runs an install job. Callers hold the server's
+// operation lock.
+func (m *Manager) installServerJarJob(job *jobHandle, cfg *ServerConfig, rs *runningServer, serverType, version string) {
+	job.start(fmt.Sprintf("Installing %s %s", serverType, version))
+
+	defer func() {
+		rs.mu.RLock()
+		status, installError := rs.status, rs.installError
+		rs.mu.RUnlock()
+		if status == "Error" {
+			job.finish(errors.New(installError))
+			return
+		}
+		job.finish(nil)
+	}()
+
 	provider, err := GetProvider(serverType)
 	if err != nil {
 		rs.mu.Lock()
@@ -3574,7 +4758,7 @@ func (m *Manager) installServerJar(id, serverType, version string) {
 	actualVersion := version
 	var versions []VersionInfo
 	if strings.EqualFold(version, "latest") || strings.EqualFold(version, "") {
-		versions, err = provider.FetchVersions(context.Background())
+		versions, err = provider.FetchVersions(job.ctx)
 		if err != nil || len(versions) == 0 {
 			rs.mu.Lock()
 			rs.status = "Error"
@@ -3592,7 +4776,7 @@ func (m *Manager) installServerJar(id, serverType, version string) {
 			actualVersion = versions[0].Version
 		}
 	} else {
-		versions, err = provider.FetchVersions(context.Background())
+		versions, err = provider.FetchVersions(job.ctx)
 		if err == nil && len(versions) > 0 {
 			found := false
 			for _, v := range versions {
@@ -3616,9 +4800,10 @@ func (m *Manager) installServerJar(id, serverType, version string) {
 		log.Printf("[%s] Install: %s", cfg.Name, msg)
 		entry := m.appendLog(rs, fmt.Sprintf("[Installer] %s", msg))
 		m.broadcastLog(rs, entry)
+		job.log(msg)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	ctx, cancel := context.WithTimeout(job.ctx, 30*time.Minute)
 	defer cancel()
 
 	javaExec, javaRequired, javaSelected, javaErr := m.javaResolver.resolve(serverType, actualVersion)
@@ -3644,15 +4829,7 @@ func (m *Manager) installServerJar(id, serverType, version string) {
 
 	// For Forge/NeoForge: detect run.sh and set StartCommand
 	if strings.EqualFold(serverType, "forge") || strings.EqualFold(serverType, "neoforge") {
-		runSh := filepath.Join(cfg.Dir, "run.sh")
-		if _, err := os.Stat(runSh); err == nil {
-			os.Chmod(runSh, 0755)
-			m.mu.Lock()
-			cfg.StartCommand = []string{"bash", "run.sh", "nogui"}
-			m.persist()
-			m.mu.Unlock()
-			progressFn("Detected run.sh — server will use Forge/NeoForge launch script.")
-		}
+		m.useForgeRunScript(cfg, progressFn)
 	}
 
 	// Persist resolved/new version after a successful install/update.
@@ -3670,6 +4847,21 @@ func (m *Manager) installServerJar(id, serverType, version string) {
 	progressFn(fmt.Sprintf("Installation complete! %s %s is ready to start.", serverType, actualVersion))
 }
 
+// useForgeRunScript makes a Forge or NeoForge server start through the
+// run.sh its installer wrote, if there is one.
+func (m *Manager) useForgeRunScript(cfg *ServerConfig, progressFn func(string)) {
+	runSh := filepath.Join(cfg.Dir, "run.sh")
+	if _, err := os.Stat(runSh); err != nil {
+		return
+	}
+	os.Chmod(runSh, 0755)
+	m.mu.Lock()
+	cfg.StartCommand = []string{"bash", "run.sh", "nogui"}
+	m.persist()
+	m.mu.Unlock()
+	progressFn("Detected run.sh — server will use Forge/NeoForge launch script.")
+}
+
 // RetryInstall retries a failed installation
 func (m *Manager) RetryInstall(id string) error {
 	m.mu.RLock()
@@ -3681,7 +4873,7 @@ func (m *Manager) RetryInstall(id string) error {
 		return err
 	}
 	if !rsOk {
-		return fmt.Errorf("server %s not found", id)
+		return errServerNotFound(id)
 	}
 
 	rs.mu.Lock()
@@ -3689,10 +4881,20 @@ func (m *Manager) RetryInstall(id string) error {
 		rs.mu.Unlock()
 		return fmt.Errorf("server %s is not in error state (status: %s)", id, rs.status)
 	}
+	if cfg.Modpack != nil {
+		if _, err := os.Stat(m.modpackArchivePath(id)); err != nil {
+			rs.mu.Unlock()
+			return fmt.Errorf("the modpack archive for %s is gone; delete the server and install the pack again", cfg.Name)
+		}
+	}
 	rs.status = "Installing"
 	rs.installError = ""
 	rs.mu.Unlock()
 
+	if cfg.Modpack != nil {
+		go m.installModpack(id)
+		return nil
+	}
 	go m.installServerJar(id, cfg.Type, cfg.Version)
 	return nil
 }
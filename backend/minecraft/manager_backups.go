@@ -1,6 +1,7 @@
 package minecraft
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -51,6 +52,10 @@ func (m *Manager) ListBackups(id string) ([]BackupInfo, error) {
 			Size: formatFileSize(info.Size()),
 		})
 	}
+	backups = append(backups, m.incrementalBackupInfos(cfg)...)
+	for i := range backups {
+		backups[i].Uploads = m.backupUploads(cfg, backups[i].Name)
+	}
 
 	sort.Slice(backups, func(i, j int) bool {
 		return backups[i].Date > backups[j].Date
@@ -59,8 +64,8 @@ func (m *Manager) ListBackups(id string) ([]BackupInfo, error) {
 	return backups, nil
 }
 
-// CreateBackup creates a tar.gz archive of the server directory
-func (m *Manager) CreateBackup(id string) (*BackupInfo, error) {
+// backupSource returns the config of a server that is about to be backed up.
+func (m *Manager) backupSource(id string) (*ServerConfig, error) {
 	m.mu.RLock()
 	cfg, err := m.serverConfigForOperationLocked(id)
 	m.mu.RUnlock()
@@ -70,7 +75,117 @@ func (m *Manager) CreateBackup(id string) (*BackupInfo, error) {
 	if err := m.validateManagedServerDir(cfg.Dir); err != nil {
 		return nil, m.configPathErrorLocked(id, err.Error())
 	}
+	if err := m.checkBackupQuota(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// CreateBackup creates a tar.gz archive of the server directory and waits
+// for it to finish.
+func (m *Manager) CreateBackup(id string) (*BackupInfo, error) {
+	return m.createBackup(id, BackupModeFull)
+}
+
+func (m *Manager) createBackup(id, mode string) (*BackupInfo, error) {
+	cfg, err := m.backupSource(id)
+	if err != nil {
+		return nil, err
+	}
+
+	job := m.newJob(JobTypeBackup, id)
+	info, err := m.createBackupJob(job, cfg, mode)
+	job.finish(err)
+	return info, err
+}
+
+// StartBackup queues a full or incremental backup of the server and
+// returns its job right away. The job reports bytes archived as it goes
+// and carries the new backup as its result.
+func (m *Manager) StartBackup(id, mode string) (*Job, error) {
+	if !validBackupMode(mode) {
+		return nil, fmt.Errorf("invalid backup mode: %s", mode)
+	}
+	cfg, err := m.backupSource(id)
+	if err != nil {
+		return nil, err
+	}
+
+	job := m.newJob(JobTypeBackup, id)
+	go func() {
+		_, err := m.createBackupJob(job, cfg, mode)
+		if err != nil {
+			log.Printf("[%s] Backup failed: %v", cfg.Name, err)
+		}
+		job.finish(err)
+	}()
+	return m.GetJob(job.id)
+}
+
+// GetBackupJob returns one of a server's backup jobs. Jobs of another
+// server or of another type are reported as not found.
+func (m *Manager) GetBackupJob(id, jobID string) (*Job, error) {
+	job, err := m.GetJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.ServerID != id || job.Type != JobTypeBackup {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// createBackupLocked creates a backup like createBackup, for callers that
+// already hold the server's operation lock.
+func (m *Manager) createBackupLocked(id, mode string) (*BackupInfo, error) {
+	cfg, err := m.backupSource(id)
+	if err != nil {
+		return nil, err
+	}
 
+	job := m.newJob(JobTypeBackup, id)
+	info, err := m.createBackupJobLocked(job, cfg, mode)
+	job.finish(err)
+	return info, err
+}
+
+func (m *Manager) createBackupJob(job *jobHandle, cfg *ServerConfig, mode string) (*BackupInfo, error) {
+	release, err := m.acquireServerOperation(job.ctx, cfg.ID, operationBackup)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return m.createBackupJobLocked(job, cfg, mode)
+}
+
+// createBackupJobLocked writes the backup for a job. Callers hold the
+// server's operation lock.
+func (m *Manager) createBackupJobLocked(job *jobHandle, cfg *ServerConfig, mode string) (*BackupInfo, error) {
+	var info *BackupInfo
+	var err error
+	if mode == BackupModeIncremental {
+		job.start("Creating incremental snapshot")
+		info, err = m.writeIncrementalBackup(job, cfg, 0, 99)
+	} else {
+		job.start("Creating backup archive")
+		info, err = m.writeBackupArchive(job, cfg, 0, 99)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info.Kind != BackupModeIncremental {
+		if err := m.enforceBackupQuota(cfg, info.Name); err != nil {
+			return nil, err
+		}
+	}
+	job.setResult(info)
+	return info, nil
+}
+
+// writeBackupArchive archives the server directory into its backups folder,
+// reporting its progress between from and to percent of the job. Callers
+// hold the server's operation lock.
+func (m *Manager) writeBackupArchive(job *jobHandle, cfg *ServerConfig, from, to int) (*BackupInfo, error) {
 	backupsDir := m.backupDir(cfg)
 	if err := m.validateManagedBackupDir(backupsDir); err != nil {
 		return nil, err
@@ -83,10 +198,14 @@ func (m *Manager) CreateBackup(id string) (*BackupInfo, error) {
 	fileName := fmt.Sprintf("backup_%s.tar.gz", timestamp)
 	backupPath := filepath.Join(backupsDir, fileName)
 
-	cmd := exec.Command("tar", "-czf", backupPath, "--exclude=backups", "-C", cfg.Dir, ".")
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return nil, fmt.Errorf("backup failed: %s: %w", string(output), err)
+	report := func(done, total int64) {
+		job.transfer(done, total, from, to)
+	}
+	if err := writeTarGz(job.ctx, cfg.Dir, backupPath, report); err != nil {
+		_ = os.Remove(backupPath)
+		return nil, fmt.Errorf("backup failed: %w", err)
 	}
+	job.log(fmt.Sprintf("Created %s", fileName))
 
 	info, err := os.Stat(backupPath)
 	if err != nil {
@@ -113,12 +232,37 @@ func (m *Manager) DeleteBackup(id, fileName string) error {
 		return err
 	}
 
+	if isIncrementalBackup(fileName) {
+		// Later snapshots hold their own links to shared files, so removing
+		// one never affects another.
+		root := m.incrementalBackupRoot(cfg)
+		snapshotPath, err := SafePath(root, fileName)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(snapshotPath); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(snapshotPath); err != nil {
+			return err
+		}
+		if err := os.Remove(snapshotPath + ".json"); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
 	backupPath, err := SafePath(backupsDir, fileName)
 	if err != nil {
 		return err
 	}
 
-	return os.Remove(backupPath)
+	if err := os.Remove(backupPath); err != nil {
+		return err
+	}
+	// Copies on remote targets are kept; only the local record goes.
+	m.forgetBackupUploads(id, fileName)
+	return nil
 }
 
 // GetBackupPath returns the full filesystem path for downloading a backup
@@ -129,18 +273,12 @@ func (m *Manager) GetBackupPath(id, fileName string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	backupsDir := m.backupDir(cfg)
-	if err := m.validateManagedBackupDir(backupsDir); err != nil {
-		return "", err
-	}
-
-	backupPath, err := SafePath(backupsDir, fileName)
+	backupPath, incremental, err := m.resolveBackup(cfg, fileName)
 	if err != nil {
 		return "", err
 	}
-
-	if _, err := os.Stat(backupPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("backup %s not found", fileName)
+	if incremental {
+		return "", fmt.Errorf("incremental snapshots cannot be downloaded; restore one or create a full backup instead")
 	}
 
 	return backupPath, nil
@@ -156,30 +294,81 @@ func (m *Manager) RestoreBackup(id, fileName string) error {
 		return err
 	}
 	if !rsOk {
-		return fmt.Errorf("server %s not found", id)
+		return errServerNotFound(id)
 	}
 
+	job := m.newJob(JobTypeRestore, id)
+	err = m.restoreBackupJob(job, cfg, rs, fileName)
+	job.finish(err)
+	return err
+}
+
+// StartRestoreBackup queues a restore of a stopped server and returns its
+// job right away. A server that is running or a backup that does not exist
+// is refused before the job is queued.
+func (m *Manager) StartRestoreBackup(id, fileName string) (*Job, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	rs, rsOk := m.running[id]
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if !rsOk {
+		return nil, errServerNotFound(id)
+	}
 	rs.mu.RLock()
 	status := rs.status
 	rs.mu.RUnlock()
 	if status != "Stopped" && status != "Crashed" && status != "Error" {
-		return fmt.Errorf("server must be stopped before restoring a backup")
+		return nil, fmt.Errorf("server must be stopped before restoring a backup")
 	}
-	if err := m.validateManagedServerDir(cfg.Dir); err != nil {
-		return m.configPathErrorLocked(id, err.Error())
+	if _, _, err := m.resolveBackup(cfg, fileName); err != nil {
+		return nil, err
 	}
-	backupsDir := m.backupDir(cfg)
-	if err := m.validateManagedBackupDir(backupsDir); err != nil {
+
+	job := m.newJob(JobTypeRestore, id)
+	go func() {
+		err := m.restoreBackupJob(job, cfg, rs, fileName)
+		if err != nil {
+			log.Printf("[%s] Restore failed: %v", cfg.Name, err)
+		}
+		job.finish(err)
+	}()
+	return m.GetJob(job.id)
+}
+
+func (m *Manager) restoreBackupJob(job *jobHandle, cfg *ServerConfig, rs *runningServer, fileName string) error {
+	id := cfg.ID
+	release, err := m.acquireServerOperation(job.ctx, id, operationRestore)
+	if err != nil {
 		return err
 	}
+	defer release()
+	job.start(fmt.Sprintf("Restoring %s", fileName))
 
-	backupPath, err := SafePath(backupsDir, fileName)
+	rs.mu.Lock()
+	status := rs.status
+	if status != "Stopped" && status != "Crashed" && status != "Error" {
+		rs.mu.Unlock()
+		return fmt.Errorf("server must be stopped before restoring a backup")
+	}
+	// Installing keeps the server from being started while its files are
+	// replaced.
+	rs.status = "Installing"
+	rs.mu.Unlock()
+	defer func() {
+		rs.mu.Lock()
+		rs.status = status
+		rs.mu.Unlock()
+	}()
+	if err := m.validateManagedServerDir(cfg.Dir); err != nil {
+		return m.configPathErrorLocked(id, err.Error())
+	}
+	backupPath, incremental, err := m.resolveBackup(cfg, fileName)
 	if err != nil {
 		return err
 	}
-	if _, err := os.Stat(backupPath); os.IsNotExist(err) {
-		return fmt.Errorf("backup %s not found", fileName)
-	}
 
 	// Clear server directory contents
 	serverRoot, err := SafePath(cfg.Dir, ".")
@@ -200,18 +389,39 @@ func (m *Manager) RestoreBackup(id, fileName string) error {
 		}
 	}
 
-	// Extract backup
-	cmd := exec.Command("tar", "-xzf", backupPath, "-C", cfg.Dir)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("restore failed: %s: %w", string(output), err)
+	job.progress(30, "Cleared server directory")
+
+	if err := extractBackup(job.ctx, backupPath, incremental, cfg.Dir); err != nil {
+		return err
 	}
 
 	log.Printf("Restored backup %s for server %s", fileName, cfg.Name)
 	return nil
 }
 
-// SetBackupSchedule sets or clears the automatic backup schedule for a server
-func (m *Manager) SetBackupSchedule(id, schedule string) error {
+// extractBackup unpacks a full archive or copies an incremental snapshot
+// into dest.
+func extractBackup(ctx context.Context, backupPath string, incremental bool, dest string) error {
+	if incremental {
+		if err := restoreIncrementalSnapshot(ctx, backupPath, dest); err != nil {
+			return fmt.Errorf("restore failed: %w", err)
+		}
+		return nil
+	}
+	cmd := exec.CommandContext(ctx, "tar", "-xzf", backupPath, "-C", dest)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("restore failed: %s: %w", string(output), err)
+	}
+	return nil
+}
+
+// validBackupSchedules are the accepted backup schedules; "" means none.
+var validBackupSchedules = map[string]bool{"": true, "daily": true, "weekly": true, "monthly": true, "sixmonths": true, "yearly": true}
+
+// SetBackupSchedule sets or clears the automatic backup schedule for a
+// server and whether scheduled backups are full archives or incremental
+// snapshots. An empty mode means full.
+func (m *Manager) SetBackupSchedule(id, schedule, mode string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -220,12 +430,18 @@ func (m *Manager) SetBackupSchedule(id, schedule string) error {
 		return err
 	}
 
-	valid := map[string]bool{"": true, "daily": true, "weekly": true, "monthly": true, "sixmonths": true, "yearly": true}
-	if !valid[schedule] {
+	if !validBackupSchedules[schedule] {
 		return fmt.Errorf("invalid schedule: %s", schedule)
 	}
+	if !validBackupMode(mode) {
+		return fmt.Errorf("invalid backup mode: %s", mode)
+	}
 
 	cfg.BackupSchedule = schedule
+	cfg.BackupMode = mode
+	if mode == BackupModeFull {
+		cfg.BackupMode = ""
+	}
 	if schedule != "" && cfg.LastScheduledBackup == "" {
 		cfg.LastScheduledBackup = time.Now().UTC().Format(time.RFC3339)
 	}
@@ -248,6 +464,10 @@ func (m *Manager) GetBackupSchedule(id string) (map[string]string, error) {
 
 	result := map[string]string{
 		"schedule": cfg.BackupSchedule,
+		"mode":     BackupModeFull,
+	}
+	if cfg.BackupMode != "" {
+		result["mode"] = cfg.BackupMode
 	}
 	if cfg.BackupSchedule != "" && cfg.LastScheduledBackup != "" {
 		lastTime, err := time.Parse(time.RFC3339, cfg.LastScheduledBackup)
@@ -277,7 +497,9 @@ func nextScheduledBackupTime(last time.Time, schedule string) time.Time {
 	}
 }
 
-// runBackupScheduler periodically checks if any scheduled backups are due
+// runBackupScheduler periodically checks if any scheduled backups, region
+// prunes, whitelist changes, temporary ban expiries or scheduled tasks are
+// due
 func (m *Manager) runBackupScheduler() {
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
@@ -288,6 +510,10 @@ func (m *Manager) runBackupScheduler() {
 			return
 		case <-ticker.C:
 			m.checkScheduledBackups()
+			m.checkScheduledRegionPrunes()
+			m.checkWhitelistSchedules()
+			m.checkTempBans()
+			m.checkScheduledTasks()
 		}
 	}
 }
@@ -296,8 +522,10 @@ func (m *Manager) runBackupScheduler() {
 func (m *Manager) checkScheduledBackups() {
 	m.mu.RLock()
 	type pending struct {
-		id   string
-		name string
+		id      string
+		name    string
+		mode    string
+		targets []string
 	}
 	var due []pending
 	now := time.Now().UTC()
@@ -312,19 +540,24 @@ func (m *Manager) checkScheduledBackups() {
 		}
 		next := nextScheduledBackupTime(lastTime, cfg.BackupSchedule)
 		if now.After(next) {
-			due = append(due, pending{id: id, name: cfg.Name})
+			due = append(due, pending{id: id, name: cfg.Name, mode: cfg.BackupMode, targets: cfg.BackupTargets})
 		}
 	}
 	m.mu.RUnlock()
 
 	for _, p := range due {
 		log.Printf("Running scheduled backup for server: %s", p.name)
-		backup, err := m.CreateBackup(p.id)
+		backup, err := m.createBackup(p.id, p.mode)
 		if err != nil {
 			log.Printf("Scheduled backup failed for %s: %v", p.name, err)
 			continue
 		}
 		log.Printf("Scheduled backup completed for %s: %s", p.name, backup.Name)
+		if backup.Kind != BackupModeIncremental && len(p.targets) > 0 {
+			if _, err := m.UploadBackup(p.id, backup.Name); err != nil {
+				log.Printf("Failed to start upload of %s for %s: %v", backup.Name, p.name, err)
+			}
+		}
 
 		// Update last scheduled backup time
 		m.mu.Lock()
@@ -105,27 +105,36 @@ func (m *Manager) WriteFileContent(id, subPath string, content []byte) error {
 
 // DeletePath removes a file or directory within a server directory
 func (m *Manager) DeletePath(id, subPath string) error {
+	_, targetPath, err := m.deleteTarget(id, subPath)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(targetPath)
+}
+
+// deleteTarget resolves a path the file API may delete: anything inside the
+// server directory except the directory itself.
+func (m *Manager) deleteTarget(id, subPath string) (*ServerConfig, string, error) {
 	m.mu.RLock()
 	cfg, err := m.serverConfigForOperationLocked(id)
 	m.mu.RUnlock()
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
 	targetPath, err := SafePath(cfg.Dir, subPath)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
 	serverRoot, err := SafePath(cfg.Dir, ".")
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 	if samePath(serverRoot, targetPath) {
-		return fmt.Errorf("cannot delete server root directory")
+		return nil, "", fmt.Errorf("cannot delete server root directory")
 	}
-
-	return os.RemoveAll(targetPath)
+	return cfg, targetPath, nil
 }
 
 // CreateDirectory creates a directory within a server directory
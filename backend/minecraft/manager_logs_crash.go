@@ -155,6 +155,44 @@ func (m *Manager) ReadLogFile(id, fileName string) ([]byte, error) {
 	return data, nil
 }
 
+// ConsoleLog returns the lines in a server's console buffer, oldest first.
+func (m *Manager) ConsoleLog(id string) ([]string, error) {
+	m.mu.RLock()
+	_, err := m.serverConfigForOperationLocked(id)
+	rs := m.running[id]
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if rs == nil {
+		return []string{}, nil
+	}
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	lines := make([]string, 0, len(rs.logBuffer))
+	for _, entry := range rs.logBuffer {
+		lines = append(lines, entry.Line)
+	}
+	return lines, nil
+}
+
+// OpenLatestLog opens a server's logs/latest.log for reading.
+func (m *Manager) OpenLatestLog(id string) (*os.File, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(filepath.Join(cfg.Dir, "logs", "latest.log"))
+}
+
+// StripConsoleFormatting removes ANSI escapes and Minecraft colour and
+// format codes from a console line.
+func StripConsoleFormatting(line string) string {
+	return motdFormatPattern.ReplaceAllString(ansiPattern.ReplaceAllString(line, ""), "")
+}
+
 // CopyCrashReport duplicates a crash report file with a "-copy" suffix.
 func (m *Manager) CopyCrashReport(id, fileName string) (string, error) {
 	m.mu.RLock()
@@ -0,0 +1,125 @@
+package minecraft
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Long-running operations that are serialized per server.
+const (
+	operationBackup      = "backup"
+	operationRestore     = "restore"
+	operationInstall     = "install"
+	operationClone       = "clone"
+	operationRestart     = "restart"
+	operationPrune       = "region-prune"
+	operationUpgrade     = "world-upgrade"
+	operationBenchmark   = "benchmark"
+	operationWorld       = "world"
+	operationMaintenance = "maintenance"
+)
+
+// serverOperationLock serializes long-running operations on one server.
+// Callers that find the lock held are queued and woken in arrival order.
+type serverOperationLock struct {
+	mu     sync.Mutex
+	active string
+	since  time.Time
+	queue  []*operationWaiter
+}
+
+type operationWaiter struct {
+	name  string
+	ready chan struct{}
+}
+
+func (m *Manager) serverOperationLockFor(id string) *serverOperationLock {
+	m.opLocksMu.Lock()
+	defer m.opLocksMu.Unlock()
+	if m.opLocks == nil {
+		m.opLocks = make(map[string]*serverOperationLock)
+	}
+	lock, ok := m.opLocks[id]
+	if !ok {
+		lock = &serverOperationLock{}
+		m.opLocks[id] = lock
+	}
+	return lock
+}
+
+// acquireServerOperation blocks until no other long-running operation holds
+// the server and returns the function that releases it. Waiting stops early
+// when ctx is cancelled.
+func (m *Manager) acquireServerOperation(ctx context.Context, id, name string) (func(), error) {
+	lock := m.serverOperationLockFor(id)
+
+	lock.mu.Lock()
+	if lock.active == "" {
+		lock.active = name
+		lock.since = time.Now()
+		lock.mu.Unlock()
+	} else {
+		waiter := &operationWaiter{name: name, ready: make(chan struct{})}
+		lock.queue = append(lock.queue, waiter)
+		busyWith := lock.active
+		lock.mu.Unlock()
+		log.Printf("Server %s: %s queued behind %s", id, name, busyWith)
+
+		select {
+		case <-waiter.ready:
+		case <-ctx.Done():
+			lock.mu.Lock()
+			for i, queued := range lock.queue {
+				if queued == waiter {
+					lock.queue = append(lock.queue[:i], lock.queue[i+1:]...)
+					lock.mu.Unlock()
+					return nil, ctx.Err()
+				}
+			}
+			lock.mu.Unlock()
+			// The lock was handed over while we were cancelled; pass it on.
+			<-waiter.ready
+			lock.releaseNext()
+			return nil, ctx.Err()
+		}
+	}
+
+	var once sync.Once
+	return func() { once.Do(lock.releaseNext) }, nil
+}
+
+// releaseNext hands the lock to the next queued operation, if any.
+func (lock *serverOperationLock) releaseNext() {
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+	if len(lock.queue) == 0 {
+		lock.active = ""
+		lock.since = time.Time{}
+		return
+	}
+	next := lock.queue[0]
+	lock.queue = lock.queue[1:]
+	lock.active = next.name
+	lock.since = time.Now()
+	close(next.ready)
+}
+
+// serverOperationState reports the operation currently holding the server
+// and the ones waiting behind it.
+func (m *Manager) serverOperationState(id string) (active string, since time.Time, queued []string) {
+	m.opLocksMu.Lock()
+	lock, ok := m.opLocks[id]
+	m.opLocksMu.Unlock()
+	if !ok {
+		return "", time.Time{}, nil
+	}
+
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+	for _, waiter := range lock.queue {
+		queued = append(queued, waiter.name)
+	}
+	return lock.active, lock.since, queued
+}
@@ -0,0 +1,58 @@
+package minecraft
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestServerOperationsRunInArrivalOrder(t *testing.T) {
+	mgr := &Manager{}
+	release, err := mgr.acquireServerOperation(context.Background(), "srv1", operationRestart)
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+
+	order := make(chan string, 2)
+	for _, name := range []string{operationBackup, operationClone} {
+		go func(name string) {
+			done, err := mgr.acquireServerOperation(context.Background(), "srv1", name)
+			if err != nil {
+				order <- "error: " + err.Error()
+				return
+			}
+			done()
+			order <- name
+		}(name)
+		// Give each waiter time to enqueue so arrival order is deterministic.
+		deadline := time.Now().Add(time.Second)
+		for {
+			_, _, queued := mgr.serverOperationState("srv1")
+			if len(queued) > 0 && queued[len(queued)-1] == name {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("%s never queued", name)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	active, _, queued := mgr.serverOperationState("srv1")
+	if active != operationRestart {
+		t.Fatalf("expected restart to hold the server, got %q", active)
+	}
+	if !reflect.DeepEqual(queued, []string{operationBackup, operationClone}) {
+		t.Fatalf("unexpected queue: %v", queued)
+	}
+
+	release()
+	got := []string{<-order, <-order}
+	if !reflect.DeepEqual(got, []string{operationBackup, operationClone}) {
+		t.Fatalf("expected queued operations to run in order, got %v", got)
+	}
+	if active, _, _ := mgr.serverOperationState("srv1"); active != "" {
+		t.Fatalf("expected server to be idle, still busy with %q", active)
+	}
+}
@@ -21,7 +21,7 @@ func (m *Manager) listPlayersSnapshot(id string) ([]PlayerInfo, bool, time.Time,
 	serverDir := cfg.Dir
 	m.mu.RUnlock()
 	if !ok {
-		return nil, false, time.Time{}, fmt.Errorf("server %s not found", id)
+		return nil, false, time.Time{}, errServerNotFound(id)
 	}
 
 	rs.mu.RLock()
@@ -45,14 +45,19 @@ func (m *Manager) listPlayersSnapshot(id string) ([]PlayerInfo, bool, time.Time,
 			onlineTime = fmt.Sprintf("%dm", minutes)
 		}
 
-		players = append(players, PlayerInfo{
+		info := PlayerInfo{
 			Name:       p.Name,
 			UUID:       normalizePlayerUUID(p.UUID),
 			IP:         p.IP,
 			Ping:       p.Ping,
 			World:      p.World,
 			OnlineTime: onlineTime,
-		})
+		}
+		if len(p.Position) == 3 {
+			info.Position = append([]float64(nil), p.Position...)
+			info.PositionAt = p.PositionAt.UTC().Format(time.RFC3339)
+		}
+		players = append(players, info)
 	}
 	enrichPlayersWithUserCache(players, serverDir)
 
@@ -190,12 +195,23 @@ func (m *Manager) KickPlayer(id, playerName, reason string) error {
 	return m.SendCommand(id, fmt.Sprintf("kick %s %s", playerName, reason))
 }
 
-// BanPlayer sends a ban command to the server
+// BanPlayer sends a ban command to the server. A permanent ban replaces
+// any temporary ban the panel would otherwise lift later.
 func (m *Manager) BanPlayer(id, playerName, reason string) error {
-	if reason == "" {
-		return m.SendCommand(id, fmt.Sprintf("ban %s", playerName))
+	command := fmt.Sprintf("ban %s", playerName)
+	if reason != "" {
+		command = fmt.Sprintf("ban %s %s", playerName, reason)
+	}
+	if err := m.SendCommand(id, command); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cfg, ok := m.configs[id]; ok && removeTempBanLocked(cfg, playerName) {
+		return m.persist()
 	}
-	return m.SendCommand(id, fmt.Sprintf("ban %s %s", playerName, reason))
+	return nil
 }
 
 // KillPlayer sends a kill command to the server
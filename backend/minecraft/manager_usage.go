@@ -74,16 +74,19 @@ func (m *Manager) runUsageSampler() {
 	defer ticker.Stop()
 	lastSummary := time.Time{}
 
-	sampleProcess := func(pid int) (float64, uint64, bool) {
+	pressureSampler := newMemoryPressureSampler()
+	lastPressureLevel := MemoryPressureOK
+
+	sampleProcess := func(pid int) (float64, uint64, uint64, bool) {
 		if pid <= 0 {
-			return 0, 0, false
+			return 0, 0, 0, false
 		}
 		proc := knownProcesses[pid]
 		if proc == nil {
 			nextProc, err := process.NewProcess(int32(pid))
 			if err != nil {
 				delete(knownProcesses, pid)
-				return 0, 0, false
+				return 0, 0, 0, false
 			}
 			proc = nextProc
 			knownProcesses[pid] = proc
@@ -92,14 +95,14 @@ func (m *Manager) runUsageSampler() {
 		rawCPU, err := proc.Percent(0)
 		if err != nil {
 			delete(knownProcesses, pid)
-			return 0, 0, false
+			return 0, 0, 0, false
 		}
 		memInfo, err := proc.MemoryInfo()
 		if err != nil || memInfo == nil {
 			delete(knownProcesses, pid)
-			return 0, 0, false
+			return 0, 0, 0, false
 		}
-		return m.hostCPUSharePercent(rawCPU), memInfo.RSS, true
+		return m.hostCPUSharePercent(rawCPU), memInfo.RSS, memInfo.Swap, true
 	}
 
 	sampleOnce := func() {
@@ -127,7 +130,7 @@ func (m *Manager) runUsageSampler() {
 
 		panelCPU := 0.0
 		panelRAM := uint64(0)
-		if cpuPercent, ramBytes, ok := sampleProcess(panelPID); ok {
+		if cpuPercent, ramBytes, _, ok := sampleProcess(panelPID); ok {
 			panelCPU = cpuPercent
 			panelRAM = ramBytes
 		}
@@ -139,14 +142,17 @@ func (m *Manager) runUsageSampler() {
 		for _, target := range targets {
 			serverCPU := 0.0
 			serverRAM := uint64(0)
-			if cpuPercent, ramBytes, ok := sampleProcess(target.PID); ok {
+			serverSwap := uint64(0)
+			if cpuPercent, ramBytes, swapBytes, ok := sampleProcess(target.PID); ok {
 				serverCPU = cpuPercent
 				serverRAM = ramBytes
+				serverSwap = swapBytes
 			}
 			target.RS.mu.Lock()
 			target.RS.cpu = serverCPU
 			target.RS.ram = m.hostRAMSharePercent(serverRAM)
 			target.RS.ramBytes = serverRAM
+			target.RS.swapBytes = serverSwap
 			target.RS.mu.Unlock()
 
 			if target.PID <= 0 {
@@ -162,12 +168,23 @@ func (m *Manager) runUsageSampler() {
 				CPUPercent: serverCPU,
 				RAMBytes:   serverRAM,
 				RAMPercent: m.hostRAMSharePercent(serverRAM),
+				SwapBytes:  serverSwap,
 			}
 			serverSnapshots = append(serverSnapshots, snapshot)
 			totalCPU += snapshot.CPUPercent
 			totalRAM += snapshot.RAMBytes
 		}
 
+		pressure := pressureSampler.sample(time.Now())
+		if pressure != nil && pressure.Level != lastPressureLevel {
+			if pressure.Level == MemoryPressureOK {
+				log.Printf("Memory pressure cleared")
+			} else {
+				log.Printf("%s (level=%s)", pressure.Warning, pressure.Level)
+			}
+			lastPressureLevel = pressure.Level
+		}
+
 		snapshot := SystemUsageSnapshot{
 			Timestamp: time.Now().UTC().Format(time.RFC3339),
 			Host: UsageHostInfo{
@@ -189,6 +206,7 @@ func (m *Manager) runUsageSampler() {
 				RAMBytes:   totalRAM,
 				RAMPercent: m.hostRAMSharePercent(totalRAM),
 			},
+			MemoryPressure: pressure,
 		}
 
 		m.usageMu.Lock()
@@ -228,11 +246,18 @@ func (m *Manager) GetSystemUsage() SystemUsageSnapshot {
 	servers := make([]UsageProcessSnapshot, len(m.systemUsage.Servers))
 	copy(servers, m.systemUsage.Servers)
 
+	var pressure *MemoryPressure
+	if m.systemUsage.MemoryPressure != nil {
+		p := *m.systemUsage.MemoryPressure
+		pressure = &p
+	}
+
 	return SystemUsageSnapshot{
-		Timestamp: m.systemUsage.Timestamp,
-		Host:      m.systemUsage.Host,
-		Panel:     m.systemUsage.Panel,
-		Servers:   servers,
-		Total:     m.systemUsage.Total,
+		Timestamp:      m.systemUsage.Timestamp,
+		Host:           m.systemUsage.Host,
+		Panel:          m.systemUsage.Panel,
+		Servers:        servers,
+		Total:          m.systemUsage.Total,
+		MemoryPressure: pressure,
 	}
 }
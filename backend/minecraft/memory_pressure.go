@@ -0,0 +1,210 @@
+package minecraft
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Memory pressure levels.
+const (
+	MemoryPressureOK       = "ok"
+	MemoryPressureWarning  = "warning"
+	MemoryPressureCritical = "critical"
+)
+
+// Thresholds for the memory pressure level. PSI values are the share of
+// the last 10 seconds that tasks waited for memory; swap-in is what makes
+// a swapping host slow, so swap merely sitting full does not count.
+const (
+	psiSomeWarning     = 10.0
+	psiFullCritical    = 10.0
+	swapInWarningRate  = 1 << 20  // bytes per second
+	swapInCriticalRate = 10 << 20 // bytes per second
+	swapOutWarningRate = 4 << 20  // bytes per second
+)
+
+// MemoryPressure is how short the host is on memory. PSI fields are only
+// set on Linux kernels with pressure stall information.
+type MemoryPressure struct {
+	Level          string  `json:"level"`
+	Warning        string  `json:"warning,omitempty"`
+	SwapTotalBytes uint64  `json:"swapTotalBytes"`
+	SwapUsedBytes  uint64  `json:"swapUsedBytes"`
+	SwapInPerSec   float64 `json:"swapInBytesPerSec"`
+	SwapOutPerSec  float64 `json:"swapOutBytesPerSec"`
+	Zram           bool    `json:"zram,omitempty"`
+	PSIAvailable   bool    `json:"psiAvailable"`
+	PSISomeAvg10   float64 `json:"psiSomeAvg10,omitempty"`
+	PSIFullAvg10   float64 `json:"psiFullAvg10,omitempty"`
+	AvailableRAM   uint64  `json:"availableRamBytes"`
+}
+
+// memoryPressureSampler reads memory pressure from procfs. Swap rates are
+// worked out from the change in /proc/vmstat between samples.
+type memoryPressureSampler struct {
+	procRoot string
+	pageSize uint64
+	prevIn   uint64
+	prevOut  uint64
+	prevAt   time.Time
+}
+
+func newMemoryPressureSampler() *memoryPressureSampler {
+	return &memoryPressureSampler{procRoot: "/proc", pageSize: uint64(os.Getpagesize())}
+}
+
+// sample returns the host's memory pressure, or nil when procfs is not
+// there to read.
+func (s *memoryPressureSampler) sample(now time.Time) *MemoryPressure {
+	meminfo, err := readKeyValueFile(filepath.Join(s.procRoot, "meminfo"))
+	if err != nil {
+		return nil
+	}
+	p := &MemoryPressure{Level: MemoryPressureOK}
+	// meminfo values are in kB.
+	p.SwapTotalBytes = meminfo["SwapTotal"] * 1024
+	p.SwapUsedBytes = (meminfo["SwapTotal"] - min(meminfo["SwapFree"], meminfo["SwapTotal"])) * 1024
+	p.AvailableRAM = meminfo["MemAvailable"] * 1024
+	p.Zram = swapOnZram(filepath.Join(s.procRoot, "swaps"))
+
+	if vmstat, err := readKeyValueFile(filepath.Join(s.procRoot, "vmstat")); err == nil {
+		in, out := vmstat["pswpin"], vmstat["pswpout"]
+		if !s.prevAt.IsZero() && in >= s.prevIn && out >= s.prevOut {
+			if elapsed := now.Sub(s.prevAt).Seconds(); elapsed > 0 {
+				p.SwapInPerSec = float64((in-s.prevIn)*s.pageSize) / elapsed
+				p.SwapOutPerSec = float64((out-s.prevOut)*s.pageSize) / elapsed
+			}
+		}
+		s.prevIn, s.prevOut, s.prevAt = in, out, now
+	}
+
+	if data, err := os.ReadFile(filepath.Join(s.procRoot, "pressure", "memory")); err == nil {
+		if some, full, ok := parseMemoryPSI(data); ok {
+			p.PSIAvailable = true
+			p.PSISomeAvg10, p.PSIFullAvg10 = some, full
+		}
+	}
+	p.classify()
+	return p
+}
+
+// classify sets the level and a warning that says why.
+func (p *MemoryPressure) classify() {
+	var reasons []string
+	critical := false
+	if p.PSIFullAvg10 >= psiFullCritical {
+		critical = true
+		reasons = append(reasons, fmt.Sprintf("all tasks stalled on memory %.0f%% of the last 10s", p.PSIFullAvg10))
+	} else if p.PSISomeAvg10 >= psiSomeWarning {
+		reasons = append(reasons, fmt.Sprintf("tasks waited for memory %.0f%% of the last 10s", p.PSISomeAvg10))
+	}
+	if p.SwapInPerSec >= swapInWarningRate || p.SwapOutPerSec >= swapOutWarningRate {
+		critical = critical || p.SwapInPerSec >= swapInCriticalRate
+		swap := "swap"
+		if p.Zram {
+			swap = "zram swap"
+		}
+		reasons = append(reasons, fmt.Sprintf("the host is using %s (%.1f MB/s in, %.1f MB/s out)", swap, bytesToMB(uint64(p.SwapInPerSec)), bytesToMB(uint64(p.SwapOutPerSec))))
+	}
+	switch {
+	case critical:
+		p.Level = MemoryPressureCritical
+	case len(reasons) > 0:
+		p.Level = MemoryPressureWarning
+	default:
+		p.Level = MemoryPressureOK
+		return
+	}
+	p.Warning = "Memory pressure: " + strings.Join(reasons, "; ") + ". Low TPS is likely caused by the host running out of RAM."
+}
+
+// parseMemoryPSI reads the avg10 values of /proc/pressure/memory.
+func parseMemoryPSI(data []byte) (some, full float64, ok bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		for _, field := range fields[1:] {
+			value, found := strings.CutPrefix(field, "avg10=")
+			if !found {
+				continue
+			}
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			switch fields[0] {
+			case "some":
+				some, ok = v, true
+			case "full":
+				full = v
+			}
+		}
+	}
+	return some, full, ok
+}
+
+// readKeyValueFile reads a procfs file of "key value" or "key: value kB"
+// lines into a map of integers.
+func readKeyValueFile(path string) (map[string]uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]uint64)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if v, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[strings.TrimSuffix(fields[0], ":")] = v
+		}
+	}
+	return values, nil
+}
+
+// swapOnZram reports whether any active swap device is a zram disk.
+func swapOnZram(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 && strings.HasPrefix(filepath.Base(fields[0]), "zram") {
+			return true
+		}
+	}
+	return false
+}
+
+// currentMemoryPressure returns the last sampled memory pressure.
+func (m *Manager) currentMemoryPressure() *MemoryPressure {
+	m.usageMu.RLock()
+	defer m.usageMu.RUnlock()
+	return m.systemUsage.MemoryPressure
+}
+
+// serverMemoryWarning is the warning shown on a running server while the
+// host is under memory pressure, noting how much of it sits in swap.
+func serverMemoryWarning(pressure *MemoryPressure, status string, swapBytes uint64) string {
+	if pressure == nil || pressure.Level == MemoryPressureOK {
+		return ""
+	}
+	if status != "Running" && status != "Booting" {
+		return ""
+	}
+	warning := pressure.Warning
+	if swapBytes > 0 {
+		warning += fmt.Sprintf(" %.0f MB of this server is in swap.", bytesToMB(swapBytes))
+	}
+	return warning
+}
@@ -0,0 +1,66 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFakeProc(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir failed: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s failed: %v", name, err)
+		}
+	}
+}
+
+func TestMemoryPressureSampler(t *testing.T) {
+	root := t.TempDir()
+	writeFakeProc(t, root, map[string]string{
+		"meminfo":         "MemTotal:       8000000 kB\nMemAvailable:    200000 kB\nSwapTotal:      4000000 kB\nSwapFree:       3000000 kB\n",
+		"vmstat":          "pgpgin 1\npswpin 1000\npswpout 1000\n",
+		"swaps":           "Filename\tType\tSize\tUsed\tPriority\n/dev/zram0 partition 4000000 1000000 100\n",
+		"pressure/memory": "some avg10=0.50 avg60=0.20 avg300=0.10 total=1234\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+	})
+	sampler := &memoryPressureSampler{procRoot: root, pageSize: 4096}
+	start := time.Now()
+
+	p := sampler.sample(start)
+	if p == nil || p.Level != MemoryPressureOK || p.Warning != "" {
+		t.Fatalf("expected no pressure on the first sample, got %+v", p)
+	}
+	if p.SwapUsedBytes != 1000000*1024 || !p.Zram || !p.PSIAvailable || p.PSISomeAvg10 != 0.5 {
+		t.Fatalf("unexpected readings: %+v", p)
+	}
+
+	// 5120 pages in over 2 seconds is 10 MB/s of swap-in.
+	writeFakeProc(t, root, map[string]string{
+		"vmstat":          "pswpin 6120\npswpout 1000\n",
+		"pressure/memory": "some avg10=35.00 avg60=20.00 avg300=5.00 total=99999\nfull avg10=4.00 avg60=1.00 avg300=0.50 total=5000\n",
+	})
+	p = sampler.sample(start.Add(2 * time.Second))
+	if p.Level != MemoryPressureCritical || p.SwapInPerSec != 10<<20 {
+		t.Fatalf("expected critical pressure from heavy swap-in, got %+v", p)
+	}
+	if !strings.Contains(p.Warning, "zram swap") || !strings.Contains(p.Warning, "35%") {
+		t.Fatalf("expected the warning to name zram and PSI, got %q", p.Warning)
+	}
+
+	if got := serverMemoryWarning(p, "Running", 300<<20); !strings.Contains(got, "300 MB of this server is in swap") {
+		t.Fatalf("unexpected server warning %q", got)
+	}
+	if got := serverMemoryWarning(p, "Stopped", 0); got != "" {
+		t.Fatalf("expected no warning on a stopped server, got %q", got)
+	}
+
+	if missing := (&memoryPressureSampler{procRoot: filepath.Join(root, "missing")}).sample(start); missing != nil {
+		t.Fatalf("expected nil without procfs, got %+v", missing)
+	}
+}
@@ -0,0 +1,172 @@
+package minecraft
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MessageError is an error with a stable code and the values used to build
+// its text, so API clients can show their own translation instead of the
+// English message.
+type MessageError struct {
+	Code    string
+	Params  map[string]any
+	Message string
+}
+
+func (e *MessageError) Error() string {
+	return e.Message
+}
+
+// Error codes shared by several operations.
+const (
+	MessageServerNotFound   = "server_not_found"
+	MessageServerNotRunning = "server_not_running"
+	MessagePortOutOfRange   = "port_out_of_range"
+	MessagePortTaken        = "port_taken"
+)
+
+func errServerNotFound(id string) error {
+	return &MessageError{
+		Code:    MessageServerNotFound,
+		Params:  map[string]any{"serverId": id},
+		Message: fmt.Sprintf("server %s not found", id),
+	}
+}
+
+func errServerNotRunningStatus(id, status string) error {
+	return &MessageError{
+		Code:    MessageServerNotRunning,
+		Params:  map[string]any{"serverId": id, "status": status},
+		Message: fmt.Sprintf("server %s is not running (status: %s)", id, status),
+	}
+}
+
+func errPortOutOfRange() error {
+	return &MessageError{
+		Code:    MessagePortOutOfRange,
+		Params:  map[string]any{"min": 1024, "max": 65535},
+		Message: "port must be between 1024 and 65535",
+	}
+}
+
+func errPortTaken(port int, serverName string) error {
+	return &MessageError{
+		Code:    MessagePortTaken,
+		Params:  map[string]any{"port": port, "serverName": serverName},
+		Message: fmt.Sprintf("port %d is already in use by server %s", port, serverName),
+	}
+}
+
+// Player-facing broadcast keys. Templates use {name} placeholders.
+const (
+	playerMsgRestartInMinutes = "restart_in_minutes"
+	playerMsgRestartInMinute  = "restart_in_one_minute"
+	playerMsgRestartInSeconds = "restart_in_seconds"
+	playerMsgRestartNow       = "restart_now"
+	playerMsgRestartCancelled = "restart_cancelled"
+	playerMsgStopInSeconds    = "stop_in_seconds"
+	playerMsgStopNow          = "stop_now"
+)
+
+const defaultLocale = "en"
+
+// playerMessages holds the in-game broadcast text per locale. English is the
+// fallback for any key a locale does not define.
+var playerMessages = map[string]map[string]string{
+	"en": {
+		playerMsgRestartInMinutes: "Server restarting in {minutes} minutes...",
+		playerMsgRestartInMinute:  "Server restarting in 1 minute...",
+		playerMsgRestartInSeconds: "Server restarting in {seconds} seconds...",
+		playerMsgRestartNow:       "Server restarting now!",
+		playerMsgRestartCancelled: "Scheduled restart cancelled.",
+		playerMsgStopInSeconds:    "Server stopping in {seconds} seconds...",
+		playerMsgStopNow:          "Server stopping now!",
+	},
+	"es": {
+		playerMsgRestartInMinutes: "El servidor se reiniciará en {minutes} minutos...",
+		playerMsgRestartInMinute:  "El servidor se reiniciará en 1 minuto...",
+		playerMsgRestartInSeconds: "El servidor se reiniciará en {seconds} segundos...",
+		playerMsgRestartNow:       "¡Reiniciando el servidor ahora!",
+		playerMsgRestartCancelled: "Reinicio programado cancelado.",
+		playerMsgStopInSeconds:    "El servidor se detendrá en {seconds} segundos...",
+		playerMsgStopNow:          "¡Deteniendo el servidor ahora!",
+	},
+	"pt": {
+		playerMsgRestartInMinutes: "O servidor será reiniciado em {minutes} minutos...",
+		playerMsgRestartInMinute:  "O servidor será reiniciado em 1 minuto...",
+		playerMsgRestartInSeconds: "O servidor será reiniciado em {seconds} segundos...",
+		playerMsgRestartNow:       "Reiniciando o servidor agora!",
+		playerMsgRestartCancelled: "Reinício agendado cancelado.",
+		playerMsgStopInSeconds:    "O servidor será desligado em {seconds} segundos...",
+		playerMsgStopNow:          "Desligando o servidor agora!",
+	},
+	"de": {
+		playerMsgRestartInMinutes: "Server startet in {minutes} Minuten neu...",
+		playerMsgRestartInMinute:  "Server startet in 1 Minute neu...",
+		playerMsgRestartInSeconds: "Server startet in {seconds} Sekunden neu...",
+		playerMsgRestartNow:       "Server startet jetzt neu!",
+		playerMsgRestartCancelled: "Geplanter Neustart abgebrochen.",
+		playerMsgStopInSeconds:    "Server stoppt in {seconds} Sekunden...",
+		playerMsgStopNow:          "Server stoppt jetzt!",
+	},
+	"fr": {
+		playerMsgRestartInMinutes: "Redémarrage du serveur dans {minutes} minutes...",
+		playerMsgRestartInMinute:  "Redémarrage du serveur dans 1 minute...",
+		playerMsgRestartInSeconds: "Redémarrage du serveur dans {seconds} secondes...",
+		playerMsgRestartNow:       "Redémarrage du serveur maintenant !",
+		playerMsgRestartCancelled: "Redémarrage programmé annulé.",
+		playerMsgStopInSeconds:    "Arrêt du serveur dans {seconds} secondes...",
+		playerMsgStopNow:          "Arrêt du serveur maintenant !",
+	},
+}
+
+// SupportedLocales lists the locales with player message translations.
+func SupportedLocales() []string {
+	locales := make([]string, 0, len(playerMessages))
+	for locale := range playerMessages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// normalizeLocale maps values like "es-MX" or "PT_br" to a supported base
+// locale, or "" when there is no translation for it.
+func normalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	if _, ok := playerMessages[locale]; !ok {
+		return ""
+	}
+	return locale
+}
+
+// formatMessageTemplate fills {name} placeholders from params. Unknown
+// placeholders are left as written.
+func formatMessageTemplate(template string, params map[string]any) string {
+	if len(params) == 0 {
+		return template
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// playerMessage renders a broadcast in the panel's configured locale.
+func (m *Manager) playerMessage(key string, params map[string]any) string {
+	m.settingsMu.RLock()
+	locale := normalizeLocale(m.settings.Locale)
+	m.settingsMu.RUnlock()
+
+	template, ok := playerMessages[locale][key]
+	if !ok {
+		template = playerMessages[defaultLocale][key]
+	}
+	return formatMessageTemplate(template, params)
+}
@@ -0,0 +1,54 @@
+package minecraft
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPlayerMessageUsesConfiguredLocale(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	if got := mgr.playerMessage(playerMsgRestartInMinutes, map[string]any{"minutes": 5}); got != "Server restarting in 5 minutes..." {
+		t.Fatalf("unexpected default message %q", got)
+	}
+
+	if _, _, err := mgr.UpdateAppSettings(AppSettingsUpdate{DefaultMinRAM: "0.5", DefaultMaxRAM: "1", DefaultFlags: "none", Locale: "xx"}); err == nil {
+		t.Fatalf("expected unsupported locale to be rejected")
+	}
+	settings, _, err := mgr.UpdateAppSettings(AppSettingsUpdate{DefaultMinRAM: "0.5", DefaultMaxRAM: "1", DefaultFlags: "none", Locale: "es-MX"})
+	if err != nil {
+		t.Fatalf("UpdateAppSettings failed: %v", err)
+	}
+	if settings.Locale != "es" {
+		t.Fatalf("expected locale es, got %q", settings.Locale)
+	}
+	if got := mgr.playerMessage(playerMsgStopInSeconds, map[string]any{"seconds": 10}); got != "El servidor se detendrá en 10 segundos..." {
+		t.Fatalf("unexpected Spanish message %q", got)
+	}
+
+	// An empty locale keeps the current one.
+	if settings, _, _ := mgr.UpdateAppSettings(AppSettingsUpdate{DefaultMinRAM: "0.5", DefaultMaxRAM: "1", DefaultFlags: "none"}); settings.Locale != "es" {
+		t.Fatalf("expected locale to be kept, got %q", settings.Locale)
+	}
+}
+
+func TestServerNotFoundCarriesCode(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	err = mgr.StartServer("missing")
+	var msgErr *MessageError
+	if !errors.As(err, &msgErr) || msgErr.Code != MessageServerNotFound || msgErr.Params["serverId"] != "missing" {
+		t.Fatalf("expected a coded not-found error, got %#v", err)
+	}
+	if err.Error() != "server missing not found" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+}
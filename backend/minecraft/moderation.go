@@ -0,0 +1,240 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Moderation actions recorded in the audit log.
+const (
+	ModerationKick    = "kick"
+	ModerationBan     = "ban"
+	ModerationTempBan = "tempban"
+	ModerationPardon  = "pardon"
+	ModerationSend    = "send"
+)
+
+const (
+	maxModerationPresets     = 50
+	maxModerationReason      = 200
+	maxModerationLogEntries  = 1000
+	defaultModerationLogSize = 100
+	// moderationSystemActor is the actor recorded for actions the panel
+	// takes by itself, such as lifting an expired temporary ban.
+	moderationSystemActor = "panel"
+)
+
+var slugUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ModerationPreset is a reusable kick or ban reason shared by the whole
+// panel, so every moderator words the same offence the same way.
+type ModerationPreset struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// ModerationEvent is one entry in the moderation audit log.
+type ModerationEvent struct {
+	Time       string `json:"time"`
+	ServerID   string `json:"serverId"`
+	ServerName string `json:"serverName,omitempty"`
+	Actor      string `json:"actor,omitempty"`
+	Action     string `json:"action"`
+	Player     string `json:"player"`
+	Reason     string `json:"reason,omitempty"`
+	Preset     string `json:"preset,omitempty"`
+	ExpiresAt  string `json:"expiresAt,omitempty"`
+}
+
+// moderationDocument is the stored form of presets and the audit log. A
+// nil Presets means the defaults were never changed.
+type moderationDocument struct {
+	Presets []ModerationPreset `json:"presets"`
+	Log     []ModerationEvent  `json:"log"`
+}
+
+func defaultModerationPresets() []ModerationPreset {
+	return []ModerationPreset{
+		{ID: "afk-farming", Reason: "AFK farming"},
+		{ID: "spam", Reason: "Spam"},
+		{ID: "griefing", Reason: "Griefing – see Discord"},
+	}
+}
+
+// slugID turns a display name into a lowercase ID of letters, digits and
+// dashes.
+func slugID(name string) string {
+	return strings.Trim(slugUnsafe.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// normalizeModerationPresets trims the presets, derives missing IDs from the
+// reason and rejects duplicates.
+func normalizeModerationPresets(presets []ModerationPreset) ([]ModerationPreset, error) {
+	if len(presets) > maxModerationPresets {
+		return nil, fmt.Errorf("at most %d presets are allowed", maxModerationPresets)
+	}
+	out := make([]ModerationPreset, 0, len(presets))
+	seen := make(map[string]bool, len(presets))
+	for i, p := range presets {
+		p.Reason = strings.TrimSpace(p.Reason)
+		if p.Reason == "" {
+			return nil, fmt.Errorf("preset %d has no reason", i+1)
+		}
+		if len(p.Reason) > maxModerationReason || strings.ContainsAny(p.Reason, "\r\n") {
+			return nil, fmt.Errorf("preset %d must be a single line of at most %d characters", i+1, maxModerationReason)
+		}
+		p.ID = slugID(p.ID)
+		if p.ID == "" {
+			p.ID = slugID(p.Reason)
+		}
+		if p.ID == "" {
+			return nil, fmt.Errorf("preset %d needs an ID", i+1)
+		}
+		if seen[p.ID] {
+			return nil, fmt.Errorf("duplicate preset %q", p.ID)
+		}
+		seen[p.ID] = true
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// loadModerationLocked reads the moderation document. Callers hold
+// m.moderationMu.
+func (m *Manager) loadModerationLocked() (moderationDocument, error) {
+	var doc moderationDocument
+	data, err := m.storage().Load(storeDocModeration)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return doc, nil
+		}
+		return doc, fmt.Errorf("failed to read moderation data: %w", err)
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return doc, fmt.Errorf("failed to parse moderation data: %w", err)
+	}
+	return doc, nil
+}
+
+func (m *Manager) saveModerationLocked(doc moderationDocument) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return m.storage().Save(storeDocModeration, data)
+}
+
+// GetModerationPresets returns the panel's kick and ban reason presets.
+func (m *Manager) GetModerationPresets() ([]ModerationPreset, error) {
+	m.moderationMu.Lock()
+	defer m.moderationMu.Unlock()
+	doc, err := m.loadModerationLocked()
+	if err != nil {
+		return nil, err
+	}
+	if doc.Presets == nil {
+		return defaultModerationPresets(), nil
+	}
+	return doc.Presets, nil
+}
+
+// SetModerationPresets replaces the panel's reason presets.
+func (m *Manager) SetModerationPresets(presets []ModerationPreset) ([]ModerationPreset, error) {
+	presets, err := normalizeModerationPresets(presets)
+	if err != nil {
+		return nil, err
+	}
+	m.moderationMu.Lock()
+	defer m.moderationMu.Unlock()
+	doc, err := m.loadModerationLocked()
+	if err != nil {
+		return nil, err
+	}
+	doc.Presets = presets
+	if err := m.saveModerationLocked(doc); err != nil {
+		return nil, err
+	}
+	return presets, nil
+}
+
+// ResolveModerationReason turns a preset and an optional free-text detail
+// into the reason sent to the server. Without a preset the detail is the
+// reason; with one, the detail is appended to the preset's text.
+func (m *Manager) ResolveModerationReason(presetID, detail string) (string, error) {
+	detail = strings.TrimSpace(detail)
+	presetID = strings.TrimSpace(presetID)
+	if presetID == "" {
+		return detail, nil
+	}
+	presets, err := m.GetModerationPresets()
+	if err != nil {
+		return "", err
+	}
+	for _, p := range presets {
+		if p.ID == presetID {
+			if detail == "" {
+				return p.Reason, nil
+			}
+			return p.Reason + " - " + detail, nil
+		}
+	}
+	return "", fmt.Errorf("unknown reason preset %q", presetID)
+}
+
+// RecordModeration appends an event to the audit log, dropping the oldest
+// entries past maxModerationLogEntries. Failures are logged, never returned,
+// so a full disk cannot undo a kick that already happened.
+func (m *Manager) RecordModeration(event ModerationEvent) {
+	if event.Time == "" {
+		event.Time = time.Now().UTC().Format(time.RFC3339)
+	}
+	if event.ServerName == "" {
+		m.mu.RLock()
+		if cfg, ok := m.configs[event.ServerID]; ok {
+			event.ServerName = cfg.Name
+		}
+		m.mu.RUnlock()
+	}
+
+	m.moderationMu.Lock()
+	defer m.moderationMu.Unlock()
+	doc, err := m.loadModerationLocked()
+	if err != nil {
+		log.Printf("Failed to record moderation event: %v", err)
+		return
+	}
+	doc.Log = append(doc.Log, event)
+	if len(doc.Log) > maxModerationLogEntries {
+		doc.Log = append([]ModerationEvent(nil), doc.Log[len(doc.Log)-maxModerationLogEntries:]...)
+	}
+	if err := m.saveModerationLocked(doc); err != nil {
+		log.Printf("Failed to record moderation event: %v", err)
+	}
+}
+
+// ModerationLog returns up to limit audit log entries, newest first,
+// optionally for one server only.
+func (m *Manager) ModerationLog(serverID string, limit int) ([]ModerationEvent, error) {
+	if limit <= 0 || limit > maxModerationLogEntries {
+		limit = defaultModerationLogSize
+	}
+	m.moderationMu.Lock()
+	doc, err := m.loadModerationLocked()
+	m.moderationMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	events := make([]ModerationEvent, 0, limit)
+	for i := len(doc.Log) - 1; i >= 0 && len(events) < limit; i-- {
+		if serverID == "" || doc.Log[i].ServerID == serverID {
+			events = append(events, doc.Log[i])
+		}
+	}
+	return events, nil
+}
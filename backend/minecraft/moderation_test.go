@@ -0,0 +1,83 @@
+package minecraft
+
+import "testing"
+
+func TestModerationPresetsAndReasons(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	presets, err := mgr.GetModerationPresets()
+	if err != nil || len(presets) != 3 || presets[0].ID != "afk-farming" {
+		t.Fatalf("expected the default presets, got %+v (%v)", presets, err)
+	}
+
+	if _, err := mgr.SetModerationPresets([]ModerationPreset{{Reason: "Spam"}, {ID: "spam", Reason: "Chat spam"}}); err == nil {
+		t.Fatalf("expected duplicate preset IDs to be refused")
+	}
+	if _, err := mgr.SetModerationPresets([]ModerationPreset{{Reason: "  "}}); err == nil {
+		t.Fatalf("expected an empty reason to be refused")
+	}
+	saved, err := mgr.SetModerationPresets([]ModerationPreset{{Reason: "Hacked client"}, {ID: "Ads", Reason: "Advertising other servers"}})
+	if err != nil {
+		t.Fatalf("SetModerationPresets failed: %v", err)
+	}
+	if saved[0].ID != "hacked-client" || saved[1].ID != "ads" {
+		t.Fatalf("unexpected preset IDs: %+v", saved)
+	}
+
+	cases := []struct {
+		preset, detail, want string
+	}{
+		{"", " griefed spawn ", "griefed spawn"},
+		{"ads", "", "Advertising other servers"},
+		{"hacked-client", "fly hack", "Hacked client - fly hack"},
+	}
+	for _, c := range cases {
+		if got, err := mgr.ResolveModerationReason(c.preset, c.detail); err != nil || got != c.want {
+			t.Fatalf("ResolveModerationReason(%q, %q) = %q, %v; want %q", c.preset, c.detail, got, err, c.want)
+		}
+	}
+	if _, err := mgr.ResolveModerationReason("spam", ""); err == nil {
+		t.Fatalf("expected a removed preset to be refused")
+	}
+}
+
+func TestModerationLogFiltersAndCaps(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	mgr.mu.Lock()
+	mgr.configs["lobby"] = &ServerConfig{ID: "lobby", Name: "Lobby"}
+	mgr.mu.Unlock()
+
+	mgr.RecordModeration(ModerationEvent{ServerID: "lobby", Actor: "alice", Action: ModerationKick, Player: "Steve", Reason: "Spam", Preset: "spam"})
+	mgr.RecordModeration(ModerationEvent{ServerID: "survival", Actor: "bob", Action: ModerationBan, Player: "Alex"})
+	mgr.RecordModeration(ModerationEvent{ServerID: "lobby", Actor: moderationSystemActor, Action: ModerationPardon, Player: "Herobrine"})
+
+	events, err := mgr.ModerationLog("lobby", 0)
+	if err != nil {
+		t.Fatalf("ModerationLog failed: %v", err)
+	}
+	if len(events) != 2 || events[0].Player != "Herobrine" || events[1].Player != "Steve" {
+		t.Fatalf("expected lobby events newest first, got %+v", events)
+	}
+	if events[1].ServerName != "Lobby" || events[1].Preset != "spam" || events[1].Time == "" {
+		t.Fatalf("unexpected event: %+v", events[1])
+	}
+	if all, _ := mgr.ModerationLog("", 1); len(all) != 1 || all[0].Player != "Herobrine" {
+		t.Fatalf("expected the limit to apply, got %+v", all)
+	}
+
+	for i := 0; i < maxModerationLogEntries; i++ {
+		mgr.RecordModeration(ModerationEvent{ServerID: "survival", Action: ModerationKick, Player: "Alex"})
+	}
+	if events, _ := mgr.ModerationLog("lobby", 0); len(events) != 0 {
+		t.Fatalf("expected the oldest entries to be dropped, got %d lobby events", len(events))
+	}
+}
@@ -0,0 +1,695 @@
+package minecraft
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Modpack sources.
+const (
+	ModpackSourceModrinth   = "modrinth"
+	ModpackSourceCurseForge = "curseforge"
+)
+
+const (
+	modrinthIndexName      = "modrinth.index.json"
+	curseForgeManifestName = "manifest.json"
+	modpackDownloadWorkers = 4
+)
+
+// ModpackInfo records the modpack a server was installed from.
+type ModpackInfo struct {
+	Source        string `json:"source"`
+	Name          string `json:"name"`
+	Version       string `json:"version,omitempty"`
+	Loader        string `json:"loader"`
+	LoaderVersion string `json:"loaderVersion"`
+	// ProjectID and FileID identify a pack installed by CurseForge ID.
+	ProjectID int `json:"projectId,omitempty"`
+	FileID    int `json:"fileId,omitempty"`
+}
+
+// ModpackInstallOptions are the settings of a server created from a
+// modpack. Empty values take the panel's new-server defaults, and an
+// empty name takes the pack's.
+type ModpackInstallOptions struct {
+	Name           string
+	Port           int
+	MinRAM         string
+	MaxRAM         string
+	MaxPlayers     int
+	Flags          string
+	AlwaysPreTouch bool
+}
+
+// modpackFile is one file a pack downloads into the server.
+type modpackFile struct {
+	path   string
+	urls   []string
+	sha1   string
+	sha512 string
+	// CurseForge packs list project and file IDs, resolved to a file name
+	// and URL through the CurseForge API during the install.
+	curseForgeProjectID int
+	curseForgeFileID    int
+}
+
+// modpackPlan is what a pack's manifest asks for: a loader, the files to
+// download and the archive folders copied over the server.
+type modpackPlan struct {
+	info       ModpackInfo
+	mcVersion  string
+	serverType string
+	files      []modpackFile
+	overrides  []string
+	// skipped counts client-only files left out of the server.
+	skipped int
+}
+
+// readModpackPlan opens a Modrinth .mrpack or a CurseForge pack zip and
+// reads its manifest.
+func readModpackPlan(archivePath string) (*modpackPlan, error) {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("modpack is not a zip archive: %w", err)
+	}
+	defer zr.Close()
+
+	if data, err := readZipEntry(&zr.Reader, modrinthIndexName); err == nil {
+		return parseModrinthIndex(data)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if data, err := readZipEntry(&zr.Reader, curseForgeManifestName); err == nil {
+		return parseCurseForgeManifest(data)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return nil, fmt.Errorf("archive has neither %s nor %s; is it a Modrinth or CurseForge modpack?", modrinthIndexName, curseForgeManifestName)
+}
+
+func readZipEntry(zr *zip.Reader, name string) ([]byte, error) {
+	f, err := zr.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, 16<<20))
+}
+
+type modrinthIndex struct {
+	FormatVersion int    `json:"formatVersion"`
+	Game          string `json:"game"`
+	VersionID     string `json:"versionId"`
+	Name          string `json:"name"`
+	Files         []struct {
+		Path   string            `json:"path"`
+		Hashes map[string]string `json:"hashes"`
+		Env    *struct {
+			Server string `json:"server"`
+		} `json:"env"`
+		Downloads []string `json:"downloads"`
+	} `json:"files"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+func parseModrinthIndex(data []byte) (*modpackPlan, error) {
+	var index modrinthIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", modrinthIndexName, err)
+	}
+	if index.FormatVersion != 1 || index.Game != "minecraft" {
+		return nil, fmt.Errorf("unsupported Modrinth pack (format %d, game %q)", index.FormatVersion, index.Game)
+	}
+	plan := &modpackPlan{
+		info: ModpackInfo{
+			Source:  ModpackSourceModrinth,
+			Name:    strings.TrimSpace(index.Name),
+			Version: strings.TrimSpace(index.VersionID),
+		},
+		mcVersion: strings.TrimSpace(index.Dependencies["minecraft"]),
+		// server-overrides are applied after, and win over, overrides.
+		overrides: []string{"overrides", "server-overrides"},
+	}
+	for _, loader := range []string{"forge", "neoforge", "fabric-loader", "quilt-loader"} {
+		if version := strings.TrimSpace(index.Dependencies[loader]); version != "" {
+			plan.info.Loader, plan.info.LoaderVersion = loader, version
+			break
+		}
+	}
+	if err := plan.resolveServerType(); err != nil {
+		return nil, err
+	}
+
+	for _, f := range index.Files {
+		if f.Env != nil && f.Env.Server == "unsupported" {
+			plan.skipped++
+			continue
+		}
+		rel, err := modpackFilePath(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		file := modpackFile{path: rel, urls: f.Downloads, sha1: strings.ToLower(f.Hashes["sha1"]), sha512: strings.ToLower(f.Hashes["sha512"])}
+		if file.sha1 == "" && file.sha512 == "" {
+			return nil, fmt.Errorf("pack file %s has no hash", f.Path)
+		}
+		if len(file.urls) == 0 {
+			return nil, fmt.Errorf("pack file %s has no download", f.Path)
+		}
+		plan.files = append(plan.files, file)
+	}
+	return plan, nil
+}
+
+type curseForgeManifest struct {
+	ManifestType string `json:"manifestType"`
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	Minecraft    struct {
+		Version    string `json:"version"`
+		ModLoaders []struct {
+			ID      string `json:"id"`
+			Primary bool   `json:"primary"`
+		} `json:"modLoaders"`
+	} `json:"minecraft"`
+	Files []struct {
+		ProjectID int  `json:"projectID"`
+		FileID    int  `json:"fileID"`
+		Required  bool `json:"required"`
+	} `json:"files"`
+	Overrides string `json:"overrides"`
+}
+
+func parseCurseForgeManifest(data []byte) (*modpackPlan, error) {
+	var manifest curseForgeManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", curseForgeManifestName, err)
+	}
+	if manifest.ManifestType != "minecraftModpack" {
+		return nil, fmt.Errorf("unsupported CurseForge manifest type %q", manifest.ManifestType)
+	}
+	plan := &modpackPlan{
+		info: ModpackInfo{
+			Source:  ModpackSourceCurseForge,
+			Name:    strings.TrimSpace(manifest.Name),
+			Version: strings.TrimSpace(manifest.Version),
+		},
+		mcVersion: strings.TrimSpace(manifest.Minecraft.Version),
+		overrides: []string{"overrides"},
+	}
+	if overrides := strings.TrimSpace(manifest.Overrides); overrides != "" {
+		plan.overrides = []string{overrides}
+	}
+	// Loader IDs look like "forge-47.2.0" or "fabric-0.15.7".
+	for _, loader := range manifest.Minecraft.ModLoaders {
+		name, version, ok := strings.Cut(strings.TrimSpace(loader.ID), "-")
+		if !ok {
+			continue
+		}
+		if plan.info.Loader == "" || loader.Primary {
+			plan.info.Loader, plan.info.LoaderVersion = strings.ToLower(name), version
+		}
+	}
+	if err := plan.resolveServerType(); err != nil {
+		return nil, err
+	}
+	for _, f := range manifest.Files {
+		if !f.Required {
+			plan.skipped++
+			continue
+		}
+		if f.ProjectID <= 0 || f.FileID <= 0 {
+			return nil, fmt.Errorf("pack lists an invalid CurseForge file (project %d, file %d)", f.ProjectID, f.FileID)
+		}
+		plan.files = append(plan.files, modpackFile{curseForgeProjectID: f.ProjectID, curseForgeFileID: f.FileID})
+	}
+	return plan, nil
+}
+
+// resolveServerType maps the pack's loader to a server type.
+func (p *modpackPlan) resolveServerType() error {
+	if p.mcVersion == "" {
+		return fmt.Errorf("modpack does not say which Minecraft version it is for")
+	}
+	switch p.info.Loader {
+	case "forge":
+		p.serverType = "Forge"
+		// Some packs spell the build as "1.20.1-47.2.0".
+		p.info.LoaderVersion = strings.TrimPrefix(p.info.LoaderVersion, p.mcVersion+"-")
+	case "neoforge":
+		p.serverType = "NeoForge"
+	case "fabric", "fabric-loader":
+		p.info.Loader = "fabric"
+		p.serverType = "Fabric"
+	case "quilt", "quilt-loader":
+		return fmt.Errorf("Quilt modpacks are not supported")
+	case "":
+		return fmt.Errorf("modpack does not name a mod loader")
+	default:
+		return fmt.Errorf("unsupported mod loader %q", p.info.Loader)
+	}
+	if p.info.LoaderVersion == "" {
+		return fmt.Errorf("modpack does not say which %s version it needs", p.serverType)
+	}
+	if p.info.Name == "" {
+		p.info.Name = "Modpack"
+	}
+	return nil
+}
+
+// modpackFilePath checks that a path from a pack stays inside the server
+// directory.
+func modpackFilePath(raw string) (string, error) {
+	rel, err := sanitizeArchiveEntryPath(raw)
+	if err != nil {
+		return "", err
+	}
+	if rel == "" {
+		return "", fmt.Errorf("pack file has an empty path")
+	}
+	return rel, nil
+}
+
+// installModpackLoader installs the exact loader build the pack asks for.
+func installModpackLoader(ctx context.Context, plan *modpackPlan, destDir, javaExec string, progressFn func(string)) error {
+	switch plan.serverType {
+	case "Forge":
+		return installForgeBuild(ctx, plan.mcVersion, plan.info.LoaderVersion, destDir, javaExec, progressFn)
+	case "NeoForge":
+		return installNeoForgeVersion(ctx, plan.info.LoaderVersion, destDir, javaExec, progressFn)
+	case "Fabric":
+		return downloadFabricServerJar(ctx, plan.mcVersion, plan.info.LoaderVersion, destDir, progressFn)
+	}
+	return fmt.Errorf("unsupported server type %s", plan.serverType)
+}
+
+// downloadModpackFiles downloads the pack's files into destDir, checking
+// each against the pack's hash before it is moved into place.
+func downloadModpackFiles(ctx context.Context, files []modpackFile, destDir string, progressFn func(string)) error {
+	baseAbs, err := filepath.Abs(filepath.Clean(destDir))
+	if err != nil {
+		return err
+	}
+	maxBytes := maxPluginUpdateBytesFromEnv()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	work := make(chan modpackFile)
+	var (
+		mu       sync.Mutex
+		firstErr error
+		done     int
+		wg       sync.WaitGroup
+	)
+	for i := 0; i < modpackDownloadWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range work {
+				err := downloadModpackFile(ctx, file, baseAbs, maxBytes)
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				if err == nil {
+					done++
+					if done%10 == 0 || done == len(files) {
+						progressFn(fmt.Sprintf("Downloaded %d/%d pack files", done, len(files)))
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for _, file := range files {
+		select {
+		case work <- file:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+func downloadModpackFile(ctx context.Context, file modpackFile, baseAbs string, maxBytes int64) error {
+	target := filepath.Join(baseAbs, filepath.FromSlash(file.path))
+	if err := ensurePathWithinBase(baseAbs, target); err != nil {
+		return fmt.Errorf("pack file %s escapes the server directory", file.path)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	tmpPath := target + ".part"
+	var lastErr error
+	for _, url := range file.urls {
+		if _, err := secureDownloadPluginUpdate(ctx, url, tmpPath, maxBytes); err != nil {
+			lastErr = err
+			continue
+		}
+		if err := verifyModpackFileHash(tmpPath, file); err != nil {
+			_ = os.Remove(tmpPath)
+			lastErr = err
+			continue
+		}
+		if err := os.Rename(tmpPath, target); err != nil {
+			_ = os.Remove(tmpPath)
+			return err
+		}
+		return nil
+	}
+	return fmt.Errorf("failed to download %s: %w", file.path, lastErr)
+}
+
+// verifyModpackFileHash checks a download against the strongest hash the
+// pack gives for it.
+func verifyModpackFileHash(path string, file modpackFile) error {
+	var h hash.Hash
+	want := file.sha512
+	if want != "" {
+		h = sha512.New()
+	} else {
+		h, want = sha1.New(), file.sha1
+	}
+	if want == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		return fmt.Errorf("%s does not match the pack's hash", file.path)
+	}
+	return nil
+}
+
+// extractModpackOverrides copies an archive folder, such as overrides/,
+// over destDir and returns how many files it wrote.
+func extractModpackOverrides(archivePath, folder, destDir string) (int, error) {
+	baseAbs, err := filepath.Abs(filepath.Clean(destDir))
+	if err != nil {
+		return 0, err
+	}
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return 0, err
+	}
+	defer zr.Close()
+
+	prefix := strings.Trim(path.Clean(folder), "/") + "/"
+	written := 0
+	for _, f := range zr.File {
+		name, err := sanitizeArchiveEntryPath(f.Name)
+		if err != nil {
+			return written, err
+		}
+		rel, ok := strings.CutPrefix(name, prefix)
+		if !ok || rel == "" || f.FileInfo().IsDir() {
+			continue
+		}
+		if f.Mode()&os.ModeSymlink != 0 {
+			return written, fmt.Errorf("file symlinks are not supported")
+		}
+		target := filepath.Join(baseAbs, filepath.FromSlash(rel))
+		if err := ensurePathWithinBase(baseAbs, target); err != nil {
+			return written, fmt.Errorf("file entry escapes extraction root")
+		}
+		in, err := f.Open()
+		if err != nil {
+			return written, err
+		}
+		writeErr := writeArchiveFile(target, in, 0644)
+		closeErr := in.Close()
+		if writeErr != nil {
+			return written, writeErr
+		}
+		if closeErr != nil {
+			return written, closeErr
+		}
+		written++
+	}
+	return written, nil
+}
+
+// modpackArchivePath is where a server's pack waits until its install
+// succeeds, so a failed install can be retried.
+func (m *Manager) modpackArchivePath(id string) string {
+	return filepath.Join(m.importsRoot, "modpack-"+id+".zip")
+}
+
+// CreateModpackServer creates a server from an uploaded Modrinth .mrpack
+// or CurseForge pack zip and installs it in the background.
+func (m *Manager) CreateModpackServer(opts ModpackInstallOptions, src io.Reader) (*ServerInfo, error) {
+	if src == nil {
+		return nil, fmt.Errorf("no file data provided")
+	}
+	staged, err := os.CreateTemp(m.importsRoot, "modpack-upload-*.zip")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	stagedPath := staged.Name()
+	defer os.Remove(stagedPath)
+	_, copyErr := io.Copy(staged, src)
+	closeErr := staged.Close()
+	if copyErr != nil {
+		return nil, fmt.Errorf("failed to save modpack: %w", copyErr)
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to save modpack: %w", closeErr)
+	}
+	return m.createModpackServer(opts, stagedPath, nil)
+}
+
+// CreateCurseForgeModpackServer creates a server from a CurseForge modpack
+// project, using fileID or, when it is zero, the project's main file. The
+// pack archive is fetched before the server is created, so a pack that
+// cannot be installed is refused up front.
+func (m *Manager) CreateCurseForgeModpackServer(ctx context.Context, opts ModpackInstallOptions, projectID, fileID int) (*ServerInfo, error) {
+	client, err := newCurseForgeClient()
+	if err != nil {
+		return nil, err
+	}
+	pack, err := client.packFile(ctx, projectID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	staged, err := os.CreateTemp(m.importsRoot, "modpack-upload-*.zip")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	stagedPath := staged.Name()
+	staged.Close()
+	defer os.Remove(stagedPath)
+	if _, err := secureDownloadPluginUpdate(ctx, pack.url, stagedPath, maxPluginUpdateBytesFromEnv()); err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", pack.fileName, err)
+	}
+	return m.createModpackServer(opts, stagedPath, func(info *ModpackInfo) {
+		info.ProjectID, info.FileID = projectID, pack.fileID
+		if pack.projectName != "" {
+			info.Name = pack.projectName
+		}
+	})
+}
+
+func (m *Manager) createModpackServer(opts ModpackInstallOptions, stagedPath string, describe func(*ModpackInfo)) (*ServerInfo, error) {
+	plan, err := readModpackPlan(stagedPath)
+	if err != nil {
+		return nil, err
+	}
+	if plan.info.Source == ModpackSourceCurseForge && len(plan.files) > 0 {
+		if _, err := newCurseForgeClient(); err != nil {
+			return nil, err
+		}
+	}
+	if describe != nil {
+		describe(&plan.info)
+	}
+	name := strings.TrimSpace(opts.Name)
+	if name == "" {
+		name = plan.info.Name
+	}
+
+	cfg, err := m.createServer(name, plan.serverType, plan.mcVersion, opts.Port, opts.MinRAM, opts.MaxRAM, opts.MaxPlayers, opts.Flags, opts.AlwaysPreTouch, func(cfg *ServerConfig) error {
+		info := plan.info
+		cfg.Modpack = &info
+		return moveFile(stagedPath, m.modpackArchivePath(cfg.ID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	go m.installModpack(cfg.ID)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.serverInfo(cfg.ID), nil
+}
+
+// moveFile renames src to dst, copying when they are on different devices.
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil || !isCrossDeviceErr(err) {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := writeArchiveFile(dst, in, 0644); err != nil {
+		_ = os.Remove(dst)
+		return err
+	}
+	return os.Remove(src)
+}
+
+// installModpack installs a modpack server's loader, downloads its files
+// and copies its overrides, logging progress to the console like
+// installServerJar.
+func (m *Manager) installModpack(id string) {
+	m.mu.RLock()
+	cfg := m.configs[id]
+	rs := m.running[id]
+	m.mu.RUnlock()
+
+	if cfg == nil || rs == nil || cfg.Modpack == nil {
+		return
+	}
+
+	job := m.newJob(JobTypeInstall, id)
+	release, err := m.acquireServerOperation(job.ctx, id, operationInstall)
+	if err != nil {
+		rs.mu.Lock()
+		rs.status = "Error"
+		rs.installError = "Installation cancelled"
+		rs.mu.Unlock()
+		job.finish(err)
+		return
+	}
+	defer release()
+	job.start(fmt.Sprintf("Installing modpack %s", cfg.Modpack.Name))
+
+	fail := func(msg string) {
+		rs.mu.Lock()
+		rs.status = "Error"
+		rs.installError = msg
+		rs.mu.Unlock()
+		log.Printf("[%s] Modpack install failed: %s", cfg.Name, msg)
+		job.finish(errors.New(msg))
+	}
+
+	progressFn := func(msg string) {
+		log.Printf("[%s] Install: %s", cfg.Name, msg)
+		entry := m.appendLog(rs, fmt.Sprintf("[Installer] %s", msg))
+		m.broadcastLog(rs, entry)
+		job.log(msg)
+	}
+
+	archivePath := m.modpackArchivePath(id)
+	plan, err := readModpackPlan(archivePath)
+	if err != nil {
+		fail(fmt.Sprintf("Failed to read modpack: %v", err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(job.ctx, 60*time.Minute)
+	defer cancel()
+
+	javaExec, javaRequired, javaSelected, javaErr := m.javaResolver.resolve(plan.serverType, plan.mcVersion)
+	if javaErr != nil {
+		fail(fmt.Sprintf("Java compatibility error: %v", javaErr))
+		return
+	}
+	log.Printf("[%s] Java selected for install: required=%d selected=%d exec=%s", cfg.Name, javaRequired, javaSelected, javaExec)
+
+	progressFn(fmt.Sprintf("Installing %s %s for Minecraft %s (%s %s)", plan.info.Name, plan.info.Version, plan.mcVersion, plan.serverType, plan.info.LoaderVersion))
+	if err := installModpackLoader(ctx, plan, cfg.Dir, javaExec, progressFn); err != nil {
+		fail(fmt.Sprintf("Loader install failed: %v", err))
+		return
+	}
+
+	files := plan.files
+	if plan.info.Source == ModpackSourceCurseForge && len(files) > 0 {
+		client, err := newCurseForgeClient()
+		if err != nil {
+			fail(err.Error())
+			return
+		}
+		progressFn(fmt.Sprintf("Resolving %d CurseForge files...", len(files)))
+		resolved, skipped, err := client.resolveFiles(ctx, files)
+		if err != nil {
+			fail(fmt.Sprintf("Failed to resolve CurseForge files: %v", err))
+			return
+		}
+		files = resolved
+		plan.skipped += skipped
+	}
+	if plan.skipped > 0 {
+		progressFn(fmt.Sprintf("Skipping %d client-only or optional files", plan.skipped))
+	}
+	progressFn(fmt.Sprintf("Downloading %d pack files...", len(files)))
+	if err := downloadModpackFiles(ctx, files, cfg.Dir, progressFn); err != nil {
+		fail(fmt.Sprintf("Download failed: %v", err))
+		return
+	}
+
+	for _, folder := range plan.overrides {
+		written, err := extractModpackOverrides(archivePath, folder, cfg.Dir)
+		if err != nil {
+			fail(fmt.Sprintf("Failed to copy %s: %v", folder, err))
+			return
+		}
+		if written > 0 {
+			progressFn(fmt.Sprintf("Copied %d files from %s/", written, folder))
+		}
+	}
+
+	// Overrides may ship a server.properties; keep the panel's port and
+	// player limit.
+	m.mu.RLock()
+	port, maxPlayers := cfg.Port, cfg.MaxPlayers
+	m.mu.RUnlock()
+	if err := applyJavaImportPropertyOverrides(filepath.Join(cfg.Dir, "server.properties"), maxPlayers, port, nil, nil, nil); err != nil {
+		fail(fmt.Sprintf("Failed to update server.properties: %v", err))
+		return
+	}
+
+	m.useForgeRunScript(cfg, progressFn)
+
+	if err := os.Remove(archivePath); err != nil && !os.IsNotExist(err) {
+		log.Printf("[%s] Failed to remove modpack archive: %v", cfg.Name, err)
+	}
+
+	rs.mu.Lock()
+	rs.status = "Stopped"
+	rs.installError = ""
+	rs.mu.Unlock()
+
+	progressFn(fmt.Sprintf("Installation complete! %s is ready to start.", plan.info.Name))
+	job.finish(nil)
+}
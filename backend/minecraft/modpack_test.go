@@ -0,0 +1,191 @@
+package minecraft
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestModpack(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pack.zip")
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	zw := zip.NewWriter(out)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip create failed: %v", err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("zip write failed: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip close failed: %v", err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	return path
+}
+
+func TestReadModrinthModpackPlan(t *testing.T) {
+	index := `{
+		"formatVersion": 1, "game": "minecraft", "versionId": "2.1.0", "name": "Create Above",
+		"files": [
+			{"path": "mods/create.jar", "hashes": {"sha1": "ABC", "sha512": "DEF"}, "env": {"client": "required", "server": "required"}, "downloads": ["https://cdn.modrinth.com/data/create.jar"]},
+			{"path": "mods/oculus.jar", "hashes": {"sha1": "123"}, "env": {"client": "required", "server": "unsupported"}, "downloads": ["https://cdn.modrinth.com/data/oculus.jar"]}
+		],
+		"dependencies": {"minecraft": "1.20.1", "forge": "1.20.1-47.2.0"}
+	}`
+	archive := writeTestModpack(t, map[string]string{
+		"modrinth.index.json":                 index,
+		"overrides/config/create.toml":        "client = true\n",
+		"overrides/config/shared.toml":        "shared = 1\n",
+		"server-overrides/config/create.toml": "client = false\n",
+		"notes/readme.txt":                    "not copied\n",
+	})
+
+	plan, err := readModpackPlan(archive)
+	if err != nil {
+		t.Fatalf("readModpackPlan failed: %v", err)
+	}
+	if plan.serverType != "Forge" || plan.mcVersion != "1.20.1" || plan.info.LoaderVersion != "47.2.0" {
+		t.Fatalf("unexpected loader in plan: %+v", plan)
+	}
+	if plan.info.Name != "Create Above" || plan.info.Version != "2.1.0" || plan.info.Source != ModpackSourceModrinth {
+		t.Fatalf("unexpected pack info %+v", plan.info)
+	}
+	if len(plan.files) != 1 || plan.files[0].path != "mods/create.jar" || plan.files[0].sha512 != "def" || plan.skipped != 1 {
+		t.Fatalf("expected only the server-side file, got %+v (skipped %d)", plan.files, plan.skipped)
+	}
+
+	dest := t.TempDir()
+	for _, folder := range plan.overrides {
+		if _, err := extractModpackOverrides(archive, folder, dest); err != nil {
+			t.Fatalf("extractModpackOverrides(%s) failed: %v", folder, err)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(dest, "config", "create.toml")); string(data) != "client = false\n" {
+		t.Fatalf("expected server-overrides to win, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "config", "shared.toml")); err != nil {
+		t.Fatalf("expected overrides to be copied: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "notes")); !os.IsNotExist(err) {
+		t.Fatalf("expected files outside the override folders to be left out")
+	}
+}
+
+func TestReadModpackPlanRejectsUnsafePacks(t *testing.T) {
+	cases := map[string]string{
+		"escaping path": `{"formatVersion": 1, "game": "minecraft", "files": [{"path": "../../evil.jar", "hashes": {"sha1": "1"}, "downloads": ["https://cdn.modrinth.com/x"]}], "dependencies": {"minecraft": "1.20.1", "fabric-loader": "0.15.7"}}`,
+		"missing hash":  `{"formatVersion": 1, "game": "minecraft", "files": [{"path": "mods/a.jar", "hashes": {}, "downloads": ["https://cdn.modrinth.com/x"]}], "dependencies": {"minecraft": "1.20.1", "fabric-loader": "0.15.7"}}`,
+		"quilt":         `{"formatVersion": 1, "game": "minecraft", "files": [], "dependencies": {"minecraft": "1.20.1", "quilt-loader": "0.23.0"}}`,
+		"no loader":     `{"formatVersion": 1, "game": "minecraft", "files": [], "dependencies": {"minecraft": "1.20.1"}}`,
+	}
+	for name, index := range cases {
+		archive := writeTestModpack(t, map[string]string{"modrinth.index.json": index})
+		if _, err := readModpackPlan(archive); err == nil {
+			t.Fatalf("%s: expected the pack to be refused", name)
+		}
+	}
+	if _, err := readModpackPlan(writeTestModpack(t, map[string]string{"server.jar": "jar"})); err == nil {
+		t.Fatal("expected an archive without a manifest to be refused")
+	}
+}
+
+func TestReadCurseForgeModpackPlan(t *testing.T) {
+	manifest := `{
+		"manifestType": "minecraftModpack", "name": "All the Mods", "version": "1.4",
+		"minecraft": {"version": "1.20.4", "modLoaders": [{"id": "neoforge-20.4.237", "primary": true}]},
+		"files": [{"projectID": 10, "fileID": 100, "required": true}, {"projectID": 11, "fileID": 110, "required": false}],
+		"overrides": "extras"
+	}`
+	plan, err := readModpackPlan(writeTestModpack(t, map[string]string{"manifest.json": manifest}))
+	if err != nil {
+		t.Fatalf("readModpackPlan failed: %v", err)
+	}
+	if plan.serverType != "NeoForge" || plan.info.LoaderVersion != "20.4.237" || plan.mcVersion != "1.20.4" {
+		t.Fatalf("unexpected loader in plan: %+v", plan)
+	}
+	if len(plan.files) != 1 || plan.files[0].curseForgeFileID != 100 || plan.skipped != 1 {
+		t.Fatalf("expected only the required file, got %+v", plan.files)
+	}
+	if len(plan.overrides) != 1 || plan.overrides[0] != "extras" {
+		t.Fatalf("expected the manifest's overrides folder, got %v", plan.overrides)
+	}
+}
+
+func TestCurseForgeResolveFiles(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "test-key" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/mods/files":
+			json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{
+				{"id": 4321567, "modId": 10, "fileName": "jei-1.20.4.jar", "downloadUrl": nil, "hashes": []map[string]any{{"value": "ABCD", "algo": 1}, {"value": "ff", "algo": 2}}},
+				{"id": 200, "modId": 20, "fileName": "faithful.zip", "downloadUrl": "https://edge.forgecdn.net/files/0/200/faithful.zip"},
+			}})
+		case "/v1/mods":
+			json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{
+				{"id": 10, "name": "JEI", "classId": curseForgeClassMods},
+				{"id": 20, "name": "Faithful", "classId": 12},
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client := &curseForgeClient{baseURL: srv.URL, apiKey: "test-key"}
+	files, skipped, err := client.resolveFiles(context.Background(), []modpackFile{
+		{curseForgeProjectID: 10, curseForgeFileID: 4321567},
+		{curseForgeProjectID: 20, curseForgeFileID: 200},
+	})
+	if err != nil {
+		t.Fatalf("resolveFiles failed: %v", err)
+	}
+	if skipped != 1 || len(files) != 1 {
+		t.Fatalf("expected the resource pack to be skipped, got %+v (skipped %d)", files, skipped)
+	}
+	jei := files[0]
+	if jei.path != "mods/jei-1.20.4.jar" || jei.sha1 != "abcd" {
+		t.Fatalf("unexpected resolved file %+v", jei)
+	}
+	if len(jei.urls) != 1 || jei.urls[0] != "https://edge.forgecdn.net/files/4321/567/jei-1.20.4.jar" {
+		t.Fatalf("expected the CDN fallback for a file without a download URL, got %v", jei.urls)
+	}
+
+	client.apiKey = "wrong"
+	if _, _, err := client.resolveFiles(context.Background(), []modpackFile{{curseForgeProjectID: 10, curseForgeFileID: 4321567}}); err == nil || !strings.Contains(err.Error(), "API key") {
+		t.Fatalf("expected an API key error, got %v", err)
+	}
+}
+
+func TestVerifyModpackFileHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mod.jar")
+	if err := os.WriteFile(path, []byte("mod"), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	sum := sha1.Sum([]byte("mod"))
+	if err := verifyModpackFileHash(path, modpackFile{path: "mods/mod.jar", sha1: hex.EncodeToString(sum[:])}); err != nil {
+		t.Fatalf("expected the hash to match: %v", err)
+	}
+	if err := verifyModpackFileHash(path, modpackFile{path: "mods/mod.jar", sha1: "00"}); err == nil {
+		t.Fatal("expected a mismatched hash to fail")
+	}
+}
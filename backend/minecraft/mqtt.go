@@ -0,0 +1,284 @@
+package minecraft
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMQTTTopicPrefix = "orexa"
+	defaultMQTTInterval    = 30 * time.Second
+	mqttDialTimeout        = 10 * time.Second
+	mqttClientID           = "orexa-panel"
+)
+
+// mqttConfig is read from ADPANEL_MQTT_* at startup. An empty addr turns
+// publishing off.
+type mqttConfig struct {
+	addr     string
+	useTLS   bool
+	username string
+	password string
+	prefix   string
+	interval time.Duration
+
+	haDiscovery       bool
+	haDiscoveryPrefix string
+}
+
+func mqttConfigFromEnv() (mqttConfig, bool) {
+	raw := strings.TrimSpace(os.Getenv("ADPANEL_MQTT_URL"))
+	if raw == "" {
+		return mqttConfig{}, false
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		log.Printf("Invalid ADPANEL_MQTT_URL value %q, MQTT publishing disabled", raw)
+		return mqttConfig{}, false
+	}
+	cfg := mqttConfig{
+		username: os.Getenv("ADPANEL_MQTT_USERNAME"),
+		password: os.Getenv("ADPANEL_MQTT_PASSWORD"),
+		prefix:   strings.Trim(strings.TrimSpace(os.Getenv("ADPANEL_MQTT_TOPIC_PREFIX")), "/"),
+		interval: defaultMQTTInterval,
+	}
+	port := u.Port()
+	switch strings.ToLower(u.Scheme) {
+	case "tcp", "mqtt":
+		if port == "" {
+			port = "1883"
+		}
+	case "ssl", "tls", "mqtts":
+		cfg.useTLS = true
+		if port == "" {
+			port = "8883"
+		}
+	default:
+		log.Printf("Unsupported ADPANEL_MQTT_URL scheme %q, MQTT publishing disabled", u.Scheme)
+		return mqttConfig{}, false
+	}
+	cfg.addr = net.JoinHostPort(u.Hostname(), port)
+	if cfg.prefix == "" {
+		cfg.prefix = defaultMQTTTopicPrefix
+	}
+	if rawInterval := strings.TrimSpace(os.Getenv("ADPANEL_MQTT_INTERVAL")); rawInterval != "" {
+		n, err := strconv.Atoi(rawInterval)
+		if err != nil || n < 5 || n > 3600 {
+			log.Printf("Invalid ADPANEL_MQTT_INTERVAL value %q, using default %s", rawInterval, defaultMQTTInterval)
+		} else {
+			cfg.interval = time.Duration(n) * time.Second
+		}
+	}
+	cfg.haDiscovery, cfg.haDiscoveryPrefix = haDiscoveryFromEnv()
+	return cfg, true
+}
+
+// availabilityTopic is "online" while the panel publishes and "offline"
+// after it stops or drops off, via the broker's last will.
+func (c mqttConfig) availabilityTopic() string {
+	return c.prefix + "/status"
+}
+
+// mqttClient is a publish-only MQTT 3.1.1 connection using QoS 0.
+type mqttClient struct {
+	conn net.Conn
+}
+
+func dialMQTT(cfg mqttConfig) (*mqttClient, error) {
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: mqttDialTimeout}
+	if cfg.useTLS {
+		host, _, _ := net.SplitHostPort(cfg.addr)
+		conn, err = tls.DialWithDialer(dialer, "tcp", cfg.addr, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.Dial("tcp", cfg.addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Publishes keep the connection alive; allow two missed intervals.
+	keepAlive := int(2 * cfg.interval / time.Second)
+	if keepAlive > 65535 {
+		keepAlive = 65535
+	}
+	flags := byte(0x02 | 0x04 | 0x20) // clean session, retained will
+	var payload bytes.Buffer
+	writeMQTTString(&payload, mqttClientID)
+	writeMQTTString(&payload, cfg.availabilityTopic())
+	writeMQTTString(&payload, "offline")
+	if cfg.username != "" {
+		flags |= 0x80
+		writeMQTTString(&payload, cfg.username)
+		if cfg.password != "" {
+			flags |= 0x40
+			writeMQTTString(&payload, cfg.password)
+		}
+	}
+	var body bytes.Buffer
+	writeMQTTString(&body, "MQTT")
+	body.WriteByte(4) // protocol level 3.1.1
+	body.WriteByte(flags)
+	binary.Write(&body, binary.BigEndian, uint16(keepAlive))
+	body.Write(payload.Bytes())
+
+	c := &mqttClient{conn: conn}
+	if err := c.writePacket(0x10, body.Bytes()); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(mqttDialTimeout))
+	ack := make([]byte, 4)
+	if _, err := io.ReadFull(conn, ack); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("no CONNACK from MQTT broker: %w", err)
+	}
+	conn.SetReadDeadline(time.Time{})
+	if ack[0] != 0x20 || ack[1] != 0x02 {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected reply from MQTT broker")
+	}
+	if ack[3] != 0 {
+		conn.Close()
+		return nil, fmt.Errorf("MQTT broker refused connection (code %d)", ack[3])
+	}
+	return c, nil
+}
+
+func (c *mqttClient) publish(topic string, payload []byte, retain bool) error {
+	var body bytes.Buffer
+	writeMQTTString(&body, topic)
+	body.Write(payload)
+	header := byte(0x30)
+	if retain {
+		header |= 0x01
+	}
+	return c.writePacket(header, body.Bytes())
+}
+
+// close disconnects cleanly. The broker does not send the last will then,
+// so callers publish "offline" themselves first.
+func (c *mqttClient) close() {
+	c.writePacket(0xE0, nil)
+	c.conn.Close()
+}
+
+func (c *mqttClient) writePacket(header byte, body []byte) error {
+	var packet bytes.Buffer
+	packet.WriteByte(header)
+	// Remaining length as a variable-length integer.
+	n := len(body)
+	for {
+		digit := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			digit |= 0x80
+		}
+		packet.WriteByte(digit)
+		if n == 0 {
+			break
+		}
+	}
+	packet.Write(body)
+	c.conn.SetWriteDeadline(time.Now().Add(mqttDialTimeout))
+	_, err := c.conn.Write(packet.Bytes())
+	return err
+}
+
+func writeMQTTString(buf *bytes.Buffer, s string) {
+	binary.Write(buf, binary.BigEndian, uint16(len(s)))
+	buf.WriteString(s)
+}
+
+// publishMQTTStates publishes every server's state as retained messages:
+// the full JSON under <prefix>/servers/<id>/state and the headline values
+// as plain topics for simple automations.
+func (m *Manager) publishMQTTStates(c *mqttClient, cfg mqttConfig) error {
+	if err := c.publish(cfg.availabilityTopic(), []byte("online"), true); err != nil {
+		return err
+	}
+	for _, state := range m.ServerStates() {
+		base := cfg.prefix + "/servers/" + state.ID
+		data, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		values := []struct {
+			topic   string
+			payload string
+		}{
+			{base + "/state", string(data)},
+			{base + "/status", state.Status},
+			{base + "/players", strconv.Itoa(state.Players)},
+			{base + "/tps", strconv.FormatFloat(state.TPS, 'f', 1, 64)},
+		}
+		for _, v := range values {
+			if err := c.publish(v.topic, []byte(v.payload), true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runMQTTPublisher publishes server state every interval until StopAll,
+// reconnecting on the next tick after a failure.
+func (m *Manager) runMQTTPublisher(cfg mqttConfig) {
+	log.Printf("MQTT publishing to %s under %q every %s", cfg.addr, cfg.prefix, cfg.interval)
+	ticker := time.NewTicker(cfg.interval)
+	defer ticker.Stop()
+
+	var client *mqttClient
+	var announced map[string]string
+	publish := func() {
+		if client == nil {
+			c, err := dialMQTT(cfg)
+			if err != nil {
+				log.Printf("MQTT connect failed: %v", err)
+				return
+			}
+			client = c
+			// Discovery configs are republished on every new connection.
+			announced = make(map[string]string)
+		}
+		var err error
+		if cfg.haDiscovery {
+			err = m.publishHADiscovery(client, cfg, announced)
+		}
+		if err == nil {
+			err = m.publishMQTTStates(client, cfg)
+		}
+		if err != nil {
+			log.Printf("MQTT publish failed: %v", err)
+			client.conn.Close()
+			client = nil
+		}
+	}
+
+	publish()
+	for {
+		select {
+		case <-m.stopMQTT:
+			if client != nil {
+				client.publish(cfg.availabilityTopic(), []byte("offline"), true)
+				client.close()
+			}
+			return
+		case <-ticker.C:
+			publish()
+		}
+	}
+}
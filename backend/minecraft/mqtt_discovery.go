@@ -0,0 +1,121 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+const defaultHADiscoveryPrefix = "homeassistant"
+
+// haDiscoveryFromEnv reads ADPANEL_MQTT_HA_DISCOVERY and its topic prefix.
+func haDiscoveryFromEnv() (bool, string) {
+	v := strings.TrimSpace(strings.ToLower(os.Getenv("ADPANEL_MQTT_HA_DISCOVERY")))
+	if v != "1" && v != "true" && v != "yes" && v != "on" {
+		return false, ""
+	}
+	prefix := strings.Trim(strings.TrimSpace(os.Getenv("ADPANEL_MQTT_HA_DISCOVERY_PREFIX")), "/")
+	if prefix == "" {
+		prefix = defaultHADiscoveryPrefix
+	}
+	return true, prefix
+}
+
+// haEntity is one Home Assistant entity built from a server's state topic.
+type haEntity struct {
+	component   string
+	key         string
+	name        string
+	template    string
+	unit        string
+	deviceClass string
+	measurement bool
+}
+
+var haEntities = []haEntity{
+	{component: "binary_sensor", key: "online", name: "Online", template: "{{ 'ON' if value_json.online else 'OFF' }}", deviceClass: "running"},
+	{component: "sensor", key: "status", name: "Status", template: "{{ value_json.status }}"},
+	{component: "sensor", key: "players", name: "Players", template: "{{ value_json.players }}", unit: "players", measurement: true},
+	{component: "sensor", key: "tps", name: "TPS", template: "{{ value_json.tps }}", measurement: true},
+	{component: "sensor", key: "cpu", name: "CPU", template: "{{ value_json.cpu }}", unit: "%", measurement: true},
+	{component: "sensor", key: "ram", name: "RAM", template: "{{ value_json.ramMb }}", unit: "MB", deviceClass: "data_size", measurement: true},
+}
+
+// haObjectID turns a server ID into a Home Assistant object ID.
+func haObjectID(id string) string {
+	var b strings.Builder
+	b.WriteString("orexa_")
+	for _, r := range id {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+func (c mqttConfig) haDiscoveryTopic(entity haEntity, serverID string) string {
+	return c.haDiscoveryPrefix + "/" + entity.component + "/" + haObjectID(serverID) + "/" + entity.key + "/config"
+}
+
+// publishHADiscovery publishes Home Assistant discovery configs for servers
+// that are new or renamed since the last call and removes the entities of
+// deleted servers. announced maps server IDs to the names already published.
+func (m *Manager) publishHADiscovery(c *mqttClient, cfg mqttConfig, announced map[string]string) error {
+	current := make(map[string]bool)
+	for _, state := range m.ServerStates() {
+		current[state.ID] = true
+		if name, ok := announced[state.ID]; ok && name == state.Name {
+			continue
+		}
+		objectID := haObjectID(state.ID)
+		device := map[string]any{
+			"identifiers":  []string{objectID},
+			"name":         state.Name,
+			"manufacturer": "Orexa Panel",
+			"model":        "Minecraft server",
+		}
+		for _, entity := range haEntities {
+			payload := map[string]any{
+				"name":               entity.name,
+				"unique_id":          objectID + "_" + entity.key,
+				"state_topic":        cfg.prefix + "/servers/" + state.ID + "/state",
+				"value_template":     entity.template,
+				"availability_topic": cfg.availabilityTopic(),
+				"device":             device,
+			}
+			if entity.unit != "" {
+				payload["unit_of_measurement"] = entity.unit
+			}
+			if entity.deviceClass != "" {
+				payload["device_class"] = entity.deviceClass
+			}
+			if entity.measurement {
+				payload["state_class"] = "measurement"
+			}
+			data, err := json.Marshal(payload)
+			if err != nil {
+				return err
+			}
+			if err := c.publish(cfg.haDiscoveryTopic(entity, state.ID), data, true); err != nil {
+				return err
+			}
+		}
+		announced[state.ID] = state.Name
+	}
+
+	// An empty retained config removes the entity from Home Assistant.
+	for id := range announced {
+		if current[id] {
+			continue
+		}
+		for _, entity := range haEntities {
+			if err := c.publish(cfg.haDiscoveryTopic(entity, id), nil, true); err != nil {
+				return err
+			}
+		}
+		delete(announced, id)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package minecraft
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// readMQTTPacket reads one packet and returns its header byte and body.
+func readMQTTPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for {
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(digit&0x7f) * multiplier
+		multiplier *= 128
+		if digit&0x80 == 0 {
+			break
+		}
+	}
+	body := make([]byte, length)
+	_, err = io.ReadFull(r, body)
+	return header, body, err
+}
+
+func TestPublishMQTTStatesSendsRetainedServerTopics(t *testing.T) {
+	t.Setenv("ADPANEL_MQTT_URL", "mqtt://broker.local")
+	t.Setenv("ADPANEL_MQTT_TOPIC_PREFIX", "/home/mc/")
+	t.Setenv("ADPANEL_MQTT_INTERVAL", "2")
+	cfg, ok := mqttConfigFromEnv()
+	if !ok || cfg.addr != "broker.local:1883" || cfg.prefix != "home/mc" || cfg.interval != defaultMQTTInterval {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+	mgr.mu.Lock()
+	mgr.configs["lobby"] = &ServerConfig{ID: "lobby", Name: "Lobby", Type: "Paper", MaxPlayers: 20, Dir: filepath.Join(mgr.serversRoot, "Lobby")}
+	mgr.running["lobby"] = &runningServer{status: "Running", tps: 14.25, players: map[string]*onlinePlayer{"a": {}, "b": {}}}
+	mgr.mu.Unlock()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer ln.Close()
+	cfg.addr = ln.Addr().String()
+	cfg.username = "panel"
+	cfg.password = "secret"
+
+	type message struct {
+		topic, payload string
+		retain         bool
+	}
+	received := make(chan message, 32)
+	connected := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		_, body, err := readMQTTPacket(r)
+		if err != nil {
+			return
+		}
+		connected <- body
+		conn.Write([]byte{0x20, 0x02, 0x00, 0x00})
+		for {
+			header, body, err := readMQTTPacket(r)
+			if err != nil || header&0xf0 != 0x30 {
+				return
+			}
+			n := int(binary.BigEndian.Uint16(body[:2]))
+			received <- message{topic: string(body[2 : 2+n]), payload: string(body[2+n:]), retain: header&0x01 == 1}
+		}
+	}()
+
+	client, err := dialMQTT(cfg)
+	if err != nil {
+		t.Fatalf("dialMQTT failed: %v", err)
+	}
+	defer client.close()
+	if connect := <-connected; connect[7]&0xC4 != 0xC4 {
+		t.Fatalf("expected username, password and will flags, got %08b", connect[7])
+	}
+	if err := mgr.publishMQTTStates(client, cfg); err != nil {
+		t.Fatalf("publishMQTTStates failed: %v", err)
+	}
+
+	want := map[string]string{
+		"home/mc/status":                "online",
+		"home/mc/servers/lobby/status":  "Running",
+		"home/mc/servers/lobby/players": "2",
+		"home/mc/servers/lobby/tps":     "14.2",
+	}
+	got := make(map[string]string)
+	timeout := time.After(5 * time.Second)
+	for len(got) < 5 {
+		select {
+		case msg := <-received:
+			if !msg.retain {
+				t.Fatalf("expected %s to be retained", msg.topic)
+			}
+			got[msg.topic] = msg.payload
+		case <-timeout:
+			t.Fatalf("timed out, got %v", got)
+		}
+	}
+	for topic, payload := range want {
+		if got[topic] != payload {
+			t.Fatalf("topic %s: expected %q, got %q", topic, payload, got[topic])
+		}
+	}
+	if got["home/mc/servers/lobby/state"] == "" {
+		t.Fatalf("expected JSON state topic, got %v", got)
+	}
+}
+
+func TestPublishHADiscoveryAnnouncesAndRemovesServers(t *testing.T) {
+	t.Setenv("ADPANEL_MQTT_URL", "mqtt://broker.local")
+	t.Setenv("ADPANEL_MQTT_HA_DISCOVERY", "true")
+	cfg, ok := mqttConfigFromEnv()
+	if !ok || !cfg.haDiscovery || cfg.haDiscoveryPrefix != "homeassistant" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+	mgr.mu.Lock()
+	mgr.configs["lobby.1"] = &ServerConfig{ID: "lobby.1", Name: "Lobby", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Lobby")}
+	mgr.running["lobby.1"] = &runningServer{status: "Running"}
+	mgr.mu.Unlock()
+
+	server, conn := net.Pipe()
+	defer server.Close()
+	client := &mqttClient{conn: conn}
+	received := make(chan [2]string, 64)
+	go func() {
+		r := bufio.NewReader(server)
+		for {
+			_, body, err := readMQTTPacket(r)
+			if err != nil {
+				return
+			}
+			n := int(binary.BigEndian.Uint16(body[:2]))
+			received <- [2]string{string(body[2 : 2+n]), string(body[2+n:])}
+		}
+	}()
+	next := func() [2]string {
+		select {
+		case msg := <-received:
+			return msg
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for a discovery message")
+			return [2]string{}
+		}
+	}
+
+	announced := make(map[string]string)
+	if err := mgr.publishHADiscovery(client, cfg, announced); err != nil {
+		t.Fatalf("publishHADiscovery failed: %v", err)
+	}
+	configs := make(map[string]map[string]any)
+	for range haEntities {
+		msg := next()
+		var payload map[string]any
+		if err := json.Unmarshal([]byte(msg[1]), &payload); err != nil {
+			t.Fatalf("invalid discovery payload %q: %v", msg[1], err)
+		}
+		configs[msg[0]] = payload
+	}
+	players := configs["homeassistant/sensor/orexa_lobby_1/players/config"]
+	if players == nil || players["state_topic"] != "orexa/servers/lobby.1/state" || players["availability_topic"] != "orexa/status" {
+		t.Fatalf("unexpected players config %v (topics %v)", players, configs)
+	}
+	if configs["homeassistant/binary_sensor/orexa_lobby_1/online/config"] == nil {
+		t.Fatalf("expected an online binary sensor, got %v", configs)
+	}
+
+	// Unchanged servers are not announced again; deleted ones are removed.
+	mgr.mu.Lock()
+	delete(mgr.configs, "lobby.1")
+	delete(mgr.running, "lobby.1")
+	mgr.mu.Unlock()
+	if err := mgr.publishHADiscovery(client, cfg, announced); err != nil {
+		t.Fatalf("publishHADiscovery failed: %v", err)
+	}
+	for range haEntities {
+		if msg := next(); msg[1] != "" {
+			t.Fatalf("expected an empty config to remove %s, got %q", msg[0], msg[1])
+		}
+	}
+	if len(announced) != 0 {
+		t.Fatalf("expected no announced servers, got %v", announced)
+	}
+}
@@ -0,0 +1,437 @@
+package minecraft
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// NBT values are decoded into plain Go values shared by the binary and
+// SNBT readers: compounds become map[string]any, lists []any, numbers
+// int64 or float64, arrays []int64 and strings string.
+
+const (
+	nbtEnd = iota
+	nbtByte
+	nbtShort
+	nbtInt
+	nbtLong
+	nbtFloat
+	nbtDouble
+	nbtByteArray
+	nbtString
+	nbtList
+	nbtCompound
+	nbtIntArray
+	nbtLongArray
+)
+
+const (
+	maxNBTDepth    = 512
+	maxNBTArrayLen = 1 << 22
+)
+
+// readNBT decodes a binary NBT document, gzip-compressed or not, and
+// returns its root compound.
+func readNBT(data []byte) (map[string]any, error) {
+	var r io.Reader = bytes.NewReader(data)
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	d := &nbtDecoder{r: bufio.NewReader(r)}
+	tagType, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+	if tagType != nbtCompound {
+		return nil, fmt.Errorf("nbt: root tag is %d, not a compound", tagType)
+	}
+	if _, err := d.string(); err != nil {
+		return nil, err
+	}
+	value, err := d.payload(nbtCompound, 0)
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]any), nil
+}
+
+type nbtDecoder struct {
+	r *bufio.Reader
+}
+
+func (d *nbtDecoder) byte() (byte, error) {
+	return d.r.ReadByte()
+}
+
+func (d *nbtDecoder) read(v any) error {
+	return binary.Read(d.r, binary.BigEndian, v)
+}
+
+func (d *nbtDecoder) string() (string, error) {
+	var n uint16
+	if err := d.read(&n); err != nil {
+		return "", err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(d.r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+func (d *nbtDecoder) length() (int, error) {
+	var n int32
+	if err := d.read(&n); err != nil {
+		return 0, err
+	}
+	if n < 0 || n > maxNBTArrayLen {
+		return 0, fmt.Errorf("nbt: invalid length %d", n)
+	}
+	return int(n), nil
+}
+
+func (d *nbtDecoder) payload(tagType byte, depth int) (any, error) {
+	if depth > maxNBTDepth {
+		return nil, fmt.Errorf("nbt: nesting too deep")
+	}
+	switch tagType {
+	case nbtByte:
+		var v int8
+		err := d.read(&v)
+		return int64(v), err
+	case nbtShort:
+		var v int16
+		err := d.read(&v)
+		return int64(v), err
+	case nbtInt:
+		var v int32
+		err := d.read(&v)
+		return int64(v), err
+	case nbtLong:
+		var v int64
+		err := d.read(&v)
+		return v, err
+	case nbtFloat:
+		var v float32
+		err := d.read(&v)
+		return float64(v), err
+	case nbtDouble:
+		var v float64
+		err := d.read(&v)
+		return v, err
+	case nbtString:
+		return d.string()
+	case nbtByteArray, nbtIntArray, nbtLongArray:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		elem := map[byte]byte{nbtByteArray: nbtByte, nbtIntArray: nbtInt, nbtLongArray: nbtLong}[tagType]
+		values := make([]int64, 0, min(n, 1024))
+		for i := 0; i < n; i++ {
+			v, err := d.payload(elem, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v.(int64))
+		}
+		return values, nil
+	case nbtList:
+		elem, err := d.byte()
+		if err != nil {
+			return nil, err
+		}
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		values := make([]any, 0, min(n, 1024))
+		for i := 0; i < n; i++ {
+			v, err := d.payload(elem, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	case nbtCompound:
+		values := make(map[string]any)
+		for {
+			child, err := d.byte()
+			if err != nil {
+				return nil, err
+			}
+			if child == nbtEnd {
+				return values, nil
+			}
+			name, err := d.string()
+			if err != nil {
+				return nil, err
+			}
+			v, err := d.payload(child, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			values[name] = v
+		}
+	default:
+		return nil, fmt.Errorf("nbt: unknown tag type %d", tagType)
+	}
+}
+
+// parseSNBT decodes the stringified NBT printed by commands such as
+// "data get entity".
+func parseSNBT(s string) (any, error) {
+	p := &snbtParser{s: s}
+	v, err := p.value(0)
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("snbt: unexpected %q at %d", p.s[p.pos], p.pos)
+	}
+	return v, nil
+}
+
+type snbtParser struct {
+	s   string
+	pos int
+}
+
+func (p *snbtParser) skipSpace() {
+	for p.pos < len(p.s) && strings.ContainsRune(" \t\r\n", rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *snbtParser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *snbtParser) expect(c byte) error {
+	if p.peek() != c {
+		return fmt.Errorf("snbt: expected %q at %d", c, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+func isSNBTBareChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' || c == '-' || c == '.' || c == '+'
+}
+
+func (p *snbtParser) value(depth int) (any, error) {
+	if depth > maxNBTDepth {
+		return nil, fmt.Errorf("snbt: nesting too deep")
+	}
+	switch c := p.peek(); c {
+	case '{':
+		return p.compound(depth)
+	case '[':
+		return p.list(depth)
+	case '"', '\'':
+		return p.quoted()
+	case 0:
+		return nil, fmt.Errorf("snbt: unexpected end of input")
+	default:
+		token := p.bare()
+		if token == "" {
+			return nil, fmt.Errorf("snbt: unexpected %q at %d", c, p.pos)
+		}
+		return snbtScalar(token), nil
+	}
+}
+
+func (p *snbtParser) compound(depth int) (any, error) {
+	p.pos++
+	values := make(map[string]any)
+	if p.peek() == '}' {
+		p.pos++
+		return values, nil
+	}
+	for {
+		var key string
+		if c := p.peek(); c == '"' || c == '\'' {
+			k, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			key = k
+		} else {
+			key = p.bare()
+		}
+		if key == "" {
+			return nil, fmt.Errorf("snbt: expected key at %d", p.pos)
+		}
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		v, err := p.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = v
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return values, nil
+		default:
+			return nil, fmt.Errorf("snbt: expected ',' or '}' at %d", p.pos)
+		}
+	}
+}
+
+func (p *snbtParser) list(depth int) (any, error) {
+	p.pos++
+	// Typed arrays look like [I; 1, 2, 3].
+	if p.pos+1 < len(p.s) && strings.ContainsRune("BIL", rune(p.s[p.pos])) && p.s[p.pos+1] == ';' {
+		p.pos += 2
+		values := []int64{}
+		if p.peek() == ']' {
+			p.pos++
+			return values, nil
+		}
+		for {
+			v, ok := snbtScalar(p.bare()).(int64)
+			if !ok {
+				return nil, fmt.Errorf("snbt: invalid array element at %d", p.pos)
+			}
+			values = append(values, v)
+			switch p.peek() {
+			case ',':
+				p.pos++
+			case ']':
+				p.pos++
+				return values, nil
+			default:
+				return nil, fmt.Errorf("snbt: expected ',' or ']' at %d", p.pos)
+			}
+		}
+	}
+	values := []any{}
+	if p.peek() == ']' {
+		p.pos++
+		return values, nil
+	}
+	for {
+		v, err := p.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return values, nil
+		default:
+			return nil, fmt.Errorf("snbt: expected ',' or ']' at %d", p.pos)
+		}
+	}
+}
+
+func (p *snbtParser) quoted() (string, error) {
+	quote := p.s[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch {
+		case c == '\\' && p.pos < len(p.s):
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		case c == quote:
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("snbt: unterminated string")
+}
+
+func (p *snbtParser) bare() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && isSNBTBareChar(p.s[p.pos]) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// snbtScalar turns an unquoted token into a number, a boolean byte or, if
+// it is neither, a string.
+func snbtScalar(token string) any {
+	switch strings.ToLower(token) {
+	case "true":
+		return int64(1)
+	case "false":
+		return int64(0)
+	}
+	body := token
+	suffix := byte(0)
+	if n := len(token); n > 1 {
+		switch last := token[n-1] | 0x20; last {
+		case 'b', 's', 'l', 'f', 'd':
+			suffix = last
+			body = token[:n-1]
+		}
+	}
+	switch suffix {
+	case 'b', 's', 'l', 0:
+		if v, err := strconv.ParseInt(body, 10, 64); err == nil {
+			return v
+		}
+		if suffix != 0 {
+			break
+		}
+		fallthrough
+	case 'f', 'd':
+		if v, err := strconv.ParseFloat(body, 64); err == nil && !math.IsInf(v, 0) && !math.IsNaN(v) {
+			return v
+		}
+	}
+	return token
+}
+
+// nbtIntField reads an integer field from a compound, accepting either number type.
+func nbtIntField(c map[string]any, key string) (int64, bool) {
+	switch v := c[key].(type) {
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// nbtFloatField reads a numeric field from a compound as a float.
+func nbtFloatField(c map[string]any, key string) (float64, bool) {
+	switch v := c[key].(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,596 @@
+package minecraft
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// velocitySecretFile is the forwarding secret file velocity.toml points at.
+const velocitySecretFile = "forwarding.secret"
+
+var networkBackendName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// NetworkBackend links a panel server to a Velocity proxy under Name, the
+// key it gets in velocity.toml's [servers] table.
+type NetworkBackend struct {
+	ServerID string `json:"serverId"`
+	Name     string `json:"name"`
+}
+
+// ProxyNetwork is the set of backends a Velocity proxy routes to. Try is
+// the join order; it defaults to the first backend.
+type ProxyNetwork struct {
+	Backends []NetworkBackend `json:"backends"`
+	Try      []string         `json:"try,omitempty"`
+}
+
+// NetworkInfo is one proxy and the backends its velocity.toml lists.
+// Managed is set when the panel writes the mapping; other proxies are
+// shown as their velocity.toml has them.
+type NetworkInfo struct {
+	ProxyID     string               `json:"proxyId"`
+	ProxyName   string               `json:"proxyName"`
+	ProxyStatus string               `json:"proxyStatus"`
+	Managed     bool                 `json:"managed"`
+	Forwarding  string               `json:"forwarding,omitempty"`
+	Try         []string             `json:"try"`
+	Backends    []NetworkBackendInfo `json:"backends"`
+	Error       string               `json:"error,omitempty"`
+}
+
+// NetworkBackendInfo is one [servers] entry, matched to a panel server by
+// port. Forwarding is set when the backend accepts the proxy's modern
+// forwarding with the same secret.
+type NetworkBackendInfo struct {
+	Name       string `json:"name"`
+	Address    string `json:"address"`
+	ServerID   string `json:"serverId,omitempty"`
+	ServerName string `json:"serverName,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Forwarding bool   `json:"forwarding"`
+}
+
+// NetworkUpdateResult is a proxy's network after it was written, with
+// anything that still needs doing by hand.
+type NetworkUpdateResult struct {
+	Network  NetworkInfo `json:"network"`
+	Warnings []string    `json:"warnings"`
+}
+
+// supportsVelocityForwarding reports whether the panel can turn on modern
+// forwarding for a backend type. Paper and its forks support it natively;
+// others need a mod or plugin.
+func supportsVelocityForwarding(serverType string) bool {
+	switch strings.ToLower(serverType) {
+	case "paper", "purpur", "folia":
+		return true
+	}
+	return false
+}
+
+// ListNetworks returns every Velocity proxy with its backends.
+func (m *Manager) ListNetworks() []NetworkInfo {
+	type serverRef struct {
+		id, name, status, typ, dir string
+		network                    *ProxyNetwork
+	}
+	m.mu.RLock()
+	var proxies []serverRef
+	byPort := make(map[int]serverRef)
+	for _, cfg := range m.configs {
+		status := ""
+		if rs, ok := m.running[cfg.ID]; ok {
+			rs.mu.RLock()
+			status = rs.status
+			rs.mu.RUnlock()
+		}
+		ref := serverRef{id: cfg.ID, name: cfg.Name, status: status, typ: cfg.Type, dir: cfg.Dir, network: cfg.Network}
+		if isProxyType(cfg.Type) {
+			proxies = append(proxies, ref)
+		} else {
+			byPort[cfg.Port] = ref
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(proxies, func(i, j int) bool { return proxies[i].name < proxies[j].name })
+	networks := make([]NetworkInfo, 0, len(proxies))
+	for _, p := range proxies {
+		info := NetworkInfo{ProxyID: p.id, ProxyName: p.name, ProxyStatus: p.status, Managed: p.network != nil, Try: []string{}, Backends: []NetworkBackendInfo{}}
+		tomlPath := filepath.Join(p.dir, "velocity.toml")
+		backends, err := readVelocityBackends(tomlPath)
+		if err != nil {
+			info.Error = err.Error()
+			networks = append(networks, info)
+			continue
+		}
+		info.Forwarding, info.Try = readVelocityForwarding(tomlPath)
+		secret := readForwardingSecret(p.dir)
+		for _, b := range backends {
+			entry := NetworkBackendInfo{Name: b.name, Address: b.address}
+			if s, ok := byPort[backendPort(b.address)]; ok {
+				entry.ServerID, entry.ServerName, entry.Status = s.id, s.name, s.status
+				entry.Forwarding = secret != "" && supportsVelocityForwarding(s.typ) && paperForwardingSecret(s.dir) == secret
+			}
+			info.Backends = append(info.Backends, entry)
+		}
+		networks = append(networks, info)
+	}
+	return networks
+}
+
+// SetProxyNetwork links backends to a Velocity proxy. It rewrites the
+// proxy's [servers] table and join order, switches it to modern forwarding
+// with a generated secret, and gives Paper-family backends the same secret.
+// An empty backend list unlinks the proxy and leaves its files alone.
+func (m *Manager) SetProxyNetwork(id string, network ProxyNetwork) (*NetworkUpdateResult, error) {
+	type backendRef struct {
+		NetworkBackend
+		cfg    ServerConfig
+		status string
+	}
+
+	m.mu.RLock()
+	proxyCfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		m.mu.RUnlock()
+		return nil, err
+	}
+	if !isProxyType(proxyCfg.Type) {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("%s is not a Velocity proxy", proxyCfg.Name)
+	}
+	proxy := *proxyCfg
+	proxyStatus := m.serverStatusLocked(id)
+	var refs []backendRef
+	names := make(map[string]bool)
+	seen := make(map[string]bool)
+	for _, b := range network.Backends {
+		cfg, err := m.serverConfigForOperationLocked(b.ServerID)
+		if err != nil {
+			m.mu.RUnlock()
+			return nil, err
+		}
+		if isProxyType(cfg.Type) {
+			m.mu.RUnlock()
+			return nil, fmt.Errorf("%s is a proxy and cannot be a backend", cfg.Name)
+		}
+		if seen[cfg.ID] {
+			m.mu.RUnlock()
+			return nil, fmt.Errorf("%s is listed twice", cfg.Name)
+		}
+		seen[cfg.ID] = true
+		name := strings.TrimSpace(b.Name)
+		if name == "" {
+			name = strings.ToLower(sanitizeName(cfg.Name))
+		}
+		if !networkBackendName.MatchString(name) {
+			m.mu.RUnlock()
+			return nil, fmt.Errorf("backend name %q must be 1-32 letters, digits, _ or -", name)
+		}
+		if names[name] {
+			m.mu.RUnlock()
+			return nil, fmt.Errorf("backend name %q is used twice", name)
+		}
+		names[name] = true
+		refs = append(refs, backendRef{NetworkBackend{ServerID: cfg.ID, Name: name}, *cfg, m.serverStatusLocked(cfg.ID)})
+	}
+	m.mu.RUnlock()
+
+	saved := &ProxyNetwork{Backends: make([]NetworkBackend, 0, len(refs))}
+	for _, ref := range refs {
+		saved.Backends = append(saved.Backends, ref.NetworkBackend)
+	}
+	for _, name := range network.Try {
+		if !names[name] {
+			return nil, fmt.Errorf("join order names %q, which is not a backend", name)
+		}
+		saved.Try = append(saved.Try, name)
+	}
+	if len(saved.Try) == 0 && len(refs) > 0 {
+		saved.Try = []string{refs[0].Name}
+	}
+
+	var warnings []string
+	if len(refs) > 0 {
+		entries := make([]velocityBackend, 0, len(refs))
+		for _, ref := range refs {
+			entries = append(entries, velocityBackend{name: ref.Name, address: "127.0.0.1:" + strconv.Itoa(ref.cfg.Port)})
+		}
+		if err := writeVelocityNetwork(filepath.Join(proxy.Dir, "velocity.toml"), entries, saved.Try); err != nil {
+			return nil, fmt.Errorf("failed to update velocity.toml: %w", err)
+		}
+		secret, err := ensureForwardingSecret(proxy.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the forwarding secret: %w", err)
+		}
+		if isActiveStatus(proxyStatus) {
+			warnings = append(warnings, fmt.Sprintf("Restart %s to load the new servers.", proxy.Name))
+		}
+		for _, ref := range refs {
+			if !supportsVelocityForwarding(ref.cfg.Type) {
+				warnings = append(warnings, fmt.Sprintf("%s is a %s server; set up Velocity forwarding on it by hand (for example with a proxy mod), or players cannot join it through %s.", ref.cfg.Name, ref.cfg.Type, proxy.Name))
+				continue
+			}
+			if err := enablePaperForwarding(ref.cfg.Dir, secret); err != nil {
+				return nil, fmt.Errorf("failed to configure forwarding on %s: %w", ref.cfg.Name, err)
+			}
+			if err := setServerProperties(filepath.Join(ref.cfg.Dir, "server.properties"), map[string]string{"online-mode": "false"}); err != nil {
+				return nil, fmt.Errorf("failed to update server.properties of %s: %w", ref.cfg.Name, err)
+			}
+			if isActiveStatus(ref.status) {
+				warnings = append(warnings, fmt.Sprintf("Restart %s to accept players from %s.", ref.cfg.Name, proxy.Name))
+			}
+		}
+	}
+
+	m.mu.Lock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	if len(refs) == 0 {
+		cfg.Network = nil
+	} else {
+		cfg.Network = saved
+	}
+	if err := m.persist(); err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	m.mu.Unlock()
+
+	result := &NetworkUpdateResult{Warnings: warnings}
+	if result.Warnings == nil {
+		result.Warnings = []string{}
+	}
+	for _, info := range m.ListNetworks() {
+		if info.ProxyID == id {
+			result.Network = info
+		}
+	}
+	return result, nil
+}
+
+// serverStatusLocked returns a server's status (caller must hold m.mu).
+func (m *Manager) serverStatusLocked(id string) string {
+	rs, ok := m.running[id]
+	if !ok {
+		return ""
+	}
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.status
+}
+
+func isActiveStatus(status string) bool {
+	return status == "Running" || status == "Booting" || status == "Suspended"
+}
+
+func readForwardingSecret(proxyDir string) string {
+	data, err := os.ReadFile(filepath.Join(proxyDir, velocitySecretFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// ensureForwardingSecret returns the proxy's forwarding secret, creating
+// one when the file is missing or empty.
+func ensureForwardingSecret(proxyDir string) (string, error) {
+	if secret := readForwardingSecret(proxyDir); secret != "" {
+		return secret, nil
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	secret := hex.EncodeToString(buf)
+	if err := os.WriteFile(filepath.Join(proxyDir, velocitySecretFile), []byte(secret), 0600); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// readVelocityForwarding returns the forwarding mode and join order of a
+// velocity.toml.
+func readVelocityForwarding(path string) (string, []string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", []string{}
+	}
+	mode := ""
+	try := []string{}
+	table := ""
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if isTOMLTableHeader(trimmed) {
+			table = trimmed
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		switch {
+		case table == "" && key == "player-info-forwarding-mode":
+			mode = strings.ToLower(strings.Trim(value, `"'`))
+		case table == "[servers]" && key == "try" && strings.HasSuffix(value, "]"):
+			for _, name := range strings.Split(strings.Trim(value, "[]"), ",") {
+				if name = strings.Trim(strings.TrimSpace(name), `"'`); name != "" {
+					try = append(try, name)
+				}
+			}
+		}
+	}
+	return mode, try
+}
+
+func isTOMLTableHeader(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "[") && !strings.HasPrefix(trimmed, "[[") && strings.HasSuffix(trimmed, "]") && !strings.Contains(trimmed, `"`)
+}
+
+func tomlStringList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// writeVelocityNetwork replaces the [servers] table of a velocity.toml,
+// turns on modern forwarding with the secret file, and drops forced hosts
+// that name servers no longer listed, which Velocity would refuse to load.
+func writeVelocityNetwork(path string, backends []velocityBackend, try []string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("velocity.toml not found (start the proxy once so it can be generated)")
+		}
+		return err
+	}
+	known := make(map[string]bool, len(backends))
+	serverLines := []string{"[servers]", "# Managed by the panel's network settings."}
+	for _, b := range backends {
+		known[b.name] = true
+		serverLines = append(serverLines, fmt.Sprintf("%s = %s", b.name, strconv.Quote(b.address)))
+	}
+	serverLines = append(serverLines, "try = "+tomlStringList(try), "")
+
+	topLevel := map[string]string{
+		"player-info-forwarding-mode": `"modern"`,
+		"forwarding-secret-file":      strconv.Quote(velocitySecretFile),
+	}
+	// forcedHostKnown reports whether every server a forced host array
+	// names is still listed.
+	forcedHostKnown := func(array string) bool {
+		for _, name := range strings.Split(strings.Trim(strings.TrimSpace(array), "[]"), ",") {
+			if name = strings.Trim(strings.TrimSpace(name), `"'`); name != "" && !known[name] {
+				return false
+			}
+		}
+		return true
+	}
+	written := make(map[string]bool)
+	var out []string
+	table := ""
+	// pending holds a multi-line array until its closing bracket; skip
+	// drops it instead of keeping it.
+	var pending []string
+	pendingValue := ""
+	skip := false
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if pending != nil {
+			pending = append(pending, line)
+			pendingValue += trimmed
+			if strings.HasPrefix(trimmed, "]") {
+				if !skip && (table != "[forced-hosts]" || forcedHostKnown(pendingValue)) {
+					out = append(out, pending...)
+				}
+				pending, pendingValue, skip = nil, "", false
+			}
+			continue
+		}
+		if isTOMLTableHeader(trimmed) {
+			if table == "" {
+				// Missing top-level keys go after the last one, before the
+				// blank lines ahead of the first table.
+				at := len(out)
+				for at > 0 && strings.TrimSpace(out[at-1]) == "" {
+					at--
+				}
+				var missing []string
+				for _, key := range []string{"player-info-forwarding-mode", "forwarding-secret-file"} {
+					if !written[key] {
+						missing = append(missing, key+" = "+topLevel[key])
+						written[key] = true
+					}
+				}
+				out = append(out[:at], append(missing, out[at:]...)...)
+			}
+			table = trimmed
+			if table == "[servers]" {
+				out = append(out, serverLines...)
+				continue
+			}
+			out = append(out, line)
+			continue
+		}
+		key, value, isKey := strings.Cut(trimmed, "=")
+		key = strings.Trim(strings.TrimSpace(key), `"'`)
+		value = strings.TrimSpace(value)
+		switch {
+		case table == "" && isKey && topLevel[key] != "":
+			out = append(out, key+" = "+topLevel[key])
+			written[key] = true
+			continue
+		case isKey && strings.HasPrefix(value, "[") && !strings.Contains(value, "]"):
+			pending, pendingValue, skip = []string{line}, value, table == "[servers]"
+			continue
+		case table == "[servers]":
+			continue
+		case table == "[forced-hosts]" && isKey && strings.HasPrefix(value, "[") && !forcedHostKnown(value):
+			continue
+		}
+		out = append(out, line)
+	}
+	if table == "" {
+		for _, key := range []string{"player-info-forwarding-mode", "forwarding-secret-file"} {
+			if !written[key] {
+				out = append(out, key+" = "+topLevel[key])
+			}
+		}
+	}
+	hasServers := false
+	for _, line := range out {
+		if strings.TrimSpace(line) == "[servers]" {
+			hasServers = true
+			break
+		}
+	}
+	if !hasServers {
+		out = append(out, "")
+		out = append(out, serverLines...)
+	}
+	return os.WriteFile(path, []byte(strings.Join(out, "\n")), 0644)
+}
+
+// paperForwardingFile returns the Paper config that holds the Velocity
+// settings: config/paper-global.yml on 1.19 and later, paper.yml before.
+func paperForwardingFile(serverDir string) (string, []string) {
+	global := filepath.Join(serverDir, "config", "paper-global.yml")
+	legacy := filepath.Join(serverDir, "paper.yml")
+	if _, err := os.Stat(global); os.IsNotExist(err) {
+		if _, err := os.Stat(legacy); err == nil {
+			return legacy, []string{"settings", "velocity-support"}
+		}
+	}
+	return global, []string{"proxies", "velocity"}
+}
+
+// paperForwardingSecret returns the Velocity secret a Paper backend
+// accepts, or "" when forwarding is off.
+func paperForwardingSecret(serverDir string) string {
+	path, section := paperForwardingFile(serverDir)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var doc map[string]any
+	if yaml.Unmarshal(data, &doc) != nil {
+		return ""
+	}
+	var node any = doc
+	for _, key := range section {
+		next, ok := node.(map[string]any)
+		if !ok {
+			return ""
+		}
+		node = next[key]
+	}
+	settings, ok := node.(map[string]any)
+	if !ok || settings["enabled"] != true {
+		return ""
+	}
+	secret, _ := settings["secret"].(string)
+	return secret
+}
+
+// enablePaperForwarding turns on Velocity modern forwarding in a Paper
+// backend's config, keeping the rest of the file and its comments. A
+// missing file is created; Paper fills in its defaults on the next start.
+func enablePaperForwarding(serverDir, secret string) error {
+	path, section := paperForwardingFile(serverDir)
+	return editPaperConfig(path, func(doc *yaml.Node) {
+		setYAMLScalar(doc, append(section, "enabled"), "true", "!!bool")
+		setYAMLScalar(doc, append(section, "online-mode"), "true", "!!bool")
+		setYAMLScalar(doc, append(section, "secret"), secret, "!!str")
+	})
+}
+
+// disablePaperForwarding turns Velocity forwarding off again, so the
+// server accepts players that connect to it directly.
+func disablePaperForwarding(serverDir string) error {
+	if paperForwardingSecret(serverDir) == "" {
+		return nil
+	}
+	path, section := paperForwardingFile(serverDir)
+	return editPaperConfig(path, func(doc *yaml.Node) {
+		setYAMLScalar(doc, append(section, "enabled"), "false", "!!bool")
+	})
+}
+
+// editPaperConfig applies edit to a Paper YAML config and writes it back.
+func editPaperConfig(path string, edit func(doc *yaml.Node)) error {
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+	}
+	edit(&doc)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// setYAMLScalar sets the scalar at path in a YAML document, creating the
+// document and any missing mappings on the way.
+func setYAMLScalar(doc *yaml.Node, path []string, value, tag string) {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		*doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	node := doc.Content[0]
+	for i, key := range path {
+		if node.Kind != yaml.MappingNode {
+			*node = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		var child *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == key {
+				child = node.Content[j+1]
+				break
+			}
+		}
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+		}
+		if i == len(path)-1 {
+			*child = yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+			if tag == "!!str" {
+				child.Style = yaml.SingleQuotedStyle
+			}
+		}
+		node = child
+	}
+}
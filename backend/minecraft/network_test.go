@@ -0,0 +1,117 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// defaultVelocityToml mirrors the parts of a freshly generated velocity.toml
+// the network settings touch, including its multi-line arrays.
+const defaultVelocityToml = `config-version = "2.7"
+bind = "0.0.0.0:25577"
+player-info-forwarding-mode = "NONE"
+
+[servers]
+lobby = "127.0.0.1:30066"
+factions = "127.0.0.1:30067"
+try = [
+    "lobby"
+]
+
+[forced-hosts]
+"lobby.example.com" = [
+    "lobby"
+]
+"factions.example.com" = ["factions"]
+
+[advanced]
+compression-threshold = 256
+`
+
+func TestSetProxyNetworkWritesVelocityAndPaperForwarding(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	proxyDir := filepath.Join(mgr.serversRoot, "Proxy")
+	survivalDir := filepath.Join(mgr.serversRoot, "Survival")
+	moddedDir := filepath.Join(mgr.serversRoot, "Modded")
+	for _, dir := range []string{proxyDir, filepath.Join(survivalDir, "config"), moddedDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("mkdir failed: %v", err)
+		}
+	}
+	os.WriteFile(filepath.Join(proxyDir, "velocity.toml"), []byte(defaultVelocityToml), 0644)
+	os.WriteFile(filepath.Join(survivalDir, "server.properties"), []byte("server-port=25566\nonline-mode=true\n"), 0644)
+	os.WriteFile(filepath.Join(survivalDir, "config", "paper-global.yml"), []byte("# Paper global config\nproxies:\n  velocity:\n    enabled: false\n    online-mode: false\n    secret: ''\nchunk-loading-basic:\n  player-max-chunk-load-rate: 100.0\n"), 0644)
+	mgr.mu.Lock()
+	mgr.configs["proxy"] = &ServerConfig{ID: "proxy", Name: "Proxy", Type: "Velocity", Port: 25577, Dir: proxyDir}
+	mgr.configs["survival"] = &ServerConfig{ID: "survival", Name: "Survival", Type: "Paper", Port: 25566, Dir: survivalDir}
+	mgr.configs["modded"] = &ServerConfig{ID: "modded", Name: "Modded", Type: "Fabric", Port: 25570, Dir: moddedDir}
+	mgr.mu.Unlock()
+
+	if _, err := mgr.SetProxyNetwork("survival", ProxyNetwork{}); err == nil {
+		t.Fatal("expected a non-proxy to be refused")
+	}
+	if _, err := mgr.SetProxyNetwork("proxy", ProxyNetwork{Backends: []NetworkBackend{{ServerID: "survival", Name: "lobby"}}, Try: []string{"hub"}}); err == nil {
+		t.Fatal("expected an unknown join order entry to be refused")
+	}
+
+	result, err := mgr.SetProxyNetwork("proxy", ProxyNetwork{Backends: []NetworkBackend{
+		{ServerID: "survival", Name: "lobby"},
+		{ServerID: "modded"},
+	}})
+	if err != nil {
+		t.Fatalf("SetProxyNetwork failed: %v", err)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "Modded") {
+		t.Fatalf("expected a warning for the Fabric backend, got %v", result.Warnings)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(proxyDir, "velocity.toml"))
+	toml := string(data)
+	for _, want := range []string{`player-info-forwarding-mode = "modern"`, `forwarding-secret-file = "forwarding.secret"`, `lobby = "127.0.0.1:25566"`, `modded = "127.0.0.1:25570"`, `try = ["lobby"]`, `"lobby.example.com" = [`, "compression-threshold = 256"} {
+		if !strings.Contains(toml, want) {
+			t.Fatalf("velocity.toml is missing %q:\n%s", want, toml)
+		}
+	}
+	for _, gone := range []string{"factions", "30066"} {
+		if strings.Contains(toml, gone) {
+			t.Fatalf("velocity.toml still mentions %q:\n%s", gone, toml)
+		}
+	}
+
+	secret := readForwardingSecret(proxyDir)
+	if len(secret) != 32 || paperForwardingSecret(survivalDir) != secret {
+		t.Fatalf("expected the backend to share the proxy's secret, got %q and %q", secret, paperForwardingSecret(survivalDir))
+	}
+	paper, _ := os.ReadFile(filepath.Join(survivalDir, "config", "paper-global.yml"))
+	if !strings.Contains(string(paper), "# Paper global config") || !strings.Contains(string(paper), "player-max-chunk-load-rate") {
+		t.Fatalf("expected the rest of paper-global.yml kept:\n%s", paper)
+	}
+	if props := parseServerPropertiesFile(filepath.Join(survivalDir, "server.properties")); props["online-mode"] != "false" {
+		t.Fatalf("expected online-mode off behind the proxy, got %q", props["online-mode"])
+	}
+
+	networks := mgr.ListNetworks()
+	if len(networks) != 1 || !networks[0].Managed || networks[0].Forwarding != "modern" || !reflect.DeepEqual(networks[0].Try, []string{"lobby"}) {
+		t.Fatalf("unexpected networks %+v", networks)
+	}
+	backends := networks[0].Backends
+	if len(backends) != 2 || backends[0].ServerID != "survival" || !backends[0].Forwarding || backends[1].ServerID != "modded" || backends[1].Forwarding {
+		t.Fatalf("unexpected backends %+v", backends)
+	}
+
+	// Applying again keeps the secret, so running backends stay in sync.
+	if _, err := mgr.SetProxyNetwork("proxy", ProxyNetwork{Backends: []NetworkBackend{{ServerID: "survival", Name: "lobby"}}}); err != nil {
+		t.Fatalf("SetProxyNetwork failed: %v", err)
+	}
+	if readForwardingSecret(proxyDir) != secret {
+		t.Fatal("expected the forwarding secret to be kept")
+	}
+}
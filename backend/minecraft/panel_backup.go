@@ -0,0 +1,245 @@
+package minecraft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	panelBackupDirName        = "panel-backups"
+	panelBackupTimeLayout     = "2006-01-02_15-04-05"
+	defaultPanelBackupKeep    = 14
+	defaultPanelBackupHour    = 3
+	panelBackupMinimumSpacing = 20 * time.Hour
+)
+
+// panelBackupDocuments are the store documents written into each panel
+// snapshot as JSON files, regardless of the storage backend.
+var panelBackupDocuments = []string{storeDocServers, storeDocSettings, storeDocPreferences, storeDocUsers, storeDocModeration, storeDocBackupTargets}
+
+// panelBackupDirs are the data/ directories copied into each panel snapshot.
+var panelBackupDirs = []string{"extension-sources"}
+
+func panelBackupKeepFromEnv() int {
+	raw := strings.TrimSpace(os.Getenv("ADPANEL_PANEL_BACKUP_KEEP"))
+	if raw == "" {
+		return defaultPanelBackupKeep
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		log.Printf("Invalid ADPANEL_PANEL_BACKUP_KEEP value %q, using default %d", raw, defaultPanelBackupKeep)
+		return defaultPanelBackupKeep
+	}
+	return n
+}
+
+func panelBackupHourFromEnv() int {
+	raw := strings.TrimSpace(os.Getenv("ADPANEL_PANEL_BACKUP_HOUR"))
+	if raw == "" {
+		return defaultPanelBackupHour
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 || n > 23 {
+		log.Printf("Invalid ADPANEL_PANEL_BACKUP_HOUR value %q, using default %d", raw, defaultPanelBackupHour)
+		return defaultPanelBackupHour
+	}
+	return n
+}
+
+func (m *Manager) panelBackupRoot() string {
+	return filepath.Join(filepath.Dir(m.dataFile), panelBackupDirName)
+}
+
+// listPanelBackups returns snapshot directory names, newest first.
+func (m *Manager) listPanelBackups() []string {
+	entries, err := os.ReadDir(m.panelBackupRoot())
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := time.ParseInLocation(panelBackupTimeLayout, entry.Name(), time.Local); err != nil {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	return names
+}
+
+func (m *Manager) latestPanelBackupTime() time.Time {
+	names := m.listPanelBackups()
+	if len(names) == 0 {
+		return time.Time{}
+	}
+	t, _ := time.ParseInLocation(panelBackupTimeLayout, names[0], time.Local)
+	return t
+}
+
+// snapshotPanelData copies the panel's data files into a timestamped
+// snapshot and prunes old snapshots beyond the retention count.
+func (m *Manager) snapshotPanelData() error {
+	dataDir := filepath.Dir(m.dataFile)
+	snapshotDir := filepath.Join(m.panelBackupRoot(), time.Now().Format(panelBackupTimeLayout))
+	if err := os.MkdirAll(snapshotDir, 0700); err != nil {
+		return fmt.Errorf("failed to create panel backup directory: %w", err)
+	}
+
+	if err := m.flushPersist(); err != nil {
+		log.Printf("Panel backup proceeding with unsaved changes: %v", err)
+	}
+
+	// The servers and settings are encoded from memory under their locks,
+	// so the snapshot matches what the panel holds; the disk writes happen
+	// after the locks are released.
+	docs := make(map[string][]byte, 2)
+	var marshalErr error
+	m.mu.RLock()
+	docs[storeDocServers], marshalErr = m.marshalConfigsLocked()
+	m.mu.RUnlock()
+	if marshalErr == nil {
+		m.settingsMu.RLock()
+		docs[storeDocSettings], marshalErr = json.MarshalIndent(m.settings, "", "  ")
+		m.settingsMu.RUnlock()
+	}
+	if marshalErr != nil {
+		_ = os.RemoveAll(snapshotDir)
+		return marshalErr
+	}
+
+	var copyErr error
+	for _, name := range panelBackupDocuments {
+		data, ok := docs[name]
+		if !ok {
+			var err error
+			if data, err = m.storage().Load(name); err != nil {
+				continue
+			}
+		}
+		if err := os.WriteFile(filepath.Join(snapshotDir, name), data, 0600); err != nil {
+			copyErr = fmt.Errorf("failed to copy %s: %w", name, err)
+			break
+		}
+	}
+	for _, name := range panelBackupDirs {
+		if copyErr != nil {
+			break
+		}
+		src := filepath.Join(dataDir, name)
+		if info, err := os.Stat(src); err != nil || !info.IsDir() {
+			continue
+		}
+		if err := copyTree(context.Background(), src, filepath.Join(snapshotDir, name), nil); err != nil {
+			copyErr = fmt.Errorf("failed to copy %s: %w", name, err)
+		}
+	}
+	if copyErr != nil {
+		_ = os.RemoveAll(snapshotDir)
+		return copyErr
+	}
+
+	keep := panelBackupKeepFromEnv()
+	for i, name := range m.listPanelBackups() {
+		if i < keep {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(m.panelBackupRoot(), name)); err != nil {
+			log.Printf("Failed to prune panel backup %s: %v", name, err)
+		}
+	}
+	log.Printf("Panel data backup written to %s", snapshotDir)
+	return nil
+}
+
+// runPanelDataBackups takes a nightly snapshot of data/ at the configured
+// local hour. A snapshot is also taken at startup when none exist yet.
+func (m *Manager) runPanelDataBackups() {
+	defer close(m.panelBackupDone)
+	if m.latestPanelBackupTime().IsZero() {
+		if err := m.snapshotPanelData(); err != nil {
+			log.Printf("Initial panel data backup failed: %v", err)
+		}
+	}
+
+	hour := panelBackupHourFromEnv()
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopPanelBackup:
+			return
+		case now := <-ticker.C:
+			if now.Hour() != hour || time.Since(m.latestPanelBackupTime()) < panelBackupMinimumSpacing {
+				continue
+			}
+			if err := m.snapshotPanelData(); err != nil {
+				log.Printf("Nightly panel data backup failed: %v", err)
+			}
+		}
+	}
+}
+
+// recoverDataFile replaces a corrupt store document with the newest snapshot
+// copy that passes validate. The corrupt data is kept in data/ for inspection.
+func (m *Manager) recoverDataFile(name string, validate func([]byte) error) ([]byte, error) {
+	store := m.storage()
+	for _, snapshot := range m.listPanelBackups() {
+		data, err := os.ReadFile(filepath.Join(m.panelBackupRoot(), snapshot, name))
+		if err != nil || validate(data) != nil {
+			continue
+		}
+		corruptPath := fmt.Sprintf("%s.corrupt-%s", filepath.Join(filepath.Dir(m.dataFile), name), time.Now().Format(panelBackupTimeLayout))
+		if current, err := store.Load(name); err == nil {
+			if err := os.WriteFile(corruptPath, current, 0600); err != nil {
+				return nil, fmt.Errorf("failed to set aside corrupt %s: %w", name, err)
+			}
+		}
+		if err := store.Save(name, data); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", name, err)
+		}
+		log.Printf("Recovered corrupt %s from panel backup %s (corrupt copy kept at %s)", name, snapshot, corruptPath)
+		return data, nil
+	}
+	return nil, fmt.Errorf("no valid panel backup of %s found", name)
+}
+
+func validateServersJSON(data []byte) error {
+	var entries []json.RawMessage
+	return json.Unmarshal(data, &entries)
+}
+
+func validateSettingsJSON(data []byte) error {
+	var settings AppSettings
+	return json.Unmarshal(data, &settings)
+}
+
+func validateUsersJSON(data []byte) error {
+	var accounts []userAccount
+	return json.Unmarshal(data, &accounts)
+}
+
+func validatePreferencesJSON(data []byte) error {
+	var prefs map[string]UserPreferences
+	return json.Unmarshal(data, &prefs)
+}
+
+func validateModerationJSON(data []byte) error {
+	var doc moderationDocument
+	return json.Unmarshal(data, &doc)
+}
+
+func validateBackupTargetsJSON(data []byte) error {
+	var targets []BackupTarget
+	return json.Unmarshal(data, &targets)
+}
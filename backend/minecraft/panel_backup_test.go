@@ -0,0 +1,77 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewManagerRecoversCorruptServersJSONFromPanelBackup(t *testing.T) {
+	base := t.TempDir()
+	dataDir := filepath.Join(base, "data")
+	snapshotDir := filepath.Join(dataDir, panelBackupDirName, time.Now().Add(-time.Hour).Format(panelBackupTimeLayout))
+	if err := os.MkdirAll(snapshotDir, 0755); err != nil {
+		t.Fatalf("failed to create snapshot dir: %v", err)
+	}
+
+	good, err := json.Marshal([]*ServerConfig{{
+		ID:      "srv1",
+		Name:    "Lobby",
+		Type:    "Paper",
+		Version: "1.21.4",
+		Port:    25565,
+		Dir:     filepath.Join(base, "Servers", "Lobby"),
+	}})
+	if err != nil {
+		t.Fatalf("failed to marshal config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(snapshotDir, "servers.json"), good, 0644); err != nil {
+		t.Fatalf("failed to write snapshot: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, "servers.json"), []byte("[{\"id\": \"srv1\", "), 0644); err != nil {
+		t.Fatalf("failed to write corrupt servers.json: %v", err)
+	}
+
+	mgr, err := NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	if cfg := mgr.configs["srv1"]; cfg == nil || cfg.Name != "Lobby" {
+		t.Fatalf("expected server to be recovered from snapshot, got %+v", cfg)
+	}
+	matches, _ := filepath.Glob(filepath.Join(dataDir, "servers.json.corrupt-*"))
+	if len(matches) != 1 {
+		t.Fatalf("expected corrupt copy to be kept, found %v", matches)
+	}
+}
+
+func TestSnapshotPanelDataPrunesOldSnapshots(t *testing.T) {
+	t.Setenv("ADPANEL_PANEL_BACKUP_KEEP", "2")
+	dataDir := filepath.Join(t.TempDir(), "data")
+	root := filepath.Join(dataDir, panelBackupDirName)
+	for _, age := range []time.Duration{72 * time.Hour, 48 * time.Hour, 24 * time.Hour} {
+		if err := os.MkdirAll(filepath.Join(root, time.Now().Add(-age).Format(panelBackupTimeLayout)), 0755); err != nil {
+			t.Fatalf("failed to seed snapshot: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, "servers.json"), []byte("[]"), 0644); err != nil {
+		t.Fatalf("failed to write servers.json: %v", err)
+	}
+
+	mgr := &Manager{dataFile: filepath.Join(dataDir, "servers.json")}
+	if err := mgr.snapshotPanelData(); err != nil {
+		t.Fatalf("snapshot failed: %v", err)
+	}
+
+	names := mgr.listPanelBackups()
+	if len(names) != 2 {
+		t.Fatalf("expected 2 snapshots after pruning, got %v", names)
+	}
+	if _, err := os.Stat(filepath.Join(root, names[0], "servers.json")); err != nil {
+		t.Fatalf("expected newest snapshot to contain servers.json: %v", err)
+	}
+}
@@ -0,0 +1,465 @@
+package minecraft
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const panelConfigFormat = "orexa-panel-config"
+const panelConfigVersion = 1
+const maxPanelConfigEntryBytes = 16 * 1024 * 1024
+
+var ErrPanelNotEmpty = errors.New("panel already has servers; import is only allowed on a fresh install")
+
+var panelConfigIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// PanelConfigManifest describes a panel configuration archive.
+type PanelConfigManifest struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	ExportedAt string `json:"exportedAt"`
+	Servers    int    `json:"servers"`
+	Users      int    `json:"users"`
+}
+
+// PanelConfigImportResult summarizes what an import restored.
+type PanelConfigImportResult struct {
+	Servers          int      `json:"servers"`
+	Users            int      `json:"users"`
+	Documents        []string `json:"documents,omitempty"`
+	ExtensionSources int      `json:"extensionSources"`
+	SettingsRestored bool     `json:"settingsRestored"`
+	MissingFiles     []string `json:"missingFiles,omitempty"`
+}
+
+// panelConfigStoreDoc is a store document an import copies as it is,
+// after checking it with validate, while holding mu.
+type panelConfigStoreDoc struct {
+	name     string
+	mu       *sync.Mutex
+	validate func([]byte) error
+	data     []byte
+}
+
+// panelConfigStoreDocs are the panel backup documents without in-memory
+// state: preferences, moderation presets and backup targets.
+func (m *Manager) panelConfigStoreDocs() []panelConfigStoreDoc {
+	return []panelConfigStoreDoc{
+		{name: storeDocPreferences, mu: &m.prefsMu, validate: validatePreferencesJSON},
+		{name: storeDocModeration, mu: &m.moderationMu, validate: validateModerationJSON},
+		{name: storeDocBackupTargets, mu: &m.backupTargetsMu, validate: validateBackupTargetsJSON},
+	}
+}
+
+// ExportPanelConfig writes the panel's own configuration (every document
+// of a panel backup and the extension sources, but no world data) as a
+// tar.gz archive.
+func (m *Manager) ExportPanelConfig(w io.Writer) error {
+	m.mu.RLock()
+	configs := make([]ServerConfig, 0, len(m.configs))
+	for _, cfg := range m.configs {
+		configs = append(configs, *cfg)
+	}
+	m.mu.RUnlock()
+
+	serversData, err := json.MarshalIndent(configs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal servers: %w", err)
+	}
+
+	m.settingsMu.RLock()
+	settingsData, err := json.MarshalIndent(m.settings, "", "  ")
+	m.settingsMu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal settings: %w", err)
+	}
+
+	m.usersMu.Lock()
+	usersData, err := m.marshalUsersLocked()
+	userCount := len(m.users)
+	m.usersMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	manifestData, err := json.MarshalIndent(PanelConfigManifest{
+		Format:     panelConfigFormat,
+		Version:    panelConfigVersion,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Servers:    len(configs),
+		Users:      userCount,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	writeEntry := func(name string, data []byte, mode int64) error {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    mode,
+			Size:    int64(len(data)),
+			ModTime: time.Now(),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err := writeEntry("manifest.json", manifestData, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	docs := map[string][]byte{storeDocServers: serversData, storeDocSettings: settingsData, storeDocUsers: usersData}
+	for _, name := range panelBackupDocuments {
+		data, ok := docs[name]
+		if !ok {
+			if data, err = m.storage().Load(name); err != nil {
+				continue
+			}
+		}
+		if err := writeEntry(name, data, 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	for i := range configs {
+		data, err := os.ReadFile(m.extensionSourcesPath(&configs[i]))
+		if err != nil {
+			continue
+		}
+		name := path.Join("extension-sources", filepath.Base(m.extensionSourcesPath(&configs[i])))
+		if err := writeEntry(name, data, 0644); err != nil {
+			return fmt.Errorf("failed to write extension sources: %w", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	return gz.Close()
+}
+
+// ImportPanelConfig restores a configuration archive produced by
+// ExportPanelConfig. Servers are recreated under the managed servers root;
+// their files must be restored separately. User accounts in the archive
+// replace the panel's own.
+func (m *Manager) ImportPanelConfig(r io.Reader) (*PanelConfigImportResult, error) {
+	entries, err := readPanelConfigArchive(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest PanelConfigManifest
+	if err := json.Unmarshal(entries["manifest.json"], &manifest); err != nil || manifest.Format != panelConfigFormat {
+		return nil, fmt.Errorf("archive is not a panel configuration export")
+	}
+	if manifest.Version > panelConfigVersion {
+		return nil, fmt.Errorf("unsupported panel configuration version %d", manifest.Version)
+	}
+
+	var configs []*ServerConfig
+	if data, ok := entries["servers.json"]; ok {
+		if err := json.Unmarshal(data, &configs); err != nil {
+			return nil, fmt.Errorf("invalid servers.json in archive: %w", err)
+		}
+	}
+
+	var settings *AppSettings
+	if data, ok := entries["settings.json"]; ok {
+		settings = &AppSettings{}
+		if err := json.Unmarshal(data, settings); err != nil {
+			return nil, fmt.Errorf("invalid settings.json in archive: %w", err)
+		}
+	}
+
+	var users map[string]userAccount
+	if data, ok := entries[storeDocUsers]; ok {
+		var accounts []userAccount
+		if err := json.Unmarshal(data, &accounts); err != nil {
+			return nil, fmt.Errorf("invalid users.json in archive: %w", err)
+		}
+		primary := m.primaryLoginUser()
+		if settings != nil && strings.TrimSpace(settings.LoginUser) != "" {
+			primary = settings.LoginUser
+		}
+		if users, err = panelConfigUsers(accounts, primary); err != nil {
+			return nil, err
+		}
+	}
+
+	var storeDocs []panelConfigStoreDoc
+	for _, doc := range m.panelConfigStoreDocs() {
+		data, ok := entries[doc.name]
+		if !ok {
+			continue
+		}
+		if err := doc.validate(data); err != nil {
+			return nil, fmt.Errorf("invalid %s in archive: %w", doc.name, err)
+		}
+		doc.data = data
+		storeDocs = append(storeDocs, doc)
+	}
+
+	m.mu.Lock()
+	if len(m.configs) > 0 {
+		m.mu.Unlock()
+		return nil, ErrPanelNotEmpty
+	}
+
+	// Every server is checked before any is registered, so a bad entry
+	// leaves the panel empty and the import can be retried.
+	result := &PanelConfigImportResult{}
+	imported := make([]*ServerConfig, 0, len(configs))
+	ids := make(map[string]bool, len(configs))
+	dirs := make(map[string]string, len(configs))
+	for _, cfg := range configs {
+		if cfg == nil {
+			continue
+		}
+		if !panelConfigIDPattern.MatchString(cfg.ID) {
+			m.mu.Unlock()
+			return nil, fmt.Errorf("server %q has an invalid id", cfg.Name)
+		}
+		if ids[cfg.ID] {
+			m.mu.Unlock()
+			return nil, fmt.Errorf("duplicate server id %s in archive", cfg.ID)
+		}
+		ids[cfg.ID] = true
+		dirName := sanitizeName(filepath.Base(filepath.Clean(cfg.Dir)))
+		if dirName == "server" {
+			dirName = sanitizeName(cfg.Name)
+		}
+		cfg.Dir = filepath.Join(m.serversRoot, dirName)
+		if err := m.validateManagedServerDir(cfg.Dir); err != nil {
+			m.mu.Unlock()
+			return nil, fmt.Errorf("server %q: %w", cfg.Name, err)
+		}
+		if other, taken := dirs[cfg.Dir]; taken {
+			m.mu.Unlock()
+			return nil, fmt.Errorf("servers %q and %q would share the folder %s", other, cfg.Name, dirName)
+		}
+		dirs[cfg.Dir] = cfg.Name
+		cfg.ScheduledRestartAt = ""
+		cfg.ScheduledRestartReason = ""
+		imported = append(imported, cfg)
+	}
+
+	// Folders this import creates are removed again if it fails, and the
+	// previous accounts and documents are put back.
+	var created []string
+	var previousUsers map[string]userAccount
+	var replaced []panelConfigStoreDoc
+	rollback := func() {
+		for i := len(replaced) - 1; i >= 0; i-- {
+			doc := replaced[i]
+			doc.mu.Lock()
+			var err error
+			if doc.data == nil {
+				err = m.storage().Delete(doc.name)
+			} else {
+				err = m.storage().Save(doc.name, doc.data)
+			}
+			doc.mu.Unlock()
+			if err != nil {
+				log.Printf("Failed to restore %s after a failed import: %v", doc.name, err)
+			}
+		}
+		for _, cfg := range imported {
+			delete(m.configs, cfg.ID)
+			delete(m.running, cfg.ID)
+		}
+		if previousUsers != nil {
+			m.usersMu.Lock()
+			m.users = previousUsers
+			if err := m.persistUsersLocked(); err != nil {
+				log.Printf("Failed to restore users after a failed import: %v", err)
+			}
+			m.usersMu.Unlock()
+		}
+		for i := len(created) - 1; i >= 0; i-- {
+			os.Remove(created[i])
+		}
+	}
+	for _, cfg := range imported {
+		if _, err := os.Stat(cfg.Dir); os.IsNotExist(err) {
+			if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+				rollback()
+				m.mu.Unlock()
+				return nil, fmt.Errorf("failed to create directory for %q: %w", cfg.Name, err)
+			}
+			created = append(created, cfg.Dir)
+		}
+	}
+
+	for _, cfg := range imported {
+		rs := &runningServer{
+			status:      "Stopped",
+			logBuffer:   make([]ConsoleLogEntry, 0),
+			nextLogSeq:  1,
+			players:     make(map[string]*onlinePlayer),
+			pingBlocked: make(map[string]bool),
+		}
+		rs.logMaxLines, rs.logTrimLines = consoleBufferLimits(cfg)
+		if dirEntries, err := os.ReadDir(cfg.Dir); err != nil || len(dirEntries) == 0 {
+			rs.status = "Error"
+			rs.installError = "Server files are missing. Restore a backup or retry the install."
+			result.MissingFiles = append(result.MissingFiles, cfg.Name)
+		}
+		m.configs[cfg.ID] = cfg
+		m.running[cfg.ID] = rs
+	}
+	m.normalizeServerOrderLocked()
+	if users != nil {
+		m.usersMu.Lock()
+		previous := m.users
+		m.users = users
+		err := m.persistUsersLocked()
+		if err != nil {
+			m.users = previous
+		}
+		m.usersMu.Unlock()
+		if err != nil {
+			rollback()
+			m.mu.Unlock()
+			return nil, err
+		}
+		previousUsers = previous
+	}
+	for _, doc := range storeDocs {
+		doc.mu.Lock()
+		previous, err := m.storage().Load(doc.name)
+		if errors.Is(err, os.ErrNotExist) {
+			previous, err = nil, nil
+		}
+		if err == nil {
+			err = m.storage().Save(doc.name, doc.data)
+		}
+		doc.mu.Unlock()
+		if err != nil {
+			rollback()
+			m.mu.Unlock()
+			return nil, fmt.Errorf("failed to restore %s: %w", doc.name, err)
+		}
+		replaced = append(replaced, panelConfigStoreDoc{name: doc.name, mu: doc.mu, data: previous})
+		result.Documents = append(result.Documents, doc.name)
+	}
+	if err := m.persist(); err != nil {
+		rollback()
+		m.mu.Unlock()
+		return nil, err
+	}
+	result.Servers = len(imported)
+	result.Users = len(users)
+	m.mu.Unlock()
+
+	for _, cfg := range imported {
+		data, ok := entries[path.Join("extension-sources", cfg.ID+".json")]
+		if !ok {
+			continue
+		}
+		manifest, err := decodeExtensionManifest(data)
+		if err != nil {
+			log.Printf("Skipping invalid extension sources for %s: %v", cfg.Name, err)
+			continue
+		}
+		if err := m.saveExtensionManifest(cfg, manifest); err != nil {
+			log.Printf("Failed to restore extension sources for %s: %v", cfg.Name, err)
+			continue
+		}
+		result.ExtensionSources++
+	}
+
+	if settings != nil {
+		m.settingsMu.Lock()
+		if strings.TrimSpace(settings.LoginPasswordHash) == "" {
+			settings.LoginPasswordHash = m.settings.LoginPasswordHash
+		}
+		if strings.TrimSpace(settings.CurseForgeAPIKey) == "" {
+			settings.CurseForgeAPIKey = m.settings.CurseForgeAPIKey
+		}
+		settings.CurseForgeAPIKeySet = false
+		applySettingsDefaults(settings)
+		m.settings = *settings
+		setUserAgentOverride(settings.UserAgent)
+		setCurseForgeAPIKeyOverride(settings.CurseForgeAPIKey)
+		err := m.persistSettings()
+		m.settingsMu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		result.SettingsRestored = true
+	}
+
+	log.Printf("Imported panel configuration: %d servers, %d users, %d extension source files", result.Servers, result.Users, result.ExtensionSources)
+	return result, nil
+}
+
+// panelConfigUsers checks the accounts from an archive. primary is the
+// settings login the import leaves in place, which no account may reuse.
+func panelConfigUsers(accounts []userAccount, primary string) (map[string]userAccount, error) {
+	if len(accounts) > maxUserAccounts {
+		return nil, fmt.Errorf("archive has more than %d users", maxUserAccounts)
+	}
+	users := make(map[string]userAccount, len(accounts))
+	for _, account := range accounts {
+		account.Username = strings.TrimSpace(account.Username)
+		if account.Username == "" || !IsValidRole(account.Role) || account.PasswordHash == "" {
+			return nil, fmt.Errorf("user %q in archive is invalid", account.Username)
+		}
+		if _, exists := users[account.Username]; exists || account.Username == primary {
+			return nil, fmt.Errorf("duplicate user %s in archive", account.Username)
+		}
+		users[account.Username] = account
+	}
+	return users, nil
+}
+
+func readPanelConfigArchive(r io.Reader) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("archive is not gzip-compressed")
+	}
+	defer gz.Close()
+
+	entries := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(strings.TrimPrefix(hdr.Name, "./"))
+		if strings.HasPrefix(name, "..") || path.IsAbs(name) {
+			return nil, fmt.Errorf("archive entry %q is not allowed", hdr.Name)
+		}
+		if hdr.Size > maxPanelConfigEntryBytes {
+			return nil, fmt.Errorf("archive entry %q is too large", hdr.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxPanelConfigEntryBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive entry %q: %w", hdr.Name, err)
+		}
+		entries[name] = data
+	}
+	return entries, nil
+}
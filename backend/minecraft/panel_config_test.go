@@ -0,0 +1,229 @@
+package minecraft
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPanelConfigExportImportRoundTrip(t *testing.T) {
+	src, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer src.StopAll()
+
+	cfg := &ServerConfig{
+		ID:             "abc123",
+		Name:           "Survival",
+		Type:           "Paper",
+		Version:        "1.21.4",
+		Port:           25565,
+		JarFile:        "server.jar",
+		Dir:            filepath.Join(src.serversRoot, "Survival"),
+		BackupSchedule: "daily",
+	}
+	src.mu.Lock()
+	src.configs[cfg.ID] = cfg
+	src.mu.Unlock()
+	if err := src.saveExtensionManifest(cfg, map[string]*ExtensionProvenance{"essentials": {SourceURL: "https://modrinth.com/plugin/essentialsx"}}); err != nil {
+		t.Fatalf("failed to save extension sources: %v", err)
+	}
+	if _, err := src.CreateUser(UserInput{Username: "helper", Password: "helperpass123", Role: RoleOperator}); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if _, err := src.SetUserPreferences("helper", UserPreferences{FavoriteServerIDs: []string{"abc123"}}); err != nil {
+		t.Fatalf("SetUserPreferences failed: %v", err)
+	}
+	if _, err := src.SetModerationPresets([]ModerationPreset{{Reason: "Griefing"}}); err != nil {
+		t.Fatalf("SetModerationPresets failed: %v", err)
+	}
+	if _, err := src.SetBackupTargets([]BackupTarget{{Name: "Offsite S3", Type: BackupTargetS3, Bucket: "mc", AccessKey: "AKID", SecretKey: "secret"}}); err != nil {
+		t.Fatalf("SetBackupTargets failed: %v", err)
+	}
+
+	var archive bytes.Buffer
+	if err := src.ExportPanelConfig(&archive); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	dst, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer dst.StopAll()
+
+	result, err := dst.ImportPanelConfig(bytes.NewReader(archive.Bytes()))
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if result.Servers != 1 || result.Users != 1 || len(result.Documents) != 3 || result.ExtensionSources != 1 || !result.SettingsRestored {
+		t.Fatalf("unexpected import result: %+v", result)
+	}
+	if _, ok := dst.AuthenticateUser("helper", "helperpass123"); !ok {
+		t.Fatal("expected the user account to survive import")
+	}
+	if prefs, err := dst.GetUserPreferences("helper"); err != nil || len(prefs.FavoriteServerIDs) != 1 {
+		t.Fatalf("expected preferences to survive import, got %+v (%v)", prefs, err)
+	}
+	if presets, err := dst.GetModerationPresets(); err != nil || len(presets) != 1 || presets[0].Reason != "Griefing" {
+		t.Fatalf("expected moderation presets to survive import, got %+v (%v)", presets, err)
+	}
+	if target, err := dst.backupTarget("offsite-s3"); err != nil || target.SecretKey != "secret" {
+		t.Fatalf("expected backup targets to survive import, got %+v (%v)", target, err)
+	}
+
+	imported := dst.configs["abc123"]
+	if imported == nil {
+		t.Fatal("expected server to be imported")
+	}
+	if imported.Dir != filepath.Join(dst.serversRoot, "Survival") {
+		t.Fatalf("expected server dir to be rebased under the new root, got %s", imported.Dir)
+	}
+	if imported.BackupSchedule != "daily" {
+		t.Fatalf("expected backup schedule to survive import, got %q", imported.BackupSchedule)
+	}
+	if _, err := os.Stat(imported.Dir); err != nil {
+		t.Fatalf("expected server dir to be created: %v", err)
+	}
+	if got := dst.loadExtensionManifest(imported)["essentials"]; got == nil || got.SourceURL == "" {
+		t.Fatal("expected extension sources to be restored")
+	}
+	if status := dst.running["abc123"].status; status != "Error" {
+		t.Fatalf("expected server without files to need attention, got %s", status)
+	}
+
+	if _, err := dst.ImportPanelConfig(bytes.NewReader(archive.Bytes())); !errors.Is(err, ErrPanelNotEmpty) {
+		t.Fatalf("expected second import to be rejected, got %v", err)
+	}
+}
+
+func TestPanelConfigImportLeavesPanelEmptyOnBadEntry(t *testing.T) {
+	src, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer src.StopAll()
+
+	// Both folders are named Survival, so they would share one folder on
+	// the new panel.
+	src.mu.Lock()
+	src.configs["aaa111"] = &ServerConfig{ID: "aaa111", Name: "Survival", Type: "Paper", Port: 25565, Dir: filepath.Join(src.serversRoot, "Survival")}
+	src.configs["bbb222"] = &ServerConfig{ID: "bbb222", Name: "Survival 2", Type: "Paper", Port: 25566, Dir: filepath.Join(src.serversRoot, "old", "Survival")}
+	src.mu.Unlock()
+	var archive bytes.Buffer
+	if err := src.ExportPanelConfig(&archive); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	dst, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer dst.StopAll()
+	if _, err := dst.ImportPanelConfig(bytes.NewReader(archive.Bytes())); err == nil {
+		t.Fatal("expected servers sharing a folder to be refused")
+	}
+	dst.mu.RLock()
+	left := len(dst.configs) + len(dst.running)
+	dst.mu.RUnlock()
+	if left != 0 {
+		t.Fatalf("expected a failed import to register nothing, got %d entries", left)
+	}
+	if entries, _ := os.ReadDir(dst.serversRoot); len(entries) != 0 {
+		t.Fatalf("expected a failed import to create no folders, got %d", len(entries))
+	}
+
+	src.mu.Lock()
+	src.configs["bbb222"].Dir = filepath.Join(src.serversRoot, "Creative")
+	src.mu.Unlock()
+	archive.Reset()
+	if err := src.ExportPanelConfig(&archive); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	result, err := dst.ImportPanelConfig(bytes.NewReader(archive.Bytes()))
+	if err != nil || result.Servers != 2 {
+		t.Fatalf("expected a retry to import both servers, got %+v (%v)", result, err)
+	}
+}
+
+func TestPanelConfigImportRejectsDuplicateUsers(t *testing.T) {
+	dst, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer dst.StopAll()
+	if _, err := dst.CreateUser(UserInput{Username: "keeper", Password: "keeperpass123", Role: RoleViewer}); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+
+	archive := panelConfigArchive(t, map[string]string{
+		"manifest.json": `{"format":"orexa-panel-config","version":1}`,
+		"servers.json":  `[{"id":"abc123","name":"Survival","type":"Paper","port":25565,"dir":"/old/Survival"}]`,
+		"users.json": `[{"username":"alice","role":"admin","passwordHash":"x"},
+			{"username":"alice","role":"viewer","passwordHash":"y"}]`,
+	})
+	if _, err := dst.ImportPanelConfig(bytes.NewReader(archive)); err == nil || !strings.Contains(err.Error(), "duplicate user alice") {
+		t.Fatalf("expected duplicate usernames to be refused, got %v", err)
+	}
+	dst.mu.RLock()
+	left := len(dst.configs)
+	dst.mu.RUnlock()
+	if left != 0 {
+		t.Fatalf("expected a failed import to register no servers, got %d", left)
+	}
+	if users := dst.ListUsers(); len(users) != 2 || users[1].Username != "keeper" {
+		t.Fatalf("expected the panel's accounts to be kept, got %+v", users)
+	}
+}
+
+func TestPanelConfigImportRejectsBadDocument(t *testing.T) {
+	dst, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer dst.StopAll()
+
+	archive := panelConfigArchive(t, map[string]string{
+		"manifest.json":       `{"format":"orexa-panel-config","version":1}`,
+		"servers.json":        `[{"id":"abc123","name":"Survival","type":"Paper","port":25565,"dir":"/old/Survival"}]`,
+		"users.json":          `[{"username":"alice","role":"admin","passwordHash":"x"}]`,
+		"backup_targets.json": `{"not":"a list"}`,
+	})
+	if _, err := dst.ImportPanelConfig(bytes.NewReader(archive)); err == nil || !strings.Contains(err.Error(), "backup_targets.json") {
+		t.Fatalf("expected the broken document to be refused, got %v", err)
+	}
+	dst.mu.RLock()
+	left := len(dst.configs)
+	dst.mu.RUnlock()
+	if left != 0 || len(dst.ListUsers()) != 1 {
+		t.Fatalf("expected a failed import to change nothing, got %d servers and %+v", left, dst.ListUsers())
+	}
+}
+
+func panelConfigArchive(t *testing.T, entries map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close archive: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("failed to close archive: %v", err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,163 @@
+package minecraft
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	persistDebounce   = 250 * time.Millisecond
+	persistRetryDelay = 5 * time.Second
+)
+
+// PersistenceStatus reports the state of the background metadata writer.
+type PersistenceStatus struct {
+	Backend         string `json:"backend"`
+	Pending         bool   `json:"pending"`
+	Writes          int64  `json:"writes"`
+	CoalescedWrites int64  `json:"coalescedWrites"`
+	LastWriteAt     string `json:"lastWriteAt,omitempty"`
+	LastError       string `json:"lastError,omitempty"`
+	LastErrorAt     string `json:"lastErrorAt,omitempty"`
+}
+
+// persistWriter coalesces document saves and writes them from a timer
+// goroutine, so callers holding the Manager lock never wait on disk IO.
+// Only the newest payload of each document is kept while a write is pending.
+type persistWriter struct {
+	save    func(name string, data []byte) error
+	backend string
+
+	writeMu sync.Mutex // serializes flushes so older payloads never win
+
+	mu          sync.Mutex
+	pending     map[string][]byte
+	timer       *time.Timer
+	writes      int64
+	coalesced   int64
+	lastWriteAt time.Time
+	lastErr     error
+	lastErrAt   time.Time
+}
+
+func newPersistWriter(backend string, save func(name string, data []byte) error) *persistWriter {
+	return &persistWriter{
+		save:    save,
+		backend: backend,
+		pending: make(map[string][]byte),
+	}
+}
+
+// schedule queues data as the next version of the named document.
+func (w *persistWriter) schedule(name string, data []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.pending[name]; ok {
+		w.coalesced++
+	}
+	w.pending[name] = data
+	w.armLocked(persistDebounce)
+}
+
+func (w *persistWriter) armLocked(delay time.Duration) {
+	if w.timer != nil {
+		return
+	}
+	w.timer = time.AfterFunc(delay, func() {
+		w.Flush()
+	})
+}
+
+// Flush writes any pending documents immediately and returns the first
+// error. Failed documents stay pending and are retried later unless a newer
+// version has been scheduled in the meantime.
+func (w *persistWriter) Flush() error {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+
+	w.mu.Lock()
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	batch := w.pending
+	w.pending = make(map[string][]byte)
+	w.mu.Unlock()
+
+	var firstErr error
+	for name, data := range batch {
+		err := w.save(name, data)
+
+		w.mu.Lock()
+		if err != nil {
+			w.lastErr = err
+			w.lastErrAt = time.Now()
+			if _, newer := w.pending[name]; !newer {
+				w.pending[name] = data
+			}
+			w.armLocked(persistRetryDelay)
+		} else {
+			w.writes++
+			w.lastWriteAt = time.Now()
+			w.lastErr = nil
+		}
+		w.mu.Unlock()
+
+		if err != nil {
+			log.Printf("Failed to persist %s (will retry): %v", name, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// stop flushes pending writes and prevents the retry timer from firing.
+func (w *persistWriter) stop() error {
+	err := w.Flush()
+	w.mu.Lock()
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	w.mu.Unlock()
+	return err
+}
+
+func (w *persistWriter) status() PersistenceStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	status := PersistenceStatus{
+		Backend:         w.backend,
+		Pending:         len(w.pending) > 0,
+		Writes:          w.writes,
+		CoalescedWrites: w.coalesced,
+	}
+	if !w.lastWriteAt.IsZero() {
+		status.LastWriteAt = w.lastWriteAt.UTC().Format(time.RFC3339)
+	}
+	if w.lastErr != nil {
+		status.LastError = w.lastErr.Error()
+		status.LastErrorAt = w.lastErrAt.UTC().Format(time.RFC3339)
+	}
+	return status
+}
+
+// GetPersistenceStatus reports whether panel metadata has been written to
+// disk and the last write error, if any.
+func (m *Manager) GetPersistenceStatus() PersistenceStatus {
+	if m.persister == nil {
+		return PersistenceStatus{Backend: storageBackendJSON}
+	}
+	return m.persister.status()
+}
+
+// flushPersist blocks until scheduled metadata writes have reached the store.
+func (m *Manager) flushPersist() error {
+	if m.persister == nil {
+		return nil
+	}
+	return m.persister.Flush()
+}
@@ -0,0 +1,49 @@
+package minecraft
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestPersistWriterCoalescesAndRetries(t *testing.T) {
+	var mu sync.Mutex
+	saved := make(map[string]string)
+	fail := true
+	w := newPersistWriter(storageBackendJSON, func(name string, data []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			return errors.New("disk full")
+		}
+		saved[name] = string(data)
+		return nil
+	})
+
+	w.schedule(storeDocServers, []byte("v1"))
+	w.schedule(storeDocServers, []byte("v2"))
+	if err := w.Flush(); err == nil {
+		t.Fatalf("expected flush to report the save error")
+	}
+	status := w.status()
+	if !status.Pending || status.LastError != "disk full" || status.CoalescedWrites != 1 {
+		t.Fatalf("unexpected status after failed flush: %+v", status)
+	}
+
+	mu.Lock()
+	fail = false
+	mu.Unlock()
+	if err := w.stop(); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	mu.Lock()
+	got := saved[storeDocServers]
+	mu.Unlock()
+	if got != "v2" {
+		t.Fatalf("expected newest payload to be written, got %q", got)
+	}
+	status = w.status()
+	if status.Pending || status.LastError != "" || status.Writes != 1 {
+		t.Fatalf("unexpected status after successful flush: %+v", status)
+	}
+}
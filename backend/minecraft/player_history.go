@@ -0,0 +1,278 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// playerHistoryPeakDays is how many days of daily peaks are kept.
+	playerHistoryPeakDays     = 90
+	defaultPlayerHistoryDays  = 30
+	playerHistoryDateLayout   = "2006-01-02"
+	maxPlayerHistoryPerServer = 5000
+)
+
+// PlayerHistoryEntry is what the panel remembers about one player on one
+// server. PlaytimeSeconds includes the session in progress, if any.
+type PlayerHistoryEntry struct {
+	Name            string `json:"name"`
+	FirstSeen       string `json:"firstSeen"`
+	LastSeen        string `json:"lastSeen"`
+	PlaytimeSeconds int64  `json:"playtimeSeconds"`
+	Sessions        int    `json:"sessions"`
+	OnlineSince     string `json:"onlineSince,omitempty"`
+}
+
+// DailyPeak is the most players online at once on one day, in the panel's
+// local time.
+type DailyPeak struct {
+	Date    string `json:"date"`
+	Players int    `json:"players"`
+}
+
+// PlayerHistory is a server's player history, most recently seen first.
+type PlayerHistory struct {
+	Players    []PlayerHistoryEntry `json:"players"`
+	DailyPeaks []DailyPeak          `json:"dailyPeaks"`
+}
+
+// serverPlayerHistory is the stored history of one server. Players are
+// keyed by lowercase name.
+type serverPlayerHistory struct {
+	Players    map[string]*PlayerHistoryEntry `json:"players"`
+	DailyPeaks map[string]int                 `json:"dailyPeaks"`
+}
+
+// playerHistoryLocked returns the history document, loading it on first
+// use. Callers hold m.playerHistoryMu.
+func (m *Manager) playerHistoryLocked() (map[string]*serverPlayerHistory, error) {
+	if m.playerHistory != nil {
+		return m.playerHistory, nil
+	}
+	doc := make(map[string]*serverPlayerHistory)
+	data, err := m.storage().Load(storeDocPlayerHistory)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read player history: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse player history: %w", err)
+		}
+	}
+	m.playerHistory = doc
+	return doc, nil
+}
+
+// savePlayerHistoryLocked writes the history through the coalescing writer
+// when there is one, since joins and leaves can come in bursts.
+func (m *Manager) savePlayerHistoryLocked() {
+	data, err := json.Marshal(m.playerHistory)
+	if err != nil {
+		log.Printf("Failed to encode player history: %v", err)
+		return
+	}
+	if m.persister != nil {
+		m.persister.schedule(storeDocPlayerHistory, data)
+		return
+	}
+	if err := m.storage().Save(storeDocPlayerHistory, data); err != nil {
+		log.Printf("Failed to save player history: %v", err)
+	}
+}
+
+func (h *serverPlayerHistory) player(name string, now time.Time) *PlayerHistoryEntry {
+	key := strings.ToLower(name)
+	entry, ok := h.Players[key]
+	if !ok {
+		entry = &PlayerHistoryEntry{Name: name, FirstSeen: now.UTC().Format(time.RFC3339)}
+		h.Players[key] = entry
+	}
+	entry.Name = name
+	return entry
+}
+
+// endSession closes a player's open session at end, or without adding any
+// playtime when end is zero because the real end is unknown.
+func (e *PlayerHistoryEntry) endSession(end time.Time) {
+	if e.OnlineSince == "" {
+		return
+	}
+	if start, err := time.Parse(time.RFC3339, e.OnlineSince); err == nil && !end.IsZero() && end.After(start) {
+		e.PlaytimeSeconds += int64(end.Sub(start).Seconds())
+		e.LastSeen = end.UTC().Format(time.RFC3339)
+	}
+	e.OnlineSince = ""
+}
+
+// recordPlayerJoin opens a session for a player and updates the day's peak
+// with the number of players now online. A session that is already open,
+// as after the panel reattached to a running server, carries on.
+func (m *Manager) recordPlayerJoin(id, name string, online int, now time.Time) {
+	m.playerHistoryMu.Lock()
+	defer m.playerHistoryMu.Unlock()
+	doc, err := m.playerHistoryLocked()
+	if err != nil {
+		log.Printf("Failed to record player join: %v", err)
+		return
+	}
+	h, ok := doc[id]
+	if !ok {
+		h = &serverPlayerHistory{Players: make(map[string]*PlayerHistoryEntry), DailyPeaks: make(map[string]int)}
+		doc[id] = h
+	}
+	entry := h.player(name, now)
+	entry.LastSeen = now.UTC().Format(time.RFC3339)
+	if entry.OnlineSince == "" {
+		entry.OnlineSince = entry.LastSeen
+		entry.Sessions++
+	}
+	day := now.Local().Format(playerHistoryDateLayout)
+	if online > h.DailyPeaks[day] {
+		h.DailyPeaks[day] = online
+	}
+	h.prune(now)
+	m.savePlayerHistoryLocked()
+}
+
+// recordPlayerLeave closes a player's session.
+func (m *Manager) recordPlayerLeave(id, name string, now time.Time) {
+	m.playerHistoryMu.Lock()
+	defer m.playerHistoryMu.Unlock()
+	doc, err := m.playerHistoryLocked()
+	if err != nil {
+		log.Printf("Failed to record player leave: %v", err)
+		return
+	}
+	h, ok := doc[id]
+	if !ok {
+		return
+	}
+	if entry, ok := h.Players[strings.ToLower(name)]; ok && entry.OnlineSince != "" {
+		entry.endSession(now)
+		m.savePlayerHistoryLocked()
+	}
+}
+
+// endPlayerSessions closes every open session of a server, at end when it
+// stopped or without playtime for sessions left over from before a start.
+func (m *Manager) endPlayerSessions(id string, end time.Time) {
+	m.playerHistoryMu.Lock()
+	defer m.playerHistoryMu.Unlock()
+	doc, err := m.playerHistoryLocked()
+	if err != nil {
+		log.Printf("Failed to close player sessions: %v", err)
+		return
+	}
+	h, ok := doc[id]
+	if !ok {
+		return
+	}
+	changed := false
+	for _, entry := range h.Players {
+		if entry.OnlineSince != "" {
+			entry.endSession(end)
+			changed = true
+		}
+	}
+	if changed {
+		m.savePlayerHistoryLocked()
+	}
+}
+
+// forgetPlayerHistory drops a deleted server's history.
+func (m *Manager) forgetPlayerHistory(id string) {
+	m.playerHistoryMu.Lock()
+	defer m.playerHistoryMu.Unlock()
+	doc, err := m.playerHistoryLocked()
+	if err != nil {
+		return
+	}
+	if _, ok := doc[id]; ok {
+		delete(doc, id)
+		m.savePlayerHistoryLocked()
+	}
+}
+
+// prune drops daily peaks older than playerHistoryPeakDays and, past
+// maxPlayerHistoryPerServer players, the ones seen longest ago.
+func (h *serverPlayerHistory) prune(now time.Time) {
+	cutoff := now.Local().AddDate(0, 0, -playerHistoryPeakDays).Format(playerHistoryDateLayout)
+	for day := range h.DailyPeaks {
+		if day < cutoff {
+			delete(h.DailyPeaks, day)
+		}
+	}
+	if len(h.Players) <= maxPlayerHistoryPerServer {
+		return
+	}
+	keys := make([]string, 0, len(h.Players))
+	for key, entry := range h.Players {
+		if entry.OnlineSince == "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return h.Players[keys[i]].LastSeen < h.Players[keys[j]].LastSeen })
+	for _, key := range keys[:min(len(keys), len(h.Players)-maxPlayerHistoryPerServer)] {
+		delete(h.Players, key)
+	}
+}
+
+// GetPlayerHistory returns a server's players, most recently seen first,
+// and its daily peaks for the last days days.
+func (m *Manager) GetPlayerHistory(id string, days int) (*PlayerHistory, error) {
+	m.mu.RLock()
+	_, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if days <= 0 {
+		days = defaultPlayerHistoryDays
+	}
+	days = min(days, playerHistoryPeakDays)
+
+	m.playerHistoryMu.Lock()
+	defer m.playerHistoryMu.Unlock()
+	doc, err := m.playerHistoryLocked()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	history := &PlayerHistory{Players: []PlayerHistoryEntry{}, DailyPeaks: []DailyPeak{}}
+	h, ok := doc[id]
+	if !ok {
+		return history, nil
+	}
+	for _, entry := range h.Players {
+		e := *entry
+		if start, err := time.Parse(time.RFC3339, e.OnlineSince); err == nil && now.After(start) {
+			e.PlaytimeSeconds += int64(now.Sub(start).Seconds())
+		}
+		history.Players = append(history.Players, e)
+	}
+	sort.Slice(history.Players, func(i, j int) bool {
+		left, right := history.Players[i], history.Players[j]
+		if (left.OnlineSince != "") != (right.OnlineSince != "") {
+			return left.OnlineSince != ""
+		}
+		if left.LastSeen != right.LastSeen {
+			return left.LastSeen > right.LastSeen
+		}
+		return strings.ToLower(left.Name) < strings.ToLower(right.Name)
+	})
+	cutoff := now.Local().AddDate(0, 0, -days+1).Format(playerHistoryDateLayout)
+	for day, players := range h.DailyPeaks {
+		if day >= cutoff {
+			history.DailyPeaks = append(history.DailyPeaks, DailyPeak{Date: day, Players: players})
+		}
+	}
+	sort.Slice(history.DailyPeaks, func(i, j int) bool { return history.DailyPeaks[i].Date < history.DailyPeaks[j].Date })
+	return history, nil
+}
@@ -0,0 +1,67 @@
+package minecraft
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPlayerHistoryTracksSessionsAndPeaks(t *testing.T) {
+	dataDir := t.TempDir()
+	mgr, err := NewManager(dataDir)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	mgr.mu.Lock()
+	mgr.configs["srv"] = &ServerConfig{ID: "srv", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	mgr.persist()
+	mgr.mu.Unlock()
+
+	start := time.Now().Add(-2 * time.Hour)
+	mgr.recordPlayerJoin("srv", "Steve", 1, start)
+	mgr.recordPlayerJoin("srv", "Alex", 2, start.Add(10*time.Minute))
+	mgr.recordPlayerLeave("srv", "Steve", start.Add(30*time.Minute))
+	mgr.recordPlayerJoin("srv", "steve", 2, start.Add(40*time.Minute))
+	mgr.endPlayerSessions("srv", start.Add(time.Hour))
+
+	history, err := mgr.GetPlayerHistory("srv", 0)
+	if err != nil {
+		t.Fatalf("GetPlayerHistory failed: %v", err)
+	}
+	if len(history.Players) != 2 {
+		t.Fatalf("expected two players, got %+v", history.Players)
+	}
+	alex, steve := history.Players[0], history.Players[1]
+	if steve.Name != "steve" || steve.Sessions != 2 || steve.PlaytimeSeconds != 50*60 || steve.OnlineSince != "" {
+		t.Fatalf("unexpected history for Steve: %+v", steve)
+	}
+	if alex.Name != "Alex" || alex.Sessions != 1 || alex.PlaytimeSeconds != 50*60 {
+		t.Fatalf("unexpected history for Alex: %+v", alex)
+	}
+	if len(history.DailyPeaks) == 0 || history.DailyPeaks[len(history.DailyPeaks)-1].Players != 2 {
+		t.Fatalf("expected a peak of two players, got %+v", history.DailyPeaks)
+	}
+
+	// A session the panel never saw end earns no playtime.
+	mgr.recordPlayerJoin("srv", "Alex", 1, start.Add(90*time.Minute))
+	mgr.endPlayerSessions("srv", time.Time{})
+	mgr.StopAll()
+
+	reloaded, err := NewManager(dataDir)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer reloaded.StopAll()
+	history, err = reloaded.GetPlayerHistory("srv", 0)
+	if err != nil {
+		t.Fatalf("GetPlayerHistory after reload failed: %v", err)
+	}
+	for _, p := range history.Players {
+		if p.Name == "Alex" && (p.Sessions != 2 || p.PlaytimeSeconds != 50*60) {
+			t.Fatalf("unexpected history for Alex after reload: %+v", p)
+		}
+	}
+	if len(history.Players) != 2 {
+		t.Fatalf("expected history to survive a panel restart, got %+v", history.Players)
+	}
+}
@@ -0,0 +1,248 @@
+package minecraft
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Player data sources reported by InspectPlayer.
+const (
+	PlayerDataLive  = "live"
+	PlayerDataSaved = "saved"
+)
+
+// InventoryItem is one stack in a player's inventory or ender chest.
+type InventoryItem struct {
+	Slot  int    `json:"slot"`
+	ID    string `json:"id"`
+	Count int    `json:"count"`
+	// Name is the item's custom name as raw text component JSON, if any.
+	Name string `json:"name,omitempty"`
+}
+
+// PlayerDetails is a read-only snapshot of a player's saved state.
+type PlayerDetails struct {
+	Name       string          `json:"name"`
+	UUID       string          `json:"uuid"`
+	Online     bool            `json:"online"`
+	Source     string          `json:"source"`
+	SavedAt    string          `json:"savedAt,omitempty"`
+	Dimension  string          `json:"dimension,omitempty"`
+	Position   []float64       `json:"position,omitempty"`
+	Health     float64         `json:"health"`
+	Food       int             `json:"food"`
+	XPLevel    int             `json:"xpLevel"`
+	XPProgress float64         `json:"xpProgress"`
+	XPTotal    int             `json:"xpTotal"`
+	GameMode   int             `json:"gameMode"`
+	Inventory  []InventoryItem `json:"inventory"`
+	EnderChest []InventoryItem `json:"enderChest"`
+}
+
+// playerDataFile returns the playerdata file for uuid in the server's world.
+func playerDataFile(cfg *ServerConfig, uuid string) (string, error) {
+	return SafePath(cfg.Dir, filepath.Join(serverLevelName(cfg), "playerdata", uuid+".dat"))
+}
+
+// InspectPlayer returns a player's inventory, ender chest, position and XP.
+// Online players are read live with "data get entity" when RCON is set up;
+// otherwise the playerdata file from the last save is used.
+func (m *Manager) InspectPlayer(id, player string) (*PlayerDetails, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	rs, rsOk := m.running[id]
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if !rsOk {
+		return nil, errServerNotFound(id)
+	}
+	if isProxyType(cfg.Type) {
+		return nil, fmt.Errorf("proxy servers do not store player data")
+	}
+
+	name, uuid, online := strings.TrimSpace(player), normalizePlayerUUID(player), false
+	rs.mu.RLock()
+	running := rs.status == "Running"
+	for _, p := range rs.players {
+		if strings.EqualFold(p.Name, name) || (uuid != "" && normalizePlayerUUID(p.UUID) == uuid) {
+			name, online = p.Name, running
+			if u := normalizePlayerUUID(p.UUID); u != "" {
+				uuid = u
+			}
+			break
+		}
+	}
+	rs.mu.RUnlock()
+
+	if uuid == "" {
+		uuid = loadUserCacheUUIDs(cfg.Dir)[strings.ToLower(name)]
+	}
+	if uuid == "" && !online {
+		return nil, fmt.Errorf("player %s has not joined this server", name)
+	}
+
+	if online {
+		if details, err := m.inspectOnlinePlayer(id, name); err == nil {
+			details.UUID = uuid
+			return details, nil
+		}
+	}
+	if uuid == "" {
+		return nil, fmt.Errorf("player %s has no saved data yet", name)
+	}
+
+	path, err := playerDataFile(cfg, uuid)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("player %s has no saved data yet", name)
+		}
+		return nil, fmt.Errorf("failed to read player data: %w", err)
+	}
+	root, err := readNBT(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse player data: %w", err)
+	}
+	details := playerDetailsFromNBT(root)
+	details.Name = name
+	details.UUID = uuid
+	details.Online = online
+	details.Source = PlayerDataSaved
+	if info, err := os.Stat(path); err == nil {
+		details.SavedAt = info.ModTime().UTC().Format(time.RFC3339)
+	}
+	return details, nil
+}
+
+// inspectOnlinePlayer reads a connected player's entity data over RCON.
+func (m *Manager) inspectOnlinePlayer(id, name string) (*PlayerDetails, error) {
+	if !isValidPlayerName(name) {
+		return nil, fmt.Errorf("invalid player name %q", name)
+	}
+	reply, err := m.rconQuery(id, "data get entity "+name)
+	if err != nil {
+		return nil, err
+	}
+	_, snbt, ok := strings.Cut(reply, "entity data: ")
+	if !ok {
+		return nil, fmt.Errorf("unexpected reply: %s", reply)
+	}
+	value, err := parseSNBT(snbt)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected entity data")
+	}
+	details := playerDetailsFromNBT(root)
+	details.Name = name
+	details.Online = true
+	details.Source = PlayerDataLive
+	return details, nil
+}
+
+func isValidPlayerName(name string) bool {
+	if len(name) < 1 || len(name) > 16 {
+		return false
+	}
+	for _, r := range name {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// playerDetailsFromNBT reads the fields the panel shows from a player
+// compound, from either a playerdata file or "data get entity".
+func playerDetailsFromNBT(root map[string]any) *PlayerDetails {
+	details := &PlayerDetails{
+		Inventory:  inventoryItems(root["Inventory"]),
+		EnderChest: inventoryItems(root["EnderItems"]),
+	}
+	if dim, ok := root["Dimension"].(string); ok {
+		details.Dimension = dim
+	} else if dim, ok := nbtIntField(root, "Dimension"); ok {
+		// Before 1.16 dimensions were numbers.
+		details.Dimension = map[int64]string{-1: "minecraft:the_nether", 0: "minecraft:overworld", 1: "minecraft:the_end"}[dim]
+	}
+	if pos, ok := root["Pos"].([]any); ok && len(pos) == 3 {
+		for _, v := range pos {
+			if f, ok := v.(float64); ok {
+				details.Position = append(details.Position, f)
+			}
+		}
+		if len(details.Position) != 3 {
+			details.Position = nil
+		}
+	}
+	if v, ok := nbtFloatField(root, "Health"); ok {
+		details.Health = v
+	}
+	if v, ok := nbtIntField(root, "foodLevel"); ok {
+		details.Food = int(v)
+	}
+	if v, ok := nbtIntField(root, "XpLevel"); ok {
+		details.XPLevel = int(v)
+	}
+	if v, ok := nbtFloatField(root, "XpP"); ok {
+		details.XPProgress = v
+	}
+	if v, ok := nbtIntField(root, "XpTotal"); ok {
+		details.XPTotal = int(v)
+	}
+	if v, ok := nbtIntField(root, "playerGameType"); ok {
+		details.GameMode = int(v)
+	}
+	return details
+}
+
+// inventoryItems reads an item list. Item stacks use "count" and
+// "components" since 1.20.5 and "Count" and "tag" before.
+func inventoryItems(value any) []InventoryItem {
+	list, _ := value.([]any)
+	items := make([]InventoryItem, 0, len(list))
+	for _, entry := range list {
+		stack, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		itemID, _ := stack["id"].(string)
+		if itemID == "" {
+			continue
+		}
+		item := InventoryItem{ID: itemID, Count: 1}
+		if slot, ok := nbtIntField(stack, "Slot"); ok {
+			item.Slot = int(slot)
+		}
+		if count, ok := nbtIntField(stack, "count"); ok {
+			item.Count = int(count)
+		} else if count, ok := nbtIntField(stack, "Count"); ok {
+			item.Count = int(count)
+		}
+		if components, ok := stack["components"].(map[string]any); ok {
+			if name, ok := components["minecraft:custom_name"].(string); ok {
+				item.Name = name
+			}
+		} else if tag, ok := stack["tag"].(map[string]any); ok {
+			if display, ok := tag["display"].(map[string]any); ok {
+				if name, ok := display["Name"].(string); ok {
+					item.Name = name
+				}
+			}
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Slot < items[j].Slot })
+	return items
+}
@@ -0,0 +1,135 @@
+package minecraft
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// nbtWriter builds binary NBT for tests.
+type nbtWriter struct{ bytes.Buffer }
+
+func (w *nbtWriter) name(tagType byte, name string) {
+	w.WriteByte(tagType)
+	binary.Write(w, binary.BigEndian, uint16(len(name)))
+	w.WriteString(name)
+}
+
+func (w *nbtWriter) str(name, value string) {
+	w.name(nbtString, name)
+	binary.Write(w, binary.BigEndian, uint16(len(value)))
+	w.WriteString(value)
+}
+
+func (w *nbtWriter) byteTag(name string, v int8) {
+	w.name(nbtByte, name)
+	binary.Write(w, binary.BigEndian, v)
+}
+
+func (w *nbtWriter) intTag(name string, v int32) {
+	w.name(nbtInt, name)
+	binary.Write(w, binary.BigEndian, v)
+}
+
+func (w *nbtWriter) floatTag(name string, v float32) {
+	w.name(nbtFloat, name)
+	binary.Write(w, binary.BigEndian, v)
+}
+
+func TestInspectPlayerReadsSavedPlayerData(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv1", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = &runningServer{status: "Stopped"}
+	mgr.mu.Unlock()
+
+	const uuid = "069a79f4-44e9-4726-a5be-fca90e38aaf5"
+	if err := os.MkdirAll(filepath.Join(cfg.Dir, "world", "playerdata"), 0755); err != nil {
+		t.Fatalf("failed to create playerdata: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Dir, "usercache.json"), []byte(`[{"name":"Alice","uuid":"`+uuid+`"}]`), 0644); err != nil {
+		t.Fatalf("failed to write usercache: %v", err)
+	}
+
+	var w nbtWriter
+	w.name(nbtCompound, "")
+	w.str("Dimension", "minecraft:the_nether")
+	w.intTag("XpLevel", 30)
+	w.floatTag("XpP", 0.5)
+	w.floatTag("Health", 18)
+	w.name(nbtList, "Pos")
+	w.WriteByte(nbtDouble)
+	binary.Write(&w, binary.BigEndian, int32(3))
+	binary.Write(&w, binary.BigEndian, []float64{10.5, 64, -20.25})
+	w.name(nbtList, "Inventory")
+	w.WriteByte(nbtCompound)
+	binary.Write(&w, binary.BigEndian, int32(2))
+	w.byteTag("Slot", 8)
+	w.str("id", "minecraft:torch")
+	w.intTag("count", 64)
+	w.WriteByte(nbtEnd)
+	w.byteTag("Slot", 0)
+	w.str("id", "minecraft:diamond_sword")
+	w.WriteByte(nbtEnd)
+	w.name(nbtList, "EnderItems")
+	w.WriteByte(nbtEnd)
+	binary.Write(&w, binary.BigEndian, int32(0))
+	w.WriteByte(nbtEnd)
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(w.Bytes())
+	zw.Close()
+	if err := os.WriteFile(filepath.Join(cfg.Dir, "world", "playerdata", uuid+".dat"), gz.Bytes(), 0644); err != nil {
+		t.Fatalf("failed to write player data: %v", err)
+	}
+
+	details, err := mgr.InspectPlayer(cfg.ID, "alice")
+	if err != nil {
+		t.Fatalf("InspectPlayer failed: %v", err)
+	}
+	if details.UUID != uuid || details.Source != PlayerDataSaved || details.Online {
+		t.Fatalf("unexpected identity: %+v", details)
+	}
+	if details.Dimension != "minecraft:the_nether" || details.XPLevel != 30 || details.XPProgress != 0.5 || details.Health != 18 {
+		t.Fatalf("unexpected stats: %+v", details)
+	}
+	if len(details.Position) != 3 || details.Position[2] != -20.25 {
+		t.Fatalf("unexpected position: %v", details.Position)
+	}
+	if len(details.Inventory) != 2 || details.Inventory[0].ID != "minecraft:diamond_sword" || details.Inventory[0].Count != 1 ||
+		details.Inventory[1].Slot != 8 || details.Inventory[1].Count != 64 {
+		t.Fatalf("unexpected inventory: %+v", details.Inventory)
+	}
+	if len(details.EnderChest) != 0 {
+		t.Fatalf("unexpected ender chest: %+v", details.EnderChest)
+	}
+
+	if _, err := mgr.InspectPlayer(cfg.ID, "Nobody"); err == nil {
+		t.Fatal("expected an error for an unknown player")
+	}
+}
+
+func TestParseSNBTEntityData(t *testing.T) {
+	value, err := parseSNBT(`{Pos: [1.5d, 70.0d, -3.0d], XpLevel: 5, XpP: 0.25f, Dimension: "minecraft:overworld", UUID: [I; 1, -2, 3, 4], ` +
+		`Inventory: [{Slot: 0b, id: "minecraft:stone", count: 12, components: {"minecraft:custom_name": '{"text":"It\'s rock"}'}}], EnderItems: []}`)
+	if err != nil {
+		t.Fatalf("parseSNBT failed: %v", err)
+	}
+	details := playerDetailsFromNBT(value.(map[string]any))
+	if details.XPLevel != 5 || details.XPProgress != 0.25 || details.Dimension != "minecraft:overworld" || details.Position[1] != 70 {
+		t.Fatalf("unexpected details: %+v", details)
+	}
+	if len(details.Inventory) != 1 || details.Inventory[0].Count != 12 || details.Inventory[0].Name != `{"text":"It's rock"}` {
+		t.Fatalf("unexpected inventory: %+v", details.Inventory)
+	}
+}
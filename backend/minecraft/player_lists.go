@@ -0,0 +1,367 @@
+package minecraft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Player lists a server keeps in its directory.
+const (
+	PlayerListWhitelist = "whitelist"
+	PlayerListOps       = "ops"
+	PlayerListBans      = "bans"
+	PlayerListIPBans    = "ip-bans"
+)
+
+var playerListFiles = map[string]string{
+	PlayerListWhitelist: "whitelist.json",
+	PlayerListOps:       "ops.json",
+	PlayerListBans:      "banned-players.json",
+	PlayerListIPBans:    "banned-ips.json",
+}
+
+// mojangProfileURL resolves an online-mode player name to its UUID.
+var mojangProfileURL = "https://api.mojang.com/users/profiles/minecraft/"
+
+// banTimeLayout is the timestamp format of banned-players.json and
+// banned-ips.json.
+const banTimeLayout = "2006-01-02 15:04:05 -0700"
+
+// PlayerListEntry is one entry of whitelist.json, ops.json,
+// banned-players.json or banned-ips.json. Fields a list does not use are
+// left out.
+type PlayerListEntry struct {
+	UUID                string `json:"uuid,omitempty"`
+	Name                string `json:"name,omitempty"`
+	IP                  string `json:"ip,omitempty"`
+	Level               int    `json:"level,omitempty"`
+	BypassesPlayerLimit bool   `json:"bypassesPlayerLimit,omitempty"`
+	Created             string `json:"created,omitempty"`
+	Source              string `json:"source,omitempty"`
+	Expires             string `json:"expires,omitempty"`
+	Reason              string `json:"reason,omitempty"`
+}
+
+// BanLists holds a server's player and IP bans.
+type BanLists struct {
+	Players []PlayerListEntry `json:"players"`
+	IPs     []PlayerListEntry `json:"ips"`
+}
+
+// playerListTarget returns the server a list edit applies to and whether
+// it is running, refusing servers that are starting or stopping.
+func (m *Manager) playerListTarget(id string) (ServerConfig, bool, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		m.mu.RUnlock()
+		return ServerConfig{}, false, err
+	}
+	snapshot := *cfg
+	rs := m.running[id]
+	m.mu.RUnlock()
+
+	if isProxyType(snapshot.Type) {
+		return ServerConfig{}, false, fmt.Errorf("proxies do not keep player lists")
+	}
+	status := "Stopped"
+	if rs != nil {
+		status = rs.currentRuntime().status
+	}
+	switch status {
+	case "Running":
+		return snapshot, true, nil
+	case "Stopped", "Crashed", "Error":
+		if err := m.validateManagedServerDir(snapshot.Dir); err != nil {
+			return ServerConfig{}, false, err
+		}
+		return snapshot, false, nil
+	default:
+		return ServerConfig{}, false, fmt.Errorf("server is %s", strings.ToLower(status))
+	}
+}
+
+// ListPlayerList returns the entries of one of a server's player lists.
+func (m *Manager) ListPlayerList(id, list string) ([]PlayerListEntry, error) {
+	file, ok := playerListFiles[list]
+	if !ok {
+		return nil, fmt.Errorf("unknown player list %q", list)
+	}
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		m.mu.RUnlock()
+		return nil, err
+	}
+	dir := cfg.Dir
+	m.mu.RUnlock()
+	return readPlayerList(filepath.Join(dir, file))
+}
+
+// ListBans returns a server's player and IP bans.
+func (m *Manager) ListBans(id string) (*BanLists, error) {
+	players, err := m.ListPlayerList(id, PlayerListBans)
+	if err != nil {
+		return nil, err
+	}
+	ips, err := m.ListPlayerList(id, PlayerListIPBans)
+	if err != nil {
+		return nil, err
+	}
+	return &BanLists{Players: players, IPs: ips}, nil
+}
+
+func readPlayerList(path string) ([]PlayerListEntry, error) {
+	entries := []PlayerListEntry{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return nil, err
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return entries, nil
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return entries, nil
+}
+
+// AddToPlayerList puts a player on the whitelist, makes them an operator
+// or bans them. A running server is told through its console; a stopped
+// one has its JSON file edited.
+func (m *Manager) AddToPlayerList(id, list, playerName, reason string) error {
+	if !isValidPlayerName(playerName) {
+		return fmt.Errorf("invalid player name %q", playerName)
+	}
+	file, ok := playerListFiles[list]
+	if !ok || list == PlayerListIPBans {
+		return fmt.Errorf("unknown player list %q", list)
+	}
+	cfg, running, err := m.playerListTarget(id)
+	if err != nil {
+		return err
+	}
+	if running {
+		switch list {
+		case PlayerListWhitelist:
+			return m.SendCommand(id, "whitelist add "+playerName)
+		case PlayerListOps:
+			return m.SendCommand(id, "op "+playerName)
+		default:
+			return m.BanPlayer(id, playerName, reason)
+		}
+	}
+
+	playerUUID, name, err := m.resolvePlayerUUID(cfg.Dir, playerName)
+	if err != nil {
+		return err
+	}
+	entry := map[string]any{"uuid": playerUUID, "name": name}
+	switch list {
+	case PlayerListOps:
+		entry["level"] = opPermissionLevel(cfg.Dir)
+		entry["bypassesPlayerLimit"] = false
+	case PlayerListBans:
+		entry["created"] = time.Now().Format(banTimeLayout)
+		entry["source"] = "Server"
+		entry["expires"] = "forever"
+		entry["reason"] = banReasonOrDefault(reason)
+	}
+	if err := editPlayerList(filepath.Join(cfg.Dir, file), func(entries []map[string]any) []map[string]any {
+		return append(withoutPlayerListEntry(entries, "name", playerName), entry)
+	}); err != nil {
+		return err
+	}
+	if list == PlayerListBans {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if c, ok := m.configs[id]; ok && removeTempBanLocked(c, playerName) {
+			return m.persist()
+		}
+	}
+	return nil
+}
+
+// RemoveFromPlayerList takes a player off the whitelist, deops them or
+// pardons them.
+func (m *Manager) RemoveFromPlayerList(id, list, playerName string) error {
+	if !isValidPlayerName(playerName) {
+		return fmt.Errorf("invalid player name %q", playerName)
+	}
+	file, ok := playerListFiles[list]
+	if !ok || list == PlayerListIPBans {
+		return fmt.Errorf("unknown player list %q", list)
+	}
+	cfg, running, err := m.playerListTarget(id)
+	if err != nil {
+		return err
+	}
+	if running {
+		command := map[string]string{
+			PlayerListWhitelist: "whitelist remove ",
+			PlayerListOps:       "deop ",
+			PlayerListBans:      "pardon ",
+		}[list]
+		if err := m.SendCommand(id, command+playerName); err != nil {
+			return err
+		}
+	} else if err := editPlayerList(filepath.Join(cfg.Dir, file), func(entries []map[string]any) []map[string]any {
+		return withoutPlayerListEntry(entries, "name", playerName)
+	}); err != nil {
+		return err
+	}
+	if list == PlayerListBans {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if c, ok := m.configs[id]; ok && removeTempBanLocked(c, playerName) {
+			return m.persist()
+		}
+	}
+	return nil
+}
+
+// BanIP bans an address, through ban-ip on a running server or by editing
+// banned-ips.json on a stopped one.
+func (m *Manager) BanIP(id, ip, reason string) error {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return fmt.Errorf("invalid IP address %q", ip)
+	}
+	ip = parsed.String()
+	cfg, running, err := m.playerListTarget(id)
+	if err != nil {
+		return err
+	}
+	if running {
+		command := "ban-ip " + ip
+		if reason != "" {
+			command += " " + reason
+		}
+		return m.SendCommand(id, command)
+	}
+	entry := map[string]any{
+		"ip":      ip,
+		"created": time.Now().Format(banTimeLayout),
+		"source":  "Server",
+		"expires": "forever",
+		"reason":  banReasonOrDefault(reason),
+	}
+	return editPlayerList(filepath.Join(cfg.Dir, playerListFiles[PlayerListIPBans]), func(entries []map[string]any) []map[string]any {
+		return append(withoutPlayerListEntry(entries, "ip", ip), entry)
+	})
+}
+
+// PardonIP lifts an IP ban.
+func (m *Manager) PardonIP(id, ip string) error {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return fmt.Errorf("invalid IP address %q", ip)
+	}
+	ip = parsed.String()
+	cfg, running, err := m.playerListTarget(id)
+	if err != nil {
+		return err
+	}
+	if running {
+		return m.SendCommand(id, "pardon-ip "+ip)
+	}
+	return editPlayerList(filepath.Join(cfg.Dir, playerListFiles[PlayerListIPBans]), func(entries []map[string]any) []map[string]any {
+		return withoutPlayerListEntry(entries, "ip", ip)
+	})
+}
+
+func banReasonOrDefault(reason string) string {
+	if reason == "" {
+		return "Banned by an operator."
+	}
+	return reason
+}
+
+// opPermissionLevel returns the level the server gives new operators.
+func opPermissionLevel(serverDir string) int {
+	props := parseServerPropertiesFile(filepath.Join(serverDir, "server.properties"))
+	if level, err := strconv.Atoi(props["op-permission-level"]); err == nil && level >= 1 && level <= 4 {
+		return level
+	}
+	return 4
+}
+
+// resolvePlayerUUID finds the UUID a stopped server will know a player by:
+// its user cache first, then the offline UUID on offline-mode servers and
+// Mojang's profile lookup on online-mode ones.
+func (m *Manager) resolvePlayerUUID(serverDir, playerName string) (string, string, error) {
+	if id := loadUserCacheUUIDs(serverDir)[strings.ToLower(playerName)]; id != "" {
+		return id, playerName, nil
+	}
+	props := parseServerPropertiesFile(filepath.Join(serverDir, "server.properties"))
+	if strings.EqualFold(props["online-mode"], "false") {
+		return uuid.UUID(offlinePlayerUUID(playerName)).String(), playerName, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var profile struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := fetchJSON(ctx, mojangProfileURL+playerName, &profile); err != nil {
+		return "", "", fmt.Errorf("could not look up %s's UUID (start the server to edit its lists while offline): %w", playerName, err)
+	}
+	id := normalizePlayerUUID(profile.ID)
+	if id == "" {
+		return "", "", fmt.Errorf("no Minecraft account is named %s", playerName)
+	}
+	if profile.Name == "" {
+		profile.Name = playerName
+	}
+	return id, profile.Name, nil
+}
+
+// editPlayerList rewrites a player list file, keeping fields the panel does
+// not know about.
+func editPlayerList(path string, edit func([]map[string]any) []map[string]any) error {
+	var entries []map[string]any
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+		}
+	}
+	entries = edit(entries)
+	if entries == nil {
+		entries = []map[string]any{}
+	}
+	out, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(out, '\n'), 0644)
+}
+
+// withoutPlayerListEntry drops the entries whose key matches value,
+// ignoring case.
+func withoutPlayerListEntry(entries []map[string]any, key, value string) []map[string]any {
+	kept := make([]map[string]any, 0, len(entries))
+	for _, entry := range entries {
+		if v, _ := entry[key].(string); strings.EqualFold(v, value) {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	return kept
+}
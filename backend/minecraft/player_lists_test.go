@@ -0,0 +1,101 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlayerListsEditFilesOfStoppedServer(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		t.Fatalf("failed to create server dir: %v", err)
+	}
+	files := map[string]string{
+		"server.properties": "online-mode=false\nop-permission-level=3\n",
+		"usercache.json":    `[{"name":"Alex","uuid":"ec561538-f3fd-461d-aff5-086b22154bce","expiresOn":"2030-01-01 00:00:00 +0000"}]`,
+		"whitelist.json":    `[{"uuid":"11111111-1111-1111-1111-111111111111","name":"Steve","custom":true}]`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(cfg.Dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	cfg.TempBans = []TempBan{{Player: "Griefer", ExpiresAt: "2099-01-01T00:00:00Z"}}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = &runningServer{status: "Stopped"}
+	mgr.mu.Unlock()
+
+	if err := mgr.AddToPlayerList(cfg.ID, PlayerListWhitelist, "Alex", ""); err != nil {
+		t.Fatalf("AddToPlayerList failed: %v", err)
+	}
+	whitelist, err := mgr.ListPlayerList(cfg.ID, PlayerListWhitelist)
+	if err != nil {
+		t.Fatalf("ListPlayerList failed: %v", err)
+	}
+	if len(whitelist) != 2 || whitelist[1].UUID != "ec561538-f3fd-461d-aff5-086b22154bce" {
+		t.Fatalf("expected Alex to be whitelisted with the cached UUID, got %+v", whitelist)
+	}
+	if data, _ := os.ReadFile(filepath.Join(cfg.Dir, "whitelist.json")); !strings.Contains(string(data), `"custom": true`) {
+		t.Fatalf("expected unknown fields to be kept:\n%s", data)
+	}
+	if err := mgr.RemoveFromPlayerList(cfg.ID, PlayerListWhitelist, "steve"); err != nil {
+		t.Fatalf("RemoveFromPlayerList failed: %v", err)
+	}
+	if whitelist, _ = mgr.ListPlayerList(cfg.ID, PlayerListWhitelist); len(whitelist) != 1 || whitelist[0].Name != "Alex" {
+		t.Fatalf("expected only Alex to remain, got %+v", whitelist)
+	}
+
+	if err := mgr.AddToPlayerList(cfg.ID, PlayerListOps, "Notch", ""); err != nil {
+		t.Fatalf("AddToPlayerList(ops) failed: %v", err)
+	}
+	ops, _ := mgr.ListPlayerList(cfg.ID, PlayerListOps)
+	if len(ops) != 1 || ops[0].Level != 3 || ops[0].UUID != "b50ad385-829d-3141-a216-7e7d7539ba7f" {
+		t.Fatalf("expected Notch as a level 3 op with the offline UUID, got %+v", ops)
+	}
+
+	if err := mgr.AddToPlayerList(cfg.ID, PlayerListBans, "Griefer", "Griefing"); err != nil {
+		t.Fatalf("AddToPlayerList(bans) failed: %v", err)
+	}
+	if err := mgr.BanIP(cfg.ID, "203.0.113.9", ""); err != nil {
+		t.Fatalf("BanIP failed: %v", err)
+	}
+	if err := mgr.BanIP(cfg.ID, "not-an-ip", ""); err == nil {
+		t.Fatalf("expected an invalid IP to be refused")
+	}
+	bans, err := mgr.ListBans(cfg.ID)
+	if err != nil {
+		t.Fatalf("ListBans failed: %v", err)
+	}
+	if len(bans.Players) != 1 || bans.Players[0].Reason != "Griefing" || bans.Players[0].Expires != "forever" {
+		t.Fatalf("unexpected player bans %+v", bans.Players)
+	}
+	if len(bans.IPs) != 1 || bans.IPs[0].IP != "203.0.113.9" {
+		t.Fatalf("unexpected IP bans %+v", bans.IPs)
+	}
+	if temp, _ := mgr.ListTempBans(cfg.ID); len(temp) != 0 {
+		t.Fatalf("expected a permanent ban to replace the temporary one, got %+v", temp)
+	}
+	if err := mgr.PardonIP(cfg.ID, "203.0.113.9"); err != nil {
+		t.Fatalf("PardonIP failed: %v", err)
+	}
+	if bans, _ = mgr.ListBans(cfg.ID); len(bans.IPs) != 0 {
+		t.Fatalf("expected the IP ban to be lifted, got %+v", bans.IPs)
+	}
+
+	rs := mgr.running[cfg.ID]
+	rs.mu.Lock()
+	rs.status = "Starting"
+	rs.mu.Unlock()
+	if err := mgr.AddToPlayerList(cfg.ID, PlayerListWhitelist, "Herobrine", ""); err == nil {
+		t.Fatalf("expected edits to be refused while the server starts")
+	}
+}
@@ -0,0 +1,260 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// maxPluginConfigBytes is the largest config file the quick editor
+	// reads or writes; bigger files are usually data, not settings.
+	maxPluginConfigBytes = 1 << 20
+	maxPluginConfigFiles = 200
+	maxPluginConfigDepth = 3
+)
+
+// ErrPluginDataFolderNotFound is returned when a plugin has not created
+// its data folder yet, typically because the server has not run with it.
+var ErrPluginDataFolderNotFound = errors.New("plugin data folder not found")
+
+// PluginConfigFile is one config file in a plugin's data folder. Path is
+// relative to the data folder.
+type PluginConfigFile struct {
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Modified string `json:"modified"`
+}
+
+// PluginConfigListing lists the config files of one plugin. DataFolder is
+// relative to the server folder, e.g. "plugins/Essentials".
+type PluginConfigListing struct {
+	Plugin     string             `json:"plugin"`
+	DataFolder string             `json:"dataFolder"`
+	Files      []PluginConfigFile `json:"files"`
+	Truncated  bool               `json:"truncated,omitempty"`
+}
+
+func isPluginConfigFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yml", ".yaml", ".json":
+		return true
+	}
+	return false
+}
+
+// pluginDataFolder finds the data folder of the plugin jar fileName under
+// plugins/. Plugins name it after the name in their plugin.yml, so that is
+// tried first, then the same name in another case or spelling, then the
+// jar's own name.
+func pluginDataFolder(cfg *ServerConfig, fileName string) (string, string, error) {
+	dirName, dirPath, err := resolveExtensionDir(cfg, ExtensionDirPlugins)
+	if err != nil {
+		return "", "", err
+	}
+	if !extensionFileAllowed(dirName, fileName) {
+		return "", "", fmt.Errorf("%s is not a plugin", fileName)
+	}
+	jarPath, err := SafePath(dirPath, filepath.Base(fileName))
+	if err != nil {
+		return "", "", fmt.Errorf("invalid plugin path: %w", err)
+	}
+	if _, err := os.Stat(jarPath); err != nil {
+		if os.IsNotExist(err) {
+			return "", "", fmt.Errorf("plugin file not found: %s", fileName)
+		}
+		return "", "", err
+	}
+
+	baseName, _ := splitDisabledSuffix(filepath.Base(fileName))
+	jarName := strings.TrimSuffix(baseName, filepath.Ext(baseName))
+	pluginName, _ := extractPluginVersion(jarPath)
+	if pluginName == "" {
+		pluginName = jarName
+	}
+
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return "", "", err
+	}
+	var folders []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			folders = append(folders, entry.Name())
+		}
+	}
+	matchers := []func(folder string) bool{
+		func(folder string) bool { return folder == pluginName },
+		func(folder string) bool { return strings.EqualFold(folder, pluginName) },
+		func(folder string) bool { return normalizeProjectName(folder) == normalizeProjectName(pluginName) },
+		func(folder string) bool { return namesLikelySame(folder, jarName) },
+	}
+	for _, matches := range matchers {
+		for _, folder := range folders {
+			if matches(folder) {
+				return pluginName, filepath.Join(dirPath, folder), nil
+			}
+		}
+	}
+	return pluginName, "", fmt.Errorf("%w: %s has no folder under plugins/ yet; start the server once to create it", ErrPluginDataFolderNotFound, pluginName)
+}
+
+// ListPluginConfigs lists the .yml, .yaml and .json files in the data
+// folder of the plugin jar fileName, up to three folders deep.
+func (m *Manager) ListPluginConfigs(id, fileName string) (*PluginConfigListing, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	pluginName, folder, err := pluginDataFolder(cfg, fileName)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(cfg.Dir, folder)
+	if err != nil {
+		return nil, err
+	}
+
+	listing := &PluginConfigListing{Plugin: pluginName, DataFolder: filepath.ToSlash(rel), Files: []PluginConfigFile{}}
+	err = filepath.WalkDir(folder, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		relPath, relErr := filepath.Rel(folder, path)
+		if relErr != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != folder && strings.Count(filepath.ToSlash(relPath), "/") >= maxPluginConfigDepth-1 {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !isPluginConfigFile(d.Name()) {
+			return nil
+		}
+		if len(listing.Files) >= maxPluginConfigFiles {
+			listing.Truncated = true
+			return filepath.SkipAll
+		}
+		info, infoErr := d.Info()
+		if infoErr != nil {
+			return nil
+		}
+		listing.Files = append(listing.Files, PluginConfigFile{
+			Path:     filepath.ToSlash(relPath),
+			Size:     info.Size(),
+			Modified: info.ModTime().UTC().Format(time.RFC3339),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Top-level files such as config.yml first, then by path.
+	sort.Slice(listing.Files, func(i, j int) bool {
+		a, b := listing.Files[i].Path, listing.Files[j].Path
+		if da, db := strings.Count(a, "/"), strings.Count(b, "/"); da != db {
+			return da < db
+		}
+		return a < b
+	})
+	return listing, nil
+}
+
+// pluginConfigPath resolves configPath inside the plugin's data folder.
+func (m *Manager) pluginConfigPath(id, fileName, configPath string) (string, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return "", err
+	}
+	_, folder, err := pluginDataFolder(cfg, fileName)
+	if err != nil {
+		return "", err
+	}
+	if !isPluginConfigFile(configPath) {
+		return "", fmt.Errorf("only .yml, .yaml and .json config files can be edited here")
+	}
+	return SafePath(folder, configPath)
+}
+
+// ReadPluginConfig returns one config file of a plugin.
+func (m *Manager) ReadPluginConfig(id, fileName, configPath string) ([]byte, error) {
+	path, err := m.pluginConfigPath(id, fileName, configPath)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxPluginConfigBytes {
+		return nil, fmt.Errorf("%s is larger than %s; use the file browser", configPath, formatFileSize(maxPluginConfigBytes))
+	}
+	return os.ReadFile(path)
+}
+
+// WritePluginConfig replaces one config file of a plugin. The content must
+// parse as YAML or JSON, matching the file's extension, so a typo cannot
+// reset the plugin to its defaults on the next reload. The file is
+// replaced in one step, so the plugin never reads a half-written config.
+func (m *Manager) WritePluginConfig(id, fileName, configPath string, content []byte) error {
+	if len(content) > maxPluginConfigBytes {
+		return fmt.Errorf("config files edited here must be at most %s", formatFileSize(maxPluginConfigBytes))
+	}
+	path, err := m.pluginConfigPath(id, fileName, configPath)
+	if err != nil {
+		return err
+	}
+	if err := validatePluginConfig(configPath, content); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+func validatePluginConfig(configPath string, content []byte) error {
+	if strings.EqualFold(filepath.Ext(configPath), ".json") {
+		var v any
+		if err := json.Unmarshal(content, &v); err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+		return nil
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return fmt.Errorf("invalid YAML: %w", err)
+	}
+	return nil
+}
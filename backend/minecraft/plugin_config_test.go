@@ -0,0 +1,94 @@
+package minecraft
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPluginConfigQuickEdit(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv", Name: "Srv", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Srv")}
+	mgr.mu.Lock()
+	mgr.configs["srv"] = cfg
+	mgr.mu.Unlock()
+	pluginsDir := filepath.Join(cfg.Dir, "plugins")
+	if err := os.MkdirAll(filepath.Join(pluginsDir, "essentials", "kits"), 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	writeTestJar(t, filepath.Join(pluginsDir, "EssentialsX-2.20.1.jar"), map[string]string{
+		"plugin.yml": "name: Essentials\nversion: 2.20.1\nmain: com.earth2me.essentials.Essentials\n",
+	})
+	writeTestJar(t, filepath.Join(pluginsDir, "Fresh-1.0.jar"), map[string]string{
+		"plugin.yml": "name: Fresh\nversion: 1.0\n",
+	})
+	files := map[string]string{
+		"config.yml":       "ops-name-color: '4'\n",
+		"kits/tools.yml":   "kits: {}\n",
+		"userdata.json":    "{}",
+		"userdata/abc.dat": "binary",
+	}
+	for name, content := range files {
+		path := filepath.Join(pluginsDir, "essentials", filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+
+	// The folder is found by the plugin.yml name, whatever its case.
+	listing, err := mgr.ListPluginConfigs("srv", "EssentialsX-2.20.1.jar")
+	if err != nil {
+		t.Fatalf("ListPluginConfigs failed: %v", err)
+	}
+	var paths []string
+	for _, f := range listing.Files {
+		paths = append(paths, f.Path)
+	}
+	if listing.Plugin != "Essentials" || listing.DataFolder != "plugins/essentials" || strings.Join(paths, ",") != "config.yml,userdata.json,kits/tools.yml" {
+		t.Fatalf("unexpected listing %+v", listing)
+	}
+
+	if _, err := mgr.ListPluginConfigs("srv", "Fresh-1.0.jar"); !errors.Is(err, ErrPluginDataFolderNotFound) {
+		t.Fatalf("expected a missing data folder, got %v", err)
+	}
+
+	data, err := mgr.ReadPluginConfig("srv", "EssentialsX-2.20.1.jar", "kits/tools.yml")
+	if err != nil || string(data) != "kits: {}\n" {
+		t.Fatalf("ReadPluginConfig = %q, %v", data, err)
+	}
+	if _, err := mgr.ReadPluginConfig("srv", "EssentialsX-2.20.1.jar", "../../server.properties"); err == nil {
+		t.Fatal("expected a path outside the data folder to be refused")
+	}
+	if _, err := mgr.ReadPluginConfig("srv", "EssentialsX-2.20.1.jar", "userdata/abc.dat"); err == nil {
+		t.Fatal("expected a non-config file to be refused")
+	}
+
+	// Broken YAML or JSON is refused and leaves the file alone.
+	if err := mgr.WritePluginConfig("srv", "EssentialsX-2.20.1.jar", "config.yml", []byte("ops-name-color: [\n")); err == nil {
+		t.Fatal("expected invalid YAML to be refused")
+	}
+	if err := mgr.WritePluginConfig("srv", "EssentialsX-2.20.1.jar", "userdata.json", []byte("{")); err == nil {
+		t.Fatal("expected invalid JSON to be refused")
+	}
+	if err := mgr.WritePluginConfig("srv", "EssentialsX-2.20.1.jar", "config.yml", []byte("ops-name-color: 'c'\n")); err != nil {
+		t.Fatalf("WritePluginConfig failed: %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(pluginsDir, "essentials", "config.yml"))
+	if string(data) != "ops-name-color: 'c'\n" {
+		t.Fatalf("config not written, got %q", data)
+	}
+	entries, _ := os.ReadDir(filepath.Join(pluginsDir, "essentials"))
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".config-") {
+			t.Fatalf("temporary file %s left behind", e.Name())
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package minecraft
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// pluginInstallSource is a user-supplied link resolved to a downloadable jar.
+type pluginInstallSource struct {
+	downloadURL string
+	sourceURL   string // project page to keep as the plugin's source link
+}
+
+// parseModrinthPageProject extracts the slug from modrinth.com/<type>/<slug>
+// project pages.
+func parseModrinthPageProject(raw string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", false
+	}
+	if strings.ToLower(strings.TrimPrefix(u.Hostname(), "www.")) != "modrinth.com" {
+		return "", false
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || segments[1] == "" {
+		return "", false
+	}
+	switch strings.ToLower(segments[0]) {
+	case "project", "plugin", "mod":
+		return segments[1], true
+	}
+	return "", false
+}
+
+// parseGitHubReleasePage matches github.com/<owner>/<repo>/releases,
+// .../releases/latest and .../releases/tag/<tag>. An empty tag means latest.
+func parseGitHubReleasePage(raw string) (owner, repo, tag string, ok bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", "", "", false
+	}
+	if strings.ToLower(strings.TrimPrefix(u.Hostname(), "www.")) != "github.com" {
+		return "", "", "", false
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 3 || segments[0] == "" || segments[1] == "" || segments[2] != "releases" {
+		return "", "", "", false
+	}
+	switch {
+	case len(segments) == 3, len(segments) == 4 && segments[3] == "latest":
+		return segments[0], segments[1], "", true
+	case len(segments) == 5 && segments[3] == "tag" && segments[4] != "":
+		return segments[0], segments[1], segments[4], true
+	}
+	return "", "", "", false
+}
+
+// resolvePluginInstallSource turns a Modrinth project, Spigot resource,
+// Jenkins job or GitHub release page into a jar download. Any other link is
+// treated as a direct download and still has to pass the update download host
+// policy.
+func resolvePluginInstallSource(ctx context.Context, raw, mcVersion, serverType string) (*pluginInstallSource, error) {
+	raw = strings.TrimSpace(raw)
+	if projectID, ok := parseModrinthPageProject(raw); ok {
+		downloadURL, err := latestModrinthJarURL(ctx, projectID, mcVersion, serverType)
+		if err != nil {
+			return nil, err
+		}
+		return &pluginInstallSource{
+			downloadURL: downloadURL,
+			sourceURL:   "https://modrinth.com/project/" + url.PathEscape(projectID),
+		}, nil
+	}
+	if resourceID, ok := parseSpigotResourceIDFromURL(raw); ok {
+		if isModdedType(serverType) {
+			return nil, fmt.Errorf("modded servers cannot install Spigot resources")
+		}
+		return &pluginInstallSource{
+			downloadURL: fmt.Sprintf("https://api.spiget.org/v2/resources/%d/download", resourceID),
+			sourceURL:   raw,
+		}, nil
+	}
+	if jobURL, ok := parseJenkinsJobURL(raw); ok && !strings.Contains(raw, "/artifact/") {
+		downloadURL, err := latestJenkinsJarURL(ctx, jobURL)
+		if err != nil {
+			return nil, err
+		}
+		return &pluginInstallSource{downloadURL: downloadURL, sourceURL: jobURL}, nil
+	}
+	if owner, repo, tag, ok := parseGitHubReleasePage(raw); ok {
+		downloadURL, err := gitHubReleaseJarURL(ctx, owner, repo, tag)
+		if err != nil {
+			return nil, err
+		}
+		return &pluginInstallSource{downloadURL: downloadURL}, nil
+	}
+	return &pluginInstallSource{downloadURL: raw}, nil
+}
+
+// latestModrinthJarURL picks the newest stable version of a project that
+// supports the server's loader and Minecraft version.
+func latestModrinthJarURL(ctx context.Context, projectID, mcVersion, serverType string) (string, error) {
+	var versions []modrinthVersion
+	if err := fetchJSON(ctx, fmt.Sprintf("https://api.modrinth.com/v2/project/%s/version", url.PathEscape(projectID)), &versions); err != nil {
+		return "", fmt.Errorf("failed to look up Modrinth project %s: %w", projectID, err)
+	}
+	allowedLoaders := loaderTagsForType(serverType)
+	for i := range versions {
+		v := &versions[i]
+		if !isStableModrinthVersion(v) {
+			continue
+		}
+		loaderMatch := len(allowedLoaders) == 0
+		for _, vl := range v.Loaders {
+			for _, al := range allowedLoaders {
+				if strings.EqualFold(vl, al) {
+					loaderMatch = true
+				}
+			}
+		}
+		gameMatch := false
+		for _, gv := range v.GameVersions {
+			if gv == mcVersion {
+				gameMatch = true
+				break
+			}
+		}
+		if !loaderMatch || !gameMatch {
+			continue
+		}
+		if jarURL := modrinthVersionJarURL(v); jarURL != "" {
+			return jarURL, nil
+		}
+	}
+	return "", fmt.Errorf("no stable version of %s supports %s %s", projectID, serverType, mcVersion)
+}
+
+// modrinthVersionJarURL returns the version's primary jar, or its first jar
+// when none is marked primary. It returns "" when the version has no jar.
+func modrinthVersionJarURL(v *modrinthVersion) string {
+	for _, f := range v.Files {
+		if strings.HasSuffix(strings.ToLower(f.Filename), ".jar") && (f.Primary || len(v.Files) == 1) {
+			return f.URL
+		}
+	}
+	for _, f := range v.Files {
+		if strings.HasSuffix(strings.ToLower(f.Filename), ".jar") {
+			return f.URL
+		}
+	}
+	return ""
+}
+
+type gitHubRelease struct {
+	Assets []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// gitHubReleaseJarURL returns the release's jar asset, skipping source and
+// javadoc jars.
+func gitHubReleaseJarURL(ctx context.Context, owner, repo, tag string) (string, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", url.PathEscape(owner), url.PathEscape(repo))
+	if tag != "" {
+		apiURL = fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/tags/%s", url.PathEscape(owner), url.PathEscape(repo), url.PathEscape(tag))
+	}
+	var release gitHubRelease
+	if err := fetchJSON(ctx, apiURL, &release); err != nil {
+		return "", fmt.Errorf("failed to look up GitHub release: %w", err)
+	}
+	for _, asset := range release.Assets {
+		name := strings.ToLower(asset.Name)
+		if !strings.HasSuffix(name, ".jar") || strings.HasSuffix(name, "-sources.jar") || strings.HasSuffix(name, "-javadoc.jar") {
+			continue
+		}
+		return asset.BrowserDownloadURL, nil
+	}
+	return "", fmt.Errorf("GitHub release has no jar asset")
+}
+
+// InstallPluginFromURL downloads a plugin/mod jar server-side and installs it
+// like an upload, including the quarantine scan.
+func (m *Manager) InstallPluginFromURL(id, rawURL, conflictAction string) (string, string, error) {
+	job := m.newJob(JobTypePluginInstall, id)
+	job.start("Installing from " + strings.TrimSpace(rawURL))
+	name, status, err := m.installPluginFromURLJob(job, id, rawURL, conflictAction)
+	job.finish(err)
+	return name, status, err
+}
+
+func (m *Manager) installPluginFromURLJob(job *jobHandle, id, rawURL, conflictAction string) (string, string, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return "", "", err
+	}
+
+	ctx, cancel := context.WithTimeout(job.ctx, 5*time.Minute)
+	defer cancel()
+	source, err := resolvePluginInstallSource(ctx, rawURL, cfg.Version, cfg.Type)
+	if err != nil {
+		return "", "", err
+	}
+	return m.installResolvedPlugin(ctx, job, id, source, conflictAction)
+}
+
+// installResolvedPlugin downloads a resolved jar and installs it like an
+// upload, recording where it came from.
+func (m *Manager) installResolvedPlugin(ctx context.Context, job *jobHandle, id string, source *pluginInstallSource, conflictAction string) (string, string, error) {
+	job.progress(20, "Resolved download")
+
+	tmpDir, err := os.MkdirTemp("", "orexa-plugin-url-")
+	if err != nil {
+		return "", "", err
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpPath := filepath.Join(tmpDir, "download")
+	downloadResult, err := secureDownloadPluginUpdate(ctx, source.downloadURL, tmpPath, maxPluginUpdateBytesFromEnv())
+	if err != nil {
+		return "", "", err
+	}
+	job.progress(60, "Downloaded jar")
+
+	jarPath, err := materializeDownloadJar(tmpPath)
+	if err != nil {
+		return "", "", err
+	}
+	name, version := extractPluginVersion(jarPath)
+	fileName := resolveUpdateJarFileName(downloadResult.ResolvedURL, "", downloadResult.ContentDisposition)
+	if !strings.HasSuffix(strings.ToLower(fileName), ".jar") || isUnfriendlyJarFileName(fileName) {
+		baseName := sanitizeFilenameComponent(name)
+		if baseName == "" {
+			return "", "", fmt.Errorf("could not determine a file name for the downloaded jar")
+		}
+		if versionPart := sanitizeFilenameComponent(version); versionPart != "" {
+			baseName += "-" + versionPart
+		}
+		fileName = baseName + ".jar"
+	}
+
+	installedFrom := downloadResult.ResolvedURL
+	if strings.TrimSpace(installedFrom) == "" {
+		installedFrom = source.downloadURL
+	}
+	savedName, status, err := m.installPluginFile(id, fileName, jarPath, conflictAction, pluginInstallOptions{
+		scan:        true,
+		origin:      ExtensionOriginURL,
+		sourceURL:   source.sourceURL,
+		downloadURL: installedFrom,
+	})
+	if err != nil {
+		return savedName, status, err
+	}
+	log.Printf("Installed %s for server %s from %s", savedName, id, installedFrom)
+	return savedName, status, nil
+}
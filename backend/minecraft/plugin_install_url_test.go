@@ -0,0 +1,47 @@
+package minecraft
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResolvePluginInstallSourceLinks(t *testing.T) {
+	if slug, ok := parseModrinthPageProject("https://modrinth.com/plugin/luckperms"); !ok || slug != "luckperms" {
+		t.Fatalf("expected Modrinth plugin page to parse, got %q %v", slug, ok)
+	}
+	if _, ok := parseModrinthPageProject("https://cdn.modrinth.com/data/abc/versions/1/x.jar"); ok {
+		t.Fatalf("expected CDN file link to be treated as a direct download")
+	}
+
+	cases := []struct {
+		raw, owner, repo, tag string
+	}{
+		{"https://github.com/EssentialsX/Essentials/releases", "EssentialsX", "Essentials", ""},
+		{"https://github.com/EssentialsX/Essentials/releases/latest", "EssentialsX", "Essentials", ""},
+		{"https://github.com/EssentialsX/Essentials/releases/tag/2.20.1", "EssentialsX", "Essentials", "2.20.1"},
+	}
+	for _, tc := range cases {
+		owner, repo, tag, ok := parseGitHubReleasePage(tc.raw)
+		if !ok || owner != tc.owner || repo != tc.repo || tag != tc.tag {
+			t.Fatalf("parseGitHubReleasePage(%q) = %q %q %q %v", tc.raw, owner, repo, tag, ok)
+		}
+	}
+	if _, _, _, ok := parseGitHubReleasePage("https://github.com/EssentialsX/Essentials/releases/download/2.20.1/EssentialsX-2.20.1.jar"); ok {
+		t.Fatalf("expected release asset link to be treated as a direct download")
+	}
+
+	spigot := "https://www.spigotmc.org/resources/luckperms.28140/"
+	source, err := resolvePluginInstallSource(context.Background(), spigot, "1.21.4", "Paper")
+	if err != nil || source.downloadURL != "https://api.spiget.org/v2/resources/28140/download" || source.sourceURL != spigot {
+		t.Fatalf("unexpected Spigot resolution: %+v (%v)", source, err)
+	}
+	if _, err := resolvePluginInstallSource(context.Background(), spigot, "1.21.4", "Fabric"); err == nil {
+		t.Fatalf("expected Spigot links to be rejected on modded servers")
+	}
+
+	direct := "https://cdn.modrinth.com/data/abc/versions/1/x.jar"
+	source, err = resolvePluginInstallSource(context.Background(), direct, "1.21.4", "Paper")
+	if err != nil || source.downloadURL != direct || source.sourceURL != "" {
+		t.Fatalf("unexpected direct resolution: %+v (%v)", source, err)
+	}
+}
@@ -0,0 +1,198 @@
+package minecraft
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// jenkinsBuild is the subset of a Jenkins build's api/json we read.
+type jenkinsBuild struct {
+	Number    int               `json:"number"`
+	URL       string            `json:"url"`
+	Artifacts []jenkinsArtifact `json:"artifacts"`
+}
+
+type jenkinsArtifact struct {
+	FileName     string `json:"fileName"`
+	RelativePath string `json:"relativePath"`
+}
+
+// parseJenkinsJobURL extracts the job URL from a Jenkins job link such as
+// https://ci.dmulloy2.net/job/ProtocolLib/ or a link to one of its builds.
+// Folder jobs (job/<folder>/job/<name>) and a context path in front of the
+// first job segment are kept.
+func parseJenkinsJobURL(raw string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return "", false
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	start := -1
+	for i, segment := range segments {
+		if segment == "job" {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return "", false
+	}
+	end := start
+	for end+1 < len(segments) && segments[end] == "job" && segments[end+1] != "" {
+		end += 2
+	}
+	if end == start {
+		return "", false
+	}
+	return fmt.Sprintf("%s://%s/%s/", u.Scheme, u.Host, strings.Join(segments[:end], "/")), true
+}
+
+// jenkinsJobAllowed reports whether the panel may query a Jenkins job. Job
+// links point at arbitrary hosts, so they share the update download allowlist.
+func jenkinsJobAllowed(jobURL string) bool {
+	u, err := url.Parse(jobURL)
+	if err != nil || u.Scheme != "https" {
+		return false
+	}
+	return hostAllowedByPolicy(u.Hostname(), pluginUpdateAllowedHosts())
+}
+
+// pickJenkinsArtifact chooses the plugin jar among a build's artifacts,
+// preferring one named after the plugin and skipping source/javadoc jars.
+func pickJenkinsArtifact(build *jenkinsBuild, pluginName string) (fileName, relativePath string, ok bool) {
+	want := normalizeProjectName(pluginName)
+	for _, preferName := range []bool{true, false} {
+		for _, artifact := range build.Artifacts {
+			name := strings.ToLower(artifact.FileName)
+			if !strings.HasSuffix(name, ".jar") || strings.HasSuffix(name, "-sources.jar") || strings.HasSuffix(name, "-javadoc.jar") {
+				continue
+			}
+			if preferName && (want == "" || !strings.Contains(normalizeProjectName(artifact.FileName), want)) {
+				continue
+			}
+			return artifact.FileName, artifact.RelativePath, true
+		}
+	}
+	return "", "", false
+}
+
+var jenkinsBuildNumberPattern = regexp.MustCompile(`(?i)(?:[-+.#]|\bb|build)(\d+)\s*\)?$`)
+
+// jenkinsBuildNumberFromVersion reads a trailing CI build number from a
+// plugin version, e.g. "5.3.0-SNAPSHOT-732" or "2.20.1-b1234".
+func jenkinsBuildNumberFromVersion(version string) (int, bool) {
+	version = strings.TrimSpace(version)
+	m := jenkinsBuildNumberPattern.FindStringSubmatch(version)
+	if m == nil {
+		return 0, false
+	}
+	// A plain release like "1.2.3" ends in ".3"; that is a version part.
+	if strings.HasPrefix(version[len(version)-len(m[0]):], ".") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// compareJenkinsBuild compares the installed version against the latest
+// successful build. Build numbers win when the installed version carries one;
+// otherwise the version embedded in the artifact name is used.
+func compareJenkinsBuild(currentVersion, artifactName string, buildNumber int) (int, bool) {
+	if current, ok := jenkinsBuildNumberFromVersion(currentVersion); ok {
+		switch {
+		case buildNumber > current:
+			return 1, true
+		case buildNumber == current:
+			return 0, true
+		default:
+			return -1, true
+		}
+	}
+	if len(parseVersionCandidates(artifactName)) == 0 {
+		return 0, false
+	}
+	return compareLatestToCurrent(currentVersion, strings.TrimSuffix(artifactName, ".jar"))
+}
+
+// checkJenkinsJob checks a plugin against the job's last successful build.
+func checkJenkinsJob(ctx context.Context, jobURL, pluginName, currentVersion string) *PluginUpdateInfo {
+	if !jenkinsJobAllowed(jobURL) {
+		return nil
+	}
+	build, err := fetchLastSuccessfulJenkinsBuild(ctx, jobURL)
+	if err != nil {
+		if debugPluginUpdatesEnabled() {
+			log.Printf("[UpdateDebug] jenkins job=%q fetch failed: %v", jobURL, err)
+		}
+		return nil
+	}
+	fileName, relativePath, ok := pickJenkinsArtifact(build, pluginName)
+	if !ok || build.Number <= 0 {
+		return nil
+	}
+
+	info := &PluginUpdateInfo{
+		Name:          pluginName,
+		Version:       currentVersion,
+		LatestVersion: fmt.Sprintf("build #%d", build.Number),
+	}
+	if len(parseVersionCandidates(fileName)) > 0 {
+		info.LatestVersion = fmt.Sprintf("%s (build #%d)", strings.TrimSuffix(fileName, ".jar"), build.Number)
+	}
+	if debugPluginUpdatesEnabled() {
+		log.Printf("[UpdateDebug] jenkins job=%q plugin=%q current=%q build=%d artifact=%q", jobURL, pluginName, currentVersion, build.Number, fileName)
+	}
+
+	cmp, confident := compareJenkinsBuild(currentVersion, fileName, build.Number)
+	switch {
+	case !confident || cmp < 0:
+		info.VersionStatus = "unknown"
+	case cmp == 0:
+		info.VersionStatus = "latest"
+	default:
+		info.VersionStatus = "outdated"
+		info.UpdateURL = jenkinsArtifactURL(jobURL, relativePath)
+	}
+	return info
+}
+
+func fetchLastSuccessfulJenkinsBuild(ctx context.Context, jobURL string) (*jenkinsBuild, error) {
+	var build jenkinsBuild
+	if err := fetchJSON(ctx, jobURL+"lastSuccessfulBuild/api/json?tree=number,url,artifacts[fileName,relativePath]", &build); err != nil {
+		return nil, err
+	}
+	return &build, nil
+}
+
+func jenkinsArtifactURL(jobURL, relativePath string) string {
+	parts := strings.Split(relativePath, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return jobURL + "lastSuccessfulBuild/artifact/" + strings.Join(parts, "/")
+}
+
+// latestJenkinsJarURL resolves a job link to its last successful jar for
+// installs from a link.
+func latestJenkinsJarURL(ctx context.Context, jobURL string) (string, error) {
+	if !jenkinsJobAllowed(jobURL) {
+		return "", fmt.Errorf("Jenkins host is not allowed; add it to ADPANEL_PLUGIN_UPDATE_ALLOWED_HOSTS")
+	}
+	build, err := fetchLastSuccessfulJenkinsBuild(ctx, jobURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up Jenkins job: %w", err)
+	}
+	_, relativePath, ok := pickJenkinsArtifact(build, "")
+	if !ok {
+		return "", fmt.Errorf("last successful Jenkins build has no jar artifact")
+	}
+	return jenkinsArtifactURL(jobURL, relativePath), nil
+}
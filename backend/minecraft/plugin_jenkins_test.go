@@ -0,0 +1,70 @@
+package minecraft
+
+import "testing"
+
+func TestParseJenkinsJobURL(t *testing.T) {
+	cases := map[string]string{
+		"https://ci.dmulloy2.net/job/ProtocolLib/":                     "https://ci.dmulloy2.net/job/ProtocolLib/",
+		"https://ci.dmulloy2.net/job/ProtocolLib/lastSuccessfulBuild/": "https://ci.dmulloy2.net/job/ProtocolLib/",
+		"https://ci.codemc.io/job/Folder/job/Plugin/42/artifact/x.jar": "https://ci.codemc.io/job/Folder/job/Plugin/",
+		"https://example.com/jenkins/job/Thing":                        "https://example.com/jenkins/job/Thing/",
+	}
+	for raw, want := range cases {
+		got, ok := parseJenkinsJobURL(raw)
+		if !ok || got != want {
+			t.Fatalf("parseJenkinsJobURL(%q) = %q, %v; want %q", raw, got, ok, want)
+		}
+	}
+	for _, raw := range []string{"https://modrinth.com/plugin/foo", "https://ci.dmulloy2.net/job/", "ftp://ci.dmulloy2.net/job/x"} {
+		if _, ok := parseJenkinsJobURL(raw); ok {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+
+	t.Setenv("ADPANEL_PLUGIN_UPDATE_ALLOWED_HOSTS", "")
+	if !jenkinsJobAllowed("https://ci.dmulloy2.net/job/ProtocolLib/") {
+		t.Fatalf("expected default Jenkins host to be allowed")
+	}
+	if jenkinsJobAllowed("https://example.com/jenkins/job/Thing/") || jenkinsJobAllowed("http://ci.dmulloy2.net/job/ProtocolLib/") {
+		t.Fatalf("expected unknown hosts and plain http to be rejected")
+	}
+}
+
+func TestCompareJenkinsBuild(t *testing.T) {
+	cases := []struct {
+		current, artifact string
+		build             int
+		want              int
+		confident         bool
+	}{
+		{"5.3.0-SNAPSHOT-732", "ProtocolLib.jar", 740, 1, true},
+		{"5.3.0-SNAPSHOT-740", "ProtocolLib.jar", 740, 0, true},
+		{"2.20.1-b1234", "EssentialsX.jar", 1234, 0, true},
+		{"5.4.150", "LuckPerms-Bukkit-5.4.152.jar", 1500, 1, true},
+		{"5.4.152", "LuckPerms-Bukkit-5.4.152.jar", 1500, 0, true},
+		{"5.3.0", "ProtocolLib.jar", 740, 0, false},
+	}
+	for _, tc := range cases {
+		got, confident := compareJenkinsBuild(tc.current, tc.artifact, tc.build)
+		if got != tc.want || confident != tc.confident {
+			t.Fatalf("compareJenkinsBuild(%q, %q, %d) = %d, %v; want %d, %v", tc.current, tc.artifact, tc.build, got, confident, tc.want, tc.confident)
+		}
+	}
+}
+
+func TestPickJenkinsArtifact(t *testing.T) {
+	build := &jenkinsBuild{Number: 10, Artifacts: []jenkinsArtifact{
+		{FileName: "Core-1.0-sources.jar", RelativePath: "core/build/libs/Core-1.0-sources.jar"},
+		{FileName: "Core-1.0.jar", RelativePath: "core/build/libs/Core-1.0.jar"},
+		{FileName: "Widget-Bukkit-1.0.jar", RelativePath: "bukkit/build/libs/Widget-Bukkit-1.0.jar"},
+	}}
+	if name, _, ok := pickJenkinsArtifact(build, "Widget"); !ok || name != "Widget-Bukkit-1.0.jar" {
+		t.Fatalf("expected the plugin-named jar, got %q", name)
+	}
+	if name, _, ok := pickJenkinsArtifact(build, ""); !ok || name != "Core-1.0.jar" {
+		t.Fatalf("expected the first non-source jar, got %q", name)
+	}
+	if got := jenkinsArtifactURL("https://ci.example.com/job/W/", "bukkit/build/libs/W 1.jar"); got != "https://ci.example.com/job/W/lastSuccessfulBuild/artifact/bukkit/build/libs/W%201.jar" {
+		t.Fatalf("unexpected artifact URL %q", got)
+	}
+}
@@ -0,0 +1,276 @@
+package minecraft
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Project sources that plugin metadata can be resolved from.
+const (
+	pluginProjectModrinth = "modrinth"
+	pluginProjectSpigot   = "spigot"
+	pluginProjectHangar   = "hangar"
+)
+
+const (
+	pluginProjectCacheTTL       = 24 * time.Hour
+	pluginProjectFailureBackoff = 30 * time.Minute
+)
+
+// pluginProjectRef identifies a project on a plugin/mod hosting site.
+type pluginProjectRef struct {
+	source string
+	id     string
+}
+
+func (r pluginProjectRef) key() string {
+	return r.source + ":" + r.id
+}
+
+func (r pluginProjectRef) valid() bool {
+	return r.source != "" && r.id != ""
+}
+
+// pluginProjectMetadata is the public listing information for a project.
+type pluginProjectMetadata struct {
+	Title       string
+	Description string
+	IconURL     string
+	ProjectURL  string
+}
+
+type pluginProjectCacheEntry struct {
+	metadata  *pluginProjectMetadata // nil when the last lookup failed
+	fetchedAt time.Time
+}
+
+// pluginProjectCache holds project metadata plus the project each installed
+// file was last matched to by an update check.
+var pluginProjectCache = struct {
+	mu       sync.RWMutex
+	projects map[string]pluginProjectCacheEntry
+	links    map[string]pluginProjectRef
+	inflight map[string]bool
+}{
+	projects: make(map[string]pluginProjectCacheEntry),
+	links:    make(map[string]pluginProjectRef),
+	inflight: make(map[string]bool),
+}
+
+func pluginProjectLinkKey(serverID, fileName string) string {
+	return serverID + ":" + normalizeExtensionSourceKey(fileName)
+}
+
+// pluginProjectFromSourceURL maps a stored source link to a project.
+func pluginProjectFromSourceURL(raw string) (pluginProjectRef, bool) {
+	if resourceID, ok := parseSpigotResourceIDFromURL(raw); ok {
+		return pluginProjectRef{source: pluginProjectSpigot, id: strconv.Itoa(resourceID)}, true
+	}
+	if projectID, ok := parseModrinthProjectFromURL(raw); ok {
+		return pluginProjectRef{source: pluginProjectModrinth, id: projectID}, true
+	}
+	if slug, ok := parseHangarProjectFromURL(raw); ok {
+		return pluginProjectRef{source: pluginProjectHangar, id: slug}, true
+	}
+	return pluginProjectRef{}, false
+}
+
+// parseHangarProjectFromURL extracts the project slug from a
+// hangar.papermc.io/<owner>/<slug> link.
+func parseHangarProjectFromURL(raw string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", false
+	}
+	host := strings.ToLower(strings.TrimPrefix(u.Hostname(), "www."))
+	if host != "hangar.papermc.io" {
+		return "", false
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] == "" || segments[1] == "" {
+		return "", false
+	}
+	return segments[1], true
+}
+
+// rememberPluginProject records which project an installed file resolved to.
+func rememberPluginProject(serverID, fileName string, ref pluginProjectRef) {
+	if !ref.valid() {
+		return
+	}
+	pluginProjectCache.mu.Lock()
+	pluginProjectCache.links[pluginProjectLinkKey(serverID, fileName)] = ref
+	pluginProjectCache.mu.Unlock()
+}
+
+// cachedPluginProject returns cached metadata and whether a (re)fetch is due.
+func cachedPluginProject(ref pluginProjectRef) (*pluginProjectMetadata, bool) {
+	pluginProjectCache.mu.RLock()
+	entry, ok := pluginProjectCache.projects[ref.key()]
+	pluginProjectCache.mu.RUnlock()
+	if !ok {
+		return nil, true
+	}
+	ttl := pluginProjectCacheTTL
+	if entry.metadata == nil {
+		ttl = pluginProjectFailureBackoff
+	}
+	return entry.metadata, time.Since(entry.fetchedAt) >= ttl
+}
+
+// resolvePluginProject returns project metadata, fetching it when the cache
+// is empty or stale.
+func resolvePluginProject(ctx context.Context, ref pluginProjectRef) *pluginProjectMetadata {
+	metadata, stale := cachedPluginProject(ref)
+	if !stale {
+		return metadata
+	}
+	fetched, err := fetchPluginProject(ctx, ref)
+	if err != nil {
+		if debugPluginUpdatesEnabled() {
+			log.Printf("[UpdateDebug] project metadata %s lookup failed: %v", ref.key(), err)
+		}
+		if metadata != nil {
+			return metadata
+		}
+	}
+	pluginProjectCache.mu.Lock()
+	pluginProjectCache.projects[ref.key()] = pluginProjectCacheEntry{metadata: fetched, fetchedAt: time.Now()}
+	pluginProjectCache.mu.Unlock()
+	return fetched
+}
+
+// prefetchPluginProject fetches metadata in the background so a later
+// listing can include it. Concurrent requests for one project are merged.
+func prefetchPluginProject(ref pluginProjectRef) {
+	pluginProjectCache.mu.Lock()
+	if pluginProjectCache.inflight[ref.key()] {
+		pluginProjectCache.mu.Unlock()
+		return
+	}
+	pluginProjectCache.inflight[ref.key()] = true
+	pluginProjectCache.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		resolvePluginProject(ctx, ref)
+		pluginProjectCache.mu.Lock()
+		delete(pluginProjectCache.inflight, ref.key())
+		pluginProjectCache.mu.Unlock()
+	}()
+}
+
+// attachPluginProjects fills icon, description and project page from cached
+// metadata. Missing metadata is fetched in the background for the next call.
+func attachPluginProjects(serverID string, plugins []PluginInfo) {
+	for i := range plugins {
+		pluginProjectCache.mu.RLock()
+		ref, linked := pluginProjectCache.links[pluginProjectLinkKey(serverID, plugins[i].FileName)]
+		pluginProjectCache.mu.RUnlock()
+		if fromSource, ok := pluginProjectFromSourceURL(plugins[i].SourceURL); ok {
+			ref, linked = fromSource, true
+		}
+		if !linked {
+			continue
+		}
+
+		metadata, stale := cachedPluginProject(ref)
+		if stale {
+			prefetchPluginProject(ref)
+		}
+		if metadata == nil {
+			continue
+		}
+		plugins[i].IconURL = metadata.IconURL
+		plugins[i].Description = metadata.Description
+		plugins[i].ProjectURL = metadata.ProjectURL
+	}
+}
+
+type modrinthProject struct {
+	Slug        string `json:"slug"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	IconURL     string `json:"icon_url"`
+	ProjectType string `json:"project_type"`
+}
+
+type spigetResourceDetails struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Tag  string `json:"tag"`
+	Icon struct {
+		URL string `json:"url"`
+	} `json:"icon"`
+}
+
+type hangarProject struct {
+	Name      string `json:"name"`
+	Namespace struct {
+		Owner string `json:"owner"`
+		Slug  string `json:"slug"`
+	} `json:"namespace"`
+	Description string `json:"description"`
+	AvatarURL   string `json:"avatarUrl"`
+}
+
+func fetchPluginProject(ctx context.Context, ref pluginProjectRef) (*pluginProjectMetadata, error) {
+	switch ref.source {
+	case pluginProjectModrinth:
+		var project modrinthProject
+		if err := fetchJSON(ctx, "https://api.modrinth.com/v2/project/"+url.PathEscape(ref.id), &project); err != nil {
+			return nil, err
+		}
+		projectType := strings.TrimSpace(project.ProjectType)
+		if projectType == "" {
+			projectType = "project"
+		}
+		slug := strings.TrimSpace(project.Slug)
+		if slug == "" {
+			slug = ref.id
+		}
+		return &pluginProjectMetadata{
+			Title:       project.Title,
+			Description: strings.TrimSpace(project.Description),
+			IconURL:     project.IconURL,
+			ProjectURL:  fmt.Sprintf("https://modrinth.com/%s/%s", projectType, url.PathEscape(slug)),
+		}, nil
+	case pluginProjectSpigot:
+		var resource spigetResourceDetails
+		if err := fetchJSON(ctx, "https://api.spiget.org/v2/resources/"+url.PathEscape(ref.id), &resource); err != nil {
+			return nil, err
+		}
+		metadata := &pluginProjectMetadata{
+			Title:       resource.Name,
+			Description: strings.TrimSpace(resource.Tag),
+			ProjectURL:  fmt.Sprintf("https://www.spigotmc.org/resources/%s/", url.PathEscape(ref.id)),
+		}
+		if icon := strings.TrimSpace(resource.Icon.URL); icon != "" {
+			metadata.IconURL = "https://www.spigotmc.org/" + strings.TrimPrefix(icon, "/")
+		}
+		return metadata, nil
+	case pluginProjectHangar:
+		var project hangarProject
+		if err := fetchJSON(ctx, "https://hangar.papermc.io/api/v1/projects/"+url.PathEscape(ref.id), &project); err != nil {
+			return nil, err
+		}
+		metadata := &pluginProjectMetadata{
+			Title:       project.Name,
+			Description: strings.TrimSpace(project.Description),
+			IconURL:     project.AvatarURL,
+		}
+		if project.Namespace.Owner != "" && project.Namespace.Slug != "" {
+			metadata.ProjectURL = fmt.Sprintf("https://hangar.papermc.io/%s/%s", url.PathEscape(project.Namespace.Owner), url.PathEscape(project.Namespace.Slug))
+		}
+		return metadata, nil
+	default:
+		return nil, fmt.Errorf("unknown project source %q", ref.source)
+	}
+}
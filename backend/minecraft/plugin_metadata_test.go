@@ -0,0 +1,51 @@
+package minecraft
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPluginProjectFromSourceURL(t *testing.T) {
+	cases := map[string]pluginProjectRef{
+		"https://www.spigotmc.org/resources/luckperms.28140/": {source: pluginProjectSpigot, id: "28140"},
+		"https://modrinth.com/project/luckperms":              {source: pluginProjectModrinth, id: "luckperms"},
+		"https://hangar.papermc.io/Lucko/LuckPerms":           {source: pluginProjectHangar, id: "LuckPerms"},
+	}
+	for raw, want := range cases {
+		got, ok := pluginProjectFromSourceURL(raw)
+		if !ok || got != want {
+			t.Fatalf("pluginProjectFromSourceURL(%q) = %+v, %v; want %+v", raw, got, ok, want)
+		}
+	}
+	if _, ok := pluginProjectFromSourceURL("https://hangar.papermc.io/Lucko"); ok {
+		t.Fatalf("expected owner-only Hangar link to be rejected")
+	}
+}
+
+func TestAttachPluginProjectsUsesCachedMetadata(t *testing.T) {
+	ref := pluginProjectRef{source: pluginProjectModrinth, id: "test-attach-project"}
+	pluginProjectCache.mu.Lock()
+	pluginProjectCache.projects[ref.key()] = pluginProjectCacheEntry{
+		metadata: &pluginProjectMetadata{
+			Description: "Permissions plugin",
+			IconURL:     "https://cdn.example/icon.png",
+			ProjectURL:  "https://modrinth.com/plugin/test-attach-project",
+		},
+		fetchedAt: time.Now(),
+	}
+	pluginProjectCache.mu.Unlock()
+	rememberPluginProject("srv-attach", "Perms.jar", ref)
+
+	plugins := []PluginInfo{
+		{Name: "Perms", FileName: "Perms.jar.disabled"},
+		{Name: "Other", FileName: "Other.jar"},
+	}
+	attachPluginProjects("srv-attach", plugins)
+
+	if plugins[0].IconURL != "https://cdn.example/icon.png" || plugins[0].Description != "Permissions plugin" || plugins[0].ProjectURL == "" {
+		t.Fatalf("expected cached metadata on linked plugin, got %+v", plugins[0])
+	}
+	if plugins[1].IconURL != "" || plugins[1].ProjectURL != "" {
+		t.Fatalf("expected unlinked plugin to stay bare, got %+v", plugins[1])
+	}
+}
@@ -0,0 +1,186 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuarantinedExtension is an uploaded jar held back because its scan
+// produced findings. It stays out of the server until approved or discarded.
+type QuarantinedExtension struct {
+	ID            string        `json:"id"`
+	ServerID      string        `json:"serverId"`
+	FileName      string        `json:"fileName"`
+	Directory     string        `json:"directory,omitempty"`
+	Size          string        `json:"size"`
+	QuarantinedAt string        `json:"quarantinedAt"`
+	Report        JarScanReport `json:"report"`
+	Origin        string        `json:"origin,omitempty"`
+	SourceURL     string        `json:"sourceUrl,omitempty"`
+	DownloadURL   string        `json:"downloadUrl,omitempty"`
+}
+
+// QuarantineError is returned by uploads whose jar was quarantined.
+type QuarantineError struct {
+	Entry QuarantinedExtension
+}
+
+func (e *QuarantineError) Error() string {
+	return fmt.Sprintf("%s was quarantined: %s", e.Entry.FileName, strings.Join(e.Entry.Report.Findings, "; "))
+}
+
+func (m *Manager) pluginQuarantineDir(serverID string) string {
+	return filepath.Join(m.baseDir, "data", "plugin-quarantine", sanitizeName(serverID))
+}
+
+func (m *Manager) pluginQuarantinePaths(serverID, quarantineID string) (jarPath, metaPath string, err error) {
+	dir := m.pluginQuarantineDir(serverID)
+	jarPath, err = SafePath(dir, quarantineID+".jar")
+	if err != nil {
+		return "", "", err
+	}
+	metaPath, err = SafePath(dir, quarantineID+".json")
+	if err != nil {
+		return "", "", err
+	}
+	return jarPath, metaPath, nil
+}
+
+// quarantinePluginUpload moves a staged upload into data/plugin-quarantine/<server>/.
+func (m *Manager) quarantinePluginUpload(cfg *ServerConfig, fileName, sourcePath string, report *JarScanReport, opts pluginInstallOptions) (QuarantinedExtension, error) {
+	entry := QuarantinedExtension{
+		ID:            uuid.New().String()[:8],
+		ServerID:      cfg.ID,
+		FileName:      fileName,
+		Directory:     opts.dir,
+		QuarantinedAt: time.Now().UTC().Format(time.RFC3339),
+		Report:        *report,
+		Origin:        opts.origin,
+		SourceURL:     opts.sourceURL,
+		DownloadURL:   opts.downloadURL,
+	}
+	if info, err := os.Stat(sourcePath); err == nil {
+		entry.Size = formatFileSize(info.Size())
+	}
+	if err := os.MkdirAll(m.pluginQuarantineDir(cfg.ID), 0755); err != nil {
+		return entry, err
+	}
+	jarPath, metaPath, err := m.pluginQuarantinePaths(cfg.ID, entry.ID)
+	if err != nil {
+		return entry, err
+	}
+	if err := moveOrCopyFile(sourcePath, jarPath, false); err != nil {
+		return entry, fmt.Errorf("failed to quarantine %s: %w", fileName, err)
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		_ = os.Remove(jarPath)
+		return entry, err
+	}
+	if err := os.WriteFile(metaPath, data, 0644); err != nil {
+		_ = os.Remove(jarPath)
+		return entry, err
+	}
+	return entry, nil
+}
+
+// ListQuarantinedPlugins returns the quarantined uploads for a server, newest first.
+func (m *Manager) ListQuarantinedPlugins(id string) ([]QuarantinedExtension, error) {
+	m.mu.RLock()
+	_, ok := m.configs[id]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, errServerNotFound(id)
+	}
+
+	entries := make([]QuarantinedExtension, 0)
+	dirEntries, err := os.ReadDir(m.pluginQuarantineDir(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return nil, err
+	}
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || filepath.Ext(dirEntry.Name()) != ".json" {
+			continue
+		}
+		entry, err := m.loadPluginQuarantineEntry(id, strings.TrimSuffix(dirEntry.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].QuarantinedAt > entries[j].QuarantinedAt
+	})
+	return entries, nil
+}
+
+func (m *Manager) loadPluginQuarantineEntry(serverID, quarantineID string) (QuarantinedExtension, error) {
+	var entry QuarantinedExtension
+	_, metaPath, err := m.pluginQuarantinePaths(serverID, quarantineID)
+	if err != nil {
+		return entry, err
+	}
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entry, fmt.Errorf("quarantined file %s not found", quarantineID)
+		}
+		return entry, err
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, fmt.Errorf("invalid quarantine record %s: %w", quarantineID, err)
+	}
+	return entry, nil
+}
+
+// ApproveQuarantinedPlugin installs a quarantined jar despite its findings.
+// Conflict handling matches a regular upload.
+func (m *Manager) ApproveQuarantinedPlugin(id, quarantineID, conflictAction string) (string, string, error) {
+	entry, err := m.loadPluginQuarantineEntry(id, quarantineID)
+	if err != nil {
+		return "", "", err
+	}
+	jarPath, metaPath, err := m.pluginQuarantinePaths(id, quarantineID)
+	if err != nil {
+		return "", "", err
+	}
+	if entry.Origin == "" {
+		entry.Origin = ExtensionOriginUpload
+	}
+	savedName, status, err := m.installPluginFile(id, entry.FileName, jarPath, conflictAction, pluginInstallOptions{
+		dir:         entry.Directory,
+		origin:      entry.Origin,
+		sourceURL:   entry.SourceURL,
+		downloadURL: entry.DownloadURL,
+	})
+	if err != nil || status == "skipped" {
+		return savedName, status, err
+	}
+	_ = os.Remove(metaPath)
+	return savedName, status, nil
+}
+
+// DiscardQuarantinedPlugin deletes a quarantined jar.
+func (m *Manager) DiscardQuarantinedPlugin(id, quarantineID string) error {
+	if _, err := m.loadPluginQuarantineEntry(id, quarantineID); err != nil {
+		return err
+	}
+	jarPath, metaPath, err := m.pluginQuarantinePaths(id, quarantineID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(jarPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Remove(metaPath)
+}
@@ -0,0 +1,338 @@
+package minecraft
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Catalogs plugins and mods can be searched in and installed from.
+const (
+	PluginSourceModrinth = "modrinth"
+	PluginSourceSpiget   = "spiget"
+)
+
+const pluginSearchLimit = 20
+
+// ErrPluginCatalogUnavailable is returned when no catalog could be searched.
+var ErrPluginCatalogUnavailable = errors.New("plugin catalogs are unavailable")
+
+// Loader tags searched when no server narrows them down.
+var (
+	pluginSearchLoaders = []string{"paper", "spigot", "bukkit", "purpur", "folia", "velocity", "bungeecord", "waterfall"}
+	modSearchLoaders    = []string{"fabric", "forge", "neoforge", "quilt"}
+)
+
+// PluginSearchResult is one project found in a catalog. ProjectID is what
+// POST /api/servers/{id}/plugins/install takes.
+type PluginSearchResult struct {
+	Source       string   `json:"source"`
+	ProjectID    string   `json:"projectId"`
+	Slug         string   `json:"slug,omitempty"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	Author       string   `json:"author,omitempty"`
+	Downloads    int64    `json:"downloads"`
+	IconURL      string   `json:"iconUrl,omitempty"`
+	PageURL      string   `json:"pageUrl"`
+	GameVersions []string `json:"gameVersions,omitempty"`
+}
+
+// PluginSearchResponse holds the hits from every catalog searched. A
+// catalog that could not be reached is named in Errors and the others are
+// still returned.
+type PluginSearchResponse struct {
+	Results []PluginSearchResult `json:"results"`
+	Errors  []string             `json:"errors,omitempty"`
+}
+
+type modrinthSearchHits struct {
+	Hits []struct {
+		ProjectID   string   `json:"project_id"`
+		Slug        string   `json:"slug"`
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Author      string   `json:"author"`
+		Downloads   int64    `json:"downloads"`
+		IconURL     string   `json:"icon_url"`
+		Versions    []string `json:"versions"`
+	} `json:"hits"`
+}
+
+type spigetSearchResources []struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Tag       string `json:"tag"`
+	Downloads int64  `json:"downloads"`
+	Premium   bool   `json:"premium"`
+	External  bool   `json:"external"`
+	Icon      struct {
+		URL string `json:"url"`
+	} `json:"icon"`
+	TestedVersions []string `json:"testedVersions"`
+}
+
+// modrinthProjectPage and spigotResourcePage are the source links kept for
+// projects installed from a search, in the form the update check reads.
+func modrinthProjectPage(project string) string {
+	return "https://modrinth.com/project/" + url.PathEscape(project)
+}
+
+func spigotResourcePage(resourceID int) string {
+	return fmt.Sprintf("https://www.spigotmc.org/resources/%d/", resourceID)
+}
+
+// normalizePluginSearchType maps the type query parameter to "plugin" or
+// "mod". An empty type follows the server, or means plugins without one.
+func normalizePluginSearchType(projectType, serverType string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(projectType)) {
+	case "":
+		if isModdedType(serverType) {
+			return "mod", nil
+		}
+		return "plugin", nil
+	case "plugin", "plugins":
+		return "plugin", nil
+	case "mod", "mods":
+		return "mod", nil
+	}
+	return "", fmt.Errorf("type must be plugin or mod")
+}
+
+// modrinthSearchURL builds a Modrinth search restricted to the given loader
+// tags and, when set, a Minecraft version.
+func modrinthSearchURL(query string, loaders []string, mcVersion string) string {
+	loaderFacet := make([]string, 0, len(loaders))
+	for _, loader := range loaders {
+		loaderFacet = append(loaderFacet, "categories:"+loader)
+	}
+	facets := [][]string{loaderFacet}
+	if mcVersion != "" {
+		facets = append(facets, []string{"versions:" + mcVersion})
+	}
+	encoded, _ := json.Marshal(facets)
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("facets", string(encoded))
+	params.Set("limit", strconv.Itoa(pluginSearchLimit))
+	return "https://api.modrinth.com/v2/search?" + params.Encode()
+}
+
+func modrinthSearchResults(hits modrinthSearchHits) []PluginSearchResult {
+	results := make([]PluginSearchResult, 0, len(hits.Hits))
+	for _, hit := range hits.Hits {
+		results = append(results, PluginSearchResult{
+			Source:       PluginSourceModrinth,
+			ProjectID:    hit.ProjectID,
+			Slug:         hit.Slug,
+			Name:         hit.Title,
+			Description:  hit.Description,
+			Author:       hit.Author,
+			Downloads:    hit.Downloads,
+			IconURL:      hit.IconURL,
+			PageURL:      modrinthProjectPage(hit.Slug),
+			GameVersions: hit.Versions,
+		})
+	}
+	return results
+}
+
+// spigetSearchResults converts Spiget resources, leaving out premium and
+// externally hosted ones, which the panel cannot download.
+func spigetSearchResults(resources spigetSearchResources) []PluginSearchResult {
+	results := make([]PluginSearchResult, 0, len(resources))
+	for _, resource := range resources {
+		if resource.Premium || resource.External {
+			continue
+		}
+		result := PluginSearchResult{
+			Source:       PluginSourceSpiget,
+			ProjectID:    strconv.Itoa(resource.ID),
+			Name:         resource.Name,
+			Description:  resource.Tag,
+			Downloads:    resource.Downloads,
+			PageURL:      spigotResourcePage(resource.ID),
+			GameVersions: resource.TestedVersions,
+		}
+		if resource.Icon.URL != "" {
+			result.IconURL = "https://www.spigotmc.org/" + strings.TrimPrefix(resource.Icon.URL, "/")
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func searchModrinth(ctx context.Context, query string, loaders []string, mcVersion string) ([]PluginSearchResult, error) {
+	var hits modrinthSearchHits
+	if err := fetchJSON(ctx, modrinthSearchURL(query, loaders, mcVersion), &hits); err != nil {
+		return nil, err
+	}
+	return modrinthSearchResults(hits), nil
+}
+
+// searchSpiget searches Spigot resources by name, most downloaded first.
+// Spiget answers 404 when nothing matches.
+func searchSpiget(ctx context.Context, query string) ([]PluginSearchResult, error) {
+	searchURL := fmt.Sprintf("https://api.spiget.org/v2/search/resources/%s?field=name&size=%d&sort=-downloads&fields=id,name,tag,downloads,premium,external,icon,testedVersions",
+		url.PathEscape(query), pluginSearchLimit)
+	resp, err := doAPIGet(ctx, searchURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request to %s failed with status %d", searchURL, resp.StatusCode)
+	}
+	var resources spigetSearchResources
+	if err := json.NewDecoder(resp.Body).Decode(&resources); err != nil {
+		return nil, err
+	}
+	return spigetSearchResults(resources), nil
+}
+
+// SearchPlugins searches Modrinth and, for plugins, Spiget. With a server
+// ID, Modrinth results are limited to projects for that server's loader and
+// Minecraft version.
+func (m *Manager) SearchPlugins(ctx context.Context, query, projectType, serverID string) (*PluginSearchResponse, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("search query is required")
+	}
+	if len(query) > 100 {
+		return nil, fmt.Errorf("search query is too long")
+	}
+
+	var serverType, mcVersion string
+	if serverID = strings.TrimSpace(serverID); serverID != "" {
+		m.mu.RLock()
+		cfg, err := m.serverConfigForOperationLocked(serverID)
+		if err == nil {
+			serverType, mcVersion = cfg.Type, cfg.Version
+		}
+		m.mu.RUnlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+	projectType, err := normalizePluginSearchType(projectType, serverType)
+	if err != nil {
+		return nil, err
+	}
+	loaders := loaderTagsForType(serverType)
+	if len(loaders) == 0 {
+		loaders = pluginSearchLoaders
+		if projectType == "mod" {
+			loaders = modSearchLoaders
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	response := &PluginSearchResponse{Results: []PluginSearchResult{}}
+	results, err := searchModrinth(ctx, query, loaders, mcVersion)
+	if err != nil {
+		response.Errors = append(response.Errors, fmt.Sprintf("Modrinth: %v", err))
+	}
+	response.Results = append(response.Results, results...)
+	if projectType == "plugin" && !isModdedType(serverType) {
+		results, err := searchSpiget(ctx, query)
+		if err != nil {
+			response.Errors = append(response.Errors, fmt.Sprintf("Spiget: %v", err))
+		}
+		response.Results = append(response.Results, results...)
+	}
+	if len(response.Results) == 0 && len(response.Errors) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPluginCatalogUnavailable, strings.Join(response.Errors, "; "))
+	}
+	return response, nil
+}
+
+// resolveCatalogInstallSource finds the jar to download for a catalog
+// project. Without a version ID, the newest stable Modrinth version for the
+// server's loader and Minecraft version is used; Spiget always serves the
+// resource's latest version.
+func resolveCatalogInstallSource(ctx context.Context, source, projectID, versionID, mcVersion, serverType string) (*pluginInstallSource, error) {
+	projectID = strings.TrimSpace(projectID)
+	versionID = strings.TrimSpace(versionID)
+	if projectID == "" {
+		return nil, fmt.Errorf("projectId is required")
+	}
+	switch strings.ToLower(strings.TrimSpace(source)) {
+	case PluginSourceModrinth:
+		if versionID == "" {
+			downloadURL, err := latestModrinthJarURL(ctx, projectID, mcVersion, serverType)
+			if err != nil {
+				return nil, err
+			}
+			return &pluginInstallSource{downloadURL: downloadURL, sourceURL: modrinthProjectPage(projectID)}, nil
+		}
+		var version struct {
+			ProjectID string `json:"project_id"`
+			modrinthVersion
+		}
+		if err := fetchJSON(ctx, "https://api.modrinth.com/v2/version/"+url.PathEscape(versionID), &version); err != nil {
+			return nil, fmt.Errorf("failed to look up Modrinth version %s: %w", versionID, err)
+		}
+		if version.ProjectID != projectID {
+			return nil, fmt.Errorf("version %s does not belong to project %s", versionID, projectID)
+		}
+		downloadURL := modrinthVersionJarURL(&version.modrinthVersion)
+		if downloadURL == "" {
+			return nil, fmt.Errorf("Modrinth version %s has no jar file", versionID)
+		}
+		return &pluginInstallSource{downloadURL: downloadURL, sourceURL: modrinthProjectPage(projectID)}, nil
+	case PluginSourceSpiget:
+		resourceID, err := strconv.Atoi(projectID)
+		if err != nil || resourceID <= 0 {
+			return nil, fmt.Errorf("invalid Spigot resource ID %q", projectID)
+		}
+		if versionID != "" {
+			return nil, fmt.Errorf("Spiget only serves the latest version of a resource")
+		}
+		if isModdedType(serverType) {
+			return nil, fmt.Errorf("modded servers cannot install Spigot resources")
+		}
+		return &pluginInstallSource{
+			downloadURL: fmt.Sprintf("https://api.spiget.org/v2/resources/%d/download", resourceID),
+			sourceURL:   spigotResourcePage(resourceID),
+		}, nil
+	}
+	return nil, fmt.Errorf("source must be %s or %s", PluginSourceModrinth, PluginSourceSpiget)
+}
+
+// InstallPluginFromCatalog downloads a Modrinth or Spiget project straight
+// into the server's plugin or mod folder, keeping the project page as its
+// source so update checks find it.
+func (m *Manager) InstallPluginFromCatalog(id, source, projectID, versionID, conflictAction string) (string, string, error) {
+	job := m.newJob(JobTypePluginInstall, id)
+	job.start(fmt.Sprintf("Installing %s project %s", source, projectID))
+	name, status, err := m.installPluginFromCatalogJob(job, id, source, projectID, versionID, conflictAction)
+	job.finish(err)
+	return name, status, err
+}
+
+func (m *Manager) installPluginFromCatalogJob(job *jobHandle, id, source, projectID, versionID, conflictAction string) (string, string, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return "", "", err
+	}
+
+	ctx, cancel := context.WithTimeout(job.ctx, 5*time.Minute)
+	defer cancel()
+	resolved, err := resolveCatalogInstallSource(ctx, source, projectID, versionID, cfg.Version, cfg.Type)
+	if err != nil {
+		return "", "", err
+	}
+	return m.installResolvedPlugin(ctx, job, id, resolved, conflictAction)
+}
@@ -0,0 +1,93 @@
+package minecraft
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+)
+
+func TestNormalizePluginSearchType(t *testing.T) {
+	cases := []struct {
+		projectType, serverType, want string
+	}{
+		{"", "", "plugin"},
+		{"", "Paper", "plugin"},
+		{"", "NeoForge", "mod"},
+		{"Mods", "Paper", "mod"},
+		{"plugin", "Fabric", "plugin"},
+	}
+	for _, c := range cases {
+		if got, err := normalizePluginSearchType(c.projectType, c.serverType); err != nil || got != c.want {
+			t.Fatalf("normalizePluginSearchType(%q, %q) = %q, %v; want %q", c.projectType, c.serverType, got, err, c.want)
+		}
+	}
+	if _, err := normalizePluginSearchType("shader", ""); err == nil {
+		t.Fatal("expected an unknown type to be refused")
+	}
+}
+
+func TestModrinthSearchURLFacets(t *testing.T) {
+	u, err := url.Parse(modrinthSearchURL("world edit", []string{"paper", "spigot"}, "1.21.4"))
+	if err != nil {
+		t.Fatalf("invalid search URL: %v", err)
+	}
+	query := u.Query()
+	if query.Get("query") != "world edit" || query.Get("limit") != "20" {
+		t.Fatalf("unexpected query: %v", query)
+	}
+	var facets [][]string
+	if err := json.Unmarshal([]byte(query.Get("facets")), &facets); err != nil {
+		t.Fatalf("facets are not JSON: %v", err)
+	}
+	if len(facets) != 2 || len(facets[0]) != 2 || facets[0][1] != "categories:spigot" || facets[1][0] != "versions:1.21.4" {
+		t.Fatalf("unexpected facets: %v", facets)
+	}
+}
+
+func TestSpigetSearchResultsSkipUndownloadable(t *testing.T) {
+	var resources spigetSearchResources
+	raw := `[
+		{"id": 28140, "name": "LuckPerms", "tag": "A permissions plugin", "downloads": 900, "icon": {"url": "data/resource_icons/28/28140.jpg"}},
+		{"id": 1, "name": "Paid", "premium": true},
+		{"id": 2, "name": "Elsewhere", "external": true}
+	]`
+	if err := json.Unmarshal([]byte(raw), &resources); err != nil {
+		t.Fatalf("failed to decode fixture: %v", err)
+	}
+	results := spigetSearchResults(resources)
+	if len(results) != 1 {
+		t.Fatalf("expected only the free resource, got %+v", results)
+	}
+	got := results[0]
+	if got.ProjectID != "28140" || got.IconURL != "https://www.spigotmc.org/data/resource_icons/28/28140.jpg" || got.Description != "A permissions plugin" {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+	if id, ok := parseSpigotResourceIDFromURL(got.PageURL); !ok || id != 28140 {
+		t.Fatalf("expected the page URL to parse back to the resource, got %d %v", id, ok)
+	}
+}
+
+func TestResolveCatalogInstallSourceSpiget(t *testing.T) {
+	source, err := resolveCatalogInstallSource(context.Background(), "Spiget", "28140", "", "1.21.4", "Paper")
+	if err != nil {
+		t.Fatalf("resolveCatalogInstallSource failed: %v", err)
+	}
+	if source.downloadURL != "https://api.spiget.org/v2/resources/28140/download" || source.sourceURL != "https://www.spigotmc.org/resources/28140/" {
+		t.Fatalf("unexpected source: %+v", source)
+	}
+	for _, c := range []struct{ source, project, version, serverType string }{
+		{"spiget", "28140", "", "Fabric"},
+		{"spiget", "28140", "5", "Paper"},
+		{"spiget", "abc", "", "Paper"},
+		{"curseforge", "1", "", "Paper"},
+		{"modrinth", "", "", "Paper"},
+	} {
+		if _, err := resolveCatalogInstallSource(context.Background(), c.source, c.project, c.version, "1.21.4", c.serverType); err == nil {
+			t.Fatalf("expected %+v to be refused", c)
+		}
+	}
+	if project, ok := parseModrinthProjectFromURL(modrinthProjectPage("P7dR8mSH")); !ok || project != "P7dR8mSH" {
+		t.Fatalf("expected the Modrinth page to parse back to the project, got %q %v", project, ok)
+	}
+}
@@ -0,0 +1,181 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StagedPluginUpdate is a downloaded and validated plugin/mod update waiting
+// for the server to stop before it replaces the live jar.
+type StagedPluginUpdate struct {
+	FileName       string `json:"fileName"`
+	TargetFileName string `json:"targetFileName"`
+	Version        string `json:"version"`
+	DownloadURL    string `json:"downloadUrl,omitempty"`
+	StagedAt       string `json:"stagedAt"`
+}
+
+// stagedPluginUpdatesMu keeps staging, discarding and applying from racing
+// each other, e.g. a restart applying while a new update is staged.
+var stagedPluginUpdatesMu sync.Mutex
+
+func (m *Manager) stagedPluginUpdatesDir(serverID string) string {
+	return filepath.Join(m.baseDir, "data", "plugin-staging", sanitizeName(serverID))
+}
+
+func (m *Manager) stagedPluginUpdatePaths(serverID, fileName string) (jarPath, metaPath string, err error) {
+	key := normalizeExtensionSourceKey(fileName)
+	dir := m.stagedPluginUpdatesDir(serverID)
+	if jarPath, err = SafePath(dir, key+".staged"); err != nil {
+		return "", "", err
+	}
+	if metaPath, err = SafePath(dir, key+".json"); err != nil {
+		return "", "", err
+	}
+	return jarPath, metaPath, nil
+}
+
+// stagePluginUpdate parks a prepared update until the server next stops.
+// Staging again for the same plugin replaces the earlier download.
+func (m *Manager) stagePluginUpdate(cfg *ServerConfig, fileName string, update *preparedPluginUpdate) (*PluginInfo, error) {
+	stagedPluginUpdatesMu.Lock()
+	defer stagedPluginUpdatesMu.Unlock()
+
+	jarPath, metaPath, err := m.stagedPluginUpdatePaths(cfg.ID, fileName)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(jarPath), 0755)
+	}
+	if err != nil {
+		_ = os.Remove(update.jarPath)
+		return nil, err
+	}
+	if err := moveOrCopyFile(update.jarPath, jarPath, true); err != nil {
+		_ = os.Remove(update.jarPath)
+		return nil, fmt.Errorf("failed to stage update: %w", err)
+	}
+	staged := &StagedPluginUpdate{
+		FileName:       filepath.Base(fileName),
+		TargetFileName: update.targetFileName,
+		Version:        update.version,
+		DownloadURL:    update.installedFrom,
+		StagedAt:       time.Now().UTC().Format(time.RFC3339),
+	}
+	data, err := json.MarshalIndent(staged, "", "  ")
+	if err != nil {
+		_ = os.Remove(jarPath)
+		return nil, err
+	}
+	if err := os.WriteFile(metaPath, data, 0644); err != nil {
+		_ = os.Remove(jarPath)
+		return nil, fmt.Errorf("failed to stage update: %w", err)
+	}
+	log.Printf("[%s] Staged update for %s (%s) until next restart", cfg.Name, fileName, update.version)
+
+	currentPath := filepath.Join(extensionsDir(cfg), filepath.Base(fileName))
+	baseName, disabled := splitDisabledSuffix(filepath.Base(fileName))
+	info := &PluginInfo{
+		FileName:      filepath.Base(fileName),
+		Directory:     defaultExtensionDirName(cfg),
+		Enabled:       !disabled,
+		PendingUpdate: staged,
+	}
+	info.Name, info.Version = extractPluginVersion(currentPath)
+	if stat, err := os.Stat(currentPath); err == nil {
+		info.Size = formatFileSize(stat.Size())
+	}
+	if info.Name == "" {
+		info.Name = strings.TrimSuffix(baseName, ".jar")
+	}
+	return info, nil
+}
+
+// listStagedPluginUpdates returns a server's staged updates keyed like the
+// extension manifest.
+func (m *Manager) listStagedPluginUpdates(serverID string) map[string]*StagedPluginUpdate {
+	staged := make(map[string]*StagedPluginUpdate)
+	entries, err := os.ReadDir(m.stagedPluginUpdatesDir(serverID))
+	if err != nil {
+		return staged
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(m.stagedPluginUpdatesDir(serverID), entry.Name()))
+		if err != nil {
+			continue
+		}
+		var update StagedPluginUpdate
+		if err := json.Unmarshal(data, &update); err != nil || update.FileName == "" {
+			continue
+		}
+		staged[normalizeExtensionSourceKey(update.FileName)] = &update
+	}
+	return staged
+}
+
+// discardStagedPluginUpdate drops any staged update for fileName.
+func (m *Manager) discardStagedPluginUpdate(cfg *ServerConfig, fileName string) {
+	stagedPluginUpdatesMu.Lock()
+	defer stagedPluginUpdatesMu.Unlock()
+	jarPath, metaPath, err := m.stagedPluginUpdatePaths(cfg.ID, fileName)
+	if err != nil {
+		return
+	}
+	_ = os.Remove(jarPath)
+	_ = os.Remove(metaPath)
+}
+
+// applyStagedPluginUpdates swaps staged jars into place. It does nothing
+// while the server is running or while safe mode has the plugins directory
+// moved aside; the updates then wait for the next stop.
+func (m *Manager) applyStagedPluginUpdates(cfg *ServerConfig) {
+	stagedPluginUpdatesMu.Lock()
+	defer stagedPluginUpdatesMu.Unlock()
+
+	staged := m.listStagedPluginUpdates(cfg.ID)
+	if len(staged) == 0 {
+		return
+	}
+	if status, _ := m.GetStatus(cfg.ID); status != nil && (status.Status == "Running" || status.Status == "Booting" || status.Status == "Suspended") {
+		return
+	}
+	if info, err := os.Stat(extensionsDir(cfg)); err != nil || !info.IsDir() {
+		return
+	}
+
+	for _, update := range staged {
+		jarPath, metaPath, err := m.stagedPluginUpdatePaths(cfg.ID, update.FileName)
+		if err != nil {
+			continue
+		}
+		// The jar may have been enabled or disabled since the update was
+		// staged; the update follows its current state.
+		fileName := filepath.Base(update.FileName)
+		if _, err := os.Stat(filepath.Join(extensionsDir(cfg), fileName)); os.IsNotExist(err) {
+			_, disabled := splitDisabledSuffix(fileName)
+			fileName = withDisabledSuffix(fileName, !disabled)
+		}
+		_, disabled := splitDisabledSuffix(fileName)
+		if _, err := os.Stat(filepath.Join(extensionsDir(cfg), fileName)); err != nil {
+			log.Printf("[%s] Dropping staged update for %s: installed jar no longer exists", cfg.Name, update.FileName)
+		} else if _, err := m.installPluginUpdate(cfg, fileName, &preparedPluginUpdate{
+			jarPath:        jarPath,
+			targetFileName: withDisabledSuffix(update.TargetFileName, disabled),
+			version:        update.Version,
+			installedFrom:  update.DownloadURL,
+		}); err != nil {
+			log.Printf("[%s] Failed to apply staged update for %s: %v", cfg.Name, update.FileName, err)
+		} else {
+			log.Printf("[%s] Applied staged update for %s (%s)", cfg.Name, update.FileName, update.Version)
+		}
+		_ = os.Remove(jarPath)
+		_ = os.Remove(metaPath)
+	}
+}
@@ -0,0 +1,116 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStagedPluginUpdateAppliesWhenStopped(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv1", Name: "Lobby", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Lobby")}
+	pluginsDir := filepath.Join(cfg.Dir, "plugins")
+	if err := os.MkdirAll(pluginsDir, 0755); err != nil {
+		t.Fatalf("failed to create plugins dir: %v", err)
+	}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.mu.Unlock()
+
+	writeTestJar(t, filepath.Join(pluginsDir, "Foo-1.0.jar"), map[string]string{"plugin.yml": "name: Foo\nversion: 1.0.0\n"})
+	download := filepath.Join(t.TempDir(), "download.jar")
+	writeTestJar(t, download, map[string]string{"plugin.yml": "name: Foo\nversion: 2.0.0\n"})
+
+	info, err := mgr.stagePluginUpdate(cfg, "Foo-1.0.jar", &preparedPluginUpdate{
+		jarPath:        download,
+		targetFileName: "Foo-2.0.jar",
+		name:           "Foo",
+		version:        "2.0.0",
+		installedFrom:  "https://cdn.modrinth.com/data/foo/Foo-2.0.jar",
+	})
+	if err != nil {
+		t.Fatalf("stage failed: %v", err)
+	}
+	if info.Version != "1.0.0" || info.PendingUpdate == nil || info.PendingUpdate.Version != "2.0.0" {
+		t.Fatalf("unexpected staged plugin info: %+v", info)
+	}
+
+	plugins, err := mgr.ListPlugins(cfg.ID)
+	if err != nil || len(plugins) != 1 || plugins[0].PendingUpdate == nil {
+		t.Fatalf("expected listing to report the pending update, got %+v (%v)", plugins, err)
+	}
+
+	mgr.applyStagedPluginUpdates(cfg)
+	if _, err := os.Stat(filepath.Join(pluginsDir, "Foo-1.0.jar")); !os.IsNotExist(err) {
+		t.Fatalf("expected old jar to be replaced, stat err=%v", err)
+	}
+	if _, version := extractPluginVersion(filepath.Join(pluginsDir, "Foo-2.0.jar")); version != "2.0.0" {
+		t.Fatalf("expected staged jar to be installed, got version %q", version)
+	}
+	if staged := mgr.listStagedPluginUpdates(cfg.ID); len(staged) != 0 {
+		t.Fatalf("expected staging area to be empty, got %+v", staged)
+	}
+	if p := provenanceForFile(mgr.loadExtensionManifest(cfg), "Foo-2.0.jar"); p == nil || p.Origin != ExtensionOriginUpdate {
+		t.Fatalf("expected update provenance for the applied jar, got %+v", p)
+	}
+}
+
+func TestPluginUpdateKeepsDisabledJarDisabled(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv1", Name: "Lobby", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Lobby")}
+	pluginsDir := filepath.Join(cfg.Dir, "plugins")
+	if err := os.MkdirAll(pluginsDir, 0755); err != nil {
+		t.Fatalf("failed to create plugins dir: %v", err)
+	}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.mu.Unlock()
+
+	writeTestJar(t, filepath.Join(pluginsDir, "Foo-1.0.jar"), map[string]string{"plugin.yml": "name: Foo\nversion: 1.0.0\n"})
+	download := filepath.Join(t.TempDir(), "download.jar")
+	writeTestJar(t, download, map[string]string{"plugin.yml": "name: Foo\nversion: 2.0.0\n"})
+
+	if _, err := mgr.stagePluginUpdate(cfg, "Foo-1.0.jar", &preparedPluginUpdate{
+		jarPath:        download,
+		targetFileName: "Foo-2.0.jar",
+		name:           "Foo",
+		version:        "2.0.0",
+	}); err != nil {
+		t.Fatalf("stage failed: %v", err)
+	}
+
+	// Disabled after staging: the update still applies and stays disabled.
+	toggled, err := mgr.TogglePlugin(cfg.ID, "", "Foo-1.0.jar")
+	if err != nil {
+		t.Fatalf("TogglePlugin failed: %v", err)
+	}
+	if toggled.Enabled || toggled.Name != "Foo" || toggled.Version != "1.0.0" {
+		t.Fatalf("expected the disabled jar's metadata, got %+v", toggled)
+	}
+	plugins, err := mgr.ListPlugins(cfg.ID)
+	if err != nil || len(plugins) != 1 || plugins[0].PendingUpdate == nil || plugins[0].Version != "1.0.0" {
+		t.Fatalf("expected the disabled jar to keep its version and pending update, got %+v (%v)", plugins, err)
+	}
+
+	mgr.applyStagedPluginUpdates(cfg)
+	if _, version := extractPluginVersion(filepath.Join(pluginsDir, "Foo-2.0.jar.disabled")); version != "2.0.0" {
+		t.Fatalf("expected the update to be installed disabled, got version %q", version)
+	}
+	if _, err := os.Stat(filepath.Join(pluginsDir, "Foo-2.0.jar")); !os.IsNotExist(err) {
+		t.Fatalf("expected no enabled copy of the update, stat err=%v", err)
+	}
+	plugins, err = mgr.ListPlugins(cfg.ID)
+	if err != nil || len(plugins) != 1 || plugins[0].Enabled || plugins[0].Version != "2.0.0" {
+		t.Fatalf("expected one disabled jar at 2.0.0, got %+v (%v)", plugins, err)
+	}
+}
@@ -0,0 +1,46 @@
+package minecraft
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestChooseBestSpigetVersionHonorsChannel(t *testing.T) {
+	versions := spigetVersionResult{
+		{ID: 3, Name: "2.0.0-beta.2"},
+		{ID: 2, Name: "1.9.0"},
+	}
+	best, ok := chooseBestSpigetVersion(versions, "1.21.1", false)
+	if !ok || best[0].Name != "1.9.0" {
+		t.Fatalf("expected stable channel to skip the beta, got %+v", best)
+	}
+	best, ok = chooseBestSpigetVersion(versions, "1.21.1", true)
+	if !ok || best[0].Name != "2.0.0-beta.2" {
+		t.Fatalf("expected prerelease channel to pick the beta, got %+v", best)
+	}
+}
+
+func TestSetPluginUpdateChannel(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv1", Name: "Test", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Test")}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.mu.Unlock()
+
+	info, err := mgr.SetPluginUpdateChannel(cfg.ID, "Prerelease")
+	if err != nil || info.PluginUpdateChannel != PluginUpdateChannelPrerelease || cfg.PluginUpdateChannel != PluginUpdateChannelPrerelease {
+		t.Fatalf("expected prerelease channel, got %+v (%v)", info, err)
+	}
+	info, err = mgr.SetPluginUpdateChannel(cfg.ID, "stable")
+	if err != nil || info.PluginUpdateChannel != PluginUpdateChannelStable || cfg.PluginUpdateChannel != "" {
+		t.Fatalf("expected stable channel to reset the override, got %+v (%v)", info, err)
+	}
+	if _, err := mgr.SetPluginUpdateChannel(cfg.ID, "nightly"); err == nil {
+		t.Fatalf("expected unknown channel to be rejected")
+	}
+}
@@ -0,0 +1,137 @@
+package minecraft
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PluginUpdateOverview is the panel-wide result of checking every server's
+// plugins/mods for updates.
+type PluginUpdateOverview struct {
+	CheckedAt string                        `json:"checkedAt"`
+	Projects  []PluginUpdateOverviewProject `json:"projects"`
+	Failed    []PluginUpdateOverviewFailure `json:"failed"`
+}
+
+// PluginUpdateOverviewProject groups the installs of one project across
+// servers. Installs that could not be matched to a project are grouped by
+// plugin name.
+type PluginUpdateOverviewProject struct {
+	Key           string                        `json:"key"`
+	Name          string                        `json:"name"`
+	IconURL       string                        `json:"iconUrl,omitempty"`
+	ProjectURL    string                        `json:"projectUrl,omitempty"`
+	LatestVersion string                        `json:"latestVersion,omitempty"`
+	OutdatedCount int                           `json:"outdatedCount"`
+	Installs      []PluginUpdateOverviewInstall `json:"installs"`
+}
+
+// PluginUpdateOverviewInstall is one server's copy of a project.
+type PluginUpdateOverviewInstall struct {
+	ServerID      string `json:"serverId"`
+	ServerName    string `json:"serverName"`
+	FileName      string `json:"fileName"`
+	Version       string `json:"version"`
+	LatestVersion string `json:"latestVersion,omitempty"`
+	VersionStatus string `json:"versionStatus"`
+	UpdateURL     string `json:"updateUrl,omitempty"`
+}
+
+// PluginUpdateOverviewFailure records a server whose check failed.
+type PluginUpdateOverviewFailure struct {
+	ServerID   string `json:"serverId"`
+	ServerName string `json:"serverName"`
+	Error      string `json:"error"`
+}
+
+type serverUpdateCheck struct {
+	serverID   string
+	serverName string
+	results    []PluginUpdateInfo
+}
+
+// CheckAllPluginUpdates runs the per-server update check on every server and
+// merges the results by project. Servers are checked one after another; the
+// per-server worker pool and API pacing already bound the request rate.
+func (m *Manager) CheckAllPluginUpdates() *PluginUpdateOverview {
+	overview := &PluginUpdateOverview{Failed: make([]PluginUpdateOverviewFailure, 0)}
+	checks := make([]serverUpdateCheck, 0)
+	for _, server := range m.ListServers() {
+		if strings.EqualFold(server.Type, "Vanilla") {
+			continue
+		}
+		results, err := m.CheckPluginUpdates(server.ID)
+		if err != nil {
+			log.Printf("Plugin update overview: skipping %s: %v", server.Name, err)
+			overview.Failed = append(overview.Failed, PluginUpdateOverviewFailure{
+				ServerID:   server.ID,
+				ServerName: server.Name,
+				Error:      err.Error(),
+			})
+			continue
+		}
+		checks = append(checks, serverUpdateCheck{serverID: server.ID, serverName: server.Name, results: results})
+	}
+	overview.Projects = buildPluginUpdateOverview(checks)
+	overview.CheckedAt = time.Now().UTC().Format(time.RFC3339)
+	return overview
+}
+
+// buildPluginUpdateOverview groups check results by project, most outdated
+// installs first.
+func buildPluginUpdateOverview(checks []serverUpdateCheck) []PluginUpdateOverviewProject {
+	byKey := make(map[string]*PluginUpdateOverviewProject)
+	order := make([]string, 0)
+	for _, check := range checks {
+		for _, result := range check.results {
+			key := "name:" + normalizeProjectName(result.Name)
+			if result.project.valid() {
+				key = result.project.key()
+			}
+			project, ok := byKey[key]
+			if !ok {
+				project = &PluginUpdateOverviewProject{Key: key, Name: result.Name}
+				if result.project.valid() {
+					if metadata, _ := cachedPluginProject(result.project); metadata != nil {
+						project.IconURL = metadata.IconURL
+						project.ProjectURL = metadata.ProjectURL
+						if metadata.Title != "" {
+							project.Name = metadata.Title
+						}
+					}
+				}
+				byKey[key] = project
+				order = append(order, key)
+			}
+			project.Installs = append(project.Installs, PluginUpdateOverviewInstall{
+				ServerID:      check.serverID,
+				ServerName:    check.serverName,
+				FileName:      result.FileName,
+				Version:       result.Version,
+				LatestVersion: result.LatestVersion,
+				VersionStatus: result.VersionStatus,
+				UpdateURL:     result.UpdateURL,
+			})
+			if result.LatestVersion != "" && (project.LatestVersion == "" || result.VersionStatus == "outdated") {
+				project.LatestVersion = result.LatestVersion
+			}
+			if result.VersionStatus == "outdated" {
+				project.OutdatedCount++
+			}
+		}
+	}
+
+	projects := make([]PluginUpdateOverviewProject, 0, len(order))
+	for _, key := range order {
+		projects = append(projects, *byKey[key])
+	}
+	sort.SliceStable(projects, func(i, j int) bool {
+		if projects[i].OutdatedCount != projects[j].OutdatedCount {
+			return projects[i].OutdatedCount > projects[j].OutdatedCount
+		}
+		return strings.ToLower(projects[i].Name) < strings.ToLower(projects[j].Name)
+	})
+	return projects
+}
@@ -0,0 +1,29 @@
+package minecraft
+
+import "testing"
+
+func TestBuildPluginUpdateOverviewGroupsByProject(t *testing.T) {
+	essentials := pluginProjectRef{source: pluginProjectSpigot, id: "9089"}
+	checks := []serverUpdateCheck{
+		{serverID: "a", serverName: "Lobby", results: []PluginUpdateInfo{
+			{Name: "Essentials", FileName: "EssentialsX-2.20.0.jar", Version: "2.20.0", LatestVersion: "2.21.0", VersionStatus: "outdated", project: essentials},
+			{Name: "LocalThing", FileName: "LocalThing.jar", Version: "1.0.0", VersionStatus: "unknown"},
+		}},
+		{serverID: "b", serverName: "Survival", results: []PluginUpdateInfo{
+			{Name: "EssentialsX", FileName: "EssentialsX.jar", Version: "2.21.0", LatestVersion: "2.21.0", VersionStatus: "latest", project: essentials},
+			{Name: "Local Thing", FileName: "local-thing.jar", Version: "1.0.0", VersionStatus: "unknown"},
+		}},
+	}
+
+	projects := buildPluginUpdateOverview(checks)
+	if len(projects) != 2 {
+		t.Fatalf("expected 2 projects, got %+v", projects)
+	}
+	first := projects[0]
+	if first.Key != essentials.key() || first.OutdatedCount != 1 || len(first.Installs) != 2 || first.LatestVersion != "2.21.0" {
+		t.Fatalf("unexpected project grouping: %+v", first)
+	}
+	if projects[1].Key != "name:localthing" || len(projects[1].Installs) != 2 {
+		t.Fatalf("expected unmatched plugins to group by name, got %+v", projects[1])
+	}
+}
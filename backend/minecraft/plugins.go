@@ -19,6 +19,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"gopkg.in/yaml.v3"
 )
@@ -298,6 +299,16 @@ type PluginUpdateInfo struct {
 	VersionStatus string `json:"versionStatus"` // latest, outdated, incompatible, unknown
 	UpdateURL     string `json:"updateUrl,omitempty"`
 	SourceURL     string `json:"sourceUrl,omitempty"`
+
+	project pluginProjectRef // project the check resolved the plugin to
+}
+
+// withProject tags a check result with the project it was resolved from.
+func withProject(info *PluginUpdateInfo, ref pluginProjectRef) *PluginUpdateInfo {
+	if info != nil {
+		info.project = ref
+	}
+	return info
 }
 
 func debugPluginUpdatesEnabled() bool {
@@ -320,6 +331,47 @@ type pluginUpdateCacheEntry struct {
 
 const pluginCacheTTL = 15 * time.Minute
 
+// Plugin update channels a server can follow. Stable is the default and
+// skips anything that looks like a beta, RC or snapshot.
+const (
+	PluginUpdateChannelStable     = "stable"
+	PluginUpdateChannelPrerelease = "prerelease"
+)
+
+func normalizedPluginUpdateChannel(channel string) string {
+	if strings.EqualFold(strings.TrimSpace(channel), PluginUpdateChannelPrerelease) {
+		return PluginUpdateChannelPrerelease
+	}
+	return PluginUpdateChannelStable
+}
+
+// SetPluginUpdateChannel chooses whether update checks for a server's
+// plugins/mods consider prereleases.
+func (m *Manager) SetPluginUpdateChannel(id, channel string) (*ServerInfo, error) {
+	channel = strings.ToLower(strings.TrimSpace(channel))
+	if channel != PluginUpdateChannelStable && channel != PluginUpdateChannelPrerelease {
+		return nil, fmt.Errorf("update channel must be %q or %q", PluginUpdateChannelStable, PluginUpdateChannelPrerelease)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if channel == PluginUpdateChannelStable {
+		channel = ""
+	}
+	cfg.PluginUpdateChannel = channel
+	if err := m.persist(); err != nil {
+		return nil, err
+	}
+
+	return m.serverInfo(id), nil
+}
+
 // CheckPluginUpdates checks all plugins for a server against Modrinth/Spiget APIs
 func (m *Manager) CheckPluginUpdates(id string) ([]PluginUpdateInfo, error) {
 	m.mu.RLock()
@@ -329,58 +381,96 @@ func (m *Manager) CheckPluginUpdates(id string) ([]PluginUpdateInfo, error) {
 		return nil, err
 	}
 
-	plugins, err := m.ListPlugins(id)
+	listed, err := m.ListPlugins(id)
 	if err != nil {
 		return nil, err
 	}
+	// Updates install into the default directory, so only its jars are
+	// checked; hybrid plugins/ and datapacks are listed but not tracked.
+	plugins := make([]PluginInfo, 0, len(listed))
+	for _, p := range listed {
+		if p.Directory == defaultExtensionDirName(cfg) {
+			plugins = append(plugins, p)
+		}
+	}
 
 	mcVersion := cfg.Version
 	serverType := cfg.Type
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	prerelease := normalizedPluginUpdateChannel(cfg.PluginUpdateChannel) == PluginUpdateChannelPrerelease
+	// Checks share a bounded pool of workers and the per-host API pacing, so
+	// allow more time on servers with many plugins.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second+time.Duration(len(plugins))*time.Second)
 	defer cancel()
 
+	checkOne := func(p PluginInfo) PluginUpdateInfo {
+		// Keyed without .disabled so enabling or disabling a jar keeps
+		// its cached result.
+		cacheKey := fmt.Sprintf(
+			"%s:%s:%s:%s:%s:%s:%t",
+			id,
+			normalizeExtensionSourceKey(p.FileName),
+			p.Version,
+			strings.ToLower(strings.TrimSpace(p.SourceURL)),
+			strings.ToLower(strings.TrimSpace(serverType)),
+			strings.TrimSpace(mcVersion),
+			prerelease,
+		)
+		pluginUpdateCache.mu.RLock()
+		cached, ok := pluginUpdateCache.entries[cacheKey]
+		pluginUpdateCache.mu.RUnlock()
+		if ok && time.Since(cached.fetchedAt) < pluginCacheTTL {
+			result := *cached.result
+			result.FileName = p.FileName
+			return result
+		}
+
+		info := checkSinglePlugin(ctx, p, mcVersion, serverType, prerelease)
+		if info.project.valid() {
+			rememberPluginProject(id, p.FileName, info.project)
+			resolvePluginProject(ctx, info.project)
+		}
+		if ctx.Err() != nil {
+			// Timed out part way; don't cache a result that may be incomplete.
+			return info
+		}
+
+		pluginUpdateCache.mu.Lock()
+		pluginUpdateCache.entries[cacheKey] = pluginUpdateCacheEntry{
+			result:    &info,
+			fetchedAt: time.Now(),
+		}
+		pluginUpdateCache.mu.Unlock()
+		return info
+	}
+
 	results := make([]PluginUpdateInfo, len(plugins))
+	workers := pluginUpdateCheckWorkersFromEnv()
+	if workers > len(plugins) {
+		workers = len(plugins)
+	}
+	queue := make(chan int)
 	var wg sync.WaitGroup
-
-	for i, plugin := range plugins {
+	for w := 0; w < workers; w++ {
 		wg.Add(1)
-		go func(idx int, p PluginInfo) {
+		go func() {
 			defer wg.Done()
-
-			cacheKey := fmt.Sprintf(
-				"%s:%s:%s:%s:%s:%s",
-				id,
-				p.FileName,
-				p.Version,
-				strings.ToLower(strings.TrimSpace(p.SourceURL)),
-				strings.ToLower(strings.TrimSpace(serverType)),
-				strings.TrimSpace(mcVersion),
-			)
-			pluginUpdateCache.mu.RLock()
-			cached, ok := pluginUpdateCache.entries[cacheKey]
-			pluginUpdateCache.mu.RUnlock()
-			if ok && time.Since(cached.fetchedAt) < pluginCacheTTL {
-				results[idx] = *cached.result
-				return
+			for idx := range queue {
+				results[idx] = checkOne(plugins[idx])
 			}
-
-			info := checkSinglePlugin(ctx, p, mcVersion, serverType)
-			results[idx] = info
-
-			pluginUpdateCache.mu.Lock()
-			pluginUpdateCache.entries[cacheKey] = pluginUpdateCacheEntry{
-				result:    &info,
-				fetchedAt: time.Now(),
-			}
-			pluginUpdateCache.mu.Unlock()
-		}(i, plugin)
+		}()
 	}
+	for i := range plugins {
+		queue <- i
+	}
+	close(queue)
 	wg.Wait()
 
 	return results, nil
 }
 
-func checkSinglePlugin(ctx context.Context, plugin PluginInfo, mcVersion, serverType string) PluginUpdateInfo {
+// checkSinglePlugin resolves one plugin's update status. prerelease widens
+// every source to betas, RCs and snapshots instead of stable releases only.
+func checkSinglePlugin(ctx context.Context, plugin PluginInfo, mcVersion, serverType string, prerelease bool) PluginUpdateInfo {
 	info := PluginUpdateInfo{
 		Name:          plugin.Name,
 		FileName:      plugin.FileName,
@@ -394,7 +484,7 @@ func checkSinglePlugin(ctx context.Context, plugin PluginInfo, mcVersion, server
 	}
 
 	if strings.TrimSpace(plugin.SourceURL) != "" {
-		if result, handled := checkBySourceURL(ctx, plugin.SourceURL, plugin.Name, plugin.Version, mcVersion, serverType); handled {
+		if result, handled := checkBySourceURL(ctx, plugin.SourceURL, plugin.Name, plugin.Version, mcVersion, serverType, prerelease); handled {
 			if result != nil {
 				result.FileName = plugin.FileName
 				result.SourceURL = plugin.SourceURL
@@ -406,7 +496,7 @@ func checkSinglePlugin(ctx context.Context, plugin PluginInfo, mcVersion, server
 
 	if isModdedType(serverType) {
 		// Modded servers: prioritize Modrinth.
-		if result := checkModrinth(ctx, plugin.Name, plugin.Version, mcVersion, serverType); result != nil {
+		if result := checkModrinth(ctx, plugin.Name, plugin.Version, mcVersion, serverType, prerelease); result != nil {
 			result.FileName = plugin.FileName
 			return *result
 		}
@@ -414,13 +504,13 @@ func checkSinglePlugin(ctx context.Context, plugin PluginInfo, mcVersion, server
 	}
 
 	// Plugin/proxy servers: check Spiget first, then Modrinth if no update is found there.
-	spigetResult := checkSpiget(ctx, plugin.Name, plugin.Version, mcVersion)
+	spigetResult := checkSpiget(ctx, plugin.Name, plugin.Version, mcVersion, prerelease)
 	if spigetResult != nil && spigetResult.VersionStatus == "outdated" {
 		spigetResult.FileName = plugin.FileName
 		return *spigetResult
 	}
 
-	modrinthResult := checkModrinth(ctx, plugin.Name, plugin.Version, mcVersion, serverType)
+	modrinthResult := checkModrinth(ctx, plugin.Name, plugin.Version, mcVersion, serverType, prerelease)
 	if modrinthResult != nil && modrinthResult.VersionStatus == "outdated" {
 		modrinthResult.FileName = plugin.FileName
 		return *modrinthResult
@@ -438,7 +528,7 @@ func checkSinglePlugin(ctx context.Context, plugin PluginInfo, mcVersion, server
 	return info
 }
 
-func checkBySourceURL(ctx context.Context, sourceURL, pluginName, currentVersion, mcVersion, serverType string) (*PluginUpdateInfo, bool) {
+func checkBySourceURL(ctx context.Context, sourceURL, pluginName, currentVersion, mcVersion, serverType string, prerelease bool) (*PluginUpdateInfo, bool) {
 	sourceURL = strings.TrimSpace(sourceURL)
 	if sourceURL == "" {
 		return nil, false
@@ -448,19 +538,87 @@ func checkBySourceURL(ctx context.Context, sourceURL, pluginName, currentVersion
 		if debugPluginUpdatesEnabled() {
 			log.Printf("[UpdateDebug] source=spigot plugin=%q current=%q mc=%q resourceID=%d", pluginName, currentVersion, mcVersion, resourceID)
 		}
-		return checkSpigetByID(ctx, resourceID, pluginName, currentVersion, mcVersion), true
+		ref := pluginProjectRef{source: pluginProjectSpigot, id: strconv.Itoa(resourceID)}
+		return withProject(checkSpigetByID(ctx, resourceID, pluginName, currentVersion, mcVersion, prerelease), ref), true
 	}
 	if projectID, ok := parseModrinthProjectFromURL(sourceURL); ok {
-		return checkModrinthByProject(ctx, projectID, pluginName, currentVersion, mcVersion, serverType), true
+		ref := pluginProjectRef{source: pluginProjectModrinth, id: projectID}
+		return withProject(checkModrinthByProject(ctx, projectID, pluginName, currentVersion, mcVersion, serverType, prerelease), ref), true
+	}
+	if jobURL, ok := parseJenkinsJobURL(sourceURL); ok {
+		return checkJenkinsJob(ctx, jobURL, pluginName, currentVersion), true
 	}
-	if _, ok := parseCurseForgeProjectFromURL(sourceURL); ok {
-		// CurseForge update checks are not available without external API credentials.
-		// Treat as handled so we do not fall back to fuzzy name matching.
-		return nil, true
+	if slug, ok := parseCurseForgeProjectFromURL(sourceURL); ok {
+		// Without an API key CurseForge cannot be checked. Treat it as
+		// handled anyway so we do not fall back to fuzzy name matching.
+		client, err := newCurseForgeClient()
+		if err != nil {
+			return nil, true
+		}
+		return checkCurseForgeByProject(ctx, client, slug, pluginName, currentVersion, mcVersion, serverType, prerelease), true
 	}
 	return nil, false
 }
 
+// checkCurseForgeByProject checks a CurseForge mod the same way
+// checkModrinthByProject checks a Modrinth project: the newest file for the
+// server's loader and Minecraft version is the candidate update.
+func checkCurseForgeByProject(ctx context.Context, client *curseForgeClient, slug, pluginName, currentVersion, mcVersion, serverType string, prerelease bool) *PluginUpdateInfo {
+	mod, err := client.modBySlug(ctx, slug)
+	if err != nil {
+		if debugPluginUpdatesEnabled() {
+			log.Printf("[UpdateDebug] source=curseforge plugin=%q slug=%q err=%v", pluginName, slug, err)
+		}
+		return nil
+	}
+	files, err := client.modFiles(ctx, mod.ID, serverType)
+	if err != nil || len(files) == 0 {
+		return nil
+	}
+
+	var latestCompatible, latestAny *curseForgeFile
+	for i := range files {
+		f := &files[i]
+		if !prerelease && f.ReleaseType != curseForgeReleaseStable {
+			continue
+		}
+		if latestAny == nil {
+			latestAny = f
+		}
+		if latestCompatible == nil && f.supportsGameVersion(mcVersion) {
+			latestCompatible = f
+		}
+	}
+
+	info := &PluginUpdateInfo{
+		Name:    pluginName,
+		Version: currentVersion,
+	}
+	switch {
+	case latestCompatible != nil:
+		info.LatestVersion = latestCompatible.versionLabel()
+		cmp, confident := compareLatestToCurrent(currentVersion, info.LatestVersion)
+		if !confident {
+			cmp, confident = compareLatestToCurrent(currentVersion, latestCompatible.FileName)
+		}
+		switch {
+		case !confident || cmp < 0:
+			info.VersionStatus = "unknown"
+		case cmp == 0:
+			info.VersionStatus = "latest"
+		case strings.HasSuffix(strings.ToLower(latestCompatible.FileName), ".jar"):
+			info.VersionStatus = "outdated"
+			info.UpdateURL = latestCompatible.downloadURLs()[0]
+		default:
+			info.VersionStatus = "unknown"
+		}
+	case latestAny != nil:
+		info.LatestVersion = latestAny.versionLabel()
+		info.VersionStatus = "incompatible"
+	}
+	return info
+}
+
 // Modrinth API types
 type modrinthSearchResult struct {
 	Hits []struct {
@@ -839,7 +997,7 @@ func compareLatestToCurrent(current, latest string) (int, bool) {
 	return 0, false
 }
 
-func chooseBestSpigetVersion(versions spigetVersionResult, mcVersion string) (spigetVersionResult, bool) {
+func chooseBestSpigetVersion(versions spigetVersionResult, mcVersion string, prerelease bool) (spigetVersionResult, bool) {
 	if len(versions) == 0 {
 		return nil, false
 	}
@@ -847,7 +1005,7 @@ func chooseBestSpigetVersion(versions spigetVersionResult, mcVersion string) (sp
 	serverMinor := normalizeMcMinor(mcVersion)
 
 	for i, v := range versions {
-		if isLikelyUnstableVersionName(v.Name) {
+		if !prerelease && isLikelyUnstableVersionName(v.Name) {
 			continue
 		}
 		if serverMinor != "" && len(v.TestedVersions) > 0 {
@@ -908,7 +1066,7 @@ func isStableModrinthVersion(v *modrinthVersion) bool {
 	return !isLikelyUnstableVersionName(v.VersionNumber)
 }
 
-func checkModrinth(ctx context.Context, pluginName, currentVersion, mcVersion, serverType string) *PluginUpdateInfo {
+func checkModrinth(ctx context.Context, pluginName, currentVersion, mcVersion, serverType string, prerelease bool) *PluginUpdateInfo {
 	// Search for the plugin on Modrinth
 	searchURL := fmt.Sprintf("https://api.modrinth.com/v2/search?query=%s&limit=5", url.QueryEscape(pluginName))
 
@@ -943,10 +1101,11 @@ func checkModrinth(ctx context.Context, pluginName, currentVersion, mcVersion, s
 		return nil
 	}
 
-	return checkModrinthByProject(ctx, projectID, pluginName, currentVersion, mcVersion, serverType)
+	ref := pluginProjectRef{source: pluginProjectModrinth, id: projectID}
+	return withProject(checkModrinthByProject(ctx, projectID, pluginName, currentVersion, mcVersion, serverType, prerelease), ref)
 }
 
-func checkModrinthByProject(ctx context.Context, projectID, pluginName, currentVersion, mcVersion, serverType string) *PluginUpdateInfo {
+func checkModrinthByProject(ctx context.Context, projectID, pluginName, currentVersion, mcVersion, serverType string, prerelease bool) *PluginUpdateInfo {
 	// Get versions for the project
 	versionsURL := fmt.Sprintf("https://api.modrinth.com/v2/project/%s/version", projectID)
 	var versions []modrinthVersion
@@ -964,7 +1123,7 @@ func checkModrinthByProject(ctx context.Context, projectID, pluginName, currentV
 	var latestAny *modrinthVersion
 	for i := range versions {
 		v := &versions[i]
-		if !isStableModrinthVersion(v) {
+		if !prerelease && !isStableModrinthVersion(v) {
 			continue
 		}
 		// Check if this version matches the server's loader
@@ -1058,7 +1217,7 @@ type spigetResourceResult struct {
 	} `json:"version"`
 }
 
-func checkSpiget(ctx context.Context, pluginName, currentVersion, mcVersion string) *PluginUpdateInfo {
+func checkSpiget(ctx context.Context, pluginName, currentVersion, mcVersion string, prerelease bool) *PluginUpdateInfo {
 	searchURL := fmt.Sprintf("https://api.spiget.org/v2/search/resources/%s?field=name&size=5", url.QueryEscape(pluginName))
 
 	var searchResult spigetSearchResult
@@ -1091,7 +1250,8 @@ func checkSpiget(ctx context.Context, pluginName, currentVersion, mcVersion stri
 		return nil
 	}
 
-	return checkSpigetByID(ctx, resourceID, pluginName, currentVersion, mcVersion)
+	ref := pluginProjectRef{source: pluginProjectSpigot, id: strconv.Itoa(resourceID)}
+	return withProject(checkSpigetByID(ctx, resourceID, pluginName, currentVersion, mcVersion, prerelease), ref)
 }
 
 var mcVersionHintPattern = regexp.MustCompile(`(?i)(?:\bmc)?(1\.\d{1,2}(?:\.\d+)?)`)
@@ -1124,7 +1284,7 @@ func versionHintsCompatibility(label, serverMCVersion string) (hasHints bool, co
 	return true, false
 }
 
-func checkSpigetByID(ctx context.Context, resourceID int, pluginName, currentVersion, mcVersion string) *PluginUpdateInfo {
+func checkSpigetByID(ctx context.Context, resourceID int, pluginName, currentVersion, mcVersion string, prerelease bool) *PluginUpdateInfo {
 	info := &PluginUpdateInfo{
 		Name:    pluginName,
 		Version: currentVersion,
@@ -1136,7 +1296,7 @@ func checkSpigetByID(ctx context.Context, resourceID int, pluginName, currentVer
 	var resource spigetResourceResult
 	if err := fetchJSON(ctx, resourceURL, &resource); err == nil && strings.TrimSpace(resource.Version.Name) != "" {
 		latest := strings.TrimSpace(resource.Version.Name)
-		if !isLikelyUnstableVersionName(latest) {
+		if prerelease || !isLikelyUnstableVersionName(latest) {
 			resourceLatest = latest
 		}
 	}
@@ -1198,9 +1358,9 @@ func checkSpigetByID(ctx context.Context, resourceID int, pluginName, currentVer
 	}
 
 	selected := versions[0]
-	if best, ok := chooseBestSpigetVersion(versions, mcVersion); ok && len(best) > 0 {
+	if best, ok := chooseBestSpigetVersion(versions, mcVersion, prerelease); ok && len(best) > 0 {
 		selected = best[0]
-	} else if isLikelyUnstableVersionName(selected.Name) {
+	} else if !prerelease && isLikelyUnstableVersionName(selected.Name) {
 		if debugPluginUpdatesEnabled() {
 			log.Printf("[UpdateDebug] spiget resource=%d selected unstable=%q -> unknown", resourceID, selected.Name)
 		}
@@ -1370,16 +1530,28 @@ func validateSourceURLForServerType(serverType, raw string) error {
 	if _, ok := parseModrinthProjectFromURL(raw); ok {
 		return nil
 	}
+	if _, ok := parseHangarProjectFromURL(raw); ok {
+		if isModdedType(serverType) {
+			return fmt.Errorf("modded servers require a Modrinth project link")
+		}
+		return nil
+	}
+	if jobURL, ok := parseJenkinsJobURL(raw); ok {
+		if !jenkinsJobAllowed(jobURL) {
+			return fmt.Errorf("Jenkins job links must use https and an allowed host (see ADPANEL_PLUGIN_UPDATE_ALLOWED_HOSTS)")
+		}
+		return nil
+	}
 	if _, ok := parseCurseForgeProjectFromURL(raw); ok {
 		if isModdedType(serverType) {
 			return nil
 		}
-		return fmt.Errorf("plugin servers only accept Spigot or Modrinth links")
+		return fmt.Errorf("plugin servers only accept Spigot, Modrinth, Hangar or Jenkins links")
 	}
 	if isModdedType(serverType) {
-		return fmt.Errorf("invalid source URL: expected a Modrinth or CurseForge mod link")
+		return fmt.Errorf("invalid source URL: expected a Modrinth, CurseForge or Jenkins job link")
 	}
-	return fmt.Errorf("invalid source URL: expected a Spigot resource link or Modrinth project link")
+	return fmt.Errorf("invalid source URL: expected a Spigot resource, Modrinth project, Hangar project or Jenkins job link")
 }
 
 // SetPluginSource stores or updates a source URL for a plugin/mod file.
@@ -1407,10 +1579,16 @@ func (m *Manager) SetPluginSource(id, fileName, sourceURL string) error {
 		return err
 	}
 
-	sources := m.loadExtensionSources(cfg)
 	key := normalizeExtensionSourceKey(fileName)
-	sources[key] = strings.TrimSpace(sourceURL)
-	if err := m.saveExtensionSources(cfg, sources); err != nil {
+	err = m.updateExtensionManifest(cfg, func(manifest map[string]*ExtensionProvenance) {
+		entry := manifest[key]
+		if entry == nil {
+			entry = &ExtensionProvenance{}
+			manifest[key] = entry
+		}
+		entry.SourceURL = strings.TrimSpace(sourceURL)
+	})
+	if err != nil {
 		return fmt.Errorf("failed to save source link: %w", err)
 	}
 
@@ -1430,6 +1608,64 @@ func (m *Manager) SetPluginSource(id, fileName, sourceURL string) error {
 	return nil
 }
 
+const maxExtensionNoteLength = 500
+
+// SetPluginNote attaches a note to an installed plugin, mod or datapack, e.g.
+// why it was disabled. An empty note clears it. Notes live in the extension
+// manifest so they follow the file through enable/disable renames.
+func (m *Manager) SetPluginNote(id, dir, fileName, note string) error {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxExtensionNoteLength {
+		return fmt.Errorf("note must be at most %d characters", maxExtensionNoteLength)
+	}
+
+	dirName, dirPath, err := resolveExtensionDir(cfg, dir)
+	if err != nil {
+		return err
+	}
+	if !extensionFileAllowed(dirName, fileName) {
+		return fmt.Errorf("%s is not a %s entry", fileName, dirName)
+	}
+	path, err := SafePath(dirPath, filepath.Base(fileName))
+	if err != nil {
+		return fmt.Errorf("invalid plugin path: %w", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("plugin file not found: %s", fileName)
+		}
+		return err
+	}
+
+	key := normalizeExtensionSourceKey(extensionRecordName(cfg, dirName, fileName))
+	err = m.updateExtensionManifest(cfg, func(manifest map[string]*ExtensionProvenance) {
+		entry := manifest[key]
+		if entry == nil {
+			if note == "" {
+				return
+			}
+			entry = &ExtensionProvenance{}
+			manifest[key] = entry
+		}
+		entry.Note = note
+		entry.NoteUpdatedAt = ""
+		if note != "" {
+			entry.NoteUpdatedAt = time.Now().UTC().Format(time.RFC3339)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save note: %w", err)
+	}
+	return nil
+}
+
 func resolveUpdateJarFileName(downloadURL, fallbackName, contentDisposition string) string {
 	if strings.TrimSpace(contentDisposition) != "" {
 		if _, params, err := mime.ParseMediaType(contentDisposition); err == nil {
@@ -1603,8 +1839,18 @@ func materializeDownloadJar(tmpPath string) (string, error) {
 	return "", fmt.Errorf("downloaded file is not a valid plugin/mod jar (or jar-containing archive)")
 }
 
-// UpdatePlugin downloads a new version of a plugin from a URL and replaces the old JAR
-func (m *Manager) UpdatePlugin(id, fileName, downloadURL string) (*PluginInfo, error) {
+// UpdatePlugin downloads a new version of a plugin from a URL and replaces the old JAR.
+// While the server is running the update is refused unless stage is set, in
+// which case the validated jar is held back and swapped in on the next stop.
+func (m *Manager) UpdatePlugin(id, fileName, downloadURL string, stage bool) (*PluginInfo, error) {
+	job := m.newJob(JobTypePluginUpdate, id)
+	job.start(fmt.Sprintf("Updating %s", fileName))
+	info, err := m.updatePluginJob(job, id, fileName, downloadURL, stage)
+	job.finish(err)
+	return info, err
+}
+
+func (m *Manager) updatePluginJob(job *jobHandle, id, fileName, downloadURL string, stage bool) (*PluginInfo, error) {
 	// Validate server exists and that plugin path is safe
 	m.mu.RLock()
 	cfg, err := m.serverConfigForOperationLocked(id)
@@ -1613,10 +1859,13 @@ func (m *Manager) UpdatePlugin(id, fileName, downloadURL string) (*PluginInfo, e
 		return nil, err
 	}
 
-	// Disallow updating while server is running to avoid file-locks / corruption
-	status, _ := m.GetStatus(id)
-	if status != nil && (status.Status == "Running" || status.Status == "Booting") {
-		return nil, fmt.Errorf("cannot update plugins while server is running; stop the server first")
+	// Disallow replacing jars while server is running to avoid file-locks / corruption
+	running := false
+	if status, _ := m.GetStatus(id); status != nil && (status.Status == "Running" || status.Status == "Booting" || status.Status == "Suspended") {
+		if !stage {
+			return nil, fmt.Errorf("cannot update plugins while server is running; stop the server first or stage the update for the next restart")
+		}
+		running = true
 	}
 
 	pDir := extensionsDir(cfg)
@@ -1630,12 +1879,48 @@ func (m *Manager) UpdatePlugin(id, fileName, downloadURL string) (*PluginInfo, e
 	if _, err := os.Stat(jarPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("plugin file not found: %s", fileName)
 	}
-	_, currentVersion := extractPluginVersion(jarPath)
 
-	// Download the new JAR to a temp file
+	// Staged downloads go straight to the pending area so nothing new
+	// appears in the live directory while the server has it open.
 	tmpPath := jarPath + ".update"
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	if running {
+		stageDir := m.stagedPluginUpdatesDir(cfg.ID)
+		if err := os.MkdirAll(stageDir, 0755); err != nil {
+			return nil, err
+		}
+		tmpPath = filepath.Join(stageDir, filepath.Base(fileName)+".download")
+	}
+	ctx, cancel := context.WithTimeout(job.ctx, 5*time.Minute)
 	defer cancel()
+	update, err := preparePluginUpdate(ctx, jarPath, downloadURL, tmpPath)
+	if err != nil {
+		return nil, err
+	}
+	job.progress(60, "Downloaded update")
+
+	if running {
+		return m.stagePluginUpdate(cfg, fileName, update)
+	}
+	return m.installPluginUpdate(cfg, fileName, update)
+}
+
+// preparedPluginUpdate is a downloaded and validated replacement jar.
+type preparedPluginUpdate struct {
+	jarPath        string
+	targetFileName string
+	name           string
+	version        string
+	installedFrom  string
+}
+
+// preparePluginUpdate downloads downloadURL to tmpPath and checks that it is
+// a newer build of the plugin at jarPath.
+func preparePluginUpdate(ctx context.Context, jarPath, downloadURL, tmpPath string) (*preparedPluginUpdate, error) {
+	// The new file name is worked out from the enabled name; a disabled jar
+	// stays disabled after the update.
+	fileName, disabled := splitDisabledSuffix(filepath.Base(jarPath))
+	_, currentVersion := extractPluginVersion(jarPath)
+
 	maxBytes := maxPluginUpdateBytesFromEnv()
 	downloadResult, err := secureDownloadPluginUpdate(ctx, downloadURL, tmpPath, maxBytes)
 	if err != nil {
@@ -1683,29 +1968,53 @@ func (m *Manager) UpdatePlugin(id, fileName, downloadURL string) (*PluginInfo, e
 			targetFileName = baseName + "-" + versionPart + ".jar"
 		}
 	}
-	targetPath, err := SafePath(pDir, filepath.Base(targetFileName))
+
+	installedFrom := downloadResult.ResolvedURL
+	if strings.TrimSpace(installedFrom) == "" {
+		installedFrom = downloadURL
+	}
+	return &preparedPluginUpdate{
+		jarPath:        downloadedJarPath,
+		targetFileName: withDisabledSuffix(filepath.Base(targetFileName), disabled),
+		name:           newName,
+		version:        newVersion,
+		installedFrom:  installedFrom,
+	}, nil
+}
+
+// installPluginUpdate swaps a prepared jar in for fileName. The server must
+// not be running.
+func (m *Manager) installPluginUpdate(cfg *ServerConfig, fileName string, update *preparedPluginUpdate) (*PluginInfo, error) {
+	pDir := extensionsDir(cfg)
+	jarPath, err := SafePath(pDir, filepath.Base(fileName))
+	if err != nil {
+		_ = os.Remove(update.jarPath)
+		return nil, fmt.Errorf("invalid plugin path: %w", err)
+	}
+	targetFileName := update.targetFileName
+	targetPath, err := SafePath(pDir, targetFileName)
 	if err != nil {
-		_ = os.Remove(downloadedJarPath)
+		_ = os.Remove(update.jarPath)
 		return nil, fmt.Errorf("invalid target plugin path: %w", err)
 	}
 
 	// Backup old JAR
 	backupPath := jarPath + ".bak"
 	if err := os.Rename(jarPath, backupPath); err != nil {
-		_ = os.Remove(downloadedJarPath)
+		_ = os.Remove(update.jarPath)
 		return nil, fmt.Errorf("failed to backup old plugin: %w", err)
 	}
 
 	if targetPath != jarPath {
 		if err := os.Remove(targetPath); err != nil && !os.IsNotExist(err) {
 			os.Rename(backupPath, jarPath)
-			_ = os.Remove(downloadedJarPath)
+			_ = os.Remove(update.jarPath)
 			return nil, fmt.Errorf("failed to replace existing target plugin: %w", err)
 		}
 	}
 
 	// Move new JAR into place
-	if err := os.Rename(downloadedJarPath, targetPath); err != nil {
+	if err := moveOrCopyFile(update.jarPath, targetPath, false); err != nil {
 		// Try to restore backup
 		os.Rename(backupPath, jarPath)
 		return nil, fmt.Errorf("failed to install update: %w", err)
@@ -1714,41 +2023,42 @@ func (m *Manager) UpdatePlugin(id, fileName, downloadURL string) (*PluginInfo, e
 	// Clean up backup
 	os.Remove(backupPath)
 
-	if oldKey, newKey := normalizeExtensionSourceKey(fileName), normalizeExtensionSourceKey(targetFileName); oldKey != newKey {
-		sources := m.loadExtensionSources(cfg)
-		if src, ok := sources[oldKey]; ok && strings.TrimSpace(src) != "" {
-			sources[newKey] = src
-			delete(sources, oldKey)
-			_ = m.saveExtensionSources(cfg, sources)
-		}
+	if err := m.moveExtensionRecord(cfg, fileName, targetFileName); err != nil {
+		log.Printf("[%s] Failed to move provenance for %s: %v", cfg.Name, fileName, err)
+	}
+	if err := m.recordExtensionInstall(cfg, targetFileName, ExtensionOriginUpdate, "", update.installedFrom, targetPath); err != nil {
+		log.Printf("[%s] Failed to record provenance for %s: %v", cfg.Name, targetFileName, err)
 	}
 
 	// Invalidate cache for this plugin
+	oldKey, newKey := normalizeExtensionSourceKey(fileName), normalizeExtensionSourceKey(targetFileName)
 	pluginUpdateCache.mu.Lock()
 	for key := range pluginUpdateCache.entries {
-		if strings.Contains(key, fileName) || strings.Contains(key, targetFileName) {
+		if strings.Contains(key, oldKey) || strings.Contains(key, newKey) {
 			delete(pluginUpdateCache.entries, key)
 		}
 	}
 	pluginUpdateCache.mu.Unlock()
 
-	log.Printf("Updated plugin %s for server %s (installed as %s)", fileName, id, targetFileName)
+	log.Printf("Updated plugin %s for server %s (installed as %s)", fileName, cfg.ID, targetFileName)
 
 	// Return updated plugin info
 	info, _ := os.Stat(targetPath)
 	pName, pVersion := extractPluginVersion(targetPath)
 	if pName == "" {
-		pName = newName
+		pName = update.name
 	}
+	baseName, disabled := splitDisabledSuffix(targetFileName)
 	if pName == "" {
-		pName = strings.TrimSuffix(targetFileName, ".jar")
+		pName = strings.TrimSuffix(baseName, ".jar")
 	}
 
 	return &PluginInfo{
-		Name:     pName,
-		FileName: targetFileName,
-		Size:     formatFileSize(info.Size()),
-		Enabled:  true,
-		Version:  pVersion,
+		Name:      pName,
+		FileName:  targetFileName,
+		Directory: defaultExtensionDirName(cfg),
+		Size:      formatFileSize(info.Size()),
+		Enabled:   !disabled,
+		Version:   pVersion,
 	}, nil
 }
@@ -0,0 +1,114 @@
+package minecraft
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	psnet "github.com/shirou/gopsutil/v4/net"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// FailureReasonPortInUse marks a boot that failed because the server port
+// was already bound.
+const FailureReasonPortInUse = "port_in_use"
+
+var portInUsePattern = regexp.MustCompile(`(?i)failed to bind to port|address already in use|BindException`)
+
+// PortConflict describes the process listening on a server's port. ServerID
+// is set when that process belongs to another server in this panel.
+type PortConflict struct {
+	Port       int    `json:"port"`
+	PID        int32  `json:"pid,omitempty"`
+	Process    string `json:"process,omitempty"`
+	ServerID   string `json:"serverId,omitempty"`
+	ServerName string `json:"serverName,omitempty"`
+}
+
+// Describe renders the conflict for console and log output.
+func (c *PortConflict) Describe() string {
+	switch {
+	case c.ServerName != "":
+		return fmt.Sprintf("port %d is in use by panel server %q (PID %d)", c.Port, c.ServerName, c.PID)
+	case c.PID > 0 && c.Process != "":
+		return fmt.Sprintf("port %d is in use by %s (PID %d), outside this panel", c.Port, c.Process, c.PID)
+	case c.PID > 0:
+		return fmt.Sprintf("port %d is in use by PID %d, outside this panel", c.Port, c.PID)
+	default:
+		return fmt.Sprintf("port %d is in use by a process the panel cannot see (it may belong to another user)", c.Port)
+	}
+}
+
+// findPortHolder looks up the process listening on a TCP port. The PID is
+// zero when the OS does not expose the owner to this user.
+func (m *Manager) findPortHolder(port int) *PortConflict {
+	conflict := &PortConflict{Port: port}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conns, err := psnet.ConnectionsWithContext(ctx, "tcp")
+	if err != nil {
+		log.Printf("Port conflict lookup for %d failed: %v", port, err)
+		return conflict
+	}
+	for _, conn := range conns {
+		if conn.Status != "LISTEN" || int(conn.Laddr.Port) != port {
+			continue
+		}
+		conflict.PID = conn.Pid
+		if conn.Pid > 0 {
+			break
+		}
+	}
+	if conflict.PID <= 0 {
+		return conflict
+	}
+
+	var parent int32
+	if proc, err := process.NewProcessWithContext(ctx, conflict.PID); err == nil {
+		if name, err := proc.NameWithContext(ctx); err == nil {
+			conflict.Process = name
+		}
+		parent, _ = proc.PpidWithContext(ctx)
+	}
+
+	// Match the listener, or its parent for servers started through a
+	// wrapper script, against the panel's running servers.
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for id, rs := range m.running {
+		rs.mu.RLock()
+		pid := int32(rs.pid)
+		rs.mu.RUnlock()
+		if pid <= 0 || (pid != conflict.PID && pid != parent) {
+			continue
+		}
+		conflict.ServerID = id
+		if cfg := m.configs[id]; cfg != nil {
+			conflict.ServerName = cfg.Name
+		}
+		break
+	}
+	return conflict
+}
+
+// recordPortConflict runs after scanOutput sees a bind failure. It finds the
+// port's owner, keeps it on the server and tells the console.
+func (m *Manager) recordPortConflict(id string, rs *runningServer, port int) {
+	conflict := m.findPortHolder(port)
+	rs.mu.Lock()
+	rs.portConflict = conflict
+	rs.mu.Unlock()
+
+	message := conflict.Describe()
+	m.mu.RLock()
+	cfg := m.configs[id]
+	m.mu.RUnlock()
+	if cfg != nil {
+		log.Printf("[%s] Boot failed: %s", cfg.Name, message)
+	}
+	entry := m.appendLog(rs, "[Panel] "+message)
+	m.broadcastLog(rs, entry)
+}
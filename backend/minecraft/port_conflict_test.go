@@ -0,0 +1,54 @@
+package minecraft
+
+import (
+	"net"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFindPortHolderMatchesPanelServer(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	conflict := mgr.findPortHolder(port)
+	if conflict.Port != port {
+		t.Fatalf("expected port %d, got %+v", port, conflict)
+	}
+	if conflict.PID == 0 {
+		t.Skip("listener owner is not visible in this environment")
+	}
+	if conflict.PID != int32(os.Getpid()) || conflict.ServerID != "" {
+		t.Fatalf("expected an external holder with our PID, got %+v", conflict)
+	}
+	if !strings.Contains(conflict.Describe(), "outside this panel") {
+		t.Fatalf("unexpected description %q", conflict.Describe())
+	}
+
+	mgr.mu.Lock()
+	mgr.configs["lobby"] = &ServerConfig{ID: "lobby", Name: "Lobby"}
+	mgr.running["lobby"] = &runningServer{status: "Running", pid: os.Getpid()}
+	mgr.mu.Unlock()
+
+	conflict = mgr.findPortHolder(port)
+	if conflict.ServerID != "lobby" || conflict.ServerName != "Lobby" {
+		t.Fatalf("expected the holder to map to the Lobby server, got %+v", conflict)
+	}
+}
+
+func TestPortConflictDescribeUnknownOwner(t *testing.T) {
+	got := (&PortConflict{Port: 25565}).Describe()
+	if !strings.Contains(got, "25565") || !strings.Contains(got, "cannot see") {
+		t.Fatalf("unexpected description %q", got)
+	}
+}
@@ -0,0 +1,115 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// UserPreferences are per-login UI choices kept on the panel so they follow
+// the user between browsers.
+type UserPreferences struct {
+	FavoriteServerIDs []string  `json:"favoriteServerIds"`
+	ServerOrder       []string  `json:"serverOrder"`
+	DefaultServerID   string    `json:"defaultServerId,omitempty"`
+	UpdatedAt         time.Time `json:"updatedAt,omitempty"`
+}
+
+// loadPreferencesLocked reads the preferences document, keyed by username.
+// A missing document is an empty set. Callers hold m.prefsMu.
+func (m *Manager) loadPreferencesLocked() (map[string]UserPreferences, error) {
+	prefs := make(map[string]UserPreferences)
+	data, err := m.storage().Load(storeDocPreferences)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return prefs, nil
+		}
+		return nil, fmt.Errorf("failed to read preferences: %w", err)
+	}
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		return nil, fmt.Errorf("failed to parse preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// filterKnownServerIDs keeps IDs of servers that still exist, once each and
+// in the given order.
+func (m *Manager) filterKnownServerIDs(ids []string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		if _, ok := m.configs[id]; !ok {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
+
+// sanitizePreferences drops references to servers that no longer exist.
+func (m *Manager) sanitizePreferences(prefs UserPreferences) UserPreferences {
+	prefs.FavoriteServerIDs = m.filterKnownServerIDs(prefs.FavoriteServerIDs)
+	prefs.ServerOrder = m.filterKnownServerIDs(prefs.ServerOrder)
+	if prefs.DefaultServerID != "" {
+		if known := m.filterKnownServerIDs([]string{prefs.DefaultServerID}); len(known) == 0 {
+			prefs.DefaultServerID = ""
+		}
+	}
+	return prefs
+}
+
+// GetUserPreferences returns the stored preferences for username, or empty
+// preferences when none were saved yet.
+func (m *Manager) GetUserPreferences(username string) (UserPreferences, error) {
+	m.prefsMu.Lock()
+	all, err := m.loadPreferencesLocked()
+	m.prefsMu.Unlock()
+	if err != nil {
+		return UserPreferences{}, err
+	}
+	return m.sanitizePreferences(all[username]), nil
+}
+
+// SetUserPreferences replaces the preferences for username. Unknown and
+// duplicate server IDs are dropped; an unknown default server is an error.
+func (m *Manager) SetUserPreferences(username string, prefs UserPreferences) (UserPreferences, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return UserPreferences{}, fmt.Errorf("username is required")
+	}
+	prefs.DefaultServerID = strings.TrimSpace(prefs.DefaultServerID)
+	requestedDefault := prefs.DefaultServerID
+	prefs = m.sanitizePreferences(prefs)
+	if requestedDefault != "" && prefs.DefaultServerID == "" {
+		return UserPreferences{}, errServerNotFound(requestedDefault)
+	}
+	prefs.UpdatedAt = time.Now().UTC()
+
+	m.prefsMu.Lock()
+	defer m.prefsMu.Unlock()
+
+	all, err := m.loadPreferencesLocked()
+	if err != nil {
+		return UserPreferences{}, err
+	}
+	all[username] = prefs
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return UserPreferences{}, fmt.Errorf("failed to marshal preferences: %w", err)
+	}
+	if err := m.storage().Save(storeDocPreferences, data); err != nil {
+		return UserPreferences{}, fmt.Errorf("failed to save preferences: %w", err)
+	}
+	return prefs, nil
+}
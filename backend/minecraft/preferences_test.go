@@ -0,0 +1,52 @@
+package minecraft
+
+import (
+	"testing"
+)
+
+func TestUserPreferencesRoundTrip(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	mgr.mu.Lock()
+	mgr.configs["lobby"] = &ServerConfig{ID: "lobby", Name: "Lobby"}
+	mgr.configs["game"] = &ServerConfig{ID: "game", Name: "Game"}
+	mgr.mu.Unlock()
+
+	if prefs, err := mgr.GetUserPreferences("admin"); err != nil || len(prefs.FavoriteServerIDs) != 0 {
+		t.Fatalf("expected empty preferences, got %+v (%v)", prefs, err)
+	}
+
+	saved, err := mgr.SetUserPreferences("admin", UserPreferences{
+		FavoriteServerIDs: []string{"game", "game", "gone"},
+		ServerOrder:       []string{"game", "lobby"},
+		DefaultServerID:   "lobby",
+	})
+	if err != nil {
+		t.Fatalf("SetUserPreferences failed: %v", err)
+	}
+	if len(saved.FavoriteServerIDs) != 1 || saved.FavoriteServerIDs[0] != "game" {
+		t.Fatalf("unexpected favorites %q", saved.FavoriteServerIDs)
+	}
+	if _, err := mgr.SetUserPreferences("admin", UserPreferences{DefaultServerID: "gone"}); err == nil {
+		t.Fatalf("expected unknown default server to be rejected")
+	}
+
+	mgr.mu.Lock()
+	delete(mgr.configs, "lobby")
+	mgr.mu.Unlock()
+
+	prefs, err := mgr.GetUserPreferences("admin")
+	if err != nil {
+		t.Fatalf("GetUserPreferences failed: %v", err)
+	}
+	if prefs.DefaultServerID != "" || len(prefs.ServerOrder) != 1 || prefs.ServerOrder[0] != "game" {
+		t.Fatalf("expected deleted server to be dropped, got %+v", prefs)
+	}
+	if other, _ := mgr.GetUserPreferences("someone"); len(other.ServerOrder) != 0 {
+		t.Fatalf("preferences leaked across users: %+v", other)
+	}
+}
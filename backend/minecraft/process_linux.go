@@ -20,3 +20,19 @@ func killServerProcessTree(pid int) error {
 	// Negative PID targets the full process group created with Setpgid.
 	return syscall.Kill(-pid, syscall.SIGKILL)
 }
+
+// terminateServerProcess asks the server's process group to shut down.
+// Minecraft saves worlds on SIGTERM through its shutdown hook.
+func terminateServerProcess(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGTERM)
+}
+
+// suspendServerProcess freezes the server's process group. The processes
+// keep their memory but get no CPU time until resumeServerProcess.
+func suspendServerProcess(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGSTOP)
+}
+
+func resumeServerProcess(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGCONT)
+}
@@ -0,0 +1,176 @@
+package minecraft
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProxyBackends is the managed Velocity proxy in front of a server and the
+// backend names it knows, in the order velocity.toml lists them.
+type ProxyBackends struct {
+	ProxyID   string   `json:"proxyId"`
+	ProxyName string   `json:"proxyName"`
+	Servers   []string `json:"servers"`
+}
+
+type velocityBackend struct {
+	name    string
+	address string
+}
+
+// readVelocityBackends returns the entries of the [servers] table of a
+// velocity.toml. The "try" key holds the join order, not a server.
+func readVelocityBackends(path string) ([]velocityBackend, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("velocity.toml not found (start the proxy once so it can be generated)")
+		}
+		return nil, err
+	}
+	var backends []velocityBackend
+	inServers := false
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") {
+			inServers = trimmed == "[servers]"
+			continue
+		}
+		if !inServers {
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, "=")
+		if !ok {
+			continue
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"'`)
+		value = strings.TrimSpace(value)
+		if key == "" || key == "try" || !strings.HasPrefix(value, `"`) {
+			continue
+		}
+		value = strings.TrimPrefix(value, `"`)
+		if end := strings.Index(value, `"`); end >= 0 {
+			value = value[:end]
+		}
+		backends = append(backends, velocityBackend{name: key, address: value})
+	}
+	return backends, nil
+}
+
+func backendNames(backends []velocityBackend) []string {
+	names := make([]string, 0, len(backends))
+	for _, b := range backends {
+		names = append(names, b.name)
+	}
+	return names
+}
+
+// backendPort returns the port of a velocity.toml server address, which
+// defaults to 25565 when left out.
+func backendPort(address string) int {
+	if _, port, err := net.SplitHostPort(address); err == nil {
+		n, _ := strconv.Atoi(port)
+		return n
+	}
+	return 25565
+}
+
+// FindProxyBackends returns the proxy that routes players for server id.
+// A proxy answers for itself; a backend is matched to the managed proxy
+// whose velocity.toml lists its port, preferring one that is running.
+func (m *Manager) FindProxyBackends(id string) (*ProxyBackends, error) {
+	type candidate struct {
+		id, name, dir string
+		running       bool
+	}
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		m.mu.RUnlock()
+		return nil, err
+	}
+	self := isProxyType(cfg.Type)
+	port := cfg.Port
+	var proxies []candidate
+	for _, c := range m.configs {
+		if !isProxyType(c.Type) || (self && c.ID != id) {
+			continue
+		}
+		running := false
+		if rs, ok := m.running[c.ID]; ok {
+			rs.mu.RLock()
+			running = rs.status == "Running"
+			rs.mu.RUnlock()
+		}
+		proxies = append(proxies, candidate{id: c.ID, name: c.Name, dir: c.Dir, running: running})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(proxies, func(i, j int) bool {
+		if proxies[i].running != proxies[j].running {
+			return proxies[i].running
+		}
+		return proxies[i].name < proxies[j].name
+	})
+	for _, p := range proxies {
+		backends, err := readVelocityBackends(filepath.Join(p.dir, "velocity.toml"))
+		if err != nil {
+			if self {
+				return nil, err
+			}
+			continue
+		}
+		if !self && !containsBackendPort(backends, port) {
+			continue
+		}
+		return &ProxyBackends{ProxyID: p.id, ProxyName: p.name, Servers: backendNames(backends)}, nil
+	}
+	return nil, fmt.Errorf("server is not behind a managed Velocity proxy")
+}
+
+func containsBackendPort(backends []velocityBackend, port int) bool {
+	for _, b := range backends {
+		if backendPort(b.address) == port {
+			return true
+		}
+	}
+	return false
+}
+
+// SendPlayer moves a connected player to another backend with Velocity's
+// send command. id is the proxy itself or a server behind it.
+func (m *Manager) SendPlayer(id, playerName, target string) (*ProxyBackends, error) {
+	if !isValidPlayerName(playerName) {
+		return nil, fmt.Errorf("invalid player name %q", playerName)
+	}
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil, fmt.Errorf("target server is required")
+	}
+	proxy, err := m.FindProxyBackends(id)
+	if err != nil {
+		return nil, err
+	}
+	known := false
+	for _, name := range proxy.Servers {
+		if name == target {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown backend %q; %s knows %s", target, proxy.ProxyName, strings.Join(proxy.Servers, ", "))
+	}
+	if err := m.SendCommand(proxy.ProxyID, fmt.Sprintf("send %s %s", playerName, target)); err != nil {
+		return nil, err
+	}
+	return proxy, nil
+}
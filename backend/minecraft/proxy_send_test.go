@@ -0,0 +1,61 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testVelocityToml = `bind = "0.0.0.0:25577"
+
+[servers]
+# Backends players can join.
+lobby = "127.0.0.1:25566"
+"survival" = "127.0.0.1:25567"
+try = ["lobby"]
+
+[forced-hosts]
+"mc.example.com" = ["lobby"]
+`
+
+func TestFindProxyBackendsMatchesBackendByPort(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	proxyDir := filepath.Join(mgr.serversRoot, "Proxy")
+	if err := os.MkdirAll(proxyDir, 0755); err != nil {
+		t.Fatalf("failed to create proxy dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(proxyDir, "velocity.toml"), []byte(testVelocityToml), 0644); err != nil {
+		t.Fatalf("failed to write velocity.toml: %v", err)
+	}
+	mgr.mu.Lock()
+	mgr.configs["proxy"] = &ServerConfig{ID: "proxy", Name: "Proxy", Type: "Velocity", Port: 25577, Dir: proxyDir}
+	mgr.configs["survival"] = &ServerConfig{ID: "survival", Name: "Survival", Type: "Paper", Port: 25567, Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	mgr.configs["creative"] = &ServerConfig{ID: "creative", Name: "Creative", Type: "Paper", Port: 25580, Dir: filepath.Join(mgr.serversRoot, "Creative")}
+	mgr.mu.Unlock()
+
+	for _, id := range []string{"proxy", "survival"} {
+		proxy, err := mgr.FindProxyBackends(id)
+		if err != nil {
+			t.Fatalf("FindProxyBackends(%s) failed: %v", id, err)
+		}
+		if proxy.ProxyID != "proxy" || !reflect.DeepEqual(proxy.Servers, []string{"lobby", "survival"}) {
+			t.Fatalf("unexpected proxy for %s: %+v", id, proxy)
+		}
+	}
+	if _, err := mgr.FindProxyBackends("creative"); err == nil {
+		t.Fatalf("expected a server the proxy does not list to have no proxy")
+	}
+
+	if _, err := mgr.SendPlayer("survival", "Steve", "hub"); err == nil {
+		t.Fatalf("expected an unknown backend to be refused")
+	}
+	if _, err := mgr.SendPlayer("survival", "Steve lobby", "lobby"); err == nil {
+		t.Fatalf("expected an invalid player name to be refused")
+	}
+}
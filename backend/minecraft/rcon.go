@@ -0,0 +1,303 @@
+package minecraft
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RCON packet types from the Source RCON protocol Minecraft implements.
+const (
+	rconTypeResponse = 0
+	rconTypeCommand  = 2
+	rconTypeAuth     = 3
+)
+
+const (
+	rconTimeout        = 5 * time.Second
+	maxRCONPayload     = 4096
+	maxRCONPasswordLen = 128
+)
+
+var errRCONAuthFailed = errors.New("RCON authentication failed")
+
+// RCONConfig is how the panel reaches a server over RCON. The panel uses it
+// when it cannot write to the server's stdin, for example after a panel
+// restart left the server running on its own.
+type RCONConfig struct {
+	Port     int    `json:"port"`
+	Password string `json:"password"`
+}
+
+// rconClient is one authenticated RCON connection.
+type rconClient struct {
+	conn   net.Conn
+	nextID int32
+}
+
+// dialRCON connects to addr and logs in with password.
+func dialRCON(addr, password string) (*rconClient, error) {
+	conn, err := net.DialTimeout("tcp", addr, rconTimeout)
+	if err != nil {
+		return nil, err
+	}
+	c := &rconClient{conn: conn}
+	id, err := c.send(rconTypeAuth, password)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	// Some servers send an empty response before the auth result.
+	for {
+		respID, respType, _, err := c.read()
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if respType != rconTypeCommand {
+			continue
+		}
+		if respID == -1 || respID != id {
+			conn.Close()
+			return nil, errRCONAuthFailed
+		}
+		return c, nil
+	}
+}
+
+func (c *rconClient) close() error {
+	return c.conn.Close()
+}
+
+// command runs cmd and returns the server's reply.
+func (c *rconClient) command(cmd string) (string, error) {
+	id, err := c.send(rconTypeCommand, cmd)
+	if err != nil {
+		return "", err
+	}
+	for {
+		respID, respType, body, err := c.read()
+		if err != nil {
+			return "", err
+		}
+		if respID == id && respType == rconTypeResponse {
+			return body, nil
+		}
+	}
+}
+
+// commandAll runs cmd and returns its whole reply. Servers split replies
+// longer than one packet, so a second request the server answers with
+// "Unknown request" marks where the reply ends.
+func (c *rconClient) commandAll(cmd string) (string, error) {
+	id, err := c.send(rconTypeCommand, cmd)
+	if err != nil {
+		return "", err
+	}
+	marker, err := c.send(rconTypeResponse, "")
+	if err != nil {
+		return "", err
+	}
+	var reply strings.Builder
+	for {
+		respID, respType, body, err := c.read()
+		if err != nil {
+			return "", err
+		}
+		if respID == marker {
+			return reply.String(), nil
+		}
+		if respID == id && respType == rconTypeResponse {
+			reply.WriteString(body)
+		}
+	}
+}
+
+func (c *rconClient) send(packetType int32, body string) (int32, error) {
+	if len(body) > maxRCONPayload-10 {
+		return 0, fmt.Errorf("RCON command is too long")
+	}
+	c.nextID++
+	id := c.nextID
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, int32(len(body)+10))
+	binary.Write(&buf, binary.LittleEndian, id)
+	binary.Write(&buf, binary.LittleEndian, packetType)
+	buf.WriteString(body)
+	buf.Write([]byte{0, 0})
+
+	c.conn.SetWriteDeadline(time.Now().Add(rconTimeout))
+	if _, err := c.conn.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (c *rconClient) read() (int32, int32, string, error) {
+	c.conn.SetReadDeadline(time.Now().Add(rconTimeout))
+	var size int32
+	if err := binary.Read(c.conn, binary.LittleEndian, &size); err != nil {
+		return 0, 0, "", err
+	}
+	if size < 10 || size > maxRCONPayload+10 {
+		return 0, 0, "", fmt.Errorf("invalid RCON packet size %d", size)
+	}
+	packet := make([]byte, size)
+	if _, err := io.ReadFull(c.conn, packet); err != nil {
+		return 0, 0, "", err
+	}
+	id := int32(binary.LittleEndian.Uint32(packet[0:4]))
+	packetType := int32(binary.LittleEndian.Uint32(packet[4:8]))
+	body := string(bytes.TrimRight(packet[8:], "\x00"))
+	return id, packetType, body, nil
+}
+
+// rconCommand sends command to server id over RCON and returns the reply.
+func (m *Manager) rconCommand(id, command string) (string, error) {
+	m.mu.RLock()
+	cfg := m.configs[id]
+	var rcon RCONConfig
+	if cfg != nil && cfg.RCON != nil {
+		rcon = *cfg.RCON
+	}
+	m.mu.RUnlock()
+	if rcon.Port == 0 {
+		return "", fmt.Errorf("RCON is not configured for server %s", id)
+	}
+
+	client, err := dialRCON(net.JoinHostPort("127.0.0.1", strconv.Itoa(rcon.Port)), rcon.Password)
+	if err != nil {
+		return "", err
+	}
+	defer client.close()
+	return client.command(command)
+}
+
+// rconQuery is rconCommand for commands whose reply can span several
+// packets, such as "data get entity".
+func (m *Manager) rconQuery(id, command string) (string, error) {
+	m.mu.RLock()
+	cfg := m.configs[id]
+	var rcon RCONConfig
+	if cfg != nil && cfg.RCON != nil {
+		rcon = *cfg.RCON
+	}
+	m.mu.RUnlock()
+	if rcon.Port == 0 {
+		return "", fmt.Errorf("RCON is not configured for server %s", id)
+	}
+
+	client, err := dialRCON(net.JoinHostPort("127.0.0.1", strconv.Itoa(rcon.Port)), rcon.Password)
+	if err != nil {
+		return "", err
+	}
+	defer client.close()
+	return client.commandAll(command)
+}
+
+// SetRCONConfig sets the RCON port and password the panel uses for a
+// server and writes them to its server.properties. Port 0 turns RCON off.
+func (m *Manager) SetRCONConfig(id string, rcon RCONConfig) (*ServerInfo, error) {
+	rcon.Password = strings.TrimSpace(rcon.Password)
+	enabled := rcon.Port != 0
+	if enabled {
+		if rcon.Port < 1024 || rcon.Port > 65535 {
+			return nil, fmt.Errorf("RCON port must be between 1024 and 65535")
+		}
+		if rcon.Password == "" {
+			return nil, fmt.Errorf("RCON password is required")
+		}
+		if len(rcon.Password) > maxRCONPasswordLen || strings.ContainsAny(rcon.Password, "\r\n") {
+			return nil, fmt.Errorf("RCON password must be a single line of at most %d characters", maxRCONPasswordLen)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(cfg.Type, "velocity") {
+		return nil, fmt.Errorf("RCON is not available for Velocity proxies")
+	}
+	if enabled {
+		if rcon.Port == cfg.Port {
+			return nil, fmt.Errorf("RCON port must differ from the server port")
+		}
+		for _, other := range m.configs {
+			if other.ID != id && (other.Port == rcon.Port || (other.RCON != nil && other.RCON.Port == rcon.Port)) {
+				return nil, errPortTaken(rcon.Port, other.Name)
+			}
+		}
+	}
+
+	props := map[string]string{"enable-rcon": "false"}
+	if enabled {
+		props = map[string]string{
+			"enable-rcon":   "true",
+			"rcon.port":     strconv.Itoa(rcon.Port),
+			"rcon.password": rcon.Password,
+		}
+	}
+	if err := setServerProperties(filepath.Join(cfg.Dir, "server.properties"), props); err != nil {
+		return nil, fmt.Errorf("failed to update server.properties: %w", err)
+	}
+
+	if enabled {
+		cfg.RCON = &rcon
+	} else {
+		cfg.RCON = nil
+	}
+	if err := m.persist(); err != nil {
+		return nil, err
+	}
+	return m.serverInfo(id), nil
+}
+
+// setServerProperties replaces or appends the given keys in a
+// server.properties file. A missing file is created.
+func setServerProperties(path string, values map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	var lines []string
+	if content := strings.ReplaceAll(string(data), "\r\n", "\n"); content != "" {
+		lines = strings.Split(strings.TrimRight(content, "\n"), "\n")
+	}
+	written := make(map[string]bool, len(values))
+	for i, line := range lines {
+		key, _, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if value, set := values[key]; set {
+			lines[i] = key + "=" + value
+			written[key] = true
+		}
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if !written[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lines = append(lines, key+"="+values[key])
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
@@ -0,0 +1,117 @@
+package minecraft
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// serveFakeRCON answers one RCON connection, accepting password and
+// replying to every command with reply.
+func serveFakeRCON(t *testing.T, ln net.Listener, password, reply string) {
+	t.Helper()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		write := func(id, packetType int32, body string) {
+			var buf bytes.Buffer
+			binary.Write(&buf, binary.LittleEndian, int32(len(body)+10))
+			binary.Write(&buf, binary.LittleEndian, id)
+			binary.Write(&buf, binary.LittleEndian, packetType)
+			buf.WriteString(body)
+			buf.Write([]byte{0, 0})
+			conn.Write(buf.Bytes())
+		}
+		for {
+			var size int32
+			if err := binary.Read(conn, binary.LittleEndian, &size); err != nil {
+				return
+			}
+			packet := make([]byte, size)
+			if _, err := io.ReadFull(conn, packet); err != nil {
+				return
+			}
+			id := int32(binary.LittleEndian.Uint32(packet[0:4]))
+			body := string(bytes.TrimRight(packet[8:], "\x00"))
+			switch int32(binary.LittleEndian.Uint32(packet[4:8])) {
+			case rconTypeAuth:
+				if body != password {
+					id = -1
+				}
+				write(id, rconTypeCommand, "")
+			case rconTypeCommand:
+				write(id, rconTypeResponse, reply)
+			}
+		}
+	}()
+}
+
+func TestSendCommandFallsBackToRCON(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer ln.Close()
+	rconPort := ln.Addr().(*net.TCPAddr).Port
+
+	dir := filepath.Join(mgr.serversRoot, "Lobby")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "server.properties"), []byte("motd=hi\nenable-rcon=false\n"), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	rs := &runningServer{status: "Stopped"}
+	mgr.mu.Lock()
+	mgr.configs["srv1"] = &ServerConfig{ID: "srv1", Name: "Lobby", Type: "Paper", Port: 25565, Dir: dir}
+	mgr.running["srv1"] = rs
+	mgr.mu.Unlock()
+
+	if err := mgr.SendCommand("srv1", "list"); err == nil {
+		t.Fatalf("expected command to fail without stdin or RCON")
+	}
+	if _, err := mgr.SetRCONConfig("srv1", RCONConfig{Port: 25565, Password: "secret"}); err == nil {
+		t.Fatalf("expected RCON on the game port to be rejected")
+	}
+	info, err := mgr.SetRCONConfig("srv1", RCONConfig{Port: rconPort, Password: "secret"})
+	if err != nil {
+		t.Fatalf("SetRCONConfig failed: %v", err)
+	}
+	if info.RCONPort != rconPort {
+		t.Fatalf("expected rconPort %d, got %d", rconPort, info.RCONPort)
+	}
+	props := parseServerPropertiesFile(filepath.Join(dir, "server.properties"))
+	if props["enable-rcon"] != "true" || props["rcon.password"] != "secret" || props["motd"] != "hi" {
+		t.Fatalf("unexpected server.properties %v", props)
+	}
+
+	serveFakeRCON(t, ln, "secret", "There are 0 of a max of 20 players online")
+	if err := mgr.SendCommand("srv1", "list"); err != nil {
+		t.Fatalf("SendCommand over RCON failed: %v", err)
+	}
+	rs.mu.RLock()
+	last := rs.logBuffer[len(rs.logBuffer)-1].Line
+	rs.mu.RUnlock()
+	if !strings.Contains(last, "[RCON] There are 0 of a max of 20 players online") {
+		t.Fatalf("expected RCON reply in console, got %q", last)
+	}
+
+	serveFakeRCON(t, ln, "other", "")
+	if err := mgr.SendCommand("srv1", "list"); err == nil || !strings.Contains(err.Error(), "authentication failed") {
+		t.Fatalf("expected wrong password to fail, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package minecraft
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	maxReadyCommands      = 20
+	maxReadyCommandLength = 256
+	readyCommandInterval  = 500 * time.Millisecond
+)
+
+// normalizeReadyCommands trims each command, drops blanks and a leading
+// slash, and rejects lists the console could not take line by line.
+func normalizeReadyCommands(commands []string) ([]string, error) {
+	normalized := make([]string, 0, len(commands))
+	for _, command := range commands {
+		command = strings.TrimPrefix(strings.TrimSpace(command), "/")
+		if command == "" {
+			continue
+		}
+		if strings.ContainsAny(command, "\r\n") {
+			return nil, fmt.Errorf("ready commands must be single lines")
+		}
+		if len(command) > maxReadyCommandLength {
+			return nil, fmt.Errorf("ready commands must be at most %d characters", maxReadyCommandLength)
+		}
+		normalized = append(normalized, command)
+	}
+	if len(normalized) > maxReadyCommands {
+		return nil, fmt.Errorf("at most %d ready commands are allowed", maxReadyCommands)
+	}
+	return normalized, nil
+}
+
+// SetReadyCommands stores console commands that run, in order, each time the
+// server finishes booting. An empty list turns the feature off.
+func (m *Manager) SetReadyCommands(id string, commands []string) (*ServerInfo, error) {
+	normalized, err := normalizeReadyCommands(commands)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(normalized) == 0 {
+		normalized = nil
+	}
+	cfg.ReadyCommands = normalized
+	if err := m.persist(); err != nil {
+		return nil, err
+	}
+
+	return m.serverInfo(id), nil
+}
+
+// runReadyCommands sends the server's ready commands once it reports Running.
+// Commands are paced so plugins see them one at a time, and the run stops
+// if the server leaves Running part way through.
+func (m *Manager) runReadyCommands(id string, rs *runningServer) {
+	m.mu.RLock()
+	cfg := m.configs[id]
+	var commands []string
+	name := ""
+	if cfg != nil {
+		commands = append(commands, cfg.ReadyCommands...)
+		name = cfg.Name
+	}
+	m.mu.RUnlock()
+	if len(commands) == 0 {
+		return
+	}
+
+	log.Printf("[%s] Running %d ready command(s)", name, len(commands))
+	for i, command := range commands {
+		if i > 0 {
+			time.Sleep(readyCommandInterval)
+		}
+		if err := rs.writeStdin(command); err != nil {
+			log.Printf("[%s] Ready commands stopped at %q: %v", name, command, err)
+			return
+		}
+		entry := m.appendLog(rs, "> "+command)
+		m.broadcastLog(rs, entry)
+	}
+}
@@ -0,0 +1,68 @@
+package minecraft
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type nopWriteCloser struct{ *bytes.Buffer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestSetReadyCommandsNormalizes(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv1", Name: "Lobby", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Lobby")}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = &runningServer{status: "Stopped"}
+	mgr.mu.Unlock()
+
+	info, err := mgr.SetReadyCommands(cfg.ID, []string{" /whitelist off ", "", "say Lobby is open"})
+	if err != nil {
+		t.Fatalf("SetReadyCommands failed: %v", err)
+	}
+	if strings.Join(info.ReadyCommands, "|") != "whitelist off|say Lobby is open" {
+		t.Fatalf("unexpected ready commands %q", info.ReadyCommands)
+	}
+
+	if _, err := mgr.SetReadyCommands(cfg.ID, []string{"say a\nstop"}); err == nil {
+		t.Fatalf("expected multi-line command to be rejected")
+	}
+	if _, err := mgr.SetReadyCommands(cfg.ID, make([]string, 0)); err != nil {
+		t.Fatalf("clearing ready commands failed: %v", err)
+	}
+	if cfg.ReadyCommands != nil {
+		t.Fatalf("expected ready commands to be cleared, got %q", cfg.ReadyCommands)
+	}
+}
+
+func TestRunReadyCommandsWritesToConsole(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	var stdin bytes.Buffer
+	rs := &runningServer{status: "Running", stdin: nopWriteCloser{&stdin}}
+	mgr.mu.Lock()
+	mgr.configs["srv1"] = &ServerConfig{ID: "srv1", Name: "Lobby", ReadyCommands: []string{"whitelist off", "say ready"}}
+	mgr.running["srv1"] = rs
+	mgr.mu.Unlock()
+
+	mgr.runReadyCommands("srv1", rs)
+
+	if got := stdin.String(); got != "whitelist off\nsay ready\n" {
+		t.Fatalf("unexpected stdin %q", got)
+	}
+	if len(rs.logBuffer) != 2 || rs.logBuffer[1].Line != "> say ready" {
+		t.Fatalf("expected commands echoed to the console, got %+v", rs.logBuffer)
+	}
+}
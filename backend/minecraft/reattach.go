@@ -0,0 +1,251 @@
+package minecraft
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+const (
+	reattachPollInterval = 2 * time.Second
+	logTailInterval      = 500 * time.Millisecond
+)
+
+// processStartTime returns when pid started, in milliseconds since the
+// epoch, or 0 if it is not running. Comparing it with the recorded value
+// tells a server's process apart from a later one that reused its PID.
+func processStartTime(pid int) int64 {
+	if pid <= 0 {
+		return 0
+	}
+	proc, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return 0
+	}
+	created, err := proc.CreateTime()
+	if err != nil {
+		return 0
+	}
+	return created
+}
+
+// processSuspended reports whether pid is stopped by a signal such as the
+// SIGSTOP of SuspendServer.
+func processSuspended(pid int) bool {
+	proc, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return false
+	}
+	states, err := proc.Status()
+	if err != nil {
+		return false
+	}
+	for _, state := range states {
+		if state == process.Stop {
+			return true
+		}
+	}
+	return false
+}
+
+// recordServerProcess stores a started server's PID and start time in
+// servers.json so a restarted panel can find the process again.
+func (m *Manager) recordServerProcess(id string, pid int) {
+	startedAt := processStartTime(pid)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg := m.configs[id]
+	if cfg == nil {
+		return
+	}
+	cfg.PID = pid
+	cfg.ProcessStartedAt = startedAt
+	if err := m.persist(); err != nil {
+		log.Printf("[%s] Failed to record server PID: %v", cfg.Name, err)
+	}
+}
+
+// forgetServerProcess clears the recorded PID once that process has exited.
+// A PID recorded by a later start is left alone.
+func (m *Manager) forgetServerProcess(id string, pid int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg := m.configs[id]
+	if cfg == nil || cfg.PID == 0 || cfg.PID != pid {
+		return
+	}
+	cfg.PID = 0
+	cfg.ProcessStartedAt = 0
+	if err := m.persist(); err != nil {
+		log.Printf("[%s] Failed to clear server PID: %v", cfg.Name, err)
+	}
+}
+
+// waitForProcessExit blocks until pid is no longer the process that
+// started at startedAt. It is used for reattached servers, which are not
+// children of the panel and cannot be waited on.
+func waitForProcessExit(pid int, startedAt int64) {
+	if startedAt == 0 {
+		return
+	}
+	for processStartTime(pid) == startedAt {
+		time.Sleep(reattachPollInterval)
+	}
+}
+
+// reattachServers picks up servers whose process outlived the previous
+// panel run. Their console follows logs/latest.log and commands go over
+// RCON, since the old stdin and stdout pipes are gone. It returns the IDs
+// of the servers it reattached.
+func (m *Manager) reattachServers() map[string]bool {
+	reattached := make(map[string]bool)
+	stale := false
+	for id, cfg := range m.configs {
+		if cfg.PID == 0 {
+			continue
+		}
+		startedAt := processStartTime(cfg.PID)
+		if startedAt == 0 || startedAt != cfg.ProcessStartedAt {
+			cfg.PID = 0
+			cfg.ProcessStartedAt = 0
+			stale = true
+			continue
+		}
+		if rs := m.running[id]; rs != nil {
+			m.reattachServer(id, cfg, rs)
+			reattached[id] = true
+		}
+	}
+	if stale {
+		if err := m.persist(); err != nil {
+			log.Printf("Failed to clear stale server PIDs: %v", err)
+		}
+	}
+	return reattached
+}
+
+func (m *Manager) reattachServer(id string, cfg *ServerConfig, rs *runningServer) {
+	pid := cfg.PID
+	startedAt := cfg.ProcessStartedAt
+	stopMetrics := make(chan struct{})
+
+	rs.mu.Lock()
+	rs.status = "Running"
+	if processSuspended(pid) {
+		// Suspended before the panel restarted.
+		rs.status = "Suspended"
+		rs.suspendedAt = time.Now()
+	}
+	rs.pid = pid
+	rs.bootStartedAt = time.UnixMilli(startedAt)
+	rs.reattached = true
+	rs.stopMetrics = stopMetrics
+	rs.mu.Unlock()
+
+	if cfg.RCON != nil {
+		log.Printf("[%s] Reattached to running server (PID: %d)", cfg.Name, pid)
+	} else {
+		log.Printf("[%s] Reattached to running server (PID: %d); configure RCON to send it commands", cfg.Name, pid)
+	}
+	m.broadcastLog(rs, m.appendLog(rs, "[Panel] Reattached after a panel restart. Showing new lines from logs/latest.log."))
+
+	logReader, logWriter := io.Pipe()
+	go m.scanOutput(id, rs, logReader)
+	go tailLogFile(filepath.Join(cfg.Dir, "logs", "latest.log"), logWriter, stopMetrics)
+	go m.collectMetrics(id, rs)
+	go m.refreshPingSupport(id)
+
+	go func() {
+		waitForProcessExit(pid, startedAt)
+		rs.mu.Lock()
+		if rs.reattached && rs.pid == pid {
+			log.Printf("[%s] Reattached server exited", cfg.Name)
+			resetStoppedRuntimeStateLocked(rs)
+		}
+		rs.mu.Unlock()
+		select {
+		case <-stopMetrics:
+		default:
+			close(stopMetrics)
+		}
+		m.forgetServerProcess(id, pid)
+		m.endPlayerSessions(id, time.Now())
+	}()
+}
+
+// tailLogFile copies lines appended to path into w until stop is closed,
+// starting from the current end of the file. It follows the file when the
+// server rotates it.
+func tailLogFile(path string, w *io.PipeWriter, stop <-chan struct{}) {
+	defer w.Close()
+
+	var file *os.File
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+	var offset int64
+	open := func(fromEnd bool) {
+		if file != nil {
+			file.Close()
+			file = nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return
+		}
+		file = f
+		offset = 0
+		if fromEnd {
+			if info, err := f.Stat(); err == nil {
+				offset = info.Size()
+			}
+		}
+	}
+	open(true)
+
+	ticker := time.NewTicker(logTailInterval)
+	defer ticker.Stop()
+	buf := make([]byte, 32*1024)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		current, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if file == nil {
+			open(false)
+			if file == nil {
+				continue
+			}
+		} else if opened, err := file.Stat(); err != nil || !os.SameFile(opened, current) || current.Size() < offset {
+			open(false)
+			if file == nil {
+				continue
+			}
+		}
+
+		for {
+			n, err := file.ReadAt(buf, offset)
+			if n > 0 {
+				if _, werr := w.Write(buf[:n]); werr != nil {
+					return
+				}
+				offset += int64(n)
+			}
+			if err != nil {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+//go:build linux
+
+package minecraft
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewManagerReattachesToRunningServer(t *testing.T) {
+	base := t.TempDir()
+	cmd := exec.Command("sh", "-c", "sleep 120")
+	prepareServerProcessCommand(cmd)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start test process: %v", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+	defer cmd.Process.Kill()
+
+	first, err := NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	dir := filepath.Join(first.serversRoot, "Lobby")
+	if err := os.MkdirAll(filepath.Join(dir, "logs"), 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	logPath := filepath.Join(dir, "logs", "latest.log")
+	if err := os.WriteFile(logPath, []byte("[10:00:00 INFO]: old line\n"), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	first.mu.Lock()
+	first.configs["srv1"] = &ServerConfig{ID: "srv1", Name: "Lobby", Type: "Paper", Port: 25565, Dir: dir}
+	first.running["srv1"] = &runningServer{status: "Stopped"}
+	first.mu.Unlock()
+	first.recordServerProcess("srv1", cmd.Process.Pid)
+	first.StopAll()
+
+	mgr, err := NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+	mgr.mu.RLock()
+	rs := mgr.running["srv1"]
+	mgr.mu.RUnlock()
+	rs.mu.RLock()
+	status, pid := rs.status, rs.pid
+	rs.mu.RUnlock()
+	if status != "Running" || pid != cmd.Process.Pid {
+		t.Fatalf("expected reattached Running server with pid %d, got %s/%d", cmd.Process.Pid, status, pid)
+	}
+
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	f.WriteString("[10:00:05 INFO]: new line\n")
+	f.Close()
+
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	waitFor("tailed log line", func() bool {
+		rs.mu.RLock()
+		defer rs.mu.RUnlock()
+		for _, entry := range rs.logBuffer {
+			if strings.Contains(entry.Line, "old line") {
+				t.Fatalf("expected tailing to start at the end of latest.log")
+			}
+			if strings.Contains(entry.Line, "new line") {
+				return true
+			}
+		}
+		return false
+	})
+
+	cmd.Process.Kill()
+	<-exited
+	waitFor("reattached server to stop", func() bool {
+		rs.mu.RLock()
+		defer rs.mu.RUnlock()
+		return rs.status == "Stopped"
+	})
+	waitFor("recorded PID to be cleared", func() bool {
+		mgr.mu.RLock()
+		defer mgr.mu.RUnlock()
+		return mgr.configs["srv1"].PID == 0
+	})
+}
+
+func TestNewManagerClearsStalePID(t *testing.T) {
+	base := t.TempDir()
+	first, err := NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	dir := filepath.Join(first.serversRoot, "Lobby")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	first.mu.Lock()
+	// Our own PID is alive but started at a different time, as when a PID
+	// is reused.
+	first.configs["srv1"] = &ServerConfig{ID: "srv1", Name: "Lobby", Type: "Paper", Port: 25565, Dir: dir, PID: os.Getpid(), ProcessStartedAt: 1}
+	first.persist()
+	first.mu.Unlock()
+	first.StopAll()
+
+	mgr, err := NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+	info, err := mgr.GetStatus("srv1")
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	if info.Status != "Stopped" {
+		t.Fatalf("expected a reused PID to leave the server Stopped, got %s", info.Status)
+	}
+	mgr.mu.RLock()
+	pid := mgr.configs["srv1"].PID
+	mgr.mu.RUnlock()
+	if pid != 0 {
+		t.Fatalf("expected stale PID to be cleared, got %d", pid)
+	}
+}
+
+func TestShutdownPolicyFromEnv(t *testing.T) {
+	cases := map[string]string{
+		"":               ShutdownPolicyStop,
+		"stop":           ShutdownPolicyStop,
+		" Leave-Running": ShutdownPolicyLeaveRunning,
+		"pause":          ShutdownPolicyStop,
+	}
+	for value, want := range cases {
+		t.Setenv("ADPANEL_SHUTDOWN_POLICY", value)
+		if got := ShutdownPolicyFromEnv(); got != want {
+			t.Fatalf("ShutdownPolicyFromEnv() with %q = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+//go:build linux
+
+package minecraft
+
+import (
+	"os"
+	"syscall"
+)
+
+// ficlone is the FICLONE ioctl, which makes a file share all blocks of
+// another until either is written.
+const ficlone = 0x40049409
+
+// reflinkFile clones src into dst copy-on-write, keeping its permissions
+// and modification time. It fails on filesystems without reflink support
+// and across filesystems, leaving no dst behind.
+func reflinkFile(src, dst string, info os.FileInfo) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, out.Fd(), ficlone, in.Fd()); errno != 0 {
+		out.Close()
+		os.Remove(dst)
+		return errno
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
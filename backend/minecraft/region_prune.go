@@ -0,0 +1,382 @@
+package minecraft
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	maxRegionPruneAgeDays     = 3650
+	maxRegionPruneSpawnRadius = 1000000
+	regionSizeBlocks          = 512
+)
+
+var regionFilePattern = regexp.MustCompile(`^r\.(-?\d+)\.(-?\d+)\.mca$`)
+
+// regionDataDirs hold the per-region files of a dimension. A region is
+// pruned from all of them together so entities and POIs do not outlive
+// their chunks.
+var regionDataDirs = []string{"region", "entities", "poi"}
+
+// RegionPruneSettings controls which region files a prune deletes. Regions
+// that overlap the square of SpawnRadius blocks around CenterX/CenterZ are
+// always kept; the rest are deleted once none of their files changed in
+// MaxAgeDays.
+type RegionPruneSettings struct {
+	MaxAgeDays  int `json:"maxAgeDays"`
+	SpawnRadius int `json:"spawnRadius"`
+	CenterX     int `json:"centerX"`
+	CenterZ     int `json:"centerZ"`
+	// Schedule is one of the backup schedules, or "" for manual prunes only.
+	Schedule string `json:"schedule,omitempty"`
+	LastRun  string `json:"lastRun,omitempty"`
+}
+
+// RegionPruneInfo is the API view of a server's prune settings.
+type RegionPruneInfo struct {
+	RegionPruneSettings
+	NextRun string `json:"nextRun,omitempty"`
+}
+
+// RegionPruneFile is one file a prune deletes.
+type RegionPruneFile struct {
+	Path       string `json:"path"`
+	Size       int64  `json:"size"`
+	ModifiedAt string `json:"modifiedAt"`
+}
+
+// RegionPrunePlan lists what a prune deletes. Previews return it without
+// touching the world.
+type RegionPrunePlan struct {
+	Files          []RegionPruneFile `json:"files"`
+	Regions        int               `json:"regions"`
+	RegionsScanned int               `json:"regionsScanned"`
+	TotalBytes     int64             `json:"totalBytes"`
+	TotalSize      string            `json:"totalSize"`
+}
+
+// RegionPruneResult reports a finished prune and the backup taken first.
+type RegionPruneResult struct {
+	RegionPrunePlan
+	Backup *BackupInfo `json:"backup,omitempty"`
+}
+
+func validateRegionPruneSettings(s RegionPruneSettings) error {
+	if s.MaxAgeDays < 1 || s.MaxAgeDays > maxRegionPruneAgeDays {
+		return fmt.Errorf("maxAgeDays must be between 1 and %d", maxRegionPruneAgeDays)
+	}
+	if s.SpawnRadius < 0 || s.SpawnRadius > maxRegionPruneSpawnRadius {
+		return fmt.Errorf("spawnRadius must be between 0 and %d", maxRegionPruneSpawnRadius)
+	}
+	if !validBackupSchedules[s.Schedule] {
+		return fmt.Errorf("invalid schedule: %s", s.Schedule)
+	}
+	return nil
+}
+
+// regionDimensionDirs returns the dimension folders of the server's world,
+// covering both the vanilla layout and the per-dimension folders used by
+// Bukkit-based servers.
+func regionDimensionDirs(cfg *ServerConfig) []string {
+	level := serverLevelName(cfg)
+	candidates := []string{
+		level,
+		filepath.Join(level, "DIM-1"),
+		filepath.Join(level, "DIM1"),
+		filepath.Join(level+"_nether", "DIM-1"),
+		filepath.Join(level+"_the_end", "DIM1"),
+	}
+	var dirs []string
+	for _, rel := range candidates {
+		dir, err := SafePath(cfg.Dir, rel)
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(dir, "region")); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// regionOverlapsSpawn reports whether region rx, rz overlaps the kept area.
+func regionOverlapsSpawn(rx, rz int, s RegionPruneSettings) bool {
+	minX, minZ := rx*regionSizeBlocks, rz*regionSizeBlocks
+	maxX, maxZ := minX+regionSizeBlocks-1, minZ+regionSizeBlocks-1
+	return minX <= s.CenterX+s.SpawnRadius && maxX >= s.CenterX-s.SpawnRadius &&
+		minZ <= s.CenterZ+s.SpawnRadius && maxZ >= s.CenterZ-s.SpawnRadius
+}
+
+// planRegionPrune finds the region files s selects without deleting them.
+func planRegionPrune(cfg *ServerConfig, s RegionPruneSettings, now time.Time) (RegionPrunePlan, error) {
+	plan := RegionPrunePlan{Files: []RegionPruneFile{}}
+	cutoff := now.Add(-time.Duration(s.MaxAgeDays) * 24 * time.Hour)
+
+	type region struct {
+		rx, rz int
+		newest time.Time
+		files  []RegionPruneFile
+	}
+	for _, dimDir := range regionDimensionDirs(cfg) {
+		regions := make(map[string]*region)
+		for _, sub := range regionDataDirs {
+			entries, err := os.ReadDir(filepath.Join(dimDir, sub))
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return RegionPrunePlan{}, fmt.Errorf("failed to read %s: %w", sub, err)
+			}
+			for _, entry := range entries {
+				match := regionFilePattern.FindStringSubmatch(entry.Name())
+				if match == nil || !entry.Type().IsRegular() {
+					continue
+				}
+				info, err := entry.Info()
+				if err != nil {
+					continue
+				}
+				key := match[1] + "." + match[2]
+				r := regions[key]
+				if r == nil {
+					rx, _ := strconv.Atoi(match[1])
+					rz, _ := strconv.Atoi(match[2])
+					r = &region{rx: rx, rz: rz}
+					regions[key] = r
+				}
+				if info.ModTime().After(r.newest) {
+					r.newest = info.ModTime()
+				}
+				path := filepath.Join(dimDir, sub, entry.Name())
+				rel, err := filepath.Rel(cfg.Dir, path)
+				if err != nil {
+					return RegionPrunePlan{}, err
+				}
+				r.files = append(r.files, RegionPruneFile{
+					Path:       filepath.ToSlash(rel),
+					Size:       info.Size(),
+					ModifiedAt: info.ModTime().UTC().Format(time.RFC3339),
+				})
+			}
+		}
+
+		plan.RegionsScanned += len(regions)
+		for _, r := range regions {
+			if regionOverlapsSpawn(r.rx, r.rz, s) || !r.newest.Before(cutoff) {
+				continue
+			}
+			plan.Regions++
+			for _, f := range r.files {
+				plan.Files = append(plan.Files, f)
+				plan.TotalBytes += f.Size
+			}
+		}
+	}
+	sort.Slice(plan.Files, func(i, j int) bool { return plan.Files[i].Path < plan.Files[j].Path })
+	plan.TotalSize = formatFileSize(plan.TotalBytes)
+	return plan, nil
+}
+
+// GetRegionPrune returns a server's prune settings and its next scheduled run.
+func (m *Manager) GetRegionPrune(id string) (*RegionPruneInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	return regionPruneInfo(cfg), nil
+}
+
+func regionPruneInfo(cfg *ServerConfig) *RegionPruneInfo {
+	info := &RegionPruneInfo{}
+	if cfg.RegionPrune == nil {
+		return info
+	}
+	info.RegionPruneSettings = *cfg.RegionPrune
+	if cfg.RegionPrune.Schedule != "" && cfg.RegionPrune.LastRun != "" {
+		if last, err := time.Parse(time.RFC3339, cfg.RegionPrune.LastRun); err == nil {
+			info.NextRun = nextScheduledBackupTime(last, cfg.RegionPrune.Schedule).UTC().Format(time.RFC3339)
+		}
+	}
+	return info
+}
+
+// SetRegionPrune saves a server's prune settings. Like backup schedules, a
+// new schedule first runs one period after it is set.
+func (m *Manager) SetRegionPrune(id string, s RegionPruneSettings) (*RegionPruneInfo, error) {
+	if err := validateRegionPruneSettings(s); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	s.LastRun = ""
+	if s.Schedule != "" {
+		if cfg.RegionPrune != nil && cfg.RegionPrune.LastRun != "" {
+			s.LastRun = cfg.RegionPrune.LastRun
+		} else {
+			s.LastRun = time.Now().UTC().Format(time.RFC3339)
+		}
+	}
+	cfg.RegionPrune = &s
+	if err := m.persist(); err != nil {
+		return nil, err
+	}
+	return regionPruneInfo(cfg), nil
+}
+
+// PreviewRegionPrune lists the files a prune with s would delete.
+func (m *Manager) PreviewRegionPrune(id string, s RegionPruneSettings) (*RegionPrunePlan, error) {
+	if err := validateRegionPruneSettings(s); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.validateManagedServerDir(cfg.Dir); err != nil {
+		return nil, m.configPathErrorLocked(id, err.Error())
+	}
+	plan, err := planRegionPrune(cfg, s, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// PruneRegions backs up a stopped server and then deletes the region files
+// s selects.
+func (m *Manager) PruneRegions(id string, s RegionPruneSettings) (*RegionPruneResult, error) {
+	if err := validateRegionPruneSettings(s); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	rs, rsOk := m.running[id]
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if !rsOk {
+		return nil, errServerNotFound(id)
+	}
+	if err := m.validateManagedServerDir(cfg.Dir); err != nil {
+		return nil, m.configPathErrorLocked(id, err.Error())
+	}
+
+	job := m.newJob(JobTypeRegionPrune, id)
+	result, err := m.pruneRegionsJob(job, cfg, rs, s)
+	job.finish(err)
+	return result, err
+}
+
+func (m *Manager) pruneRegionsJob(job *jobHandle, cfg *ServerConfig, rs *runningServer, s RegionPruneSettings) (*RegionPruneResult, error) {
+	release, err := m.acquireServerOperation(job.ctx, cfg.ID, operationPrune)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	job.start("Scanning region files")
+
+	rs.mu.RLock()
+	status := rs.status
+	rs.mu.RUnlock()
+	if status != "Stopped" && status != "Crashed" && status != "Error" {
+		return nil, fmt.Errorf("server must be stopped before pruning regions")
+	}
+
+	plan, err := planRegionPrune(cfg, s, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	result := &RegionPruneResult{RegionPrunePlan: plan}
+	if len(plan.Files) == 0 {
+		job.log("No region files matched; nothing to prune")
+		return result, nil
+	}
+
+	job.progress(10, fmt.Sprintf("Backing up before pruning %d regions", plan.Regions))
+	backup, err := m.writeBackupArchive(job, cfg, 10, 60)
+	if err != nil {
+		return nil, fmt.Errorf("pre-prune backup failed: %w", err)
+	}
+	result.Backup = backup
+
+	job.progress(60, fmt.Sprintf("Deleting %d files", len(plan.Files)))
+	for i, f := range plan.Files {
+		if err := job.ctx.Err(); err != nil {
+			return nil, err
+		}
+		path, err := SafePath(cfg.Dir, filepath.FromSlash(f.Path))
+		if err != nil {
+			return nil, err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to delete %s: %w", f.Path, err)
+		}
+		if (i+1)%100 == 0 {
+			job.progress(60+39*(i+1)/len(plan.Files), fmt.Sprintf("Deleted %d of %d files", i+1, len(plan.Files)))
+		}
+	}
+	job.log(fmt.Sprintf("Pruned %d regions, freeing %s", plan.Regions, plan.TotalSize))
+	log.Printf("[%s] Pruned %d regions (%s); backup %s", cfg.Name, plan.Regions, plan.TotalSize, backup.Name)
+	return result, nil
+}
+
+// checkScheduledRegionPrunes runs due scheduled prunes. A server that is
+// running when its prune is due is skipped until the next period.
+func (m *Manager) checkScheduledRegionPrunes() {
+	m.mu.RLock()
+	type pending struct {
+		id       string
+		name     string
+		settings RegionPruneSettings
+	}
+	var due []pending
+	now := time.Now().UTC()
+	for id, cfg := range m.configs {
+		if cfg.RegionPrune == nil || cfg.RegionPrune.Schedule == "" || cfg.RegionPrune.LastRun == "" {
+			continue
+		}
+		last, err := time.Parse(time.RFC3339, cfg.RegionPrune.LastRun)
+		if err != nil {
+			continue
+		}
+		if now.After(nextScheduledBackupTime(last, cfg.RegionPrune.Schedule)) {
+			due = append(due, pending{id: id, name: cfg.Name, settings: *cfg.RegionPrune})
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, p := range due {
+		log.Printf("Running scheduled region prune for server: %s", p.name)
+		result, err := m.PruneRegions(p.id, p.settings)
+		if err != nil {
+			log.Printf("Scheduled region prune skipped for %s: %v", p.name, err)
+		} else {
+			log.Printf("Scheduled region prune completed for %s: %d regions, %s", p.name, result.Regions, result.TotalSize)
+		}
+
+		m.mu.Lock()
+		if cfg, ok := m.configs[p.id]; ok && cfg.RegionPrune != nil {
+			cfg.RegionPrune.LastRun = time.Now().UTC().Format(time.RFC3339)
+			m.persist()
+		}
+		m.mu.Unlock()
+	}
+}
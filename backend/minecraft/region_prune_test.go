@@ -0,0 +1,89 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestRegion(t *testing.T, path string, modified time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create region dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("region"), 0644); err != nil {
+		t.Fatalf("failed to write region: %v", err)
+	}
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatalf("failed to set region time: %v", err)
+	}
+}
+
+func TestPruneRegionsKeepsSpawnAndRecentRegions(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv1", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	rs := &runningServer{status: "Running"}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = rs
+	mgr.mu.Unlock()
+
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	writeTestRegion(t, filepath.Join(cfg.Dir, "world", "region", "r.0.0.mca"), old)
+	writeTestRegion(t, filepath.Join(cfg.Dir, "world", "region", "r.-1.-1.mca"), old)
+	writeTestRegion(t, filepath.Join(cfg.Dir, "world", "region", "r.5.3.mca"), old)
+	writeTestRegion(t, filepath.Join(cfg.Dir, "world", "entities", "r.5.3.mca"), old)
+	writeTestRegion(t, filepath.Join(cfg.Dir, "world", "region", "r.6.3.mca"), old)
+	writeTestRegion(t, filepath.Join(cfg.Dir, "world", "entities", "r.6.3.mca"), time.Now())
+	writeTestRegion(t, filepath.Join(cfg.Dir, "world_nether", "DIM-1", "region", "r.-4.0.mca"), old)
+
+	settings := RegionPruneSettings{MaxAgeDays: 30, SpawnRadius: 256}
+	plan, err := mgr.PreviewRegionPrune(cfg.ID, settings)
+	if err != nil {
+		t.Fatalf("PreviewRegionPrune failed: %v", err)
+	}
+	want := []string{
+		"world/entities/r.5.3.mca",
+		"world/region/r.5.3.mca",
+		"world_nether/DIM-1/region/r.-4.0.mca",
+	}
+	if plan.Regions != 2 || plan.RegionsScanned != 5 || len(plan.Files) != len(want) {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	for i, f := range plan.Files {
+		if f.Path != want[i] {
+			t.Fatalf("file %d = %q, want %q", i, f.Path, want[i])
+		}
+	}
+
+	if _, err := mgr.PruneRegions(cfg.ID, settings); err == nil {
+		t.Fatal("expected prune of a running server to fail")
+	}
+	rs.status = "Stopped"
+	result, err := mgr.PruneRegions(cfg.ID, settings)
+	if err != nil {
+		t.Fatalf("PruneRegions failed: %v", err)
+	}
+	if result.Backup == nil {
+		t.Fatal("expected a backup before pruning")
+	}
+	if _, err := os.Stat(filepath.Join(mgr.backupDir(cfg), result.Backup.Name)); err != nil {
+		t.Fatalf("expected backup archive: %v", err)
+	}
+	for _, f := range want {
+		if _, err := os.Stat(filepath.Join(cfg.Dir, filepath.FromSlash(f))); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be deleted, stat err=%v", f, err)
+		}
+	}
+	for _, f := range []string{"world/region/r.0.0.mca", "world/region/r.-1.-1.mca", "world/region/r.6.3.mca"} {
+		if _, err := os.Stat(filepath.Join(cfg.Dir, filepath.FromSlash(f))); err != nil {
+			t.Fatalf("expected %s to be kept: %v", f, err)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package minecraft
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	defaultVerifyMinTPS = 15
+	// restartVerifyWindow is how long a restarted server is watched after
+	// it reports Running.
+	restartVerifyWindow = 5 * time.Minute
+	restartVerifyPoll   = 10 * time.Second
+)
+
+// What set off a restart verification.
+const (
+	verifyTriggerRestart      = "restart"
+	verifyTriggerCrashRestart = "crash restart"
+	verifyTriggerMaintenance  = "maintenance"
+)
+
+// RestartVerificationSettings has the panel watch a server after every
+// automated restart or update: it must reach Running, keep its TPS at or
+// above MinTPS and write no crash report for five minutes. With Rollback
+// set, a failed check after an update puts the server jar and plugin jars
+// from before the update back and starts the server again. Last is the
+// most recent result.
+type RestartVerificationSettings struct {
+	Enabled  bool                       `json:"enabled"`
+	MinTPS   float64                    `json:"minTps"`
+	Rollback bool                       `json:"rollback"`
+	Last     *RestartVerificationResult `json:"last,omitempty"`
+}
+
+// RestartVerificationResult is the outcome of one check. TPS is the last
+// reading taken, when the server reports one.
+type RestartVerificationResult struct {
+	OK         bool    `json:"ok"`
+	Trigger    string  `json:"trigger"`
+	Message    string  `json:"message"`
+	TPS        float64 `json:"tps,omitempty"`
+	RolledBack bool    `json:"rolledBack,omitempty"`
+	CheckedAt  string  `json:"checkedAt"`
+}
+
+// GetRestartVerification returns a server's restart verification settings.
+func (m *Manager) GetRestartVerification(id string) (*RestartVerificationSettings, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	settings := RestartVerificationSettings{MinTPS: defaultVerifyMinTPS}
+	if cfg.RestartVerification != nil {
+		settings = *cfg.RestartVerification
+	}
+	return &settings, nil
+}
+
+// SetRestartVerification saves whether restarts are verified, the TPS a
+// server must hold and whether failed updates are rolled back.
+func (m *Manager) SetRestartVerification(id string, s RestartVerificationSettings) (*RestartVerificationSettings, error) {
+	if s.MinTPS == 0 {
+		s.MinTPS = defaultVerifyMinTPS
+	}
+	if s.MinTPS < 0 || s.MinTPS > 20 {
+		return nil, fmt.Errorf("minTps must be between 0 and 20")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	s.Last = nil
+	if cfg.RestartVerification != nil {
+		s.Last = cfg.RestartVerification.Last
+	}
+	cfg.RestartVerification = &s
+	if err := m.persist(); err != nil {
+		return nil, err
+	}
+	settings := s
+	return &settings, nil
+}
+
+// restartVerificationEnabled reports whether a server verifies restarts
+// and rolls back failed updates.
+func (m *Manager) restartVerificationEnabled(id string) (enabled, rollback bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if cfg := m.configs[id]; cfg != nil && cfg.RestartVerification != nil {
+		return cfg.RestartVerification.Enabled, cfg.RestartVerification.Enabled && cfg.RestartVerification.Rollback
+	}
+	return false, false
+}
+
+// verifyRestart watches a server the panel just started. It returns nil
+// when the server does not verify restarts. A failed check is logged to
+// the console and, when snap is set and rollback is on, undone. The result
+// is stored on the server either way. Callers hold the server's operation
+// lock, since a rollback stops and starts the server again.
+func (m *Manager) verifyRestart(ctx context.Context, id, trigger string, snap *updateSnapshot) *RestartVerificationResult {
+	m.mu.RLock()
+	cfg := m.configs[id]
+	rs := m.running[id]
+	var settings RestartVerificationSettings
+	var name, dir, serverType string
+	if cfg != nil && cfg.RestartVerification != nil {
+		settings = *cfg.RestartVerification
+		name, dir, serverType = cfg.Name, cfg.Dir, cfg.Type
+	}
+	m.mu.RUnlock()
+	if rs == nil || !settings.Enabled {
+		return nil
+	}
+
+	started := time.Now()
+	result := &RestartVerificationResult{Trigger: trigger}
+	problem := ""
+	if err := waitForServerRunning(ctx, rs, maintenanceStartTimeout); err != nil {
+		problem = err.Error()
+	}
+	_, tpsSupported := tpsCommandForType(serverType)
+	deadline := time.Now().Add(restartVerifyWindow)
+	for problem == "" && time.Now().Before(deadline) {
+		if err := sleepContext(ctx, min(restartVerifyPoll, time.Until(deadline))); err != nil {
+			problem = "verification was cancelled"
+			break
+		}
+		runtime := rs.currentRuntime()
+		if runtime.status != "Running" && runtime.status != "Suspended" {
+			problem = fmt.Sprintf("server went %s while being watched", strings.ToLower(runtime.status))
+		} else if report := newCrashReport(dir, started); report != "" {
+			problem = fmt.Sprintf("server wrote crash report %s", report)
+		} else if tpsSupported && runtime.lastTpsUpdate.After(started) {
+			result.TPS = runtime.tps
+		}
+	}
+	if problem == "" && result.TPS > 0 && result.TPS < settings.MinTPS {
+		problem = fmt.Sprintf("TPS was %.1f, below %.1f", result.TPS, settings.MinTPS)
+	}
+
+	if problem == "" {
+		result.OK = true
+		result.Message = fmt.Sprintf("Server stayed healthy for %s after the %s", restartVerifyWindow, trigger)
+		m.broadcastLog(rs, m.appendLog(rs, "[Panel] Restart verification passed"))
+	} else {
+		result.Message = fmt.Sprintf("Verification after the %s failed: %s", trigger, problem)
+		log.Printf("[%s] %s", name, result.Message)
+		m.broadcastLog(rs, m.appendLog(rs, "[Panel] "+result.Message))
+		if snap != nil && settings.Rollback {
+			if err := m.rollbackUpdate(id, rs, snap); err != nil {
+				result.Message += fmt.Sprintf("; rollback failed: %v", err)
+			} else {
+				result.RolledBack = true
+				result.Message += "; the update was rolled back"
+			}
+			log.Printf("[%s] %s", name, result.Message)
+			m.broadcastLog(rs, m.appendLog(rs, "[Panel] "+result.Message))
+		}
+	}
+	result.CheckedAt = time.Now().UTC().Format(time.RFC3339)
+
+	m.mu.Lock()
+	if cfg, ok := m.configs[id]; ok && cfg.RestartVerification != nil {
+		last := *result
+		cfg.RestartVerification.Last = &last
+		if err := m.persist(); err != nil {
+			log.Printf("[%s] Failed to save restart verification result: %v", cfg.Name, err)
+		}
+	}
+	m.mu.Unlock()
+	return result
+}
+
+// newCrashReport returns the newest crash report written after since, or
+// "" when there is none.
+func newCrashReport(dir string, since time.Time) string {
+	entries, err := os.ReadDir(filepath.Join(dir, "crash-reports"))
+	if err != nil {
+		return ""
+	}
+	newest, newestAt := "", since
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".txt") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && info.ModTime().After(newestAt) {
+			newest, newestAt = entry.Name(), info.ModTime()
+		}
+	}
+	return newest
+}
+
+// updateSnapshot is a server's jars and version from before an update,
+// kept so a failed update can be undone. Only the jars in the server folder
+// and the top of the plugin or mod folder are kept: plugin data and worlds
+// carry on as they are.
+type updateSnapshot struct {
+	dir          string
+	version      string
+	startCommand []string
+}
+
+func (m *Manager) updateRollbackDir(serverID string) string {
+	return filepath.Join(m.baseDir, "data", "update-rollback", sanitizeName(serverID))
+}
+
+// snapshotForUpdate copies a server's jars aside before an update,
+// replacing any earlier copy.
+func (m *Manager) snapshotForUpdate(id string) (*updateSnapshot, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	var serverDir, extDir string
+	snap := &updateSnapshot{dir: m.updateRollbackDir(id)}
+	if err == nil {
+		serverDir, extDir = cfg.Dir, extensionsDir(cfg)
+		snap.version = cfg.Version
+		snap.startCommand = append([]string(nil), cfg.StartCommand...)
+	}
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.RemoveAll(snap.dir); err != nil {
+		return nil, err
+	}
+	if err := copyTopLevelJars(serverDir, filepath.Join(snap.dir, "server")); err != nil {
+		return nil, fmt.Errorf("failed to copy server jars: %w", err)
+	}
+	if err := copyTopLevelJars(extDir, filepath.Join(snap.dir, "extensions")); err != nil {
+		return nil, fmt.Errorf("failed to copy plugin jars: %w", err)
+	}
+	return snap, nil
+}
+
+// rollbackUpdate stops the server if it is up, puts the jars and version
+// from snap back and starts it again. Callers hold the server's operation
+// lock.
+func (m *Manager) rollbackUpdate(id string, rs *runningServer, snap *updateSnapshot) error {
+	rs.mu.Lock()
+	stopCrashRestartLocked(rs)
+	status := rs.status
+	rs.mu.Unlock()
+	if status == "Running" || status == "Booting" || status == "Suspended" {
+		if err := m.StopServer(id); err != nil {
+			return err
+		}
+	}
+
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	var serverDir, extDir string
+	if err == nil {
+		serverDir, extDir = cfg.Dir, extensionsDir(cfg)
+	}
+	m.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if err := replaceTopLevelJars(filepath.Join(snap.dir, "server"), serverDir); err != nil {
+		return fmt.Errorf("failed to restore server jars: %w", err)
+	}
+	if err := replaceTopLevelJars(filepath.Join(snap.dir, "extensions"), extDir); err != nil {
+		return fmt.Errorf("failed to restore plugin jars: %w", err)
+	}
+
+	m.mu.Lock()
+	if cfg, ok := m.configs[id]; ok {
+		cfg.Version = snap.version
+		cfg.StartCommand = append([]string(nil), snap.startCommand...)
+		m.persist()
+	}
+	m.mu.Unlock()
+	return m.StartServer(id)
+}
+
+// isTopLevelJar reports whether a directory entry is a jar, enabled or
+// disabled.
+func isTopLevelJar(entry os.DirEntry) bool {
+	name, _ := splitDisabledSuffix(entry.Name())
+	return entry.Type().IsRegular() && strings.HasSuffix(strings.ToLower(name), ".jar")
+}
+
+// copyTopLevelJars copies the jars directly inside src to dst.
+func copyTopLevelJars(src, dst string) error {
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(src)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !isTopLevelJar(entry) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if err := copyRegularFile(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name()), info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceTopLevelJars removes the jars directly inside dst, including ones
+// an update added under a new name, and copies the jars in src back.
+func replaceTopLevelJars(src, dst string) error {
+	entries, err := os.ReadDir(dst)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, entry := range entries {
+		if isTopLevelJar(entry) {
+			if err := os.Remove(filepath.Join(dst, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return copyTopLevelJars(src, dst)
+}
@@ -0,0 +1,128 @@
+package minecraft
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRestartVerificationSettings(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv", Name: "Survival", Type: "Paper", Version: "1.21", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = &runningServer{status: "Stopped"}
+	mgr.mu.Unlock()
+
+	if settings, err := mgr.GetRestartVerification(cfg.ID); err != nil || settings.Enabled || settings.MinTPS != defaultVerifyMinTPS {
+		t.Fatalf("expected verification off with the default TPS, got %+v (%v)", settings, err)
+	}
+	if _, err := mgr.SetRestartVerification(cfg.ID, RestartVerificationSettings{Enabled: true, MinTPS: 25}); err == nil {
+		t.Fatal("expected a TPS above 20 to be refused")
+	}
+	if _, err := mgr.SetRestartVerification(cfg.ID, RestartVerificationSettings{Enabled: true, Rollback: true}); err != nil {
+		t.Fatalf("SetRestartVerification failed: %v", err)
+	}
+	if enabled, rollback := mgr.restartVerificationEnabled(cfg.ID); !enabled || !rollback {
+		t.Fatalf("expected verification and rollback on, got %v %v", enabled, rollback)
+	}
+	if result := mgr.verifyRestart(context.Background(), "missing", verifyTriggerRestart, nil); result != nil {
+		t.Fatalf("expected no check for an unknown server, got %+v", result)
+	}
+}
+
+func TestUpdateSnapshotPutsOldJarsBack(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv", Name: "Survival", Type: "Paper", Version: "1.21", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	plugins := filepath.Join(cfg.Dir, "plugins")
+	if err := os.MkdirAll(plugins, 0755); err != nil {
+		t.Fatalf("failed to create plugins dir: %v", err)
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+	write(filepath.Join(cfg.Dir, "server.jar"), "old server")
+	write(filepath.Join(plugins, "Essentials-2.20.jar"), "old plugin")
+	write(filepath.Join(plugins, "Off.jar.disabled"), "disabled plugin")
+	write(filepath.Join(plugins, "config.yml"), "kept")
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.mu.Unlock()
+
+	snap, err := mgr.snapshotForUpdate(cfg.ID)
+	if err != nil {
+		t.Fatalf("snapshotForUpdate failed: %v", err)
+	}
+
+	write(filepath.Join(cfg.Dir, "server.jar"), "new server")
+	if err := os.Remove(filepath.Join(plugins, "Essentials-2.20.jar")); err != nil {
+		t.Fatalf("failed to remove plugin: %v", err)
+	}
+	write(filepath.Join(plugins, "Essentials-2.21.jar"), "new plugin")
+
+	if err := replaceTopLevelJars(filepath.Join(snap.dir, "server"), cfg.Dir); err != nil {
+		t.Fatalf("failed to restore server jars: %v", err)
+	}
+	if err := replaceTopLevelJars(filepath.Join(snap.dir, "extensions"), plugins); err != nil {
+		t.Fatalf("failed to restore plugin jars: %v", err)
+	}
+
+	for path, want := range map[string]string{
+		filepath.Join(cfg.Dir, "server.jar"):          "old server",
+		filepath.Join(plugins, "Essentials-2.20.jar"): "old plugin",
+		filepath.Join(plugins, "Off.jar.disabled"):    "disabled plugin",
+		filepath.Join(plugins, "config.yml"):          "kept",
+	} {
+		if data, err := os.ReadFile(path); err != nil || string(data) != want {
+			t.Fatalf("expected %s to hold %q, got %q (%v)", path, want, data, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(plugins, "Essentials-2.21.jar")); !os.IsNotExist(err) {
+		t.Fatalf("expected the updated plugin to be removed, got %v", err)
+	}
+}
+
+func TestNewCrashReportIgnoresOlderReports(t *testing.T) {
+	dir := t.TempDir()
+	reports := filepath.Join(dir, "crash-reports")
+	if err := os.MkdirAll(reports, 0755); err != nil {
+		t.Fatalf("failed to create crash-reports: %v", err)
+	}
+	since := time.Now()
+	old := filepath.Join(reports, "crash-old.txt")
+	if err := os.WriteFile(old, []byte("old"), 0644); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+	if err := os.Chtimes(old, since.Add(-time.Hour), since.Add(-time.Hour)); err != nil {
+		t.Fatalf("failed to age report: %v", err)
+	}
+	if got := newCrashReport(dir, since); got != "" {
+		t.Fatalf("expected no new report, got %q", got)
+	}
+
+	fresh := filepath.Join(reports, "crash-new.txt")
+	if err := os.WriteFile(fresh, []byte("new"), 0644); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+	if err := os.Chtimes(fresh, since.Add(time.Second), since.Add(time.Second)); err != nil {
+		t.Fatalf("failed to date report: %v", err)
+	}
+	if got := newCrashReport(dir, since); got != "crash-new.txt" {
+		t.Fatalf("expected crash-new.txt, got %q", got)
+	}
+}
@@ -0,0 +1,202 @@
+package minecraft
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// RestoreAsNewOptions names the backup to restore and the new server's
+// name and port. An empty name or zero port is picked automatically.
+type RestoreAsNewOptions struct {
+	SourceID string `json:"sourceId"`
+	Backup   string `json:"backup"`
+	Name     string `json:"name"`
+	Port     int    `json:"port"`
+}
+
+// RestoreBackupAsNew creates a new server from one of another server's
+// backups, leaving the original untouched. The copy gets its own name and
+// port, RCON is turned off in it so it cannot clash with the original, and
+// it does not start automatically.
+func (m *Manager) RestoreBackupAsNew(opts RestoreAsNewOptions) (*ServerInfo, error) {
+	sourceCfg, backupPath, incremental, err := m.restoreAsNewSource(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	job := m.newJob(JobTypeRestoreAsNew, sourceCfg.ID)
+	info, err := m.restoreBackupAsNewJob(job, sourceCfg, backupPath, incremental, opts)
+	job.finish(err)
+	return info, err
+}
+
+// StartRestoreBackupAsNew queues RestoreBackupAsNew and returns its job
+// right away. The job carries the new server as its result.
+func (m *Manager) StartRestoreBackupAsNew(opts RestoreAsNewOptions) (*Job, error) {
+	sourceCfg, backupPath, incremental, err := m.restoreAsNewSource(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	job := m.newJob(JobTypeRestoreAsNew, sourceCfg.ID)
+	go func() {
+		_, err := m.restoreBackupAsNewJob(job, sourceCfg, backupPath, incremental, opts)
+		if err != nil {
+			log.Printf("[%s] Restore as new server failed: %v", sourceCfg.Name, err)
+		}
+		job.finish(err)
+	}()
+	return m.GetJob(job.id)
+}
+
+// restoreAsNewSource checks a restore-as-new request and resolves the
+// backup it names.
+func (m *Manager) restoreAsNewSource(opts RestoreAsNewOptions) (*ServerConfig, string, bool, error) {
+	m.mu.RLock()
+	sourceCfg, err := m.serverConfigForOperationLocked(opts.SourceID)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, "", false, err
+	}
+	if opts.Port != 0 && (opts.Port < 1024 || opts.Port > 65535) {
+		return nil, "", false, errPortOutOfRange()
+	}
+	backupPath, incremental, err := m.resolveBackup(sourceCfg, opts.Backup)
+	if err != nil {
+		return nil, "", false, err
+	}
+	return sourceCfg, backupPath, incremental, nil
+}
+
+func (m *Manager) restoreBackupAsNewJob(job *jobHandle, sourceCfg *ServerConfig, backupPath string, incremental bool, opts RestoreAsNewOptions) (*ServerInfo, error) {
+	job.start(fmt.Sprintf("Restoring %s as a new server", opts.Backup))
+	baseName := strings.TrimSpace(opts.Name)
+	if baseName == "" {
+		baseName = sourceCfg.Name + " (restored)"
+	}
+	defaults := m.GetSettings()
+
+	m.mu.Lock()
+	name := m.resolveImportedServerNameLocked(baseName)
+	port := opts.Port
+	if port == 0 {
+		free, err := m.freePortInRangeLocked(defaults.DefaultPortRangeStart, defaults.DefaultPortRangeEnd)
+		if err != nil {
+			m.mu.Unlock()
+			return nil, err
+		}
+		port = free
+	}
+	for _, cfg := range m.configs {
+		if cfg.Port == port {
+			m.mu.Unlock()
+			return nil, errPortTaken(port, cfg.Name)
+		}
+	}
+	id := uuid.New().String()[:8]
+	dirName := sanitizeName(name)
+	serverDir := filepath.Join(m.serversRoot, dirName)
+	if _, err := os.Stat(serverDir); err == nil {
+		serverDir = filepath.Join(m.serversRoot, dirName+"_"+id)
+	}
+	serverDir = filepath.Clean(serverDir)
+	if err := m.validateManagedServerDir(serverDir); err != nil {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("invalid server directory: %w", err)
+	}
+	// Creating the directory under the lock reserves it against servers
+	// created while the archive is extracted.
+	if err := os.MkdirAll(serverDir, 0755); err != nil {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("failed to create server directory: %w", err)
+	}
+	m.mu.Unlock()
+
+	cleanup := func() {
+		if err := os.RemoveAll(serverDir); err != nil {
+			log.Printf("Failed to remove partial restore %s: %v", serverDir, err)
+		}
+	}
+
+	job.progress(10, "Extracting backup")
+	if err := extractBackup(job.ctx, backupPath, incremental, serverDir); err != nil {
+		cleanup()
+		return nil, err
+	}
+	job.progress(80, "Configuring the new server")
+
+	if isProxyType(sourceCfg.Type) {
+		velocityPath := filepath.Join(serverDir, "velocity.toml")
+		if _, err := os.Stat(velocityPath); err == nil {
+			if err := updateVelocityToml(velocityPath, sourceCfg.MaxPlayers, port); err != nil {
+				cleanup()
+				return nil, fmt.Errorf("failed to update velocity.toml: %w", err)
+			}
+		}
+	} else {
+		propsPath := filepath.Join(serverDir, "server.properties")
+		if err := setServerProperties(propsPath, map[string]string{
+			"server-port": fmt.Sprint(port),
+			"enable-rcon": "false",
+		}); err != nil {
+			cleanup()
+			return nil, fmt.Errorf("failed to update server.properties: %w", err)
+		}
+	}
+
+	cfg := &ServerConfig{
+		ID:                  id,
+		Name:                name,
+		Type:                sourceCfg.Type,
+		Version:             sourceCfg.Version,
+		Port:                port,
+		JarFile:             sourceCfg.JarFile,
+		MaxRAM:              sourceCfg.MaxRAM,
+		MinRAM:              sourceCfg.MinRAM,
+		MaxPlayers:          sourceCfg.MaxPlayers,
+		Dir:                 serverDir,
+		StartCommand:        append([]string(nil), sourceCfg.StartCommand...),
+		Flags:               sourceCfg.Flags,
+		AlwaysPreTouch:      sourceCfg.AlwaysPreTouch,
+		PluginUpdateChannel: sourceCfg.PluginUpdateChannel,
+		Groups:              append([]string(nil), sourceCfg.Groups...),
+	}
+
+	m.mu.Lock()
+	for _, other := range m.configs {
+		if other.Port == port {
+			m.mu.Unlock()
+			cleanup()
+			return nil, errPortTaken(port, other.Name)
+		}
+	}
+	cfg.Name = m.resolveImportedServerNameLocked(name)
+	m.configs[id] = cfg
+	m.assignNewServerOrderLocked(cfg)
+	m.running[id] = &runningServer{
+		status:      "Stopped",
+		logBuffer:   make([]ConsoleLogEntry, 0),
+		nextLogSeq:  1,
+		players:     make(map[string]*onlinePlayer),
+		pingBlocked: make(map[string]bool),
+	}
+	if err := m.persist(); err != nil {
+		delete(m.configs, id)
+		delete(m.running, id)
+		m.mu.Unlock()
+		cleanup()
+		return nil, err
+	}
+	info := m.serverInfo(id)
+	m.mu.Unlock()
+
+	job.log(fmt.Sprintf("Created server %s on port %d", cfg.Name, port))
+	job.setResult(info)
+	log.Printf("Restored backup %s of %s as new server %s", opts.Backup, sourceCfg.Name, cfg.Name)
+	return info, nil
+}
@@ -0,0 +1,78 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRestoreBackupAsNewCreatesIndependentServer(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "prod", Name: "Production", Type: "Paper", Version: "1.21.1", Port: 25565, JarFile: "server.jar", Dir: filepath.Join(mgr.serversRoot, "Production"), RCON: &RCONConfig{Port: 25575}}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = &runningServer{status: "Running"}
+	mgr.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Join(cfg.Dir, "world"), 0755); err != nil {
+		t.Fatalf("failed to create world: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Dir, "world", "level.dat"), []byte("level"), 0644); err != nil {
+		t.Fatalf("failed to write level.dat: %v", err)
+	}
+	props := "server-port=25565\nenable-rcon=true\nrcon.port=25575\nmotd=Prod\n"
+	if err := os.WriteFile(filepath.Join(cfg.Dir, "server.properties"), []byte(props), 0644); err != nil {
+		t.Fatalf("failed to write server.properties: %v", err)
+	}
+
+	backup, err := mgr.CreateBackup(cfg.ID)
+	if err != nil {
+		t.Fatalf("CreateBackup failed: %v", err)
+	}
+
+	if _, err := mgr.RestoreBackupAsNew(RestoreAsNewOptions{SourceID: cfg.ID, Backup: backup.Name, Port: 25565}); err == nil {
+		t.Fatalf("expected the original's port to be refused")
+	}
+	if _, err := mgr.RestoreBackupAsNew(RestoreAsNewOptions{SourceID: cfg.ID, Backup: "../missing.tar.gz"}); err == nil {
+		t.Fatalf("expected an unknown backup to be refused")
+	}
+
+	info, err := mgr.RestoreBackupAsNew(RestoreAsNewOptions{SourceID: cfg.ID, Backup: backup.Name, Port: 25600})
+	if err != nil {
+		t.Fatalf("RestoreBackupAsNew failed: %v", err)
+	}
+	if info.ID == cfg.ID || info.Name != "Production (restored)" || info.Port != 25600 || info.Status != "Stopped" || info.RCONPort != 0 {
+		t.Fatalf("unexpected new server: %+v", info)
+	}
+
+	mgr.mu.RLock()
+	newCfg := mgr.configs[info.ID]
+	mgr.mu.RUnlock()
+	if newCfg.Dir == cfg.Dir || newCfg.JarFile != "server.jar" || newCfg.Version != "1.21.1" {
+		t.Fatalf("unexpected new config: %+v", newCfg)
+	}
+	if data, err := os.ReadFile(filepath.Join(newCfg.Dir, "world", "level.dat")); err != nil || string(data) != "level" {
+		t.Fatalf("expected the world to be restored, got %q (%v)", data, err)
+	}
+	newProps := parseServerPropertiesFile(filepath.Join(newCfg.Dir, "server.properties"))
+	if newProps["server-port"] != "25600" || newProps["enable-rcon"] != "false" || newProps["motd"] != "Prod" {
+		t.Fatalf("unexpected server.properties: %v", newProps)
+	}
+	if original := parseServerPropertiesFile(filepath.Join(cfg.Dir, "server.properties")); original["server-port"] != "25565" || original["enable-rcon"] != "true" {
+		t.Fatalf("expected the original to be untouched, got %v", original)
+	}
+
+	// A second copy gets a fresh name.
+	second, err := mgr.RestoreBackupAsNew(RestoreAsNewOptions{SourceID: cfg.ID, Backup: backup.Name})
+	if err != nil {
+		t.Fatalf("second RestoreBackupAsNew failed: %v", err)
+	}
+	if second.Name != "Production (restored)-2" || second.Port == 25565 || second.Port == 25600 {
+		t.Fatalf("unexpected second copy: %+v", second)
+	}
+}
@@ -0,0 +1,58 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStartServerSafeModeSelectiveValidatesAndRestores(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv1", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	pluginsDir := filepath.Join(cfg.Dir, "plugins")
+	if err := os.MkdirAll(pluginsDir, 0755); err != nil {
+		t.Fatalf("failed to create plugins dir: %v", err)
+	}
+	for _, name := range []string{"A.jar", "B.jar", "C.jar.disabled"} {
+		if err := os.WriteFile(filepath.Join(pluginsDir, name), []byte("jar"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	// A server that is still installing refuses to start, which exercises
+	// the rollback after the selected files have been renamed.
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = &runningServer{status: "Installing"}
+	mgr.mu.Unlock()
+
+	invalid := [][]ExtensionRef{
+		nil,
+		{{FileName: "C.jar.disabled"}},
+		{{FileName: "Missing.jar"}},
+		{{FileName: "../server.jar"}},
+		{{Directory: "datapacks", FileName: "A.jar"}},
+	}
+	for _, selection := range invalid {
+		if err := mgr.StartServerSafeModeSelective(cfg.ID, selection); err == nil {
+			t.Fatalf("expected selection %+v to be rejected", selection)
+		}
+	}
+
+	err = mgr.StartServerSafeModeSelective(cfg.ID, []ExtensionRef{{FileName: "A.jar"}, {Directory: "plugins", FileName: "B.jar"}})
+	if err == nil {
+		t.Fatalf("expected start to fail while installing")
+	}
+	for _, name := range []string{"A.jar", "B.jar", "C.jar.disabled"} {
+		if _, err := os.Stat(filepath.Join(pluginsDir, name)); err != nil {
+			t.Fatalf("expected %s to be restored after the failed start: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(pluginsDir, "A.jar.disabled")); !os.IsNotExist(err) {
+		t.Fatalf("expected no leftover A.jar.disabled, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package minecraft
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNormalizeRestartWarningMinutes(t *testing.T) {
+	got := normalizeRestartWarningMinutes([]int{1, 10, 0, 5, 10, -3, 5000})
+	want := []int{10, 5, 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRestartWarningsCountDownToRestartTime(t *testing.T) {
+	got := restartWarnings(3*time.Minute, []int{10, 5, 2, 1})
+	want := []restartWarning{
+		{after: time.Minute, seconds: 120},
+		{after: 2 * time.Minute, seconds: 60},
+		{after: 3*time.Minute - 10*time.Second, seconds: 10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	got = restartWarnings(4*time.Second, []int{1})
+	want = []restartWarning{{after: 0, seconds: 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got := restartWarnings(0, []int{1}); len(got) != 0 {
+		t.Fatalf("expected no warnings for an immediate restart, got %v", got)
+	}
+}
+
+func TestServerInfoExposesRestartAt(t *testing.T) {
+	const id = "srv1"
+	restartAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	mgr := buildTestManagerForKill(t, id, &runningServer{
+		status:    "Running",
+		restartAt: restartAt,
+	})
+
+	info := mgr.serverInfo(id)
+	if info.RestartAt != "2030-01-02T03:04:05Z" {
+		t.Fatalf("expected restartAt to be exposed, got %q", info.RestartAt)
+	}
+}
+
+func TestResumePersistedRestartDropsElapsedSchedule(t *testing.T) {
+	const id = "srv1"
+	rs := &runningServer{status: "Running"}
+	mgr := buildTestManagerForKill(t, id, rs)
+	mgr.dataFile = filepath.Join(t.TempDir(), "servers.json")
+	mgr.configs[id].ScheduledRestartAt = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+
+	mgr.resumePersistedRestart(id)
+
+	if mgr.configs[id].ScheduledRestartAt != "" {
+		t.Fatalf("expected elapsed schedule to be cleared, got %q", mgr.configs[id].ScheduledRestartAt)
+	}
+	if rs.restartTimer != nil {
+		t.Fatal("expected no restart timer for an elapsed schedule")
+	}
+}
+
+func TestCancelRestartAbortsRestartWaitingForLock(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	const id = "srv"
+	rs := &runningServer{status: "Running"}
+	mgr.mu.Lock()
+	mgr.configs[id] = &ServerConfig{ID: id, Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	mgr.running[id] = rs
+	mgr.mu.Unlock()
+
+	release, err := mgr.acquireServerOperation(context.Background(), id, operationBackup)
+	if err != nil {
+		t.Fatalf("acquireServerOperation failed: %v", err)
+	}
+	defer release()
+	if err := mgr.ScheduleRestart(id, 0, ""); err != nil {
+		t.Fatalf("ScheduleRestart failed: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rs.mu.RLock()
+		queued := len(rs.queuedRestarts)
+		rs.mu.RUnlock()
+		if queued == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the restart did not queue behind the running operation")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := mgr.CancelRestart(id); err != nil {
+		t.Fatalf("CancelRestart failed: %v", err)
+	}
+	for {
+		jobs := mgr.ListJobs(id, JobStateCancelled)
+		if len(jobs) == 1 && jobs[0].Type == JobTypeRestart {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the queued restart to be cancelled, got %+v", mgr.ListJobs(id, ""))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status := rs.currentRuntime().status; status != "Running" {
+		t.Fatalf("expected the server to be left running, got %s", status)
+	}
+	if err := mgr.CancelRestart(id); err == nil {
+		t.Fatal("expected nothing left to cancel")
+	}
+}
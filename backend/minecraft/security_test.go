@@ -109,7 +109,7 @@ func TestUpdateAppSettingsEnforcesPasswordLength(t *testing.T) {
 	}
 	defer mgr.StopAll()
 
-	_, err = mgr.UpdateAppSettings("", "0.5", "1", "none", 3, 30, 15, 20, "adminuser", "short")
+	_, _, err = mgr.UpdateAppSettings(AppSettingsUpdate{DefaultMinRAM: "0.5", DefaultMaxRAM: "1", DefaultFlags: "none", LoginUser: "adminuser", LoginPassword: "short"})
 	if err == nil {
 		t.Fatalf("expected short password to be rejected")
 	}
@@ -0,0 +1,110 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ServerInstall describes where and how a server is installed, for
+// debugging installs. Only admins see it.
+type ServerInstall struct {
+	Dir          string   `json:"dir"`
+	JarFile      string   `json:"jarFile,omitempty"`
+	JarPresent   bool     `json:"jarPresent"`
+	JarSize      int64    `json:"jarSize,omitempty"`
+	StartCommand []string `json:"startCommand,omitempty"`
+	// RunScript is true when the directory has the run.sh a Forge or
+	// NeoForge installer writes.
+	RunScript bool `json:"runScript"`
+	// UserJVMArgs is true when user_jvm_args.txt exists, and
+	// UserJVMArgsManaged when the panel keeps the flag preset in it.
+	UserJVMArgs        bool `json:"userJvmArgs"`
+	UserJVMArgsManaged bool `json:"userJvmArgsManaged"`
+}
+
+// ServerDetail is the full view of one server: its runtime state plus the
+// configuration the listing leaves out, and the jobs still queued or
+// running for it.
+type ServerDetail struct {
+	ServerInfo
+	Install                *ServerInstall       `json:"install,omitempty"`
+	BackupSchedule         string               `json:"backupSchedule,omitempty"`
+	BackupMode             string               `json:"backupMode,omitempty"`
+	LastScheduledBackup    string               `json:"lastScheduledBackup,omitempty"`
+	BackupTargets          []string             `json:"backupTargets,omitempty"`
+	ScheduledRestartReason string               `json:"scheduledRestartReason,omitempty"`
+	RegionPrune            *RegionPruneSettings `json:"regionPrune,omitempty"`
+	WhitelistSchedule      *WhitelistSchedule   `json:"whitelistSchedule,omitempty"`
+	TempBans               []TempBan            `json:"tempBans,omitempty"`
+	JoinCheckEnabled       bool                 `json:"joinCheckEnabled"`
+	JoinCheckPlayer        string               `json:"joinCheckPlayer,omitempty"`
+	RCONEnabled            bool                 `json:"rconEnabled"`
+	PendingJobs            []Job                `json:"pendingJobs"`
+}
+
+// GetServerDetail returns the single-server resource. Secrets such as the
+// RCON password are left out, and Install is only filled in for admins.
+func (m *Manager) GetServerDetail(id string, withInstall bool) (*ServerDetail, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		m.mu.RUnlock()
+		return nil, err
+	}
+	snapshot := *cfg
+	rs := m.running[id]
+	detail := &ServerDetail{
+		BackupSchedule:         cfg.BackupSchedule,
+		BackupMode:             cfg.BackupMode,
+		LastScheduledBackup:    cfg.LastScheduledBackup,
+		BackupTargets:          append([]string(nil), cfg.BackupTargets...),
+		ScheduledRestartReason: cfg.ScheduledRestartReason,
+		TempBans:               append([]TempBan(nil), cfg.TempBans...),
+		RCONEnabled:            cfg.RCON != nil,
+	}
+	if cfg.RegionPrune != nil {
+		prune := *cfg.RegionPrune
+		detail.RegionPrune = &prune
+	}
+	if cfg.WhitelistSchedule != nil {
+		schedule := *cfg.WhitelistSchedule
+		schedule.Windows = append([]WhitelistWindow(nil), cfg.WhitelistSchedule.Windows...)
+		detail.WhitelistSchedule = &schedule
+	}
+	if cfg.JoinCheck != nil {
+		detail.JoinCheckEnabled = cfg.JoinCheck.Enabled
+		detail.JoinCheckPlayer = cfg.JoinCheck.Player
+	}
+	m.mu.RUnlock()
+
+	detail.ServerInfo = *m.buildServerInfo(&snapshot, rs)
+	if withInstall {
+		detail.Install = inspectServerInstall(&snapshot)
+	}
+	detail.PendingJobs = append(m.ListJobs(id, JobStateRunning), m.ListJobs(id, JobStateQueued)...)
+	return detail, nil
+}
+
+// inspectServerInstall reports the launch files found in a server's
+// directory.
+func inspectServerInstall(cfg *ServerConfig) *ServerInstall {
+	install := &ServerInstall{
+		Dir:          cfg.Dir,
+		JarFile:      cfg.JarFile,
+		StartCommand: append([]string(nil), cfg.StartCommand...),
+	}
+	if cfg.JarFile != "" {
+		if info, err := os.Stat(filepath.Join(cfg.Dir, cfg.JarFile)); err == nil && info.Mode().IsRegular() {
+			install.JarPresent = true
+			install.JarSize = info.Size()
+		}
+	}
+	if info, err := os.Stat(filepath.Join(cfg.Dir, "run.sh")); err == nil && info.Mode().IsRegular() {
+		install.RunScript = true
+	}
+	if data, err := os.ReadFile(filepath.Join(cfg.Dir, "user_jvm_args.txt")); err == nil {
+		install.UserJVMArgs = true
+		install.UserJVMArgsManaged = userJVMArgsManaged(string(data))
+	}
+	return install
+}
@@ -0,0 +1,75 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetServerDetailIncludesConfigAndPendingJobs(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	mgr.mu.Lock()
+	mgr.configs["srv"] = &ServerConfig{
+		ID:             "srv",
+		Name:           "Survival",
+		Type:           "Paper",
+		Dir:            filepath.Join(mgr.serversRoot, "Survival"),
+		JarFile:        "server.jar",
+		BackupSchedule: "daily",
+		RCON:           &RCONConfig{Port: 25575, Password: "hunter2"},
+		JoinCheck:      &JoinCheckSettings{Enabled: true, Player: "PanelCheck"},
+	}
+	mgr.mu.Unlock()
+	dir := filepath.Join(mgr.serversRoot, "Survival")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create server dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "server.jar"), []byte("jar"), 0644); err != nil {
+		t.Fatalf("failed to write jar: %v", err)
+	}
+	if err := writeManagedUserJVMArgs(filepath.Join(dir, "user_jvm_args.txt"), []string{"-XX:+UseG1GC"}); err != nil {
+		t.Fatalf("failed to write user_jvm_args.txt: %v", err)
+	}
+	job := mgr.newJob(JobTypeBackup, "srv")
+	done := mgr.newJob(JobTypeBackup, "srv")
+	done.finish(nil)
+
+	detail, err := mgr.GetServerDetail("srv", true)
+	if err != nil {
+		t.Fatalf("GetServerDetail failed: %v", err)
+	}
+	if detail.Name != "Survival" || detail.Status != "Stopped" || detail.BackupSchedule != "daily" {
+		t.Fatalf("unexpected detail %+v", detail)
+	}
+	install := detail.Install
+	if install == nil || install.Dir != dir || !install.JarPresent || install.JarSize != 3 || install.RunScript || !install.UserJVMArgs || !install.UserJVMArgsManaged {
+		t.Fatalf("unexpected install info %+v", install)
+	}
+	if !detail.RCONEnabled || !detail.JoinCheckEnabled || detail.RCONPort != 25575 {
+		t.Fatalf("expected RCON and join check settings, got %+v", detail)
+	}
+	if len(detail.PendingJobs) != 1 || detail.PendingJobs[0].ID != job.id {
+		t.Fatalf("expected only the queued job to be pending, got %+v", detail.PendingJobs)
+	}
+	data, err := json.Marshal(detail)
+	if err != nil {
+		t.Fatalf("failed to encode detail: %v", err)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Fatalf("detail leaks the RCON password: %s", data)
+	}
+
+	if detail, err := mgr.GetServerDetail("srv", false); err != nil || detail.Install != nil {
+		t.Fatalf("expected install info to be left out, got %+v (%v)", detail, err)
+	}
+	if _, err := mgr.GetServerDetail("missing", true); err == nil {
+		t.Fatalf("expected an unknown server to be refused")
+	}
+}
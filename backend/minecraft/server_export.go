@@ -0,0 +1,351 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// serverExportFormat is the version of the portable archive layout:
+	// manifest.json next to a server/ folder with the server's files.
+	serverExportFormat   = 1
+	serverExportManifest = "manifest.json"
+	serverExportDir      = "server"
+	serverExportSuffix   = ".adpanel.tar.gz"
+	// serverExportTTL is how long a finished export stays downloadable.
+	serverExportTTL = 24 * time.Hour
+)
+
+// ServerExportManifest describes the server in a portable archive. Server
+// holds its settings with everything tied to this panel cleared: its ID,
+// folder, process, backup uploads, network and webhooks.
+type ServerExportManifest struct {
+	Format     int          `json:"format"`
+	ExportedAt string       `json:"exportedAt"`
+	Server     ServerConfig `json:"server"`
+}
+
+// ServerExportResult is the result of an export job: the archive to
+// download from GET /api/servers/{id}/export/{file}.
+type ServerExportResult struct {
+	File string `json:"file"`
+	Size string `json:"size"`
+}
+
+// ServerArchiveImportOptions overrides the name and port of a server
+// imported from a portable archive. Empty values keep the exported ones,
+// moving a taken port to the next free one.
+type ServerArchiveImportOptions struct {
+	Name string
+	Port int
+}
+
+func (m *Manager) exportsDir() string {
+	return filepath.Join(m.importsRoot, "exports")
+}
+
+// portableServerConfig returns a copy of cfg that can be recreated on
+// another panel.
+func portableServerConfig(cfg *ServerConfig) ServerConfig {
+	out := *cfg
+	out.ID, out.Dir, out.Order = "", "", 0
+	out.PID, out.ProcessStartedAt = 0, 0
+	out.LastScheduledBackup = ""
+	out.ScheduledRestartAt, out.ScheduledRestartReason = "", ""
+	out.BackupTargets, out.BackupUploads = nil, nil
+	out.Network = nil
+	out.Webhooks = nil
+	out.Groups = nil
+	out.StartCommand = append([]string(nil), cfg.StartCommand...)
+	out.ReadyCommands = append([]string(nil), cfg.ReadyCommands...)
+	out.TempBans = append([]TempBan(nil), cfg.TempBans...)
+	out.Tasks = nil
+	for _, task := range cfg.Tasks {
+		// A staging refresh names a server on this panel.
+		if task.Action == TaskRefreshStaging {
+			continue
+		}
+		task.NextRun, task.LastRun, task.LastResult = "", "", ""
+		out.Tasks = append(out.Tasks, task)
+	}
+	return out
+}
+
+// StartServerExport writes a server's folder and settings into one archive
+// that ImportServerArchive recreates on another panel, and returns the job
+// doing it. Symlinks are left out, since the importer refuses them.
+func (m *Manager) StartServerExport(id string) (*Job, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err == nil {
+		if dirErr := m.validateManagedServerDir(cfg.Dir); dirErr != nil {
+			err = m.configPathErrorLocked(id, dirErr.Error())
+		}
+	}
+	var manifest []byte
+	if err == nil {
+		// The manifest is encoded under the lock, since the copy shares the
+		// config's nested settings.
+		manifest, err = json.MarshalIndent(ServerExportManifest{
+			Format:     serverExportFormat,
+			ExportedAt: time.Now().UTC().Format(time.RFC3339),
+			Server:     portableServerConfig(cfg),
+		}, "", "  ")
+	}
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	job := m.newJob(JobTypeExport, id)
+	go func() {
+		err := m.serverExportJob(job, cfg, manifest)
+		if err != nil {
+			log.Printf("[%s] Export failed: %v", cfg.Name, err)
+		}
+		job.finish(err)
+	}()
+	return m.GetJob(job.id)
+}
+
+func (m *Manager) serverExportJob(job *jobHandle, cfg *ServerConfig, manifest []byte) error {
+	release, err := m.acquireServerOperation(job.ctx, cfg.ID, operationBackup)
+	if err != nil {
+		return err
+	}
+	defer release()
+	job.start(fmt.Sprintf("Exporting %s", cfg.Name))
+
+	dir := filepath.Join(m.exportsDir(), cfg.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s_%s%s", sanitizeName(cfg.Name), time.Now().Format("2006-01-02_15-04-05"), serverExportSuffix)
+	dest := filepath.Join(dir, name)
+	opts := tarGzOptions{
+		prefix:       serverExportDir + "/",
+		extra:        []tarFile{{name: serverExportManifest, data: manifest}},
+		skipSymlinks: true,
+	}
+	err = writeTarGzWith(job.ctx, cfg.Dir, dest, opts, func(done, total int64) {
+		job.transfer(done, total, 0, 99)
+	})
+	if err != nil {
+		os.Remove(dest)
+		return err
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		return err
+	}
+	result := &ServerExportResult{File: name, Size: formatFileSize(info.Size())}
+	job.log(fmt.Sprintf("Wrote %s (%s); it can be downloaded for %s", name, result.Size, serverExportTTL))
+	job.setResult(result)
+	log.Printf("[%s] Exported to %s", cfg.Name, name)
+	return nil
+}
+
+// ServerExportPath returns the path of a finished export of server id.
+func (m *Manager) ServerExportPath(id, fileName string) (string, error) {
+	m.mu.RLock()
+	_, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(fileName, serverExportSuffix) {
+		return "", fmt.Errorf("export %s not found", fileName)
+	}
+	path, err := SafePath(filepath.Join(m.exportsDir(), id), fileName)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return "", fmt.Errorf("export %s not found", fileName)
+	}
+	return path, nil
+}
+
+// cleanupExpiredExports removes exports older than serverExportTTL.
+func (m *Manager) cleanupExpiredExports() {
+	dirs, err := os.ReadDir(m.exportsDir())
+	if err != nil {
+		return
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		path := filepath.Join(m.exportsDir(), dir.Name())
+		files, _ := os.ReadDir(path)
+		for _, file := range files {
+			if info, err := file.Info(); err == nil && time.Since(info.ModTime()) > serverExportTTL {
+				os.Remove(filepath.Join(path, file.Name()))
+			}
+		}
+		os.Remove(path)
+	}
+}
+
+// ImportServerArchive recreates a server from an archive written by
+// StartServerExport on this or another panel. The server gets a new ID
+// and folder; its name and port are kept unless taken or overridden. It
+// does not start automatically.
+func (m *Manager) ImportServerArchive(src io.Reader, opts ServerArchiveImportOptions) (*ServerInfo, error) {
+	if opts.Port != 0 && (opts.Port < 1024 || opts.Port > 65535) {
+		return nil, errPortOutOfRange()
+	}
+	workingDir := filepath.Join(m.importsRoot, "portable-"+uuid.NewString()[:12])
+	if err := os.MkdirAll(workingDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to prepare import workspace: %w", err)
+	}
+	defer os.RemoveAll(workingDir)
+
+	archivePath := filepath.Join(workingDir, "upload.tar.gz")
+	out, err := os.Create(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		return nil, fmt.Errorf("failed to store uploaded file: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return nil, err
+	}
+	extractDir := filepath.Join(workingDir, "extracted")
+	if err := extractTarGzArchive(archivePath, extractDir); err != nil {
+		return nil, fmt.Errorf("failed to extract archive: %w", err)
+	}
+	os.Remove(archivePath)
+
+	data, err := os.ReadFile(filepath.Join(extractDir, serverExportManifest))
+	if err != nil {
+		return nil, fmt.Errorf("not a server export: %s is missing", serverExportManifest)
+	}
+	var manifest ServerExportManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", serverExportManifest, err)
+	}
+	if manifest.Format < 1 || manifest.Format > serverExportFormat {
+		return nil, fmt.Errorf("export format %d is not supported by this panel; update it first", manifest.Format)
+	}
+	filesDir := filepath.Join(extractDir, serverExportDir)
+	if info, err := os.Stat(filesDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("not a server export: %s/ is missing", serverExportDir)
+	}
+	cfg := manifest.Server
+	cfg.Type = canonicalServerType(cfg.Type)
+	if cfg.Type == "" {
+		return nil, fmt.Errorf("server type %q is not supported", manifest.Server.Type)
+	}
+	if _, err := GetProvider(cfg.Type); err != nil {
+		return nil, err
+	}
+	if cfg.Tasks == nil {
+		cfg.Tasks = []ScheduledTask{}
+	}
+	for i := range cfg.Tasks {
+		task := &cfg.Tasks[i]
+		if err := validateTask(task); err != nil || task.Action == TaskRefreshStaging {
+			return nil, fmt.Errorf("invalid scheduled task %q in the export", task.ID)
+		}
+		task.scheduleNextRun(time.Now())
+	}
+
+	m.mu.Lock()
+	id := uuid.New().String()[:8]
+	baseName := strings.TrimSpace(opts.Name)
+	if baseName == "" {
+		baseName = cfg.Name
+	}
+	cfg.Name = m.resolveImportedServerNameLocked(baseName)
+	exportedPort := cfg.Port
+	if opts.Port != 0 {
+		for _, other := range m.configs {
+			if other.Port == opts.Port {
+				suggested, _ := m.nearestAvailablePortLocked(opts.Port)
+				m.mu.Unlock()
+				return nil, &ImportPortConflictError{RequestedPort: opts.Port, SuggestedPort: suggested}
+			}
+		}
+		cfg.Port = opts.Port
+	} else if cfg.Port, err = m.resolveImportedPortLocked(cfg.Port); err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	serverDir := filepath.Join(m.serversRoot, sanitizeName(cfg.Name))
+	if _, err := os.Stat(serverDir); err == nil {
+		serverDir = filepath.Join(m.serversRoot, sanitizeName(cfg.Name)+"_"+id)
+	}
+	serverDir = filepath.Clean(serverDir)
+	if err := m.validateManagedServerDir(serverDir); err != nil {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("invalid target server directory: %w", err)
+	}
+	if err := moveDirectory(filesDir, serverDir); err != nil {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("failed to move imported server files: %w", err)
+	}
+	m.mu.Unlock()
+
+	cleanup := func() {
+		if err := os.RemoveAll(serverDir); err != nil {
+			log.Printf("Failed to remove partial import %s: %v", serverDir, err)
+		}
+	}
+	if cfg.Port != exportedPort {
+		var err error
+		if isProxyType(cfg.Type) {
+			err = updateVelocityToml(filepath.Join(serverDir, "velocity.toml"), cfg.MaxPlayers, cfg.Port)
+		} else {
+			err = setServerProperties(filepath.Join(serverDir, "server.properties"), map[string]string{"server-port": fmt.Sprint(cfg.Port)})
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			cleanup()
+			return nil, fmt.Errorf("failed to set the new port: %w", err)
+		}
+	}
+
+	cfg.ID = id
+	cfg.Dir = serverDir
+	cfg.AutoStart = false
+	m.mu.Lock()
+	for _, other := range m.configs {
+		if other.Port == cfg.Port {
+			m.mu.Unlock()
+			cleanup()
+			return nil, errPortTaken(cfg.Port, other.Name)
+		}
+	}
+	m.configs[id] = &cfg
+	m.assignNewServerOrderLocked(&cfg)
+	m.running[id] = &runningServer{
+		status:      "Stopped",
+		logBuffer:   make([]ConsoleLogEntry, 0),
+		nextLogSeq:  1,
+		players:     make(map[string]*onlinePlayer),
+		pingBlocked: make(map[string]bool),
+	}
+	if err := m.persist(); err != nil {
+		delete(m.configs, id)
+		delete(m.running, id)
+		m.mu.Unlock()
+		cleanup()
+		return nil, err
+	}
+	info := m.serverInfo(id)
+	m.mu.Unlock()
+
+	log.Printf("Imported server %s from an export made %s", cfg.Name, manifest.ExportedAt)
+	return info, nil
+}
@@ -0,0 +1,165 @@
+package minecraft
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerExportRoundTripsToAnotherPanel(t *testing.T) {
+	src, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer src.StopAll()
+
+	cfg := &ServerConfig{
+		ID: "surv", Name: "Survival", Type: "Paper", Version: "1.21.4", Port: 25570, JarFile: "paper-1.21.4.jar",
+		MaxRAM: "4096M", MinRAM: "1024M", MaxPlayers: 30, Flags: "aikars", Dir: filepath.Join(src.serversRoot, "Survival"),
+		PID: 1234, BackupTargets: []string{"s3"}, Groups: []string{"smp"},
+		Tasks: []ScheduledTask{
+			{ID: "t1", Schedule: "0 4 * * *", Action: TaskRestart, Enabled: true, LastRun: "2026-01-01T04:00:00Z", LastResult: "ok"},
+			{ID: "t2", Schedule: "0 5 * * *", Action: TaskRefreshStaging, SourceID: "prod", Enabled: true},
+		},
+		Webhooks: []InboundWebhook{{ID: "w1"}},
+	}
+	src.mu.Lock()
+	src.configs[cfg.ID] = cfg
+	src.running[cfg.ID] = &runningServer{status: "Stopped"}
+	src.mu.Unlock()
+	files := map[string]string{
+		"paper-1.21.4.jar":  "jar",
+		"server.properties": "server-port=25570\nmotd=Survival\n",
+		"world/level.dat":   "level",
+	}
+	for name, content := range files {
+		path := filepath.Join(cfg.Dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	job, err := src.StartServerExport(cfg.ID)
+	if err != nil {
+		t.Fatalf("StartServerExport failed: %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for !job.finished() {
+		if time.Now().After(deadline) {
+			t.Fatalf("export job did not finish: %+v", job)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if job, err = src.GetJob(job.ID); err != nil {
+			t.Fatalf("GetJob failed: %v", err)
+		}
+	}
+	if job.State != JobStateSucceeded {
+		t.Fatalf("expected the export to succeed, got %+v", job)
+	}
+	result, ok := job.Result.(*ServerExportResult)
+	if !ok {
+		t.Fatalf("expected an export result, got %#v", job.Result)
+	}
+	if _, err := src.ServerExportPath(cfg.ID, "../servers.json"); err == nil {
+		t.Fatalf("expected a path outside the exports to be refused")
+	}
+	archivePath, err := src.ServerExportPath(cfg.ID, result.File)
+	if err != nil {
+		t.Fatalf("ServerExportPath failed: %v", err)
+	}
+	names := tarEntryNames(t, archivePath)
+	if names[0] != serverExportManifest || !containsString(names, "server/world/level.dat") {
+		t.Fatalf("unexpected archive layout: %v", names)
+	}
+
+	// The other panel already has a server on the exported port.
+	dst, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer dst.StopAll()
+	dst.mu.Lock()
+	dst.configs["lobby"] = &ServerConfig{ID: "lobby", Name: "Lobby", Type: "Paper", Port: 25570, Dir: filepath.Join(dst.serversRoot, "Lobby")}
+	dst.running["lobby"] = &runningServer{status: "Stopped"}
+	dst.mu.Unlock()
+
+	open := func() *os.File {
+		f, err := os.Open(archivePath)
+		if err != nil {
+			t.Fatalf("failed to open export: %v", err)
+		}
+		t.Cleanup(func() { f.Close() })
+		return f
+	}
+	var conflict *ImportPortConflictError
+	if _, err := dst.ImportServerArchive(open(), ServerArchiveImportOptions{Port: 25570}); !errors.As(err, &conflict) {
+		t.Fatalf("expected a port conflict, got %v", err)
+	}
+	if _, err := dst.ImportServerArchive(strings.NewReader("not an archive"), ServerArchiveImportOptions{}); err == nil {
+		t.Fatalf("expected a broken upload to be refused")
+	}
+	info, err := dst.ImportServerArchive(open(), ServerArchiveImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportServerArchive failed: %v", err)
+	}
+
+	dst.mu.RLock()
+	imported := dst.configs[info.ID]
+	dst.mu.RUnlock()
+	if imported == nil || imported.ID == cfg.ID {
+		t.Fatalf("expected the server to be registered under a new ID, got %+v", imported)
+	}
+	if imported.Name != "Survival" || imported.Type != "Paper" || imported.Version != "1.21.4" || imported.Port != 25571 ||
+		imported.MaxRAM != "4096M" || imported.Flags != "aikars" || imported.JarFile != "paper-1.21.4.jar" {
+		t.Fatalf("unexpected imported config: %+v", imported)
+	}
+	if imported.PID != 0 || imported.BackupTargets != nil || imported.Groups != nil || imported.Webhooks != nil {
+		t.Fatalf("expected panel-specific settings to be dropped, got %+v", imported)
+	}
+	if len(imported.Tasks) != 1 || imported.Tasks[0].Action != TaskRestart || imported.Tasks[0].LastRun != "" || imported.Tasks[0].NextRun == "" {
+		t.Fatalf("expected only the restart task to be carried over and rescheduled, got %+v", imported.Tasks)
+	}
+	if props := parseServerPropertiesFile(filepath.Join(imported.Dir, "server.properties")); props["server-port"] != "25571" || props["motd"] != "Survival" {
+		t.Fatalf("expected the new port in server.properties, got %v", props)
+	}
+	if data, err := os.ReadFile(filepath.Join(imported.Dir, "world", "level.dat")); err != nil || string(data) != "level" {
+		t.Fatalf("expected the world to be imported, got %q (%v)", data, err)
+	}
+	if entries, _ := os.ReadDir(dst.importsRoot); len(entries) != 0 {
+		t.Fatalf("expected the import workspace to be removed, got %d entries", len(entries))
+	}
+}
+
+func tarEntryNames(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names
+		}
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		names = append(names, strings.TrimSuffix(hdr.Name, "/"))
+	}
+}
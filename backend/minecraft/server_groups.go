@@ -0,0 +1,209 @@
+package minecraft
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	maxServerGroups       = 10
+	maxServerGroupNameLen = 32
+)
+
+var serverGroupNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 _.-]*$`)
+
+// ServerGroup is a named set of servers, e.g. a proxy's lobby and game
+// servers, that operators watch as one unit.
+type ServerGroup struct {
+	Name      string   `json:"name"`
+	ServerIDs []string `json:"serverIds"`
+}
+
+// ServerGroupMember is one server's share of a group summary.
+type ServerGroupMember struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Status   string  `json:"status"`
+	Players  int     `json:"players"`
+	TPS      float64 `json:"tps"`
+	RAMBytes uint64  `json:"ramBytes"`
+}
+
+// ServerGroupSummary aggregates the live state of a group's servers. Status
+// is "Running" when every server runs, "Stopped" when none does and
+// "Degraded" otherwise. WorstTPS is null until a running server reports TPS.
+type ServerGroupSummary struct {
+	Name          string              `json:"name"`
+	Status        string              `json:"status"`
+	StatusCounts  map[string]int      `json:"statusCounts"`
+	TotalPlayers  int                 `json:"totalPlayers"`
+	MaxPlayers    int                 `json:"maxPlayers"`
+	TotalRAMBytes uint64              `json:"totalRamBytes"`
+	TotalRAMMB    float64             `json:"totalRamMb"`
+	WorstTPS      *float64            `json:"worstTps"`
+	WorstTPSID    string              `json:"worstTpsServerId,omitempty"`
+	Servers       []ServerGroupMember `json:"servers"`
+}
+
+// normalizeServerGroups trims and validates group names and drops
+// case-insensitive duplicates, keeping the first spelling.
+func normalizeServerGroups(groups []string) ([]string, error) {
+	normalized := make([]string, 0, len(groups))
+	seen := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			continue
+		}
+		if len(group) > maxServerGroupNameLen || !serverGroupNamePattern.MatchString(group) {
+			return nil, fmt.Errorf("invalid group name %q: use up to %d letters, digits, spaces, dots, dashes or underscores", group, maxServerGroupNameLen)
+		}
+		key := strings.ToLower(group)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, group)
+	}
+	if len(normalized) > maxServerGroups {
+		return nil, fmt.Errorf("a server can belong to at most %d groups", maxServerGroups)
+	}
+	return normalized, nil
+}
+
+func serverInGroup(cfg *ServerConfig, name string) bool {
+	for _, group := range cfg.Groups {
+		if strings.EqualFold(group, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetServerGroups replaces the groups a server belongs to.
+func (m *Manager) SetServerGroups(id string, groups []string) (*ServerInfo, error) {
+	normalized, err := normalizeServerGroups(groups)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(normalized) == 0 {
+		normalized = nil
+	}
+	cfg.Groups = normalized
+	if err := m.persist(); err != nil {
+		return nil, err
+	}
+
+	return m.serverInfo(id), nil
+}
+
+// ListServerGroups returns every group in use, sorted by name.
+func (m *Manager) ListServerGroups() []ServerGroup {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	byKey := make(map[string]*ServerGroup)
+	for _, id := range m.orderedServerIDsLocked() {
+		for _, group := range m.configs[id].Groups {
+			key := strings.ToLower(group)
+			entry, ok := byKey[key]
+			if !ok {
+				entry = &ServerGroup{Name: group}
+				byKey[key] = entry
+			}
+			entry.ServerIDs = append(entry.ServerIDs, id)
+		}
+	}
+
+	groups := make([]ServerGroup, 0, len(byKey))
+	for _, entry := range byKey {
+		groups = append(groups, *entry)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i].Name) < strings.ToLower(groups[j].Name)
+	})
+	return groups
+}
+
+// GroupSummary aggregates status, players, RAM and TPS across a group.
+func (m *Manager) GroupSummary(name string) (*ServerGroupSummary, error) {
+	name = strings.TrimSpace(name)
+	type groupEntry struct {
+		cfg ServerConfig
+		rs  *runningServer
+	}
+
+	m.mu.RLock()
+	var members []groupEntry
+	for _, id := range m.orderedServerIDsLocked() {
+		if cfg := m.configs[id]; serverInGroup(cfg, name) {
+			members = append(members, groupEntry{cfg: *cfg, rs: m.running[id]})
+		}
+	}
+	m.mu.RUnlock()
+
+	if len(members) == 0 {
+		return nil, fmt.Errorf("group %q has no servers", name)
+	}
+	// Report the spelling stored on the servers rather than the request's.
+	for _, group := range members[0].cfg.Groups {
+		if strings.EqualFold(group, name) {
+			name = group
+			break
+		}
+	}
+
+	summary := &ServerGroupSummary{
+		Name:         name,
+		StatusCounts: make(map[string]int),
+		Servers:      make([]ServerGroupMember, 0, len(members)),
+	}
+	running := 0
+	for _, entry := range members {
+		cfg, rs := &entry.cfg, entry.rs
+		member := ServerGroupMember{ID: cfg.ID, Name: cfg.Name, Status: "Stopped"}
+		if rs != nil {
+			runtime := rs.displayRuntime()
+			member.Status = runtime.status
+			member.TPS = runtime.tps
+			member.RAMBytes = runtime.ramBytes
+			rs.mu.RLock()
+			member.Players = len(rs.players)
+			rs.mu.RUnlock()
+		}
+		summary.StatusCounts[member.Status]++
+		summary.MaxPlayers += cfg.MaxPlayers
+		summary.TotalPlayers += member.Players
+		summary.TotalRAMBytes += member.RAMBytes
+		if member.Status == "Running" {
+			running++
+			if member.TPS > 0 && (summary.WorstTPS == nil || member.TPS < *summary.WorstTPS) {
+				tps := member.TPS
+				summary.WorstTPS = &tps
+				summary.WorstTPSID = cfg.ID
+			}
+		}
+		summary.Servers = append(summary.Servers, member)
+	}
+	summary.TotalRAMMB = bytesToMB(summary.TotalRAMBytes)
+	switch running {
+	case len(members):
+		summary.Status = "Running"
+	case 0:
+		summary.Status = "Stopped"
+	default:
+		summary.Status = "Degraded"
+	}
+	return summary, nil
+}
@@ -0,0 +1,63 @@
+package minecraft
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGroupSummaryAggregatesMembers(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	add := func(id, name string, rs *runningServer) {
+		mgr.mu.Lock()
+		mgr.configs[id] = &ServerConfig{ID: id, Name: name, Type: "Paper", MaxPlayers: 20, Dir: filepath.Join(mgr.serversRoot, name)}
+		mgr.running[id] = rs
+		mgr.mu.Unlock()
+	}
+	add("lobby", "Lobby", &runningServer{status: "Running", tps: 19.5, ramBytes: 1 << 30, players: map[string]*onlinePlayer{"a": {}, "b": {}}})
+	add("game", "Game", &runningServer{status: "Running", tps: 14.2, ramBytes: 2 << 30, players: map[string]*onlinePlayer{"c": {}}})
+	add("event", "Event", &runningServer{status: "Stopped"})
+	add("solo", "Solo", &runningServer{status: "Running"})
+
+	for _, id := range []string{"lobby", "game", "event"} {
+		if _, err := mgr.SetServerGroups(id, []string{"Network", "network", " "}); err != nil {
+			t.Fatalf("SetServerGroups(%s) failed: %v", id, err)
+		}
+	}
+	if _, err := mgr.SetServerGroups("solo", []string{"bad/name"}); err == nil {
+		t.Fatalf("expected invalid group name to be rejected")
+	}
+
+	groups := mgr.ListServerGroups()
+	if len(groups) != 1 || groups[0].Name != "Network" || len(groups[0].ServerIDs) != 3 {
+		t.Fatalf("unexpected groups %+v", groups)
+	}
+
+	summary, err := mgr.GroupSummary("NETWORK")
+	if err != nil {
+		t.Fatalf("GroupSummary failed: %v", err)
+	}
+	if summary.Name != "Network" || summary.Status != "Degraded" {
+		t.Fatalf("unexpected name/status %q/%q", summary.Name, summary.Status)
+	}
+	if summary.TotalPlayers != 3 || summary.MaxPlayers != 60 {
+		t.Fatalf("unexpected players %d/%d", summary.TotalPlayers, summary.MaxPlayers)
+	}
+	if summary.TotalRAMBytes != 3<<30 {
+		t.Fatalf("unexpected RAM total %d", summary.TotalRAMBytes)
+	}
+	if summary.WorstTPS == nil || *summary.WorstTPS != 14.2 || summary.WorstTPSID != "game" {
+		t.Fatalf("unexpected worst TPS %v (%s)", summary.WorstTPS, summary.WorstTPSID)
+	}
+	if summary.StatusCounts["Running"] != 2 || summary.StatusCounts["Stopped"] != 1 {
+		t.Fatalf("unexpected status counts %v", summary.StatusCounts)
+	}
+
+	if _, err := mgr.GroupSummary("missing"); err == nil {
+		t.Fatalf("expected unknown group to fail")
+	}
+}
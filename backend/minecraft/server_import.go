@@ -5,6 +5,7 @@ import (
 	"archive/zip"
 	"bufio"
 	"compress/gzip"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -651,9 +652,16 @@ func detectServerType(rootDir string, plugins, mods []string) (string, bool) {
 	}
 
 	if len(plugins) > 0 {
-		switch {
-		case hasAnyFile(rootDir, "purpur.yml"):
+		if hasAnyFile(rootDir, "purpur.yml") {
 			return "Purpur", true
+		}
+		// The jar name tells Purpur and Folia apart from Paper, whose
+		// config files they write too.
+		switch jarType, _ := serverTypeFromJarNames(rootDir); jarType {
+		case "Purpur", "Folia", "Paper", "Spigot":
+			return jarType, true
+		}
+		switch {
 		case hasPaperConfig(rootDir):
 			return "Paper", true
 		case hasAnyFile(rootDir, "spigot.yml", "bukkit.yml"):
@@ -663,6 +671,12 @@ func detectServerType(rootDir string, plugins, mods []string) (string, bool) {
 		}
 	}
 
+	return serverTypeFromJarNames(rootDir)
+}
+
+// serverTypeFromJarNames guesses the server type from the names of the jars
+// in rootDir, such as purpur-1.21.4-2400.jar.
+func serverTypeFromJarNames(rootDir string) (string, bool) {
 	rootEntries, _ := os.ReadDir(rootDir)
 	for _, entry := range rootEntries {
 		if entry.IsDir() || !strings.HasSuffix(strings.ToLower(entry.Name()), ".jar") {
@@ -924,65 +938,13 @@ func isCrossDeviceErr(err error) bool {
 	return strings.Contains(strings.ToLower(err.Error()), "cross-device link")
 }
 
-func copyDirectory(srcDir, dstDir string) error {
-	return filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		rel, relErr := filepath.Rel(srcDir, path)
-		if relErr != nil {
-			return relErr
-		}
-		if rel == "." {
-			return os.MkdirAll(dstDir, 0755)
-		}
-		target := filepath.Join(dstDir, rel)
-		if d.IsDir() {
-			return os.MkdirAll(target, 0755)
-		}
-		if d.Type()&os.ModeSymlink != 0 {
-			return fmt.Errorf("symlinks are not supported")
-		}
-		info, infoErr := d.Info()
-		if infoErr != nil {
-			return infoErr
-		}
-		src, openErr := os.Open(path)
-		if openErr != nil {
-			return openErr
-		}
-		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
-			_ = src.Close()
-			return err
-		}
-		dst, createErr := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
-		if createErr != nil {
-			_ = src.Close()
-			return createErr
-		}
-		_, copyErr := io.Copy(dst, src)
-		srcCloseErr := src.Close()
-		closeErr := dst.Close()
-		if copyErr != nil {
-			return copyErr
-		}
-		if srcCloseErr != nil {
-			return srcCloseErr
-		}
-		if closeErr != nil {
-			return closeErr
-		}
-		return nil
-	})
-}
-
 func moveDirectory(srcDir, dstDir string) error {
 	if err := os.Rename(srcDir, dstDir); err == nil {
 		return nil
 	} else if !isCrossDeviceErr(err) {
 		return err
 	}
-	if err := copyDirectory(srcDir, dstDir); err != nil {
+	if err := copyTree(context.Background(), srcDir, dstDir, nil); err != nil {
 		return err
 	}
 	return os.RemoveAll(srcDir)
@@ -1017,6 +979,7 @@ func (m *Manager) cleanupExpiredImportAnalyses() {
 	for _, analysis := range stale {
 		_ = os.RemoveAll(analysis.WorkingDir)
 	}
+	m.cleanupExpiredExports()
 }
 
 func (m *Manager) AnalyzeServerImportArchive(fileName string, src io.Reader) (*ServerImportAnalysisResult, error) {
@@ -1256,7 +1219,7 @@ func (m *Manager) CommitServerImport(analysisID string, opts ServerImportCommitO
 		requestedPort := *opts.Port
 		if requestedPort < 1024 || requestedPort > 65535 {
 			m.mu.Unlock()
-			return nil, fmt.Errorf("port must be between 1024 and 65535")
+			return nil, errPortOutOfRange()
 		}
 		inUse := false
 		for _, cfg := range m.configs {
@@ -1387,6 +1350,17 @@ func (m *Manager) CommitServerImport(analysisID string, opts ServerImportCommitO
 
 	jarFile := chooseImportedJarFile(serverDir, serverType)
 	startCommand := detectImportedStartCommand(serverDir, serverType)
+	if len(startCommand) > 0 {
+		// The panel writes its RAM settings into user_jvm_args.txt from now
+		// on, so start from the sizes the server already had.
+		userMin, userMax := userJVMHeap(filepath.Join(serverDir, "user_jvm_args.txt"))
+		if userMin != "" {
+			minRAM = userMin
+		}
+		if userMax != "" {
+			maxRAM = userMax
+		}
+	}
 
 	cfg := &ServerConfig{
 		ID:             newID,
@@ -0,0 +1,333 @@
+package minecraft
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrDirectoryImportDisabled is returned when no import roots are set, so
+// the panel will not read server folders outside its own.
+var ErrDirectoryImportDisabled = fmt.Errorf("importing existing directories is off; set ADPANEL_IMPORT_ROOTS to the folders servers may be imported from")
+
+// ServerDirImportOptions registers a server that already exists on disk.
+// Type, version, name and port are detected when left empty. The folder is
+// copied into the panel's servers folder, or moved there when Move is set;
+// nothing is downloaded.
+type ServerDirImportOptions struct {
+	Path       string `json:"path"`
+	Name       string `json:"name"`
+	Port       int    `json:"port"`
+	Type       string `json:"type"`
+	Version    string `json:"version"`
+	Move       bool   `json:"move"`
+	AcceptEULA bool   `json:"acceptEula"`
+}
+
+// importRootsFromEnv returns the folders existing servers may be imported
+// from, read from the comma-separated ADPANEL_IMPORT_ROOTS.
+func importRootsFromEnv() []string {
+	raw := strings.TrimSpace(os.Getenv("ADPANEL_IMPORT_ROOTS"))
+	if raw == "" {
+		return nil
+	}
+	var roots []string
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || !filepath.IsAbs(part) {
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(part); err == nil {
+			roots = append(roots, filepath.Clean(resolved))
+		}
+	}
+	return roots
+}
+
+// resolveImportDirectory checks that path is a folder inside one of the
+// import roots and outside the panel's own folders, and returns it with
+// symlinks resolved.
+func (m *Manager) resolveImportDirectory(path string) (string, error) {
+	roots := importRootsFromEnv()
+	if len(roots) == 0 {
+		return "", ErrDirectoryImportDisabled
+	}
+	path = strings.TrimSpace(path)
+	if path == "" || !filepath.IsAbs(path) {
+		return "", fmt.Errorf("path must be an absolute folder path")
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("folder not found: %s", path)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("%s is not a folder", path)
+	}
+	allowed := false
+	for _, root := range roots {
+		if resolved != root && ensurePathWithinBase(root, resolved) == nil {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return "", fmt.Errorf("%s is not inside an import root (%s)", path, strings.Join(roots, ", "))
+	}
+	for _, own := range []string{m.serversRootReal, m.backupsRootReal, m.importsRoot} {
+		if own != "" && (ensurePathWithinBase(own, resolved) == nil || ensurePathWithinBase(resolved, own) == nil) {
+			return "", fmt.Errorf("%s overlaps the panel's own folders", path)
+		}
+	}
+	return resolved, nil
+}
+
+// StartImportServerDirectory checks an existing server folder and returns
+// the job that copies or moves it into the panel and registers it. The
+// type is detected from the jars, plugins and mods, the port from
+// server.properties or velocity.toml; a taken port is moved to the next
+// free one. The job carries the new server as its result.
+func (m *Manager) StartImportServerDirectory(opts ServerDirImportOptions) (*Job, error) {
+	srcDir, err := m.resolveImportDirectory(opts.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !hasImportRuntimeSignal(srcDir) {
+		return nil, fmt.Errorf("%s does not look like a Minecraft server folder", opts.Path)
+	}
+
+	plugins := listJarNames(filepath.Join(srcDir, "plugins"))
+	mods := listJarNames(filepath.Join(srcDir, "mods"))
+	serverType := canonicalServerType(opts.Type)
+	if strings.TrimSpace(opts.Type) != "" && serverType == "" {
+		return nil, fmt.Errorf("unsupported server type %q", opts.Type)
+	}
+	if serverType == "" {
+		detected, ok := detectServerType(srcDir, plugins, mods)
+		if serverType = canonicalServerType(detected); !ok || serverType == "" {
+			return nil, fmt.Errorf("could not detect the server type; pass type")
+		}
+	}
+	if _, err := GetProvider(serverType); err != nil {
+		return nil, err
+	}
+	version := strings.TrimSpace(opts.Version)
+	if version == "" {
+		if version = detectVersion(srcDir); version == "" {
+			return nil, &ImportInvalidVersionError{Message: "Could not detect the server version; pass version."}
+		}
+	}
+	if opts.Port != 0 && (opts.Port < 1024 || opts.Port > 65535) {
+		return nil, errPortOutOfRange()
+	}
+
+	newID := uuid.New().String()[:8]
+	m.mu.Lock()
+	if opts.Port != 0 {
+		for _, cfg := range m.configs {
+			if cfg.Port == opts.Port {
+				suggested, _ := m.nearestAvailablePortLocked(opts.Port)
+				m.mu.Unlock()
+				return nil, &ImportPortConflictError{RequestedPort: opts.Port, SuggestedPort: suggested}
+			}
+		}
+	}
+	baseName := strings.TrimSpace(opts.Name)
+	if baseName == "" {
+		baseName = filepath.Base(srcDir)
+	}
+	name := m.resolveImportedServerNameLocked(baseName)
+	serverDir := filepath.Join(m.serversRoot, sanitizeName(name))
+	if _, err := os.Stat(serverDir); err == nil {
+		serverDir = filepath.Join(m.serversRoot, sanitizeName(name)+"_"+newID)
+	}
+	serverDir = filepath.Clean(serverDir)
+	if err := m.validateManagedServerDir(serverDir); err != nil {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("invalid target server directory: %w", err)
+	}
+	// Creating the directory under the lock reserves it against servers
+	// created while the files are copied.
+	if err := os.MkdirAll(serverDir, 0755); err != nil {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("failed to create server directory: %w", err)
+	}
+	m.mu.Unlock()
+
+	job := m.newJob(JobTypeImport, newID)
+	go func() {
+		_, err := m.importServerDirectoryJob(job, newID, name, serverType, version, srcDir, serverDir, opts)
+		if err != nil {
+			log.Printf("Import of %s failed: %v", srcDir, err)
+		}
+		job.finish(err)
+	}()
+	return m.GetJob(job.id)
+}
+
+func (m *Manager) importServerDirectoryJob(job *jobHandle, id, name, serverType, version, srcDir, serverDir string, opts ServerDirImportOptions) (*ServerInfo, error) {
+	job.start(fmt.Sprintf("Importing %s", srcDir))
+	// A failed copy is removed; moved files are put back where they were.
+	cleanup := func() {
+		if opts.Move {
+			if err := moveDirectory(serverDir, srcDir); err != nil {
+				log.Printf("Failed to move %s back to %s: %v", serverDir, srcDir, err)
+			}
+			return
+		}
+		if err := os.RemoveAll(serverDir); err != nil {
+			log.Printf("Failed to remove partial import %s: %v", serverDir, err)
+		}
+	}
+
+	if opts.Move {
+		job.progress(5, "Moving files")
+		if err := moveDirectory(srcDir, serverDir); err != nil {
+			os.Remove(serverDir)
+			return nil, fmt.Errorf("failed to move %s: %w", srcDir, err)
+		}
+	} else {
+		total, _ := backupSourceSize(job.ctx, srcDir)
+		var done int64
+		job.progress(5, "Copying files")
+		err := copyTree(job.ctx, srcDir, serverDir, func(n int64) {
+			done += n
+			if total > 0 {
+				job.progress(5+int(done*80/total), "")
+			}
+		})
+		if err != nil {
+			cleanup()
+			return nil, fmt.Errorf("failed to copy %s: %w", srcDir, err)
+		}
+	}
+	job.progress(90, "Registering the server")
+
+	serverProps := parseServerPropertiesFile(filepath.Join(serverDir, "server.properties"))
+	velocityPath := filepath.Join(serverDir, "velocity.toml")
+	detectedPort := parseImportPort(serverProps, velocityPath)
+	maxPlayers := 20
+	if n := parseIntPtr(serverProps["max-players"]); n != nil && *n > 0 {
+		maxPlayers = *n
+	} else if _, n := parseVelocityToml(velocityPath); n != nil && *n > 0 {
+		maxPlayers = *n
+	}
+
+	m.settingsMu.RLock()
+	minRAM := toRAMMBString(m.settings.DefaultMinRAM, 512)
+	maxRAM := toRAMMBString(m.settings.DefaultMaxRAM, 1024)
+	flags := strings.TrimSpace(m.settings.DefaultFlags)
+	m.settingsMu.RUnlock()
+	if flags == "" {
+		flags = "none"
+	}
+	startCommand := detectImportedStartCommand(serverDir, serverType)
+	if len(startCommand) > 0 {
+		userMin, userMax := userJVMHeap(filepath.Join(serverDir, "user_jvm_args.txt"))
+		if userMin != "" {
+			minRAM = userMin
+		}
+		if userMax != "" {
+			maxRAM = userMax
+		}
+	}
+
+	m.mu.Lock()
+	port := opts.Port
+	if port == 0 {
+		resolved, err := m.resolveImportedPortLocked(detectedPort)
+		if err != nil {
+			m.mu.Unlock()
+			cleanup()
+			return nil, err
+		}
+		port = resolved
+	}
+	for _, other := range m.configs {
+		if other.Port == port {
+			m.mu.Unlock()
+			cleanup()
+			return nil, errPortTaken(port, other.Name)
+		}
+	}
+	cfg := &ServerConfig{
+		ID:           id,
+		Name:         m.resolveImportedServerNameLocked(name),
+		Type:         serverType,
+		Version:      version,
+		Port:         port,
+		JarFile:      chooseImportedJarFile(serverDir, serverType),
+		MaxRAM:       maxRAM,
+		MinRAM:       minRAM,
+		MaxPlayers:   maxPlayers,
+		Dir:          serverDir,
+		StartCommand: startCommand,
+		Flags:        flags,
+	}
+	m.mu.Unlock()
+
+	if isProxyType(serverType) {
+		if _, err := os.Stat(velocityPath); err == nil {
+			if err := updateVelocityToml(velocityPath, maxPlayers, port); err != nil {
+				cleanup()
+				return nil, fmt.Errorf("failed to update velocity.toml: %w", err)
+			}
+		}
+	} else {
+		if port != detectedPort && opts.Port == 0 {
+			job.log(fmt.Sprintf("Port %d is taken; the server now uses %d", detectedPort, port))
+		}
+		if err := setServerProperties(filepath.Join(serverDir, "server.properties"), map[string]string{"server-port": fmt.Sprint(port)}); err != nil {
+			cleanup()
+			return nil, fmt.Errorf("failed to update server.properties: %w", err)
+		}
+		eulaPath := filepath.Join(serverDir, "eula.txt")
+		switch {
+		case parseServerPropertiesFile(eulaPath)["eula"] == "true":
+		case opts.AcceptEULA:
+			if err := os.WriteFile(eulaPath, []byte("eula=true\n"), 0644); err != nil {
+				cleanup()
+				return nil, fmt.Errorf("failed to write eula.txt: %w", err)
+			}
+			job.log("Accepted the Minecraft EULA in eula.txt")
+		default:
+			job.log("The Minecraft EULA is not accepted in eula.txt; the server will not start until it is")
+		}
+	}
+
+	m.mu.Lock()
+	for _, other := range m.configs {
+		if other.Port == port {
+			m.mu.Unlock()
+			cleanup()
+			return nil, errPortTaken(port, other.Name)
+		}
+	}
+	m.configs[id] = cfg
+	m.assignNewServerOrderLocked(cfg)
+	m.running[id] = &runningServer{
+		status:      "Stopped",
+		logBuffer:   make([]ConsoleLogEntry, 0),
+		nextLogSeq:  1,
+		players:     make(map[string]*onlinePlayer),
+		pingBlocked: make(map[string]bool),
+	}
+	if err := m.persist(); err != nil {
+		delete(m.configs, id)
+		delete(m.running, id)
+		m.mu.Unlock()
+		cleanup()
+		return nil, err
+	}
+	info := m.serverInfo(id)
+	m.mu.Unlock()
+
+	job.log(fmt.Sprintf("Imported %s as %s %s on port %d", srcDir, cfg.Type, cfg.Version, port))
+	job.setResult(info)
+	log.Printf("Imported existing server %s from %s", cfg.Name, srcDir)
+	return info, nil
+}
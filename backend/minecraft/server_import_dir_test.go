@@ -0,0 +1,97 @@
+package minecraft
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportServerDirectoryCopiesAndRegistersExistingServer(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	mgr.mu.Lock()
+	mgr.configs["lobby"] = &ServerConfig{ID: "lobby", Name: "Lobby", Type: "Paper", Port: 25565, Dir: filepath.Join(mgr.serversRoot, "Lobby")}
+	mgr.running["lobby"] = &runningServer{status: "Stopped"}
+	mgr.mu.Unlock()
+
+	root := t.TempDir()
+	src := filepath.Join(root, "survival")
+	files := map[string]string{
+		"purpur-1.21.4-2400.jar": "jar",
+		"plugins/Essentials.jar": "plugin",
+		"server.properties":      "server-port=25565\nmax-players=40\n",
+		"eula.txt":               "eula=false\n",
+		"world/level.dat":        "level",
+	}
+	for name, content := range files {
+		path := filepath.Join(src, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	t.Setenv("ADPANEL_IMPORT_ROOTS", "")
+	if _, err := mgr.StartImportServerDirectory(ServerDirImportOptions{Path: src}); !errors.Is(err, ErrDirectoryImportDisabled) {
+		t.Fatalf("expected directory import to be off without import roots, got %v", err)
+	}
+	t.Setenv("ADPANEL_IMPORT_ROOTS", root)
+	if _, err := mgr.StartImportServerDirectory(ServerDirImportOptions{Path: t.TempDir()}); err == nil {
+		t.Fatalf("expected a folder outside the import roots to be refused")
+	}
+	if _, err := mgr.StartImportServerDirectory(ServerDirImportOptions{Path: src, Port: 25565}); err == nil {
+		t.Fatalf("expected a taken port to be refused")
+	}
+
+	job, err := mgr.StartImportServerDirectory(ServerDirImportOptions{Path: src, AcceptEULA: true})
+	if err != nil {
+		t.Fatalf("StartImportServerDirectory failed: %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for !job.finished() {
+		if time.Now().After(deadline) {
+			t.Fatalf("import job did not finish: %+v", job)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if job, err = mgr.GetJob(job.ID); err != nil {
+			t.Fatalf("GetJob failed: %v", err)
+		}
+	}
+	if job.State != JobStateSucceeded {
+		t.Fatalf("expected the import to succeed, got %+v", job)
+	}
+	if !strings.Contains(strings.Join(job.Logs, "\n"), "Port 25565 is taken") {
+		t.Fatalf("expected the port change to be logged, got %q", job.Logs)
+	}
+
+	mgr.mu.RLock()
+	cfg := mgr.configs[job.ServerID]
+	mgr.mu.RUnlock()
+	if cfg == nil {
+		t.Fatalf("expected server %s to be registered", job.ServerID)
+	}
+	if cfg.Name != "survival" || cfg.Type != "Purpur" || cfg.Version != "1.21.4" || cfg.Port != 25566 || cfg.MaxPlayers != 40 || cfg.JarFile != "purpur-1.21.4-2400.jar" {
+		t.Fatalf("unexpected imported config: %+v", cfg)
+	}
+	if props := parseServerPropertiesFile(filepath.Join(cfg.Dir, "server.properties")); props["server-port"] != "25566" {
+		t.Fatalf("expected the new port in server.properties, got %v", props)
+	}
+	if eula := parseServerPropertiesFile(filepath.Join(cfg.Dir, "eula.txt")); eula["eula"] != "true" {
+		t.Fatalf("expected the EULA to be accepted, got %v", eula)
+	}
+	if data, err := os.ReadFile(filepath.Join(cfg.Dir, "world", "level.dat")); err != nil || string(data) != "level" {
+		t.Fatalf("expected the world to be copied, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(src, "purpur-1.21.4-2400.jar")); err != nil {
+		t.Fatalf("expected the original folder to be left in place: %v", err)
+	}
+}
@@ -347,6 +347,9 @@ func TestServerImportAcceptsForgeRunScriptEvidence(t *testing.T) {
 		if len(cfg.StartCommand) != 3 || cfg.StartCommand[0] != "bash" || cfg.StartCommand[1] != "run.sh" || cfg.StartCommand[2] != "nogui" {
 			t.Fatalf("expected run.sh start command for forge import, got %#v", cfg.StartCommand)
 		}
+		if cfg.MaxRAM != "2048M" {
+			t.Fatalf("expected the -Xmx from user_jvm_args.txt to become MaxRAM, got %q", cfg.MaxRAM)
+		}
 	})
 }
 
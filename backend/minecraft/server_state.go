@@ -0,0 +1,72 @@
+package minecraft
+
+// ServerState is a flat snapshot of one server for integrations that only
+// need its headline numbers.
+type ServerState struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Online     bool    `json:"online"`
+	Players    int     `json:"players"`
+	MaxPlayers int     `json:"maxPlayers"`
+	TPS        float64 `json:"tps"`
+	CPU        float64 `json:"cpu"`
+	RAMMB      float64 `json:"ramMb"`
+}
+
+// ServerStates returns the state of every server in panel order.
+func (m *Manager) ServerStates() []ServerState {
+	type stateEntry struct {
+		cfg ServerConfig
+		rs  *runningServer
+	}
+
+	m.mu.RLock()
+	ids := m.orderedServerIDsLocked()
+	entries := make([]stateEntry, 0, len(ids))
+	for _, id := range ids {
+		if cfg := m.configs[id]; cfg != nil {
+			entries = append(entries, stateEntry{cfg: *cfg, rs: m.running[id]})
+		}
+	}
+	m.mu.RUnlock()
+
+	states := make([]ServerState, 0, len(entries))
+	for _, entry := range entries {
+		state := ServerState{
+			ID:         entry.cfg.ID,
+			Name:       entry.cfg.Name,
+			Status:     "Stopped",
+			MaxPlayers: entry.cfg.MaxPlayers,
+		}
+		if rs := entry.rs; rs != nil {
+			runtime := rs.displayRuntime()
+			state.Status = runtime.status
+			state.Online = runtime.status == "Running"
+			state.TPS = runtime.tps
+			state.CPU = runtime.cpu
+			state.RAMMB = bytesToMB(runtime.ramBytes)
+			rs.mu.RLock()
+			state.Players = len(rs.players)
+			rs.mu.RUnlock()
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// ServerState returns the state of one server.
+func (m *Manager) ServerState(id string) (*ServerState, error) {
+	m.mu.RLock()
+	_, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	for _, state := range m.ServerStates() {
+		if state.ID == id {
+			return &state, nil
+		}
+	}
+	return nil, errServerNotFound(id)
+}
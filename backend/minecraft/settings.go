@@ -6,10 +6,11 @@ import (
 	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
@@ -25,8 +26,49 @@ type AppSettings struct {
 	TpsPollInterval    int    `json:"tpsPollInterval,omitempty"`
 	PlayerSyncInterval int    `json:"playerSyncInterval,omitempty"`
 	PingPollInterval   int    `json:"pingPollInterval,omitempty"`
-	LoginUser          string `json:"loginUser,omitempty"`
-	LoginPasswordHash  string `json:"loginPasswordHash,omitempty"`
+	// New servers created without a port take the first free port in
+	// DefaultPortRangeStart..DefaultPortRangeEnd.
+	DefaultPortRangeStart int    `json:"defaultPortRangeStart,omitempty"`
+	DefaultPortRangeEnd   int    `json:"defaultPortRangeEnd,omitempty"`
+	DefaultMaxPlayers     int    `json:"defaultMaxPlayers,omitempty"`
+	DefaultBackupSchedule string `json:"defaultBackupSchedule,omitempty"`
+	// Session and login security. Sessions older than SessionTTLHours are
+	// dropped, including ones issued before the value was lowered; a client
+	// is locked out for LoginLockoutMinutes after LoginMaxFailures failed
+	// logins within LoginWindowMinutes.
+	SessionTTLHours      int  `json:"sessionTtlHours,omitempty"`
+	LoginMaxFailures     int  `json:"loginMaxFailures,omitempty"`
+	LoginWindowMinutes   int  `json:"loginWindowMinutes,omitempty"`
+	LoginLockoutMinutes  int  `json:"loginLockoutMinutes,omitempty"`
+	SessionBindIP        bool `json:"sessionBindIp,omitempty"`
+	SessionBindUserAgent bool `json:"sessionBindUserAgent,omitempty"`
+	// CSPReportOnly sends the content security policy as report-only, so
+	// violations are logged instead of blocked while the policy is tuned.
+	CSPReportOnly bool `json:"cspReportOnly,omitempty"`
+	// AccessAllowList and AccessDenyList limit which client IPs may use the
+	// panel, as IPs or CIDR ranges. They do not affect game ports.
+	AccessAllowList []string `json:"accessAllowList,omitempty"`
+	AccessDenyList  []string `json:"accessDenyList,omitempty"`
+	// RestartWarningMinutes lists the countdown broadcasts sent before a
+	// scheduled restart, in minutes before the restart time.
+	RestartWarningMinutes []int `json:"restartWarningMinutes,omitempty"`
+	// Locale selects the language of in-game broadcasts such as restart
+	// warnings.
+	Locale            string `json:"locale,omitempty"`
+	LoginUser         string `json:"loginUser,omitempty"`
+	LoginPasswordHash string `json:"loginPasswordHash,omitempty"`
+	// CurseForgeAPIKey enables CurseForge update checks and downloads. It
+	// is never sent back to clients; CurseForgeAPIKeySet says whether one
+	// is saved.
+	CurseForgeAPIKey    string `json:"curseForgeApiKey,omitempty"`
+	CurseForgeAPIKeySet bool   `json:"curseForgeApiKeySet,omitempty"`
+}
+
+// redactSecrets clears the values clients must not see.
+func (s *AppSettings) redactSecrets() {
+	s.LoginPasswordHash = ""
+	s.CurseForgeAPIKeySet = s.CurseForgeAPIKey != ""
+	s.CurseForgeAPIKey = ""
 }
 
 var (
@@ -49,6 +91,26 @@ func defaultUserAgent() string {
 	return "Orexa-Panel/1.0 (+https://github.com/pbarrera813/Orexa-Panel)"
 }
 
+func defaultRestartWarningMinutes() []int {
+	return []int{10, 5, 1}
+}
+
+// normalizeRestartWarningMinutes drops invalid and duplicate entries and
+// orders the warnings from the earliest to the latest.
+func normalizeRestartWarningMinutes(values []int) []int {
+	seen := make(map[int]bool, len(values))
+	out := make([]int, 0, len(values))
+	for _, v := range values {
+		if v <= 0 || v > 1440 || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(out)))
+	return out
+}
+
 func defaultLoginUser() string {
 	return "mcpanel"
 }
@@ -198,38 +260,37 @@ func applySettingsDefaults(cfg *AppSettings) {
 	if cfg.DefaultFlags == "" {
 		cfg.DefaultFlags = "none"
 	}
-	if cfg.StatusPollInterval <= 0 {
-		cfg.StatusPollInterval = 3
-	}
-	if cfg.StatusPollInterval > 30 {
-		cfg.StatusPollInterval = 30
-	}
-	if cfg.TpsPollInterval <= 0 {
-		cfg.TpsPollInterval = 30
+	cfg.StatusPollInterval, _ = statusPollRange.clamp(cfg.StatusPollInterval)
+	cfg.TpsPollInterval, _ = tpsPollRange.clamp(cfg.TpsPollInterval)
+	cfg.PlayerSyncInterval, _ = playerSyncRange.clamp(cfg.PlayerSyncInterval)
+	cfg.PingPollInterval, _ = pingPollRange.clamp(cfg.PingPollInterval)
+	if cfg.DefaultPortRangeStart <= 0 {
+		cfg.DefaultPortRangeStart = defaultPortRangeStart
 	}
-	if cfg.TpsPollInterval < 5 {
-		cfg.TpsPollInterval = 5
+	if cfg.DefaultPortRangeEnd <= 0 {
+		cfg.DefaultPortRangeEnd = defaultPortRangeEnd
 	}
-	if cfg.TpsPollInterval > 300 {
-		cfg.TpsPollInterval = 300
+	cfg.DefaultMaxPlayers, _ = maxPlayersRange.clamp(cfg.DefaultMaxPlayers)
+	cfg.SessionTTLHours, _ = sessionTTLRange.clamp(cfg.SessionTTLHours)
+	cfg.LoginMaxFailures, _ = loginMaxFailuresRange.clamp(cfg.LoginMaxFailures)
+	cfg.LoginWindowMinutes, _ = loginWindowRange.clamp(cfg.LoginWindowMinutes)
+	cfg.LoginLockoutMinutes, _ = loginLockoutRange.clamp(cfg.LoginLockoutMinutes)
+	if cfg.AccessAllowList == nil {
+		cfg.AccessAllowList = []string{}
 	}
-	if cfg.PlayerSyncInterval <= 0 {
-		cfg.PlayerSyncInterval = 15
+	if cfg.AccessDenyList == nil {
+		cfg.AccessDenyList = []string{}
 	}
-	if cfg.PlayerSyncInterval < 2 {
-		cfg.PlayerSyncInterval = 2
+	if !validBackupSchedules[cfg.DefaultBackupSchedule] {
+		cfg.DefaultBackupSchedule = ""
 	}
-	if cfg.PlayerSyncInterval > 300 {
-		cfg.PlayerSyncInterval = 300
+	cfg.RestartWarningMinutes = normalizeRestartWarningMinutes(cfg.RestartWarningMinutes)
+	if len(cfg.RestartWarningMinutes) == 0 {
+		cfg.RestartWarningMinutes = defaultRestartWarningMinutes()
 	}
-	if cfg.PingPollInterval <= 0 {
-		cfg.PingPollInterval = 20
-	}
-	if cfg.PingPollInterval < 5 {
-		cfg.PingPollInterval = 5
-	}
-	if cfg.PingPollInterval > 300 {
-		cfg.PingPollInterval = 300
+	cfg.Locale = normalizeLocale(cfg.Locale)
+	if cfg.Locale == "" {
+		cfg.Locale = defaultLocale
 	}
 	if strings.TrimSpace(cfg.LoginUser) == "" {
 		cfg.LoginUser = defaultLoginUser()
@@ -240,13 +301,9 @@ func (m *Manager) loadSettings() error {
 	m.settingsMu.Lock()
 	defer m.settingsMu.Unlock()
 
-	if m.settingsFile == "" {
-		return fmt.Errorf("settings file path is not configured")
-	}
-
-	data, err := os.ReadFile(m.settingsFile)
+	data, err := m.storage().Load(storeDocSettings)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, os.ErrNotExist) {
 			defaultHash, hashErr := hashPassword(defaultLoginPassword())
 			if hashErr != nil {
 				return hashErr
@@ -258,9 +315,6 @@ func (m *Manager) loadSettings() error {
 			}
 			applySettingsDefaults(&m.settings)
 			setUserAgentOverride(m.settings.UserAgent)
-			if err := os.MkdirAll(filepath.Dir(m.settingsFile), 0755); err != nil {
-				return fmt.Errorf("failed to create settings directory: %w", err)
-			}
 			if err := m.persistSettings(); err != nil {
 				return err
 			}
@@ -271,7 +325,15 @@ func (m *Manager) loadSettings() error {
 
 	var cfg AppSettings
 	if err := json.Unmarshal(data, &cfg); err != nil {
-		return fmt.Errorf("failed to parse settings file: %w", err)
+		log.Printf("settings.json is corrupt (%v); attempting recovery from panel backups", err)
+		recovered, recoverErr := m.recoverDataFile(storeDocSettings, validateSettingsJSON)
+		if recoverErr != nil {
+			return fmt.Errorf("failed to parse settings file: %w (recovery failed: %v)", err, recoverErr)
+		}
+		cfg = AppSettings{}
+		if err := json.Unmarshal(recovered, &cfg); err != nil {
+			return fmt.Errorf("failed to parse recovered settings file: %w", err)
+		}
 	}
 	cfg.UserAgent = strings.TrimSpace(cfg.UserAgent)
 	if cfg.UserAgent == "" {
@@ -290,9 +352,11 @@ func (m *Manager) loadSettings() error {
 		cfg.LoginPasswordHash = defaultHash
 		needsPersist = true
 	}
+	cfg.CurseForgeAPIKeySet = false
 	applySettingsDefaults(&cfg)
 	m.settings = cfg
 	setUserAgentOverride(cfg.UserAgent)
+	setCurseForgeAPIKeyOverride(cfg.CurseForgeAPIKey)
 	if needsPersist {
 		if err := m.persistSettings(); err != nil {
 			return err
@@ -302,22 +366,12 @@ func (m *Manager) loadSettings() error {
 }
 
 func (m *Manager) persistSettings() error {
-	if m.settingsFile == "" {
-		return fmt.Errorf("settings file path is not configured")
-	}
 	data, err := json.MarshalIndent(m.settings, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal settings: %w", err)
 	}
-	tmpFile := m.settingsFile + ".tmp"
-	if err := os.WriteFile(tmpFile, data, 0600); err != nil {
-		return fmt.Errorf("failed to write temp settings: %w", err)
-	}
-	if err := os.Rename(tmpFile, m.settingsFile); err != nil {
-		return fmt.Errorf("failed to rename settings file: %w", err)
-	}
-	if err := os.Chmod(m.settingsFile, 0600); err != nil {
-		return fmt.Errorf("failed to secure settings file permissions: %w", err)
+	if err := m.storage().Save(storeDocSettings, data); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
 	}
 	return nil
 }
@@ -330,119 +384,10 @@ func (m *Manager) GetSettings() AppSettings {
 		s.UserAgent = effectiveUserAgent()
 	}
 	applySettingsDefaults(&s)
-	s.LoginPasswordHash = ""
+	s.redactSecrets()
 	return s
 }
 
-func (m *Manager) UpdateAppSettings(
-	userAgent,
-	defaultMinRAM,
-	defaultMaxRAM,
-	defaultFlags string,
-	statusPollInterval,
-	tpsPollInterval,
-	playerSyncInterval,
-	pingPollInterval int,
-	loginUser,
-	loginPassword string,
-) (AppSettings, error) {
-	m.settingsMu.Lock()
-	defer m.settingsMu.Unlock()
-
-	ua := strings.TrimSpace(userAgent)
-	if ua == "" {
-		ua = defaultUserAgent()
-	}
-
-	if statusPollInterval <= 0 {
-		statusPollInterval = 3
-	}
-	if statusPollInterval > 30 {
-		statusPollInterval = 30
-	}
-	if tpsPollInterval <= 0 {
-		tpsPollInterval = 30
-	}
-	if tpsPollInterval < 5 {
-		tpsPollInterval = 5
-	}
-	if tpsPollInterval > 300 {
-		tpsPollInterval = 300
-	}
-	if playerSyncInterval <= 0 {
-		playerSyncInterval = 15
-	}
-	if playerSyncInterval < 2 {
-		playerSyncInterval = 2
-	}
-	if playerSyncInterval > 300 {
-		playerSyncInterval = 300
-	}
-	if pingPollInterval <= 0 {
-		pingPollInterval = 20
-	}
-	if pingPollInterval < 5 {
-		pingPollInterval = 5
-	}
-	if pingPollInterval > 300 {
-		pingPollInterval = 300
-	}
-	loginUser = strings.TrimSpace(loginUser)
-	if loginUser == "" {
-		loginUser = m.settings.LoginUser
-	}
-	if loginUser == "" {
-		loginUser = defaultLoginUser()
-	}
-	if len(loginUser) < 4 || len(loginUser) > 12 {
-		return AppSettings{}, fmt.Errorf("username must be between 4 and 12 characters")
-	}
-
-	passwordHash := m.settings.LoginPasswordHash
-	if strings.TrimSpace(loginPassword) != "" {
-		if len(loginPassword) < LoginPasswordMinLength {
-			return AppSettings{}, fmt.Errorf("password must be at least %d characters", LoginPasswordMinLength)
-		}
-		hashed, err := hashPassword(loginPassword)
-		if err != nil {
-			return AppSettings{}, err
-		}
-		passwordHash = hashed
-	}
-	if strings.TrimSpace(passwordHash) == "" {
-		hashed, err := hashPassword(defaultLoginPassword())
-		if err != nil {
-			return AppSettings{}, err
-		}
-		passwordHash = hashed
-	}
-
-	m.settings = AppSettings{
-		UserAgent:          ua,
-		DefaultMinRAM:      defaultMinRAM,
-		DefaultMaxRAM:      defaultMaxRAM,
-		DefaultFlags:       defaultFlags,
-		StatusPollInterval: statusPollInterval,
-		TpsPollInterval:    tpsPollInterval,
-		PlayerSyncInterval: playerSyncInterval,
-		PingPollInterval:   pingPollInterval,
-		LoginUser:          loginUser,
-		LoginPasswordHash:  passwordHash,
-	}
-	applySettingsDefaults(&m.settings)
-	setUserAgentOverride(ua)
-
-	if err := os.MkdirAll(filepath.Dir(m.settingsFile), 0755); err != nil {
-		return AppSettings{}, fmt.Errorf("failed to create settings directory: %w", err)
-	}
-	if err := m.persistSettings(); err != nil {
-		return AppSettings{}, err
-	}
-	result := m.settings
-	result.LoginPasswordHash = ""
-	return result, nil
-}
-
 func (m *Manager) ValidateLogin(username, password string) bool {
 	trimmedUsername := strings.TrimSpace(username)
 
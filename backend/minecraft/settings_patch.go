@@ -0,0 +1,154 @@
+package minecraft
+
+// AppSettingsPatch is a partial settings change. Only fields present in the
+// request are touched; everything else keeps its saved value, so a client
+// changing one interval cannot reset the login or user agent by omission.
+type AppSettingsPatch struct {
+	UserAgent             *string   `json:"userAgent"`
+	DefaultMinRAM         *string   `json:"defaultMinRam"`
+	DefaultMaxRAM         *string   `json:"defaultMaxRam"`
+	DefaultFlags          *string   `json:"defaultFlags"`
+	StatusPollInterval    *int      `json:"statusPollInterval"`
+	TpsPollInterval       *int      `json:"tpsPollInterval"`
+	PlayerSyncInterval    *int      `json:"playerSyncInterval"`
+	PingPollInterval      *int      `json:"pingPollInterval"`
+	DefaultPortRangeStart *int      `json:"defaultPortRangeStart"`
+	DefaultPortRangeEnd   *int      `json:"defaultPortRangeEnd"`
+	DefaultMaxPlayers     *int      `json:"defaultMaxPlayers"`
+	DefaultBackupSchedule *string   `json:"defaultBackupSchedule"`
+	SessionTTLHours       *int      `json:"sessionTtlHours"`
+	LoginMaxFailures      *int      `json:"loginMaxFailures"`
+	LoginWindowMinutes    *int      `json:"loginWindowMinutes"`
+	LoginLockoutMinutes   *int      `json:"loginLockoutMinutes"`
+	SessionBindIP         *bool     `json:"sessionBindIp"`
+	SessionBindUserAgent  *bool     `json:"sessionBindUserAgent"`
+	CSPReportOnly         *bool     `json:"cspReportOnly"`
+	AccessAllowList       *[]string `json:"accessAllowList"`
+	AccessDenyList        *[]string `json:"accessDenyList"`
+	RequesterIP           string    `json:"-"`
+	RequesterDirect       bool      `json:"-"`
+	RestartWarningMinutes *[]int    `json:"restartWarningMinutes"`
+	Locale                *string   `json:"locale"`
+	LoginUser             *string   `json:"loginUser"`
+	LoginPassword         *string   `json:"loginPassword"`
+	CurseForgeAPIKey      *string   `json:"curseForgeApiKey"`
+}
+
+// update fills the fields missing from the patch with the current settings
+// and returns the full update plus the names of the fields that were sent.
+func (p AppSettingsPatch) update(current AppSettings) (AppSettingsUpdate, map[string]bool) {
+	req := AppSettingsUpdate{
+		UserAgent:             current.UserAgent,
+		DefaultMinRAM:         current.DefaultMinRAM,
+		DefaultMaxRAM:         current.DefaultMaxRAM,
+		DefaultFlags:          current.DefaultFlags,
+		StatusPollInterval:    current.StatusPollInterval,
+		TpsPollInterval:       current.TpsPollInterval,
+		PlayerSyncInterval:    current.PlayerSyncInterval,
+		PingPollInterval:      current.PingPollInterval,
+		DefaultPortRangeStart: current.DefaultPortRangeStart,
+		DefaultPortRangeEnd:   current.DefaultPortRangeEnd,
+		DefaultMaxPlayers:     current.DefaultMaxPlayers,
+		DefaultBackupSchedule: current.DefaultBackupSchedule,
+		SessionTTLHours:       current.SessionTTLHours,
+		LoginMaxFailures:      current.LoginMaxFailures,
+		LoginWindowMinutes:    current.LoginWindowMinutes,
+		LoginLockoutMinutes:   current.LoginLockoutMinutes,
+		SessionBindIP:         current.SessionBindIP,
+		SessionBindUserAgent:  current.SessionBindUserAgent,
+		CSPReportOnly:         current.CSPReportOnly,
+		AccessAllowList:       current.AccessAllowList,
+		AccessDenyList:        current.AccessDenyList,
+		RequesterIP:           p.RequesterIP,
+		RequesterDirect:       p.RequesterDirect,
+		RestartWarningMinutes: current.RestartWarningMinutes,
+		Locale:                current.Locale,
+		LoginUser:             current.LoginUser,
+		CurseForgeAPIKey:      p.CurseForgeAPIKey,
+	}
+	sent := make(map[string]bool)
+	setString := func(field string, value *string, target *string) {
+		if value != nil {
+			*target = *value
+			sent[field] = true
+		}
+	}
+	setInt := func(field string, value *int, target *int) {
+		if value != nil {
+			*target = *value
+			sent[field] = true
+		}
+	}
+	setBool := func(field string, value *bool, target *bool) {
+		if value != nil {
+			*target = *value
+			sent[field] = true
+		}
+	}
+
+	setString("userAgent", p.UserAgent, &req.UserAgent)
+	setString("defaultMinRam", p.DefaultMinRAM, &req.DefaultMinRAM)
+	setString("defaultMaxRam", p.DefaultMaxRAM, &req.DefaultMaxRAM)
+	setString("defaultFlags", p.DefaultFlags, &req.DefaultFlags)
+	setInt("statusPollInterval", p.StatusPollInterval, &req.StatusPollInterval)
+	setInt("tpsPollInterval", p.TpsPollInterval, &req.TpsPollInterval)
+	setInt("playerSyncInterval", p.PlayerSyncInterval, &req.PlayerSyncInterval)
+	setInt("pingPollInterval", p.PingPollInterval, &req.PingPollInterval)
+	setInt("defaultPortRangeStart", p.DefaultPortRangeStart, &req.DefaultPortRangeStart)
+	setInt("defaultPortRangeEnd", p.DefaultPortRangeEnd, &req.DefaultPortRangeEnd)
+	setInt("defaultMaxPlayers", p.DefaultMaxPlayers, &req.DefaultMaxPlayers)
+	setString("defaultBackupSchedule", p.DefaultBackupSchedule, &req.DefaultBackupSchedule)
+	setInt("sessionTtlHours", p.SessionTTLHours, &req.SessionTTLHours)
+	setInt("loginMaxFailures", p.LoginMaxFailures, &req.LoginMaxFailures)
+	setInt("loginWindowMinutes", p.LoginWindowMinutes, &req.LoginWindowMinutes)
+	setInt("loginLockoutMinutes", p.LoginLockoutMinutes, &req.LoginLockoutMinutes)
+	setBool("sessionBindIp", p.SessionBindIP, &req.SessionBindIP)
+	setBool("sessionBindUserAgent", p.SessionBindUserAgent, &req.SessionBindUserAgent)
+	setBool("cspReportOnly", p.CSPReportOnly, &req.CSPReportOnly)
+	setStrings := func(field string, value *[]string, target *[]string) {
+		if value != nil {
+			*target = *value
+			sent[field] = true
+		}
+	}
+	setStrings("accessAllowList", p.AccessAllowList, &req.AccessAllowList)
+	setStrings("accessDenyList", p.AccessDenyList, &req.AccessDenyList)
+	if p.RestartWarningMinutes != nil {
+		req.RestartWarningMinutes = *p.RestartWarningMinutes
+		if req.RestartWarningMinutes == nil {
+			req.RestartWarningMinutes = []int{}
+		}
+		sent["restartWarningMinutes"] = true
+	}
+	setString("locale", p.Locale, &req.Locale)
+	setString("loginUser", p.LoginUser, &req.LoginUser)
+	setString("loginPassword", p.LoginPassword, &req.LoginPassword)
+	if p.CurseForgeAPIKey != nil {
+		sent["curseForgeApiKey"] = true
+	}
+	return req, sent
+}
+
+// sentSettingResults keeps the results for fields the client sent, plus any
+// rejection caused by a field it did not send (such as the saved minimum RAM
+// exceeding a newly lowered maximum).
+func sentSettingResults(fields []SettingsFieldResult, sent map[string]bool) []SettingsFieldResult {
+	out := make([]SettingsFieldResult, 0, len(sent))
+	for _, field := range fields {
+		if sent[field.Field] || field.Status == SettingRejected {
+			out = append(out, field)
+		}
+	}
+	return out
+}
+
+// PatchAppSettings applies only the fields present in patch. Results are
+// reported like UpdateAppSettings, limited to the fields that were sent.
+func (m *Manager) PatchAppSettings(patch AppSettingsPatch) (AppSettings, []SettingsFieldResult, error) {
+	m.settingsMu.Lock()
+	defer m.settingsMu.Unlock()
+
+	req, sent := patch.update(m.settings)
+	next, fields := m.planSettingsUpdateLocked(req)
+	return m.commitSettingsLocked(next, sentSettingResults(fields, sent), req.LoginPassword)
+}
@@ -0,0 +1,435 @@
+package minecraft
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// AppSettingsUpdate is a settings change as submitted by the panel. Empty
+// values either take the default or keep the current value; the per-field
+// results say which.
+type AppSettingsUpdate struct {
+	UserAgent             string   `json:"userAgent"`
+	DefaultMinRAM         string   `json:"defaultMinRam"`
+	DefaultMaxRAM         string   `json:"defaultMaxRam"`
+	DefaultFlags          string   `json:"defaultFlags"`
+	StatusPollInterval    int      `json:"statusPollInterval"`
+	TpsPollInterval       int      `json:"tpsPollInterval"`
+	PlayerSyncInterval    int      `json:"playerSyncInterval"`
+	PingPollInterval      int      `json:"pingPollInterval"`
+	DefaultPortRangeStart int      `json:"defaultPortRangeStart"`
+	DefaultPortRangeEnd   int      `json:"defaultPortRangeEnd"`
+	DefaultMaxPlayers     int      `json:"defaultMaxPlayers"`
+	DefaultBackupSchedule string   `json:"defaultBackupSchedule"`
+	SessionTTLHours       int      `json:"sessionTtlHours"`
+	LoginMaxFailures      int      `json:"loginMaxFailures"`
+	LoginWindowMinutes    int      `json:"loginWindowMinutes"`
+	LoginLockoutMinutes   int      `json:"loginLockoutMinutes"`
+	SessionBindIP         bool     `json:"sessionBindIp"`
+	SessionBindUserAgent  bool     `json:"sessionBindUserAgent"`
+	CSPReportOnly         bool     `json:"cspReportOnly"`
+	AccessAllowList       []string `json:"accessAllowList"`
+	AccessDenyList        []string `json:"accessDenyList"`
+	// RequesterIP is the address of the client making the change. When set,
+	// access lists that would lock that client out are rejected.
+	// RequesterDirect says the request reached the panel without a proxy.
+	RequesterIP           string `json:"-"`
+	RequesterDirect       bool   `json:"-"`
+	RestartWarningMinutes []int  `json:"restartWarningMinutes"`
+	Locale                string `json:"locale"`
+	LoginUser             string `json:"loginUser"`
+	LoginPassword         string `json:"loginPassword"`
+	// CurseForgeAPIKey replaces the saved key when set; an empty string
+	// removes it and nil keeps it.
+	CurseForgeAPIKey *string `json:"curseForgeApiKey"`
+}
+
+// Outcomes reported for each submitted setting.
+const (
+	SettingApplied  = "applied"
+	SettingClamped  = "clamped"
+	SettingRejected = "rejected"
+)
+
+// SettingsFieldResult says what happened to one submitted setting and which
+// value is in effect. Clamped values were adjusted to fit; a rejected value
+// blocks the whole update.
+type SettingsFieldResult struct {
+	Field     string `json:"field"`
+	Status    string `json:"status"`
+	Requested any    `json:"requested,omitempty"`
+	Effective any    `json:"effective,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// SettingsRejectedError is returned when at least one field was rejected.
+type SettingsRejectedError struct {
+	Fields []SettingsFieldResult
+}
+
+func (e *SettingsRejectedError) Error() string {
+	for _, field := range e.Fields {
+		if field.Status == SettingRejected {
+			return field.Message
+		}
+	}
+	return "settings were rejected"
+}
+
+// intSettingRange bounds an interval setting. Zero or negative values mean
+// "not set" and take the default.
+type intSettingRange struct {
+	def, min, max int
+}
+
+var (
+	statusPollRange = intSettingRange{def: 3, min: 1, max: 30}
+	tpsPollRange    = intSettingRange{def: 30, min: 5, max: 300}
+	playerSyncRange = intSettingRange{def: 15, min: 2, max: 300}
+	pingPollRange   = intSettingRange{def: 20, min: 5, max: 300}
+	maxPlayersRange = intSettingRange{def: 20, min: 1, max: 1000}
+
+	sessionTTLRange       = intSettingRange{def: 7 * 24, min: 1, max: 30 * 24}
+	loginMaxFailuresRange = intSettingRange{def: 10, min: 3, max: 100}
+	loginWindowRange      = intSettingRange{def: 15, min: 1, max: 1440}
+	loginLockoutRange     = intSettingRange{def: 15, min: 1, max: 1440}
+)
+
+const (
+	defaultPortRangeStart = 25565
+	defaultPortRangeEnd   = 25665
+)
+
+var jvmFlagPresets = []string{"none", "aikars", "zgc", "velocity", "modded"}
+
+func (r intSettingRange) clamp(value int) (int, string) {
+	switch {
+	case value <= 0:
+		return r.def, fmt.Sprintf("not set; using default %d", r.def)
+	case value < r.min:
+		return r.min, fmt.Sprintf("raised to the minimum of %d", r.min)
+	case value > r.max:
+		return r.max, fmt.Sprintf("lowered to the maximum of %d", r.max)
+	default:
+		return value, ""
+	}
+}
+
+// settingsPlan collects per-field results while an update is checked.
+type settingsPlan struct {
+	fields []SettingsFieldResult
+}
+
+func (p *settingsPlan) add(field, status string, requested, effective any, message string) {
+	p.fields = append(p.fields, SettingsFieldResult{
+		Field:     field,
+		Status:    status,
+		Requested: requested,
+		Effective: effective,
+		Message:   message,
+	})
+}
+
+// reject marks an already reported field as rejected, keeping its
+// requested value.
+func (p *settingsPlan) reject(field string, effective any, message string) {
+	for i := range p.fields {
+		if p.fields[i].Field == field {
+			p.fields[i].Status = SettingRejected
+			p.fields[i].Effective = effective
+			p.fields[i].Message = message
+			return
+		}
+	}
+	p.add(field, SettingRejected, nil, effective, message)
+}
+
+func (p *settingsPlan) interval(field string, r intSettingRange, value int) int {
+	effective, note := r.clamp(value)
+	status := SettingApplied
+	if note != "" {
+		status = SettingClamped
+	}
+	p.add(field, status, value, effective, note)
+	return effective
+}
+
+func hasRejectedSetting(fields []SettingsFieldResult) bool {
+	for _, field := range fields {
+		if field.Status == SettingRejected {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRAMSetting(value string) (float64, error) {
+	gb, err := strconv.ParseFloat(value, 64)
+	if err != nil || gb <= 0 {
+		return 0, fmt.Errorf("must be a positive number of GB")
+	}
+	return gb, nil
+}
+
+// planSettingsUpdateLocked checks req against the current settings and
+// returns the settings it would produce. The password hash is left as is;
+// callers hash a new password only when applying. Caller holds settingsMu.
+func (m *Manager) planSettingsUpdateLocked(req AppSettingsUpdate) (AppSettings, []SettingsFieldResult) {
+	current := m.settings
+	next := current
+	plan := &settingsPlan{}
+
+	if ua := strings.TrimSpace(req.UserAgent); ua == "" {
+		next.UserAgent = defaultUserAgent()
+		plan.add("userAgent", SettingClamped, req.UserAgent, next.UserAgent, "empty; using the default user agent")
+	} else {
+		next.UserAgent = ua
+		plan.add("userAgent", SettingApplied, req.UserAgent, ua, "")
+	}
+
+	ramFields := []struct {
+		field, value, def, current string
+		target                     *string
+	}{
+		{"defaultMinRam", req.DefaultMinRAM, "0.5", current.DefaultMinRAM, &next.DefaultMinRAM},
+		{"defaultMaxRam", req.DefaultMaxRAM, "1", current.DefaultMaxRAM, &next.DefaultMaxRAM},
+	}
+	for _, ram := range ramFields {
+		value := strings.TrimSpace(ram.value)
+		if value == "" {
+			*ram.target = ram.def
+			plan.add(ram.field, SettingClamped, ram.value, ram.def, "not set; using default "+ram.def)
+			continue
+		}
+		if _, err := parseRAMSetting(value); err != nil {
+			plan.add(ram.field, SettingRejected, ram.value, ram.current, ram.field+" "+err.Error())
+			continue
+		}
+		*ram.target = value
+		plan.add(ram.field, SettingApplied, ram.value, value, "")
+	}
+	if minGB, err := parseRAMSetting(next.DefaultMinRAM); err == nil {
+		if maxGB, err := parseRAMSetting(next.DefaultMaxRAM); err == nil && minGB > maxGB {
+			plan.reject("defaultMinRam", current.DefaultMinRAM, "defaultMinRam cannot be greater than defaultMaxRam")
+		}
+	}
+
+	flags := strings.ToLower(strings.TrimSpace(req.DefaultFlags))
+	switch {
+	case flags == "":
+		next.DefaultFlags = "none"
+		plan.add("defaultFlags", SettingClamped, req.DefaultFlags, "none", "not set; using default none")
+	case !containsString(jvmFlagPresets, flags):
+		plan.add("defaultFlags", SettingRejected, req.DefaultFlags, current.DefaultFlags,
+			"defaultFlags must be one of: "+strings.Join(jvmFlagPresets, ", "))
+	default:
+		next.DefaultFlags = flags
+		plan.add("defaultFlags", SettingApplied, req.DefaultFlags, flags, "")
+	}
+
+	next.StatusPollInterval = plan.interval("statusPollInterval", statusPollRange, req.StatusPollInterval)
+	next.TpsPollInterval = plan.interval("tpsPollInterval", tpsPollRange, req.TpsPollInterval)
+	next.PlayerSyncInterval = plan.interval("playerSyncInterval", playerSyncRange, req.PlayerSyncInterval)
+	next.PingPollInterval = plan.interval("pingPollInterval", pingPollRange, req.PingPollInterval)
+	next.DefaultMaxPlayers = plan.interval("defaultMaxPlayers", maxPlayersRange, req.DefaultMaxPlayers)
+
+	portFields := []struct {
+		field      string
+		value, def int
+		current    int
+		target     *int
+	}{
+		{"defaultPortRangeStart", req.DefaultPortRangeStart, defaultPortRangeStart, current.DefaultPortRangeStart, &next.DefaultPortRangeStart},
+		{"defaultPortRangeEnd", req.DefaultPortRangeEnd, defaultPortRangeEnd, current.DefaultPortRangeEnd, &next.DefaultPortRangeEnd},
+	}
+	for _, port := range portFields {
+		switch {
+		case port.value <= 0:
+			*port.target = port.def
+			plan.add(port.field, SettingClamped, port.value, port.def, fmt.Sprintf("not set; using default %d", port.def))
+		case port.value < 1024 || port.value > 65535:
+			plan.add(port.field, SettingRejected, port.value, port.current, port.field+" must be between 1024 and 65535")
+		default:
+			*port.target = port.value
+			plan.add(port.field, SettingApplied, port.value, port.value, "")
+		}
+	}
+	if next.DefaultPortRangeStart > next.DefaultPortRangeEnd {
+		plan.reject("defaultPortRangeEnd", current.DefaultPortRangeEnd, "defaultPortRangeEnd cannot be lower than defaultPortRangeStart")
+	}
+
+	next.SessionTTLHours = plan.interval("sessionTtlHours", sessionTTLRange, req.SessionTTLHours)
+	next.LoginMaxFailures = plan.interval("loginMaxFailures", loginMaxFailuresRange, req.LoginMaxFailures)
+	next.LoginWindowMinutes = plan.interval("loginWindowMinutes", loginWindowRange, req.LoginWindowMinutes)
+	next.LoginLockoutMinutes = plan.interval("loginLockoutMinutes", loginLockoutRange, req.LoginLockoutMinutes)
+	next.SessionBindIP = req.SessionBindIP
+	plan.add("sessionBindIp", SettingApplied, req.SessionBindIP, req.SessionBindIP, "")
+	next.SessionBindUserAgent = req.SessionBindUserAgent
+	plan.add("sessionBindUserAgent", SettingApplied, req.SessionBindUserAgent, req.SessionBindUserAgent, "")
+	next.CSPReportOnly = req.CSPReportOnly
+	plan.add("cspReportOnly", SettingApplied, req.CSPReportOnly, req.CSPReportOnly, "")
+
+	accessFields := []struct {
+		field   string
+		value   []string
+		current []string
+		target  *[]string
+	}{
+		{"accessAllowList", req.AccessAllowList, current.AccessAllowList, &next.AccessAllowList},
+		{"accessDenyList", req.AccessDenyList, current.AccessDenyList, &next.AccessDenyList},
+	}
+	for _, access := range accessFields {
+		entries, err := normalizeAccessEntries(access.field, access.value)
+		if err != nil {
+			plan.add(access.field, SettingRejected, access.value, access.current, err.Error())
+			continue
+		}
+		*access.target = entries
+		plan.add(access.field, SettingApplied, access.value, entries, "")
+	}
+	if req.RequesterIP != "" && !next.IPAccessPermitted(req.RequesterIP, req.RequesterDirect) && current.IPAccessPermitted(req.RequesterIP, req.RequesterDirect) {
+		plan.reject("accessAllowList", current.AccessAllowList,
+			fmt.Sprintf("these access lists would block your own address %s", req.RequesterIP))
+	}
+
+	schedule := strings.ToLower(strings.TrimSpace(req.DefaultBackupSchedule))
+	if !validBackupSchedules[schedule] {
+		plan.add("defaultBackupSchedule", SettingRejected, req.DefaultBackupSchedule, current.DefaultBackupSchedule,
+			"defaultBackupSchedule must be empty or one of: daily, weekly, monthly, sixmonths, yearly")
+	} else {
+		next.DefaultBackupSchedule = schedule
+		plan.add("defaultBackupSchedule", SettingApplied, req.DefaultBackupSchedule, schedule, "")
+	}
+
+	if req.RestartWarningMinutes != nil {
+		warnings := normalizeRestartWarningMinutes(req.RestartWarningMinutes)
+		switch {
+		case len(warnings) == 0:
+			next.RestartWarningMinutes = defaultRestartWarningMinutes()
+			plan.add("restartWarningMinutes", SettingClamped, req.RestartWarningMinutes, next.RestartWarningMinutes, "no valid entries; using the defaults")
+		case len(warnings) != len(req.RestartWarningMinutes):
+			next.RestartWarningMinutes = warnings
+			plan.add("restartWarningMinutes", SettingClamped, req.RestartWarningMinutes, warnings, "dropped duplicate entries and entries outside 1-1440 minutes")
+		default:
+			next.RestartWarningMinutes = warnings
+			plan.add("restartWarningMinutes", SettingApplied, req.RestartWarningMinutes, warnings, "")
+		}
+	}
+
+	if strings.TrimSpace(req.Locale) != "" {
+		if locale := normalizeLocale(req.Locale); locale == "" {
+			plan.add("locale", SettingRejected, req.Locale, current.Locale,
+				"locale must be one of: "+strings.Join(SupportedLocales(), ", "))
+		} else {
+			next.Locale = locale
+			status, note := SettingApplied, ""
+			if locale != strings.TrimSpace(req.Locale) {
+				status, note = SettingClamped, "using the "+locale+" translation"
+			}
+			plan.add("locale", status, req.Locale, locale, note)
+		}
+	}
+
+	if loginUser := strings.TrimSpace(req.LoginUser); loginUser != "" {
+		if len(loginUser) < 4 || len(loginUser) > 12 {
+			plan.add("loginUser", SettingRejected, req.LoginUser, current.LoginUser, "username must be between 4 and 12 characters")
+		} else {
+			next.LoginUser = loginUser
+			plan.add("loginUser", SettingApplied, req.LoginUser, loginUser, "")
+		}
+	}
+
+	// The password is never echoed back, only whether it would be accepted.
+	if strings.TrimSpace(req.LoginPassword) != "" {
+		if len(req.LoginPassword) < LoginPasswordMinLength {
+			plan.add("loginPassword", SettingRejected, nil, nil, fmt.Sprintf("password must be at least %d characters", LoginPasswordMinLength))
+		} else {
+			plan.add("loginPassword", SettingApplied, nil, nil, "")
+		}
+	}
+
+	// Like the password, the key is never echoed back.
+	if req.CurseForgeAPIKey != nil {
+		key := strings.TrimSpace(*req.CurseForgeAPIKey)
+		switch {
+		case key == "":
+			next.CurseForgeAPIKey = ""
+			plan.add("curseForgeApiKey", SettingApplied, nil, nil, "removed")
+		case len(key) > 256 || strings.ContainsFunc(key, unicode.IsSpace):
+			plan.add("curseForgeApiKey", SettingRejected, nil, nil, "curseForgeApiKey must be a single token of at most 256 characters")
+		default:
+			next.CurseForgeAPIKey = key
+			plan.add("curseForgeApiKey", SettingApplied, nil, nil, "")
+		}
+	}
+
+	applySettingsDefaults(&next)
+	return next, plan.fields
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAppSettings reports what UpdateAppSettings would do with req
+// without saving anything.
+func (m *Manager) ValidateAppSettings(req AppSettingsUpdate) (AppSettings, []SettingsFieldResult) {
+	m.settingsMu.RLock()
+	defer m.settingsMu.RUnlock()
+
+	next, fields := m.planSettingsUpdateLocked(req)
+	next.redactSecrets()
+	return next, fields
+}
+
+// UpdateAppSettings applies req and reports the outcome per field. If any
+// field is rejected nothing is saved and a *SettingsRejectedError is
+// returned with the same results.
+func (m *Manager) UpdateAppSettings(req AppSettingsUpdate) (AppSettings, []SettingsFieldResult, error) {
+	m.settingsMu.Lock()
+	defer m.settingsMu.Unlock()
+
+	next, fields := m.planSettingsUpdateLocked(req)
+	return m.commitSettingsLocked(next, fields, req.LoginPassword)
+}
+
+// commitSettingsLocked saves a planned update unless a field was rejected.
+// password is the new login password, or empty to keep the current one.
+// Caller holds settingsMu for writing.
+func (m *Manager) commitSettingsLocked(next AppSettings, fields []SettingsFieldResult, password string) (AppSettings, []SettingsFieldResult, error) {
+	if hasRejectedSetting(fields) {
+		return AppSettings{}, fields, &SettingsRejectedError{Fields: fields}
+	}
+
+	if strings.TrimSpace(password) != "" {
+		hashed, err := hashPassword(password)
+		if err != nil {
+			return AppSettings{}, fields, err
+		}
+		next.LoginPasswordHash = hashed
+	}
+	if strings.TrimSpace(next.LoginPasswordHash) == "" {
+		hashed, err := hashPassword(defaultLoginPassword())
+		if err != nil {
+			return AppSettings{}, fields, err
+		}
+		next.LoginPasswordHash = hashed
+	}
+
+	m.settings = next
+	setUserAgentOverride(next.UserAgent)
+	setCurseForgeAPIKeyOverride(next.CurseForgeAPIKey)
+	if err := m.persistSettings(); err != nil {
+		return AppSettings{}, fields, err
+	}
+	result := m.settings
+	result.redactSecrets()
+	return result, fields, nil
+}
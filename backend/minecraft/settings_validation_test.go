@@ -0,0 +1,218 @@
+package minecraft
+
+import (
+	"errors"
+	"testing"
+)
+
+func findSettingResult(fields []SettingsFieldResult, name string) SettingsFieldResult {
+	for _, field := range fields {
+		if field.Field == name {
+			return field
+		}
+	}
+	return SettingsFieldResult{}
+}
+
+func TestValidateAppSettingsReportsEachField(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	settings, fields := mgr.ValidateAppSettings(AppSettingsUpdate{
+		UserAgent:          "Custom/1.0",
+		DefaultMinRAM:      "0.5",
+		DefaultMaxRAM:      "2",
+		DefaultFlags:       "aikars",
+		StatusPollInterval: 45,
+		TpsPollInterval:    3,
+		PlayerSyncInterval: 10,
+	})
+	if got := findSettingResult(fields, "statusPollInterval"); got.Status != SettingClamped || got.Effective != 30 {
+		t.Fatalf("expected status poll to be clamped to 30, got %+v", got)
+	}
+	if got := findSettingResult(fields, "tpsPollInterval"); got.Status != SettingClamped || got.Effective != 5 {
+		t.Fatalf("expected TPS poll to be raised to 5, got %+v", got)
+	}
+	if got := findSettingResult(fields, "pingPollInterval"); got.Status != SettingClamped || got.Effective != 20 {
+		t.Fatalf("expected unset ping poll to use the default, got %+v", got)
+	}
+	if got := findSettingResult(fields, "playerSyncInterval"); got.Status != SettingApplied {
+		t.Fatalf("expected player sync to be applied, got %+v", got)
+	}
+	if settings.StatusPollInterval != 30 || settings.DefaultFlags != "aikars" {
+		t.Fatalf("unexpected effective settings %+v", settings)
+	}
+	if mgr.GetSettings().StatusPollInterval != 3 {
+		t.Fatalf("dry run must not change the saved settings")
+	}
+}
+
+func TestUpdateAppSettingsRejectsWithoutSaving(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	_, fields, err := mgr.UpdateAppSettings(AppSettingsUpdate{
+		DefaultMinRAM:      "4",
+		DefaultMaxRAM:      "2",
+		DefaultFlags:       "turbo",
+		StatusPollInterval: 10,
+	})
+	var rejected *SettingsRejectedError
+	if !errors.As(err, &rejected) {
+		t.Fatalf("expected a SettingsRejectedError, got %v", err)
+	}
+	if got := findSettingResult(fields, "defaultMinRam"); got.Status != SettingRejected || got.Requested != "4" {
+		t.Fatalf("expected min RAM above max to be rejected, got %+v", got)
+	}
+	if got := findSettingResult(fields, "defaultFlags"); got.Status != SettingRejected {
+		t.Fatalf("expected unknown flags preset to be rejected, got %+v", got)
+	}
+	if got := mgr.GetSettings(); got.StatusPollInterval != 3 || got.DefaultMinRAM != "0.5" {
+		t.Fatalf("rejected update must not be saved, got %+v", got)
+	}
+
+	_, fields, err = mgr.UpdateAppSettings(AppSettingsUpdate{DefaultMinRAM: "1", DefaultMaxRAM: "2", StatusPollInterval: 10, LoginPassword: "a-long-password"})
+	if err != nil {
+		t.Fatalf("UpdateAppSettings failed: %v", err)
+	}
+	if got := findSettingResult(fields, "loginPassword"); got.Status != SettingApplied || got.Requested != nil {
+		t.Fatalf("password must be reported without its value, got %+v", got)
+	}
+	if got := mgr.GetSettings(); got.StatusPollInterval != 10 || got.DefaultMaxRAM != "2" {
+		t.Fatalf("expected update to be saved, got %+v", got)
+	}
+}
+
+func TestPatchAppSettingsLeavesOmittedFields(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	if _, _, err := mgr.UpdateAppSettings(AppSettingsUpdate{UserAgent: "Custom/1.0", DefaultMinRAM: "1", DefaultMaxRAM: "4", DefaultFlags: "aikars", LoginUser: "operator"}); err != nil {
+		t.Fatalf("UpdateAppSettings failed: %v", err)
+	}
+
+	interval := 12
+	settings, fields, err := mgr.PatchAppSettings(AppSettingsPatch{StatusPollInterval: &interval})
+	if err != nil {
+		t.Fatalf("PatchAppSettings failed: %v", err)
+	}
+	if len(fields) != 1 || fields[0].Field != "statusPollInterval" || fields[0].Status != SettingApplied {
+		t.Fatalf("expected only the sent field to be reported, got %+v", fields)
+	}
+	if settings.StatusPollInterval != 12 || settings.UserAgent != "Custom/1.0" || settings.LoginUser != "operator" || settings.DefaultFlags != "aikars" || settings.DefaultMaxRAM != "4" {
+		t.Fatalf("omitted fields must keep their values, got %+v", settings)
+	}
+
+	lowMax := "0.5"
+	_, fields, err = mgr.PatchAppSettings(AppSettingsPatch{DefaultMaxRAM: &lowMax})
+	if err == nil {
+		t.Fatalf("expected max RAM below the saved min RAM to be rejected")
+	}
+	if got := findSettingResult(fields, "defaultMinRam"); got.Status != SettingRejected {
+		t.Fatalf("expected the conflicting saved field to be reported, got %+v", fields)
+	}
+	if got := mgr.GetSettings(); got.DefaultMaxRAM != "4" {
+		t.Fatalf("rejected patch must not be saved, got %+v", got)
+	}
+}
+
+func TestCurseForgeAPIKeyIsWriteOnly(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+	defer setCurseForgeAPIKeyOverride("")
+	t.Setenv("ADPANEL_CURSEFORGE_API_KEY", "")
+
+	key := "$2a$10$abcdef"
+	settings, fields, err := mgr.PatchAppSettings(AppSettingsPatch{CurseForgeAPIKey: &key})
+	if err != nil {
+		t.Fatalf("PatchAppSettings failed: %v", err)
+	}
+	if got := findSettingResult(fields, "curseForgeApiKey"); got.Status != SettingApplied || got.Effective != nil {
+		t.Fatalf("expected the key to be applied without echoing it, got %+v", got)
+	}
+	if settings.CurseForgeAPIKey != "" || !settings.CurseForgeAPIKeySet {
+		t.Fatalf("expected only the key's presence to be reported, got %+v", settings)
+	}
+	if got := curseForgeAPIKey(); got != key {
+		t.Fatalf("expected the saved key to be used for CurseForge requests, got %q", got)
+	}
+
+	interval := 12
+	if _, _, err := mgr.PatchAppSettings(AppSettingsPatch{StatusPollInterval: &interval}); err != nil {
+		t.Fatalf("PatchAppSettings failed: %v", err)
+	}
+	if !mgr.GetSettings().CurseForgeAPIKeySet {
+		t.Fatalf("a patch without the key must keep it")
+	}
+
+	spaced := "two words"
+	if _, _, err := mgr.PatchAppSettings(AppSettingsPatch{CurseForgeAPIKey: &spaced}); err == nil {
+		t.Fatalf("expected a key with spaces to be rejected")
+	}
+
+	empty := ""
+	if settings, _, err = mgr.PatchAppSettings(AppSettingsPatch{CurseForgeAPIKey: &empty}); err != nil {
+		t.Fatalf("PatchAppSettings failed: %v", err)
+	}
+	if settings.CurseForgeAPIKeySet || curseForgeAPIKey() != "" {
+		t.Fatalf("expected an empty key to remove the saved one")
+	}
+}
+
+func TestCreateServerUsesSettingsDefaults(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	start, end, players, schedule, minRAM, maxRAM, flags := 30000, 30001, 8, "weekly", "1.5", "3", "aikars"
+	if _, _, err := mgr.PatchAppSettings(AppSettingsPatch{
+		DefaultPortRangeStart: &start,
+		DefaultPortRangeEnd:   &end,
+		DefaultMaxPlayers:     &players,
+		DefaultBackupSchedule: &schedule,
+		DefaultMinRAM:         &minRAM,
+		DefaultMaxRAM:         &maxRAM,
+		DefaultFlags:          &flags,
+	}); err != nil {
+		t.Fatalf("PatchAppSettings failed: %v", err)
+	}
+
+	first, err := mgr.CreateServer("First", "Vanilla", "1.21.10", 0, "", "", 0, "", false)
+	if err != nil {
+		t.Fatalf("CreateServer failed: %v", err)
+	}
+	if first.Port != 30000 || first.MinRAM != "1536M" || first.MaxRAM != "3072M" || first.MaxPlayers != 8 || first.Flags != "aikars" {
+		t.Fatalf("expected settings defaults, got %+v", first)
+	}
+	mgr.mu.RLock()
+	backupSchedule := mgr.configs[first.ID].BackupSchedule
+	mgr.mu.RUnlock()
+	if backupSchedule != "weekly" {
+		t.Fatalf("expected default backup schedule, got %q", backupSchedule)
+	}
+
+	second, err := mgr.CreateServer("Second", "Vanilla", "1.21.10", 0, "", "", 0, "", false)
+	if err != nil {
+		t.Fatalf("CreateServer failed: %v", err)
+	}
+	if second.Port != 30001 {
+		t.Fatalf("expected next free port 30001, got %d", second.Port)
+	}
+	if _, err := mgr.CreateServer("Third", "Vanilla", "1.21.10", 0, "", "", 0, "", false); err == nil {
+		t.Fatalf("expected an exhausted port range to fail")
+	}
+}
@@ -0,0 +1,238 @@
+package minecraft
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// refreshingDirName holds a backup being unpacked into a staging server
+// before it replaces the server's files. It is hidden from the file
+// browser and left out of backups.
+const refreshingDirName = ".adpanel-refreshing"
+
+// stagingKeptProperties are the server.properties settings a staging
+// server keeps from its own copy rather than taking from production.
+var stagingKeptProperties = []string{"server-ip", "rcon.port", "rcon.password", "query.port"}
+
+// stagingProperties are forced on a refreshed staging server so it is
+// never reachable like production: only whitelisted players can join, it
+// is not announced through query, and RCON is off unless the staging
+// server had it on.
+var stagingProperties = map[string]string{
+	"white-list":        "true",
+	"enforce-whitelist": "true",
+	"enable-query":      "false",
+	"enable-rcon":       "false",
+	"online-mode":       "true",
+}
+
+// validateStagingRefreshLocked checks that sourceID can refresh the staging
+// server cfg. The caller holds m.mu.
+func (m *Manager) validateStagingRefreshLocked(cfg *ServerConfig, sourceID string) (*ServerConfig, error) {
+	if sourceID == "" {
+		return nil, fmt.Errorf("refresh-staging tasks need a sourceId")
+	}
+	if sourceID == cfg.ID {
+		return nil, fmt.Errorf("a server cannot be refreshed from itself")
+	}
+	source, err := m.serverConfigForOperationLocked(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if isProxyType(cfg.Type) || isProxyType(source.Type) {
+		return nil, fmt.Errorf("staging refreshes are not available for proxies")
+	}
+	return source, nil
+}
+
+// StartStagingRefresh overwrites the staging server id with the latest
+// backup of the server sourceID and returns the job doing it. The staging
+// server keeps its name, port, RAM and panel settings; its files, type and
+// jar come from the backup. A running staging server is stopped first and
+// started again afterwards.
+func (m *Manager) StartStagingRefresh(id, sourceID string) (*Job, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	var source *ServerConfig
+	if err == nil {
+		source, err = m.validateStagingRefreshLocked(cfg, sourceID)
+	}
+	rs := m.running[id]
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if rs == nil {
+		return nil, errServerNotFound(id)
+	}
+	backups, err := m.ListBackups(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(backups) == 0 {
+		return nil, fmt.Errorf("%s has no backups to refresh from yet", source.Name)
+	}
+	backup := backups[0].Name
+	backupPath, incremental, err := m.resolveBackup(source, backup)
+	if err != nil {
+		return nil, err
+	}
+
+	job := m.newJob(JobTypeStagingRefresh, id)
+	go func() {
+		err := m.stagingRefreshJob(job, cfg, rs, source, backup, backupPath, incremental)
+		if err != nil {
+			log.Printf("[%s] Staging refresh from %s failed: %v", cfg.Name, source.Name, err)
+		}
+		job.finish(err)
+	}()
+	return m.GetJob(job.id)
+}
+
+func (m *Manager) stagingRefreshJob(job *jobHandle, cfg *ServerConfig, rs *runningServer, source *ServerConfig, backup, backupPath string, incremental bool) error {
+	id := cfg.ID
+	release, err := m.acquireServerOperation(job.ctx, id, operationRestore)
+	if err != nil {
+		return err
+	}
+	defer release()
+	job.start(fmt.Sprintf("Refreshing from %s backup %s", source.Name, backup))
+	if err := m.validateManagedServerDir(cfg.Dir); err != nil {
+		return m.configPathErrorLocked(id, err.Error())
+	}
+
+	status := rs.currentRuntime().status
+	if status == "Booting" || status == "Installing" {
+		return fmt.Errorf("server is busy (%s)", status)
+	}
+	wasRunning := status == "Running" || status == "Suspended"
+	if wasRunning {
+		job.progress(5, "Stopping the staging server")
+		if err := m.StopServer(id); err != nil {
+			return fmt.Errorf("failed to stop the staging server: %w", err)
+		}
+	}
+
+	rs.mu.Lock()
+	status = rs.status
+	if status != "Stopped" && status != "Crashed" && status != "Error" {
+		rs.mu.Unlock()
+		return fmt.Errorf("server must be stopped before it is refreshed")
+	}
+	// Installing keeps the server from being started while its files are
+	// replaced.
+	rs.status = "Installing"
+	rs.mu.Unlock()
+	restoreStatus := func() {
+		rs.mu.Lock()
+		rs.status = status
+		rs.mu.Unlock()
+	}
+
+	kept := parseServerPropertiesFile(filepath.Join(cfg.Dir, "server.properties"))
+	staging := filepath.Join(cfg.Dir, refreshingDirName)
+	if err := os.RemoveAll(staging); err != nil {
+		restoreStatus()
+		return err
+	}
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		restoreStatus()
+		return err
+	}
+	// The backup is unpacked next to the current files first, so a broken
+	// archive leaves the staging server as it was.
+	job.progress(15, "Extracting backup")
+	if err := extractBackup(job.ctx, backupPath, incremental, staging); err != nil {
+		os.RemoveAll(staging)
+		restoreStatus()
+		return err
+	}
+
+	job.progress(70, "Replacing the staging server's files")
+	if err := replaceServerFiles(cfg.Dir, staging); err != nil {
+		restoreStatus()
+		return err
+	}
+
+	job.progress(85, "Applying staging settings")
+	values := map[string]string{"server-port": fmt.Sprint(cfg.Port)}
+	for key, value := range stagingProperties {
+		values[key] = value
+	}
+	for _, key := range stagingKeptProperties {
+		if value, ok := kept[key]; ok {
+			values[key] = value
+		}
+	}
+	if kept["enable-rcon"] == "true" {
+		values["enable-rcon"] = "true"
+	}
+	if err := setServerProperties(filepath.Join(cfg.Dir, "server.properties"), values); err != nil {
+		restoreStatus()
+		return fmt.Errorf("failed to update server.properties: %w", err)
+	}
+	// A production backend behind Velocity only accepts forwarded players;
+	// staging is joined directly.
+	if err := disablePaperForwarding(cfg.Dir); err != nil {
+		job.log(fmt.Sprintf("Failed to turn off Velocity forwarding: %v", err))
+	}
+
+	m.mu.Lock()
+	if current, ok := m.configs[id]; ok {
+		current.Type = source.Type
+		current.Version = source.Version
+		current.JarFile = source.JarFile
+		current.StartCommand = append([]string(nil), source.StartCommand...)
+		current.Modpack = source.Modpack
+		if err := m.persist(); err != nil {
+			log.Printf("[%s] Failed to save staging refresh: %v", cfg.Name, err)
+		}
+	}
+	m.mu.Unlock()
+	restoreStatus()
+
+	m.broadcastLog(rs, m.appendLog(rs, fmt.Sprintf("[Panel] Refreshed from %s backup %s.", source.Name, backup)))
+	job.log(fmt.Sprintf("Copied %s backup %s; port %d, whitelist on, query off", source.Name, backup, cfg.Port))
+	log.Printf("[%s] Refreshed from %s backup %s", cfg.Name, source.Name, backup)
+
+	if wasRunning {
+		job.progress(95, "Starting the staging server")
+		if err := m.StartServer(id); err != nil {
+			return fmt.Errorf("refreshed, but failed to start the staging server: %w", err)
+		}
+	}
+	return nil
+}
+
+// replaceServerFiles removes everything in dir except the unpacked folder
+// staging, then moves staging's contents up into dir.
+func replaceServerFiles(dir, staging string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read server directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.Name() == refreshingDirName {
+			continue
+		}
+		target := filepath.Join(dir, entry.Name())
+		if err := ensurePathWithinBase(dir, filepath.Clean(target)); err != nil {
+			return fmt.Errorf("failed to clear server directory entry %q: path safety check failed", entry.Name())
+		}
+		if err := os.RemoveAll(target); err != nil {
+			return fmt.Errorf("failed to clear server directory entry %q: %w", entry.Name(), err)
+		}
+	}
+	entries, err = os.ReadDir(staging)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := os.Rename(filepath.Join(staging, entry.Name()), filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to move %s into place: %w", entry.Name(), err)
+		}
+	}
+	return os.Remove(staging)
+}
@@ -0,0 +1,114 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStagingRefreshCopiesLatestBackupAndLocksItDown(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	prod := &ServerConfig{ID: "prod", Name: "Production", Type: "Paper", Version: "1.21.4", Port: 25565, JarFile: "paper-1.21.4.jar", Dir: filepath.Join(mgr.serversRoot, "Production")}
+	staging := &ServerConfig{ID: "stage", Name: "Staging", Type: "Paper", Version: "1.21.1", Port: 25600, JarFile: "paper-1.21.1.jar", Dir: filepath.Join(mgr.serversRoot, "Staging")}
+	mgr.mu.Lock()
+	for _, cfg := range []*ServerConfig{prod, staging} {
+		mgr.configs[cfg.ID] = cfg
+		mgr.running[cfg.ID] = &runningServer{status: "Stopped"}
+	}
+	mgr.mu.Unlock()
+
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+	writeFile(filepath.Join(prod.Dir, "world", "level.dat"), "production world")
+	writeFile(filepath.Join(prod.Dir, "server.properties"), "server-port=25565\nonline-mode=false\nwhite-list=false\nenable-query=true\nmotd=Production\n")
+	writeFile(filepath.Join(prod.Dir, "config", "paper-global.yml"), "proxies:\n  velocity:\n    enabled: true\n    online-mode: true\n    secret: 'abc'\n")
+	writeFile(filepath.Join(staging.Dir, "server.properties"), "server-port=25600\nenable-rcon=true\nrcon.port=25610\nrcon.password=stage\n")
+	writeFile(filepath.Join(staging.Dir, "plugins", "Broken.jar"), "being tested")
+
+	if _, err := mgr.StartStagingRefresh(staging.ID, prod.ID); err == nil || !strings.Contains(err.Error(), "no backups") {
+		t.Fatalf("expected a refresh without backups to be refused, got %v", err)
+	}
+	if _, err := mgr.StartStagingRefresh(staging.ID, staging.ID); err == nil {
+		t.Fatalf("expected refreshing a server from itself to be refused")
+	}
+	if _, err := mgr.CreateBackup(prod.ID); err != nil {
+		t.Fatalf("CreateBackup failed: %v", err)
+	}
+
+	if _, err := mgr.SaveTask(staging.ID, "", ScheduledTask{Schedule: "0 5 * * *", Action: TaskRefreshStaging}); err == nil {
+		t.Fatalf("expected a refresh task without a source to be rejected")
+	}
+	task, err := mgr.SaveTask(staging.ID, "", ScheduledTask{Schedule: "0 5 * * *", Action: TaskRefreshStaging, SourceID: prod.ID, Enabled: true})
+	if err != nil {
+		t.Fatalf("SaveTask failed: %v", err)
+	}
+	if _, err := mgr.RunTask(staging.ID, task.ID); err != nil {
+		t.Fatalf("RunTask failed: %v", err)
+	}
+	var job *Job
+	for _, j := range mgr.ListJobs(staging.ID, "") {
+		if j.Type == JobTypeStagingRefresh {
+			j := j
+			job = &j
+		}
+	}
+	if job == nil {
+		t.Fatalf("expected the task to start a staging refresh job")
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for !job.finished() {
+		if time.Now().After(deadline) {
+			t.Fatalf("staging refresh did not finish: %+v", job)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if job, err = mgr.GetJob(job.ID); err != nil {
+			t.Fatalf("GetJob failed: %v", err)
+		}
+	}
+	if job.State != JobStateSucceeded {
+		t.Fatalf("expected the refresh to succeed, got %+v", job)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(staging.Dir, "world", "level.dat")); err != nil || string(data) != "production world" {
+		t.Fatalf("expected the production world, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(staging.Dir, "plugins", "Broken.jar")); !os.IsNotExist(err) {
+		t.Fatalf("expected staging's own files to be replaced, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(staging.Dir, refreshingDirName)); !os.IsNotExist(err) {
+		t.Fatalf("expected the unpack folder to be removed, got %v", err)
+	}
+	props := parseServerPropertiesFile(filepath.Join(staging.Dir, "server.properties"))
+	want := map[string]string{
+		"server-port": "25600", "white-list": "true", "enforce-whitelist": "true", "enable-query": "false",
+		"online-mode": "true", "enable-rcon": "true", "rcon.port": "25610", "motd": "Production",
+	}
+	for key, value := range want {
+		if props[key] != value {
+			t.Fatalf("expected %s=%s, got %q in %v", key, value, props[key], props)
+		}
+	}
+	if secret := paperForwardingSecret(staging.Dir); secret != "" {
+		t.Fatalf("expected Velocity forwarding to be off, got secret %q", secret)
+	}
+
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	if cfg := mgr.configs[staging.ID]; cfg.Port != 25600 || cfg.Name != "Staging" || cfg.JarFile != "paper-1.21.4.jar" || cfg.Version != "1.21.4" {
+		t.Fatalf("unexpected staging config after refresh: %+v", cfg)
+	}
+}
@@ -0,0 +1,107 @@
+package minecraft
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// motdFormatPattern matches colour and format codes in either case.
+var motdFormatPattern = regexp.MustCompile(`(?i)\x{00a7}[0-9a-fk-or]`)
+
+// StatusPageServer is what the public status page shows of a server that
+// opted in to it.
+type StatusPageServer struct {
+	Name       string   `json:"name"`
+	Motd       string   `json:"motd"`
+	Version    string   `json:"version"`
+	Status     string   `json:"status"`
+	Online     bool     `json:"online"`
+	Players    []string `json:"players"`
+	MaxPlayers int      `json:"maxPlayers"`
+	// UptimeSeconds is how long the server has been up, zero while it
+	// is not running.
+	UptimeSeconds int64 `json:"uptimeSeconds"`
+}
+
+// StatusPageServers returns the servers shown on the status page, in panel
+// order. The page is off while this is empty.
+func (m *Manager) StatusPageServers() []StatusPageServer {
+	type statusEntry struct {
+		cfg ServerConfig
+		rs  *runningServer
+	}
+
+	m.mu.RLock()
+	ids := m.orderedServerIDsLocked()
+	entries := make([]statusEntry, 0, len(ids))
+	for _, id := range ids {
+		if cfg := m.configs[id]; cfg != nil && cfg.ShowOnStatusPage {
+			entries = append(entries, statusEntry{cfg: *cfg, rs: m.running[id]})
+		}
+	}
+	m.mu.RUnlock()
+
+	servers := make([]StatusPageServer, 0, len(entries))
+	for _, entry := range entries {
+		server := StatusPageServer{
+			Name:       entry.cfg.Name,
+			Version:    entry.cfg.Version,
+			Status:     "Stopped",
+			Players:    []string{},
+			MaxPlayers: entry.cfg.MaxPlayers,
+		}
+		if !isProxyType(entry.cfg.Type) {
+			props := parseServerPropertiesFile(filepath.Join(entry.cfg.Dir, "server.properties"))
+			server.Motd = plainMotd(props["motd"])
+		}
+		if rs := entry.rs; rs != nil {
+			rs.mu.RLock()
+			server.Status = rs.status
+			server.Online = rs.status == "Running"
+			if server.Online {
+				for _, player := range rs.players {
+					server.Players = append(server.Players, player.Name)
+				}
+				if !rs.bootStartedAt.IsZero() {
+					server.UptimeSeconds = int64(time.Since(rs.bootStartedAt) / time.Second)
+				}
+			}
+			rs.mu.RUnlock()
+		}
+		sort.Slice(server.Players, func(i, j int) bool {
+			return strings.ToLower(server.Players[i]) < strings.ToLower(server.Players[j])
+		})
+		servers = append(servers, server)
+	}
+	return servers
+}
+
+// SetShowOnStatusPage toggles whether a server is listed on the public
+// status page.
+func (m *Manager) SetShowOnStatusPage(id string, enabled bool) (*ServerInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.ShowOnStatusPage = enabled
+	if err := m.persist(); err != nil {
+		return nil, err
+	}
+
+	return m.serverInfo(id), nil
+}
+
+// plainMotd turns a server.properties motd into plain text: escaped line
+// breaks become spaces and colour and format codes are dropped.
+func plainMotd(motd string) string {
+	motd = strings.NewReplacer(`\u00a7`, "\u00a7", `\u00A7`, "\u00a7", `\n`, " ", `\:`, ":", `\=`, "=").Replace(motd)
+	motd = motdFormatPattern.ReplaceAllString(motd, "")
+	return strings.Join(strings.Fields(motd), " ")
+}
@@ -0,0 +1,57 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStatusPageServers(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	shown := &ServerConfig{ID: "srv1", Name: "Survival", Type: "Paper", Version: "1.21.1", MaxPlayers: 20, Dir: filepath.Join(mgr.serversRoot, "Survival"), ShowOnStatusPage: true}
+	hidden := &ServerConfig{ID: "srv2", Name: "Staging", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Staging")}
+	if err := os.MkdirAll(shown.Dir, 0755); err != nil {
+		t.Fatalf("failed to create server dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(shown.Dir, "server.properties"), []byte(`motd=§aWelcome§r to\nSurvival`+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write server.properties: %v", err)
+	}
+	mgr.mu.Lock()
+	mgr.configs[shown.ID] = shown
+	mgr.configs[hidden.ID] = hidden
+	mgr.running[shown.ID] = &runningServer{
+		status:        "Running",
+		bootStartedAt: time.Now().Add(-90 * time.Minute),
+		players:       map[string]*onlinePlayer{"b": {Name: "steve"}, "a": {Name: "Alex"}},
+	}
+	mgr.running[hidden.ID] = &runningServer{status: "Running", players: map[string]*onlinePlayer{}}
+	mgr.mu.Unlock()
+
+	servers := mgr.StatusPageServers()
+	if len(servers) != 1 {
+		t.Fatalf("expected only the opted-in server, got %+v", servers)
+	}
+	got := servers[0]
+	if got.Name != "Survival" || got.Motd != "Welcome to Survival" || !got.Online || got.Version != "1.21.1" {
+		t.Fatalf("unexpected status page entry %+v", got)
+	}
+	if len(got.Players) != 2 || got.Players[0] != "Alex" || got.Players[1] != "steve" {
+		t.Fatalf("expected sorted player names, got %v", got.Players)
+	}
+	if got.UptimeSeconds < 89*60 || got.UptimeSeconds > 91*60 {
+		t.Fatalf("expected about 90 minutes of uptime, got %ds", got.UptimeSeconds)
+	}
+
+	if _, err := mgr.SetShowOnStatusPage(shown.ID, false); err != nil {
+		t.Fatalf("SetShowOnStatusPage failed: %v", err)
+	}
+	if servers := mgr.StatusPageServers(); len(servers) != 0 {
+		t.Fatalf("expected the status page to be empty, got %+v", servers)
+	}
+}
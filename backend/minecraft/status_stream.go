@@ -0,0 +1,234 @@
+package minecraft
+
+import (
+	"log"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// statusStreamInterval is how often server state is compared for changes
+// while anyone follows the status stream.
+const statusStreamInterval = time.Second
+
+// statusSubscriberBuffer is how many updates a subscriber may have queued.
+// Updates are deltas, so one that falls further behind is disconnected and
+// starts over from a fresh snapshot.
+const statusSubscriberBuffer = 32
+
+// ServerStatus is a ServerInfo with the online player count.
+type ServerStatus struct {
+	ServerInfo
+	Players int `json:"players"`
+}
+
+// StatusTransition records a server moving from one status to another.
+type StatusTransition struct {
+	ServerID string `json:"serverId"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	At       string `json:"at"`
+}
+
+// StatusUpdate is one push to a status subscriber. Servers holds only the
+// servers that changed since the previous push. Order is set when servers
+// were added, removed or reordered.
+type StatusUpdate struct {
+	Servers     []ServerStatus     `json:"servers,omitempty"`
+	Removed     []string           `json:"removed,omitempty"`
+	Order       []string           `json:"order,omitempty"`
+	Transitions []StatusTransition `json:"transitions,omitempty"`
+}
+
+// statusSubscriber follows the status stream. A nil filter follows every
+// server.
+type statusSubscriber struct {
+	ch     chan StatusUpdate
+	filter map[string]bool
+}
+
+func (s *statusSubscriber) follows(id string) bool {
+	return s.filter == nil || s.filter[id]
+}
+
+// serverStatuses returns the status of every server in panel order.
+func (m *Manager) serverStatuses() []ServerStatus {
+	servers := m.ListServers()
+	m.mu.RLock()
+	running := make(map[string]*runningServer, len(servers))
+	for _, info := range servers {
+		running[info.ID] = m.running[info.ID]
+	}
+	m.mu.RUnlock()
+
+	statuses := make([]ServerStatus, 0, len(servers))
+	for _, info := range servers {
+		status := ServerStatus{ServerInfo: info}
+		if rs := running[info.ID]; rs != nil {
+			rs.mu.RLock()
+			status.Players = len(rs.players)
+			rs.mu.RUnlock()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// SubscribeStatus follows server status changes. ids limits the stream to
+// those servers; empty follows them all. It returns the current status of
+// the followed servers and a channel of later changes, which is closed if
+// the subscriber falls too far behind.
+func (m *Manager) SubscribeStatus(ids []string) ([]ServerStatus, <-chan StatusUpdate, func()) {
+	sub := &statusSubscriber{ch: make(chan StatusUpdate, statusSubscriberBuffer)}
+	if len(ids) > 0 {
+		sub.filter = make(map[string]bool, len(ids))
+		for _, id := range ids {
+			sub.filter[id] = true
+		}
+	}
+
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+	if m.statusLast == nil {
+		m.statusLast = m.serverStatuses()
+	}
+	snapshot := make([]ServerStatus, 0, len(m.statusLast))
+	for _, status := range m.statusLast {
+		if sub.follows(status.ID) {
+			snapshot = append(snapshot, status)
+		}
+	}
+	m.statusSubs[sub] = struct{}{}
+
+	cancel := func() {
+		m.statusMu.Lock()
+		defer m.statusMu.Unlock()
+		if _, ok := m.statusSubs[sub]; ok {
+			delete(m.statusSubs, sub)
+			close(sub.ch)
+		}
+	}
+	return snapshot, sub.ch, cancel
+}
+
+// notifyStatusChange asks the status broadcaster to compare now instead of
+// at its next tick, so state transitions reach subscribers right away.
+func (m *Manager) notifyStatusChange() {
+	select {
+	case m.statusKick <- struct{}{}:
+	default:
+	}
+}
+
+// runStatusBroadcaster pushes status changes to subscribers until StopAll.
+func (m *Manager) runStatusBroadcaster() {
+	ticker := time.NewTicker(statusStreamInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopStatusStream:
+			return
+		case <-ticker.C:
+		case <-m.statusKick:
+		}
+		m.publishStatusChanges()
+	}
+}
+
+// publishStatusChanges compares server state with the last push and sends
+// each subscriber the changes to the servers it follows.
+func (m *Manager) publishStatusChanges() {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+	if len(m.statusSubs) == 0 {
+		// Nobody is listening; the next subscriber starts from a fresh
+		// snapshot.
+		m.statusLast = nil
+		return
+	}
+
+	current := m.serverStatuses()
+	update := diffServerStatuses(m.statusLast, current, time.Now())
+	m.statusLast = current
+
+	for sub := range m.statusSubs {
+		filtered := update.filter(sub)
+		if filtered == nil {
+			continue
+		}
+		select {
+		case sub.ch <- *filtered:
+		default:
+			log.Printf("Disconnecting status stream subscriber that fell behind")
+			delete(m.statusSubs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// diffServerStatuses returns what changed between two status lists.
+func diffServerStatuses(prev, current []ServerStatus, now time.Time) StatusUpdate {
+	var update StatusUpdate
+	before := make(map[string]ServerStatus, len(prev))
+	prevOrder := make([]string, 0, len(prev))
+	for _, status := range prev {
+		before[status.ID] = status
+		prevOrder = append(prevOrder, status.ID)
+	}
+	order := make([]string, 0, len(current))
+	at := now.UTC().Format(time.RFC3339)
+	for _, status := range current {
+		order = append(order, status.ID)
+		old, existed := before[status.ID]
+		delete(before, status.ID)
+		if existed && reflect.DeepEqual(old, status) {
+			continue
+		}
+		update.Servers = append(update.Servers, status)
+		if existed && old.Status != status.Status {
+			update.Transitions = append(update.Transitions, StatusTransition{
+				ServerID: status.ID,
+				From:     old.Status,
+				To:       status.Status,
+				At:       at,
+			})
+		}
+	}
+	for _, id := range prevOrder {
+		if _, removed := before[id]; removed {
+			update.Removed = append(update.Removed, id)
+		}
+	}
+	if !slices.Equal(prevOrder, order) {
+		update.Order = order
+	}
+	return update
+}
+
+// filter returns the part of the update a subscriber follows, or nil if
+// none of it concerns the subscriber.
+func (u StatusUpdate) filter(sub *statusSubscriber) *StatusUpdate {
+	var out StatusUpdate
+	for _, status := range u.Servers {
+		if sub.follows(status.ID) {
+			out.Servers = append(out.Servers, status)
+		}
+	}
+	for _, id := range u.Removed {
+		if sub.follows(id) {
+			out.Removed = append(out.Removed, id)
+		}
+	}
+	for _, transition := range u.Transitions {
+		if sub.follows(transition.ServerID) {
+			out.Transitions = append(out.Transitions, transition)
+		}
+	}
+	if sub.filter == nil && u.Order != nil {
+		out.Order = u.Order
+	}
+	if len(out.Servers) == 0 && len(out.Removed) == 0 && len(out.Order) == 0 {
+		return nil
+	}
+	return &out
+}
@@ -0,0 +1,68 @@
+package minecraft
+
+import "testing"
+
+func TestStatusStreamPushesFilteredChanges(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	alpha := &runningServer{status: "Stopped", players: make(map[string]*onlinePlayer)}
+	beta := &runningServer{status: "Stopped", players: make(map[string]*onlinePlayer)}
+	mgr.mu.Lock()
+	mgr.configs["alpha"] = &ServerConfig{ID: "alpha", Name: "Alpha", Type: "Paper", Order: 1}
+	mgr.configs["beta"] = &ServerConfig{ID: "beta", Name: "Beta", Type: "Paper", Order: 2}
+	mgr.running["alpha"] = alpha
+	mgr.running["beta"] = beta
+	mgr.mu.Unlock()
+
+	snapshot, updates, cancel := mgr.SubscribeStatus([]string{"alpha"})
+	defer cancel()
+	if len(snapshot) != 1 || snapshot[0].ID != "alpha" || snapshot[0].Status != "Stopped" {
+		t.Fatalf("expected a snapshot of alpha only, got %+v", snapshot)
+	}
+
+	// Unchanged state pushes nothing.
+	mgr.publishStatusChanges()
+	select {
+	case update := <-updates:
+		t.Fatalf("expected no update without changes, got %+v", update)
+	default:
+	}
+
+	// A change to an unfollowed server is filtered out.
+	beta.mu.Lock()
+	beta.status = "Running"
+	beta.mu.Unlock()
+	mgr.publishStatusChanges()
+	select {
+	case update := <-updates:
+		t.Fatalf("expected beta's change to be filtered, got %+v", update)
+	default:
+	}
+
+	alpha.mu.Lock()
+	alpha.status = "Booting"
+	alpha.players["steve"] = &onlinePlayer{Name: "Steve"}
+	alpha.mu.Unlock()
+	mgr.publishStatusChanges()
+	update := <-updates
+	if len(update.Servers) != 1 || update.Servers[0].ID != "alpha" || update.Servers[0].Players != 1 {
+		t.Fatalf("expected alpha with one player, got %+v", update.Servers)
+	}
+	if len(update.Transitions) != 1 || update.Transitions[0].From != "Stopped" || update.Transitions[0].To != "Booting" {
+		t.Fatalf("expected a Stopped to Booting transition, got %+v", update.Transitions)
+	}
+
+	mgr.mu.Lock()
+	delete(mgr.configs, "alpha")
+	delete(mgr.running, "alpha")
+	mgr.mu.Unlock()
+	mgr.publishStatusChanges()
+	update = <-updates
+	if len(update.Removed) != 1 || update.Removed[0] != "alpha" || update.Order != nil {
+		t.Fatalf("expected alpha removed without the unfiltered order, got %+v", update)
+	}
+}
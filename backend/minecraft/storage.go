@@ -0,0 +1,161 @@
+package minecraft
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Panel metadata documents. Each one is the JSON payload that historically
+// lived in data/<name>, whichever backend now stores it.
+const (
+	storeDocServers        = "servers.json"
+	storeDocSettings       = "settings.json"
+	storeDocCorruptServers = "servers.corrupt.json"
+	storeDocPreferences    = "preferences.json"
+	storeDocLoginAttempts  = "login_attempts.json"
+	storeDocUsers          = "users.json"
+	storeDocModeration     = "moderation.json"
+	storeDocBackupTargets  = "backup_targets.json"
+	storeDocPlayerHistory  = "player_history.json"
+)
+
+// storeDocuments lists every document a backend may hold, in migration order.
+var storeDocuments = []string{storeDocServers, storeDocSettings, storeDocCorruptServers, storeDocPreferences, storeDocLoginAttempts, storeDocUsers, storeDocModeration, storeDocBackupTargets, storeDocPlayerHistory}
+
+const (
+	storageBackendJSON   = "json"
+	storageBackendSQLite = "sqlite"
+)
+
+// panelStore persists the panel's metadata documents. Load reports a missing
+// document with an error matching os.ErrNotExist.
+type panelStore interface {
+	Load(name string) ([]byte, error)
+	Save(name string, data []byte) error
+	Delete(name string) error
+	Close() error
+}
+
+func storageBackendFromEnv() string {
+	raw := strings.ToLower(strings.TrimSpace(os.Getenv("ADPANEL_STORAGE")))
+	switch raw {
+	case "":
+		return storageBackendJSON
+	case storageBackendJSON, storageBackendSQLite:
+		return raw
+	default:
+		log.Printf("Invalid ADPANEL_STORAGE value %q, using default %s", raw, storageBackendJSON)
+		return storageBackendJSON
+	}
+}
+
+// openPanelStore opens the named storage backend.
+func openPanelStore(dataDir, backend string) (panelStore, error) {
+	switch backend {
+	case storageBackendSQLite:
+		return openSQLiteStore(dataDir)
+	default:
+		return jsonFileStore{dir: dataDir}, nil
+	}
+}
+
+// storage returns the manager's store. Managers assembled without NewManager
+// fall back to JSON files next to dataFile.
+func (m *Manager) storage() panelStore {
+	if m.store != nil {
+		return m.store
+	}
+	if m.dataFile == "" {
+		return jsonFileStore{}
+	}
+	return jsonFileStore{dir: filepath.Dir(m.dataFile)}
+}
+
+// jsonFileStore keeps each document as a file in the data directory,
+// replaced atomically on every save.
+type jsonFileStore struct {
+	dir string
+}
+
+func (s jsonFileStore) path(name string) (string, error) {
+	if s.dir == "" {
+		return "", fmt.Errorf("data directory is not configured")
+	}
+	if name != filepath.Base(name) {
+		return "", fmt.Errorf("invalid document name %q", name)
+	}
+	return filepath.Join(s.dir, name), nil
+}
+
+func (s jsonFileStore) Load(name string) ([]byte, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+func (s jsonFileStore) Save(name string, data []byte) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	// Settings and users carry password hashes and backup targets carry
+	// credentials, so keep them owner-only.
+	perm := os.FileMode(0644)
+	if name == storeDocSettings || name == storeDocUsers || name == storeDocBackupTargets {
+		perm = 0600
+	}
+	tmpFile := path + ".tmp"
+	if err := writeFileSync(tmpFile, data, perm); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		return fmt.Errorf("failed to set permissions on %s: %w", name, err)
+	}
+	// Sync the directory so the rename itself survives a power loss.
+	if dir, err := os.Open(s.dir); err == nil {
+		_ = dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// writeFileSync is os.WriteFile followed by an fsync of the file contents.
+func writeFileSync(path string, data []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s jsonFileStore) Delete(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s jsonFileStore) Close() error {
+	return nil
+}
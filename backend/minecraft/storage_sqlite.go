@@ -0,0 +1,147 @@
+package minecraft
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteStoreFile = "panel.db"
+const sqliteSchemaVersion = 1
+
+// sqliteStore keeps panel documents in data/panel.db. Writes are
+// transactional, so concurrent saves never leave a half-written document.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+func openSQLiteStore(dataDir string) (*sqliteStore, error) {
+	dbPath := filepath.Join(dataDir, sqliteStoreFile)
+	dsn := (&url.URL{
+		Scheme: "file",
+		Path:   dbPath,
+		RawQuery: url.Values{"_pragma": {
+			"busy_timeout(5000)",
+			"journal_mode(WAL)",
+			"synchronous(FULL)",
+		}}.Encode(),
+	}).String()
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", sqliteStoreFile, err)
+	}
+	// A single connection serializes writers inside the process and avoids
+	// SQLITE_BUSY between our own goroutines.
+	db.SetMaxOpenConns(1)
+
+	store := &sqliteStore{db: db}
+	if err := store.migrateSchema(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := os.Chmod(dbPath, 0600); err != nil {
+		log.Printf("Failed to secure %s permissions: %v", sqliteStoreFile, err)
+	}
+	if err := store.importJSONDocuments(dataDir); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *sqliteStore) migrateSchema() error {
+	var version int
+	if err := s.db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return fmt.Errorf("failed to read %s schema version: %w", sqliteStoreFile, err)
+	}
+	if version > sqliteSchemaVersion {
+		return fmt.Errorf("%s schema version %d is newer than this panel supports (%d)", sqliteStoreFile, version, sqliteSchemaVersion)
+	}
+	if version == sqliteSchemaVersion {
+		return nil
+	}
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS documents (
+			name       TEXT PRIMARY KEY,
+			data       BLOB NOT NULL,
+			updated_at TEXT NOT NULL
+		);
+		PRAGMA user_version = 1;`)
+	if err != nil {
+		return fmt.Errorf("failed to initialize %s: %w", sqliteStoreFile, err)
+	}
+	return nil
+}
+
+// importJSONDocuments moves existing JSON data files into the database the
+// first time the SQLite backend is used. Imported files are renamed to
+// <name>.migrated so they are not picked up again.
+func (s *sqliteStore) importJSONDocuments(dataDir string) error {
+	for _, name := range storeDocuments {
+		if _, err := s.Load(name); err == nil {
+			continue
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		src := filepath.Join(dataDir, name)
+		data, err := os.ReadFile(src)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("failed to read %s for migration: %w", name, err)
+		}
+		if err := s.Save(name, data); err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", name, err)
+		}
+		if err := os.Rename(src, src+".migrated"); err != nil {
+			log.Printf("Migrated %s into %s but could not rename the original: %v", name, sqliteStoreFile, err)
+			continue
+		}
+		log.Printf("Migrated %s into %s", name, sqliteStoreFile)
+	}
+	return nil
+}
+
+func (s *sqliteStore) Load(name string) ([]byte, error) {
+	var data []byte
+	err := s.db.QueryRow(`SELECT data FROM documents WHERE name = ?`, name).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("document %s: %w", name, os.ErrNotExist)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", name, err)
+	}
+	return data, nil
+}
+
+func (s *sqliteStore) Save(name string, data []byte) error {
+	_, err := s.db.Exec(`
+		INSERT INTO documents (name, data, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET data = excluded.data, updated_at = excluded.updated_at`,
+		name, data, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to save %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) Delete(name string) error {
+	if _, err := s.db.Exec(`DELETE FROM documents WHERE name = ?`, name); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) Close() error {
+	return s.db.Close()
+}
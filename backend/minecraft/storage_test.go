@@ -0,0 +1,94 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteStoreMigratesJSONFiles(t *testing.T) {
+	dataDir := t.TempDir()
+	servers := []byte(`[{"id":"srv1","name":"Lobby","dir":"/srv/lobby"}]`)
+	if err := os.WriteFile(filepath.Join(dataDir, storeDocServers), servers, 0644); err != nil {
+		t.Fatalf("failed to write servers.json: %v", err)
+	}
+
+	store, err := openSQLiteStore(dataDir)
+	if err != nil {
+		t.Fatalf("openSQLiteStore failed: %v", err)
+	}
+	got, err := store.Load(storeDocServers)
+	if err != nil || string(got) != string(servers) {
+		t.Fatalf("expected migrated servers document, got %q (%v)", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, storeDocServers+".migrated")); err != nil {
+		t.Fatalf("expected original file to be renamed: %v", err)
+	}
+	if _, err := store.Load(storeDocSettings); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected missing settings to report ErrNotExist, got %v", err)
+	}
+	if err := store.Save(storeDocSettings, []byte(`{"loginUser":"admin"}`)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	reopened, err := openSQLiteStore(dataDir)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	defer reopened.Close()
+	if got, err := reopened.Load(storeDocSettings); err != nil || string(got) != `{"loginUser":"admin"}` {
+		t.Fatalf("expected settings to survive reopen, got %q (%v)", got, err)
+	}
+	if err := reopened.Delete(storeDocSettings); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := reopened.Load(storeDocSettings); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected deleted document to be missing, got %v", err)
+	}
+}
+
+func TestNewManagerUsesSQLiteStorage(t *testing.T) {
+	t.Setenv("ADPANEL_STORAGE", "sqlite")
+	base := t.TempDir()
+	dataDir := filepath.Join(base, "data")
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		t.Fatalf("failed to create data dir: %v", err)
+	}
+	raw, err := json.Marshal([]*ServerConfig{{
+		ID:   "srv1",
+		Name: "Lobby",
+		Type: "Paper",
+		Port: 25565,
+		Dir:  filepath.Join(base, "Servers", "Lobby"),
+	}})
+	if err != nil {
+		t.Fatalf("failed to marshal config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, storeDocServers), raw, 0644); err != nil {
+		t.Fatalf("failed to write servers.json: %v", err)
+	}
+
+	mgr, err := NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	if _, ok := mgr.store.(*sqliteStore); !ok {
+		t.Fatalf("expected sqlite store, got %T", mgr.store)
+	}
+	if cfg := mgr.configs["srv1"]; cfg == nil || cfg.Name != "Lobby" {
+		t.Fatalf("expected server to load from migrated data, got %+v", cfg)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, storeDocSettings)); !os.IsNotExist(err) {
+		t.Fatalf("expected settings to be stored in the database, stat err=%v", err)
+	}
+	if _, err := mgr.store.Load(storeDocSettings); err != nil {
+		t.Fatalf("expected default settings in the database: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package minecraft
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// serverProcessPIDLocked returns the PID of the process group a server
+// runs in, or 0 when there is none. Callers hold rs.mu.
+func serverProcessPIDLocked(rs *runningServer) int {
+	if rs.reattached {
+		return rs.pid
+	}
+	if rs.cmd != nil && rs.cmd.Process != nil {
+		return rs.cmd.Process.Pid
+	}
+	return 0
+}
+
+// SuspendServer freezes a running server's process with SIGSTOP. It keeps
+// its memory and players' connections time out, but it uses no CPU until
+// ResumeServer, which is much quicker than a stop and a new boot.
+func (m *Manager) SuspendServer(id string) error {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	rs, ok := m.running[id]
+	m.mu.RUnlock()
+
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errServerNotFound(id)
+	}
+
+	rs.mu.Lock()
+	if rs.status != "Running" {
+		rs.mu.Unlock()
+		return errServerNotRunningStatus(id, rs.status)
+	}
+	pid := serverProcessPIDLocked(rs)
+	if pid == 0 {
+		rs.mu.Unlock()
+		return fmt.Errorf("server %s has no process to suspend", id)
+	}
+	if err := suspendServerProcess(pid); err != nil {
+		rs.mu.Unlock()
+		log.Printf("[%s] Failed to suspend server process (pid=%d): %v", cfg.Name, pid, err)
+		return fmt.Errorf("failed to suspend server process")
+	}
+	rs.status = "Suspended"
+	rs.suspendedAt = time.Now()
+	rs.cpu = 0
+	rs.tps = 0
+	m.notifyStatusChange()
+	rs.mu.Unlock()
+
+	log.Printf("[%s] Server suspended (PID: %d)", cfg.Name, pid)
+	m.broadcastLog(rs, m.appendLog(rs, "[Panel] Server suspended. It keeps its memory but gets no CPU until it is resumed."))
+	return nil
+}
+
+// ResumeServer continues a suspended server with SIGCONT.
+func (m *Manager) ResumeServer(id string) error {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	rs, ok := m.running[id]
+	m.mu.RUnlock()
+
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errServerNotFound(id)
+	}
+
+	rs.mu.Lock()
+	if rs.status != "Suspended" {
+		rs.mu.Unlock()
+		return fmt.Errorf("server %s is not suspended (status: %s)", id, rs.status)
+	}
+	suspendedFor := time.Since(rs.suspendedAt).Round(time.Second)
+	if err := resumeSuspendedLocked(rs); err != nil {
+		rs.mu.Unlock()
+		log.Printf("[%s] Failed to resume server process: %v", cfg.Name, err)
+		return fmt.Errorf("failed to resume server process")
+	}
+	m.notifyStatusChange()
+	rs.mu.Unlock()
+
+	log.Printf("[%s] Server resumed after %s", cfg.Name, suspendedFor)
+	m.broadcastLog(rs, m.appendLog(rs, fmt.Sprintf("[Panel] Server resumed after %s suspended.", suspendedFor)))
+	return nil
+}
+
+// resumeSuspendedLocked sends SIGCONT to a suspended server and marks it
+// running again. Callers hold rs.mu.
+func resumeSuspendedLocked(rs *runningServer) error {
+	if pid := serverProcessPIDLocked(rs); pid != 0 {
+		if err := resumeServerProcess(pid); err != nil {
+			return err
+		}
+	}
+	rs.status = "Running"
+	rs.suspendedAt = time.Time{}
+	return nil
+}
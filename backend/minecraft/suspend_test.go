@@ -0,0 +1,74 @@
+//go:build linux
+
+package minecraft
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestSuspendAndResumeServer(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "sleep 120")
+	prepareServerProcessCommand(cmd)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start test process: %v", err)
+	}
+	go func() { _ = cmd.Wait() }()
+
+	const id = "srv1"
+	rs := &runningServer{status: "Running", cmd: cmd, pid: cmd.Process.Pid, stopMetrics: make(chan struct{})}
+	mgr := buildTestManagerForKill(t, id, rs)
+	defer mgr.KillServer(id)
+
+	if err := mgr.ResumeServer(id); err == nil {
+		t.Fatal("expected resuming a server that is not suspended to fail")
+	}
+	if err := mgr.SuspendServer(id); err != nil {
+		t.Fatalf("SuspendServer failed: %v", err)
+	}
+	if !waitForProcessSuspended(cmd.Process.Pid, true) {
+		t.Fatal("expected the process to be stopped")
+	}
+	if info := mgr.serverInfo(id); info.Status != "Suspended" || info.SuspendedAt == "" {
+		t.Fatalf("expected a suspended server, got %+v", info)
+	}
+	if err := mgr.SendCommand(id, "list"); err == nil {
+		t.Fatal("expected commands to a suspended server to fail")
+	}
+	if err := mgr.StartServer(id); err == nil {
+		t.Fatal("expected starting a suspended server to fail")
+	}
+
+	if err := mgr.ResumeServer(id); err != nil {
+		t.Fatalf("ResumeServer failed: %v", err)
+	}
+	if !waitForProcessSuspended(cmd.Process.Pid, false) {
+		t.Fatal("expected the process to run again")
+	}
+	if info := mgr.serverInfo(id); info.Status != "Running" || info.SuspendedAt != "" {
+		t.Fatalf("expected a running server, got %+v", info)
+	}
+
+	// Killing works on a suspended server too.
+	if err := mgr.SuspendServer(id); err != nil {
+		t.Fatalf("SuspendServer failed: %v", err)
+	}
+	if err := mgr.KillServer(id); err != nil {
+		t.Fatalf("KillServer failed on a suspended server: %v", err)
+	}
+	if status := rs.currentRuntime().status; status != "Stopped" {
+		t.Fatalf("expected Stopped after kill, got %s", status)
+	}
+}
+
+func waitForProcessSuspended(pid int, want bool) bool {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if processSuspended(pid) == want {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return false
+}
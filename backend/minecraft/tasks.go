@@ -0,0 +1,483 @@
+package minecraft
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions a scheduled task can take.
+const (
+	TaskCommand   = "command"
+	TaskBroadcast = "broadcast"
+	TaskRestart   = "restart"
+	TaskBackup    = "backup"
+	// TaskMaintenance runs the server's maintenance routine.
+	TaskMaintenance = "maintenance"
+	// TaskRefreshStaging overwrites the server with the latest backup of
+	// the server named by SourceID.
+	TaskRefreshStaging = "refresh-staging"
+)
+
+const (
+	maxTasksPerServer   = 50
+	maxTaskText         = 256
+	maxTaskRestartDelay = 3600
+	// taskMissedGrace is how late a task may still run, for example after
+	// the panel was briefly down. Older runs are skipped.
+	taskMissedGrace = 5 * time.Minute
+)
+
+// ScheduledTask runs an action on a server on a cron schedule in the
+// panel's local time.
+type ScheduledTask struct {
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Schedule string `json:"schedule"`
+	Action   string `json:"action"`
+	Enabled  bool   `json:"enabled"`
+	// Command is the console command of a command task and Message the
+	// text of a broadcast.
+	Command string `json:"command,omitempty"`
+	Message string `json:"message,omitempty"`
+	// DelaySeconds makes a restart task count down with the server's
+	// restart warnings first.
+	DelaySeconds int `json:"delaySeconds,omitempty"`
+	// SourceID is the production server a refresh-staging task copies.
+	SourceID   string `json:"sourceId,omitempty"`
+	NextRun    string `json:"nextRun,omitempty"`
+	LastRun    string `json:"lastRun,omitempty"`
+	LastResult string `json:"lastResult,omitempty"`
+}
+
+// cronSchedule is a parsed five-field cron expression.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny record a "*" day field: when both day fields are
+	// restricted, a day matching either one runs, as in cron.
+	domAny, dowAny bool
+}
+
+var cronAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// parseCron reads "minute hour day-of-month month day-of-week" with *,
+// lists, ranges, steps and month or weekday names, or an alias such as
+// @daily.
+func parseCron(expr string) (*cronSchedule, error) {
+	expr = strings.ToLower(strings.TrimSpace(expr))
+	if alias, ok := cronAliases[expr]; ok {
+		expr = alias
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule %q must have five fields: minute hour day-of-month month day-of-week", expr)
+	}
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	names := [5]string{"minute", "hour", "day-of-month", "month", "day-of-week"}
+	var sets [5]uint64
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s field %q: %w", names[i], field, err)
+		}
+		sets[i] = set
+	}
+	// 7 is another name for Sunday.
+	if sets[4]&(1<<7) != 0 {
+		sets[4] = sets[4]&^(1<<7) | 1
+	}
+	return &cronSchedule{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		domAny: fields[2] == "*", dowAny: fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, lo, hi int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if base, s, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(s)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("bad step %q", s)
+			}
+			step = n
+			part = base
+		}
+		start, end := lo, hi
+		if part != "*" {
+			from, to, isRange := strings.Cut(part, "-")
+			var err error
+			if start, err = cronValue(from, lo, hi); err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				if end, err = cronValue(to, lo, hi); err != nil {
+					return 0, err
+				}
+				if end < start {
+					return 0, fmt.Errorf("range %q runs backwards", part)
+				}
+			} else if step > 1 {
+				end = hi
+			}
+		}
+		for v := start; v <= end; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+func cronValue(s string, lo, hi int) (int, error) {
+	v, ok := cronNames[s]
+	if !ok {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("bad value %q", s)
+		}
+		v = n
+	}
+	if v < lo || v > hi {
+		return 0, fmt.Errorf("%d is outside %d-%d", v, lo, hi)
+	}
+	return v, nil
+}
+
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<int(t.Weekday())) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// next returns the first minute after t the schedule matches, or the zero
+// time when none falls within five years (such as 30 February).
+func (c *cronSchedule) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case c.hour&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case c.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// validateTask checks a task and fills in its derived fields.
+func validateTask(task *ScheduledTask) error {
+	task.Name = strings.TrimSpace(task.Name)
+	task.Schedule = strings.TrimSpace(task.Schedule)
+	task.Command = strings.TrimPrefix(strings.TrimSpace(task.Command), "/")
+	task.Message = strings.TrimSpace(task.Message)
+	task.SourceID = strings.TrimSpace(task.SourceID)
+	if len(task.Name) > 64 || strings.ContainsAny(task.Name, "\r\n") {
+		return fmt.Errorf("task name must be a single line of at most 64 characters")
+	}
+	schedule, err := parseCron(task.Schedule)
+	if err != nil {
+		return err
+	}
+	if schedule.next(time.Now()).IsZero() {
+		return fmt.Errorf("schedule %q never runs", task.Schedule)
+	}
+	text := ""
+	switch task.Action {
+	case TaskCommand:
+		if task.Command == "" {
+			return fmt.Errorf("command tasks need a command")
+		}
+		text = task.Command
+		task.Message, task.DelaySeconds = "", 0
+	case TaskBroadcast:
+		if task.Message == "" {
+			return fmt.Errorf("broadcast tasks need a message")
+		}
+		text = task.Message
+		task.Command, task.DelaySeconds = "", 0
+	case TaskRestart:
+		if task.DelaySeconds < 0 || task.DelaySeconds > maxTaskRestartDelay {
+			return fmt.Errorf("delaySeconds must be between 0 and %d", maxTaskRestartDelay)
+		}
+		text = task.Message
+		task.Command = ""
+	case TaskBackup, TaskMaintenance:
+		task.Command, task.Message, task.DelaySeconds = "", "", 0
+	case TaskRefreshStaging:
+		if task.SourceID == "" {
+			return fmt.Errorf("refresh-staging tasks need a sourceId")
+		}
+		task.Command, task.Message, task.DelaySeconds = "", "", 0
+	default:
+		return fmt.Errorf("unknown action %q, use command, broadcast, restart, backup, maintenance or refresh-staging", task.Action)
+	}
+	if task.Action != TaskRefreshStaging {
+		task.SourceID = ""
+	}
+	if len(text) > maxTaskText || strings.ContainsAny(text, "\r\n") {
+		return fmt.Errorf("task text must be a single line of at most %d characters", maxTaskText)
+	}
+	return nil
+}
+
+// scheduleNextRun sets when an enabled task runs next after t.
+func (task *ScheduledTask) scheduleNextRun(t time.Time) {
+	task.NextRun = ""
+	if !task.Enabled {
+		return
+	}
+	if schedule, err := parseCron(task.Schedule); err == nil {
+		if next := schedule.next(t); !next.IsZero() {
+			task.NextRun = next.UTC().Format(time.RFC3339)
+		}
+	}
+}
+
+// ListTasks returns a server's scheduled tasks.
+func (m *Manager) ListTasks(id string) ([]ScheduledTask, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	return append([]ScheduledTask{}, cfg.Tasks...), nil
+}
+
+// SaveTask creates a task, or replaces the one with taskID.
+func (m *Manager) SaveTask(id, taskID string, task ScheduledTask) (*ScheduledTask, error) {
+	if err := validateTask(&task); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	if isProxyType(cfg.Type) && (task.Action == TaskBroadcast || task.Action == TaskBackup) {
+		return nil, fmt.Errorf("%s tasks are not available for proxies", task.Action)
+	}
+	if task.Action == TaskRefreshStaging {
+		if _, err := m.validateStagingRefreshLocked(cfg, task.SourceID); err != nil {
+			return nil, err
+		}
+	}
+	index := -1
+	if taskID == "" {
+		if len(cfg.Tasks) >= maxTasksPerServer {
+			return nil, fmt.Errorf("at most %d tasks are allowed per server", maxTasksPerServer)
+		}
+		task.ID = uuid.New().String()[:8]
+		task.LastRun, task.LastResult = "", ""
+	} else {
+		for i := range cfg.Tasks {
+			if cfg.Tasks[i].ID == taskID {
+				index = i
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("task %q not found", taskID)
+		}
+		task.ID = taskID
+		task.LastRun, task.LastResult = cfg.Tasks[index].LastRun, cfg.Tasks[index].LastResult
+	}
+	task.scheduleNextRun(time.Now())
+	if index < 0 {
+		cfg.Tasks = append(cfg.Tasks, task)
+	} else {
+		cfg.Tasks[index] = task
+	}
+	if err := m.persist(); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// DeleteTask removes a task.
+func (m *Manager) DeleteTask(id, taskID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return err
+	}
+	for i := range cfg.Tasks {
+		if cfg.Tasks[i].ID == taskID {
+			cfg.Tasks = append(cfg.Tasks[:i], cfg.Tasks[i+1:]...)
+			return m.persist()
+		}
+	}
+	return fmt.Errorf("task %q not found", taskID)
+}
+
+// RunTask runs a task now, outside its schedule.
+func (m *Manager) RunTask(id, taskID string) (*ScheduledTask, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		m.mu.RUnlock()
+		return nil, err
+	}
+	var task *ScheduledTask
+	for i := range cfg.Tasks {
+		if cfg.Tasks[i].ID == taskID {
+			t := cfg.Tasks[i]
+			task = &t
+		}
+	}
+	m.mu.RUnlock()
+	if task == nil {
+		return nil, fmt.Errorf("task %q not found", taskID)
+	}
+	err = m.runTask(id, *task)
+	return m.recordTaskRun(id, taskID, time.Now(), err, false), err
+}
+
+// runTask performs a task's action. Backups, maintenance and staging
+// refreshes run in the background.
+func (m *Manager) runTask(id string, task ScheduledTask) error {
+	switch task.Action {
+	case TaskCommand:
+		return m.SendCommand(id, task.Command)
+	case TaskBroadcast:
+		return m.SendCommand(id, "say "+task.Message)
+	case TaskRestart:
+		return m.ScheduleRestart(id, task.DelaySeconds, task.Message)
+	case TaskBackup:
+		m.mu.RLock()
+		cfg, err := m.serverConfigForOperationLocked(id)
+		mode, name := "", ""
+		if err == nil {
+			mode, name = cfg.BackupMode, cfg.Name
+		}
+		m.mu.RUnlock()
+		if err != nil {
+			return err
+		}
+		go func() {
+			if _, err := m.createBackup(id, mode); err != nil {
+				log.Printf("[%s] Scheduled task backup failed: %v", name, err)
+			}
+		}()
+		return nil
+	case TaskMaintenance:
+		_, err := m.StartMaintenance(id)
+		return err
+	case TaskRefreshStaging:
+		_, err := m.StartStagingRefresh(id, task.SourceID)
+		return err
+	}
+	return fmt.Errorf("unknown action %q", task.Action)
+}
+
+// recordTaskRun stores a run's outcome and, when advance is set, when the
+// task runs next.
+func (m *Manager) recordTaskRun(id, taskID string, at time.Time, runErr error, advance bool) *ScheduledTask {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg, ok := m.configs[id]
+	if !ok {
+		return nil
+	}
+	for i := range cfg.Tasks {
+		task := &cfg.Tasks[i]
+		if task.ID != taskID {
+			continue
+		}
+		task.LastRun = at.UTC().Format(time.RFC3339)
+		task.LastResult = "ok"
+		if runErr != nil {
+			task.LastResult = runErr.Error()
+		}
+		if advance {
+			task.scheduleNextRun(at)
+		}
+		m.persist()
+		t := *task
+		return &t
+	}
+	return nil
+}
+
+// checkScheduledTasks runs the tasks that are due. A task whose run was
+// missed by more than taskMissedGrace is moved on to its next run.
+func (m *Manager) checkScheduledTasks() {
+	type pending struct {
+		serverID, name string
+		task           ScheduledTask
+	}
+	now := time.Now()
+	var due []pending
+	m.mu.Lock()
+	for id, cfg := range m.configs {
+		for i := range cfg.Tasks {
+			task := &cfg.Tasks[i]
+			if !task.Enabled {
+				continue
+			}
+			next, err := time.Parse(time.RFC3339, task.NextRun)
+			if err != nil {
+				if task.scheduleNextRun(now); task.NextRun != "" {
+					m.persist()
+				}
+				continue
+			}
+			if now.Before(next) {
+				continue
+			}
+			if now.Sub(next) > taskMissedGrace {
+				log.Printf("[%s] Skipped scheduled task %s missed at %s", cfg.Name, task.ID, task.NextRun)
+				task.scheduleNextRun(now)
+				m.persist()
+				continue
+			}
+			due = append(due, pending{serverID: id, name: cfg.Name, task: *task})
+		}
+	}
+	m.mu.Unlock()
+
+	for _, p := range due {
+		err := m.runTask(p.serverID, p.task)
+		if err != nil {
+			log.Printf("[%s] Scheduled task %s (%s) failed: %v", p.name, p.task.ID, p.task.Action, err)
+		}
+		m.recordTaskRun(p.serverID, p.task.ID, now, err, true)
+	}
+}
@@ -0,0 +1,106 @@
+package minecraft
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	loc := time.UTC
+	from := time.Date(2025, 3, 14, 3, 56, 30, 0, loc) // a Friday
+	cases := map[string]time.Time{
+		"55 3 * * *":    time.Date(2025, 3, 15, 3, 55, 0, 0, loc),
+		"0 4 * * *":     time.Date(2025, 3, 14, 4, 0, 0, 0, loc),
+		"*/15 * * * *":  time.Date(2025, 3, 14, 4, 0, 0, 0, loc),
+		"0 12 * * mon":  time.Date(2025, 3, 17, 12, 0, 0, 0, loc),
+		"0 0 1 jan *":   time.Date(2026, 1, 1, 0, 0, 0, 0, loc),
+		"@hourly":       time.Date(2025, 3, 14, 4, 0, 0, 0, loc),
+		"30 9 1-7 * 7":  time.Date(2025, 3, 16, 9, 30, 0, 0, loc),
+		"0 18 * * 1-5":  time.Date(2025, 3, 14, 18, 0, 0, 0, loc),
+		"0 0 29 feb *":  time.Date(2028, 2, 29, 0, 0, 0, 0, loc),
+		"5,10 4 14 3 *": time.Date(2025, 3, 14, 4, 5, 0, 0, loc),
+	}
+	for expr, want := range cases {
+		schedule, err := parseCron(expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q) failed: %v", expr, err)
+		}
+		if got := schedule.next(from); !got.Equal(want) {
+			t.Fatalf("next(%q) = %v, want %v", expr, got, want)
+		}
+	}
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "0 0 30 feb *"} {
+		task := ScheduledTask{Schedule: expr, Action: TaskBackup}
+		if err := validateTask(&task); err == nil {
+			t.Fatalf("expected schedule %q to be rejected", expr)
+		}
+	}
+}
+
+func TestScheduledTasksRunWhenDue(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	mgr.mu.Lock()
+	mgr.configs["srv"] = &ServerConfig{ID: "srv", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	mgr.running["srv"] = &runningServer{status: "Stopped"}
+	mgr.mu.Unlock()
+
+	if _, err := mgr.SaveTask("srv", "", ScheduledTask{Schedule: "55 3 * * *", Action: TaskBroadcast}); err == nil {
+		t.Fatalf("expected a broadcast without a message to be rejected")
+	}
+	task, err := mgr.SaveTask("srv", "", ScheduledTask{Name: "Warn", Schedule: "55 3 * * *", Action: TaskBroadcast, Message: "Restart in 5 min", Enabled: true})
+	if err != nil {
+		t.Fatalf("SaveTask failed: %v", err)
+	}
+	if task.ID == "" || task.NextRun == "" {
+		t.Fatalf("expected an ID and next run, got %+v", task)
+	}
+
+	// Pretend the run came due a minute ago.
+	due := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	mgr.mu.Lock()
+	mgr.configs["srv"].Tasks[0].NextRun = due
+	mgr.mu.Unlock()
+	mgr.checkScheduledTasks()
+
+	tasks, err := mgr.ListTasks("srv")
+	if err != nil {
+		t.Fatalf("ListTasks failed: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].LastRun == "" || tasks[0].LastResult == "ok" || tasks[0].NextRun <= due {
+		t.Fatalf("expected the run to fail on a stopped server and move on, got %+v", tasks)
+	}
+
+	// A run missed by far more than the grace period is skipped.
+	missed := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	mgr.mu.Lock()
+	mgr.configs["srv"].Tasks[0].NextRun = missed
+	mgr.configs["srv"].Tasks[0].LastRun = ""
+	mgr.mu.Unlock()
+	mgr.checkScheduledTasks()
+	if tasks, _ = mgr.ListTasks("srv"); tasks[0].LastRun != "" || tasks[0].NextRun <= missed {
+		t.Fatalf("expected the missed run to be skipped, got %+v", tasks[0])
+	}
+
+	disabled := *task
+	disabled.Enabled = false
+	updated, err := mgr.SaveTask("srv", task.ID, disabled)
+	if err != nil || updated.NextRun != "" || updated.ID != task.ID {
+		t.Fatalf("expected a disabled task without a next run, got %+v (%v)", updated, err)
+	}
+	if _, err := mgr.RunTask("srv", task.ID); err == nil || !strings.Contains(err.Error(), "not running") {
+		t.Fatalf("expected running a broadcast on a stopped server to fail, got %v", err)
+	}
+	if err := mgr.DeleteTask("srv", task.ID); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if tasks, _ = mgr.ListTasks("srv"); len(tasks) != 0 {
+		t.Fatalf("expected no tasks, got %+v", tasks)
+	}
+}
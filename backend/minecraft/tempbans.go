@@ -0,0 +1,247 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxTempBanDuration caps how long a temporary ban may last.
+const maxTempBanDuration = 365 * 24 * time.Hour
+
+var banDurationPattern = regexp.MustCompile(`^(\d+)\s*([mhdw])$`)
+
+// TempBan is a ban the panel lifts by itself once it expires.
+type TempBan struct {
+	Player    string `json:"player"`
+	Reason    string `json:"reason,omitempty"`
+	BannedAt  string `json:"bannedAt"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+// parseBanDuration reads durations such as 30m, 12h, 3d or 2w.
+func parseBanDuration(s string) (time.Duration, error) {
+	match := banDurationPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if match == nil {
+		return 0, fmt.Errorf("invalid duration %q, use a number followed by m, h, d or w", s)
+	}
+	n, err := strconv.Atoi(match[1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("duration must be positive")
+	}
+	unit := map[string]time.Duration{"m": time.Minute, "h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[match[2]]
+	if time.Duration(n) > maxTempBanDuration/unit {
+		return 0, fmt.Errorf("temporary bans can last at most 365 days")
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// removeTempBanLocked drops a player's pending temporary ban and reports
+// whether there was one. Callers hold m.mu.
+func removeTempBanLocked(cfg *ServerConfig, player string) bool {
+	for i, ban := range cfg.TempBans {
+		if strings.EqualFold(ban.Player, player) {
+			cfg.TempBans = append(cfg.TempBans[:i], cfg.TempBans[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// TempBanPlayer bans a player and records when the panel should pardon
+// them. Banning an already temp-banned player replaces the old expiry.
+func (m *Manager) TempBanPlayer(id, playerName, reason, duration string) (*TempBan, error) {
+	if !isValidPlayerName(playerName) {
+		return nil, fmt.Errorf("invalid player name")
+	}
+	d, err := parseBanDuration(duration)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	expires := now.Add(d)
+	until := expires.Format("2006-01-02 15:04 MST")
+	message := "Banned until " + until
+	if reason = strings.TrimSpace(reason); reason != "" {
+		message = fmt.Sprintf("%s (until %s)", reason, until)
+	}
+	if err := m.SendCommand(id, fmt.Sprintf("ban %s %s", playerName, message)); err != nil {
+		return nil, err
+	}
+
+	ban := TempBan{
+		Player:    playerName,
+		Reason:    reason,
+		BannedAt:  now.UTC().Format(time.RFC3339),
+		ExpiresAt: expires.UTC().Format(time.RFC3339),
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	removeTempBanLocked(cfg, playerName)
+	cfg.TempBans = append(cfg.TempBans, ban)
+	if err := m.persist(); err != nil {
+		return nil, err
+	}
+	log.Printf("[%s] Temporarily banned %s until %s", cfg.Name, playerName, ban.ExpiresAt)
+	return &ban, nil
+}
+
+// ListTempBans returns a server's pending temporary bans, soonest expiry
+// first.
+func (m *Manager) ListTempBans(id string) ([]TempBan, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	bans := append([]TempBan{}, cfg.TempBans...)
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].ExpiresAt < bans[j].ExpiresAt
+	})
+	return bans, nil
+}
+
+// LiftTempBan pardons a temporarily banned player before the ban expires.
+func (m *Manager) LiftTempBan(id, playerName string) error {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	var found bool
+	var snapshot ServerConfig
+	if err == nil {
+		snapshot = *cfg
+		for _, ban := range cfg.TempBans {
+			if strings.EqualFold(ban.Player, playerName) {
+				found = true
+				playerName = ban.Player
+				break
+			}
+		}
+	}
+	rs := m.running[id]
+	m.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%s has no temporary ban", playerName)
+	}
+	if err := m.pardonPlayer(&snapshot, rs, playerName); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cfg, ok := m.configs[id]; ok && removeTempBanLocked(cfg, playerName) {
+		return m.persist()
+	}
+	return nil
+}
+
+// checkTempBans pardons players whose temporary bans have run out. Bans on
+// servers that are starting up are retried on the next check.
+func (m *Manager) checkTempBans() {
+	type pending struct {
+		cfg    ServerConfig
+		rs     *runningServer
+		player string
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	var due []pending
+	m.mu.RLock()
+	for id, cfg := range m.configs {
+		for _, ban := range cfg.TempBans {
+			if ban.ExpiresAt <= now {
+				due = append(due, pending{cfg: *cfg, rs: m.running[id], player: ban.Player})
+			}
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, p := range due {
+		if err := m.pardonPlayer(&p.cfg, p.rs, p.player); err != nil {
+			log.Printf("[%s] Failed to lift temporary ban of %s: %v", p.cfg.Name, p.player, err)
+			continue
+		}
+		log.Printf("[%s] Temporary ban of %s expired", p.cfg.Name, p.player)
+		m.RecordModeration(ModerationEvent{
+			ServerID:   p.cfg.ID,
+			ServerName: p.cfg.Name,
+			Actor:      moderationSystemActor,
+			Action:     ModerationPardon,
+			Player:     p.player,
+			Reason:     "Temporary ban expired",
+		})
+		m.mu.Lock()
+		if cfg, ok := m.configs[p.cfg.ID]; ok && removeTempBanLocked(cfg, p.player) {
+			m.persist()
+		}
+		m.mu.Unlock()
+	}
+}
+
+// pardonPlayer unbans a player, through the console when the server runs
+// and by editing banned-players.json when it is stopped.
+func (m *Manager) pardonPlayer(cfg *ServerConfig, rs *runningServer, player string) error {
+	status := "Stopped"
+	if rs != nil {
+		status = rs.currentRuntime().status
+	}
+	switch status {
+	case "Running":
+		if err := m.SendCommand(cfg.ID, "pardon "+player); err != nil {
+			return err
+		}
+		m.broadcastLog(rs, m.appendLog(rs, fmt.Sprintf("[Temp Ban] %s's ban expired or was lifted", player)))
+		return nil
+	case "Stopped", "Crashed", "Error":
+		if err := m.validateManagedServerDir(cfg.Dir); err != nil {
+			return err
+		}
+		return removeFromBanList(filepath.Join(cfg.Dir, "banned-players.json"), player)
+	default:
+		return fmt.Errorf("server is %s", strings.ToLower(status))
+	}
+}
+
+// removeFromBanList deletes a player's entry from a server's
+// banned-players.json, keeping every other entry and field as it was.
+func removeFromBanList(path, player string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var entries []map[string]any
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	kept := entries[:0]
+	for _, entry := range entries {
+		if name, _ := entry["name"].(string); strings.EqualFold(name, player) {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	if len(kept) == len(entries) {
+		return nil
+	}
+	out, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(out, '\n'), 0644)
+}
@@ -0,0 +1,90 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBanDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"30m": 30 * time.Minute,
+		"12h": 12 * time.Hour,
+		"3d":  72 * time.Hour,
+		" 2W": 14 * 24 * time.Hour,
+	}
+	for in, want := range cases {
+		got, err := parseBanDuration(in)
+		if err != nil || got != want {
+			t.Fatalf("parseBanDuration(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "0d", "3", "3y", "-1h", "53w"} {
+		if _, err := parseBanDuration(in); err == nil {
+			t.Fatalf("expected %q to be rejected", in)
+		}
+	}
+}
+
+func TestExpiredTempBanIsLiftedOnStoppedServer(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		t.Fatalf("failed to create server dir: %v", err)
+	}
+	banList := `[
+  {"uuid": "1", "name": "Griefer", "created": "2024-01-01 00:00:00 +0000", "source": "Server", "expires": "forever", "reason": "x"},
+  {"uuid": "2", "name": "Cheater", "created": "2024-01-01 00:00:00 +0000", "source": "Server", "expires": "forever", "reason": "y"}
+]`
+	banPath := filepath.Join(cfg.Dir, "banned-players.json")
+	if err := os.WriteFile(banPath, []byte(banList), 0644); err != nil {
+		t.Fatalf("failed to write ban list: %v", err)
+	}
+	past := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	cfg.TempBans = []TempBan{
+		{Player: "griefer", ExpiresAt: past},
+		{Player: "Cheater", ExpiresAt: future},
+	}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = &runningServer{status: "Stopped"}
+	mgr.mu.Unlock()
+
+	mgr.checkTempBans()
+
+	bans, err := mgr.ListTempBans(cfg.ID)
+	if err != nil {
+		t.Fatalf("ListTempBans failed: %v", err)
+	}
+	if len(bans) != 1 || bans[0].Player != "Cheater" {
+		t.Fatalf("expected only the unexpired ban to remain, got %+v", bans)
+	}
+	data, err := os.ReadFile(banPath)
+	if err != nil {
+		t.Fatalf("failed to read ban list: %v", err)
+	}
+	if strings.Contains(string(data), "Griefer") || !strings.Contains(string(data), `"expires": "forever"`) {
+		t.Fatalf("unexpected ban list after expiry:\n%s", data)
+	}
+
+	if err := mgr.LiftTempBan(cfg.ID, "cheater"); err != nil {
+		t.Fatalf("LiftTempBan failed: %v", err)
+	}
+	if bans, _ := mgr.ListTempBans(cfg.ID); len(bans) != 0 {
+		t.Fatalf("expected no temporary bans left, got %+v", bans)
+	}
+	if data, _ := os.ReadFile(banPath); strings.Contains(string(data), "Cheater") {
+		t.Fatalf("expected Cheater to be pardoned:\n%s", data)
+	}
+	if err := mgr.LiftTempBan(cfg.ID, "Nobody"); err == nil {
+		t.Fatalf("expected lifting an unknown ban to fail")
+	}
+}
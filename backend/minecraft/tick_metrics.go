@@ -0,0 +1,104 @@
+package minecraft
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// forgeDimensionTickPattern matches a dimension line of "forge tps",
+	// "Dim minecraft:the_nether (minecraft:the_nether): Mean tick time:
+	// 0.301 ms. Mean TPS: 20.000", and of older Forge, "Dim  0 (overworld) :
+	// Mean tick time: ...".
+	forgeDimensionTickPattern = regexp.MustCompile(`(?i)\bDim\s+(\S+?)\s*(?:\(([^)]*)\))?\s*:\s*Mean tick time:\s*([0-9.]+)\s*ms\.?\s*Mean TPS:\s*([0-9.]+)`)
+	// neoForgeDimensionTickPattern matches a dimension line of "neoforge
+	// tps", "minecraft:overworld: 20.000 TPS (0.510 ms/tick)".
+	neoForgeDimensionTickPattern = regexp.MustCompile(`(?i)(?:^|[\s\]])([a-z0-9_.-]+:[a-z0-9_./-]+)(?:\s*\([^)]*\))?:\s*([0-9.]+)\s*TPS\s*\(([0-9.]+)\s*ms/tick\)`)
+	neoForgeOverallTickPattern   = regexp.MustCompile(`(?i)overall:\s*[0-9.]+\s*TPS\s*\(([0-9.]+)\s*ms/tick\)`)
+)
+
+// DimensionTick is one dimension's tick rate from a modded server's tps
+// command.
+type DimensionTick struct {
+	Dimension  string  `json:"dimension"`
+	TPS        float64 `json:"tps"`
+	MeanTickMS float64 `json:"meanTickMs"`
+}
+
+// TickMetrics is the last complete report of Forge's or NeoForge's tps
+// command. Dimensions are sorted slowest first, so the one lagging the
+// server leads the list.
+type TickMetrics struct {
+	MeanTickMS float64         `json:"meanTickMs,omitempty"`
+	Dimensions []DimensionTick `json:"dimensions"`
+	UpdatedAt  string          `json:"updatedAt"`
+}
+
+// parseDimensionTick reads one dimension line of a tps report.
+func parseDimensionTick(line string) (DimensionTick, bool) {
+	if match := forgeDimensionTickPattern.FindStringSubmatch(line); match != nil {
+		dimension := match[1]
+		// Older Forge numbers dimensions and names them in brackets.
+		if _, err := strconv.Atoi(dimension); err == nil && strings.TrimSpace(match[2]) != "" {
+			dimension = strings.TrimSpace(match[2])
+		}
+		mspt, errMSPT := strconv.ParseFloat(match[3], 64)
+		tps, errTPS := strconv.ParseFloat(match[4], 64)
+		if errMSPT == nil && errTPS == nil {
+			return DimensionTick{Dimension: dimension, TPS: tps, MeanTickMS: mspt}, true
+		}
+	}
+	if match := neoForgeDimensionTickPattern.FindStringSubmatch(line); match != nil {
+		tps, errTPS := strconv.ParseFloat(match[2], 64)
+		mspt, errMSPT := strconv.ParseFloat(match[3], 64)
+		if errMSPT == nil && errTPS == nil {
+			return DimensionTick{Dimension: match[1], TPS: tps, MeanTickMS: mspt}, true
+		}
+	}
+	return DimensionTick{}, false
+}
+
+// parseOverallTickMS reads the mean tick time of a tps report's Overall
+// line.
+func parseOverallTickMS(line string) (float64, bool) {
+	for _, pattern := range []*regexp.Regexp{forgeMSPTPattern, neoForgeOverallTickPattern} {
+		if match := pattern.FindStringSubmatch(line); match != nil {
+			if v, err := strconv.ParseFloat(match[1], 64); err == nil {
+				return v, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// addDimensionTickLocked collects a dimension line until the report's
+// Overall line. A dimension seen twice means an earlier report never
+// finished, so collection starts over. Callers hold rs.mu.
+func addDimensionTickLocked(rs *runningServer, tick DimensionTick) {
+	for _, pending := range rs.pendingDimensionTicks {
+		if pending.Dimension == tick.Dimension {
+			rs.pendingDimensionTicks = nil
+			break
+		}
+	}
+	rs.pendingDimensionTicks = append(rs.pendingDimensionTicks, tick)
+}
+
+// publishTickMetricsLocked turns the collected dimension lines into the
+// server's tick metrics once the Overall line arrives. Callers hold rs.mu.
+func publishTickMetricsLocked(rs *runningServer, overallLine string, now time.Time) {
+	if len(rs.pendingDimensionTicks) == 0 {
+		return
+	}
+	dimensions := rs.pendingDimensionTicks
+	rs.pendingDimensionTicks = nil
+	sort.SliceStable(dimensions, func(i, j int) bool { return dimensions[i].MeanTickMS > dimensions[j].MeanTickMS })
+	metrics := &TickMetrics{Dimensions: dimensions, UpdatedAt: now.UTC().Format(time.RFC3339)}
+	if mspt, ok := parseOverallTickMS(overallLine); ok {
+		metrics.MeanTickMS = mspt
+	}
+	rs.tickMetrics = metrics
+}
@@ -0,0 +1,80 @@
+package minecraft
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseDimensionTick(t *testing.T) {
+	cases := []struct {
+		line string
+		want DimensionTick
+	}{
+		{
+			line: "[12:00:00] [Server thread/INFO] [minecraft/MinecraftServer]: Dim minecraft:the_nether (minecraft:the_nether): Mean tick time: 12.301 ms. Mean TPS: 20.000",
+			want: DimensionTick{Dimension: "minecraft:the_nether", TPS: 20, MeanTickMS: 12.301},
+		},
+		{
+			line: "[12:00:00 INFO]: Dim  0 (overworld) : Mean tick time: 61.500 ms. Mean TPS: 16.260",
+			want: DimensionTick{Dimension: "overworld", TPS: 16.26, MeanTickMS: 61.5},
+		},
+		{
+			line: "[12:00:00] [Server thread/INFO] [minecraft/MinecraftServer]: mekanism:dimension: 19.500 TPS (51.200 ms/tick)",
+			want: DimensionTick{Dimension: "mekanism:dimension", TPS: 19.5, MeanTickMS: 51.2},
+		},
+	}
+	for _, c := range cases {
+		got, ok := parseDimensionTick(c.line)
+		if !ok || got != c.want {
+			t.Fatalf("parseDimensionTick(%q) = %+v, %v; want %+v", c.line, got, ok, c.want)
+		}
+	}
+	for _, line := range []string{
+		"[12:00:00 INFO]: Overall: Mean tick time: 0.755 ms. Mean TPS: 20.000",
+		"[12:00:00 INFO]: Overall: 20.000 TPS (0.755 ms/tick)",
+		"[12:00:00 INFO]: TPS from last 1m, 5m, 15m: 19.8, 20.0, 20.0",
+	} {
+		if got, ok := parseDimensionTick(line); ok {
+			t.Fatalf("expected %q not to be a dimension line, got %+v", line, got)
+		}
+	}
+	if _, ok := parseBenchmarkTPS(cases[0].line); ok {
+		t.Fatalf("expected a dimension line not to count as the server's TPS")
+	}
+}
+
+func TestForgeTpsReportBecomesTickMetrics(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	rs := &runningServer{status: "Running", players: make(map[string]*onlinePlayer)}
+	mgr.mu.Lock()
+	mgr.configs["srv"] = &ServerConfig{ID: "srv", Name: "Modded", Type: "Forge", Dir: filepath.Join(mgr.serversRoot, "Modded")}
+	mgr.running["srv"] = rs
+	mgr.mu.Unlock()
+
+	report := strings.Join([]string{
+		"[12:00:00 INFO]: Dim minecraft:overworld (minecraft:overworld): Mean tick time: 10.000 ms. Mean TPS: 20.000",
+		"[12:00:00 INFO]: Dim minecraft:the_nether (minecraft:the_nether): Mean tick time: 70.000 ms. Mean TPS: 14.286",
+		"[12:00:00 INFO]: Overall: Mean tick time: 80.000 ms. Mean TPS: 12.500",
+	}, "\n") + "\n"
+	mgr.scanOutput("srv", rs, strings.NewReader(report))
+
+	mgr.mu.RLock()
+	info := mgr.serverInfo("srv")
+	mgr.mu.RUnlock()
+	if info.TPS != 12.5 {
+		t.Fatalf("expected the Overall TPS, got %v", info.TPS)
+	}
+	metrics := info.TickMetrics
+	if metrics == nil || metrics.MeanTickMS != 80 || len(metrics.Dimensions) != 2 {
+		t.Fatalf("expected tick metrics for two dimensions, got %+v", metrics)
+	}
+	if metrics.Dimensions[0].Dimension != "minecraft:the_nether" || metrics.Dimensions[0].TPS != 14.286 {
+		t.Fatalf("expected the slowest dimension first, got %+v", metrics.Dimensions)
+	}
+}
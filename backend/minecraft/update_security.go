@@ -33,6 +33,11 @@ var defaultPluginUpdateAllowedHosts = map[string]struct{}{
 	"github.com":                    {},
 	"raw.githubusercontent.com":     {},
 	"objects.githubusercontent.com": {},
+	// Jenkins servers that publish dev builds of popular plugins.
+	"ci.dmulloy2.net": {},
+	"ci.lucko.me":     {},
+	"ci.codemc.io":    {},
+	"ci.ender.zone":   {},
 }
 
 type pluginUpdateDownloadResult struct {
@@ -0,0 +1,271 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// User roles, from most to least privileged.
+const (
+	RoleAdmin    = "admin"
+	RoleOperator = "operator"
+	RoleViewer   = "viewer"
+)
+
+// maxUserAccounts caps the additional accounts stored in users.json.
+const maxUserAccounts = 100
+
+// userAccount is one additional login stored in users.json. The login from
+// the panel settings is always an admin and is not stored here.
+type userAccount struct {
+	Username     string    `json:"username"`
+	Role         string    `json:"role"`
+	PasswordHash string    `json:"passwordHash"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// UserInfo is the API view of an account.
+type UserInfo struct {
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	CreatedAt string `json:"createdAt,omitempty"`
+	// Primary marks the login from the panel settings, which is changed
+	// there and cannot be deleted.
+	Primary bool `json:"primary,omitempty"`
+}
+
+// UserInput creates an account.
+type UserInput struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+// UserUpdate changes an account's role or password. Nil fields are kept.
+type UserUpdate struct {
+	Role     *string `json:"role"`
+	Password *string `json:"password"`
+}
+
+// IsValidRole reports whether role is one of the known roles.
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleOperator, RoleViewer:
+		return true
+	default:
+		return false
+	}
+}
+
+// loadUsers reads users.json. A missing document is no extra accounts.
+func (m *Manager) loadUsers() error {
+	m.usersMu.Lock()
+	defer m.usersMu.Unlock()
+
+	m.users = make(map[string]userAccount)
+	data, err := m.storage().Load(storeDocUsers)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read users: %w", err)
+	}
+	var accounts []userAccount
+	if err := json.Unmarshal(data, &accounts); err != nil {
+		log.Printf("users.json is corrupt (%v); attempting recovery from panel backups", err)
+		recovered, recoverErr := m.recoverDataFile(storeDocUsers, validateUsersJSON)
+		if recoverErr != nil {
+			return fmt.Errorf("failed to parse users: %w (recovery failed: %v)", err, recoverErr)
+		}
+		if err := json.Unmarshal(recovered, &accounts); err != nil {
+			return fmt.Errorf("failed to parse recovered users: %w", err)
+		}
+	}
+	for _, account := range accounts {
+		if account.Username == "" || !IsValidRole(account.Role) {
+			log.Printf("Skipping invalid user account %q", account.Username)
+			continue
+		}
+		m.users[account.Username] = account
+	}
+	return nil
+}
+
+// marshalUsersLocked encodes the accounts as stored in users.json.
+// Callers hold m.usersMu.
+func (m *Manager) marshalUsersLocked() ([]byte, error) {
+	accounts := make([]userAccount, 0, len(m.users))
+	for _, account := range m.users {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Username < accounts[j].Username })
+	data, err := json.MarshalIndent(accounts, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal users: %w", err)
+	}
+	return data, nil
+}
+
+// persistUsersLocked saves users.json. Callers hold m.usersMu.
+func (m *Manager) persistUsersLocked() error {
+	data, err := m.marshalUsersLocked()
+	if err != nil {
+		return err
+	}
+	if err := m.storage().Save(storeDocUsers, data); err != nil {
+		return fmt.Errorf("failed to save users: %w", err)
+	}
+	return nil
+}
+
+func (m *Manager) primaryLoginUser() string {
+	m.settingsMu.RLock()
+	defer m.settingsMu.RUnlock()
+	return m.settings.LoginUser
+}
+
+// ListUsers returns the settings login first, then other accounts by name.
+func (m *Manager) ListUsers() []UserInfo {
+	users := []UserInfo{{Username: m.primaryLoginUser(), Role: RoleAdmin, Primary: true}}
+
+	m.usersMu.Lock()
+	defer m.usersMu.Unlock()
+	names := make([]string, 0, len(m.users))
+	for name := range m.users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		account := m.users[name]
+		users = append(users, UserInfo{
+			Username:  account.Username,
+			Role:      account.Role,
+			CreatedAt: account.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return users
+}
+
+// CreateUser adds an account with its own password and role.
+func (m *Manager) CreateUser(input UserInput) (*UserInfo, error) {
+	username := strings.TrimSpace(input.Username)
+	if len(username) < 4 || len(username) > 12 {
+		return nil, fmt.Errorf("username must be between 4 and 12 characters")
+	}
+	if !IsValidRole(input.Role) {
+		return nil, fmt.Errorf("role must be admin, operator or viewer")
+	}
+	if len(input.Password) < LoginPasswordMinLength {
+		return nil, fmt.Errorf("password must be at least %d characters", LoginPasswordMinLength)
+	}
+	if username == m.primaryLoginUser() {
+		return nil, fmt.Errorf("user %s already exists", username)
+	}
+	hash, err := hashPassword(input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	m.usersMu.Lock()
+	defer m.usersMu.Unlock()
+	if _, exists := m.users[username]; exists {
+		return nil, fmt.Errorf("user %s already exists", username)
+	}
+	if len(m.users) >= maxUserAccounts {
+		return nil, fmt.Errorf("at most %d additional users are allowed", maxUserAccounts)
+	}
+	account := userAccount{
+		Username:     username,
+		Role:         input.Role,
+		PasswordHash: hash,
+		CreatedAt:    time.Now(),
+	}
+	m.users[username] = account
+	if err := m.persistUsersLocked(); err != nil {
+		delete(m.users, username)
+		return nil, err
+	}
+	return &UserInfo{Username: username, Role: account.Role, CreatedAt: account.CreatedAt.UTC().Format(time.RFC3339)}, nil
+}
+
+// UpdateUser changes an account's role or password. The settings login is
+// changed through the settings instead.
+func (m *Manager) UpdateUser(username string, update UserUpdate) (*UserInfo, error) {
+	if update.Role != nil && !IsValidRole(*update.Role) {
+		return nil, fmt.Errorf("role must be admin, operator or viewer")
+	}
+	var hash string
+	if update.Password != nil {
+		if len(*update.Password) < LoginPasswordMinLength {
+			return nil, fmt.Errorf("password must be at least %d characters", LoginPasswordMinLength)
+		}
+		var err error
+		if hash, err = hashPassword(*update.Password); err != nil {
+			return nil, err
+		}
+	}
+	if username == m.primaryLoginUser() {
+		return nil, fmt.Errorf("change the primary login in System Settings")
+	}
+
+	m.usersMu.Lock()
+	defer m.usersMu.Unlock()
+	account, ok := m.users[username]
+	if !ok {
+		return nil, fmt.Errorf("user %s not found", username)
+	}
+	previous := account
+	if update.Role != nil {
+		account.Role = *update.Role
+	}
+	if hash != "" {
+		account.PasswordHash = hash
+	}
+	m.users[username] = account
+	if err := m.persistUsersLocked(); err != nil {
+		m.users[username] = previous
+		return nil, err
+	}
+	return &UserInfo{Username: username, Role: account.Role, CreatedAt: account.CreatedAt.UTC().Format(time.RFC3339)}, nil
+}
+
+// DeleteUser removes an account. The settings login cannot be deleted.
+func (m *Manager) DeleteUser(username string) error {
+	if username == m.primaryLoginUser() {
+		return fmt.Errorf("the primary login cannot be deleted")
+	}
+	m.usersMu.Lock()
+	defer m.usersMu.Unlock()
+	account, ok := m.users[username]
+	if !ok {
+		return fmt.Errorf("user %s not found", username)
+	}
+	delete(m.users, username)
+	if err := m.persistUsersLocked(); err != nil {
+		m.users[username] = account
+		return err
+	}
+	return nil
+}
+
+// AuthenticateUser checks a login against the settings login and the
+// stored accounts and returns the account's role.
+func (m *Manager) AuthenticateUser(username, password string) (string, bool) {
+	if m.ValidateLogin(username, password) {
+		return RoleAdmin, true
+	}
+	username = strings.TrimSpace(username)
+	m.usersMu.Lock()
+	account, ok := m.users[username]
+	m.usersMu.Unlock()
+	if !ok || !verifyPassword(account.PasswordHash, password) {
+		return "", false
+	}
+	return account.Role, true
+}
@@ -0,0 +1,65 @@
+package minecraft
+
+import "testing"
+
+func TestUserAccountsAuthenticateWithRoles(t *testing.T) {
+	base := t.TempDir()
+	mgr, err := NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	if _, err := mgr.CreateUser(UserInput{Username: "viewer1", Password: "short", Role: RoleViewer}); err == nil {
+		t.Fatalf("expected a short password to be rejected")
+	}
+	if _, err := mgr.CreateUser(UserInput{Username: "viewer1", Password: "longenough1", Role: "owner"}); err == nil {
+		t.Fatalf("expected an unknown role to be rejected")
+	}
+	if _, err := mgr.CreateUser(UserInput{Username: defaultLoginUser(), Password: "longenough1", Role: RoleViewer}); err == nil {
+		t.Fatalf("expected the primary login name to be taken")
+	}
+	if _, err := mgr.CreateUser(UserInput{Username: "viewer1", Password: "longenough1", Role: RoleViewer}); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if _, err := mgr.CreateUser(UserInput{Username: "viewer1", Password: "longenough1", Role: RoleViewer}); err == nil {
+		t.Fatalf("expected a duplicate username to be rejected")
+	}
+
+	if role, ok := mgr.AuthenticateUser("viewer1", "longenough1"); !ok || role != RoleViewer {
+		t.Fatalf("expected viewer login, got %q %v", role, ok)
+	}
+	if _, ok := mgr.AuthenticateUser("viewer1", "wrongpassword"); ok {
+		t.Fatalf("expected a wrong password to fail")
+	}
+	if role, ok := mgr.AuthenticateUser(defaultLoginUser(), defaultLoginPassword()); !ok || role != RoleAdmin {
+		t.Fatalf("expected the settings login to be admin, got %q %v", role, ok)
+	}
+
+	operator := RoleOperator
+	if _, err := mgr.UpdateUser("viewer1", UserUpdate{Role: &operator}); err != nil {
+		t.Fatalf("UpdateUser failed: %v", err)
+	}
+	if _, err := mgr.UpdateUser(defaultLoginUser(), UserUpdate{Role: &operator}); err == nil {
+		t.Fatalf("expected the primary login to be managed in settings")
+	}
+	if err := mgr.DeleteUser(defaultLoginUser()); err == nil {
+		t.Fatalf("expected the primary login to be undeletable")
+	}
+	mgr.StopAll()
+
+	reloaded, err := NewManager(base)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer reloaded.StopAll()
+	users := reloaded.ListUsers()
+	if len(users) != 2 || !users[0].Primary || users[1].Username != "viewer1" || users[1].Role != RoleOperator {
+		t.Fatalf("unexpected users after reload %+v", users)
+	}
+	if err := reloaded.DeleteUser("viewer1"); err != nil {
+		t.Fatalf("DeleteUser failed: %v", err)
+	}
+	if _, ok := reloaded.AuthenticateUser("viewer1", "longenough1"); ok {
+		t.Fatalf("expected a deleted user to be unable to log in")
+	}
+}
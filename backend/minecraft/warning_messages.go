@@ -0,0 +1,156 @@
+package minecraft
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const maxWarningMessageLength = 200
+
+var warningPlaceholderPattern = regexp.MustCompile(`\{([A-Za-z]+)\}`)
+
+// warningPlaceholders are the values a warning template may reference.
+var warningPlaceholders = map[string]bool{"minutes": true, "seconds": true, "reason": true}
+
+// WarningMessages overrides the broadcasts players see before a scheduled
+// restart or stop. Empty fields use the panel's translated default.
+// Templates may use {minutes}, {seconds} and {reason}.
+type WarningMessages struct {
+	Restart    string `json:"restart,omitempty"`
+	RestartNow string `json:"restartNow,omitempty"`
+	Stop       string `json:"stop,omitempty"`
+	StopNow    string `json:"stopNow,omitempty"`
+}
+
+func (w WarningMessages) isZero() bool {
+	return w == WarningMessages{}
+}
+
+func normalizeWarningTemplate(field, template string) (string, error) {
+	template = strings.TrimSpace(template)
+	if strings.ContainsAny(template, "\r\n") {
+		return "", fmt.Errorf("%s message must be a single line", field)
+	}
+	if len(template) > maxWarningMessageLength {
+		return "", fmt.Errorf("%s message must be at most %d characters", field, maxWarningMessageLength)
+	}
+	for _, match := range warningPlaceholderPattern.FindAllStringSubmatch(template, -1) {
+		if !warningPlaceholders[match[1]] {
+			return "", fmt.Errorf("%s message uses unknown placeholder %s; use {minutes}, {seconds} or {reason}", field, match[0])
+		}
+	}
+	return template, nil
+}
+
+func normalizeWarningMessages(msgs WarningMessages) (WarningMessages, error) {
+	var err error
+	if msgs.Restart, err = normalizeWarningTemplate("restart", msgs.Restart); err != nil {
+		return WarningMessages{}, err
+	}
+	if msgs.RestartNow, err = normalizeWarningTemplate("restart now", msgs.RestartNow); err != nil {
+		return WarningMessages{}, err
+	}
+	if msgs.Stop, err = normalizeWarningTemplate("stop", msgs.Stop); err != nil {
+		return WarningMessages{}, err
+	}
+	if msgs.StopNow, err = normalizeWarningTemplate("stop now", msgs.StopNow); err != nil {
+		return WarningMessages{}, err
+	}
+	return msgs, nil
+}
+
+// SetWarningMessages replaces a server's restart and stop warning templates.
+func (m *Manager) SetWarningMessages(id string, msgs WarningMessages) (*ServerInfo, error) {
+	normalized, err := normalizeWarningMessages(msgs)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if normalized.isZero() {
+		cfg.WarningMessages = nil
+	} else {
+		cfg.WarningMessages = &normalized
+	}
+	if err := m.persist(); err != nil {
+		return nil, err
+	}
+
+	return m.serverInfo(id), nil
+}
+
+// Warning kinds passed to warningCommand.
+const (
+	warningRestart    = "restart"
+	warningRestartNow = "restartNow"
+	warningStop       = "stop"
+	warningStopNow    = "stopNow"
+)
+
+// warningCommand builds the "say" command for a restart or stop warning
+// that fires seconds before the action. The server's own template wins over
+// the translated default; a reason the template does not place is appended.
+func (m *Manager) warningCommand(id, kind string, seconds int, reason string) string {
+	m.mu.RLock()
+	var custom WarningMessages
+	if cfg := m.configs[id]; cfg != nil && cfg.WarningMessages != nil {
+		custom = *cfg.WarningMessages
+	}
+	m.mu.RUnlock()
+
+	minutes := seconds / 60
+	params := map[string]any{"minutes": minutes, "seconds": seconds, "reason": reason}
+
+	var template string
+	switch kind {
+	case warningRestart:
+		template = custom.Restart
+	case warningRestartNow:
+		template = custom.RestartNow
+	case warningStop:
+		template = custom.Stop
+	case warningStopNow:
+		template = custom.StopNow
+	}
+
+	var message string
+	if template != "" {
+		message = formatMessageTemplate(template, params)
+	} else {
+		message = m.playerMessage(defaultWarningKey(kind, seconds), params)
+	}
+	if reason != "" && !strings.Contains(template, "{reason}") {
+		message += " (" + reason + ")"
+	}
+	return "say " + message
+}
+
+// defaultWarningKey picks the translated message for a warning, using
+// minute wording for whole minutes and seconds otherwise.
+func defaultWarningKey(kind string, seconds int) string {
+	switch kind {
+	case warningRestart:
+		switch {
+		case seconds == 60:
+			return playerMsgRestartInMinute
+		case seconds > 0 && seconds%60 == 0:
+			return playerMsgRestartInMinutes
+		default:
+			return playerMsgRestartInSeconds
+		}
+	case warningRestartNow:
+		return playerMsgRestartNow
+	case warningStop:
+		return playerMsgStopInSeconds
+	default:
+		return playerMsgStopNow
+	}
+}
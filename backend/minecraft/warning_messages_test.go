@@ -0,0 +1,50 @@
+package minecraft
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestWarningCommandUsesServerTemplates(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	mgr.mu.Lock()
+	mgr.configs["srv1"] = &ServerConfig{ID: "srv1", Name: "Lobby", Dir: filepath.Join(mgr.serversRoot, "Lobby")}
+	mgr.mu.Unlock()
+
+	if got := mgr.warningCommand("srv1", warningRestart, 300, ""); got != "say Server restarting in 5 minutes..." {
+		t.Fatalf("unexpected default warning %q", got)
+	}
+	if got := mgr.warningCommand("srv1", warningRestart, 60, "update"); got != "say Server restarting in 1 minute... (update)" {
+		t.Fatalf("unexpected default warning with reason %q", got)
+	}
+
+	if _, err := mgr.SetWarningMessages("srv1", WarningMessages{Restart: "Back in {hours}"}); err == nil {
+		t.Fatalf("expected unknown placeholder to be rejected")
+	}
+	if _, err := mgr.SetWarningMessages("srv1", WarningMessages{
+		Restart: " Restart in {minutes}m/{seconds}s: {reason} ",
+		StopNow: "Bye!",
+	}); err != nil {
+		t.Fatalf("SetWarningMessages failed: %v", err)
+	}
+
+	if got := mgr.warningCommand("srv1", warningRestart, 120, "new map"); got != "say Restart in 2m/120s: new map" {
+		t.Fatalf("unexpected templated warning %q", got)
+	}
+	if got := mgr.warningCommand("srv1", warningStopNow, 0, "maintenance"); got != "say Bye! (maintenance)" {
+		t.Fatalf("unexpected stop message %q", got)
+	}
+	if got := mgr.warningCommand("srv1", warningStop, 10, ""); got != "say Server stopping in 10 seconds..." {
+		t.Fatalf("expected default for unset template, got %q", got)
+	}
+
+	info, err := mgr.SetWarningMessages("srv1", WarningMessages{})
+	if err != nil || info.WarningMessages != nil {
+		t.Fatalf("expected templates to be cleared, got %+v (%v)", info.WarningMessages, err)
+	}
+}
@@ -0,0 +1,306 @@
+package minecraft
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxWebhooksPerServer = 20
+	maxWebhookCommands   = 10
+	minWebhookSecretLen  = 16
+)
+
+var (
+	// ErrWebhookNotFound and ErrWebhookUnauthorized let the receiver answer
+	// 404 and 401 without saying which of the two it was to a caller that
+	// does not hold the secret.
+	ErrWebhookNotFound     = errors.New("webhook not found")
+	ErrWebhookUnauthorized = errors.New("webhook secret does not match")
+	// ErrWebhookServerOffline is returned when a call arrives while the
+	// server cannot take commands. Nothing is run.
+	ErrWebhookServerOffline = errors.New("server is not running")
+
+	// webhookValuePattern is what a value from a call may look like before
+	// it is put into a command: player names, amounts and simple IDs. No
+	// spaces, so a value cannot add arguments to the command, and no @ or
+	// *, so it cannot be a target selector such as @a.
+	webhookValuePattern = regexp.MustCompile(`^[A-Za-z0-9_.,:+\-#]{1,64}$`)
+	// webhookPlayerPattern is what a player placeholder must hold: a
+	// Minecraft username.
+	webhookPlayerPattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,16}$`)
+	// webhookPlaceholderPattern finds {name} placeholders in a template.
+	webhookPlaceholderPattern = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+)
+
+// InboundWebhook lets an outside service, such as a Votifier proxy or a
+// donation platform, run console commands on a server. Each command is a
+// template whose {placeholders} are filled from the fields of the call.
+type InboundWebhook struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Commands []string `json:"commands"`
+	Enabled  bool     `json:"enabled"`
+	// SecretHash is the SHA-256 of the shared secret. It never leaves the
+	// panel; the secret itself is shown once when it is set.
+	SecretHash   string `json:"secretHash,omitempty"`
+	LastCalledAt string `json:"lastCalledAt,omitempty"`
+	LastResult   string `json:"lastResult,omitempty"`
+}
+
+// ListWebhooks returns a server's inbound webhooks without their secrets.
+func (m *Manager) ListWebhooks(id string) ([]InboundWebhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	hooks := make([]InboundWebhook, 0, len(cfg.Webhooks))
+	for _, hook := range cfg.Webhooks {
+		hook.SecretHash = ""
+		hook.Commands = append([]string(nil), hook.Commands...)
+		hooks = append(hooks, hook)
+	}
+	return hooks, nil
+}
+
+// SaveWebhook creates a webhook, or replaces the one with hookID. A new
+// webhook gets secret, or a generated one when secret is empty; an existing
+// one keeps its secret unless a new one is given. The secret is returned
+// only when it was set by this call.
+func (m *Manager) SaveWebhook(id, hookID string, hook InboundWebhook, secret string) (*InboundWebhook, string, error) {
+	hook.Name = strings.TrimSpace(hook.Name)
+	if hook.Name == "" {
+		return nil, "", fmt.Errorf("webhook name is required")
+	}
+	commands := make([]string, 0, len(hook.Commands))
+	for _, command := range hook.Commands {
+		command = strings.TrimPrefix(strings.TrimSpace(command), "/")
+		if command == "" {
+			continue
+		}
+		if len(command) > maxTaskText || strings.ContainsAny(command, "\r\n") {
+			return nil, "", fmt.Errorf("webhook commands must be a single line of at most %d characters", maxTaskText)
+		}
+		commands = append(commands, command)
+	}
+	if len(commands) == 0 || len(commands) > maxWebhookCommands {
+		return nil, "", fmt.Errorf("a webhook needs between 1 and %d commands", maxWebhookCommands)
+	}
+	hook.Commands = commands
+	secret = strings.TrimSpace(secret)
+	if secret != "" && len(secret) < minWebhookSecretLen {
+		return nil, "", fmt.Errorf("webhook secrets must be at least %d characters", minWebhookSecretLen)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, "", err
+	}
+	index := -1
+	if hookID == "" {
+		if len(cfg.Webhooks) >= maxWebhooksPerServer {
+			return nil, "", fmt.Errorf("at most %d webhooks are allowed per server", maxWebhooksPerServer)
+		}
+		hook.ID = uuid.New().String()[:8]
+		hook.LastCalledAt, hook.LastResult = "", ""
+		if secret == "" {
+			if secret, err = newWebhookSecret(); err != nil {
+				return nil, "", err
+			}
+		}
+	} else {
+		for i := range cfg.Webhooks {
+			if cfg.Webhooks[i].ID == hookID {
+				index = i
+			}
+		}
+		if index < 0 {
+			return nil, "", ErrWebhookNotFound
+		}
+		existing := cfg.Webhooks[index]
+		hook.ID = hookID
+		hook.SecretHash = existing.SecretHash
+		hook.LastCalledAt, hook.LastResult = existing.LastCalledAt, existing.LastResult
+	}
+	if secret != "" {
+		hook.SecretHash = hashWebhookSecret(secret)
+	}
+
+	if index < 0 {
+		cfg.Webhooks = append(cfg.Webhooks, hook)
+	} else {
+		cfg.Webhooks[index] = hook
+	}
+	if err := m.persist(); err != nil {
+		return nil, "", err
+	}
+	hook.SecretHash = ""
+	return &hook, secret, nil
+}
+
+// DeleteWebhook removes a webhook.
+func (m *Manager) DeleteWebhook(id, hookID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return err
+	}
+	for i := range cfg.Webhooks {
+		if cfg.Webhooks[i].ID == hookID {
+			cfg.Webhooks = append(cfg.Webhooks[:i], cfg.Webhooks[i+1:]...)
+			return m.persist()
+		}
+	}
+	return ErrWebhookNotFound
+}
+
+// ReceiveWebhook checks secret against the webhook and runs its commands
+// with values filled in. It returns the commands it ran. A value that is
+// missing or does not look like a name, number or ID fails the whole call
+// before anything runs.
+func (m *Manager) ReceiveWebhook(id, hookID, secret string, values map[string]string) ([]string, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		m.mu.RUnlock()
+		return nil, ErrWebhookNotFound
+	}
+	name := cfg.Name
+	var hook *InboundWebhook
+	for i := range cfg.Webhooks {
+		if cfg.Webhooks[i].ID == hookID {
+			h := cfg.Webhooks[i]
+			h.Commands = append([]string(nil), h.Commands...)
+			hook = &h
+		}
+	}
+	rs := m.running[id]
+	m.mu.RUnlock()
+	if hook == nil {
+		return nil, ErrWebhookNotFound
+	}
+	given := hashWebhookSecret(strings.TrimSpace(secret))
+	if hook.SecretHash == "" || subtle.ConstantTimeCompare([]byte(given), []byte(hook.SecretHash)) != 1 {
+		return nil, ErrWebhookUnauthorized
+	}
+	if !hook.Enabled {
+		return nil, fmt.Errorf("webhook %s is disabled", hook.Name)
+	}
+
+	commands, err := expandWebhookCommands(hook.Commands, values)
+	if err != nil {
+		m.recordWebhookCall(id, hookID, err.Error())
+		return nil, err
+	}
+	if rs == nil || rs.currentRuntime().status != "Running" {
+		m.recordWebhookCall(id, hookID, ErrWebhookServerOffline.Error())
+		return nil, ErrWebhookServerOffline
+	}
+
+	ran := make([]string, 0, len(commands))
+	for _, command := range commands {
+		if err := m.SendCommand(id, command); err != nil {
+			m.recordWebhookCall(id, hookID, err.Error())
+			return ran, err
+		}
+		ran = append(ran, command)
+	}
+	log.Printf("[%s] Webhook %s ran %d commands", name, hook.Name, len(ran))
+	m.recordWebhookCall(id, hookID, fmt.Sprintf("ran %d commands", len(ran)))
+	return ran, nil
+}
+
+// webhookPlayerKeys are the placeholders that name a player, whose values
+// must be a valid username.
+var webhookPlayerKeys = map[string]bool{
+	"player": true, "playername": true, "username": true, "user": true, "name": true, "target": true,
+}
+
+// expandWebhookCommands fills the placeholders in each template from
+// values. Keys are matched case-insensitively.
+func expandWebhookCommands(templates []string, values map[string]string) ([]string, error) {
+	lookup := make(map[string]string, len(values))
+	for key, value := range values {
+		lookup[strings.ToLower(key)] = strings.TrimSpace(value)
+	}
+	var missing []string
+	commands := make([]string, 0, len(templates))
+	for _, tmpl := range templates {
+		var bad error
+		command := webhookPlaceholderPattern.ReplaceAllStringFunc(tmpl, func(match string) string {
+			key := strings.ToLower(match[1 : len(match)-1])
+			value, ok := lookup[key]
+			if !ok || value == "" {
+				missing = append(missing, key)
+				return match
+			}
+			pattern := webhookValuePattern
+			if webhookPlayerKeys[key] {
+				pattern = webhookPlayerPattern
+			}
+			if !pattern.MatchString(value) {
+				bad = fmt.Errorf("value of %s is not allowed in a command", key)
+			}
+			return value
+		})
+		if bad != nil {
+			return nil, bad
+		}
+		commands = append(commands, command)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing values: %s", strings.Join(missing, ", "))
+	}
+	return commands, nil
+}
+
+func (m *Manager) recordWebhookCall(id, hookID, result string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg := m.configs[id]
+	if cfg == nil {
+		return
+	}
+	for i := range cfg.Webhooks {
+		if cfg.Webhooks[i].ID == hookID {
+			cfg.Webhooks[i].LastCalledAt = time.Now().UTC().Format(time.RFC3339)
+			cfg.Webhooks[i].LastResult = result
+			if err := m.persist(); err != nil {
+				log.Printf("[%s] Failed to record webhook call: %v", cfg.Name, err)
+			}
+			return
+		}
+	}
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashWebhookSecret hashes a secret for storage. Secrets are long and
+// random or at least minWebhookSecretLen characters, so no salt is needed.
+func hashWebhookSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
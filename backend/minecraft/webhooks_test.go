@@ -0,0 +1,84 @@
+package minecraft
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReceiveWebhookRunsCommandTemplates(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	var stdin bytes.Buffer
+	cfg := &ServerConfig{ID: "srv1", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	rs := &runningServer{status: "Stopped", stdin: nopWriteCloser{&stdin}}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = rs
+	mgr.mu.Unlock()
+
+	if _, _, err := mgr.SaveWebhook(cfg.ID, "", InboundWebhook{Name: "Votes", Commands: []string{"give {player} diamond 1"}}, "short"); err == nil {
+		t.Fatal("expected a short secret to be refused")
+	}
+	hook, secret, err := mgr.SaveWebhook(cfg.ID, "", InboundWebhook{Name: "Votes", Enabled: true, Commands: []string{"/give {player} diamond {amount}", "say Thanks {Player}!"}}, "")
+	if err != nil || secret == "" || hook.SecretHash != "" {
+		t.Fatalf("SaveWebhook failed: %+v %q (%v)", hook, secret, err)
+	}
+	hooks, err := mgr.ListWebhooks(cfg.ID)
+	if err != nil || len(hooks) != 1 || hooks[0].SecretHash != "" {
+		t.Fatalf("expected the secret to be hidden, got %+v (%v)", hooks, err)
+	}
+
+	values := map[string]string{"player": "Steve", "amount": "3"}
+	if _, err := mgr.ReceiveWebhook(cfg.ID, hook.ID, "wrong", values); !errors.Is(err, ErrWebhookUnauthorized) {
+		t.Fatalf("expected a wrong secret to be refused, got %v", err)
+	}
+	if _, err := mgr.ReceiveWebhook(cfg.ID, hook.ID, secret, values); !errors.Is(err, ErrWebhookServerOffline) {
+		t.Fatalf("expected a stopped server to be refused, got %v", err)
+	}
+
+	rs.mu.Lock()
+	rs.status = "Running"
+	rs.mu.Unlock()
+	if _, err := mgr.ReceiveWebhook(cfg.ID, hook.ID, secret, map[string]string{"player": "Steve op Alex", "amount": "1"}); err == nil {
+		t.Fatal("expected a value with spaces to be refused")
+	}
+	for _, selector := range []string{"@a", "@e[type=player]", "*", "St"} {
+		if _, err := mgr.ReceiveWebhook(cfg.ID, hook.ID, secret, map[string]string{"player": selector, "amount": "1"}); err == nil {
+			t.Fatalf("expected player %q to be refused", selector)
+		}
+	}
+	if _, err := mgr.ReceiveWebhook(cfg.ID, hook.ID, secret, map[string]string{"player": "Steve", "amount": "@p"}); err == nil {
+		t.Fatal("expected a selector in any value to be refused")
+	}
+	if _, err := mgr.ReceiveWebhook(cfg.ID, hook.ID, secret, map[string]string{"player": "Steve"}); err == nil || !strings.Contains(err.Error(), "amount") {
+		t.Fatalf("expected the missing amount to be reported, got %v", err)
+	}
+	if stdin.Len() != 0 {
+		t.Fatalf("expected nothing to run before a valid call, got %q", stdin.String())
+	}
+	ran, err := mgr.ReceiveWebhook(cfg.ID, hook.ID, secret, values)
+	if err != nil || len(ran) != 2 {
+		t.Fatalf("ReceiveWebhook failed: %v (%v)", ran, err)
+	}
+	if got := stdin.String(); got != "give Steve diamond 3\nsay Thanks Steve!\n" {
+		t.Fatalf("unexpected commands %q", got)
+	}
+	hooks, _ = mgr.ListWebhooks(cfg.ID)
+	if hooks[0].LastResult != "ran 2 commands" || hooks[0].LastCalledAt == "" {
+		t.Fatalf("expected the call to be recorded, got %+v", hooks[0])
+	}
+
+	if _, _, err := mgr.SaveWebhook(cfg.ID, hook.ID, InboundWebhook{Name: "Votes", Enabled: true, Commands: []string{"say {player}"}}, ""); err != nil {
+		t.Fatalf("updating the webhook failed: %v", err)
+	}
+	if _, err := mgr.ReceiveWebhook(cfg.ID, hook.ID, secret, values); err != nil {
+		t.Fatalf("expected an update to keep the secret: %v", err)
+	}
+}
@@ -0,0 +1,286 @@
+package minecraft
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Whitelist states the schedule applies.
+const (
+	WhitelistOn  = "on"
+	WhitelistOff = "off"
+)
+
+// maxWhitelistWindows caps how many public windows one server may have.
+const maxWhitelistWindows = 50
+
+var whitelistDays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// WhitelistWindow is a weekly period in the panel's local time during
+// which the server is open to everyone. An end at or before the start runs
+// past midnight into the next day.
+type WhitelistWindow struct {
+	Days  []string `json:"days"`
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+}
+
+// WhitelistSchedule turns the whitelist off during its windows and on
+// outside them. LastState is the state the panel last applied.
+type WhitelistSchedule struct {
+	Enabled   bool              `json:"enabled"`
+	Windows   []WhitelistWindow `json:"windows"`
+	LastState string            `json:"lastState,omitempty"`
+}
+
+// WhitelistScheduleInfo is a schedule with the state it wants now and when
+// that next changes.
+type WhitelistScheduleInfo struct {
+	WhitelistSchedule
+	Desired    string `json:"desired,omitempty"`
+	NextChange string `json:"nextChange,omitempty"`
+}
+
+func parseClock(s string) (int, int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q, use HH:MM", s)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+func validateWhitelistSchedule(s *WhitelistSchedule) error {
+	if len(s.Windows) > maxWhitelistWindows {
+		return fmt.Errorf("at most %d windows are allowed", maxWhitelistWindows)
+	}
+	if s.Enabled && len(s.Windows) == 0 {
+		return fmt.Errorf("add at least one window before enabling the schedule")
+	}
+	for i := range s.Windows {
+		w := &s.Windows[i]
+		if len(w.Days) == 0 {
+			return fmt.Errorf("window %d has no days", i+1)
+		}
+		for j, day := range w.Days {
+			day = strings.ToLower(strings.TrimSpace(day))
+			if _, ok := whitelistDays[day]; !ok {
+				return fmt.Errorf("invalid day %q, use mon, tue, wed, thu, fri, sat or sun", w.Days[j])
+			}
+			w.Days[j] = day
+		}
+		if _, _, err := parseClock(w.Start); err != nil {
+			return err
+		}
+		if _, _, err := parseClock(w.End); err != nil {
+			return err
+		}
+		if strings.TrimSpace(w.Start) == strings.TrimSpace(w.End) {
+			return fmt.Errorf("window %d starts and ends at the same time", i+1)
+		}
+	}
+	return nil
+}
+
+// occurrence returns the window's period that starts on day's date.
+func (w WhitelistWindow) occurrence(day time.Time) (time.Time, time.Time) {
+	sh, sm, _ := parseClock(w.Start)
+	eh, em, _ := parseClock(w.End)
+	start := time.Date(day.Year(), day.Month(), day.Day(), sh, sm, 0, 0, day.Location())
+	end := time.Date(day.Year(), day.Month(), day.Day(), eh, em, 0, 0, day.Location())
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end
+}
+
+func (w WhitelistWindow) runsOn(day time.Weekday) bool {
+	for _, d := range w.Days {
+		if whitelistDays[d] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// whitelistOpenAt reports whether t falls inside one of the windows.
+func whitelistOpenAt(windows []WhitelistWindow, t time.Time) bool {
+	for _, w := range windows {
+		// Yesterday's window may run past midnight into today.
+		for _, offset := range []int{0, -1} {
+			day := t.AddDate(0, 0, offset)
+			if !w.runsOn(day.Weekday()) {
+				continue
+			}
+			start, end := w.occurrence(day)
+			if !t.Before(start) && t.Before(end) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func desiredWhitelistState(windows []WhitelistWindow, t time.Time) string {
+	if whitelistOpenAt(windows, t) {
+		return WhitelistOff
+	}
+	return WhitelistOn
+}
+
+// nextWhitelistChange returns the next time after t at which the desired
+// state flips, or the zero time if it never does.
+func nextWhitelistChange(windows []WhitelistWindow, t time.Time) time.Time {
+	var boundaries []time.Time
+	for _, w := range windows {
+		for offset := -1; offset <= 7; offset++ {
+			day := t.AddDate(0, 0, offset)
+			if !w.runsOn(day.Weekday()) {
+				continue
+			}
+			start, end := w.occurrence(day)
+			for _, b := range []time.Time{start, end} {
+				if b.After(t) {
+					boundaries = append(boundaries, b)
+				}
+			}
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].Before(boundaries[j]) })
+	current := whitelistOpenAt(windows, t)
+	for _, b := range boundaries {
+		if whitelistOpenAt(windows, b) != current {
+			return b
+		}
+	}
+	return time.Time{}
+}
+
+func whitelistScheduleInfo(cfg *ServerConfig, now time.Time) *WhitelistScheduleInfo {
+	info := &WhitelistScheduleInfo{}
+	if cfg.WhitelistSchedule == nil {
+		info.Windows = []WhitelistWindow{}
+		return info
+	}
+	info.WhitelistSchedule = *cfg.WhitelistSchedule
+	if info.Enabled {
+		info.Desired = desiredWhitelistState(info.Windows, now)
+		if next := nextWhitelistChange(info.Windows, now); !next.IsZero() {
+			info.NextChange = next.UTC().Format(time.RFC3339)
+		}
+	}
+	return info
+}
+
+// GetWhitelistSchedule returns a server's whitelist schedule.
+func (m *Manager) GetWhitelistSchedule(id string) (*WhitelistScheduleInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	return whitelistScheduleInfo(cfg, time.Now()), nil
+}
+
+// SetWhitelistSchedule saves a server's whitelist schedule and applies it
+// right away.
+func (m *Manager) SetWhitelistSchedule(id string, s WhitelistSchedule) (*WhitelistScheduleInfo, error) {
+	if err := validateWhitelistSchedule(&s); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	if isProxyType(cfg.Type) {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("proxy servers have no whitelist")
+	}
+	s.LastState = ""
+	if cfg.WhitelistSchedule != nil {
+		s.LastState = cfg.WhitelistSchedule.LastState
+	}
+	cfg.WhitelistSchedule = &s
+	if err := m.persist(); err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	m.mu.Unlock()
+
+	m.checkWhitelistSchedules()
+	return m.GetWhitelistSchedule(id)
+}
+
+// checkWhitelistSchedules turns whitelists on or off where a schedule
+// wants a different state than the one last applied. Running servers get
+// a whitelist command; stopped ones have server.properties edited.
+func (m *Manager) checkWhitelistSchedules() {
+	type pending struct {
+		cfg     ServerConfig
+		rs      *runningServer
+		desired string
+	}
+	now := time.Now()
+	var due []pending
+	m.mu.RLock()
+	for id, cfg := range m.configs {
+		s := cfg.WhitelistSchedule
+		if s == nil || !s.Enabled || isProxyType(cfg.Type) {
+			continue
+		}
+		if desired := desiredWhitelistState(s.Windows, now); desired != s.LastState {
+			due = append(due, pending{cfg: *cfg, rs: m.running[id], desired: desired})
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, p := range due {
+		if err := m.applyWhitelistState(&p.cfg, p.rs, p.desired); err != nil {
+			log.Printf("[%s] Scheduled whitelist %s failed: %v", p.cfg.Name, p.desired, err)
+			continue
+		}
+		log.Printf("[%s] Whitelist turned %s by schedule", p.cfg.Name, p.desired)
+		m.mu.Lock()
+		if cfg, ok := m.configs[p.cfg.ID]; ok && cfg.WhitelistSchedule != nil {
+			cfg.WhitelistSchedule.LastState = p.desired
+			m.persist()
+		}
+		m.mu.Unlock()
+	}
+}
+
+func (m *Manager) applyWhitelistState(cfg *ServerConfig, rs *runningServer, state string) error {
+	status := "Stopped"
+	if rs != nil {
+		status = rs.currentRuntime().status
+	}
+	switch status {
+	case "Running":
+		if err := m.SendCommand(cfg.ID, "whitelist "+state); err != nil {
+			return err
+		}
+		m.broadcastLog(rs, m.appendLog(rs, fmt.Sprintf("[Whitelist] Turned %s by schedule", state)))
+		return nil
+	case "Stopped", "Crashed", "Error":
+		if err := m.validateManagedServerDir(cfg.Dir); err != nil {
+			return err
+		}
+		value := "false"
+		if state == WhitelistOn {
+			value = "true"
+		}
+		return setServerProperties(fmt.Sprintf("%s/server.properties", cfg.Dir), map[string]string{"white-list": value})
+	default:
+		// Booting or busy: try again on the next check.
+		return fmt.Errorf("server is %s", strings.ToLower(status))
+	}
+}
@@ -0,0 +1,104 @@
+package minecraft
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWhitelistScheduleWindows(t *testing.T) {
+	windows := []WhitelistWindow{
+		{Days: []string{"sat"}, Start: "18:00", End: "23:00"},
+		{Days: []string{"fri"}, Start: "22:00", End: "02:00"},
+	}
+	// 2024-05-31 is a Friday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 5, day, hour, minute, 0, 0, time.Local)
+	}
+	cases := []struct {
+		t    time.Time
+		want string
+	}{
+		{at(31, 21, 59), WhitelistOn},
+		{at(31, 23, 0), WhitelistOff},
+		{at(32, 1, 30), WhitelistOff}, // Friday's window runs past midnight.
+		{at(32, 2, 0), WhitelistOn},
+		{at(32, 17, 59), WhitelistOn},
+		{at(32, 18, 0), WhitelistOff},
+		{at(32, 22, 59), WhitelistOff},
+		{at(32, 23, 0), WhitelistOn},
+		{at(33, 19, 0), WhitelistOn},
+	}
+	for _, c := range cases {
+		if got := desiredWhitelistState(windows, c.t); got != c.want {
+			t.Fatalf("desiredWhitelistState(%s) = %s, want %s", c.t, got, c.want)
+		}
+	}
+
+	if next := nextWhitelistChange(windows, at(32, 12, 0)); !next.Equal(at(32, 18, 0)) {
+		t.Fatalf("expected the next change at Saturday 18:00, got %s", next)
+	}
+	if next := nextWhitelistChange(windows, at(32, 20, 0)); !next.Equal(at(32, 23, 0)) {
+		t.Fatalf("expected the next change at Saturday 23:00, got %s", next)
+	}
+}
+
+func TestValidateWhitelistSchedule(t *testing.T) {
+	bad := []WhitelistSchedule{
+		{Enabled: true},
+		{Windows: []WhitelistWindow{{Start: "18:00", End: "23:00"}}},
+		{Windows: []WhitelistWindow{{Days: []string{"caturday"}, Start: "18:00", End: "23:00"}}},
+		{Windows: []WhitelistWindow{{Days: []string{"sat"}, Start: "25:00", End: "23:00"}}},
+		{Windows: []WhitelistWindow{{Days: []string{"sat"}, Start: "18:00", End: "18:00"}}},
+	}
+	for _, s := range bad {
+		if err := validateWhitelistSchedule(&s); err == nil {
+			t.Fatalf("expected %+v to be rejected", s)
+		}
+	}
+	ok := WhitelistSchedule{Enabled: true, Windows: []WhitelistWindow{{Days: []string{" SAT "}, Start: "18:00", End: "23:00"}}}
+	if err := validateWhitelistSchedule(&ok); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok.Windows[0].Days[0] != "sat" {
+		t.Fatalf("expected days to be normalized, got %q", ok.Windows[0].Days[0])
+	}
+}
+
+func TestWhitelistScheduleAppliesToStoppedServer(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		t.Fatalf("failed to create server dir: %v", err)
+	}
+	propsPath := filepath.Join(cfg.Dir, "server.properties")
+	if err := os.WriteFile(propsPath, []byte("white-list=false\nmotd=Hi\n"), 0644); err != nil {
+		t.Fatalf("failed to write server.properties: %v", err)
+	}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = &runningServer{status: "Stopped"}
+	mgr.mu.Unlock()
+
+	// A window three days out is closed now, so the whitelist goes on.
+	day := strings.ToLower(time.Now().AddDate(0, 0, 3).Weekday().String()[:3])
+	schedule := WhitelistSchedule{Enabled: true, Windows: []WhitelistWindow{{Days: []string{day}, Start: "00:00", End: "23:59"}}}
+	info, err := mgr.SetWhitelistSchedule(cfg.ID, schedule)
+	if err != nil {
+		t.Fatalf("SetWhitelistSchedule failed: %v", err)
+	}
+	if info.Desired != WhitelistOn || info.LastState != WhitelistOn || info.NextChange == "" {
+		t.Fatalf("unexpected schedule info: %+v", info)
+	}
+	props := parseServerPropertiesFile(propsPath)
+	if props["white-list"] != "true" || props["motd"] != "Hi" {
+		t.Fatalf("expected the whitelist to be turned on, got %v", props)
+	}
+}
@@ -0,0 +1,197 @@
+package minecraft
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// worldUpgradeStopTimeout bounds how long the server may take to shut down
+// after the upgrade before it is killed.
+const worldUpgradeStopTimeout = 2 * time.Minute
+
+// worldUpgradeProgressPattern matches the progress line vanilla logs once a
+// second while --forceUpgrade runs, e.g. "42% completed (420 / 1000 chunks)...".
+var worldUpgradeProgressPattern = regexp.MustCompile(`(\d+)% completed \((\d+) / (\d+) chunks\)`)
+
+// WorldUpgradeOptions configures a forced world upgrade.
+type WorldUpgradeOptions struct {
+	// EraseCache also drops cached data such as lighting, which the server
+	// then recomputes.
+	EraseCache bool `json:"eraseCache"`
+}
+
+// parseWorldUpgradeProgress returns the percentage from a --forceUpgrade
+// progress line.
+func parseWorldUpgradeProgress(line string) (int, bool) {
+	match := worldUpgradeProgressPattern.FindStringSubmatch(line)
+	if match == nil {
+		return 0, false
+	}
+	pct, err := strconv.Atoi(match[1])
+	if err != nil || pct < 0 || pct > 100 {
+		return 0, false
+	}
+	return pct, true
+}
+
+// StartWorldUpgrade queues a job that backs up a stopped server and runs it
+// once with --forceUpgrade, so the world is converted up front instead of
+// chunk by chunk on the first boot after a version bump. It returns the job.
+func (m *Manager) StartWorldUpgrade(id string, opts WorldUpgradeOptions) (*Job, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	rs, rsOk := m.running[id]
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if !rsOk {
+		return nil, errServerNotFound(id)
+	}
+	if isProxyType(cfg.Type) {
+		return nil, fmt.Errorf("proxy servers have no worlds to upgrade")
+	}
+	if err := m.validateManagedServerDir(cfg.Dir); err != nil {
+		return nil, m.configPathErrorLocked(id, err.Error())
+	}
+
+	rs.mu.Lock()
+	if rs.status != "Stopped" && rs.status != "Crashed" && rs.status != "Error" {
+		status := rs.status
+		rs.mu.Unlock()
+		return nil, fmt.Errorf("server must be stopped before upgrading its world (status: %s)", status)
+	}
+	// Installing keeps the server from being started or reinstalled while
+	// the upgrade runs.
+	rs.status = "Installing"
+	rs.installError = ""
+	rs.mu.Unlock()
+
+	job := m.newJob(JobTypeWorldUpgrade, id)
+	go func() {
+		err := m.worldUpgradeJob(job, cfg, rs, opts)
+		rs.mu.Lock()
+		rs.status = "Stopped"
+		rs.mu.Unlock()
+		if err != nil {
+			log.Printf("[%s] World upgrade failed: %v", cfg.Name, err)
+		}
+		job.finish(err)
+	}()
+	return m.GetJob(job.id)
+}
+
+func (m *Manager) worldUpgradeJob(job *jobHandle, cfg *ServerConfig, rs *runningServer, opts WorldUpgradeOptions) error {
+	release, err := m.acquireServerOperation(job.ctx, cfg.ID, operationUpgrade)
+	if err != nil {
+		return err
+	}
+	defer release()
+	job.start("Backing up before the world upgrade")
+
+	say := func(msg string) {
+		m.broadcastLog(rs, m.appendLog(rs, "[World Upgrade] "+msg))
+	}
+
+	backup, err := m.writeBackupArchive(job, cfg, 0, 5)
+	if err != nil {
+		return fmt.Errorf("pre-upgrade backup failed: %w", err)
+	}
+	say(fmt.Sprintf("Backed up to %s", backup.Name))
+	job.progress(5, "Starting the server with --forceUpgrade")
+
+	args := []string{"--forceUpgrade"}
+	if opts.EraseCache {
+		args = append(args, "--eraseCache")
+	}
+	cmd, err := m.serverLaunchCommand(cfg, args...)
+	if err != nil {
+		return err
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start server: %w", err)
+	}
+	pid := cmd.Process.Pid
+	log.Printf("[%s] World upgrade started (PID: %d)", cfg.Name, pid)
+	say("Started the server with --forceUpgrade")
+
+	lines := make(chan string)
+	output := lines
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	upgraded := false
+	stopping := false
+	var stopDeadline <-chan time.Time
+	for lines != nil {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				lines = nil
+				continue
+			}
+			if pct, ok := parseWorldUpgradeProgress(line); ok {
+				// Map the upgrade onto 10-95% so the backup and shutdown show too.
+				job.progress(10+pct*85/100, fmt.Sprintf("Upgrading chunks: %d%%", pct))
+				continue
+			}
+			job.log(line)
+			say(line)
+			if !stopping && strings.Contains(line, "Done (") {
+				// The upgrade is finished once the server reaches the normal
+				// "Done" line; shut it down cleanly so the world is saved.
+				upgraded = true
+				stopping = true
+				job.progress(95, "Upgrade finished, stopping the server")
+				io.WriteString(stdin, "stop\n")
+				stopDeadline = time.After(worldUpgradeStopTimeout)
+			}
+		case <-job.ctx.Done():
+			killServerProcessTree(pid)
+			lines = nil
+		case <-stopDeadline:
+			log.Printf("[%s] Server did not stop after the world upgrade, killing it", cfg.Name)
+			killServerProcessTree(pid)
+			lines = nil
+		}
+	}
+	// Drain what is left so the reader finishes before Wait closes the pipe.
+	for range output {
+	}
+	waitErr := cmd.Wait()
+
+	if err := job.ctx.Err(); err != nil {
+		say("World upgrade cancelled")
+		return err
+	}
+	if !upgraded {
+		if waitErr != nil {
+			return fmt.Errorf("server exited before the upgrade finished: %w", waitErr)
+		}
+		return fmt.Errorf("server exited before the upgrade finished")
+	}
+	say("World upgrade complete")
+	log.Printf("[%s] World upgrade complete", cfg.Name)
+	return nil
+}
@@ -0,0 +1,22 @@
+package minecraft
+
+import "testing"
+
+func TestParseWorldUpgradeProgress(t *testing.T) {
+	cases := []struct {
+		line string
+		pct  int
+		ok   bool
+	}{
+		{"[12:00:01] [Server thread/INFO]: 42% completed (420 / 1000 chunks)...", 42, true},
+		{"[12:00:09] [Server thread/INFO]: 100% completed (1000 / 1000 chunks)...", 100, true},
+		{"[12:00:00] [Server thread/INFO]: Forcing world upgrade!", 0, false},
+		{"[12:00:10] [Server thread/INFO]: Done (12.345s)! For help, type \"help\"", 0, false},
+	}
+	for _, tc := range cases {
+		pct, ok := parseWorldUpgradeProgress(tc.line)
+		if pct != tc.pct || ok != tc.ok {
+			t.Fatalf("parseWorldUpgradeProgress(%q) = %d, %v; want %d, %v", tc.line, pct, ok, tc.pct, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,460 @@
+package minecraft
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	WorldDimensionNether = "nether"
+	WorldDimensionEnd    = "end"
+)
+
+var worldNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 _.-]{0,63}$`)
+
+// WorldInfo is a world folder in a server directory. On Bukkit-based
+// servers the nether and end live in their own <name>_nether and
+// <name>_the_end folders, which are listed with the world they belong to.
+type WorldInfo struct {
+	Name       string   `json:"name"`
+	Folders    []string `json:"folders"`
+	Size       string   `json:"size"`
+	SizeBytes  int64    `json:"sizeBytes"`
+	Seed       string   `json:"seed,omitempty"` // a string, as seeds overflow JavaScript numbers
+	Version    string   `json:"version,omitempty"`
+	LastPlayed string   `json:"lastPlayed,omitempty"`
+	Active     bool     `json:"active"`
+	HasNether  bool     `json:"hasNether"`
+	HasEnd     bool     `json:"hasEnd"`
+}
+
+// WorldResetResult reports a nether/end reset and the backup taken first.
+type WorldResetResult struct {
+	Removed []string    `json:"removed"`
+	Backup  *BackupInfo `json:"backup,omitempty"`
+}
+
+func validateWorldName(name string) error {
+	if !worldNamePattern.MatchString(name) || strings.HasSuffix(name, ".") {
+		return fmt.Errorf("invalid world name %q: use up to 64 letters, digits, spaces, dots, dashes or underscores", name)
+	}
+	return nil
+}
+
+// worldDimensionPaths returns the folders, relative to the server
+// directory, that may hold a world's nether or end: the vanilla DIM-1/DIM1
+// subfolder and the Bukkit sibling folder.
+func worldDimensionPaths(name, dimension string) []string {
+	if dimension == WorldDimensionNether {
+		return []string{filepath.Join(name, "DIM-1"), name + "_nether"}
+	}
+	return []string{filepath.Join(name, "DIM1"), name + "_the_end"}
+}
+
+func isWorldDir(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "level.dat"))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// worldCompanionOf returns the world a Bukkit dimension folder belongs to.
+func worldCompanionOf(name string) (string, bool) {
+	for _, suffix := range []string{"_nether", "_the_end"} {
+		if base, ok := strings.CutSuffix(name, suffix); ok && base != "" {
+			return base, true
+		}
+	}
+	return "", false
+}
+
+// ListWorlds lists the world folders in a server's directory, the active
+// one first.
+func (m *Manager) ListWorlds(id string) ([]WorldInfo, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if isProxyType(cfg.Type) {
+		return nil, fmt.Errorf("proxy servers have no worlds")
+	}
+	return listWorlds(cfg)
+}
+
+func listWorlds(cfg *ServerConfig) ([]WorldInfo, error) {
+	entries, err := os.ReadDir(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() && isWorldDir(filepath.Join(cfg.Dir, entry.Name())) {
+			names[entry.Name()] = true
+		}
+	}
+
+	active := serverLevelName(cfg)
+	worlds := make([]WorldInfo, 0, len(names))
+	for name := range names {
+		if base, ok := worldCompanionOf(name); ok && names[base] {
+			continue
+		}
+		worlds = append(worlds, worldInfo(cfg, name, active))
+	}
+	sort.Slice(worlds, func(i, j int) bool {
+		if worlds[i].Active != worlds[j].Active {
+			return worlds[i].Active
+		}
+		return strings.ToLower(worlds[i].Name) < strings.ToLower(worlds[j].Name)
+	})
+	return worlds, nil
+}
+
+func worldInfo(cfg *ServerConfig, name, active string) WorldInfo {
+	world := WorldInfo{Name: name, Folders: []string{name}, Active: name == active}
+	for _, companion := range []string{name + "_nether", name + "_the_end"} {
+		if info, err := os.Stat(filepath.Join(cfg.Dir, companion)); err == nil && info.IsDir() {
+			world.Folders = append(world.Folders, companion)
+		}
+	}
+	for _, folder := range world.Folders {
+		if size, err := backupSourceSize(context.Background(), filepath.Join(cfg.Dir, folder)); err == nil {
+			world.SizeBytes += size
+		}
+	}
+	world.Size = formatFileSize(world.SizeBytes)
+	world.HasNether = worldDimensionExists(cfg, name, WorldDimensionNether)
+	world.HasEnd = worldDimensionExists(cfg, name, WorldDimensionEnd)
+	readLevelDatSummary(filepath.Join(cfg.Dir, name, "level.dat"), &world)
+	return world
+}
+
+func worldDimensionExists(cfg *ServerConfig, name, dimension string) bool {
+	for _, rel := range worldDimensionPaths(name, dimension) {
+		if info, err := os.Stat(filepath.Join(cfg.Dir, rel)); err == nil && info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+// readLevelDatSummary fills in the seed, game version and last played time
+// from level.dat. Seeds moved into WorldGenSettings in 1.16; older worlds
+// keep them in RandomSeed.
+func readLevelDatSummary(path string, world *WorldInfo) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	root, err := readNBT(data)
+	if err != nil {
+		return
+	}
+	level, ok := root["Data"].(map[string]any)
+	if !ok {
+		return
+	}
+	if settings, ok := level["WorldGenSettings"].(map[string]any); ok {
+		if seed, ok := nbtIntField(settings, "seed"); ok {
+			world.Seed = strconv.FormatInt(seed, 10)
+		}
+	}
+	if world.Seed == "" {
+		if seed, ok := nbtIntField(level, "RandomSeed"); ok {
+			world.Seed = strconv.FormatInt(seed, 10)
+		}
+	}
+	if version, ok := level["Version"].(map[string]any); ok {
+		world.Version, _ = version["Name"].(string)
+	}
+	if lastPlayed, ok := nbtIntField(level, "LastPlayed"); ok && lastPlayed > 0 {
+		world.LastPlayed = time.UnixMilli(lastPlayed).UTC().Format(time.RFC3339)
+	}
+}
+
+// stoppedWorldServer returns the server for a world change that needs it
+// stopped, so the game does not write to the folders being changed.
+func (m *Manager) stoppedWorldServer(id, action string) (*ServerConfig, *runningServer, error) {
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	rs, rsOk := m.running[id]
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !rsOk {
+		return nil, nil, errServerNotFound(id)
+	}
+	if isProxyType(cfg.Type) {
+		return nil, nil, fmt.Errorf("proxy servers have no worlds")
+	}
+	if err := m.validateManagedServerDir(cfg.Dir); err != nil {
+		return nil, nil, m.configPathErrorLocked(id, err.Error())
+	}
+	if err := worldServerStopped(rs, action); err != nil {
+		return nil, nil, err
+	}
+	return cfg, rs, nil
+}
+
+func worldServerStopped(rs *runningServer, action string) error {
+	rs.mu.RLock()
+	status := rs.status
+	rs.mu.RUnlock()
+	if status != "Stopped" && status != "Crashed" && status != "Error" {
+		return fmt.Errorf("server must be stopped before %s (status: %s)", action, status)
+	}
+	return nil
+}
+
+// SwitchWorld points level-name in server.properties at another world. A
+// name with no folder yet makes the server generate a new world on its
+// next start.
+func (m *Manager) SwitchWorld(id, name string) ([]WorldInfo, error) {
+	name = strings.TrimSpace(name)
+	if err := validateWorldName(name); err != nil {
+		return nil, err
+	}
+	cfg, _, err := m.stoppedWorldServer(id, "switching worlds")
+	if err != nil {
+		return nil, err
+	}
+	dir, err := SafePath(cfg.Dir, name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dir); err == nil && !isWorldDir(dir) {
+		return nil, fmt.Errorf("%s is not a world folder", name)
+	}
+	if err := setServerProperties(filepath.Join(cfg.Dir, "server.properties"), map[string]string{"level-name": name}); err != nil {
+		return nil, fmt.Errorf("failed to update server.properties: %w", err)
+	}
+	log.Printf("[%s] Switched world to %s", cfg.Name, name)
+	return listWorlds(cfg)
+}
+
+// ResetWorldDimensions backs up a stopped server and deletes a world's
+// nether and/or end, which the game regenerates on its next start.
+func (m *Manager) ResetWorldDimensions(id, name string, dimensions []string) (*WorldResetResult, error) {
+	if len(dimensions) == 0 {
+		return nil, fmt.Errorf("choose at least one dimension to reset")
+	}
+	for _, dimension := range dimensions {
+		if dimension != WorldDimensionNether && dimension != WorldDimensionEnd {
+			return nil, fmt.Errorf("invalid dimension %q: use nether or end", dimension)
+		}
+	}
+	cfg, rs, err := m.existingWorld(id, name, "resetting dimensions")
+	if err != nil {
+		return nil, err
+	}
+
+	job := m.newJob(JobTypeWorldReset, id)
+	result, err := m.resetWorldDimensionsJob(job, cfg, rs, name, dimensions)
+	job.finish(err)
+	return result, err
+}
+
+func (m *Manager) resetWorldDimensionsJob(job *jobHandle, cfg *ServerConfig, rs *runningServer, name string, dimensions []string) (*WorldResetResult, error) {
+	release, err := m.acquireServerOperation(job.ctx, cfg.ID, operationWorld)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	job.start("Backing up before the reset")
+	if err := worldServerStopped(rs, "resetting dimensions"); err != nil {
+		return nil, err
+	}
+
+	var targets []string
+	for _, dimension := range dimensions {
+		for _, rel := range worldDimensionPaths(name, dimension) {
+			if info, err := os.Stat(filepath.Join(cfg.Dir, rel)); err == nil && info.IsDir() {
+				targets = append(targets, rel)
+			}
+		}
+	}
+	result := &WorldResetResult{Removed: []string{}}
+	if len(targets) == 0 {
+		job.log("Nothing to reset; the dimensions have not been generated")
+		return result, nil
+	}
+
+	backup, err := m.writeBackupArchive(job, cfg, 0, 90)
+	if err != nil {
+		return nil, fmt.Errorf("pre-reset backup failed: %w", err)
+	}
+	result.Backup = backup
+	job.progress(90, "Deleting dimension folders")
+	for _, rel := range targets {
+		if err := os.RemoveAll(filepath.Join(cfg.Dir, rel)); err != nil {
+			return nil, fmt.Errorf("failed to delete %s: %w", filepath.ToSlash(rel), err)
+		}
+		result.Removed = append(result.Removed, filepath.ToSlash(rel))
+	}
+	log.Printf("[%s] Reset %s of world %s; backup %s", cfg.Name, strings.Join(dimensions, " and "), name, backup.Name)
+	return result, nil
+}
+
+// DeleteWorld backs up a stopped server and deletes a world that is not
+// the active one, with its Bukkit nether and end folders.
+func (m *Manager) DeleteWorld(id, name string) (*BackupInfo, error) {
+	cfg, rs, err := m.existingWorld(id, name, "deleting a world")
+	if err != nil {
+		return nil, err
+	}
+	if name == serverLevelName(cfg) {
+		return nil, fmt.Errorf("%s is the active world; switch to another world before deleting it", name)
+	}
+
+	job := m.newJob(JobTypeWorldDelete, id)
+	backup, err := m.deleteWorldJob(job, cfg, rs, name)
+	job.finish(err)
+	return backup, err
+}
+
+func (m *Manager) deleteWorldJob(job *jobHandle, cfg *ServerConfig, rs *runningServer, name string) (*BackupInfo, error) {
+	release, err := m.acquireServerOperation(job.ctx, cfg.ID, operationWorld)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	job.start("Backing up before deleting the world")
+	if err := worldServerStopped(rs, "deleting a world"); err != nil {
+		return nil, err
+	}
+
+	backup, err := m.writeBackupArchive(job, cfg, 0, 90)
+	if err != nil {
+		return nil, fmt.Errorf("pre-delete backup failed: %w", err)
+	}
+	job.progress(90, "Deleting world folders")
+	for _, folder := range worldInfo(cfg, name, "").Folders {
+		if err := os.RemoveAll(filepath.Join(cfg.Dir, folder)); err != nil {
+			return nil, fmt.Errorf("failed to delete %s: %w", folder, err)
+		}
+	}
+	log.Printf("[%s] Deleted world %s; backup %s", cfg.Name, name, backup.Name)
+	return backup, nil
+}
+
+// existingWorld checks that name is a world folder of a stopped server.
+func (m *Manager) existingWorld(id, name, action string) (*ServerConfig, *runningServer, error) {
+	if err := validateWorldName(name); err != nil {
+		return nil, nil, err
+	}
+	cfg, rs, err := m.stoppedWorldServer(id, action)
+	if err != nil {
+		return nil, nil, err
+	}
+	dir, err := SafePath(cfg.Dir, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isWorldDir(dir) {
+		return nil, nil, fmt.Errorf("world %s not found", name)
+	}
+	return cfg, rs, nil
+}
+
+// UploadWorld installs a world from a .zip as a new folder. The archive may
+// hold the world at its root or in a subfolder; the shallowest folder with
+// a level.dat is used. session.lock and Bukkit's uid.dat are dropped so the
+// copy is not mistaken for a world that is already loaded. The server may
+// be running, since the new folder is not in use until it is switched to.
+func (m *Manager) UploadWorld(id, name, archivePath string) (*WorldInfo, error) {
+	name = strings.TrimSpace(name)
+	if err := validateWorldName(name); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	cfg, err := m.serverConfigForOperationLocked(id)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if isProxyType(cfg.Type) {
+		return nil, fmt.Errorf("proxy servers have no worlds")
+	}
+	if err := m.validateManagedServerDir(cfg.Dir); err != nil {
+		return nil, m.configPathErrorLocked(id, err.Error())
+	}
+	target, err := SafePath(cfg.Dir, name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(target); err == nil {
+		return nil, fmt.Errorf("%s already exists", name)
+	}
+
+	job := m.newJob(JobTypeWorldUpload, id)
+	world, err := m.uploadWorldJob(job, cfg, name, target, archivePath)
+	job.finish(err)
+	return world, err
+}
+
+// uploadWorldJob holds the server's operation lock, so a restore cannot
+// remove the folder being extracted and a backup does not archive it.
+func (m *Manager) uploadWorldJob(job *jobHandle, cfg *ServerConfig, name, target, archivePath string) (*WorldInfo, error) {
+	release, err := m.acquireServerOperation(job.ctx, cfg.ID, operationWorld)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	job.start(fmt.Sprintf("Extracting world %s", name))
+
+	// Extract next to the server so the final move is a rename.
+	extractDir, err := os.MkdirTemp(cfg.Dir, ".world-upload-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(extractDir)
+	if err := extractZipArchive(archivePath, extractDir); err != nil {
+		return nil, fmt.Errorf("failed to extract world archive: %w", err)
+	}
+	root, err := findUploadedWorldRoot(extractDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, stale := range []string{"session.lock", "uid.dat"} {
+		_ = os.Remove(filepath.Join(root, stale))
+	}
+	if _, err := os.Stat(target); err == nil {
+		return nil, fmt.Errorf("%s already exists", name)
+	}
+	if err := os.Rename(root, target); err != nil {
+		return nil, err
+	}
+	job.log(fmt.Sprintf("Installed world %s", name))
+	log.Printf("[%s] Uploaded world %s", cfg.Name, name)
+	world := worldInfo(cfg, name, serverLevelName(cfg))
+	job.setResult(&world)
+	return &world, nil
+}
+
+func findUploadedWorldRoot(extractDir string) (string, error) {
+	if isWorldDir(extractDir) {
+		return extractDir, nil
+	}
+	var best string
+	for _, rel := range detectWorldDirectories(extractDir) {
+		if strings.HasPrefix(rel, "__MACOSX") {
+			continue
+		}
+		if best == "" || strings.Count(rel, "/") < strings.Count(best, "/") {
+			best = rel
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("archive does not contain a world (no level.dat found)")
+	}
+	return filepath.Join(extractDir, filepath.FromSlash(best)), nil
+}
@@ -0,0 +1,142 @@
+package minecraft
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestLevelDat(t *testing.T, dir string, seed int64) {
+	t.Helper()
+	var w nbtWriter
+	w.name(nbtCompound, "")
+	w.name(nbtCompound, "Data")
+	w.name(nbtCompound, "WorldGenSettings")
+	w.name(nbtLong, "seed")
+	binary.Write(&w, binary.BigEndian, seed)
+	w.WriteByte(nbtEnd)
+	w.name(nbtCompound, "Version")
+	w.str("Name", "1.21.1")
+	w.WriteByte(nbtEnd)
+	w.WriteByte(nbtEnd)
+	w.WriteByte(nbtEnd)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create world dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "level.dat"), w.Bytes(), 0644); err != nil {
+		t.Fatalf("failed to write level.dat: %v", err)
+	}
+}
+
+func TestWorldManagement(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.StopAll()
+
+	cfg := &ServerConfig{ID: "srv1", Name: "Survival", Type: "Paper", Dir: filepath.Join(mgr.serversRoot, "Survival")}
+	rs := &runningServer{status: "Running"}
+	mgr.mu.Lock()
+	mgr.configs[cfg.ID] = cfg
+	mgr.running[cfg.ID] = rs
+	mgr.mu.Unlock()
+
+	writeTestLevelDat(t, filepath.Join(cfg.Dir, "world"), -4172144997902289642)
+	writeTestLevelDat(t, filepath.Join(cfg.Dir, "world_nether"), -4172144997902289642)
+	if err := os.MkdirAll(filepath.Join(cfg.Dir, "world_nether", "DIM-1", "region"), 0755); err != nil {
+		t.Fatalf("failed to create nether: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Dir, "server.properties"), []byte("level-name=world\n"), 0644); err != nil {
+		t.Fatalf("failed to write server.properties: %v", err)
+	}
+
+	worlds, err := mgr.ListWorlds(cfg.ID)
+	if err != nil || len(worlds) != 1 {
+		t.Fatalf("expected one world, got %+v (%v)", worlds, err)
+	}
+	if w := worlds[0]; !w.Active || w.Seed != "-4172144997902289642" || w.Version != "1.21.1" || len(w.Folders) != 2 || !w.HasNether || w.HasEnd {
+		t.Fatalf("unexpected world %+v", w)
+	}
+
+	archive := filepath.Join(t.TempDir(), "Creative.zip")
+	var level nbtWriter
+	level.name(nbtCompound, "")
+	level.name(nbtCompound, "Data")
+	level.WriteByte(nbtEnd)
+	level.WriteByte(nbtEnd)
+	writeTestJar(t, archive, map[string]string{"Creative/level.dat": level.String(), "Creative/session.lock": "x", "Creative/region/r.0.0.mca": "data"})
+	uploaded, err := mgr.UploadWorld(cfg.ID, "Creative", archive)
+	if err != nil || uploaded.Name != "Creative" || uploaded.Active {
+		t.Fatalf("UploadWorld failed: %+v (%v)", uploaded, err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Dir, "Creative", "session.lock")); !os.IsNotExist(err) {
+		t.Fatalf("expected session.lock to be dropped, got %v", err)
+	}
+	if _, err := mgr.UploadWorld(cfg.ID, "Creative", archive); err == nil {
+		t.Fatal("expected an upload over an existing world to be refused")
+	}
+
+	// An upload waits for a running restore instead of extracting into the
+	// folder the restore is replacing.
+	release, err := mgr.acquireServerOperation(context.Background(), cfg.ID, operationRestore)
+	if err != nil {
+		t.Fatalf("acquireServerOperation failed: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := mgr.UploadWorld(cfg.ID, "Skyblock", archive)
+		done <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	if matches, _ := filepath.Glob(filepath.Join(cfg.Dir, ".world-upload-*")); len(matches) != 0 {
+		t.Fatalf("expected the upload to wait for the restore, found %v", matches)
+	}
+	release()
+	if err := <-done; err != nil {
+		t.Fatalf("expected the upload to finish after the restore: %v", err)
+	}
+	if err := os.RemoveAll(filepath.Join(cfg.Dir, "Skyblock")); err != nil {
+		t.Fatalf("failed to remove Skyblock: %v", err)
+	}
+	if _, err := mgr.UploadWorld(cfg.ID, "../escape", archive); err == nil {
+		t.Fatal("expected an invalid world name to be refused")
+	}
+
+	if _, err := mgr.SwitchWorld(cfg.ID, "Creative"); err == nil {
+		t.Fatal("expected switching worlds on a running server to be refused")
+	}
+	rs.mu.Lock()
+	rs.status = "Stopped"
+	rs.mu.Unlock()
+	if _, err := mgr.SwitchWorld(cfg.ID, "Hardcore"); err != nil {
+		t.Fatalf("expected switching to a new world name to work: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(cfg.Dir, "logs"), 0755); err != nil {
+		t.Fatalf("failed to create logs: %v", err)
+	}
+	if _, err := mgr.SwitchWorld(cfg.ID, "logs"); err == nil {
+		t.Fatal("expected switching to a non-world folder to be refused")
+	}
+	worlds, err = mgr.SwitchWorld(cfg.ID, "Creative")
+	if err != nil || len(worlds) != 2 || worlds[0].Name != "Creative" || !worlds[0].Active {
+		t.Fatalf("expected Creative to be active, got %+v (%v)", worlds, err)
+	}
+
+	reset, err := mgr.ResetWorldDimensions(cfg.ID, "world", []string{WorldDimensionNether})
+	if err != nil || len(reset.Removed) != 1 || reset.Removed[0] != "world_nether" || reset.Backup == nil {
+		t.Fatalf("unexpected reset result %+v (%v)", reset, err)
+	}
+	if _, err := mgr.DeleteWorld(cfg.ID, "Creative"); err == nil {
+		t.Fatal("expected deleting the active world to be refused")
+	}
+	if _, err := mgr.DeleteWorld(cfg.ID, "world"); err != nil {
+		t.Fatalf("DeleteWorld failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Dir, "world")); !os.IsNotExist(err) {
+		t.Fatalf("expected the world folder to be gone, got %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import { Loader2, Plus, Trash2 } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../lib/api';
+
+interface BackupTarget {
+  id: string;
+  name: string;
+  type: 's3' | 'sftp';
+  path?: string;
+  endpoint?: string;
+  region?: string;
+  bucket?: string;
+  accessKey?: string;
+  secretKey?: string;
+  pathStyle?: boolean;
+  host?: string;
+  port?: number;
+  user?: string;
+  password?: string;
+  privateKey?: string;
+  hostKey?: string;
+  hasSecret: boolean;
+}
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-3 py-2 text-sm text-white focus:outline-none focus:border-[#E5B80B]';
+
+// Remote places full backups are copied to. Secrets are write-only: the API
+// never returns them, and leaving a secret field empty keeps the stored one.
+export const BackupTargetsPanel = () => {
+  const [targets, setTargets] = useState<BackupTarget[]>([]);
+  const [loading, setLoading] = useState(false);
+  const [saving, setSaving] = useState(false);
+  const [testing, setTesting] = useState<string | null>(null);
+
+  const load = useCallback(async () => {
+    setLoading(true);
+    try {
+      setTargets(await apiRequest<BackupTarget[]>('/api/backup-targets', undefined, 'Failed to load backup targets'));
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to load backup targets'));
+    } finally {
+      setLoading(false);
+    }
+  }, []);
+
+  useEffect(() => {
+    load();
+  }, [load]);
+
+  const update = (index: number, patch: Partial<BackupTarget>) => {
+    setTargets((prev) => prev.map((target, i) => (i === index ? { ...target, ...patch } : target)));
+  };
+
+  const save = async () => {
+    setSaving(true);
+    try {
+      const saved = await apiRequest<BackupTarget[]>(
+        '/api/backup-targets',
+        {
+          method: 'PUT',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify(targets),
+        },
+        'Failed to save backup targets'
+      );
+      setTargets(saved);
+      toast.success('Backup targets saved.');
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save backup targets'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const test = async (target: BackupTarget) => {
+    setTesting(target.id);
+    try {
+      await apiRequest(`/api/backup-targets/${encodeURIComponent(target.id)}/test`, { method: 'POST' }, 'Connection test failed');
+      toast.success(`${target.name} is reachable and writable.`);
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Connection test failed'));
+    } finally {
+      setTesting(null);
+    }
+  };
+
+  const secretPlaceholder = (target: BackupTarget) => (target.hasSecret ? 'unchanged' : '');
+
+  return (
+    <div>
+      <div className="flex items-center justify-between mb-3">
+        <label className="block text-sm text-gray-400">Remote Backup Targets</label>
+        {loading && <Loader2 size={14} className="animate-spin text-gray-400" />}
+      </div>
+      <div className="space-y-3">
+        {targets.map((target, index) => (
+          <div key={target.id || `new-${index}`} className="border border-[#3a3a3a] rounded p-3 space-y-2">
+            <div className="flex items-center gap-2">
+              <input
+                type="text"
+                value={target.name}
+                onChange={(e) => update(index, { name: e.target.value })}
+                placeholder="Name"
+                className={inputClass}
+                disabled={saving}
+              />
+              <select
+                value={target.type}
+                onChange={(e) => update(index, { type: e.target.value as BackupTarget['type'] })}
+                className="bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-2 text-sm text-white focus:outline-none focus:border-[#E5B80B]"
+                disabled={saving || !!target.id}
+              >
+                <option value="s3">S3</option>
+                <option value="sftp">SFTP</option>
+              </select>
+              {target.id && (
+                <button
+                  type="button"
+                  onClick={() => test(target)}
+                  className="px-3 py-2 text-xs border border-[#3a3a3a] rounded text-gray-300 hover:border-[#E5B80B] hover:text-white disabled:opacity-50 whitespace-nowrap"
+                  disabled={saving || testing !== null}
+                  title="Upload and remove a small test file"
+                >
+                  {testing === target.id ? 'Testing...' : 'Test'}
+                </button>
+              )}
+              <button
+                type="button"
+                onClick={() => setTargets((prev) => prev.filter((_, i) => i !== index))}
+                className="p-1.5 text-gray-400 hover:text-red-400"
+                title="Remove"
+                disabled={saving}
+              >
+                <Trash2 size={14} />
+              </button>
+            </div>
+            {target.type === 's3' ? (
+              <div className="grid grid-cols-1 md:grid-cols-2 gap-2">
+                <input type="text" value={target.endpoint || ''} onChange={(e) => update(index, { endpoint: e.target.value })} placeholder="Endpoint (empty for AWS)" className={inputClass} disabled={saving} />
+                <input type="text" value={target.region || ''} onChange={(e) => update(index, { region: e.target.value })} placeholder="Region (us-east-1)" className={inputClass} disabled={saving} />
+                <input type="text" value={target.bucket || ''} onChange={(e) => update(index, { bucket: e.target.value })} placeholder="Bucket" className={inputClass} disabled={saving} />
+                <input type="text" value={target.path || ''} onChange={(e) => update(index, { path: e.target.value })} placeholder="Path prefix" className={inputClass} disabled={saving} />
+                <input type="text" value={target.accessKey || ''} onChange={(e) => update(index, { accessKey: e.target.value })} placeholder="Access key" className={inputClass} disabled={saving} />
+                <input type="password" value={target.secretKey || ''} onChange={(e) => update(index, { secretKey: e.target.value })} placeholder={secretPlaceholder(target) || 'Secret key'} className={inputClass} disabled={saving} autoComplete="new-password" />
+                <label className="flex items-center gap-2 text-xs text-gray-400">
+                  <input type="checkbox" checked={!!target.pathStyle} onChange={(e) => update(index, { pathStyle: e.target.checked })} disabled={saving} />
+                  Path-style addressing (MinIO and most self-hosted stores)
+                </label>
+              </div>
+            ) : (
+              <div className="grid grid-cols-1 md:grid-cols-2 gap-2">
+                <input type="text" value={target.host || ''} onChange={(e) => update(index, { host: e.target.value })} placeholder="Host" className={inputClass} disabled={saving} />
+                <input
+                  type="number"
+                  value={target.port || ''}
+                  onChange={(e) => update(index, { port: e.target.value ? Number(e.target.value) : undefined })}
+                  placeholder="Port (22)"
+                  className={inputClass}
+                  disabled={saving}
+                />
+                <input type="text" value={target.user || ''} onChange={(e) => update(index, { user: e.target.value })} placeholder="User" className={inputClass} disabled={saving} />
+                <input type="text" value={target.path || ''} onChange={(e) => update(index, { path: e.target.value })} placeholder="Remote directory" className={inputClass} disabled={saving} />
+                <input type="password" value={target.password || ''} onChange={(e) => update(index, { password: e.target.value })} placeholder={secretPlaceholder(target) || 'Password'} className={inputClass} disabled={saving} autoComplete="new-password" />
+                <input type="text" value={target.hostKey || ''} onChange={(e) => update(index, { hostKey: e.target.value })} placeholder="Host key (SHA256:...)" className={`${inputClass} font-mono`} disabled={saving} />
+                <textarea
+                  value={target.privateKey || ''}
+                  onChange={(e) => update(index, { privateKey: e.target.value })}
+                  placeholder={secretPlaceholder(target) || 'Private key (optional, instead of a password)'}
+                  rows={3}
+                  className={`${inputClass} font-mono md:col-span-2`}
+                  disabled={saving}
+                />
+              </div>
+            )}
+          </div>
+        ))}
+      </div>
+      <div className="flex items-center justify-between mt-2">
+        <button
+          type="button"
+          onClick={() => setTargets((prev) => [...prev, { id: '', name: '', type: 's3', hasSecret: false }])}
+          className="flex items-center gap-1 px-3 py-1 text-xs border border-[#3a3a3a] rounded text-gray-300 hover:border-[#E5B80B] hover:text-white disabled:opacity-50"
+          disabled={saving}
+        >
+          <Plus size={12} /> Add target
+        </button>
+        <button
+          type="button"
+          onClick={save}
+          className="px-3 py-1 text-xs bg-[#E5B80B] hover:bg-[#d4a90a] text-black rounded font-bold disabled:opacity-50"
+          disabled={saving}
+        >
+          {saving ? 'Saving...' : 'Save targets'}
+        </button>
+      </div>
+      <p className="text-xs text-gray-500 mt-2">Pick targets per server in the backup schedule. Full scheduled backups are uploaded to &lt;path&gt;/&lt;server folder&gt;/; incremental snapshots stay local.</p>
+    </div>
+  );
+};
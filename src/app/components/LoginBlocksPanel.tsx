@@ -0,0 +1,114 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import { Loader2, RefreshCw } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../lib/api';
+
+interface LoginBlock {
+  ip: string;
+  failures: number;
+  windowStart: string;
+  blockedUntil?: string;
+  blocked: boolean;
+}
+
+interface LoginBlocksResponse {
+  blocks: LoginBlock[];
+  authLogPath?: string;
+}
+
+// Addresses with recent failed logins, and the lockouts currently in force.
+export const LoginBlocksPanel = () => {
+  const [data, setData] = useState<LoginBlocksResponse | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [clearing, setClearing] = useState<string | null>(null);
+
+  const load = useCallback(async () => {
+    setLoading(true);
+    try {
+      setData(await apiRequest<LoginBlocksResponse>('/api/security/login-blocks', undefined, 'Failed to load login blocks'));
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to load login blocks'));
+    } finally {
+      setLoading(false);
+    }
+  }, []);
+
+  useEffect(() => {
+    load();
+  }, [load]);
+
+  const clear = async (ip?: string) => {
+    setClearing(ip ?? '*');
+    try {
+      const url = ip ? `/api/security/login-blocks/${encodeURIComponent(ip)}` : '/api/security/login-blocks';
+      await apiRequest(url, { method: 'DELETE' }, 'Failed to clear login block');
+      toast.success(ip ? `Cleared ${ip}.` : 'Cleared all login blocks.');
+      await load();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to clear login block'));
+    } finally {
+      setClearing(null);
+    }
+  };
+
+  const blocks = data?.blocks ?? [];
+
+  return (
+    <div>
+      <div className="flex items-center justify-between mb-3">
+        <label className="block text-sm text-gray-400">Failed Logins</label>
+        <div className="flex items-center gap-2">
+          <button
+            type="button"
+            onClick={load}
+            className="p-1.5 text-gray-400 hover:text-white"
+            title="Refresh"
+            disabled={loading}
+          >
+            {loading ? <Loader2 size={14} className="animate-spin" /> : <RefreshCw size={14} />}
+          </button>
+          {blocks.length > 0 && (
+            <button
+              type="button"
+              onClick={() => clear()}
+              className="px-3 py-1 text-xs border border-[#3a3a3a] rounded text-gray-300 hover:border-[#E5B80B] hover:text-white disabled:opacity-50"
+              disabled={clearing !== null}
+            >
+              Clear all
+            </button>
+          )}
+        </div>
+      </div>
+      {blocks.length === 0 ? (
+        <p className="text-xs text-gray-500">No failed logins recorded.</p>
+      ) : (
+        <div className="divide-y divide-[#3a3a3a] border border-[#3a3a3a] rounded">
+          {blocks.map((block) => (
+            <div key={block.ip} className="flex items-center justify-between px-3 py-2 text-sm">
+              <div>
+                <span className="font-mono text-white">{block.ip}</span>
+                <span className="ml-3 text-xs text-gray-500">
+                  {block.failures} failed
+                  {block.blocked && block.blockedUntil && ` · blocked until ${new Date(block.blockedUntil).toLocaleString()}`}
+                </span>
+              </div>
+              <button
+                type="button"
+                onClick={() => clear(block.ip)}
+                className="px-2 py-1 text-xs text-gray-400 hover:text-[#E5B80B] disabled:opacity-50"
+                disabled={clearing !== null}
+              >
+                {block.blocked ? 'Unblock' : 'Reset'}
+              </button>
+            </div>
+          ))}
+        </div>
+      )}
+      {data?.authLogPath && (
+        <p className="text-xs text-gray-500 mt-2">
+          Failures are also written to <span className="font-mono">{data.authLogPath}</span> for fail2ban.
+        </p>
+      )}
+    </div>
+  );
+};
@@ -0,0 +1,138 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import { Loader2, Plus, Trash2 } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../lib/api';
+
+interface ModerationPreset {
+  id: string;
+  reason: string;
+}
+
+interface ModerationEvent {
+  time: string;
+  serverName?: string;
+  actor?: string;
+  action: string;
+  player: string;
+  reason?: string;
+}
+
+// Panel-wide kick and ban reasons, plus the latest moderation actions.
+export const ModerationPresetsPanel = () => {
+  const [presets, setPresets] = useState<ModerationPreset[]>([]);
+  const [events, setEvents] = useState<ModerationEvent[]>([]);
+  const [loading, setLoading] = useState(false);
+  const [saving, setSaving] = useState(false);
+
+  const load = useCallback(async () => {
+    setLoading(true);
+    try {
+      const [presetData, eventData] = await Promise.all([
+        apiRequest<ModerationPreset[]>('/api/moderation/presets', undefined, 'Failed to load reason presets'),
+        apiRequest<ModerationEvent[]>('/api/moderation/log?limit=10', undefined, 'Failed to load moderation log'),
+      ]);
+      setPresets(presetData);
+      setEvents(eventData);
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to load reason presets'));
+    } finally {
+      setLoading(false);
+    }
+  }, []);
+
+  useEffect(() => {
+    load();
+  }, [load]);
+
+  const updateReason = (index: number, reason: string) => {
+    setPresets((prev) => prev.map((preset, i) => (i === index ? { ...preset, reason } : preset)));
+  };
+
+  const save = async () => {
+    setSaving(true);
+    try {
+      const saved = await apiRequest<ModerationPreset[]>(
+        '/api/moderation/presets',
+        {
+          method: 'PUT',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify(presets.filter((preset) => preset.reason.trim())),
+        },
+        'Failed to save reason presets'
+      );
+      setPresets(saved);
+      toast.success('Reason presets saved.');
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save reason presets'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div>
+      <div className="flex items-center justify-between mb-3">
+        <label className="block text-sm text-gray-400">Kick and Ban Reasons</label>
+        {loading && <Loader2 size={14} className="animate-spin text-gray-400" />}
+      </div>
+      <div className="space-y-2">
+        {presets.map((preset, index) => (
+          <div key={preset.id || `new-${index}`} className="flex items-center gap-2">
+            <input
+              type="text"
+              value={preset.reason}
+              onChange={(e) => updateReason(index, e.target.value)}
+              maxLength={200}
+              className="flex-1 bg-[#1a1a1a] border border-[#3a3a3a] rounded px-3 py-2 text-sm text-white focus:outline-none focus:border-[#E5B80B]"
+              disabled={saving}
+            />
+            <button
+              type="button"
+              onClick={() => setPresets((prev) => prev.filter((_, i) => i !== index))}
+              className="p-1.5 text-gray-400 hover:text-red-400"
+              title="Remove"
+              disabled={saving}
+            >
+              <Trash2 size={14} />
+            </button>
+          </div>
+        ))}
+      </div>
+      <div className="flex items-center justify-between mt-2">
+        <button
+          type="button"
+          onClick={() => setPresets((prev) => [...prev, { id: '', reason: '' }])}
+          className="flex items-center gap-1 px-3 py-1 text-xs border border-[#3a3a3a] rounded text-gray-300 hover:border-[#E5B80B] hover:text-white disabled:opacity-50"
+          disabled={saving}
+        >
+          <Plus size={12} /> Add reason
+        </button>
+        <button
+          type="button"
+          onClick={save}
+          className="px-3 py-1 text-xs bg-[#E5B80B] hover:bg-[#d4a90a] text-black rounded font-bold disabled:opacity-50"
+          disabled={saving}
+        >
+          {saving ? 'Saving...' : 'Save reasons'}
+        </button>
+      </div>
+      <p className="text-xs text-gray-500 mt-2">Moderators pick these from the player list, so kicks and bans on every server use the same wording.</p>
+
+      {events.length > 0 && (
+        <div className="mt-4">
+          <label className="block text-xs text-gray-500 mb-1">Recent moderation</label>
+          <div className="divide-y divide-[#3a3a3a] border border-[#3a3a3a] rounded">
+            {events.map((event, index) => (
+              <div key={`${event.time}-${index}`} className="px-3 py-2 text-xs text-gray-400">
+                <span className="text-white">{event.actor || 'unknown'}</span> {event.action} <span className="text-white">{event.player}</span>
+                {event.serverName && <> on {event.serverName}</>}
+                {event.reason && <> · {event.reason}</>}
+                <span className="ml-2 text-gray-500">{new Date(event.time).toLocaleString()}</span>
+              </div>
+            ))}
+          </div>
+        </div>
+      )}
+    </div>
+  );
+};
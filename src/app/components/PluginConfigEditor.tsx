@@ -0,0 +1,179 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import { motion } from 'motion/react';
+import { FileText, Loader2, Save, X } from 'lucide-react';
+import clsx from 'clsx';
+import { toast } from 'sonner';
+import { apiRequest, apiRequestRaw, toErrorMessage } from '../lib/api';
+import { useEscapeKey } from '../hooks/useEscapeKey';
+
+interface PluginConfigFile {
+  path: string;
+  size: number;
+  modified: string;
+}
+
+interface PluginConfigListing {
+  plugin: string;
+  dataFolder: string;
+  files: PluginConfigFile[];
+  truncated?: boolean;
+}
+
+interface PluginConfigEditorProps {
+  serverId: string;
+  fileName: string;
+  onClose: () => void;
+}
+
+// Quick editor for the yml/json files in a plugin's data folder, so
+// config.yml is one click away instead of a trip through the file browser.
+export const PluginConfigEditor = ({ serverId, fileName, onClose }: PluginConfigEditorProps) => {
+  const base = `/api/servers/${serverId}/plugins/${encodeURIComponent(fileName)}/config`;
+  const [listing, setListing] = useState<PluginConfigListing | null>(null);
+  const [error, setError] = useState<string | null>(null);
+  const [selected, setSelected] = useState<string | null>(null);
+  const [content, setContent] = useState('');
+  const [original, setOriginal] = useState('');
+  const [loadingFile, setLoadingFile] = useState(false);
+  const [saving, setSaving] = useState(false);
+  const dirty = content !== original;
+
+  useEscapeKey(true, onClose);
+
+  const openFile = useCallback(async (path: string) => {
+    setSelected(path);
+    setLoadingFile(true);
+    try {
+      const res = await apiRequestRaw(`${base}/content?path=${encodeURIComponent(path)}`);
+      if (!res.ok) {
+        const payload = await res.json().catch(() => null);
+        throw new Error(payload?.error || 'Failed to read config');
+      }
+      const text = await res.text();
+      setContent(text);
+      setOriginal(text);
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to read config'));
+      setSelected(null);
+    } finally {
+      setLoadingFile(false);
+    }
+  }, [base]);
+
+  useEffect(() => {
+    (async () => {
+      try {
+        const data = await apiRequest<PluginConfigListing>(base, undefined, 'Failed to load config files');
+        setListing(data);
+        if (data.files.length > 0) openFile(data.files[0].path);
+      } catch (err) {
+        setError(toErrorMessage(err, 'Failed to load config files'));
+      }
+    })();
+  }, [base, openFile]);
+
+  const select = (path: string) => {
+    if (path === selected) return;
+    if (dirty && !window.confirm('Discard unsaved changes?')) return;
+    openFile(path);
+  };
+
+  const save = async () => {
+    if (!selected) return;
+    setSaving(true);
+    try {
+      await apiRequest(`${base}/content`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ path: selected, content }),
+      }, 'Failed to save config');
+      setOriginal(content);
+      toast.success(`Saved ${selected}. Reload the plugin or restart the server to apply it.`);
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save config'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div className="fixed inset-0 z-50 flex items-center justify-center bg-black/60 backdrop-blur-sm p-4">
+      <motion.div
+        initial={{ opacity: 0, scale: 0.95 }}
+        animate={{ opacity: 1, scale: 1 }}
+        exit={{ opacity: 0, scale: 0.95 }}
+        className="w-full max-w-5xl h-[85vh] flex flex-col bg-[#252524] border border-[#404040] rounded-lg shadow-2xl p-6"
+      >
+        <div className="flex items-center justify-between mb-4">
+          <div>
+            <h3 className="text-xl font-bold text-white">{listing?.plugin ?? fileName} config</h3>
+            {listing && <p className="text-xs text-gray-500">{listing.dataFolder}/</p>}
+          </div>
+          <div className="flex items-center gap-2">
+            <button
+              onClick={save}
+              disabled={!selected || !dirty || saving}
+              className="px-3 py-1.5 bg-[#E5B80B] hover:bg-[#d4a90a] text-black rounded font-bold text-sm flex items-center gap-1 disabled:opacity-50"
+            >
+              {saving ? <Loader2 size={14} className="animate-spin" /> : <Save size={14} />} Save
+            </button>
+            <button onClick={onClose} className="p-2 text-gray-400 hover:text-white" title="Close">
+              <X size={18} />
+            </button>
+          </div>
+        </div>
+
+        {error && <p className="text-red-400 text-sm">{error}</p>}
+        {!listing && !error && (
+          <div className="flex items-center gap-2 text-gray-400 text-sm">
+            <Loader2 size={16} className="animate-spin" /> Looking for config files...
+          </div>
+        )}
+        {listing && listing.files.length === 0 && (
+          <p className="text-gray-400 text-sm">No yml or json files in {listing.dataFolder}/.</p>
+        )}
+        {listing && listing.files.length > 0 && (
+          <div className="flex flex-1 gap-4 min-h-0">
+            <div className="w-56 shrink-0 overflow-y-auto space-y-1">
+              {listing.files.map(file => (
+                <button
+                  key={file.path}
+                  onClick={() => select(file.path)}
+                  title={`${file.path} · ${new Date(file.modified).toLocaleString()}`}
+                  className={clsx(
+                    'w-full text-left text-xs px-2 py-1.5 rounded flex items-center gap-2 truncate',
+                    file.path === selected ? 'bg-[#E5B80B]/20 text-[#E5B80B]' : 'text-gray-300 hover:bg-[#333]'
+                  )}
+                >
+                  <FileText size={12} className="shrink-0" />
+                  <span className="truncate">{file.path}</span>
+                </button>
+              ))}
+              {listing.truncated && <p className="text-[11px] text-gray-500 px-2">More files in the file browser.</p>}
+            </div>
+            <div className="flex-1 min-w-0 flex flex-col">
+              {loadingFile ? (
+                <div className="flex items-center gap-2 text-gray-400 text-sm">
+                  <Loader2 size={16} className="animate-spin" /> Loading...
+                </div>
+              ) : selected && (
+                <textarea
+                  value={content}
+                  onChange={(e) => setContent(e.target.value)}
+                  onKeyDown={(e) => {
+                    if ((e.ctrlKey || e.metaKey) && e.key === 's') {
+                      e.preventDefault();
+                      if (dirty) save();
+                    }
+                  }}
+                  spellCheck={false}
+                  className="flex-1 w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded p-3 font-mono text-xs text-gray-200 focus:outline-none focus:border-[#E5B80B] resize-none"
+                />
+              )}
+            </div>
+          </div>
+        )}
+      </motion.div>
+    </div>
+  );
+};
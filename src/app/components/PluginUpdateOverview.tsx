@@ -0,0 +1,162 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import { motion } from 'motion/react';
+import { Loader2, RefreshCw, X } from 'lucide-react';
+import clsx from 'clsx';
+import { apiRequest, toErrorMessage } from '../lib/api';
+import { useEscapeKey } from '../hooks/useEscapeKey';
+
+interface OverviewInstall {
+  serverId: string;
+  serverName: string;
+  fileName: string;
+  version: string;
+  latestVersion?: string;
+  versionStatus: 'latest' | 'outdated' | 'incompatible' | 'unknown';
+  updateUrl?: string;
+}
+
+interface OverviewProject {
+  key: string;
+  name: string;
+  iconUrl?: string;
+  projectUrl?: string;
+  latestVersion?: string;
+  outdatedCount: number;
+  installs: OverviewInstall[];
+}
+
+interface OverviewFailure {
+  serverId: string;
+  serverName: string;
+  error: string;
+}
+
+interface PluginUpdateOverviewData {
+  checkedAt: string;
+  projects: OverviewProject[];
+  failed: OverviewFailure[];
+}
+
+interface PluginUpdateOverviewProps {
+  onClose: () => void;
+}
+
+// Panel-wide update check: every server's plugins/mods grouped by project.
+export const PluginUpdateOverview = ({ onClose }: PluginUpdateOverviewProps) => {
+  const [data, setData] = useState<PluginUpdateOverviewData | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+  const [showAll, setShowAll] = useState(false);
+
+  useEscapeKey(true, onClose);
+
+  const load = useCallback(async () => {
+    setLoading(true);
+    setError(null);
+    try {
+      setData(await apiRequest<PluginUpdateOverviewData>('/api/plugins/updates', undefined, 'Failed to check updates'));
+    } catch (err) {
+      setError(toErrorMessage(err, 'Failed to check updates'));
+    } finally {
+      setLoading(false);
+    }
+  }, []);
+
+  useEffect(() => {
+    load();
+  }, [load]);
+
+  const projects = (data?.projects ?? []).filter(p => showAll || p.outdatedCount > 0);
+
+  return (
+    <div className="fixed inset-0 z-50 flex items-center justify-center bg-black/60 backdrop-blur-sm p-4">
+      <motion.div
+        initial={{ opacity: 0, scale: 0.95 }}
+        animate={{ opacity: 1, scale: 1 }}
+        exit={{ opacity: 0, scale: 0.95 }}
+        className="w-full max-w-3xl max-h-[85vh] flex flex-col bg-[#252524] border border-[#404040] rounded-lg shadow-2xl p-6"
+      >
+        <div className="flex items-center justify-between mb-4">
+          <div>
+            <h3 className="text-xl font-bold text-white">Updates across all servers</h3>
+            {data && (
+              <p className="text-xs text-gray-500">Checked {new Date(data.checkedAt).toLocaleString()}</p>
+            )}
+          </div>
+          <div className="flex items-center gap-3">
+            <label className="flex items-center gap-2 text-sm text-gray-400">
+              <input type="checkbox" checked={showAll} onChange={(e) => setShowAll(e.target.checked)} />
+              Show up to date
+            </label>
+            <button
+              onClick={load}
+              disabled={loading}
+              className="p-2 text-gray-400 hover:text-white disabled:opacity-50"
+              title="Check again"
+            >
+              {loading ? <Loader2 size={18} className="animate-spin" /> : <RefreshCw size={18} />}
+            </button>
+            <button onClick={onClose} className="p-2 text-gray-400 hover:text-white" title="Close">
+              <X size={18} />
+            </button>
+          </div>
+        </div>
+
+        <div className="overflow-y-auto flex-1 space-y-3">
+          {loading && !data && (
+            <div className="flex items-center gap-2 text-gray-400 text-sm">
+              <Loader2 size={16} className="animate-spin" /> Checking every server, this can take a while...
+            </div>
+          )}
+          {error && <p className="text-red-400 text-sm">{error}</p>}
+          {data && projects.length === 0 && (
+            <p className="text-gray-400 text-sm">{showAll ? 'No plugins or mods installed.' : 'Everything is up to date.'}</p>
+          )}
+          {projects.map(project => (
+            <div key={project.key} className="bg-[#1a1a1a] border border-[#333] rounded p-3">
+              <div className="flex items-center gap-3 mb-2">
+                {project.iconUrl && <img src={project.iconUrl} alt="" className="w-6 h-6 rounded" />}
+                {project.projectUrl ? (
+                  <a href={project.projectUrl} target="_blank" rel="noreferrer" className="font-semibold text-white hover:underline">
+                    {project.name}
+                  </a>
+                ) : (
+                  <span className="font-semibold text-white">{project.name}</span>
+                )}
+                {project.latestVersion && <span className="text-xs text-gray-500">latest {project.latestVersion}</span>}
+                <span className={clsx('ml-auto text-xs font-medium', project.outdatedCount > 0 ? 'text-[#E5B80B]' : 'text-green-400')}>
+                  {project.outdatedCount > 0
+                    ? `Outdated on ${project.outdatedCount} of ${project.installs.length} server${project.installs.length === 1 ? '' : 's'}`
+                    : `Up to date on ${project.installs.length} server${project.installs.length === 1 ? '' : 's'}`}
+                </span>
+              </div>
+              <div className="flex flex-wrap gap-2">
+                {project.installs.map(install => (
+                  <span
+                    key={`${install.serverId}:${install.fileName}`}
+                    title={install.fileName}
+                    className={clsx(
+                      'text-xs px-2 py-1 rounded border',
+                      install.versionStatus === 'outdated'
+                        ? 'border-[#E5B80B]/50 text-[#E5B80B]'
+                        : 'border-[#404040] text-gray-400'
+                    )}
+                  >
+                    {install.serverName}: {install.version || '?'}
+                  </span>
+                ))}
+              </div>
+            </div>
+          ))}
+          {data && data.failed.length > 0 && (
+            <div className="text-xs text-red-400">
+              {data.failed.map(f => (
+                <p key={f.serverId}>{f.serverName}: {f.error}</p>
+              ))}
+            </div>
+          )}
+        </div>
+      </motion.div>
+    </div>
+  );
+};
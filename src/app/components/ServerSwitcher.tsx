@@ -1,7 +1,9 @@
-import React, { useState } from 'react';
+import React, { useMemo, useState } from 'react';
 import { useServer } from '../context/ServerContext';
 import { DropdownMenu, DropdownMenuTrigger, DropdownMenuContent, DropdownMenuItem } from './ui/dropdown-menu';
-import { ChevronDown, Check } from 'lucide-react';
+import { ChevronDown, Check, Star, Home } from 'lucide-react';
+import { toast } from 'sonner';
+import { toErrorMessage } from '../lib/api';
 import clsx from 'clsx';
 
 type Variant = 'sidebar' | 'header';
@@ -12,11 +14,24 @@ interface ServerSwitcherProps {
 }
 
 export const ServerSwitcher = ({ variant = 'sidebar', className }: ServerSwitcherProps) => {
-  const { servers, activeServerId, setActiveServerId } = useServer();
+  const { servers, activeServerId, setActiveServerId, preferences, toggleFavorite, updatePreferences } = useServer();
   const activeServer = servers.find(s => s.id === activeServerId) || null;
   const hasMultiple = servers.length > 1;
   const [isOpen, setIsOpen] = useState(false);
 
+  // Favorites are listed first, keeping the user's card order within each part.
+  const listedServers = useMemo(() => {
+    const favorites = new Set(preferences.favoriteServerIds);
+    return [
+      ...servers.filter((server) => favorites.has(server.id)),
+      ...servers.filter((server) => !favorites.has(server.id)),
+    ];
+  }, [servers, preferences.favoriteServerIds]);
+
+  const savePreference = (action: Promise<void>) => {
+    action.catch((err) => toast.error(toErrorMessage(err, 'Failed to save preferences')));
+  };
+
   const statusDot = activeServer
     ? activeServer.status === 'Running'
       ? 'bg-green-500'
@@ -24,7 +39,9 @@ export const ServerSwitcher = ({ variant = 'sidebar', className }: ServerSwitche
         ? 'bg-red-500'
         : activeServer.status === 'Booting' || activeServer.status === 'Installing'
           ? 'bg-yellow-500'
-          : 'bg-gray-500'
+          : activeServer.status === 'Suspended'
+            ? 'bg-purple-500'
+            : 'bg-gray-500'
     : 'bg-gray-500';
 
   const buttonClasses = clsx(
@@ -61,7 +78,7 @@ export const ServerSwitcher = ({ variant = 'sidebar', className }: ServerSwitche
           align={variant === 'header' ? 'start' : 'center'}
           className="!bg-[#252524] border border-[#3a3a3a] text-gray-200 w-[var(--radix-dropdown-menu-trigger-width)] max-h-40 rounded-lg p-1 shadow-[0_14px_40px_rgba(0,0,0,0.45)]"
         >
-          {servers.map(server => (
+          {listedServers.map(server => (
             <DropdownMenuItem
               key={server.id}
               onSelect={() => setActiveServerId(server.id)}
@@ -75,9 +92,44 @@ export const ServerSwitcher = ({ variant = 'sidebar', className }: ServerSwitche
                     ? 'bg-red-500'
                     : server.status === 'Booting' || server.status === 'Installing'
                       ? 'bg-yellow-500'
-                      : 'bg-gray-500'
+                      : server.status === 'Suspended'
+                        ? 'bg-purple-500'
+                        : 'bg-gray-500'
               )} />
               <span className="truncate flex-1">{server.name}</span>
+              <button
+                type="button"
+                title={preferences.defaultServerId === server.id ? 'Opens by default' : 'Open this server by default'}
+                onPointerDown={(e) => e.stopPropagation()}
+                onClick={(e) => {
+                  e.stopPropagation();
+                  e.preventDefault();
+                  const next = preferences.defaultServerId === server.id ? '' : server.id;
+                  savePreference(updatePreferences({ defaultServerId: next }));
+                }}
+                className={clsx(
+                  "shrink-0 transition-colors",
+                  preferences.defaultServerId === server.id ? "text-[#E5B80B]" : "text-gray-600 hover:text-gray-300"
+                )}
+              >
+                <Home size={13} />
+              </button>
+              <button
+                type="button"
+                title={preferences.favoriteServerIds.includes(server.id) ? 'Remove from favorites' : 'Add to favorites'}
+                onPointerDown={(e) => e.stopPropagation()}
+                onClick={(e) => {
+                  e.stopPropagation();
+                  e.preventDefault();
+                  savePreference(toggleFavorite(server.id));
+                }}
+                className={clsx(
+                  "shrink-0 transition-colors",
+                  preferences.favoriteServerIds.includes(server.id) ? "text-[#E5B80B]" : "text-gray-600 hover:text-gray-300"
+                )}
+              >
+                <Star size={13} fill={preferences.favoriteServerIds.includes(server.id) ? 'currentColor' : 'none'} />
+              </button>
               {server.id === activeServerId && <Check size={14} className="text-[#E5B80B]" />}
             </DropdownMenuItem>
           ))}
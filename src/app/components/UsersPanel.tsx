@@ -0,0 +1,187 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import { Loader2, RefreshCw, Trash2 } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../lib/api';
+
+type Role = 'admin' | 'operator' | 'viewer';
+
+interface PanelUser {
+  username: string;
+  role: Role;
+  createdAt?: string;
+  primary?: boolean;
+}
+
+const ROLE_LABELS: Record<Role, string> = {
+  admin: 'Admin',
+  operator: 'Operator',
+  viewer: 'Viewer',
+};
+
+// Additional panel logins and their roles. Only admins can load this list.
+export const UsersPanel = () => {
+  const [users, setUsers] = useState<PanelUser[] | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [busy, setBusy] = useState<string | null>(null);
+  const [username, setUsername] = useState('');
+  const [password, setPassword] = useState('');
+  const [role, setRole] = useState<Role>('viewer');
+
+  const load = useCallback(async () => {
+    setLoading(true);
+    try {
+      setUsers(await apiRequest<PanelUser[]>('/api/users', undefined, 'Failed to load users'));
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to load users'));
+    } finally {
+      setLoading(false);
+    }
+  }, []);
+
+  useEffect(() => {
+    load();
+  }, [load]);
+
+  const create = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setBusy('*');
+    try {
+      await apiRequest('/api/users', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ username: username.trim(), password, role }),
+      }, 'Failed to create user');
+      toast.success(`Created ${username.trim()}.`);
+      setUsername('');
+      setPassword('');
+      await load();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to create user'));
+    } finally {
+      setBusy(null);
+    }
+  };
+
+  const changeRole = async (user: PanelUser, next: Role) => {
+    setBusy(user.username);
+    try {
+      await apiRequest(`/api/users/${encodeURIComponent(user.username)}`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ role: next }),
+      }, 'Failed to update user');
+      toast.success(`${user.username} is now ${ROLE_LABELS[next].toLowerCase()}.`);
+      await load();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to update user'));
+    } finally {
+      setBusy(null);
+    }
+  };
+
+  const remove = async (user: PanelUser) => {
+    if (!window.confirm(`Delete ${user.username}? Their sessions end immediately.`)) return;
+    setBusy(user.username);
+    try {
+      await apiRequest(`/api/users/${encodeURIComponent(user.username)}`, { method: 'DELETE' }, 'Failed to delete user');
+      toast.success(`Deleted ${user.username}.`);
+      await load();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to delete user'));
+    } finally {
+      setBusy(null);
+    }
+  };
+
+  return (
+    <div>
+      <div className="flex items-center justify-between mb-3">
+        <label className="block text-sm text-gray-400">Users</label>
+        <button
+          type="button"
+          onClick={load}
+          className="p-1.5 text-gray-400 hover:text-white"
+          title="Refresh"
+          disabled={loading}
+        >
+          {loading ? <Loader2 size={14} className="animate-spin" /> : <RefreshCw size={14} />}
+        </button>
+      </div>
+      {users && (
+        <div className="divide-y divide-[#3a3a3a] border border-[#3a3a3a] rounded">
+          {users.map((user) => (
+            <div key={user.username} className="flex items-center justify-between px-3 py-2 text-sm">
+              <div>
+                <span className="text-white">{user.username}</span>
+                {user.primary && <span className="ml-3 text-xs text-gray-500">login from System Settings</span>}
+              </div>
+              <div className="flex items-center gap-2">
+                {user.primary ? (
+                  <span className="text-xs text-gray-400">{ROLE_LABELS[user.role]}</span>
+                ) : (
+                  <>
+                    <select
+                      value={user.role}
+                      onChange={(e) => changeRole(user, e.target.value as Role)}
+                      className="bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1 text-xs text-white focus:outline-none focus:border-[#E5B80B]"
+                      disabled={busy !== null}
+                    >
+                      {(Object.keys(ROLE_LABELS) as Role[]).map((r) => (
+                        <option key={r} value={r}>{ROLE_LABELS[r]}</option>
+                      ))}
+                    </select>
+                    <button
+                      type="button"
+                      onClick={() => remove(user)}
+                      className="p-1 text-gray-400 hover:text-red-400 disabled:opacity-50"
+                      title="Delete user"
+                      disabled={busy !== null}
+                    >
+                      <Trash2 size={14} />
+                    </button>
+                  </>
+                )}
+              </div>
+            </div>
+          ))}
+        </div>
+      )}
+      <form onSubmit={create} className="flex flex-wrap items-center gap-2 mt-3">
+        <input
+          type="text"
+          value={username}
+          onChange={(e) => setUsername(e.target.value)}
+          placeholder="Username"
+          className="flex-1 min-w-[120px] bg-[#1a1a1a] border border-[#3a3a3a] rounded px-3 py-1.5 text-sm text-white focus:outline-none focus:border-[#E5B80B]"
+        />
+        <input
+          type="password"
+          value={password}
+          onChange={(e) => setPassword(e.target.value)}
+          placeholder="Password"
+          autoComplete="new-password"
+          className="flex-1 min-w-[120px] bg-[#1a1a1a] border border-[#3a3a3a] rounded px-3 py-1.5 text-sm text-white focus:outline-none focus:border-[#E5B80B]"
+        />
+        <select
+          value={role}
+          onChange={(e) => setRole(e.target.value as Role)}
+          className="bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-sm text-white focus:outline-none focus:border-[#E5B80B]"
+        >
+          {(Object.keys(ROLE_LABELS) as Role[]).map((r) => (
+            <option key={r} value={r}>{ROLE_LABELS[r]}</option>
+          ))}
+        </select>
+        <button
+          type="submit"
+          className="px-3 py-1.5 text-sm bg-[#E5B80B] text-black rounded font-medium hover:bg-[#d4a90a] disabled:opacity-50"
+          disabled={busy !== null || !username.trim() || !password}
+        >
+          Add user
+        </button>
+      </form>
+      <p className="text-xs text-gray-500 mt-2">
+        Operators can start, stop and use the console but cannot delete servers or change settings. Viewers have read-only access.
+      </p>
+    </div>
+  );
+};
@@ -0,0 +1,107 @@
+import React, { useState } from 'react';
+import { History, Search } from 'lucide-react';
+import clsx from 'clsx';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import type { Server } from '../../context/ServerContext';
+
+interface BlockHistoryCardProps {
+  server: Server;
+}
+
+interface CoreProtectEntry {
+  time: string;
+  player: string;
+  world: string;
+  x: number;
+  y: number;
+  z: number;
+  block: string;
+  action: string;
+  rolledBack: boolean;
+}
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]';
+
+const ACTION_COLORS: Record<string, string> = {
+  break: 'text-red-400',
+  place: 'text-green-400',
+  interact: 'text-blue-400',
+};
+
+// Browses CoreProtect's block log by player or by coordinate.
+export const BlockHistoryCard = ({ server }: BlockHistoryCardProps) => {
+  const [player, setPlayer] = useState('');
+  const [coords, setCoords] = useState('');
+  const [hours, setHours] = useState('72');
+  const [entries, setEntries] = useState<CoreProtectEntry[] | null>(null);
+  const [error, setError] = useState<string | null>(null);
+  const [busy, setBusy] = useState(false);
+
+  if (server.type.toLowerCase() === 'velocity') {
+    return null;
+  }
+
+  const lookup = async () => {
+    const params = new URLSearchParams({ hours: hours || '72' });
+    if (player.trim()) params.set('player', player.trim());
+    const parts = coords.trim().split(/[\s,]+/).filter(Boolean);
+    if (parts.length === 3) {
+      params.set('x', parts[0]);
+      params.set('y', parts[1]);
+      params.set('z', parts[2]);
+    }
+    setBusy(true);
+    setError(null);
+    try {
+      setEntries(await apiRequest<CoreProtectEntry[]>(`/api/servers/${server.id}/coreprotect/lookup?${params}`, undefined, 'Lookup failed'));
+    } catch (err) {
+      setEntries(null);
+      setError(toErrorMessage(err, 'Lookup failed'));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center gap-2">
+        <History size={14} className="text-gray-400" />
+        <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Block History</h4>
+      </div>
+      <p className="text-[11px] text-gray-500">Reads CoreProtect's log. Give a player, coordinates (x y z), or both.</p>
+      <input value={player} onChange={(e) => setPlayer(e.target.value)} placeholder="Player" className={inputClass} />
+      <div className="grid grid-cols-3 gap-2">
+        <input value={coords} onChange={(e) => setCoords(e.target.value)} placeholder="x y z" className={clsx(inputClass, 'col-span-2')} />
+        <input value={hours} onChange={(e) => setHours(e.target.value.replace(/[^0-9]/g, ''))} inputMode="numeric" placeholder="Hours" title="Hours back" className={inputClass} />
+      </div>
+      <button
+        onClick={lookup}
+        disabled={busy || (!player.trim() && !coords.trim())}
+        className="w-full py-2 bg-[#E5B80B] text-black rounded font-bold text-xs hover:bg-[#d4a90a] flex items-center justify-center gap-1 disabled:opacity-50"
+      >
+        <Search size={12} /> {busy ? 'Searching...' : 'Look up'}
+      </button>
+      {error && <p className="text-[11px] text-red-400">{error}</p>}
+      {entries && entries.length === 0 && <p className="text-[11px] text-gray-500">No changes found.</p>}
+      {entries && entries.length > 0 && (
+        <div className="max-h-64 overflow-y-auto space-y-1">
+          {entries.map((entry, index) => (
+            <div key={index} className={clsx('text-[11px] bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1', entry.rolledBack && 'opacity-50 line-through')}>
+              <div className="flex justify-between gap-2">
+                <span className="text-white truncate">{entry.player}</span>
+                <span className="text-gray-500 shrink-0">{new Date(entry.time).toLocaleString()}</span>
+              </div>
+              <div className="flex justify-between gap-2">
+                <span className={clsx('truncate', ACTION_COLORS[entry.action] ?? 'text-gray-300')}>
+                  {entry.action} {entry.block.replace(/^minecraft:/, '')}
+                </span>
+                <span className="text-gray-400 font-mono shrink-0">{entry.x} {entry.y} {entry.z}</span>
+              </div>
+            </div>
+          ))}
+        </div>
+      )}
+    </div>
+  );
+};
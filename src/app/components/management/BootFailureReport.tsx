@@ -0,0 +1,120 @@
+import React, { useEffect, useState } from 'react';
+import { motion } from 'motion/react';
+import { AlertTriangle, Loader2, X } from 'lucide-react';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import { useEscapeKey } from '../../hooks/useEscapeKey';
+import type { PortConflict } from '../../context/ServerContext';
+
+interface BootFailureCause {
+  code: 'port_in_use' | 'eula_not_accepted' | 'wrong_java' | 'missing_dependency';
+  summary: string;
+  evidence: string;
+}
+
+interface BootFailureReportData {
+  serverId: string;
+  startedAt: string;
+  failedAt: string;
+  exitError?: string;
+  causes: BootFailureCause[];
+  logSource: 'latest.log' | 'console';
+  logTail: string[];
+  crashReport?: string;
+  crashCause?: string;
+  installError?: string;
+  installerLog?: string[];
+  portConflict?: PortConflict;
+}
+
+interface BootFailureReportProps {
+  serverId: string;
+  onClose: () => void;
+}
+
+// Shows what the backend collected when the server's last boot failed.
+export const BootFailureReport = ({ serverId, onClose }: BootFailureReportProps) => {
+  const [report, setReport] = useState<BootFailureReportData | null>(null);
+  const [error, setError] = useState<string | null>(null);
+
+  useEscapeKey(true, onClose);
+
+  useEffect(() => {
+    let cancelled = false;
+    apiRequest<BootFailureReportData>(`/api/servers/${serverId}/boot-failure`, undefined, 'Failed to load boot failure report')
+      .then((data) => { if (!cancelled) setReport(data); })
+      .catch((err) => { if (!cancelled) setError(toErrorMessage(err, 'Failed to load boot failure report')); });
+    return () => { cancelled = true; };
+  }, [serverId]);
+
+  return (
+    <div className="fixed inset-0 z-50 flex items-center justify-center bg-black/60 backdrop-blur-sm p-4">
+      <motion.div
+        initial={{ opacity: 0, scale: 0.95 }}
+        animate={{ opacity: 1, scale: 1 }}
+        exit={{ opacity: 0, scale: 0.95 }}
+        className="w-full max-w-3xl max-h-[85vh] flex flex-col bg-[#252524] border border-[#404040] rounded-lg shadow-2xl p-6"
+      >
+        <div className="flex items-center justify-between mb-4">
+          <div>
+            <h3 className="text-xl font-bold text-white flex items-center gap-2">
+              <AlertTriangle size={20} className="text-red-400" /> Boot failure
+            </h3>
+            {report && (
+              <p className="text-xs text-gray-500">
+                Failed {new Date(report.failedAt).toLocaleString()}{report.exitError ? ` (${report.exitError})` : ''}
+              </p>
+            )}
+          </div>
+          <button onClick={onClose} className="p-2 text-gray-400 hover:text-white" title="Close">
+            <X size={18} />
+          </button>
+        </div>
+
+        <div className="overflow-y-auto flex-1 space-y-4">
+          {!report && !error && (
+            <div className="flex items-center gap-2 text-gray-400 text-sm">
+              <Loader2 size={16} className="animate-spin" /> Loading report...
+            </div>
+          )}
+          {error && <p className="text-red-400 text-sm">{error}</p>}
+          {report && (
+            <>
+              {report.causes.length > 0 ? (
+                <div className="space-y-2">
+                  {report.causes.map((cause) => (
+                    <div key={cause.code} className="bg-red-900/10 border border-red-900/30 rounded p-3">
+                      <p className="text-sm font-medium text-red-300">{cause.summary}</p>
+                      <p className="text-xs font-mono text-gray-400 mt-1 break-all">{cause.evidence}</p>
+                    </div>
+                  ))}
+                </div>
+              ) : (
+                <p className="text-sm text-gray-400">No common cause was recognised. Check the log below.</p>
+              )}
+              {report.crashReport && (
+                <p className="text-sm text-gray-300">
+                  Crash report <span className="font-mono">{report.crashReport}</span>
+                  {report.crashCause && report.crashCause !== 'Unknown' ? `: ${report.crashCause}` : ''}
+                </p>
+              )}
+              {report.installError && (
+                <p className="text-sm text-red-400">Installer: {report.installError}</p>
+              )}
+              {report.installerLog && report.installerLog.length > 0 && (
+                <pre className="bg-[#1a1a1a] border border-[#333] rounded p-3 text-xs text-gray-400 whitespace-pre-wrap">
+                  {report.installerLog.join('\n')}
+                </pre>
+              )}
+              <div>
+                <p className="text-xs text-gray-500 mb-1">Last lines from {report.logSource}</p>
+                <pre className="bg-[#1a1a1a] border border-[#333] rounded p-3 text-xs text-gray-300 whitespace-pre-wrap max-h-80 overflow-y-auto">
+                  {report.logTail.join('\n')}
+                </pre>
+              </div>
+            </>
+          )}
+        </div>
+      </motion.div>
+    </div>
+  );
+};
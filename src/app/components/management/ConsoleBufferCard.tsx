@@ -0,0 +1,72 @@
+import React, { useEffect, useState } from 'react';
+import { Save, ScrollText } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import { useServer, type Server } from '../../context/ServerContext';
+
+interface ConsoleBufferCardProps {
+  server: Server;
+}
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]';
+
+// Sizes the console history the panel keeps in memory for this server.
+// Changes apply immediately, even while it runs.
+export const ConsoleBufferCard = ({ server }: ConsoleBufferCardProps) => {
+  const { refreshServers } = useServer();
+  const [maxLines, setMaxLines] = useState(2000);
+  const [trimLines, setTrimLines] = useState(200);
+  const [saving, setSaving] = useState(false);
+
+  useEffect(() => {
+    setMaxLines(server.consoleBuffer?.maxLines ?? 2000);
+    setTrimLines(server.consoleBuffer?.trimLines ?? 200);
+  }, [server.id, server.consoleBuffer?.maxLines, server.consoleBuffer?.trimLines]);
+
+  const save = async () => {
+    setSaving(true);
+    try {
+      await apiRequest(`/api/servers/${server.id}/console-buffer`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ maxLines, trimLines }),
+      }, 'Failed to save console buffer');
+      toast.success('Console buffer updated');
+      await refreshServers();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save console buffer'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center gap-2">
+        <ScrollText size={14} className="text-gray-400" />
+        <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Console Buffer</h4>
+      </div>
+      <p className="text-[11px] text-gray-500">
+        Lines of console history kept in memory. Once full, the oldest lines are dropped in batches.
+      </p>
+      <div className="grid grid-cols-2 gap-2">
+        <label className="text-[11px] text-gray-500 space-y-1">
+          <span>Max lines</span>
+          <input type="number" min={100} max={50000} value={maxLines} onChange={(e) => setMaxLines(Number(e.target.value))} className={inputClass} />
+        </label>
+        <label className="text-[11px] text-gray-500 space-y-1">
+          <span>Drop per trim</span>
+          <input type="number" min={1} max={maxLines} value={trimLines} onChange={(e) => setTrimLines(Number(e.target.value))} className={inputClass} />
+        </label>
+      </div>
+      <button
+        onClick={save}
+        disabled={saving}
+        className="w-full py-2 bg-[#E5B80B] text-black rounded font-bold text-xs hover:bg-[#d4a90a] flex items-center justify-center gap-1 disabled:opacity-50"
+      >
+        <Save size={12} /> {saving ? 'Saving...' : 'Save'}
+      </button>
+    </div>
+  );
+};
@@ -1,6 +1,6 @@
 import React, { useState, useEffect, useRef } from 'react';
 import { Server } from '../../context/ServerContext';
-import { Send, ChevronsDown } from 'lucide-react';
+import { Send, ChevronsDown, Download } from 'lucide-react';
 
 interface ConsoleLogEntry {
   seq: number;
@@ -295,61 +295,80 @@ export const ConsoleView = ({ server }: ConsoleViewProps) => {
 
     const loc = window.location;
     const protocol = loc.protocol === 'https:' ? 'wss:' : 'ws:';
-    const lastSeq = lastSeqRef.current;
-    const wsUrl = `${protocol}//${loc.host}/api/logs/${server.id}?lastSeq=${lastSeq}`;
-    const ws = new WebSocket(wsUrl);
-    wsRef.current = ws;
-
-    ws.onopen = () => {
-      setConnected(true);
-    };
-
-    ws.onmessage = (event) => {
-      try {
-        const data = JSON.parse(event.data);
-        if (data.type === 'snapshot' && Array.isArray(data.entries)) {
-          const incoming = data.entries
-            .filter((entry: unknown): entry is { seq: number; line: string } => {
-              if (!entry || typeof entry !== 'object') return false;
-              const raw = entry as { seq?: unknown; line?: unknown };
-              return typeof raw.line === 'string' && typeof raw.seq === 'number';
-            })
-            .map((entry) => ({
-              seq: entry.seq,
-              line: normalizeLogTimestamp(entry.line),
-            }));
-          const isReset = data.reset === true;
-          if (isReset) {
-            setLogs(() => trimLogs(incoming.sort((a, b) => a.seq - b.seq)));
-          } else {
-            setLogs((prev) => mergeLogsBySeq(prev, incoming));
+    let disposed = false;
+    let retryDelay = 1000;
+    let retryTimer: ReturnType<typeof setTimeout> | undefined;
+
+    // Reconnect with the last sequence seen so the server only replays
+    // what was missed while the socket was down.
+    const connect = () => {
+      const wsUrl = `${protocol}//${loc.host}/api/logs/${server.id}?lastSeq=${lastSeqRef.current}`;
+      const ws = new WebSocket(wsUrl);
+      wsRef.current = ws;
+
+      ws.onopen = () => {
+        setConnected(true);
+        retryDelay = 1000;
+      };
+
+      ws.onmessage = (event) => {
+        try {
+          const data = JSON.parse(event.data);
+          if (data.type === 'snapshot' && Array.isArray(data.entries)) {
+            const incoming = data.entries
+              .filter((entry: unknown): entry is { seq: number; line: string } => {
+                if (!entry || typeof entry !== 'object') return false;
+                const raw = entry as { seq?: unknown; line?: unknown };
+                return typeof raw.line === 'string' && typeof raw.seq === 'number';
+              })
+              .map((entry) => ({
+                seq: entry.seq,
+                line: normalizeLogTimestamp(entry.line),
+              }));
+            const isReset = data.reset === true;
+            if (isReset) {
+              setLogs(() => trimLogs(incoming.sort((a, b) => a.seq - b.seq)));
+            } else {
+              setLogs((prev) => mergeLogsBySeq(prev, incoming));
+            }
+            return;
           }
-          return;
-        }
-        if (data.type === 'log') {
-          const line = typeof data.line === 'string' ? data.line : '';
-          if (!line) return;
-          const seq = typeof data.seq === 'number' ? data.seq : (lastSeqRef.current + 1);
-          const normalized = normalizeLogTimestamp(line);
-          setLogs(prev => mergeLogsBySeq(prev, [{ seq, line: normalized }]));
+          if (data.type === 'log') {
+            const line = typeof data.line === 'string' ? data.line : '';
+            if (!line) return;
+            const seq = typeof data.seq === 'number' ? data.seq : (lastSeqRef.current + 1);
+            const normalized = normalizeLogTimestamp(line);
+            setLogs(prev => mergeLogsBySeq(prev, [{ seq, line: normalized }]));
+            return;
+          }
+          if (data.type === 'end' && typeof data.lastSeq === 'number' && data.lastSeq > lastSeqRef.current) {
+            lastSeqRef.current = data.lastSeq;
+          }
+        } catch {
+          // Handle non-JSON messages as raw text
+          const normalized = normalizeLogTimestamp(String(event.data));
+          setLogs(prev => mergeLogsBySeq(prev, [{ seq: lastSeqRef.current + 1, line: normalized }]));
         }
-      } catch {
-        // Handle non-JSON messages as raw text
-        const normalized = normalizeLogTimestamp(String(event.data));
-        setLogs(prev => mergeLogsBySeq(prev, [{ seq: lastSeqRef.current + 1, line: normalized }]));
-      }
+      };
+
+      ws.onclose = () => {
+        setConnected(false);
+        if (disposed) return;
+        retryTimer = setTimeout(connect, retryDelay);
+        retryDelay = Math.min(retryDelay * 2, 15000);
+      };
+
+      ws.onerror = () => {
+        setConnected(false);
+      };
     };
 
-    ws.onclose = () => {
-      setConnected(false);
-    };
-
-    ws.onerror = () => {
-      setConnected(false);
-    };
+    connect();
 
     return () => {
-      ws.close();
+      disposed = true;
+      if (retryTimer) clearTimeout(retryTimer);
+      wsRef.current?.close();
       wsRef.current = null;
     };
   }, [server.id, server.status]);
@@ -428,6 +447,14 @@ export const ConsoleView = ({ server }: ConsoleViewProps) => {
         >
           <Send size={18} />
         </button>
+        <a
+          href={`/api/logs/${server.id}/export?latest=true&strip=true`}
+          download
+          title="Export console and latest.log without colour codes"
+          className="bg-[#333] text-gray-300 hover:text-white px-3 rounded border border-[#3a3a3a] hover:border-gray-500 transition-colors flex items-center"
+        >
+          <Download size={18} />
+        </a>
       </form>
     </div>
   );
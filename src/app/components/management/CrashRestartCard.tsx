@@ -0,0 +1,90 @@
+import React, { useEffect, useState } from 'react';
+import { RotateCcw, Save } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import { useServer, type Server } from '../../context/ServerContext';
+
+interface CrashRestartCardProps {
+  server: Server;
+}
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]';
+
+// Starts a crashed server again, waiting twice as long after each crash in
+// a row and giving up after the configured number of tries.
+export const CrashRestartCard = ({ server }: CrashRestartCardProps) => {
+  const { refreshServers } = useServer();
+  const [enabled, setEnabled] = useState(false);
+  const [maxRetries, setMaxRetries] = useState(3);
+  const [initialDelay, setInitialDelay] = useState(10);
+  const [saving, setSaving] = useState(false);
+
+  useEffect(() => {
+    setEnabled(server.restartOnCrash?.enabled ?? false);
+    setMaxRetries(server.restartOnCrash?.maxRetries ?? 3);
+    setInitialDelay(server.restartOnCrash?.initialDelaySeconds ?? 10);
+  }, [server.id, server.restartOnCrash?.enabled, server.restartOnCrash?.maxRetries, server.restartOnCrash?.initialDelaySeconds]);
+
+  const save = async () => {
+    setSaving(true);
+    try {
+      await apiRequest(`/api/servers/${server.id}/restart-on-crash`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ enabled, maxRetries, initialDelaySeconds: initialDelay }),
+      }, 'Failed to save crash restart');
+      toast.success(enabled ? 'Crashed server will restart automatically' : 'Crash restart disabled');
+      await refreshServers();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save crash restart'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const crashes = server.crashCount ?? 0;
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center justify-between gap-2">
+        <div className="flex items-center gap-2">
+          <RotateCcw size={14} className="text-gray-400" />
+          <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Restart On Crash</h4>
+        </div>
+        <label className="flex items-center gap-1 text-[11px] text-gray-400">
+          <input type="checkbox" checked={enabled} onChange={(e) => setEnabled(e.target.checked)} className="accent-[#E5B80B]" />
+          Enabled
+        </label>
+      </div>
+      <div className="grid grid-cols-2 gap-2">
+        <label className="text-[11px] text-gray-500 space-y-1">
+          <span>Max retries</span>
+          <input type="number" min={1} max={20} value={maxRetries} onChange={(e) => setMaxRetries(Number(e.target.value))} className={inputClass} />
+        </label>
+        <label className="text-[11px] text-gray-500 space-y-1">
+          <span>First delay (s)</span>
+          <input type="number" min={5} max={600} value={initialDelay} onChange={(e) => setInitialDelay(Number(e.target.value))} className={inputClass} />
+        </label>
+      </div>
+      {crashes > 0 && (
+        <p className="text-[11px] text-red-400">
+          Crashed {crashes} {crashes === 1 ? 'time' : 'times'} in the last hour
+          {server.lastCrashAt && <span className="text-gray-500"> · last {new Date(server.lastCrashAt).toLocaleString()}</span>}
+        </p>
+      )}
+      {server.crashRestartAt && (
+        <p className="text-[11px] text-[#E5B80B]">
+          Restarting at {new Date(server.crashRestartAt).toLocaleTimeString()} (attempt {server.crashRestarts ?? 1})
+        </p>
+      )}
+      <button
+        onClick={save}
+        disabled={saving}
+        className="w-full py-2 bg-[#E5B80B] text-black rounded font-bold text-xs hover:bg-[#d4a90a] flex items-center justify-center gap-1 disabled:opacity-50"
+      >
+        <Save size={12} /> {saving ? 'Saving...' : 'Save'}
+      </button>
+    </div>
+  );
+};
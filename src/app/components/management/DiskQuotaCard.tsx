@@ -0,0 +1,87 @@
+import React, { useEffect, useState } from 'react';
+import { HardDrive, Save } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import { useServer, type Server } from '../../context/ServerContext';
+
+interface DiskQuotaCardProps {
+  server: Server;
+}
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]';
+
+const formatGb = (bytes: number) => `${(bytes / 1024 / 1024 / 1024).toFixed(2)} GB`;
+
+// Caps the disk space of the server folder and its backups. A soft quota
+// only warns on the console; an enforced one refuses uploads and backups.
+export const DiskQuotaCard = ({ server }: DiskQuotaCardProps) => {
+  const { refreshServers } = useServer();
+  const [serverGb, setServerGb] = useState(0);
+  const [backupsGb, setBackupsGb] = useState(0);
+  const [enforce, setEnforce] = useState(false);
+  const [saving, setSaving] = useState(false);
+  const usage = server.diskQuota?.usage;
+
+  useEffect(() => {
+    setServerGb(server.diskQuota?.serverGb ?? 0);
+    setBackupsGb(server.diskQuota?.backupsGb ?? 0);
+    setEnforce(server.diskQuota?.enforce ?? false);
+  }, [server.id, server.diskQuota?.serverGb, server.diskQuota?.backupsGb, server.diskQuota?.enforce]);
+
+  const save = async () => {
+    setSaving(true);
+    try {
+      await apiRequest(`/api/servers/${server.id}/disk-quota`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ serverGb, backupsGb, enforce }),
+      }, 'Failed to save disk quota');
+      toast.success('Disk quota updated');
+      await refreshServers();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save disk quota'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center gap-2">
+        <HardDrive size={14} className="text-gray-400" />
+        <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Disk Quota</h4>
+      </div>
+      <p className="text-[11px] text-gray-500">
+        Space the server folder and its backups may use, in GB. 0 means no limit.
+      </p>
+      <div className="grid grid-cols-2 gap-2">
+        <label className="text-[11px] text-gray-500 space-y-1">
+          <span>Server folder</span>
+          <input type="number" min={0} step={0.5} value={serverGb} onChange={(e) => setServerGb(Number(e.target.value))} className={inputClass} />
+          {usage && (
+            <span className={`block ${usage.serverOver ? 'text-red-400' : ''}`}>Using {formatGb(usage.serverBytes)}</span>
+          )}
+        </label>
+        <label className="text-[11px] text-gray-500 space-y-1">
+          <span>Backups</span>
+          <input type="number" min={0} step={0.5} value={backupsGb} onChange={(e) => setBackupsGb(Number(e.target.value))} className={inputClass} />
+          {usage && (
+            <span className={`block ${usage.backupsOver ? 'text-red-400' : ''}`}>Using {formatGb(usage.backupsBytes)}</span>
+          )}
+        </label>
+      </div>
+      <label className="flex items-center gap-2 text-[11px] text-gray-400">
+        <input type="checkbox" checked={enforce} onChange={(e) => setEnforce(e.target.checked)} className="accent-[#E5B80B]" />
+        Refuse uploads and backups over the quota (otherwise only warn)
+      </label>
+      <button
+        onClick={save}
+        disabled={saving}
+        className="w-full py-2 bg-[#E5B80B] text-black rounded font-bold text-xs hover:bg-[#d4a90a] flex items-center justify-center gap-1 disabled:opacity-50"
+      >
+        <Save size={12} /> {saving ? 'Saving...' : 'Save'}
+      </button>
+    </div>
+  );
+};
@@ -0,0 +1,56 @@
+import React, { useState } from 'react';
+import { PackageOpen, Loader2 } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage, waitForJob, type Job } from '../../lib/api';
+import type { Server } from '../../context/ServerContext';
+
+interface ExportCardProps {
+  server: Server;
+}
+
+// Packs the server folder and its settings into one archive that another
+// panel can import, for moving the server to a new host.
+export const ExportCard = ({ server }: ExportCardProps) => {
+  const [progress, setProgress] = useState<string | null>(null);
+
+  const exportServer = async () => {
+    setProgress('Starting export...');
+    try {
+      const job = await apiRequest<Job<{ file: string; size: string }>>(
+        `/api/servers/${server.id}/export`,
+        { method: 'POST' },
+        'Failed to export server',
+      );
+      const result = await waitForJob(job, (current) => setProgress(`Exporting (${current.progress}%)`));
+      if (!result) throw new Error('The export did not produce a file');
+      toast.success(`Exported ${server.name} (${result.size})`);
+      window.open(`/api/servers/${server.id}/export/${encodeURIComponent(result.file)}`, '_blank');
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to export server'));
+    } finally {
+      setProgress(null);
+    }
+  };
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center gap-2">
+        <PackageOpen size={14} className="text-gray-400" />
+        <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Export</h4>
+      </div>
+      <p className="text-[11px] text-gray-500">
+        Download the server folder with its settings and scheduled tasks as one archive, to import it on another panel.
+        The download link stays valid for a day.
+      </p>
+      {progress && <p className="text-[11px] text-gray-400">{progress}</p>}
+      <button
+        onClick={exportServer}
+        disabled={progress !== null}
+        className="w-full py-2 bg-[#E5B80B] text-black rounded font-bold text-xs hover:bg-[#d4a90a] flex items-center justify-center gap-1 disabled:opacity-50"
+      >
+        {progress !== null ? <Loader2 size={12} className="animate-spin" /> : <PackageOpen size={12} />}
+        {progress !== null ? 'Exporting...' : 'Export Server'}
+      </button>
+    </div>
+  );
+};
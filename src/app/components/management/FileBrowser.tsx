@@ -7,12 +7,33 @@ import { toast } from 'sonner';
 import { useEscapeKey } from '../../hooks/useEscapeKey';
 import { Checkbox } from '../ui/checkbox';
 import { useStagedDeleteUndo } from '../../hooks/useStagedDeleteUndo';
-import { apiRequest, toErrorMessage } from '../../lib/api';
+import { apiRequest, csrfHeaders, toErrorMessage, waitForJob, type Job } from '../../lib/api';
 
 interface FileBrowserProps {
   server: Server;
 }
 
+interface DeletePreview {
+  path: string;
+  isDir: boolean;
+  size: number;
+  files: number;
+  folders: number;
+  async: boolean;
+}
+
+const formatBytes = (bytes: number) => {
+  if (bytes < 1024) return `${bytes} B`;
+  const units = ['KB', 'MB', 'GB', 'TB'];
+  let value = bytes / 1024;
+  let unit = 0;
+  while (value >= 1024 && unit < units.length - 1) {
+    value /= 1024;
+    unit++;
+  }
+  return `${value.toFixed(1)} ${units[unit]}`;
+};
+
 interface UploadItem {
   file: File;
   relativePath: string;
@@ -221,6 +242,7 @@ export const FileBrowser = ({ server }: FileBrowserProps) => {
   const [renameExtensionWarningOpen, setRenameExtensionWarningOpen] = useState(false);
   const [renaming, setRenaming] = useState(false);
   const [deleteConfirmOpen, setDeleteConfirmOpen] = useState(false);
+  const [deletePreviews, setDeletePreviews] = useState<DeletePreview[] | null>(null);
   const [pendingDeletedPaths, setPendingDeletedPaths] = useState<Set<string>>(new Set());
   const fileInputRef = useRef<HTMLInputElement>(null);
   const folderInputRef = useRef<HTMLInputElement>(null);
@@ -545,9 +567,21 @@ export const FileBrowser = ({ server }: FileBrowserProps) => {
     }
   };
 
-  const handleDelete = () => {
+  const handleDelete = async () => {
     if (selectedNames.size === 0) return;
+    const paths = Array.from(selectedNames).map((name) => (currentPath === '.' ? name : `${currentPath}/${name}`));
+    setDeletePreviews(null);
     setDeleteConfirmOpen(true);
+    try {
+      const previews = await Promise.all(paths.map((path) => apiRequest<DeletePreview>(
+        `/api/servers/${server.id}/files/delete-preview?path=${encodeURIComponent(path)}`,
+        undefined,
+        'Failed to measure selection'
+      )));
+      setDeletePreviews(previews);
+    } catch {
+      setDeletePreviews([]);
+    }
   };
 
   const handleConfirmDelete = () => {
@@ -580,12 +614,19 @@ export const FileBrowser = ({ server }: FileBrowserProps) => {
         });
       },
       onCommit: async () => {
+        const jobs: Job[] = [];
         for (const targetPath of paths) {
-          await apiRequest<void>(
+          const res = await apiRequest<Job | { status: string }>(
             `/api/servers/${server.id}/files?path=${encodeURIComponent(targetPath)}`,
             { method: 'DELETE' },
             `Failed to delete ${targetPath}`
           );
+          // Large folders are removed by a job after they leave the listing.
+          if ('id' in res) jobs.push(res);
+        }
+        if (jobs.length > 0) {
+          toast.info('Deleting large folders in the background...');
+          await Promise.all(jobs.map((job) => waitForJob(job)));
         }
         setPendingDeletedPaths((prev) => {
           const next = new Set(prev);
@@ -608,7 +649,7 @@ export const FileBrowser = ({ server }: FileBrowserProps) => {
     try {
       const res = await fetch(`/api/servers/${server.id}/files/download`, {
         method: 'POST',
-        headers: { 'Content-Type': 'application/json' },
+        headers: { 'Content-Type': 'application/json', ...csrfHeaders() },
         body: JSON.stringify({ paths }),
       });
       if (!res.ok) throw new Error('Failed to download');
@@ -669,6 +710,7 @@ export const FileBrowser = ({ server }: FileBrowserProps) => {
     new Promise((resolve, reject) => {
       const xhr = new XMLHttpRequest();
       xhr.open('POST', `/api/servers/${server.id}/files/upload?path=${encodeURIComponent(currentPath)}`);
+      Object.entries(csrfHeaders()).forEach(([name, value]) => xhr.setRequestHeader(name, value));
       xhr.upload.onprogress = (event) => {
         if (event.lengthComputable) {
           onProgress(event.loaded, event.total);
@@ -1259,11 +1301,30 @@ export const FileBrowser = ({ server }: FileBrowserProps) => {
               className="w-full max-w-md bg-[#252524] border border-red-900/50 rounded-lg shadow-2xl p-6"
             >
               <h3 className="text-xl font-bold text-white mb-3">Delete selected items?</h3>
-              <p className="text-gray-300 mb-6">
+              <p className="text-gray-300 mb-3">
                 {selectedNames.size > 1
                   ? 'Are you sure you want to delete the chosen files?'
                   : 'Are you sure you want to delete the chosen file?'}
               </p>
+              <div className="text-sm text-gray-400 mb-6">
+                {deletePreviews === null ? (
+                  <span className="flex items-center gap-2"><Loader2 size={14} className="animate-spin" /> Measuring selection...</span>
+                ) : deletePreviews.length > 0 && (() => {
+                  const size = deletePreviews.reduce((sum, p) => sum + p.size, 0);
+                  const files = deletePreviews.reduce((sum, p) => sum + p.files, 0);
+                  const large = deletePreviews.filter((p) => p.async);
+                  return (
+                    <>
+                      <p>This removes <span className="text-white font-semibold">{formatBytes(size)}</span> in {files.toLocaleString()} file{files === 1 ? '' : 's'}.</p>
+                      {large.length > 0 && (
+                        <p className="text-[#E5B80B] mt-1">
+                          {large.map((p) => p.path).join(', ')} {large.length === 1 ? 'is' : 'are'} large and will be deleted in the background.
+                        </p>
+                      )}
+                    </>
+                  );
+                })()}
+              </div>
               <div className="flex justify-end gap-3">
                 <button
                   type="button"
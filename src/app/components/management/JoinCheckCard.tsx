@@ -0,0 +1,112 @@
+import React, { useEffect, useState } from 'react';
+import { Bot, Loader2, Save } from 'lucide-react';
+import clsx from 'clsx';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import type { JoinCheckResult, Server } from '../../context/ServerContext';
+
+interface JoinCheckCardProps {
+  server: Server;
+}
+
+interface JoinCheckSettings {
+  enabled: boolean;
+  player?: string;
+  last?: JoinCheckResult;
+}
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]';
+
+// A bot that logs in after each boot to prove the server still accepts
+// players, for example after a version or plugin upgrade.
+export const JoinCheckCard = ({ server }: JoinCheckCardProps) => {
+  const [enabled, setEnabled] = useState(false);
+  const [player, setPlayer] = useState('PanelCheck');
+  const [last, setLast] = useState<JoinCheckResult | undefined>();
+  const [saving, setSaving] = useState(false);
+  const [running, setRunning] = useState(false);
+
+  useEffect(() => {
+    apiRequest<JoinCheckSettings>(`/api/servers/${server.id}/join-check`, undefined, 'Failed to load join check')
+      .then((data) => {
+        setEnabled(data.enabled);
+        setPlayer(data.player || 'PanelCheck');
+        setLast(data.last);
+      })
+      .catch(() => {});
+  }, [server.id]);
+
+  const save = async () => {
+    setSaving(true);
+    try {
+      const data = await apiRequest<JoinCheckSettings>(`/api/servers/${server.id}/join-check`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ enabled, player }),
+      }, 'Failed to save join check');
+      setPlayer(data.player || 'PanelCheck');
+      toast.success(enabled ? 'Join check runs after each boot' : 'Join check disabled');
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save join check'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const runNow = async () => {
+    setRunning(true);
+    try {
+      const result = await apiRequest<JoinCheckResult>(`/api/servers/${server.id}/join-check`, { method: 'POST' }, 'Join check failed');
+      setLast(result);
+      if (result.ok) {
+        toast.success(result.message);
+      } else {
+        toast.error(result.message);
+      }
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Join check failed'));
+    } finally {
+      setRunning(false);
+    }
+  };
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center justify-between gap-2">
+        <div className="flex items-center gap-2">
+          <Bot size={14} className="text-gray-400" />
+          <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Join Check</h4>
+        </div>
+        <label className="flex items-center gap-1 text-[11px] text-gray-400">
+          <input type="checkbox" checked={enabled} onChange={(e) => setEnabled(e.target.checked)} className="accent-[#E5B80B]" />
+          After each boot
+        </label>
+      </div>
+      <p className="text-[11px] text-gray-500">A bot logs in and leaves right away. Offline-mode servers must let its name in; online-mode servers are checked up to authentication.</p>
+      <input type="text" value={player} onChange={(e) => setPlayer(e.target.value)} maxLength={16} className={inputClass} placeholder="Bot name" />
+      {last && (
+        <p className={clsx('text-[11px]', last.ok ? 'text-green-400' : 'text-red-400')}>
+          {last.ok ? 'Passed' : 'Failed'}: {last.message}
+          <span className="text-gray-500"> · {new Date(last.checkedAt).toLocaleString()}{last.version && ` · ${last.version}`}</span>
+        </p>
+      )}
+      <div className="grid grid-cols-2 gap-2">
+        <button
+          onClick={runNow}
+          disabled={running || server.status !== 'Running'}
+          className="py-2 border border-[#3a3a3a] text-gray-300 rounded text-xs hover:bg-[#333] flex items-center justify-center gap-1 disabled:opacity-50"
+        >
+          {running ? <Loader2 size={12} className="animate-spin" /> : <Bot size={12} />} Run now
+        </button>
+        <button
+          onClick={save}
+          disabled={saving}
+          className="py-2 bg-[#E5B80B] text-black rounded font-bold text-xs hover:bg-[#d4a90a] flex items-center justify-center gap-1 disabled:opacity-50"
+        >
+          <Save size={12} /> {saving ? 'Saving...' : 'Save'}
+        </button>
+      </div>
+    </div>
+  );
+};
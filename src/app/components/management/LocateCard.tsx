@@ -0,0 +1,72 @@
+import React, { useState } from 'react';
+import { Compass, Search } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import type { Server } from '../../context/ServerContext';
+
+interface LocateCardProps {
+  server: Server;
+}
+
+interface StructureLocation {
+  structure: string;
+  x: number;
+  y?: number;
+  z: number;
+  distance: number;
+}
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]';
+
+// Finds the structure nearest to a player, or to spawn, over RCON.
+export const LocateCard = ({ server }: LocateCardProps) => {
+  const [structure, setStructure] = useState('minecraft:village_plains');
+  const [player, setPlayer] = useState('');
+  const [result, setResult] = useState<StructureLocation | null>(null);
+  const [busy, setBusy] = useState(false);
+
+  if (server.type.toLowerCase() === 'velocity' || server.status !== 'Running') {
+    return null;
+  }
+
+  const locate = async () => {
+    setBusy(true);
+    setResult(null);
+    try {
+      setResult(await apiRequest<StructureLocation>(`/api/servers/${server.id}/locate`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ structure: structure.trim(), player: player.trim() || undefined }),
+      }, 'Failed to locate structure'));
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to locate structure'));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center gap-2">
+        <Compass size={14} className="text-gray-400" />
+        <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Locate Structure</h4>
+      </div>
+      <p className="text-[11px] text-gray-500">Needs RCON. Searches from the player if one is given, otherwise from spawn.</p>
+      <input value={structure} onChange={(e) => setStructure(e.target.value)} placeholder="minecraft:village_plains" className={inputClass} />
+      <input value={player} onChange={(e) => setPlayer(e.target.value)} placeholder="Near player (optional)" className={inputClass} />
+      {result && (
+        <p className="text-[11px] text-gray-300 font-mono">
+          {result.x}, {result.y ?? '~'}, {result.z} ({result.distance} blocks away)
+        </p>
+      )}
+      <button
+        onClick={locate}
+        disabled={busy || !structure.trim()}
+        className="w-full py-2 bg-[#E5B80B] text-black rounded font-bold text-xs hover:bg-[#d4a90a] flex items-center justify-center gap-1 disabled:opacity-50"
+      >
+        <Search size={12} /> {busy ? 'Searching...' : 'Locate'}
+      </button>
+    </div>
+  );
+};
@@ -0,0 +1,117 @@
+import React, { useEffect, useState } from 'react';
+import { Play, Wrench } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage, waitForJob, Job } from '../../lib/api';
+import type { Server } from '../../context/ServerContext';
+
+interface MaintenanceCardProps {
+  server: Server;
+}
+
+interface MaintenanceRoutine {
+  warningSeconds: number;
+  reason?: string;
+  backup: boolean;
+  updateJar: boolean;
+  updatePlugins: boolean;
+}
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]';
+
+const STEP_LABELS: { key: 'backup' | 'updateJar' | 'updatePlugins'; label: string }[] = [
+  { key: 'backup', label: 'Back up before stopping' },
+  { key: 'updateJar', label: 'Update the server jar to the latest build' },
+  { key: 'updatePlugins', label: 'Update outdated plugins' },
+];
+
+// Edits the server's maintenance routine (warn, back up, stop, update,
+// start, verify) and runs it on demand. Scheduled runs are tasks with the
+// maintenance action.
+export const MaintenanceCard = ({ server }: MaintenanceCardProps) => {
+  const [routine, setRoutine] = useState<MaintenanceRoutine | null>(null);
+  const [running, setRunning] = useState(false);
+  const [status, setStatus] = useState('');
+
+  useEffect(() => {
+    apiRequest<MaintenanceRoutine>(`/api/servers/${server.id}/maintenance`, undefined, 'Failed to load maintenance routine')
+      .then(setRoutine)
+      .catch(() => {});
+  }, [server.id]);
+
+  const save = async (next: MaintenanceRoutine) => {
+    setRoutine(next);
+    try {
+      const data = await apiRequest<MaintenanceRoutine>(`/api/servers/${server.id}/maintenance`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(next),
+      }, 'Failed to save maintenance routine');
+      setRoutine(data);
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save maintenance routine'));
+    }
+  };
+
+  const run = async () => {
+    setRunning(true);
+    try {
+      const job = await apiRequest<Job>(`/api/servers/${server.id}/maintenance/run`, { method: 'POST' }, 'Failed to start maintenance');
+      await waitForJob(job, (current) => setStatus(current.message || ''));
+      toast.success('Maintenance complete');
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Maintenance failed'));
+    } finally {
+      setRunning(false);
+      setStatus('');
+    }
+  };
+
+  if (!routine) {
+    return null;
+  }
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center gap-2">
+        <Wrench size={14} className="text-gray-400" />
+        <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Maintenance Routine</h4>
+      </div>
+      <p className="text-[11px] text-gray-500">
+        Warns players, stops, updates and starts the server again, stopping at the first step that fails. Schedule it with a maintenance task.
+      </p>
+      <label className="block text-[11px] text-gray-400">
+        Warning (seconds)
+        <input
+          type="number"
+          min={0}
+          max={3600}
+          value={routine.warningSeconds}
+          onChange={(e) => setRoutine({ ...routine, warningSeconds: Number(e.target.value) })}
+          onBlur={() => save(routine)}
+          className={inputClass}
+        />
+      </label>
+      <input
+        value={routine.reason || ''}
+        onChange={(e) => setRoutine({ ...routine, reason: e.target.value })}
+        onBlur={() => save(routine)}
+        placeholder="Reason (optional)"
+        className={inputClass}
+      />
+      {STEP_LABELS.map(({ key, label }) => (
+        <label key={key} className="flex items-center gap-2 text-xs text-white">
+          <input type="checkbox" checked={routine[key]} onChange={() => save({ ...routine, [key]: !routine[key] })} className="accent-[#E5B80B]" />
+          {label}
+        </label>
+      ))}
+      <button
+        onClick={run}
+        disabled={running}
+        className="w-full py-2 border border-[#3a3a3a] text-gray-300 rounded text-xs hover:bg-[#333] flex items-center justify-center gap-1 disabled:opacity-50"
+      >
+        <Play size={12} /> {running ? status || 'Running...' : 'Run maintenance now'}
+      </button>
+    </div>
+  );
+};
@@ -0,0 +1,142 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import { Network, Save } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import { useServer, type Server } from '../../context/ServerContext';
+
+interface NetworkBackendInfo {
+  name: string;
+  address: string;
+  serverId?: string;
+  serverName?: string;
+  status?: string;
+  forwarding: boolean;
+}
+
+interface NetworkInfo {
+  proxyId: string;
+  managed: boolean;
+  forwarding?: string;
+  try: string[];
+  backends: NetworkBackendInfo[];
+  error?: string;
+}
+
+interface NetworkCardProps {
+  server: Server;
+}
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]';
+
+const defaultName = (name: string) => name.toLowerCase().replace(/[^a-z0-9_-]+/g, '-').replace(/^-+|-+$/g, '').slice(0, 32);
+
+// Links a Velocity proxy to the panel's other servers: the panel writes
+// velocity.toml's [servers] table and sets up modern forwarding.
+export const NetworkCard = ({ server }: NetworkCardProps) => {
+  const { servers } = useServer();
+  const [network, setNetwork] = useState<NetworkInfo | null>(null);
+  const [names, setNames] = useState<Record<string, string>>({});
+  const [saving, setSaving] = useState(false);
+  const candidates = servers.filter((s) => s.type !== 'Velocity');
+
+  const load = useCallback(async () => {
+    try {
+      const networks = await apiRequest<NetworkInfo[]>('/api/networks', undefined, 'Failed to load network');
+      const current = networks.find((n) => n.proxyId === server.id) ?? null;
+      setNetwork(current);
+      const linked: Record<string, string> = {};
+      current?.backends.forEach((b) => {
+        if (b.serverId) linked[b.serverId] = b.name;
+      });
+      setNames(linked);
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to load network'));
+    }
+  }, [server.id]);
+
+  useEffect(() => {
+    load();
+  }, [load]);
+
+  const toggle = (s: Server, checked: boolean) => {
+    setNames((prev) => {
+      const next = { ...prev };
+      if (checked) next[s.id] = defaultName(s.name);
+      else delete next[s.id];
+      return next;
+    });
+  };
+
+  const save = async () => {
+    setSaving(true);
+    try {
+      const backends = candidates.filter((s) => s.id in names).map((s) => ({ serverId: s.id, name: names[s.id] }));
+      const tryOrder = (network?.try ?? []).filter((n) => backends.some((b) => b.name === n));
+      const result = await apiRequest<{ network: NetworkInfo; warnings: string[] }>(`/api/servers/${server.id}/network`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ backends, try: tryOrder }),
+      }, 'Failed to save network');
+      toast.success(backends.length > 0 ? 'Network updated' : 'Network unlinked');
+      result.warnings.forEach((w) => toast.warning(w));
+      setNetwork(result.network);
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save network'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const unmanaged = network?.backends.filter((b) => !b.serverId) ?? [];
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center gap-2">
+        <Network size={14} className="text-gray-400" />
+        <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Network</h4>
+      </div>
+      <p className="text-[11px] text-gray-500">
+        Backends this proxy routes to. Saving rewrites velocity.toml's servers and turns on modern forwarding; Paper, Purpur and Folia backends get the secret too.
+      </p>
+      {network?.error && <p className="text-[11px] text-red-400">{network.error}</p>}
+      <div className="space-y-1">
+        {candidates.map((s) => {
+          const linked = network?.backends.find((b) => b.serverId === s.id);
+          return (
+            <div key={s.id} className="flex items-center gap-2">
+              <input type="checkbox" checked={s.id in names} onChange={(e) => toggle(s, e.target.checked)} className="accent-[#E5B80B]" />
+              <span className="text-xs text-gray-300 w-28 truncate" title={`${s.name} (port ${s.port})`}>{s.name}</span>
+              {s.id in names && (
+                <input
+                  value={names[s.id]}
+                  onChange={(e) => setNames({ ...names, [s.id]: e.target.value })}
+                  className={inputClass}
+                  placeholder="name in velocity.toml"
+                />
+              )}
+              {linked && (
+                <span className={`text-[10px] whitespace-nowrap ${linked.forwarding ? 'text-green-400' : 'text-gray-500'}`}>
+                  {linked.forwarding ? 'forwarding' : 'no forwarding'}
+                </span>
+              )}
+            </div>
+          );
+        })}
+        {candidates.length === 0 && <p className="text-[11px] text-gray-500">No other servers to link.</p>}
+      </div>
+      {unmanaged.length > 0 && (
+        <p className="text-[11px] text-gray-500">
+          Also in velocity.toml: {unmanaged.map((b) => `${b.name} (${b.address})`).join(', ')}. Saving replaces them.
+        </p>
+      )}
+      <button
+        onClick={save}
+        disabled={saving}
+        className="w-full py-2 bg-[#E5B80B] text-black rounded font-bold text-xs hover:bg-[#d4a90a] flex items-center justify-center gap-1 disabled:opacity-50"
+      >
+        <Save size={12} /> {saving ? 'Saving...' : 'Save'}
+      </button>
+    </div>
+  );
+};
@@ -0,0 +1,134 @@
+import React, { useEffect, useState } from 'react';
+import { Loader2, X } from 'lucide-react';
+import { motion } from 'motion/react';
+import clsx from 'clsx';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import { useEscapeKey } from '../../hooks/useEscapeKey';
+
+interface InventoryItem {
+  slot: number;
+  id: string;
+  count: number;
+  name?: string;
+}
+
+interface PlayerDetails {
+  name: string;
+  uuid: string;
+  online: boolean;
+  source: 'live' | 'saved';
+  savedAt?: string;
+  dimension?: string;
+  position?: number[];
+  health: number;
+  food: number;
+  xpLevel: number;
+  xpProgress: number;
+  inventory: InventoryItem[];
+  enderChest: InventoryItem[];
+}
+
+interface PlayerInspectorProps {
+  serverId: string;
+  playerName: string;
+  onClose: () => void;
+}
+
+// Armor and offhand use these slots in the player's inventory list.
+const SPECIAL_SLOTS: Record<number, string> = { 100: 'Feet', 101: 'Legs', 102: 'Chest', 103: 'Head', [-106]: 'Offhand' };
+
+const itemLabel = (id: string) => id.replace(/^minecraft:/, '').replace(/_/g, ' ');
+
+const ItemGrid = ({ title, items }: { title: string; items: InventoryItem[] }) => (
+  <div>
+    <div className="text-[11px] uppercase tracking-wider text-gray-500 mb-1">{title}</div>
+    {items.length === 0 ? (
+      <div className="text-xs text-gray-500">Empty</div>
+    ) : (
+      <div className="grid grid-cols-1 sm:grid-cols-2 gap-1">
+        {items.map((item) => (
+          <div key={`${item.slot}-${item.id}`} className="flex items-center justify-between bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1 text-xs">
+            <span className="text-gray-300 truncate" title={item.name || item.id}>
+              <span className="text-gray-500 mr-2">{SPECIAL_SLOTS[item.slot] ?? item.slot}</span>
+              {itemLabel(item.id)}
+            </span>
+            <span className="text-white font-mono ml-2">×{item.count}</span>
+          </div>
+        ))}
+      </div>
+    )}
+  </div>
+);
+
+// Read-only view of a player's inventory, ender chest, position and XP.
+export const PlayerInspector = ({ serverId, playerName, onClose }: PlayerInspectorProps) => {
+  const [details, setDetails] = useState<PlayerDetails | null>(null);
+  const [error, setError] = useState<string | null>(null);
+
+  useEscapeKey(true, onClose);
+
+  useEffect(() => {
+    setDetails(null);
+    setError(null);
+    apiRequest<PlayerDetails>(
+      `/api/servers/${serverId}/players/${encodeURIComponent(playerName)}/inspect`,
+      undefined,
+      'Failed to load player data'
+    )
+      .then(setDetails)
+      .catch((err) => setError(toErrorMessage(err, 'Failed to load player data')));
+  }, [serverId, playerName]);
+
+  return (
+    <div className="fixed inset-0 z-50 flex items-center justify-center bg-black/60 backdrop-blur-sm p-4" onClick={onClose}>
+      <motion.div
+        initial={{ opacity: 0, scale: 0.95 }}
+        animate={{ opacity: 1, scale: 1 }}
+        className="w-full max-w-2xl max-h-[85vh] overflow-y-auto bg-[#252524] border border-[#E5B80B]/30 rounded-lg shadow-2xl p-6 space-y-4"
+        onClick={(e) => e.stopPropagation()}
+      >
+        <div className="flex items-center justify-between">
+          <h3 className="text-xl font-bold text-white">{playerName}</h3>
+          <button onClick={onClose} className="text-gray-500 hover:text-white transition-colors">
+            <X size={20} />
+          </button>
+        </div>
+        {error && <div className="text-sm text-red-400">{error}</div>}
+        {!details && !error && (
+          <div className="flex justify-center py-8">
+            <Loader2 size={24} className="animate-spin text-gray-500" />
+          </div>
+        )}
+        {details && (
+          <>
+            <div className={clsx('text-xs', details.source === 'live' ? 'text-green-400' : 'text-gray-500')}>
+              {details.source === 'live'
+                ? 'Live data'
+                : `Last saved ${details.savedAt ? new Date(details.savedAt).toLocaleString() : ''}${details.online ? ' (configure RCON for live data)' : ''}`}
+            </div>
+            <div className="grid grid-cols-2 sm:grid-cols-4 gap-2 text-xs">
+              <div className="bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5">
+                <div className="text-gray-500">Dimension</div>
+                <div className="text-white">{details.dimension ? itemLabel(details.dimension) : '-'}</div>
+              </div>
+              <div className="bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5">
+                <div className="text-gray-500">Position</div>
+                <div className="text-white font-mono">{details.position ? details.position.map((v) => Math.floor(v)).join(' ') : '-'}</div>
+              </div>
+              <div className="bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5">
+                <div className="text-gray-500">XP</div>
+                <div className="text-white">Level {details.xpLevel} ({Math.round(details.xpProgress * 100)}%)</div>
+              </div>
+              <div className="bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5">
+                <div className="text-gray-500">Health / Food</div>
+                <div className="text-white">{details.health.toFixed(1)} / {details.food}</div>
+              </div>
+            </div>
+            <ItemGrid title="Inventory" items={details.inventory} />
+            <ItemGrid title="Ender Chest" items={details.enderChest} />
+          </>
+        )}
+      </motion.div>
+    </div>
+  );
+};
@@ -1,16 +1,30 @@
 import React, { useState, useEffect, useCallback } from 'react';
 import { Server, Player } from '../../context/ServerContext';
-import { UserX, Ban, Skull, Search, Loader2, ChevronDown, ChevronUp } from 'lucide-react';
+import { UserX, Ban, Skull, Search, Loader2, ChevronDown, ChevronUp, Backpack, Timer, ArrowRightLeft } from 'lucide-react';
 import { toast } from 'sonner';
 import clsx from 'clsx';
 import { AnimatePresence, motion } from 'motion/react';
 import { Tooltip, TooltipTrigger, TooltipContent } from '../ui/tooltip';
 import { apiRequest, toErrorMessage } from '../../lib/api';
+import { PlayerInspector } from './PlayerInspector';
 
 interface PlayerListProps {
   server: Server;
 }
 
+interface ModerationPreset {
+  id: string;
+  reason: string;
+}
+
+interface ProxyBackends {
+  proxyId: string;
+  proxyName: string;
+  servers: string[];
+}
+
+const formatPosition = (position: number[]) => position.map((v) => Math.floor(v)).join(', ');
+
 export const PlayerList = ({ server }: PlayerListProps) => {
   const [players, setPlayers] = useState<Player[]>([]);
   const [loading, setLoading] = useState(true);
@@ -20,6 +34,10 @@ export const PlayerList = ({ server }: PlayerListProps) => {
   const [dataStale, setDataStale] = useState(false);
   const [openPlayerName, setOpenPlayerName] = useState<string | null>(null);
   const [mobileMenuView, setMobileMenuView] = useState<'details' | 'actions'>('details');
+  const [inspectedPlayer, setInspectedPlayer] = useState<string | null>(null);
+  const [presets, setPresets] = useState<ModerationPreset[]>([]);
+  const [presetId, setPresetId] = useState('');
+  const [proxy, setProxy] = useState<ProxyBackends | null>(null);
 
   useEffect(() => {
     let mounted = true;
@@ -47,6 +65,35 @@ export const PlayerList = ({ server }: PlayerListProps) => {
     };
   }, []);
 
+  useEffect(() => {
+    let mounted = true;
+    apiRequest<ModerationPreset[]>('/api/moderation/presets', undefined, 'Failed to load reason presets')
+      .then((data) => {
+        if (mounted) setPresets(data);
+      })
+      .catch(() => {
+        // Kicks and bans still work without a reason.
+      });
+    return () => {
+      mounted = false;
+    };
+  }, []);
+
+  useEffect(() => {
+    let mounted = true;
+    apiRequest<ProxyBackends>(`/api/servers/${server.id}/proxy-backends`, undefined, 'Failed to load proxy servers')
+      .then((data) => {
+        if (mounted) setProxy(data);
+      })
+      .catch(() => {
+        // Not behind a managed proxy: players cannot be sent elsewhere.
+        if (mounted) setProxy(null);
+      });
+    return () => {
+      mounted = false;
+    };
+  }, [server.id]);
+
   const fetchPlayers = useCallback(async () => {
     try {
       const data = await apiRequest<{ players?: Player[]; pingSupported?: boolean; pingStatus?: string; dataStale?: boolean } | Player[]>(
@@ -126,25 +173,54 @@ export const PlayerList = ({ server }: PlayerListProps) => {
     }
   }, [filteredPlayers, openPlayerName]);
 
-  const handleAction = async (playerName: string, action: 'kick' | 'ban' | 'kill') => {
+  const handleAction = async (playerName: string, action: 'kick' | 'ban' | 'kill', body: Record<string, string> = {}) => {
+    if (presetId && action !== 'kill') {
+      body = { ...body, preset: presetId };
+    }
     try {
       await apiRequest(
         `/api/servers/${server.id}/players/${encodeURIComponent(playerName)}/${action}`,
         {
           method: 'POST',
           headers: { 'Content-Type': 'application/json' },
-          body: JSON.stringify({}),
+          body: JSON.stringify(body),
         },
         `Failed to ${action} player`
       );
       const labels = { kick: 'Kicked', ban: 'Banned', kill: 'Killed' };
-      toast.success(`${labels[action]} ${playerName}`);
+      toast.success(`${labels[action]} ${playerName}${body.duration ? ` for ${body.duration}` : ''}`);
       setTimeout(fetchPlayers, 1000);
     } catch (err) {
       toast.error(toErrorMessage(err, `Failed to ${action} player`));
     }
   };
 
+  // Temporary bans are lifted by the panel once they expire.
+  const handleTempBan = (playerName: string) => {
+    const duration = window.prompt(`Ban ${playerName} for how long? For example 30m, 12h, 3d or 2w.`, '1d');
+    if (!duration?.trim()) return;
+    handleAction(playerName, 'ban', { duration: duration.trim() });
+  };
+
+  // Velocity moves the player; the backend's list drops them on the next sync.
+  const handleSend = async (playerName: string) => {
+    if (!proxy) return;
+    const others = proxy.servers.filter((name) => name !== server.name);
+    const target = window.prompt(`Send ${playerName} to which server on ${proxy.proxyName}? (${proxy.servers.join(', ')})`, others[0] || '');
+    if (!target?.trim()) return;
+    try {
+      await apiRequest(
+        `/api/servers/${server.id}/players/${encodeURIComponent(playerName)}/send?target=${encodeURIComponent(target.trim())}`,
+        { method: 'POST' },
+        'Failed to send player'
+      );
+      toast.success(`Sent ${playerName} to ${target.trim()}`);
+      setTimeout(fetchPlayers, 1000);
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to send player'));
+    }
+  };
+
   const handleMobileToggle = (playerName: string) => {
     if (openPlayerName === playerName) {
       setOpenPlayerName(null);
@@ -230,6 +306,19 @@ export const PlayerList = ({ server }: PlayerListProps) => {
             className="w-full bg-[#252524] border border-[#3a3a3a] rounded-full py-2 pl-9 pr-4 text-sm text-white focus:outline-none focus:border-[#E5B80B]"
           />
         </div>
+        {presets.length > 0 && (
+          <select
+            value={presetId}
+            onChange={(e) => setPresetId(e.target.value)}
+            title="Reason used for kicks and bans"
+            className="w-full md:w-56 bg-[#252524] border border-[#3a3a3a] rounded-full py-2 px-4 text-sm text-white focus:outline-none focus:border-[#E5B80B]"
+          >
+            <option value="">No reason</option>
+            {presets.map((preset) => (
+              <option key={preset.id} value={preset.id}>{preset.reason}</option>
+            ))}
+          </select>
+        )}
         <div className="text-sm text-gray-400">
           Online: <span className="text-white font-bold">{players.length}</span>
           <span className="text-gray-500">/{server.maxPlayers}</span>
@@ -314,10 +403,16 @@ export const PlayerList = ({ server }: PlayerListProps) => {
                       )}
                     </td>
                     <td className="px-4 py-3 text-gray-300 hidden sm:table-cell">{player.onlineTime}</td>
-                    <td className="px-4 py-3 text-gray-300 hidden lg:table-cell">{player.world || '-'}</td>
+                    <td className="px-4 py-3 text-gray-300 hidden lg:table-cell">
+                      <div>{player.world || '-'}</div>
+                      {player.position && <div className="text-[11px] text-gray-500 font-mono">{formatPosition(player.position)}</div>}
+                    </td>
                     <td className="px-4 py-3 text-right">
                       <div className="flex items-center justify-end gap-1 opacity-0 group-hover:opacity-100 transition-opacity">
+                        <ActionBtn icon={Backpack} label="Inspect" color="hover:bg-[#E5B80B]/10 hover:text-[#E5B80B]" onClick={() => setInspectedPlayer(player.name)} />
+                        {proxy && <ActionBtn icon={ArrowRightLeft} label="Send to server" color="hover:bg-blue-900/40 hover:text-blue-400" onClick={() => handleSend(player.name)} />}
                         <ActionBtn icon={UserX} label="Kick" color="hover:bg-yellow-900/40 hover:text-yellow-500" onClick={() => handleAction(player.name, 'kick')} />
+                        <ActionBtn icon={Timer} label="Temp ban" color="hover:bg-orange-900/40 hover:text-orange-400" onClick={() => handleTempBan(player.name)} />
                         <ActionBtn icon={Ban} label="Ban" color="hover:bg-red-900/40 hover:text-red-500" onClick={() => handleAction(player.name, 'ban')} />
                         <ActionBtn icon={Skull} label="Kill" color="hover:bg-gray-700 hover:text-gray-300" onClick={() => handleAction(player.name, 'kill')} />
                       </div>
@@ -385,6 +480,7 @@ export const PlayerList = ({ server }: PlayerListProps) => {
                             />
                             <MobileInfoRow label="Online Time" value={player.onlineTime || '-'} />
                             <MobileInfoRow label="Current World" value={player.world || '-'} />
+                            <MobileInfoRow label="Coordinates" value={player.position ? formatPosition(player.position) : '-'} />
                             <button
                               type="button"
                               onClick={() => setMobileMenuView('actions')}
@@ -395,6 +491,22 @@ export const PlayerList = ({ server }: PlayerListProps) => {
                           </div>
                         ) : (
                           <div className="px-3 py-2.5 space-y-2">
+                            <button
+                              type="button"
+                              onClick={() => { setInspectedPlayer(player.name); setOpenPlayerName(null); setMobileMenuView('details'); }}
+                              className="w-full px-3 py-2 rounded border border-[#3a3a3a] bg-[#1f1f1f] text-gray-200 text-left hover:border-[#E5B80B]/60 transition-colors"
+                            >
+                              Inspect
+                            </button>
+                            {proxy && (
+                              <button
+                                type="button"
+                                onClick={() => { handleSend(player.name); setOpenPlayerName(null); setMobileMenuView('details'); }}
+                                className="w-full px-3 py-2 rounded border border-blue-700/40 bg-blue-900/15 text-blue-300 text-left hover:bg-blue-900/30 transition-colors"
+                              >
+                                Send to server
+                              </button>
+                            )}
                             <button
                               type="button"
                               onClick={() => handleMobileAction(player.name, 'kick')}
@@ -402,6 +514,13 @@ export const PlayerList = ({ server }: PlayerListProps) => {
                             >
                               Kick
                             </button>
+                            <button
+                              type="button"
+                              onClick={() => { handleTempBan(player.name); setOpenPlayerName(null); setMobileMenuView('details'); }}
+                              className="w-full px-3 py-2 rounded border border-orange-700/40 bg-orange-900/15 text-orange-300 text-left hover:bg-orange-900/30 transition-colors"
+                            >
+                              Temp ban
+                            </button>
                             <button
                               type="button"
                               onClick={() => handleMobileAction(player.name, 'ban')}
@@ -437,6 +556,9 @@ export const PlayerList = ({ server }: PlayerListProps) => {
           )}
         </div>
       )}
+      {inspectedPlayer && (
+        <PlayerInspector serverId={server.id} playerName={inspectedPlayer} onClose={() => setInspectedPlayer(null)} />
+      )}
     </motion.div>
   );
 };
@@ -0,0 +1,96 @@
+import React, { useEffect, useState } from 'react';
+import { Save, Terminal } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import type { Server } from '../../context/ServerContext';
+
+interface RconCardProps {
+  server: Server;
+  onSaved: () => Promise<void>;
+}
+
+// RCON settings the panel uses to send commands when it has no stdin for
+// the server, for example after the panel restarted.
+export const RconCard = ({ server, onSaved }: RconCardProps) => {
+  const [port, setPort] = useState('');
+  const [password, setPassword] = useState('');
+  const [saving, setSaving] = useState(false);
+
+  useEffect(() => {
+    setPort(server.rconPort ? String(server.rconPort) : '');
+    setPassword('');
+  }, [server.id, server.rconPort]);
+
+  if (server.type.toLowerCase() === 'velocity') {
+    return null;
+  }
+
+  const portValue = port.trim() === '' ? 0 : Number(port);
+  const changed = portValue !== (server.rconPort ?? 0) || password !== '';
+
+  const handleSave = async () => {
+    setSaving(true);
+    try {
+      await apiRequest(
+        `/api/servers/${server.id}/rcon`,
+        {
+          method: 'PUT',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({ port: portValue, password }),
+        },
+        'Failed to save RCON settings'
+      );
+      toast.success(portValue ? 'RCON enabled. Restart the server to apply it.' : 'RCON disabled');
+      await onSaved();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save RCON settings'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center gap-2">
+        <Terminal size={14} className="text-gray-400" />
+        <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">RCON</h4>
+      </div>
+      <p className="text-[11px] text-gray-500">
+        Used for commands when the panel has no console input, such as after a panel restart. Leave the port empty to turn it off.
+      </p>
+      <div className="grid grid-cols-2 gap-2">
+        <div>
+          <label className="block text-[11px] text-gray-500 mb-1">Port</label>
+          <input
+            value={port}
+            onChange={(e) => setPort(e.target.value.replace(/[^0-9]/g, ''))}
+            placeholder="25575"
+            inputMode="numeric"
+            className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]"
+          />
+        </div>
+        <div>
+          <label className="block text-[11px] text-gray-500 mb-1">Password</label>
+          <input
+            type="password"
+            value={password}
+            onChange={(e) => setPassword(e.target.value)}
+            maxLength={128}
+            autoComplete="new-password"
+            className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]"
+          />
+        </div>
+      </div>
+      {changed && (
+        <button
+          onClick={handleSave}
+          disabled={saving || (portValue !== 0 && password === '')}
+          className="w-full py-2 bg-[#E5B80B] text-black rounded font-bold text-sm hover:bg-[#d4a90a] transition-colors flex items-center justify-center gap-2 disabled:opacity-50"
+        >
+          <Save size={14} />
+          {saving ? 'Saving...' : 'Save RCON'}
+        </button>
+      )}
+    </div>
+  );
+};
@@ -0,0 +1,157 @@
+import React, { useEffect, useState } from 'react';
+import { Eye, Map as MapIcon, Save, Scissors } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import type { Server } from '../../context/ServerContext';
+
+interface RegionPruneCardProps {
+  server: Server;
+}
+
+interface RegionPruneSettings {
+  maxAgeDays: number;
+  spawnRadius: number;
+  centerX: number;
+  centerZ: number;
+  schedule?: string;
+  lastRun?: string;
+  nextRun?: string;
+}
+
+interface RegionPrunePlan {
+  files: { path: string; size: number }[];
+  regions: number;
+  regionsScanned: number;
+  totalSize: string;
+  backup?: { name: string };
+}
+
+const SCHEDULES: { value: string; label: string }[] = [
+  { value: '', label: 'Manual only' },
+  { value: 'weekly', label: 'Weekly' },
+  { value: 'monthly', label: 'Monthly' },
+  { value: 'sixmonths', label: 'Every 6 months' },
+  { value: 'yearly', label: 'Yearly' },
+];
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]';
+
+// Deletes region files nobody has visited in a while, outside a kept area
+// around spawn. The panel backs the server up before every prune.
+export const RegionPruneCard = ({ server }: RegionPruneCardProps) => {
+  const [maxAgeDays, setMaxAgeDays] = useState('90');
+  const [spawnRadius, setSpawnRadius] = useState('2048');
+  const [schedule, setSchedule] = useState('');
+  const [nextRun, setNextRun] = useState<string | undefined>();
+  const [plan, setPlan] = useState<RegionPrunePlan | null>(null);
+  const [busy, setBusy] = useState<string | null>(null);
+
+  useEffect(() => {
+    setPlan(null);
+    apiRequest<RegionPruneSettings>(`/api/servers/${server.id}/region-prune`, undefined, 'Failed to load prune settings')
+      .then((data) => {
+        if (data.maxAgeDays) {
+          setMaxAgeDays(String(data.maxAgeDays));
+          setSpawnRadius(String(data.spawnRadius));
+        }
+        setSchedule(data.schedule || '');
+        setNextRun(data.nextRun);
+      })
+      .catch(() => {});
+  }, [server.id]);
+
+  if (server.type.toLowerCase() === 'velocity') {
+    return null;
+  }
+
+  const body = () => JSON.stringify({
+    maxAgeDays: Number(maxAgeDays) || 0,
+    spawnRadius: Number(spawnRadius) || 0,
+    centerX: 0,
+    centerZ: 0,
+    schedule,
+  });
+
+  const run = async (kind: 'save' | 'preview' | 'prune') => {
+    if (kind === 'prune' && !window.confirm('Prune old regions now? The server must be stopped; a backup is taken first.')) return;
+    setBusy(kind);
+    try {
+      const init = { headers: { 'Content-Type': 'application/json' }, body: body() };
+      if (kind === 'save') {
+        const data = await apiRequest<RegionPruneSettings>(`/api/servers/${server.id}/region-prune`, { ...init, method: 'PUT' }, 'Failed to save prune settings');
+        setNextRun(data.nextRun);
+        toast.success(schedule ? 'Region prune scheduled' : 'Region prune settings saved');
+      } else if (kind === 'preview') {
+        setPlan(await apiRequest<RegionPrunePlan>(`/api/servers/${server.id}/region-prune/preview`, { ...init, method: 'POST' }, 'Failed to preview prune'));
+      } else {
+        const result = await apiRequest<RegionPrunePlan>(`/api/servers/${server.id}/region-prune`, { ...init, method: 'POST' }, 'Failed to prune regions');
+        setPlan(null);
+        toast.success(`Pruned ${result.regions} regions, freed ${result.totalSize}`);
+      }
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Region prune failed'));
+    } finally {
+      setBusy(null);
+    }
+  };
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center gap-2">
+        <MapIcon size={14} className="text-gray-400" />
+        <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Region Pruning</h4>
+      </div>
+      <p className="text-[11px] text-gray-500">
+        Deletes regions untouched for the given days outside the spawn radius. A backup is taken first and the server must be stopped.
+      </p>
+      <div className="grid grid-cols-2 gap-2">
+        <div>
+          <label className="block text-[11px] text-gray-500 mb-1">Older than (days)</label>
+          <input value={maxAgeDays} onChange={(e) => setMaxAgeDays(e.target.value.replace(/[^0-9]/g, ''))} inputMode="numeric" className={inputClass} />
+        </div>
+        <div>
+          <label className="block text-[11px] text-gray-500 mb-1">Keep radius (blocks)</label>
+          <input value={spawnRadius} onChange={(e) => setSpawnRadius(e.target.value.replace(/[^0-9]/g, ''))} inputMode="numeric" className={inputClass} />
+        </div>
+      </div>
+      <div>
+        <label className="block text-[11px] text-gray-500 mb-1">Schedule</label>
+        <select value={schedule} onChange={(e) => setSchedule(e.target.value)} className={inputClass}>
+          {SCHEDULES.map((opt) => (
+            <option key={opt.value} value={opt.value}>{opt.label}</option>
+          ))}
+        </select>
+        {schedule && nextRun && <p className="text-[11px] text-gray-500 mt-1">Next run: {new Date(nextRun).toLocaleString()}</p>}
+      </div>
+      {plan && (
+        <p className="text-[11px] text-gray-400">
+          {plan.regions} of {plan.regionsScanned} regions ({plan.files.length} files, {plan.totalSize}) would be deleted.
+        </p>
+      )}
+      <div className="grid grid-cols-3 gap-2">
+        <button
+          onClick={() => run('save')}
+          disabled={busy !== null}
+          className="py-2 border border-[#3a3a3a] text-gray-300 rounded text-xs hover:bg-[#333] flex items-center justify-center gap-1 disabled:opacity-50"
+        >
+          <Save size={12} /> Save
+        </button>
+        <button
+          onClick={() => run('preview')}
+          disabled={busy !== null}
+          className="py-2 border border-[#3a3a3a] text-gray-300 rounded text-xs hover:bg-[#333] flex items-center justify-center gap-1 disabled:opacity-50"
+        >
+          <Eye size={12} /> {busy === 'preview' ? 'Scanning...' : 'Preview'}
+        </button>
+        <button
+          onClick={() => run('prune')}
+          disabled={busy !== null}
+          className="py-2 bg-[#E5B80B] text-black rounded font-bold text-xs hover:bg-[#d4a90a] flex items-center justify-center gap-1 disabled:opacity-50"
+        >
+          <Scissors size={12} /> {busy === 'prune' ? 'Pruning...' : 'Prune'}
+        </button>
+      </div>
+    </div>
+  );
+};
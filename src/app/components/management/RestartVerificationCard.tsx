@@ -0,0 +1,96 @@
+import React, { useEffect, useState } from 'react';
+import { Save, ShieldCheck } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import { useServer, type Server } from '../../context/ServerContext';
+
+interface RestartVerificationCardProps {
+  server: Server;
+}
+
+interface RestartVerificationSettings {
+  enabled: boolean;
+  minTps: number;
+  rollback: boolean;
+}
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]';
+
+// Watches the server for five minutes after scheduled restarts, crash
+// restarts and maintenance runs, and rolls a failed update back.
+export const RestartVerificationCard = ({ server }: RestartVerificationCardProps) => {
+  const { refreshServers } = useServer();
+  const [settings, setSettings] = useState<RestartVerificationSettings | null>(null);
+  const [saving, setSaving] = useState(false);
+
+  useEffect(() => {
+    apiRequest<RestartVerificationSettings>(`/api/servers/${server.id}/restart-verification`, undefined, 'Failed to load restart verification')
+      .then(setSettings)
+      .catch(() => {});
+  }, [server.id]);
+
+  const save = async () => {
+    if (!settings) return;
+    setSaving(true);
+    try {
+      const data = await apiRequest<RestartVerificationSettings>(`/api/servers/${server.id}/restart-verification`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(settings),
+      }, 'Failed to save restart verification');
+      setSettings(data);
+      toast.success(data.enabled ? 'Restarts will be verified' : 'Restart verification disabled');
+      await refreshServers();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save restart verification'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  if (!settings) {
+    return null;
+  }
+
+  const last = server.restartVerification;
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center justify-between gap-2">
+        <div className="flex items-center gap-2">
+          <ShieldCheck size={14} className="text-gray-400" />
+          <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Restart Verification</h4>
+        </div>
+        <label className="flex items-center gap-1 text-[11px] text-gray-400">
+          <input type="checkbox" checked={settings.enabled} onChange={(e) => setSettings({ ...settings, enabled: e.target.checked })} className="accent-[#E5B80B]" />
+          Enabled
+        </label>
+      </div>
+      <p className="text-[11px] text-gray-500">
+        After an automated restart or update the server must reach Running, hold its TPS and write no crash report for 5 minutes.
+      </p>
+      <label className="block text-[11px] text-gray-500 space-y-1">
+        <span>Minimum TPS</span>
+        <input type="number" min={0} max={20} step={0.5} value={settings.minTps} onChange={(e) => setSettings({ ...settings, minTps: Number(e.target.value) })} className={inputClass} />
+      </label>
+      <label className="flex items-center gap-2 text-xs text-white">
+        <input type="checkbox" checked={settings.rollback} onChange={(e) => setSettings({ ...settings, rollback: e.target.checked })} className="accent-[#E5B80B]" />
+        Roll back the jar and plugins when a maintenance update fails
+      </label>
+      {last && (
+        <p className={`text-[11px] ${last.ok ? 'text-green-400' : 'text-red-400'}`}>
+          {last.message}
+          <span className="text-gray-500"> · {new Date(last.checkedAt).toLocaleString()}</span>
+        </p>
+      )}
+      <button
+        onClick={save}
+        disabled={saving}
+        className="w-full py-2 bg-[#E5B80B] text-black rounded font-bold text-xs hover:bg-[#d4a90a] flex items-center justify-center gap-1 disabled:opacity-50"
+      >
+        <Save size={12} /> {saving ? 'Saving...' : 'Save'}
+      </button>
+    </div>
+  );
+};
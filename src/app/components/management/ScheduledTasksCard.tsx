@@ -0,0 +1,205 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import { Clock, Play, Plus, Trash2 } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import { useServer, type Server } from '../../context/ServerContext';
+
+interface ScheduledTasksCardProps {
+  server: Server;
+}
+
+type TaskAction = 'command' | 'broadcast' | 'restart' | 'backup' | 'maintenance' | 'refresh-staging';
+
+interface ScheduledTask {
+  id: string;
+  name?: string;
+  schedule: string;
+  action: TaskAction;
+  enabled: boolean;
+  command?: string;
+  message?: string;
+  delaySeconds?: number;
+  sourceId?: string;
+  nextRun?: string;
+  lastRun?: string;
+  lastResult?: string;
+}
+
+const ACTION_LABELS: Record<TaskAction, string> = {
+  command: 'Console command',
+  broadcast: 'Broadcast',
+  restart: 'Restart',
+  backup: 'Backup',
+  maintenance: 'Maintenance routine',
+  'refresh-staging': 'Refresh from production',
+};
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]';
+
+const describeTask = (task: ScheduledTask, servers: Server[]) => {
+  switch (task.action) {
+    case 'command':
+      return `/${task.command}`;
+    case 'broadcast':
+      return `say ${task.message}`;
+    case 'restart':
+      return task.delaySeconds ? `Restart after ${task.delaySeconds}s of warnings` : 'Restart now';
+    case 'maintenance':
+      return 'Maintenance routine';
+    case 'refresh-staging':
+      return `Overwrite with the latest backup of ${servers.find((s) => s.id === task.sourceId)?.name ?? task.sourceId}`;
+    default:
+      return 'Backup';
+  }
+};
+
+// Runs console commands, broadcasts, restarts, backups, maintenance and
+// staging refreshes on cron schedules in the panel's local time.
+export const ScheduledTasksCard = ({ server }: ScheduledTasksCardProps) => {
+  const { servers } = useServer();
+  const sources = servers.filter((s) => s.id !== server.id && s.type !== 'Velocity');
+  const [tasks, setTasks] = useState<ScheduledTask[]>([]);
+  const [sourceId, setSourceId] = useState('');
+  const [schedule, setSchedule] = useState('0 4 * * *');
+  const [action, setAction] = useState<TaskAction>('broadcast');
+  const [text, setText] = useState('');
+  const [saving, setSaving] = useState(false);
+
+  const load = useCallback(() => {
+    apiRequest<ScheduledTask[]>(`/api/servers/${server.id}/tasks`, undefined, 'Failed to load scheduled tasks')
+      .then(setTasks)
+      .catch(() => {});
+  }, [server.id]);
+
+  useEffect(() => {
+    load();
+  }, [load]);
+
+  const saveTask = async (task: Partial<ScheduledTask>, id?: string) => {
+    const data = await apiRequest<ScheduledTask>(id ? `/api/servers/${server.id}/tasks/${id}` : `/api/servers/${server.id}/tasks`, {
+      method: id ? 'PUT' : 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify(task),
+    }, 'Failed to save task');
+    setTasks((prev) => (id ? prev.map((t) => (t.id === id ? data : t)) : [...prev, data]));
+  };
+
+  const add = async () => {
+    setSaving(true);
+    try {
+      await saveTask({
+        schedule,
+        action,
+        enabled: true,
+        command: action === 'command' ? text : undefined,
+        message: action === 'broadcast' || action === 'restart' ? text : undefined,
+        sourceId: action === 'refresh-staging' ? sourceId : undefined,
+      });
+      setText('');
+      toast.success('Task added');
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to add task'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const toggle = async (task: ScheduledTask) => {
+    try {
+      await saveTask({ ...task, enabled: !task.enabled }, task.id);
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to update task'));
+    }
+  };
+
+  const runNow = async (task: ScheduledTask) => {
+    try {
+      const data = await apiRequest<ScheduledTask>(`/api/servers/${server.id}/tasks/${task.id}/run`, { method: 'POST' }, 'Failed to run task');
+      setTasks((prev) => prev.map((t) => (t.id === task.id ? data : t)));
+      toast.success('Task ran');
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to run task'));
+      load();
+    }
+  };
+
+  const remove = async (task: ScheduledTask) => {
+    try {
+      await apiRequest(`/api/servers/${server.id}/tasks/${task.id}`, { method: 'DELETE' }, 'Failed to delete task');
+      setTasks((prev) => prev.filter((t) => t.id !== task.id));
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to delete task'));
+    }
+  };
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center gap-2">
+        <Clock size={14} className="text-gray-400" />
+        <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Scheduled Tasks</h4>
+      </div>
+      <p className="text-[11px] text-gray-500">Cron schedules (minute hour day month weekday) in the panel's local time.</p>
+      {tasks.map((task) => (
+        <div key={task.id} className="bg-[#1a1a1a] border border-[#3a3a3a] rounded p-2 space-y-1">
+          <div className="flex items-center justify-between gap-2">
+            <label className="flex items-center gap-1 text-xs text-white min-w-0">
+              <input type="checkbox" checked={task.enabled} onChange={() => toggle(task)} className="accent-[#E5B80B]" />
+              <span className="font-mono">{task.schedule}</span>
+              <span className="text-gray-400 truncate">{task.name || ACTION_LABELS[task.action]}</span>
+            </label>
+            <div className="flex items-center gap-2 shrink-0">
+              <button onClick={() => runNow(task)} className="text-gray-500 hover:text-[#E5B80B]" title="Run now">
+                <Play size={12} />
+              </button>
+              <button onClick={() => remove(task)} className="text-gray-500 hover:text-red-400" title="Delete task">
+                <Trash2 size={12} />
+              </button>
+            </div>
+          </div>
+          <p className="text-[11px] text-gray-400 truncate">{describeTask(task, servers)}</p>
+          <p className="text-[11px] text-gray-500">
+            {task.nextRun ? `Next: ${new Date(task.nextRun).toLocaleString()}` : 'Paused'}
+            {task.lastRun && ` · Last: ${new Date(task.lastRun).toLocaleString()} (${task.lastResult})`}
+          </p>
+        </div>
+      ))}
+      <div className="grid grid-cols-2 gap-2">
+        <input value={schedule} onChange={(e) => setSchedule(e.target.value)} placeholder="55 3 * * *" className={`${inputClass} font-mono`} />
+        <select value={action} onChange={(e) => setAction(e.target.value as TaskAction)} className={inputClass}>
+          {(Object.keys(ACTION_LABELS) as TaskAction[]).map((a) => (
+            <option key={a} value={a}>{ACTION_LABELS[a]}</option>
+          ))}
+        </select>
+      </div>
+      {action === 'refresh-staging' && (
+        <>
+          <select value={sourceId} onChange={(e) => setSourceId(e.target.value)} className={inputClass}>
+            <option value="">Production server...</option>
+            {sources.map((s) => (
+              <option key={s.id} value={s.id}>{s.name}</option>
+            ))}
+          </select>
+          <p className="text-[11px] text-gray-500">
+            Replaces this server's files with the production server's latest backup, then keeps this server's port and turns on the whitelist, turns off query and Velocity forwarding.
+          </p>
+        </>
+      )}
+      {action !== 'backup' && action !== 'maintenance' && action !== 'refresh-staging' && (
+        <input
+          value={text}
+          onChange={(e) => setText(e.target.value)}
+          placeholder={action === 'command' ? 'save-all' : action === 'restart' ? 'Reason (optional)' : 'Restart in 5 minutes'}
+          className={inputClass}
+        />
+      )}
+      <button
+        onClick={add}
+        disabled={saving}
+        className="w-full py-2 border border-[#3a3a3a] text-gray-300 rounded text-xs hover:bg-[#333] flex items-center justify-center gap-1 disabled:opacity-50"
+      >
+        <Plus size={12} /> {saving ? 'Adding...' : 'Add task'}
+      </button>
+    </div>
+  );
+};
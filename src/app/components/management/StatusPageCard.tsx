@@ -0,0 +1,52 @@
+import React, { useState } from 'react';
+import { Globe } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import { useServer, type Server } from '../../context/ServerContext';
+
+interface StatusPageCardProps {
+  server: Server;
+}
+
+// Lists the server on the public, read-only status page at /status.
+export const StatusPageCard = ({ server }: StatusPageCardProps) => {
+  const { refreshServers } = useServer();
+  const [saving, setSaving] = useState(false);
+  const enabled = server.showOnStatusPage ?? false;
+
+  const toggle = async () => {
+    setSaving(true);
+    try {
+      await apiRequest(`/api/servers/${server.id}/status-page`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ enabled: !enabled }),
+      }, 'Failed to update status page');
+      toast.success(enabled ? 'Removed from the status page' : 'Shown on the status page');
+      await refreshServers();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to update status page'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center justify-between gap-2">
+        <div className="flex items-center gap-2">
+          <Globe size={14} className="text-gray-400" />
+          <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Status Page</h4>
+        </div>
+        <label className="flex items-center gap-1 text-[11px] text-gray-400">
+          <input type="checkbox" checked={enabled} disabled={saving} onChange={toggle} className="accent-[#E5B80B]" />
+          Show on status page
+        </label>
+      </div>
+      <p className="text-[11px] text-gray-500">
+        Anyone can see the MOTD, version, online players and uptime at{' '}
+        <a href="/status" target="_blank" rel="noreferrer" className="text-[#E5B80B] hover:underline">/status</a>.
+      </p>
+    </div>
+  );
+};
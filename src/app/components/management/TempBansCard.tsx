@@ -0,0 +1,78 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import { Timer, X } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import type { Server } from '../../context/ServerContext';
+
+interface TempBansCardProps {
+  server: Server;
+}
+
+interface TempBan {
+  player: string;
+  reason?: string;
+  bannedAt: string;
+  expiresAt: string;
+}
+
+// Lists temporary bans the panel will lift when they expire.
+export const TempBansCard = ({ server }: TempBansCardProps) => {
+  const [bans, setBans] = useState<TempBan[]>([]);
+  const [lifting, setLifting] = useState<string | null>(null);
+
+  const load = useCallback(() => {
+    apiRequest<TempBan[]>(`/api/servers/${server.id}/tempbans`, undefined, 'Failed to load temporary bans')
+      .then(setBans)
+      .catch(() => {});
+  }, [server.id]);
+
+  useEffect(() => {
+    load();
+    const interval = setInterval(load, 30000);
+    return () => clearInterval(interval);
+  }, [load]);
+
+  if (server.type.toLowerCase() === 'velocity' || bans.length === 0) {
+    return null;
+  }
+
+  const lift = async (player: string) => {
+    setLifting(player);
+    try {
+      await apiRequest(`/api/servers/${server.id}/tempbans/${encodeURIComponent(player)}`, { method: 'DELETE' }, 'Failed to lift ban');
+      toast.success(`Pardoned ${player}`);
+      load();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to lift ban'));
+    } finally {
+      setLifting(null);
+    }
+  };
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center gap-2">
+        <Timer size={14} className="text-gray-400" />
+        <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Temporary Bans</h4>
+      </div>
+      <div className="space-y-1">
+        {bans.map((ban) => (
+          <div key={ban.player} className="flex items-center justify-between gap-2 text-[11px] bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1">
+            <div className="min-w-0">
+              <div className="text-white truncate">{ban.player}</div>
+              <div className="text-gray-500 truncate">Until {new Date(ban.expiresAt).toLocaleString()}{ban.reason ? ` · ${ban.reason}` : ''}</div>
+            </div>
+            <button
+              onClick={() => lift(ban.player)}
+              disabled={lifting !== null}
+              title="Lift ban now"
+              className="text-gray-500 hover:text-[#E5B80B] disabled:opacity-50 shrink-0"
+            >
+              <X size={12} />
+            </button>
+          </div>
+        ))}
+      </div>
+    </div>
+  );
+};
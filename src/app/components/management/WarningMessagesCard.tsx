@@ -0,0 +1,85 @@
+import React, { useEffect, useState } from 'react';
+import { Megaphone, Save } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import type { Server, WarningMessages } from '../../context/ServerContext';
+
+interface WarningMessagesCardProps {
+  server: Server;
+  onSaved: () => Promise<void>;
+}
+
+const fields: { key: keyof WarningMessages; label: string; placeholder: string }[] = [
+  { key: 'restart', label: 'Restart countdown', placeholder: 'Server restarting in {minutes} minutes...' },
+  { key: 'restartNow', label: 'Restarting now', placeholder: 'Server restarting now!' },
+  { key: 'stop', label: 'Stop countdown', placeholder: 'Server stopping in {seconds} seconds...' },
+  { key: 'stopNow', label: 'Stopping now', placeholder: 'Server stopping now!' },
+];
+
+// Per-server overrides for the broadcasts sent before scheduled restarts and stops.
+export const WarningMessagesCard = ({ server, onSaved }: WarningMessagesCardProps) => {
+  const [draft, setDraft] = useState<WarningMessages>({});
+  const [saving, setSaving] = useState(false);
+  const saved = server.warningMessages ?? {};
+
+  useEffect(() => {
+    setDraft(server.warningMessages ?? {});
+  }, [server.id, server.warningMessages]);
+
+  const changed = fields.some(({ key }) => (draft[key] ?? '').trim() !== (saved[key] ?? ''));
+
+  const handleSave = async () => {
+    setSaving(true);
+    try {
+      await apiRequest(
+        `/api/servers/${server.id}/warning-messages`,
+        {
+          method: 'PUT',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify(draft),
+        },
+        'Failed to save warning messages'
+      );
+      toast.success('Warning messages saved');
+      await onSaved();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save warning messages'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center gap-2">
+        <Megaphone size={14} className="text-gray-400" />
+        <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Player Warnings</h4>
+      </div>
+      <p className="text-[11px] text-gray-500">
+        Leave empty for the default. Use <span className="font-mono">{'{minutes}'}</span>, <span className="font-mono">{'{seconds}'}</span> and <span className="font-mono">{'{reason}'}</span>.
+      </p>
+      {fields.map(({ key, label, placeholder }) => (
+        <div key={key}>
+          <label className="block text-[11px] text-gray-500 mb-1">{label}</label>
+          <input
+            value={draft[key] ?? ''}
+            onChange={(e) => setDraft((prev) => ({ ...prev, [key]: e.target.value }))}
+            placeholder={placeholder}
+            maxLength={200}
+            className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]"
+          />
+        </div>
+      ))}
+      {changed && (
+        <button
+          onClick={handleSave}
+          disabled={saving}
+          className="w-full py-2 bg-[#E5B80B] text-black rounded font-bold text-sm hover:bg-[#d4a90a] transition-colors flex items-center justify-center gap-2 disabled:opacity-50"
+        >
+          <Save size={14} />
+          {saving ? 'Saving...' : 'Save Messages'}
+        </button>
+      )}
+    </div>
+  );
+};
@@ -0,0 +1,129 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import { Plus, Webhook, X } from 'lucide-react';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import type { Server } from '../../context/ServerContext';
+
+interface WebhooksCardProps {
+  server: Server;
+}
+
+interface InboundWebhook {
+  id: string;
+  name: string;
+  commands: string[];
+  enabled: boolean;
+  lastCalledAt?: string;
+  lastResult?: string;
+}
+
+interface SavedWebhook {
+  webhook: InboundWebhook;
+  secret: string;
+  url: string;
+}
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]';
+
+// Inbound webhooks let vote and donation services run console commands,
+// with {placeholders} filled from the fields they send.
+export const WebhooksCard = ({ server }: WebhooksCardProps) => {
+  const [hooks, setHooks] = useState<InboundWebhook[]>([]);
+  const [name, setName] = useState('');
+  const [commands, setCommands] = useState('');
+  const [saving, setSaving] = useState(false);
+  const [created, setCreated] = useState<SavedWebhook | null>(null);
+
+  const load = useCallback(() => {
+    apiRequest<InboundWebhook[]>(`/api/servers/${server.id}/webhooks`, undefined, 'Failed to load webhooks')
+      .then(setHooks)
+      .catch(() => {});
+  }, [server.id]);
+
+  useEffect(() => {
+    setCreated(null);
+    load();
+  }, [load]);
+
+  const create = async () => {
+    setSaving(true);
+    try {
+      const saved = await apiRequest<SavedWebhook>(`/api/servers/${server.id}/webhooks`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ name, enabled: true, commands: commands.split('\n') }),
+      }, 'Failed to create webhook');
+      setCreated(saved);
+      setName('');
+      setCommands('');
+      load();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to create webhook'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const remove = async (hook: InboundWebhook) => {
+    try {
+      await apiRequest(`/api/servers/${server.id}/webhooks/${hook.id}`, { method: 'DELETE' }, 'Failed to delete webhook');
+      toast.success(`Deleted ${hook.name}`);
+      if (created?.webhook.id === hook.id) {
+        setCreated(null);
+      }
+      load();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to delete webhook'));
+    }
+  };
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center gap-2">
+        <Webhook size={14} className="text-gray-400" />
+        <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Webhooks</h4>
+      </div>
+      {hooks.length > 0 && (
+        <div className="space-y-1">
+          {hooks.map((hook) => (
+            <div key={hook.id} className="flex items-center justify-between gap-2 text-[11px] bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1">
+              <div className="min-w-0">
+                <div className="text-white truncate">{hook.name}</div>
+                <div className="text-gray-500 truncate">
+                  /api/hooks/{server.id}/{hook.id}
+                  {hook.lastCalledAt && ` · ${new Date(hook.lastCalledAt).toLocaleString()}: ${hook.lastResult ?? ''}`}
+                </div>
+              </div>
+              <button onClick={() => remove(hook)} className="text-gray-500 hover:text-red-400" title="Delete webhook">
+                <X size={12} />
+              </button>
+            </div>
+          ))}
+        </div>
+      )}
+      {created && (
+        <div className="text-[11px] bg-[#1a1a1a] border border-[#E5B80B] rounded p-2 space-y-1 break-all">
+          <p className="text-[#E5B80B]">Copy the secret now, it is not shown again.</p>
+          <p className="text-gray-400">URL: <span className="text-white">{window.location.origin}{created.url}</span></p>
+          <p className="text-gray-400">Secret: <span className="text-white font-mono">{created.secret}</span></p>
+        </div>
+      )}
+      <input value={name} onChange={(e) => setName(e.target.value)} placeholder="Name, e.g. Votes" className={inputClass} />
+      <textarea
+        value={commands}
+        onChange={(e) => setCommands(e.target.value)}
+        placeholder={'One command per line, e.g.\ngive {player} diamond 1'}
+        rows={3}
+        className={inputClass}
+      />
+      <button
+        onClick={create}
+        disabled={saving || !name.trim() || !commands.trim()}
+        className="w-full py-2 bg-[#E5B80B] text-black rounded font-bold text-xs hover:bg-[#d4a90a] flex items-center justify-center gap-1 disabled:opacity-50"
+      >
+        <Plus size={12} /> {saving ? 'Creating...' : 'Add Webhook'}
+      </button>
+    </div>
+  );
+};
@@ -0,0 +1,141 @@
+import React, { useEffect, useState } from 'react';
+import { CalendarClock, Plus, Save, Trash2 } from 'lucide-react';
+import clsx from 'clsx';
+import { toast } from 'sonner';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+import type { Server } from '../../context/ServerContext';
+
+interface WhitelistScheduleCardProps {
+  server: Server;
+}
+
+interface WhitelistWindow {
+  days: string[];
+  start: string;
+  end: string;
+}
+
+interface WhitelistScheduleInfo {
+  enabled: boolean;
+  windows: WhitelistWindow[];
+  lastState?: string;
+  desired?: string;
+  nextChange?: string;
+}
+
+const DAYS = ['mon', 'tue', 'wed', 'thu', 'fri', 'sat', 'sun'];
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]';
+
+// Opens the server to everyone during weekly windows and turns the
+// whitelist back on outside them.
+export const WhitelistScheduleCard = ({ server }: WhitelistScheduleCardProps) => {
+  const [enabled, setEnabled] = useState(false);
+  const [windows, setWindows] = useState<WhitelistWindow[]>([]);
+  const [info, setInfo] = useState<WhitelistScheduleInfo | null>(null);
+  const [saving, setSaving] = useState(false);
+
+  useEffect(() => {
+    apiRequest<WhitelistScheduleInfo>(`/api/servers/${server.id}/whitelist-schedule`, undefined, 'Failed to load whitelist schedule')
+      .then((data) => {
+        setEnabled(data.enabled);
+        setWindows(data.windows ?? []);
+        setInfo(data);
+      })
+      .catch(() => {});
+  }, [server.id]);
+
+  if (server.type.toLowerCase() === 'velocity') {
+    return null;
+  }
+
+  const updateWindow = (index: number, patch: Partial<WhitelistWindow>) => {
+    setWindows((prev) => prev.map((w, i) => (i === index ? { ...w, ...patch } : w)));
+  };
+
+  const toggleDay = (index: number, day: string) => {
+    const days = windows[index].days;
+    updateWindow(index, { days: days.includes(day) ? days.filter((d) => d !== day) : [...days, day] });
+  };
+
+  const save = async () => {
+    setSaving(true);
+    try {
+      const data = await apiRequest<WhitelistScheduleInfo>(`/api/servers/${server.id}/whitelist-schedule`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ enabled, windows }),
+      }, 'Failed to save whitelist schedule');
+      setInfo(data);
+      toast.success(enabled ? 'Whitelist schedule saved' : 'Whitelist schedule disabled');
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save whitelist schedule'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+      <div className="flex items-center justify-between gap-2">
+        <div className="flex items-center gap-2">
+          <CalendarClock size={14} className="text-gray-400" />
+          <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Whitelist Schedule</h4>
+        </div>
+        <label className="flex items-center gap-1 text-[11px] text-gray-400">
+          <input type="checkbox" checked={enabled} onChange={(e) => setEnabled(e.target.checked)} className="accent-[#E5B80B]" />
+          Enabled
+        </label>
+      </div>
+      <p className="text-[11px] text-gray-500">The server is public during these windows and whitelisted outside them.</p>
+      {windows.map((w, index) => (
+        <div key={index} className="bg-[#1a1a1a] border border-[#3a3a3a] rounded p-2 space-y-2">
+          <div className="flex flex-wrap gap-1">
+            {DAYS.map((day) => (
+              <button
+                key={day}
+                onClick={() => toggleDay(index, day)}
+                className={clsx(
+                  'px-1.5 py-0.5 rounded text-[10px] uppercase border',
+                  w.days.includes(day) ? 'bg-[#E5B80B] text-black border-[#E5B80B]' : 'border-[#3a3a3a] text-gray-400 hover:bg-[#333]',
+                )}
+              >
+                {day}
+              </button>
+            ))}
+          </div>
+          <div className="flex items-center gap-2">
+            <input type="time" value={w.start} onChange={(e) => updateWindow(index, { start: e.target.value })} className={inputClass} />
+            <span className="text-gray-500 text-xs">to</span>
+            <input type="time" value={w.end} onChange={(e) => updateWindow(index, { end: e.target.value })} className={inputClass} />
+            <button onClick={() => setWindows((prev) => prev.filter((_, i) => i !== index))} className="text-gray-500 hover:text-red-400" title="Remove window">
+              <Trash2 size={12} />
+            </button>
+          </div>
+        </div>
+      ))}
+      {info?.enabled && info.desired && (
+        <p className="text-[11px] text-gray-500">
+          Whitelist is {info.desired === 'off' ? 'off (public)' : 'on'}
+          {info.nextChange && ` until ${new Date(info.nextChange).toLocaleString()}`}
+        </p>
+      )}
+      <div className="grid grid-cols-2 gap-2">
+        <button
+          onClick={() => setWindows((prev) => [...prev, { days: ['sat'], start: '18:00', end: '23:00' }])}
+          className="py-2 border border-[#3a3a3a] text-gray-300 rounded text-xs hover:bg-[#333] flex items-center justify-center gap-1"
+        >
+          <Plus size={12} /> Add window
+        </button>
+        <button
+          onClick={save}
+          disabled={saving}
+          className="py-2 bg-[#E5B80B] text-black rounded font-bold text-xs hover:bg-[#d4a90a] flex items-center justify-center gap-1 disabled:opacity-50"
+        >
+          <Save size={12} /> {saving ? 'Saving...' : 'Save'}
+        </button>
+      </div>
+    </div>
+  );
+};
@@ -1,7 +1,7 @@
-import React, { createContext, useContext, useState, useEffect, useCallback, ReactNode } from 'react';
+import React, { createContext, useContext, useState, useEffect, useCallback, useMemo, ReactNode } from 'react';
 import { apiRequest, toErrorMessage } from '../lib/api';
 
-export type ServerStatus = 'Running' | 'Stopped' | 'Crashed' | 'Booting' | 'Installing' | 'Error';
+export type ServerStatus = 'Running' | 'Stopped' | 'Crashed' | 'Booting' | 'Installing' | 'Error' | 'Suspended';
 export type ServerType = 'Vanilla' | 'Spigot' | 'Paper' | 'Folia' | 'Purpur' | 'Velocity' | 'Forge' | 'Fabric' | 'NeoForge';
 
 export interface Server {
@@ -21,10 +21,113 @@ export interface Server {
   minRam: string;
   maxPlayers: number;
   autoStart: boolean;
+  showOnStatusPage?: boolean;
   flags: string;
   alwaysPreTouch: boolean;
+  pluginUpdateChannel?: 'stable' | 'prerelease';
+  readyCommands?: string[];
+  groups?: string[];
+  warningMessages?: WarningMessages;
+  rconPort?: number;
   installError?: string;
+  bootFailedAt?: string;
+  failureReason?: 'port_in_use';
+  portConflict?: PortConflict;
   fabricTpsAvailable?: boolean;
+  joinCheck?: JoinCheckResult;
+  restartVerification?: RestartVerificationResult;
+  restartOnCrash?: CrashRestartSettings;
+  consoleBuffer?: ConsoleBufferSettings;
+  diskQuota?: DiskQuotaSettings;
+  crashCount?: number;
+  lastCrashAt?: string;
+  crashRestarts?: number;
+  crashRestartAt?: string;
+  suspendedAt?: string;
+  swapBytes?: number;
+  memoryWarning?: string;
+  tickMetrics?: TickMetrics;
+  modpack?: ModpackInfo;
+  // Online player count; only sent on the status stream.
+  players?: number;
+}
+
+export interface ModpackInfo {
+  source: 'modrinth' | 'curseforge';
+  name: string;
+  version?: string;
+  loader: string;
+  loaderVersion: string;
+  projectId?: number;
+  fileId?: number;
+}
+
+export interface DimensionTick {
+  dimension: string;
+  tps: number;
+  meanTickMs: number;
+}
+
+export interface TickMetrics {
+  meanTickMs?: number;
+  // Slowest dimension first.
+  dimensions: DimensionTick[];
+  updatedAt: string;
+}
+
+export interface CrashRestartSettings {
+  enabled: boolean;
+  maxRetries: number;
+  initialDelaySeconds: number;
+}
+
+export interface RestartVerificationResult {
+  ok: boolean;
+  trigger: string;
+  message: string;
+  tps?: number;
+  rolledBack?: boolean;
+  checkedAt: string;
+}
+
+export interface ConsoleBufferSettings {
+  maxLines: number;
+  trimLines: number;
+}
+
+export interface DiskUsage {
+  serverBytes: number;
+  backupsBytes: number;
+  serverOver?: boolean;
+  backupsOver?: boolean;
+  checkedAt: string;
+}
+
+export interface DiskQuotaSettings {
+  // 0 means no limit.
+  serverGb: number;
+  backupsGb: number;
+  enforce: boolean;
+  usage?: DiskUsage;
+}
+
+export interface JoinCheckResult {
+  ok: boolean;
+  joined: boolean;
+  player: string;
+  version?: string;
+  protocol?: number;
+  message: string;
+  latencyMs: number;
+  checkedAt: string;
+}
+
+export interface PortConflict {
+  port: number;
+  pid?: number;
+  process?: string;
+  serverId?: string;
+  serverName?: string;
 }
 
 export interface Player {
@@ -34,11 +137,16 @@ export interface Player {
   ping: number;
   world: string;
   onlineTime: string;
+  position?: number[];
+  positionAt?: string;
 }
 
+export type ExtensionDirectory = 'plugins' | 'mods' | 'datapacks';
+
 export interface Plugin {
   name: string;
   fileName: string;
+  directory?: ExtensionDirectory;
   size: string;
   enabled: boolean;
   version: string;
@@ -46,12 +154,48 @@ export interface Plugin {
   versionStatus?: 'latest' | 'outdated' | 'incompatible' | 'unknown';
   updateUrl?: string;
   sourceUrl?: string;
+  note?: string;
+  iconUrl?: string;
+  description?: string;
+  projectUrl?: string;
+  provenance?: PluginProvenance;
+  pendingUpdate?: StagedPluginUpdate;
+}
+
+export interface StagedPluginUpdate {
+  fileName: string;
+  targetFileName: string;
+  version: string;
+  downloadUrl?: string;
+  stagedAt: string;
+}
+
+export interface PluginProvenance {
+  sourceUrl?: string;
+  origin?: 'upload' | 'update' | 'url';
+  downloadUrl?: string;
+  installedAt?: string;
+  updatedAt?: string;
+  sha256?: string;
+  note?: string;
+  noteUpdatedAt?: string;
 }
 
 export interface Backup {
   name: string;
   date: string;
   size: string;
+  kind?: 'incremental';
+  newData?: string;
+  uploads?: BackupUpload[];
+}
+
+export interface BackupUpload {
+  target: string;
+  targetName?: string;
+  status: 'pending' | 'uploading' | 'uploaded' | 'failed';
+  error?: string;
+  updatedAt: string;
 }
 
 export interface FileEntry {
@@ -61,6 +205,20 @@ export interface FileEntry {
   modTime: string;
 }
 
+export interface WarningMessages {
+  restart?: string;
+  restartNow?: string;
+  stop?: string;
+  stopNow?: string;
+}
+
+export interface UserPreferences {
+  favoriteServerIds: string[];
+  serverOrder: string[];
+  defaultServerId?: string;
+  updatedAt?: string;
+}
+
 export interface LogEntry {
   id: string;
   timestamp: string;
@@ -79,8 +237,13 @@ interface ServerContextType {
   startServer: (id: string) => Promise<void>;
   stopServer: (id: string) => Promise<void>;
   killServer: (id: string) => Promise<void>;
+  suspendServer: (id: string) => Promise<void>;
+  resumeServer: (id: string) => Promise<void>;
   reorderServers: (orderedIds: string[]) => Promise<void>;
   refreshServers: () => Promise<void>;
+  preferences: UserPreferences;
+  updatePreferences: (changes: Partial<UserPreferences>) => Promise<void>;
+  toggleFavorite: (id: string) => Promise<void>;
   loading: boolean;
   error: string | null;
 }
@@ -88,13 +251,21 @@ interface ServerContextType {
 const ServerContext = createContext<ServerContextType | undefined>(undefined);
 
 export const ServerProvider = ({ children }: { children: ReactNode }) => {
-  const [servers, setServers] = useState<Server[]>([]);
+  const [serverList, setServers] = useState<Server[]>([]);
+  const [preferences, setPreferences] = useState<UserPreferences>({ favoriteServerIds: [], serverOrder: [] });
   const [activeServerId, setActiveServerId] = useState<string | null>(null);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
 
   const [pollInterval, setPollInterval] = useState(3000);
 
+  // Per-user card order wins over the panel-wide order when one is saved.
+  const servers = useMemo(() => {
+    if (preferences.serverOrder.length === 0) return serverList;
+    const rank = new Map(preferences.serverOrder.map((id, index) => [id, index]));
+    return [...serverList].sort((a, b) => (rank.get(a.id) ?? Infinity) - (rank.get(b.id) ?? Infinity));
+  }, [serverList, preferences.serverOrder]);
+
   const activeServer = servers.find(s => s.id === activeServerId);
 
   // Load poll interval from settings
@@ -108,6 +279,49 @@ export const ServerProvider = ({ children }: { children: ReactNode }) => {
       .catch(() => {});
   }, []);
 
+  // Load the signed-in user's preferences and open their default server
+  useEffect(() => {
+    apiRequest<UserPreferences>(`${API_BASE}/api/preferences`)
+      .then(data => {
+        setPreferences({
+          ...data,
+          favoriteServerIds: data.favoriteServerIds ?? [],
+          serverOrder: data.serverOrder ?? [],
+        });
+        if (data.defaultServerId) {
+          setActiveServerId((current) => current ?? data.defaultServerId ?? null);
+        }
+      })
+      .catch(() => {});
+  }, []);
+
+  const updatePreferences = async (changes: Partial<UserPreferences>) => {
+    const next = { ...preferences, ...changes };
+    setPreferences(next);
+    try {
+      const saved = await apiRequest<UserPreferences>(`${API_BASE}/api/preferences`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(next),
+      }, 'Failed to save preferences');
+      setPreferences({
+        ...saved,
+        favoriteServerIds: saved.favoriteServerIds ?? [],
+        serverOrder: saved.serverOrder ?? [],
+      });
+    } catch (err) {
+      setPreferences(preferences);
+      throw new Error(toErrorMessage(err, 'Failed to save preferences'));
+    }
+  };
+
+  const toggleFavorite = async (id: string) => {
+    const favorites = preferences.favoriteServerIds.includes(id)
+      ? preferences.favoriteServerIds.filter((favoriteId) => favoriteId !== id)
+      : [...preferences.favoriteServerIds, id];
+    await updatePreferences({ favoriteServerIds: favorites });
+  };
+
   // Fetch all servers from API
   const refreshServers = useCallback(async () => {
     try {
@@ -121,12 +335,75 @@ export const ServerProvider = ({ children }: { children: ReactNode }) => {
     }
   }, []);
 
-  // Initial fetch + polling at configurable interval
+  // Live status over /api/ws/status. While the socket is down the list
+  // falls back to polling at the configured interval.
+  const [streamConnected, setStreamConnected] = useState(false);
+
+  useEffect(() => {
+    const loc = window.location;
+    const protocol = loc.protocol === 'https:' ? 'wss:' : 'ws:';
+    let disposed = false;
+    let retryDelay = 1000;
+    let retryTimer: ReturnType<typeof setTimeout> | undefined;
+    let ws: WebSocket | null = null;
+
+    const connect = () => {
+      ws = new WebSocket(`${protocol}//${loc.host}/api/ws/status`);
+
+      ws.onmessage = (event) => {
+        let data: { type?: string; servers?: Server[]; removed?: string[]; order?: string[] };
+        try {
+          data = JSON.parse(event.data);
+        } catch {
+          return;
+        }
+        if (data.type === 'snapshot') {
+          setServers(data.servers ?? []);
+          setStreamConnected(true);
+          setError(null);
+          setLoading(false);
+          retryDelay = 1000;
+          return;
+        }
+        if (data.type !== 'update') return;
+        setServers((prev) => {
+          const changed = new Map((data.servers ?? []).map((server) => [server.id, server]));
+          const removed = new Set(data.removed ?? []);
+          const next = prev
+            .filter((server) => !removed.has(server.id))
+            .map((server) => changed.get(server.id) ?? server);
+          const known = new Set(next.map((server) => server.id));
+          next.push(...[...changed.values()].filter((server) => !known.has(server.id)));
+          if (!data.order) return next;
+          const rank = new Map(data.order.map((id, index) => [id, index]));
+          return next.sort((a, b) => (rank.get(a.id) ?? Infinity) - (rank.get(b.id) ?? Infinity));
+        });
+      };
+
+      ws.onclose = () => {
+        setStreamConnected(false);
+        if (disposed) return;
+        retryTimer = setTimeout(connect, retryDelay);
+        retryDelay = Math.min(retryDelay * 2, 30000);
+      };
+    };
+
+    connect();
+
+    return () => {
+      disposed = true;
+      if (retryTimer) clearTimeout(retryTimer);
+      ws?.close();
+    };
+  }, []);
+
+  // Initial fetch + polling at configurable interval when the stream is down
   useEffect(() => {
     refreshServers();
+    if (streamConnected) return;
     const interval = setInterval(refreshServers, pollInterval);
     return () => clearInterval(interval);
-  }, [refreshServers, pollInterval]);
+  }, [refreshServers, pollInterval, streamConnected]);
 
   // Create a new server via API
   const addServer = async (newServer: Omit<Server, 'id' | 'cpu' | 'ram' | 'status' | 'autoStart' | 'installError'>) => {
@@ -155,6 +432,17 @@ export const ServerProvider = ({ children }: { children: ReactNode }) => {
     await refreshServers();
   };
 
+  // Freeze a running server's process without stopping it, and continue it
+  const suspendServer = async (id: string) => {
+    await apiRequest(`${API_BASE}/api/servers/${id}/suspend`, { method: 'POST' }, 'Failed to suspend server');
+    await refreshServers();
+  };
+
+  const resumeServer = async (id: string) => {
+    await apiRequest(`${API_BASE}/api/servers/${id}/resume`, { method: 'POST' }, 'Failed to resume server');
+    await refreshServers();
+  };
+
   const reorderServers = async (orderedIds: string[]) => {
     const normalized = orderedIds.map((id) => id.trim()).filter(Boolean);
     setServers((prev) => {
@@ -163,6 +451,9 @@ export const ServerProvider = ({ children }: { children: ReactNode }) => {
       const missing = prev.filter((server) => !normalized.includes(server.id));
       return [...ordered, ...missing];
     });
+    if (preferences.serverOrder.length > 0) {
+      setPreferences((prev) => ({ ...prev, serverOrder: normalized }));
+    }
 
     try {
       await apiRequest(`${API_BASE}/api/servers/order`, {
@@ -170,6 +461,7 @@ export const ServerProvider = ({ children }: { children: ReactNode }) => {
         headers: { 'Content-Type': 'application/json' },
         body: JSON.stringify({ orderedIds: normalized }),
       }, 'Failed to save server order');
+      await updatePreferences({ serverOrder: normalized });
     } catch (err) {
       await refreshServers();
       throw new Error(toErrorMessage(err, 'Failed to save server order'));
@@ -179,7 +471,8 @@ export const ServerProvider = ({ children }: { children: ReactNode }) => {
   return (
     <ServerContext.Provider value={{
       servers, activeServerId, setActiveServerId, activeServer,
-      addServer, startServer, stopServer, killServer, reorderServers, refreshServers,
+      addServer, startServer, stopServer, killServer, suspendServer, resumeServer, reorderServers, refreshServers,
+      preferences, updatePreferences, toggleFavorite,
       loading, error,
     }}>
       {children}
@@ -1,19 +1,47 @@
 export class ApiError extends Error {
   status: number;
   code?: string;
+  params?: Record<string, unknown>;
   details?: unknown;
 
-  constructor(message: string, status: number, code?: string, details?: unknown) {
+  constructor(message: string, status: number, code?: string, details?: unknown, params?: Record<string, unknown>) {
     super(message);
     this.name = 'ApiError';
     this.status = status;
     this.code = code;
     this.details = details;
+    this.params = params;
   }
 }
 
 type JsonLike = Record<string, unknown>;
 
+const CSRF_COOKIE = 'orexa_csrf';
+const CSRF_HEADER = 'X-CSRF-Token';
+const UNSAFE_METHODS = new Set(['POST', 'PUT', 'PATCH', 'DELETE']);
+
+function readCsrfToken(): string {
+  const match = document.cookie.split('; ').find((part) => part.startsWith(`${CSRF_COOKIE}=`));
+  return match ? decodeURIComponent(match.slice(CSRF_COOKIE.length + 1)) : '';
+}
+
+// csrfHeaders returns the header the panel requires on state-changing
+// requests. Use it for calls that bypass apiRequest, such as XHR uploads.
+export function csrfHeaders(): Record<string, string> {
+  const token = readCsrfToken();
+  return token ? { [CSRF_HEADER]: token } : {};
+}
+
+function withCsrf(init?: RequestInit): RequestInit | undefined {
+  const method = (init?.method || 'GET').toUpperCase();
+  if (!UNSAFE_METHODS.has(method)) return init;
+  const headers = new Headers(init?.headers);
+  for (const [name, value] of Object.entries(csrfHeaders())) {
+    if (!headers.has(name)) headers.set(name, value);
+  }
+  return { ...init, headers };
+}
+
 async function readJsonSafe(res: Response): Promise<JsonLike | null> {
   try {
     const parsed = await res.json();
@@ -47,12 +75,19 @@ export async function apiRequest<T>(
   init?: RequestInit,
   fallbackErrorMessage = 'Request failed'
 ): Promise<T> {
-  const res = await fetch(input, init);
+  const res = await fetch(input, withCsrf(init));
   if (!res.ok) {
     const payload = await readJsonSafe(res);
     const message = messageFromPayload(payload) || fallbackErrorMessage;
-    const code = payload && typeof payload.error === 'string' ? payload.error : undefined;
-    throw new ApiError(message, res.status, code, payload ?? undefined);
+    // Structured errors carry a stable `code` and `params` for translation;
+    // older responses put the code in `error`.
+    const code = payload && typeof payload.code === 'string'
+      ? payload.code
+      : payload && typeof payload.error === 'string' ? payload.error : undefined;
+    const params = payload && payload.params && typeof payload.params === 'object'
+      ? payload.params as Record<string, unknown>
+      : undefined;
+    throw new ApiError(message, res.status, code, payload ?? undefined, params);
   }
 
   if (res.status === 204) {
@@ -66,5 +101,31 @@ export async function apiRequestRaw(
   input: RequestInfo | URL,
   init?: RequestInit
 ): Promise<Response> {
-  return fetch(input, init);
+  return fetch(input, withCsrf(init));
+}
+
+export interface Job<T = unknown> {
+  id: string;
+  type: string;
+  state: 'queued' | 'running' | 'succeeded' | 'failed' | 'cancelled';
+  progress: number;
+  message?: string;
+  error?: string;
+  result?: T;
+}
+
+// waitForJob polls a job returned by a 202 response until it finishes and
+// returns its result. onProgress sees every poll. Failed and cancelled jobs
+// throw their error.
+export async function waitForJob<T>(job: Job<T>, onProgress?: (job: Job<T>) => void): Promise<T | undefined> {
+  let current = job;
+  while (current.state === 'queued' || current.state === 'running') {
+    await new Promise((resolve) => setTimeout(resolve, 1000));
+    current = await apiRequest<Job<T>>(`/api/jobs/${encodeURIComponent(current.id)}`, undefined, 'Failed to check job progress');
+    onProgress?.(current);
+  }
+  if (current.state !== 'succeeded') {
+    throw new Error(current.error || `Job ${current.state}`);
+  }
+  return current.result;
 }
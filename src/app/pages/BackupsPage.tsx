@@ -1,29 +1,51 @@
 import React, { useState, useEffect, useCallback } from 'react';
 import { useServer, Backup } from '../context/ServerContext';
-import { Archive, Clock, Download, Upload, Trash2, Plus, Loader2, AlertTriangle, CalendarClock, X, Check, Square } from 'lucide-react';
+import { Archive, Clock, Download, Upload, Trash2, Plus, Loader2, AlertTriangle, CalendarClock, X, Check, Square, CopyPlus, CloudUpload } from 'lucide-react';
 import { motion, AnimatePresence } from 'motion/react';
 import { format } from 'date-fns';
 import { toast } from 'sonner';
 import clsx from 'clsx';
 import { useEscapeKey } from '../hooks/useEscapeKey';
 import { useStagedDeleteUndo } from '../hooks/useStagedDeleteUndo';
-import { apiRequest, toErrorMessage } from '../lib/api';
+import { apiRequest, toErrorMessage, waitForJob, Job } from '../lib/api';
+
+interface BackupTargetSummary {
+  id: string;
+  name: string;
+  type: 's3' | 'sftp';
+}
+
+const uploadStatusStyles: Record<string, string> = {
+  pending: 'text-gray-400 border-[#3a3a3a]',
+  uploading: 'text-blue-400 border-blue-900/60',
+  uploaded: 'text-green-400 border-green-900/60',
+  failed: 'text-red-400 border-red-900/60',
+};
 
 export const BackupsPage = () => {
-  const { activeServer } = useServer();
+  const { activeServer, refreshServers } = useServer();
   const [backups, setBackups] = useState<Backup[]>([]);
   const [loading, setLoading] = useState(true);
   const [creating, setCreating] = useState(false);
+  const [backupProgress, setBackupProgress] = useState(0);
   const [deleteTarget, setDeleteTarget] = useState<string | null>(null);
   const [restoreTarget, setRestoreTarget] = useState<string | null>(null);
   const [restoring, setRestoring] = useState(false);
+  const [restoreAsNewTarget, setRestoreAsNewTarget] = useState<string | null>(null);
+  const [newServerName, setNewServerName] = useState('');
+  const [newServerPort, setNewServerPort] = useState('');
   const [schedulePopup, setSchedulePopup] = useState(false);
   const [currentSchedule, setCurrentSchedule] = useState('');
   const [selectedSchedule, setSelectedSchedule] = useState('');
+  const [currentMode, setCurrentMode] = useState<'full' | 'incremental'>('full');
+  const [selectedMode, setSelectedMode] = useState<'full' | 'incremental'>('full');
   const [nextBackup, setNextBackup] = useState<string | null>(null);
   const [selectedBackups, setSelectedBackups] = useState<Set<string>>(new Set());
   const [batchDeleteConfirm, setBatchDeleteConfirm] = useState(false);
   const [pendingDeletedBackups, setPendingDeletedBackups] = useState<Set<string>>(new Set());
+  const [availableTargets, setAvailableTargets] = useState<BackupTargetSummary[]>([]);
+  const [serverTargets, setServerTargets] = useState<string[]>([]);
+  const [selectedTargets, setSelectedTargets] = useState<string[]>([]);
   const { stageDelete, undoOverlay } = useStagedDeleteUndo();
 
   const fetchBackups = useCallback(async () => {
@@ -46,11 +68,58 @@ export const BackupsPage = () => {
     fetchBackups();
   }, [fetchBackups]);
 
+  // Refresh while uploads to remote targets are still running.
+  const uploadsActive = backups.some((b) => b.uploads?.some((u) => u.status === 'pending' || u.status === 'uploading'));
+  useEffect(() => {
+    if (!uploadsActive) return;
+    const timer = setInterval(fetchBackups, 3000);
+    return () => clearInterval(timer);
+  }, [uploadsActive, fetchBackups]);
+
+  // Target credentials are admin-only; other roles simply see no picker.
+  useEffect(() => {
+    if (!activeServer) return;
+    let mounted = true;
+    Promise.all([
+      apiRequest<BackupTargetSummary[]>('/api/backup-targets', undefined, 'Failed to load backup targets'),
+      apiRequest<{ targets: string[] }>(`/api/servers/${activeServer.id}/backup-targets`, undefined, 'Failed to load backup targets'),
+    ])
+      .then(([targets, selection]) => {
+        if (!mounted) return;
+        setAvailableTargets(targets);
+        setServerTargets(selection.targets || []);
+      })
+      .catch(() => {
+        if (mounted) setAvailableTargets([]);
+      });
+    return () => {
+      mounted = false;
+    };
+  }, [activeServer?.id]);
+
+  const handleUpload = async (name: string) => {
+    if (!activeServer) return;
+    try {
+      await apiRequest(
+        `/api/servers/${activeServer.id}/backups/${encodeURIComponent(name)}/upload`,
+        { method: 'POST' },
+        'Failed to start upload'
+      );
+      toast.success('Upload started');
+      fetchBackups();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to start upload'));
+    }
+  };
+
   const handleCreateBackup = async () => {
     if (!activeServer) return;
     setCreating(true);
+    setBackupProgress(0);
     try {
-      await apiRequest(`/api/servers/${activeServer.id}/backups`, { method: 'POST' }, 'Failed to create backup');
+      // The backup runs as a job; poll it until it finishes.
+      const job = await apiRequest<Job<Backup>>(`/api/servers/${activeServer.id}/backups`, { method: 'POST' }, 'Failed to create backup');
+      await waitForJob(job, (current) => setBackupProgress(current.progress));
       toast.success('Backup created successfully');
       fetchBackups();
     } catch (err) {
@@ -105,11 +174,12 @@ export const BackupsPage = () => {
     if (!activeServer) return;
     setRestoring(true);
     try {
-      await apiRequest(
+      const job = await apiRequest<Job>(
         `/api/servers/${activeServer.id}/backups/${encodeURIComponent(name)}/restore`,
         { method: 'POST' },
         'Failed to restore backup'
       );
+      await waitForJob(job);
       toast.success('Backup restored successfully');
       setRestoreTarget(null);
     } catch (err) {
@@ -119,6 +189,31 @@ export const BackupsPage = () => {
     }
   };
 
+  const handleRestoreAsNew = async (name: string) => {
+    if (!activeServer) return;
+    setRestoring(true);
+    try {
+      const job = await apiRequest<Job<{ name: string; port: number }>>('/api/servers/restore-as-new', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({
+          sourceId: activeServer.id,
+          backup: name,
+          name: newServerName.trim(),
+          port: Number(newServerPort) || 0,
+        }),
+      }, 'Failed to restore backup');
+      const created = await waitForJob(job);
+      toast.success(created ? `Created ${created.name} on port ${created.port}` : 'Backup restored as a new server');
+      setRestoreAsNewTarget(null);
+      await refreshServers();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to restore'));
+    } finally {
+      setRestoring(false);
+    }
+  };
+
   const handleDownload = (name: string) => {
     if (!activeServer) return;
     window.open(`/api/servers/${activeServer.id}/backups/${encodeURIComponent(name)}/download`, '_blank');
@@ -127,12 +222,13 @@ export const BackupsPage = () => {
   const fetchSchedule = useCallback(async () => {
     if (!activeServer) return;
     try {
-      const data = await apiRequest<{ schedule?: string; nextBackup?: string | null }>(
+      const data = await apiRequest<{ schedule?: string; mode?: 'full' | 'incremental'; nextBackup?: string | null }>(
         `/api/servers/${activeServer.id}/backup-schedule`,
         undefined,
         'Failed to fetch backup schedule'
       );
       setCurrentSchedule(data.schedule || '');
+      setCurrentMode(data.mode || 'full');
       setNextBackup(data.nextBackup || null);
     } catch { /* ignore */ }
   }, [activeServer?.id]);
@@ -148,6 +244,7 @@ export const BackupsPage = () => {
 
   useEscapeKey(!!deleteTarget, () => setDeleteTarget(null));
   useEscapeKey(!!restoreTarget, () => setRestoreTarget(null));
+  useEscapeKey(!!restoreAsNewTarget, () => setRestoreAsNewTarget(null));
   useEscapeKey(schedulePopup, () => setSchedulePopup(false));
   useEscapeKey(batchDeleteConfirm, () => setBatchDeleteConfirm(false));
 
@@ -159,11 +256,24 @@ export const BackupsPage = () => {
         {
           method: 'PUT',
           headers: { 'Content-Type': 'application/json' },
-          body: JSON.stringify({ schedule: selectedSchedule }),
+          body: JSON.stringify({ schedule: selectedSchedule, mode: selectedMode }),
         },
         'Failed to update schedule'
       );
+      if (availableTargets.length > 0) {
+        const saved = await apiRequest<{ targets: string[] }>(
+          `/api/servers/${activeServer.id}/backup-targets`,
+          {
+            method: 'PUT',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ targets: selectedTargets }),
+          },
+          'Failed to update backup targets'
+        );
+        setServerTargets(saved.targets || []);
+      }
       setCurrentSchedule(selectedSchedule);
+      setCurrentMode(selectedMode);
       setNextBackup(data.nextBackup || null);
       setSchedulePopup(false);
       if (selectedSchedule) {
@@ -254,7 +364,7 @@ export const BackupsPage = () => {
             </button>
           )}
           <button
-            onClick={() => { setSelectedSchedule(currentSchedule); setSchedulePopup(true); }}
+            onClick={() => { setSelectedSchedule(currentSchedule); setSelectedMode(currentMode); setSelectedTargets(serverTargets); setSchedulePopup(true); }}
             className={clsx(
               "flex items-center gap-2 px-4 py-2 rounded font-bold border transition-colors",
               currentSchedule
@@ -271,7 +381,7 @@ export const BackupsPage = () => {
             className="flex items-center gap-2 px-4 py-2 bg-[#E5B80B] text-black rounded font-bold hover:bg-[#d4a90a] disabled:opacity-50 disabled:cursor-not-allowed transition-colors"
           >
             {creating ? <Loader2 size={18} className="animate-spin" /> : <Plus size={18} />}
-            {creating ? 'Creating...' : 'Create Backup'}
+            {creating ? `Creating... ${backupProgress}%` : 'Create Backup'}
           </button>
         </div>
       </div>
@@ -324,11 +434,27 @@ export const BackupsPage = () => {
                     return (
                       <>
                         <div className="font-bold text-white text-lg">{valid ? format(parsed as Date, 'MMM d, yyyy') : '—'}</div>
-                        <div className="text-sm text-gray-500 font-mono">{valid ? format(parsed as Date, 'HH:mm:ss') : '—'} &bull; {backup.size}</div>
+                        <div className="text-sm text-gray-500 font-mono">
+                          {valid ? format(parsed as Date, 'HH:mm:ss') : '—'} &bull; {backup.size}
+                          {backup.kind === 'incremental' && <span className="ml-2 text-[#E5B80B]">Incremental &bull; +{backup.newData}</span>}
+                        </div>
                       </>
                     );
                   })()}
                   <div className="text-xs text-gray-600 font-mono mt-0.5">{backup.name}</div>
+                  {backup.uploads && backup.uploads.length > 0 && (
+                    <div className="flex flex-wrap gap-1 mt-1">
+                      {backup.uploads.map((upload) => (
+                        <span
+                          key={upload.target}
+                          className={clsx('px-1.5 py-0.5 text-[11px] rounded border', uploadStatusStyles[upload.status])}
+                          title={upload.error || undefined}
+                        >
+                          {upload.targetName || upload.target}: {upload.status}
+                        </span>
+                      ))}
+                    </div>
+                  )}
                 </div>
               </div>
 
@@ -351,13 +477,41 @@ export const BackupsPage = () => {
                   type="button"
                   onClick={(e) => {
                     e.stopPropagation();
-                    handleDownload(backup.name);
+                    setNewServerName('');
+                    setNewServerPort('');
+                    setRestoreAsNewTarget(backup.name);
                   }}
-                  className="p-2 hover:bg-[#333] text-gray-300 rounded"
-                  title="Download"
+                  className="p-2 hover:bg-[#E5B80B]/10 text-[#E5B80B] rounded"
+                  title="Restore as new server"
                 >
-                  <Download size={20} />
+                  <CopyPlus size={20} />
                 </button>
+                {backup.kind !== 'incremental' && serverTargets.length > 0 && (
+                  <button
+                    type="button"
+                    onClick={(e) => {
+                      e.stopPropagation();
+                      handleUpload(backup.name);
+                    }}
+                    className="p-2 hover:bg-[#333] text-gray-300 rounded"
+                    title="Upload to remote targets"
+                  >
+                    <CloudUpload size={20} />
+                  </button>
+                )}
+                {backup.kind !== 'incremental' && (
+                  <button
+                    type="button"
+                    onClick={(e) => {
+                      e.stopPropagation();
+                      handleDownload(backup.name);
+                    }}
+                    className="p-2 hover:bg-[#333] text-gray-300 rounded"
+                    title="Download"
+                  >
+                    <Download size={20} />
+                  </button>
+                )}
                 <button
                   type="button"
                   onClick={(e) => {
@@ -447,6 +601,60 @@ export const BackupsPage = () => {
         )}
       </AnimatePresence>
 
+      {/* Restore As New Server Modal */}
+      <AnimatePresence>
+        {restoreAsNewTarget && (
+          <div className="fixed inset-0 z-50 flex items-center justify-center bg-black/60 backdrop-blur-sm p-4">
+            <motion.div
+              initial={{ opacity: 0, scale: 0.95 }}
+              animate={{ opacity: 1, scale: 1 }}
+              exit={{ opacity: 0, scale: 0.95 }}
+              className="w-full max-w-md bg-[#252524] border border-[#E5B80B]/30 rounded-lg shadow-2xl p-6"
+            >
+              <div className="flex items-center gap-3 text-[#E5B80B] mb-4">
+                <CopyPlus size={24} />
+                <h3 className="text-xl font-bold">Restore as New Server</h3>
+              </div>
+              <p className="text-gray-300 mb-4 text-sm">
+                Creates a separate server from <span className="font-mono text-white">{restoreAsNewTarget}</span>. {activeServer.name} is not touched, and RCON is turned off in the copy.
+              </p>
+              <div className="space-y-3 mb-6">
+                <input
+                  value={newServerName}
+                  onChange={(e) => setNewServerName(e.target.value)}
+                  placeholder={`${activeServer.name} (restored)`}
+                  className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-3 py-2 text-sm text-white focus:outline-none focus:border-[#E5B80B]"
+                />
+                <input
+                  value={newServerPort}
+                  onChange={(e) => setNewServerPort(e.target.value.replace(/[^0-9]/g, ''))}
+                  inputMode="numeric"
+                  placeholder="Port (automatic)"
+                  className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-3 py-2 text-sm text-white focus:outline-none focus:border-[#E5B80B]"
+                />
+              </div>
+              <div className="flex justify-end gap-3">
+                <button
+                  onClick={() => setRestoreAsNewTarget(null)}
+                  disabled={restoring}
+                  className="px-4 py-2 bg-[#333] hover:bg-[#404040] text-gray-200 rounded font-medium"
+                >
+                  Cancel
+                </button>
+                <button
+                  onClick={() => handleRestoreAsNew(restoreAsNewTarget)}
+                  disabled={restoring}
+                  className="px-4 py-2 bg-[#E5B80B] hover:bg-[#d4a90a] text-black rounded font-bold flex items-center gap-2 disabled:opacity-50"
+                >
+                  {restoring && <Loader2 size={16} className="animate-spin" />}
+                  {restoring ? 'Creating...' : 'Create Server'}
+                </button>
+              </div>
+            </motion.div>
+          </div>
+        )}
+      </AnimatePresence>
+
       {/* Batch Delete Confirmation Modal */}
       <AnimatePresence>
         {batchDeleteConfirm && (
@@ -521,6 +729,53 @@ export const BackupsPage = () => {
                 ))}
               </div>
 
+              {selectedSchedule && (
+                <div className="grid grid-cols-2 gap-2 mb-6">
+                  {([
+                    { value: 'full', label: 'Full archive', desc: 'A complete .tar.gz every time' },
+                    { value: 'incremental', label: 'Incremental', desc: 'Only changed files take new space' },
+                  ] as const).map(opt => (
+                    <button
+                      key={opt.value}
+                      onClick={() => setSelectedMode(opt.value)}
+                      className={clsx(
+                        "px-3 py-2 rounded border text-left transition-all",
+                        selectedMode === opt.value
+                          ? "border-[#E5B80B] bg-[#E5B80B]/10 text-white"
+                          : "border-[#3a3a3a] bg-[#1a1a1a] text-gray-400 hover:border-[#E5B80B]/40 hover:text-white"
+                      )}
+                    >
+                      <div className="text-sm font-medium">{opt.label}</div>
+                      <div className="text-xs text-gray-500 mt-0.5">{opt.desc}</div>
+                    </button>
+                  ))}
+                </div>
+              )}
+
+              {selectedSchedule && availableTargets.length > 0 && (
+                <div className="mb-6">
+                  <div className="text-xs text-gray-500 mb-2">Upload full backups to</div>
+                  <div className="space-y-1">
+                    {availableTargets.map((target) => (
+                      <label key={target.id} className="flex items-center gap-2 text-sm text-gray-300 cursor-pointer">
+                        <input
+                          type="checkbox"
+                          checked={selectedTargets.includes(target.id)}
+                          onChange={(e) =>
+                            setSelectedTargets((prev) =>
+                              e.target.checked ? [...prev, target.id] : prev.filter((id) => id !== target.id)
+                            )
+                          }
+                          className="accent-[#E5B80B]"
+                        />
+                        {target.name}
+                        <span className="text-xs text-gray-500 uppercase">{target.type}</span>
+                      </label>
+                    ))}
+                  </div>
+                </div>
+              )}
+
               {nextBackup && currentSchedule && (
                 <p className="text-xs text-gray-500 mb-4">
                   Next backup: {format(new Date(nextBackup), 'MMM d, yyyy HH:mm')}
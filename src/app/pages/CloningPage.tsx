@@ -6,7 +6,7 @@ import { toast } from 'sonner';
 import clsx from 'clsx';
 import { useEscapeKey } from '../hooks/useEscapeKey';
 import { Checkbox } from '../components/ui/checkbox';
-import { apiRequest, toErrorMessage } from '../lib/api';
+import { apiRequest, toErrorMessage, waitForJob, Job } from '../lib/api';
 
 const findNextAvailablePort = (startPort: number, occupiedPorts: Set<number>) => {
   let port = Math.max(1024, startPort);
@@ -41,7 +41,8 @@ export const CloningPage = () => {
   const [options, setOptions] = useState({
     plugins: true,
     worlds: true,
-    config: true
+    config: true,
+    linkFiles: false
   });
 
   const [newPort, setNewPort] = useState(25566);
@@ -68,7 +69,7 @@ export const CloningPage = () => {
       for (let i = 0; i < sources.length; i += 1) {
         const source = sources[i];
         const cloneName = sources.length === 1 ? newName : `${source.name} (Clone)`;
-        await apiRequest(
+        const job = await apiRequest<Job>(
           '/api/servers/clone',
           {
             method: 'POST',
@@ -80,10 +81,13 @@ export const CloningPage = () => {
               copyPlugins: options.plugins,
               copyWorlds: options.worlds,
               copyConfig: options.config,
+              linkFiles: options.linkFiles,
             }),
           },
           `Failed to clone ${source.name}`
         );
+        // Copying worlds can take minutes; the clone runs as a job.
+        await waitForJob(job);
       }
       await refreshServers();
       toast.success(sources.length === 1 ? 'Server cloned successfully' : `${sources.length} servers cloned successfully`);
@@ -239,6 +243,19 @@ export const CloningPage = () => {
                     />
                     Copy Configuration Files
                   </label>
+                  <label className="flex items-start gap-2 text-gray-300 cursor-pointer select-none">
+                    <Checkbox
+                      checked={options.linkFiles}
+                      onCheckedChange={(checked) => setOptions({ ...options, linkFiles: checked === true })}
+                      className="mt-0.5"
+                    />
+                    <span>
+                      Share Disk With the Source
+                      <span className="block text-xs text-gray-500">
+                        Hard-links plugin and mod jars, and clones other files copy-on-write on Btrfs or XFS. Good for many test copies.
+                      </span>
+                    </span>
+                  </label>
                 </div>
 
                 {selectedSourceIds.size > 1 && (
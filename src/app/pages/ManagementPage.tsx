@@ -1,6 +1,6 @@
 import React, { useState, useEffect, useRef } from 'react';
 import { useServer } from '../context/ServerContext';
-import { Play, Square, AlertOctagon, RotateCw, Terminal, Folder, Users, ShieldAlert, Cpu, HardDrive, Clock, Calendar as CalendarIcon, AlertTriangle, Settings, Save, ChevronUp, ChevronDown } from 'lucide-react';
+import { Play, Pause, Square, AlertOctagon, RotateCw, Terminal, Folder, Users, ShieldAlert, Cpu, HardDrive, Clock, Calendar as CalendarIcon, AlertTriangle, Settings, Save, ChevronUp, ChevronDown } from 'lucide-react';
 import { AreaChart, Area, ResponsiveContainer } from 'recharts';
 import clsx from 'clsx';
 import { motion, AnimatePresence } from 'motion/react';
@@ -16,6 +16,25 @@ import { apiRequest, toErrorMessage } from '../lib/api';
 import { ConsoleView } from '../components/management/ConsoleView';
 import { FileBrowser } from '../components/management/FileBrowser';
 import { PlayerList } from '../components/management/PlayerList';
+import { BootFailureReport } from '../components/management/BootFailureReport';
+import { WarningMessagesCard } from '../components/management/WarningMessagesCard';
+import { RegionPruneCard } from '../components/management/RegionPruneCard';
+import { RconCard } from '../components/management/RconCard';
+import { LocateCard } from '../components/management/LocateCard';
+import { BlockHistoryCard } from '../components/management/BlockHistoryCard';
+import { WhitelistScheduleCard } from '../components/management/WhitelistScheduleCard';
+import { JoinCheckCard } from '../components/management/JoinCheckCard';
+import { MaintenanceCard } from '../components/management/MaintenanceCard';
+import { RestartVerificationCard } from '../components/management/RestartVerificationCard';
+import { ScheduledTasksCard } from '../components/management/ScheduledTasksCard';
+import { CrashRestartCard } from '../components/management/CrashRestartCard';
+import { ConsoleBufferCard } from '../components/management/ConsoleBufferCard';
+import { DiskQuotaCard } from '../components/management/DiskQuotaCard';
+import { NetworkCard } from '../components/management/NetworkCard';
+import { StatusPageCard } from '../components/management/StatusPageCard';
+import { WebhooksCard } from '../components/management/WebhooksCard';
+import { TempBansCard } from '../components/management/TempBansCard';
+import { ExportCard } from '../components/management/ExportCard';
 
 type Tab = 'console' | 'browse' | 'players';
 type RestartOption = 'now' | '5m' | '30m' | '1h' | '3h' | '6h' | 'custom';
@@ -182,7 +201,7 @@ const DateTimePopover = ({ value, onChange, placeholder = 'dd/mm/yyyy --:--' }:
 };
 
 export const ManagementPage = () => {
-  const { activeServer, startServer, stopServer, killServer, refreshServers } = useServer();
+  const { activeServer, startServer, stopServer, killServer, suspendServer, resumeServer, refreshServers } = useServer();
   const [activeTab, setActiveTab] = useState<Tab>('console');
   const [isLargeScreen, setIsLargeScreen] = useState(true);
   const playersUnsupportedForType = activeServer?.type === 'Velocity';
@@ -191,6 +210,7 @@ export const ManagementPage = () => {
   const [isRestartModalOpen, setIsRestartModalOpen] = useState(false);
   const [isStopModalOpen, setIsStopModalOpen] = useState(false);
   const [isSafeModeModalOpen, setIsSafeModeModalOpen] = useState(false);
+  const [isBootFailureOpen, setIsBootFailureOpen] = useState(false);
   const [isKillModalOpen, setIsKillModalOpen] = useState(false);
   const [isStopToEditModalOpen, setIsStopToEditModalOpen] = useState(false);
 
@@ -200,6 +220,9 @@ export const ManagementPage = () => {
   const [settingsMaxPlayers, setSettingsMaxPlayers] = useState('');
   const [settingsPort, setSettingsPort] = useState('');
   const [savingSettings, setSavingSettings] = useState(false);
+  const [readyDraft, setReadyDraft] = useState('');
+  const [savingReady, setSavingReady] = useState(false);
+  const [groupsDraft, setGroupsDraft] = useState('');
 
   // Convert MB string (e.g. "1024M") to GB number for display
   const mbToGb = (mb: string) => String(parseInt(mb?.replace('M', '') || '1024') / 1024);
@@ -211,6 +234,8 @@ export const ManagementPage = () => {
       setSettingsMaxRam(mbToGb(activeServer.maxRam));
       setSettingsMaxPlayers(String(activeServer.maxPlayers || 20));
       setSettingsPort(String(activeServer.port || 25565));
+      setReadyDraft((activeServer.readyCommands ?? []).join('\n'));
+      setGroupsDraft((activeServer.groups ?? []).join(', '));
     }
   }, [activeServer?.id, activeServer?.minRam, activeServer?.maxRam, activeServer?.maxPlayers, activeServer?.port]);
   
@@ -218,6 +243,7 @@ export const ManagementPage = () => {
   const [stopOption, setStopOption] = useState<StopOption>('5m');
   const [customTime, setCustomTime] = useState<Date | null>(null);
   const [customStopTime, setCustomStopTime] = useState<Date | null>(null);
+  const [scheduleReason, setScheduleReason] = useState('');
 
   useEffect(() => {
     const handleResize = () => setIsLargeScreen(window.innerWidth >= 1280);
@@ -275,7 +301,7 @@ export const ManagementPage = () => {
         {
           method: 'POST',
           headers: { 'Content-Type': 'application/json' },
-          body: JSON.stringify({ delaySeconds }),
+          body: JSON.stringify({ delaySeconds, reason: scheduleReason.trim() }),
         },
         'Failed to schedule restart'
       );
@@ -299,6 +325,7 @@ export const ManagementPage = () => {
 
     setCustomTime(null);
     setRestartOption('5m');
+    setScheduleReason('');
   };
 
   const handleScheduleStop = async () => {
@@ -337,7 +364,7 @@ export const ManagementPage = () => {
         {
           method: 'POST',
           headers: { 'Content-Type': 'application/json' },
-          body: JSON.stringify({ delaySeconds }),
+          body: JSON.stringify({ delaySeconds, reason: scheduleReason.trim() }),
         },
         'Failed to schedule stop'
       );
@@ -358,6 +385,7 @@ export const ManagementPage = () => {
 
     setCustomStopTime(null);
     setStopOption('5m');
+    setScheduleReason('');
   };
 
   const handleSafeMode = async () => {
@@ -438,6 +466,52 @@ export const ManagementPage = () => {
     }
   };
 
+  const handleSaveReadyCommands = async () => {
+    if (!activeServer) return;
+    setSavingReady(true);
+    try {
+      await apiRequest(
+        `/api/servers/${activeServer.id}/ready-commands`,
+        {
+          method: 'PUT',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({ commands: readyDraft.split('\n') }),
+        },
+        'Failed to save ready commands'
+      );
+      toast.success('Ready commands saved');
+      await refreshServers();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save ready commands'));
+    } finally {
+      setSavingReady(false);
+    }
+  };
+
+  const handleSaveGroups = async () => {
+    if (!activeServer) return;
+    const groups = groupsDraft.split(',').map((g) => g.trim()).filter(Boolean);
+    if (groups.join(', ') === (activeServer.groups ?? []).join(', ')) return;
+    try {
+      await apiRequest(
+        `/api/servers/${activeServer.id}/groups`,
+        {
+          method: 'PUT',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({ groups }),
+        },
+        'Failed to save groups'
+      );
+      toast.success('Groups saved');
+      await refreshServers();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save groups'));
+      setGroupsDraft((activeServer.groups ?? []).join(', '));
+    }
+  };
+
+  const readyChanged = activeServer && readyDraft.trim() !== (activeServer.readyCommands ?? []).join('\n');
+
   const settingsChanged = activeServer && (
     settingsMinRam !== mbToGb(activeServer.minRam) ||
     settingsMaxRam !== mbToGb(activeServer.maxRam) ||
@@ -450,7 +524,7 @@ export const ManagementPage = () => {
   }
 
   // Derived state for button enabling
-  const isServerOff = activeServer.status !== 'Running' && activeServer.status !== 'Booting' && activeServer.status !== 'Installing';
+  const isServerOff = activeServer.status !== 'Running' && activeServer.status !== 'Booting' && activeServer.status !== 'Installing' && activeServer.status !== 'Suspended';
 
   return (
     <div className="flex flex-col h-full bg-[#1e1e1d]">
@@ -463,6 +537,7 @@ export const ManagementPage = () => {
               ${activeServer.status === 'Running' ? 'bg-green-900/40 text-green-400 border border-green-800' :
                 activeServer.status === 'Crashed' || activeServer.status === 'Error' ? 'bg-red-900/40 text-red-400 border border-red-800' :
                 activeServer.status === 'Installing' ? 'bg-blue-900/40 text-blue-400 border border-blue-800' :
+                activeServer.status === 'Suspended' ? 'bg-purple-900/40 text-purple-400 border border-purple-800' :
                 'bg-gray-700/40 text-gray-400 border border-gray-600'}`}>
               {activeServer.status}
             </span>
@@ -470,6 +545,30 @@ export const ManagementPage = () => {
           <div className="text-xs text-gray-500 mt-1 font-mono">
             {activeServer.type} {activeServer.version} • Port: {activeServer.port}
           </div>
+          {activeServer.failureReason === 'port_in_use' && activeServer.portConflict && (
+            <div className="mt-2 text-xs text-red-400">
+              Port {activeServer.portConflict.port} is already in use
+              {activeServer.portConflict.serverName
+                ? ` by panel server "${activeServer.portConflict.serverName}"`
+                : activeServer.portConflict.pid
+                  ? ` by ${activeServer.portConflict.process || 'PID'} (PID ${activeServer.portConflict.pid}), outside this panel`
+                  : ' by a process the panel cannot see'}
+              .
+            </div>
+          )}
+          {activeServer.memoryWarning && (
+            <div className="mt-2 flex items-start gap-1.5 text-xs text-yellow-400 max-w-xl">
+              <AlertTriangle size={14} className="shrink-0" /> {activeServer.memoryWarning}
+            </div>
+          )}
+          {activeServer.bootFailedAt && isServerOff && (
+            <button
+              onClick={() => setIsBootFailureOpen(true)}
+              className="mt-2 flex items-center gap-1.5 text-xs text-red-400 hover:text-red-300"
+            >
+              <AlertTriangle size={14} /> Last boot failed. View report
+            </button>
+          )}
         </div>
 
         <div className="flex flex-wrap items-center gap-3">
@@ -509,6 +608,23 @@ export const ManagementPage = () => {
 	               >
 	                 <Square size={18} fill="currentColor" /> Stop
 	               </button>
+               {(activeServer.status === 'Running' || activeServer.status === 'Suspended') && (
+                 <button
+                   onClick={async () => {
+                     const suspended = activeServer.status === 'Suspended';
+                     try {
+                       await (suspended ? resumeServer(activeServer.id) : suspendServer(activeServer.id));
+                       toast.success(suspended ? 'Server resumed' : 'Server suspended');
+                     } catch (err) {
+                       toast.error(toErrorMessage(err, suspended ? 'Failed to resume' : 'Failed to suspend'));
+                     }
+                   }}
+                   title={activeServer.status === 'Suspended' ? 'Continue the frozen server' : 'Freeze the process to free CPU; memory is kept'}
+                   className="flex items-center gap-2 px-4 py-2 bg-[#2a2a29] border border-[#404040] hover:bg-[#333] text-gray-200 rounded font-medium transition-colors"
+                 >
+                   {activeServer.status === 'Suspended' ? <><Play size={18} /> Resume</> : <><Pause size={18} /> Suspend</>}
+                 </button>
+               )}
 	               <button
 	                 onClick={() => setIsStopModalOpen(true)}
 	                 className="flex items-center gap-2 px-4 py-2 bg-[#2a2a29] border border-[#404040] hover:bg-[#333] text-gray-200 rounded font-medium transition-colors"
@@ -592,6 +708,21 @@ export const ManagementPage = () => {
                           />
                         </div>
                       )}
+                      {showValue && activeServer.tickMetrics && activeServer.tickMetrics.dimensions.length > 0 && (
+                        <div className="mt-3 space-y-1">
+                          {activeServer.tickMetrics.dimensions.map(dim => (
+                            <div key={dim.dimension} className="flex justify-between text-xs font-mono">
+                              <span className="text-gray-400 truncate mr-2" title={dim.dimension}>{dim.dimension}</span>
+                              <span className={clsx(
+                                dim.tps >= 18 ? "text-green-400" :
+                                dim.tps >= 15 ? "text-yellow-400" : "text-red-400"
+                              )}>
+                                {dim.tps.toFixed(1)} · {dim.meanTickMs.toFixed(1)} ms
+                              </span>
+                            </div>
+                          ))}
+                        </div>
+                      )}
                     </div>
                   );
 
@@ -749,6 +880,81 @@ export const ManagementPage = () => {
                )}
              </div>
 
+             <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+               <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">Groups</h4>
+               <input
+                 value={groupsDraft}
+                 onChange={(e) => setGroupsDraft(e.target.value)}
+                 onBlur={handleSaveGroups}
+                 onKeyDown={(e) => { if (e.key === 'Enter') handleSaveGroups(); }}
+                 placeholder="network, lobby"
+                 className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]"
+               />
+               <p className="text-[11px] text-gray-500">Comma separated. Group totals are available from <span className="font-mono">/api/groups/&lt;name&gt;/summary</span>.</p>
+             </div>
+
+             {/* Commands run once the server reports it is ready */}
+             <div className="bg-[#202020] rounded-lg border border-[#333] p-4 space-y-2">
+               <div className="flex items-center gap-2">
+                 <Terminal size={14} className="text-gray-400" />
+                 <h4 className="text-gray-400 text-xs uppercase font-bold tracking-wider">When Ready</h4>
+               </div>
+               <p className="text-[11px] text-gray-500">Console commands sent in order each time the server finishes booting. One per line.</p>
+               <textarea
+                 value={readyDraft}
+                 onChange={(e) => setReadyDraft(e.target.value)}
+                 rows={3}
+                 placeholder={'whitelist off\nsay Server is open!'}
+                 className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-2 py-1.5 text-xs text-white font-mono focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]"
+               />
+               {readyChanged && (
+                 <button
+                   onClick={handleSaveReadyCommands}
+                   disabled={savingReady}
+                   className="w-full py-2 bg-[#E5B80B] text-black rounded font-bold text-sm hover:bg-[#d4a90a] transition-colors flex items-center justify-center gap-2 disabled:opacity-50"
+                 >
+                   <Save size={14} />
+                   {savingReady ? 'Saving...' : 'Save Commands'}
+                 </button>
+               )}
+             </div>
+
+             <WarningMessagesCard server={activeServer} onSaved={refreshServers} />
+
+             <RconCard server={activeServer} onSaved={refreshServers} />
+
+             <LocateCard server={activeServer} />
+
+             <BlockHistoryCard server={activeServer} />
+
+             <TempBansCard server={activeServer} />
+
+             <WhitelistScheduleCard server={activeServer} />
+
+             <JoinCheckCard server={activeServer} />
+
+             <ScheduledTasksCard server={activeServer} />
+
+             <MaintenanceCard server={activeServer} />
+
+             <RestartVerificationCard server={activeServer} />
+
+             <WebhooksCard server={activeServer} />
+
+             <CrashRestartCard server={activeServer} />
+
+             <ConsoleBufferCard server={activeServer} />
+
+             <DiskQuotaCard server={activeServer} />
+
+             {isVelocityProxy && <NetworkCard server={activeServer} />}
+
+             <StatusPageCard server={activeServer} />
+
+             <RegionPruneCard server={activeServer} />
+
+             <ExportCard server={activeServer} />
+
              <div className="mt-auto">
                <button
                 onClick={() => setIsRestartModalOpen(true)}
@@ -806,6 +1012,17 @@ export const ManagementPage = () => {
                     </motion.div>
                   )}
                 </AnimatePresence>
+
+                <div>
+                  <label className="block text-sm text-gray-400 mb-2">Reason (optional):</label>
+                  <input
+                    value={scheduleReason}
+                    onChange={(e) => setScheduleReason(e.target.value)}
+                    maxLength={200}
+                    placeholder="Plugin update"
+                    className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded p-3 text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]"
+                  />
+                </div>
               </div>
 
               <div className="flex justify-end gap-3">
@@ -871,6 +1088,17 @@ export const ManagementPage = () => {
                     </motion.div>
                   )}
                 </AnimatePresence>
+
+                <div>
+                  <label className="block text-sm text-gray-400 mb-2">Reason (optional):</label>
+                  <input
+                    value={scheduleReason}
+                    onChange={(e) => setScheduleReason(e.target.value)}
+                    maxLength={200}
+                    placeholder="Plugin update"
+                    className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded p-3 text-white focus:outline-none focus:border-[#E5B80B] focus:ring-1 focus:ring-[#E5B80B]"
+                  />
+                </div>
               </div>
 
               <div className="flex justify-end gap-3">
@@ -892,6 +1120,12 @@ export const ManagementPage = () => {
         )}
       </AnimatePresence>
 
+      <AnimatePresence>
+        {isBootFailureOpen && (
+          <BootFailureReport serverId={activeServer.id} onClose={() => setIsBootFailureOpen(false)} />
+        )}
+      </AnimatePresence>
+
       {/* Safe Mode Modal */}
       <AnimatePresence>
         {isSafeModeModalOpen && (
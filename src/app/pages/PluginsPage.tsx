@@ -1,13 +1,15 @@
 import React, { useState, useEffect, useRef, useCallback } from 'react';
-import { useServer, Plugin } from '../context/ServerContext';
-import { Upload, Trash2, RefreshCw, AlertTriangle, AlertCircle, CheckCircle, XCircle, Loader2, ArrowDownCircle, Cloud, Check, Square, Save, Pencil } from 'lucide-react';
+import { useServer, Plugin, ExtensionDirectory } from '../context/ServerContext';
+import { Upload, Trash2, RefreshCw, AlertTriangle, AlertCircle, CheckCircle, XCircle, Loader2, ArrowDownCircle, Cloud, Check, Square, Save, Pencil, StickyNote, FileCog } from 'lucide-react';
 import { motion, AnimatePresence } from 'motion/react';
 import { toast } from 'sonner';
 import { Tooltip, TooltipTrigger, TooltipContent } from '../components/ui/tooltip';
 import clsx from 'clsx';
 import { useEscapeKey } from '../hooks/useEscapeKey';
 import { useStagedDeleteUndo } from '../hooks/useStagedDeleteUndo';
-import { apiRequest, toErrorMessage } from '../lib/api';
+import { ApiError, apiRequest, csrfHeaders, toErrorMessage } from '../lib/api';
+import { PluginUpdateOverview } from '../components/PluginUpdateOverview';
+import { PluginConfigEditor } from '../components/PluginConfigEditor';
 
 type UploadConflictAction = 'prompt' | 'replace' | 'skip';
 
@@ -37,6 +39,38 @@ class UploadConflictError extends Error {
   }
 }
 
+interface QuarantinedPlugin {
+  id: string;
+  fileName: string;
+  size: string;
+  quarantinedAt: string;
+  report: {
+    findings?: string[];
+    virusTotal?: { link?: string };
+  };
+}
+
+class QuarantinedUploadError extends Error {
+  entry: QuarantinedPlugin;
+
+  constructor(entry: QuarantinedPlugin) {
+    super(`${entry.fileName} was quarantined`);
+    this.name = 'QuarantinedUploadError';
+    this.entry = entry;
+  }
+}
+
+interface CatalogResult {
+  source: 'modrinth' | 'spiget';
+  projectId: string;
+  name: string;
+  description?: string;
+  author?: string;
+  downloads: number;
+  iconUrl?: string;
+  pageUrl: string;
+}
+
 interface PluginWithUpdate extends Plugin {
   latestVersion?: string;
   versionStatus?: 'latest' | 'outdated' | 'incompatible' | 'unknown';
@@ -50,14 +84,26 @@ interface StickyUpdateInfo {
   checkedVersion?: string;
 }
 
+const formatProvenance = (plugin: Plugin): string | undefined => {
+  const p = plugin.provenance;
+  if (!p?.installedAt) return undefined;
+  const lines = [`Installed ${new Date(p.installedAt).toLocaleString()}${p.origin === 'upload' ? ' (uploaded)' : p.origin === 'url' && p.downloadUrl ? ` from ${p.downloadUrl}` : ''}`];
+  if (p.origin === 'update' && p.updatedAt) {
+    lines.push(`Updated ${new Date(p.updatedAt).toLocaleString()}${p.downloadUrl ? ` from ${p.downloadUrl}` : ''}`);
+  }
+  if (p.sha256) lines.push(`SHA-256 ${p.sha256}`);
+  return lines.join('\n');
+};
+
 export const PluginsPage = () => {
-  const { activeServer } = useServer();
+  const { activeServer, refreshServers } = useServer();
   const activeServerId = activeServer?.id ?? null;
   const [plugins, setPlugins] = useState<PluginWithUpdate[]>([]);
   const [loading, setLoading] = useState(true);
   const [isUploadModalOpen, setIsUploadModalOpen] = useState(false);
   const [deleteTarget, setDeleteTarget] = useState<string | null>(null);
   const [uploading, setUploading] = useState(false);
+  const [uploadDir, setUploadDir] = useState<'' | ExtensionDirectory>('');
   const [checkingUpdates, setCheckingUpdates] = useState(false);
   const [updatingPlugin, setUpdatingPlugin] = useState<string | null>(null);
   const [updatingAll, setUpdatingAll] = useState(false);
@@ -65,6 +111,7 @@ export const PluginsPage = () => {
   const [lastCheckedAt, setLastCheckedAt] = useState<Date | null>(null);
   const [restartPromptOpen, setRestartPromptOpen] = useState(false);
   const [updateAllPromptOpen, setUpdateAllPromptOpen] = useState(false);
+  const [overviewOpen, setOverviewOpen] = useState(false);
   const [pendingUpdate, setPendingUpdate] = useState<PluginWithUpdate | null>(null);
   const [selectedPlugins, setSelectedPlugins] = useState<Set<string>>(new Set());
   const fileInputRef = useRef<HTMLInputElement>(null);
@@ -75,16 +122,26 @@ export const PluginsPage = () => {
   const [pendingSource, setPendingSource] = useState<{ fileName: string; url: string } | null>(null);
   const [savingSourceFor, setSavingSourceFor] = useState<string | null>(null);
   const [editingSources, setEditingSources] = useState<Set<string>>(new Set());
+  const [noteEditing, setNoteEditing] = useState<{ fileName: string; draft: string } | null>(null);
+  const [savingNote, setSavingNote] = useState(false);
+  const [configPlugin, setConfigPlugin] = useState<string | null>(null);
   const [uploadConflict, setUploadConflict] = useState<UploadConflictState | null>(null);
   const [duplicateInstalledModalOpen, setDuplicateInstalledModalOpen] = useState(false);
   const [uploadMaxBytes, setUploadMaxBytes] = useState(256 * 1024 * 1024);
   const [pendingDeletedPluginFiles, setPendingDeletedPluginFiles] = useState<Set<string>>(new Set());
+  const [quarantined, setQuarantined] = useState<QuarantinedPlugin[]>([]);
+  const [installUrl, setInstallUrl] = useState('');
+  const [searchQuery, setSearchQuery] = useState('');
+  const [searchResults, setSearchResults] = useState<CatalogResult[] | null>(null);
+  const [searching, setSearching] = useState(false);
+  const [resolvingQuarantine, setResolvingQuarantine] = useState<string | null>(null);
   const uploadConflictResolverRef = useRef<((action: Exclude<UploadConflictAction, 'prompt'>) => void) | null>(null);
   const { stageDelete, undoOverlay } = useStagedDeleteUndo();
 
   const isServerOff = activeServer?.status === 'Stopped' || activeServer?.status === 'Crashed' || activeServer?.status === 'Error';
 
   const isModded = activeServer?.type === 'Forge' || activeServer?.type === 'Fabric' || activeServer?.type === 'NeoForge';
+  const isProxy = activeServer?.type === 'Velocity';
   const itemLabel = isModded ? 'mod' : 'plugin';
   const itemLabelPlural = isModded ? 'mods' : 'plugins';
   const itemLabelCap = isModded ? 'Mod' : 'Plugin';
@@ -121,6 +178,45 @@ export const PluginsPage = () => {
     fetchPlugins(activeServerId);
   }, [fetchPlugins, activeServerId]);
 
+  const fetchQuarantine = useCallback(async () => {
+    if (!activeServerId) return;
+    try {
+      const data = await apiRequest<QuarantinedPlugin[]>(
+        `/api/servers/${activeServerId}/plugins/quarantine`,
+        undefined,
+        'Failed to fetch quarantined uploads'
+      );
+      setQuarantined(data || []);
+    } catch (err) {
+      console.error(toErrorMessage(err, 'Failed to fetch quarantined uploads'));
+    }
+  }, [activeServerId]);
+
+  useEffect(() => {
+    fetchQuarantine();
+  }, [fetchQuarantine]);
+
+  const resolveQuarantine = async (entry: QuarantinedPlugin, approve: boolean) => {
+    if (!activeServerId) return;
+    setResolvingQuarantine(entry.id);
+    try {
+      await apiRequest(
+        approve
+          ? `/api/servers/${activeServerId}/plugins/quarantine/${entry.id}/approve`
+          : `/api/servers/${activeServerId}/plugins/quarantine/${entry.id}`,
+        { method: approve ? 'POST' : 'DELETE' },
+        approve ? `Failed to install ${entry.fileName}` : `Failed to discard ${entry.fileName}`
+      );
+      toast.success(approve ? `${entry.fileName} installed` : `${entry.fileName} discarded`);
+      if (approve) fetchPlugins();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Couldn’t resolve quarantined file. Try again.'));
+    } finally {
+      setResolvingQuarantine(null);
+      fetchQuarantine();
+    }
+  };
+
   useEffect(() => {
     let cancelled = false;
     apiRequest<{ maxUploadBytes?: number }>('/api/settings', undefined, 'Failed to load settings')
@@ -199,6 +295,13 @@ export const PluginsPage = () => {
 
   // Restart handled manually by user after updates.
 
+  // Entries outside the default plugins/mods folder need their directory on
+  // toggle/delete requests.
+  const dirQuery = (fileName: string) => {
+    const dir = plugins.find((p) => p.fileName === fileName)?.directory;
+    return dir ? `?dir=${encodeURIComponent(dir)}` : '';
+  };
+
   const handleDelete = (fileName: string) => {
     if (!activeServer) return;
     setDeleteTarget(null);
@@ -212,6 +315,7 @@ export const PluginsPage = () => {
       next.add(fileName);
       return next;
     });
+    const query = dirQuery(fileName);
     stageDelete({
       label: `${itemLabelCap} "${fileName}"`,
       successMessage: `${itemLabelCap} deleted`,
@@ -225,7 +329,7 @@ export const PluginsPage = () => {
       },
       onCommit: async () => {
         await apiRequest(
-          `/api/servers/${activeServer.id}/plugins/${encodeURIComponent(fileName)}`,
+          `/api/servers/${activeServer.id}/plugins/${encodeURIComponent(fileName)}${query}`,
           { method: 'DELETE' },
           `Failed to delete ${itemLabel}`
         );
@@ -243,7 +347,7 @@ export const PluginsPage = () => {
     if (!activeServer) return;
     try {
       await apiRequest(
-        `/api/servers/${activeServer.id}/plugins/${encodeURIComponent(fileName)}/toggle`,
+        `/api/servers/${activeServer.id}/plugins/${encodeURIComponent(fileName)}/toggle${dirQuery(fileName)}`,
         { method: 'PUT' },
         `Failed to toggle ${itemLabel}`
       );
@@ -254,6 +358,35 @@ export const PluginsPage = () => {
     }
   };
 
+  // Boots once with only the selected entries disabled; the backend renames
+  // them back when the server stops.
+  const handleSafeModeSelected = async () => {
+    if (!activeServer || selectedPlugins.size === 0) return;
+    const disable = visiblePlugins
+      .filter((p) => selectedPlugins.has(p.fileName) && p.enabled)
+      .map((p) => ({ directory: p.directory ?? '', fileName: p.fileName }));
+    if (disable.length === 0) {
+      toast.info(`No enabled ${itemLabelPlural} selected`);
+      return;
+    }
+    try {
+      await apiRequest(
+        `/api/servers/${activeServer.id}/start-safe`,
+        {
+          method: 'POST',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({ disable }),
+        },
+        'Failed to start in safe mode'
+      );
+      toast.success(`Starting with ${disable.length} ${disable.length === 1 ? itemLabel : itemLabelPlural} disabled for this boot`);
+      setSelectedPlugins(new Set());
+      fetchPlugins();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to start in safe mode'));
+    }
+  };
+
   const handleDisableSelected = async () => {
     if (!activeServer || selectedPlugins.size === 0) return;
     setUpdatingAll(true);
@@ -265,7 +398,7 @@ export const PluginsPage = () => {
       }
       for (const p of toDisable) {
         await apiRequest(
-          `/api/servers/${activeServer.id}/plugins/${encodeURIComponent(p.fileName)}/toggle`,
+          `/api/servers/${activeServer.id}/plugins/${encodeURIComponent(p.fileName)}/toggle${dirQuery(p.fileName)}`,
           { method: 'PUT' },
           `Failed to disable ${p.fileName}`
         );
@@ -283,6 +416,7 @@ export const PluginsPage = () => {
   const handleDeleteSelected = () => {
     if (!activeServer || selectedPlugins.size === 0) return;
     const names = Array.from(selectedPlugins);
+    const queries = new Map(names.map((name) => [name, dirQuery(name)]));
     setSelectedPlugins(new Set());
     setPendingDeletedPluginFiles((prev) => {
       const next = new Set(prev);
@@ -303,7 +437,7 @@ export const PluginsPage = () => {
       onCommit: async () => {
         for (const name of names) {
           await apiRequest<void>(
-            `/api/servers/${activeServer.id}/plugins/${encodeURIComponent(name)}`,
+            `/api/servers/${activeServer.id}/plugins/${encodeURIComponent(name)}${queries.get(name) ?? ''}`,
             { method: 'DELETE' },
             `Failed to delete ${name}`
           );
@@ -331,8 +465,12 @@ export const PluginsPage = () => {
     const formData = new FormData();
     formData.append('file', file);
     formData.append('conflictAction', conflictAction);
+    // Datapacks always go to the world's datapacks folder.
+    const dir = file.name.toLowerCase().endsWith('.zip') ? 'datapacks' : uploadDir;
+    if (dir) formData.append('dir', dir);
     const res = await fetch(`/api/servers/${activeServer?.id}/plugins`, {
       method: 'POST',
+      headers: csrfHeaders(),
       body: formData,
     });
 
@@ -348,6 +486,12 @@ export const PluginsPage = () => {
     if (res.status === 413) {
       throw new Error(`File exceeds maximum allowed size (${uploadMaxMb} MB).`);
     }
+    if (res.status === 422) {
+      const payload = await res.json().catch(() => ({} as { error?: string; quarantine?: QuarantinedPlugin }));
+      if (payload?.error === 'quarantined' && payload.quarantine) {
+        throw new QuarantinedUploadError(payload.quarantine);
+      }
+    }
 
     const data = await res.json().catch(() => ({} as { error?: string; status?: string }));
     if (!res.ok) {
@@ -365,9 +509,11 @@ export const PluginsPage = () => {
     try {
       let uploadedCount = 0;
       let skippedCount = 0;
+      let quarantinedCount = 0;
       for (const file of Array.from(fileList)) {
-        if (!file.name.toLowerCase().endsWith('.jar')) {
-          toast.error(`${file.name} is not a .jar file`);
+        const lowerName = file.name.toLowerCase();
+        if (!lowerName.endsWith('.jar') && (!lowerName.endsWith('.zip') || isProxy)) {
+          toast.error(`${file.name} is not a .jar file${isProxy ? '' : ' or .zip datapack'}`);
           continue;
         }
 
@@ -386,6 +532,13 @@ export const PluginsPage = () => {
               setDuplicateInstalledModalOpen(true);
               throw err;
             }
+            if (err instanceof QuarantinedUploadError) {
+              quarantinedCount += 1;
+              toast.warning(`${file.name} was quarantined`, {
+                description: (err.entry.report.findings || []).join('; '),
+              });
+              break;
+            }
             if (err instanceof UploadConflictError) {
               const choice = await requestConflictAction(err.fileName);
               uploadConflictResolverRef.current = null;
@@ -405,11 +558,12 @@ export const PluginsPage = () => {
         toast.success(`Uploaded ${uploadedCount} ${uploadedCount === 1 ? itemLabel : itemLabelPlural}, skipped ${skippedCount}`);
       } else if (uploadedCount > 0) {
         toast.success(`${itemLabelCap}(s) uploaded successfully`);
-      } else {
+      } else if (skippedCount > 0 || quarantinedCount === 0) {
         toast.info(skippedCount === 1 ? `${itemLabelCap} skipped` : `Skipped ${skippedCount} ${itemLabelPlural}`);
       }
       setIsUploadModalOpen(false);
       fetchPlugins();
+      if (quarantinedCount > 0) fetchQuarantine();
     } catch (err) {
       if (err instanceof DuplicateInstalledError) {
         return;
@@ -425,6 +579,113 @@ export const PluginsPage = () => {
     }
   };
 
+  // Installs a jar the backend downloads itself, from a link or a catalog
+  // project, asking before replacing a file of the same name. Returns
+  // whether the modal can close.
+  const installRemote = async (path: string, payload: Record<string, string>, label: string, failure: string): Promise<boolean> => {
+    if (!activeServerId) return false;
+    setUploading(true);
+    try {
+      let action: UploadConflictAction = 'prompt';
+      for (;;) {
+        try {
+          const result = await apiRequest<{ status: string; name: string }>(
+            `/api/servers/${activeServerId}/plugins/${path}`,
+            {
+              method: 'POST',
+              headers: { 'Content-Type': 'application/json' },
+              body: JSON.stringify({ ...payload, conflictAction: action }),
+            },
+            failure
+          );
+          if (result.status === 'skipped') {
+            toast.info(`${itemLabelCap} skipped`);
+          } else {
+            toast.success(`Installed ${result.name}`);
+          }
+          break;
+        } catch (err) {
+          if (!(err instanceof ApiError)) throw err;
+          const details = (err.details || {}) as { name?: string; quarantine?: QuarantinedPlugin };
+          if (err.code === 'already_installed') {
+            setDuplicateInstalledModalOpen(true);
+            return false;
+          }
+          if (err.code === 'quarantined' && details.quarantine) {
+            toast.warning(`${details.quarantine.fileName} was quarantined`, {
+              description: (details.quarantine.report.findings || []).join('; '),
+            });
+            setIsUploadModalOpen(false);
+            fetchQuarantine();
+            return false;
+          }
+          if (err.code === 'file_exists') {
+            const choice = await requestConflictAction(details.name || label);
+            uploadConflictResolverRef.current = null;
+            setUploadConflict(null);
+            if (choice === 'skip') {
+              toast.info(`${itemLabelCap} skipped`);
+              break;
+            }
+            action = 'replace';
+            continue;
+          }
+          throw err;
+        }
+      }
+      setIsUploadModalOpen(false);
+      fetchPlugins();
+      return true;
+    } catch (err) {
+      toast.error(toErrorMessage(err, `${failure}. Try again.`));
+      return false;
+    } finally {
+      if (uploadConflictResolverRef.current) {
+        uploadConflictResolverRef.current('skip');
+        uploadConflictResolverRef.current = null;
+      }
+      setUploadConflict(null);
+      setUploading(false);
+    }
+  };
+
+  const handleInstallFromUrl = async () => {
+    const url = installUrl.trim();
+    if (!url) return;
+    if (await installRemote('from-url', { url }, url, `Couldn’t install ${itemLabel} from URL`)) {
+      setInstallUrl('');
+    }
+  };
+
+  const handleSearchCatalog = async () => {
+    const q = searchQuery.trim();
+    if (!q || !activeServerId) return;
+    setSearching(true);
+    try {
+      const params = new URLSearchParams({ q, serverId: activeServerId });
+      const data = await apiRequest<{ results: CatalogResult[]; errors?: string[] }>(
+        `/api/plugins/search?${params.toString()}`,
+        undefined,
+        `Couldn’t search for ${itemLabelPlural}`
+      );
+      setSearchResults(data.results);
+      if (data.errors?.length) {
+        toast.warning('Some catalogs could not be searched', { description: data.errors.join('; ') });
+      }
+    } catch (err) {
+      toast.error(toErrorMessage(err, `Couldn’t search for ${itemLabelPlural}`));
+    } finally {
+      setSearching(false);
+    }
+  };
+
+  const handleInstallFromCatalog = async (result: CatalogResult) => {
+    if (await installRemote('install', { source: result.source, projectId: result.projectId }, result.name, `Couldn’t install ${result.name}`)) {
+      setSearchResults(null);
+      setSearchQuery('');
+    }
+  };
+
   const handleCheckUpdates = async () => {
     if (!activeServer) return;
     setCheckingUpdates(true);
@@ -438,13 +699,35 @@ export const PluginsPage = () => {
     }
   };
 
+  const handleUpdateChannelChange = async (channel: string) => {
+    if (!activeServer) return;
+    try {
+      await apiRequest(
+        `/api/servers/${activeServer.id}/plugins/update-channel`,
+        {
+          method: 'PUT',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({ channel }),
+        },
+        'Failed to change update channel'
+      );
+      await refreshServers();
+      setUpdatesChecked(false);
+      setStickyUpdates({});
+      toast.success(channel === 'prerelease' ? 'Update checks now include betas and RCs' : 'Update checks now use stable releases only');
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to change update channel'));
+    }
+  };
+
+  // Running servers get the update staged; it is swapped in on the next stop or restart.
   const sendPluginUpdate = async (serverID: string, fileName: string, url: string) => {
     await apiRequest(
       `/api/servers/${serverID}/plugins/${encodeURIComponent(fileName)}/update`,
       {
         method: 'POST',
         headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ url }),
+        body: JSON.stringify({ url, stage: !isServerOff }),
       },
       'Failed to update plugin'
     );
@@ -486,12 +769,6 @@ export const PluginsPage = () => {
       setRestartPromptOpen(false);
       return;
     }
-    // Require server to be stopped before applying update to avoid corrupting plugin data
-    if (!isServerOff) {
-      toast.error('Stop the server before applying updates to avoid data corruption');
-      setRestartPromptOpen(false);
-      return;
-    }
     setRestartPromptOpen(false);
     await runSingleUpdate(pendingUpdate);
   };
@@ -512,12 +789,6 @@ export const PluginsPage = () => {
       return;
     }
 
-    // Require server to be stopped before applying updates
-    if (!isServerOff) {
-      toast.error('Stop the server before applying updates to avoid data corruption');
-      setUpdateAllPromptOpen(false);
-      return;
-    }
 
     setUpdateAllPromptOpen(false);
     setUpdatingAll(true);
@@ -573,7 +844,7 @@ export const PluginsPage = () => {
       toast.success('Update successful');
       return;
     }
-    toast.success('Update complete. A server restart is required for the updates to take effect.');
+    toast.success('Update staged. It will be installed when the server next stops or restarts.');
   };
 
   const handleTogglePlugin = (fileName: string) => {
@@ -707,6 +978,29 @@ export const PluginsPage = () => {
     }
   };
 
+  const saveNote = async () => {
+    if (!activeServer || !noteEditing) return;
+    setSavingNote(true);
+    try {
+      await apiRequest(
+        `/api/servers/${activeServer.id}/plugins/${encodeURIComponent(noteEditing.fileName)}/note${dirQuery(noteEditing.fileName)}`,
+        {
+          method: 'PUT',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({ note: noteEditing.draft }),
+        },
+        'Failed to save note'
+      );
+      toast.success(noteEditing.draft.trim() ? 'Note saved' : 'Note cleared');
+      setNoteEditing(null);
+      fetchPlugins();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to save note'));
+    } finally {
+      setSavingNote(false);
+    }
+  };
+
   const startEditingSource = (plugin: PluginWithUpdate) => {
     setSourceDrafts(prev => ({ ...prev, [plugin.fileName]: prev[plugin.fileName] ?? plugin.sourceUrl ?? '' }));
     setEditingSources(prev => {
@@ -746,6 +1040,14 @@ export const PluginsPage = () => {
         ) : (
           <span className="text-gray-300 text-sm font-mono">{plugin.version}</span>
         )}
+        {plugin.pendingUpdate && (
+          <span
+            className="text-xs px-1.5 py-0.5 rounded border border-sky-700 text-sky-300"
+            title={`Staged ${new Date(plugin.pendingUpdate.stagedAt).toLocaleString()}`}
+          >
+            {plugin.pendingUpdate.version} on restart
+          </span>
+        )}
       </div>
     );
   };
@@ -776,6 +1078,17 @@ export const PluginsPage = () => {
             </span>
           )}
 
+          {/* Update channel: stable releases only, or betas/RCs too */}
+          <select
+            value={activeServer.pluginUpdateChannel ?? 'stable'}
+            onChange={(e) => handleUpdateChannelChange(e.target.value)}
+            title="Which releases update checks consider for this server"
+            className="px-3 py-2 bg-[#252524] border border-[#404040] text-gray-200 rounded text-sm focus:outline-none focus:border-[#E5B80B]"
+          >
+            <option value="stable">Stable only</option>
+            <option value="prerelease">Include betas/RCs</option>
+          </select>
+
           {/* Check for updates (cloud) */}
           <button
             onClick={handleCheckUpdates}
@@ -786,6 +1099,15 @@ export const PluginsPage = () => {
             {checkingUpdates ? 'Checking...' : 'Check for updates'}
           </button>
 
+          {/* Panel-wide update overview */}
+          <button
+            onClick={() => setOverviewOpen(true)}
+            className="flex items-center gap-2 px-4 py-2 bg-[#252524] border border-[#404040] text-gray-200 rounded font-medium hover:bg-[#333] transition-colors"
+          >
+            <Cloud size={18} />
+            All servers
+          </button>
+
           {/* Update all / Update selected (appears after check or when selection exists) */}
           {(updatesChecked && outdatedPlugins.length > 0) || hasSelection ? (
             <button
@@ -807,6 +1129,15 @@ export const PluginsPage = () => {
               >
                 <XCircle size={16} /> Disable Selected ({selectedPlugins.size})
               </button>
+              {isServerOff && (
+                <button
+                  onClick={handleSafeModeSelected}
+                  className="flex items-center gap-2 px-4 py-2 bg-[#333] border border-[#404040] text-gray-200 rounded font-medium hover:bg-[#444] transition-colors"
+                  title="Start the server once with the selection disabled; it is re-enabled when the server stops"
+                >
+                  <AlertTriangle size={16} /> Safe mode without selected
+                </button>
+              )}
               <button
                 onClick={() => setBatchDeleteConfirmPlugins(true)}
                 className="flex items-center gap-2 px-4 py-2 rounded font-bold border border-red-500 text-red-400 hover:bg-red-900/20 transition-colors"
@@ -832,6 +1163,47 @@ export const PluginsPage = () => {
         </div>
       </div>
 
+      {quarantined.length > 0 && (
+        <div className="mb-4 bg-[#202020] border border-yellow-600/60 rounded-lg p-4">
+          <div className="flex items-center gap-2 text-yellow-400 font-medium mb-3">
+            <AlertTriangle size={18} /> Quarantined uploads ({quarantined.length})
+          </div>
+          <div className="space-y-3">
+            {quarantined.map(entry => (
+              <div key={entry.id} className="flex items-start justify-between gap-4">
+                <div className="min-w-0">
+                  <p className="font-mono text-sm text-gray-200">{entry.fileName} <span className="text-gray-500">({entry.size})</span></p>
+                  <ul className="text-xs text-gray-400 list-disc ml-4">
+                    {(entry.report.findings || []).map(finding => <li key={finding}>{finding}</li>)}
+                  </ul>
+                  {entry.report.virusTotal?.link && (
+                    <a href={entry.report.virusTotal.link} target="_blank" rel="noopener noreferrer" className="text-xs text-[#E5B80B] hover:underline">
+                      View on VirusTotal
+                    </a>
+                  )}
+                </div>
+                <div className="flex items-center gap-2 flex-shrink-0">
+                  <button
+                    onClick={() => resolveQuarantine(entry, true)}
+                    disabled={resolvingQuarantine === entry.id}
+                    className="px-3 py-1.5 rounded border border-[#404040] text-gray-300 text-sm hover:bg-[#333] disabled:opacity-50"
+                  >
+                    Install anyway
+                  </button>
+                  <button
+                    onClick={() => resolveQuarantine(entry, false)}
+                    disabled={resolvingQuarantine === entry.id}
+                    className="px-3 py-1.5 rounded border border-red-500 text-red-400 text-sm hover:bg-red-900/20 disabled:opacity-50"
+                  >
+                    Discard
+                  </button>
+                </div>
+              </div>
+            ))}
+          </div>
+        </div>
+      )}
+
       {loading ? (
         <div className="flex items-center justify-center py-20">
           <Loader2 size={32} className="animate-spin text-[#E5B80B]" />
@@ -878,8 +1250,65 @@ export const PluginsPage = () => {
                         {selectedPlugins.has(plugin.fileName) ? <Check size={16} /> : <Square size={16} />}
                       </span>
                     </td>
-                    <td className="px-4 py-4 font-medium text-white">{plugin.name}</td>
-                    <td className="px-4 py-4 text-gray-400 font-mono text-sm">{plugin.fileName}</td>
+                    <td className="px-4 py-4">
+                      <div className="flex items-center gap-3">
+                        {plugin.iconUrl ? (
+                          <img src={plugin.iconUrl} alt="" loading="lazy" referrerPolicy="no-referrer" className="w-8 h-8 rounded flex-shrink-0 object-cover bg-[#2a2a2a]" />
+                        ) : (
+                          <div className="w-8 h-8 rounded flex-shrink-0 bg-[#2a2a2a]" />
+                        )}
+                        <div className="min-w-0">
+                          {plugin.projectUrl ? (
+                            <a
+                              href={plugin.projectUrl}
+                              target="_blank"
+                              rel="noopener noreferrer"
+                              onClick={(e) => e.stopPropagation()}
+                              className="font-medium text-white hover:text-[#E5B80B]"
+                            >
+                              {plugin.name}
+                            </a>
+                          ) : (
+                            <span className="font-medium text-white">{plugin.name}</span>
+                          )}
+                          {plugin.description && (
+                            <p className="text-xs text-gray-500 truncate max-w-[280px]" title={plugin.description}>{plugin.description}</p>
+                          )}
+                          {noteEditing?.fileName === plugin.fileName ? (
+                            <div className="flex items-center gap-2 mt-1" onClick={(e) => e.stopPropagation()}>
+                              <input
+                                autoFocus
+                                value={noteEditing.draft}
+                                maxLength={500}
+                                onChange={(e) => setNoteEditing({ fileName: plugin.fileName, draft: e.target.value })}
+                                onKeyDown={(e) => {
+                                  if (e.key === 'Enter') saveNote();
+                                  if (e.key === 'Escape') setNoteEditing(null);
+                                }}
+                                placeholder="Why is this disabled?"
+                                className="bg-[#1a1a1a] border border-[#404040] rounded px-2 py-1 text-xs text-white w-64 focus:outline-none focus:border-[#E5B80B]"
+                              />
+                              <button onClick={saveNote} disabled={savingNote} className="text-[#E5B80B] disabled:opacity-50" title="Save note">
+                                {savingNote ? <Loader2 size={14} className="animate-spin" /> : <Save size={14} />}
+                              </button>
+                            </div>
+                          ) : plugin.note && (
+                            <p
+                              className="text-xs text-amber-300/80 italic truncate max-w-[280px]"
+                              title={plugin.provenance?.noteUpdatedAt ? `${plugin.note} (${new Date(plugin.provenance.noteUpdatedAt).toLocaleString()})` : plugin.note}
+                            >
+                              {plugin.note}
+                            </p>
+                          )}
+                        </div>
+                      </div>
+                    </td>
+                    <td className="px-4 py-4 text-gray-400 font-mono text-sm" title={formatProvenance(plugin)}>
+                      {plugin.fileName}
+                      {plugin.directory && plugin.directory !== (isModded ? 'mods' : 'plugins') && (
+                        <span className="ml-2 px-1.5 py-0.5 text-[10px] rounded bg-[#333] text-gray-400">{plugin.directory}/</span>
+                      )}
+                    </td>
                     <td className="px-4 py-4">{renderVersionBadge(plugin)}</td>
                     <td className="px-4 py-4">
                       {(plugin.sourceUrl && !editingSources.has(plugin.fileName)) ? (
@@ -974,6 +1403,28 @@ export const PluginsPage = () => {
                         >
                           {plugin.enabled ? <XCircle size={18} /> : <CheckCircle size={18} />}
                         </button>
+                        <button
+                          onClick={(e) => {
+                            e.stopPropagation();
+                            setNoteEditing({ fileName: plugin.fileName, draft: plugin.note ?? '' });
+                          }}
+                          className="p-2 hover:bg-[#333] text-gray-300 rounded"
+                          title={plugin.note ? 'Edit note' : 'Add note'}
+                        >
+                          <StickyNote size={18} />
+                        </button>
+                        {!isModded && (!plugin.directory || plugin.directory === 'plugins') && (
+                          <button
+                            onClick={(e) => {
+                              e.stopPropagation();
+                              setConfigPlugin(plugin.fileName);
+                            }}
+                            className="p-2 hover:bg-[#333] text-gray-300 rounded"
+                            title="Edit config"
+                          >
+                            <FileCog size={18} />
+                          </button>
+                        )}
                         <button
                           onClick={(e) => {
                             e.stopPropagation();
@@ -1025,17 +1476,103 @@ export const PluginsPage = () => {
                   <input
                     ref={fileInputRef}
                     type="file"
-                    accept=".jar"
+                    accept={isProxy ? '.jar' : '.jar,.zip'}
                     multiple
                     className="hidden"
                     onChange={(e) => handleUpload(e.target.files)}
                   />
                   <Upload size={32} className="mb-2" />
-                  <p>Drag & drop .jar file here</p>
+                  <p>{isProxy ? 'Drag & drop .jar file here' : 'Drag & drop .jar file or .zip datapack here'}</p>
                   <p className="text-xs text-gray-600 mt-2">or click to browse</p>
                   <p className="text-xs text-gray-600 mt-1">Maximum file size: 256MB</p>
                 </div>
               )}
+              {!uploading && !isProxy && (
+                <div className="mb-6">
+                  <label className="block text-sm text-gray-400 mb-2">Install jars into</label>
+                  <select
+                    value={uploadDir}
+                    onChange={(e) => setUploadDir(e.target.value as '' | ExtensionDirectory)}
+                    className="w-full bg-[#1a1a1a] border border-[#333] rounded px-3 py-2 text-sm text-gray-300 focus:outline-none focus:border-[#E5B80B]"
+                  >
+                    <option value="">{isModded ? 'mods/ (default)' : 'plugins/ (default)'}</option>
+                    <option value={isModded ? 'plugins' : 'mods'}>{isModded ? 'plugins/ (hybrid servers)' : 'mods/ (hybrid servers)'}</option>
+                  </select>
+                </div>
+              )}
+              {!uploading && (
+                <div className="mb-6">
+                  <label className="block text-sm text-gray-400 mb-2">Or search Modrinth{isModded ? '' : ' and Spigot'}</label>
+                  <div className="flex items-center gap-2">
+                    <input
+                      type="text"
+                      value={searchQuery}
+                      onChange={(e) => setSearchQuery(e.target.value)}
+                      onKeyDown={(e) => { if (e.key === 'Enter') handleSearchCatalog(); }}
+                      placeholder={isModded ? 'Sodium, JEI...' : 'LuckPerms, WorldEdit...'}
+                      className="w-full bg-[#1a1a1a] border border-[#333] rounded px-3 py-2 text-sm text-gray-300 focus:outline-none focus:border-[#E5B80B]"
+                    />
+                    <button
+                      onClick={handleSearchCatalog}
+                      disabled={searching || searchQuery.trim() === ''}
+                      className="px-4 py-2 bg-[#333] hover:bg-[#404040] text-gray-200 rounded font-medium disabled:opacity-50 flex-shrink-0"
+                    >
+                      {searching ? <Loader2 size={16} className="animate-spin" /> : 'Search'}
+                    </button>
+                  </div>
+                  {searchResults && (
+                    <div className="mt-2 max-h-64 overflow-y-auto space-y-1">
+                      {searchResults.length === 0 && <p className="text-xs text-gray-500">No {itemLabelPlural} found.</p>}
+                      {searchResults.map((result) => (
+                        <div key={`${result.source}-${result.projectId}`} className="flex items-center gap-2 bg-[#1a1a1a] border border-[#333] rounded px-2 py-1.5">
+                          {result.iconUrl ? (
+                            <img src={result.iconUrl} alt="" className="w-8 h-8 rounded flex-shrink-0" />
+                          ) : (
+                            <div className="w-8 h-8 rounded bg-[#333] flex-shrink-0" />
+                          )}
+                          <div className="min-w-0 flex-1">
+                            <a href={result.pageUrl} target="_blank" rel="noreferrer" className="text-sm text-white hover:text-[#E5B80B] truncate block">
+                              {result.name}
+                            </a>
+                            <p className="text-[11px] text-gray-500 truncate">
+                              {result.source === 'modrinth' ? 'Modrinth' : 'Spigot'} · {result.downloads.toLocaleString()} downloads
+                              {result.description && ` · ${result.description}`}
+                            </p>
+                          </div>
+                          <button
+                            onClick={() => handleInstallFromCatalog(result)}
+                            className="px-3 py-1 bg-[#E5B80B] text-black rounded text-xs font-bold hover:bg-[#d4a90a] flex-shrink-0"
+                          >
+                            Install
+                          </button>
+                        </div>
+                      ))}
+                    </div>
+                  )}
+                </div>
+              )}
+              {!uploading && (
+                <div className="mb-6">
+                  <label className="block text-sm text-gray-400 mb-2">Or install from a Modrinth, Spigot, Jenkins or GitHub release link</label>
+                  <div className="flex items-center gap-2">
+                    <input
+                      type="text"
+                      value={installUrl}
+                      onChange={(e) => setInstallUrl(e.target.value)}
+                      onKeyDown={(e) => { if (e.key === 'Enter') handleInstallFromUrl(); }}
+                      placeholder="https://modrinth.com/plugin/..."
+                      className="w-full bg-[#1a1a1a] border border-[#333] rounded px-3 py-2 text-sm text-gray-300 focus:outline-none focus:border-[#E5B80B]"
+                    />
+                    <button
+                      onClick={handleInstallFromUrl}
+                      disabled={installUrl.trim() === ''}
+                      className="px-4 py-2 bg-[#E5B80B] text-black rounded font-bold hover:bg-[#d4a90a] disabled:opacity-50 flex-shrink-0"
+                    >
+                      Install
+                    </button>
+                  </div>
+                </div>
+              )}
               <div className="flex justify-end gap-3">
                 <button
                   onClick={() => setIsUploadModalOpen(false)}
@@ -1210,8 +1747,8 @@ export const PluginsPage = () => {
               exit={{ opacity: 0, scale: 0.95 }}
               className="w-full max-w-md bg-[#252524] border border-[#404040] rounded-lg shadow-2xl p-6"
             >
-              <h3 className="text-xl font-bold text-white mb-3">Stop server to apply update</h3>
-              <p className="text-gray-300 mb-6">Stop the server first to avoid corrupting plugin data. After stopping you can apply updates from this UI.</p>
+              <h3 className="text-xl font-bold text-white mb-3">Stage update for next restart</h3>
+              <p className="text-gray-300 mb-6">The server is running, so the new version will be downloaded and checked now, then swapped in automatically the next time the server stops or restarts.</p>
               <div className="flex justify-end gap-3">
                 <button
                   onClick={() => { setRestartPromptOpen(false); setPendingUpdate(null); }}
@@ -1225,7 +1762,7 @@ export const PluginsPage = () => {
                   className="px-4 py-2 bg-[#E5B80B] hover:bg-[#d4a90a] text-black rounded font-bold"
                   disabled={updatingPlugin !== null}
                 >
-                  Update
+                  Stage update
                 </button>
               </div>
             </motion.div>
@@ -1243,8 +1780,8 @@ export const PluginsPage = () => {
               exit={{ opacity: 0, scale: 0.95 }}
               className="w-full max-w-md bg-[#252524] border border-[#404040] rounded-lg shadow-2xl p-6"
             >
-              <h3 className="text-xl font-bold text-white mb-3">Stop server to apply updates</h3>
-              <p className="text-gray-300 mb-6">Stop the server first to avoid corrupting plugin data. After stopping you can apply updates from this UI.</p>
+              <h3 className="text-xl font-bold text-white mb-3">Stage updates for next restart</h3>
+              <p className="text-gray-300 mb-6">The server is running, so the new versions will be downloaded and checked now, then swapped in automatically the next time the server stops or restarts.</p>
               <div className="flex justify-end gap-3">
                 <button
                   onClick={() => setUpdateAllPromptOpen(false)}
@@ -1258,12 +1795,16 @@ export const PluginsPage = () => {
                   className="px-4 py-2 bg-[#E5B80B] hover:bg-[#d4a90a] text-black rounded font-bold"
                   disabled={updatingAll}
                 >
-                  {hasSelection && !allSelected ? 'Update selected' : 'Update all'}
+                  {hasSelection && !allSelected ? 'Stage selected' : 'Stage all'}
                 </button>
               </div>
             </motion.div>
           </div>
         )}
+        {overviewOpen && <PluginUpdateOverview onClose={() => setOverviewOpen(false)} />}
+        {configPlugin && activeServer && (
+          <PluginConfigEditor serverId={activeServer.id} fileName={configPlugin} onClose={() => setConfigPlugin(null)} />
+        )}
       </AnimatePresence>
       {undoOverlay}
 
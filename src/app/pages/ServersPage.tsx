@@ -1,6 +1,6 @@
 import React, { useState, useEffect, useRef, useMemo } from 'react';
 import { useServer } from '../context/ServerContext';
-import { Plus, Cpu, HardDrive, Play, Square, AlertTriangle, ArrowLeft, Check, ChevronDown, ChevronUp, ChevronRight, Loader2, RotateCw, Power, Settings2, X, Trash2, FileUp, Upload } from 'lucide-react';
+import { Plus, Cpu, HardDrive, Play, Square, AlertTriangle, ArrowLeft, Check, ChevronDown, ChevronUp, ChevronRight, Loader2, RotateCw, Power, Settings2, X, Trash2, FileUp, FolderInput, Upload, Package, PackageOpen } from 'lucide-react';
 import { AnimatePresence, motion } from 'motion/react';
 import {
   DndContext,
@@ -20,7 +20,7 @@ import { toast } from 'sonner';
 import clsx from 'clsx';
 import { useEscapeKey } from '../hooks/useEscapeKey';
 import { useStagedDeleteUndo } from '../hooks/useStagedDeleteUndo';
-import { ApiError, apiRequest, toErrorMessage } from '../lib/api';
+import { ApiError, apiRequest, csrfHeaders, toErrorMessage, Job } from '../lib/api';
 import {
   DEFAULT_CREATE_FORM,
   DRAG_CLICK_GUARD_MS,
@@ -39,6 +39,9 @@ import type {
   VersionInfo,
 } from './servers/types';
 import { SortableServerCard } from './servers/SortableServerCard';
+import { ModpackInstallModal } from './servers/ModpackInstallModal';
+import { DirectoryImportModal } from './servers/DirectoryImportModal';
+import { ArchiveImportModal } from './servers/ArchiveImportModal';
 
 interface ServersPageProps {
   onViewChange: (view: 'servers' | 'management' | 'plugins' | 'backups' | 'logs' | 'cloning') => void;
@@ -48,6 +51,7 @@ type SettingsDefaultsResponse = {
   defaultMinRam?: string;
   defaultMaxRam?: string;
   defaultFlags?: JVMFlagsPreset;
+  defaultMaxPlayers?: number;
 };
 
 export const ServersPage = ({ onViewChange }: ServersPageProps) => {
@@ -58,14 +62,18 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
     minRam: DEFAULT_CREATE_FORM.minRam,
     maxRam: DEFAULT_CREATE_FORM.maxRam,
     flags: DEFAULT_CREATE_FORM.flags,
+    maxPlayers: DEFAULT_CREATE_FORM.maxPlayers,
   });
   const [formData, setFormData] = useState(DEFAULT_CREATE_FORM);
   const [versions, setVersions] = useState<VersionInfo[]>([]);
   const [versionsLoading, setVersionsLoading] = useState(false);
   const [typeVersionCatalog, setTypeVersionCatalog] = useState<Record<string, VersionInfo[]>>({});
-  const [updatePopup, setUpdatePopup] = useState<{ serverId: string; serverName: string; currentVersion: string; selectedVersion: string; options: VersionInfo[] } | null>(null);
+  const [updatePopup, setUpdatePopup] = useState<{ serverId: string; serverName: string; currentVersion: string; selectedVersion: string; options: VersionInfo[]; upgradeWorld: boolean } | null>(null);
   const [updatingVersion, setUpdatingVersion] = useState(false);
   const [isImportOpen, setIsImportOpen] = useState(false);
+  const [isModpackOpen, setIsModpackOpen] = useState(false);
+  const [isDirectoryImportOpen, setIsDirectoryImportOpen] = useState(false);
+  const [isArchiveImportOpen, setIsArchiveImportOpen] = useState(false);
   const [importDragActive, setImportDragActive] = useState(false);
   const [isImportUploading, setIsImportUploading] = useState(false);
   const [isImportAnalyzing, setIsImportAnalyzing] = useState(false);
@@ -96,6 +104,7 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
           minRam: data.defaultMinRam || DEFAULT_CREATE_FORM.minRam,
           maxRam: data.defaultMaxRam || DEFAULT_CREATE_FORM.maxRam,
           flags: (data.defaultFlags || DEFAULT_CREATE_FORM.flags) as JVMFlagsPreset,
+          maxPlayers: data.defaultMaxPlayers ? String(data.defaultMaxPlayers) : DEFAULT_CREATE_FORM.maxPlayers,
         };
         setFormDefaults(defaults);
         setFormData(prev => ({
@@ -103,6 +112,7 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
           minRam: defaults.minRam,
           maxRam: defaults.maxRam,
           flags: defaults.flags,
+          maxPlayers: defaults.maxPlayers,
         }));
       })
       .catch(() => {});
@@ -215,7 +225,8 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
       },
       onCommit: async () => {
         for (const serverId of serverIds) {
-          await apiRequest<void>(`/api/servers/${serverId}`, { method: 'DELETE' }, `Failed to delete server ${serverId}`);
+          // The server leaves the panel at once; its files are deleted by a background job.
+          await apiRequest<Job>(`/api/servers/${serverId}`, { method: 'DELETE' }, `Failed to delete server ${serverId}`);
         }
         setPendingDeletedServerIds((prev) => {
           const next = new Set(prev);
@@ -237,10 +248,11 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
         name: formData.name || `My ${formData.type} Server`,
         type: formData.type as any,
         version: formData.version,
-        port: parseInt(formData.port) || 25565,
+        // An empty port lets the panel pick the first free one in the default range.
+        port: parseInt(formData.port) || 0,
         minRam: Math.round((parseFloat(formData.minRam) || 0.5) * 1024) + 'M',
         maxRam: Math.round((parseFloat(formData.maxRam) || 1) * 1024) + 'M',
-        maxPlayers: parseInt(formData.maxPlayers) || 20,
+        maxPlayers: parseInt(formData.maxPlayers) || 0,
         flags: formData.flags,
         alwaysPreTouch: formData.alwaysPreTouch,
       });
@@ -251,6 +263,7 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
         minRam: formDefaults.minRam,
         maxRam: formDefaults.maxRam,
         flags: formDefaults.flags,
+        maxPlayers: formDefaults.maxPlayers,
       });
     } catch (err) {
       toast.error(err instanceof Error ? err.message : 'Failed to create server');
@@ -262,7 +275,7 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
   const handleToggleStatus = async (e: React.MouseEvent, serverId: string, status: string) => {
     e.stopPropagation();
     try {
-      if (status === 'Running') {
+      if (status === 'Running' || status === 'Suspended') {
         await stopServer(serverId);
         toast.success('Server stopping...');
       } else {
@@ -559,6 +572,7 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
       currentVersion: server.version,
       selectedVersion: options[0].version,
       options,
+      upgradeWorld: false,
     });
   };
 
@@ -574,9 +588,11 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
       await apiRequest(`/api/servers/${updatePopup.serverId}/version`, {
         method: 'PUT',
         headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ version: updatePopup.selectedVersion }),
+        body: JSON.stringify({ version: updatePopup.selectedVersion, upgradeWorld: updatePopup.upgradeWorld }),
       }, 'Failed to update server version');
-      toast.info(`Updating to ${updatePopup.selectedVersion}...`);
+      toast.info(updatePopup.upgradeWorld
+        ? `Updating to ${updatePopup.selectedVersion}, then upgrading the world...`
+        : `Updating to ${updatePopup.selectedVersion}...`);
       setUpdatePopup(null);
       await refreshServers();
     } catch (err) {
@@ -694,6 +710,7 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
         const xhr = new XMLHttpRequest();
         importAnalyzeXhrRef.current = xhr;
         xhr.open('POST', '/api/servers/import/analyze');
+        Object.entries(csrfHeaders()).forEach(([name, value]) => xhr.setRequestHeader(name, value));
         xhr.responseType = 'json';
 
         xhr.upload.onprogress = (event) => {
@@ -961,12 +978,14 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
             ? 'bg-yellow-900/30 text-yellow-400'
             : server.status === 'Installing'
               ? 'bg-blue-900/30 text-blue-400'
-              : 'bg-gray-700/30 text-gray-400';
+              : server.status === 'Suspended'
+                ? 'bg-purple-900/30 text-purple-400'
+                : 'bg-gray-700/30 text-gray-400';
     const actionDisabled = server.status === 'Booting' || server.status === 'Installing' || !interactive;
     const actionClass = clsx(
       'text-xs px-3 py-1.5 rounded font-medium border transition-colors',
       (server.status === 'Booting' || server.status === 'Installing') && 'opacity-50 cursor-not-allowed border-blue-500 text-blue-400',
-      server.status === 'Running' && 'border-red-500 text-red-400 hover:bg-red-900/20',
+      (server.status === 'Running' || server.status === 'Suspended') && 'border-red-500 text-red-400 hover:bg-red-900/20',
       server.status === 'Error' && 'border-orange-500 text-orange-400 hover:bg-orange-900/20',
       (server.status === 'Stopped' || server.status === 'Crashed') && 'border-green-500 text-green-400 hover:bg-green-900/20',
       !interactive && 'opacity-95'
@@ -1094,7 +1113,7 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
             disabled={actionDisabled}
             className={actionClass}
           >
-            {server.status === 'Running'
+            {server.status === 'Running' || server.status === 'Suspended'
               ? 'Stop'
               : server.status === 'Booting'
                 ? 'Booting...'
@@ -1149,6 +1168,7 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
                   {([
                     { value: 'none', label: 'None', desc: 'Default JVM flags.' },
                     { value: 'aikars', label: "Aikar's Flags", desc: 'Optimized GC for game servers.' },
+                    { value: 'zgc', label: 'ZGC', desc: 'Low-pause GC for large heaps on Java 21+.' },
                     { value: 'velocity', label: 'Velocity Proxy', desc: 'Optimized for proxy servers.' },
                     { value: 'modded', label: 'Modded', desc: 'Recommended for modded servers.' },
                   ] as const).map(opt => (
@@ -1250,6 +1270,7 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
                       type="number"
                       value={formData.port}
                       onChange={(e) => setFormData({...formData, port: e.target.value})}
+                      placeholder="Auto"
                       disabled={!formData.type}
                       min={1024}
                       max={65535}
@@ -1386,6 +1407,29 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
             <FileUp size={20} />
             Import Server
           </button>
+          <button
+            onClick={() => setIsDirectoryImportOpen(true)}
+            title="Register a server folder that already exists on the host"
+            className="flex items-center gap-2 border border-blue-500 text-blue-300 px-4 py-2 rounded font-bold hover:bg-blue-900/20 transition-colors"
+          >
+            <FolderInput size={20} />
+            Import Folder
+          </button>
+          <button
+            onClick={() => setIsArchiveImportOpen(true)}
+            title="Recreate a server exported from another panel"
+            className="flex items-center gap-2 border border-blue-500 text-blue-300 px-4 py-2 rounded font-bold hover:bg-blue-900/20 transition-colors"
+          >
+            <PackageOpen size={20} />
+            Import Export
+          </button>
+          <button
+            onClick={() => setIsModpackOpen(true)}
+            className="flex items-center gap-2 bg-purple-600 text-white px-4 py-2 rounded font-bold hover:bg-purple-500 transition-colors shadow-lg shadow-purple-900/30"
+          >
+            <Package size={20} />
+            Install Modpack
+          </button>
         </div>
       </div>
 
@@ -1465,6 +1509,7 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
                   ${server.status === 'Running' ? 'bg-green-900/30 text-green-400' :
                     server.status === 'Crashed' || server.status === 'Error' ? 'bg-red-900/30 text-red-400' :
                     server.status === 'Booting' ? 'bg-yellow-900/30 text-yellow-400' :
+                    server.status === 'Suspended' ? 'bg-purple-900/30 text-purple-400' :
                     server.status === 'Installing' ? 'bg-blue-900/30 text-blue-400' :
                     'bg-gray-700/30 text-gray-400'}
                 `}>
@@ -1558,12 +1603,12 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
                   className={clsx(
                     "text-xs px-3 py-1.5 rounded font-medium border transition-colors",
                     (server.status === 'Booting' || server.status === 'Installing') && "opacity-50 cursor-not-allowed border-blue-500 text-blue-400",
-                    server.status === 'Running' && "border-red-500 text-red-400 hover:bg-red-900/20",
+                    (server.status === 'Running' || server.status === 'Suspended') && "border-red-500 text-red-400 hover:bg-red-900/20",
                     server.status === 'Error' && "border-orange-500 text-orange-400 hover:bg-orange-900/20",
                     (server.status === 'Stopped' || server.status === 'Crashed') && "border-green-500 text-green-400 hover:bg-green-900/20",
                   )}
                  >
-                   {server.status === 'Running' ? 'Stop' :
+                   {server.status === 'Running' || server.status === 'Suspended' ? 'Stop' :
                     server.status === 'Booting' ? 'Booting...' :
                     server.status === 'Installing' ? 'Installing...' :
                     server.status === 'Error' ? 'Retry' : 'Start'}
@@ -1587,6 +1632,7 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
                     {([
                       { value: 'none', label: 'None', desc: 'Default JVM flags.' },
                       { value: 'aikars', label: "Aikar's Flags", desc: 'Optimized GC for game servers.' },
+                      { value: 'zgc', label: 'ZGC', desc: 'Low-pause GC for large heaps on Java 21+.' },
                       { value: 'velocity', label: 'Velocity Proxy', desc: 'Optimized for proxy servers.' },
                       { value: 'modded', label: 'Modded', desc: 'Recommended for modded servers.' },
                     ] as const).map(opt => (
@@ -1685,6 +1731,19 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
                     <ChevronDown className="absolute right-3 top-1/2 -translate-y-1/2 text-gray-500 pointer-events-none" size={16} />
                   </div>
 
+                  {server.type.toLowerCase() !== 'velocity' && (
+                    <label className="flex items-start gap-2 mt-3 text-xs text-gray-400 cursor-pointer">
+                      <input
+                        type="checkbox"
+                        checked={updatePopup.upgradeWorld}
+                        onChange={(e) => setUpdatePopup({ ...updatePopup, upgradeWorld: e.target.checked })}
+                        className="mt-0.5 accent-[#E5B80B]"
+                        disabled={updatingVersion}
+                      />
+                      <span>Upgrade the world afterwards. Backs up, then converts every chunk now so the first boot does not lag.</span>
+                    </label>
+                  )}
+
                   <div className="flex justify-end gap-2 mt-4 pt-3 border-t border-[#333]">
                     <button
                       onClick={() => setUpdatePopup(null)}
@@ -1725,6 +1784,10 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
         </DndContext>
       )}
 
+      <ModpackInstallModal open={isModpackOpen} onClose={() => setIsModpackOpen(false)} />
+      <DirectoryImportModal open={isDirectoryImportOpen} onClose={() => setIsDirectoryImportOpen(false)} />
+      <ArchiveImportModal open={isArchiveImportOpen} onClose={() => setIsArchiveImportOpen(false)} />
+
       {/* Import Server Modal */}
       <AnimatePresence>
         {isImportOpen && (
@@ -2039,6 +2102,7 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
                   {([
                     { value: 'none', label: 'None' },
                     { value: 'aikars', label: "Aikar's Flags" },
+                    { value: 'zgc', label: 'ZGC' },
                     { value: 'velocity', label: 'Velocity Proxy' },
                     { value: 'modded', label: 'Modded' },
                   ] as const).map((opt) => (
@@ -2088,7 +2152,7 @@ export const ServersPage = ({ onViewChange }: ServersPageProps) => {
       {/* Delete Confirmation Popup */}
       {deleteConfirm && (() => {
         const selectedServers = visibleServers.filter(s => selectedServerIds.has(s.id));
-        const hasActive = selectedServers.some(s => s.status === 'Running' || s.status === 'Booting' || s.status === 'Installing');
+        const hasActive = selectedServers.some(s => s.status === 'Running' || s.status === 'Booting' || s.status === 'Installing' || s.status === 'Suspended');
 
         return (
           <div className="fixed inset-0 z-50 flex items-center justify-center bg-black/60 backdrop-blur-sm" onClick={() => setDeleteConfirm(false)}>
@@ -1,11 +1,15 @@
 import React, { useCallback, useEffect, useMemo, useState } from 'react';
-import { Loader2, Cpu, HardDrive, ChevronDown, ChevronUp, Settings, Square, X } from 'lucide-react';
+import { Loader2, Cpu, HardDrive, ChevronDown, ChevronUp, Settings, Square, X, AlertTriangle } from 'lucide-react';
 import { Area, AreaChart, ResponsiveContainer } from 'recharts';
 import { toast } from 'sonner';
 import clsx from 'clsx';
 import { AnimatePresence, motion } from 'motion/react';
 import { Tooltip, TooltipContent, TooltipTrigger } from '../components/ui/tooltip';
 import { useServer } from '../context/ServerContext';
+import { LoginBlocksPanel } from '../components/LoginBlocksPanel';
+import { UsersPanel } from '../components/UsersPanel';
+import { ModerationPresetsPanel } from '../components/ModerationPresetsPanel';
+import { BackupTargetsPanel } from '../components/BackupTargetsPanel';
 import { apiRequest, toErrorMessage } from '../lib/api';
 
 type View = 'servers' | 'management' | 'plugins' | 'backups' | 'logs' | 'cloning' | 'settings';
@@ -24,6 +28,7 @@ type UsageProcess = {
   cpuPercent: number;
   ramBytes: number;
   ramPercent: number;
+  swapBytes?: number;
 };
 
 type UsageTotals = {
@@ -32,12 +37,27 @@ type UsageTotals = {
   ramPercent: number;
 };
 
+type MemoryPressure = {
+  level: 'ok' | 'warning' | 'critical';
+  warning?: string;
+  swapTotalBytes: number;
+  swapUsedBytes: number;
+  swapInBytesPerSec: number;
+  swapOutBytesPerSec: number;
+  zram?: boolean;
+  psiAvailable: boolean;
+  psiSomeAvg10?: number;
+  psiFullAvg10?: number;
+  availableRamBytes: number;
+};
+
 type SystemUsage = {
   timestamp: string;
   host: UsageHost;
   panel: UsageProcess;
   servers: UsageProcess[];
   total: UsageTotals;
+  memoryPressure?: MemoryPressure;
 };
 
 type UsageHistoryPoint = {
@@ -52,18 +72,80 @@ type SettingsSnapshot = {
   defaultMinRam: string;
   defaultMaxRam: string;
   defaultFlags: string;
+  defaultPortRangeStart: string;
+  defaultPortRangeEnd: string;
+  defaultMaxPlayers: string;
+  defaultBackupSchedule: string;
+  sessionTtlHours: string;
+  loginMaxFailures: string;
+  loginWindowMinutes: string;
+  loginLockoutMinutes: string;
+  sessionBindIp: boolean;
+  sessionBindUserAgent: boolean;
+  cspReportOnly: boolean;
+  accessAllowList: string;
+  accessDenyList: string;
   statusPollInterval: string;
   tpsPollInterval: string;
   playerSyncInterval: string;
   pingPollInterval: string;
+  locale: string;
+  curseForgeApiKey: string;
+};
+
+type SettingsFieldResult = {
+  field: string;
+  status: 'applied' | 'clamped' | 'rejected';
+  requested?: unknown;
+  effective?: unknown;
+  message?: string;
+};
+
+type SettingsSaveResponse = {
+  loginUser: string;
+  userAgent: string;
+  defaultMinRam: string;
+  defaultMaxRam: string;
+  defaultFlags: string;
+  defaultPortRangeStart: number;
+  defaultPortRangeEnd: number;
+  defaultMaxPlayers: number;
+  defaultBackupSchedule: string;
+  sessionTtlHours: number;
+  loginMaxFailures: number;
+  loginWindowMinutes: number;
+  loginLockoutMinutes: number;
+  sessionBindIp: boolean;
+  sessionBindUserAgent: boolean;
+  cspReportOnly: boolean;
+  accessAllowList: string[];
+  accessDenyList: string[];
+  statusPollInterval: number;
+  tpsPollInterval: number;
+  playerSyncInterval: number;
+  pingPollInterval: number;
+  locale: string;
+  curseForgeApiKeySet?: boolean;
+  fields?: SettingsFieldResult[];
 };
 
+const splitAccessList = (value: string) =>
+  value.split(/[\s,]+/).map((entry) => entry.trim()).filter(Boolean);
+
 type SystemSettingsPageProps = {
   onViewChange?: (view: View) => void;
 };
 
 const DETAILS_STORAGE_KEY = 'orexa.systemSettings.detailsOpen';
 
+const localeLabels: Record<string, string> = {
+  de: 'Deutsch',
+  en: 'English',
+  es: 'Español',
+  fr: 'Français',
+  pt: 'Português',
+};
+
 const emptyUsage: SystemUsage = {
   timestamp: '',
   host: { logicalCpuCount: 0, totalRamBytes: 0 },
@@ -129,10 +211,27 @@ export const SystemSettingsPage = ({ onViewChange }: SystemSettingsPageProps) =>
   const [defaultMinRam, setDefaultMinRam] = useState('0.5');
   const [defaultMaxRam, setDefaultMaxRam] = useState('1');
   const [defaultFlags, setDefaultFlags] = useState('none');
+  const [defaultPortRangeStart, setDefaultPortRangeStart] = useState('25565');
+  const [defaultPortRangeEnd, setDefaultPortRangeEnd] = useState('25665');
+  const [defaultMaxPlayers, setDefaultMaxPlayers] = useState('20');
+  const [defaultBackupSchedule, setDefaultBackupSchedule] = useState('');
+  const [sessionTtlHours, setSessionTtlHours] = useState('168');
+  const [loginMaxFailures, setLoginMaxFailures] = useState('10');
+  const [loginWindowMinutes, setLoginWindowMinutes] = useState('15');
+  const [loginLockoutMinutes, setLoginLockoutMinutes] = useState('15');
+  const [sessionBindIp, setSessionBindIp] = useState(false);
+  const [sessionBindUserAgent, setSessionBindUserAgent] = useState(false);
+  const [cspReportOnly, setCspReportOnly] = useState(false);
+  const [accessAllowList, setAccessAllowList] = useState('');
+  const [accessDenyList, setAccessDenyList] = useState('');
   const [statusPollInterval, setStatusPollInterval] = useState('3');
   const [tpsPollInterval, setTpsPollInterval] = useState('30');
   const [playerSyncInterval, setPlayerSyncInterval] = useState('15');
   const [pingPollInterval, setPingPollInterval] = useState('20');
+  const [locale, setLocale] = useState('en');
+  const [supportedLocales, setSupportedLocales] = useState<string[]>(['en']);
+  const [curseForgeApiKey, setCurseForgeApiKey] = useState('');
+  const [curseForgeApiKeySet, setCurseForgeApiKeySet] = useState(false);
   const [loading, setLoading] = useState(true);
   const [saving, setSaving] = useState(false);
 
@@ -154,19 +253,49 @@ export const SystemSettingsPage = ({ onViewChange }: SystemSettingsPageProps) =>
       defaultMinRam,
       defaultMaxRam,
       defaultFlags,
+      defaultPortRangeStart,
+      defaultPortRangeEnd,
+      defaultMaxPlayers,
+      defaultBackupSchedule,
+      sessionTtlHours,
+      loginMaxFailures,
+      loginWindowMinutes,
+      loginLockoutMinutes,
+      sessionBindIp,
+      sessionBindUserAgent,
+      cspReportOnly,
+      accessAllowList,
+      accessDenyList,
       statusPollInterval,
       tpsPollInterval,
       playerSyncInterval,
       pingPollInterval,
+      locale,
+      curseForgeApiKey,
     }),
     [
+      accessAllowList,
+      accessDenyList,
+      cspReportOnly,
+      curseForgeApiKey,
+      defaultBackupSchedule,
       defaultFlags,
+      defaultMaxPlayers,
       defaultMaxRam,
       defaultMinRam,
+      defaultPortRangeEnd,
+      defaultPortRangeStart,
+      locale,
+      loginLockoutMinutes,
+      loginMaxFailures,
       loginPassword,
       loginUser,
+      loginWindowMinutes,
       pingPollInterval,
       playerSyncInterval,
+      sessionBindIp,
+      sessionBindUserAgent,
+      sessionTtlHours,
       statusPollInterval,
       tpsPollInterval,
       userAgent,
@@ -182,10 +311,25 @@ export const SystemSettingsPage = ({ onViewChange }: SystemSettingsPageProps) =>
       currentSnapshot.defaultMinRam !== savedSnapshot.defaultMinRam ||
       currentSnapshot.defaultMaxRam !== savedSnapshot.defaultMaxRam ||
       currentSnapshot.defaultFlags !== savedSnapshot.defaultFlags ||
+      currentSnapshot.defaultPortRangeStart !== savedSnapshot.defaultPortRangeStart ||
+      currentSnapshot.defaultPortRangeEnd !== savedSnapshot.defaultPortRangeEnd ||
+      currentSnapshot.defaultMaxPlayers !== savedSnapshot.defaultMaxPlayers ||
+      currentSnapshot.defaultBackupSchedule !== savedSnapshot.defaultBackupSchedule ||
+      currentSnapshot.sessionTtlHours !== savedSnapshot.sessionTtlHours ||
+      currentSnapshot.loginMaxFailures !== savedSnapshot.loginMaxFailures ||
+      currentSnapshot.loginWindowMinutes !== savedSnapshot.loginWindowMinutes ||
+      currentSnapshot.loginLockoutMinutes !== savedSnapshot.loginLockoutMinutes ||
+      currentSnapshot.sessionBindIp !== savedSnapshot.sessionBindIp ||
+      currentSnapshot.sessionBindUserAgent !== savedSnapshot.sessionBindUserAgent ||
+      currentSnapshot.cspReportOnly !== savedSnapshot.cspReportOnly ||
+      currentSnapshot.accessAllowList !== savedSnapshot.accessAllowList ||
+      currentSnapshot.accessDenyList !== savedSnapshot.accessDenyList ||
       currentSnapshot.statusPollInterval !== savedSnapshot.statusPollInterval ||
       currentSnapshot.tpsPollInterval !== savedSnapshot.tpsPollInterval ||
       currentSnapshot.playerSyncInterval !== savedSnapshot.playerSyncInterval ||
-      currentSnapshot.pingPollInterval !== savedSnapshot.pingPollInterval
+      currentSnapshot.pingPollInterval !== savedSnapshot.pingPollInterval ||
+      currentSnapshot.locale !== savedSnapshot.locale ||
+      currentSnapshot.curseForgeApiKey !== savedSnapshot.curseForgeApiKey
     );
   }, [currentSnapshot, savedSnapshot]);
 
@@ -219,10 +363,28 @@ export const SystemSettingsPage = ({ onViewChange }: SystemSettingsPageProps) =>
           setDefaultMinRam(data.defaultMinRam || '0.5');
           setDefaultMaxRam(data.defaultMaxRam || '1');
           setDefaultFlags(data.defaultFlags || 'none');
+          setDefaultPortRangeStart(String(data.defaultPortRangeStart || 25565));
+          setDefaultPortRangeEnd(String(data.defaultPortRangeEnd || 25665));
+          setDefaultMaxPlayers(String(data.defaultMaxPlayers || 20));
+          setDefaultBackupSchedule(data.defaultBackupSchedule || '');
+          setSessionTtlHours(String(data.sessionTtlHours || 168));
+          setLoginMaxFailures(String(data.loginMaxFailures || 10));
+          setLoginWindowMinutes(String(data.loginWindowMinutes || 15));
+          setLoginLockoutMinutes(String(data.loginLockoutMinutes || 15));
+          setSessionBindIp(Boolean(data.sessionBindIp));
+          setSessionBindUserAgent(Boolean(data.sessionBindUserAgent));
+          setCspReportOnly(Boolean(data.cspReportOnly));
+          setAccessAllowList((data.accessAllowList || []).join('\n'));
+          setAccessDenyList((data.accessDenyList || []).join('\n'));
           setStatusPollInterval(String(data.statusPollInterval || 3));
           setTpsPollInterval(String(data.tpsPollInterval || 30));
           setPlayerSyncInterval(String(data.playerSyncInterval || 15));
           setPingPollInterval(String(data.pingPollInterval || 20));
+          setLocale(data.locale || 'en');
+          if (Array.isArray(data.supportedLocales) && data.supportedLocales.length > 0) {
+            setSupportedLocales(data.supportedLocales);
+          }
+          setCurseForgeApiKeySet(Boolean(data.curseForgeApiKeySet));
           setSavedSnapshot({
             loginUser: data.loginUser || 'mcpanel',
             loginPassword: '',
@@ -230,10 +392,25 @@ export const SystemSettingsPage = ({ onViewChange }: SystemSettingsPageProps) =>
             defaultMinRam: data.defaultMinRam || '0.5',
             defaultMaxRam: data.defaultMaxRam || '1',
             defaultFlags: data.defaultFlags || 'none',
+            defaultPortRangeStart: String(data.defaultPortRangeStart || 25565),
+            defaultPortRangeEnd: String(data.defaultPortRangeEnd || 25665),
+            defaultMaxPlayers: String(data.defaultMaxPlayers || 20),
+            defaultBackupSchedule: data.defaultBackupSchedule || '',
+            sessionTtlHours: String(data.sessionTtlHours || 168),
+            loginMaxFailures: String(data.loginMaxFailures || 10),
+            loginWindowMinutes: String(data.loginWindowMinutes || 15),
+            loginLockoutMinutes: String(data.loginLockoutMinutes || 15),
+            sessionBindIp: Boolean(data.sessionBindIp),
+            sessionBindUserAgent: Boolean(data.sessionBindUserAgent),
+            cspReportOnly: Boolean(data.cspReportOnly),
+            accessAllowList: (data.accessAllowList || []).join('\n'),
+            accessDenyList: (data.accessDenyList || []).join('\n'),
             statusPollInterval: String(data.statusPollInterval || 3),
             tpsPollInterval: String(data.tpsPollInterval || 30),
             playerSyncInterval: String(data.playerSyncInterval || 15),
             pingPollInterval: String(data.pingPollInterval || 20),
+            locale: data.locale || 'en',
+            curseForgeApiKey: '',
           });
         }
       } catch (err) {
@@ -342,42 +519,140 @@ export const SystemSettingsPage = ({ onViewChange }: SystemSettingsPageProps) =>
       return;
     }
 
+    const parsedPortStart = parseInt(String(defaultPortRangeStart), 10);
+    const parsedPortEnd = parseInt(String(defaultPortRangeEnd), 10);
+    if (
+      isNaN(parsedPortStart) || isNaN(parsedPortEnd) ||
+      parsedPortStart < 1024 || parsedPortEnd > 65535 || parsedPortStart > parsedPortEnd
+    ) {
+      toast.error('Default port range must be within 1024-65535, start before end.');
+      return;
+    }
+    const parsedMaxPlayers = parseInt(String(defaultMaxPlayers), 10);
+    if (isNaN(parsedMaxPlayers) || parsedMaxPlayers < 1 || parsedMaxPlayers > 1000) {
+      toast.error('Default max players must be between 1 and 1000.');
+      return;
+    }
+
+    const parsedSessionTtl = parseInt(String(sessionTtlHours), 10);
+    if (isNaN(parsedSessionTtl) || parsedSessionTtl < 1 || parsedSessionTtl > 720) {
+      toast.error('Session lifetime must be between 1 and 720 hours.');
+      return;
+    }
+    const parsedMaxFailures = parseInt(String(loginMaxFailures), 10);
+    if (isNaN(parsedMaxFailures) || parsedMaxFailures < 3 || parsedMaxFailures > 100) {
+      toast.error('Failed login limit must be between 3 and 100.');
+      return;
+    }
+    const parsedLoginWindow = parseInt(String(loginWindowMinutes), 10);
+    const parsedLockout = parseInt(String(loginLockoutMinutes), 10);
+    if (
+      isNaN(parsedLoginWindow) || isNaN(parsedLockout) ||
+      parsedLoginWindow < 1 || parsedLoginWindow > 1440 || parsedLockout < 1 || parsedLockout > 1440
+    ) {
+      toast.error('Login window and lockout must be between 1 and 1440 minutes.');
+      return;
+    }
+
+    // Send only what changed so fields edited elsewhere are not overwritten.
+    const changes: Record<string, unknown> = {};
+    if (!savedSnapshot || trimmedLoginUser !== savedSnapshot.loginUser) changes.loginUser = trimmedLoginUser;
+    if (loginPassword) changes.loginPassword = loginPassword;
+    if (!savedSnapshot || userAgent !== savedSnapshot.userAgent) changes.userAgent = userAgent;
+    if (!savedSnapshot || defaultMinRam !== savedSnapshot.defaultMinRam) changes.defaultMinRam = defaultMinRam;
+    if (!savedSnapshot || defaultMaxRam !== savedSnapshot.defaultMaxRam) changes.defaultMaxRam = defaultMaxRam;
+    if (!savedSnapshot || defaultFlags !== savedSnapshot.defaultFlags) changes.defaultFlags = defaultFlags;
+    if (!savedSnapshot || String(parsedPortStart) !== savedSnapshot.defaultPortRangeStart) changes.defaultPortRangeStart = parsedPortStart;
+    if (!savedSnapshot || String(parsedPortEnd) !== savedSnapshot.defaultPortRangeEnd) changes.defaultPortRangeEnd = parsedPortEnd;
+    if (!savedSnapshot || String(parsedMaxPlayers) !== savedSnapshot.defaultMaxPlayers) changes.defaultMaxPlayers = parsedMaxPlayers;
+    if (!savedSnapshot || defaultBackupSchedule !== savedSnapshot.defaultBackupSchedule) changes.defaultBackupSchedule = defaultBackupSchedule;
+    if (!savedSnapshot || String(parsedSessionTtl) !== savedSnapshot.sessionTtlHours) changes.sessionTtlHours = parsedSessionTtl;
+    if (!savedSnapshot || String(parsedMaxFailures) !== savedSnapshot.loginMaxFailures) changes.loginMaxFailures = parsedMaxFailures;
+    if (!savedSnapshot || String(parsedLoginWindow) !== savedSnapshot.loginWindowMinutes) changes.loginWindowMinutes = parsedLoginWindow;
+    if (!savedSnapshot || String(parsedLockout) !== savedSnapshot.loginLockoutMinutes) changes.loginLockoutMinutes = parsedLockout;
+    if (!savedSnapshot || sessionBindIp !== savedSnapshot.sessionBindIp) changes.sessionBindIp = sessionBindIp;
+    if (!savedSnapshot || sessionBindUserAgent !== savedSnapshot.sessionBindUserAgent) changes.sessionBindUserAgent = sessionBindUserAgent;
+    if (!savedSnapshot || cspReportOnly !== savedSnapshot.cspReportOnly) changes.cspReportOnly = cspReportOnly;
+    if (!savedSnapshot || accessAllowList !== savedSnapshot.accessAllowList) changes.accessAllowList = splitAccessList(accessAllowList);
+    if (!savedSnapshot || accessDenyList !== savedSnapshot.accessDenyList) changes.accessDenyList = splitAccessList(accessDenyList);
+    if (!savedSnapshot || String(pollInterval) !== savedSnapshot.statusPollInterval) changes.statusPollInterval = pollInterval;
+    if (!savedSnapshot || String(parsedTpsPoll) !== savedSnapshot.tpsPollInterval) changes.tpsPollInterval = parsedTpsPoll;
+    if (!savedSnapshot || String(parsedPlayerSync) !== savedSnapshot.playerSyncInterval) changes.playerSyncInterval = parsedPlayerSync;
+    if (!savedSnapshot || String(parsedPingPoll) !== savedSnapshot.pingPollInterval) changes.pingPollInterval = parsedPingPoll;
+    if (!savedSnapshot || locale !== savedSnapshot.locale) changes.locale = locale;
+    if (curseForgeApiKey.trim()) changes.curseForgeApiKey = curseForgeApiKey.trim();
+
     setSaving(true);
     try {
-      await apiRequest(
+      const data = await apiRequest<SettingsSaveResponse>(
         '/api/settings',
         {
-          method: 'PUT',
+          method: 'PATCH',
           headers: { 'Content-Type': 'application/json' },
-          body: JSON.stringify({
-            loginUser: trimmedLoginUser,
-            loginPassword,
-            userAgent,
-            defaultMinRam,
-            defaultMaxRam,
-            defaultFlags,
-            statusPollInterval: pollInterval,
-            tpsPollInterval: parsedTpsPoll,
-            playerSyncInterval: parsedPlayerSync,
-            pingPollInterval: parsedPingPoll,
-          }),
+          body: JSON.stringify(changes),
         },
         'Couldn’t save settings. Try again.'
       );
-      setLoginPassword('');
-      setSavedSnapshot({
-        loginUser: trimmedLoginUser,
+      // Show what the server actually saved, which may differ after clamping.
+      const saved: SettingsSnapshot = {
+        loginUser: data.loginUser,
         loginPassword: '',
-        userAgent,
-        defaultMinRam,
-        defaultMaxRam,
-        defaultFlags,
-        statusPollInterval: String(pollInterval),
-        tpsPollInterval: String(parsedTpsPoll),
-        playerSyncInterval: String(parsedPlayerSync),
-        pingPollInterval: String(parsedPingPoll),
-      });
-      toast.success('Applied changes.');
+        userAgent: data.userAgent,
+        defaultMinRam: data.defaultMinRam,
+        defaultMaxRam: data.defaultMaxRam,
+        defaultFlags: data.defaultFlags,
+        defaultPortRangeStart: String(data.defaultPortRangeStart),
+        defaultPortRangeEnd: String(data.defaultPortRangeEnd),
+        defaultMaxPlayers: String(data.defaultMaxPlayers),
+        defaultBackupSchedule: data.defaultBackupSchedule,
+        sessionTtlHours: String(data.sessionTtlHours),
+        loginMaxFailures: String(data.loginMaxFailures),
+        loginWindowMinutes: String(data.loginWindowMinutes),
+        loginLockoutMinutes: String(data.loginLockoutMinutes),
+        sessionBindIp: data.sessionBindIp,
+        sessionBindUserAgent: data.sessionBindUserAgent,
+        cspReportOnly: data.cspReportOnly,
+        accessAllowList: (data.accessAllowList || []).join('\n'),
+        accessDenyList: (data.accessDenyList || []).join('\n'),
+        statusPollInterval: String(data.statusPollInterval),
+        tpsPollInterval: String(data.tpsPollInterval),
+        playerSyncInterval: String(data.playerSyncInterval),
+        pingPollInterval: String(data.pingPollInterval),
+        locale: data.locale,
+        curseForgeApiKey: '',
+      };
+      setLoginPassword('');
+      setCurseForgeApiKey('');
+      setCurseForgeApiKeySet(Boolean(data.curseForgeApiKeySet));
+      setUserAgent(saved.userAgent);
+      setDefaultMinRam(saved.defaultMinRam);
+      setDefaultMaxRam(saved.defaultMaxRam);
+      setDefaultFlags(saved.defaultFlags);
+      setDefaultPortRangeStart(saved.defaultPortRangeStart);
+      setDefaultPortRangeEnd(saved.defaultPortRangeEnd);
+      setDefaultMaxPlayers(saved.defaultMaxPlayers);
+      setDefaultBackupSchedule(saved.defaultBackupSchedule);
+      setSessionTtlHours(saved.sessionTtlHours);
+      setLoginMaxFailures(saved.loginMaxFailures);
+      setLoginWindowMinutes(saved.loginWindowMinutes);
+      setLoginLockoutMinutes(saved.loginLockoutMinutes);
+      setSessionBindIp(saved.sessionBindIp);
+      setSessionBindUserAgent(saved.sessionBindUserAgent);
+      setCspReportOnly(saved.cspReportOnly);
+      setAccessAllowList(saved.accessAllowList);
+      setAccessDenyList(saved.accessDenyList);
+      setStatusPollInterval(saved.statusPollInterval);
+      setTpsPollInterval(saved.tpsPollInterval);
+      setPlayerSyncInterval(saved.playerSyncInterval);
+      setPingPollInterval(saved.pingPollInterval);
+      setLocale(saved.locale);
+      setSavedSnapshot(saved);
+      const adjusted = (data.fields ?? []).filter((field) => field.status === 'clamped' && field.field !== 'userAgent');
+      if (adjusted.length > 0) {
+        toast.info(`Applied changes. Adjusted: ${adjusted.map((field) => `${field.field} (${field.message})`).join('; ')}`);
+      } else {
+        toast.success('Applied changes.');
+      }
     } catch (err) {
       toast.error(toErrorMessage(err, 'Couldn’t save settings. Try again.'));
     } finally {
@@ -393,13 +668,50 @@ export const SystemSettingsPage = ({ onViewChange }: SystemSettingsPageProps) =>
     setDefaultMinRam(savedSnapshot.defaultMinRam);
     setDefaultMaxRam(savedSnapshot.defaultMaxRam);
     setDefaultFlags(savedSnapshot.defaultFlags);
+    setDefaultPortRangeStart(savedSnapshot.defaultPortRangeStart);
+    setDefaultPortRangeEnd(savedSnapshot.defaultPortRangeEnd);
+    setDefaultMaxPlayers(savedSnapshot.defaultMaxPlayers);
+    setDefaultBackupSchedule(savedSnapshot.defaultBackupSchedule);
+    setSessionTtlHours(savedSnapshot.sessionTtlHours);
+    setLoginMaxFailures(savedSnapshot.loginMaxFailures);
+    setLoginWindowMinutes(savedSnapshot.loginWindowMinutes);
+    setLoginLockoutMinutes(savedSnapshot.loginLockoutMinutes);
+    setSessionBindIp(savedSnapshot.sessionBindIp);
+    setSessionBindUserAgent(savedSnapshot.sessionBindUserAgent);
+    setCspReportOnly(savedSnapshot.cspReportOnly);
+    setAccessAllowList(savedSnapshot.accessAllowList);
+    setAccessDenyList(savedSnapshot.accessDenyList);
     setStatusPollInterval(savedSnapshot.statusPollInterval);
     setTpsPollInterval(savedSnapshot.tpsPollInterval);
     setPlayerSyncInterval(savedSnapshot.playerSyncInterval);
     setPingPollInterval(savedSnapshot.pingPollInterval);
+    setLocale(savedSnapshot.locale);
+    setCurseForgeApiKey('');
     toast.info('Unsaved changes discarded.');
   };
 
+  const handleRemoveCurseForgeKey = async () => {
+    setSaving(true);
+    try {
+      const data = await apiRequest<SettingsSaveResponse>(
+        '/api/settings',
+        {
+          method: 'PATCH',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({ curseForgeApiKey: '' }),
+        },
+        'Couldn’t remove the CurseForge API key.'
+      );
+      setCurseForgeApiKey('');
+      setCurseForgeApiKeySet(Boolean(data.curseForgeApiKeySet));
+      toast.success('CurseForge API key removed.');
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Couldn’t remove the CurseForge API key.'));
+    } finally {
+      setSaving(false);
+    }
+  };
+
   return (
     <div className="flex-1 p-4 md:p-8 overflow-y-auto">
       <div className="mb-8">
@@ -483,6 +795,7 @@ export const SystemSettingsPage = ({ onViewChange }: SystemSettingsPageProps) =>
                 {([
                   { value: 'none', label: 'None', desc: 'Default JVM flags.' },
                   { value: 'aikars', label: "Aikar's Flags", desc: 'Optimized GC for game servers.' },
+                  { value: 'zgc', label: 'ZGC', desc: 'Low-pause GC for large heaps on Java 21+.' },
                   { value: 'velocity', label: 'Velocity Proxy', desc: 'Optimized for proxy servers.' },
                   { value: 'modded', label: 'Modded', desc: 'Recommended for modded servers.' },
                 ] as const).map(opt => (
@@ -505,6 +818,64 @@ export const SystemSettingsPage = ({ onViewChange }: SystemSettingsPageProps) =>
               </div>
               <p className="text-xs text-gray-500 mt-2">Pre-selected JVM flag preset when creating servers.</p>
 
+              <hr className="border-[#3a3a3a] my-6" />
+              <label className="block text-sm text-gray-400 mb-3">New Server Defaults</label>
+              <div className="grid grid-cols-1 md:grid-cols-4 gap-4">
+                <div>
+                  <label className="block text-xs text-gray-500 mb-1">Port Range Start</label>
+                  <input
+                    type="text"
+                    inputMode="numeric"
+                    value={defaultPortRangeStart}
+                    onChange={(e) => setDefaultPortRangeStart(e.target.value)}
+                    pattern="\d*"
+                    className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded p-3 text-white focus:outline-none focus:border-[#E5B80B]"
+                    disabled={saving}
+                  />
+                </div>
+                <div>
+                  <label className="block text-xs text-gray-500 mb-1">Port Range End</label>
+                  <input
+                    type="text"
+                    inputMode="numeric"
+                    value={defaultPortRangeEnd}
+                    onChange={(e) => setDefaultPortRangeEnd(e.target.value)}
+                    pattern="\d*"
+                    className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded p-3 text-white focus:outline-none focus:border-[#E5B80B]"
+                    disabled={saving}
+                  />
+                </div>
+                <div>
+                  <label className="block text-xs text-gray-500 mb-1">Max Players</label>
+                  <input
+                    type="text"
+                    inputMode="numeric"
+                    value={defaultMaxPlayers}
+                    onChange={(e) => setDefaultMaxPlayers(e.target.value)}
+                    pattern="\d*"
+                    className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded p-3 text-white focus:outline-none focus:border-[#E5B80B]"
+                    disabled={saving}
+                  />
+                </div>
+                <div>
+                  <label className="block text-xs text-gray-500 mb-1">Backup Schedule</label>
+                  <select
+                    value={defaultBackupSchedule}
+                    onChange={(e) => setDefaultBackupSchedule(e.target.value)}
+                    className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded p-3 text-white focus:outline-none focus:border-[#E5B80B]"
+                    disabled={saving}
+                  >
+                    <option value="">None</option>
+                    <option value="daily">Daily</option>
+                    <option value="weekly">Weekly</option>
+                    <option value="monthly">Monthly</option>
+                    <option value="sixmonths">Every 6 months</option>
+                    <option value="yearly">Yearly</option>
+                  </select>
+                </div>
+              </div>
+              <p className="text-xs text-gray-500 mt-2">New servers get the first free port in this range, this player limit and this automatic backup schedule.</p>
+
               <hr className="border-[#3a3a3a] my-6" />
               <label className="block text-sm text-gray-400 mb-2">Status Polling Interval (seconds)</label>
               <input
@@ -560,6 +931,172 @@ export const SystemSettingsPage = ({ onViewChange }: SystemSettingsPageProps) =>
               </div>
               <p className="text-xs text-gray-500 mt-2">How often will these values be updated (seconds).</p>
 
+              <div className="mt-6">
+                <label className="block text-xs text-gray-500 mb-1">In-game Message Language</label>
+                <select
+                  value={locale}
+                  onChange={(e) => setLocale(e.target.value)}
+                  className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded p-3 text-white focus:outline-none focus:border-[#E5B80B]"
+                  disabled={saving}
+                >
+                  {supportedLocales.map((code) => (
+                    <option key={code} value={code}>{localeLabels[code] ?? code}</option>
+                  ))}
+                </select>
+                <p className="text-xs text-gray-500 mt-2">Used for restart and stop warnings broadcast to players.</p>
+              </div>
+
+              <hr className="border-[#3a3a3a] my-6" />
+              <label className="block text-sm text-gray-400 mb-3">CurseForge API Key</label>
+              <div className="flex gap-2">
+                <input
+                  type="password"
+                  value={curseForgeApiKey}
+                  onChange={(e) => setCurseForgeApiKey(e.target.value)}
+                  placeholder={curseForgeApiKeySet ? 'Saved. Leave empty to keep the current key' : 'Not set'}
+                  autoComplete="off"
+                  className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded p-3 text-white focus:outline-none focus:border-[#E5B80B]"
+                  disabled={saving}
+                />
+                {curseForgeApiKeySet && (
+                  <button
+                    type="button"
+                    onClick={handleRemoveCurseForgeKey}
+                    className="px-4 bg-[#2a2a2a] hover:bg-[#333] border border-[#3a3a3a] rounded text-sm text-gray-300 disabled:opacity-50"
+                    disabled={saving}
+                  >
+                    Remove
+                  </button>
+                )}
+              </div>
+              <p className="text-xs text-gray-500 mt-2">Used to check and download updates for mods linked to CurseForge, and to install CurseForge modpacks.</p>
+
+              <hr className="border-[#3a3a3a] my-6" />
+              <label className="block text-sm text-gray-400 mb-3">Session Security</label>
+              <div className="grid grid-cols-1 md:grid-cols-4 gap-4">
+                <div>
+                  <label className="block text-xs text-gray-500 mb-1">Session Lifetime (hours)</label>
+                  <input
+                    type="text"
+                    inputMode="numeric"
+                    value={sessionTtlHours}
+                    onChange={(e) => setSessionTtlHours(e.target.value)}
+                    pattern="\d*"
+                    className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded p-3 text-white focus:outline-none focus:border-[#E5B80B]"
+                    disabled={saving}
+                  />
+                </div>
+                <div>
+                  <label className="block text-xs text-gray-500 mb-1">Failed Logins Before Lockout</label>
+                  <input
+                    type="text"
+                    inputMode="numeric"
+                    value={loginMaxFailures}
+                    onChange={(e) => setLoginMaxFailures(e.target.value)}
+                    pattern="\d*"
+                    className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded p-3 text-white focus:outline-none focus:border-[#E5B80B]"
+                    disabled={saving}
+                  />
+                </div>
+                <div>
+                  <label className="block text-xs text-gray-500 mb-1">Failure Window (minutes)</label>
+                  <input
+                    type="text"
+                    inputMode="numeric"
+                    value={loginWindowMinutes}
+                    onChange={(e) => setLoginWindowMinutes(e.target.value)}
+                    pattern="\d*"
+                    className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded p-3 text-white focus:outline-none focus:border-[#E5B80B]"
+                    disabled={saving}
+                  />
+                </div>
+                <div>
+                  <label className="block text-xs text-gray-500 mb-1">Lockout (minutes)</label>
+                  <input
+                    type="text"
+                    inputMode="numeric"
+                    value={loginLockoutMinutes}
+                    onChange={(e) => setLoginLockoutMinutes(e.target.value)}
+                    pattern="\d*"
+                    className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded p-3 text-white focus:outline-none focus:border-[#E5B80B]"
+                    disabled={saving}
+                  />
+                </div>
+              </div>
+              <div className="flex flex-col gap-2 mt-4">
+                <label className="flex items-center gap-2 text-sm text-gray-300">
+                  <input
+                    type="checkbox"
+                    checked={sessionBindIp}
+                    onChange={(e) => setSessionBindIp(e.target.checked)}
+                    className="accent-[#E5B80B]"
+                    disabled={saving}
+                  />
+                  Sign out sessions when the client IP changes
+                </label>
+                <label className="flex items-center gap-2 text-sm text-gray-300">
+                  <input
+                    type="checkbox"
+                    checked={sessionBindUserAgent}
+                    onChange={(e) => setSessionBindUserAgent(e.target.checked)}
+                    className="accent-[#E5B80B]"
+                    disabled={saving}
+                  />
+                  Sign out sessions when the browser changes
+                </label>
+                <label className="flex items-center gap-2 text-sm text-gray-300">
+                  <input
+                    type="checkbox"
+                    checked={cspReportOnly}
+                    onChange={(e) => setCspReportOnly(e.target.checked)}
+                    className="accent-[#E5B80B]"
+                    disabled={saving}
+                  />
+                  Content security policy in report-only mode (log violations instead of blocking)
+                </label>
+              </div>
+              <div className="grid grid-cols-1 md:grid-cols-2 gap-4 mt-4">
+                <div>
+                  <label className="block text-xs text-gray-500 mb-1">Allowed IPs / CIDR ranges</label>
+                  <textarea
+                    value={accessAllowList}
+                    onChange={(e) => setAccessAllowList(e.target.value)}
+                    rows={3}
+                    placeholder={'192.168.1.0/24\n10.8.0.0/16'}
+                    className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded p-3 text-white font-mono text-sm focus:outline-none focus:border-[#E5B80B]"
+                    disabled={saving}
+                  />
+                </div>
+                <div>
+                  <label className="block text-xs text-gray-500 mb-1">Denied IPs / CIDR ranges</label>
+                  <textarea
+                    value={accessDenyList}
+                    onChange={(e) => setAccessDenyList(e.target.value)}
+                    rows={3}
+                    className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded p-3 text-white font-mono text-sm focus:outline-none focus:border-[#E5B80B]"
+                    disabled={saving}
+                  />
+                </div>
+              </div>
+              <p className="text-xs text-gray-500 mt-2">One entry per line. Leave the allow list empty to accept any address not denied. Localhost is always allowed, and a list that would block your current address is rejected.</p>
+              <p className="text-xs text-gray-500 mt-2">Changes apply to signed-in sessions too: a shorter lifetime ends older sessions, and binding checks start on the next request.</p>
+
+              <div className="mt-6">
+                <LoginBlocksPanel />
+              </div>
+
+              <div className="mt-6">
+                <UsersPanel />
+              </div>
+
+              <div className="mt-6">
+                <ModerationPresetsPanel />
+              </div>
+
+              <div className="mt-6">
+                <BackupTargetsPanel />
+              </div>
+
               <div className="flex justify-end mt-8">
                 <button
                   onClick={handleSave}
@@ -602,6 +1139,15 @@ export const SystemSettingsPage = ({ onViewChange }: SystemSettingsPageProps) =>
             </div>
           ) : (
             <>
+              {usage.memoryPressure && usage.memoryPressure.level !== 'ok' && (
+                <div className={clsx(
+                  'mb-3 p-3 rounded border text-xs flex items-start gap-2',
+                  usage.memoryPressure.level === 'critical' ? 'border-red-800 bg-red-900/20 text-red-300' : 'border-yellow-800 bg-yellow-900/20 text-yellow-300'
+                )}>
+                  <AlertTriangle size={14} className="shrink-0 mt-0.5" />
+                  <span>{usage.memoryPressure.warning}</span>
+                </div>
+              )}
               <div className="grid grid-cols-1 gap-3">
                 <MiniMetricChart title="CPU" value={usage.total.cpuPercent || 0} color="#E5B80B" unit="%" points={cpuSeries} />
                 <MiniMetricChart title="RAM" value={usage.total.ramPercent || 0} color="#3b82f6" unit="%" points={ramSeries} />
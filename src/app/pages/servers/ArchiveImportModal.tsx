@@ -0,0 +1,129 @@
+import React, { useState } from 'react';
+import { AnimatePresence, motion } from 'motion/react';
+import { PackageOpen, Loader2, X } from 'lucide-react';
+import { toast } from 'sonner';
+import { useServer } from '../../context/ServerContext';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+
+interface ArchiveImportModalProps {
+  open: boolean;
+  onClose: () => void;
+}
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-3 py-2 text-white text-sm focus:outline-none focus:border-blue-500';
+
+// ArchiveImportModal recreates a server from an archive exported by another
+// panel, with its settings and scheduled tasks. The name and port are kept
+// unless given here; a taken port moves to the next free one.
+export const ArchiveImportModal = ({ open, onClose }: ArchiveImportModalProps) => {
+  const { refreshServers } = useServer();
+  const [file, setFile] = useState<File | null>(null);
+  const [name, setName] = useState('');
+  const [port, setPort] = useState('');
+  const [submitting, setSubmitting] = useState(false);
+
+  const reset = () => {
+    setFile(null);
+    setName('');
+    setPort('');
+  };
+
+  const close = () => {
+    if (submitting) return;
+    reset();
+    onClose();
+  };
+
+  const submit = async () => {
+    if (!file || submitting) return;
+    setSubmitting(true);
+    try {
+      const formData = new FormData();
+      formData.append('file', file);
+      if (name.trim()) formData.append('name', name.trim());
+      if (port.trim()) formData.append('port', port.trim());
+      const server = await apiRequest<{ name: string; port: number }>('/api/servers/import/archive', { method: 'POST', body: formData }, 'Failed to import server');
+      toast.success(`Imported ${server.name} on port ${server.port}`);
+      await refreshServers();
+      reset();
+      onClose();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to import server'));
+    } finally {
+      setSubmitting(false);
+    }
+  };
+
+  return (
+    <AnimatePresence>
+      {open && (
+        <motion.div
+          initial={{ opacity: 0 }}
+          animate={{ opacity: 1 }}
+          exit={{ opacity: 0 }}
+          className="fixed inset-0 z-50 flex items-center justify-center bg-black/60 backdrop-blur-sm p-4"
+          onClick={close}
+        >
+          <motion.div
+            initial={{ opacity: 0, scale: 0.96, y: 8 }}
+            animate={{ opacity: 1, scale: 1, y: 0 }}
+            exit={{ opacity: 0, scale: 0.98, y: 6 }}
+            transition={{ duration: 0.2, ease: 'easeOut' }}
+            className="bg-[#202020] border border-blue-500/40 rounded-lg p-6 max-w-lg w-full shadow-2xl"
+            onClick={(e) => e.stopPropagation()}
+          >
+            <div className="flex items-center justify-between mb-4">
+              <div className="flex items-center gap-3">
+                <div className="p-2 rounded-full bg-blue-900/40 text-blue-300">
+                  <PackageOpen size={18} />
+                </div>
+                <h3 className="text-xl font-bold text-white">Import Panel Export</h3>
+              </div>
+              <button onClick={close} className="text-gray-500 hover:text-white transition-colors">
+                <X size={18} />
+              </button>
+            </div>
+
+            <div className="space-y-3">
+              <div>
+                <label className="block text-xs text-gray-400 uppercase font-bold mb-1">Export archive</label>
+                <input
+                  type="file"
+                  accept=".tar.gz,.gz"
+                  onChange={(e) => setFile(e.target.files?.[0] ?? null)}
+                  className="w-full text-sm text-gray-300 file:mr-3 file:rounded file:border-0 file:bg-[#333] file:px-3 file:py-1.5 file:text-white"
+                />
+                <p className="text-xs text-gray-500 mt-1">A .adpanel.tar.gz file from a server's Export card.</p>
+              </div>
+              <div className="grid grid-cols-2 gap-3">
+                <div>
+                  <label className="block text-xs text-gray-400 uppercase font-bold mb-1">Server name</label>
+                  <input value={name} onChange={(e) => setName(e.target.value)} placeholder="From the export" className={inputClass} />
+                </div>
+                <div>
+                  <label className="block text-xs text-gray-400 uppercase font-bold mb-1">Port</label>
+                  <input value={port} onChange={(e) => setPort(e.target.value)} placeholder="From the export" className={inputClass} />
+                </div>
+              </div>
+            </div>
+
+            <div className="flex justify-end gap-3 mt-6">
+              <button onClick={close} disabled={submitting} className="px-4 py-2 text-gray-400 hover:text-white transition-colors">
+                Cancel
+              </button>
+              <button
+                onClick={submit}
+                disabled={!file || submitting}
+                className="flex items-center gap-2 bg-blue-600 text-white px-4 py-2 rounded font-bold hover:bg-blue-500 transition-colors disabled:opacity-50 disabled:cursor-not-allowed"
+              >
+                {submitting ? <Loader2 size={16} className="animate-spin" /> : <PackageOpen size={16} />}
+                Import
+              </button>
+            </div>
+          </motion.div>
+        </motion.div>
+      )}
+    </AnimatePresence>
+  );
+};
@@ -0,0 +1,159 @@
+import React, { useState } from 'react';
+import { AnimatePresence, motion } from 'motion/react';
+import { FolderInput, Loader2, X } from 'lucide-react';
+import { toast } from 'sonner';
+import { useServer } from '../../context/ServerContext';
+import { apiRequest, toErrorMessage, waitForJob, type Job } from '../../lib/api';
+
+interface DirectoryImportModalProps {
+  open: boolean;
+  onClose: () => void;
+}
+
+const inputClass =
+  'w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-3 py-2 text-white text-sm focus:outline-none focus:border-blue-500';
+
+// DirectoryImportModal registers a server that already exists on the host,
+// in a folder under ADPANEL_IMPORT_ROOTS. The panel detects its type,
+// version and port, and copies or moves it into the servers folder without
+// downloading anything.
+export const DirectoryImportModal = ({ open, onClose }: DirectoryImportModalProps) => {
+  const { refreshServers } = useServer();
+  const [path, setPath] = useState('');
+  const [name, setName] = useState('');
+  const [port, setPort] = useState('');
+  const [type, setType] = useState('');
+  const [version, setVersion] = useState('');
+  const [move, setMove] = useState(false);
+  const [acceptEula, setAcceptEula] = useState(false);
+  const [progress, setProgress] = useState<string | null>(null);
+
+  const reset = () => {
+    setPath('');
+    setName('');
+    setPort('');
+    setType('');
+    setVersion('');
+    setMove(false);
+    setAcceptEula(false);
+  };
+
+  const close = () => {
+    if (progress !== null) return;
+    reset();
+    onClose();
+  };
+
+  const submit = async () => {
+    if (!path.trim() || progress !== null) return;
+    setProgress('Checking folder...');
+    try {
+      const job = await apiRequest<Job<{ name: string }>>('/api/servers/import', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({
+          path: path.trim(),
+          name: name.trim(),
+          port: port.trim() ? Number(port.trim()) : 0,
+          type: type.trim(),
+          version: version.trim(),
+          move,
+          acceptEula,
+        }),
+      }, 'Failed to import server');
+      const server = await waitForJob(job, (current) => setProgress(`${current.message || 'Importing'} (${current.progress}%)`));
+      toast.success(`Imported ${server?.name ?? 'server'}`);
+      await refreshServers();
+      reset();
+      onClose();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to import server'));
+    } finally {
+      setProgress(null);
+    }
+  };
+
+  return (
+    <AnimatePresence>
+      {open && (
+        <motion.div
+          initial={{ opacity: 0 }}
+          animate={{ opacity: 1 }}
+          exit={{ opacity: 0 }}
+          className="fixed inset-0 z-50 flex items-center justify-center bg-black/60 backdrop-blur-sm p-4"
+          onClick={close}
+        >
+          <motion.div
+            initial={{ opacity: 0, scale: 0.96, y: 8 }}
+            animate={{ opacity: 1, scale: 1, y: 0 }}
+            exit={{ opacity: 0, scale: 0.98, y: 6 }}
+            transition={{ duration: 0.2, ease: 'easeOut' }}
+            className="bg-[#202020] border border-blue-500/40 rounded-lg p-6 max-w-lg w-full shadow-2xl"
+            onClick={(e) => e.stopPropagation()}
+          >
+            <div className="flex items-center justify-between mb-4">
+              <div className="flex items-center gap-3">
+                <div className="p-2 rounded-full bg-blue-900/40 text-blue-300">
+                  <FolderInput size={18} />
+                </div>
+                <h3 className="text-xl font-bold text-white">Import From Folder</h3>
+              </div>
+              <button onClick={close} className="text-gray-500 hover:text-white transition-colors">
+                <X size={18} />
+              </button>
+            </div>
+
+            <div className="space-y-3">
+              <div>
+                <label className="block text-xs text-gray-400 uppercase font-bold mb-1">Folder on the host</label>
+                <input value={path} onChange={(e) => setPath(e.target.value)} placeholder="/srv/minecraft/survival" className={`${inputClass} font-mono`} />
+                <p className="text-xs text-gray-500 mt-1">Must be inside a folder listed in ADPANEL_IMPORT_ROOTS.</p>
+              </div>
+              <div className="grid grid-cols-2 gap-3">
+                <div>
+                  <label className="block text-xs text-gray-400 uppercase font-bold mb-1">Server name</label>
+                  <input value={name} onChange={(e) => setName(e.target.value)} placeholder="Folder name" className={inputClass} />
+                </div>
+                <div>
+                  <label className="block text-xs text-gray-400 uppercase font-bold mb-1">Port</label>
+                  <input value={port} onChange={(e) => setPort(e.target.value)} placeholder="From server.properties" className={inputClass} />
+                </div>
+                <div>
+                  <label className="block text-xs text-gray-400 uppercase font-bold mb-1">Type</label>
+                  <input value={type} onChange={(e) => setType(e.target.value)} placeholder="Detect" className={inputClass} />
+                </div>
+                <div>
+                  <label className="block text-xs text-gray-400 uppercase font-bold mb-1">Version</label>
+                  <input value={version} onChange={(e) => setVersion(e.target.value)} placeholder="Detect" className={inputClass} />
+                </div>
+              </div>
+              <label className="flex items-center gap-2 text-sm text-gray-300">
+                <input type="checkbox" checked={move} onChange={(e) => setMove(e.target.checked)} className="accent-blue-500" />
+                Move the folder instead of copying it
+              </label>
+              <label className="flex items-center gap-2 text-sm text-gray-300">
+                <input type="checkbox" checked={acceptEula} onChange={(e) => setAcceptEula(e.target.checked)} className="accent-blue-500" />
+                Accept the Minecraft EULA if eula.txt has not
+              </label>
+              {progress && <p className="text-xs text-gray-400">{progress}</p>}
+            </div>
+
+            <div className="flex justify-end gap-3 mt-6">
+              <button onClick={close} disabled={progress !== null} className="px-4 py-2 text-gray-400 hover:text-white transition-colors">
+                Cancel
+              </button>
+              <button
+                onClick={submit}
+                disabled={!path.trim() || progress !== null}
+                className="flex items-center gap-2 bg-blue-600 text-white px-4 py-2 rounded font-bold hover:bg-blue-500 transition-colors disabled:opacity-50 disabled:cursor-not-allowed"
+              >
+                {progress !== null ? <Loader2 size={16} className="animate-spin" /> : <FolderInput size={16} />}
+                Import
+              </button>
+            </div>
+          </motion.div>
+        </motion.div>
+      )}
+    </AnimatePresence>
+  );
+};
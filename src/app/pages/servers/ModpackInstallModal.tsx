@@ -0,0 +1,200 @@
+import React, { useState } from 'react';
+import { AnimatePresence, motion } from 'motion/react';
+import { Loader2, Package, X } from 'lucide-react';
+import { toast } from 'sonner';
+import clsx from 'clsx';
+import { useServer } from '../../context/ServerContext';
+import { apiRequest, toErrorMessage } from '../../lib/api';
+
+type ModpackSource = 'upload' | 'curseforge';
+
+interface ModpackInstallModalProps {
+  open: boolean;
+  onClose: () => void;
+}
+
+// ModpackInstallModal creates a server from a Modrinth .mrpack, a CurseForge
+// pack zip or a CurseForge project ID. The panel installs the loader, mods
+// and overrides in the background; progress shows in the new server's console.
+export const ModpackInstallModal = ({ open, onClose }: ModpackInstallModalProps) => {
+  const { refreshServers } = useServer();
+  const [source, setSource] = useState<ModpackSource>('upload');
+  const [file, setFile] = useState<File | null>(null);
+  const [projectId, setProjectId] = useState('');
+  const [fileId, setFileId] = useState('');
+  const [name, setName] = useState('');
+  const [port, setPort] = useState('');
+  const [submitting, setSubmitting] = useState(false);
+
+  const reset = () => {
+    setSource('upload');
+    setFile(null);
+    setProjectId('');
+    setFileId('');
+    setName('');
+    setPort('');
+  };
+
+  const close = () => {
+    if (submitting) return;
+    reset();
+    onClose();
+  };
+
+  const canSubmit = source === 'upload' ? !!file : /^\d+$/.test(projectId.trim());
+
+  const submit = async () => {
+    if (!canSubmit || submitting) return;
+    setSubmitting(true);
+    try {
+      if (source === 'upload' && file) {
+        const formData = new FormData();
+        formData.append('file', file);
+        if (name.trim()) formData.append('name', name.trim());
+        if (port.trim()) formData.append('port', port.trim());
+        await apiRequest('/api/servers/modpack', { method: 'POST', body: formData }, 'Failed to install modpack');
+      } else {
+        await apiRequest('/api/servers/modpack/curseforge', {
+          method: 'POST',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({
+            projectId: Number(projectId.trim()),
+            fileId: fileId.trim() ? Number(fileId.trim()) : 0,
+            name: name.trim(),
+            port: port.trim() ? Number(port.trim()) : 0,
+          }),
+        }, 'Failed to install modpack');
+      }
+      toast.success('Modpack server created. Installing mods in the background...');
+      await refreshServers();
+      reset();
+      onClose();
+    } catch (err) {
+      toast.error(toErrorMessage(err, 'Failed to install modpack'));
+    } finally {
+      setSubmitting(false);
+    }
+  };
+
+  return (
+    <AnimatePresence>
+      {open && (
+        <motion.div
+          initial={{ opacity: 0 }}
+          animate={{ opacity: 1 }}
+          exit={{ opacity: 0 }}
+          className="fixed inset-0 z-50 flex items-center justify-center bg-black/60 backdrop-blur-sm p-4"
+          onClick={close}
+        >
+          <motion.div
+            initial={{ opacity: 0, scale: 0.96, y: 8 }}
+            animate={{ opacity: 1, scale: 1, y: 0 }}
+            exit={{ opacity: 0, scale: 0.98, y: 6 }}
+            transition={{ duration: 0.2, ease: 'easeOut' }}
+            className="bg-[#202020] border border-purple-500/40 rounded-lg p-6 max-w-lg w-full shadow-2xl"
+            onClick={(e) => e.stopPropagation()}
+          >
+            <div className="flex items-center justify-between mb-4">
+              <div className="flex items-center gap-3">
+                <div className="p-2 rounded-full bg-purple-900/40 text-purple-300">
+                  <Package size={18} />
+                </div>
+                <h3 className="text-xl font-bold text-white">Install Modpack</h3>
+              </div>
+              <button onClick={close} className="text-gray-500 hover:text-white transition-colors">
+                <X size={18} />
+              </button>
+            </div>
+
+            <div className="flex gap-2 mb-4">
+              {(['upload', 'curseforge'] as ModpackSource[]).map((value) => (
+                <button
+                  key={value}
+                  onClick={() => setSource(value)}
+                  className={clsx(
+                    "flex-1 px-3 py-2 rounded text-sm font-bold border transition-colors",
+                    source === value
+                      ? "border-purple-500 bg-purple-900/30 text-purple-200"
+                      : "border-[#3a3a3a] text-gray-400 hover:text-white"
+                  )}
+                >
+                  {value === 'upload' ? 'Upload pack' : 'CurseForge ID'}
+                </button>
+              ))}
+            </div>
+
+            <div className="space-y-3">
+              {source === 'upload' ? (
+                <div>
+                  <label className="block text-xs text-gray-400 uppercase font-bold mb-1">Pack file</label>
+                  <input
+                    type="file"
+                    accept=".mrpack,.zip,application/zip"
+                    onChange={(e) => setFile(e.target.files?.[0] ?? null)}
+                    className="w-full text-sm text-gray-300 file:mr-3 file:px-3 file:py-1.5 file:rounded file:border-0 file:bg-[#2a2a2a] file:text-gray-200"
+                  />
+                  <p className="text-xs text-gray-500 mt-1">A Modrinth .mrpack or a CurseForge pack zip (the client download with manifest.json).</p>
+                </div>
+              ) : (
+                <div className="grid grid-cols-2 gap-3">
+                  <div>
+                    <label className="block text-xs text-gray-400 uppercase font-bold mb-1">Project ID</label>
+                    <input
+                      value={projectId}
+                      onChange={(e) => setProjectId(e.target.value)}
+                      placeholder="e.g. 925200"
+                      className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-3 py-2 text-white text-sm focus:outline-none focus:border-purple-500"
+                    />
+                  </div>
+                  <div>
+                    <label className="block text-xs text-gray-400 uppercase font-bold mb-1">File ID (optional)</label>
+                    <input
+                      value={fileId}
+                      onChange={(e) => setFileId(e.target.value)}
+                      placeholder="Latest"
+                      className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-3 py-2 text-white text-sm focus:outline-none focus:border-purple-500"
+                    />
+                  </div>
+                </div>
+              )}
+              <div className="grid grid-cols-2 gap-3">
+                <div>
+                  <label className="block text-xs text-gray-400 uppercase font-bold mb-1">Server name</label>
+                  <input
+                    value={name}
+                    onChange={(e) => setName(e.target.value)}
+                    placeholder="Pack name"
+                    className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-3 py-2 text-white text-sm focus:outline-none focus:border-purple-500"
+                  />
+                </div>
+                <div>
+                  <label className="block text-xs text-gray-400 uppercase font-bold mb-1">Port</label>
+                  <input
+                    value={port}
+                    onChange={(e) => setPort(e.target.value)}
+                    placeholder="Auto"
+                    className="w-full bg-[#1a1a1a] border border-[#3a3a3a] rounded px-3 py-2 text-white text-sm focus:outline-none focus:border-purple-500"
+                  />
+                </div>
+              </div>
+            </div>
+
+            <div className="flex justify-end gap-3 mt-6">
+              <button onClick={close} disabled={submitting} className="px-4 py-2 text-gray-400 hover:text-white transition-colors">
+                Cancel
+              </button>
+              <button
+                onClick={submit}
+                disabled={!canSubmit || submitting}
+                className="flex items-center gap-2 bg-purple-600 text-white px-4 py-2 rounded font-bold hover:bg-purple-500 transition-colors disabled:opacity-50 disabled:cursor-not-allowed"
+              >
+                {submitting ? <Loader2 size={16} className="animate-spin" /> : <Package size={16} />}
+                Install
+              </button>
+            </div>
+          </motion.div>
+        </motion.div>
+      )}
+    </AnimatePresence>
+  );
+};
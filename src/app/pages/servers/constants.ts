@@ -10,7 +10,7 @@ export const DEFAULT_CREATE_FORM = {
   alwaysPreTouch: false,
   type: '',
   version: '',
-  port: '25565',
+  port: '',
   minRam: '0.5',
   maxRam: '1',
   maxPlayers: '20',
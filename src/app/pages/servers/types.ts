@@ -36,7 +36,7 @@ export interface ImportFormState {
   onlineMode: ImportBoolState;
 }
 
-export type JVMFlagsPreset = 'none' | 'aikars' | 'velocity' | 'modded';
+export type JVMFlagsPreset = 'none' | 'aikars' | 'zgc' | 'velocity' | 'modded';
 
 export type ContextMenuState = {
   serverId: string;